@@ -17,11 +17,13 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 )
 
@@ -113,18 +115,58 @@ const (
 	MinParentsFlag   = "min-parents"
 	DecorateFlag     = "decorate"
 	OneLineFlag      = "oneline"
+	GraphFlag        = "graph"
 	ShallowFlag      = "shallow"
 	CachedFlag       = "cached"
 	ListFlag         = "list"
 	UserParam        = "user"
 	NoPrettyFlag     = "no-pretty"
 	ShowIgnoredFlag  = "ignored"
+	PruneFlag        = "prune"
+	NoVerifyFlag     = "no-verify"
+	SingleBranchFlag = "single-branch"
+	VerifyFlag       = "verify"
+	TagsFlag         = "tags"
+	SignFlag         = "gpg-sign"
+	NoSignFlag       = "no-gpg-sign"
+	MetadataParam    = "metadata"
+	AsOfParam        = "as-of"
+	PrefixParam      = "prefix"
+	DeleteSourceFlag = "delete-source"
+	ServerFlag       = "server"
+)
+
+const (
+	DeleteOrphansFlag = "delete-orphans"
+	NullifyFksFlag    = "nullify-fks"
+	DedupeUniqueFlag  = "dedupe-unique"
+	VerifyFKsFlag     = "verify-fks"
+)
+
+const (
+	SinceParam = "since"
+	UntilParam = "until"
+	SkipParam  = "skip"
+)
+
+const (
+	KeepDaysParam     = "keep-days"
+	SnapshotDaysParam = "snapshot-days"
+	PurgeBeforeParam  = "before"
+)
+
+const (
+	AuthEndpointParam = "auth-endpoint"
+	LoginURLParam     = "login-url"
+	InsecureFlag      = "insecure"
 )
 
 const (
 	SyncBackupId        = "sync"
 	SyncBackupUrlId     = "sync-url"
 	RestoreBackupId     = "restore"
+	GenerationsBackupId = "generations"
+	VerifyBackupId      = "verify"
 	AddBackupId         = "add"
 	RemoveBackupId      = "remove"
 	RemoveBackupShortId = "rm"
@@ -149,6 +191,10 @@ func CreateCommitArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(AllFlag, "a", "Adds all existing, changed tables (but not new tables) in the working set to the staged set.")
 	ap.SupportsFlag(UpperCaseAllFlag, "A", "Adds all tables (including new tables) in the working set to the staged set.")
 	ap.SupportsFlag(AmendFlag, "", "Amend previous commit")
+	ap.SupportsFlag(NoVerifyFlag, "", "Bypass the pre-commit hook.")
+	ap.SupportsFlag(SignFlag, "S", "Sign the commit with the key in user.signingkey, using the format in gpg.format.")
+	ap.SupportsFlag(NoSignFlag, "", "Don't sign the commit, even if commit.gpgsign is set.")
+	ap.SupportsString(MetadataParam, "", "json", "Attach the key-value metadata in the given JSON object to the commit, such as a ticket id or the URL of the pipeline run that made it. It's shown in the {{.EmphasisLeft}}metadata{{.EmphasisRight}} column of {{.EmphasisLeft}}dolt_log{{.EmphasisRight}}. When amending, the amended commit's metadata is kept unless this is given.")
 	return ap
 }
 
@@ -172,6 +218,9 @@ func CreateMergeArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(NoCommitFlag, "", "Perform the merge and stop just before creating a merge commit. Note this will not prevent a fast-forward merge; use the --no-ff arg together with the --no-commit arg to prevent both fast-forwards and merge commits.")
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(VerifyFKsFlag, "", "Verify the foreign keys of every row in the merged result, instead of only the rows changed since the merge base, and report each violation found. Fast-forward merges are not verified.")
+	ap.SupportsFlag(DryRunFlag, "", "Compute the merge and report its stats, conflicts and constraint violations without modifying the working set or creating a commit.")
+	ap.SupportsFlag(DeleteSourceFlag, "", "Delete the merged branch and its working set once the merge has been committed. Has no effect with {{.EmphasisLeft}}--no-commit{{.EmphasisRight}} or {{.EmphasisLeft}}--squash{{.EmphasisRight}}, when the merge has conflicts, or when merging something other than a local branch.")
 
 	return ap
 }
@@ -180,6 +229,8 @@ func CreatePushArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("push", 2)
 	ap.SupportsFlag(SetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference, used by argument-less {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} and other commands.")
 	ap.SupportsFlag(ForceFlag, "f", "Update the remote with local history, overwriting any conflicting history in the remote.")
+	ap.SupportsFlag(AllFlag, "", "Push all branches.")
+	ap.SupportsFlag(TagsFlag, "", "Push all tags.")
 	return ap
 }
 
@@ -202,7 +253,11 @@ func CreateCloneArgParser() *argparser.ArgParser {
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use.")
 	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file.")
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use.")
+	supportsGCSParams(ap)
+	supportsAzureParams(ap)
+	supportsOCIParams(ap)
 	ap.SupportsString(UserParam, "u", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
+	supportsGRPCCredsParams(ap)
 	return ap
 }
 
@@ -219,15 +274,50 @@ func CreateRemoteArgParser() *argparser.ArgParser {
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+	supportsGCSParams(ap)
+	supportsAzureParams(ap)
+	supportsOCIParams(ap)
+	supportsGRPCCredsParams(ap)
 	return ap
 }
 
+// supportsAzureParams adds the params that configure how an az remote is accessed
+func supportsAzureParams(ap *argparser.ArgParser) {
+	ap.SupportsValidatedString(dbfactory.AzureCredsTypeParam, "", "creds-type", "Azure credentials type. Valid values are: "+strings.Join(dbfactory.AzureCredTypes, ", ")+".", argparser.ValidatorFromStrList(dbfactory.AzureCredsTypeParam, dbfactory.AzureCredTypes))
+	ap.SupportsString(dbfactory.AzureSASTokenFileParam, "", "file", "File containing the SAS token to use. Defaults to the token in AZURE_STORAGE_SAS_TOKEN.")
+	ap.SupportsString(dbfactory.AzureManagedIdentityParam, "", "client-id", "Client id of the user-assigned managed identity to use. Defaults to the system-assigned identity.")
+}
+
+// supportsOCIParams adds the params that configure how an oci remote is accessed
+func supportsOCIParams(ap *argparser.ArgParser) {
+	ap.SupportsString(dbfactory.OCIUserParam, "", "user", "User name to authenticate with the registry as. Gets password from the environment variable {{.EmphasisLeft}}DOLT_OCI_PASSWORD{{.EmphasisRight}}. Defaults to the credentials in the docker config.")
+}
+
+// supportsGCSParams adds the params that configure how a gs remote is accessed
+func supportsGCSParams(ap *argparser.ArgParser) {
+	ap.SupportsString(dbfactory.GCSCredsFileParam, "", "file", "GCP service account key file to authenticate with instead of the application default credentials.")
+	ap.SupportsString(dbfactory.GCSUploadChunkSizeParam, "", "bytes", "Size of the chunks that table files are uploaded to GCS in. Chunks of a failed upload are retried individually. 0 disables resumable uploads.")
+}
+
+// supportsGRPCCredsParams adds the params that configure the credentials a http or https remote is accessed with
+func supportsGRPCCredsParams(ap *argparser.ArgParser) {
+	ap.SupportsString(dbfactory.GRPCCredsKeyParam, "", "key", "Id or public key of the credentials, as listed by {{.EmphasisLeft}}dolt creds ls{{.EmphasisRight}}, to use when authenticating with the remote instead of the credentials selected by {{.EmphasisLeft}}user.creds{{.EmphasisRight}}.")
+	ap.SupportsString(dbfactory.GRPCUserParam, "", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
+	ap.SupportsString(dbfactory.GRPCCredsHelperParam, "", "command", "Credential helper to run to get the credentials to authenticate with the remote, instead of the helper set by {{.EmphasisLeft}}creds.helper{{.EmphasisRight}}.")
+}
+
 func CreateCleanArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("clean")
 	ap.SupportsFlag(DryRunFlag, "", "Tests removing untracked tables without modifying the working set.")
 	return ap
 }
 
+func CreateStatementStatsResetArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("statement_stats_reset", 0)
+	ap.SupportsFlag(AllFlag, "a", "Resets the statement stats of all databases, instead of just the current one.")
+	return ap
+}
+
 func CreateCheckoutArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("checkout")
 	ap.SupportsString(CheckoutCoBranch, "", "branch", "Create a new branch named {{.LessThan}}new_branch{{.GreaterThan}} and start it at {{.LessThan}}start_point{{.GreaterThan}}.")
@@ -248,6 +338,8 @@ func CreateCherryPickArgParser() *argparser.ArgParser {
 func CreateFetchArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("fetch")
 	ap.SupportsString(UserParam, "u", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
+	ap.SupportsFlag(PruneFlag, "p", "After fetching, remove any remote-tracking branches which no longer exist on the remote.")
+	ap.SupportsFlag(VerifyFlag, "", "Verify that the data of every chunk downloaded hashes to the chunk's address, failing on the first chunk that doesn't. Defaults to the {{.EmphasisLeft}}fetch.verify{{.EmphasisRight}} config setting.")
 	return ap
 }
 
@@ -271,6 +363,7 @@ func CreatePullArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(NoCommitFlag, "", "Perform the merge and stop just before creating a merge commit. Note this will not prevent a fast-forward merge; use the --no-ff arg together with the --no-commit arg to prevent both fast-forwards and merge commits.")
 	ap.SupportsFlag(NoEditFlag, "", "Use an auto-generated commit message when creating a merge commit. The default for interactive CLI sessions is to open an editor.")
 	ap.SupportsString(UserParam, "u", "user", "User name to use when authenticating with the remote. Gets password from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}.")
+	ap.SupportsFlag(VerifyFlag, "", "Verify that the data of every chunk downloaded hashes to the chunk's address, failing on the first chunk that doesn't. Defaults to the {{.EmphasisLeft}}fetch.verify{{.EmphasisRight}} config setting.")
 	return ap
 }
 
@@ -306,6 +399,8 @@ func CreateTagArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(VerboseFlag, "v", "list tags along with their metadata.")
 	ap.SupportsFlag(DeleteFlag, "d", "Delete a tag.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(SignFlag, "s", "Sign the tag with the key in user.signingkey, using the format in gpg.format.")
+	ap.SupportsFlag(NoSignFlag, "", "Don't sign the tag, even if tag.gpgsign is set.")
 	return ap
 }
 
@@ -315,10 +410,24 @@ func CreateBackupArgParser() *argparser.ArgParser {
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"creds-type", "credential type.  Valid options are role, env, and file.  See the help section for additional details."})
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"profile", "AWS profile to use."})
 	ap.SupportsFlag(VerboseFlag, "v", "When printing the list of backups adds additional details.")
+	ap.SupportsString(dbfactory.EncryptionKeyFileParam, "", "file", "Encrypt the table files of a file backup with the key in {{.LessThan}}file{{.GreaterThan}}, and decrypt them with it when verifying or restoring.")
+	ap.SupportsString(AsOfParam, "", "time", "When restoring, move every branch back to the newest commit made at or before {{.LessThan}}time{{.GreaterThan}}.")
 	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, dbfactory.AWSCredTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file")
 	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use")
+	supportsGCSParams(ap)
+	supportsAzureParams(ap)
+	supportsOCIParams(ap)
+	return ap
+}
+
+func CreateLoginArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("login", 1)
+	ap.SupportsString(AuthEndpointParam, "e", "hostname:port", fmt.Sprintf("Specify the endpoint used to authenticate this client. Must be used with --%s OR set in the configuration file as `%s`", LoginURLParam, env.AddCredsUrlKey))
+	ap.SupportsString(LoginURLParam, "url", "url", "Specify the login url where the browser will add credentials.")
+	ap.SupportsFlag(InsecureFlag, "i", "If set, makes insecure connection to remote authentication server")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"creds", "A specific credential to use for login. If omitted, new credentials will be generated."})
 	return ap
 }
 
@@ -330,6 +439,15 @@ func CreateVerifyConstraintsArgParser(name string) *argparser.ArgParser {
 	return ap
 }
 
+func CreateRepairConstraintsArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("repair constraints")
+	ap.SupportsFlag(DeleteOrphansFlag, "", "Delete child rows that violate a foreign key.")
+	ap.SupportsFlag(NullifyFksFlag, "", "Set the columns of child rows that violate a foreign key to NULL.")
+	ap.SupportsFlag(DedupeUniqueFlag, "", "For rows that violate a unique key, keep the row with the lowest primary key and delete the others.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table(s) to repair. If omitted, repairs all tables with constraint violations."})
+	return ap
+}
+
 func CreateLogArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithVariableArgs("log")
 	ap.SupportsInt(NumberFlag, "n", "num_commits", "Limit the number of commits to output.")
@@ -338,16 +456,43 @@ func CreateLogArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(ParentsFlag, "", "Shows all parents of each commit in the log.")
 	ap.SupportsString(DecorateFlag, "", "decorate_fmt", "Shows refs next to commits. Valid options are short, full, no, and auto")
 	ap.SupportsFlag(OneLineFlag, "", "Shows logs in a compact format.")
+	ap.SupportsFlag(GraphFlag, "", "Draws a text-based graph of the commit history alongside the log.")
 	ap.SupportsStringList(NotFlag, "", "revision", "Excludes commits from revision.")
 	return ap
 }
 
+// CreateLogTableFunctionArgParser returns the arg parser of the dolt_log() table function, which supports the
+// arguments of dolt log and filters on the author and date of commits, and skipping commits.
+func CreateLogTableFunctionArgParser() *argparser.ArgParser {
+	ap := CreateLogArgParser()
+	ap.SupportsString(AuthorParam, "", "pattern", "Limit the log to commits whose author, as Name <email>, matches the regular expression.")
+	ap.SupportsString(SinceParam, "", "date", "Limit the log to commits made at or after the date.")
+	ap.SupportsString(UntilParam, "", "date", "Limit the log to commits made at or before the date.")
+	ap.SupportsInt(SkipParam, "", "num_commits", "Skip this many commits before starting to output commits.")
+	return ap
+}
+
 func CreateGCArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("gc", 0)
 	ap.SupportsFlag(ShallowFlag, "s", "perform a fast, but incomplete garbage collection pass")
 	return ap
 }
 
+func CreateRetentionArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs("retention")
+	ap.SupportsInt(KeepDaysParam, "", "days", "Keep every commit made in this many days.")
+	ap.SupportsInt(SnapshotDaysParam, "", "days", "Squash the commits made before then into one snapshot per this many days. Defaults to 7.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"branch", "The branches whose history is squashed. Every branch is squashed if none are given."})
+	return ap
+}
+
+func CreatePurgeHistoryArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("purge-history", 1)
+	ap.SupportsString(PurgeBeforeParam, "", "date", "Remove the table from commits created before this date. Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}}.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table whose old versions should be removed."})
+	return ap
+}
+
 func CreateCountCommitsArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("gc", 0)
 	ap.SupportsString("from", "f", "commit id", "commit to start counting from")
@@ -357,6 +502,9 @@ func CreateCountCommitsArgParser() *argparser.ArgParser {
 
 var awsParams = []string{dbfactory.AWSRegionParam, dbfactory.AWSCredsTypeParam, dbfactory.AWSCredsFileParam, dbfactory.AWSCredsProfile}
 var ossParams = []string{dbfactory.OSSCredsFileParam, dbfactory.OSSCredsProfile}
+var gcsParams = []string{dbfactory.GCSCredsFileParam, dbfactory.GCSUploadChunkSizeParam}
+var azureParams = []string{dbfactory.AzureCredsTypeParam, dbfactory.AzureSASTokenFileParam, dbfactory.AzureManagedIdentityParam}
+var ociParams = []string{dbfactory.OCIUserParam}
 
 func ProcessBackupArgs(apr *argparser.ArgParseResults, scheme, backupUrl string) (map[string]string, error) {
 	params := map[string]string{}
@@ -370,6 +518,18 @@ func ProcessBackupArgs(apr *argparser.ArgParseResults, scheme, backupUrl string)
 	default:
 		err = VerifyNoAwsParams(apr)
 	}
+	if err == nil {
+		err = AddGCSParams(backupUrl, apr, params)
+	}
+	if err == nil {
+		err = AddAzureParams(backupUrl, apr, params)
+	}
+	if err == nil {
+		err = AddOCIParams(backupUrl, apr, params)
+	}
+	if err == nil {
+		err = AddEncryptionParams(scheme, apr, params)
+	}
 	return params, err
 }
 
@@ -413,6 +573,113 @@ func AddOSSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[s
 	return nil
 }
 
+func AddGCSParams(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) error {
+	isGCS := strings.HasPrefix(remoteUrl, "gs")
+
+	if !isGCS {
+		for _, p := range gcsParams {
+			if _, ok := apr.GetValue(p); ok {
+				return fmt.Errorf("%s param is only valid for gcs remotes in the format gs://gcs-bucket/database", p)
+			}
+		}
+	}
+
+	for _, p := range gcsParams {
+		if val, ok := apr.GetValue(p); ok {
+			params[p] = val
+		}
+	}
+
+	return nil
+}
+
+func AddAzureParams(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) error {
+	isAzure := strings.HasPrefix(remoteUrl, "az://")
+
+	if !isAzure {
+		for _, p := range azureParams {
+			if _, ok := apr.GetValue(p); ok {
+				return fmt.Errorf("%s param is only valid for azure remotes in the format az://storage-account/container/database", p)
+			}
+		}
+	}
+
+	for _, p := range azureParams {
+		if val, ok := apr.GetValue(p); ok {
+			params[p] = val
+		}
+	}
+
+	// the token file is read whenever the remote is used, which may be from another directory
+	if tokenFile, ok := params[dbfactory.AzureSASTokenFileParam]; ok {
+		abs, err := filepath.Abs(tokenFile)
+		if err != nil {
+			return err
+		}
+		params[dbfactory.AzureSASTokenFileParam] = abs
+	}
+
+	return nil
+}
+
+func AddOCIParams(remoteUrl string, apr *argparser.ArgParseResults, params map[string]string) error {
+	isOCI := strings.HasPrefix(remoteUrl, "oci://")
+
+	if !isOCI {
+		for _, p := range ociParams {
+			if _, ok := apr.GetValue(p); ok {
+				return fmt.Errorf("%s param is only valid for oci remotes in the format oci://registry/repository:tag", p)
+			}
+		}
+	}
+
+	for _, p := range ociParams {
+		if val, ok := apr.GetValue(p); ok {
+			params[p] = val
+		}
+	}
+
+	return nil
+}
+
+func AddGRPCCredsParams(scheme string, apr *argparser.ArgParseResults, params map[string]string) error {
+	isGRPC := scheme == dbfactory.HTTPSScheme || scheme == dbfactory.HTTPScheme
+
+	if !isGRPC {
+		for _, p := range dbfactory.GRPCCredsParams {
+			if _, ok := apr.GetValue(p); ok {
+				return fmt.Errorf("%s param is only valid for http and https remotes", p)
+			}
+		}
+	}
+
+	for _, p := range dbfactory.GRPCCredsParams {
+		if val, ok := apr.GetValue(p); ok {
+			params[p] = val
+		}
+	}
+
+	return nil
+}
+
+// AddEncryptionParams adds the absolute path of the encryption key file given by the encryption-key-file param to
+// |params|. Client-side encryption is only supported for file databases.
+func AddEncryptionParams(scheme string, apr *argparser.ArgParseResults, params map[string]string) error {
+	keyFile, ok := apr.GetValue(dbfactory.EncryptionKeyFileParam)
+	if !ok {
+		return nil
+	}
+	if scheme != dbfactory.FileScheme {
+		return fmt.Errorf("%s param is only valid for file urls", dbfactory.EncryptionKeyFileParam)
+	}
+	absKeyFile, err := filepath.Abs(keyFile)
+	if err != nil {
+		return err
+	}
+	params[dbfactory.EncryptionKeyFileParam] = absKeyFile
+	return nil
+}
+
 func VerifyNoAwsParams(apr *argparser.ArgParseResults) error {
 	if awsParams := apr.GetValues(awsParams...); len(awsParams) > 0 {
 		awsParamKeys := make([]string, 0, len(awsParams))
@@ -20,5 +20,8 @@ import (
 
 var Commands = cli.NewHiddenSubCommandHandler("admin", "Commands for directly working with Dolt storage for purposes of testing or database recovery", []cli.Command{
 	SetRefCmd{},
+	DeleteRefCmd{},
+	SetHeadCmd{},
+	CheckRefsCmd{},
 	ShowRootCmd{},
 })
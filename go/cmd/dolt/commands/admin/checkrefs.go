@@ -0,0 +1,157 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const fixParam = "fix"
+
+type CheckRefsCmd struct {
+}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd CheckRefsCmd) Name() string {
+	return "check-refs"
+}
+
+// Description returns a description of the command
+func (cmd CheckRefsCmd) Description() string {
+	return "Reports refs that are in an inconsistent state, optionally fixing the ones that can be fixed safely"
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd CheckRefsCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd CheckRefsCmd) Docs() *cli.CommandDocumentation {
+	return nil
+}
+
+func (cmd CheckRefsCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsFlag(fixParam, "", "create missing working sets for branches. Other problems are only reported, and must be fixed with set-ref, delete-ref or set-head.")
+	return ap
+}
+
+func (cmd CheckRefsCmd) Hidden() bool {
+	return true
+}
+
+// Exec executes the command
+func (cmd CheckRefsCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	usage, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, cli.CommandDocumentationContent{}, ap))
+
+	apr := cli.ParseArgsOrDie(ap, args, usage)
+	fix := apr.Contains(fixParam)
+
+	db := doltdb.HackDatasDatabaseFromDoltDB(dEnv.DoltDB)
+	dss, err := db.Datasets(ctx)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("failed to get database datasets").AddCause(err).Build(), usage)
+	}
+
+	refs := make(map[string]hash.Hash)
+	err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+		refs[id] = addr
+		return nil
+	})
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("failed to iterate all ref entries").AddCause(err).Build(), usage)
+	}
+
+	ids := make([]string, 0, len(refs))
+	for id := range refs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	problems := 0
+	dangling := make(map[string]bool)
+	for _, id := range ids {
+		ok, err := dEnv.DoltDB.Has(ctx, refs[id])
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.BuildDError("error reading %s", refs[id].String()).AddCause(err).Build(), usage)
+		}
+		if !ok {
+			cli.Printf("%s points to %s, which does not exist; repoint it with set-ref or remove it with delete-ref --force\n", id, refs[id].String())
+			dangling[id] = true
+			problems++
+		}
+	}
+
+	for _, id := range ids {
+		if ref.IsWorkingSet(id) {
+			headRef, err := ref.NewWorkingSetRef(id).ToHeadRef()
+			if err != nil {
+				continue
+			}
+			if _, ok := refs[headRef.String()]; !ok {
+				cli.Printf("%s has no corresponding %s; remove it with delete-ref\n", id, headRef.String())
+				problems++
+			}
+			continue
+		}
+
+		if !ref.IsRef(id) || dangling[id] {
+			continue
+		}
+		r, err := ref.Parse(id)
+		if err != nil || r.GetType() != ref.BranchRefType {
+			continue
+		}
+
+		missing, err := ensureWorkingSet(ctx, dEnv.DoltDB, r, !fix)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.BuildDError("error checking working set for %s", id).AddCause(err).Build(), usage)
+		}
+		if missing && fix {
+			cli.Printf("%s had no working set; created one from its head commit\n", id)
+		} else if missing {
+			cli.Printf("%s has no working set; create one with check-refs --fix\n", id)
+			problems++
+		}
+	}
+
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		cli.Printf("HEAD can't be read: %s; repoint it with set-head\n", err.Error())
+		problems++
+	} else if _, ok := refs[headRef.String()]; !ok {
+		cli.Printf("HEAD points to %s, which does not exist; repoint it with set-head\n", headRef.String())
+		problems++
+	}
+
+	if problems > 0 {
+		return 1
+	}
+
+	cli.Println("no problems found")
+	return 0
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+type DeleteRefCmd struct {
+}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd DeleteRefCmd) Name() string {
+	return "delete-ref"
+}
+
+// Description returns a description of the command
+func (cmd DeleteRefCmd) Description() string {
+	return "Deletes a ref from the root refs map directly"
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd DeleteRefCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd DeleteRefCmd) Docs() *cli.CommandDocumentation {
+	return nil
+}
+
+func (cmd DeleteRefCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	supportsRefArgs(ap)
+	ap.SupportsFlag(forceParam, "f", "delete the ref even if it is the checked out branch or the last branch")
+	ap.SupportsFlag(dryRunParam, "", "print the change that would be made without making it")
+	return ap
+}
+
+func (cmd DeleteRefCmd) Hidden() bool {
+	return true
+}
+
+// Exec executes the command
+func (cmd DeleteRefCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	usage, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, cli.CommandDocumentationContent{}, ap))
+
+	apr := cli.ParseArgsOrDie(ap, args, usage)
+
+	refPath, verr := refPathFromArgs(apr)
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	db := doltdb.HackDatasDatabaseFromDoltDB(dEnv.DoltDB)
+	curr, exists, err := lookupRef(ctx, db, refPath)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error reading %s", refPath).AddCause(err).Build(), usage)
+	}
+	if !exists {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("ref %s does not exist", refPath).Build(), usage)
+	}
+
+	if !apr.Contains(forceParam) {
+		if verr := checkDeleteRefIsSafe(ctx, dEnv, refPath); verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+	}
+
+	if apr.Contains(dryRunParam) {
+		cli.Printf("would delete %s (was %s)\n", refPath, curr.String())
+		return 0
+	}
+
+	ds, err := db.GetDataset(ctx, refPath)
+	if err == nil {
+		_, err = db.Delete(ctx, ds)
+	}
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error deleting %s", refPath).AddCause(err).Build(), usage)
+	}
+
+	cli.Printf("deleted %s (was %s)\n", refPath, curr.String())
+	return 0
+}
+
+// checkDeleteRefIsSafe returns an error if deleting the ref given would leave the repository without a usable HEAD
+func checkDeleteRefIsSafe(ctx context.Context, dEnv *env.DoltEnv, refPath string) errhand.VerboseError {
+	if !ref.IsRef(refPath) {
+		return nil
+	}
+
+	r, err := ref.Parse(refPath)
+	if err != nil || r.GetType() != ref.BranchRefType {
+		return nil
+	}
+
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err == nil && ref.Equals(headRef, r) {
+		return errhand.BuildDError("%s is the checked out branch; use --force to delete it anyway", refPath).Build()
+	}
+
+	branches, err := dEnv.DoltDB.GetBranches(ctx)
+	if err != nil {
+		return errhand.BuildDError("error reading branches").AddCause(err).Build()
+	}
+	if len(branches) == 1 {
+		return errhand.BuildDError("%s is the last branch; use --force to delete it anyway", refPath).Build()
+	}
+
+	return nil
+}
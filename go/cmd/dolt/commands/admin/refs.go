@@ -0,0 +1,136 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	refParam          = "ref"
+	branchParam       = "branch"
+	remoteNameParam   = "remote-name"
+	remoteBranchParam = "remote-branch"
+	forceParam        = "force"
+	dryRunParam       = "dry-run"
+)
+
+var errStopIteration = errors.New("stop iteration")
+
+// supportsRefArgs adds the arguments used to name the ref a command operates on
+func supportsRefArgs(ap *argparser.ArgParser) {
+	ap.SupportsOptionalString(refParam, "", "ref", "the full path of the ref, e.g. refs/heads/main or workingSets/heads/main")
+	ap.SupportsOptionalString(branchParam, "", "branch name", "the branch ref")
+	ap.SupportsOptionalString(remoteNameParam, "", "remote name", "the remote name, e.g. origin, of the remote ref")
+	ap.SupportsOptionalString(remoteBranchParam, "", "remote branch name", "the remote branch name of the remote ref")
+}
+
+// refPathFromArgs returns the full path of the ref named by the arguments given. The path is returned as a string
+// rather than a ref.DoltRef, since refs being repaired may not be parseable.
+func refPathFromArgs(apr *argparser.ArgParseResults) (string, errhand.VerboseError) {
+	count := 0
+	for _, param := range []string{refParam, branchParam, remoteNameParam} {
+		if apr.Contains(param) {
+			count++
+		}
+	}
+	if count != 1 {
+		return "", errhand.BuildDError("exactly one of --%s, --%s or --%s / --%s must be supplied", refParam, branchParam, remoteNameParam, remoteBranchParam).SetPrintUsage().Build()
+	}
+
+	switch {
+	case apr.Contains(refParam):
+		refPath := apr.MustGetValue(refParam)
+		if err := datas.ValidateDatasetId(refPath); err != nil {
+			return "", errhand.BuildDError("invalid ref %s", refPath).AddCause(err).Build()
+		}
+		return refPath, nil
+	case apr.Contains(branchParam):
+		if apr.Contains(remoteBranchParam) {
+			return "", errhand.BuildDError("--%s and --%s are mutually exclusive", branchParam, remoteBranchParam).SetPrintUsage().Build()
+		}
+		return ref.NewBranchRef(apr.MustGetValue(branchParam)).String(), nil
+	default:
+		if !apr.Contains(remoteBranchParam) {
+			return "", errhand.BuildDError("--%s and --%s must both be supplied", remoteNameParam, remoteBranchParam).SetPrintUsage().Build()
+		}
+		return ref.NewRemoteRef(apr.MustGetValue(remoteNameParam), apr.MustGetValue(remoteBranchParam)).String(), nil
+	}
+}
+
+// lookupRef returns the address the ref given points to in the root map of the database, without reading the value at
+// that address. This works even when the value is missing from the chunk store.
+func lookupRef(ctx context.Context, db datas.Database, refPath string) (hash.Hash, bool, error) {
+	dss, err := db.Datasets(ctx)
+	if err != nil {
+		return hash.Hash{}, false, err
+	}
+
+	var addr hash.Hash
+	found := false
+	err = dss.IterAll(ctx, func(id string, a hash.Hash) error {
+		if id == refPath {
+			addr, found = a, true
+			return errStopIteration
+		}
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return hash.Hash{}, false, err
+	}
+
+	return addr, found, nil
+}
+
+// ensureWorkingSet creates the working set for the branch given from the branch's head commit if it doesn't have one,
+// returning whether it was missing. When |dryRun| is true, the working set is not created.
+func ensureWorkingSet(ctx context.Context, ddb *doltdb.DoltDB, branch ref.DoltRef, dryRun bool) (bool, error) {
+	wsRef, err := ref.WorkingSetRefForHead(branch)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = ddb.ResolveWorkingSet(ctx, wsRef)
+	if err == nil {
+		return false, nil
+	} else if err != doltdb.ErrWorkingSetNotFound {
+		return false, err
+	}
+
+	if dryRun {
+		return true, nil
+	}
+
+	cm, err := ddb.ResolveCommitRef(ctx, branch)
+	if err != nil {
+		return false, err
+	}
+
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	ws := doltdb.EmptyWorkingSet(wsRef).WithWorkingRoot(root).WithStagedRoot(root)
+	return true, ddb.UpdateWorkingSet(ctx, wsRef, ws, hash.Hash{}, doltdb.TodoWorkingSetMeta(), nil)
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+type SetHeadCmd struct {
+}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd SetHeadCmd) Name() string {
+	return "set-head"
+}
+
+// Description returns a description of the command
+func (cmd SetHeadCmd) Description() string {
+	return "Points the checked out branch of the repository at a branch, without touching any working set"
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd SetHeadCmd) RequiresRepo() bool {
+	return true
+}
+
+func (cmd SetHeadCmd) Docs() *cli.CommandDocumentation {
+	return nil
+}
+
+func (cmd SetHeadCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"branch", "the branch to check out"})
+	ap.SupportsFlag(forceParam, "f", "point HEAD at the branch even if it doesn't exist")
+	ap.SupportsFlag(dryRunParam, "", "print the change that would be made without making it")
+	return ap
+}
+
+func (cmd SetHeadCmd) Hidden() bool {
+	return true
+}
+
+// Exec executes the command
+func (cmd SetHeadCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	usage, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, cli.CommandDocumentationContent{}, ap))
+
+	apr := cli.ParseArgsOrDie(ap, args, usage)
+	if apr.NArg() != 1 {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("a branch name must be supplied").SetPrintUsage().Build(), usage)
+	}
+
+	branch := ref.NewBranchRef(apr.Arg(0))
+	dryRun := apr.Contains(dryRunParam)
+
+	_, exists, err := lookupRef(ctx, doltdb.HackDatasDatabaseFromDoltDB(dEnv.DoltDB), branch.String())
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error reading %s", branch.String()).AddCause(err).Build(), usage)
+	}
+
+	if !exists && !apr.Contains(forceParam) {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("branch %s does not exist; use --force to check it out anyway", apr.Arg(0)).Build(), usage)
+	}
+
+	// A branch without a working set can't be checked out, so create one from the branch's head if it's missing
+	if exists {
+		created, err := ensureWorkingSet(ctx, dEnv.DoltDB, branch, dryRun)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.BuildDError("error creating working set for %s", branch.String()).AddCause(err).Build(), usage)
+		}
+		if created && dryRun {
+			cli.Printf("would create working set for %s\n", branch.String())
+		} else if created {
+			cli.Printf("created working set for %s\n", branch.String())
+		}
+	}
+
+	if dryRun {
+		cli.Printf("would set HEAD to %s\n", branch.String())
+		return 0
+	}
+
+	err = dEnv.RepoStateWriter().SetCWBHeadRef(ctx, ref.MarshalableRef{Ref: branch})
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error setting HEAD to %s", branch.String()).AddCause(err).Build(), usage)
+	}
+
+	return 0
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -51,10 +52,10 @@ func (cmd SetRefCmd) Docs() *cli.CommandDocumentation {
 
 func (cmd SetRefCmd) ArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
-	ap.SupportsOptionalString("branch", "", "branch name", "the branch ref to set")
-	ap.SupportsOptionalString("remote-name", "", "remote name", "the remote name, e.g. origin, of the remote ref to set")
-	ap.SupportsOptionalString("remote-branch", "", "remote branch name", "the remote branch name of the remote ref set")
+	supportsRefArgs(ap)
 	ap.SupportsString("to", "", "commit-hash", "the commit hash to set the ref to")
+	ap.SupportsFlag(forceParam, "f", "set the ref even if commits it currently points to would become unreachable from it")
+	ap.SupportsFlag(dryRunParam, "", "print the change that would be made without making it")
 	return ap
 }
 
@@ -70,29 +71,107 @@ func (cmd SetRefCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	apr := cli.ParseArgsOrDie(ap, args, usage)
 
-	var r ref.DoltRef
+	refPath, verr := refPathFromArgs(apr)
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+	if verr := checkSetRefType(refPath); verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
 
-	if apr.Contains("branch") {
-		if apr.Contains("remote-name") || apr.Contains("remote-branch") {
-			verr := errhand.BuildDError("--branch and --branch-name / --remote-branch are mutually exclusive").SetPrintUsage().Build()
-			commands.HandleVErrAndExitCode(verr, usage)
-		}
-		r = ref.NewBranchRef(apr.MustGetValue("branch"))
-	} else if apr.Contains("remote-name") || apr.Contains("remote-branch") {
-		if !(apr.Contains("remote-branch") && apr.Contains("remote-name")) {
-			verr := errhand.BuildDError("--branch-name and --remote-branch must both be supplied").SetPrintUsage().Build()
-			commands.HandleVErrAndExitCode(verr, usage)
+	to, ok := apr.GetValue("to")
+	if !ok {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("--to must be supplied").SetPrintUsage().Build(), usage)
+	}
+	h, ok := hash.MaybeParse(to)
+	if !ok {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("invalid hash %s", to).Build(), usage)
+	}
+
+	db := doltdb.HackDatasDatabaseFromDoltDB(dEnv.DoltDB)
+	curr, exists, err := lookupRef(ctx, db, refPath)
+	if err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error reading %s", refPath).AddCause(err).Build(), usage)
+	}
+
+	if exists && curr != h && !apr.Contains(forceParam) {
+		if verr := checkSetRefIsFastForward(ctx, dEnv.DoltDB, refPath, curr, h); verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
 		}
-		r = ref.NewRemoteRef(apr.MustGetValue("remote-name"), apr.MustGetValue("remote-branch"))
 	}
 
-	h := hash.Parse(apr.MustGetValue("to"))
+	if apr.Contains(dryRunParam) {
+		if exists {
+			cli.Printf("would set %s from %s to %s\n", refPath, curr.String(), h.String())
+		} else {
+			cli.Printf("would create %s at %s\n", refPath, h.String())
+		}
+		return 0
+	}
 
-	err := dEnv.DoltDB.SetHead(ctx, r, h)
+	ds, err := db.GetDataset(ctx, refPath)
+	if err == nil {
+		_, err = db.SetHead(ctx, ds, h)
+	}
 	if err != nil {
-		verr := errhand.BuildDError("error setting %s to %s", r.String(), h.String()).AddCause(err).Build()
-		commands.HandleVErrAndExitCode(verr, usage)
+		verr := errhand.BuildDError("error setting %s to %s", refPath, h.String()).AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
 	return 0
 }
+
+// checkSetRefType returns an error unless the ref given is a branch, remote or tag ref. Working sets and internal refs
+// hold values other than commits, which setting directly would corrupt.
+func checkSetRefType(refPath string) errhand.VerboseError {
+	if ref.IsRef(refPath) {
+		if r, err := ref.Parse(refPath); err == nil {
+			switch r.GetType() {
+			case ref.BranchRefType, ref.RemoteRefType, ref.TagRefType:
+				return nil
+			}
+		}
+	}
+	return errhand.BuildDError("%s is not a branch, remote or tag ref; only those can be set", refPath).Build()
+}
+
+// checkSetRefIsFastForward returns an error if moving the ref given from |curr| to |new| would make commits unreachable
+// from it. Refs pointing to values that are missing from the database can always be moved, since that's what this
+// command is used to repair.
+func checkSetRefIsFastForward(ctx context.Context, ddb *doltdb.DoltDB, refPath string, curr, new hash.Hash) errhand.VerboseError {
+	if ok, err := ddb.Has(ctx, curr); err != nil {
+		return errhand.BuildDError("error reading %s", curr.String()).AddCause(err).Build()
+	} else if !ok {
+		return nil
+	}
+
+	currCommit, err := ddb.ReadCommit(ctx, curr)
+	if err != nil {
+		return errhand.BuildDError("%s points to %s, which is not a commit; use --force to overwrite it", refPath, curr.String()).Build()
+	}
+
+	newCommit, err := ddb.ReadCommit(ctx, new)
+	if err != nil {
+		return errhand.BuildDError("error reading commit %s", new.String()).AddCause(err).Build()
+	}
+
+	ancestor, err := doltdb.GetCommitAncestor(ctx, currCommit, newCommit)
+	if err != nil {
+		return errhand.BuildDError("error finding common ancestor of %s and %s", curr.String(), new.String()).AddCause(err).Build()
+	}
+
+	if ancestor == nil {
+		return errhand.BuildDError("%s is not a descendant of %s, the current value of %s; use --force to set it anyway", new.String(), curr.String(), refPath).Build()
+	}
+
+	ancestorHash, err := ancestor.HashOf()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	if ancestorHash != curr {
+		return errhand.BuildDError("%s is not a descendant of %s, the current value of %s; use --force to set it anyway", new.String(), curr.String(), refPath).Build()
+	}
+
+	return nil
+}
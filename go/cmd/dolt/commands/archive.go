@@ -0,0 +1,113 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/chunks"
+)
+
+const (
+	archiveOlderThanParam   = "older-than"
+	archiveRebuildFlag      = "rebuild"
+	defaultArchiveOlderThan = 100
+)
+
+var archiveDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Moves old history into compact archive files.",
+	LongDesc: `Moves the data that is only reachable from old commits into archive table files. Archives compress their data in large blocks with a shared dictionary, so they are much smaller than regular table files, but reading a single row from them is slower. Archiving is intended for history that is kept but rarely read.
+
+Each table's data is archived separately, and each archive's dictionary is built from the data it holds, so that it fits the rows of that table. Dictionaries are trained with zstd's dictionary builder on samples taken from across all the data written to the archive. Dictionaries are only used by archives; regular table files still compress each chunk on its own. Later runs archive newly old data into new archives with dictionaries of their own. Running with {{.EmphasisLeft}}--rebuild{{.EmphasisRight}} archives the already archived data again, merging each table's archives into one and training its dictionary again on all of its archived data. Rebuilding rewrites every archive, so it is best run periodically rather than every time.
+
+A commit is old if it is more than {{.EmphasisLeft}}--older-than{{.EmphasisRight}} commits behind every branch, remote tracking branch and tag it can be reached from. The default is 100. Data that is also reachable from a recent commit or a working set is never archived, and neither are the commits themselves, so {{.EmphasisLeft}}dolt log{{.EmphasisRight}} stays fast.
+
+Data that has not yet been written to a table file by {{.EmphasisLeft}}dolt gc{{.EmphasisRight}} is not archived.
+`,
+	Synopsis: []string{
+		"[--older-than {{.LessThan}}n{{.GreaterThan}}] [--rebuild]",
+	},
+}
+
+type ArchiveCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ArchiveCmd) Name() string {
+	return "archive"
+}
+
+// Description returns a description of the command
+func (cmd ArchiveCmd) Description() string {
+	return archiveDocs.ShortDesc
+}
+
+func (cmd ArchiveCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(archiveDocs, ap)
+}
+
+func (cmd ArchiveCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
+	ap.SupportsInt(archiveOlderThanParam, "", "n", "Archive the data of commits more than n commits old. Defaults to 100.")
+	ap.SupportsFlag(archiveRebuildFlag, "", "Archive already archived data again, training the dictionary of each table's archive again on all of its archived data.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd ArchiveCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd ArchiveCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, archiveDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	olderThan := apr.GetIntOrDefault(archiveOlderThanParam, defaultArchiveOlderThan)
+	if olderThan < 0 {
+		return HandleVErrAndExitCode(errhand.BuildDError("--%s must not be negative", archiveOlderThanParam).SetPrintUsage().Build(), usage)
+	}
+
+	if dEnv.IsLocked() {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(env.ErrActiveServerLock.New(dEnv.LockFile())), help)
+	}
+
+	dEnv, err := MaybeMigrateEnv(ctx, dEnv)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("could not load manifest for archive").AddCause(err).Build(), usage)
+	}
+
+	n, err := dEnv.DoltDB.Archive(ctx, olderThan, apr.Contains(archiveRebuildFlag))
+	if err != nil {
+		if errors.Is(err, chunks.ErrUnsupportedOperation) {
+			return HandleVErrAndExitCode(errhand.BuildDError("this database does not support archiving").Build(), usage)
+		}
+		return HandleVErrAndExitCode(errhand.BuildDError("an error occurred while archiving").AddCause(err).Build(), usage)
+	}
+
+	if n == 0 {
+		cli.Println("Nothing to archive.")
+	} else {
+		cli.Printf("Archived %d chunk(s).\n", n)
+	}
+	return 0
+}
@@ -17,7 +17,12 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
 
 	"github.com/dolthub/dolt/go/store/types"
 
@@ -37,7 +42,7 @@ var backupDocs = cli.CommandDocumentationContent{
 
 {{.EmphasisLeft}}add{{.EmphasisRight}}
 Adds a backup named {{.LessThan}}name{{.GreaterThan}} for the database at {{.LessThan}}url{{.GreaterThan}}.
-The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, gs, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}backups.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
+The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, gs, az, oci, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}backups.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
 The URL address must be unique to existing remotes and backups.
 
 AWS cloud backup urls should be of the form {{.EmphasisLeft}}aws://[dynamo-table:s3-bucket]/database{{.EmphasisRight}}. You may configure your aws cloud backup using the optional parameters {{.EmphasisLeft}}aws-region{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-type{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-file{{.EmphasisRight}}.
@@ -52,28 +57,41 @@ GCP backup urls should be of the form gs://gcs-bucket/database and will use the
 
 The local filesystem can be used as a backup by providing a repository url in the format file://absolute path. See https://en.wikipedia.org/wiki/File_URI_scheme
 
+The table files of a file backup can be encrypted on the client with {{.EmphasisLeft}}--encryption-key-file{{.EmphasisRight}}, which names a file holding a 256-bit AES key as 32 raw bytes, 64 hex characters or base64. The key file is recorded with the backup, and is needed to verify or restore it.
+
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
 Remove the backup named {{.LessThan}}name{{.GreaterThan}}. All configuration settings for the backup are removed. The contents of the backup are not affected.
 
 {{.EmphasisLeft}}restore{{.EmphasisRight}}
-Restore a Dolt database from a given {{.LessThan}}url{{.GreaterThan}} into a specified directory {{.LessThan}}url{{.GreaterThan}}.
+Restore a Dolt database from a given {{.LessThan}}url{{.GreaterThan}} into a specified directory {{.LessThan}}url{{.GreaterThan}}. With {{.EmphasisLeft}}--as-of{{.EmphasisRight}}, every branch of the restored database is moved back to the newest commit on its history that was made at or before {{.LessThan}}time{{.GreaterThan}}, branches with no commits from before then are removed, and so are tags created after it. {{.LessThan}}time{{.GreaterThan}} is in UTC, in one of the formats YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS, or YYYY-MM-DDTHH:MM:SSZ07:00.
 
 {{.EmphasisLeft}}sync{{.EmphasisRight}}
-Snapshot the database and upload to the backup {{.LessThan}}name{{.GreaterThan}}. This includes branches, tags, working sets, and remote tracking refs.
+Snapshot the database and upload to the backup {{.LessThan}}name{{.GreaterThan}}. This includes branches, tags, working sets, and remote tracking refs. The first sync of a backup uploads the whole database. Each sync after it is incremental, and only uploads the chunks that are new since the last sync. Every sync is recorded as a new generation of the backup.
+
+{{.EmphasisLeft}}verify{{.EmphasisRight}}
+Check the backup {{.LessThan}}name{{.GreaterThan}}, or the backup at {{.LessThan}}url{{.GreaterThan}}, without restoring it. Verification checks that every table file in the manifest of the backup can be read, that every chunk reachable from its branches, tags, working sets and remote tracking refs is present, and that the hash of every one of those chunks matches its address. Every problem found is printed, and the command fails if there are any.
+
+{{.EmphasisLeft}}generations{{.EmphasisRight}}
+List the generations of the backup {{.LessThan}}name{{.GreaterThan}}, one for each sync, with the time of the sync, the root of the database that was backed up, and the number of chunks and bytes that were uploaded.
 	
 {{.EmphasisLeft}}sync-url{{.EmphasisRight}}
 Snapshot the database and upload the backup to {{.LessThan}}url{{.GreaterThan}}. Like sync, this includes branches, tags, working sets, and remote tracking refs, but it does not require you to create a named backup`,
 
 	Synopsis: []string{
 		"[-v | --verbose]",
-		"add [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}} {{.LessThan}}url{{.GreaterThan}}",
+		"add [--encryption-key-file {{.LessThan}}file{{.GreaterThan}}] [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}} {{.LessThan}}url{{.GreaterThan}}",
 		"remove {{.LessThan}}name{{.GreaterThan}}",
-		"restore {{.LessThan}}url{{.GreaterThan}} {{.LessThan}}name{{.GreaterThan}}",
+		"restore [--as-of {{.LessThan}}time{{.GreaterThan}}] {{.LessThan}}url{{.GreaterThan}} {{.LessThan}}name{{.GreaterThan}}",
 		"sync {{.LessThan}}name{{.GreaterThan}}",
+		"generations {{.LessThan}}name{{.GreaterThan}}",
+		"verify [--encryption-key-file {{.LessThan}}file{{.GreaterThan}}] ({{.LessThan}}name{{.GreaterThan}} | {{.LessThan}}url{{.GreaterThan}})",
 		"sync-url [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}url{{.GreaterThan}}",
 	},
 }
 
+// tempBackupName is the name of the backup that sync-url syncs to
+const tempBackupName = "__temp__"
+
 type BackupCmd struct{}
 
 // Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
@@ -127,6 +145,10 @@ func (cmd BackupCmd) Exec(ctx context.Context, commandStr string, args []string,
 		verr = syncBackupUrl(ctx, dEnv, apr)
 	case apr.Arg(0) == cli.RestoreBackupId:
 		verr = restoreBackup(ctx, dEnv, apr)
+	case apr.Arg(0) == cli.GenerationsBackupId:
+		verr = printBackupGenerations(dEnv, apr)
+	case apr.Arg(0) == cli.VerifyBackupId:
+		verr = verifyBackup(ctx, dEnv, apr)
 	default:
 		verr = errhand.BuildDError("").SetPrintUsage().Build()
 	}
@@ -144,6 +166,10 @@ func removeBackup(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 
 	switch err {
 	case nil:
+		err = actions.RemoveBackupGenerations(dEnv.FS, dEnv.GetDoltDir(), old)
+		if err != nil {
+			return errhand.BuildDError("error: failed to remove the generations of backup '%s'", old).AddCause(err).Build()
+		}
 		return nil
 	case env.ErrFailedToWriteRepoState:
 		return errhand.BuildDError("error: failed to save change to repo state").AddCause(err).Build()
@@ -217,6 +243,102 @@ func printBackups(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Ver
 	return nil
 }
 
+func printBackupGenerations(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	backupName := strings.TrimSpace(apr.Arg(1))
+	backups, err := dEnv.GetBackups()
+	if err != nil {
+		return errhand.BuildDError("Unable to get backups from the local directory").AddCause(err).Build()
+	}
+	if _, ok := backups[backupName]; !ok {
+		return errhand.BuildDError("error: unknown backup: '%s' ", backupName).Build()
+	}
+
+	gens, err := actions.LoadBackupGenerations(dEnv.FS, dEnv.GetDoltDir(), backupName)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	for i := len(gens) - 1; i >= 0; i-- {
+		gen := gens[i]
+		kind := "full"
+		if gen.Incremental {
+			kind = "incremental"
+		}
+		cli.Printf("%d\t%s\t%s\t%s\t%d chunks\t%s\n", gen.Generation, gen.Time.Local().Format(time.RFC3339), gen.Root, kind, gen.Chunks, humanize.Bytes(gen.Bytes))
+	}
+	return nil
+}
+
+// backupGenerationSummary returns a line describing the sync of the generation |gen| of the backup |backupName|
+func verifyBackup(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	target := strings.TrimSpace(apr.Arg(1))
+	backups, err := dEnv.GetBackups()
+	if err != nil {
+		return errhand.BuildDError("Unable to get backups from the local directory").AddCause(err).Build()
+	}
+
+	b, named := backups[target]
+	if !named {
+		scheme, absBackupUrl, err := env.GetAbsRemoteUrl(dEnv.FS, dEnv.Config, target)
+		if err != nil {
+			return errhand.BuildDError("error: '%s' is not a backup or a valid url.", target).AddCause(err).Build()
+		}
+		params, err := cli.ProcessBackupArgs(apr, scheme, absBackupUrl)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		b = env.NewRemote(tempBackupName, target, params)
+	}
+
+	backupDb, err := b.GetRemoteDB(ctx, dEnv.DoltDB.Format(), dEnv)
+	if err != nil {
+		return errhand.BuildDError("error: unable to open backup '%s'.", target).AddCause(err).Build()
+	}
+	report, err := backupDb.Verify(ctx)
+	if err != nil {
+		return errhand.BuildDError("error: failed to verify backup '%s'.", target).AddCause(err).Build()
+	}
+
+	cli.Printf("root: %s\n", report.Root.String())
+	if named {
+		gens, err := actions.LoadBackupGenerations(dEnv.FS, dEnv.GetDoltDir(), b.Name)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+		if len(gens) > 0 && gens[len(gens)-1].Root != report.Root.String() {
+			last := gens[len(gens)-1]
+			cli.Printf("note: the root of the backup is not the root of its last generation, %d (%s); it was changed by another sync\n", last.Generation, last.Root)
+		}
+	}
+	for _, p := range report.Problems {
+		cli.Println(color.RedString(p))
+	}
+	if len(report.Problems) > 0 {
+		return errhand.BuildDError("backup '%s' failed verification with %d problems", target, len(report.Problems)).Build()
+	}
+	cli.Printf("backup '%s' verified: %d table files, %d refs, %d chunks\n", target, report.TableFiles, report.Refs, report.Chunks)
+	return nil
+}
+
+func backupGenerationSummary(backupName string, gen actions.BackupGeneration) string {
+	switch {
+	case gen.Chunks == 0:
+		return fmt.Sprintf("backup '%s' generation %d: no new chunks to upload", backupName, gen.Generation)
+	case gen.Incremental:
+		return fmt.Sprintf("backup '%s' generation %d: uploaded %d new chunks (%s)", backupName, gen.Generation, gen.Chunks, humanize.Bytes(gen.Bytes))
+	default:
+		return fmt.Sprintf("backup '%s' generation %d: uploaded %d chunks (%s), a full backup", backupName, gen.Generation, gen.Chunks, humanize.Bytes(gen.Bytes))
+	}
+}
+
 func syncBackupUrl(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
 	if apr.NArg() != 2 {
 		return errhand.BuildDError("").SetPrintUsage().Build()
@@ -233,7 +355,7 @@ func syncBackupUrl(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	b := env.NewRemote("__temp__", backupUrl, params)
+	b := env.NewRemote(tempBackupName, backupUrl, params)
 	return backup(ctx, dEnv, b)
 }
 
@@ -266,7 +388,16 @@ func backup(ctx context.Context, dEnv *env.DoltEnv, b env.Remote) errhand.Verbos
 	if err != nil {
 		return errhand.BuildDError("error: ").AddCause(err).Build()
 	}
-	err = actions.SyncRoots(ctx, dEnv.DoltDB, destDb, tmpDir, buildProgStarter(defaultLanguage), stopProgFuncs)
+	gen, err := actions.SyncRootsWithGeneration(ctx, dEnv.DoltDB, destDb, tmpDir, buildProgStarter(defaultLanguage), stopProgFuncs)
+
+	// backups synced by url don't have a name to record their generations under
+	if (err == nil || err == pull.ErrDBUpToDate) && b.Name != tempBackupName {
+		gen, err = actions.RecordBackupGeneration(dEnv.FS, dEnv.GetDoltDir(), b.Name, gen)
+		if err != nil {
+			return errhand.BuildDError("error: failed to record the generation of backup '%s'", b.Name).AddCause(err).Build()
+		}
+		cli.Println(backupGenerationSummary(b.Name, gen))
+	}
 
 	switch err {
 	case nil:
@@ -296,6 +427,16 @@ func restoreBackup(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 		return verr
 	}
 
+	var asOf time.Time
+	asOfStr, restoreAsOf := apr.GetValue(cli.AsOfParam)
+	if restoreAsOf {
+		var err error
+		asOf, err = cli.ParseDate(asOfStr)
+		if err != nil {
+			return errhand.BuildDError("error: invalid --%s", cli.AsOfParam).AddCause(err).Build()
+		}
+	}
+
 	// second return value isDir is relevant but handled by library functions
 	userDirExists, _ := dEnv.FS.Exists(dir)
 
@@ -305,7 +446,7 @@ func restoreBackup(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 	}
 
 	var params map[string]string
-	params, verr = parseRemoteArgs(apr, scheme, remoteUrl)
+	params, verr = parseRemoteArgs(dEnv, apr, scheme, remoteUrl)
 	if verr != nil {
 		return verr
 	}
@@ -346,5 +487,23 @@ func restoreBackup(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 		return errhand.VerboseErrorFromError(err)
 	}
 
+	if restoreAsOf {
+		branches, err := actions.RestoreBranchesAsOf(ctx, clonedEnv.DoltDB, asOf)
+		if err != nil {
+			return errhand.BuildDError("error: failed to restore branches as of %s", asOfStr).AddCause(err).Build()
+		}
+		for _, b := range branches {
+			if b.Commit == nil {
+				cli.Printf("removed branch '%s', it has no commits from before %s\n", b.Branch.GetPath(), asOfStr)
+				continue
+			}
+			h, err := b.Commit.HashOf()
+			if err != nil {
+				return errhand.VerboseErrorFromError(err)
+			}
+			cli.Printf("restored branch '%s' to %s\n", b.Branch.GetPath(), h.String())
+		}
+	}
+
 	return nil
 }
@@ -33,6 +33,7 @@ import (
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -51,13 +52,16 @@ With a {{.EmphasisLeft}}-m{{.EmphasisRight}}, {{.LessThan}}oldbranch{{.GreaterTh
 
 The {{.EmphasisLeft}}-c{{.EmphasisRight}} options have the exact same semantics as {{.EmphasisLeft}}-m{{.EmphasisRight}}, except instead of the branch being renamed it will be copied to a new name.
 
-With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}branchname{{.GreaterThan}} will be deleted. You may specify more than one branch for deletion.`,
+With a {{.EmphasisLeft}}-d{{.EmphasisRight}}, {{.LessThan}}branchname{{.GreaterThan}} will be deleted. You may specify more than one branch for deletion.
+
+With {{.EmphasisLeft}}--as-of{{.EmphasisRight}}, a new branch named {{.LessThan}}prefix{{.GreaterThan}}/{{.LessThan}}branchname{{.GreaterThan}} is created for every branch, at the newest commit on its history that was made at or before {{.LessThan}}time{{.GreaterThan}}. This recreates every branch as it was at that time, without changing the existing branches. {{.LessThan}}time{{.GreaterThan}} is in UTC, in one of the formats YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS, or YYYY-MM-DDTHH:MM:SSZ07:00. The {{.LessThan}}prefix{{.GreaterThan}} defaults to {{.EmphasisLeft}}as-of-{{.EmphasisRight}} followed by the time.`,
 	Synopsis: []string{
 		`[--list] [-v] [-a] [-r]`,
 		`[-f] {{.LessThan}}branchname{{.GreaterThan}} [{{.LessThan}}start-point{{.GreaterThan}}]`,
 		`-m [-f] [{{.LessThan}}oldbranch{{.GreaterThan}}] {{.LessThan}}newbranch{{.GreaterThan}}`,
 		`-c [-f] [{{.LessThan}}oldbranch{{.GreaterThan}}] {{.LessThan}}newbranch{{.GreaterThan}}`,
 		`-d [-f] [-r] {{.LessThan}}branchname{{.GreaterThan}}...`,
+		`--as-of {{.LessThan}}time{{.GreaterThan}} [--prefix {{.LessThan}}prefix{{.GreaterThan}}]`,
 	},
 }
 
@@ -94,6 +98,9 @@ func (cmd BranchCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(datasetsFlag, "", "List all datasets in the database")
 	ap.SupportsFlag(cli.RemoteParam, "r", "When in list mode, show only remote tracked branches. When with -d, delete a remote tracking branch.")
 	ap.SupportsFlag(showCurrentFlag, "", "Print the name of the current branch")
+	ap.SupportsString(cli.AsOfParam, "", "time", "Create a branch for every branch at the newest commit made at or before {{.LessThan}}time{{.GreaterThan}}")
+	ap.SupportsString(cli.PrefixParam, "", "prefix", "When used with --as-of, the prefix of the names of the new branches")
+	supportsJsonFormat(ap)
 	return ap
 }
 
@@ -117,11 +124,20 @@ func (cmd BranchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		defer closeFunc()
 	}
 
-	if len(apr.ContainsMany(cli.MoveFlag, cli.CopyFlag, cli.DeleteFlag, cli.DeleteForceFlag, cli.ListFlag, showCurrentFlag)) > 1 {
-		cli.PrintErrln("Must specify exactly one of --move/-m, --copy/-c, --delete/-d, -D, --show-current, or --list.")
+	if len(apr.ContainsMany(cli.MoveFlag, cli.CopyFlag, cli.DeleteFlag, cli.DeleteForceFlag, cli.ListFlag, showCurrentFlag, cli.AsOfParam)) > 1 {
+		cli.PrintErrln("Must specify exactly one of --move/-m, --copy/-c, --delete/-d, -D, --show-current, --as-of, or --list.")
 		return 1
 	}
 
+	outputJson, err := isJsonFormat(apr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	listing := apr.Contains(cli.ListFlag) || (apr.NArg() == 0 && len(apr.ContainsMany(cli.MoveFlag, cli.CopyFlag, cli.DeleteFlag, cli.DeleteForceFlag, showCurrentFlag, datasetsFlag, cli.AsOfParam)) == 0)
+	if outputJson && !listing {
+		return HandleVErrAndExitCode(errhand.BuildDError("--format json is only supported when listing branches").Build(), usage)
+	}
+
 	for _, arg := range apr.Args {
 		if !doltdb.IsValidUserBranchName(arg) {
 			cli.PrintErrf("%s is an invalid branch name", arg)
@@ -143,6 +159,8 @@ func (cmd BranchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return printCurrentBranch(sqlCtx, queryEngine)
 	case apr.Contains(datasetsFlag):
 		return printAllDatasets(ctx, dEnv)
+	case apr.Contains(cli.AsOfParam):
+		return createBranchesAsOf(ctx, dEnv, apr, usage)
 	case apr.NArg() > 0:
 		return createBranch(sqlCtx, queryEngine, apr, args, usage)
 	default:
@@ -225,6 +243,10 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 		return branches[i].name < branches[j].name
 	})
 
+	if outputJson, _ := isJsonFormat(apr); outputJson {
+		return printBranchesJson(branches, branchSet, currentBranch)
+	}
+
 	for _, branch := range branches {
 		if branchSet.Size() > 0 && !branchSet.Contains(branch.name) {
 			continue
@@ -253,6 +275,37 @@ func printBranches(sqlCtx *sql.Context, queryEngine cli.Queryist, apr *argparser
 	return 0
 }
 
+// branchJson is a branch in the output of dolt branch --format json
+type branchJson struct {
+	Name    string `json:"name"`
+	Hash    string `json:"hash"`
+	Remote  bool   `json:"remote"`
+	Current bool   `json:"current"`
+}
+
+// printBranchesJson prints |branches| as a JSON object with a branches array, limited to the names in |branchSet| if
+// it isn't empty.
+func printBranchesJson(branches []branchMeta, branchSet *set.StrSet, currentBranch string) int {
+	out := struct {
+		Branches []branchJson `json:"branches"`
+	}{Branches: []branchJson{}}
+	for _, branch := range branches {
+		if branchSet.Size() > 0 && !branchSet.Contains(branch.name) {
+			continue
+		}
+		out.Branches = append(out.Branches, branchJson{
+			Name:    branch.name,
+			Hash:    branch.hash,
+			Remote:  branch.remote,
+			Current: !branch.remote && branch.name == currentBranch,
+		})
+	}
+	if err := printJson(out); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+	}
+	return 0
+}
+
 func printCurrentBranch(sqlCtx *sql.Context, queryEngine cli.Queryist) int {
 	currentBranchName, err := getActiveBranchName(sqlCtx, queryEngine)
 	if err != nil {
@@ -299,6 +352,31 @@ func printAllDatasets(ctx context.Context, dEnv *env.DoltEnv) int {
 	return 0
 }
 
+func createBranchesAsOf(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, usage cli.UsagePrinter) int {
+	if apr.NArg() > 0 {
+		return HandleVErrAndExitCode(errhand.BuildDError("--%s does not take any branch names", cli.AsOfParam).SetPrintUsage().Build(), usage)
+	}
+	asOfStr := apr.MustGetValue(cli.AsOfParam)
+	asOf, err := cli.ParseDate(asOfStr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: invalid --%s", cli.AsOfParam).AddCause(err).Build(), usage)
+	}
+	prefix := apr.GetValueOrDefault(cli.PrefixParam, "as-of-"+asOf.Format("20060102T150405"))
+
+	branches, err := actions.CreateBranchesAsOf(ctx, dEnv.DoltDB, asOf, prefix)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	for _, b := range branches {
+		h, err := b.Commit.HashOf()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+		}
+		cli.Printf("created branch '%s' at %s\n", b.Branch.GetPath(), h.String())
+	}
+	return 0
+}
+
 // generateBranchSql returns the query that will call the `DOLT_BRANCH` stored procedure.
 func generateBranchSql(args []string) (string, error) {
 	var buffer bytes.Buffer
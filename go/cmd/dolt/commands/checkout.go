@@ -92,8 +92,13 @@ func (cmd CheckoutCmd) Exec(ctx context.Context, commandStr string, args []strin
 		return 1
 	}
 
+	prevHead := headHashOrEmpty(ctx, dEnv)
+
 	if branchOrTrack {
 		verr := checkoutNewBranch(ctx, dEnv, apr)
+		if verr == nil {
+			runPostHook(ctx, dEnv, postCheckoutHook, prevHead, headHashOrEmpty(ctx, dEnv), "1")
+		}
 		return HandleVErrAndExitCode(verr, usagePrt)
 	}
 
@@ -110,6 +115,9 @@ func (cmd CheckoutCmd) Exec(ctx context.Context, commandStr string, args []strin
 		return HandleVErrAndExitCode(verr, usagePrt)
 	} else if isBranch {
 		verr := checkoutBranch(ctx, dEnv, name, force)
+		if verr == nil {
+			runPostHook(ctx, dEnv, postCheckoutHook, prevHead, headHashOrEmpty(ctx, dEnv), "1")
+		}
 		return HandleVErrAndExitCode(verr, usagePrt)
 	}
 
@@ -128,12 +136,20 @@ func (cmd CheckoutCmd) Exec(ctx context.Context, commandStr string, args []strin
 			HandleVErrAndExitCode(errhand.BuildDError(err.Error()).Build(), usagePrt)
 		}
 		verr := actions.ResetHard(ctx, dEnv, "HEAD", roots, headRef, ws)
+		if verr == nil {
+			runPostHook(ctx, dEnv, postCheckoutHook, prevHead, prevHead, "0")
+		}
 		return handleResetError(verr, usagePrt)
 	}
 
 	verr := checkoutTables(ctx, dEnv, args)
-	if verr != nil && apr.NArg() == 1 {
+	if verr == nil {
+		runPostHook(ctx, dEnv, postCheckoutHook, prevHead, prevHead, "0")
+	} else if apr.NArg() == 1 {
 		verr = checkoutRemoteBranchOrSuggestNew(ctx, dEnv, name)
+		if verr == nil {
+			runPostHook(ctx, dEnv, postCheckoutHook, prevHead, headHashOrEmpty(ctx, dEnv), "1")
+		}
 	}
 
 	return HandleVErrAndExitCode(verr, usagePrt)
@@ -250,6 +266,9 @@ func checkoutRemoteBranchOrSuggestNew(ctx context.Context, dEnv *env.DoltEnv, na
 }
 
 func checkoutNewBranchFromStartPt(ctx context.Context, dEnv *env.DoltEnv, newBranch, startPt string) errhand.VerboseError {
+	if err := env.CheckBranchName(dEnv.Config, newBranch); err != nil {
+		return errhand.BuildDError(err.Error()).Build()
+	}
 	err := actions.CreateBranchWithStartPt(ctx, dEnv.DbData(), newBranch, startPt, false, nil)
 	if err != nil {
 		return errhand.BuildDError(err.Error()).Build()
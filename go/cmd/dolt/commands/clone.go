@@ -42,9 +42,13 @@ var cloneDocs = cli.CommandDocumentationContent{
 After the clone, a plain {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} without arguments will update all the remote-tracking branches, and a {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} without arguments will in addition merge the remote branch into the current branch.
 
 This default configuration is achieved by creating references to the remote branch heads under {{.LessThan}}refs/remotes/origin{{.GreaterThan}}  and by creating a remote named 'origin'.
+
+With {{.EmphasisLeft}}--single-branch{{.EmphasisRight}}, only the history of a single branch is downloaded: the branch given by {{.EmphasisLeft}}--branch{{.EmphasisRight}}, or the remote's default branch. A single remote-tracking branch is created, and the remote's fetch spec is limited to that branch, so later fetches and pulls do not download other branches either.
+
+A repository on a host that can be connected to with ssh, and which has dolt installed, can be cloned using a url of the form {{.EmphasisLeft}}ssh://[user@]host[:port]/path{{.EmphasisRight}}, or the scp-like {{.EmphasisLeft}}[user@]host:path{{.EmphasisRight}}, in which a relative path is relative to the user's home directory. See {{.EmphasisLeft}}dolt remote{{.EmphasisRight}} for details.
 `,
 	Synopsis: []string{
-		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}]  [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
+		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}] [--single-branch] [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
 	},
 }
 
@@ -72,7 +76,10 @@ func (cmd CloneCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd CloneCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateCloneArgParser()
+	ap := cli.CreateCloneArgParser()
+	ap.SupportsFlag(cli.SingleBranchFlag, "", "Clone only the history of the branch given by {{.EmphasisLeft}}--branch{{.EmphasisRight}}, or the remote's default branch if none is given. Only a remote-tracking branch for that branch is created, and the remote is configured to fetch only that branch.")
+	ap.SupportsFlag(cli.VerifyFlag, "", "Verify that the data of every chunk downloaded hashes to the chunk's address, failing on the first chunk that doesn't. The chunks are pulled individually rather than copying the remote's table files. Defaults to the {{.EmphasisLeft}}fetch.verify{{.EmphasisRight}} config setting.")
+	return ap
 }
 
 // EventType returns the type of the event to log
@@ -97,6 +104,7 @@ func (cmd CloneCmd) Exec(ctx context.Context, commandStr string, args []string,
 func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEnv) errhand.VerboseError {
 	remoteName := apr.GetValueOrDefault(cli.RemoteParam, "origin")
 	branch := apr.GetValueOrDefault(cli.BranchParam, "")
+	singleBranch := apr.Contains(cli.SingleBranchFlag)
 	dir, urlStr, verr := parseArgs(apr)
 	if verr != nil {
 		return verr
@@ -121,7 +129,7 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 		return errhand.BuildDError("error: '%s' is not valid.", urlStr).Build()
 	}
 	var params map[string]string
-	params, verr = parseRemoteArgs(apr, scheme, remoteUrl)
+	params, verr = parseRemoteArgs(dEnv, apr, scheme, remoteUrl)
 	if verr != nil {
 		return verr
 	}
@@ -133,6 +141,22 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 		return verr
 	}
 
+	verify, err := env.VerifyFetches(dEnv.Config, apr.Contains(cli.VerifyFlag))
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if verify {
+		srcDB = srcDB.WithVerifiedPulls()
+	}
+
+	cacheDir, err := env.SharedCacheDir(dEnv.Config)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	if cacheDir != "" {
+		srcDB = srcDB.WithSharedCache(cacheDir)
+	}
+
 	// Create a new Dolt env for the clone
 	clonedEnv, err := actions.EnvForClone(ctx, srcDB.ValueReadWriter().Format(), r, dir, dEnv.FS, dEnv.Version, env.GetCurrentUserHomeDir)
 	if err != nil {
@@ -142,7 +166,7 @@ func clone(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEn
 	// Nil out the old Dolt env so we don't accidentally operate on the wrong database
 	dEnv = nil
 
-	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, clonedEnv)
+	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, singleBranch, clonedEnv, nil)
 	if err != nil {
 		// If we're cloning into a directory that already exists do not erase it. Otherwise
 		// make best effort to delete the directory we created.
@@ -179,7 +203,11 @@ func parseArgs(apr *argparser.ArgParseResults) (string, string, errhand.VerboseE
 	}
 
 	urlStr := apr.Arg(0)
-	_, err := earl.Parse(urlStr)
+	parsedUrlStr := urlStr
+	if sshUrl, ok := dbfactory.SSHURLFromSCPLike(urlStr); ok {
+		parsedUrlStr = sshUrl
+	}
+	_, err := earl.Parse(parsedUrlStr)
 
 	if err != nil {
 		return "", "", errhand.BuildDError("error: invalid remote url: " + urlStr).Build()
@@ -189,9 +217,9 @@ func parseArgs(apr *argparser.ArgParseResults) (string, string, errhand.VerboseE
 	if apr.NArg() == 2 {
 		dir = apr.Arg(1)
 	} else {
-		dir = path.Base(urlStr)
+		dir = path.Base(parsedUrlStr)
 		if dir == "." {
-			dir = path.Dir(urlStr)
+			dir = path.Dir(parsedUrlStr)
 		} else if dir == "/" {
 			return "", "", errhand.BuildDError("Could not infer repo name.  Please explicitly define a directory for this url").Build()
 		}
@@ -240,9 +268,9 @@ func getRemoteUserAndPassConfig(apr *argparser.ArgParseResults) (*creds.DoltCred
 	if !apr.Contains(cli.UserParam) {
 		return nil, nil
 	}
-	pass, found := os.LookupEnv("DOLT_REMOTE_PASSWORD")
+	pass, found := os.LookupEnv(env.RemotePasswordEnvVar)
 	if !found {
-		return nil, errhand.BuildDError("error: must set DOLT_REMOTE_PASSWORD environment variable to use --user param").Build()
+		return nil, errhand.BuildDError("error: must set %s environment variable to use --user param", env.RemotePasswordEnvVar).Build()
 	}
 	return &creds.DoltCredsForPass{
 		Username: apr.GetValueOrDefault(cli.UserParam, ""),
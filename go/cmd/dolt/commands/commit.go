@@ -52,7 +52,9 @@ The content to be added can be specified by using dolt add to incrementally \"ad
 
 The log message can be added with the parameter {{.EmphasisLeft}}-m <msg>{{.EmphasisRight}}.  If the {{.LessThan}}-m{{.GreaterThan}} parameter is not provided an editor will be opened where you can review the commit and provide a log message.
 
-The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}} (where {{.LessThan}}07:00{{.GreaterThan}} is the time zone offset)."`,
+The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}} (where {{.LessThan}}07:00{{.GreaterThan}} is the time zone offset).
+
+If an executable {{.EmphasisLeft}}.dolt/hooks/pre-commit{{.EmphasisRight}} exists, it is run before the commit is created and the commit is aborted if it exits with a nonzero status. The check can be skipped with {{.EmphasisLeft}}--no-verify{{.EmphasisRight}}. An executable {{.EmphasisLeft}}.dolt/hooks/post-commit{{.EmphasisRight}} is run after the commit is created."`,
 	Synopsis: []string{
 		"[options]",
 	},
@@ -85,7 +87,7 @@ func (cmd CommitCmd) RequiresRepo() bool {
 
 // Exec executes the command
 func (cmd CommitCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
-	res, skipped := performCommit(ctx, commandStr, args, cliCtx)
+	res, skipped := performCommit(ctx, commandStr, args, dEnv, cliCtx)
 	if res == 1 {
 		return res
 	}
@@ -103,8 +105,9 @@ func (cmd CommitCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 // performCommit creates a new Dolt commit using the specified |commandStr| and |args|. The response is an integer
 // status code indicating success or failure, as well as a boolean that indicates if the commit was skipped
-// (e.g. because --skip-empty was specified as an argument).
-func performCommit(ctx context.Context, commandStr string, args []string, cliCtx cli.CliContext) (int, bool) {
+// (e.g. because --skip-empty was specified as an argument). The pre-commit and post-commit hooks of |dEnv| are run
+// around the commit, unless |dEnv| is nil or --no-verify was given.
+func performCommit(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) (int, bool) {
 	ap := cli.CreateCommitArgParser()
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, commitDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
@@ -113,6 +116,12 @@ func performCommit(ctx context.Context, commandStr string, args []string, cliCtx
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), help), false
 	}
 
+	if !apr.Contains(cli.NoVerifyFlag) {
+		if verr := runHook(ctx, dEnv, preCommitHook); verr != nil {
+			return HandleVErrAndExitCode(verr, nil), false
+		}
+	}
+
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
 	if err != nil {
 		cli.Println(err.Error())
@@ -185,6 +194,8 @@ func performCommit(ctx context.Context, commandStr string, args []string, cliCtx
 		return 1, false
 	}
 
+	runPostHook(ctx, dEnv, postCommitHook)
+
 	return 0, false
 }
 
@@ -268,6 +279,21 @@ func constructParametrizedDoltCommitQuery(msg string, apr *argparser.ArgParseRes
 		writeToBuffer("--skip-empty")
 	}
 
+	if apr.Contains(cli.SignFlag) {
+		writeToBuffer("-S")
+	}
+
+	if apr.Contains(cli.NoSignFlag) {
+		writeToBuffer("--no-gpg-sign")
+	}
+
+	if metadata, ok := apr.GetValue(cli.MetadataParam); ok {
+		writeToBuffer("--metadata")
+		param = true
+		writeToBuffer("?")
+		params = append(params, metadata)
+	}
+
 	buffer.WriteString(")")
 	return buffer.String(), params, nil
 }
@@ -692,10 +718,14 @@ const (
 	conflictedIgnoredHeader     = `Tables with conflicting dolt_ignore patterns:`
 	conflictedIgnoredHeaderHelp = `  (use "dolt add -f <table>" to include in what will be committed)`
 
+	brokenViewsHeader     = `Views with missing dependencies:`
+	brokenViewsHeaderHelp = `  (use "dolt sql" to recreate or drop these views)`
+
 	statusFmt           = "\t%-18s%s"
 	statusRenameFmt     = "\t%-18s%s -> %s"
 	schemaConflictLabel = "schema conflict:"
 	bothModifiedLabel   = "both modified:"
+	brokenViewLabel     = "broken view:"
 )
 
 var tblDiffTypeToLabel = map[diff.TableDiffType]string{
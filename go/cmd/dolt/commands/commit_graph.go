@@ -0,0 +1,116 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	commitGraphWriteSubcommand  = "write"
+	commitGraphStatusSubcommand = "status"
+	commitGraphRemoveSubcommand = "remove"
+
+	commitGraphRewriteFlag = "rewrite"
+)
+
+var commitGraphDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Write and check the commit-graph file.`,
+	LongDesc: `The commit-graph file holds the height and the parents of each commit in the repository, so that finding merge bases, listing the commits on one branch but not another with {{.EmphasisLeft}}dolt log{{.EmphasisRight}}, and counting how far a branch is ahead of or behind another don't have to read every commit between them from the database.
+
+{{.EmphasisLeft}}dolt commit-graph write{{.EmphasisRight}} adds the commits reachable from every branch, remote branch and tag to the commit-graph file, creating it if needed. Commits made after the file was last written are still found, but are read from the database, so the file should be written again from time to time. {{.EmphasisLeft}}dolt gc{{.EmphasisRight}} rewrites it if it exists.
+
+{{.EmphasisLeft}}dolt commit-graph status{{.EmphasisRight}} prints how many commits are in the file, and {{.EmphasisLeft}}dolt commit-graph remove{{.EmphasisRight}} deletes it.`,
+	Synopsis: []string{
+		`write [--rewrite]`,
+		`status`,
+		`remove`,
+	},
+}
+
+type CommitGraphCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd CommitGraphCmd) Name() string {
+	return "commit-graph"
+}
+
+// Description returns a description of the command
+func (cmd CommitGraphCmd) Description() string {
+	return commitGraphDocs.ShortDesc
+}
+
+func (cmd CommitGraphCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(commitGraphDocs, ap)
+}
+
+func (cmd CommitGraphCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"subcommand", "One of write, status or remove."})
+	ap.SupportsFlag(commitGraphRewriteFlag, "", "Write the commit-graph file from scratch, instead of adding to the existing one.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd CommitGraphCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd CommitGraphCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, commitGraphDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	switch apr.Arg(0) {
+	case commitGraphWriteSubcommand:
+		g, added, err := dEnv.WriteCommitGraph(ctx, apr.Contains(commitGraphRewriteFlag))
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("failed to write the commit-graph file").AddCause(err).Build(), usage)
+		}
+		cli.Printf("wrote %d commits to the commit-graph, %d new\n", g.Len(), added)
+	case commitGraphStatusSubcommand:
+		if !dEnv.HasCommitGraph() {
+			cli.Println("no commit-graph file")
+			return 0
+		}
+		g, err := dEnv.ReadCommitGraph()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("failed to read the commit-graph file").AddCause(err).Build(), usage)
+		}
+		cli.Printf("%d commits in the commit-graph\n", g.Len())
+	case commitGraphRemoveSubcommand:
+		if err := dEnv.RemoveCommitGraph(); err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("failed to remove the commit-graph file").AddCause(err).Build(), usage)
+		}
+	default:
+		verr := errhand.BuildDError("unknown subcommand '%s'; must be %s, %s or %s", apr.Arg(0),
+			commitGraphWriteSubcommand, commitGraphStatusSubcommand, commitGraphRemoveSubcommand).SetPrintUsage().Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+	return 0
+}
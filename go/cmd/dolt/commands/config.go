@@ -51,8 +51,14 @@ Valid configuration variables:
 
 	- creds.add_url - sets the endpoint used to authenticate a client for 'dolt login'.
 
+	- creds.helper - a credential helper, a program run to get the credentials used to authenticate with http and https remotes that don't have credentials of their own, instead of those selected by user.creds. See 'dolt remote' for the protocol it follows.
+
 	- doltlab.insecure - boolean flag used to authenticate a client against DoltLab.
 
+	- fetch.shared_cache_dir - a directory of table files shared by the databases on a machine. Fetch, pull and clone download chunks through it, skipping those it already has, and hard link the table files they receive from it, so clones of the same remote share their data on disk.
+
+	- fetch.verify - if set to "true" assume --verify for fetch, pull and clone, verifying that every chunk downloaded hashes to its address.
+
 	- init.defaultbranch - allows overriding the default branch name e.g. when initializing a new repository.
 
 	- metrics.disabled - boolean flag disables sending metrics when true.
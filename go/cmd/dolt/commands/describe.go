@@ -0,0 +1,214 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	describeLongFlag    = "long"
+	describeAlwaysFlag  = "always"
+	describeAbbrevParam = "abbrev"
+
+	defaultDescribeAbbrev = 7
+)
+
+var describeDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Describe a commit using the most recent tag reachable from it.`,
+	LongDesc: `Finds the most recent tag that is reachable from a commit, and describes the commit relative to it. If the tag points to the commit itself, only the tag name is shown. Otherwise, the tag name is suffixed with the number of commits on top of the tagged commit and the abbreviated hash of the commit, e.g. {{.EmphasisLeft}}v2.1.0-14-ga1b2c3d{{.EmphasisRight}}.
+
+If no {{.LessThan}}commit{{.GreaterThan}} is given, {{.EmphasisLeft}}HEAD{{.EmphasisRight}} is described. When several tags point to the nearest tagged commit, the most recently created one is used.`,
+	Synopsis: []string{
+		`[--long] [--always] [--abbrev {{.LessThan}}n{{.GreaterThan}}] [{{.LessThan}}commit{{.GreaterThan}}]`,
+	},
+}
+
+type DescribeCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd DescribeCmd) Name() string {
+	return "describe"
+}
+
+// Description returns a description of the command
+func (cmd DescribeCmd) Description() string {
+	return describeDocs.ShortDesc
+}
+
+func (cmd DescribeCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(describeDocs, ap)
+}
+
+func (cmd DescribeCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "The commit to describe. Defaults to HEAD."})
+	ap.SupportsFlag(describeLongFlag, "", "Always output the commit count and abbreviated hash, even when the commit is tagged.")
+	ap.SupportsFlag(describeAlwaysFlag, "", "Output the abbreviated commit hash if no tag is reachable from the commit.")
+	ap.SupportsInt(describeAbbrevParam, "", "n", fmt.Sprintf("Number of characters of the commit hash to output. Defaults to %d.", defaultDescribeAbbrev))
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd DescribeCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd DescribeCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, describeDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	abbrev := apr.GetIntOrDefault(describeAbbrevParam, defaultDescribeAbbrev)
+	if abbrev < 1 || abbrev > hash.StringLen {
+		return HandleVErrAndExitCode(errhand.BuildDError("--%s must be between 1 and %d", describeAbbrevParam, hash.StringLen).Build(), usage)
+	}
+
+	cSpecStr := "HEAD"
+	if apr.NArg() == 1 {
+		cSpecStr = apr.Arg(0)
+	}
+
+	cm, verr := ResolveCommitWithVErr(dEnv, cSpecStr)
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	description, verr := describeCommit(ctx, dEnv.DoltDB, cm, abbrev, apr.Contains(describeLongFlag), apr.Contains(describeAlwaysFlag))
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	cli.Println(description)
+	return 0
+}
+
+// describeCommit returns the description of |cm| relative to the nearest tag reachable from it
+func describeCommit(ctx context.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit, abbrev int, long, always bool) (string, errhand.VerboseError) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", errhand.BuildDError("error getting commit hash").AddCause(err).Build()
+	}
+	abbrevHash := h.String()[:abbrev]
+
+	tagsByCommit, err := newestTagsByCommit(ctx, ddb)
+	if err != nil {
+		return "", errhand.BuildDError("error reading tags").AddCause(err).Build()
+	}
+
+	tagName, tagCommit, found, err := nearestTag(ctx, ddb, h, tagsByCommit)
+	if err != nil {
+		return "", errhand.BuildDError("error walking commit history").AddCause(err).Build()
+	}
+
+	if !found {
+		if always {
+			return abbrevHash, nil
+		}
+		return "", errhand.BuildDError("no tags can describe '%s'; use --%s to show the commit hash instead", h.String(), describeAlwaysFlag).Build()
+	}
+
+	if tagCommit == h && !long {
+		return tagName, nil
+	}
+
+	distance, err := countCommitsSince(ctx, ddb, h, tagCommit)
+	if err != nil {
+		return "", errhand.BuildDError("error counting commits since %s", tagName).AddCause(err).Build()
+	}
+
+	return tagName + "-" + strconv.Itoa(distance) + "-g" + abbrevHash, nil
+}
+
+// newestTagsByCommit returns the name of the most recently created tag pointing at each tagged commit
+func newestTagsByCommit(ctx context.Context, ddb *doltdb.DoltDB) (map[hash.Hash]string, error) {
+	tags, err := ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newest := make(map[hash.Hash]*doltdb.Tag)
+	for _, t := range tags {
+		curr, ok := newest[t.Hash]
+		if !ok || t.Tag.Meta.Timestamp > curr.Meta.Timestamp ||
+			(t.Tag.Meta.Timestamp == curr.Meta.Timestamp && t.Tag.Name > curr.Name) {
+			newest[t.Hash] = t.Tag
+		}
+	}
+
+	names := make(map[hash.Hash]string, len(newest))
+	for h, t := range newest {
+		names[h] = t.Name
+	}
+	return names, nil
+}
+
+// nearestTag walks the history of |start| in topological order, returning the first tagged commit found
+func nearestTag(ctx context.Context, ddb *doltdb.DoltDB, start hash.Hash, tagsByCommit map[hash.Hash]string) (string, hash.Hash, bool, error) {
+	if len(tagsByCommit) == 0 {
+		return "", hash.Hash{}, false, nil
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{start}, nil)
+	if err != nil {
+		return "", hash.Hash{}, false, err
+	}
+
+	for {
+		h, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			return "", hash.Hash{}, false, nil
+		} else if err != nil {
+			return "", hash.Hash{}, false, err
+		}
+
+		if name, ok := tagsByCommit[h]; ok {
+			return name, h, true, nil
+		}
+	}
+}
+
+// countCommitsSince returns the number of commits reachable from |start| that aren't reachable from |since|
+func countCommitsSince(ctx context.Context, ddb *doltdb.DoltDB, start, since hash.Hash) (int, error) {
+	itr, err := commitwalk.GetDotDotRevisionsIterator(ctx, ddb, []hash.Hash{start}, ddb, []hash.Hash{since}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for {
+		_, _, err := itr.Next(ctx)
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 type diffOutput int
@@ -61,10 +62,15 @@ const (
 	SummaryFlag = "summary"
 	whereParam  = "where"
 	limitParam  = "limit"
+	minKeyParam = "min-key"
+	maxKeyParam = "max-key"
 	SkinnyFlag  = "skinny"
 	MergeBase   = "merge-base"
 	DiffMode    = "diff-mode"
 	ReverseFlag = "reverse"
+
+	KeylessCountsFlag = "keyless-counts"
+	KeylessHashFlag   = "keyless-hash"
 )
 
 var diffDocs = cli.CommandDocumentationContent{
@@ -87,10 +93,17 @@ Show changes between the working and staged tables, changes between the working
 {{.EmphasisLeft}}dolt diff [--options] <commit>...<commit> [<tables>...]{{.EmphasisRight}}
    This is to view the changes on the branch containing and up to the second {{.LessThan}}commit{{.GreaterThan}}, starting at a common ancestor of both {{.LessThan}}commit{{.GreaterThan}}. {{.EmphasisLeft}}dolt diff A...B{{.EmphasisRight}} is equivalent to {{.EmphasisLeft}}dolt diff $(dolt merge-base A B) B{{.EmphasisRight}} and {{.EmphasisLeft}}dolt diff --merge-base A B{{.EmphasisRight}}. You can omit any one of {{.LessThan}}commit{{.GreaterThan}}, which has the same effect as using HEAD instead.
 
+{{.EmphasisLeft}}dolt diff [--options] <database>/<commit> <database>/<commit> [<tables>...]{{.EmphasisRight}}
+   This is to view the changes between commits in two different databases, such as a staging clone and production. A {{.LessThan}}commit{{.GreaterThan}} that doesn't resolve in the current database can be prefixed with the name of a database in a directory beside it, e.g. {{.EmphasisLeft}}dolt diff staging/main prod/main mytable{{.EmphasisRight}} run in the {{.EmphasisLeft}}prod{{.EmphasisRight}} directory.
+
 The diffs displayed can be limited to show the first N by providing the parameter {{.EmphasisLeft}}--limit N{{.EmphasisRight}} where {{.EmphasisLeft}}N{{.EmphasisRight}} is the number of diffs to display.
 
 To filter which data rows are displayed, use {{.EmphasisLeft}}--where <SQL expression>{{.EmphasisRight}}. Table column names in the filter expression must be prefixed with {{.EmphasisLeft}}from_{{.EmphasisRight}} or {{.EmphasisLeft}}to_{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}to_COLUMN_NAME > 100{{.EmphasisRight}} or {{.EmphasisLeft}}from_COLUMN_NAME + to_COLUMN_NAME = 0{{.EmphasisRight}}.
 
+To only diff the rows within a range of primary keys, use {{.EmphasisLeft}}--min-key <value>{{.EmphasisRight}} and/or {{.EmphasisLeft}}--max-key <value>{{.EmphasisRight}}, which bound the first primary key column of the rows diffed, inclusively. Unlike {{.EmphasisLeft}}--where{{.EmphasisRight}}, the bounds are searched for in the table's storage, so only the rows within the range are read, e.g. {{.EmphasisLeft}}dolt diff HEAD~ HEAD orders --min-key 42 --max-key 42{{.EmphasisRight}} for the changes to the orders of the customer with id 42 in a table keyed by {{.EmphasisLeft}}(customer_id, order_id){{.EmphasisRight}}.
+
+Keyless tables can hold many copies of the same row, and each copy that was added or removed is shown as its own row by default. Use {{.EmphasisLeft}}--keyless-counts{{.EmphasisRight}} to show each distinct row once with the number of copies added or removed, and {{.EmphasisLeft}}--keyless-hash{{.EmphasisRight}} to show a hash of each row's contents that identifies it the way a primary key would. These options can't be used with {{.EmphasisLeft}}-r sql{{.EmphasisRight}} or {{.EmphasisLeft}}--skinny{{.EmphasisRight}}.
+
 The {{.EmphasisLeft}}--diff-mode{{.EmphasisRight}} argument controls how modified rows are presented when the format output is set to {{.EmphasisLeft}}tabular{{.EmphasisRight}}. When set to {{.EmphasisLeft}}row{{.EmphasisRight}}, modified rows are presented as old and new rows. When set to {{.EmphasisLeft}}line{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented using "+" and "-" within the column. When set to {{.EmphasisLeft}}in-place{{.EmphasisRight}}, modified rows are presented as a single row, and changes are presented side-by-side with a color distinction (requires a color-enabled terminal). When set to {{.EmphasisLeft}}context{{.EmphasisRight}}, rows that contain at least one column that spans multiple lines uses {{.EmphasisLeft}}line{{.EmphasisRight}}, while all other rows use {{.EmphasisLeft}}row{{.EmphasisRight}}. The default value is {{.EmphasisLeft}}context{{.EmphasisRight}}.
 `,
 	Synopsis: []string{
@@ -106,6 +119,13 @@ type diffDisplaySettings struct {
 	limit      int
 	where      string
 	skinny     bool
+
+	// minKey and maxKey bound the first primary key column of the rows diffed, if set
+	minKey, maxKey *string
+
+	// keylessCounts and keylessHash control how the rows of keyless tables are displayed
+	keylessCounts bool
+	keylessHash   bool
 }
 
 type diffDatasets struct {
@@ -119,6 +139,10 @@ type diffArgs struct {
 	*diffDisplaySettings
 	*diffDatasets
 	tableSet *set.StrSet
+	// databases holds the other databases named by revisions of the form `<database>/<ref>`, keyed by name
+	databases map[string]*env.DoltEnv
+	// jsonOut is where JSON output is written, or the CLI's output if it's nil
+	jsonOut io.Writer
 }
 
 type DiffCmd struct{}
@@ -150,8 +174,13 @@ func (cmd DiffCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(StatFlag, "", "Show stats of data changes")
 	ap.SupportsFlag(SummaryFlag, "", "Show summary of data and schema changes")
 	ap.SupportsString(FormatFlag, "r", "result output format", "How to format diff output. Valid values are tabular, sql, json. Defaults to tabular.")
+	ap.SupportsAlias(formatFlag, FormatFlag)
 	ap.SupportsString(whereParam, "", "column", "filters columns based on values in the diff.  See {{.EmphasisLeft}}dolt diff --help{{.EmphasisRight}} for details.")
 	ap.SupportsInt(limitParam, "", "record_count", "limits to the first N diffs.")
+	ap.SupportsString(minKeyParam, "", "value", "limits data diffs to rows whose first primary key column is at least {{.LessThan}}value{{.GreaterThan}}.")
+	ap.SupportsString(maxKeyParam, "", "value", "limits data diffs to rows whose first primary key column is at most {{.LessThan}}value{{.GreaterThan}}.")
+	ap.SupportsFlag(KeylessCountsFlag, "", "For keyless tables, show each distinct row that was added or removed once, with the number of copies of it that were added or removed.")
+	ap.SupportsFlag(KeylessHashFlag, "", "For keyless tables, show a hash of each row's contents that identifies it like a primary key.")
 	ap.SupportsFlag(cli.CachedFlag, "c", "Show only the staged data changes.")
 	ap.SupportsFlag(SkinnyFlag, "sk", "Shows only primary key columns and any columns with data changes.")
 	ap.SupportsFlag(MergeBase, "", "Uses merge base of the first commit and second commit (or HEAD if not supplied) as the first commit")
@@ -194,6 +223,15 @@ func (cmd DiffCmd) validateArgs(apr *argparser.ArgParseResults) errhand.VerboseE
 		return errhand.BuildDError("invalid output format: %s", f).Build()
 	}
 
+	if apr.Contains(KeylessCountsFlag) || apr.Contains(KeylessHashFlag) {
+		if strings.ToLower(f) == "sql" {
+			return errhand.BuildDError("invalid Arguments: --keyless-counts and --keyless-hash cannot be used with sql output").Build()
+		}
+		if apr.Contains(SkinnyFlag) {
+			return errhand.BuildDError("invalid Arguments: --keyless-counts and --keyless-hash cannot be combined with --skinny").Build()
+		}
+	}
+
 	return nil
 }
 
@@ -212,6 +250,8 @@ func parseDiffDisplaySettings(ctx context.Context, dEnv *env.DoltEnv, apr *argpa
 	}
 
 	displaySettings.skinny = apr.Contains(SkinnyFlag)
+	displaySettings.keylessCounts = apr.Contains(KeylessCountsFlag)
+	displaySettings.keylessHash = apr.Contains(KeylessHashFlag)
 
 	f := apr.GetValueOrDefault(FormatFlag, "tabular")
 	switch strings.ToLower(f) {
@@ -235,6 +275,12 @@ func parseDiffDisplaySettings(ctx context.Context, dEnv *env.DoltEnv, apr *argpa
 
 	displaySettings.limit, _ = apr.GetInt(limitParam)
 	displaySettings.where = apr.GetValueOrDefault(whereParam, "")
+	if minKey, ok := apr.GetValue(minKeyParam); ok {
+		displaySettings.minKey = &minKey
+	}
+	if maxKey, ok := apr.GetValue(maxKeyParam); ok {
+		displaySettings.maxKey = &maxKey
+	}
 
 	return displaySettings
 }
@@ -360,7 +406,7 @@ func (dArgs *diffArgs) applyDiffRoots(ctx context.Context, dEnv *env.DoltEnv, ar
 	}
 
 	// treat the first arg as a ref spec
-	fromRoot, ok := diff.MaybeResolveRoot(ctx, dEnv.RepoStateReader(), dEnv.DoltDB, args[0])
+	fromRoot, ok := dArgs.resolveRoot(ctx, dEnv, args[0])
 	// if it doesn't resolve, treat it as a table name
 	if !ok {
 		// `dolt diff table`
@@ -384,7 +430,7 @@ func (dArgs *diffArgs) applyDiffRoots(ctx context.Context, dEnv *env.DoltEnv, ar
 		return nil, nil
 	}
 
-	toRoot, ok := diff.MaybeResolveRoot(ctx, dEnv.RepoStateReader(), dEnv.DoltDB, args[1])
+	toRoot, ok := dArgs.resolveRoot(ctx, dEnv, args[1])
 	if !ok {
 		// `dolt diff from_commit [...tables]`
 		if useMergeBase {
@@ -410,6 +456,41 @@ func (dArgs *diffArgs) applyDiffRoots(ctx context.Context, dEnv *env.DoltEnv, ar
 	return args[2:], nil
 }
 
+// resolveRoot resolves |spec| to a root value. Specs that don't resolve in this database may name a ref in another
+// database as `<database>/<ref>`, where the database is in a directory beside this one, e.g. a staging clone of a
+// production database. The tables of the two databases are compared chunk-wise, so only the parts that differ are read.
+func (dArgs *diffArgs) resolveRoot(ctx context.Context, dEnv *env.DoltEnv, spec string) (*doltdb.RootValue, bool) {
+	if root, ok := diff.MaybeResolveRoot(ctx, dEnv.RepoStateReader(), dEnv.DoltDB, spec); ok {
+		return root, true
+	}
+
+	dbName, refSpec, ok := strings.Cut(spec, "/")
+	if !ok || len(refSpec) == 0 {
+		return nil, false
+	}
+
+	otherEnv, ok := dArgs.databases[dbName]
+	if !ok {
+		var err error
+		otherEnv, err = env.LoadSiblingEnv(ctx, dEnv, dbName)
+		if err != nil || otherEnv == nil {
+			return nil, false
+		}
+	}
+
+	root, ok := diff.MaybeResolveRoot(ctx, otherEnv.RepoStateReader(), otherEnv.DoltDB, refSpec)
+	if !ok {
+		return nil, false
+	}
+	if otherEnv != dEnv {
+		if dArgs.databases == nil {
+			dArgs.databases = make(map[string]*env.DoltEnv)
+		}
+		dArgs.databases[dbName] = otherEnv
+	}
+	return root, true
+}
+
 // applyMergeBase applies the merge base of two revisions to the |from| root
 // values.
 func (dArgs *diffArgs) applyMergeBase(ctx context.Context, dEnv *env.DoltEnv, leftStr, rightStr string) error {
@@ -452,7 +533,7 @@ func (dArgs *diffArgs) applyDotRevisions(ctx context.Context, dEnv *env.DoltEnv,
 		}
 
 		if len(refs[1]) > 0 {
-			if toRoot, ok = diff.MaybeResolveRoot(ctx, dEnv.RepoStateReader(), dEnv.DoltDB, refs[1]); !ok {
+			if toRoot, ok = dArgs.resolveRoot(ctx, dEnv, refs[1]); !ok {
 				return fmt.Errorf("to ref in three dot diff must be valid ref: %s", refs[1])
 			}
 			dArgs.toRoot = toRoot
@@ -470,7 +551,7 @@ func (dArgs *diffArgs) applyDotRevisions(ctx context.Context, dEnv *env.DoltEnv,
 		ok := true
 
 		if len(refs[0]) > 0 {
-			if fromRoot, ok = diff.MaybeResolveRoot(ctx, dEnv.RepoStateReader(), dEnv.DoltDB, refs[0]); !ok {
+			if fromRoot, ok = dArgs.resolveRoot(ctx, dEnv, refs[0]); !ok {
 				return fmt.Errorf("from ref in two dot diff must be valid ref: %s", refs[0])
 			}
 			dArgs.fromRoot = fromRoot
@@ -478,7 +559,7 @@ func (dArgs *diffArgs) applyDotRevisions(ctx context.Context, dEnv *env.DoltEnv,
 		}
 
 		if len(refs[1]) > 0 {
-			if toRoot, ok = diff.MaybeResolveRoot(ctx, dEnv.RepoStateReader(), dEnv.DoltDB, refs[1]); !ok {
+			if toRoot, ok = dArgs.resolveRoot(ctx, dEnv, refs[1]); !ok {
 				return fmt.Errorf("to ref in two dot diff must be valid ref: %s", refs[1])
 			}
 			dArgs.toRoot = toRoot
@@ -538,7 +619,7 @@ func diffUserTables(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) err
 		return errhand.BuildDError("error: unable to diff tables").AddCause(err).Build()
 	}
 
-	sqlEng, dbName, err := engine.NewSqlEngineForEnv(ctx, dEnv)
+	sqlEng, dbName, err := newDiffSqlEngine(ctx, dEnv, dArgs)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
@@ -557,7 +638,11 @@ func diffUserTables(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) err
 		return printDiffSummary(ctx, tableDeltas, dArgs)
 	}
 
-	dw, err := newDiffWriter(dArgs.diffOutput)
+	var jsonOut io.Writer = cli.CliOut
+	if dArgs.jsonOut != nil {
+		jsonOut = dArgs.jsonOut
+	}
+	dw, err := newDiffWriter(dArgs.diffOutput, jsonOut)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
@@ -588,6 +673,7 @@ func diffUserTables(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) err
 	}
 
 	doltSchemasChanged := false
+	doltProceduresChanged := false
 	for _, td := range tableDeltas {
 		// Don't print tables if one side of the diff is an ignored table in the working set being added.
 		if toRootHash == workingSetHash && td.FromTable == nil {
@@ -617,6 +703,9 @@ func diffUserTables(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) err
 		if isDoltSchemasTable(td) {
 			// save dolt_schemas table diff for last in diff output
 			doltSchemasChanged = true
+		} else if isDoltProceduresTable(td) {
+			// save dolt_procedures table diff for last in diff output, after dolt_schemas
+			doltProceduresChanged = true
 		} else {
 			verr := diffUserTable(sqlCtx, td, sqlEng, dArgs, dw)
 			if verr != nil {
@@ -632,6 +721,13 @@ func diffUserTables(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) err
 		}
 	}
 
+	if doltProceduresChanged {
+		verr := diffDoltProceduresTable(sqlCtx, sqlEng, dArgs, dw)
+		if verr != nil {
+			return verr
+		}
+	}
+
 	err = dw.Close(ctx)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
@@ -640,6 +736,34 @@ func diffUserTables(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) err
 	return nil
 }
 
+// newDiffSqlEngine returns a SqlEngine for the database in |dEnv| that also serves any other databases whose refs are
+// being diffed
+func newDiffSqlEngine(ctx context.Context, dEnv *env.DoltEnv, dArgs *diffArgs) (*engine.SqlEngine, string, error) {
+	if len(dArgs.databases) == 0 {
+		return engine.NewSqlEngineForEnv(ctx, dEnv)
+	}
+
+	mrEnv, err := env.MultiEnvForSingleEnv(ctx, dEnv)
+	if err != nil {
+		return nil, "", err
+	}
+	for name, otherEnv := range dArgs.databases {
+		if mrEnv.GetEnv(name) == nil {
+			mrEnv.AddEnv(name, otherEnv)
+		}
+	}
+
+	sqlEng, err := engine.NewSqlEngine(
+		ctx,
+		mrEnv,
+		&engine.SqlEngineConfig{
+			ServerUser: "root",
+			ServerHost: "localhost",
+		},
+	)
+	return sqlEng, mrEnv.GetFirstDatabase(), err
+}
+
 func shouldPrintTableDelta(tablesToPrint *set.StrSet, td diff.TableDelta) bool {
 	// TODO: this should be case insensitive
 	return tablesToPrint.Contains(td.FromName) || tablesToPrint.Contains(td.ToName)
@@ -649,6 +773,10 @@ func isDoltSchemasTable(td diff.TableDelta) bool {
 	return td.FromName == doltdb.SchemasTableName || td.ToName == doltdb.SchemasTableName
 }
 
+func isDoltProceduresTable(td diff.TableDelta) bool {
+	return td.FromName == doltdb.ProceduresTableName || td.ToName == doltdb.ProceduresTableName
+}
+
 func diffUserTable(
 	ctx *sql.Context,
 	td diff.TableDelta,
@@ -779,6 +907,64 @@ func diffDoltSchemasTable(
 	return nil
 }
 
+func diffDoltProceduresTable(
+	sqlCtx *sql.Context,
+	sqlEng *engine.SqlEngine,
+	dArgs *diffArgs,
+	dw diffWriter,
+) errhand.VerboseError {
+	// procedures that were dropped and recreated unchanged only differ in their modified_at time, so skip them
+	query := fmt.Sprintf("select from_name,to_name,from_create_stmt,to_create_stmt "+
+		"from dolt_diff('%s','%s','%s') "+
+		"where not (from_create_stmt <=> to_create_stmt) "+
+		"order by coalesce(from_name, to_name)",
+		dArgs.fromRef, dArgs.toRef, doltdb.ProceduresTableName)
+
+	_, rowIter, err := sqlEng.Query(sqlCtx, query)
+	if err != nil {
+		return errhand.BuildDError("Error running diff query:\n%s", query).AddCause(err).Build()
+	}
+
+	defer rowIter.Close(sqlCtx)
+	for {
+		row, err := rowIter.Next(sqlCtx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+
+		var procedureName string
+		if row[0] != nil {
+			procedureName = row[0].(string)
+		} else {
+			procedureName = row[1].(string)
+		}
+
+		var oldStmt string
+		var newStmt string
+		if row[2] != nil {
+			oldStmt = row[2].(string)
+			if len(oldStmt) > 0 && oldStmt[len(oldStmt)-1] != ';' {
+				oldStmt += ";"
+			}
+		}
+		if row[3] != nil {
+			newStmt = row[3].(string)
+			if len(newStmt) > 0 && newStmt[len(newStmt)-1] != ';' {
+				newStmt += ";"
+			}
+		}
+
+		err = dw.WriteProcedureDiff(sqlCtx, procedureName, oldStmt, newStmt)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+	}
+
+	return nil
+}
+
 func diffRows(
 	ctx *sql.Context,
 	sqlEng *engine.SqlEngine,
@@ -807,8 +993,14 @@ func diffRows(
 
 	unionSch := unionSchemas(fromSch, toSch)
 
+	keylessDisplay := isKeylessTableDelta(td) && (dArgs.keylessCounts || dArgs.keylessHash)
+	displaySch := unionSch
+	if keylessDisplay {
+		displaySch = keylessDisplaySchema(unionSch, dArgs)
+	}
+
 	// We always instantiate a RowWriter in case the diffWriter needs it to close off any work from schema output
-	rowWriter, err := dw.RowWriter(ctx, td, unionSch)
+	rowWriter, err := dw.RowWriter(ctx, td, displaySch)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
@@ -849,6 +1041,11 @@ func diffRows(
 
 	columns := getColumnNamesString(td.FromSch, td.ToSch)
 	query := fmt.Sprintf("select %s, %s from dolt_diff('%s', '%s', '%s')", columns, "diff_type", dArgs.fromRef, dArgs.toRef, tableName)
+	if dArgs.minKey != nil || dArgs.maxKey != nil {
+		// the key range is searched for in the table's prolly trees, so rows outside of it are never read
+		query = fmt.Sprintf("select %s, %s from dolt_diff('%s', '%s', '%s', %s, %s)", columns, "diff_type",
+			dArgs.fromRef, dArgs.toRef, tableName, sqlKeyBound(dArgs.minKey), sqlKeyBound(dArgs.maxKey))
+	}
 
 	if len(dArgs.where) > 0 {
 		query += " where " + dArgs.where
@@ -906,7 +1103,11 @@ func diffRows(
 		}
 	}
 
-	err = writeDiffResults(ctx, sch, unionSch, rowIter, rowWriter, modifiedColNames, dArgs)
+	if keylessDisplay {
+		err = writeKeylessDiffResults(ctx, sch, unionSch, rowIter, rowWriter, dArgs)
+	} else {
+		err = writeDiffResults(ctx, sch, unionSch, rowIter, rowWriter, modifiedColNames, dArgs)
+	}
 	if err != nil {
 		return errhand.BuildDError("Error running diff query:\n%s", query).AddCause(err).Build()
 	}
@@ -914,6 +1115,129 @@ func diffRows(
 	return nil
 }
 
+// sqlKeyBound returns the SQL literal for a key range bound given to the command
+func sqlKeyBound(bound *string) string {
+	if bound == nil {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(*bound, "'", "''") + "'"
+}
+
+func isKeylessTableDelta(td diff.TableDelta) bool {
+	return (td.FromSch == nil || schema.IsKeyless(td.FromSch)) && (td.ToSch == nil || schema.IsKeyless(td.ToSch))
+}
+
+// keylessDisplaySchema returns the schema used to display the diff of a keyless table with the columns of |unionSch|,
+// with a leading row_hash column and a trailing count column if they were asked for
+func keylessDisplaySchema(unionSch sql.Schema, dArgs *diffArgs) sql.Schema {
+	var displaySch sql.Schema
+	if dArgs.keylessHash {
+		displaySch = append(displaySch, &sql.Column{Name: "row_hash", Type: types.Text})
+	}
+	displaySch = append(displaySch, unionSch...)
+	if dArgs.keylessCounts {
+		displaySch = append(displaySch, &sql.Column{Name: "count", Type: types.Int64})
+	}
+	return displaySch
+}
+
+// writeKeylessDiffResults writes the diff of a keyless table. Copies of a row are diffed one at a time, so with
+// --keyless-counts consecutive copies of a row that were all added or all removed are written as a single row with
+// their count. With --keyless-hash each row is written with a hash of its contents, which identifies its copies the
+// way a primary key would.
+func writeKeylessDiffResults(
+	ctx *sql.Context,
+	diffQuerySch sql.Schema,
+	targetSch sql.Schema,
+	iter sql.RowIter,
+	writer diff.SqlRowDiffWriter,
+	dArgs *diffArgs,
+) error {
+	ds, err := diff.NewDiffSplitter(diffQuerySch, targetSch)
+	if err != nil {
+		return err
+	}
+
+	var pending diff.RowDiff
+	var count int64
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+		row, colDiffs := pending.Row, pending.ColDiffs
+		if dArgs.keylessHash {
+			row = append(sql.Row{keylessRowHash(pending.Row)}, row...)
+			colDiffs = append([]diff.ChangeType{pending.RowDiff}, colDiffs...)
+		}
+		if dArgs.keylessCounts {
+			row = append(row, count)
+			colDiffs = append(colDiffs, pending.RowDiff)
+		}
+		count = 0
+		return writer.WriteRow(ctx, row, pending.RowDiff, colDiffs)
+	}
+
+	for {
+		r, err := iter.Next(ctx)
+		if err == io.EOF {
+			return flush()
+		} else if err != nil {
+			return err
+		}
+
+		oldRow, newRow, err := ds.SplitDiffResultRow(r)
+		if err != nil {
+			return err
+		}
+
+		for _, rd := range []diff.RowDiff{oldRow, newRow} {
+			if rd.Row == nil {
+				continue
+			}
+			if count > 0 && dArgs.keylessCounts && rd.RowDiff == pending.RowDiff {
+				same, err := rowsEqual(targetSch, rd.Row, pending.Row)
+				if err != nil {
+					return err
+				}
+				if same {
+					count++
+					continue
+				}
+			}
+			if err = flush(); err != nil {
+				return err
+			}
+			pending, count = rd, 1
+		}
+	}
+}
+
+func rowsEqual(sch sql.Schema, left, right sql.Row) (bool, error) {
+	for i, col := range sch {
+		cmp, err := col.Type.Compare(left[i], right[i])
+		if err != nil {
+			return false, err
+		} else if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// keylessRowHash returns a hash of the values of |row|, used to identify the copies of a row in a keyless table
+func keylessRowHash(row sql.Row) string {
+	var sb strings.Builder
+	for _, v := range row {
+		if v == nil {
+			sb.WriteString("\\N")
+		} else {
+			fmt.Fprintf(&sb, "%v", v)
+		}
+		sb.WriteByte(0)
+	}
+	return hash.Of([]byte(sb.String())).String()
+}
+
 func unionSchemas(s1 sql.Schema, s2 sql.Schema) sql.Schema {
 	var union sql.Schema
 	for i := range s1 {
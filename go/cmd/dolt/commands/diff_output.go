@@ -52,6 +52,8 @@ type diffWriter interface {
 	WriteTriggerDiff(ctx context.Context, triggerName, oldDefn, newDefn string) error
 	// WriteViewDiff is called to write a view diff
 	WriteViewDiff(ctx context.Context, viewName, oldDefn, newDefn string) error
+	// WriteProcedureDiff is called to write a stored procedure diff
+	WriteProcedureDiff(ctx context.Context, procedureName, oldDefn, newDefn string) error
 	// RowWriter returns a row writer for the table delta provided, which will have Close() called on it when rows are
 	// done being written.
 	RowWriter(ctx context.Context, td diff.TableDelta, unionSch sql.Schema) (diff.SqlRowDiffWriter, error)
@@ -59,15 +61,16 @@ type diffWriter interface {
 	Close(ctx context.Context) error
 }
 
-// newDiffWriter returns a diffWriter for the output format given
-func newDiffWriter(diffOutput diffOutput) (diffWriter, error) {
+// newDiffWriter returns a diffWriter for the output format given. JSON output is written to |wr|, other formats are
+// printed to the CLI's output.
+func newDiffWriter(diffOutput diffOutput, wr io.Writer) (diffWriter, error) {
 	switch diffOutput {
 	case TabularDiffOutput:
 		return tabularDiffWriter{}, nil
 	case SQLDiffOutput:
 		return sqlDiffWriter{}, nil
 	case JsonDiffOutput:
-		return newJsonDiffWriter(iohelp.NopWrCloser(cli.CliOut))
+		return newJsonDiffWriter(iohelp.NopWrCloser(wr))
 	default:
 		panic(fmt.Sprintf("unexpected diff output: %v", diffOutput))
 	}
@@ -286,6 +289,11 @@ func (t tabularDiffWriter) WriteViewDiff(ctx context.Context, viewName, oldDefn,
 	return nil
 }
 
+func (t tabularDiffWriter) WriteProcedureDiff(ctx context.Context, procedureName, oldDefn, newDefn string) error {
+	// identical implementation
+	return t.WriteViewDiff(ctx, procedureName, oldDefn, newDefn)
+}
+
 func (t tabularDiffWriter) RowWriter(ctx context.Context, td diff.TableDelta, unionSch sql.Schema) (diff.SqlRowDiffWriter, error) {
 	return tabular.NewFixedWidthDiffTableWriter(unionSch, iohelp.NopWrCloser(cli.CliOut), 100), nil
 }
@@ -362,6 +370,20 @@ func (s sqlDiffWriter) WriteViewDiff(ctx context.Context, viewName, oldDefn, new
 	return nil
 }
 
+func (s sqlDiffWriter) WriteProcedureDiff(ctx context.Context, procedureName, oldDefn, newDefn string) error {
+	// definitions will already be semicolon terminated, no need to add additional ones
+	if oldDefn == "" {
+		cli.Println(newDefn)
+	} else if newDefn == "" {
+		cli.Println(fmt.Sprintf("DROP PROCEDURE %s;", sql.QuoteIdentifier(procedureName)))
+	} else {
+		cli.Println(fmt.Sprintf("DROP PROCEDURE %s;", sql.QuoteIdentifier(procedureName)))
+		cli.Println(newDefn)
+	}
+
+	return nil
+}
+
 func (s sqlDiffWriter) RowWriter(ctx context.Context, td diff.TableDelta, unionSch sql.Schema) (diff.SqlRowDiffWriter, error) {
 	targetSch := td.ToSch
 	if targetSch == nil {
@@ -372,13 +394,14 @@ func (s sqlDiffWriter) RowWriter(ctx context.Context, td diff.TableDelta, unionS
 }
 
 type jsonDiffWriter struct {
-	wr               io.WriteCloser
-	schemaDiffWriter diff.SchemaDiffWriter
-	rowDiffWriter    diff.SqlRowDiffWriter
-	tablesWritten    int
-	triggersWritten  int
-	viewsWritten     int
-	eventsWritten    int
+	wr                io.WriteCloser
+	schemaDiffWriter  diff.SchemaDiffWriter
+	rowDiffWriter     diff.SqlRowDiffWriter
+	tablesWritten     int
+	triggersWritten   int
+	viewsWritten      int
+	eventsWritten     int
+	proceduresWritten int
 }
 
 var _ diffWriter = (*tabularDiffWriter)(nil)
@@ -395,7 +418,7 @@ const jsonDiffDataDiffHeader = `],"data_diff":[`
 const jsonDataDiffFooter = `}]`
 
 func (j *jsonDiffWriter) beginDocumentIfNecessary() error {
-	if j.tablesWritten == 0 && j.triggersWritten == 0 && j.viewsWritten == 0 {
+	if j.tablesWritten == 0 && j.eventsWritten == 0 && j.triggersWritten == 0 && j.viewsWritten == 0 && j.proceduresWritten == 0 {
 		_, err := j.wr.Write([]byte("{"))
 		return err
 	}
@@ -479,7 +502,7 @@ func (j *jsonDiffWriter) RowWriter(ctx context.Context, td diff.TableDelta, unio
 		return nil, err
 	}
 
-	j.rowDiffWriter, err = json.NewJsonDiffWriter(iohelp.NopWrCloser(cli.CliOut), sch)
+	j.rowDiffWriter, err = json.NewJsonDiffWriter(iohelp.NopWrCloser(j.wr), sch)
 	return j.rowDiffWriter, err
 }
 
@@ -646,17 +669,74 @@ func (j *jsonDiffWriter) WriteViewDiff(ctx context.Context, viewName, oldDefn, n
 	return nil
 }
 
+func (j *jsonDiffWriter) WriteProcedureDiff(ctx context.Context, procedureName, oldDefn, newDefn string) error {
+	err := j.beginDocumentIfNecessary()
+	if err != nil {
+		return err
+	}
+
+	if j.proceduresWritten == 0 {
+		// end the previous block if necessary
+		if j.tablesWritten > 0 && j.eventsWritten == 0 && j.triggersWritten == 0 && j.viewsWritten == 0 {
+			_, err := j.wr.Write([]byte(jsonDataDiffFooter + ","))
+			if err != nil {
+				return err
+			}
+		} else if j.eventsWritten > 0 || j.triggersWritten > 0 || j.viewsWritten > 0 {
+			_, err := j.wr.Write([]byte("],"))
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := j.wr.Write([]byte(`"procedures":[`))
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err := j.wr.Write([]byte(","))
+		if err != nil {
+			return err
+		}
+	}
+
+	procedureNameBytes, err := ejson.Marshal(procedureName)
+	if err != nil {
+		return err
+	}
+
+	oldDefnBytes, err := ejson.Marshal(oldDefn)
+	if err != nil {
+		return err
+	}
+
+	newDefnBytes, err := ejson.Marshal(newDefn)
+	if err != nil {
+		return err
+	}
+
+	_, err = j.wr.Write([]byte(fmt.Sprintf(`{"name":%s,"from_definition":%s,"to_definition":%s}`,
+		procedureNameBytes, oldDefnBytes, newDefnBytes)))
+	if err != nil {
+		return err
+	}
+
+	j.proceduresWritten++
+	return nil
+}
+
 func (j *jsonDiffWriter) Close(ctx context.Context) error {
-	if j.tablesWritten > 0 || j.triggersWritten > 0 || j.viewsWritten > 0 {
-		// We only need to close off the "tables" array if we didn't also write a view / trigger
+	schemaElementsWritten := j.eventsWritten + j.triggersWritten + j.viewsWritten + j.proceduresWritten
+	if j.tablesWritten > 0 || schemaElementsWritten > 0 {
+		// We only need to close off the "tables" array if we didn't also write an event / trigger / view / procedure
 		// (which also closes that array)
-		if j.triggersWritten == 0 && j.viewsWritten == 0 {
+		if schemaElementsWritten == 0 {
 			_, err := j.wr.Write([]byte(jsonDataDiffFooter))
 			if err != nil {
 				return err
 			}
 		} else {
-			// if we did write a trigger or view, we need to close off that array
+			// if we did write an event, trigger, view or procedure, we need to close off that array
 			_, err := j.wr.Write([]byte("]"))
 			if err != nil {
 				return err
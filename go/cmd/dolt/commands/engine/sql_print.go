@@ -43,6 +43,7 @@ const (
 	FormatNull // used for profiling
 	FormatVertical
 	FormatParquet
+	FormatJsonl
 )
 
 type PrintSummaryBehavior byte
@@ -92,6 +93,12 @@ func prettyPrintResultsWithSummary(ctx *sql.Context, resultFormat PrintResultFor
 		if err != nil {
 			return err
 		}
+	case FormatJsonl:
+		var err error
+		wr, err = json.NewJSONLSqlWriter(iohelp.NopWrCloser(cli.CliOut), sqlSch)
+		if err != nil {
+			return err
+		}
 	case FormatTabular:
 		wr = tabular.NewFixedWidthTableWriter(sqlSch, iohelp.NopWrCloser(cli.CliOut), 100)
 	case FormatNull:
@@ -30,12 +30,16 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/archiving"
 	dblr "github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cdc"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/mysql_file_handler"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/statspro"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -62,10 +66,16 @@ type SqlEngineConfig struct {
 	ServerHost              string
 	Autocommit              bool
 	DoltTransactionCommit   bool
+	MergeDeleteSource       bool
 	Bulk                    bool
 	JwksConfig              []JwksConfig
 	ClusterController       *cluster.Controller
 	BinlogReplicaController binlogreplication.BinlogReplicaController
+	CDCPublisher            *cdc.Publisher
+	Archiver                *archiving.Archiver
+	ResultCache             *dsqle.ResultCache
+	PlanCacheEntries        int
+	StorageQuotas           *dsess.StorageQuotas
 }
 
 // NewSqlEngine returns a SqlEngine
@@ -83,6 +93,17 @@ func NewSqlEngine(
 		return nil, err
 	}
 
+	// finish any transaction that a crash left committed to only some of the databases
+	if !config.IsReadOnly {
+		ddbs := make(map[string]*doltdb.DoltDB, len(dbs))
+		for _, db := range dbs {
+			ddbs[strings.ToLower(db.Name())] = db.DbData().Ddb
+		}
+		if err = doltdb.RecoverStagedWorkingSets(ctx, ddbs); err != nil {
+			return nil, err
+		}
+	}
+
 	nbf := types.Format_Default
 	if len(dbs) > 0 {
 		nbf = dbs[0].DbData().Ddb.Format()
@@ -105,6 +126,20 @@ func NewSqlEngine(
 		return nil, err
 	}
 
+	if err = config.CDCPublisher.ApplyCommitHooks(ctx, mrEnv); err != nil {
+		return nil, err
+	}
+	if err = config.CDCPublisher.Run(bThreads); err != nil {
+		return nil, err
+	}
+
+	if err = config.Archiver.ApplyCommitHooks(ctx, mrEnv); err != nil {
+		return nil, err
+	}
+	if err = config.Archiver.Run(bThreads); err != nil {
+		return nil, err
+	}
+
 	all := append(dbs)
 
 	clusterDB := config.ClusterController.ClusterDatabase()
@@ -122,6 +157,8 @@ func NewSqlEngine(
 
 	config.ClusterController.RegisterStoredProcedures(pro)
 	pro.InitDatabaseHook = cluster.NewInitDatabaseHook(config.ClusterController, bThreads, pro.InitDatabaseHook)
+	pro.InitDatabaseHook = cdc.NewInitDatabaseHook(config.CDCPublisher, pro.InitDatabaseHook)
+	pro.InitDatabaseHook = archiving.NewInitDatabaseHook(config.Archiver, pro.InitDatabaseHook)
 	config.ClusterController.ManageDatabaseProvider(pro)
 
 	// Load in privileges from file, if it exists
@@ -142,13 +179,17 @@ func NewSqlEngine(
 		IsReadOnly:     config.IsReadOnly,
 		IsServerLocked: config.IsServerLocked,
 	}).WithBackgroundThreads(bThreads)
+	dsqle.AddAnalyzerRules(engine.Analyzer)
+	engine.ProcessList = dsess.NewSessionClosingProcessList(dsess.NewStatementStatsProcessList(engine.ProcessList))
+	engine.Analyzer.Catalog.InfoSchema = statspro.NewInformationSchemaDatabase(engine.Analyzer.Catalog.InfoSchema, pro)
 	engine.Analyzer.Catalog.MySQLDb.SetPersister(persister)
 
 	engine.Analyzer.Catalog.MySQLDb.SetPlugins(map[string]mysql_db.PlaintextAuthPlugin{
 		"authentication_dolt_jwt": NewAuthenticateDoltJWTPlugin(config.JwksConfig),
 	})
 
-	engine.Analyzer.ExecBuilder = rowexec.DefaultBuilder
+	engine.Analyzer.ExecBuilder = dsqle.NewResultCachingBuilder(rowexec.DefaultBuilder, config.ResultCache)
+	planCache := dsess.NewPlanCache(config.PlanCacheEntries, engine.PreparedDataCache, engine.PrepareQuery)
 
 	// Load MySQL Db information
 	if err = engine.Analyzer.Catalog.MySQLDb.LoadData(sql.NewEmptyContext(), data); err != nil {
@@ -171,8 +212,12 @@ func NewSqlEngine(
 	if err != nil {
 		return nil, err
 	}
+	err = sql.SystemVariables.SetGlobal(dsess.MergeDeleteSource, config.MergeDeleteSource)
+	if err != nil {
+		return nil, err
+	}
 
-	sessionFactory := doltSessionFactory(pro, mrEnv.Config(), bcController, config.Autocommit)
+	sessionFactory := doltSessionFactory(pro, mrEnv.Config(), bcController, config.Autocommit, config.StorageQuotas, planCache)
 
 	if config.BinlogReplicaController != nil {
 		binLogSession, err := sessionFactory(sql.NewBaseSession(), pro)
@@ -205,12 +250,14 @@ func NewRebasedSqlEngine(engine *gms.Engine, dbs map[string]dsess.SqlDatabase) *
 // Databases returns a slice of all databases in the engine
 func (se *SqlEngine) Databases(ctx *sql.Context) []dsess.SqlDatabase {
 	databases := se.provider.AllDatabases(ctx)
-	dbs := make([]dsess.SqlDatabase, len(databases))
-	for i := range databases {
-		dbs[i] = databases[i].(dsess.SqlDatabase)
+	dbs := make([]dsess.SqlDatabase, 0, len(databases))
+	for _, db := range databases {
+		if sqlDb, ok := db.(dsess.SqlDatabase); ok {
+			dbs = append(dbs, sqlDb)
+		}
 	}
 
-	return nil
+	return dbs
 }
 
 // NewContext returns a new sql.Context with the given session.
@@ -287,12 +334,14 @@ func sqlContextFactory() contextFactory {
 }
 
 // doltSessionFactory returns a sessionFactory that creates a new DoltSession
-func doltSessionFactory(pro dsqle.DoltDatabaseProvider, config config.ReadWriteConfig, bc *branch_control.Controller, autocommit bool) sessionFactory {
+func doltSessionFactory(pro dsqle.DoltDatabaseProvider, config config.ReadWriteConfig, bc *branch_control.Controller, autocommit bool, quotas *dsess.StorageQuotas, planCache *dsess.PlanCache) sessionFactory {
 	return func(mysqlSess *sql.BaseSession, provider sql.DatabaseProvider) (*dsess.DoltSession, error) {
 		doltSession, err := dsess.NewDoltSession(mysqlSess, pro, config, bc)
 		if err != nil {
 			return nil, err
 		}
+		doltSession.SetStorageQuotas(quotas)
+		doltSession.SetPlanCache(planCache)
 
 		// nil ctx is actually fine in this context, not used in setting a session variable. Creating a new context isn't
 		// free, and would be throwaway work, since we need to create a session before creating a sql.Context for user work.
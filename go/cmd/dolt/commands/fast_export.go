@@ -0,0 +1,326 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/fastexport"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const fastExportOutParam = "out"
+
+var fastExportDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Writes the history of a branch as a portable stream of commits",
+	LongDesc: `Writes every commit reachable from {{.LessThan}}branch{{.GreaterThan}}, or from the current branch if none is given, to a text stream that can be read by {{.EmphasisLeft}}dolt fast-import{{.EmphasisRight}}. Parents are written before their children. Each commit in the stream records its author, dates and message, the commits that are its parents, and the SQL statements that turn the data of its first parent into its own data. The statements are those returned by the {{.EmphasisLeft}}dolt_patch(){{.EmphasisRight}} table function, except that the rows of a table whose schema changed are written in full, since {{.EmphasisLeft}}dolt_patch(){{.EmphasisRight}} can't diff them.
+
+The stream can be edited or filtered by other programs before it is imported, and can be imported into a database that uses a different storage format.
+
+The stream is written to stdout, or to the file given with {{.EmphasisLeft}}--out{{.EmphasisRight}}.
+`,
+	Synopsis: []string{
+		"[--out {{.LessThan}}file{{.GreaterThan}}] [{{.LessThan}}branch{{.GreaterThan}}]",
+	},
+}
+
+type FastExportCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd FastExportCmd) Name() string {
+	return "fast-export"
+}
+
+// Description returns a description of the command
+func (cmd FastExportCmd) Description() string {
+	return fastExportDocs.ShortDesc
+}
+
+func (cmd FastExportCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(fastExportDocs, ap)
+}
+
+func (cmd FastExportCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"branch", "The branch whose history is exported. Defaults to the current branch."})
+	ap.SupportsString(fastExportOutParam, "o", "file", "Write the stream to {{.LessThan}}file{{.GreaterThan}} instead of stdout.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd FastExportCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd FastExportCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, fastExportDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	branchRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if apr.NArg() == 1 {
+		branchRef = ref.NewBranchRef(apr.Arg(0))
+	}
+
+	head, err := dEnv.DoltDB.ResolveCommitRef(ctx, branchRef)
+	if err == doltdb.ErrBranchNotFound {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: branch '%s' not found", branchRef.GetPath()).Build(), usage)
+	} else if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: could not resolve branch '%s'", branchRef.GetPath()).AddCause(err).Build(), usage)
+	}
+
+	var out io.Writer = cli.CliOut
+	if path, ok := apr.GetValue(fastExportOutParam); ok {
+		f, err := os.Create(path)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: could not create '%s'", path).AddCause(err).Build(), usage)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	if err = fastExport(ctx, sqlCtx, queryist, dEnv.DoltDB, branchRef, head, out); err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: fast-export failed").AddCause(err).Build(), usage)
+	}
+	return 0
+}
+
+func fastExport(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, ddb *doltdb.DoltDB, branchRef ref.DoltRef, head *doltdb.Commit, out io.Writer) error {
+	commits, err := ancestorsParentsFirst(ctx, ddb, head)
+	if err != nil {
+		return err
+	}
+
+	w := fastexport.NewWriter(out)
+	marks := make(map[hash.Hash]int, len(commits))
+	for i, commit := range commits {
+		h, err := commit.HashOf()
+		if err != nil {
+			return err
+		}
+		marks[h] = i + 1
+
+		meta, err := commit.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		parents, err := commit.ParentHashes(ctx)
+		if err != nil {
+			return err
+		}
+
+		c := fastexport.Commit{
+			Ref:           branchRef.String(),
+			Mark:          marks[h],
+			Name:          meta.Name,
+			Email:         meta.Email,
+			UserTimestamp: meta.UserTimestamp,
+			Timestamp:     meta.Timestamp,
+			Message:       meta.Description,
+		}
+		for _, p := range parents {
+			c.Parents = append(c.Parents, marks[p])
+		}
+
+		if len(parents) == 0 {
+			c.Statements, err = rootCommitStatements(ctx, commit)
+		} else {
+			c.Statements, err = commitStatements(ctx, sqlCtx, queryist, ddb, commit)
+		}
+		if err != nil {
+			return fmt.Errorf("commit %s: %w", h.String(), err)
+		}
+
+		if err = w.WriteCommit(c); err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// ancestorsParentsFirst returns |head| and all of its ancestors, ordered so that each commit comes after its parents
+func ancestorsParentsFirst(ctx context.Context, ddb *doltdb.DoltDB, head *doltdb.Commit) ([]*doltdb.Commit, error) {
+	type frame struct {
+		commit     *doltdb.Commit
+		nextParent int
+	}
+
+	headHash, err := head.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	var ordered []*doltdb.Commit
+	visited := map[hash.Hash]struct{}{headHash: {}}
+	stack := []*frame{{commit: head}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		if f.nextParent == f.commit.NumParents() {
+			ordered = append(ordered, f.commit)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		parent, err := ddb.ResolveParent(ctx, f.commit, f.nextParent)
+		if err != nil {
+			return nil, err
+		}
+		f.nextParent++
+
+		h, err := parent.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := visited[h]; !ok {
+			visited[h] = struct{}{}
+			stack = append(stack, &frame{commit: parent})
+		}
+	}
+
+	return ordered, nil
+}
+
+// rootCommitStatements checks that a commit without parents has no tables, as is true of the commit that every
+// database is initialized with, since there is no root value to express its data relative to.
+func rootCommitStatements(ctx context.Context, commit *doltdb.Commit) ([]string, error) {
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := root.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) > 0 {
+		return nil, fmt.Errorf("commits without parents that have tables can't be exported")
+	}
+	return nil, nil
+}
+
+// commitStatements returns the SQL statements that produce the root value of |commit| from that of its first parent.
+// These are the statements returned by dolt_patch(), followed by statements that rewrite every table whose
+// data dolt_patch() can't diff because its schema changed.
+func commitStatements(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, ddb *doltdb.DoltDB, commit *doltdb.Commit) ([]string, error) {
+	parent, err := ddb.ResolveParent(ctx, commit, 0)
+	if err != nil {
+		return nil, err
+	}
+	from, err := parent.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	to, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT statement FROM dolt_patch('%s', '%s') ORDER BY statement_order", from.String(), to.String())
+	rows, err := getRowsForSql(queryist, sqlCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	stmts := make([]string, len(rows))
+	for i, row := range rows {
+		stmts[i] = row[0].(string)
+	}
+
+	fromRoot, err := parent.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	toRoot, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, td := range deltas {
+		if td.IsAdd() || td.IsDrop() {
+			continue
+		}
+		// same checks as dolt_patch() makes before diffing data
+		if schema.ArePrimaryKeySetsDiffable(td.Format(), td.FromSch, td.ToSch) && schema.SchemasAreEqual(td.FromSch, td.ToSch) {
+			continue
+		}
+		stmts, err = appendTableRewrite(ctx, stmts, td)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stmts, nil
+}
+
+// appendTableRewrite appends statements that replace the rows of the table in |td| with the rows of its ToTable
+func appendTableRewrite(ctx context.Context, stmts []string, td diff.TableDelta) ([]string, error) {
+	stmts = append(stmts, fmt.Sprintf("DELETE FROM %s;", sqlfmt.QuoteIdentifier(td.ToName)))
+
+	rowData, err := td.ToTable.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	itr, err := table.NewTableIterator(ctx, td.ToSch, rowData, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close(ctx)
+
+	for {
+		r, err := itr.Next(ctx)
+		if err == io.EOF {
+			return stmts, nil
+		} else if err != nil {
+			return nil, err
+		}
+		stmt, err := sqlfmt.SqlRowAsInsertStmt(r, td.ToName, td.ToSch)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/fastexport"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+var fastImportDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Creates a branch from a stream of commits written by dolt fast-export",
+	LongDesc: `Reads a stream written by {{.EmphasisLeft}}dolt fast-export{{.EmphasisRight}} from {{.LessThan}}file{{.GreaterThan}}, or from stdin if no file is given, and creates {{.LessThan}}branch{{.GreaterThan}} pointing at the last commit in the stream.
+
+Each commit in the stream is recreated by running its SQL statements against the data of its first parent, and is given the author, dates, message and parents recorded in the stream. Foreign key checks are disabled while the statements are run. A commit without parents, such as the commit that the exported database was initialized with, is replaced by the commit that this database was initialized with. Since their data may also be stored differently, imported commits may not have the same hashes as the commits that were exported.
+
+{{.EmphasisLeft}}dolt fast-import{{.EmphasisRight}} fails if {{.LessThan}}branch{{.GreaterThan}} already exists, unless {{.EmphasisLeft}}--force{{.EmphasisRight}} is given. The current branch can't be replaced.
+`,
+	Synopsis: []string{
+		"[-f] {{.LessThan}}branch{{.GreaterThan}} [{{.LessThan}}file{{.GreaterThan}}]",
+	},
+}
+
+type FastImportCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd FastImportCmd) Name() string {
+	return "fast-import"
+}
+
+// Description returns a description of the command
+func (cmd FastImportCmd) Description() string {
+	return fastImportDocs.ShortDesc
+}
+
+func (cmd FastImportCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(fastImportDocs, ap)
+}
+
+func (cmd FastImportCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 2)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"branch", "The branch to create."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "The stream to import. Defaults to stdin."})
+	ap.SupportsFlag(cli.ForceFlag, "f", "Replace {{.LessThan}}branch{{.GreaterThan}} if it already exists.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd FastImportCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd FastImportCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, fastImportDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	if dEnv.IsLocked() {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(env.ErrActiveServerLock.New(dEnv.LockFile())), help)
+	}
+
+	branchName := apr.Arg(0)
+	if !doltdb.IsValidUserBranchName(branchName) {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: '%s' is not a valid branch name", branchName).Build(), usage)
+	}
+	branchRef := ref.NewBranchRef(branchName)
+
+	_, exists, err := dEnv.DoltDB.HasBranch(ctx, branchName)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if exists {
+		if !apr.Contains(cli.ForceFlag) {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: branch '%s' already exists, use --force to replace it", branchName).Build(), usage)
+		}
+		headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if ref.Equals(headRef, branchRef) {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: can't replace the current branch '%s'", branchName).Build(), usage)
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if apr.NArg() == 2 {
+		f, err := os.Open(apr.Arg(1))
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: could not open '%s'", apr.Arg(1)).AddCause(err).Build(), usage)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	head, err := fastImport(ctx, dEnv, in)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: fast-import failed").AddCause(err).Build(), usage)
+	}
+
+	if err = dEnv.DoltDB.NewBranchAtCommit(ctx, branchRef, head, nil); err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: could not create branch '%s'", branchName).AddCause(err).Build(), usage)
+	}
+	return 0
+}
+
+// fastImport recreates the commits in the stream |in| and returns the last of them
+func fastImport(ctx context.Context, dEnv *env.DoltEnv, in io.Reader) (*doltdb.Commit, error) {
+	ddb := dEnv.DoltDB
+	rd := fastexport.NewReader(in)
+	commits := make(map[int]*doltdb.Commit)
+
+	var head *doltdb.Commit
+	for {
+		c, err := rd.ReadCommit()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if _, ok := commits[c.Mark]; ok {
+			return nil, fmt.Errorf("commit :%d appears more than once", c.Mark)
+		}
+
+		parents := make([]*doltdb.Commit, len(c.Parents))
+		for i, p := range c.Parents {
+			if parents[i] = commits[p]; parents[i] == nil {
+				return nil, fmt.Errorf("commit :%d has unknown parent :%d", c.Mark, p)
+			}
+		}
+
+		// commits can't be created without parents, so a commit without parents stands for the initial commit
+		if len(parents) == 0 {
+			if len(c.Statements) > 0 {
+				return nil, fmt.Errorf("commit :%d has no parents, but has statements", c.Mark)
+			}
+			if head, err = initialCommit(ctx, dEnv); err != nil {
+				return nil, err
+			}
+			commits[c.Mark] = head
+			continue
+		}
+
+		root, err := parents[0].GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Statements) > 0 {
+			root, err = applyStatements(ctx, dEnv, root, c.Statements)
+			if err != nil {
+				return nil, fmt.Errorf("commit :%d: %w", c.Mark, err)
+			}
+		}
+
+		_, valHash, err := ddb.WriteRootValue(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+
+		meta := &datas.CommitMeta{
+			Name:          c.Name,
+			Email:         c.Email,
+			Timestamp:     c.Timestamp,
+			Description:   c.Message,
+			UserTimestamp: c.UserTimestamp,
+		}
+		head, err = ddb.CommitDanglingWithParentCommits(ctx, valHash, parents, meta)
+		if err != nil {
+			return nil, err
+		}
+		commits[c.Mark] = head
+	}
+
+	if head == nil {
+		return nil, fmt.Errorf("stream has no commits")
+	}
+	return head, nil
+}
+
+// initialCommit returns the commit that the database was initialized with
+func initialCommit(ctx context.Context, dEnv *env.DoltEnv) (*doltdb.Commit, error) {
+	cm, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for cm.NumParents() > 0 {
+		if cm, err = dEnv.DoltDB.ResolveParent(ctx, cm, 0); err != nil {
+			return nil, err
+		}
+	}
+	return cm, nil
+}
+
+// applyStatements runs |stmts| against |root| and returns the resulting root value
+func applyStatements(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, stmts []string) (*doltdb.RootValue, error) {
+	sqlCtx, eng, err := rebaseSqlEngine(ctx, dEnv, root)
+	if err != nil {
+		return nil, err
+	}
+
+	// statements are ordered table by table, so rows may be inserted before the rows they reference
+	stmts = append([]string{"SET foreign_key_checks = 0"}, stmts...)
+	for _, stmt := range stmts {
+		_, itr, err := eng.Query(sqlCtx, stmt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", stmt, err)
+		}
+		for {
+			if _, err = itr.Next(sqlCtx); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("%s: %w", stmt, err)
+			}
+		}
+		if err = itr.Close(sqlCtx); err != nil {
+			return nil, err
+		}
+	}
+
+	sess := dsess.DSessFromSess(sqlCtx.Session)
+	ws, err := sess.WorkingSet(sqlCtx, filterDbName)
+	if err != nil {
+		return nil, err
+	}
+	return ws.WorkingRoot(), nil
+}
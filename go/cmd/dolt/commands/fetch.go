@@ -34,10 +34,12 @@ var fetchDocs = cli.CommandDocumentationContent{
 By default dolt will attempt to fetch from a remote named {{.EmphasisLeft}}origin{{.EmphasisRight}}.  The {{.LessThan}}remote{{.GreaterThan}} parameter allows you to specify the name of a different remote you wish to pull from by the remote's name.
 
 When no refspec(s) are specified on the command line, the fetch_specs for the default remote are used.
+
+With {{.EmphasisLeft}}--prune{{.EmphasisRight}}, remote-tracking branches of the remote whose branch no longer exists on the remote are deleted after fetching.
 `,
 
 	Synopsis: []string{
-		"[{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
+		"[--prune] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
 	},
 }
 
@@ -89,9 +91,34 @@ func (cmd FetchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
+	verify, err := env.VerifyFetches(dEnv.Config, apr.Contains(cli.VerifyFlag))
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if verify {
+		srcDB = srcDB.WithVerifiedPulls()
+	}
+
+	cacheDir, err := env.SharedCacheDir(dEnv.Config)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if cacheDir != "" {
+		srcDB = srcDB.WithSharedCache(cacheDir)
+	}
+
 	err = actions.FetchRefSpecs(ctx, dEnv.DbData(), srcDB, refSpecs, r, ref.UpdateMode{Force: true}, buildProgStarter(downloadLanguage), stopProgFuncs)
 	if err != nil && err != doltdb.ErrUpToDate {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
+
+	if apr.Contains(cli.PruneFlag) {
+		pruned, err := actions.PruneBranches(ctx, dEnv.DbData(), srcDB, refSpecs, r, false)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		printPrunedRefs(pruned, false)
+	}
+
 	return HandleVErrAndExitCode(nil, usage)
 }
@@ -205,7 +205,7 @@ func processFilterQuery(ctx context.Context, dEnv *env.DoltEnv, cm *doltdb.Commi
 		return nil, err
 	}
 
-	sqlCtx, eng, err := rebaseSqlEngine(ctx, dEnv, cm)
+	sqlCtx, eng, err := rebaseSqlEngine(ctx, dEnv, root)
 	if err != nil {
 		return nil, err
 	}
@@ -273,7 +273,7 @@ func processFilterQuery(ctx context.Context, dEnv *env.DoltEnv, cm *doltdb.Commi
 // The SQL engine returned has transactions disabled. This is to prevent transactions starts from overwriting the root
 // we set manually with the one at the working set of the HEAD being rebased.
 // Some functionality will not work on this kind of engine, e.g. many DOLT_ functions.
-func rebaseSqlEngine(ctx context.Context, dEnv *env.DoltEnv, cm *doltdb.Commit) (*sql.Context, *engine.SqlEngine, error) {
+func rebaseSqlEngine(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue) (*sql.Context, *engine.SqlEngine, error) {
 	tmpDir, err := dEnv.TempTableFilesDir()
 	if err != nil {
 		return nil, nil, err
@@ -310,11 +310,7 @@ func rebaseSqlEngine(ctx context.Context, dEnv *env.DoltEnv, cm *doltdb.Commit)
 
 	parallelism := runtime.GOMAXPROCS(0)
 	azr := analyzer.NewBuilder(pro).WithParallelism(parallelism).Build()
-
-	root, err := cm.GetRootValue(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
+	dsqle.AddAnalyzerRules(azr)
 
 	err = db.SetRoot(sqlCtx, root)
 	if err != nil {
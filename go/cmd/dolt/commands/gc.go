@@ -117,6 +117,11 @@ func (cmd GarbageCollectionCmd) Exec(ctx context.Context, commandStr string, arg
 			} else {
 				verr = errhand.BuildDError("an error occurred during garbage collection").AddCause(err).Build()
 			}
+		} else if dEnv.HasCommitGraph() {
+			// gc removes commits that are no longer reachable, so the commit-graph is written again from scratch
+			if _, _, err = dEnv.WriteCommitGraph(ctx, true); err != nil {
+				verr = errhand.BuildDError("an error occurred writing the commit-graph").AddCause(err).Build()
+			}
 		}
 	}
 
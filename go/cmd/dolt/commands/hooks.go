@@ -0,0 +1,119 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+// Client side hooks are executables placed in .dolt/hooks, named after the event they handle. They are run by the
+// CLI with the repository root as their working directory. Hooks are never run for operations performed in a SQL
+// session or against a running server.
+const (
+	hooksDir = "hooks"
+
+	// preCommitHook is run before a commit is created, including the commit of a merge. A nonzero exit status aborts
+	// the commit.
+	preCommitHook = "pre-commit"
+	// postCommitHook is run after a commit is created, including the commit of a merge. Its exit status does not
+	// affect the commit.
+	postCommitHook = "post-commit"
+	// postMergeHook is run after a successful merge. It is passed "1" if the merge was a squash merge and "0"
+	// otherwise.
+	postMergeHook = "post-merge"
+	// postCheckoutHook is run after a branch or tables are checked out. It is passed the hash of the previous HEAD,
+	// the hash of the new HEAD, and "1" for a branch checkout or "0" for a checkout of tables.
+	postCheckoutHook = "post-checkout"
+)
+
+// hookPath returns the path of the hook named |name| for |dEnv|, and whether it exists and should be run.
+func hookPath(dEnv *env.DoltEnv, name string) (string, bool) {
+	if dEnv == nil || !dEnv.Valid() {
+		return "", false
+	}
+
+	doltDir := dEnv.GetDoltDir()
+	if doltDir == "" {
+		return "", false
+	}
+
+	path := filepath.Join(doltDir, hooksDir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	if info.Mode()&0111 == 0 {
+		cli.PrintErrf("hint: The '%s' hook was ignored because it's not set as executable.\n", path)
+		return "", false
+	}
+
+	return path, true
+}
+
+// runHook runs the hook named |name| with |args| if it exists. A nonzero exit status from the hook is returned as an
+// error.
+func runHook(ctx context.Context, dEnv *env.DoltEnv, name string, args ...string) errhand.VerboseError {
+	path, ok := hookPath(dEnv, name)
+	if !ok {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = filepath.Dir(filepath.Dir(filepath.Dir(path)))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = cli.CliOut
+	cmd.Stderr = cli.CliErr
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return errhand.BuildDError("error: the '%s' hook exited with status %d", name, exitErr.ExitCode()).Build()
+		}
+		return errhand.BuildDError("error: failed to run the '%s' hook", name).AddCause(err).Build()
+	}
+
+	return nil
+}
+
+// runPostHook runs the hook named |name| with |args| if it exists. Post hooks run after the operation has completed,
+// so a failure is reported but does not change the outcome of the command.
+func runPostHook(ctx context.Context, dEnv *env.DoltEnv, name string, args ...string) {
+	if verr := runHook(ctx, dEnv, name, args...); verr != nil {
+		cli.PrintErrln(verr.Verbose())
+	}
+}
+
+// headHashOrEmpty returns the hash of the HEAD commit of |dEnv|, or the empty string if it can't be resolved.
+func headHashOrEmpty(ctx context.Context, dEnv *env.DoltEnv) string {
+	cm, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return ""
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return ""
+	}
+	return h.String()
+}
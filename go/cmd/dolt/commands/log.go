@@ -17,8 +17,8 @@ package commands
 import (
 	"context"
 	"fmt"
-	"io"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -41,6 +41,8 @@ type logOpts struct {
 	minParents           int
 	decoration           string
 	oneLine              bool
+	graph                bool
+	json                 bool
 	excludingCommitSpecs []*doltdb.CommitSpec
 	commitSpecs          []*doltdb.CommitSpec
 	tableName            string
@@ -66,8 +68,8 @@ The command takes options to control what is shown and how.
 {{.EmphasisLeft}}dolt log [<revisions>...]{{.EmphasisRight}}
   Lists commit logs starting from revision. If multiple revisions provided, lists logs reachable by all revisions.
 	
-{{.EmphasisLeft}}dolt log [<revisions>...] <table>{{.EmphasisRight}}
-  Lists commit logs starting from revisions, only including commits with changes to table.
+{{.EmphasisLeft}}dolt log [<revisions>...] [--] <table>{{.EmphasisRight}}
+  Lists commit logs starting from revisions, only including commits with changes to table. A merge commit is only included if the table differs from all of its parents. Use {{.EmphasisLeft}}--{{.EmphasisRight}} to separate revisions from a table whose name is also a revision.
 	
 {{.EmphasisLeft}}dolt log <revisionB>..<revisionA>{{.EmphasisRight}}
 {{.EmphasisLeft}}dolt log <revisionA> --not <revisionB>{{.EmphasisRight}}
@@ -76,9 +78,12 @@ The command takes options to control what is shown and how.
 	
 {{.EmphasisLeft}}dolt log <revisionB>...<revisionA>{{.EmphasisRight}}
 {{.EmphasisLeft}}dolt log <revisionA> <revisionB> --not $(dolt merge-base <revisionA> <revisionB>){{.EmphasisRight}}
-  Different ways to list three dot logs. These will list commit logs reachable by revisionA OR revisionB, while excluding commits reachable by BOTH revisionA AND revisionB.`,
+  Different ways to list three dot logs. These will list commit logs reachable by revisionA OR revisionB, while excluding commits reachable by BOTH revisionA AND revisionB.
+
+{{.EmphasisLeft}}dolt log --graph [--oneline]{{.EmphasisRight}}
+  Draws a text-based graph of the commit history on the left side of the output, showing where branches diverge and merges join them.`,
 	Synopsis: []string{
-		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [{{.LessThan}}revision-range{{.GreaterThan}}] [[--] {{.LessThan}}table{{.GreaterThan}}]`,
+		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [--graph] [{{.LessThan}}revision-range{{.GreaterThan}}] [[--] {{.LessThan}}table{{.GreaterThan}}]`,
 	},
 }
 
@@ -105,7 +110,9 @@ func (cmd LogCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd LogCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateLogArgParser()
+	ap := cli.CreateLogArgParser()
+	supportsJsonFormat(ap)
+	return ap
 }
 
 // Exec executes the command
@@ -129,9 +136,6 @@ func (cmd LogCmd) logWithLoggerFunc(ctx context.Context, commandStr string, args
 		}
 		opts.commitSpecs = append(opts.commitSpecs, headRef)
 	}
-	if len(opts.tableName) > 0 {
-		return handleErrAndExit(logTableCommits(ctx, dEnv, opts))
-	}
 	return logCommits(ctx, dEnv, opts)
 }
 
@@ -148,15 +152,25 @@ func parseLogArgs(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 		return nil, fmt.Errorf("fatal: invalid --decorate option: %s", decorateOption)
 	}
 
+	outputJson, err := isJsonFormat(apr)
+	if err != nil {
+		return nil, err
+	}
+	if outputJson && (apr.Contains(cli.OneLineFlag) || apr.Contains(cli.GraphFlag)) {
+		return nil, fmt.Errorf("--format json cannot be combined with --oneline or --graph")
+	}
+
 	opts := &logOpts{
 		numLines:    apr.GetIntOrDefault(cli.NumberFlag, -1),
 		showParents: apr.Contains(cli.ParentsFlag),
 		minParents:  minParents,
 		oneLine:     apr.Contains(cli.OneLineFlag),
+		graph:       apr.Contains(cli.GraphFlag),
+		json:        outputJson,
 		decoration:  decorateOption,
 	}
 
-	err := opts.parseRefsAndTable(ctx, apr, dEnv)
+	err = opts.parseRefsAndTable(ctx, apr, dEnv)
 	if err != nil {
 		return nil, err
 	}
@@ -183,19 +197,34 @@ func parseLogArgs(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 }
 
 func (opts *logOpts) parseRefsAndTable(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEnv) error {
+	args := apr.Args
+	tableAfterDashes := false
+	// `dolt log [<revisions>...] -- <table>`
+	for i, arg := range args {
+		if arg == "--" {
+			tableAfterDashes = true
+			if len(args[i+1:]) != 1 {
+				return fmt.Errorf("exactly one table must be provided after --")
+			}
+			opts.tableName = args[i+1]
+			args = args[:i]
+			break
+		}
+	}
+
 	// `dolt log`
-	if apr.NArg() == 0 {
+	if len(args) == 0 {
 		return nil
 	}
 
-	if strings.Contains(apr.Arg(0), "..") {
-		if apr.NArg() > 1 {
+	if strings.Contains(args[0], "..") {
+		if len(args) > 1 || len(opts.tableName) > 0 {
 			return fmt.Errorf("Cannot use two or three dot syntax when 2 or more arguments provided")
 		}
 
 		// `dolt log <ref>...<ref>`
-		if strings.Contains(apr.Arg(0), "...") {
-			refs := strings.Split(apr.Arg(0), "...")
+		if strings.Contains(args[0], "...") {
+			refs := strings.Split(args[0], "...")
 
 			for _, ref := range refs {
 				cs, err := getCommitSpec(ref)
@@ -219,7 +248,7 @@ func (opts *logOpts) parseRefsAndTable(ctx context.Context, apr *argparser.ArgPa
 		}
 
 		// `dolt log <ref>..<ref>`
-		refs := strings.Split(apr.Arg(0), "..")
+		refs := strings.Split(args[0], "..")
 		notCs, err := getCommitSpec(refs[0])
 		if err != nil {
 			return err
@@ -238,7 +267,7 @@ func (opts *logOpts) parseRefsAndTable(ctx context.Context, apr *argparser.ArgPa
 
 	seenRefs := make(map[string]bool)
 
-	for _, arg := range apr.Args {
+	for _, arg := range args {
 		// ^<ref>
 		if strings.HasPrefix(arg, "^") {
 			commit := strings.TrimPrefix(arg, "^")
@@ -253,15 +282,15 @@ func (opts *logOpts) parseRefsAndTable(ctx context.Context, apr *argparser.ArgPa
 			if err != nil {
 				return nil
 			}
-			// <ref>
-			if argIsRef && !seenRefs[arg] {
+			// <ref>. When a table was given after --, every argument before it is a ref.
+			if (argIsRef || tableAfterDashes) && !seenRefs[arg] {
 				cs, err := getCommitSpec(arg)
 				if err != nil {
 					return err
 				}
 				seenRefs[arg] = true
 				opts.commitSpecs = append(opts.commitSpecs, cs)
-			} else {
+			} else if !tableAfterDashes {
 				// <table>
 				opts.tableName = arg
 			}
@@ -283,7 +312,9 @@ func getCommitSpec(commit string) (*doltdb.CommitSpec, error) {
 	return cs, nil
 }
 
-func getHashToRefs(ctx context.Context, dEnv *env.DoltEnv, decorationLevel string) (map[hash.Hash][]string, error) {
+// getHashToRefs returns the names of the branches, remote branches and tags that point to each commit. Names are
+// colored for the terminal if |colored| is true.
+func getHashToRefs(ctx context.Context, dEnv *env.DoltEnv, decorationLevel string, colored bool) (map[hash.Hash][]string, error) {
 	cHashToRefs := map[hash.Hash][]string{}
 
 	// Get all branches
@@ -297,7 +328,9 @@ func getHashToRefs(ctx context.Context, dEnv *env.DoltEnv, decorationLevel strin
 		if decorationLevel != "full" {
 			refName = b.Ref.GetPath() // trim out "refs/heads/"
 		}
-		refName = fmt.Sprintf("\033[32;1m%s\033[0m", refName) // branch names are bright green (32;1m)
+		if colored {
+			refName = fmt.Sprintf("\033[32;1m%s\033[0m", refName) // branch names are bright green (32;1m)
+		}
 		cHashToRefs[b.Hash] = append(cHashToRefs[b.Hash], refName)
 	}
 
@@ -311,7 +344,9 @@ func getHashToRefs(ctx context.Context, dEnv *env.DoltEnv, decorationLevel strin
 		if decorationLevel != "full" {
 			refName = r.Ref.GetPath() // trim out "refs/remotes/"
 		}
-		refName = fmt.Sprintf("\033[31;1m%s\033[0m", refName) // remote names are bright red (31;1m)
+		if colored {
+			refName = fmt.Sprintf("\033[31;1m%s\033[0m", refName) // remote names are bright red (31;1m)
+		}
 		cHashToRefs[r.Hash] = append(cHashToRefs[r.Hash], refName)
 	}
 
@@ -325,7 +360,10 @@ func getHashToRefs(ctx context.Context, dEnv *env.DoltEnv, decorationLevel strin
 		if decorationLevel != "full" {
 			tagName = t.Tag.Name // trim out "refs/tags/"
 		}
-		tagName = fmt.Sprintf("\033[33;1mtag: %s\033[0m", tagName) // tags names are bright yellow (33;1m)
+		tagName = "tag: " + tagName
+		if colored {
+			tagName = fmt.Sprintf("\033[33;1m%s\033[0m", tagName) // tags names are bright yellow (33;1m)
+		}
 		cHashToRefs[t.Hash] = append(cHashToRefs[t.Hash], tagName)
 	}
 	return cHashToRefs, nil
@@ -351,17 +389,33 @@ func logCommits(ctx context.Context, dEnv *env.DoltEnv, opts *logOpts) int {
 			return 1
 		}
 
+		if len(opts.tableName) > 0 {
+			exists, err := tableExists(ctx, commit, opts.tableName)
+			if err != nil {
+				return handleErrAndExit(err)
+			}
+			if !exists {
+				return handleErrAndExit(fmt.Errorf("error: table %s does not exist", opts.tableName))
+			}
+		}
+
 		hashes[i] = h
 	}
 
-	cHashToRefs, err := getHashToRefs(ctx, dEnv, opts.decoration)
+	cHashToRefs, err := getHashToRefs(ctx, dEnv, opts.decoration, !opts.json)
 
 	if err != nil {
 		return handleErrAndExit(err)
 	}
 
 	matchFunc := func(c *doltdb.Commit) (bool, error) {
-		return c.NumParents() >= opts.minParents, nil
+		if c.NumParents() < opts.minParents {
+			return false, nil
+		}
+		if len(opts.tableName) > 0 {
+			return c.TableChanged(ctx, opts.tableName)
+		}
+		return true, nil
 	}
 
 	var commits []*doltdb.Commit
@@ -429,11 +483,55 @@ func logCommits(ctx context.Context, dEnv *env.DoltEnv, opts *logOpts) int {
 			isHead:       cmHash == *cwbHash})
 	}
 
+	if opts.json {
+		return handleErrAndExit(printLogJson(opts, commitsInfo))
+	}
 	logToStdOut(opts, commitsInfo)
 
 	return 0
 }
 
+// logCommitJson is a commit in the output of dolt log --format json
+type logCommitJson struct {
+	Hash    string   `json:"hash"`
+	Parents []string `json:"parents"`
+	// Refs are the branches and tags that point to the commit, decorated as asked for with --decorate
+	Refs    []string `json:"refs"`
+	Head    bool     `json:"head"`
+	Author  string   `json:"author"`
+	Email   string   `json:"email"`
+	Date    string   `json:"date"`
+	Message string   `json:"message"`
+}
+
+// printLogJson prints |commits| as a JSON object with a commits array, newest first. Dates are in RFC 3339 format.
+func printLogJson(opts *logOpts, commits []logNode) error {
+	out := struct {
+		Commits []logCommitJson `json:"commits"`
+	}{Commits: make([]logCommitJson, len(commits))}
+	for i, comm := range commits {
+		parents := make([]string, len(comm.parentHashes))
+		for j, h := range comm.parentHashes {
+			parents[j] = h.String()
+		}
+		refs := []string{}
+		if opts.decoration != "no" {
+			refs = append(refs, comm.branchNames...)
+		}
+		out.Commits[i] = logCommitJson{
+			Hash:    comm.commitHash.String(),
+			Parents: parents,
+			Refs:    refs,
+			Head:    comm.isHead,
+			Author:  comm.commitMeta.Name,
+			Email:   comm.commitMeta.Email,
+			Date:    comm.commitMeta.Time().UTC().Format(time.RFC3339),
+			Message: comm.commitMeta.Description,
+		}
+	}
+	return printJson(out)
+}
+
 func tableExists(ctx context.Context, commit *doltdb.Commit, tableName string) (bool, error) {
 	rv, err := commit.GetRootValue(ctx)
 	if err != nil {
@@ -448,126 +546,25 @@ func tableExists(ctx context.Context, commit *doltdb.Commit, tableName string) (
 	return ok, nil
 }
 
-func logTableCommits(ctx context.Context, dEnv *env.DoltEnv, opts *logOpts) error {
-	hashes := make([]hash.Hash, len(opts.commitSpecs))
-
-	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
-	if err != nil {
-		return err
-	}
-
-	for i, cs := range opts.commitSpecs {
-		commit, err := dEnv.DoltDB.Resolve(ctx, cs, headRef)
-		if err != nil {
-			return err
-		}
-
-		h, err := commit.HashOf()
-		if err != nil {
-			return err
-		}
-
-		// Check that the table exists in the head commits
-		exists, err := tableExists(ctx, commit, opts.tableName)
-		if err != nil {
-			return err
-		}
-
-		if !exists {
-			return fmt.Errorf("error: table %s does not exist", opts.tableName)
-		}
-
-		hashes[i] = h
-	}
-
-	matchFunc := func(commit *doltdb.Commit) (bool, error) {
-		return commit.NumParents() >= opts.minParents, nil
-	}
-
-	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, dEnv.DoltDB, hashes, matchFunc)
-	if err != nil && err != io.EOF {
-		return err
-	}
-
-	var prevCommit *doltdb.Commit = nil
-	var prevHash hash.Hash
-	var commitsInfo []logNode
-
-	numLines := opts.numLines
-	for {
-		// If we reached the limit then break
-		if numLines == 0 {
-			break
-		}
-
-		h, c, err := itr.Next(ctx)
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return err
-		}
-
-		if prevCommit == nil {
-			prevCommit = c
-			prevHash = h
-			continue
-		}
-
-		parentRV, err := c.GetRootValue(ctx)
-		if err != nil {
-			return err
-		}
-
-		childRV, err := prevCommit.GetRootValue(ctx)
-		if err != nil {
-			return err
-		}
-
-		ok, err := didTableChangeBetweenRootValues(ctx, childRV, parentRV, opts.tableName)
-		if err != nil {
-			return err
-		}
-
-		if ok {
-			meta, err := prevCommit.GetCommitMeta(ctx)
-			if err != nil {
-				return err
-			}
-
-			ph, err := prevCommit.ParentHashes(ctx)
-			if err != nil {
-				return err
-			}
-
-			commitsInfo = append(commitsInfo, logNode{
-				commitMeta:   meta,
-				commitHash:   prevHash,
-				parentHashes: ph})
-
-			numLines--
-		}
-
-		prevCommit = c
-		prevHash = h
-	}
-
-	logToStdOut(opts, commitsInfo)
-
-	return nil
+func logRefs(pager *outputpager.Pager, comm logNode) {
+	pager.Writer.Write([]byte(formatRefs(comm)))
 }
 
-func logRefs(pager *outputpager.Pager, comm logNode) {
+// formatRefs returns the decoration for the commit given, listing the refs that point to it
+func formatRefs(comm logNode) string {
 	// Do nothing if no associate branches
 	if len(comm.branchNames) == 0 {
-		return
+		return ""
 	}
 
-	pager.Writer.Write([]byte("\033[33m(\033[0m"))
+	sb := strings.Builder{}
+	sb.WriteString("\033[33m(\033[0m")
 	if comm.isHead {
-		pager.Writer.Write([]byte("\033[36;1mHEAD -> \033[0m"))
+		sb.WriteString("\033[36;1mHEAD -> \033[0m")
 	}
-	pager.Writer.Write([]byte(strings.Join(comm.branchNames, "\033[33m, \033[0m"))) // Separate with Dim Yellow comma
-	pager.Writer.Write([]byte("\033[33m) \033[0m"))
+	sb.WriteString(strings.Join(comm.branchNames, "\033[33m, \033[0m")) // Separate with Dim Yellow comma
+	sb.WriteString("\033[33m) \033[0m")
+	return sb.String()
 }
 
 func logCompact(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
@@ -576,24 +573,31 @@ func logCompact(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
 			return
 		}
 
-		chStr := comm.commitHash.String()
-		if opts.showParents {
-			for _, h := range comm.parentHashes {
-				chStr += " " + h.String()
-			}
-		}
-
-		// TODO: use short hash instead
-		// Write commit hash
-		pager.Writer.Write([]byte(fmt.Sprintf("\033[33m%s \033[0m", chStr)))
+		pager.Writer.Write([]byte(formatCompactCommit(opts, comm)))
+	}
+}
 
-		if opts.decoration != "no" {
-			logRefs(pager, comm)
+// formatCompactCommit returns the single line --oneline representation of the commit given, including a trailing
+// newline
+func formatCompactCommit(opts *logOpts, comm logNode) string {
+	chStr := comm.commitHash.String()
+	if opts.showParents {
+		for _, h := range comm.parentHashes {
+			chStr += " " + h.String()
 		}
+	}
 
-		formattedDesc := strings.Replace(comm.commitMeta.Description, "\n", " ", -1) + "\n"
-		pager.Writer.Write([]byte(fmt.Sprintf("%s", formattedDesc)))
+	// TODO: use short hash instead
+	// Write commit hash
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("\033[33m%s \033[0m", chStr))
+
+	if opts.decoration != "no" {
+		sb.WriteString(formatRefs(comm))
 	}
+
+	sb.WriteString(strings.Replace(comm.commitMeta.Description, "\n", " ", -1) + "\n")
+	return sb.String()
 }
 
 func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, decoration string, comm logNode) {
@@ -601,6 +605,11 @@ func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, dec
 		return
 	}
 
+	pager.Writer.Write([]byte(formatCommit(showParents, decoration, comm)))
+}
+
+// formatCommit returns the full, multi-line representation of the commit given
+func formatCommit(showParents bool, decoration string, comm logNode) string {
 	chStr := comm.commitHash.String()
 	if showParents {
 		for _, h := range comm.parentHashes {
@@ -609,27 +618,28 @@ func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, dec
 	}
 
 	// Write commit hash
-	pager.Writer.Write([]byte(fmt.Sprintf("\033[33mcommit %s \033[0m", chStr))) // Use Dim Yellow (33m)
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("\033[33mcommit %s \033[0m", chStr)) // Use Dim Yellow (33m)
 
 	// Show decoration
 	if decoration != "no" {
-		logRefs(pager, comm)
+		sb.WriteString(formatRefs(comm))
 	}
 
 	if len(comm.parentHashes) > 1 {
-		pager.Writer.Write([]byte(fmt.Sprintf("\nMerge:")))
+		sb.WriteString("\nMerge:")
 		for _, h := range comm.parentHashes {
-			pager.Writer.Write([]byte(fmt.Sprintf(" " + h.String())))
+			sb.WriteString(" " + h.String())
 		}
 	}
 
-	pager.Writer.Write([]byte(fmt.Sprintf("\nAuthor: %s <%s>", comm.commitMeta.Name, comm.commitMeta.Email)))
+	sb.WriteString(fmt.Sprintf("\nAuthor: %s <%s>", comm.commitMeta.Name, comm.commitMeta.Email))
 
 	timeStr := comm.commitMeta.FormatTS()
-	pager.Writer.Write([]byte(fmt.Sprintf("\nDate:  %s", timeStr)))
+	sb.WriteString(fmt.Sprintf("\nDate:  %s", timeStr))
 
-	formattedDesc := "\n\n\t" + strings.Replace(comm.commitMeta.Description, "\n", "\n\t", -1) + "\n\n"
-	pager.Writer.Write([]byte(fmt.Sprintf("%s", formattedDesc)))
+	sb.WriteString("\n\n\t" + strings.Replace(comm.commitMeta.Description, "\n", "\n\t", -1) + "\n\n")
+	return sb.String()
 }
 
 func logDefault(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
@@ -645,7 +655,9 @@ func logToStdOut(opts *logOpts, commits []logNode) {
 	cli.ExecuteWithStdioRestored(func() {
 		pager := outputpager.Start()
 		defer pager.Stop()
-		if opts.oneLine {
+		if opts.graph {
+			logGraph(pager, opts, commits)
+		} else if opts.oneLine {
 			logCompact(pager, opts, commits)
 		} else {
 			logDefault(pager, opts, commits)
@@ -653,30 +665,6 @@ func logToStdOut(opts *logOpts, commits []logNode) {
 	})
 }
 
-func didTableChangeBetweenRootValues(ctx context.Context, child, parent *doltdb.RootValue, tableName string) (bool, error) {
-	childHash, ok, err := child.GetTableHash(ctx, tableName)
-
-	if !ok {
-		return false, nil
-	}
-	if err != nil {
-		return false, err
-	}
-
-	parentHash, ok, err := parent.GetTableHash(ctx, tableName)
-
-	// If the table didn't exist in the parent then we know there was a change
-	if !ok {
-		return true, nil
-	}
-
-	if err != nil {
-		return false, err
-	}
-
-	return childHash != parentHash, nil
-}
-
 func handleErrAndExit(err error) int {
 	if err != nil {
 		cli.PrintErrln(err)
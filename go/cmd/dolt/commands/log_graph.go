@@ -0,0 +1,259 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/outputpager"
+)
+
+// commitGraph renders the ASCII graph drawn to the left of `dolt log --graph` output. Each column of the graph is a
+// line of history waiting for the commit it points to, identified by that commit's hash. Commits must be fed to the
+// graph in topological order, children before parents.
+type commitGraph struct {
+	columns []hash.Hash
+	// present contains the hashes of every commit that will be drawn. Edges to parents outside this set (e.g. because
+	// the log was limited with -n) are not drawn, so that their columns don't dangle forever.
+	present map[hash.Hash]bool
+}
+
+// graphRows are the graph prefixes for a single commit: the row with the commit's marker, the rows that connect the
+// commit to its parents and shift other columns into place, and the row used to continue any remaining text lines.
+type graphRows struct {
+	commitRow       string
+	connectorRows   []string
+	continuationRow string
+}
+
+func newCommitGraph(commits []logNode) *commitGraph {
+	present := make(map[hash.Hash]bool, len(commits))
+	for _, c := range commits {
+		present[c.commitHash] = true
+	}
+	return &commitGraph{present: present}
+}
+
+// next advances the graph past the commit given and returns the rows to draw for it
+func (g *commitGraph) next(comm logNode) graphRows {
+	idx := indexOfHash(g.columns, comm.commitHash)
+	if idx < 0 {
+		g.columns = append(g.columns, comm.commitHash)
+		idx = len(g.columns) - 1
+	}
+
+	var parents []hash.Hash
+	for _, p := range comm.parentHashes {
+		if g.present[p] {
+			parents = append(parents, p)
+		}
+	}
+
+	commitRow := strings.Builder{}
+	for i := range g.columns {
+		if i == idx {
+			commitRow.WriteString("* ")
+		} else {
+			commitRow.WriteString("| ")
+		}
+	}
+
+	// Compute the columns after this commit. The commit's column is replaced with its parents, and any other columns
+	// waiting on this commit end here.
+	var newColumns []hash.Hash
+	for i, h := range g.columns {
+		if i == idx {
+			for _, p := range parents {
+				if indexOfHash(newColumns, p) < 0 {
+					newColumns = append(newColumns, p)
+				}
+			}
+		} else if h != comm.commitHash && indexOfHash(newColumns, h) < 0 {
+			newColumns = append(newColumns, h)
+		}
+	}
+
+	// Every old column becomes one or more edges to its position among the new columns
+	type edge struct {
+		from, to int
+	}
+	var edges []edge
+	for i, h := range g.columns {
+		if i == idx {
+			for _, p := range parents {
+				edges = append(edges, edge{from: i, to: indexOfHash(newColumns, p)})
+			}
+		} else if h == comm.commitHash {
+			if len(parents) > 0 {
+				edges = append(edges, edge{from: i, to: indexOfHash(newColumns, parents[0])})
+			}
+		} else {
+			edges = append(edges, edge{from: i, to: indexOfHash(newColumns, h)})
+		}
+	}
+
+	// Draw connector rows, moving every edge at most one column per row until all of them are in place
+	width := len(g.columns)
+	if len(newColumns) > width {
+		width = len(newColumns)
+	}
+
+	var connectorRows []string
+	for {
+		moving := false
+		for _, e := range edges {
+			if e.from != e.to {
+				moving = true
+				break
+			}
+		}
+		if !moving {
+			break
+		}
+
+		row := []byte(strings.Repeat(" ", 2*width))
+		for i := range edges {
+			e := &edges[i]
+			switch {
+			case e.to > e.from:
+				row[2*e.from+1] = '\\'
+				e.from++
+			case e.to < e.from:
+				row[2*e.from-1] = '/'
+				e.from--
+			default:
+				row[2*e.from] = '|'
+			}
+		}
+		connectorRows = append(connectorRows, string(row))
+	}
+
+	continuationRow := strings.Repeat("| ", len(newColumns))
+
+	g.columns = newColumns
+	return graphRows{
+		commitRow:       commitRow.String(),
+		connectorRows:   connectorRows,
+		continuationRow: continuationRow,
+	}
+}
+
+// prefixLines prefixes each line of the text given with the graph rows for its commit. The first line gets the commit
+// row, subsequent lines get the connector rows and then the continuation row. Connector rows left over once the text
+// runs out are written on their own lines. All rows are padded to the same width so the text lines up.
+func (r graphRows) prefixLines(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	width := len(r.commitRow)
+	for _, row := range append(r.connectorRows, r.continuationRow) {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	pad := func(row string) string {
+		return row + strings.Repeat(" ", width-len(row))
+	}
+
+	sb := strings.Builder{}
+	connectors := r.connectorRows
+	for i, line := range lines {
+		prefix := r.continuationRow
+		if i == 0 {
+			prefix = r.commitRow
+		} else if len(connectors) > 0 {
+			prefix = connectors[0]
+			connectors = connectors[1:]
+		}
+		sb.WriteString(strings.TrimRight(pad(prefix)+line, " "))
+		sb.WriteString("\n")
+	}
+
+	for _, row := range connectors {
+		sb.WriteString(strings.TrimRight(row, " "))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func indexOfHash(hashes []hash.Hash, h hash.Hash) int {
+	for i := range hashes {
+		if hashes[i] == h {
+			return i
+		}
+	}
+	return -1
+}
+
+// graphParents returns the parents to draw for each of the commits given for which |keep| is true. Commits that aren't
+// kept are left out of the graph, so each kept commit is connected to its nearest kept ancestors instead, through any
+// commits that aren't. Commits must be in topological order, children before parents.
+func graphParents(commits []logNode, keep func(logNode) bool) map[hash.Hash][]hash.Hash {
+	kept := make(map[hash.Hash][]hash.Hash)
+	hidden := make(map[hash.Hash][]hash.Hash)
+	for i := len(commits) - 1; i >= 0; i-- {
+		comm := commits[i]
+		var parents []hash.Hash
+		for _, p := range comm.parentHashes {
+			ancestors, ok := hidden[p]
+			if !ok {
+				ancestors = []hash.Hash{p}
+			}
+			for _, a := range ancestors {
+				if indexOfHash(parents, a) < 0 {
+					parents = append(parents, a)
+				}
+			}
+		}
+		if keep(comm) {
+			kept[comm.commitHash] = parents
+		} else {
+			hidden[comm.commitHash] = parents
+		}
+	}
+	return kept
+}
+
+func logGraph(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
+	// Commits hidden by --min-parents are left out of the graph, and edges through them are drawn to the nearest
+	// ancestors that are shown
+	parents := graphParents(commits, func(comm logNode) bool {
+		return len(comm.parentHashes) >= opts.minParents
+	})
+	var shown, nodes []logNode
+	for _, comm := range commits {
+		if p, ok := parents[comm.commitHash]; ok {
+			shown = append(shown, comm)
+			node := comm
+			node.parentHashes = p
+			nodes = append(nodes, node)
+		}
+	}
+
+	graph := newCommitGraph(nodes)
+	for i, comm := range shown {
+		rows := graph.next(nodes[i])
+
+		var text string
+		if opts.oneLine {
+			text = formatCompactCommit(opts, comm)
+		} else {
+			text = formatCommit(opts.showParents, opts.decoration, comm)
+		}
+
+		pager.Writer.Write([]byte(rows.prefixLines(text)))
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestCommitGraph(t *testing.T) {
+	h := func(s string) hash.Hash {
+		return hash.Of([]byte(s))
+	}
+	node := func(name string, parents ...string) logNode {
+		n := logNode{commitHash: h(name)}
+		for _, p := range parents {
+			n.parentHashes = append(n.parentHashes, h(p))
+		}
+		return n
+	}
+	render := func(names []string, commits []logNode) string {
+		g := newCommitGraph(commits)
+		sb := strings.Builder{}
+		for i, c := range commits {
+			sb.WriteString(g.next(c).prefixLines(names[i] + "\n"))
+		}
+		return sb.String()
+	}
+
+	t.Run("linear", func(t *testing.T) {
+		commits := []logNode{node("c", "b"), node("b", "a"), node("a")}
+		assert.Equal(t, "* c\n* b\n* a\n", render([]string{"c", "b", "a"}, commits))
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		commits := []logNode{node("m", "a", "b"), node("a", "c"), node("b", "c"), node("c")}
+		expected := "*   m\n" +
+			"|\\\n" +
+			"* | a\n" +
+			"| * b\n" +
+			"|/\n" +
+			"* c\n"
+		assert.Equal(t, expected, render([]string{"m", "a", "b", "c"}, commits))
+	})
+
+	t.Run("two branch tips", func(t *testing.T) {
+		commits := []logNode{node("x", "c"), node("y", "c"), node("c")}
+		expected := "* x\n" +
+			"| * y\n" +
+			"|/\n" +
+			"* c\n"
+		assert.Equal(t, expected, render([]string{"x", "y", "c"}, commits))
+	})
+
+	t.Run("parents outside the log are not drawn", func(t *testing.T) {
+		commits := []logNode{node("c", "b"), node("b", "a")}
+		assert.Equal(t, "* c\n* b\n", render([]string{"c", "b"}, commits))
+	})
+
+	t.Run("hidden commits", func(t *testing.T) {
+		// only the merges m2 and m1 are kept, so m2 is drawn with m1 as its parent, and m1 with no parents
+		commits := []logNode{node("m2", "x", "b"), node("x", "m1"), node("b", "a"), node("m1", "a", "y"), node("y", "a"), node("a")}
+		parents := graphParents(commits, func(c logNode) bool {
+			return len(c.parentHashes) > 1
+		})
+		assert.Len(t, parents, 2)
+		assert.Equal(t, []hash.Hash{h("m1")}, parents[h("m2")])
+		assert.Empty(t, parents[h("m1")])
+
+		var shown []logNode
+		for _, c := range commits {
+			if p, ok := parents[c.commitHash]; ok {
+				shown = append(shown, logNode{commitHash: c.commitHash, parentHashes: p})
+			}
+		}
+		assert.Equal(t, "* m2\n* m1\n", render([]string{"m2", "m1"}, shown))
+	})
+
+	t.Run("multi-line text", func(t *testing.T) {
+		commits := []logNode{node("m", "a", "b"), node("a"), node("b")}
+		g := newCommitGraph(commits)
+		out := g.next(commits[0]).prefixLines("commit m\nAuthor: me\n\n")
+		assert.Equal(t, "*   commit m\n|\\  Author: me\n| |\n", out)
+	})
+}
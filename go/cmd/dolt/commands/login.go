@@ -35,12 +35,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 )
 
-const (
-	loginRetryInterval = 5
-	authEndpointParam  = "auth-endpoint"
-	loginURLParam      = "login-url"
-	insecureParam      = "insecure"
-)
+const loginRetryInterval = 5
 
 var loginDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Login to DoltHub or DoltLab",
@@ -80,12 +75,7 @@ func (cmd LoginCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd LoginCmd) ArgParser() *argparser.ArgParser {
-	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
-	ap.SupportsString(authEndpointParam, "e", "hostname:port", fmt.Sprintf("Specify the endpoint used to authenticate this client. Must be used with --%s OR set in the configuration file as `%s`", loginURLParam, env.AddCredsUrlKey))
-	ap.SupportsString(loginURLParam, "url", "url", "Specify the login url where the browser will add credentials.")
-	ap.SupportsFlag(insecureParam, "i", "If set, makes insecure connection to remote authentication server")
-	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"creds", "A specific credential to use for login. If omitted, new credentials will be generated."})
-	return ap
+	return cli.CreateLoginArgParser()
 }
 
 // EventType returns the type of the event to log
@@ -101,11 +91,11 @@ func (cmd LoginCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	// use config values over defaults, flag values over config values
 	loginUrl := dEnv.Config.GetStringOrDefault(env.AddCredsUrlKey, env.DefaultLoginUrl)
-	loginUrl = apr.GetValueOrDefault(loginURLParam, loginUrl)
+	loginUrl = apr.GetValueOrDefault(cli.LoginURLParam, loginUrl)
 
 	var authHost string
 	var authPort string
-	authEndpoint := apr.GetValueOrDefault(authEndpointParam, "")
+	authEndpoint := apr.GetValueOrDefault(cli.AuthEndpointParam, "")
 	if authEndpoint != "" {
 		var err error
 		authHost, authPort, err = net.SplitHostPort(authEndpoint)
@@ -124,7 +114,7 @@ func (cmd LoginCmd) Exec(ctx context.Context, commandStr string, args []string,
 		loginUrl = env.DefaultLoginUrl
 	}
 
-	insecure := apr.Contains(insecureParam)
+	insecure := apr.Contains(cli.InsecureFlag)
 
 	var err error
 	if !insecure {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sort"
 	"strconv"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/store/datas"
@@ -48,6 +50,8 @@ The second syntax ({{.LessThan}}dolt merge --abort{{.GreaterThan}}) can only be
 	Synopsis: []string{
 		"[--squash] {{.LessThan}}branch{{.GreaterThan}}",
 		"--no-ff [-m message] {{.LessThan}}branch{{.GreaterThan}}",
+		"--delete-source {{.LessThan}}branch{{.GreaterThan}}",
+		"--dry-run {{.LessThan}}branch{{.GreaterThan}}",
 		"--abort",
 	},
 }
@@ -70,7 +74,9 @@ func (cmd MergeCmd) Docs() *cli.CommandDocumentation {
 }
 
 func (cmd MergeCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateMergeArgParser()
+	ap := cli.CreateMergeArgParser()
+	supportsJsonFormat(ap)
+	return ap
 }
 
 // EventType returns the type of the event to log
@@ -80,10 +86,21 @@ func (cmd MergeCmd) EventType() eventsapi.ClientEventType {
 
 // Exec executes the command
 func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
-	ap := cli.CreateMergeArgParser()
+	ap := cmd.ArgParser()
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, mergeDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
+	outputJson, err := isJsonFormat(apr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if outputJson && apr.Contains(cli.AbortParam) {
+		return HandleVErrAndExitCode(errhand.BuildDError("--format json cannot be combined with --abort").Build(), usage)
+	}
+	if outputJson && apr.Contains(cli.DeleteSourceFlag) {
+		return HandleVErrAndExitCode(errhand.BuildDError("--format json cannot be combined with --delete-source").Build(), usage)
+	}
+
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
 	if err != nil {
 		cli.Println(err.Error())
@@ -99,7 +116,7 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 	}
 
 	// This command may create a commit, so we need user identity
-	if !cli.CheckUserNameAndEmail(cliCtx.Config()) {
+	if !apr.Contains(cli.DryRunFlag) && !cli.CheckUserNameAndEmail(cliCtx.Config()) {
 		return 1
 	}
 
@@ -188,17 +205,47 @@ func (cmd MergeCmd) Exec(ctx context.Context, commandStr string, args []string,
 				return handleCommitErr(sqlCtx, queryist, errhand.VerboseErrorFromError(err), usage)
 			}
 			if spec == nil {
+				if outputJson {
+					return handleErrAndExit(printJson(mergeJson{Status: "up_to_date", Tables: []mergeTableJson{}}))
+				}
 				cli.Println("Everything up-to-date")
 				return handleCommitErr(sqlCtx, queryist, nil, usage)
 			}
+			spec.VerifyForeignKeys = apr.Contains(cli.VerifyFKsFlag)
+
+			if outputJson {
+				return performJsonMerge(ctx, sqlCtx, queryist, dEnv, spec, suggestedMsg, apr.Contains(cli.DryRunFlag), cliCtx, usage)
+			}
 
 			err = validateMergeSpec(ctx, spec)
 			if err != nil {
 				return handleCommitErr(sqlCtx, queryist, err, usage)
 			}
 
+			if apr.Contains(cli.DryRunFlag) {
+				return performDryRunMerge(ctx, dEnv, spec)
+			}
+
 			tblToStats, mergeErr := performMerge(ctx, sqlCtx, queryist, dEnv, spec, suggestedMsg, cliCtx)
 			hasConflicts, hasConstraintViolations := printSuccessStats(tblToStats)
+			if mergeErr == nil && !hasConflicts && !hasConstraintViolations && !spec.NoCommit {
+				squash := "0"
+				if spec.Squash {
+					squash = "1"
+				}
+				runPostHook(ctx, dEnv, postMergeHook, squash)
+				if apr.Contains(cli.DeleteSourceFlag) && !spec.Squash {
+					if err := deleteMergedBranch(ctx, dEnv, headRef, commitSpecStr); err != nil {
+						return handleCommitErr(sqlCtx, queryist, errhand.VerboseErrorFromError(err), usage)
+					}
+				}
+			}
+			if mergeErr == nil {
+				// the merge may have dropped or renamed tables and columns that views on this branch depend on
+				if brokenViews, err := getBrokenViews(queryist, sqlCtx); err == nil && len(brokenViews) > 0 {
+					printBrokenViews(brokenViews)
+				}
+			}
 			return handleMergeErr(ctx, sqlCtx, queryist, dEnv, mergeErr, hasConflicts, hasConstraintViolations, usage)
 		}
 	}
@@ -341,6 +388,9 @@ func printConflictsAndViolations(tblToStats map[string]*merge.MergeStats) (confl
 			}
 			if stats.HasConstraintViolations() {
 				cli.Println("CONSTRAINT VIOLATION (content): Merge created constraint violation in", tblName)
+				for _, v := range stats.ForeignKeyViolations {
+					cli.Println("CONSTRAINT VIOLATION (foreign key):", v.String())
+				}
 				hasConstraintViolations = true
 			}
 		}
@@ -446,6 +496,24 @@ func fillStringWithChar(ch rune, strLen int) string {
 	return string(runes)
 }
 
+// deleteMergedBranch deletes the branch |branchName| and its working set once it has been merged into |headRef|.
+// Merges of commits, tags and remote branches have nothing to delete.
+func deleteMergedBranch(ctx context.Context, dEnv *env.DoltEnv, headRef ref.DoltRef, branchName string) error {
+	name, ok, err := dEnv.DoltDB.HasBranch(ctx, branchName)
+	if err != nil {
+		return err
+	} else if !ok || name == headRef.GetPath() {
+		return nil
+	}
+	// the merge commit is already on |headRef|, so there's no need to check that the branch is merged
+	err = actions.DeleteBranch(ctx, dEnv.DbData(), name, actions.DeleteOptions{Force: true}, dEnv, nil)
+	if err != nil {
+		return err
+	}
+	cli.Printf("Deleted branch %s\n", name)
+	return nil
+}
+
 func handleMergeErr(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, mergeErr error, hasConflicts, hasConstraintViolations bool, usage cli.UsagePrinter) int {
 	ws, err := dEnv.WorkingSet(ctx)
 	if err != nil {
@@ -507,6 +575,191 @@ func performMerge(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryis
 	return executeMergeAndCommit(ctx, sqlCtx, queryist, dEnv, spec, suggestedMsg, cliCtx)
 }
 
+// performDryRunMerge computes the merge described by |spec| and prints the stats, conflicts and constraint violations it
+// would produce, without touching the working set. Returns a non-zero exit code if the merge would not apply cleanly, so
+// that scripts can gate on it.
+func performDryRunMerge(ctx context.Context, dEnv *env.DoltEnv, spec *merge.MergeSpec) int {
+	if spec.HeadH == spec.MergeH {
+		return 0
+	}
+	if ok, err := spec.HeadC.CanFastForwardTo(ctx, spec.MergeC); err != nil {
+		if errors.Is(err, doltdb.ErrUpToDate) || errors.Is(err, doltdb.ErrIsAhead) {
+			return 0
+		}
+		cli.PrintErrln(err.Error())
+		return 1
+	} else if ok {
+		cli.Println("Dry run: no changes were made")
+		return 0
+	}
+
+	tblToStats, err := merge.PreviewMerge(ctx, dEnv, spec)
+	if err != nil {
+		cli.PrintErrln(err.Error())
+		return 1
+	}
+
+	hasConflicts, hasConstraintViolations := printSuccessStats(tblToStats)
+	cli.Println("Dry run: no changes were made")
+	if hasConflicts || hasConstraintViolations {
+		cli.Println("Automatic merge would fail; resolve the conflicts and constraint violations above before merging.")
+		return 1
+	}
+	return 0
+}
+
+// mergeJson is the output of dolt merge --format json
+type mergeJson struct {
+	// Status is one of up_to_date, fast_forward, merged or conflicts. Merges with conflicts or constraint violations
+	// have the conflicts status.
+	Status string `json:"status"`
+	// From and To are the commits at the head of the current branch and of the branch merged, before the merge
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Head is the commit at the head of the current branch after the merge
+	Head string `json:"head"`
+	// Committed is true if the merge created a merge commit
+	Committed bool             `json:"committed"`
+	DryRun    bool             `json:"dry_run"`
+	Tables    []mergeTableJson `json:"tables"`
+}
+
+// mergeTableJson holds the stats of a table the merge changed
+type mergeTableJson struct {
+	Table string `json:"table"`
+	// Operation is one of added, deleted or modified
+	Operation            string `json:"operation"`
+	RowsAdded            int    `json:"rows_added"`
+	RowsModified         int    `json:"rows_modified"`
+	RowsDeleted          int    `json:"rows_deleted"`
+	DataConflicts        int    `json:"data_conflicts"`
+	SchemaConflicts      int    `json:"schema_conflicts"`
+	ConstraintViolations int    `json:"constraint_violations"`
+	// ForeignKeyViolations describes each foreign key violation found when --verify-fks is given
+	ForeignKeyViolations []string `json:"foreign_key_violations"`
+}
+
+// performJsonMerge performs the merge described by |spec| the same way as the text output version of the command,
+// and prints a JSON summary of it in place of the text output. Errors are still printed to stderr.
+func performJsonMerge(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, spec *merge.MergeSpec, suggestedMsg string, dryRun bool, cliCtx cli.CliContext, usage cli.UsagePrinter) int {
+	out := mergeJson{From: spec.HeadH.String(), To: spec.MergeH.String(), DryRun: dryRun, Tables: []mergeTableJson{}}
+
+	textOut := cli.CliOut
+	cli.CliOut = io.Discard
+	res := func() int {
+		if verr := validateMergeSpec(ctx, spec); verr != nil {
+			return handleCommitErr(sqlCtx, queryist, verr, usage)
+		}
+		if spec.HeadH == spec.MergeH {
+			out.Status = "up_to_date"
+			return 0
+		}
+		canFF, err := spec.HeadC.CanFastForwardTo(ctx, spec.MergeC)
+		if errors.Is(err, doltdb.ErrUpToDate) || errors.Is(err, doltdb.ErrIsAhead) {
+			out.Status = "up_to_date"
+			return 0
+		} else if err != nil {
+			cli.PrintErrln(err.Error())
+			return 1
+		}
+		fastForward := canFF && !spec.Noff
+
+		var tblToStats map[string]*merge.MergeStats
+		var mergeErr error
+		if dryRun {
+			if !canFF {
+				tblToStats, mergeErr = merge.PreviewMerge(ctx, dEnv, spec)
+			}
+		} else {
+			tblToStats, mergeErr = performMerge(ctx, sqlCtx, queryist, dEnv, spec, suggestedMsg, cliCtx)
+		}
+		hasConflicts, hasConstraintViolations := printSuccessStats(tblToStats)
+		out.Tables = mergeTablesJson(tblToStats)
+		switch {
+		case fastForward:
+			out.Status = "fast_forward"
+		case hasConflicts || hasConstraintViolations:
+			out.Status = "conflicts"
+		default:
+			out.Status = "merged"
+		}
+
+		if dryRun {
+			if mergeErr != nil {
+				cli.PrintErrln(mergeErr.Error())
+				return 1
+			} else if hasConflicts || hasConstraintViolations {
+				return 1
+			}
+			return 0
+		}
+
+		clean := mergeErr == nil && !hasConflicts && !hasConstraintViolations && !spec.NoCommit
+		if clean {
+			squash := "0"
+			if spec.Squash {
+				squash = "1"
+			}
+			runPostHook(ctx, dEnv, postMergeHook, squash)
+		}
+		out.Committed = clean && !fastForward && !spec.Squash
+		return handleMergeErr(ctx, sqlCtx, queryist, dEnv, mergeErr, hasConflicts, hasConstraintViolations, usage)
+	}()
+	cli.CliOut = textOut
+
+	if res != 0 && out.Status != "conflicts" {
+		return res
+	}
+	rows, err := getRowsForSql(queryist, sqlCtx, "select hashof('HEAD');")
+	if err != nil {
+		cli.PrintErrln(err.Error())
+		return 1
+	}
+	out.Head = rows[0][0].(string)
+	if err = printJson(out); err != nil {
+		cli.PrintErrln(err.Error())
+		return 1
+	}
+	return res
+}
+
+// mergeTablesJson returns the stats of the tables in |tblToStats| that the merge changed, sorted by name
+func mergeTablesJson(tblToStats map[string]*merge.MergeStats) []mergeTableJson {
+	tables := []mergeTableJson{}
+	for tblName, stats := range tblToStats {
+		var op string
+		switch stats.Operation {
+		case merge.TableAdded:
+			op = "added"
+		case merge.TableRemoved:
+			op = "deleted"
+		case merge.TableModified:
+			op = "modified"
+		default:
+			continue
+		}
+		fkViolations := make([]string, len(stats.ForeignKeyViolations))
+		for i, v := range stats.ForeignKeyViolations {
+			fkViolations[i] = v.String()
+		}
+		tables = append(tables, mergeTableJson{
+			Table:                tblName,
+			Operation:            op,
+			RowsAdded:            stats.Adds,
+			RowsModified:         stats.Modifications,
+			RowsDeleted:          stats.Deletes,
+			DataConflicts:        stats.DataConflicts,
+			SchemaConflicts:      stats.SchemaConflicts,
+			ConstraintViolations: stats.ConstraintViolations,
+			ForeignKeyViolations: fkViolations,
+		})
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Table < tables[j].Table
+	})
+	return tables
+}
+
 func executeNoFFMergeAndCommit(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, spec *merge.MergeSpec, suggestedMsg string, cliCtx cli.CliContext) (map[string]*merge.MergeStats, error) {
 	tblToStats, err := merge.ExecNoFFMerge(ctx, dEnv, spec)
 	if err != nil {
@@ -594,7 +847,7 @@ func executeMergeAndCommit(ctx context.Context, sqlCtx *sql.Context, queryist cl
 
 	author := fmt.Sprintf("%s <%s>", spec.Name, spec.Email)
 
-	res, skipped := performCommit(ctx, "commit", []string{"-m", msg, "--author", author}, cliCtx)
+	res, skipped := performCommit(ctx, "commit", []string{"-m", msg, "--author", author}, dEnv, cliCtx)
 	if res != 0 || skipped {
 		return nil, fmt.Errorf("dolt commit failed after merging")
 	}
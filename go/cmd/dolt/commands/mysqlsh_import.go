@@ -0,0 +1,322 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/mvdata"
+	"github.com/dolthub/dolt/go/libraries/doltcore/mysqlshdump"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	mysqlshSchemaParam  = "schema"
+	mysqlshThreadsParam = "threads"
+)
+
+var mysqlshImportDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Imports a dump written by MySQL Shell",
+	LongDesc: `Imports the tables, rows, views and triggers of a schema in {{.LessThan}}dump_dir{{.GreaterThan}}, a directory written by MySQL Shell's {{.EmphasisLeft}}util.dumpInstance(){{.EmphasisRight}}, {{.EmphasisLeft}}util.dumpSchemas(){{.EmphasisRight}} or {{.EmphasisLeft}}util.dumpTables(){{.EmphasisRight}}, into the working set of the current branch.
+
+The tables are created by running the CREATE TABLE statements in the dump, and then the data files of each table are read and written in parallel. Views and triggers are created after all rows are imported. Foreign key checks are disabled during the import. Dumps that MySQL Shell didn't finish writing can't be imported.
+
+If the dump contains more than one schema, {{.EmphasisLeft}}--schema{{.EmphasisRight}} chooses the schema to import.
+`,
+	Synopsis: []string{
+		"[--schema {{.LessThan}}name{{.GreaterThan}}] [--threads {{.LessThan}}n{{.GreaterThan}}] {{.LessThan}}dump_dir{{.GreaterThan}}",
+	},
+}
+
+type MysqlshImportCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd MysqlshImportCmd) Name() string {
+	return "mysqlsh-import"
+}
+
+// Description returns a description of the command
+func (cmd MysqlshImportCmd) Description() string {
+	return mysqlshImportDocs.ShortDesc
+}
+
+func (cmd MysqlshImportCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(mysqlshImportDocs, ap)
+}
+
+func (cmd MysqlshImportCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"dump_dir", "The directory of the dump to import."})
+	ap.SupportsString(mysqlshSchemaParam, "", "name", "The schema in the dump to import. Required if the dump contains more than one schema.")
+	ap.SupportsInt(mysqlshThreadsParam, "", "n", "The number of data files to read at once. Defaults to the number of CPUs.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd MysqlshImportCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd MysqlshImportCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, mysqlshImportDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	if dEnv.IsLocked() {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(env.ErrActiveServerLock.New(dEnv.LockFile())), help)
+	}
+
+	threads := apr.GetIntOrDefault(mysqlshThreadsParam, runtime.GOMAXPROCS(0))
+	if threads < 1 {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: --%s must be at least 1", mysqlshThreadsParam).Build(), usage)
+	}
+
+	dump, err := mysqlshdump.Open(apr.Arg(0))
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: could not open dump '%s'", apr.Arg(0)).AddCause(err).Build(), usage)
+	}
+
+	schemaName, ok := apr.GetValue(mysqlshSchemaParam)
+	if !ok {
+		if len(dump.Schemas()) != 1 {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: the dump contains %d schemas, use --%s to choose one of: %s",
+				len(dump.Schemas()), mysqlshSchemaParam, strings.Join(dump.Schemas(), ", ")).Build(), usage)
+		}
+		schemaName = dump.Schemas()[0]
+	}
+	sch, err := dump.Schema(schemaName)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	if err = mysqlshImport(ctx, dEnv, queryist, sqlCtx, sch, threads); err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: mysqlsh-import failed").AddCause(err).Build(), usage)
+	}
+	return 0
+}
+
+// mysqlshImport creates the tables of |sch|, imports their rows, and then creates its views and triggers
+func mysqlshImport(ctx context.Context, dEnv *env.DoltEnv, queryist cli.Queryist, sqlCtx *sql.Context, sch *mysqlshdump.Schema, threads int) error {
+	// tables may reference each other in any order
+	err := execBatchMode(sqlCtx, queryist, strings.NewReader("SET foreign_key_checks = 0;"), false, engine.FormatTabular)
+	if err != nil {
+		return err
+	}
+
+	for _, tbl := range sch.Tables {
+		if err = execSqlFile(sqlCtx, queryist, tbl.DDL); err != nil {
+			return err
+		}
+	}
+
+	for _, tbl := range sch.Tables {
+		n, err := importMysqlshTable(ctx, dEnv, tbl, threads)
+		if err != nil {
+			return fmt.Errorf("table '%s': %w", tbl.Name, err)
+		}
+		cli.Printf("Imported %d rows into %s\n", n, tbl.Name)
+	}
+
+	for _, view := range sch.Views {
+		if err = execSqlFile(sqlCtx, queryist, view); err != nil {
+			return err
+		}
+	}
+	for _, tbl := range sch.Tables {
+		if tbl.Triggers == "" {
+			continue
+		}
+		if err = execSqlFile(sqlCtx, queryist, tbl.Triggers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func execSqlFile(sqlCtx *sql.Context, queryist cli.Queryist, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err = execBatchMode(sqlCtx, queryist, f, false, engine.FormatTabular); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// importMysqlshTable writes the rows in the data files of |tbl| to the table, reading up to |threads| files at once,
+// and returns the number of rows written
+func importMysqlshTable(ctx context.Context, dEnv *env.DoltEnv, tbl *mysqlshdump.Table, threads int) (int64, error) {
+	if len(tbl.Chunks) == 0 {
+		return 0, nil
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return 0, err
+	}
+	table, tableName, ok, err := root.GetTableInsensitive(ctx, tbl.Name)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("the table was not created by its CREATE TABLE statement")
+	}
+	tableSch, err := table.GetSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the data files may not contain every column, like generated ones, or may order them differently
+	cols := schema.NewColCollection()
+	for _, name := range tbl.Columns {
+		col, ok := tableSch.GetAllCols().GetByNameCaseInsensitive(name)
+		if !ok {
+			return 0, fmt.Errorf("unknown column '%s'", name)
+		}
+		cols = cols.Append(col)
+	}
+	rowOperationSchema, err := schema.SchemaFromCols(cols)
+	if err != nil {
+		return 0, err
+	}
+
+	wr, err := mvdata.NewSqlEngineTableWriter(ctx, dEnv, tableSch, rowOperationSchema, &mvdata.MoverOptions{
+		TableToWriteTo: tableName,
+		Operation:      mvdata.AppendOp,
+		DisableFks:     true,
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	chunks := make(chan string)
+	rows := make(chan sql.Row, 1024)
+	var count int64
+	var rowErr error
+
+	g.Go(func() error {
+		defer close(chunks)
+		for _, path := range tbl.Chunks {
+			select {
+			case chunks <- path:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		return nil
+	})
+
+	var readers sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		readers.Add(1)
+		g.Go(func() error {
+			defer readers.Done()
+			for path := range chunks {
+				if err := readMysqlshChunk(gctx, tbl, path, rows, &count); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		readers.Wait()
+		close(rows)
+		return nil
+	})
+
+	g.Go(func() error {
+		err := wr.WriteRows(gctx, rows, func(row sql.Row, err error) (quit bool) {
+			if rowErr == nil {
+				rowErr = fmt.Errorf("A bad row was encountered: %s: %w", sql.FormatRow(row), err)
+			}
+			return true
+		})
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	})
+
+	err = g.Wait()
+	if rowErr != nil {
+		return 0, rowErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err = wr.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func readMysqlshChunk(ctx context.Context, tbl *mysqlshdump.Table, path string, rows chan<- sql.Row, count *int64) error {
+	cr, err := tbl.OpenChunk(path)
+	if err != nil {
+		return err
+	}
+	defer cr.Close()
+
+	for {
+		row, err := cr.ReadRow()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		select {
+		case rows <- sql.Row(row):
+			atomic.AddInt64(count, 1)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -112,19 +112,36 @@ func (cmd PullCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
-	err = pullHelper(ctx, sqlCtx, queryist, dEnv, pullSpec, cliCtx)
+	verify, err := env.VerifyFetches(dEnv.Config, apr.Contains(cli.VerifyFlag))
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	err = pullHelper(ctx, sqlCtx, queryist, dEnv, pullSpec, verify, cliCtx)
 	if err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 	return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 }
 
-// pullHelper splits pull into fetch, prepare merge, and merge to interleave printing
-func pullHelper(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, pullSpec *env.PullSpec, cliCtx cli.CliContext) error {
+// pullHelper splits pull into fetch, prepare merge, and merge to interleave printing. If |verify| is true, the chunks
+// fetched are verified against their addresses. Chunks are fetched through the shared cache configured for |dEnv|, if
+// there is one.
+func pullHelper(ctx context.Context, sqlCtx *sql.Context, queryist cli.Queryist, dEnv *env.DoltEnv, pullSpec *env.PullSpec, verify bool, cliCtx cli.CliContext) error {
 	srcDB, err := pullSpec.Remote.GetRemoteDBWithoutCaching(ctx, dEnv.DoltDB.ValueReadWriter().Format(), dEnv)
 	if err != nil {
 		return fmt.Errorf("failed to get remote db; %w", err)
 	}
+	if verify {
+		srcDB = srcDB.WithVerifiedPulls()
+	}
+	cacheDir, err := env.SharedCacheDir(dEnv.Config)
+	if err != nil {
+		return err
+	}
+	if cacheDir != "" {
+		srcDB = srcDB.WithSharedCache(cacheDir)
+	}
 
 	// Fetch all references
 	branchRefs, err := srcDB.GetHeadRefs(ctx)
@@ -0,0 +1,185 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/fatih/color"
+	"github.com/gocraft/dbr/v2"
+	"github.com/gocraft/dbr/v2/dialect"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/chunks"
+)
+
+const purgeNoGCFlag = "no-gc"
+
+var purgeHistoryDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Removes old versions of a table from the commit history",
+	LongDesc: `Rewrites the history of all branches and tags so that {{.LessThan}}table{{.GreaterThan}} is removed from every commit created before the {{.EmphasisLeft}}--before{{.EmphasisRight}} date, then runs garbage collection to reclaim the space used by those versions. Commits on or after the cutoff, and the commits that branches and tags point to, keep the table unchanged, so current data and recent history are preserved. All other tables are left as they are.
+
+This is intended for tables with heavy churn, where storing every historical version costs more than it is worth. Like {{.EmphasisLeft}}dolt filter-branch{{.EmphasisRight}}, the hashes of every rewritten commit change, so clones of this database will no longer share history with it. Remote tracking branches are not rewritten, and old versions reachable from them are not collected until they are removed, e.g. with {{.EmphasisLeft}}dolt remote prune{{.EmphasisRight}}.
+
+Purging history requires a clean working set on the current branch. Garbage collection can be skipped with {{.EmphasisLeft}}--no-gc{{.EmphasisRight}}. If a sql-server is running for this database, both steps are run by the server. History can also be purged from SQL with {{.EmphasisLeft}}CALL DOLT_PURGE_HISTORY('--before', {{.LessThan}}date{{.GreaterThan}}, {{.LessThan}}table{{.GreaterThan}}){{.EmphasisRight}}, followed by {{.EmphasisLeft}}CALL DOLT_GC(){{.EmphasisRight}}.
+`,
+	Synopsis: []string{
+		"--before {{.LessThan}}date{{.GreaterThan}} [--no-gc] {{.LessThan}}table{{.GreaterThan}}",
+	},
+}
+
+type PurgeHistoryCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd PurgeHistoryCmd) Name() string {
+	return "purge-history"
+}
+
+// Description returns a description of the command
+func (cmd PurgeHistoryCmd) Description() string {
+	return purgeHistoryDocs.ShortDesc
+}
+
+func (cmd PurgeHistoryCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(purgeHistoryDocs, ap)
+}
+
+func (cmd PurgeHistoryCmd) ArgParser() *argparser.ArgParser {
+	ap := cli.CreatePurgeHistoryArgParser()
+	ap.SupportsFlag(purgeNoGCFlag, "", "Rewrite the history without running garbage collection afterwards.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd PurgeHistoryCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd PurgeHistoryCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, purgeHistoryDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		return HandleVErrAndExitCode(errhand.BuildDError("%s requires a table name", cmd.Name()).SetPrintUsage().Build(), usage)
+	}
+	tableName := apr.Arg(0)
+
+	beforeStr, ok := apr.GetValue(cli.PurgeBeforeParam)
+	if !ok {
+		return HandleVErrAndExitCode(errhand.BuildDError("--%s is required", cli.PurgeBeforeParam).SetPrintUsage().Build(), usage)
+	}
+	cutoff, err := cli.ParseDate(beforeStr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: invalid date").AddCause(err).Build(), usage)
+	}
+
+	purged, err := callPurgeHistory(ctx, cliCtx, beforeStr, tableName)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error rewriting history").AddCause(err).Build(), usage)
+	}
+
+	if purged == 0 {
+		cli.Printf("No versions of '%s' older than %s were found.\n", tableName, cutoff.Format(time.RFC3339))
+		return 0
+	}
+	cli.Printf("Removed '%s' from %d commit(s) older than %s.\n", tableName, purged, cutoff.Format(time.RFC3339))
+
+	if apr.Contains(purgeNoGCFlag) {
+		return 0
+	}
+
+	if dEnv.IsLocked() {
+		// a running server owns the database, so it collects the garbage too
+		if err = callGC(ctx, cliCtx); err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("an error occurred during garbage collection").AddCause(err).Build(), usage)
+		}
+		return 0
+	}
+
+	dEnv, err = MaybeMigrateEnv(ctx, dEnv)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("could not load manifest for gc").AddCause(err).Build(), usage)
+	}
+
+	err = dEnv.DoltDB.GC(ctx, nil)
+	if err != nil {
+		if errors.Is(err, chunks.ErrNothingToCollect) {
+			cli.PrintErrln(color.YellowString("Nothing to collect."))
+			return 0
+		}
+		return HandleVErrAndExitCode(errhand.BuildDError("an error occurred during garbage collection").AddCause(err).Build(), usage)
+	}
+
+	return 0
+}
+
+// callPurgeHistory calls the DOLT_PURGE_HISTORY stored procedure, and returns the number of commits it removed
+// |tableName| from.
+func callPurgeHistory(ctx context.Context, cliCtx cli.CliContext, before, tableName string) (int64, error) {
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if closeFunc != nil {
+		// the engine is closed before garbage collection runs
+		defer closeFunc()
+	}
+
+	query, err := dbr.InterpolateForDialect("CALL DOLT_PURGE_HISTORY(?, ?, ?)", []interface{}{"--" + cli.PurgeBeforeParam, before, tableName}, dialect.MySQL)
+	if err != nil {
+		return 0, err
+	}
+	schema, rowIter, err := queryist.Query(sqlCtx, query)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := sql.RowIterToRows(sqlCtx, schema, rowIter)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) != 1 || len(rows[0]) != 1 {
+		return 0, fmt.Errorf("unexpected result from DOLT_PURGE_HISTORY: %v", rows)
+	}
+	return getInt64ColAsInt64(rows[0][0])
+}
+
+// callGC calls the DOLT_GC stored procedure.
+func callGC(ctx context.Context, cliCtx cli.CliContext) error {
+	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
+	if err != nil {
+		return err
+	}
+	if closeFunc != nil {
+		defer closeFunc()
+	}
+
+	schema, rowIter, err := queryist.Query(sqlCtx, "CALL DOLT_GC()")
+	if err != nil {
+		return err
+	}
+	_, err = sql.RowIterToRows(sqlCtx, schema, rowIter)
+	return err
+}
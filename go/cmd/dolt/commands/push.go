@@ -16,6 +16,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -46,10 +47,13 @@ When the command line does not specify where to push with the {{.LessThan}}remot
 When the command line does not specify what to push with {{.LessThan}}refspec{{.GreaterThan}}... then the current branch will be used.
 
 When neither the command-line does not specify what to push, the default behavior is used, which corresponds to the current branch being pushed to the corresponding upstream branch, but as a safety measure, the push is aborted if the upstream branch does not have the same name as the local one.
+
+With {{.EmphasisLeft}}--all{{.EmphasisRight}}, every local branch is pushed to the branch of the same name on the remote, and with {{.EmphasisLeft}}--tags{{.EmphasisRight}}, every tag is pushed. The two can be combined. The data for all of the refs is sent to the remote in a single transfer, and refs that can't be fast-forwarded are rejected without affecting the others.
 `,
 
 	Synopsis: []string{
 		"[-u | --set-upstream] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}]",
+		"[-u | --set-upstream] [--all] [--tags] [{{.LessThan}}remote{{.GreaterThan}}]",
 	},
 }
 
@@ -91,6 +95,10 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
+	if apr.ContainsAny(cli.AllFlag, cli.TagsFlag) {
+		return HandleVErrAndExitCode(pushAll(ctx, apr, dEnv), usage)
+	}
+
 	opts, err := env.NewPushOpts(ctx, apr, dEnv.RepoStateReader(), dEnv.DoltDB, apr.Contains(cli.ForceFlag), apr.Contains(cli.SetUpstreamFlag), pushAutoSetUpRemote)
 	if err != nil {
 		var verr errhand.VerboseError
@@ -145,6 +153,70 @@ func (cmd PushCmd) Exec(ctx context.Context, commandStr string, args []string, d
 	return HandleVErrAndExitCode(verr, usage)
 }
 
+// pushAll pushes all branches and/or tags to the remote, as requested by --all and --tags
+func pushAll(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEnv) errhand.VerboseError {
+	opts, err := env.NewPushAllOpts(ctx, apr.Args, dEnv.RepoStateReader(), dEnv.DoltDB, apr.Contains(cli.AllFlag), apr.Contains(cli.TagsFlag), apr.Contains(cli.ForceFlag), apr.Contains(cli.SetUpstreamFlag))
+	if err != nil {
+		if err == env.ErrInvalidPushAllArgs {
+			return errhand.BuildDError("error: %s", err.Error()).SetPrintUsage().Build()
+		}
+		return errhand.VerboseErrorFromError(err)
+	}
+	if len(opts) == 0 {
+		cli.Println("Everything up-to-date")
+		return nil
+	}
+
+	remote := opts[0].Remote
+	remoteDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format(), dEnv)
+	if err != nil {
+		err = actions.HandleInitRemoteStorageClientErr(remote.Name, remote.Url, err)
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	results, err := actions.PushAll(ctx, dEnv.RepoStateWriter(), dEnv.DoltDB, remoteDB, tmpDir, opts, buildProgStarter(defaultLanguage), stopProgFuncs)
+	if err != nil {
+		return errhand.BuildDError("error: push failed").AddCause(err).Build()
+	}
+
+	if apr.Contains(cli.SetUpstreamFlag) {
+		if err := dEnv.RepoState.Save(dEnv.FS); err != nil {
+			return errhand.BuildDError("error: failed to save upstream branches").AddCause(err).Build()
+		}
+	}
+
+	var failed []string
+	upToDate := 0
+	for i, res := range results {
+		src, dest := opts[i].SrcRef.GetPath(), opts[i].DestRef.GetPath()
+		switch {
+		case res == nil:
+			cli.Printf(" * %-20s %s -> %s\n", "[pushed]", src, dest)
+		case errors.Is(res, doltdb.ErrUpToDate):
+			upToDate++
+		case errors.Is(res, doltdb.ErrIsAhead), errors.Is(res, actions.ErrCantFF), errors.Is(res, datas.ErrMergeNeeded):
+			cli.Printf(" ! %-20s %s -> %s (non-fast-forward)\n", "[rejected]", src, dest)
+			failed = append(failed, src)
+		default:
+			cli.Printf(" ! %-20s %s -> %s (%s)\n", "[remote rejected]", src, dest, res.Error())
+			failed = append(failed, src)
+		}
+	}
+
+	if upToDate == len(results) {
+		cli.Println("Everything up-to-date")
+	}
+	if len(failed) > 0 {
+		return errhand.BuildDError("error: failed to push some refs to '%s'", remote.Url).Build()
+	}
+	return nil
+}
+
 func printInfoForPushError(err error, remote env.Remote, destRef, remoteRef ref.DoltRef) errhand.VerboseError {
 	switch err {
 	case doltdb.ErrUpToDate:
@@ -197,6 +269,9 @@ func pullerProgFunc(ctx context.Context, statsCh chan pull.Stats, language progL
 					humanize.Bytes(stats.BufferedSendBytes),
 					humanize.SIWithDigits(stats.SendBytesPerSec, 2, "B"),
 				)
+				if stats.ResumedChunks > 0 {
+					p.Printf(" Skipped %s chunks uploaded by an earlier attempt.", humanize.Comma(int64(stats.ResumedChunks)))
+				}
 			}
 			p.Display()
 		}
@@ -116,7 +116,7 @@ func (cmd ReadTablesCmd) Exec(ctx context.Context, commandStr string, args []str
 		return HandleVErrAndExitCode(errhand.BuildDError("Invalid remote url").AddCause(err).Build(), usage)
 	}
 
-	remoteUrlParams, verr := parseRemoteArgs(apr, scheme, remoteUrl)
+	remoteUrlParams, verr := parseRemoteArgs(dEnv, apr, scheme, remoteUrl)
 
 	if verr != nil {
 		return HandleVErrAndExitCode(verr, usage)
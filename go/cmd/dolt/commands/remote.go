@@ -23,8 +23,11 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 )
 
@@ -37,7 +40,7 @@ var remoteDocs = cli.CommandDocumentationContent{
 {{.EmphasisLeft}}add{{.EmphasisRight}}
 Adds a remote named {{.LessThan}}name{{.GreaterThan}} for the repository at {{.LessThan}}url{{.GreaterThan}}. The command dolt fetch {{.LessThan}}name{{.GreaterThan}} can then be used to create and update remote-tracking branches {{.EmphasisLeft}}<name>/<branch>{{.EmphasisRight}}.
 
-The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, gs, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}remotes.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
+The {{.LessThan}}url{{.GreaterThan}} parameter supports url schemes of http, https, aws, gs, az, oci, ssh, and file. The url prefix defaults to https. If the {{.LessThan}}url{{.GreaterThan}} parameter is in the format {{.EmphasisLeft}}<organization>/<repository>{{.EmphasisRight}} then dolt will use the {{.EmphasisLeft}}remotes.default_host{{.EmphasisRight}} from your configuration file (Which will be dolthub.com unless changed).
 
 AWS cloud remote urls should be of the form {{.EmphasisLeft}}aws://[dynamo-table:s3-bucket]/database{{.EmphasisRight}}.  You may configure your aws cloud remote using the optional parameters {{.EmphasisLeft}}aws-region{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-type{{.EmphasisRight}}, {{.EmphasisLeft}}aws-creds-file{{.EmphasisRight}}.
 
@@ -47,17 +50,33 @@ aws-creds-type specifies the means by which credentials should be retrieved in o
 	env: Looks for environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
 	file: Uses the credentials file specified by the parameter aws-creds-file
 	
-GCP remote urls should be of the form gs://gcs-bucket/database and will use the credentials setup using the gcloud command line available from Google.
+GCP remote urls should be of the form gs://gcs-bucket/database and will use the application default credentials, such as those set up using the gcloud command line available from Google, unless a service account key file is given with {{.EmphasisLeft}}gcs-creds-file{{.EmphasisRight}}. Table files are uploaded to GCS with resumable uploads, in chunks whose size can be set with {{.EmphasisLeft}}gcs-upload-chunk-size{{.EmphasisRight}}.
+
+Azure remote urls should be of the form az://storage-account/container/database. By default, a SAS token is used if one is given with {{.EmphasisLeft}}az-sas-token-file{{.EmphasisRight}} or in the AZURE_STORAGE_SAS_TOKEN environment variable, and otherwise the credentials found in the environment, the managed identity of the host, or those set up using the az command line are used. Set {{.EmphasisLeft}}az-creds-type{{.EmphasisRight}} to {{.EmphasisLeft}}sas{{.EmphasisRight}} or {{.EmphasisLeft}}managed-identity{{.EmphasisRight}} to require one kind of credentials. A user-assigned managed identity can be chosen by its client id with {{.EmphasisLeft}}az-managed-identity{{.EmphasisRight}}.
+
+OCI remote urls should be of the form oci://registry/repository:tag, such as oci://ghcr.io/org/dataset:v1, and the tag defaults to latest. The database is stored as an OCI artifact whose layers are its table files, so any registry which supports OCI artifacts can host it, and each tag of a repository is a separate database. By default the credentials for the registry in the docker config are used, such as those set up by {{.EmphasisLeft}}docker login{{.EmphasisRight}}. {{.EmphasisLeft}}oci-user{{.EmphasisRight}} gives a user name to authenticate with instead, and the password is read from the environment variable {{.EmphasisLeft}}DOLT_OCI_PASSWORD{{.EmphasisRight}}. Registries on localhost are accessed over http.
+
+SSH remote urls should be of the form ssh://[user@]host[:port]/path, or use the scp-like syntax [user@]host:path, in which a relative path is relative to the user's home directory on the host. Dolt must be installed on the host, which is connected to by running {{.EmphasisLeft}}ssh{{.EmphasisRight}}, so the keys and configuration of ssh are used. The environment variable {{.EmphasisLeft}}DOLT_SSH{{.EmphasisRight}} gives a different command to run instead of ssh, and {{.EmphasisLeft}}DOLT_SSH_EXEC_PATH{{.EmphasisRight}} gives the path of dolt on the host if it isn't on the PATH. Pushing to a path on the host that isn't a dolt repository stores the database there, as for file remotes.
+
+http and https remotes, such as DoltHub, are accessed with the credentials selected by {{.EmphasisLeft}}user.creds{{.EmphasisRight}} unless the remote is configured with its own credentials. {{.EmphasisLeft}}--creds-key{{.EmphasisRight}} gives the id or public key of the credentials to use, as listed by {{.EmphasisLeft}}dolt creds ls{{.EmphasisRight}}. {{.EmphasisLeft}}--creds-user{{.EmphasisRight}} gives a user name to authenticate with, and the password is read from the environment variable {{.EmphasisLeft}}DOLT_REMOTE_PASSWORD{{.EmphasisRight}}. {{.EmphasisLeft}}--creds-helper{{.EmphasisRight}} gives a credential helper, a program that is run with the argument {{.EmphasisLeft}}get{{.EmphasisRight}} to get the credentials, such as a short-lived token from a secret store. It's written the protocol and host of the remote on stdin as lines of the form key=value, and writes back either a {{.EmphasisLeft}}token{{.EmphasisRight}} or a {{.EmphasisLeft}}username{{.EmphasisRight}} and {{.EmphasisLeft}}password{{.EmphasisRight}} in the same form, with an optional RFC 3339 {{.EmphasisLeft}}expiry{{.EmphasisRight}} after which it's run again. Remotes without credentials of their own use the helper set by {{.EmphasisLeft}}creds.helper{{.EmphasisRight}}, if any. A {{.EmphasisLeft}}--user{{.EmphasisRight}} given to a command such as {{.EmphasisLeft}}dolt push{{.EmphasisRight}} takes precedence over the credentials of the remote.
 
 The local filesystem can be used as a remote by providing a repository url in the format file://absolute path. See https://en.wikipedia.org/wiki/File_URI_scheme
 
 {{.EmphasisLeft}}remove{{.EmphasisRight}}, {{.EmphasisLeft}}rm{{.EmphasisRight}}
-Remove the remote named {{.LessThan}}name{{.GreaterThan}}. All remote-tracking branches and configuration settings for the remote are removed.`,
+Remove the remote named {{.LessThan}}name{{.GreaterThan}}. All remote-tracking branches and configuration settings for the remote are removed.
+
+{{.EmphasisLeft}}prune{{.EmphasisRight}}
+Deletes the remote-tracking branches of the remote named {{.LessThan}}name{{.GreaterThan}} whose branch no longer exists on the remote. With {{.EmphasisLeft}}--dry-run{{.EmphasisRight}}, the remote-tracking branches that would be deleted are listed, but not deleted.
+
+{{.EmphasisLeft}}set-creds{{.EmphasisRight}}
+Replaces the credentials configured for the remote named {{.LessThan}}name{{.GreaterThan}} with the credentials given by the same parameters that {{.EmphasisLeft}}add{{.EmphasisRight}} accepts. With no parameters, the remote is accessed with the default credentials.`,
 
 	Synopsis: []string{
 		"[-v | --verbose]",
-		"add [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}} {{.LessThan}}url{{.GreaterThan}}",
+		"add [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] [--gcs-creds-file {{.LessThan}}file{{.GreaterThan}}] [--gcs-upload-chunk-size {{.LessThan}}bytes{{.GreaterThan}}] [--az-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--az-sas-token-file {{.LessThan}}file{{.GreaterThan}}] [--az-managed-identity {{.LessThan}}client-id{{.GreaterThan}}] [--oci-user {{.LessThan}}user{{.GreaterThan}}] [--creds-key {{.LessThan}}key{{.GreaterThan}}] [--creds-user {{.LessThan}}user{{.GreaterThan}}] [--creds-helper {{.LessThan}}command{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}} {{.LessThan}}url{{.GreaterThan}}",
 		"remove {{.LessThan}}name{{.GreaterThan}}",
+		"prune [--dry-run] {{.LessThan}}name{{.GreaterThan}}",
+		"set-creds [--creds-key {{.LessThan}}key{{.GreaterThan}}] [--creds-user {{.LessThan}}user{{.GreaterThan}}] [--creds-helper {{.LessThan}}command{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] [--gcs-creds-file {{.LessThan}}file{{.GreaterThan}}] [--az-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--az-sas-token-file {{.LessThan}}file{{.GreaterThan}}] [--az-managed-identity {{.LessThan}}client-id{{.GreaterThan}}] [--oci-user {{.LessThan}}user{{.GreaterThan}}] {{.LessThan}}name{{.GreaterThan}}",
 	},
 }
 
@@ -65,6 +84,8 @@ const (
 	addRemoteId         = "add"
 	removeRemoteId      = "remove"
 	removeRemoteShortId = "rm"
+	pruneRemoteId       = "prune"
+	setCredsRemoteId    = "set-creds"
 )
 
 type RemoteCmd struct{}
@@ -90,6 +111,7 @@ func (cmd RemoteCmd) ArgParser() *argparser.ArgParser {
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"creds-type", "credential type.  Valid options are role, env, and file.  See the help section for additional details."})
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"profile", "AWS profile to use."})
 	ap.SupportsFlag(cli.VerboseFlag, "v", "When printing the list of remotes adds additional details.")
+	ap.SupportsFlag(cli.DryRunFlag, "", "When pruning, lists the remote-tracking branches that would be deleted without deleting them.")
 	ap.SupportsString(dbfactory.OSSCredsFileParam, "", "file", "OSS credentials file")
 	ap.SupportsString(dbfactory.OSSCredsProfile, "", "profile", "OSS profile to use")
 	return ap
@@ -117,6 +139,10 @@ func (cmd RemoteCmd) Exec(ctx context.Context, commandStr string, args []string,
 		verr = removeRemote(ctx, dEnv, apr)
 	case apr.Arg(0) == removeRemoteShortId:
 		verr = removeRemote(ctx, dEnv, apr)
+	case apr.Arg(0) == pruneRemoteId:
+		verr = pruneRemote(ctx, dEnv, apr)
+	case apr.Arg(0) == setCredsRemoteId:
+		verr = setRemoteCreds(dEnv, apr)
 	default:
 		verr = errhand.BuildDError("").SetPrintUsage().Build()
 	}
@@ -148,6 +174,49 @@ func removeRemote(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 	}
 }
 
+func pruneRemote(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	remoteName := strings.TrimSpace(apr.Arg(1))
+	remotes, err := dEnv.GetRemotes()
+	if err != nil {
+		return errhand.BuildDError("error: failed to read remotes").AddCause(err).Build()
+	}
+
+	r, ok := remotes[remoteName]
+	if !ok {
+		return errhand.BuildDError("error: unknown remote: '%s' ", remoteName).Build()
+	}
+
+	srcDB, err := r.GetRemoteDBWithoutCaching(ctx, dEnv.DbData().Ddb.ValueReadWriter().Format(), dEnv)
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	dryRun := apr.Contains(cli.DryRunFlag)
+	pruned, err := actions.PruneBranches(ctx, dEnv.DbData(), srcDB, nil, r, dryRun)
+	if err != nil {
+		return errhand.BuildDError("error: failed to prune remote '%s'", remoteName).AddCause(err).Build()
+	}
+
+	cli.Printf("Pruning %s\nURL: %s\n", r.Name, r.Url)
+	printPrunedRefs(pruned, dryRun)
+	return nil
+}
+
+// printPrunedRefs prints the remote-tracking branches deleted by a prune
+func printPrunedRefs(pruned []ref.DoltRef, dryRun bool) {
+	status := "pruned"
+	if dryRun {
+		status = "would prune"
+	}
+	for _, r := range pruned {
+		cli.Printf(" * [%s] %s\n", status, r.GetPath())
+	}
+}
+
 func addRemote(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
 	if apr.NArg() != 3 {
 		return errhand.BuildDError("").SetPrintUsage().Build()
@@ -161,7 +230,7 @@ func addRemote(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbos
 		return errhand.BuildDError("error: '%s' is not valid.", remoteUrl).AddCause(err).Build()
 	}
 
-	params, verr := parseRemoteArgs(apr, scheme, absRemoteUrl)
+	params, verr := parseRemoteArgs(dEnv, apr, scheme, absRemoteUrl)
 	if verr != nil {
 		return verr
 	}
@@ -185,7 +254,7 @@ func addRemote(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbos
 	}
 }
 
-func parseRemoteArgs(apr *argparser.ArgParseResults, scheme, remoteUrl string) (map[string]string, errhand.VerboseError) {
+func parseRemoteArgs(dEnv *env.DoltEnv, apr *argparser.ArgParseResults, scheme, remoteUrl string) (map[string]string, errhand.VerboseError) {
 	params := map[string]string{}
 
 	var err error
@@ -197,13 +266,107 @@ func parseRemoteArgs(apr *argparser.ArgParseResults, scheme, remoteUrl string) (
 	default:
 		err = cli.VerifyNoAwsParams(apr)
 	}
+	if err == nil {
+		err = cli.AddGCSParams(remoteUrl, apr, params)
+	}
+	if err == nil {
+		err = cli.AddAzureParams(remoteUrl, apr, params)
+	}
+	if err == nil {
+		err = cli.AddOCIParams(remoteUrl, apr, params)
+	}
+	if err == nil {
+		err = cli.AddGRPCCredsParams(scheme, apr, params)
+	}
+	if err == nil {
+		err = cli.AddEncryptionParams(scheme, apr, params)
+	}
 	if err != nil {
 		return nil, errhand.VerboseErrorFromError(err)
 	}
 
+	if verr := resolveCredsKey(dEnv, params); verr != nil {
+		return nil, verr
+	}
+
 	return params, nil
 }
 
+// resolveCredsKey checks that the credentials named by the creds-key param in |params| exist, and replaces a public key
+// given for the param with the id of the credentials
+func resolveCredsKey(dEnv *env.DoltEnv, params map[string]string) errhand.VerboseError {
+	key, ok := params[dbfactory.GRPCCredsKeyParam]
+	if !ok {
+		return nil
+	}
+
+	if len(key) == creds.B32EncodedPubKeyLen {
+		if kid, err := creds.PubKeyStrToKIDStr(key); err == nil {
+			key = kid
+		}
+	}
+
+	if _, err := dEnv.DoltCredsForKeyID(key); err != nil {
+		return errhand.BuildDError("error: invalid value for %s", dbfactory.GRPCCredsKeyParam).AddCause(err).Build()
+	}
+
+	params[dbfactory.GRPCCredsKeyParam] = key
+	return nil
+}
+
+func setRemoteCreds(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	remoteName := strings.TrimSpace(apr.Arg(1))
+	remotes, err := dEnv.GetRemotes()
+	if err != nil {
+		return errhand.BuildDError("error: failed to read remotes").AddCause(err).Build()
+	}
+
+	r, ok := remotes[remoteName]
+	if !ok {
+		return errhand.BuildDError("error: unknown remote: '%s' ", remoteName).Build()
+	}
+
+	scheme, absRemoteUrl, err := env.GetAbsRemoteUrl(dEnv.FS, dEnv.Config, r.Url)
+	if err != nil {
+		return errhand.BuildDError("error: '%s' is not valid.", r.Url).AddCause(err).Build()
+	}
+
+	params, verr := parseRemoteArgs(dEnv, apr, scheme, absRemoteUrl)
+	if verr != nil {
+		return verr
+	}
+
+	// the new credentials replace all of the credentials previously configured for the remote
+	credsParams := []string{
+		dbfactory.GRPCCredsKeyParam, dbfactory.GRPCUserParam, dbfactory.GRPCCredsHelperParam,
+		dbfactory.AWSCredsTypeParam, dbfactory.AWSCredsFileParam, dbfactory.AWSCredsProfile,
+		dbfactory.OSSCredsFileParam, dbfactory.OSSCredsProfile,
+		dbfactory.GCSCredsFileParam,
+		dbfactory.AzureCredsTypeParam, dbfactory.AzureSASTokenFileParam, dbfactory.AzureManagedIdentityParam,
+		dbfactory.OCIUserParam,
+	}
+	updated := make(map[string]string)
+	for k, v := range r.Params {
+		updated[k] = v
+	}
+	for _, p := range credsParams {
+		delete(updated, p)
+	}
+	for k, v := range params {
+		updated[k] = v
+	}
+	r.Params = updated
+
+	if err = dEnv.UpdateRemote(r); err != nil {
+		return errhand.BuildDError("error: Unable to save changes.").AddCause(err).Build()
+	}
+	return nil
+}
+
 func printRemotes(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
 	remotes, err := dEnv.GetRemotes()
 
@@ -15,10 +15,14 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -46,7 +50,11 @@ type showOpts struct {
 
 var showDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Show information about a specific commit`,
-	LongDesc:  `Show information about a specific commit`,
+	LongDesc: `Show information about a specific commit.
+
+Prints the commit's metadata followed by the schema and data changes it made relative to its first parent. Merge commits are diffed against their first parent, the branch that was merged into.
+
+Use {{.EmphasisLeft}}--stat{{.EmphasisRight}} to summarize the changed rows and cells instead of printing them. Use {{.EmphasisLeft}}--format json{{.EmphasisRight}} to print the metadata and the diff together as a single JSON document, with the commit under {{.EmphasisLeft}}commit{{.EmphasisRight}} and the changes in the same format as {{.EmphasisLeft}}dolt diff --format json{{.EmphasisRight}} under {{.EmphasisLeft}}diff{{.EmphasisRight}}.`,
 	Synopsis: []string{
 		`[{{.LessThan}}revision{{.GreaterThan}}]`,
 	},
@@ -87,6 +95,7 @@ func (cmd ShowCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(StatFlag, "", "Show stats of data changes")
 	ap.SupportsFlag(SummaryFlag, "", "Show summary of data and schema changes")
 	ap.SupportsString(FormatFlag, "r", "result output format", "How to format diff output. Valid values are tabular, sql, json. Defaults to tabular.")
+	ap.SupportsAlias(formatFlag, FormatFlag)
 	ap.SupportsString(whereParam, "", "column", "filters columns based on values in the diff.  See {{.EmphasisLeft}}dolt diff --help{{.EmphasisRight}} for details.")
 	ap.SupportsInt(limitParam, "", "record_count", "limits to the first N diffs.")
 	ap.SupportsFlag(cli.CachedFlag, "c", "Show only the staged data changes.")
@@ -137,6 +146,10 @@ func (cmd ShowCmd) validateArgs(apr *argparser.ArgParseResults) errhand.VerboseE
 		return errhand.BuildDError("invalid output format: %s", f).Build()
 	}
 
+	if strings.ToLower(f) == "json" && (apr.Contains(StatFlag) || apr.Contains(SummaryFlag)) {
+		return errhand.BuildDError("invalid Arguments: --stat and --summary cannot be combined with --format json").Build()
+	}
+
 	return nil
 }
 
@@ -271,7 +284,7 @@ func showCommitSpec(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm
 
 func showCommit(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *doltdb.Commit) error {
 
-	cHashToRefs, err := getHashToRefs(ctx, dEnv, opts.decoration)
+	cHashToRefs, err := getHashToRefs(ctx, dEnv, opts.decoration, true)
 	if err != nil {
 		return err
 	}
@@ -301,6 +314,10 @@ func showCommit(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *do
 		return err
 	}
 
+	if opts.diffOutput == JsonDiffOutput {
+		return showCommitJson(ctx, dEnv, opts, comm, meta, cmHash, pHashes)
+	}
+
 	cli.ExecuteWithStdioRestored(func() {
 		pager := outputpager.Start()
 		defer pager.Stop()
@@ -317,10 +334,11 @@ func showCommit(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *do
 		return nil
 	}
 
-	if comm.NumParents() > 1 {
-		return fmt.Errorf("requested commit is a merge commit. 'dolt show' currently only supports viewing non-merge commits")
-	}
+	return showCommitDiff(ctx, dEnv, opts, comm, cmHash, cli.CliOut)
+}
 
+// showCommitDiff prints the diff between the commit given and its first parent. JSON diffs are written to |jsonOut|.
+func showCommitDiff(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *doltdb.Commit, cmHash hash.Hash, jsonOut io.Writer) error {
 	commitRoot, err := comm.GetRootValue(ctx)
 	if err != nil {
 		return err
@@ -360,7 +378,63 @@ func showCommit(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *do
 		diffDisplaySettings: opts.diffDisplaySettings,
 		diffDatasets:        datasets,
 		tableSet:            tableSet,
+		jsonOut:             jsonOut,
 	}
 
 	return diffUserTables(ctx, dEnv, dArgs)
 }
+
+type showCommitJsonMeta struct {
+	Hash    string   `json:"hash"`
+	Parents []string `json:"parents"`
+	Author  string   `json:"author"`
+	Email   string   `json:"email"`
+	Date    string   `json:"date"`
+	Message string   `json:"message"`
+}
+
+type showCommitJsonDoc struct {
+	Commit showCommitJsonMeta `json:"commit"`
+	Diff   json.RawMessage    `json:"diff"`
+}
+
+// showCommitJson prints the commit given and its diff against its first parent as a single JSON document
+func showCommitJson(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *doltdb.Commit, meta *datas.CommitMeta, cmHash hash.Hash, pHashes []hash.Hash) error {
+	parents := make([]string, len(pHashes))
+	for i, h := range pHashes {
+		parents[i] = h.String()
+	}
+
+	doc := showCommitJsonDoc{
+		Commit: showCommitJsonMeta{
+			Hash:    cmHash.String(),
+			Parents: parents,
+			Author:  meta.Name,
+			Email:   meta.Email,
+			Date:    meta.Time().UTC().Format(time.RFC3339),
+			Message: meta.Description,
+		},
+		Diff: json.RawMessage("{}"),
+	}
+
+	if comm.NumParents() > 0 {
+		// Capture the diff to embed it in the document
+		buf := &bytes.Buffer{}
+		err := showCommitDiff(ctx, dEnv, opts, comm, cmHash, buf)
+		if err != nil {
+			return err
+		}
+
+		if diffJson := bytes.TrimSpace(buf.Bytes()); len(diffJson) > 0 {
+			doc.Diff = diffJson
+		}
+	}
+
+	docJson, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	cli.Println(string(docJson))
+	return nil
+}
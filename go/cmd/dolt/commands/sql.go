@@ -40,6 +40,7 @@ import (
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/pgdump"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -53,6 +54,8 @@ var sqlDocs = cli.CommandDocumentationContent{
 
 Multiple SQL statements must be separated by semicolons. Use {{.EmphasisLeft}}-b{{.EmphasisRight}} to enable batch mode to speed up large batches of INSERT / UPDATE statements. Pipe SQL files to dolt sql (no {{.EmphasisLeft}}-q{{.EmphasisRight}}) to execute a SQL import or update script. 
 
+With {{.EmphasisLeft}}--pg-dump{{.EmphasisRight}}, the input is read as a dump written by {{.EmphasisLeft}}pg_dump --format=plain{{.EmphasisRight}}, and is translated from the Postgres dialect before it is run. Tables, constraints, indexes, views, enum types and the data in COPY blocks are translated, and serial and identity columns become AUTO_INCREMENT columns. Statements that only apply to Postgres, like those about owners, privileges and sequences, are dropped, and statements that can't be translated, like CREATE FUNCTION, are skipped with a warning.
+
 Queries can be saved to the query catalog with {{.EmphasisLeft}}-s{{.EmphasisRight}}. Alternatively {{.EmphasisLeft}}-x{{.EmphasisRight}} can be used to execute a saved query by name.
 
 By default this command uses the dolt database in the current working directory, as well as any dolt databases that are found in the current directory. Any databases created with CREATE DATABASE are placed in the current directory as well. Running with {{.EmphasisLeft}}--data-dir <directory>{{.EmphasisRight}} uses each of the subdirectories of the supplied directory (each subdirectory must be a valid dolt data repository) as databases. Subdirectories starting with '.' are ignored.`,
@@ -60,6 +63,7 @@ By default this command uses the dolt database in the current working directory,
 	Synopsis: []string{
 		"",
 		"< script.sql",
+		"--pg-dump < dump.sql",
 		"[--data-dir {{.LessThan}}directory{{.GreaterThan}}] [-r {{.LessThan}}result format{{.GreaterThan}}]",
 		"-q {{.LessThan}}query{{.GreaterThan}} [-r {{.LessThan}}result format{{.GreaterThan}}] [-s {{.LessThan}}name{{.GreaterThan}} -m {{.LessThan}}message{{.GreaterThan}}] [-b]",
 		"-q {{.LessThan}}query{{.GreaterThan}} --data-dir {{.LessThan}}directory{{.GreaterThan}} [-r {{.LessThan}}result format{{.GreaterThan}}] [-b]",
@@ -88,6 +92,7 @@ const (
 	DefaultBranchCtrlName = "branch_control.db"
 	continueFlag          = "continue"
 	fileInputFlag         = "file"
+	pgDumpFlag            = "pg-dump"
 	UserFlag              = "user"
 	DefaultUser           = "root"
 	DefaultHost           = "localhost"
@@ -133,7 +138,7 @@ func (cmd SqlCmd) Docs() *cli.CommandDocumentation {
 func (cmd SqlCmd) ArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
 	ap.SupportsString(QueryFlag, "q", "SQL query to run", "Runs a single query and exits.")
-	ap.SupportsString(FormatFlag, "r", "result output format", "How to format result output. Valid values are tabular, csv, json, vertical, and parquet. Defaults to tabular.")
+	ap.SupportsString(FormatFlag, "r", "result output format", "How to format result output. Valid values are tabular, csv, json, jsonl, vertical, and parquet. Defaults to tabular.")
 	ap.SupportsString(saveFlag, "s", "saved query name", "Used with --query, save the query to the query catalog with the name provided. Saved queries can be examined in the dolt_query_catalog system table.")
 	ap.SupportsString(executeFlag, "x", "saved query name", "Executes a saved query with the given name.")
 	ap.SupportsFlag(listSavedFlag, "l", "List all saved queries.")
@@ -141,6 +146,7 @@ func (cmd SqlCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(BatchFlag, "b", "Use to enable more efficient batch processing for large SQL import scripts. This mode is no longer supported and this flag is a no-op. To speed up your SQL imports, use either LOAD DATA, or structure your SQL import script to insert many rows per statement.")
 	ap.SupportsFlag(continueFlag, "c", "Continue running queries on an error. Used for batch mode only.")
 	ap.SupportsString(fileInputFlag, "f", "input file", "Execute statements from the file given.")
+	ap.SupportsFlag(pgDumpFlag, "", "Translate the input from a dump written by pg_dump --format=plain before executing it.")
 	return ap
 }
 
@@ -226,22 +232,37 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 		}
 
 		_, continueOnError := apr.GetValue(continueFlag)
+		pgDump := apr.Contains(pgDumpFlag)
 
-		input := os.Stdin
+		var input io.Reader = os.Stdin
 		if fileInput, ok := apr.GetValue(fileInputFlag); ok {
 			isTty = false
-			input, err = os.OpenFile(fileInput, os.O_RDONLY, os.ModePerm)
+			f, err := os.OpenFile(fileInput, os.O_RDONLY, os.ModePerm)
 			if err != nil {
 				return sqlHandleVErrAndExitCode(queryist, errhand.BuildDError("couldn't open file %s", fileInput).Build(), usage)
 			}
+			defer f.Close()
+			input = f
 			info, err := os.Stat(fileInput)
 			if err != nil {
 				return sqlHandleVErrAndExitCode(queryist, errhand.BuildDError("couldn't get file size %s", fileInput).Build(), usage)
 			}
 
-			// initialize fileReadProg global variable if there is a file to process queries from
-			fileReadProg = &fileReadProgress{bytesRead: 0, totalBytes: info.Size(), printed: 0, displayStrLen: 0}
-			defer fileReadProg.close()
+			// initialize fileReadProg global variable if there is a file to process queries from. The progress of a
+			// pg_dump file can't be measured by the size of the translated statements.
+			if !pgDump {
+				fileReadProg = &fileReadProgress{bytesRead: 0, totalBytes: info.Size(), printed: 0, displayStrLen: 0}
+				defer fileReadProg.close()
+			}
+		}
+
+		if pgDump {
+			if isTty {
+				return sqlHandleVErrAndExitCode(queryist, errhand.BuildDError("error: --%s requires a dump on stdin or a file given with --%s", pgDumpFlag, fileInputFlag).Build(), usage)
+			}
+			input = pgdump.NewTranslator(input, func(msg string) {
+				cli.PrintErrln(color.YellowString("warning: %s", msg))
+			})
 		}
 
 		if isTty {
@@ -297,7 +318,7 @@ func (cmd SqlCmd) handleLegacyArguments(ap *argparser.ArgParser, commandStr stri
 	if err != nil {
 		legacyParser := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
 		legacyParser.SupportsString(QueryFlag, "q", "SQL query to run", "Runs a single query and exits.")
-		legacyParser.SupportsString(FormatFlag, "r", "result output format", "How to format result output. Valid values are tabular, csv, json, vertical, and parquet. Defaults to tabular.")
+		legacyParser.SupportsString(FormatFlag, "r", "result output format", "How to format result output. Valid values are tabular, csv, json, jsonl, vertical, and parquet. Defaults to tabular.")
 		legacyParser.SupportsString(saveFlag, "s", "saved query name", "Used with --query, save the query to the query catalog with the name provided. Saved queries can be examined in the dolt_query_catalog system table.")
 		legacyParser.SupportsString(executeFlag, "x", "saved query name", "Executes a saved query with the given name.")
 		legacyParser.SupportsFlag(listSavedFlag, "l", "List all saved queries.")
@@ -514,6 +535,8 @@ func GetResultFormat(format string) (engine.PrintResultFormat, errhand.VerboseEr
 		return engine.FormatCsv, nil
 	case "json":
 		return engine.FormatJson, nil
+	case "jsonl":
+		return engine.FormatJsonl, nil
 	case "null":
 		return engine.FormatNull, nil
 	case "vertical":
@@ -521,7 +544,7 @@ func GetResultFormat(format string) (engine.PrintResultFormat, errhand.VerboseEr
 	case "parquet":
 		return engine.FormatParquet, nil
 	default:
-		return engine.FormatTabular, errhand.BuildDError("Invalid argument for --result-format. Valid values are tabular, csv, json").Build()
+		return engine.FormatTabular, errhand.BuildDError("Invalid argument for --result-format. Valid values are tabular, csv, json, jsonl").Build()
 	}
 }
 
@@ -534,6 +557,10 @@ func validateSqlArgs(apr *argparser.ArgParseResults) error {
 	_, dataDir := apr.GetValue(DataDirFlag)
 	_, multiDbDir := apr.GetValue(MultiDBDirFlag)
 
+	if apr.Contains(pgDumpFlag) && (query || execute || list) {
+		return errhand.BuildDError("Invalid Argument: --%s is only used with statements from stdin or --%s", pgDumpFlag, fileInputFlag).Build()
+	}
+
 	if len(apr.Args) > 0 && !query {
 		return errhand.BuildDError("Invalid Argument: use --query or -q to pass inline SQL queries").Build()
 	}
@@ -0,0 +1,130 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const (
+	defaultBackgroundConjoinInterval    = time.Minute
+	defaultBackgroundConjoinBytesPerSec = 32 * 1024 * 1024
+
+	// backgroundConjoinMaxTables is the number of table files a database may have before they are conjoined in
+	// the background. Manifest updates only force a conjoin at a much larger number of table files.
+	backgroundConjoinMaxTables = 64
+)
+
+// backgroundConjoiner periodically conjoins the table files of the databases served by a sql-server, so that
+// reads don't have to search hundreds of small table files. Conjoins share a single rate limit on the table file
+// data they read, so they don't starve queries of IO.
+type backgroundConjoiner struct {
+	se       *engine.SqlEngine
+	interval time.Duration
+	lim      *rate.Limiter
+	lgr      *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newBackgroundConjoiner returns a backgroundConjoiner for the databases of |se| configured by |cfg|, or nil if
+// background conjoins are disabled.
+func newBackgroundConjoiner(cfg ServerConfig, se *engine.SqlEngine, lgr *logrus.Logger) *backgroundConjoiner {
+	if cfg.DisableBackgroundConjoin() || cfg.ReadOnly() {
+		return nil
+	}
+	interval := time.Duration(cfg.BackgroundConjoinIntervalSecs()) * time.Second
+	if interval <= 0 {
+		interval = defaultBackgroundConjoinInterval
+	}
+	bytesPerSec := cfg.BackgroundConjoinBytesPerSec()
+	if bytesPerSec <= 0 {
+		bytesPerSec = defaultBackgroundConjoinBytesPerSec
+	}
+	return &backgroundConjoiner{
+		se:       se,
+		interval: interval,
+		lim:      rate.NewLimiter(rate.Limit(bytesPerSec), bytesPerSec),
+		lgr:      lgr,
+	}
+}
+
+// Start starts conjoining table files in the background until Stop is called.
+func (bc *backgroundConjoiner) Start(ctx context.Context) {
+	ctx, bc.cancel = context.WithCancel(ctx)
+	bc.wg.Add(1)
+	go func() {
+		defer bc.wg.Done()
+		ticker := time.NewTicker(bc.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				bc.conjoinAll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops conjoining table files, waiting for a running conjoin to finish or abort.
+func (bc *backgroundConjoiner) Stop() {
+	bc.cancel()
+	bc.wg.Wait()
+}
+
+func (bc *backgroundConjoiner) conjoinAll(ctx context.Context) {
+	sqlCtx, err := bc.se.NewDefaultContext(ctx)
+	if err != nil {
+		bc.lgr.Warnf("error creating context for background conjoin: %v", err)
+		return
+	}
+
+	seen := make(map[*doltdb.DoltDB]struct{})
+	for _, db := range bc.se.Databases(sqlCtx) {
+		ddb := db.DbData().Ddb
+		if _, ok := seen[ddb]; ok || ddb == nil {
+			continue
+		}
+		seen[ddb] = struct{}{}
+
+		cs := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(ddb))
+		conjoiner, ok := cs.(nbs.TableFileConjoiner)
+		if !ok {
+			continue
+		}
+		start := time.Now()
+		conjoined, err := conjoiner.ConjoinTableFiles(ctx, backgroundConjoinMaxTables, bc.lim)
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			bc.lgr.Warnf("error conjoining table files of database %s: %v", db.Name(), err)
+		} else if conjoined {
+			bc.lgr.Debugf("conjoined table files of database %s in %v", db.Name(), time.Since(start))
+		}
+	}
+}
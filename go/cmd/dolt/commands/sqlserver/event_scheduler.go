@@ -0,0 +1,288 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+)
+
+// eventSchedulerInterval is how often the event scheduler checks for events that are due. Events can't run more
+// often than this.
+const eventSchedulerInterval = time.Second
+
+// eventScheduler runs the events defined with CREATE EVENT in the databases served by a sql-server. Events are read
+// from, and run on, a single configured branch of each database, and whatever changes an event makes are committed
+// to that branch.
+//
+// Like MySQL, the scheduler does not catch up on executions missed while the server was down: recurring events next
+// run at their first scheduled time after the server starts, and one-time events scheduled before the server started
+// never run.
+type eventScheduler struct {
+	se     *engine.SqlEngine
+	branch string
+	lgr    *logrus.Logger
+
+	// started is when the scheduler started. Executions scheduled before it are skipped.
+	started time.Time
+	// next is the next execution time of every scheduled event, keyed by eventKey. It's the zero time for events
+	// that will never run again.
+	next map[string]time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newEventScheduler returns an eventScheduler for the databases of |se| configured by |cfg|, or nil if the event
+// scheduler is disabled.
+func newEventScheduler(cfg ServerConfig, se *engine.SqlEngine, lgr *logrus.Logger) *eventScheduler {
+	if !cfg.EventScheduler() || cfg.ReadOnly() {
+		return nil
+	}
+	return &eventScheduler{
+		se:     se,
+		branch: cfg.EventSchedulerBranch(),
+		lgr:    lgr,
+		next:   make(map[string]time.Time),
+	}
+}
+
+// Start starts running events until Stop is called.
+func (es *eventScheduler) Start(ctx context.Context) {
+	ctx, es.cancel = context.WithCancel(ctx)
+	es.started = time.Now()
+	es.wg.Add(1)
+	go func() {
+		defer es.wg.Done()
+		ticker := time.NewTicker(eventSchedulerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				es.runDueEvents(ctx, time.Now())
+			}
+		}
+	}()
+}
+
+// Stop stops running events, waiting for a running event to finish or abort.
+func (es *eventScheduler) Stop() {
+	es.cancel()
+	es.wg.Wait()
+}
+
+func (es *eventScheduler) runDueEvents(ctx context.Context, now time.Time) {
+	sqlCtx, err := es.se.NewLocalContext(ctx)
+	if err != nil {
+		es.lgr.Warnf("error creating context for event scheduler: %v", err)
+		return
+	}
+
+	seen := make(map[string]struct{})
+	for _, db := range es.se.Databases(sqlCtx) {
+		dbName := db.Name()
+		if es.branch != "" {
+			dbName = dbName + "/" + es.branch
+		}
+		events, err := es.loadEvents(ctx, dbName)
+		if err != nil {
+			es.lgr.Warnf("error loading events of database %s: %v", dbName, err)
+			continue
+		}
+
+		for _, ed := range events {
+			key := eventKey(dbName, ed)
+			seen[key] = struct{}{}
+			next, ok := es.next[key]
+			if !ok {
+				next, _ = nextEventExecution(ed, es.started, true)
+				es.next[key] = next
+			}
+			if next.IsZero() || next.After(now) {
+				continue
+			}
+
+			if err := es.runEvent(ctx, dbName, ed); err != nil {
+				es.lgr.Warnf("error running event %s in database %s: %v", ed.Details.Name, dbName, err)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			es.next[key], _ = nextEventExecution(ed, now, false)
+		}
+	}
+
+	// forget dropped and altered events
+	for key := range es.next {
+		if _, ok := seen[key]; !ok {
+			delete(es.next, key)
+		}
+	}
+}
+
+// scheduledEvent is an event with its ON SCHEDULE clause resolved.
+type scheduledEvent struct {
+	Details sql.EventDetails
+	// Every is the interval of a recurring event, or nil for a one-time event.
+	Every *plan.EventOnScheduleEveryInterval
+}
+
+// loadEvents returns the enabled events of the database |dbName|.
+func (es *eventScheduler) loadEvents(ctx context.Context, dbName string) ([]scheduledEvent, error) {
+	sqlCtx, err := es.se.NewLocalContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db, err := es.se.GetUnderlyingEngine().Analyzer.Catalog.Database(sqlCtx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	eventDb, ok := db.(sql.EventDatabase)
+	if !ok {
+		return nil, nil
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	defs, err := eventDb.GetEvents(sqlCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []scheduledEvent
+	for _, def := range defs {
+		node, err := parse.Parse(sqlCtx, def.CreateStatement)
+		if err != nil {
+			return nil, err
+		}
+		createEvent, ok := node.(*plan.CreateEvent)
+		if !ok {
+			return nil, sql.ErrEventCreateStatementInvalid.New(def.CreateStatement)
+		}
+		if createEvent.Status != plan.EventStatus_Enable {
+			continue
+		}
+		details, err := createEvent.GetEventDetails(sqlCtx, def.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		ev := scheduledEvent{Details: details}
+		if createEvent.Every != nil {
+			delta, err := createEvent.Every.EvalDelta(sqlCtx, nil)
+			if err != nil {
+				return nil, err
+			}
+			ev.Every = plan.NewEveryInterval(delta.Years, delta.Months, delta.Days, delta.Hours, delta.Minutes, delta.Seconds)
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// runEvent runs the body of |ev| in the database |dbName| and commits its changes. One-time events are dropped after
+// they run, or disabled if they were created with ON COMPLETION PRESERVE.
+func (es *eventScheduler) runEvent(ctx context.Context, dbName string, ev scheduledEvent) error {
+	sqlCtx, err := es.se.NewLocalContext(ctx)
+	if err != nil {
+		return err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	queries := []string{ev.Details.Definition}
+	if ev.Every == nil {
+		if ev.Details.OnCompletionPreserve {
+			queries = append(queries, fmt.Sprintf("ALTER EVENT `%s` DISABLE", ev.Details.Name))
+		} else {
+			queries = append(queries, fmt.Sprintf("DROP EVENT `%s`", ev.Details.Name))
+		}
+	}
+	msg := strings.ReplaceAll(fmt.Sprintf("Run event %s", ev.Details.Name), "'", "''")
+	queries = append(queries, fmt.Sprintf("CALL DOLT_COMMIT('-A', '--skip-empty', '-m', '%s')", msg))
+
+	start := time.Now()
+	for _, q := range queries {
+		sch, iter, err := es.se.Query(sqlCtx, q)
+		if err != nil {
+			return err
+		}
+		if _, err = sql.RowIterToRows(sqlCtx, sch, iter); err != nil {
+			return err
+		}
+	}
+	es.lgr.Debugf("ran event %s in database %s in %v", ev.Details.Name, dbName, time.Since(start))
+	return nil
+}
+
+// nextEventExecution returns the first time |ev| is scheduled to run after |after|, or at |after| if |inclusive| is
+// true. It returns the zero time and false if |ev| will never run again.
+func nextEventExecution(ev scheduledEvent, after time.Time, inclusive bool) (time.Time, bool) {
+	isNext := func(t time.Time) bool {
+		return t.After(after) || (inclusive && t.Equal(after))
+	}
+
+	d := ev.Details
+	if ev.Every == nil {
+		if !isNext(d.ExecuteAt) {
+			return time.Time{}, false
+		}
+		return d.ExecuteAt, true
+	}
+
+	i := ev.Every
+	if i.Years == 0 && i.Months == 0 && i.Days == 0 && i.Hours == 0 && i.Minutes == 0 && i.Seconds == 0 {
+		return time.Time{}, false
+	}
+	step := func(t time.Time, n int) time.Time {
+		t = t.AddDate(int(i.Years)*n, int(i.Months)*n, int(i.Days)*n)
+		return t.Add(time.Duration(n) * (time.Duration(i.Hours)*time.Hour + time.Duration(i.Minutes)*time.Minute + time.Duration(i.Seconds)*time.Second))
+	}
+
+	// Executions are always computed from STARTS, since adding months to a date that doesn't exist in the next month
+	// drifts. Estimate how many intervals have passed using the length of the first one, then correct the estimate.
+	k := 0
+	if length := step(d.Starts, 1).Sub(d.Starts); length > 0 && after.After(d.Starts) {
+		k = int(after.Sub(d.Starts) / length)
+	}
+	for k > 0 && isNext(step(d.Starts, k-1)) {
+		k--
+	}
+	next := step(d.Starts, k)
+	for !isNext(next) {
+		k++
+		next = step(d.Starts, k)
+	}
+	if d.HasEnds && next.After(d.Ends) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// eventKey identifies an event definition in a database. Altering an event changes its key, so the event is
+// rescheduled.
+func eventKey(dbName string, ev scheduledEvent) string {
+	return dbName + "\x00" + ev.Details.CreateEventStatement()
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextEventExecution(t *testing.T) {
+	starts := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	at := func(ed sql.EventDetails) scheduledEvent {
+		return scheduledEvent{Details: ed}
+	}
+	every := func(ed sql.EventDetails, i *plan.EventOnScheduleEveryInterval) scheduledEvent {
+		return scheduledEvent{Details: ed, Every: i}
+	}
+
+	tests := []struct {
+		name      string
+		ev        scheduledEvent
+		after     time.Time
+		inclusive bool
+		exp       time.Time
+		ok        bool
+	}{
+		{
+			name:  "one-time event in the future",
+			ev:    at(sql.EventDetails{HasExecuteAt: true, ExecuteAt: starts}),
+			after: starts.Add(-time.Second),
+			exp:   starts,
+			ok:    true,
+		},
+		{
+			name:      "one-time event now, inclusive",
+			ev:        at(sql.EventDetails{HasExecuteAt: true, ExecuteAt: starts}),
+			after:     starts,
+			inclusive: true,
+			exp:       starts,
+			ok:        true,
+		},
+		{
+			name:  "one-time event already run",
+			ev:    at(sql.EventDetails{HasExecuteAt: true, ExecuteAt: starts}),
+			after: starts,
+		},
+		{
+			name:  "recurring event before it starts",
+			ev:    every(sql.EventDetails{Starts: starts}, plan.NewEveryInterval(0, 0, 0, 1, 0, 0)),
+			after: starts.Add(-24 * time.Hour),
+			exp:   starts,
+			ok:    true,
+		},
+		{
+			name:  "recurring event after it starts",
+			ev:    every(sql.EventDetails{Starts: starts}, plan.NewEveryInterval(0, 0, 0, 1, 0, 0)),
+			after: starts.Add(90 * time.Minute),
+			exp:   starts.Add(2 * time.Hour),
+			ok:    true,
+		},
+		{
+			name:  "recurring event on a scheduled time",
+			ev:    every(sql.EventDetails{Starts: starts}, plan.NewEveryInterval(0, 0, 0, 0, 0, 30)),
+			after: starts.Add(time.Minute),
+			exp:   starts.Add(90 * time.Second),
+			ok:    true,
+		},
+		{
+			name:      "recurring event on a scheduled time, inclusive",
+			ev:        every(sql.EventDetails{Starts: starts}, plan.NewEveryInterval(0, 0, 0, 0, 0, 30)),
+			after:     starts.Add(time.Minute),
+			inclusive: true,
+			exp:       starts.Add(time.Minute),
+			ok:        true,
+		},
+		{
+			name:  "monthly event",
+			ev:    every(sql.EventDetails{Starts: starts}, plan.NewEveryInterval(0, 1, 0, 0, 0, 0)),
+			after: starts.AddDate(0, 0, 100),
+			exp:   starts.AddDate(0, 4, 0),
+			ok:    true,
+		},
+		{
+			name:  "recurring event past its end",
+			ev:    every(sql.EventDetails{Starts: starts, HasEnds: true, Ends: starts.Add(time.Hour)}, plan.NewEveryInterval(0, 0, 0, 1, 0, 0)),
+			after: starts.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := nextEventExecution(tt.ev, tt.after, tt.inclusive)
+			assert.Equal(t, tt.ok, ok)
+			assert.True(t, tt.exp.Equal(next), "expected %v, got %v", tt.exp, next)
+		})
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/flight"
+	"github.com/apache/arrow/go/v12/arrow/flight/flightsql"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+)
+
+const (
+	// flightSQLBatchRows is the number of rows in each record batch streamed to a client.
+	flightSQLBatchRows = 8192
+	// flightSQLResultTimeout is how long the results of a query are kept for a client to fetch them.
+	flightSQLResultTimeout = 5 * time.Minute
+
+	// flightSQLDatabaseHeader is the call header that selects the database a query runs in.
+	flightSQLDatabaseHeader = "database"
+	// flightSQLRevisionHeader is the call header that pins a query to a branch, tag or commit of its database.
+	flightSQLRevisionHeader = "revision"
+)
+
+// flightSQLServer serves queries over Arrow Flight SQL, alongside the MySQL listener, streaming their results to
+// clients as Arrow record batches.
+//
+// Running a query takes two calls in Flight SQL: GetFlightInfo returns the result schema and a ticket for the
+// results, and DoGet fetches the results with the ticket. The query is run by the first call, and its results are
+// kept until the second call streams them, or until they time out.
+type flightSQLServer struct {
+	flightsql.BaseServer
+
+	se  *engine.SqlEngine
+	lgr *logrus.Entry
+
+	mu      sync.Mutex
+	results map[string]*flightSQLResult
+}
+
+// flightSQLResult is the results of a query waiting to be fetched.
+type flightSQLResult struct {
+	user    mysql_db.MysqlConnectionUser
+	sqlCtx  *sql.Context
+	schema  sql.Schema
+	iter    sql.RowIter
+	cancel  context.CancelFunc
+	expires time.Time
+}
+
+func (r *flightSQLResult) close() error {
+	defer r.cancel()
+	return r.iter.Close(r.sqlCtx)
+}
+
+// flightSQLListener is the gRPC server for a flightSQLServer.
+type flightSQLListener struct {
+	srv    flight.Server
+	flight *flightSQLServer
+}
+
+// newFlightSQLListener returns the Flight SQL server for |cfg| listening on |port|, using TLS if |tlsConfig| isn't nil.
+// Clients authenticate as users of the server's privilege database, and their queries run as those users.
+func newFlightSQLListener(cfg ServerConfig, tlsConfig *tls.Config, se *engine.SqlEngine, lgr *logrus.Entry, port int) (*flightSQLListener, error) {
+	fs := &flightSQLServer{
+		se:      se,
+		lgr:     lgr,
+		results: make(map[string]*flightSQLResult),
+	}
+	fs.Alloc = memory.DefaultAllocator
+	if err := fs.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerName, "dolt"); err != nil {
+		return nil, err
+	}
+	if err := fs.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerReadOnly, cfg.ReadOnly()); err != nil {
+		return nil, err
+	}
+
+	auth := newFlightSQLAuth(se.GetUnderlyingEngine().Analyzer.Catalog.MySQLDb, lgr)
+	var opts []grpc.ServerOption
+	opts = append(opts, auth.Options()...)
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	srv := flight.NewServerWithMiddleware(nil, opts...)
+	if err := srv.Init(fmt.Sprintf("%s:%d", cfg.Host(), port)); err != nil {
+		return nil, err
+	}
+	srv.RegisterFlightService(flightsql.NewFlightServer(fs))
+	return &flightSQLListener{srv: srv, flight: fs}, nil
+}
+
+// Serve serves Flight SQL requests until Close is called.
+func (l *flightSQLListener) Serve() error {
+	return l.srv.Serve()
+}
+
+// Close stops the server, and closes the results no client fetched.
+func (l *flightSQLListener) Close() {
+	l.srv.Shutdown()
+	l.flight.mu.Lock()
+	defer l.flight.mu.Unlock()
+	for handle, r := range l.flight.results {
+		_ = r.close()
+		delete(l.flight.results, handle)
+	}
+}
+
+// GetFlightInfoStatement runs the query in |cmd|, and returns the schema of its results and the ticket to fetch them.
+func (s *flightSQLServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	r, err := s.query(ctx, cmd.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	arrowSch, err := arrowSchema(r.schema)
+	if err != nil {
+		_ = r.close()
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+
+	handle, err := s.addResult(r)
+	if err != nil {
+		_ = r.close()
+		return nil, err
+	}
+	ticket, err := flightsql.CreateStatementQueryTicket([]byte(handle))
+	if err != nil {
+		return nil, err
+	}
+
+	return &flight.FlightInfo{
+		Schema:           flight.SerializeSchema(arrowSch, s.Alloc),
+		FlightDescriptor: desc,
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+// DoGetStatement streams the results of the query with the handle in |ticket|.
+func (s *flightSQLServer) DoGetStatement(ctx context.Context, ticket flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	user, _ := flightSQLUser(ctx)
+	r := s.takeResult(string(ticket.GetStatementHandle()), user)
+	if r == nil {
+		return nil, nil, status.Error(codes.NotFound, "no results for this ticket; they may have timed out")
+	}
+	arrowSch, err := arrowSchema(r.schema)
+	if err != nil {
+		_ = r.close()
+		return nil, nil, err
+	}
+
+	ch := make(chan flight.StreamChunk)
+	go func() {
+		defer close(ch)
+		err := streamRecords(ctx, r.sqlCtx, s.Alloc, arrowSch, r.schema, r.iter, func(rec arrow.Record) bool {
+			select {
+			case ch <- flight.StreamChunk{Data: rec}:
+				return true
+			case <-ctx.Done():
+				rec.Release()
+				return false
+			}
+		})
+		if cerr := r.close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			s.lgr.WithError(err).Warn("error streaming Flight SQL results")
+			select {
+			case ch <- flight.StreamChunk{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return arrowSch, ch, nil
+}
+
+// DoPutCommandStatementUpdate runs the statement in |cmd|, and returns the number of rows it affected.
+func (s *flightSQLServer) DoPutCommandStatementUpdate(ctx context.Context, cmd flightsql.StatementUpdate) (int64, error) {
+	r, err := s.query(ctx, cmd.GetQuery())
+	if err != nil {
+		return 0, err
+	}
+	defer r.close()
+
+	var affected int64
+	for {
+		row, err := r.iter.Next(r.sqlCtx)
+		if err == io.EOF {
+			return affected, nil
+		} else if err != nil {
+			return 0, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if len(row) == 1 {
+			if ok, isOk := row[0].(types.OkResult); isOk {
+				affected += int64(ok.RowsAffected)
+			}
+		}
+	}
+}
+
+// query runs |query| in a new session of the user the call is authenticated as, so the user's grants and branch
+// permissions apply to it. The database it runs in is set by the database and revision call headers. The session's
+// context lives until the results are closed, rather than until the call that ran the query returns.
+func (s *flightSQLServer) query(ctx context.Context, query string) (*flightSQLResult, error) {
+	s.expireResults(time.Now())
+
+	user, ok := flightSQLUser(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+
+	queryCtx, cancel := context.WithCancel(context.Background())
+	sqlCtx, err := s.se.NewDefaultContext(queryCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	sqlCtx.Session.SetClient(sql.Client{User: user.User, Address: user.Host, Capabilities: 0})
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if dbs := md.Get(flightSQLDatabaseHeader); len(dbs) > 0 && dbs[0] != "" {
+			dbName := dbs[0]
+			if revs := md.Get(flightSQLRevisionHeader); len(revs) > 0 && revs[0] != "" {
+				dbName = dbName + "/" + revs[0]
+			}
+			sqlCtx.SetCurrentDatabase(dbName)
+		}
+	}
+
+	sch, iter, err := s.se.Query(sqlCtx, query)
+	if err != nil {
+		cancel()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &flightSQLResult{user: user, sqlCtx: sqlCtx, schema: sch, iter: iter, cancel: cancel}, nil
+}
+
+func (s *flightSQLServer) addResult(r *flightSQLResult) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	handle := hex.EncodeToString(b[:])
+	r.expires = time.Now().Add(flightSQLResultTimeout)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[handle] = r
+	return handle, nil
+}
+
+// takeResult removes and returns the results with |handle|, if they're the results of a query run by |user|.
+func (s *flightSQLServer) takeResult(handle string, user mysql_db.MysqlConnectionUser) *flightSQLResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.results[handle]
+	if r == nil || r.user != user {
+		return nil
+	}
+	delete(s.results, handle)
+	return r
+}
+
+// expireResults closes the results that weren't fetched in time.
+func (s *flightSQLServer) expireResults(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for handle, r := range s.results {
+		if now.After(r.expires) {
+			_ = r.close()
+			delete(s.results, handle)
+		}
+	}
+}
+
+var errFlightSQLStreamStopped = errors.New("client stopped reading results")
+
+// streamRecords reads the rows of |iter| into record batches of up to flightSQLBatchRows rows, and passes each to
+// |send|, which takes ownership of it. It stops early if |send| returns false.
+func streamRecords(ctx context.Context, sqlCtx *sql.Context, mem memory.Allocator, arrowSch *arrow.Schema, sch sql.Schema, iter sql.RowIter, send func(arrow.Record) bool) error {
+	bldr := newRecordBuilder(mem, arrowSch, sch)
+	defer bldr.Release()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		row, err := iter.Next(sqlCtx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := bldr.Append(sqlCtx, row); err != nil {
+			return err
+		}
+		if bldr.Len() >= flightSQLBatchRows {
+			if !send(bldr.NewRecord()) {
+				return errFlightSQLStreamStopped
+			}
+		}
+	}
+
+	if bldr.Len() > 0 {
+		if !send(bldr.NewRecord()) {
+			return errFlightSQLStreamStopped
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/decimal128"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/shopspring/decimal"
+)
+
+// maxArrowDecimalPrecision is the largest precision of an Arrow decimal128. Wider decimals are sent as strings.
+const maxArrowDecimalPrecision = 38
+
+// arrowType returns the Arrow type that values of |t| are sent as. Integers, floats, decimals, dates, datetimes and
+// times are sent as the matching Arrow types, binary strings and geometries as binary, and everything else, like
+// strings, enums, sets and JSON, as strings.
+func arrowType(t sql.Type) arrow.DataType {
+	switch t.Type() {
+	case sqltypes.Int8:
+		return arrow.PrimitiveTypes.Int8
+	case sqltypes.Uint8:
+		return arrow.PrimitiveTypes.Uint8
+	case sqltypes.Int16, sqltypes.Year:
+		return arrow.PrimitiveTypes.Int16
+	case sqltypes.Uint16:
+		return arrow.PrimitiveTypes.Uint16
+	case sqltypes.Int24, sqltypes.Int32:
+		return arrow.PrimitiveTypes.Int32
+	case sqltypes.Uint24, sqltypes.Uint32:
+		return arrow.PrimitiveTypes.Uint32
+	case sqltypes.Int64:
+		return arrow.PrimitiveTypes.Int64
+	case sqltypes.Uint64, sqltypes.Bit:
+		return arrow.PrimitiveTypes.Uint64
+	case sqltypes.Float32:
+		return arrow.PrimitiveTypes.Float32
+	case sqltypes.Float64:
+		return arrow.PrimitiveTypes.Float64
+	case sqltypes.Decimal:
+		if dt, ok := t.(sql.DecimalType); ok && dt.Precision() <= maxArrowDecimalPrecision {
+			return &arrow.Decimal128Type{Precision: int32(dt.Precision()), Scale: int32(dt.Scale())}
+		}
+		return arrow.BinaryTypes.String
+	case sqltypes.Date:
+		return arrow.FixedWidthTypes.Date32
+	case sqltypes.Datetime, sqltypes.Timestamp:
+		return arrow.FixedWidthTypes.Timestamp_us
+	case sqltypes.Time:
+		// TIME values range from -838:59:59 to 838:59:59, so they're durations rather than times of day
+		return arrow.FixedWidthTypes.Duration_us
+	case sqltypes.Binary, sqltypes.VarBinary, sqltypes.Blob, sqltypes.Geometry:
+		return arrow.BinaryTypes.Binary
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// arrowSchema returns the Arrow schema of rows with the schema |sch|.
+func arrowSchema(sch sql.Schema) (*arrow.Schema, error) {
+	fields := make([]arrow.Field, len(sch))
+	for i, col := range sch {
+		if col.Type == nil {
+			return nil, fmt.Errorf("column %s has no type", col.Name)
+		}
+		fields[i] = arrow.Field{Name: col.Name, Type: arrowType(col.Type), Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// recordBuilder builds Arrow records from sql rows.
+type recordBuilder struct {
+	bldr *array.RecordBuilder
+	sch  sql.Schema
+	n    int
+}
+
+func newRecordBuilder(mem memory.Allocator, arrowSch *arrow.Schema, sch sql.Schema) *recordBuilder {
+	return &recordBuilder{bldr: array.NewRecordBuilder(mem, arrowSch), sch: sch}
+}
+
+// Len returns the number of rows appended since the last record was built.
+func (b *recordBuilder) Len() int {
+	return b.n
+}
+
+// NewRecord returns a record of the rows appended since the last record was built.
+func (b *recordBuilder) NewRecord() arrow.Record {
+	b.n = 0
+	return b.bldr.NewRecord()
+}
+
+func (b *recordBuilder) Release() {
+	b.bldr.Release()
+}
+
+// Append appends |row| to the record being built.
+func (b *recordBuilder) Append(ctx *sql.Context, row sql.Row) error {
+	for i, v := range row {
+		if err := appendValue(ctx, b.bldr.Field(i), b.sch[i].Type, v); err != nil {
+			return fmt.Errorf("column %s: %w", b.sch[i].Name, err)
+		}
+	}
+	b.n++
+	return nil
+}
+
+func appendValue(ctx *sql.Context, fb array.Builder, t sql.Type, v interface{}) error {
+	if v == nil {
+		fb.AppendNull()
+		return nil
+	}
+	// Expressions don't always return values of the Go type of their sql type, so they're converted first.
+	if _, isTimespan := v.(types.Timespan); !isTimespan {
+		if cv, _, err := t.Convert(v); err == nil && cv != nil {
+			v = cv
+		}
+	}
+
+	switch fb := fb.(type) {
+	case *array.Int8Builder:
+		n, err := toInt64(v)
+		fb.Append(int8(n))
+		return err
+	case *array.Int16Builder:
+		n, err := toInt64(v)
+		fb.Append(int16(n))
+		return err
+	case *array.Int32Builder:
+		n, err := toInt64(v)
+		fb.Append(int32(n))
+		return err
+	case *array.Int64Builder:
+		n, err := toInt64(v)
+		fb.Append(n)
+		return err
+	case *array.Uint8Builder:
+		n, err := toUint64(v)
+		fb.Append(uint8(n))
+		return err
+	case *array.Uint16Builder:
+		n, err := toUint64(v)
+		fb.Append(uint16(n))
+		return err
+	case *array.Uint32Builder:
+		n, err := toUint64(v)
+		fb.Append(uint32(n))
+		return err
+	case *array.Uint64Builder:
+		n, err := toUint64(v)
+		fb.Append(n)
+		return err
+	case *array.Float32Builder:
+		f, err := toFloat64(v)
+		fb.Append(float32(f))
+		return err
+	case *array.Float64Builder:
+		f, err := toFloat64(v)
+		fb.Append(f)
+		return err
+	case *array.Decimal128Builder:
+		d, ok := v.(decimal.Decimal)
+		if !ok {
+			return fmt.Errorf("unexpected decimal value %v", v)
+		}
+		scale := fb.Type().(*arrow.Decimal128Type).Scale
+		fb.Append(decimal128.FromBigInt(d.Shift(scale).BigInt()))
+		return nil
+	case *array.Date32Builder:
+		tm, ok := v.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected date value %v", v)
+		}
+		fb.Append(arrow.Date32FromTime(tm))
+		return nil
+	case *array.TimestampBuilder:
+		tm, ok := v.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected datetime value %v", v)
+		}
+		fb.Append(arrow.Timestamp(tm.UnixMicro()))
+		return nil
+	case *array.DurationBuilder:
+		ts, ok := v.(types.Timespan)
+		if !ok {
+			return fmt.Errorf("unexpected time value %v", v)
+		}
+		fb.Append(arrow.Duration(ts.AsMicroseconds()))
+		return nil
+	case *array.BinaryBuilder:
+		sv, err := t.SQL(ctx, nil, v)
+		if err != nil {
+			return err
+		}
+		fb.Append(sv.Raw())
+		return nil
+	case *array.StringBuilder:
+		sv, err := t.SQL(ctx, nil, v)
+		if err != nil {
+			return err
+		}
+		fb.Append(sv.ToString())
+		return nil
+	default:
+		return fmt.Errorf("unsupported arrow builder %T", fb)
+	}
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unexpected integer value %v", v)
+	}
+}
+
+func toUint64(v interface{}) (uint64, error) {
+	if u, ok := v.(uint64); ok {
+		return u, nil
+	}
+	n, err := toInt64(v)
+	return uint64(n), err
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case decimal.Decimal:
+		f, _ := v.Float64()
+		return f, nil
+	default:
+		n, err := toInt64(v)
+		return float64(n), err
+	}
+}
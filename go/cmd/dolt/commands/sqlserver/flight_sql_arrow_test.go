@@ -0,0 +1,89 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v12/arrow"
+	"github.com/apache/arrow/go/v12/arrow/array"
+	"github.com/apache/arrow/go/v12/arrow/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRecords(t *testing.T) {
+	sch := sql.Schema{
+		{Name: "id", Type: types.Int32},
+		{Name: "name", Type: types.Text},
+		{Name: "price", Type: types.MustCreateDecimalType(10, 2)},
+		{Name: "created", Type: types.Datetime},
+		{Name: "day", Type: types.Date},
+		{Name: "data", Type: types.Blob},
+		{Name: "total", Type: types.Uint64},
+	}
+	arrowSch, err := arrowSchema(sch)
+	require.NoError(t, err)
+	assert.Equal(t, arrow.PrimitiveTypes.Int32, arrowSch.Field(0).Type)
+	assert.Equal(t, arrow.BinaryTypes.String, arrowSch.Field(1).Type)
+	assert.Equal(t, &arrow.Decimal128Type{Precision: 10, Scale: 2}, arrowSch.Field(2).Type)
+	assert.Equal(t, arrow.FixedWidthTypes.Timestamp_us, arrowSch.Field(3).Type)
+	assert.Equal(t, arrow.FixedWidthTypes.Date32, arrowSch.Field(4).Type)
+	assert.Equal(t, arrow.BinaryTypes.Binary, arrowSch.Field(5).Type)
+	assert.Equal(t, arrow.PrimitiveTypes.Uint64, arrowSch.Field(6).Type)
+
+	created := time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)
+	rows := make([]sql.Row, flightSQLBatchRows+1)
+	for i := range rows {
+		rows[i] = sql.Row{int32(i), "name", decimal.RequireFromString("12.34"), created, created, []byte{1, 2}, int64(i)}
+	}
+	rows[0] = sql.Row{int32(0), nil, nil, nil, nil, nil, nil}
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	ctx := sql.NewEmptyContext()
+	var records []arrow.Record
+	err = streamRecords(context.Background(), ctx, mem, arrowSch, sch, sql.RowsToRowIter(rows...), func(rec arrow.Record) bool {
+		records = append(records, rec)
+		return true
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	defer func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}()
+
+	assert.Equal(t, int64(flightSQLBatchRows), records[0].NumRows())
+	assert.Equal(t, int64(1), records[1].NumRows())
+
+	first := records[0]
+	assert.True(t, first.Column(1).IsNull(0))
+	assert.True(t, first.Column(2).IsNull(0))
+	assert.Equal(t, int32(1), first.Column(0).(*array.Int32).Value(1))
+	assert.Equal(t, "name", first.Column(1).(*array.String).Value(1))
+	assert.Equal(t, "12.34", first.Column(2).(*array.Decimal128).Value(1).ToString(2))
+	assert.Equal(t, arrow.Timestamp(created.UnixMicro()), first.Column(3).(*array.Timestamp).Value(1))
+	assert.Equal(t, arrow.Date32FromTime(created), first.Column(4).(*array.Date32).Value(1))
+	assert.Equal(t, []byte{1, 2}, first.Column(5).(*array.Binary).Value(1))
+	assert.Equal(t, uint64(1), first.Column(6).(*array.Uint64).Value(1))
+}
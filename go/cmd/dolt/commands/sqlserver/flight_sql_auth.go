@@ -0,0 +1,215 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// flightSQLTokenTimeout is how long the bearer token returned by a Handshake call can be used.
+	flightSQLTokenTimeout = time.Hour
+
+	flightSQLAuthHeader   = "authorization"
+	flightSQLBasicPrefix  = "Basic "
+	flightSQLBearerPrefix = "Bearer "
+)
+
+// flightSQLUserKey is the context key of the user a Flight SQL call is authenticated as.
+type flightSQLUserKey struct{}
+
+// flightSQLUser returns the user the Flight SQL call with |ctx| is authenticated as.
+func flightSQLUser(ctx context.Context) (mysql_db.MysqlConnectionUser, bool) {
+	u, ok := ctx.Value(flightSQLUserKey{}).(mysql_db.MysqlConnectionUser)
+	return u, ok
+}
+
+// flightSQLAuth authenticates Flight SQL calls as the users of the server's privilege database, the same way the MySQL
+// listener authenticates connections. Clients send their user and password in a basic authorization header, either
+// with each call or once with a Handshake call, which returns a bearer token to send with later calls instead.
+type flightSQLAuth struct {
+	mysqlDb *mysql_db.MySQLDb
+	lgr     *logrus.Entry
+
+	mu     sync.Mutex
+	tokens map[string]flightSQLToken
+}
+
+// flightSQLToken is a bearer token returned by a Handshake call.
+type flightSQLToken struct {
+	user    mysql_db.MysqlConnectionUser
+	expires time.Time
+}
+
+func newFlightSQLAuth(mysqlDb *mysql_db.MySQLDb, lgr *logrus.Entry) *flightSQLAuth {
+	return &flightSQLAuth{
+		mysqlDb: mysqlDb,
+		lgr:     lgr,
+		tokens:  make(map[string]flightSQLToken),
+	}
+}
+
+func (a *flightSQLAuth) Options() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(a.Unary()),
+		grpc.ChainStreamInterceptor(a.Stream()),
+	}
+}
+
+func (a *flightSQLAuth) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		u, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, flightSQLUserKey{}, u), req)
+	}
+}
+
+func (a *flightSQLAuth) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		u, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(info.FullMethod, "/Handshake") {
+			token, err := a.newToken(u)
+			if err != nil {
+				return err
+			}
+			ss.SetTrailer(metadata.Pairs(flightSQLAuthHeader, flightSQLBearerPrefix+token))
+		}
+		return handler(srv, &flightSQLAuthStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), flightSQLUserKey{}, u)})
+	}
+}
+
+// flightSQLAuthStream is a server stream whose context has the user it's authenticated as.
+type flightSQLAuthStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *flightSQLAuthStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate returns the user that the authorization header of the call with |ctx| authenticates.
+func (a *flightSQLAuth) authenticate(ctx context.Context) (mysql_db.MysqlConnectionUser, error) {
+	unauthenticated := status.Error(codes.Unauthenticated, "unauthenticated")
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	auths := md.Get(flightSQLAuthHeader)
+	if len(auths) != 1 {
+		a.lgr.Info("incoming Flight SQL request had no authorization")
+		return mysql_db.MysqlConnectionUser{}, unauthenticated
+	}
+	auth := auths[0]
+
+	if strings.HasPrefix(auth, flightSQLBearerPrefix) {
+		u, ok := a.checkToken(strings.TrimPrefix(auth, flightSQLBearerPrefix), time.Now())
+		if !ok {
+			a.lgr.Info("incoming Flight SQL request had an unknown or expired bearer token")
+			return mysql_db.MysqlConnectionUser{}, unauthenticated
+		}
+		// the user may have been dropped or locked since the token was issued
+		if entry := a.mysqlDb.GetUser(u.User, u.Host, false); a.mysqlDb.Enabled && (entry == nil || entry.Locked) {
+			a.lgr.Infof("incoming Flight SQL request had a bearer token of user '%s', which can no longer log in", u.User)
+			return mysql_db.MysqlConnectionUser{}, unauthenticated
+		}
+		return u, nil
+	} else if !strings.HasPrefix(auth, flightSQLBasicPrefix) {
+		a.lgr.Info("incoming Flight SQL request had malformed authorization header")
+		return mysql_db.MysqlConnectionUser{}, unauthenticated
+	}
+
+	// clients differ in whether they pad the encoding
+	encoded := strings.TrimRight(strings.TrimPrefix(auth, flightSQLBasicPrefix), "=")
+	decoded, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		a.lgr.Infof("incoming Flight SQL request authorization header failed to decode: %v", err)
+		return mysql_db.MysqlConnectionUser{}, unauthenticated
+	}
+	user, password, _ := strings.Cut(string(decoded), ":")
+
+	var addr net.Addr
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr
+	}
+	u, err := a.validate(user, password, addr)
+	if err != nil {
+		a.lgr.Infof("incoming Flight SQL request failed to authenticate: %v", err)
+		return mysql_db.MysqlConnectionUser{}, unauthenticated
+	}
+	return u, nil
+}
+
+// validate returns the user of the privilege database that |user| and |password| authenticate as when connecting from
+// |addr|. It checks the password with the mysql_native_password method, like the MySQL listener does.
+func (a *flightSQLAuth) validate(user, password string, addr net.Addr) (mysql_db.MysqlConnectionUser, error) {
+	if addr == nil {
+		addr = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	}
+	salt, err := mysql.NewSalt()
+	if err != nil {
+		return mysql_db.MysqlConnectionUser{}, err
+	}
+	getter, err := a.mysqlDb.ValidateHash(salt, user, mysql.ScrambleMysqlNativePassword(salt, []byte(password)), addr)
+	if err != nil {
+		return mysql_db.MysqlConnectionUser{}, err
+	}
+	return getter.(mysql_db.MysqlConnectionUser), nil
+}
+
+func (a *flightSQLAuth) newToken(u mysql_db.MysqlConnectionUser) (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b[:])
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tokens[token] = flightSQLToken{user: u, expires: time.Now().Add(flightSQLTokenTimeout)}
+	return token, nil
+}
+
+// checkToken returns the user |token| was issued to, if it hasn't expired at |now|. Expired tokens are removed.
+func (a *flightSQLAuth) checkToken(token string, now time.Time) (mysql_db.MysqlConnectionUser, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for t, tok := range a.tokens {
+		if now.After(tok.expires) {
+			delete(a.tokens, t)
+		}
+	}
+	tok, ok := a.tokens[token]
+	return tok.user, ok
+}
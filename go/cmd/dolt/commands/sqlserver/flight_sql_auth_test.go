@@ -0,0 +1,106 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/arrow/flight/flightsql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+func TestFlightSQLAuthentication(t *testing.T) {
+	env, err := sqle.CreateEnvWithSeedData()
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, env.DoltDB.Close())
+	}()
+
+	flightPort := 15331
+	cfgDir := t.TempDir()
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).WithPort(15330).WithFlightSQLPort(&flightPort).
+		withCfgDir(cfgDir).
+		withPrivilegeFilePath(filepath.Join(cfgDir, "privileges.db")).
+		withBranchControlFilePath(filepath.Join(cfgDir, "branch_control.db"))
+
+	sc := NewServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "0.0.0", serverConfig, sc, env)
+	}()
+	require.NoError(t, sc.WaitForStart())
+
+	conn, err := dbr.Open("mysql", ConnectionString(serverConfig, "dolt"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, query := range []string{
+		"CREATE USER reader@'%' IDENTIFIED BY 'pass'",
+		"GRANT SELECT ON dolt.* TO reader@'%'",
+	} {
+		_, err = conn.Exec(query)
+		require.NoError(t, err)
+	}
+
+	cl, err := flightsql.NewClient(fmt.Sprintf("localhost:%d", flightPort), nil, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer cl.Close()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), flightSQLDatabaseHeader, "dolt")
+
+	// calls without credentials, or with the wrong password, are rejected
+	_, err = cl.Execute(ctx, "SELECT * FROM people")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = cl.Client.AuthenticateBasicToken(ctx, "reader", "wrong")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = cl.Client.AuthenticateBasicToken(ctx, "nobody", "")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the handshake returns a bearer token that later calls authenticate with
+	authCtx, err := cl.Client.AuthenticateBasicToken(ctx, "reader", "pass")
+	require.NoError(t, err)
+	info, err := cl.Execute(authCtx, "SELECT name FROM people ORDER BY name")
+	require.NoError(t, err)
+
+	// the results can only be fetched by the user that ran the query
+	rootCtx, err := cl.Client.AuthenticateBasicToken(ctx, "root", "")
+	require.NoError(t, err)
+	_, err = cl.DoGet(rootCtx, info.Endpoint[0].Ticket)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	rdr, err := cl.DoGet(authCtx, info.Endpoint[0].Ticket)
+	require.NoError(t, err)
+	defer rdr.Release()
+	var rows int64
+	for rdr.Next() {
+		rows += rdr.Record().NumRows()
+	}
+	require.NoError(t, rdr.Err())
+	assert.EqualValues(t, 3, rows)
+
+	// queries run with the privileges of the user
+	_, err = cl.ExecuteUpdate(authCtx, "DELETE FROM people")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "command denied to user 'reader'")
+}
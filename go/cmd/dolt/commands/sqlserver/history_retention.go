@@ -0,0 +1,162 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+)
+
+const defaultRetentionInterval = 24 * time.Hour
+
+// RetentionConfig is the configuration for squashing old history and garbage collecting it on a schedule.
+type RetentionConfig interface {
+	// KeepDays is the number of days of history that are kept in full.
+	KeepDays() int
+	// SnapshotDays is the number of days of older history squashed into each snapshot. 0 uses the default of 7.
+	SnapshotDays() int
+	// Branches are the branches whose history is squashed. Every branch is squashed if it's empty.
+	Branches() []string
+	// IntervalSecs is how often, in seconds, history is squashed. 0 uses the default of a day.
+	IntervalSecs() int
+}
+
+// historyRetention applies a retention policy to the databases served by a sql-server on a schedule. Each time, the
+// history of every database is squashed with dolt_retention(), and the databases whose history changed are garbage
+// collected with dolt_gc() to remove the chunks that are no longer referenced.
+//
+// Like a dolt_gc() run by a client, garbage collection ends every other connection to the server, so the interval
+// should be chosen so that it runs when the server is idle.
+type historyRetention struct {
+	se       *engine.SqlEngine
+	cfg      RetentionConfig
+	interval time.Duration
+	lgr      *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newHistoryRetention returns a historyRetention for the databases of |se| configured by |cfg|, or nil if no
+// retention policy is configured.
+func newHistoryRetention(cfg ServerConfig, se *engine.SqlEngine, lgr *logrus.Logger) *historyRetention {
+	if cfg.RetentionConfig() == nil || cfg.ReadOnly() {
+		return nil
+	}
+	interval := time.Duration(cfg.RetentionConfig().IntervalSecs()) * time.Second
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	return &historyRetention{
+		se:       se,
+		cfg:      cfg.RetentionConfig(),
+		interval: interval,
+		lgr:      lgr,
+	}
+}
+
+// Start starts applying the retention policy until Stop is called.
+func (hr *historyRetention) Start(ctx context.Context) {
+	ctx, hr.cancel = context.WithCancel(ctx)
+	hr.wg.Add(1)
+	go func() {
+		defer hr.wg.Done()
+		ticker := time.NewTicker(hr.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				hr.applyAll(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops applying the retention policy, waiting for a running squash or garbage collection to finish or abort.
+func (hr *historyRetention) Stop() {
+	hr.cancel()
+	hr.wg.Wait()
+}
+
+func (hr *historyRetention) applyAll(ctx context.Context) {
+	sqlCtx, err := hr.se.NewLocalContext(ctx)
+	if err != nil {
+		hr.lgr.Warnf("error creating context for history retention: %v", err)
+		return
+	}
+
+	for _, db := range hr.se.Databases(sqlCtx) {
+		start := time.Now()
+		squashed, err := hr.apply(ctx, db.Name())
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			hr.lgr.Warnf("error applying history retention to database %s: %v", db.Name(), err)
+		} else if squashed {
+			hr.lgr.Infof("squashed and garbage collected the history of database %s in %v", db.Name(), time.Since(start))
+		}
+	}
+}
+
+// apply squashes the history of the database |dbName| and garbage collects it if it changed, returning whether it
+// did.
+func (hr *historyRetention) apply(ctx context.Context, dbName string) (bool, error) {
+	args := []string{"--keep-days", fmt.Sprint(hr.cfg.KeepDays())}
+	if hr.cfg.SnapshotDays() > 0 {
+		args = append(args, "--snapshot-days", fmt.Sprint(hr.cfg.SnapshotDays()))
+	}
+	args = append(args, hr.cfg.Branches()...)
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	}
+
+	rows, err := hr.query(ctx, dbName, fmt.Sprintf("CALL DOLT_RETENTION(%s)", strings.Join(quoted, ", ")))
+	if err != nil {
+		return false, err
+	}
+	if len(rows) != 1 || rows[0][0].(int64) == 0 {
+		return false, nil
+	}
+
+	if _, err = hr.query(ctx, dbName, "CALL DOLT_GC()"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (hr *historyRetention) query(ctx context.Context, dbName, query string) ([]sql.Row, error) {
+	sqlCtx, err := hr.se.NewLocalContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	sch, iter, err := hr.se.Query(sqlCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowIterToRows(sqlCtx, sch, iter)
+}
@@ -38,9 +38,11 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/archiving"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cdc"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
-	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 )
@@ -136,6 +138,32 @@ func Serve(
 		return err, nil
 	}
 
+	cdcPublisher, err := cdc.NewPublisher(serverConfig.CDCConfig(), lgr)
+	if err != nil {
+		return err, nil
+	}
+
+	archiver, err := archiving.NewArchiver(serverConfig.ArchivingConfig(), serverConfig.MetricsLabels(), lgr)
+	if err != nil {
+		return err, nil
+	}
+
+	for _, path := range serverConfig.Plugins() {
+		if err = dfunctions.LoadPlugin(path); err != nil {
+			return err, nil
+		}
+		lgr.Infof("Loaded functions from plugin %s", path)
+	}
+
+	var quotas []dsess.StorageQuota
+	for _, q := range serverConfig.StorageQuotas() {
+		quotas = append(quotas, dsess.StorageQuota{Database: q.Database, Branch: q.Branch, MaxBytes: q.MaxBytes})
+	}
+	storageQuotas, err := dsess.NewStorageQuotas(quotas)
+	if err != nil {
+		return err, nil
+	}
+
 	serverConf, sErr, cErr := getConfigFromServerConfig(serverConfig)
 	if cErr != nil {
 		return nil, cErr
@@ -154,9 +182,15 @@ func Serve(
 		ServerHost:              serverConfig.Host(),
 		Autocommit:              serverConfig.AutoCommit(),
 		DoltTransactionCommit:   serverConfig.DoltTransactionCommit(),
+		MergeDeleteSource:       serverConfig.MergeDeleteSource(),
 		JwksConfig:              serverConfig.JwksConfig(),
 		ClusterController:       clusterController,
 		BinlogReplicaController: binlogreplication.DoltBinlogReplicaController,
+		CDCPublisher:            cdcPublisher,
+		Archiver:                archiver,
+		ResultCache:             sqle.NewResultCache(serverConfig.ResultCacheEntries(), serverConfig.ResultCacheMaxRows()),
+		PlanCacheEntries:        serverConfig.PlanCacheEntries(),
+		StorageQuotas:           storageQuotas,
 	}
 	sqlEngine, err := engine.NewSqlEngine(
 		ctx,
@@ -239,11 +273,16 @@ func Serve(
 		port := *serverConfig.RemotesapiPort()
 		if remoteSrvSqlCtx, err := sqlEngine.NewDefaultContext(ctx); err == nil {
 			listenaddr := fmt.Sprintf(":%d", port)
+			readOnly := serverConfig.RemotesapiReadOnly() == nil || *serverConfig.RemotesapiReadOnly()
 			args := sqle.RemoteSrvServerArgs(remoteSrvSqlCtx, remotesrv.ServerArgs{
 				Logger:         logrus.NewEntry(lgr),
-				ReadOnly:       true,
+				ReadOnly:       readOnly,
 				HttpListenAddr: listenaddr,
 				GrpcListenAddr: listenaddr,
+				PushHooks: &remotesrv.PushHooks{
+					PreReceive: serverConfig.RemotesapiPreReceiveHook(),
+					Webhooks:   serverConfig.RemotesapiWebhooks(),
+				},
 			})
 			args = sqle.WithUserPasswordAuth(args, remotesrv.UserAuth{User: serverConfig.User(), Password: serverConfig.Password()})
 			args.TLSConfig = serverConf.TLSConfig
@@ -268,6 +307,22 @@ func Serve(
 		}
 	}
 
+	var flightSQLSrv *flightSQLListener
+	if serverConfig.FlightSQLPort() != nil {
+		port := *serverConfig.FlightSQLPort()
+		flightSQLSrv, err = newFlightSQLListener(serverConfig, serverConf.TLSConfig, sqlEngine, logrus.NewEntry(lgr), port)
+		if err != nil {
+			lgr.Errorf("error creating Flight SQL server on port %d: %v", port, err)
+			startError = err
+			return
+		}
+		go func() {
+			if err := flightSQLSrv.Serve(); err != nil {
+				lgr.Errorf("error serving Flight SQL on port %d: %v", port, err)
+			}
+		}()
+	}
+
 	var clusterRemoteSrv *remotesrv.Server
 	if clusterController != nil {
 		if remoteSrvSqlCtx, err := sqlEngine.NewDefaultContext(ctx); err == nil {
@@ -330,20 +385,55 @@ func Serve(
 		if remoteSrv != nil {
 			remoteSrv.GracefulStop()
 		}
+		if flightSQLSrv != nil {
+			flightSQLSrv.Close()
+		}
 		if clusterRemoteSrv != nil {
 			clusterRemoteSrv.GracefulStop()
 		}
 		if clusterController != nil {
 			clusterController.GracefulStop()
 		}
+		if err := cdcPublisher.Close(); err != nil {
+			lgr.Errorf("error closing connection to kafka: %v", err)
+		}
 
 		return mySQLServer.Close()
 	})
 
+	conjoiner := newBackgroundConjoiner(serverConfig, sqlEngine, lgr)
+	if conjoiner != nil {
+		conjoiner.Start(ctx)
+	}
+	scheduler := newEventScheduler(serverConfig, sqlEngine, lgr)
+	if scheduler != nil {
+		scheduler.Start(ctx)
+	}
+	retention := newHistoryRetention(serverConfig, sqlEngine, lgr)
+	if retention != nil {
+		retention.Start(ctx)
+	}
+	snapshots := newSnapshotTagger(serverConfig, sqlEngine, lgr)
+	if snapshots != nil {
+		snapshots.Start(ctx)
+	}
+
 	closeError = mySQLServer.Start()
 	if closeError != nil {
 		cli.PrintErr(closeError)
 	}
+	if snapshots != nil {
+		snapshots.Stop()
+	}
+	if retention != nil {
+		retention.Stop()
+	}
+	if scheduler != nil {
+		scheduler.Stop()
+	}
+	if conjoiner != nil {
+		conjoiner.Stop()
+	}
 	if err := mrEnv.Unlock(); err != nil {
 		cli.PrintErr(err)
 	}
@@ -21,9 +21,14 @@ import (
 	"net"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/archiving"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cdc"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/utils/cron"
 )
 
 // LogLevel defines the available levels of logging for the server.
@@ -152,6 +157,9 @@ type ServerConfig interface {
 	UserVars() []UserSessionVars
 	// JwksConfig is an array containing jwks config
 	JwksConfig() []engine.JwksConfig
+	// Plugins are the paths of the Go plugins that the server loads user-defined functions and table functions from.
+	// Loading plugins needs a dolt binary built with cgo, which the release binaries aren't. See dfunctions.LoadPlugin.
+	Plugins() []string
 	// AllowCleartextPasswords is true if the server should accept cleartext passwords.
 	AllowCleartextPasswords() bool
 	// Socket is a path to the unix socket file
@@ -161,8 +169,55 @@ type ServerConfig interface {
 	// as a dolt remote for things like `clone`, `fetch` and read
 	// replication.
 	RemotesapiPort() *int
+	// RemotesapiReadOnly is false if the remotesapi interface accepts pushes. It's read only unless this is set to
+	// false.
+	RemotesapiReadOnly() *bool
+	// RemotesapiPreReceiveHook is the script or http(s) endpoint run before each push to the remotesapi interface is
+	// applied, which rejects the push if it fails.
+	RemotesapiPreReceiveHook() string
+	// RemotesapiWebhooks are the http(s) endpoints that are sent each push to the remotesapi interface after it is
+	// applied.
+	RemotesapiWebhooks() []string
+	// FlightSQLPort is the port to serve Arrow Flight SQL on, alongside the MySQL listener, or nil to not serve it.
+	FlightSQLPort() *int
 	// ClusterConfig is the configuration for clustering in this sql-server.
 	ClusterConfig() cluster.Config
+	// CDCConfig is the configuration for publishing the row changes of each commit to Kafka, or nil to not publish
+	// them.
+	CDCConfig() cdc.Config
+	// ArchivingConfig is the configuration for continuously archiving branches to a backup of each database, or nil to
+	// not archive them.
+	ArchivingConfig() archiving.Config
+	// RetentionConfig is the configuration for squashing old history and garbage collecting it on a schedule, or nil
+	// to keep all history.
+	RetentionConfig() RetentionConfig
+	// SnapshotConfig is the configuration for tagging branches on a schedule, or nil to not tag them.
+	SnapshotConfig() SnapshotConfig
+	// StorageQuotas are the storage quotas of databases and branches, enforced when transactions are committed.
+	StorageQuotas() []StorageQuotaYAMLConfig
+	// DisableBackgroundConjoin is true if the server should not conjoin the table files of its databases in the
+	// background.
+	DisableBackgroundConjoin() bool
+	// BackgroundConjoinIntervalSecs is how often, in seconds, the server checks whether the table files of its
+	// databases need to be conjoined. 0 uses the default of 60.
+	BackgroundConjoinIntervalSecs() int
+	// BackgroundConjoinBytesPerSec limits how fast table files are read while conjoining them in the background.
+	// 0 uses the default of 32MiB per second.
+	BackgroundConjoinBytesPerSec() int
+	// ResultCacheEntries is the number of query results the server caches, or 0 to not cache query results.
+	ResultCacheEntries() int
+	// ResultCacheMaxRows is the most rows a query can return for its result to be cached. 0 uses the default.
+	ResultCacheMaxRows() int
+	// PlanCacheEntries is the number of query plans the server caches for each connection, or 0 to not cache them.
+	PlanCacheEntries() int
+	// EventScheduler is true if the server should run the events defined with CREATE EVENT in its databases.
+	EventScheduler() bool
+	// EventSchedulerBranch is the branch events are read from and run on, committing their changes. "" uses each
+	// database's default branch.
+	EventSchedulerBranch() string
+	// MergeDeleteSource defines the value of the @@dolt_merge_delete_source system variable, which deletes the merged
+	// branch after every successful DOLT_MERGE.
+	MergeDeleteSource() bool
 }
 
 type validatingServerConfig interface {
@@ -197,6 +252,7 @@ type commandLineServerConfig struct {
 	allowCleartextPasswords bool
 	socket                  string
 	remotesapiPort          *int
+	flightSQLPort           *int
 	goldenMysqlConn         string
 }
 
@@ -263,6 +319,57 @@ func (cfg *commandLineServerConfig) QueryParallelism() int {
 	return cfg.queryParallelism
 }
 
+// DisableBackgroundConjoin is true if the server should not conjoin the table files of its databases in the
+// background. Background conjoins can only be configured in a config file.
+func (cfg *commandLineServerConfig) DisableBackgroundConjoin() bool {
+	return false
+}
+
+// BackgroundConjoinIntervalSecs is how often, in seconds, the server checks whether the table files of its
+// databases need to be conjoined.
+func (cfg *commandLineServerConfig) BackgroundConjoinIntervalSecs() int {
+	return 0
+}
+
+// BackgroundConjoinBytesPerSec limits how fast table files are read while conjoining them in the background.
+func (cfg *commandLineServerConfig) BackgroundConjoinBytesPerSec() int {
+	return 0
+}
+
+// ResultCacheEntries is the number of query results the server caches, or 0 to not cache query results. The result
+// cache can only be enabled in a config file.
+func (cfg *commandLineServerConfig) ResultCacheEntries() int {
+	return 0
+}
+
+// ResultCacheMaxRows is the most rows a query can return for its result to be cached. 0 uses the default.
+func (cfg *commandLineServerConfig) ResultCacheMaxRows() int {
+	return 0
+}
+
+// PlanCacheEntries is the number of query plans the server caches for each connection, or 0 to not cache them. The
+// plan cache can only be enabled in a config file.
+func (cfg *commandLineServerConfig) PlanCacheEntries() int {
+	return 0
+}
+
+// EventScheduler is true if the server should run the events defined in its databases. The event scheduler can only
+// be enabled in a config file.
+func (cfg *commandLineServerConfig) EventScheduler() bool {
+	return false
+}
+
+// EventSchedulerBranch is the branch events are read from and run on.
+func (cfg *commandLineServerConfig) EventSchedulerBranch() string {
+	return ""
+}
+
+// MergeDeleteSource defines the value of the @@dolt_merge_delete_source system variable. It can only be enabled in a
+// config file.
+func (cfg *commandLineServerConfig) MergeDeleteSource() bool {
+	return false
+}
+
 // PersistenceBehavior returns whether to autoload persisted server configuration
 func (cfg *commandLineServerConfig) PersistenceBehavior() string {
 	return cfg.persistenceBehavior
@@ -321,10 +428,50 @@ func (cfg *commandLineServerConfig) RemotesapiPort() *int {
 	return cfg.remotesapiPort
 }
 
+// RemotesapiReadOnly is false if the remotesapi interface accepts pushes. Pushes can only be enabled in a config file.
+func (cfg *commandLineServerConfig) RemotesapiReadOnly() *bool {
+	return nil
+}
+
+// RemotesapiPreReceiveHook is the hook run before pushes to the remotesapi interface are applied.
+func (cfg *commandLineServerConfig) RemotesapiPreReceiveHook() string {
+	return ""
+}
+
+// RemotesapiWebhooks are the endpoints sent pushes to the remotesapi interface.
+func (cfg *commandLineServerConfig) RemotesapiWebhooks() []string {
+	return nil
+}
+
+// FlightSQLPort is the port to serve Arrow Flight SQL on.
+func (cfg *commandLineServerConfig) FlightSQLPort() *int {
+	return cfg.flightSQLPort
+}
+
 func (cfg *commandLineServerConfig) ClusterConfig() cluster.Config {
 	return nil
 }
 
+func (cfg *commandLineServerConfig) CDCConfig() cdc.Config {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) ArchivingConfig() archiving.Config {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) RetentionConfig() RetentionConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) SnapshotConfig() SnapshotConfig {
+	return nil
+}
+
+func (cfg *commandLineServerConfig) StorageQuotas() []StorageQuotaYAMLConfig {
+	return nil
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg *commandLineServerConfig) PrivilegeFilePath() string {
@@ -345,6 +492,10 @@ func (cfg *commandLineServerConfig) JwksConfig() []engine.JwksConfig {
 	return nil
 }
 
+func (cfg *commandLineServerConfig) Plugins() []string {
+	return nil
+}
+
 func (cfg *commandLineServerConfig) AllowCleartextPasswords() bool {
 	return cfg.allowCleartextPasswords
 }
@@ -466,6 +617,12 @@ func (cfg *commandLineServerConfig) WithRemotesapiPort(port *int) *commandLineSe
 	return cfg
 }
 
+// WithFlightSQLPort sets the port to serve Arrow Flight SQL on.
+func (cfg *commandLineServerConfig) WithFlightSQLPort(port *int) *commandLineServerConfig {
+	cfg.flightSQLPort = port
+	return cfg
+}
+
 func (cfg *commandLineServerConfig) goldenMysqlConnectionString() string {
 	return cfg.goldenMysqlConn
 }
@@ -514,9 +671,77 @@ func ValidateConfig(config ServerConfig) error {
 	if config.RequireSecureTransport() && config.TLSCert() == "" && config.TLSKey() == "" {
 		return fmt.Errorf("require_secure_transport can only be `true` when a tls_key and tls_cert are provided.")
 	}
+	if err := ValidateRetentionConfig(config.RetentionConfig()); err != nil {
+		return err
+	}
+	if err := ValidateSnapshotConfig(config.SnapshotConfig()); err != nil {
+		return err
+	}
+	if err := ValidateStorageQuotas(config.StorageQuotas()); err != nil {
+		return err
+	}
 	return ValidateClusterConfig(config.ClusterConfig())
 }
 
+func ValidateRetentionConfig(config RetentionConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.KeepDays() <= 0 {
+		return fmt.Errorf("retention: keep_days: is %d but must be > 0", config.KeepDays())
+	}
+	if config.SnapshotDays() < 0 {
+		return fmt.Errorf("retention: snapshot_days: is %d but must be >= 0", config.SnapshotDays())
+	}
+	if config.IntervalSecs() < 0 {
+		return fmt.Errorf("retention: interval_secs: is %d but must be >= 0", config.IntervalSecs())
+	}
+	return nil
+}
+
+func ValidateSnapshotConfig(config SnapshotConfig) error {
+	if config == nil {
+		return nil
+	}
+	if _, err := cron.Parse(config.Schedule()); err != nil {
+		return fmt.Errorf("snapshots: schedule: %w", err)
+	}
+	if len(config.Branches()) == 0 {
+		return fmt.Errorf("snapshots: branches: at least one branch must be supplied")
+	}
+	for _, branch := range config.Branches() {
+		name := snapshotTagName(config, branch, time.Now())
+		if !ref.IsValidTagName(name) {
+			return fmt.Errorf("snapshots: '%s' is not a valid tag name; check tag_prefix and time_format", name)
+		}
+	}
+	if config.Keep() < 0 {
+		return fmt.Errorf("snapshots: keep: is %d but must be >= 0", config.Keep())
+	}
+	return nil
+}
+
+func ValidateStorageQuotas(quotas []StorageQuotaYAMLConfig) error {
+	seen := make(map[string]bool)
+	for _, q := range quotas {
+		if q.Database == "" {
+			return fmt.Errorf("quotas: database: must be supplied for every quota")
+		}
+		if q.MaxBytes == 0 {
+			return fmt.Errorf("quotas: max_bytes: must be > 0 for database %s", q.Database)
+		}
+		key := strings.ToLower(q.Database) + "/" + q.Branch
+		if seen[key] {
+			if q.Branch == "" {
+				return fmt.Errorf("quotas: database %s has more than one quota", q.Database)
+			}
+			return fmt.Errorf("quotas: branch %s of database %s has more than one quota", q.Branch, q.Database)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
 func ValidateClusterConfig(config cluster.Config) error {
 	if config == nil {
 		return nil
@@ -0,0 +1,239 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/utils/cron"
+)
+
+const (
+	defaultSnapshotTagPrefix  = "snapshot/"
+	defaultSnapshotTimeFormat = "2006-01-02"
+)
+
+// SnapshotConfig is the configuration for tagging branches on a schedule.
+type SnapshotConfig interface {
+	// Schedule is the cron schedule, in UTC, on which branches are tagged.
+	Schedule() string
+	// Branches are the branches that are tagged.
+	Branches() []string
+	// TagPrefix begins the name of every snapshot tag. "" uses the default of "snapshot/".
+	TagPrefix() string
+	// TimeFormat is the Go time layout of the time in the name of every snapshot tag. "" uses the default of
+	// "2006-01-02".
+	TimeFormat() string
+	// Keep is the number of snapshot tags of each branch that are kept, deleting the oldest. 0 keeps every tag.
+	Keep() int
+}
+
+// snapshotTagName returns the name of the snapshot tag of |branch| made at |t|.
+func snapshotTagName(cfg SnapshotConfig, branch string, t time.Time) string {
+	return snapshotTagPrefix(cfg, branch) + t.UTC().Format(snapshotTimeFormat(cfg))
+}
+
+// snapshotTagPrefix returns the prefix of the names of the snapshot tags of |branch|.
+func snapshotTagPrefix(cfg SnapshotConfig, branch string) string {
+	prefix := cfg.TagPrefix()
+	if prefix == "" {
+		prefix = defaultSnapshotTagPrefix
+	}
+	return prefix + branch + "/"
+}
+
+func snapshotTimeFormat(cfg SnapshotConfig) string {
+	if cfg.TimeFormat() == "" {
+		return defaultSnapshotTimeFormat
+	}
+	return cfg.TimeFormat()
+}
+
+// snapshotTagger tags branches of the databases served by a sql-server on a cron schedule, so that there are
+// point-in-time references to them, like snapshot/main/2024-05-01. Each time, the configured branches of every database
+// that has them are tagged, and the oldest snapshot tags of each branch beyond the number to keep are deleted. A
+// branch isn't tagged again if the tag for the current time already exists, such as when the time format is coarser
+// than the schedule.
+type snapshotTagger struct {
+	se    *engine.SqlEngine
+	cfg   SnapshotConfig
+	sched *cron.Schedule
+	lgr   *logrus.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newSnapshotTagger returns a snapshotTagger for the databases of |se| configured by |cfg|, or nil if no snapshot
+// schedule is configured.
+func newSnapshotTagger(cfg ServerConfig, se *engine.SqlEngine, lgr *logrus.Logger) *snapshotTagger {
+	if cfg.SnapshotConfig() == nil || cfg.ReadOnly() {
+		return nil
+	}
+	sched, err := cron.Parse(cfg.SnapshotConfig().Schedule())
+	if err != nil {
+		// the schedule was validated with the rest of the config
+		lgr.Warnf("not tagging snapshots: %v", err)
+		return nil
+	}
+	return &snapshotTagger{
+		se:    se,
+		cfg:   cfg.SnapshotConfig(),
+		sched: sched,
+		lgr:   lgr,
+	}
+}
+
+// Start starts tagging branches on the schedule until Stop is called.
+func (st *snapshotTagger) Start(ctx context.Context) {
+	ctx, st.cancel = context.WithCancel(ctx)
+	st.wg.Add(1)
+	go func() {
+		defer st.wg.Done()
+		for {
+			next := st.sched.Next(time.Now().UTC())
+			if next.IsZero() {
+				st.lgr.Warnf("not tagging snapshots: schedule %s never fires", st.cfg.Schedule())
+				return
+			}
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				st.tagAll(ctx, next)
+			}
+		}
+	}()
+}
+
+// Stop stops tagging branches, waiting for the branches being tagged to finish.
+func (st *snapshotTagger) Stop() {
+	st.cancel()
+	st.wg.Wait()
+}
+
+func (st *snapshotTagger) tagAll(ctx context.Context, t time.Time) {
+	sqlCtx, err := st.se.NewLocalContext(ctx)
+	if err != nil {
+		st.lgr.Warnf("error creating context for snapshot tags: %v", err)
+		return
+	}
+
+	for _, db := range st.se.Databases(sqlCtx) {
+		for _, branch := range st.cfg.Branches() {
+			err := st.tag(ctx, db.Name(), branch, t)
+			if ctx.Err() != nil {
+				return
+			} else if err != nil {
+				st.lgr.Warnf("error tagging snapshot of branch %s of database %s: %v", branch, db.Name(), err)
+			}
+		}
+	}
+}
+
+// tag tags |branch| of the database |dbName| with the snapshot tag for |t| and prunes its old snapshot tags. Databases
+// without the branch are skipped.
+func (st *snapshotTagger) tag(ctx context.Context, dbName, branch string, t time.Time) error {
+	rows, err := st.query(ctx, dbName, fmt.Sprintf("SELECT COUNT(*) FROM dolt_branches WHERE name = %s", quoteSqlString(branch)))
+	if err != nil {
+		return err
+	}
+	if rows[0][0].(int64) == 0 {
+		return nil
+	}
+
+	tagName := snapshotTagName(st.cfg, branch, t)
+	rows, err = st.query(ctx, dbName, fmt.Sprintf("SELECT COUNT(*) FROM dolt_tags WHERE tag_name = %s", quoteSqlString(tagName)))
+	if err != nil {
+		return err
+	}
+	if rows[0][0].(int64) == 0 {
+		_, err = st.query(ctx, dbName, fmt.Sprintf("CALL DOLT_TAG(%s, %s, '-m', %s)",
+			quoteSqlString(tagName), quoteSqlString(branch), quoteSqlString("scheduled snapshot of "+branch)))
+		if err != nil {
+			return err
+		}
+		st.lgr.Infof("tagged branch %s of database %s as %s", branch, dbName, tagName)
+	}
+
+	return st.prune(ctx, dbName, branch)
+}
+
+// prune deletes the oldest snapshot tags of |branch| of the database |dbName| beyond the number to keep.
+func (st *snapshotTagger) prune(ctx context.Context, dbName, branch string) error {
+	if st.cfg.Keep() <= 0 {
+		return nil
+	}
+
+	rows, err := st.query(ctx, dbName, "SELECT tag_name, date FROM dolt_tags")
+	if err != nil {
+		return err
+	}
+	prefix := snapshotTagPrefix(st.cfg, branch)
+	var snapshots []sql.Row
+	for _, row := range rows {
+		if strings.HasPrefix(row[0].(string), prefix) {
+			snapshots = append(snapshots, row)
+		}
+	}
+	if len(snapshots) <= st.cfg.Keep() {
+		return nil
+	}
+
+	// newest first, by the time they were tagged and then by name
+	sort.Slice(snapshots, func(i, j int) bool {
+		ti, tj := snapshots[i][1].(time.Time), snapshots[j][1].(time.Time)
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return snapshots[i][0].(string) > snapshots[j][0].(string)
+	})
+	for _, row := range snapshots[st.cfg.Keep():] {
+		if _, err = st.query(ctx, dbName, fmt.Sprintf("CALL DOLT_TAG('-d', %s)", quoteSqlString(row[0].(string)))); err != nil {
+			return err
+		}
+		st.lgr.Infof("deleted snapshot tag %s of database %s", row[0], dbName)
+	}
+	return nil
+}
+
+func (st *snapshotTagger) query(ctx context.Context, dbName, query string) ([]sql.Row, error) {
+	sqlCtx, err := st.se.NewLocalContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	sch, iter, err := st.se.Query(sqlCtx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowIterToRows(sqlCtx, sch, iter)
+}
+
+func quoteSqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -47,6 +47,7 @@ const (
 	allowCleartextPasswordsFlag = "allow-cleartext-passwords"
 	socketFlag                  = "socket"
 	remotesapiPortFlag          = "remotesapi-port"
+	flightSQLPortFlag           = "flight-sql-port"
 	goldenMysqlConn             = "golden"
 )
 
@@ -107,6 +108,10 @@ SUPPORTED CONFIG FILE FIELDS:
 
 {{.EmphasisLeft}}remotesapi.port{{.EmphasisRight}}: A port to listen for remote API operations on. If set to a positive integer, this server will accept connections from clients to clone, pull, etc. databases being served.
 
+{{.EmphasisLeft}}flight_sql.port{{.EmphasisRight}}: A port to serve Arrow Flight SQL on. Clients like ADBC, pandas, polars and Spark can run queries over Flight SQL and receive the results as Arrow record batches, without converting them row by row. A query runs in the database named by the {{.EmphasisLeft}}database{{.EmphasisRight}} call header, pinned to the branch, tag or commit in the {{.EmphasisLeft}}revision{{.EmphasisRight}} call header if there is one. Clients authenticate with basic authorization as any user of the server, and their queries run with that user's privileges. The listener uses TLS if {{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}} and {{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}} are set.
+
+{{.EmphasisLeft}}cdc.kafka.brokers{{.EmphasisRight}}: Kafka brokers to publish the row changes made by each commit to, as change events in Debezium's envelope. The changes to each table are published to the topic {{.EmphasisLeft}}<cdc.topic_prefix>.<database>.<table>{{.EmphasisRight}}, keyed by primary key. Only commits to the branches in {{.EmphasisLeft}}cdc.branches{{.EmphasisRight}} are published, or commits to every branch if it isn't set.
+
 {{.EmphasisLeft}}user_session_vars{{.EmphasisRight}}: A map of user name to a map of session variables to set on connection for each session.
 
 {{.EmphasisLeft}}cluster{{.EmphasisRight}}: Settings related to running this server in a replicated cluster. For information on setting these values, see https://docs.dolthub.com/sql-reference/server/replication
@@ -165,6 +170,7 @@ func (cmd SqlServerCmd) ArgParserWithName(name string) *argparser.ArgParser {
 	ap.SupportsString(allowCleartextPasswordsFlag, "", "allow-cleartext-passwords", "Allows use of cleartext passwords. Defaults to false.")
 	ap.SupportsOptionalString(socketFlag, "", "socket file", "Path for the unix socket file. Defaults to '/tmp/mysql.sock'.")
 	ap.SupportsUint(remotesapiPortFlag, "", "remotesapi port", "Sets the port for a server which can expose the databases in this sql-server over remotesapi, so that clients can clone or pull from this server.")
+	ap.SupportsUint(flightSQLPortFlag, "", "flight sql port", "Sets the port for an Arrow Flight SQL server, which streams query results as Arrow record batches.")
 	ap.SupportsString(goldenMysqlConn, "", "mysql connection string", "Provides a connection string to a MySQL instance to be used to validate query results")
 	return ap
 }
@@ -391,6 +397,10 @@ func getCommandLineServerConfig(apr *argparser.ArgParseResults) (ServerConfig, e
 		serverConfig.WithRemotesapiPort(&port)
 	}
 
+	if port, ok := apr.GetInt(flightSQLPortFlag); ok {
+		serverConfig.WithFlightSQLPort(&port)
+	}
+
 	if persistenceBehavior, ok := apr.GetValue(persistenceBehaviorFlag); ok {
 		serverConfig.withPersistenceBehavior(persistenceBehavior)
 	}
@@ -23,6 +23,8 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/commands/engine"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/archiving"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cdc"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 )
 
@@ -79,6 +81,13 @@ type BehaviorYAMLConfig struct {
 	// DoltTransactionCommit enables the @@dolt_transaction_commit system variable, which
 	// automatically creates a Dolt commit when any SQL transaction is committed.
 	DoltTransactionCommit *bool `yaml:"dolt_transaction_commit"`
+	// EventScheduler runs the events defined with CREATE EVENT in each database, committing their changes.
+	EventScheduler *bool `yaml:"event_scheduler,omitempty"`
+	// EventSchedulerBranch is the branch events are read from and run on. Defaults to each database's default branch.
+	EventSchedulerBranch *string `yaml:"event_scheduler_branch,omitempty"`
+	// MergeDeleteSource enables the @@dolt_merge_delete_source system variable, which deletes the merged branch after
+	// every successful DOLT_MERGE.
+	MergeDeleteSource *bool `yaml:"merge_delete_source,omitempty"`
 }
 
 // UserYAMLConfig contains server configuration regarding the user account clients must use to connect
@@ -115,6 +124,18 @@ type ListenerYAMLConfig struct {
 // PerformanceYAMLConfig contains configuration parameters for performance tweaking
 type PerformanceYAMLConfig struct {
 	QueryParallelism *int `yaml:"query_parallelism"`
+	// DisableBackgroundConjoin turns off conjoining the table files of each database in the background.
+	DisableBackgroundConjoin *bool `yaml:"disable_background_conjoin,omitempty"`
+	// BackgroundConjoinIntervalSecs is how often table files are checked and conjoined in the background.
+	BackgroundConjoinIntervalSecs *int `yaml:"background_conjoin_interval_secs,omitempty"`
+	// BackgroundConjoinBytesPerSec limits how fast table files are read while conjoining them in the background.
+	BackgroundConjoinBytesPerSec *int `yaml:"background_conjoin_bytes_per_sec,omitempty"`
+	// ResultCacheEntries is the number of query results kept in the result cache. The cache is disabled if it's unset.
+	ResultCacheEntries *int `yaml:"result_cache_entries,omitempty"`
+	// ResultCacheMaxRows is the most rows a query can return for its result to be cached.
+	ResultCacheMaxRows *int `yaml:"result_cache_max_rows,omitempty"`
+	// PlanCacheEntries is the number of query plans kept for each connection. The cache is disabled if it's unset.
+	PlanCacheEntries *int `yaml:"plan_cache_entries,omitempty"`
 }
 
 type MetricsYAMLConfig struct {
@@ -124,13 +145,20 @@ type MetricsYAMLConfig struct {
 }
 
 type RemotesapiYAMLConfig struct {
-	Port_ *int `yaml:"port"`
+	Port_           *int     `yaml:"port"`
+	ReadOnly_       *bool    `yaml:"read_only,omitempty"`
+	PreReceiveHook_ *string  `yaml:"pre_receive_hook,omitempty"`
+	Webhooks_       []string `yaml:"webhooks,omitempty"`
 }
 
 func (r RemotesapiYAMLConfig) Port() int {
 	return *r.Port_
 }
 
+type FlightSQLYAMLConfig struct {
+	Port *int `yaml:"port,omitempty"`
+}
+
 type UserSessionVars struct {
 	Name string            `yaml:"name"`
 	Vars map[string]string `yaml:"vars"`
@@ -138,23 +166,30 @@ type UserSessionVars struct {
 
 // YAMLConfig is a ServerConfig implementation which is read from a yaml file
 type YAMLConfig struct {
-	LogLevelStr       *string               `yaml:"log_level,omitempty"`
-	MaxQueryLenInLogs *int                  `yaml:"max_logged_query_len,omitempty"`
-	EncodeLoggedQuery *bool                 `yaml:"encode_logged_query,omitempty"`
-	BehaviorConfig    BehaviorYAMLConfig    `yaml:"behavior"`
-	UserConfig        UserYAMLConfig        `yaml:"user"`
-	ListenerConfig    ListenerYAMLConfig    `yaml:"listener"`
-	PerformanceConfig PerformanceYAMLConfig `yaml:"performance"`
-	DataDirStr        *string               `yaml:"data_dir,omitempty"`
-	CfgDirStr         *string               `yaml:"cfg_dir,omitempty"`
-	MetricsConfig     MetricsYAMLConfig     `yaml:"metrics"`
-	RemotesapiConfig  RemotesapiYAMLConfig  `yaml:"remotesapi"`
-	ClusterCfg        *ClusterYAMLConfig    `yaml:"cluster,omitempty"`
-	PrivilegeFile     *string               `yaml:"privilege_file,omitempty"`
-	BranchControlFile *string               `yaml:"branch_control_file,omitempty"`
-	Vars              []UserSessionVars     `yaml:"user_session_vars"`
-	Jwks              []engine.JwksConfig   `yaml:"jwks"`
-	GoldenMysqlConn   *string               `yaml:"golden_mysql_conn,omitempty"`
+	LogLevelStr       *string                  `yaml:"log_level,omitempty"`
+	MaxQueryLenInLogs *int                     `yaml:"max_logged_query_len,omitempty"`
+	EncodeLoggedQuery *bool                    `yaml:"encode_logged_query,omitempty"`
+	BehaviorConfig    BehaviorYAMLConfig       `yaml:"behavior"`
+	UserConfig        UserYAMLConfig           `yaml:"user"`
+	ListenerConfig    ListenerYAMLConfig       `yaml:"listener"`
+	PerformanceConfig PerformanceYAMLConfig    `yaml:"performance"`
+	DataDirStr        *string                  `yaml:"data_dir,omitempty"`
+	CfgDirStr         *string                  `yaml:"cfg_dir,omitempty"`
+	MetricsConfig     MetricsYAMLConfig        `yaml:"metrics"`
+	RemotesapiConfig  RemotesapiYAMLConfig     `yaml:"remotesapi"`
+	FlightSQLConfig   FlightSQLYAMLConfig      `yaml:"flight_sql,omitempty"`
+	ClusterCfg        *ClusterYAMLConfig       `yaml:"cluster,omitempty"`
+	CDCCfg            *CDCYAMLConfig           `yaml:"cdc,omitempty"`
+	ArchivingCfg      *ArchivingYAMLConfig     `yaml:"archiving,omitempty"`
+	RetentionCfg      *RetentionYAMLConfig     `yaml:"retention,omitempty"`
+	SnapshotCfg       *SnapshotYAMLConfig      `yaml:"snapshots,omitempty"`
+	Quotas            []StorageQuotaYAMLConfig `yaml:"quotas,omitempty"`
+	PrivilegeFile     *string                  `yaml:"privilege_file,omitempty"`
+	BranchControlFile *string                  `yaml:"branch_control_file,omitempty"`
+	Vars              []UserSessionVars        `yaml:"user_session_vars"`
+	Jwks              []engine.JwksConfig      `yaml:"jwks"`
+	PluginPaths       []string                 `yaml:"plugins,omitempty"`
+	GoldenMysqlConn   *string                  `yaml:"golden_mysql_conn,omitempty"`
 }
 
 var _ ServerConfig = YAMLConfig{}
@@ -181,6 +216,9 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			strPtr(cfg.PersistenceBehavior()),
 			boolPtr(cfg.DisableClientMultiStatements()),
 			boolPtr(cfg.DoltTransactionCommit()),
+			nillableBoolPtr(cfg.EventScheduler()),
+			nillableStrPtr(cfg.EventSchedulerBranch()),
+			nillableBoolPtr(cfg.MergeDeleteSource()),
 		},
 		UserConfig: UserYAMLConfig{
 			Name:     strPtr(cfg.User()),
@@ -199,7 +237,13 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			nillableStrPtr(cfg.Socket()),
 		},
 		PerformanceConfig: PerformanceYAMLConfig{
-			QueryParallelism: nillableIntPtr(cfg.QueryParallelism()),
+			QueryParallelism:              nillableIntPtr(cfg.QueryParallelism()),
+			DisableBackgroundConjoin:      nillableBoolPtr(cfg.DisableBackgroundConjoin()),
+			BackgroundConjoinIntervalSecs: nillableIntPtr(cfg.BackgroundConjoinIntervalSecs()),
+			BackgroundConjoinBytesPerSec:  nillableIntPtr(cfg.BackgroundConjoinBytesPerSec()),
+			ResultCacheEntries:            nillableIntPtr(cfg.ResultCacheEntries()),
+			ResultCacheMaxRows:            nillableIntPtr(cfg.ResultCacheMaxRows()),
+			PlanCacheEntries:              nillableIntPtr(cfg.PlanCacheEntries()),
 		},
 		DataDirStr: strPtr(cfg.DataDir()),
 		CfgDirStr:  strPtr(cfg.CfgDir()),
@@ -209,13 +253,25 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			Port:   intPtr(cfg.MetricsPort()),
 		},
 		RemotesapiConfig: RemotesapiYAMLConfig{
-			Port_: cfg.RemotesapiPort(),
+			Port_:           cfg.RemotesapiPort(),
+			ReadOnly_:       cfg.RemotesapiReadOnly(),
+			PreReceiveHook_: nillableStrPtr(cfg.RemotesapiPreReceiveHook()),
+			Webhooks_:       cfg.RemotesapiWebhooks(),
+		},
+		FlightSQLConfig: FlightSQLYAMLConfig{
+			Port: cfg.FlightSQLPort(),
 		},
 		ClusterCfg:        clusterConfigAsYAMLConfig(cfg.ClusterConfig()),
+		CDCCfg:            cdcConfigAsYAMLConfig(cfg.CDCConfig()),
+		ArchivingCfg:      archivingConfigAsYAMLConfig(cfg.ArchivingConfig()),
+		RetentionCfg:      retentionConfigAsYAMLConfig(cfg.RetentionConfig()),
+		SnapshotCfg:       snapshotConfigAsYAMLConfig(cfg.SnapshotConfig()),
+		Quotas:            cfg.StorageQuotas(),
 		PrivilegeFile:     strPtr(cfg.PrivilegeFilePath()),
 		BranchControlFile: strPtr(cfg.BranchControlFilePath()),
 		Vars:              cfg.UserVars(),
 		Jwks:              cfg.JwksConfig(),
+		PluginPaths:       cfg.Plugins(),
 	}
 }
 
@@ -240,6 +296,58 @@ func clusterConfigAsYAMLConfig(config cluster.Config) *ClusterYAMLConfig {
 	}
 }
 
+func cdcConfigAsYAMLConfig(config cdc.Config) *CDCYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &CDCYAMLConfig{
+		Kafka:        CDCKafkaYAMLConfig{Brokers_: config.Brokers()},
+		TopicPrefix_: config.TopicPrefix(),
+		Branches_:    config.Branches(),
+	}
+}
+
+func archivingConfigAsYAMLConfig(config archiving.Config) *ArchivingYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &ArchivingYAMLConfig{
+		Backup_:       config.Backup(),
+		Branches_:     config.Branches(),
+		EveryCommits_: config.EveryCommits(),
+		IntervalSecs_: config.IntervalSecs(),
+	}
+}
+
+func retentionConfigAsYAMLConfig(config RetentionConfig) *RetentionYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &RetentionYAMLConfig{
+		KeepDays_:     config.KeepDays(),
+		SnapshotDays_: config.SnapshotDays(),
+		Branches_:     config.Branches(),
+		IntervalSecs_: config.IntervalSecs(),
+	}
+}
+
+func snapshotConfigAsYAMLConfig(config SnapshotConfig) *SnapshotYAMLConfig {
+	if config == nil {
+		return nil
+	}
+
+	return &SnapshotYAMLConfig{
+		Schedule_:   config.Schedule(),
+		Branches_:   config.Branches(),
+		TagPrefix_:  config.TagPrefix(),
+		TimeFormat_: config.TimeFormat(),
+		Keep_:       config.Keep(),
+	}
+}
+
 // String returns the YAML representation of the config
 func (cfg YAMLConfig) String() string {
 	data, err := yaml.Marshal(cfg)
@@ -383,6 +491,31 @@ func (cfg YAMLConfig) DisableClientMultiStatements() bool {
 	return *cfg.BehaviorConfig.DisableClientMultiStatements
 }
 
+// EventScheduler is true if the server should run the events defined in its databases.
+func (cfg YAMLConfig) EventScheduler() bool {
+	if cfg.BehaviorConfig.EventScheduler == nil {
+		return false
+	}
+	return *cfg.BehaviorConfig.EventScheduler
+}
+
+// EventSchedulerBranch is the branch events are read from and run on.
+func (cfg YAMLConfig) EventSchedulerBranch() string {
+	if cfg.BehaviorConfig.EventSchedulerBranch == nil {
+		return ""
+	}
+	return *cfg.BehaviorConfig.EventSchedulerBranch
+}
+
+// MergeDeleteSource defines the value of the @@dolt_merge_delete_source system variable, which deletes the merged
+// branch after every successful DOLT_MERGE.
+func (cfg YAMLConfig) MergeDeleteSource() bool {
+	if cfg.BehaviorConfig.MergeDeleteSource == nil {
+		return false
+	}
+	return *cfg.BehaviorConfig.MergeDeleteSource
+}
+
 // MetricsLabels returns labels that are applied to all prometheus metrics
 func (cfg YAMLConfig) MetricsLabels() map[string]string {
 	return cfg.MetricsConfig.Labels
@@ -408,6 +541,25 @@ func (cfg YAMLConfig) RemotesapiPort() *int {
 	return cfg.RemotesapiConfig.Port_
 }
 
+func (cfg YAMLConfig) RemotesapiReadOnly() *bool {
+	return cfg.RemotesapiConfig.ReadOnly_
+}
+
+func (cfg YAMLConfig) RemotesapiPreReceiveHook() string {
+	if cfg.RemotesapiConfig.PreReceiveHook_ == nil {
+		return ""
+	}
+	return *cfg.RemotesapiConfig.PreReceiveHook_
+}
+
+func (cfg YAMLConfig) RemotesapiWebhooks() []string {
+	return cfg.RemotesapiConfig.Webhooks_
+}
+
+func (cfg YAMLConfig) FlightSQLPort() *int {
+	return cfg.FlightSQLConfig.Port
+}
+
 // PrivilegeFilePath returns the path to the file which contains all needed privilege information in the form of a
 // JSON string.
 func (cfg YAMLConfig) PrivilegeFilePath() string {
@@ -442,6 +594,11 @@ func (cfg YAMLConfig) JwksConfig() []engine.JwksConfig {
 	return nil
 }
 
+// Plugins are the paths of the Go plugins the server loads user-defined functions from.
+func (cfg YAMLConfig) Plugins() []string {
+	return cfg.PluginPaths
+}
+
 func (cfg YAMLConfig) AllowCleartextPasswords() bool {
 	if cfg.ListenerConfig.AllowCleartextPasswords == nil {
 		return defaultAllowCleartextPasswords
@@ -458,6 +615,56 @@ func (cfg YAMLConfig) QueryParallelism() int {
 	return *cfg.PerformanceConfig.QueryParallelism
 }
 
+// DisableBackgroundConjoin is true if the server should not conjoin the table files of its databases in the
+// background.
+func (cfg YAMLConfig) DisableBackgroundConjoin() bool {
+	if cfg.PerformanceConfig.DisableBackgroundConjoin == nil {
+		return false
+	}
+	return *cfg.PerformanceConfig.DisableBackgroundConjoin
+}
+
+// BackgroundConjoinIntervalSecs is how often, in seconds, the server checks whether the table files of its
+// databases need to be conjoined.
+func (cfg YAMLConfig) BackgroundConjoinIntervalSecs() int {
+	if cfg.PerformanceConfig.BackgroundConjoinIntervalSecs == nil {
+		return 0
+	}
+	return *cfg.PerformanceConfig.BackgroundConjoinIntervalSecs
+}
+
+// BackgroundConjoinBytesPerSec limits how fast table files are read while conjoining them in the background.
+func (cfg YAMLConfig) BackgroundConjoinBytesPerSec() int {
+	if cfg.PerformanceConfig.BackgroundConjoinBytesPerSec == nil {
+		return 0
+	}
+	return *cfg.PerformanceConfig.BackgroundConjoinBytesPerSec
+}
+
+// ResultCacheEntries is the number of query results the server caches, or 0 to not cache query results.
+func (cfg YAMLConfig) ResultCacheEntries() int {
+	if cfg.PerformanceConfig.ResultCacheEntries == nil {
+		return 0
+	}
+	return *cfg.PerformanceConfig.ResultCacheEntries
+}
+
+// ResultCacheMaxRows is the most rows a query can return for its result to be cached. 0 uses the default.
+func (cfg YAMLConfig) ResultCacheMaxRows() int {
+	if cfg.PerformanceConfig.ResultCacheMaxRows == nil {
+		return 0
+	}
+	return *cfg.PerformanceConfig.ResultCacheMaxRows
+}
+
+// PlanCacheEntries is the number of query plans the server caches for each connection, or 0 to not cache them.
+func (cfg YAMLConfig) PlanCacheEntries() int {
+	if cfg.PerformanceConfig.PlanCacheEntries == nil {
+		return 0
+	}
+	return *cfg.PerformanceConfig.PlanCacheEntries
+}
+
 // TLSKey returns a path to the servers PEM-encoded private TLS key. "" if there is none.
 func (cfg YAMLConfig) TLSKey() string {
 	if cfg.ListenerConfig.TLSKey == nil {
@@ -551,6 +758,142 @@ func (cfg YAMLConfig) ClusterConfig() cluster.Config {
 	return cfg.ClusterCfg
 }
 
+func (cfg YAMLConfig) CDCConfig() cdc.Config {
+	if cfg.CDCCfg == nil {
+		return nil
+	}
+	return cfg.CDCCfg
+}
+
+type CDCYAMLConfig struct {
+	Kafka        CDCKafkaYAMLConfig `yaml:"kafka"`
+	TopicPrefix_ string             `yaml:"topic_prefix"`
+	Branches_    []string           `yaml:"branches,omitempty"`
+}
+
+type CDCKafkaYAMLConfig struct {
+	Brokers_ []string `yaml:"brokers"`
+}
+
+func (c *CDCYAMLConfig) Brokers() []string {
+	return c.Kafka.Brokers_
+}
+
+func (c *CDCYAMLConfig) TopicPrefix() string {
+	return c.TopicPrefix_
+}
+
+func (c *CDCYAMLConfig) Branches() []string {
+	return c.Branches_
+}
+
+func (cfg YAMLConfig) ArchivingConfig() archiving.Config {
+	if cfg.ArchivingCfg == nil {
+		return nil
+	}
+	return cfg.ArchivingCfg
+}
+
+type ArchivingYAMLConfig struct {
+	Backup_       string   `yaml:"backup"`
+	Branches_     []string `yaml:"branches,omitempty"`
+	EveryCommits_ int      `yaml:"every_commits,omitempty"`
+	IntervalSecs_ int      `yaml:"interval_secs,omitempty"`
+}
+
+func (c *ArchivingYAMLConfig) Backup() string {
+	return c.Backup_
+}
+
+func (c *ArchivingYAMLConfig) Branches() []string {
+	return c.Branches_
+}
+
+func (c *ArchivingYAMLConfig) EveryCommits() int {
+	return c.EveryCommits_
+}
+
+func (c *ArchivingYAMLConfig) IntervalSecs() int {
+	return c.IntervalSecs_
+}
+
+func (cfg YAMLConfig) RetentionConfig() RetentionConfig {
+	if cfg.RetentionCfg == nil {
+		return nil
+	}
+	return cfg.RetentionCfg
+}
+
+type RetentionYAMLConfig struct {
+	KeepDays_     int      `yaml:"keep_days"`
+	SnapshotDays_ int      `yaml:"snapshot_days,omitempty"`
+	Branches_     []string `yaml:"branches,omitempty"`
+	IntervalSecs_ int      `yaml:"interval_secs,omitempty"`
+}
+
+func (c *RetentionYAMLConfig) KeepDays() int {
+	return c.KeepDays_
+}
+
+func (c *RetentionYAMLConfig) SnapshotDays() int {
+	return c.SnapshotDays_
+}
+
+func (c *RetentionYAMLConfig) Branches() []string {
+	return c.Branches_
+}
+
+func (c *RetentionYAMLConfig) IntervalSecs() int {
+	return c.IntervalSecs_
+}
+
+func (cfg YAMLConfig) SnapshotConfig() SnapshotConfig {
+	if cfg.SnapshotCfg == nil {
+		return nil
+	}
+	return cfg.SnapshotCfg
+}
+
+type SnapshotYAMLConfig struct {
+	Schedule_   string   `yaml:"schedule"`
+	Branches_   []string `yaml:"branches"`
+	TagPrefix_  string   `yaml:"tag_prefix,omitempty"`
+	TimeFormat_ string   `yaml:"time_format,omitempty"`
+	Keep_       int      `yaml:"keep,omitempty"`
+}
+
+func (c *SnapshotYAMLConfig) Schedule() string {
+	return c.Schedule_
+}
+
+func (c *SnapshotYAMLConfig) Branches() []string {
+	return c.Branches_
+}
+
+func (c *SnapshotYAMLConfig) TagPrefix() string {
+	return c.TagPrefix_
+}
+
+func (c *SnapshotYAMLConfig) TimeFormat() string {
+	return c.TimeFormat_
+}
+
+func (c *SnapshotYAMLConfig) Keep() int {
+	return c.Keep_
+}
+
+// StorageQuotas are the storage quotas of databases and branches, enforced when transactions are committed.
+func (cfg YAMLConfig) StorageQuotas() []StorageQuotaYAMLConfig {
+	return cfg.Quotas
+}
+
+// StorageQuotaYAMLConfig limits the storage used by a database, or by one of its branches. See dsess.StorageQuota.
+type StorageQuotaYAMLConfig struct {
+	Database string `yaml:"database"`
+	Branch   string `yaml:"branch,omitempty"`
+	MaxBytes uint64 `yaml:"max_bytes"`
+}
+
 type ClusterYAMLConfig struct {
 	StandbyRemotes_ []StandbyRemoteYAMLConfig   `yaml:"standby_remotes"`
 	BootstrapRole_  string                      `yaml:"bootstrap_role"`
@@ -16,6 +16,7 @@ package sqlserver
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -158,6 +159,259 @@ remotesapi:
 	require.Equal(t, 8000, *config.RemotesapiPort())
 }
 
+func TestUnmarshallFlightSQLPort(t *testing.T) {
+	testStr := `
+flight_sql:
+  port: 32010
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.FlightSQLPort())
+	require.Equal(t, 32010, *config.FlightSQLPort())
+}
+
+func TestUnmarshallCDCConfig(t *testing.T) {
+	testStr := `
+cdc:
+  kafka:
+    brokers: ["localhost:9092", "localhost:9093"]
+  topic_prefix: dolt
+  branches: [main]
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.CDCConfig())
+	require.Equal(t, []string{"localhost:9092", "localhost:9093"}, config.CDCConfig().Brokers())
+	require.Equal(t, "dolt", config.CDCConfig().TopicPrefix())
+	require.Equal(t, []string{"main"}, config.CDCConfig().Branches())
+
+	config, err = NewYamlConfig([]byte(`listener:
+  port: 3306
+`))
+	require.NoError(t, err)
+	require.Nil(t, config.CDCConfig())
+}
+
+func TestUnmarshallArchivingConfig(t *testing.T) {
+	testStr := `
+archiving:
+  backup: archive
+  branches: [main, release]
+  every_commits: 10
+  interval_secs: 300
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.ArchivingConfig())
+	require.Equal(t, "archive", config.ArchivingConfig().Backup())
+	require.Equal(t, []string{"main", "release"}, config.ArchivingConfig().Branches())
+	require.Equal(t, 10, config.ArchivingConfig().EveryCommits())
+	require.Equal(t, 300, config.ArchivingConfig().IntervalSecs())
+
+	config, err = NewYamlConfig([]byte(`listener:
+  port: 3306
+`))
+	require.NoError(t, err)
+	require.Nil(t, config.ArchivingConfig())
+}
+
+func TestUnmarshallRetentionConfig(t *testing.T) {
+	testStr := `
+retention:
+  keep_days: 90
+  snapshot_days: 7
+  branches: [main]
+  interval_secs: 3600
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.RetentionConfig())
+	require.Equal(t, 90, config.RetentionConfig().KeepDays())
+	require.Equal(t, 7, config.RetentionConfig().SnapshotDays())
+	require.Equal(t, []string{"main"}, config.RetentionConfig().Branches())
+	require.Equal(t, 3600, config.RetentionConfig().IntervalSecs())
+	require.NoError(t, ValidateRetentionConfig(config.RetentionConfig()))
+
+	config, err = NewYamlConfig([]byte(`retention:
+  snapshot_days: 7
+`))
+	require.NoError(t, err)
+	require.Error(t, ValidateRetentionConfig(config.RetentionConfig()))
+
+	config, err = NewYamlConfig([]byte(`listener:
+  port: 3306
+`))
+	require.NoError(t, err)
+	require.Nil(t, config.RetentionConfig())
+}
+
+func TestUnmarshallSnapshotConfig(t *testing.T) {
+	testStr := `
+snapshots:
+  schedule: "0 0 * * *"
+  branches: [main, release]
+  tag_prefix: nightly/
+  keep: 30
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.SnapshotConfig())
+	require.Equal(t, "0 0 * * *", config.SnapshotConfig().Schedule())
+	require.Equal(t, []string{"main", "release"}, config.SnapshotConfig().Branches())
+	require.Equal(t, "nightly/", config.SnapshotConfig().TagPrefix())
+	require.Equal(t, "", config.SnapshotConfig().TimeFormat())
+	require.Equal(t, 30, config.SnapshotConfig().Keep())
+	require.NoError(t, ValidateSnapshotConfig(config.SnapshotConfig()))
+
+	now := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, "nightly/main/2024-05-01", snapshotTagName(config.SnapshotConfig(), "main", now))
+
+	for _, invalid := range []string{
+		"snapshots:\n  schedule: \"0 0 * *\"\n  branches: [main]\n",
+		"snapshots:\n  schedule: \"@daily\"\n",
+		"snapshots:\n  schedule: \"@daily\"\n  branches: [main]\n  time_format: \"15:04\"\n",
+		"snapshots:\n  schedule: \"@daily\"\n  branches: [main]\n  keep: -1\n",
+	} {
+		config, err = NewYamlConfig([]byte(invalid))
+		require.NoError(t, err)
+		require.Error(t, ValidateSnapshotConfig(config.SnapshotConfig()), invalid)
+	}
+
+	config, err = NewYamlConfig([]byte(`listener:
+  port: 3306
+`))
+	require.NoError(t, err)
+	require.Nil(t, config.SnapshotConfig())
+}
+
+func TestUnmarshallStorageQuotas(t *testing.T) {
+	testStr := `
+quotas:
+  - database: mydb
+    max_bytes: 1073741824
+  - database: mydb
+    branch: experiment
+    max_bytes: 104857600
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.Equal(t, []StorageQuotaYAMLConfig{
+		{Database: "mydb", MaxBytes: 1073741824},
+		{Database: "mydb", Branch: "experiment", MaxBytes: 104857600},
+	}, config.StorageQuotas())
+	require.NoError(t, ValidateStorageQuotas(config.StorageQuotas()))
+
+	config, err = NewYamlConfig([]byte(`quotas:
+  - database: mydb
+`))
+	require.NoError(t, err)
+	require.Error(t, ValidateStorageQuotas(config.StorageQuotas()))
+
+	config, err = NewYamlConfig([]byte(`quotas:
+  - database: mydb
+    branch: main
+    max_bytes: 1024
+  - database: MYDB
+    branch: main
+    max_bytes: 2048
+`))
+	require.NoError(t, err)
+	require.Error(t, ValidateStorageQuotas(config.StorageQuotas()))
+}
+
+func TestUnmarshallRemotesapiPushHooks(t *testing.T) {
+	testStr := `
+remotesapi:
+  port: 8000
+  read_only: false
+  pre_receive_hook: /usr/local/bin/check-push
+  webhooks:
+    - http://localhost:9000/pushed
+    - https://example.com/pushed
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	require.NotNil(t, config.RemotesapiReadOnly())
+	assert.False(t, *config.RemotesapiReadOnly())
+	assert.Equal(t, "/usr/local/bin/check-push", config.RemotesapiPreReceiveHook())
+	assert.Equal(t, []string{"http://localhost:9000/pushed", "https://example.com/pushed"}, config.RemotesapiWebhooks())
+
+	config, err = NewYamlConfig([]byte{})
+	require.NoError(t, err)
+	assert.Nil(t, config.RemotesapiReadOnly())
+	assert.Equal(t, "", config.RemotesapiPreReceiveHook())
+	assert.Empty(t, config.RemotesapiWebhooks())
+}
+
+func TestUnmarshallBackgroundConjoin(t *testing.T) {
+	testStr := `
+performance:
+  disable_background_conjoin: true
+  background_conjoin_interval_secs: 300
+  background_conjoin_bytes_per_sec: 1048576
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	assert.True(t, config.DisableBackgroundConjoin())
+	assert.Equal(t, 300, config.BackgroundConjoinIntervalSecs())
+	assert.Equal(t, 1048576, config.BackgroundConjoinBytesPerSec())
+
+	config, err = NewYamlConfig([]byte{})
+	require.NoError(t, err)
+	assert.False(t, config.DisableBackgroundConjoin())
+	assert.Equal(t, 0, config.BackgroundConjoinIntervalSecs())
+	assert.Equal(t, 0, config.BackgroundConjoinBytesPerSec())
+}
+
+func TestUnmarshallCaches(t *testing.T) {
+	testStr := `
+performance:
+  result_cache_entries: 512
+  result_cache_max_rows: 1000
+  plan_cache_entries: 64
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	assert.Equal(t, 512, config.ResultCacheEntries())
+	assert.Equal(t, 1000, config.ResultCacheMaxRows())
+	assert.Equal(t, 64, config.PlanCacheEntries())
+
+	config, err = NewYamlConfig([]byte{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, config.ResultCacheEntries())
+	assert.Equal(t, 0, config.ResultCacheMaxRows())
+	assert.Equal(t, 0, config.PlanCacheEntries())
+}
+
+func TestUnmarshallPlugins(t *testing.T) {
+	testStr := `
+plugins:
+  - /usr/lib/dolt/geo.so
+  - /usr/lib/dolt/text.so
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/usr/lib/dolt/geo.so", "/usr/lib/dolt/text.so"}, config.Plugins())
+
+	config, err = NewYamlConfig([]byte{})
+	require.NoError(t, err)
+	assert.Empty(t, config.Plugins())
+}
+
+func TestUnmarshallMergeDeleteSource(t *testing.T) {
+	testStr := `
+behavior:
+  merge_delete_source: true
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	assert.True(t, config.MergeDeleteSource())
+
+	config, err = NewYamlConfig([]byte{})
+	require.NoError(t, err)
+	assert.False(t, config.MergeDeleteSource())
+}
+
 func TestUnmarshallCluster(t *testing.T) {
 	testStr := `
 cluster:
@@ -415,3 +669,20 @@ listener:
 	err = ValidateConfig(cfg)
 	assert.Error(t, err)
 }
+
+func TestUnmarshallEventScheduler(t *testing.T) {
+	testStr := `
+behavior:
+  event_scheduler: true
+  event_scheduler_branch: maintenance
+`
+	config, err := NewYamlConfig([]byte(testStr))
+	require.NoError(t, err)
+	assert.True(t, config.EventScheduler())
+	assert.Equal(t, "maintenance", config.EventSchedulerBranch())
+
+	config, err = NewYamlConfig([]byte{})
+	require.NoError(t, err)
+	assert.False(t, config.EventScheduler())
+	assert.Equal(t, "", config.EventSchedulerBranch())
+}
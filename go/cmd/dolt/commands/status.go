@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -57,6 +58,9 @@ type printData struct {
 	dataConflictTables,
 	schemaConflictTables map[string]bool
 
+	// brokenViews maps the name of each view that no longer resolves to the reason it doesn't
+	brokenViews map[string]string
+
 	ignorePatterns doltdb.IgnorePatterns
 	ignoredTables  doltdb.IgnoredTables
 }
@@ -93,6 +97,7 @@ func (cmd StatusCmd) Docs() *cli.CommandDocumentation {
 func (cmd StatusCmd) ArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
 	ap.SupportsFlag(cli.ShowIgnoredFlag, "", "Show tables that are ignored (according to dolt_ignore)")
+	supportsJsonFormat(ap)
 	return ap
 }
 
@@ -103,6 +108,10 @@ func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string,
 	help, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, statusDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 	showIgnoredTables := apr.Contains(cli.ShowIgnoredFlag)
+	outputJson, err := isJsonFormat(apr)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
 
 	// configure SQL engine
 	queryist, sqlCtx, closeFunc, err := cliCtx.QueryEngine(ctx)
@@ -119,7 +128,11 @@ func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return handleStatusVErr(err)
 	}
 
-	err = printEverything(pd)
+	if outputJson {
+		err = printStatusJson(pd)
+	} else {
+		err = printEverything(pd)
+	}
 	if err != nil {
 		return handleStatusVErr(err)
 	}
@@ -168,6 +181,11 @@ func createPrintData(err error, queryist cli.Queryist, sqlCtx *sql.Context, show
 		return nil, err
 	}
 
+	brokenViews, err := getBrokenViews(queryist, sqlCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	statusRows, err := getRowsForSql(queryist, sqlCtx, "select * from dolt_status;")
 	if err != nil {
 		return nil, err
@@ -298,6 +316,7 @@ func createPrintData(err error, queryist cli.Queryist, sqlCtx *sql.Context, show
 		schemaConflictTables:      schemaConflictTables,
 		ignorePatterns:            ignorePatterns,
 		dataConflictTables:        dataConflictTables,
+		brokenViews:               brokenViews,
 	}
 	return &pd, nil
 }
@@ -421,6 +440,42 @@ func getConstraintViolationTables(queryist cli.Queryist, sqlCtx *sql.Context) (m
 	return constraintViolationTables, nil
 }
 
+// getBrokenViews returns the views of the current database whose definitions no longer resolve, mapped to the reason
+// they don't. A view breaks when a table or column it depends on is dropped or renamed, including by a merge.
+func getBrokenViews(queryist cli.Queryist, sqlCtx *sql.Context) (map[string]string, error) {
+	views, err := getRowsForSql(queryist, sqlCtx, "select table_name from information_schema.views where table_schema = database();")
+	if err != nil {
+		return nil, err
+	}
+
+	brokenViews := make(map[string]string)
+	for _, row := range views {
+		viewName := row[0].(string)
+		_, err := getRowsForSql(queryist, sqlCtx, fmt.Sprintf("select * from %s limit 0;", sql.QuoteIdentifier(viewName)))
+		if err != nil {
+			brokenViews[viewName] = err.Error()
+		}
+	}
+	return brokenViews, nil
+}
+
+// printBrokenViews prints the views in |brokenViews|, sorted by name.
+func printBrokenViews(brokenViews map[string]string) {
+	viewNames := make([]string, 0, len(brokenViews))
+	for viewName := range brokenViews {
+		viewNames = append(viewNames, viewName)
+	}
+	sort.Strings(viewNames)
+
+	cli.Println(brokenViewsHeader)
+	cli.Println(brokenViewsHeaderHelp)
+	for _, viewName := range viewNames {
+		text := fmt.Sprintf(statusFmt, brokenViewLabel, viewName) + fmt.Sprintf(" (%s)", brokenViews[viewName])
+		redText := color.RedString(text)
+		cli.Println(redText)
+	}
+}
+
 func getWorkingStagedTables(queryist cli.Queryist, sqlCtx *sql.Context) (map[string]bool, map[string]bool, error) {
 	stagedTableNames := make(map[string]bool)
 	workingTableNames := make(map[string]bool)
@@ -638,6 +693,17 @@ and have %v and %v different commits each, respectively.
 		}
 	}
 
+	// views with missing dependencies
+	if len(data.brokenViews) > 0 {
+		if changesPresent {
+			cli.Println()
+		}
+		printBrokenViews(data.brokenViews)
+		if !changesPresent {
+			cli.Println()
+		}
+	}
+
 	// nothing to commit
 	if !changesPresent {
 		cli.Println("nothing to commit, working tree clean")
@@ -646,6 +712,108 @@ and have %v and %v different commits each, respectively.
 	return nil
 }
 
+// statusJson is the output of dolt status --format json
+type statusJson struct {
+	Branch string `json:"branch"`
+	// Upstream is null if the branch doesn't track a remote branch
+	Upstream    *statusUpstreamJson `json:"upstream"`
+	MergeActive bool                `json:"merge_active"`
+	Staged      []statusTableJson   `json:"staged"`
+	Unstaged    []statusTableJson   `json:"unstaged"`
+	Untracked   []statusTableJson   `json:"untracked"`
+	// Unmerged holds the tables with conflicts or constraint violations
+	Unmerged []statusTableJson `json:"unmerged"`
+	// Ignored is only filled in when --ignored is given
+	Ignored     []string               `json:"ignored"`
+	BrokenViews []statusBrokenViewJson `json:"broken_views"`
+}
+
+type statusUpstreamJson struct {
+	Remote string `json:"remote"`
+	Branch string `json:"branch"`
+	Ahead  int64  `json:"ahead"`
+	Behind int64  `json:"behind"`
+}
+
+type statusTableJson struct {
+	Table  string `json:"table"`
+	Status string `json:"status"`
+}
+
+type statusBrokenViewJson struct {
+	View  string `json:"view"`
+	Error string `json:"error"`
+}
+
+// printStatusJson prints the status in |data| as JSON, with the same tables in each category as printEverything.
+// Tables are sorted by name.
+func printStatusJson(data *printData) error {
+	out := statusJson{
+		Branch:      data.branchName,
+		MergeActive: data.mergeActive,
+		Staged:      []statusTableJson{},
+		Unstaged:    []statusTableJson{},
+		Untracked:   []statusTableJson{},
+		Unmerged:    []statusTableJson{},
+		Ignored:     []string{},
+		BrokenViews: []statusBrokenViewJson{},
+	}
+	if data.remoteName != "" {
+		out.Upstream = &statusUpstreamJson{
+			Remote: data.remoteName,
+			Branch: data.remoteBranchName,
+			Ahead:  data.ahead,
+			Behind: data.behind,
+		}
+	}
+
+	for tableName, status := range data.stagedTables {
+		if !doltdb.IsReadOnlySystemTable(tableName) {
+			out.Staged = append(out.Staged, statusTableJson{Table: tableName, Status: status})
+		}
+	}
+	if data.conflictsPresent {
+		for tableName := range data.schemaConflictTables {
+			out.Unmerged = append(out.Unmerged, statusTableJson{Table: tableName, Status: "schema conflict"})
+		}
+		for tableName := range data.dataConflictTables {
+			out.Unmerged = append(out.Unmerged, statusTableJson{Table: tableName, Status: "both modified"})
+		}
+	}
+	for tableName := range data.constraintViolationTables {
+		if !data.dataConflictTables[tableName] && !data.schemaConflictTables[tableName] {
+			out.Unmerged = append(out.Unmerged, statusTableJson{Table: tableName, Status: "constraint violation"})
+		}
+	}
+	for tableName, status := range data.unstagedTables {
+		hasConflicts := data.dataConflictTables[tableName] || data.schemaConflictTables[tableName]
+		if !hasConflicts && !data.constraintViolationTables[tableName] {
+			out.Unstaged = append(out.Unstaged, statusTableJson{Table: tableName, Status: status})
+		}
+	}
+	for tableName, status := range data.filteredUntrackedTables {
+		out.Untracked = append(out.Untracked, statusTableJson{Table: tableName, Status: status})
+	}
+	if data.showIgnoredTables {
+		out.Ignored = append(out.Ignored, data.ignoredTables.Ignore...)
+		sort.Strings(out.Ignored)
+	}
+	for viewName, viewErr := range data.brokenViews {
+		out.BrokenViews = append(out.BrokenViews, statusBrokenViewJson{View: viewName, Error: viewErr})
+	}
+
+	for _, tables := range [][]statusTableJson{out.Staged, out.Unstaged, out.Untracked, out.Unmerged} {
+		sort.Slice(tables, func(i, j int) bool {
+			return tables[i].Table < tables[j].Table
+		})
+	}
+	sort.Slice(out.BrokenViews, func(i, j int) bool {
+		return out.BrokenViews[i].View < out.BrokenViews[j].View
+	})
+
+	return printJson(out)
+}
+
 func handleStatusVErr(err error) int {
 	cli.PrintErrln(errhand.VerboseErrorFromError(err).Verbose())
 	return 1
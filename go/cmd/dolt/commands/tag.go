@@ -112,7 +112,7 @@ func (cmd TagCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 	} else if len(apr.Args) > 2 {
 		verr = errhand.BuildDError("create tag takes at most two args").Build()
 	} else {
-		props, err := getTagProps(dEnv, apr)
+		props, err := getTagProps(ctx, dEnv, apr)
 		if err != nil {
 			verr = errhand.BuildDError("failed to get tag props").AddCause(err).Build()
 			return HandleVErrAndExitCode(verr, usage)
@@ -131,7 +131,7 @@ func (cmd TagCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 	return HandleVErrAndExitCode(verr, usage)
 }
 
-func getTagProps(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (props actions.TagProps, err error) {
+func getTagProps(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (props actions.TagProps, err error) {
 	var name, email string
 	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
 		name, email, err = cli.ParseAuthor(authorStr)
@@ -144,10 +144,16 @@ func getTagProps(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (props actio
 
 	msg, _ := apr.GetValue(cli.MessageArg)
 
+	sign, err := env.GetSignFunc(ctx, dEnv.Config, env.TagGpgSign, apr.Contains(cli.SignFlag), apr.Contains(cli.NoSignFlag))
+	if err != nil {
+		return props, err
+	}
+
 	props = actions.TagProps{
 		TaggerName:  name,
 		TaggerEmail: email,
 		Description: msg,
+		Sign:        sign,
 	}
 
 	return props, nil
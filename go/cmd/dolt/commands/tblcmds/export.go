@@ -29,8 +29,10 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/mvdata"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
@@ -41,17 +43,27 @@ var exportDocs = cli.CommandDocumentationContent{
 	LongDesc: `{{.EmphasisLeft}}dolt table export{{.EmphasisRight}} will export the contents of {{.LessThan}}table{{.GreaterThan}} to {{.LessThan}}|file{{.GreaterThan}}
 
 See the help for {{.EmphasisLeft}}dolt table import{{.EmphasisRight}} as the options are the same.
+
+Tables can be exported as JSON Lines (.jsonl or .ndjson files, or stdout with {{.EmphasisLeft}}--file-type jsonl{{.EmphasisRight}}), with the JSON object of each row on its own line. Rows are written as they are read, so tables of any size can be exported. The same {{.EmphasisLeft}}--flatten{{.EmphasisRight}} file that is used to import a JSON Lines file can be used to export it: columns that are read from a JSONPath are written to that path, and the fields of the remainder column are written to the object of each row.
+
+Spatial columns are written to csv and psv files as 0x followed by the hex of each value in MySQL's internal format, unless another format is chosen with {{.EmphasisLeft}}--spatial-format{{.EmphasisRight}}: wkt writes well-known text, prefixed with SRID=<srid>; for values whose SRID isn't 0, and wkb writes the hex of well-known binary, which is PostGIS's EWKB for values whose SRID isn't 0. Coordinates are written in x y order, which is longitude latitude order for geographic SRIDs. Spatial columns are written to json and jsonl files as GeoJSON geometry objects, which have a crs member naming their SRID if it isn't 0.
+
+Tables with a spatial column can be exported as a GeoJSON FeatureCollection (.geojson files, or stdout with {{.EmphasisLeft}}--file-type geojson{{.EmphasisRight}}). Each row is a feature whose geometry is the first spatial column of the table, and whose properties are the other columns.
 `,
 	Synopsis: []string{
-		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] [--flatten {{.LessThan}}file{{.GreaterThan}}] [--spatial-format {{.LessThan}}format{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
 	},
 }
 
+const spatialFormatParam = "spatial-format"
+
 type exportOptions struct {
-	tableName  string
-	force      bool
-	dest       mvdata.DataLocation
-	srcOptions interface{}
+	tableName     string
+	force         bool
+	dest          mvdata.DataLocation
+	srcOptions    interface{}
+	flatten       *json.FlattenSpec
+	spatialFormat sqlutil.SpatialFormat
 }
 
 func (m exportOptions) checkOverwrite(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
@@ -76,6 +88,14 @@ func (m exportOptions) WritesToTable() bool {
 	return false
 }
 
+func (m exportOptions) FlattenSpec() *json.FlattenSpec {
+	return m.flatten
+}
+
+func (m exportOptions) SpatialFormat() sqlutil.SpatialFormat {
+	return m.spatialFormat
+}
+
 func (m exportOptions) SrcName() string {
 	return m.tableName
 }
@@ -110,7 +130,7 @@ func getExportDestination(apr *argparser.ArgParseResults) mvdata.DataLocation {
 		if val.Format == mvdata.InvalidDataFormat {
 			val = mvdata.StreamDataLocation{Format: mvdata.CsvFile, Reader: os.Stdin, Writer: iohelp.NopWrCloser(cli.CliOut)}
 			destLoc = val
-		} else if val.Format != mvdata.CsvFile && val.Format != mvdata.PsvFile {
+		} else if val.Format != mvdata.CsvFile && val.Format != mvdata.PsvFile && val.Format != mvdata.JsonlFile && val.Format != mvdata.GeoJsonFile {
 			cli.PrintErrln(color.RedString("Cannot export this format to stdout"))
 			return nil
 		}
@@ -119,7 +139,7 @@ func getExportDestination(apr *argparser.ArgParseResults) mvdata.DataLocation {
 	return destLoc
 }
 
-func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string) (*exportOptions, errhand.VerboseError) {
+func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string, fs filesys.ReadableFS) (*exportOptions, errhand.VerboseError) {
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, exportDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
@@ -144,10 +164,36 @@ func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string)
 		return nil, errhand.BuildDError("could not validate table export args").Build()
 	}
 
+	var flattenSpec *json.FlattenSpec
+	if flattenFile, ok := apr.GetValue(flattenParam); ok {
+		if fileFormat(fileLoc) != mvdata.JsonlFile {
+			return nil, errhand.BuildDError("fatal: --%s is only supported for jsonl files", flattenParam).Build()
+		}
+		var err error
+		flattenSpec, err = json.FlattenSpecFromFile(flattenFile, fs)
+		if err != nil {
+			return nil, errhand.BuildDError("error: failed to read flatten file").AddCause(err).Build()
+		}
+	}
+
+	spatialFormat := sqlutil.SpatialHex
+	if f, ok := apr.GetValue(spatialFormatParam); ok {
+		if format := fileFormat(fileLoc); format != mvdata.CsvFile && format != mvdata.PsvFile {
+			return nil, errhand.BuildDError("fatal: --%s is only supported for csv and psv files", spatialFormatParam).Build()
+		}
+		var err error
+		spatialFormat, err = sqlutil.SpatialFormatFromString(f)
+		if err != nil {
+			return nil, errhand.BuildDError("fatal: invalid --%s", spatialFormatParam).AddCause(err).Build()
+		}
+	}
+
 	return &exportOptions{
-		tableName: tableName,
-		force:     apr.Contains(forceParam),
-		dest:      fileLoc,
+		tableName:     tableName,
+		force:         apr.Contains(forceParam),
+		dest:          fileLoc,
+		flatten:       flattenSpec,
+		spatialFormat: spatialFormat,
 	}, nil
 }
 
@@ -174,6 +220,8 @@ func (cmd ExportCmd) ArgParser() *argparser.ArgParser {
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "The file being output to."})
 	ap.SupportsFlag(forceParam, "f", "If data already exists in the destination, the force flag will allow the target to be overwritten.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsString(flattenParam, "", "flatten_file", "A file that lays out how columns are written to the nested fields of a jsonl file.")
+	ap.SupportsString(spatialFormatParam, "", "format", "The format spatial columns are written to csv and psv files in: hex, wkt or wkb. Defaults to hex.")
 	return ap
 }
 
@@ -187,7 +235,7 @@ func (cmd ExportCmd) Exec(ctx context.Context, commandStr string, args []string,
 	ap := cmd.ArgParser()
 	_, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, exportDocs, ap))
 
-	exOpts, verr := parseExportArgs(ap, commandStr, args)
+	exOpts, verr := parseExportArgs(ap, commandStr, args, dEnv.FS)
 	if verr != nil {
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
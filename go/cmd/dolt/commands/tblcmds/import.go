@@ -16,15 +16,18 @@ package tblcmds
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
 	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/dolthub/vitess/go/sqltypes"
 	"github.com/fatih/color"
@@ -44,6 +47,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fixedwidth"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/funcitr"
@@ -65,6 +70,13 @@ const (
 	primaryKeyParam   = "pk"
 	fileTypeParam     = "file-type"
 	delimParam        = "delim"
+	quoteParam        = "quote"
+	escapeParam       = "escape"
+	encodingParam     = "encoding"
+	badRowsParam      = "bad-rows"
+	flattenParam      = "flatten"
+	columnTypesParam  = "column-types"
+	layoutParam       = "layout"
 	quiet             = "quiet"
 	ignoreSkippedRows = "ignore-skipped-rows" // alias for quiet
 	disableFkChecks   = "disable-fk-checks"
@@ -82,6 +94,38 @@ var jsonInputFileHelp = "The expected JSON input file format is:" + `
 	}
 
 where column_name is the name of a column of the table being imported and value is the data for that column in the table.
+
+JSON Lines files (.jsonl or .ndjson) have a JSON object on each line, and can also be read from stdin with {{.EmphasisLeft}}--file-type jsonl{{.EmphasisRight}}. Nested fields of each object can be mapped to columns with {{.EmphasisLeft}}--flatten{{.EmphasisRight}}, which takes a json file in the format:
+
+	{
+		"columns": {"user_id": "$.user.id", "first_tag": "$.tags[0]"},
+		"flatten": true,
+		"separator": "_",
+		"remainder": "extra"
+	}
+
+where columns sets a column to the value at a JSONPath, which may use member (.key or ['key']) and array index ([0]) selectors. If flatten is true, nested objects are flattened into columns named by joining the keys on the path to each field with separator, which defaults to '_', so {"user": {"name": "x"}} is imported to the column user_name. Fields that are not imported to any column are collected into a JSON object that is written to the remainder column. Lines with fields that are not imported to any column are rejected when there is no remainder column.
+`
+
+var fixedWidthHelp = "Files of fixed-width records without delimiters, like mainframe extracts, are imported by giving the positions of their fields with {{.EmphasisLeft}}--layout{{.EmphasisRight}}, which implies {{.EmphasisLeft}}--file-type fixed-width{{.EmphasisRight}}. The layout is either a COBOL copybook, or a json file in the format:" + `
+
+	{
+		"columns": [
+			{"name": "id", "start": 1, "width": 6, "type": "int"},
+			{"name": "name", "width": 20},
+			{"name": "balance", "width": 9, "type": "decimal(9,2)", "scale": 2, "signed": true}
+		],
+		"record_length": 0
+	}
+
+where start is the 1-based position of the first character of a field, which defaults to the position after the previous field, and type is the type of the column when a table is created. Numeric fields with a scale have that many implied decimal places, and signed fields may have a separate leading or trailing sign, or a sign overpunched on their first or last digit. Each line of the file is a record, unless record_length is set, in which case the file is a sequence of records of that many characters. Fields are imported without their leading and trailing spaces, and fields that are all spaces are null.
+
+A copybook describes a single record, whose elementary items are imported to columns named after the items in lower case with hyphens replaced by underscores. Items that occur more than once are numbered, e.g. phone_1 and phone_2, and FILLER items and items that redefine others are skipped. The types of the columns are derived from the PICTURE clauses of the items, and only items with DISPLAY usage can be imported. Files encoded in EBCDIC can be imported with {{.EmphasisLeft}}--encoding cp037{{.EmphasisRight}} or {{.EmphasisLeft}}--encoding cp1047{{.EmphasisRight}}.
+`
+
+var spatialHelp = `Spatial columns can be imported from csv, psv, json and jsonl files as well-known text, e.g. POINT(1 2), which may be prefixed with the SRID of the value, e.g. SRID=4326;POINT(-71.06 42.36), as the hex of well-known binary or PostGIS's EWKB, as a GeoJSON geometry object, or as 0x followed by the hex of the value in MySQL's internal format, which is how spatial values are exported by default. Coordinates are in x y order, which is longitude latitude order for geographic SRIDs. Values that don't give their SRID get the SRID of their column, or 0 if the column doesn't define one. GeoJSON objects give their SRID with a crs member that names it, e.g. {"type": "name", "properties": {"name": "EPSG:4326"}}.
+
+GeoJSON files (.geojson) are imported from a FeatureCollection, with a row for each feature. The geometry of a feature is imported to the first spatial column of the table, and its properties are imported to the columns with the same names. A crs of the collection or of a feature applies to its geometries that don't have their own. As with json files, a schema file is required to create a table from a GeoJSON file.
 `
 
 var importDocs = cli.CommandDocumentationContent{
@@ -102,20 +146,46 @@ A mapping file can be used to map fields between the file being imported and the
 
 During import, if there is an error importing any row, the import will be aborted by default. Use the {{.EmphasisLeft}}--continue{{.EmphasisRight}} flag to continue importing when an error is encountered. You can add the {{.EmphasisLeft}}--quiet{{.EmphasisRight}} flag to prevent the import utility from printing all the skipped rows. 
 
+Use {{.EmphasisLeft}}--bad-rows{{.EmphasisRight}} to write the rows that could not be imported to a file instead, and continue importing. Each line of the file is a csv record holding the values of a rejected row followed by the reason it was rejected. Malformed lines of csv and psv files, like those with the wrong number of fields or an unterminated quote, are rejected in the same way, and are ignored when inferring the schema of a new table.
+
+When a table is created with an inferred schema, the types of some of its columns can be chosen with {{.EmphasisLeft}}--column-types{{.EmphasisRight}}, which takes a comma separated list of columns and their types, e.g. {{.EmphasisLeft}}--column-types "zip:varchar(10),price:decimal(10,2)"{{.EmphasisRight}}. The types of the other columns are inferred.
+
 ` + schcmds.MappingFileHelp +
 		`
+` + mappingSpecHelp +
+		`
 ` + jsonInputFileHelp +
 		`
-In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (csv, psv, json, xlsx).  For files separated by a delimiter other than a ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimiter`,
+` + fixedWidthHelp +
+		`
+` + spatialHelp +
+		`
+In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (csv, psv, json, jsonl, geojson, xlsx, fixed-width).  For files separated by a delimiter other than a ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimiter, which may be given as '\t' for tab separated files. Fields of csv and psv files are quoted with '"' unless another character is given with {{.EmphasisLeft}}--quote{{.EmphasisRight}}, and a quote inside a quoted field is escaped by doubling it, or by preceding it with the character given with {{.EmphasisLeft}}--escape{{.EmphasisRight}}. Files that are not UTF-8 can be imported by naming their character encoding with {{.EmphasisLeft}}--encoding{{.EmphasisRight}}, e.g. latin1, windows-1252, shift_jis, or utf-16. A byte order mark at the start of a file takes precedence over the encoding, so utf-16 files are read correctly whichever byte order they were written in.`,
 
 	Synopsis: []string{
-		"-c [-f] [--pk {{.LessThan}}field{{.GreaterThan}}] [--schema {{.LessThan}}file{{.GreaterThan}}] [--map {{.LessThan}}file{{.GreaterThan}}] [--continue]  [--quiet] [--disable-fk-checks] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
-		"-u [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--quiet] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
-		"-a [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--quiet] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
-		"-r [--map {{.LessThan}}file{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-c [-f] [--pk {{.LessThan}}field{{.GreaterThan}}] [--schema {{.LessThan}}file{{.GreaterThan}} | --column-types {{.LessThan}}types{{.GreaterThan}}] [--map {{.LessThan}}file{{.GreaterThan}}] [--continue]  [--quiet] [--bad-rows {{.LessThan}}file{{.GreaterThan}}] [--disable-fk-checks] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-u [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--quiet] [--bad-rows {{.LessThan}}file{{.GreaterThan}}] [--flatten {{.LessThan}}file{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-a [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--quiet] [--bad-rows {{.LessThan}}file{{.GreaterThan}}] [--flatten {{.LessThan}}file{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-r [--map {{.LessThan}}file{{.GreaterThan}}] [--flatten {{.LessThan}}file{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
 	},
 }
 
+var mappingSpecHelp = "For more control over how data is imported, a mapping file can instead be json in the format:" + `
+
+	{
+		"columns": {
+			"id": {"name": "pk", "type": "bigint"},
+			"first_name": "first",
+			"state": {"transform": "upper"},
+			"notes": {"skip": true}
+		},
+		"defaults": {"source": "crm-export"},
+		"expressions": {"full_name": "${first_name} ${last_name}"}
+	}
+
+where each entry in columns is either the name of the dest field, or an object that may rename the field (name), cast its values to a sql type (type), apply one of the transforms upper, lower or trim (transform), or leave the field out of the import (skip). Fields not listed in columns are imported with their own name. defaults sets dest fields to a constant value, and expressions sets dest fields to a template in which ${field} is replaced by the value of a source field. When a table is created, fields with a type are created with that type. The mapping file is checked against the file being imported before any rows are written.
+`
+
 var bitTypeRegex = regexp.MustCompile(`(?m)b\'(\d+)\'`)
 
 type importOptions struct {
@@ -126,10 +196,15 @@ type importOptions struct {
 	schFile         string
 	primaryKeys     []string
 	nameMapper      rowconv.NameMapper
+	mappingFile     string
+	mappingSpec     *rowconv.MappingSpec
 	src             mvdata.DataLocation
 	srcOptions      interface{}
 	quiet           bool
 	disableFkChecks bool
+	badRowsFile     string
+	badRows         *badRowWriter
+	columnTypes     map[string]sql.Type
 }
 
 func (m importOptions) IsBatched() bool {
@@ -184,13 +259,23 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 		path = apr.Arg(1)
 	}
 
-	fType, _ := apr.GetValue(fileTypeParam)
-	srcLoc := mvdata.NewDataLocation(path, fType)
+	srcLoc := mvdata.NewDataLocation(path, importFileType(apr))
 	delim, hasDelim := apr.GetValue(delimParam)
+	if delim == `\t` {
+		delim = "\t"
+	}
+	csvOpts := mvdata.CsvOptions{
+		Delim:    delim,
+		Quote:    apr.GetValueOrDefault(quoteParam, ""),
+		Escape:   apr.GetValueOrDefault(escapeParam, ""),
+		Encoding: apr.GetValueOrDefault(encodingParam, ""),
+	}
+	hasCsvOpts := apr.ContainsAny(delimParam, quoteParam, escapeParam, encodingParam)
 
 	schemaFile, _ := apr.GetValue(schemaParam)
 	force := apr.Contains(forceParam)
-	contOnErr := apr.Contains(contOnErrParam)
+	badRowsFile := apr.GetValueOrDefault(badRowsParam, "")
+	contOnErr := apr.Contains(contOnErrParam) || badRowsFile != ""
 	quiet := apr.Contains(quiet)
 	disableFks := apr.Contains(disableFkChecks)
 
@@ -199,30 +284,69 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 	pks = funcitr.FilterStrings(pks, func(s string) bool { return s != "" })
 
 	mappingFile := apr.GetValueOrDefault(mappingFileParam, "")
-	colMapper, err := rowconv.NameMapperFromFile(mappingFile, dEnv.FS)
+	mappingSpec, err := rowconv.MappingSpecFromFile(mappingFile, dEnv.FS)
 	if err != nil {
 		return nil, errhand.VerboseErrorFromError(err)
 	}
 
+	// mappings that only rename columns are applied by name, anything more is applied by wrapping the reader
+	colMapper := mappingSpec.NameMapper()
+	if mappingSpec.IsNameMappingOnly() {
+		mappingSpec = nil
+	} else {
+		colMapper = make(rowconv.NameMapper)
+	}
+
+	var columnTypes map[string]sql.Type
+	if typesStr, ok := apr.GetValue(columnTypesParam); ok {
+		columnTypes, err = parseColumnTypes(ctx, typesStr)
+		if err != nil {
+			return nil, errhand.BuildDError("error: invalid --%s", columnTypesParam).AddCause(err).Build()
+		}
+	}
+
+	var flattenSpec *json.FlattenSpec
+	if flattenFile, ok := apr.GetValue(flattenParam); ok {
+		flattenSpec, err = json.FlattenSpecFromFile(flattenFile, dEnv.FS)
+		if err != nil {
+			return nil, errhand.BuildDError("error: failed to read flatten file").AddCause(err).Build()
+		}
+	}
+
+	var layout *fixedwidth.Layout
+	if layoutFile, ok := apr.GetValue(layoutParam); ok {
+		layout, err = fixedwidth.LayoutFromFile(layoutFile, dEnv.FS)
+		if err != nil {
+			return nil, errhand.BuildDError("error: failed to read layout file").AddCause(err).Build()
+		}
+		columnTypes, err = addLayoutTypes(ctx, columnTypes, layout)
+		if err != nil {
+			return nil, errhand.BuildDError("error: invalid layout file").AddCause(err).Build()
+		}
+	}
+	fwOpts := mvdata.FixedWidthOptions{Layout: layout, Encoding: csvOpts.Encoding}
+
 	var srcOpts interface{}
 	switch val := srcLoc.(type) {
 	case mvdata.FileDataLocation:
-		if hasDelim {
-			if val.Format == mvdata.InvalidDataFormat {
-				val = mvdata.FileDataLocation{Path: val.Path, Format: mvdata.CsvFile}
-				srcLoc = val
-			}
+		if hasDelim && val.Format == mvdata.InvalidDataFormat {
+			val = mvdata.FileDataLocation{Path: val.Path, Format: mvdata.CsvFile}
+			srcLoc = val
+		}
 
-			srcOpts = mvdata.CsvOptions{Delim: delim}
+		if hasCsvOpts {
+			srcOpts = csvOpts
 		}
 
 		if val.Format == mvdata.XlsxFile {
 			// table name must match sheet name currently
 			srcOpts = mvdata.XlsxOptions{SheetName: tableName}
-		} else if val.Format == mvdata.JsonFile {
-			srcOpts = mvdata.JSONOptions{TableName: tableName, SchFile: schemaFile}
+		} else if val.Format == mvdata.JsonFile || val.Format == mvdata.JsonlFile || val.Format == mvdata.GeoJsonFile {
+			srcOpts = mvdata.JSONOptions{TableName: tableName, SchFile: schemaFile, Flatten: flattenSpec}
 		} else if val.Format == mvdata.ParquetFile {
 			srcOpts = mvdata.ParquetOptions{TableName: tableName, SchFile: schemaFile}
+		} else if val.Format == mvdata.FixedWidthFile {
+			srcOpts = fwOpts
 		}
 
 	case mvdata.StreamDataLocation:
@@ -231,8 +355,14 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 			srcLoc = val
 		}
 
-		if hasDelim {
-			srcOpts = mvdata.CsvOptions{Delim: delim}
+		if hasCsvOpts {
+			srcOpts = csvOpts
+		}
+
+		if val.Format == mvdata.JsonlFile || val.Format == mvdata.GeoJsonFile {
+			srcOpts = mvdata.JSONOptions{TableName: tableName, SchFile: schemaFile, Flatten: flattenSpec}
+		} else if val.Format == mvdata.FixedWidthFile {
+			srcOpts = fwOpts
 		}
 	}
 
@@ -269,11 +399,15 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 		force:           force,
 		schFile:         schemaFile,
 		nameMapper:      colMapper,
+		mappingFile:     mappingFile,
+		mappingSpec:     mappingSpec,
 		primaryKeys:     pks,
 		src:             srcLoc,
 		srcOptions:      srcOpts,
 		quiet:           quiet,
 		disableFkChecks: disableFks,
+		badRowsFile:     badRowsFile,
+		columnTypes:     columnTypes,
 	}, nil
 
 }
@@ -299,6 +433,15 @@ func validateImportArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
 		return errhand.BuildDError("fatal: " + schemaParam + " is not supported for update or replace operations").Build()
 	}
 
+	if apr.Contains(columnTypesParam) {
+		if !apr.Contains(createParam) {
+			return errhand.BuildDError("fatal: --%s is only supported when creating a table", columnTypesParam).Build()
+		}
+		if apr.Contains(schemaParam) {
+			return errhand.BuildDError("parameters %s and %s are mutually exclusive", schemaParam, columnTypesParam).Build()
+		}
+	}
+
 	tableName := apr.Arg(0)
 	if err := schcmds.ValidateTableNameForCreate(tableName); err != nil {
 		return err
@@ -313,6 +456,7 @@ func validateImportArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
 	if hasFileType && mvdata.DFFromString(fType) == mvdata.InvalidDataFormat {
 		return errhand.BuildDError("'%s' is not a valid file type.", fType).Build()
 	}
+	fType = importFileType(apr)
 
 	_, hasDelim := apr.GetValue(delimParam)
 	srcLoc := mvdata.NewDataLocation(path, fType)
@@ -324,6 +468,18 @@ func validateImportArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
 		}
 	}
 
+	if apr.Contains(flattenParam) {
+		if fileFormat(srcLoc) != mvdata.JsonlFile {
+			return errhand.BuildDError("fatal: --%s is only supported for jsonl files", flattenParam).Build()
+		}
+	}
+
+	if fileFormat(srcLoc) == mvdata.FixedWidthFile && !apr.Contains(layoutParam) {
+		return errhand.BuildDError("fatal: --%s is required to import fixed-width files", layoutParam).Build()
+	} else if fileFormat(srcLoc) != mvdata.FixedWidthFile && apr.Contains(layoutParam) {
+		return errhand.BuildDError("fatal: --%s is only supported for fixed-width files", layoutParam).Build()
+	}
+
 	if srcFileLoc, isFileType := srcLoc.(mvdata.FileDataLocation); isFileType {
 		if srcFileLoc.Format == mvdata.SqlFile {
 			return errhand.BuildDError("For SQL import, please pipe SQL input files to `dolt sql`").Build()
@@ -332,6 +488,10 @@ func validateImportArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
 		_, hasSchema := apr.GetValue(schemaParam)
 		if srcFileLoc.Format == mvdata.JsonFile && apr.Contains(createParam) && !hasSchema {
 			return errhand.BuildDError("Please specify schema file for .json tables.").Build()
+		} else if srcFileLoc.Format == mvdata.JsonlFile && apr.Contains(createParam) && !hasSchema {
+			return errhand.BuildDError("Please specify schema file for .jsonl tables.").Build()
+		} else if srcFileLoc.Format == mvdata.GeoJsonFile && apr.Contains(createParam) && !hasSchema {
+			return errhand.BuildDError("Please specify schema file for .geojson tables.").Build()
 		} else if srcFileLoc.Format == mvdata.ParquetFile && apr.Contains(createParam) && !hasSchema {
 			return errhand.BuildDError("Please specify schema file for .parquet tables.").Build()
 		}
@@ -375,6 +535,13 @@ func (cmd ImportCmd) ArgParser() *argparser.ArgParser {
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
 	ap.SupportsString(delimParam, "", "delimiter", "Specify a delimiter for a csv style file with a non-comma delimiter.")
+	ap.SupportsString(quoteParam, "", "character", "Specify the character used to quote fields of a csv style file. Defaults to '\"'.")
+	ap.SupportsString(escapeParam, "", "character", "Specify a character that escapes quotes inside quoted fields of a csv style file. By default quotes are escaped by doubling them.")
+	ap.SupportsString(encodingParam, "", "encoding", "Specify the character encoding of a csv style file that is not UTF-8.")
+	ap.SupportsString(badRowsParam, "", "file", "Write rows that can't be imported to {{.LessThan}}file{{.GreaterThan}}, along with the reason they were rejected, and continue importing.")
+	ap.SupportsString(flattenParam, "", "flatten_file", "A file that lays out how the nested fields of a jsonl file are mapped to columns.")
+	ap.SupportsString(layoutParam, "", "layout_file", "A json file or COBOL copybook that lays out the fields of the records of a fixed-width file.")
+	ap.SupportsString(columnTypesParam, "", "types", "A comma separated list of columns and their types, e.g. 'zip:varchar(10)', overriding the inferred types of the columns of a new table.")
 	return ap
 }
 
@@ -422,6 +589,14 @@ func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
+	if mvOpts.badRowsFile != "" {
+		mvOpts.badRows, err = newBadRowWriter(dEnv.FS, mvOpts.badRowsFile)
+		if err != nil {
+			verr = errhand.BuildDError("Unable to create bad rows file '%s'.", mvOpts.badRowsFile).AddCause(err).Build()
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+	}
+
 	wr, nDMErr := newImportSqlEngineMover(ctx, dEnv, rd.GetSchema(), mvOpts)
 	if nDMErr != nil {
 		verr = newDataMoverErrToVerr(mvOpts, nDMErr)
@@ -429,6 +604,11 @@ func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string,
 	}
 
 	skipped, err := move(ctx, rd, wr, mvOpts)
+	if mvOpts.badRows != nil {
+		if closeErr := mvOpts.badRows.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to write bad rows file '%s': %w", mvOpts.badRowsFile, closeErr)
+		}
+	}
 	if err != nil {
 		bdr := errhand.BuildDError("\nAn error occurred while moving data")
 		bdr.AddCause(err)
@@ -442,6 +622,9 @@ func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	if skipped > 0 {
 		cli.PrintErrln(color.YellowString("Lines skipped: %d", skipped))
+		if mvOpts.badRows != nil {
+			cli.PrintErrln(color.YellowString("Skipped rows were written to %s", mvOpts.badRowsFile))
+		}
 	}
 	cli.Println(color.CyanString("Import completed successfully."))
 
@@ -475,7 +658,21 @@ func newImportDataReader(ctx context.Context, root *doltdb.RootValue, dEnv *env.
 		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.CreateReaderErr, Cause: err}
 	}
 
-	return rd, nil
+	return impOpts.applyMappingSpec(ctx, rd)
+}
+
+// applyMappingSpec wraps |rd| in a reader that applies the mapping spec of these options, if there is one
+func (m importOptions) applyMappingSpec(ctx context.Context, rd table.SqlRowReader) (table.SqlRowReader, *mvdata.DataMoverCreationError) {
+	if m.mappingSpec == nil {
+		return rd, nil
+	}
+
+	mapped, err := mvdata.NewMappedReader(ctx, rd, m.mappingSpec)
+	if err != nil {
+		rd.Close(ctx)
+		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.MappingErr, Cause: err}
+	}
+	return mapped, nil
 }
 
 func newImportSqlEngineMover(ctx context.Context, dEnv *env.DoltEnv, rdSchema schema.Schema, imOpts *importOptions) (*mvdata.SqlEngineTableWriter, *mvdata.DataMoverCreationError) {
@@ -549,6 +746,14 @@ func move(ctx context.Context, rd table.SqlRowReader, wr *mvdata.SqlEngineTableW
 			return true
 		}
 
+		if options.badRows != nil {
+			if err := options.badRows.Write(row, err); err != nil {
+				rowErr = fmt.Errorf("failed to write bad rows file '%s': %w", options.badRowsFile, err)
+				return true
+			}
+			return false
+		}
+
 		// Don't log the skipped rows when asked to suppress warning output
 		if options.quiet {
 			return false
@@ -602,6 +807,52 @@ func move(ctx context.Context, rd table.SqlRowReader, wr *mvdata.SqlEngineTableW
 	return badCount, nil
 }
 
+// badRowWriter writes rows that could not be imported, and the reason they were rejected, to a csv file. It is safe
+// for concurrent use, as rows are rejected by both the reader and the writer of an import.
+type badRowWriter struct {
+	mu sync.Mutex
+	wr io.WriteCloser
+	cw *csv.Writer
+}
+
+func newBadRowWriter(fs filesys.WritableFS, path string) (*badRowWriter, error) {
+	wr, err := fs.OpenForWrite(path, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &badRowWriter{wr: wr, cw: csv.NewWriter(wr)}, nil
+}
+
+// Write writes |row| followed by the reason it was rejected, |rowErr|
+func (bw *badRowWriter) Write(row sql.Row, rowErr error) error {
+	record := make([]string, 0, len(row)+1)
+	for _, v := range row {
+		switch v := v.(type) {
+		case nil:
+			record = append(record, "")
+		case string:
+			record = append(record, v)
+		default:
+			record = append(record, fmt.Sprint(v))
+		}
+	}
+	record = append(record, strings.Join(strings.Fields(rowErr.Error()), " "))
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.cw.Write(record)
+}
+
+// Close flushes any buffered rows and closes the file
+func (bw *badRowWriter) Close() error {
+	bw.cw.Flush()
+	if err := bw.cw.Error(); err != nil {
+		bw.wr.Close()
+		return err
+	}
+	return bw.wr.Close()
+}
+
 func moveRows(
 	ctx context.Context,
 	wr *mvdata.SqlEngineTableWriter,
@@ -631,10 +882,16 @@ func moveRows(
 				return err
 			}
 		} else {
-			sqlRow, err = NameAndTypeTransform(sqlRow, wr.RowOperationSchema(), rdSqlSch, options.nameMapper)
-			if err != nil {
+			transformed, err := NameAndTypeTransform(sqlRow, wr.RowOperationSchema(), rdSqlSch, options.nameMapper)
+			if table.IsBadRow(err) {
+				if badRowCb(sqlRow, err) {
+					return err
+				}
+				continue
+			} else if err != nil {
 				return err
 			}
+			sqlRow = transformed
 
 			select {
 			case <-ctx.Done():
@@ -671,10 +928,14 @@ func getImportSchema(ctx context.Context, dEnv *env.DoltEnv, impOpts *importOpti
 			return nil, nil
 		}
 
-		rd, _, err := impOpts.src.NewReader(ctx, root, dEnv.FS, impOpts.srcOptions)
+		srcRd, _, err := impOpts.src.NewReader(ctx, root, dEnv.FS, impOpts.srcOptions)
 		if err != nil {
 			return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.CreateReaderErr, Cause: err}
 		}
+		rd, dmce := impOpts.applyMappingSpec(ctx, srcRd)
+		if dmce != nil {
+			return nil, dmce
+		}
 		defer rd.Close(ctx)
 
 		if impOpts.srcIsJson() {
@@ -686,6 +947,18 @@ func getImportSchema(ctx context.Context, dEnv *env.DoltEnv, impOpts *importOpti
 			return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
 		}
 
+		if impOpts.mappingSpec != nil {
+			outSch, err = mvdata.ApplyMappingTypes(ctx, outSch, impOpts.mappingSpec)
+			if err != nil {
+				return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
+			}
+		}
+
+		outSch, err = mvdata.ApplyColumnTypes(outSch, impOpts.columnTypes)
+		if err != nil {
+			return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
+		}
+
 		return outSch, nil
 	}
 
@@ -699,6 +972,70 @@ func getImportSchema(ctx context.Context, dEnv *env.DoltEnv, impOpts *importOpti
 	return tblRd.GetSchema(), nil
 }
 
+// parseColumnTypes parses a comma separated list of columns and their types, like id:int,price:decimal(10,2). Commas
+// in the parentheses of a type don't separate columns.
+func parseColumnTypes(ctx context.Context, s string) (map[string]sql.Type, error) {
+	var specs []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			specs = append(specs, s[start:i])
+			start = i + 1
+		}
+	}
+	specs = append(specs, s[start:])
+
+	colTypes := make(map[string]sql.Type, len(specs))
+	for _, spec := range specs {
+		name, typ, ok := strings.Cut(spec, ":")
+		name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+		if !ok || name == "" || typ == "" {
+			return nil, fmt.Errorf("expected column:type, found '%s'", spec)
+		}
+		t, err := parse.ParseColumnTypeString(sql.NewContext(ctx), typ)
+		if err != nil {
+			return nil, fmt.Errorf("column '%s' has invalid type '%s': %w", name, typ, err)
+		}
+		colTypes[name] = t
+	}
+	return colTypes, nil
+}
+
+// addLayoutTypes adds the types of the columns of |layout| to |colTypes|, unless they're given by --column-types
+func addLayoutTypes(ctx context.Context, colTypes map[string]sql.Type, layout *fixedwidth.Layout) (map[string]sql.Type, error) {
+	for name, typ := range layout.ColumnTypes() {
+		if _, ok := colTypes[name]; ok {
+			continue
+		}
+		t, err := parse.ParseColumnTypeString(sql.NewContext(ctx), typ)
+		if err != nil {
+			return nil, fmt.Errorf("column '%s' has invalid type '%s': %w", name, typ, err)
+		}
+		if colTypes == nil {
+			colTypes = make(map[string]sql.Type)
+		}
+		colTypes[name] = t
+	}
+	return colTypes, nil
+}
+
+// importFileType returns the type of the file being imported given by --file-type, which is fixed-width if only
+// --layout is given
+func importFileType(apr *argparser.ArgParseResults) string {
+	if fType, ok := apr.GetValue(fileTypeParam); ok {
+		return fType
+	}
+	if apr.Contains(layoutParam) {
+		return string(mvdata.FixedWidthFile)
+	}
+	return ""
+}
+
 func newDataMoverErrToVerr(mvOpts *importOptions, err *mvdata.DataMoverCreationError) errhand.VerboseError {
 	switch err.ErrType {
 	case mvdata.CreateReaderErr:
@@ -716,7 +1053,7 @@ func newDataMoverErrToVerr(mvOpts *importOptions, err *mvdata.DataMoverCreationE
 	case mvdata.MappingErr:
 		bdr := errhand.BuildDError("Error determining the mapping from input fields to output fields.")
 		bdr.AddDetails("When attempting to move data from %s to %s, determine the mapping from input fields t, output fields.", mvOpts.src.String(), mvOpts.destTableName)
-		bdr.AddDetails(`Mapping File: "%s"`, mvOpts.nameMapper)
+		bdr.AddDetails(`Mapping File: "%s"`, mvOpts.mappingFile)
 		return bdr.AddCause(err.Cause).Build()
 
 	case mvdata.ReplacingErr:
@@ -794,6 +1131,19 @@ func NameAndTypeTransform(row sql.Row, rowOperationSchema sql.PrimaryKeySchema,
 			}
 		}
 
+		// Spatial values are parsed from well-known text, well-known binary or GeoJSON. Values that can't be parsed
+		// make the row bad, rather than failing the import.
+		if _, ok := col.Type.(sql.SpatialColumnType); ok {
+			if s, ok := row[i].(string); ok && s != "" {
+				g, err := sqlutil.ParseSpatial(s, col.Type)
+				if err != nil {
+					return nil, table.NewBadRow(nil, fmt.Sprintf("column %s: %s", col.Name, err.Error()))
+				}
+				row[i] = g
+				continue
+			}
+		}
+
 		// For non string types we want empty strings to be converted to nils. String types should be allowed to take on
 		// an empty string value
 		switch col.Type.(type) {
@@ -863,3 +1213,14 @@ func applyMapperToRow(row sql.Row, rowOperationSchema, rdSchema sql.PrimaryKeySc
 
 	return returnRow
 }
+
+// fileFormat returns the format of |loc| if it's a file or stream, or else InvalidDataFormat
+func fileFormat(loc mvdata.DataLocation) mvdata.DataFormat {
+	switch val := loc.(type) {
+	case mvdata.FileDataLocation:
+		return val.Format
+	case mvdata.StreamDataLocation:
+		return val.Format
+	}
+	return mvdata.InvalidDataFormat
+}
@@ -0,0 +1,206 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotesrv"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/connmux"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const transferMemTableSize = 128 * 1024 * 1024
+
+var transferDocs = cli.CommandDocumentationContent{
+	ShortDesc: "Serves a database to a dolt client connected over ssh.",
+	LongDesc: `Serves the database at {{.LessThan}}path{{.GreaterThan}} over stdin and stdout. This command is run on the host of an ssh remote by the dolt client that connects to it, and is not meant to be run directly.
+
+If {{.LessThan}}path{{.GreaterThan}} is a dolt repository, its database is served. Otherwise a bare database is stored at {{.LessThan}}path{{.GreaterThan}}, which is created if it doesn't exist, as it is for file remotes.`,
+	Synopsis: []string{
+		"{{.LessThan}}path{{.GreaterThan}}",
+	},
+}
+
+type TransferCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd TransferCmd) Name() string {
+	return dbfactory.SSHTransferCommand
+}
+
+// Description returns a description of the command
+func (cmd TransferCmd) Description() string {
+	return transferDocs.ShortDesc
+}
+
+// Hidden should return true if this command should be hidden from the help text
+func (cmd TransferCmd) Hidden() bool {
+	return true
+}
+
+// RequiresRepo should return false if this interface is implemented, and the command does not have the requirement
+// that it be run from within a data repository directory
+func (cmd TransferCmd) RequiresRepo() bool {
+	return false
+}
+
+func (cmd TransferCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(transferDocs, ap)
+}
+
+func (cmd TransferCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 1)
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"path", "The path of the database to serve."})
+	return ap
+}
+
+// Exec executes the command
+func (cmd TransferCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, transferDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	// stdout carries the connection to the client, so nothing else may be written to it
+	cli.CliOut = cli.CliErr
+
+	var verr errhand.VerboseError
+	if cli.ExecuteWithStdioRestored == nil {
+		verr = serveTransfer(ctx, dEnv, apr.Arg(0))
+	} else {
+		cli.ExecuteWithStdioRestored(func() {
+			verr = serveTransfer(ctx, dEnv, apr.Arg(0))
+		})
+	}
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+// serveTransfer serves the database at |path| over the process's stdin and stdout until the client disconnects
+func serveTransfer(ctx context.Context, dEnv *env.DoltEnv, path string) errhand.VerboseError {
+	fs, err := dEnv.FS.WithWorkingDir(path)
+	if err != nil {
+		return errhand.BuildDError("error: invalid path '%s'", path).AddCause(err).Build()
+	}
+	if exists, isDir := fs.Exists("."); exists && !isDir {
+		return errhand.BuildDError("error: '%s' is not a directory", path).Build()
+	}
+
+	// table files are served by their path relative to the parent directory, as the server requires the path of
+	// each to include a directory
+	absPath, err := fs.Abs(".")
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	httpFS, err := dEnv.FS.WithWorkingDir(filepath.Dir(absPath))
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	dbCache := &transferDBCache{fs: fs}
+	if exists, isDir := fs.Exists(dbfactory.DoltDir); exists && isDir {
+		repoEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB, dEnv.Version)
+		if repoEnv.DBLoadError != nil {
+			return errhand.BuildDError("error: failed to load the database at '%s'", path).AddCause(repoEnv.DBLoadError).Build()
+		}
+		cs := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(repoEnv.DoltDB))
+		rss, ok := cs.(remotesrv.RemoteSrvStore)
+		if !ok {
+			return errhand.BuildDError("error: the database at '%s' can't be served", path).Build()
+		}
+		dbCache.store = rss
+	}
+
+	lgr := logrus.New()
+	lgr.SetOutput(os.Stderr)
+	lgr.SetLevel(logrus.WarnLevel)
+
+	// the server isn't listening, so the address is only used to choose to serve gRPC and HTTP on each connection
+	server, err := remotesrv.NewServer(remotesrv.ServerArgs{
+		Logger:         logrus.NewEntry(lgr),
+		HttpHost:       dbfactory.SSHTransferCommand,
+		HttpListenAddr: dbfactory.SSHTransferCommand,
+		GrpcListenAddr: dbfactory.SSHTransferCommand,
+		FS:             httpFS,
+		DBCache:        dbCache,
+	})
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+
+	conns := connmux.New(connmux.Pipes(os.Stdin, os.Stdout, os.Stdout.Close), dbfactory.SSHTransferConns)
+	netConns := make([]net.Conn, len(conns))
+	for i, conn := range conns {
+		netConns[i] = conn
+	}
+	server.ServeConns(netConns...)
+
+	return nil
+}
+
+// transferDBCache is the remotesrv.DBCache of the transfer command, which serves a single database whatever the path
+// requested. When the transfer command isn't run on a dolt repository, a bare database is created on first use, with
+// the format of the client.
+type transferDBCache struct {
+	mu    sync.Mutex
+	fs    filesys.Filesys
+	store remotesrv.RemoteSrvStore
+}
+
+var _ remotesrv.DBCache = (*transferDBCache)(nil)
+
+func (c *transferDBCache) Get(_, nbfVerStr string) (remotesrv.RemoteSrvStore, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.store != nil {
+		return c.store, nil
+	}
+
+	if err := c.fs.MkDirs("."); err != nil {
+		return nil, err
+	}
+	path, err := c.fs.Abs(".")
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := nbs.NewLocalStore(context.TODO(), nbfVerStr, path, transferMemTableSize, nbs.NewUnlimitedMemQuotaProvider())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a database at '%s': %w", path, err)
+	}
+	c.store = store
+	return store, nil
+}
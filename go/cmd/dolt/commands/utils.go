@@ -16,8 +16,10 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -33,6 +35,38 @@ import (
 
 var fwtStageName = "fwt"
 
+// formatFlag selects between the text output of a command, meant for people, and JSON output with a stable schema,
+// meant for scripts. show and diff take it as an alias of their --result-format option.
+const formatFlag = "format"
+
+// supportsJsonFormat adds --format to |ap|, for commands that can print their output as JSON.
+func supportsJsonFormat(ap *argparser.ArgParser) {
+	ap.SupportsString(formatFlag, "", "format", "How to format output. Valid values are text and json. Defaults to text.")
+}
+
+// isJsonFormat returns whether --format json was given, or an error if the format given isn't text or json.
+func isJsonFormat(apr *argparser.ArgParseResults) (bool, error) {
+	f := apr.GetValueOrDefault(formatFlag, "text")
+	switch strings.ToLower(f) {
+	case "text":
+		return false, nil
+	case "json":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid output format: %s", f)
+	}
+}
+
+// printJson prints |v| as a single line of JSON.
+func printJson(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	cli.Println(string(b))
+	return nil
+}
+
 func GetWorkingWithVErr(dEnv *env.DoltEnv) (*doltdb.RootValue, errhand.VerboseError) {
 	working, err := dEnv.WorkingRoot(context.Background())
 
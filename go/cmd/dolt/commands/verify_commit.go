@@ -0,0 +1,121 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var verifyCommitDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Check the signatures of commits.`,
+	LongDesc: `Checks the GPG or SSH signature of each of the given commits. Commits are signed with {{.EmphasisLeft}}dolt commit -S{{.EmphasisRight}}, or by default when {{.EmphasisLeft}}commit.gpgsign{{.EmphasisRight}} is set.
+
+GPG signatures are checked with {{.EmphasisLeft}}gpg{{.EmphasisRight}}, or the program in {{.EmphasisLeft}}gpg.program{{.EmphasisRight}}, against the keys in its keyring. SSH signatures are checked with {{.EmphasisLeft}}ssh-keygen{{.EmphasisRight}} against the keys in the file named by {{.EmphasisLeft}}gpg.ssh.allowedsignersfile{{.EmphasisRight}}, in the ALLOWED SIGNERS format of ssh-keygen.
+
+Exits with a non-zero status if any of the commits are unsigned or have a bad signature.`,
+	Synopsis: []string{
+		`[-v] {{.LessThan}}commit{{.GreaterThan}}...`,
+	},
+}
+
+type VerifyCommitCmd struct{}
+
+// Name returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd VerifyCommitCmd) Name() string {
+	return "verify-commit"
+}
+
+// Description returns a description of the command
+func (cmd VerifyCommitCmd) Description() string {
+	return verifyCommitDocs.ShortDesc
+}
+
+func (cmd VerifyCommitCmd) Docs() *cli.CommandDocumentation {
+	ap := cmd.ArgParser()
+	return cli.NewCommandDocumentation(verifyCommitDocs, ap)
+}
+
+func (cmd VerifyCommitCmd) ArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithVariableArgs(cmd.Name())
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"commit", "A commit whose signature should be checked."})
+	ap.SupportsFlag(cli.VerboseFlag, "v", "Print the output of the program that checked each signature.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd VerifyCommitCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TYPE_UNSPECIFIED
+}
+
+// Exec executes the command
+func (cmd VerifyCommitCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
+	ap := cmd.ArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, verifyCommitDocs, ap))
+	apr := cli.ParseArgsOrDie(ap, args, help)
+
+	if apr.NArg() == 0 {
+		verr := errhand.BuildDError("%s requires at least one commit", cmd.Name()).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	program := dEnv.Config.GetStringOrDefault(env.GpgProgram, "")
+	allowedSigners := dEnv.Config.GetStringOrDefault(env.GpgSSHAllowedSigners, "")
+
+	allGood := true
+	for _, cSpecStr := range apr.Args {
+		cm, verr := ResolveCommitWithVErr(dEnv, cSpecStr)
+		if verr != nil {
+			return HandleVErrAndExitCode(verr, usage)
+		}
+
+		payload, sig, err := cm.GetSigningPayload()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		if sig == "" {
+			cli.PrintErrf("%s: no signature found\n", cSpecStr)
+			allGood = false
+			continue
+		}
+
+		v, err := creds.Verify(ctx, program, allowedSigners, payload, sig)
+		if err != nil {
+			verr := errhand.BuildDError("failed to verify the signature of %s", cSpecStr).AddCause(err).Build()
+			return HandleVErrAndExitCode(verr, usage)
+		}
+		if apr.Contains(cli.VerboseFlag) {
+			cli.PrintErrln(strings.TrimSpace(v.Output))
+		}
+		if v.Good {
+			cli.Printf("%s: good signature from %s\n", cSpecStr, v.Signer)
+		} else {
+			cli.PrintErrf("%s: bad signature\n", cSpecStr)
+			allGood = false
+		}
+	}
+
+	if !allGood {
+		return 1
+	}
+	return 0
+}
@@ -107,12 +107,21 @@ var doltSubCommands = []cli.Command{
 	indexcmds.Commands,
 	commands.ReadTablesCmd{},
 	commands.GarbageCollectionCmd{},
+	commands.CommitGraphCmd{},
+	commands.ArchiveCmd{},
 	commands.FilterBranchCmd{},
+	commands.PurgeHistoryCmd{},
+	commands.FastExportCmd{},
+	commands.FastImportCmd{},
+	commands.MysqlshImportCmd{},
 	commands.MergeBaseCmd{},
+	commands.VerifyCommitCmd{},
+	commands.DescribeCmd{},
 	commands.RootsCmd{},
 	commands.VersionCmd{VersionStr: Version},
 	commands.DumpCmd{},
 	commands.InspectCmd{},
+	commands.TransferCmd{},
 	dumpDocsCommand,
 	dumpZshCommand,
 	docscmds.Commands,
@@ -147,12 +156,19 @@ var commandsWithoutCliCtx = []cli.Command{
 	indexcmds.Commands,
 	commands.ReadTablesCmd{},
 	commands.GarbageCollectionCmd{},
+	commands.CommitGraphCmd{},
+	commands.ArchiveCmd{},
 	commands.FilterBranchCmd{},
+	commands.FastImportCmd{},
+	commands.MysqlshImportCmd{},
 	commands.MergeBaseCmd{},
+	commands.VerifyCommitCmd{},
+	commands.DescribeCmd{},
 	commands.RootsCmd{},
 	commands.VersionCmd{VersionStr: Version},
 	commands.DumpCmd{},
 	commands.InspectCmd{},
+	commands.TransferCmd{},
 	dumpDocsCommand,
 	dumpZshCommand,
 	docscmds.Commands,
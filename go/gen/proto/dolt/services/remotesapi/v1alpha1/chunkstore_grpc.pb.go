@@ -55,6 +55,18 @@ type ChunkStoreServiceClient interface {
 	ListTableFiles(ctx context.Context, in *ListTableFilesRequest, opts ...grpc.CallOption) (*ListTableFilesResponse, error)
 	RefreshTableFileUrl(ctx context.Context, in *RefreshTableFileUrlRequest, opts ...grpc.CallOption) (*RefreshTableFileUrlResponse, error)
 	AddTableFiles(ctx context.Context, in *AddTableFilesRequest, opts ...grpc.CallOption) (*AddTableFilesResponse, error)
+	// Get which of a list of table files were uploaded to the repository and
+	// are still held by it, whether or not they were added to its manifest. An
+	// interrupted push uses this to add the table files it already uploaded
+	// instead of uploading them again.
+	GetUploadedTableFiles(ctx context.Context, in *GetUploadedTableFilesRequest, opts ...grpc.CallOption) (*GetUploadedTableFilesResponse, error)
+	// Get chunks as deltas against base chunks the client already has. A
+	// client pulling a change to a large tree pairs each chunk it needs with
+	// the chunk of its older version of the tree, and receives little more than
+	// the change. Chunks which the server doesn't have, or which don't delta
+	// well against their base, are left out of the response, and are fetched
+	// from their download locations.
+	GetDeltaChunks(ctx context.Context, in *GetDeltaChunksRequest, opts ...grpc.CallOption) (*GetDeltaChunksResponse, error)
 }
 
 type chunkStoreServiceClient struct {
@@ -186,6 +198,24 @@ func (c *chunkStoreServiceClient) AddTableFiles(ctx context.Context, in *AddTabl
 	return out, nil
 }
 
+func (c *chunkStoreServiceClient) GetUploadedTableFiles(ctx context.Context, in *GetUploadedTableFilesRequest, opts ...grpc.CallOption) (*GetUploadedTableFilesResponse, error) {
+	out := new(GetUploadedTableFilesResponse)
+	err := c.cc.Invoke(ctx, "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/GetUploadedTableFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chunkStoreServiceClient) GetDeltaChunks(ctx context.Context, in *GetDeltaChunksRequest, opts ...grpc.CallOption) (*GetDeltaChunksResponse, error) {
+	out := new(GetDeltaChunksResponse)
+	err := c.cc.Invoke(ctx, "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/GetDeltaChunks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChunkStoreServiceServer is the server API for ChunkStoreService service.
 // All implementations must embed UnimplementedChunkStoreServiceServer
 // for forward compatibility
@@ -208,6 +238,18 @@ type ChunkStoreServiceServer interface {
 	ListTableFiles(context.Context, *ListTableFilesRequest) (*ListTableFilesResponse, error)
 	RefreshTableFileUrl(context.Context, *RefreshTableFileUrlRequest) (*RefreshTableFileUrlResponse, error)
 	AddTableFiles(context.Context, *AddTableFilesRequest) (*AddTableFilesResponse, error)
+	// Get which of a list of table files were uploaded to the repository and
+	// are still held by it, whether or not they were added to its manifest. An
+	// interrupted push uses this to add the table files it already uploaded
+	// instead of uploading them again.
+	GetUploadedTableFiles(context.Context, *GetUploadedTableFilesRequest) (*GetUploadedTableFilesResponse, error)
+	// Get chunks as deltas against base chunks the client already has. A
+	// client pulling a change to a large tree pairs each chunk it needs with
+	// the chunk of its older version of the tree, and receives little more than
+	// the change. Chunks which the server doesn't have, or which don't delta
+	// well against their base, are left out of the response, and are fetched
+	// from their download locations.
+	GetDeltaChunks(context.Context, *GetDeltaChunksRequest) (*GetDeltaChunksResponse, error)
 	mustEmbedUnimplementedChunkStoreServiceServer()
 }
 
@@ -248,6 +290,12 @@ func (UnimplementedChunkStoreServiceServer) RefreshTableFileUrl(context.Context,
 func (UnimplementedChunkStoreServiceServer) AddTableFiles(context.Context, *AddTableFilesRequest) (*AddTableFilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddTableFiles not implemented")
 }
+func (UnimplementedChunkStoreServiceServer) GetUploadedTableFiles(context.Context, *GetUploadedTableFilesRequest) (*GetUploadedTableFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadedTableFiles not implemented")
+}
+func (UnimplementedChunkStoreServiceServer) GetDeltaChunks(context.Context, *GetDeltaChunksRequest) (*GetDeltaChunksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeltaChunks not implemented")
+}
 func (UnimplementedChunkStoreServiceServer) mustEmbedUnimplementedChunkStoreServiceServer() {}
 
 // UnsafeChunkStoreServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -467,6 +515,42 @@ func _ChunkStoreService_AddTableFiles_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChunkStoreService_GetUploadedTableFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadedTableFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChunkStoreServiceServer).GetUploadedTableFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/GetUploadedTableFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChunkStoreServiceServer).GetUploadedTableFiles(ctx, req.(*GetUploadedTableFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChunkStoreService_GetDeltaChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeltaChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChunkStoreServiceServer).GetDeltaChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/GetDeltaChunks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChunkStoreServiceServer).GetDeltaChunks(ctx, req.(*GetDeltaChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChunkStoreService_ServiceDesc is the grpc.ServiceDesc for ChunkStoreService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -514,6 +598,14 @@ var ChunkStoreService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddTableFiles",
 			Handler:    _ChunkStoreService_AddTableFiles_Handler,
 		},
+		{
+			MethodName: "GetUploadedTableFiles",
+			Handler:    _ChunkStoreService_GetUploadedTableFiles_Handler,
+		},
+		{
+			MethodName: "GetDeltaChunks",
+			Handler:    _ChunkStoreService_GetDeltaChunks_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
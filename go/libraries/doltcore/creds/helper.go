@@ -0,0 +1,156 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A credential helper is a program dolt runs to get the credentials it authenticates with a remote, so that the
+// credentials can come from a secret store, such as Vault, or be short-lived tokens issued by a cloud provider, rather
+// than being kept in dolt's config.
+//
+// The helper is run with the argument "get", after any arguments given with it. It's written the remote on its stdin
+// as lines of the form key=value, ended by a blank line:
+//
+//	protocol=https
+//	host=dolthub.example.com:443
+//
+// It writes the credentials on its stdout in the same form, and exits with a status of zero:
+//
+//	token=<bearer token>
+//	expiry=2023-06-01T17:00:00Z
+//
+// A helper gives either a token, which is sent as a bearer token, or a username and password, which are sent with
+// basic auth. The optional expiry is an RFC 3339 time, after which the helper is run again to refresh the credentials.
+// Credentials without an expiry are used for as long as dolt runs. Keys dolt doesn't know are ignored.
+const (
+	HelperGetArg = "get"
+
+	HelperProtocolKey = "protocol"
+	HelperHostKey     = "host"
+	HelperTokenKey    = "token"
+	HelperUsernameKey = "username"
+	HelperPasswordKey = "password"
+	HelperExpiryKey   = "expiry"
+)
+
+// helperRefreshWindow is how long before the expiry of credentials the helper is run again to refresh them
+const helperRefreshWindow = 30 * time.Second
+
+// ErrNoHelperCreds is returned when a credential helper doesn't give a token or a username and password
+var ErrNoHelperCreds = errors.New("credential helper gave no credentials")
+
+// HelperRPCCreds are RPC credentials got from a credential helper. The helper is run for the first request, and again
+// whenever the credentials it gave are about to expire.
+type HelperRPCCreds struct {
+	// Command is the helper program, followed by any arguments to run it with, separated by spaces
+	Command    string
+	Protocol   string
+	Host       string
+	RequireTLS bool
+
+	mu     sync.Mutex
+	header string
+	expiry time.Time
+}
+
+// NewHelperRPCCreds returns the credentials given by the credential helper |command| for the remote |host|, accessed
+// with |protocol|
+func NewHelperRPCCreds(command, protocol, host string) *HelperRPCCreds {
+	return &HelperRPCCreds{Command: command, Protocol: protocol, Host: host}
+}
+
+func (c *HelperRPCCreds) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.header == "" || (!c.expiry.IsZero() && time.Now().Add(helperRefreshWindow).After(c.expiry)) {
+		header, expiry, err := c.runHelper(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.header, c.expiry = header, expiry
+	}
+	return map[string]string{
+		"authorization": c.header,
+	}, nil
+}
+
+func (c *HelperRPCCreds) RequireTransportSecurity() bool {
+	return c.RequireTLS
+}
+
+// runHelper runs the helper, and returns the authorization header of the credentials it gives and their expiry
+func (c *HelperRPCCreds) runHelper(ctx context.Context) (string, time.Time, error) {
+	args := strings.Fields(c.Command)
+	if len(args) == 0 {
+		return "", time.Time{}, errors.New("no credential helper command given")
+	}
+
+	var stdin, stdout, stderr bytes.Buffer
+	fmt.Fprintf(&stdin, "%s=%s\n%s=%s\n\n", HelperProtocolKey, c.Protocol, HelperHostKey, c.Host)
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], HelperGetArg)...)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", time.Time{}, fmt.Errorf("credential helper '%s' failed: %w: %s", c.Command, err, msg)
+		}
+		return "", time.Time{}, fmt.Errorf("credential helper '%s' failed: %w", c.Command, err)
+	}
+
+	vals := make(map[string]string)
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			break
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			vals[k] = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	var expiry time.Time
+	if s, ok := vals[HelperExpiryKey]; ok {
+		var err error
+		expiry, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("credential helper '%s' gave an invalid expiry: %w", c.Command, err)
+		}
+	}
+
+	if token := vals[HelperTokenKey]; token != "" {
+		return "Bearer " + token, expiry, nil
+	}
+	if user, ok := vals[HelperUsernameKey]; ok && user != "" {
+		pass := DoltCredsForPass{Username: user, Password: vals[HelperPasswordKey]}
+		return "Basic " + pass.ToBase64Str(), expiry, nil
+	}
+	return "", time.Time{}, fmt.Errorf("%w: '%s' must give a %s, or a %s and %s", ErrNoHelperCreds, c.Command, HelperTokenKey, HelperUsernameKey, HelperPasswordKey)
+}
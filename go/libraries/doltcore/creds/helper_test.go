@@ -0,0 +1,123 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeHelper writes a credential helper script to |dir| which records its input and args, and prints |output|
+func writeHelper(t *testing.T, dir, output string) string {
+	path := filepath.Join(dir, "helper.sh")
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" > " + filepath.Join(dir, "args") + "\n" +
+		"cat > " + filepath.Join(dir, "input") + "\n" +
+		"echo run >> " + filepath.Join(dir, "runs") + "\n" +
+		output
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func helperRuns(t *testing.T, dir string) int {
+	b, err := os.ReadFile(filepath.Join(dir, "runs"))
+	require.NoError(t, err)
+	return strings.Count(string(b), "run")
+}
+
+func TestHelperRPCCreds(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper scripts require a unix shell")
+	}
+	ctx := context.Background()
+
+	t.Run("Token", func(t *testing.T) {
+		dir := t.TempDir()
+		helper := writeHelper(t, dir, "echo token=abc\necho unknown=ignored\n")
+		c := NewHelperRPCCreds(helper+" --vault-path secret/dolt", "https", "dolthub.example.com:443")
+
+		md, err := c.GetRequestMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer abc", md["authorization"])
+		md, err = c.GetRequestMetadata(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer abc", md["authorization"])
+		assert.Equal(t, 1, helperRuns(t, dir))
+
+		args, err := os.ReadFile(filepath.Join(dir, "args"))
+		require.NoError(t, err)
+		assert.Equal(t, "--vault-path secret/dolt get\n", string(args))
+		input, err := os.ReadFile(filepath.Join(dir, "input"))
+		require.NoError(t, err)
+		assert.Equal(t, "protocol=https\nhost=dolthub.example.com:443\n\n", string(input))
+	})
+
+	t.Run("UsernameAndPassword", func(t *testing.T) {
+		dir := t.TempDir()
+		helper := writeHelper(t, dir, "echo username=user\necho password=pass\n")
+		md, err := NewHelperRPCCreds(helper, "http", "localhost:50051").GetRequestMetadata(ctx)
+		require.NoError(t, err)
+		expected := DoltCredsForPass{Username: "user", Password: "pass"}.ToBase64Str()
+		assert.Equal(t, "Basic "+expected, md["authorization"])
+	})
+
+	t.Run("RefreshesExpiring", func(t *testing.T) {
+		dir := t.TempDir()
+		expiry := time.Now().Add(10 * time.Second).UTC().Format(time.RFC3339)
+		helper := writeHelper(t, dir, "echo token=abc\necho expiry="+expiry+"\n")
+		c := NewHelperRPCCreds(helper, "https", "dolthub.example.com:443")
+		for i := 0; i < 2; i++ {
+			_, err := c.GetRequestMetadata(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, helperRuns(t, dir))
+
+		dir = t.TempDir()
+		expiry = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		helper = writeHelper(t, dir, "echo token=abc\necho expiry="+expiry+"\n")
+		c = NewHelperRPCCreds(helper, "https", "dolthub.example.com:443")
+		for i := 0; i < 2; i++ {
+			_, err := c.GetRequestMetadata(ctx)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, helperRuns(t, dir))
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		dir := t.TempDir()
+		helper := writeHelper(t, dir, "echo 'vault is sealed' >&2\nexit 1\n")
+		_, err := NewHelperRPCCreds(helper, "https", "dolthub.example.com:443").GetRequestMetadata(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vault is sealed")
+
+		helper = writeHelper(t, dir, "echo expiry=soon\necho token=abc\n")
+		_, err = NewHelperRPCCreds(helper, "https", "dolthub.example.com:443").GetRequestMetadata(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid expiry")
+
+		helper = writeHelper(t, dir, "echo password=pass\n")
+		_, err = NewHelperRPCCreds(helper, "https", "dolthub.example.com:443").GetRequestMetadata(ctx)
+		assert.True(t, errors.Is(err, ErrNoHelperCreds))
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creds
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Commits and tags are signed and verified the way git does it, by running gpg or ssh-keygen. The signature is made
+// over a signing payload holding the commit's or tag's contents, and is stored with its metadata.
+const (
+	OpenPGPSigningFormat = "openpgp"
+	SSHSigningFormat     = "ssh"
+
+	defaultGPGProgram = "gpg"
+	defaultSSHProgram = "ssh-keygen"
+
+	// sshSigningNamespace is the namespace ssh-keygen signs in, so that signatures made for dolt can't be passed off
+	// as signatures for another purpose, and vice versa.
+	sshSigningNamespace = "dolt"
+
+	pgpSignaturePrefix = "-----BEGIN PGP SIGNATURE-----"
+	sshSignaturePrefix = "-----BEGIN SSH SIGNATURE-----"
+)
+
+// ErrNoSigningKey is returned when signing is asked for without a signing key configured
+var ErrNoSigningKey = errors.New("no signing key is configured; set user.signingkey with dolt config")
+
+// Signer signs commits and tags with a GPG key or an SSH key.
+type Signer struct {
+	// Format is OpenPGPSigningFormat or SSHSigningFormat
+	Format string
+	// Key is the GPG key id, or the path of the SSH private key, to sign with
+	Key string
+	// Program is the gpg or ssh-keygen program to run. It defaults to the one on the PATH.
+	Program string
+}
+
+// NewSigner returns a Signer for the |key| in |format|, which defaults to OpenPGPSigningFormat, run with |program|.
+func NewSigner(format, key, program string) (*Signer, error) {
+	if key == "" {
+		return nil, ErrNoSigningKey
+	}
+	if format == "" {
+		format = OpenPGPSigningFormat
+	}
+	if format != OpenPGPSigningFormat && format != SSHSigningFormat {
+		return nil, fmt.Errorf("unknown signing format '%s'; must be %s or %s", format, OpenPGPSigningFormat, SSHSigningFormat)
+	}
+	return &Signer{Format: format, Key: key, Program: program}, nil
+}
+
+// Sign returns the ASCII armored signature of |payload|.
+func (s *Signer) Sign(ctx context.Context, payload []byte) (string, error) {
+	var cmd *exec.Cmd
+	if s.Format == SSHSigningFormat {
+		cmd = exec.CommandContext(ctx, programOrDefault(s.Program, defaultSSHProgram), "-Y", "sign", "-n", sshSigningNamespace, "-f", s.Key)
+	} else {
+		cmd = exec.CommandContext(ctx, programOrDefault(s.Program, defaultGPGProgram), "--status-fd=2", "-bsau", s.Key)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to sign with %s: %w: %s", cmd.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	if s.Format != SSHSigningFormat && !strings.Contains(stderr.String(), "[GNUPG:] SIG_CREATED ") {
+		return "", fmt.Errorf("failed to sign with %s: %s", cmd.Path, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Verification is the result of verifying a signature.
+type Verification struct {
+	// Good is true if the signature is a good signature of the payload by a trusted key
+	Good bool
+	// Signer identifies who made the signature: the user id of a GPG key, or the principal of an SSH key
+	Signer string
+	// Output is the output of the program that verified the signature
+	Output string
+}
+
+// Verify checks that |signature| is a good signature of |payload|, running the gpg or ssh-keygen |program| that
+// matches the signature's format. SSH signatures are checked against the keys in |allowedSignersFile|, in the format
+// of ssh-keygen's ALLOWED SIGNERS, which must be given.
+func Verify(ctx context.Context, program, allowedSignersFile string, payload []byte, signature string) (*Verification, error) {
+	sigFile, err := os.CreateTemp("", "dolt-signature-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(sigFile.Name())
+	_, err = sigFile.WriteString(signature)
+	if cerr := sigFile.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(signature, sshSignaturePrefix):
+		return verifySSH(ctx, programOrDefault(program, defaultSSHProgram), allowedSignersFile, payload, sigFile.Name())
+	case strings.HasPrefix(signature, pgpSignaturePrefix):
+		return verifyGPG(ctx, programOrDefault(program, defaultGPGProgram), payload, sigFile.Name())
+	default:
+		return nil, errors.New("signature is not a GPG or SSH signature")
+	}
+}
+
+func verifyGPG(ctx context.Context, program string, payload []byte, sigFile string) (*Verification, error) {
+	out, err := runVerifier(ctx, payload, program, "--status-fd=1", "--verify", sigFile, "-")
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Verification{Output: out}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 4)
+		if len(fields) < 2 || fields[0] != "[GNUPG:]" {
+			continue
+		}
+		switch fields[1] {
+		case "GOODSIG":
+			v.Good = true
+			if len(fields) == 4 {
+				v.Signer = fields[3]
+			}
+		case "BADSIG", "ERRSIG", "EXPSIG", "EXPKEYSIG", "REVKEYSIG":
+			v.Good = false
+			return v, nil
+		}
+	}
+	return v, nil
+}
+
+func verifySSH(ctx context.Context, program, allowedSignersFile string, payload []byte, sigFile string) (*Verification, error) {
+	if allowedSignersFile == "" {
+		return nil, errors.New("gpg.ssh.allowedsignersfile must be configured to verify SSH signatures")
+	}
+
+	out, err := runVerifier(ctx, nil, program, "-Y", "find-principals", "-f", allowedSignersFile, "-s", sigFile)
+	if err != nil {
+		return nil, err
+	}
+	principal := strings.TrimSpace(strings.SplitN(out, "\n", 2)[0])
+	if principal == "" || strings.Contains(out, "No principal matched") {
+		return &Verification{Output: out}, nil
+	}
+
+	out, err = runVerifier(ctx, payload, program, "-Y", "verify", "-n", sshSigningNamespace, "-f", allowedSignersFile, "-I", principal, "-s", sigFile)
+	if err != nil {
+		return nil, err
+	}
+	return &Verification{Good: strings.Contains(out, "Good \""+sshSigningNamespace+"\" signature"), Signer: principal, Output: out}, nil
+}
+
+// runVerifier runs |program| with |args| and |stdin|, and returns its combined output. A bad signature makes the
+// program exit with a non-zero status, so that isn't an error; failing to run the program at all is.
+func runVerifier(ctx context.Context, stdin []byte, program string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return "", fmt.Errorf("failed to run %s: %w", program, err)
+		}
+	}
+	return out.String(), nil
+}
+
+func programOrDefault(program, def string) string {
+	if program == "" {
+		return def
+	}
+	return program
+}
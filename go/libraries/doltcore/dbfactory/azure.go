@@ -0,0 +1,210 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// AzureCredsTypeParam is a creation parameter that can be used to set the type of credentials that should be
+	// used. Valid values are auto, sas and managed-identity
+	AzureCredsTypeParam = "az-creds-type"
+
+	// AzureSASTokenFileParam is a creation parameter that can be used to specify a file containing the SAS token to
+	// use.
+	AzureSASTokenFileParam = "az-sas-token-file"
+
+	// AzureManagedIdentityParam is a creation parameter that can be used to specify the client id of the
+	// user-assigned managed identity to use. The system-assigned identity is used if it isn't given.
+	AzureManagedIdentityParam = "az-managed-identity"
+
+	azureSASTokenEnvKey = "AZURE_STORAGE_SAS_TOKEN"
+
+	// azureBlobEndpointEnvKey can be set to the url of the blob service of the storage account, to use a service
+	// other than https://<account>.blob.core.windows.net, such as a storage emulator.
+	azureBlobEndpointEnvKey = "AZURE_STORAGE_BLOB_ENDPOINT"
+)
+
+// AzureCredentialSource is an enum type representing the different sources of Azure credentials
+type AzureCredentialSource int
+
+const (
+	InvalidAzureCS AzureCredentialSource = iota - 1
+
+	// AutoAzureCS uses a SAS token if one is given, and otherwise the credentials found by the Azure SDK in the
+	// environment, the managed identity of the host, or the Azure CLI. This is the default.
+	AutoAzureCS
+
+	// SASAzureCS uses the SAS token in the file given by az-sas-token-file or in AZURE_STORAGE_SAS_TOKEN
+	SASAzureCS
+
+	// ManagedIdentityAzureCS uses the managed identity of the host
+	ManagedIdentityAzureCS
+)
+
+var AzureCredTypes = []string{AutoAzureCS.String(), SASAzureCS.String(), ManagedIdentityAzureCS.String()}
+
+// String returns the string representation of an AzureCredentialSource
+func (cs AzureCredentialSource) String() string {
+	switch cs {
+	case AutoAzureCS:
+		return "auto"
+	case SASAzureCS:
+		return "sas"
+	case ManagedIdentityAzureCS:
+		return "managed-identity"
+	default:
+		return "invalid"
+	}
+}
+
+// AzureCredentialSourceFromStr converts a string to an AzureCredentialSource
+func AzureCredentialSourceFromStr(str string) AzureCredentialSource {
+	switch strings.ToLower(str) {
+	case "", "auto":
+		return AutoAzureCS
+	case "sas":
+		return SASAzureCS
+	case "managed-identity":
+		return ManagedIdentityAzureCS
+	default:
+		return InvalidAzureCS
+	}
+}
+
+// AzureFactory is a DBFactory implementation for creating Azure Blob Storage backed databases. Urls are of the form
+// az://storage-account/container/path.
+type AzureFactory struct {
+}
+
+// PrepareDB prepares an Azure backed database
+func (fact AzureFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) error {
+	// nothing to prepare
+	return nil
+}
+
+// CreateDB creates an Azure backed database
+func (fact AzureFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
+	containerName, prefix, err := azureContainerAndPrefix(urlObj)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	client, err := newAzureContainerClient(urlObj.Host, containerName, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bs := blobstore.NewAzureBlobstore(client, containerName, prefix)
+	q := nbs.NewUnlimitedMemQuotaProvider()
+	azureStore, err := nbs.NewBSStore(ctx, nbf.VersionString(), bs, defaultMemTableSize, q)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	vrw := types.NewValueStore(azureStore)
+	ns := tree.NewNodeStore(azureStore)
+	db := datas.NewTypesDatabase(vrw, ns)
+
+	return db, vrw, ns, nil
+}
+
+// azureContainerAndPrefix splits the path of an az:// url into a container name and the path within the container
+func azureContainerAndPrefix(urlObj *url.URL) (string, string, error) {
+	containerName, prefix, _ := strings.Cut(strings.TrimPrefix(urlObj.Path, "/"), "/")
+	if urlObj.Host == "" || containerName == "" {
+		return "", "", fmt.Errorf("azure urls should be of the form az://storage-account/container/path, found '%s'", urlObj.String())
+	}
+	return containerName, prefix, nil
+}
+
+func newAzureContainerClient(account, containerName string, params map[string]interface{}) (*container.Client, error) {
+	serviceURL := "https://" + account + ".blob.core.windows.net"
+	if endpoint := os.Getenv(azureBlobEndpointEnvKey); endpoint != "" {
+		serviceURL = endpoint
+	}
+	containerURL := strings.TrimSuffix(serviceURL, "/") + "/" + containerName
+
+	credsType := AutoAzureCS
+	if val, ok := params[AzureCredsTypeParam]; ok {
+		credsType = AzureCredentialSourceFromStr(val.(string))
+		if credsType == InvalidAzureCS {
+			return nil, fmt.Errorf("invalid value for %s: '%s', valid values are %s", AzureCredsTypeParam, val, strings.Join(AzureCredTypes, ", "))
+		}
+	}
+
+	sasToken, err := azureSASToken(params)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case credsType == SASAzureCS || (credsType == AutoAzureCS && sasToken != ""):
+		if sasToken == "" {
+			return nil, fmt.Errorf("no SAS token found in %s or %s", AzureSASTokenFileParam, azureSASTokenEnvKey)
+		}
+		return container.NewClientWithNoCredential(containerURL+"?"+strings.TrimPrefix(sasToken, "?"), nil)
+
+	case credsType == ManagedIdentityAzureCS:
+		opts := &azidentity.ManagedIdentityCredentialOptions{}
+		if val, ok := params[AzureManagedIdentityParam]; ok {
+			opts.ID = azidentity.ClientID(val.(string))
+		}
+		cred, err := azidentity.NewManagedIdentityCredential(opts)
+		if err != nil {
+			return nil, err
+		}
+		return container.NewClient(containerURL, cred, nil)
+
+	default:
+		var cred azcore.TokenCredential
+		if cred, err = azidentity.NewDefaultAzureCredential(nil); err != nil {
+			return nil, err
+		}
+		return container.NewClient(containerURL, cred, nil)
+	}
+}
+
+// azureSASToken returns the SAS token in the file given by the az-sas-token-file param, or in the
+// AZURE_STORAGE_SAS_TOKEN environment variable if no file is given
+func azureSASToken(params map[string]interface{}) (string, error) {
+	if val, ok := params[AzureSASTokenFileParam]; ok {
+		data, err := os.ReadFile(val.(string))
+		if err != nil {
+			return "", fmt.Errorf("failed to read SAS token file %s: %w", val, err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("SAS token file %s is empty", val)
+		}
+		return token, nil
+	}
+	return strings.TrimSpace(os.Getenv(azureSASTokenEnvKey)), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureCredentialSourceFromStr(t *testing.T) {
+	for _, str := range AzureCredTypes {
+		assert.Equal(t, str, AzureCredentialSourceFromStr(str).String())
+	}
+	assert.Equal(t, AutoAzureCS, AzureCredentialSourceFromStr(""))
+	assert.Equal(t, InvalidAzureCS, AzureCredentialSourceFromStr("role"))
+}
+
+func TestAzureContainerAndPrefix(t *testing.T) {
+	tests := []struct {
+		url               string
+		expectedContainer string
+		expectedPrefix    string
+		expectErr         bool
+	}{
+		{url: "az://account/container/db", expectedContainer: "container", expectedPrefix: "db"},
+		{url: "az://account/container/path/to/db", expectedContainer: "container", expectedPrefix: "path/to/db"},
+		{url: "az://account/container", expectedContainer: "container", expectedPrefix: ""},
+		{url: "az://account", expectErr: true},
+		{url: "az:///container/db", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			urlObj, err := url.Parse(test.url)
+			require.NoError(t, err)
+
+			containerName, prefix, err := azureContainerAndPrefix(urlObj)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedContainer, containerName)
+			assert.Equal(t, test.expectedPrefix, prefix)
+		})
+	}
+}
+
+func TestAzureSASToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("?sv=2021-08-06&sig=abc\n"), 0600))
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, nil, 0600))
+
+	t.Setenv(azureSASTokenEnvKey, "sv=env")
+
+	token, err := azureSASToken(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "sv=env", token)
+
+	token, err = azureSASToken(map[string]interface{}{AzureSASTokenFileParam: tokenFile})
+	require.NoError(t, err)
+	assert.Equal(t, "?sv=2021-08-06&sig=abc", token)
+
+	_, err = azureSASToken(map[string]interface{}{AzureSASTokenFileParam: emptyFile})
+	assert.Error(t, err)
+
+	_, err = azureSASToken(map[string]interface{}{AzureSASTokenFileParam: filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
+
+func TestNewAzureContainerClient(t *testing.T) {
+	t.Setenv(azureSASTokenEnvKey, "")
+
+	client, err := newAzureContainerClient("account", "container", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://account.blob.core.windows.net/container", client.URL())
+
+	t.Setenv(azureSASTokenEnvKey, "?sv=env")
+	client, err = newAzureContainerClient("account", "container", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://account.blob.core.windows.net/container?sv=env", client.URL())
+
+	t.Setenv(azureBlobEndpointEnvKey, "http://127.0.0.1:10000/devstoreaccount1/")
+	client, err = newAzureContainerClient("devstoreaccount1", "container", map[string]interface{}{AzureCredsTypeParam: "sas"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:10000/devstoreaccount1/container?sv=env", client.URL())
+
+	t.Setenv(azureSASTokenEnvKey, "")
+	_, err = newAzureContainerClient("account", "container", map[string]interface{}{AzureCredsTypeParam: "sas"})
+	assert.Error(t, err)
+
+	_, err = newAzureContainerClient("account", "container", map[string]interface{}{AzureCredsTypeParam: "role"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const (
+	// EncryptionKeyFileEnv names a file holding the 256-bit key used to encrypt local databases at rest. The file
+	// holds the key as 32 raw bytes, 64 hex characters or base64.
+	EncryptionKeyFileEnv = "DOLT_ENCRYPTION_KEY_FILE"
+
+	// EncryptionKMSKeyFileEnv names a file holding a data key encrypted with AWS KMS, raw or base64 encoded. The
+	// key is decrypted with KMS using the shared AWS config and used to encrypt local databases at rest.
+	EncryptionKMSKeyFileEnv = "DOLT_ENCRYPTION_KMS_KEY_FILE"
+
+	// EncryptionKeyFileParam names a file holding the key used to encrypt a file database, such as a backup, instead
+	// of the key configured for local databases through the environment. The key is in the same formats.
+	EncryptionKeyFileParam = "encryption-key-file"
+)
+
+// kmsDecrypter is the part of the KMS API used to decrypt data keys.
+type kmsDecrypter interface {
+	Decrypt(*kms.DecryptInput) (*kms.DecryptOutput, error)
+}
+
+var newKMSDecrypter = func() (kmsDecrypter, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, err
+	}
+	return kms.New(sess), nil
+}
+
+var encryptionOnce struct {
+	sync.Mutex
+	done bool
+	enc  *nbs.Encryption
+	err  error
+}
+
+// localEncryption returns the encryption configured for local databases through the environment, or nil if local
+// databases are not encrypted. The key is loaded once per process.
+func localEncryption() (*nbs.Encryption, error) {
+	encryptionOnce.Lock()
+	defer encryptionOnce.Unlock()
+	if !encryptionOnce.done {
+		encryptionOnce.enc, encryptionOnce.err = loadEncryption(os.Getenv(EncryptionKeyFileEnv), os.Getenv(EncryptionKMSKeyFileEnv))
+		encryptionOnce.done = true
+	}
+	return encryptionOnce.enc, encryptionOnce.err
+}
+
+// paramsEncryption returns the encryption for a file database opened with |params|. It's the encryption of the key
+// file given by EncryptionKeyFileParam, if there is one, and otherwise the encryption of local databases.
+func paramsEncryption(params map[string]interface{}) (*nbs.Encryption, error) {
+	keyFile, ok := params[EncryptionKeyFileParam]
+	if !ok {
+		return localEncryption()
+	}
+	path, ok := keyFile.(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("invalid %s param", EncryptionKeyFileParam)
+	}
+	return loadEncryption(path, "")
+}
+
+func loadEncryption(keyFile, kmsKeyFile string) (*nbs.Encryption, error) {
+	if keyFile != "" && kmsKeyFile != "" {
+		return nil, fmt.Errorf("only one of %s and %s may be set", EncryptionKeyFileEnv, EncryptionKMSKeyFileEnv)
+	}
+
+	var key []byte
+	if keyFile != "" {
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading encryption key: %w", err)
+		}
+		key, err = decodeKey(b, 32)
+		if err != nil {
+			return nil, fmt.Errorf("error reading encryption key from %s: %w", keyFile, err)
+		}
+	} else if kmsKeyFile != "" {
+		b, err := os.ReadFile(kmsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading encrypted data key: %w", err)
+		}
+		blob, err := decodeKey(b, -1)
+		if err != nil {
+			return nil, fmt.Errorf("error reading encrypted data key from %s: %w", kmsKeyFile, err)
+		}
+		svc, err := newKMSDecrypter()
+		if err != nil {
+			return nil, err
+		}
+		out, err := svc.Decrypt(&kms.DecryptInput{CiphertextBlob: blob})
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting data key with KMS: %w", err)
+		}
+		key = out.Plaintext
+	} else {
+		return nil, nil
+	}
+
+	return nbs.NewEncryption(key)
+}
+
+// decodeKey decodes |b| as raw bytes, hex or base64. If |size| is positive the decoded key must be |size| bytes.
+func decodeKey(b []byte, size int) ([]byte, error) {
+	if size > 0 && len(b) == size {
+		return b, nil
+	}
+	s := bytes.TrimSpace(b)
+	if size > 0 && len(s) == 2*size {
+		if key, err := hex.DecodeString(string(s)); err == nil {
+			return key, nil
+		}
+	}
+	if key, err := base64.StdEncoding.DecodeString(string(s)); err == nil && (size <= 0 || len(key) == size) {
+		return key, nil
+	}
+	if size <= 0 {
+		return b, nil
+	}
+	return nil, errors.New("key must be 32 bytes, 64 hex characters or base64")
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKMS struct {
+	blob, key []byte
+}
+
+func (k testKMS) Decrypt(in *kms.DecryptInput) (*kms.DecryptOutput, error) {
+	if !bytes.Equal(in.CiphertextBlob, k.blob) {
+		return nil, errors.New("invalid ciphertext")
+	}
+	return &kms.DecryptOutput{Plaintext: k.key}, nil
+}
+
+func TestLoadEncryption(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, b []byte) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, b, 0600))
+		return p
+	}
+	key := bytes.Repeat([]byte{0x2a}, 32)
+
+	enc, err := loadEncryption("", "")
+	require.NoError(t, err)
+	assert.Nil(t, enc)
+
+	for name, b := range map[string][]byte{
+		"raw":    key,
+		"hex":    []byte(hex.EncodeToString(key) + "\n"),
+		"base64": []byte(base64.StdEncoding.EncodeToString(key) + "\n"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			enc, err := loadEncryption(write(name, b), "")
+			require.NoError(t, err)
+			assert.NotNil(t, enc)
+		})
+	}
+
+	_, err = loadEncryption(write("short", []byte("too short")), "")
+	assert.Error(t, err)
+	_, err = loadEncryption(filepath.Join(dir, "missing"), "")
+	assert.Error(t, err)
+	_, err = loadEncryption(write("both", key), write("both_kms", key))
+	assert.Error(t, err)
+
+	orig := newKMSDecrypter
+	defer func() { newKMSDecrypter = orig }()
+	blob := []byte("encrypted data key")
+	newKMSDecrypter = func() (kmsDecrypter, error) {
+		return testKMS{blob: blob, key: key}, nil
+	}
+	enc, err = loadEncryption("", write("kms", []byte(base64.StdEncoding.EncodeToString(blob))))
+	require.NoError(t, err)
+	assert.NotNil(t, enc)
+	_, err = loadEncryption("", write("kms_bad", []byte("some other blob")))
+	assert.Error(t, err)
+}
+
+func TestParamsEncryption(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "backup.key")
+	require.NoError(t, os.WriteFile(p, bytes.Repeat([]byte{0x2a}, 32), 0600))
+
+	enc, err := paramsEncryption(map[string]interface{}{EncryptionKeyFileParam: p})
+	require.NoError(t, err)
+	assert.NotNil(t, enc)
+
+	_, err = paramsEncryption(map[string]interface{}{EncryptionKeyFileParam: ""})
+	assert.Error(t, err)
+	_, err = paramsEncryption(map[string]interface{}{EncryptionKeyFileParam: filepath.Join(t.TempDir(), "missing")})
+	assert.Error(t, err)
+}
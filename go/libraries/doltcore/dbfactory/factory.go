@@ -50,6 +50,15 @@ const (
 
 	OSSScheme = "oss"
 
+	// AzureScheme
+	AzureScheme = "az"
+
+	// OCIScheme
+	OCIScheme = "oci"
+
+	// SSHScheme
+	SSHScheme = "ssh"
+
 	defaultScheme       = HTTPSScheme
 	defaultMemTableSize = 256 * 1024 * 1024
 )
@@ -69,6 +78,9 @@ var DBFactories = map[string]DBFactory{
 	AWSScheme:     AWSFactory{},
 	OSSScheme:     OSSFactory{},
 	GSScheme:      GSFactory{},
+	AzureScheme:   AzureFactory{},
+	OCIScheme:     OCIFactory{},
+	SSHScheme:     SSHFactory{},
 	FileScheme:    FileFactory{},
 	MemScheme:     MemFactory{},
 	LocalBSScheme: LocalBSFactory{},
@@ -136,12 +136,20 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 		_, useJournal = params[ChunkJournalParam]
 	}
 
+	enc, err := paramsEncryption(params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err = configureFileReads(); err != nil {
+		return nil, nil, nil, err
+	}
+
 	var newGenSt *nbs.NomsBlockStore
 	q := nbs.NewUnlimitedMemQuotaProvider()
 	if useJournal && chunkJournalFeatureFlag {
-		newGenSt, err = nbs.NewLocalJournalingStore(ctx, nbf.VersionString(), path, q)
+		newGenSt, err = nbs.NewLocalJournalingStoreWithEncryption(ctx, nbf.VersionString(), path, q, enc)
 	} else {
-		newGenSt, err = nbs.NewLocalStore(ctx, nbf.VersionString(), path, defaultMemTableSize, q)
+		newGenSt, err = nbs.NewLocalStoreWithEncryption(ctx, nbf.VersionString(), path, defaultMemTableSize, q, enc)
 	}
 
 	if err != nil {
@@ -161,7 +169,7 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 		}
 	}
 
-	oldGenSt, err := nbs.NewLocalStore(ctx, newGenSt.Version(), oldgenPath, defaultMemTableSize, q)
+	oldGenSt, err := nbs.NewLocalStoreWithEncryption(ctx, newGenSt.Version(), oldgenPath, defaultMemTableSize, q, enc)
 
 	if err != nil {
 		return nil, nil, nil, err
@@ -34,6 +34,23 @@ import (
 
 var GRPCDialProviderParam = "__DOLT__grpc_dial_provider"
 
+const (
+	// GRPCCredsKeyParam is a creation parameter that can be used to specify the id of the dolt credentials that are
+	// used to authenticate with the remote, instead of the credentials selected by user.creds.
+	GRPCCredsKeyParam = "creds-key"
+
+	// GRPCUserParam is a creation parameter that can be used to specify a user name to authenticate with the remote.
+	// The password is read from the DOLT_REMOTE_PASSWORD environment variable.
+	GRPCUserParam = "creds-user"
+
+	// GRPCCredsHelperParam is a creation parameter that can be used to specify a credential helper, a program run to
+	// get the credentials used to authenticate with the remote.
+	GRPCCredsHelperParam = "creds-helper"
+)
+
+// GRPCCredsParams are the creation parameters that configure how to authenticate with a remotesapi remote
+var GRPCCredsParams = []string{GRPCCredsKeyParam, GRPCUserParam, GRPCCredsHelperParam}
+
 type GRPCRemoteConfig struct {
 	Endpoint    string
 	DialOptions []grpc.DialOption
@@ -100,10 +117,16 @@ func (fact DoltRemoteFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFo
 var NoCachingParameter = "__dolt__NO_CACHING"
 
 func (fact DoltRemoteFactory) newChunkStore(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}, dp GRPCDialProvider) (chunks.ChunkStore, error) {
+	credsKeyID, _ := params[GRPCCredsKeyParam].(string)
+	user, _ := params[GRPCUserParam].(string)
+	credsHelper, _ := params[GRPCCredsHelperParam].(string)
 	cfg, err := dp.GetGRPCDialParams(grpcendpoint.Config{
 		Endpoint:     urlObj.Host,
 		Insecure:     fact.insecure,
 		WithEnvCreds: true,
+		CredsKeyID:   credsKeyID,
+		User:         user,
+		CredsHelper:  credsHelper,
 	})
 	if err != nil {
 		return nil, err
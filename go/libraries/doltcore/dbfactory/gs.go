@@ -16,10 +16,13 @@ package dbfactory
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
 
 	"github.com/dolthub/dolt/go/store/blobstore"
 	"github.com/dolthub/dolt/go/store/datas"
@@ -28,6 +31,16 @@ import (
 	"github.com/dolthub/dolt/go/store/types"
 )
 
+const (
+	// GCSCredsFileParam is a creation parameter that can be used to specify a service account key file to authenticate
+	// with. Application default credentials are used if it isn't given.
+	GCSCredsFileParam = "gcs-creds-file"
+
+	// GCSUploadChunkSizeParam is a creation parameter that can be used to specify the size in bytes of the chunks that
+	// table files are uploaded in. A size of 0 disables resumable uploads.
+	GCSUploadChunkSizeParam = "gcs-upload-chunk-size"
+)
+
 // GSFactory is a DBFactory implementation for creating GCS backed databases
 type GSFactory struct {
 }
@@ -40,13 +53,19 @@ func (fact GSFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat, u
 // CreateDB creates an GCS backed database
 func (fact GSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
 	var db datas.Database
-	gcs, err := storage.NewClient(ctx)
+	opts, chunkSize, err := gcsOptionsFromParams(params)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	gcs, err := storage.NewClient(ctx, opts...)
 
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	bs := blobstore.NewGCSBlobstore(gcs, urlObj.Host, urlObj.Path)
+	bs := blobstore.NewGCSBlobstore(gcs, urlObj.Host, urlObj.Path).WithUploadChunkSize(chunkSize)
 	q := nbs.NewUnlimitedMemQuotaProvider()
 	gcsStore, err := nbs.NewBSStore(ctx, nbf.VersionString(), bs, defaultMemTableSize, q)
 
@@ -61,6 +80,25 @@ func (fact GSFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, ur
 	return db, vrw, ns, nil
 }
 
+// gcsOptionsFromParams returns the client options and upload chunk size configured by |params|
+func gcsOptionsFromParams(params map[string]interface{}) ([]option.ClientOption, int, error) {
+	var opts []option.ClientOption
+	if credsFile, ok := params[GCSCredsFileParam]; ok {
+		opts = append(opts, option.WithCredentialsFile(credsFile.(string)))
+	}
+
+	chunkSize := blobstore.DefaultGCSUploadChunkSize
+	if sizeStr, ok := params[GCSUploadChunkSizeParam]; ok {
+		size, err := strconv.Atoi(sizeStr.(string))
+		if err != nil || size < 0 {
+			return nil, 0, fmt.Errorf("invalid value for %s: '%s'", GCSUploadChunkSizeParam, sizeStr)
+		}
+		chunkSize = size
+	}
+
+	return opts, chunkSize, nil
+}
+
 // LocalBSFactory is a DBFactory implementation for creating a local filesystem blobstore backed databases for testing
 type LocalBSFactory struct {
 }
@@ -0,0 +1,79 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+)
+
+func TestGCSOptionsFromParams(t *testing.T) {
+	tests := []struct {
+		name              string
+		params            map[string]interface{}
+		expectedOpts      int
+		expectedChunkSize int
+		expectErr         bool
+	}{
+		{
+			name:              "defaults",
+			params:            map[string]interface{}{},
+			expectedChunkSize: blobstore.DefaultGCSUploadChunkSize,
+		},
+		{
+			name:              "creds file",
+			params:            map[string]interface{}{GCSCredsFileParam: "/path/to/key.json"},
+			expectedOpts:      1,
+			expectedChunkSize: blobstore.DefaultGCSUploadChunkSize,
+		},
+		{
+			name:              "chunk size",
+			params:            map[string]interface{}{GCSUploadChunkSizeParam: "8388608"},
+			expectedChunkSize: 8 * 1024 * 1024,
+		},
+		{
+			name:              "resumable uploads disabled",
+			params:            map[string]interface{}{GCSUploadChunkSizeParam: "0"},
+			expectedChunkSize: 0,
+		},
+		{
+			name:      "invalid chunk size",
+			params:    map[string]interface{}{GCSUploadChunkSizeParam: "16MB"},
+			expectErr: true,
+		},
+		{
+			name:      "negative chunk size",
+			params:    map[string]interface{}{GCSUploadChunkSizeParam: "-1"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts, chunkSize, err := gcsOptionsFromParams(test.params)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, opts, test.expectedOpts)
+			assert.Equal(t, test.expectedChunkSize, chunkSize)
+		})
+	}
+}
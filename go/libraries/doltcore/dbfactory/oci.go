@@ -0,0 +1,241 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// OCIUserParam is a creation parameter that can be used to give the user name to authenticate with the registry
+	// as. The password is read from the DOLT_OCI_PASSWORD environment variable. The credentials in the docker config
+	// are used if it isn't given.
+	OCIUserParam = "oci-user"
+
+	// OCIPasswordEnvVar is the environment variable that holds the password of the user given by OCIUserParam
+	OCIPasswordEnvVar = "DOLT_OCI_PASSWORD"
+
+	defaultOCITag = "latest"
+
+	// dockerHubHost is the host that docker.io references are pulled from, and dockerHubAuthKey is the key that its
+	// credentials are stored under in the docker config
+	dockerHubHost    = "registry-1.docker.io"
+	dockerHubAuthKey = "https://index.docker.io/v1/"
+)
+
+var (
+	ociRepositoryRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	ociTagRegex        = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+)
+
+// OCIFactory is a DBFactory implementation for creating databases stored as artifacts in OCI registries. Urls are of
+// the form oci://registry/repository:tag, and the tag defaults to latest.
+type OCIFactory struct {
+}
+
+// PrepareDB prepares an OCI backed database
+func (fact OCIFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) error {
+	// nothing to prepare
+	return nil
+}
+
+// CreateDB creates an OCI backed database
+func (fact OCIFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
+	registry, repository, tag, err := ociReference(urlObj)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	creds, err := ociCredentials(registry, params)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bs := blobstore.NewOCIBlobstore(&http.Client{}, ociRegistryURL(registry), repository, tag, creds)
+	q := nbs.NewUnlimitedMemQuotaProvider()
+	ociStore, err := nbs.NewBSStore(ctx, nbf.VersionString(), bs, defaultMemTableSize, q)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	vrw := types.NewValueStore(ociStore)
+	ns := tree.NewNodeStore(ociStore)
+	db := datas.NewTypesDatabase(vrw, ns)
+
+	return db, vrw, ns, nil
+}
+
+// ociReference splits an oci:// url into the registry, repository and tag it references
+func ociReference(urlObj *url.URL) (registry, repository, tag string, err error) {
+	registry = urlObj.Host
+	repository = strings.Trim(urlObj.Path, "/")
+	tag = defaultOCITag
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+
+	if registry == "" || repository == "" {
+		return "", "", "", fmt.Errorf("oci urls should be of the form oci://registry/repository:tag, found '%s'", urlObj.String())
+	} else if !ociRepositoryRegex.MatchString(repository) {
+		return "", "", "", fmt.Errorf("invalid oci repository name '%s'; repository names must be lowercase", repository)
+	} else if !ociTagRegex.MatchString(tag) {
+		return "", "", "", fmt.Errorf("invalid oci tag '%s'", tag)
+	}
+
+	if registry == "docker.io" {
+		registry = dockerHubHost
+	}
+	return registry, repository, tag, nil
+}
+
+// ociRegistryURL returns the url of the registry given. Like docker, registries on the loopback interface are
+// accessed over http, and all other registries over https.
+func ociRegistryURL(registry string) *url.URL {
+	scheme := "https"
+	host := registry
+	if h, _, err := net.SplitHostPort(registry); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		scheme = "http"
+	}
+	return &url.URL{Scheme: scheme, Host: registry}
+}
+
+// ociCredentials returns the credentials to use with |registry|: the user given by the oci-user param, or else the
+// credentials stored for the registry in the docker config. The registry is accessed anonymously if there are none.
+func ociCredentials(registry string, params map[string]interface{}) (blobstore.OCICredentials, error) {
+	if val, ok := params[OCIUserParam]; ok {
+		pass, ok := os.LookupEnv(OCIPasswordEnvVar)
+		if !ok {
+			return blobstore.OCICredentials{}, fmt.Errorf("must set %s environment variable to authenticate with the registry as user '%s'", OCIPasswordEnvVar, val)
+		}
+		return blobstore.OCICredentials{Username: val.(string), Password: pass}, nil
+	}
+	return dockerConfigCredentials(registry)
+}
+
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// dockerConfigCredentials returns the credentials for |registry| in the docker config, which is found in the
+// directory named by DOCKER_CONFIG, or in ~/.docker
+func dockerConfigCredentials(registry string) (blobstore.OCICredentials, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return blobstore.OCICredentials{}, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return blobstore.OCICredentials{}, nil
+	} else if err != nil {
+		return blobstore.OCICredentials{}, err
+	}
+
+	var cfg dockerConfig
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return blobstore.OCICredentials{}, fmt.Errorf("failed to parse docker config %s: %w", filepath.Join(dir, "config.json"), err)
+	}
+
+	authKey := registry
+	if registry == dockerHubHost {
+		authKey = dockerHubAuthKey
+	}
+
+	if helper, ok := cfg.CredHelpers[registry]; ok {
+		return dockerCredentialHelper(helper, authKey)
+	}
+
+	for key, auth := range cfg.Auths {
+		if key != authKey && dockerAuthHost(key) != registry {
+			continue
+		}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return blobstore.OCICredentials{}, fmt.Errorf("invalid auth for %s in docker config: %w", key, err)
+			}
+			user, pass, _ := strings.Cut(string(decoded), ":")
+			return blobstore.OCICredentials{Username: user, Password: pass}, nil
+		} else if auth.Username != "" {
+			return blobstore.OCICredentials{Username: auth.Username, Password: auth.Password}, nil
+		}
+	}
+
+	if cfg.CredsStore != "" {
+		return dockerCredentialHelper(cfg.CredsStore, authKey)
+	}
+	return blobstore.OCICredentials{}, nil
+}
+
+// dockerAuthHost returns the host of a key of the auths in a docker config, which may be a url
+func dockerAuthHost(key string) string {
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return key
+}
+
+// dockerCredentialHelper gets the credentials for |serverURL| from the docker credential helper named |helper|. No
+// credentials are returned if the helper doesn't have any for the server.
+func dockerCredentialHelper(helper, serverURL string) (blobstore.OCICredentials, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return blobstore.OCICredentials{}, nil
+	}
+
+	var resp struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return blobstore.OCICredentials{}, fmt.Errorf("failed to parse the output of docker-credential-%s: %w", helper, err)
+	}
+	return blobstore.OCICredentials{Username: resp.Username, Password: resp.Secret}, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"encoding/base64"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+)
+
+func TestOCIReference(t *testing.T) {
+	tests := []struct {
+		url                string
+		expectedRegistry   string
+		expectedRepository string
+		expectedTag        string
+		expectErr          bool
+	}{
+		{url: "oci://ghcr.io/org/dataset:v1", expectedRegistry: "ghcr.io", expectedRepository: "org/dataset", expectedTag: "v1"},
+		{url: "oci://ghcr.io/org/dataset", expectedRegistry: "ghcr.io", expectedRepository: "org/dataset", expectedTag: "latest"},
+		{url: "oci://localhost:5000/dataset:v1", expectedRegistry: "localhost:5000", expectedRepository: "dataset", expectedTag: "v1"},
+		{url: "oci://docker.io/org/dataset:v1", expectedRegistry: "registry-1.docker.io", expectedRepository: "org/dataset", expectedTag: "v1"},
+		{url: "oci://ghcr.io/org/Dataset:v1", expectErr: true},
+		{url: "oci://ghcr.io/org/dataset:-v1", expectErr: true},
+		{url: "oci://ghcr.io", expectErr: true},
+		{url: "oci:///org/dataset", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			urlObj, err := url.Parse(test.url)
+			require.NoError(t, err)
+
+			registry, repository, tag, err := ociReference(urlObj)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRegistry, registry)
+			assert.Equal(t, test.expectedRepository, repository)
+			assert.Equal(t, test.expectedTag, tag)
+		})
+	}
+}
+
+func TestOCIRegistryURL(t *testing.T) {
+	assert.Equal(t, "https://ghcr.io", ociRegistryURL("ghcr.io").String())
+	assert.Equal(t, "https://registry.example.com:5000", ociRegistryURL("registry.example.com:5000").String())
+	assert.Equal(t, "http://localhost:5000", ociRegistryURL("localhost:5000").String())
+	assert.Equal(t, "http://127.0.0.1:5000", ociRegistryURL("127.0.0.1:5000").String())
+}
+
+func TestOCICredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	// no docker config
+	creds, err := ociCredentials("ghcr.io", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, blobstore.OCICredentials{}, creds)
+
+	auth := base64.StdEncoding.EncodeToString([]byte("ghuser:ghpass"))
+	hubAuth := base64.StdEncoding.EncodeToString([]byte("hubuser:hubpass"))
+	config := `{"auths": {"ghcr.io": {"auth": "` + auth + `"}, "https://index.docker.io/v1/": {"auth": "` + hubAuth + `"}, "https://registry.example.com": {"username": "exuser", "password": "expass"}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0600))
+
+	creds, err = ociCredentials("ghcr.io", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, blobstore.OCICredentials{Username: "ghuser", Password: "ghpass"}, creds)
+
+	creds, err = ociCredentials(dockerHubHost, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, blobstore.OCICredentials{Username: "hubuser", Password: "hubpass"}, creds)
+
+	creds, err = ociCredentials("registry.example.com", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, blobstore.OCICredentials{Username: "exuser", Password: "expass"}, creds)
+
+	creds, err = ociCredentials("quay.io", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, blobstore.OCICredentials{}, creds)
+
+	// the oci-user param takes precedence over the docker config
+	_, err = ociCredentials("ghcr.io", map[string]interface{}{OCIUserParam: "paramuser"})
+	assert.Error(t, err)
+
+	t.Setenv(OCIPasswordEnvVar, "parampass")
+	creds, err = ociCredentials("ghcr.io", map[string]interface{}{OCIUserParam: "paramuser"})
+	require.NoError(t, err)
+	assert.Equal(t, blobstore.OCICredentials{Username: "paramuser", Password: "parampass"}, creds)
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const (
+	// TableFileMmapEnv enables reading the table files of local databases through mmap when set to true.
+	TableFileMmapEnv = "DOLT_TABLE_FILE_MMAP"
+
+	// TableFileMaxMappedBytesEnv limits the total size of the table files mapped at once, e.g. "512MiB". Table
+	// files past the limit are read with pread.
+	TableFileMaxMappedBytesEnv = "DOLT_TABLE_FILE_MAX_MAPPED_BYTES"
+
+	// TableFileReadAdviceEnv is the readahead hint given for table files: normal, random, sequential or willneed.
+	// random keeps scans from filling the page cache, which counts against the memory limit of containers.
+	TableFileReadAdviceEnv = "DOLT_TABLE_FILE_READ_ADVICE"
+)
+
+var readOptionsOnce struct {
+	sync.Mutex
+	done bool
+	err  error
+}
+
+// configureFileReads sets the read options of local table files from the environment. The environment is read
+// once per process.
+func configureFileReads() error {
+	readOptionsOnce.Lock()
+	defer readOptionsOnce.Unlock()
+	if !readOptionsOnce.done {
+		var opts nbs.FileReadOptions
+		opts, readOptionsOnce.err = loadFileReadOptions(os.Getenv(TableFileMmapEnv), os.Getenv(TableFileMaxMappedBytesEnv), os.Getenv(TableFileReadAdviceEnv))
+		if readOptionsOnce.err == nil {
+			nbs.SetFileReadOptions(opts)
+		}
+		readOptionsOnce.done = true
+	}
+	return readOptionsOnce.err
+}
+
+func loadFileReadOptions(mmap, maxMapped, advice string) (opts nbs.FileReadOptions, err error) {
+	if mmap != "" {
+		opts.Mmap, err = strconv.ParseBool(mmap)
+		if err != nil {
+			return nbs.FileReadOptions{}, fmt.Errorf("invalid value %q for %s: must be true or false", mmap, TableFileMmapEnv)
+		}
+	}
+	if maxMapped != "" {
+		n, err := humanize.ParseBytes(maxMapped)
+		if err != nil || int64(n) < 0 {
+			return nbs.FileReadOptions{}, fmt.Errorf("invalid value %q for %s: must be a size such as 512MiB", maxMapped, TableFileMaxMappedBytesEnv)
+		}
+		opts.MaxMappedBytes = int64(n)
+	}
+	opts.Advice, err = nbs.ParseReadAdvice(advice)
+	if err != nil {
+		return nbs.FileReadOptions{}, fmt.Errorf("invalid value for %s: %w", TableFileReadAdviceEnv, err)
+	}
+	return opts, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+func TestLoadFileReadOptions(t *testing.T) {
+	opts, err := loadFileReadOptions("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, nbs.FileReadOptions{}, opts)
+
+	opts, err = loadFileReadOptions("true", "512MiB", "random")
+	require.NoError(t, err)
+	assert.Equal(t, nbs.FileReadOptions{Mmap: true, MaxMappedBytes: 512 << 20, Advice: nbs.ReadAdviceRandom}, opts)
+
+	opts, err = loadFileReadOptions("0", "1000", "sequential")
+	require.NoError(t, err)
+	assert.Equal(t, nbs.FileReadOptions{MaxMappedBytes: 1000, Advice: nbs.ReadAdviceSequential}, opts)
+
+	_, err = loadFileReadOptions("maybe", "", "")
+	assert.Error(t, err)
+	_, err = loadFileReadOptions("", "lots", "")
+	assert.Error(t, err)
+	_, err = loadFileReadOptions("", "", "always")
+	assert.Error(t, err)
+}
@@ -0,0 +1,295 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/libraries/events"
+	"github.com/dolthub/dolt/go/libraries/utils/connmux"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// SSHCommandEnvVar is the environment variable that gives the command used to connect to ssh remotes, which
+	// defaults to ssh. It may include arguments, such as "ssh -i ~/.ssh/dolt_key".
+	SSHCommandEnvVar = "DOLT_SSH"
+
+	// SSHExecPathEnvVar is the environment variable that gives the path of the dolt binary on the hosts of ssh
+	// remotes, for hosts where dolt isn't on the PATH.
+	SSHExecPathEnvVar = "DOLT_SSH_EXEC_PATH"
+
+	// SSHTransferCommand is the dolt command run on the host of an ssh remote, which serves the database at the path
+	// given to it over its stdin and stdout
+	SSHTransferCommand = "transfer"
+
+	// SSHTransferConns is the number of connections multiplexed over the stdin and stdout of the transfer command.
+	// The first carries the gRPC service, and the second table file uploads and downloads.
+	SSHTransferConns = 2
+)
+
+// scpLikeURLRegex matches the scp-like syntax for ssh urls, [user@]host:path
+var scpLikeURLRegex = regexp.MustCompile(`^(?:([^@/:]+)@)?([^@/:]+):(.*)$`)
+
+// SSHURLFromSCPLike converts a url in the scp-like syntax [user@]host:path to an ssh:// url. A relative path is
+// relative to the user's home directory on the host. ok is false if |urlStr| doesn't use the scp-like syntax. A url
+// without a user whose path starts with a port number, such as localhost:50051/org/repo, is not scp-like.
+func SSHURLFromSCPLike(urlStr string) (sshURL string, ok bool) {
+	if strings.Contains(urlStr, "://") {
+		return "", false
+	}
+	m := scpLikeURLRegex.FindStringSubmatch(urlStr)
+	if m == nil {
+		return "", false
+	}
+	user, host, path := m[1], m[2], m[3]
+	if len(host) == 1 {
+		// a windows drive letter
+		return "", false
+	}
+	if user == "" {
+		port, _, _ := strings.Cut(path, "/")
+		if port != "" && strings.Trim(port, "0123456789") == "" {
+			return "", false
+		}
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/~/" + strings.TrimPrefix(strings.TrimPrefix(path, "~"), "/")
+	}
+	u := url.URL{Scheme: SSHScheme, Host: host, Path: path}
+	if user != "" {
+		u.User = url.User(user)
+	}
+	return u.String(), true
+}
+
+// SSHFactory is a DBFactory implementation for creating databases accessed over ssh. Urls are of the form
+// ssh://[user@]host[:port]/path, where a path starting with /~/ is relative to the user's home directory. The dolt
+// transfer command is run on the host, and serves the remotesapi over its stdin and stdout.
+type SSHFactory struct {
+}
+
+// PrepareDB prepares a database accessed over ssh to receive a push
+func (fact SSHFactory) PrepareDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) error {
+	// the transfer command creates the database when it doesn't exist
+	return nil
+}
+
+// CreateDB creates a database accessed over ssh
+func (fact SSHFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]interface{}) (datas.Database, types.ValueReadWriter, tree.NodeStore, error) {
+	if urlObj.Host == "" || strings.Trim(urlObj.Path, "/") == "" {
+		return nil, nil, nil, fmt.Errorf("ssh urls should be of the form ssh://user@host/path or user@host:path, found '%s'", urlObj.String())
+	}
+
+	proc, conns, err := startSSHTransfer(sshCommand(urlObj))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	grpcConn, err := grpc.Dial("passthrough:///"+urlObj.Host,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(onceDialer(conns[0])),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(128*1024*1024)),
+		grpc.WithChainUnaryInterceptor(remotestorage.EventsUnaryClientInterceptor(events.GlobalCollector)),
+		grpc.WithChainUnaryInterceptor(remotestorage.RetryingUnaryClientInterceptor))
+	if err != nil {
+		_ = proc.close()
+		return nil, nil, nil, err
+	}
+
+	httpDial := onceDialer(conns[1])
+	httpFetcher := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			// all requests share the one connection to the transfer command
+			StrictMaxConcurrentStreams: true,
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return httpDial(context.Background(), addr)
+			},
+		},
+	}
+
+	csClient := remotesapi.NewChunkStoreServiceClient(grpcConn)
+	cs, err := remotestorage.NewDoltChunkStoreFromPath(ctx, nbf, urlObj.Path, urlObj.Host, csClient)
+	if err != nil {
+		if msg := proc.errorOutput(); msg != "" {
+			return nil, nil, nil, fmt.Errorf("could not access dolt url '%s': %s", urlObj.String(), msg)
+		}
+		return nil, nil, nil, fmt.Errorf("could not access dolt url '%s': %w", urlObj.String(), err)
+	}
+	cs = cs.WithHTTPFetcher(httpFetcher)
+
+	if _, ok := params[NoCachingParameter]; ok {
+		cs = cs.WithNoopChunkCache()
+	}
+
+	vrw := types.NewValueStore(cs)
+	ns := tree.NewNodeStore(cs)
+	db := datas.NewTypesDatabase(vrw, ns)
+
+	return db, vrw, ns, nil
+}
+
+// sshCommand returns the command that runs the transfer command for the database at |urlObj| on its host
+func sshCommand(urlObj *url.URL) *exec.Cmd {
+	args := strings.Fields(os.Getenv(SSHCommandEnvVar))
+	if len(args) == 0 {
+		args = []string{"ssh"}
+	}
+	if port := urlObj.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+
+	dest := urlObj.Hostname()
+	if urlObj.User != nil {
+		dest = urlObj.User.Username() + "@" + dest
+	}
+
+	execPath := os.Getenv(SSHExecPathEnvVar)
+	if execPath == "" {
+		execPath = "dolt"
+	}
+	args = append(args, dest, execPath+" "+SSHTransferCommand+" "+shellQuote(sshRemotePath(urlObj.Path)))
+
+	return exec.Command(args[0], args[1:]...)
+}
+
+// sshRemotePath returns the path on the host of the database at an ssh url with path |urlPath|. Paths under /~/ are
+// returned relative to the home directory the command is run in.
+func sshRemotePath(urlPath string) string {
+	if urlPath == "/~" {
+		return "."
+	} else if strings.HasPrefix(urlPath, "/~/") {
+		return strings.TrimPrefix(urlPath, "/~/")
+	}
+	return urlPath
+}
+
+// shellQuote quotes |s| as a single argument for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sshProcess is a running ssh command whose stdin and stdout carry the connections to the transfer command
+type sshProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.Closer
+	stderr *limitedBuffer
+
+	once     sync.Once
+	stdinErr error
+}
+
+// startSSHTransfer starts |cmd| and returns the connections multiplexed over its stdin and stdout. The stderr of the
+// command is kept, so that the errors of ssh and of the transfer command can be reported if the connection fails.
+// Prompts, such as for passwords, are written to the terminal by ssh.
+func startSSHTransfer(cmd *exec.Cmd) (*sshProcess, []*connmux.Conn, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	proc := &sshProcess{cmd: cmd, stdin: stdin, stderr: &limitedBuffer{limit: maxSSHErrorOutput}}
+	cmd.Stderr = proc.stderr
+
+	if err = cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to run %s: %w", cmd.Path, err)
+	}
+
+	stream := connmux.Pipes(stdout, stdin, proc.close)
+	return proc, connmux.New(stream, SSHTransferConns), nil
+}
+
+// close closes the stdin of the process, which ends the transfer command, and waits for it to exit
+func (p *sshProcess) close() error {
+	p.once.Do(func() {
+		p.stdinErr = p.stdin.Close()
+		_ = p.cmd.Wait()
+	})
+	return p.stdinErr
+}
+
+// errorOutput ends the process and returns what it wrote to stderr
+func (p *sshProcess) errorOutput() string {
+	_ = p.close()
+	return strings.TrimSpace(p.stderr.String())
+}
+
+const maxSSHErrorOutput = 64 * 1024
+
+// limitedBuffer is a buffer which keeps the first |limit| bytes written to it, and discards the rest
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if rem := b.limit - b.buf.Len(); rem > 0 {
+		if len(p) > rem {
+			b.buf.Write(p[:rem])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// onceDialer returns a dialer which returns |conn| the first time it's called, and fails after that
+func onceDialer(conn net.Conn) func(context.Context, string) (net.Conn, error) {
+	var mu sync.Mutex
+	dialed := false
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if dialed {
+			return nil, errors.New("the connection to the ssh remote was lost")
+		}
+		dialed = true
+		return conn, nil
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHURLFromSCPLike(t *testing.T) {
+	tests := []struct {
+		url         string
+		expectedURL string
+		expectedOk  bool
+	}{
+		{url: "user@host:path/to/repo", expectedURL: "ssh://user@host/~/path/to/repo", expectedOk: true},
+		{url: "user@host:/srv/repo", expectedURL: "ssh://user@host/srv/repo", expectedOk: true},
+		{url: "host:repo", expectedURL: "ssh://host/~/repo", expectedOk: true},
+		{url: "host:~/repo", expectedURL: "ssh://host/~/repo", expectedOk: true},
+		{url: "user@host:5000/repo", expectedURL: "ssh://user@host/~/5000/repo", expectedOk: true},
+		{url: "localhost:50051/org/repo", expectedOk: false},
+		{url: "ssh://user@host/srv/repo", expectedOk: false},
+		{url: "file:///srv/repo", expectedOk: false},
+		{url: "dolthub/museum-collections", expectedOk: false},
+		{url: "C:/repos/repo", expectedOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			sshURL, ok := SSHURLFromSCPLike(test.url)
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expectedURL, sshURL)
+		})
+	}
+}
+
+func TestSSHCommand(t *testing.T) {
+	tests := []struct {
+		url          string
+		sshEnv       string
+		execPathEnv  string
+		expectedArgs []string
+	}{
+		{
+			url:          "ssh://user@host/~/path/to/repo",
+			expectedArgs: []string{"ssh", "user@host", "dolt transfer 'path/to/repo'"},
+		},
+		{
+			url:          "ssh://host:2222/srv/my repo",
+			expectedArgs: []string{"ssh", "-p", "2222", "host", "dolt transfer '/srv/my repo'"},
+		},
+		{
+			url:          "ssh://host/~",
+			sshEnv:       "ssh -i key",
+			execPathEnv:  "/opt/dolt/bin/dolt",
+			expectedArgs: []string{"ssh", "-i", "key", "host", "/opt/dolt/bin/dolt transfer '.'"},
+		},
+		{
+			url:          "ssh://host/srv/it's",
+			expectedArgs: []string{"ssh", "host", `dolt transfer '/srv/it'\''s'`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			t.Setenv(SSHCommandEnvVar, test.sshEnv)
+			t.Setenv(SSHExecPathEnvVar, test.execPathEnv)
+
+			urlObj, err := url.Parse(test.url)
+			require.NoError(t, err)
+
+			cmd := sshCommand(urlObj)
+			assert.Equal(t, test.expectedArgs, cmd.Args)
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -87,22 +88,40 @@ func GetStagedUnstagedTableDeltas(ctx context.Context, roots doltdb.Roots) (stag
 
 // GetTableDeltas returns a slice of TableDelta objects for each table that changed between fromRoot and toRoot.
 // It matches tables across roots by finding Schemas with Column tags in common.
+//
+// Tables are compared by the hashes stored in each root before any of them are loaded: a table with the same name and
+// hash in both roots, and no foreign keys that could have changed around it, is unchanged and is never loaded. This
+// keeps the cost of comparing roots proportional to the number of changed tables rather than the number of tables.
 func GetTableDeltas(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) (deltas []TableDelta, err error) {
 	fromVRW := fromRoot.VRW()
 	fromNS := fromRoot.NodeStore()
 	toVRW := toRoot.VRW()
 	toNS := toRoot.NodeStore()
 
+	fromHashes, err := fromRoot.MapTableHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	toHashes, err := toRoot.MapTableHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fromFkc, err := fromRoot.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	toFkc, err := toRoot.GetForeignKeyCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unchanged := unchangedTables(fromHashes, toHashes, fromFkc, toFkc)
+
 	fromDeltas := make([]TableDelta, 0)
-	err = fromRoot.IterTables(ctx, func(name string, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
-		c, err := fromRoot.GetForeignKeyCollection(ctx)
-		if err != nil {
-			return true, err
-		}
-		fks, _ := c.KeysForTable(name)
+	err = iterChangedTables(ctx, fromRoot, fromHashes, unchanged, func(name string, tbl *doltdb.Table, sch schema.Schema) error {
+		fks, _ := fromFkc.KeysForTable(name)
 		parentSchs, err := getFkParentSchs(ctx, fromRoot, fks...)
 		if err != nil {
-			return false, err
+			return err
 		}
 
 		fromDeltas = append(fromDeltas, TableDelta{
@@ -116,24 +135,18 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) (de
 			ToVRW:            toVRW,
 			ToNodeStore:      toNS,
 		})
-		return
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	toDeltas := make([]TableDelta, 0)
-
-	err = toRoot.IterTables(ctx, func(name string, tbl *doltdb.Table, sch schema.Schema) (stop bool, err error) {
-		c, err := toRoot.GetForeignKeyCollection(ctx)
-		if err != nil {
-			return true, err
-		}
-
-		fks, _ := c.KeysForTable(name)
+	err = iterChangedTables(ctx, toRoot, toHashes, unchanged, func(name string, tbl *doltdb.Table, sch schema.Schema) error {
+		fks, _ := toFkc.KeysForTable(name)
 		parentSchs, err := getFkParentSchs(ctx, toRoot, fks...)
 		if err != nil {
-			return false, err
+			return err
 		}
 
 		toDeltas = append(toDeltas, TableDelta{
@@ -147,7 +160,7 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) (de
 			ToVRW:          toVRW,
 			ToNodeStore:    toNS,
 		})
-		return
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -170,6 +183,54 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) (de
 	return deltas, nil
 }
 
+// unchangedTables returns the names of the tables that are the same in both roots: they have the same hash, and
+// declare no foreign keys in either root, whose parent tables could have changed without changing their hash.
+func unchangedTables(fromHashes, toHashes map[string]hash.Hash, fromFkc, toFkc *doltdb.ForeignKeyCollection) *set.StrSet {
+	withFks := set.NewCaseInsensitiveStrSet(nil)
+	for _, fk := range fromFkc.AllKeys() {
+		withFks.Add(fk.TableName)
+	}
+	for _, fk := range toFkc.AllKeys() {
+		withFks.Add(fk.TableName)
+	}
+
+	unchanged := set.NewStrSet(nil)
+	for name, fromHash := range fromHashes {
+		if toHash, ok := toHashes[name]; ok && toHash == fromHash && !withFks.Contains(name) {
+			unchanged.Add(name)
+		}
+	}
+	return unchanged
+}
+
+// iterChangedTables calls |cb| with each table of |root| that isn't in |unchanged|, in name order.
+func iterChangedTables(ctx context.Context, root *doltdb.RootValue, hashes map[string]hash.Hash, unchanged *set.StrSet, cb func(name string, tbl *doltdb.Table, sch schema.Schema) error) error {
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		if !unchanged.Contains(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tbl, ok, err := root.GetTable(ctx, name)
+		if err != nil {
+			return err
+		} else if !ok {
+			return fmt.Errorf("root found a table with name '%s' but could not load it", name)
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return err
+		}
+		if err = cb(name, tbl, sch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getFkParentSchs(ctx context.Context, root *doltdb.RootValue, fks ...doltdb.ForeignKey) (map[string]schema.Schema, error) {
 	schs := make(map[string]schema.Schema)
 	for _, toFk := range fks {
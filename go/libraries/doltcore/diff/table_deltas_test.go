@@ -19,7 +19,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -66,3 +68,33 @@ func TestMatchTableDeltas(t *testing.T) {
 		require.ElementsMatch(t, expected, received)
 	}
 }
+
+func TestUnchangedTables(t *testing.T) {
+	h1 := hash.Of([]byte("one"))
+	h2 := hash.Of([]byte("two"))
+	fromHashes := map[string]hash.Hash{
+		"same":       h1,
+		"modified":   h1,
+		"dropped":    h1,
+		"child":      h1,
+		"other_case": h1,
+	}
+	toHashes := map[string]hash.Hash{
+		"same":       h1,
+		"modified":   h2,
+		"added":      h1,
+		"child":      h1,
+		"OTHER_CASE": h1,
+	}
+	fromFkc, err := doltdb.NewForeignKeyCollection()
+	require.NoError(t, err)
+	toFkc, err := doltdb.NewForeignKeyCollection(doltdb.ForeignKey{
+		Name:                "fk",
+		TableName:           "Child",
+		ReferencedTableName: "same",
+	})
+	require.NoError(t, err)
+
+	unchanged := unchangedTables(fromHashes, toHashes, fromFkc, toFkc)
+	require.ElementsMatch(t, []string{"same"}, unchanged.AsSlice())
+}
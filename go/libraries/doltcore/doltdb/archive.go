@@ -0,0 +1,313 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const archiveBatchSize = 4096
+
+// Archive moves the data only reachable from commits more than |olderThan| commits behind every ref that reaches
+// them into archive table files, which are smaller but slower to read. Commits themselves, and the data of working
+// sets, are never archived. Each table's data is archived apart from the rest, so that the compression dictionary
+// of each archive is built from the data of a single table. If |rebuild| is set, data that is already archived is
+// archived again, which rebuilds the dictionaries from all of the archived data. It returns the number of chunks
+// archived.
+func (ddb *DoltDB) Archive(ctx context.Context, olderThan int, rebuild bool) (int, error) {
+	if olderThan < 0 {
+		return 0, errors.New("the number of recent commits to keep must not be negative")
+	}
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	archiver, ok := cs.(nbs.ChunkArchiver)
+	if !ok {
+		return 0, chunks.ErrUnsupportedOperation
+	}
+
+	var heads []*Commit
+	hotRoots := make(hash.HashSet)
+	err := ddb.VisitRefsOfType(ctx, ref.HeadRefTypes, func(r ref.DoltRef, _ hash.Hash) error {
+		if tr, ok := r.(ref.TagRef); ok {
+			tag, err := ddb.ResolveTag(ctx, tr)
+			if err != nil {
+				return err
+			}
+			heads = append(heads, tag.Commit)
+			return nil
+		}
+		cm, err := ddb.ResolveCommitRef(ctx, r)
+		if err != nil {
+			return err
+		}
+		heads = append(heads, cm)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	dss, err := ddb.db.Datasets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	err = dss.IterAll(ctx, func(key string, _ hash.Hash) error {
+		if !ref.IsWorkingSet(key) {
+			return nil
+		}
+		ws, err := ddb.ResolveWorkingSet(ctx, ref.NewWorkingSetRef(key))
+		if err != nil {
+			return err
+		}
+		roots := []*RootValue{ws.WorkingRoot(), ws.StagedRoot()}
+		if ms := ws.MergeState(); ms != nil {
+			roots = append(roots, ms.PreMergeWorkingRoot())
+			heads = append(heads, ms.Commit())
+		}
+		for _, root := range roots {
+			if root == nil {
+				continue
+			}
+			h, err := root.HashOf()
+			if err != nil {
+				return err
+			}
+			hotRoots.Insert(h)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// a commit is hot if it is one of the |olderThan| most recent
+	// ancestors of any head, and cold if it is only an older one
+	hot := make(hash.HashSet)
+	seen := make(hash.HashSet)
+	coldRoots := make(hash.HashSet)
+	var coldRootVals []*RootValue
+	for _, head := range heads {
+		height, err := head.Height()
+		if err != nil {
+			return 0, err
+		}
+		recent := make(hash.HashSet)
+		err = walkCommits(ctx, head, recent, func(cm *Commit) (bool, error) {
+			h, err := cm.Height()
+			if err != nil {
+				return false, err
+			}
+			return h+uint64(olderThan) > height, nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		hot.InsertAll(recent)
+	}
+	for _, head := range heads {
+		err = walkCommits(ctx, head, seen, func(*Commit) (bool, error) {
+			return true, nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	for h := range seen {
+		cm, err := ddb.ReadCommit(ctx, h)
+		if err != nil {
+			return 0, err
+		}
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return 0, err
+		}
+		rh, err := root.HashOf()
+		if err != nil {
+			return 0, err
+		}
+		if hot.Has(h) {
+			hotRoots.Insert(rh)
+		} else if !coldRoots.Has(rh) {
+			coldRoots.Insert(rh)
+			coldRootVals = append(coldRootVals, root)
+		}
+	}
+
+	walk, err := types.WalkAddrsForChunkStore(cs)
+	if err != nil {
+		return 0, err
+	}
+	keep, err := reachableChunks(ctx, cs, walk, hotRoots, func(hash.Hash) bool {
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	cold, err := reachableChunks(ctx, cs, walk, coldRoots, func(h hash.Hash) bool {
+		return !keep.Has(h)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(cold) == 0 {
+		return 0, nil
+	}
+	groups, err := archiveGroups(ctx, cs, walk, coldRootVals, cold)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := archiver.ArchiveChunks(ctx, groups, rebuild)
+	if err != nil {
+		return 0, err
+	}
+	return n, ddb.ShallowGC(ctx)
+}
+
+// walkCommits adds |cm| and its ancestors to |visited|. |descend| decides whether each commit and its ancestors
+// are visited.
+func walkCommits(ctx context.Context, cm *Commit, visited hash.HashSet, descend func(*Commit) (bool, error)) error {
+	queue := []*Commit{cm}
+	for len(queue) > 0 {
+		cm, queue = queue[0], queue[1:]
+		h, err := cm.HashOf()
+		if err != nil {
+			return err
+		}
+		if visited.Has(h) {
+			continue
+		}
+		if ok, err := descend(cm); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		visited.Insert(h)
+		for i := 0; i < cm.NumParents(); i++ {
+			parent, err := cm.GetParent(ctx, i)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, parent)
+		}
+	}
+	return nil
+}
+
+// archiveGroups splits |cold| by the table of |roots| whose data each chunk is, in table name order. Chunks that
+// aren't the data of any one table, like the roots themselves, make up the last group.
+func archiveGroups(ctx context.Context, cs chunks.ChunkStore, walk func(chunks.Chunk, func(hash.Hash, bool) error) error, roots []*RootValue, cold hash.HashSet) ([]hash.HashSet, error) {
+	tables := make(map[string]hash.HashSet)
+	for _, root := range roots {
+		tm, err := root.getTableMap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = tmIterAll(ctx, tm, func(name string, addr hash.Hash) {
+			if _, ok := tables[name]; !ok {
+				tables[name] = make(hash.HashSet)
+			}
+			tables[name].Insert(addr)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// a chunk shared by several tables is archived with the first of them
+	grouped := make(hash.HashSet)
+	groups := make([]hash.HashSet, 0, len(names)+1)
+	for _, name := range names {
+		group, err := reachableChunks(ctx, cs, walk, tables[name], func(h hash.Hash) bool {
+			return cold.Has(h) && !grouped.Has(h)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(group) > 0 {
+			grouped.InsertAll(group)
+			groups = append(groups, group)
+		}
+	}
+	rest := make(hash.HashSet)
+	for h := range cold {
+		if !grouped.Has(h) {
+			rest.Insert(h)
+		}
+	}
+	if len(rest) > 0 {
+		groups = append(groups, rest)
+	}
+	return groups, nil
+}
+
+// reachableChunks returns the chunks for which |include| is true that are reachable from |roots| through such chunks.
+func reachableChunks(ctx context.Context, cs chunks.ChunkStore, walk func(chunks.Chunk, func(hash.Hash, bool) error) error, roots hash.HashSet, include func(hash.Hash) bool) (hash.HashSet, error) {
+	visited := make(hash.HashSet)
+	next := make(hash.HashSet)
+	for h := range roots {
+		if include(h) {
+			next.Insert(h)
+		}
+	}
+	for len(next) > 0 {
+		batch := make(hash.HashSet)
+		for h := range next {
+			visited.Insert(h)
+			batch.Insert(h)
+			delete(next, h)
+			if len(batch) == archiveBatchSize {
+				break
+			}
+		}
+
+		var mu sync.Mutex
+		var werr error
+		err := cs.GetMany(ctx, batch, func(_ context.Context, c *chunks.Chunk) {
+			mu.Lock()
+			defer mu.Unlock()
+			if werr != nil {
+				return
+			}
+			werr = walk(*c, func(h hash.Hash, _ bool) error {
+				if !visited.Has(h) && include(h) {
+					next.Insert(h)
+				}
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		if werr != nil {
+			return nil, werr
+		}
+	}
+	return visited, nil
+}
@@ -81,6 +81,12 @@ func (c *Commit) GetCommitMeta(ctx context.Context) (*datas.CommitMeta, error) {
 	return datas.GetCommitMeta(ctx, c.dCommit.NomsValue())
 }
 
+// GetSigningPayload returns the payload the commit's signature is made over, and the signature, which is empty if the
+// commit isn't signed.
+func (c *Commit) GetSigningPayload() ([]byte, string, error) {
+	return datas.GetCommitSigningPayload(c.dCommit.NomsValue())
+}
+
 // DatasParents returns the []*datas.Commit of the commit parents.
 func (c *Commit) DatasParents() []*datas.Commit {
 	return c.parents
@@ -146,7 +152,14 @@ func GetCommitAncestor(ctx context.Context, cm1, cm2 *Commit) (*Commit, error) {
 }
 
 func getCommitAncestorAddr(ctx context.Context, c1, c2 *datas.Commit, vrw1, vrw2 types.ValueReadWriter, ns1, ns2 tree.NodeStore) (hash.Hash, error) {
-	ancestorAddr, ok, err := datas.FindCommonAncestor(ctx, c1, c2, vrw1, vrw2, ns1, ns2)
+	var ancestorAddr hash.Hash
+	var ok bool
+	var err error
+	if g := commitGraphFor(vrw1); g != nil && vrw1 == vrw2 {
+		ancestorAddr, ok, err = g.FindCommonAncestor(ctx, vrw1, c1.Addr(), c2.Addr())
+	} else {
+		ancestorAddr, ok, err = datas.FindCommonAncestor(ctx, c1, c2, vrw1, vrw2, ns1, ns2)
+	}
 	if err != nil {
 		return hash.Hash{}, err
 	}
@@ -227,6 +240,49 @@ func (c *Commit) ResolveRootValue(ctx context.Context) (*RootValue, error) {
 	return c.GetRootValue(ctx)
 }
 
+// TableChanged returns whether the commit changed the table named, which is the case when the table's hash in this
+// commit's root differs from its hash in every parent's root. Only the top-level table hashes are compared, so no table
+// data is read. A merge commit that takes the table unchanged from one of its parents didn't change it. A table that
+// exists in a commit with no parents was changed by that commit.
+func (c *Commit) TableChanged(ctx context.Context, tableName string) (bool, error) {
+	root, err := c.GetRootValue(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	h, ok, err := root.GetTableHash(ctx, tableName)
+	if err != nil {
+		return false, err
+	}
+
+	if c.NumParents() == 0 {
+		return ok, nil
+	}
+
+	for i := 0; i < c.NumParents(); i++ {
+		parent, err := c.GetParent(ctx, i)
+		if err != nil {
+			return false, err
+		}
+
+		parentRoot, err := parent.GetRootValue(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		parentHash, parentOk, err := parentRoot.GetTableHash(ctx, tableName)
+		if err != nil {
+			return false, err
+		}
+
+		if ok == parentOk && h == parentHash {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // PendingCommit represents a commit that hasn't yet been written to storage. It contains a root value and options to
 // use when committing it. Use a PendingCommit when it's important to update the working set and HEAD together
 // atomically, via doltdb.CommitWithWorkingSet
@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// commitGraphs maps the ValueReadWriter of each database that has a commit-graph loaded to its *datas.CommitGraph.
+// They're keyed by the ValueReadWriter, rather than kept on the DoltDB, so that the Commits loaded from a database,
+// which only hold its ValueReadWriter, can use its commit-graph too.
+var commitGraphs sync.Map
+
+// commitGraphRefFilter selects the refs whose commits are added to a commit-graph.
+var commitGraphRefFilter = map[ref.RefType]struct{}{
+	ref.BranchRefType:    {},
+	ref.RemoteRefType:    {},
+	ref.WorkspaceRefType: {},
+}
+
+// SetCommitGraph makes ancestry queries on this database, like finding merge bases and counting the commits between
+// branches, use |g|. A nil |g| stops them from using one.
+func (ddb *DoltDB) SetCommitGraph(g *datas.CommitGraph) {
+	if g == nil {
+		commitGraphs.Delete(ddb.vrw)
+		return
+	}
+	commitGraphs.Store(ddb.vrw, g)
+}
+
+// CommitGraph returns the commit-graph set for this database with SetCommitGraph, or nil if there isn't one.
+func (ddb *DoltDB) CommitGraph() *datas.CommitGraph {
+	return commitGraphFor(ddb.vrw)
+}
+
+// UpdateCommitGraph adds the commits reachable from the branches, remote branches, workspaces and tags of this
+// database that |g| doesn't have yet to it, and returns how many were added.
+func (ddb *DoltDB) UpdateCommitGraph(ctx context.Context, g *datas.CommitGraph) (int, error) {
+	var heads []hash.Hash
+	err := ddb.VisitRefsOfType(ctx, commitGraphRefFilter, func(_ ref.DoltRef, addr hash.Hash) error {
+		heads = append(heads, addr)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	tags, err := ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range tags {
+		heads = append(heads, t.Hash)
+	}
+
+	return g.Add(ctx, ddb.vrw, heads)
+}
+
+func commitGraphFor(vrw types.ValueReadWriter) *datas.CommitGraph {
+	if vrw == nil {
+		return nil
+	}
+	if g, ok := commitGraphs.Load(vrw); ok {
+		return g.(*datas.CommitGraph)
+	}
+	return nil
+}
@@ -0,0 +1,397 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const (
+	// CommitRootsRef is the name of the internal ref of the commit the commit root index of a database is persisted in
+	CommitRootsRef = "commit-roots"
+
+	// commitRootsTable is the table of the commit root index in the root value of its commit
+	commitRootsTable = "commit_roots"
+
+	// defaultCommitRootIndexSize is the number of commits kept in the process wide commit root index. An entry holds
+	// a commit's metadata and one hash per table, so this is a few megabytes for typical schemas.
+	defaultCommitRootIndexSize = 64 * 1024
+
+	// commitRootsFlushSize is the number of commits added to a database's commit root index by queries after which
+	// they're persisted. Commits added by committing are persisted right away.
+	commitRootsFlushSize = 256
+)
+
+// commitRootsSchema is the schema of the persisted commit root index. It has a row for each table in a commit's root
+// value, keyed by the commit's hash and the table's lower case name. A commit without tables has a single row with an
+// empty table name, so that it's known to be indexed.
+var commitRootsSchema, _ = schema.SchemaFromCols(schema.NewColCollection(
+	schema.NewColumn("commit_hash", 0, types.StringKind, true),
+	schema.NewColumn("table_name", 1, types.StringKind, true),
+	schema.NewColumn("name", 2, types.StringKind, false),
+	schema.NewColumn("table_hash", 3, types.StringKind, false),
+))
+
+var commitRoots = NewCommitRootIndex(defaultCommitRootIndexSize)
+
+// CommitRootIndex maps commits to their metadata and the hashes of the tables in their root values. Queries that walk
+// the commit graph, like those on the dolt_history_ and dolt_diff_ system tables, use it to filter commits and find
+// the commits that changed a table without loading every commit's root value on every query. Commits are immutable
+// and addressed by the hash of their contents, so entries never go stale and can be shared between databases. The
+// least recently used commits are evicted once the index is full.
+//
+// The index is also persisted in each database, see DoltDB.CommitRootEntry, so that commits evicted from it, or
+// indexed by an earlier process, don't need their root values loaded again.
+type CommitRootIndex struct {
+	entries *lru.TwoQueueCache[hash.Hash, *CommitRootEntry]
+}
+
+// CommitRootEntry is a commit's entry in a CommitRootIndex.
+type CommitRootEntry struct {
+	Meta *datas.CommitMeta
+	// tables maps lower case table names to the table's exact name and hash.
+	tables map[string]namedTableHash
+}
+
+type namedTableHash struct {
+	name string
+	h    hash.Hash
+}
+
+// NewCommitRootIndex returns a CommitRootIndex holding up to |size| commits.
+func NewCommitRootIndex(size int) *CommitRootIndex {
+	entries, err := lru.New2Q[hash.Hash, *CommitRootEntry](size)
+	if err != nil {
+		panic(err)
+	}
+	return &CommitRootIndex{entries: entries}
+}
+
+// Get returns the entry for the commit with hash |h|, and false if it isn't in the index.
+func (idx *CommitRootIndex) Get(h hash.Hash) (*CommitRootEntry, bool) {
+	return idx.entries.Get(h)
+}
+
+// Add adds |e| to the index as the entry for the commit with hash |h|.
+func (idx *CommitRootIndex) Add(h hash.Hash, e *CommitRootEntry) {
+	idx.entries.Add(h, e)
+}
+
+// TableHash returns the exact name and the hash of the table named |tableName|, matched case-insensitively, and false
+// if the commit has no such table.
+func (e *CommitRootEntry) TableHash(tableName string) (string, hash.Hash, bool) {
+	t, ok := e.tables[strings.ToLower(tableName)]
+	return t.name, t.h, ok
+}
+
+// tableHashesOfRoot returns the tables of |root| keyed by their lower case names.
+func tableHashesOfRoot(ctx context.Context, root *RootValue) (map[string]namedTableHash, error) {
+	hashes, err := root.MapTableHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]namedTableHash, len(hashes))
+	for name, th := range hashes {
+		tables[strings.ToLower(name)] = namedTableHash{name: name, h: th}
+	}
+	return tables, nil
+}
+
+// CommitRootEntry returns the entry for |cm| in the commit root index. If the commit isn't in the process wide index,
+// its entry is read from the index persisted in this database, and only if it isn't there either is the commit's
+// root value loaded to build it. Entries built this way are persisted in batches.
+func (ddb *DoltDB) CommitRootEntry(ctx context.Context, cm *Commit) (*CommitRootEntry, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := commitRoots.Get(h); ok {
+		return e, nil
+	}
+
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tables, ok, err := ddb.persistedRoots.lookup(ctx, ddb, h)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		root, err := cm.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if tables, err = tableHashesOfRoot(ctx, root); err != nil {
+			return nil, err
+		}
+		if err = ddb.persistedRoots.add(ctx, ddb, h, tables, false); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &CommitRootEntry{Meta: meta, tables: tables}
+	commitRoots.Add(h, e)
+	return e, nil
+}
+
+// indexCommit adds |cm|, whose root value is |root|, to the commit root index and persists it. It's called for the
+// commits made to this database, whose root values are already loaded.
+func (ddb *DoltDB) indexCommit(ctx context.Context, cm *Commit, root *RootValue) error {
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+	tables, err := tableHashesOfRoot(ctx, root)
+	if err != nil {
+		return err
+	}
+	commitRoots.Add(h, &CommitRootEntry{Meta: meta, tables: tables})
+	return ddb.persistedRoots.add(ctx, ddb, h, tables, true)
+}
+
+// persistedCommitRoots is the commit root index persisted in a database, in the table commitRootsTable of the root
+// value of the commit that CommitRootsRef points to. Each write replaces that commit with one without parents, so
+// earlier versions of the index are garbage collected. The index is a cache: entries lost when two processes write
+// it at the same time are added back the next time they're needed. It's only persisted in databases of the
+// __DOLT__ format.
+type persistedCommitRoots struct {
+	mu sync.Mutex
+	// loaded is true once the persisted index has been read into rows
+	loaded bool
+	// head is the address of the commit rows was read from or written to
+	head hash.Hash
+	rows prolly.Map
+	// pending are the entries added since rows was last written
+	pending map[hash.Hash]map[string]namedTableHash
+}
+
+func newPersistedCommitRoots() *persistedCommitRoots {
+	return &persistedCommitRoots{pending: make(map[hash.Hash]map[string]namedTableHash)}
+}
+
+// lookup returns the tables of the commit with hash |h| in the persisted index, and false if it isn't indexed.
+func (p *persistedCommitRoots) lookup(ctx context.Context, ddb *DoltDB, h hash.Hash) (map[string]namedTableHash, bool, error) {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return nil, false, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if tables, ok := p.pending[h]; ok {
+		return tables, true, nil
+	}
+	if err := p.load(ctx, ddb); err != nil {
+		return nil, false, err
+	}
+
+	kd, vd := p.rows.Descriptors()
+	kb := val.NewTupleBuilder(kd.PrefixDesc(1))
+	kb.PutString(0, h.String())
+	iter, err := p.rows.IterRange(ctx, prolly.PrefixRange(kb.Build(ddb.ns.Pool()), kb.Desc))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var tables map[string]namedTableHash
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+		if tables == nil {
+			tables = make(map[string]namedTableHash)
+		}
+		lower, _ := kd.GetString(1, k)
+		if lower == "" {
+			continue
+		}
+		name, _ := vd.GetString(0, v)
+		th, _ := vd.GetString(1, v)
+		tables[lower] = namedTableHash{name: name, h: hash.Parse(th)}
+	}
+	return tables, tables != nil, nil
+}
+
+// add adds the tables of the commit with hash |h| to the persisted index. The entries added are written once
+// |flush| is true or enough of them have been added. Only errors writing entries that were flushed are returned:
+// queries add entries to databases that may not be writable, and the index is only a cache, so failing to write
+// their entries just drops them.
+func (p *persistedCommitRoots) add(ctx context.Context, ddb *DoltDB, h hash.Hash, tables map[string]namedTableHash, flush bool) error {
+	if !types.IsFormat_DOLT(ddb.Format()) {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending[h] = tables
+	if flush {
+		return p.flush(ctx, ddb)
+	}
+	if len(p.pending) >= commitRootsFlushSize {
+		if err := p.flush(ctx, ddb); err != nil {
+			p.pending = make(map[hash.Hash]map[string]namedTableHash)
+		}
+	}
+	return nil
+}
+
+// load reads the persisted index into rows, if it hasn't been read or has been written by another process since.
+func (p *persistedCommitRoots) load(ctx context.Context, ddb *DoltDB) error {
+	ds, err := ddb.db.GetDataset(ctx, ref.NewInternalRef(CommitRootsRef).String())
+	if err != nil {
+		return err
+	}
+	addr, ok := ds.MaybeHeadAddr()
+	if p.loaded && addr == p.head {
+		return nil
+	}
+	if !ok {
+		p.rows, err = prolly.NewMapFromTuples(ctx, ddb.ns, commitRootsSchema.GetKeyDescriptor(), commitRootsSchema.GetValueDescriptor())
+		if err != nil {
+			return err
+		}
+		p.loaded, p.head = true, hash.Hash{}
+		return nil
+	}
+
+	dc, err := datas.LoadCommitAddr(ctx, ddb.vrw, addr)
+	if err != nil {
+		return err
+	}
+	cm, err := NewCommit(ctx, ddb.vrw, ddb.ns, dc)
+	if err != nil {
+		return err
+	}
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	tbl, ok, err := root.GetTable(ctx, commitRootsTable)
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.New("commit root index has no table")
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	p.rows = durable.ProllyMapFromIndex(idx)
+	p.loaded, p.head = true, addr
+	return nil
+}
+
+// flush writes the pending entries to the persisted index, merging them with any written by other processes.
+func (p *persistedCommitRoots) flush(ctx context.Context, ddb *DoltDB) error {
+	if err := p.load(ctx, ddb); err != nil {
+		return err
+	}
+
+	mut := p.rows.Mutate()
+	kb := val.NewTupleBuilder(commitRootsSchema.GetKeyDescriptor())
+	vb := val.NewTupleBuilder(commitRootsSchema.GetValueDescriptor())
+	put := func(h hash.Hash, lower string, t namedTableHash) error {
+		kb.PutString(0, h.String())
+		kb.PutString(1, lower)
+		vb.PutString(0, t.name)
+		if t.name == "" {
+			vb.PutString(1, "")
+		} else {
+			vb.PutString(1, t.h.String())
+		}
+		return mut.Put(ctx, kb.Build(ddb.ns.Pool()), vb.Build(ddb.ns.Pool()))
+	}
+	for h, tables := range p.pending {
+		if len(tables) == 0 {
+			if err := put(h, "", namedTableHash{}); err != nil {
+				return err
+			}
+		}
+		for lower, t := range tables {
+			if err := put(h, lower, t); err != nil {
+				return err
+			}
+		}
+	}
+	rows, err := mut.Map(ctx)
+	if err != nil {
+		return err
+	}
+
+	tbl, err := NewTable(ctx, ddb.vrw, ddb.ns, commitRootsSchema, durable.IndexFromProllyMap(rows), nil, nil)
+	if err != nil {
+		return err
+	}
+	root, err := EmptyRootValue(ctx, ddb.vrw, ddb.ns)
+	if err != nil {
+		return err
+	}
+	if root, err = root.PutTable(ctx, commitRootsTable, tbl); err != nil {
+		return err
+	}
+	root, _, err = ddb.writeRootValue(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	meta, err := datas.NewCommitMeta("Dolt System Account", "doltuser@dolthub.com", "commit root index")
+	if err != nil {
+		return err
+	}
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	dc, err := datas.NewRootCommitForValue(ctx, cs, ddb.vrw, ddb.ns, root.nomsValue(), datas.CommitOptions{Meta: meta})
+	if err != nil {
+		return err
+	}
+	r, err := ddb.vrw.WriteValue(ctx, dc.NomsValue())
+	if err != nil {
+		return err
+	}
+
+	// the index isn't replicated, so its commits don't run the commit hooks
+	ds, err := ddb.db.GetDataset(ctx, ref.NewInternalRef(CommitRootsRef).String())
+	if err != nil {
+		return err
+	}
+	if _, err = ddb.db.Database.SetHead(ctx, ds, r.TargetHash()); err != nil {
+		return err
+	}
+
+	p.rows, p.head = rows, r.TargetHash()
+	p.pending = make(map[hash.Hash]map[string]namedTableHash)
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCommitRootIndex(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "main", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	cs, err := NewCommitSpec("main")
+	require.NoError(t, err)
+	init, err := ddb.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	initHash, err := init.HashOf()
+	require.NoError(t, err)
+
+	entry, err := ddb.CommitRootEntry(ctx, init)
+	require.NoError(t, err)
+	assert.Equal(t, "Bill Billerson", entry.Meta.Name)
+	_, _, ok := entry.TableHash("t")
+	assert.False(t, ok)
+
+	again, err := ddb.CommitRootEntry(ctx, init)
+	require.NoError(t, err)
+	assert.Same(t, entry, again)
+
+	root, err := init.GetRootValue(ctx)
+	require.NoError(t, err)
+	root, err = root.CreateEmptyTable(ctx, "MyTable", createTestSchema(t))
+	require.NoError(t, err)
+	expected, ok, err := root.GetTableHash(ctx, "MyTable")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, h, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "add a table")
+	require.NoError(t, err)
+	cm, err := ddb.CommitDanglingWithParentCommits(ctx, h, []*Commit{init}, meta)
+	require.NoError(t, err)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+
+	entry, err = ddb.CommitRootEntry(ctx, cm)
+	require.NoError(t, err)
+	name, th, ok := entry.TableHash("mytable")
+	require.True(t, ok)
+	assert.Equal(t, "MyTable", name)
+	assert.Equal(t, expected, th)
+
+	// committing persisted the index, along with the commit it was queried for before
+	persisted := newPersistedCommitRoots()
+	tables, ok, err := persisted.lookup(ctx, ddb, cmHash)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]namedTableHash{"mytable": {name: "MyTable", h: expected}}, tables)
+	tables, ok, err = persisted.lookup(ctx, ddb, initHash)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Empty(t, tables)
+
+	_, ok, err = persisted.lookup(ctx, ddb, hash.Of([]byte("not a commit")))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// the index commit isn't a branch
+	branches, err := ddb.GetBranches(ctx)
+	require.NoError(t, err)
+	assert.Len(t, branches, 1)
+}
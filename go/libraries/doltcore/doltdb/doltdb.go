@@ -34,6 +34,7 @@ import (
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/datas/pull"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/types/edits"
@@ -72,6 +73,13 @@ type DoltDB struct {
 	db  hooksDatabase
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
+
+	// verifyPulls is true if chunks pulled from this database are checked against their addresses
+	verifyPulls bool
+	// sharedCacheDir is the directory of the shared cache that chunks pulled from this database are written through
+	sharedCacheDir string
+	// persistedRoots is the commit root index persisted in this database
+	persistedRoots *persistedCommitRoots
 }
 
 // DoltDBFromCS creates a DoltDB from a noms chunks.ChunkStore
@@ -80,7 +88,7 @@ func DoltDBFromCS(cs chunks.ChunkStore) *DoltDB {
 	ns := tree.NewNodeStore(cs)
 	db := datas.NewTypesDatabase(vrw, ns)
 
-	return &DoltDB{hooksDatabase{Database: db}, vrw, ns}
+	return &DoltDB{db: hooksDatabase{Database: db}, vrw: vrw, ns: ns, persistedRoots: newPersistedCommitRoots()}
 }
 
 // HackDatasDatabaseFromDoltDB unwraps a DoltDB to a datas.Database.
@@ -122,7 +130,7 @@ func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr
 	if err != nil {
 		return nil, err
 	}
-	return &DoltDB{hooksDatabase{Database: db}, vrw, ns}, nil
+	return &DoltDB{db: hooksDatabase{Database: db}, vrw: vrw, ns: ns, persistedRoots: newPersistedCommitRoots()}, nil
 }
 
 // NomsRoot returns the hash of the noms dataset map
@@ -139,6 +147,16 @@ func (ddb *DoltDB) Has(ctx context.Context, h hash.Hash) (bool, error) {
 	return datas.ChunkStoreFromDatabase(ddb.db).Has(ctx, h)
 }
 
+// StorageSize returns the size in bytes of the table files of the database, or 0 if its storage doesn't report its
+// size.
+func (ddb *DoltDB) StorageSize(ctx context.Context) (uint64, error) {
+	tfs, ok := datas.ChunkStoreFromDatabase(ddb.db).(chunks.TableFileStore)
+	if !ok {
+		return 0, nil
+	}
+	return tfs.Size(ctx)
+}
+
 func (ddb *DoltDB) CSMetricsSummary() string {
 	return datas.GetCSStatSummaryForDB(ddb.db)
 }
@@ -633,6 +651,23 @@ func (ddb *DoltDB) SetHeadToCommit(ctx context.Context, ref ref.DoltRef, cm *Com
 	return ddb.SetHead(ctx, ref, addr)
 }
 
+// SetHeadToCommitIfUnchanged sets the given ref to point at the given commit, as long as it still points at the
+// commit with hash |expected|. The check and the update are atomic. It returns datas.ErrMergeNeeded if the ref
+// points at another commit.
+func (ddb *DoltDB) SetHeadToCommitIfUnchanged(ctx context.Context, ref ref.DoltRef, cm *Commit, expected hash.Hash) error {
+	ds, err := ddb.db.GetDataset(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+	if curr, ok := ds.MaybeHeadAddr(); !ok || curr != expected {
+		return datas.ErrMergeNeeded
+	}
+
+	// WriteCommit only updates the ref if it still points at the head of |ds|
+	_, err = ddb.db.WriteCommit(ctx, ds, cm.dCommit)
+	return err
+}
+
 func (ddb *DoltDB) SetHead(ctx context.Context, ref ref.DoltRef, addr hash.Hash) error {
 	ds, err := ddb.db.GetDataset(ctx, ref.String())
 
@@ -722,7 +757,7 @@ func (ddb *DoltDB) CommitValue(ctx context.Context, dref ref.DoltRef, val types.
 		return nil, err
 	}
 
-	return NewCommit(ctx, ddb.vrw, ddb.ns, dc)
+	return ddb.newIndexedCommit(ctx, dc, val)
 }
 
 // dangling commits are unreferenced by any branch or ref. They are created in the course of programmatic updates
@@ -749,11 +784,18 @@ func (ddb *DoltDB) CommitDanglingWithParentCommits(ctx context.Context, valHash
 	return ddb.CommitDangling(ctx, val, commitOpts)
 }
 
-// CommitDangling creates a new Commit for |val| that is not referenced by any DoltRef.
+// CommitDangling creates a new Commit for |val| that is not referenced by any DoltRef. A commit with no parents
+// starts a new history.
 func (ddb *DoltDB) CommitDangling(ctx context.Context, val types.Value, opts datas.CommitOptions) (*Commit, error) {
 	cs := datas.ChunkStoreFromDatabase(ddb.db)
 
-	dcommit, err := datas.NewCommitForValue(ctx, cs, ddb.vrw, ddb.ns, val, opts)
+	var dcommit *datas.Commit
+	var err error
+	if len(opts.Parents) == 0 {
+		dcommit, err = datas.NewRootCommitForValue(ctx, cs, ddb.vrw, ddb.ns, val, opts)
+	} else {
+		dcommit, err = datas.NewCommitForValue(ctx, cs, ddb.vrw, ddb.ns, val, opts)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -763,7 +805,23 @@ func (ddb *DoltDB) CommitDangling(ctx context.Context, val types.Value, opts dat
 		return nil, err
 	}
 
-	return NewCommit(ctx, ddb.vrw, ddb.ns, dcommit)
+	return ddb.newIndexedCommit(ctx, dcommit, val)
+}
+
+// newIndexedCommit returns the Commit for |dc|, whose root value is |val|, and adds it to the commit root index.
+func (ddb *DoltDB) newIndexedCommit(ctx context.Context, dc *datas.Commit, val types.Value) (*Commit, error) {
+	cm, err := NewCommit(ctx, ddb.vrw, ddb.ns, dc)
+	if err != nil {
+		return nil, err
+	}
+	root, err := newRootValue(ddb.vrw, ddb.ns, val)
+	if err != nil {
+		return nil, err
+	}
+	if err = ddb.indexCommit(ctx, cm, root); err != nil {
+		return nil, err
+	}
+	return cm, nil
 }
 
 // ValueReadWriter returns the underlying noms database as a types.ValueReadWriter.
@@ -1312,7 +1370,7 @@ func (ddb *DoltDB) CommitWithWorkingSet(
 		return nil, err
 	}
 
-	return NewCommit(ctx, ddb.vrw, ddb.ns, dc)
+	return ddb.newIndexedCommit(ctx, dc, commit.Roots.Staged.nomsValue())
 }
 
 // DeleteWorkingSet deletes the working set given
@@ -1326,6 +1384,16 @@ func (ddb *DoltDB) DeleteWorkingSet(ctx context.Context, workingSetRef ref.Worki
 	return err
 }
 
+// BuildTagAtCommit writes a new tag for the commit given without pointing any ref at it, and returns its address. An
+// existing tag can be moved to it with SetHead, which replaces the tag in a single update.
+func (ddb *DoltDB) BuildTagAtCommit(ctx context.Context, c *Commit, meta *datas.TagMeta) (hash.Hash, error) {
+	commitAddr, err := c.HashOf()
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	return ddb.db.BuildNewTag(ctx, commitAddr, datas.TagOptions{Meta: meta})
+}
+
 func (ddb *DoltDB) DeleteTag(ctx context.Context, tag ref.DoltRef) error {
 	err := ddb.deleteRef(ctx, tag, nil)
 
@@ -1438,7 +1506,7 @@ func (ddb *DoltDB) pruneUnreferencedDatasets(ctx context.Context) error {
 
 	var deletes []string
 	_ = dd.IterAll(ctx, func(dsID string, _ hash.Hash) (err error) {
-		if !ref.IsRef(dsID) && !ref.IsWorkingSet(dsID) {
+		if !ref.IsRef(dsID) && !ref.IsWorkingSet(dsID) && !strings.HasPrefix(dsID, stagedWorkingSetsPrefix) {
 			deletes = append(deletes, dsID)
 		}
 		return nil
@@ -1474,7 +1542,40 @@ func (ddb *DoltDB) PullChunks(
 	targetHashes []hash.Hash,
 	statsCh chan pull.Stats,
 ) error {
-	return pullHash(ctx, ddb.db, srcDB.db, targetHashes, tempDir, statsCh)
+	return pullHash(ctx, ddb.db, srcDB.db, targetHashes, tempDir, srcDB.verifyPulls, srcDB.sharedCacheDir, statsCh)
+}
+
+// WithVerifiedPulls returns a copy of this DoltDB which verifies the chunks pulled from it. The data of every chunk is
+// hashed as it's received and checked against the chunk's address, and a pull fails with a
+// *pull.ChunkVerificationError on the first chunk that doesn't match. Since a commit's address covers its metadata,
+// its parents and its root value, this verifies every commit pulled along with the data it references.
+func (ddb *DoltDB) WithVerifiedPulls() *DoltDB {
+	verified := *ddb
+	verified.verifyPulls = true
+	return &verified
+}
+
+// VerifiesPulls returns whether the chunks pulled from this DoltDB are verified against their addresses.
+func (ddb *DoltDB) VerifiesPulls() bool {
+	return ddb.verifyPulls
+}
+
+// WithSharedCache returns a copy of this DoltDB whose chunks are pulled and cloned through the shared cache in |dir|.
+// Chunks and table files already in the cache aren't downloaded, and the table files received are hard linked from the
+// cache into the destination database, so that the databases on a machine pulling from the same remote share them on
+// disk. See nbs.SharedCache.
+func (ddb *DoltDB) WithSharedCache(dir string) *DoltDB {
+	cached := *ddb
+	cached.sharedCacheDir = dir
+	return &cached
+}
+
+// openSharedCache opens the shared cache in |dir| for databases of the format |nbf|. The cache is nil if |dir| is empty.
+func openSharedCache(ctx context.Context, nbf *types.NomsBinFormat, dir string) (*nbs.SharedCache, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	return nbs.OpenSharedCache(ctx, nbf.VersionString(), dir, nbs.NewUnlimitedMemQuotaProvider())
 }
 
 func pullHash(
@@ -1482,6 +1583,8 @@ func pullHash(
 	destDB, srcDB datas.Database,
 	targetHashes []hash.Hash,
 	tempDir string,
+	verify bool,
+	sharedCacheDir string,
 	statsCh chan pull.Stats,
 ) error {
 	srcCS := datas.ChunkStoreFromDatabase(srcDB)
@@ -1495,6 +1598,26 @@ func pullHash(
 		} else if err != nil {
 			return err
 		}
+		puller.SetVerify(verify)
+
+		cache, err := openSharedCache(ctx, srcDB.Format(), sharedCacheDir)
+		if err != nil {
+			return err
+		}
+		if cache != nil {
+			defer cache.Close()
+			puller.SetSharedCache(cache)
+		}
+
+		_, srcDeltas := srcCS.(chunks.DeltaChunkSource)
+		_, destDeltas := destCS.(chunks.DeltaTableFileStore)
+		if srcDeltas || destDeltas {
+			bases, err := findDeltaBases(ctx, srcCS, destCS, targetHashes)
+			if err != nil {
+				return err
+			}
+			puller.SetDeltaBases(bases)
+		}
 
 		return puller.Pull(ctx)
 	} else {
@@ -1502,8 +1625,80 @@ func pullHash(
 	}
 }
 
+const (
+	// maxDeltaBaseDepth is how far back the first-parent history of a commit being pulled is searched for a commit
+	// the destination has
+	maxDeltaBaseDepth  = 256
+	deltaBaseBatchSize = 16
+)
+
+// findDeltaBases pairs each commit in |targetHashes|, and each commit in its first-parent history which |destCS|
+// doesn't have, with the nearest commit of that history which |destCS| does have. The chunks of a commit are sent as
+// deltas against those of the commit it's paired with, where the source or destination supports it.
+func findDeltaBases(ctx context.Context, srcCS, destCS chunks.ChunkStore, targetHashes []hash.Hash) (map[hash.Hash]hash.Hash, error) {
+	vr := types.NewValueStore(srcCS)
+	bases := make(map[hash.Hash]hash.Hash)
+	for _, h := range targetHashes {
+		v, err := vr.ReadValue(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		if ok, err := datas.IsCommit(v); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		history := []hash.Hash{h}
+		for depth := 0; depth < maxDeltaBaseDepth && v != nil; depth += deltaBaseBatchSize {
+			var batch []hash.Hash
+			for len(batch) < deltaBaseBatchSize {
+				parents, err := datas.GetCommitParents(ctx, vr, v)
+				if err != nil {
+					return nil, err
+				}
+				if len(parents) == 0 {
+					v = nil
+					break
+				}
+				batch = append(batch, parents[0].Addr())
+				v = parents[0].NomsValue()
+			}
+			if len(batch) == 0 {
+				break
+			}
+
+			absent, err := destCS.HasMany(ctx, hash.NewHashSet(batch...))
+			if err != nil {
+				return nil, err
+			}
+			for _, addr := range batch {
+				if !absent.Has(addr) {
+					for _, c := range history {
+						bases[c] = addr
+					}
+					v = nil
+					break
+				}
+				history = append(history, addr)
+			}
+		}
+	}
+	return bases, nil
+}
+
 func (ddb *DoltDB) Clone(ctx context.Context, destDB *DoltDB, eventCh chan<- pull.TableFileEvent) error {
-	return pull.Clone(ctx, datas.ChunkStoreFromDatabase(ddb.db), datas.ChunkStoreFromDatabase(destDB.db), eventCh)
+	cache, err := openSharedCache(ctx, ddb.Format(), ddb.sharedCacheDir)
+	if err != nil {
+		return err
+	}
+	if cache != nil {
+		defer cache.Close()
+	}
+	return pull.Clone(ctx, datas.ChunkStoreFromDatabase(ddb.db), datas.ChunkStoreFromDatabase(destDB.db), cache, eventCh)
 }
 
 // Returns |true| if the underlying ChunkStore for this DoltDB implements |chunks.TableFileStore|.
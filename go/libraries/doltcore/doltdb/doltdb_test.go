@@ -366,3 +366,49 @@ func TestLDNoms(t *testing.T) {
 		}
 	}
 }
+
+func TestSetHeadToCommitIfUnchanged(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+	require.NoError(t, ddb.WriteEmptyRepo(ctx, "master", "Bill Billerson", "bigbillieb@fake.horse"))
+
+	master := ref.NewBranchRef("master")
+	init, err := ddb.ResolveCommitRef(ctx, master)
+	require.NoError(t, err)
+	initHash, err := init.HashOf()
+	require.NoError(t, err)
+	root, err := init.GetRootValue(ctx)
+	require.NoError(t, err)
+	_, valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "commit")
+	require.NoError(t, err)
+	head, err := ddb.CommitWithParentCommits(ctx, valHash, master, []*Commit{init}, meta)
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	meta, err = datas.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "rewritten commit")
+	require.NoError(t, err)
+	rewritten, err := ddb.CommitDanglingWithParentCommits(ctx, valHash, []*Commit{init}, meta)
+	require.NoError(t, err)
+	rewrittenHash, err := rewritten.HashOf()
+	require.NoError(t, err)
+
+	// master moved since the rewrite started from its first commit
+	assert.Equal(t, datas.ErrMergeNeeded, ddb.SetHeadToCommitIfUnchanged(ctx, master, rewritten, initHash))
+	cm, err := ddb.ResolveCommitRef(ctx, master)
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, headHash, h)
+
+	require.NoError(t, ddb.SetHeadToCommitIfUnchanged(ctx, master, rewritten, headHash))
+	cm, err = ddb.ResolveCommitRef(ctx, master)
+	require.NoError(t, err)
+	h, err = cm.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, rewrittenHash, h)
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// ExternalTableDef defines a read-only table whose rows are read from the file at |Location| each time the table is
+// scanned. |Format| is "csv" or "parquet", or empty to use the extension of the location. |Columns| is a list of
+// column definitions, as they would be written in a CREATE TABLE statement, or empty to use the columns of the file.
+type ExternalTableDef struct {
+	Name     string
+	Location string
+	Format   string
+	Columns  string
+}
+
+// GetExternalTableDef returns the row of the dolt_external_tables table in |root| that defines the table named
+// |tableName|, matched case-insensitively.
+func GetExternalTableDef(ctx context.Context, root *RootValue, tableName string) (ExternalTableDef, bool, error) {
+	table, found, err := root.GetTable(ctx, ExternalTablesTableName)
+	if err != nil {
+		return ExternalTableDef{}, false, err
+	}
+	if !found || table.Format() == types.Format_LD_1 {
+		// dolt_external_tables is not supported for the legacy storage format.
+		return ExternalTableDef{}, false, nil
+	}
+
+	index, err := table.GetRowData(ctx)
+	if err != nil {
+		return ExternalTableDef{}, false, err
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return ExternalTableDef{}, false, err
+	}
+	keyDesc, valueDesc := sch.GetMapDescriptors()
+
+	if !keyDesc.Equals(val.NewTupleDescriptor(val.Type{Enc: val.StringEnc})) {
+		return ExternalTableDef{}, false, fmt.Errorf("dolt_external_tables had unexpected key type, this should never happen")
+	}
+	nullableString := val.Type{Enc: val.StringEnc, Nullable: true}
+	if !valueDesc.Equals(val.NewTupleDescriptor(nullableString, nullableString, nullableString)) {
+		return ExternalTableDef{}, false, fmt.Errorf("dolt_external_tables had unexpected value type, this should never happen")
+	}
+
+	iter, err := durable.ProllyMapFromIndex(index).IterAll(ctx)
+	if err != nil {
+		return ExternalTableDef{}, false, err
+	}
+
+	for {
+		keyTuple, valueTuple, err := iter.Next(ctx)
+		if err == io.EOF {
+			return ExternalTableDef{}, false, nil
+		} else if err != nil {
+			return ExternalTableDef{}, false, err
+		}
+
+		name, ok := keyDesc.GetString(0, keyTuple)
+		if !ok {
+			return ExternalTableDef{}, false, fmt.Errorf("could not read external table name")
+		}
+		if !strings.EqualFold(name, tableName) {
+			continue
+		}
+
+		def := ExternalTableDef{Name: name}
+		def.Location, _ = valueDesc.GetString(0, valueTuple)
+		def.Format, _ = valueDesc.GetString(1, valueTuple)
+		def.Columns, _ = valueDesc.GetString(2, valueTuple)
+		return def, true, nil
+	}
+}
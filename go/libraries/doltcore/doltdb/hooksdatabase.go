@@ -19,6 +19,7 @@ import (
 	"io"
 	"sync"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
@@ -179,6 +180,24 @@ func (db hooksDatabase) Delete(ctx context.Context, ds datas.Dataset) (datas.Dat
 	return ds, err
 }
 
+func (db hooksDatabase) SetDatasets(ctx context.Context, heads map[string]string, deletes []string, prevHashes map[string]hash.Hash) error {
+	err := db.Database.SetDatasets(ctx, heads, deletes, prevHashes)
+	if err != nil {
+		return err
+	}
+	for id := range heads {
+		if !ref.IsWorkingSet(id) {
+			continue
+		}
+		ds, err := db.Database.GetDataset(ctx, id)
+		if err != nil {
+			return err
+		}
+		db.ExecuteCommitHooks(ctx, ds, true)
+	}
+	return nil
+}
+
 func (db hooksDatabase) UpdateWorkingSet(ctx context.Context, ds datas.Dataset, workingSet datas.WorkingSetSpec, prevHash hash.Hash) (datas.Dataset, error) {
 	ds, err := db.Database.UpdateWorkingSet(ctx, ds, workingSet, prevHash)
 	if err == nil {
@@ -0,0 +1,88 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// MergeDriverAssignment assigns the merge driver named |Driver| to the columns matched by |Target|. A target is
+// either a column, written as "table.column", or a column type name, such as "json" or "bigint".
+type MergeDriverAssignment struct {
+	Target string
+	Driver string
+}
+
+// GetMergeDriverAssignments returns the rows of the dolt_merge_drivers table in |root|, or nil if the table doesn't
+// exist.
+func GetMergeDriverAssignments(ctx context.Context, root *RootValue) ([]MergeDriverAssignment, error) {
+	table, found, err := root.GetTable(ctx, MergeDriversTableName)
+	if err != nil {
+		return nil, err
+	}
+	if !found || table.Format() == types.Format_LD_1 {
+		// dolt_merge_drivers is not supported for the legacy storage format.
+		return nil, nil
+	}
+
+	index, err := table.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keyDesc, valueDesc := sch.GetMapDescriptors()
+
+	if !keyDesc.Equals(val.NewTupleDescriptor(val.Type{Enc: val.StringEnc})) {
+		return nil, fmt.Errorf("dolt_merge_drivers had unexpected key type, this should never happen")
+	}
+	if !valueDesc.Equals(val.NewTupleDescriptor(val.Type{Enc: val.StringEnc, Nullable: true})) {
+		return nil, fmt.Errorf("dolt_merge_drivers had unexpected value type, this should never happen")
+	}
+
+	iter, err := durable.ProllyMapFromIndex(index).IterAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []MergeDriverAssignment
+	for {
+		keyTuple, valueTuple, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		target, ok := keyDesc.GetString(0, keyTuple)
+		if !ok {
+			return nil, fmt.Errorf("could not read merge driver target")
+		}
+		driver, ok := valueDesc.GetString(0, valueTuple)
+		if !ok {
+			continue
+		}
+		assignments = append(assignments, MergeDriverAssignment{Target: target, Driver: driver})
+	}
+	return assignments, nil
+}
@@ -19,9 +19,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
+	"sort"
 	"strings"
 
 	flatbuffers "github.com/dolthub/flatbuffers/v23/go"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/dolthub/dolt/go/gen/fb/serial"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
@@ -824,6 +827,84 @@ func putTable(ctx context.Context, root *RootValue, tName string, ref types.Ref)
 	return root.withStorage(newStorage), nil
 }
 
+// PutTables inserts |tables| by name into the map of tables, replacing any tables that already exist with those names.
+// It's equivalent to calling PutTable for each table, but the tables are serialized concurrently and the map of tables
+// is edited once, which makes it much faster for roots that change many tables at once.
+func (root *RootValue) PutTables(ctx context.Context, tables map[string]*Table) (*RootValue, error) {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		if !IsValidTableName(name) {
+			panic("Don't attempt to put a table with a name that fails the IsValidTableName check")
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	edits := make([]tableEdit, len(names))
+	schChanged := make([]bool, len(names))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(runtime.GOMAXPROCS(0))
+	for i := range names {
+		i := i
+		eg.Go(func() error {
+			table := tables[names[i]]
+			changed, err := schemaChanged(egCtx, root, names[i], table)
+			if err != nil {
+				return err
+			}
+			schChanged[i] = changed
+
+			tableRef, err := durable.RefFromNomsTable(egCtx, table.table)
+			if err != nil {
+				return err
+			}
+			edits[i] = tableEdit{name: names[i], ref: &tableRef}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Tag uniqueness is only checked for tables whose schema changed. As with PutTable, each table is checked against
+	// the tags of the root it's put in, which has the tables before it in the batch.
+	var existing schema.TagMapping
+	for i, name := range names {
+		if !schChanged[i] {
+			continue
+		}
+		if existing == nil {
+			var err error
+			existing, err = GetAllTagsForRoots(ctx, root)
+			if err != nil {
+				return nil, err
+			}
+		}
+		sch, err := tables[name].GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkTagsUnused(sch, name, existing); err != nil {
+			return nil, err
+		}
+		// the tags of the table this one replaces are no longer used
+		for tag, tblName := range existing {
+			if tblName == name {
+				existing.Remove(tag)
+			}
+		}
+		for _, tag := range sch.GetAllCols().Tags {
+			existing.Add(tag, name)
+		}
+	}
+
+	newStorage, err := root.st.EditTablesMap(ctx, root.vrw, root.ns, edits)
+	if err != nil {
+		return nil, err
+	}
+	return root.withStorage(newStorage), nil
+}
+
 // CreateEmptyTable creates an empty table in this root with the name and schema given, returning the new root value.
 func (root *RootValue) CreateEmptyTable(ctx context.Context, tName string, sch schema.Schema) (*RootValue, error) {
 	empty, err := durable.NewEmptyIndex(ctx, root.vrw, root.ns, sch)
@@ -1081,26 +1162,10 @@ func FilterIgnoredTables(ctx context.Context, tables []string, roots Roots) (ign
 
 // validateTagUniqueness checks for tag collisions between the given table and the set of tables in then given root.
 func validateTagUniqueness(ctx context.Context, root *RootValue, tableName string, table *Table) error {
-	prev, ok, err := root.GetTable(ctx, tableName)
-	if err != nil {
+	changed, err := schemaChanged(ctx, root, tableName, table)
+	if err != nil || !changed {
 		return err
 	}
-	if ok {
-		prevHash, err := prev.GetSchemaHash(ctx)
-		if err != nil {
-			return err
-		}
-
-		newHash, err := table.GetSchemaHash(ctx)
-		if err != nil {
-			return err
-		}
-
-		// short-circuit if schema unchanged
-		if prevHash == newHash {
-			return nil
-		}
-	}
 
 	sch, err := table.GetSchema(ctx)
 	if err != nil {
@@ -1111,9 +1176,33 @@ func validateTagUniqueness(ctx context.Context, root *RootValue, tableName strin
 	if err != nil {
 		return err
 	}
+	return checkTagsUnused(sch, tableName, existing)
+}
+
+// schemaChanged returns whether the schema of |table| differs from the schema of the table named |tableName| in |root|,
+// or true if |root| has no such table.
+func schemaChanged(ctx context.Context, root *RootValue, tableName string, table *Table) (bool, error) {
+	prev, ok, err := root.GetTable(ctx, tableName)
+	if err != nil || !ok {
+		return true, err
+	}
+
+	prevHash, err := prev.GetSchemaHash(ctx)
+	if err != nil {
+		return false, err
+	}
+	newHash, err := table.GetSchemaHash(ctx)
+	if err != nil {
+		return false, err
+	}
+	return prevHash != newHash, nil
+}
 
+// checkTagsUnused returns an error if any of the column tags of |sch| are used by a table other than |tableName| in
+// |existing|.
+func checkTagsUnused(sch schema.Schema, tableName string, existing schema.TagMapping) error {
 	var ee []string
-	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		name, ok := existing.Get(tag)
 		if ok && name != tableName {
 			ee = append(ee, schema.ErrTagPrevUsed(tag, col.Name, name).Error())
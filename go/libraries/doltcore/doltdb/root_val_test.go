@@ -0,0 +1,79 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestPutTables(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer ddb.Close()
+
+	root, err := EmptyRootValue(ctx, ddb.vrw, ddb.ns)
+	require.NoError(t, err)
+	sch := createTestSchema(t)
+	tbl, err := CreateTestTable(ddb.vrw, ddb.ns, sch, createTestRowData(t, ddb.vrw, ddb.ns, sch))
+	require.NoError(t, err)
+
+	// putting tables in a batch is the same as putting them one at a time
+	batched, err := root.PutTables(ctx, map[string]*Table{"people": tbl})
+	require.NoError(t, err)
+	single, err := root.PutTable(ctx, "people", tbl)
+	require.NoError(t, err)
+	batchedHash, err := batched.HashOf()
+	require.NoError(t, err)
+	singleHash, err := single.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, singleHash, batchedHash)
+
+	// replacing a table with the same schema succeeds
+	_, err = batched.PutTables(ctx, map[string]*Table{"people": tbl})
+	require.NoError(t, err)
+
+	// tags must be unique among the tables of the root and the batch
+	_, err = batched.PutTables(ctx, map[string]*Table{"others": tbl})
+	assert.Error(t, err)
+	_, err = root.PutTables(ctx, map[string]*Table{"people": tbl, "others": tbl})
+	assert.Error(t, err)
+
+	// the tags of a table replaced earlier in the batch can be used by the tables after it
+	otherSch, err := schema.SchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("pk", 9999, types.IntKind, true, schema.NotNullConstraint{})))
+	require.NoError(t, err)
+	otherTbl, err := NewEmptyTable(ctx, ddb.vrw, ddb.ns, otherSch)
+	require.NoError(t, err)
+	batched, err = batched.PutTables(ctx, map[string]*Table{"people": otherTbl, "researchers": tbl})
+	require.NoError(t, err)
+	single, err = single.PutTable(ctx, "people", otherTbl)
+	require.NoError(t, err)
+	single, err = single.PutTable(ctx, "researchers", tbl)
+	require.NoError(t, err)
+	batchedHash, err = batched.HashOf()
+	require.NoError(t, err)
+	singleHash, err = single.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, singleHash, batchedHash)
+}
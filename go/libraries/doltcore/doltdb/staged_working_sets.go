@@ -0,0 +1,221 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// A transaction which writes working sets in more than one database commits them in two phases, so that a crash
+// can't leave only some of them written. One of the databases is the coordinator of the transaction.
+//
+// First every working set is staged: it's written to a dataset of its database named for the transaction, which
+// leaves the working set itself unchanged. Then the working sets staged in the coordinator replace its working sets,
+// and a commit record for the transaction is written to it, in a single update. This is the point at which the
+// transaction commits. Last, the working sets staged in the other databases replace theirs, and the commit record is
+// removed. A transaction that fails before it commits removes the working sets it staged.
+//
+// After a crash, the working sets staged in a database are published if the coordinator of their transaction has
+// its commit record, and are discarded if it doesn't. RecoverStagedWorkingSets does this. The commit record is kept,
+// as databases which weren't recovered with the coordinator may still have working sets staged for it.
+const (
+	stagedWorkingSetsPrefix = "transactions/"
+	commitRecordName        = "committed"
+)
+
+// stagedTransactionID returns the prefix of the IDs of the datasets of the transaction |txID| coordinated by the
+// database named |coordinator|. The name of the coordinator is hex encoded, as database names needn't be valid in
+// dataset IDs.
+func stagedTransactionID(txID, coordinator string) string {
+	return stagedWorkingSetsPrefix + txID + "/" + hex.EncodeToString([]byte(coordinator)) + "/"
+}
+
+// parseStagedTransactionID returns the transaction and the coordinator of the dataset |dsID|, and whether it's a
+// dataset of a transaction.
+func parseStagedTransactionID(dsID string) (txID, coordinator, rest string, ok bool) {
+	if !strings.HasPrefix(dsID, stagedWorkingSetsPrefix) {
+		return "", "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(dsID, stagedWorkingSetsPrefix), "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	name, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return "", "", "", false
+	}
+	return parts[0], string(name), parts[2], true
+}
+
+// StageWorkingSet writes |workingSet| to a dataset staging it for the transaction |txID| coordinated by the database
+// named |coordinator|, without changing the working set itself. Staged working sets aren't replicated.
+func (ddb *DoltDB) StageWorkingSet(ctx context.Context, txID, coordinator string, workingSet *WorkingSet, meta *datas.WorkingSetMeta) error {
+	ds, err := ddb.db.GetDataset(ctx, stagedTransactionID(txID, coordinator)+workingSet.Ref().String())
+	if err != nil {
+		return err
+	}
+
+	workingRootRef, stagedRef, mergeState, err := workingSet.writeValues(ctx, ddb)
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.db.Database.UpdateWorkingSet(ctx, ds, datas.WorkingSetSpec{
+		Meta:        meta,
+		WorkingRoot: workingRootRef,
+		StagedRoot:  stagedRef,
+		MergeState:  mergeState,
+	}, hash.Hash{})
+	return err
+}
+
+// PublishStagedWorkingSets replaces the working sets staged for the transaction |txID| with the staged ones, in a
+// single update. If this database is the coordinator of the transaction, the update also writes its commit record. If
+// |prevHashes| has a hash for a working set, the working set must still have it or ErrOptimisticLockFailed is
+// returned.
+func (ddb *DoltDB) PublishStagedWorkingSets(
+	ctx context.Context,
+	txID, coordinator string,
+	isCoordinator bool,
+	prevHashes map[ref.WorkingSetRef]hash.Hash,
+	replicationStatus *ReplicationStatusController,
+) error {
+	staged, err := ddb.stagedWorkingSets(ctx, txID, coordinator)
+	if err != nil {
+		return err
+	}
+
+	prefix := stagedTransactionID(txID, coordinator)
+	heads := make(map[string]string, len(staged)+1)
+	deletes := make([]string, 0, len(staged))
+	prev := make(map[string]hash.Hash, len(prevHashes))
+	for _, id := range staged {
+		wsID := strings.TrimPrefix(id, prefix)
+		heads[wsID] = id
+		deletes = append(deletes, id)
+		if h, ok := prevHashes[ref.NewWorkingSetRef(wsID)]; ok {
+			prev[wsID] = h
+		}
+	}
+	if isCoordinator {
+		if len(staged) == 0 {
+			return fmt.Errorf("transaction %s has no working sets staged in its coordinator", txID)
+		}
+		heads[prefix+commitRecordName] = staged[0]
+	}
+	if len(heads) == 0 {
+		return nil
+	}
+	return ddb.db.withReplicationStatusController(replicationStatus).SetDatasets(ctx, heads, deletes, prev)
+}
+
+// DeleteStagedWorkingSets removes the working sets staged for the transaction |txID|, and its commit record if this
+// database is its coordinator.
+func (ddb *DoltDB) DeleteStagedWorkingSets(ctx context.Context, txID, coordinator string) error {
+	deletes, err := ddb.stagedWorkingSets(ctx, txID, coordinator)
+	if err != nil {
+		return err
+	}
+	prefix := stagedTransactionID(txID, coordinator)
+	if ok, err := ddb.hasDataset(ctx, prefix+commitRecordName); err != nil {
+		return err
+	} else if ok {
+		deletes = append(deletes, prefix+commitRecordName)
+	}
+	if len(deletes) == 0 {
+		return nil
+	}
+	return ddb.db.SetDatasets(ctx, nil, deletes, nil)
+}
+
+// stagedWorkingSets returns the IDs of the datasets of the working sets staged for the transaction |txID|.
+func (ddb *DoltDB) stagedWorkingSets(ctx context.Context, txID, coordinator string) ([]string, error) {
+	datasets, err := ddb.db.Datasets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := stagedTransactionID(txID, coordinator)
+	var staged []string
+	err = datasets.IterAll(ctx, func(id string, _ hash.Hash) error {
+		if strings.HasPrefix(id, prefix) && id != prefix+commitRecordName {
+			staged = append(staged, id)
+		}
+		return nil
+	})
+	return staged, err
+}
+
+func (ddb *DoltDB) hasDataset(ctx context.Context, id string) (bool, error) {
+	ds, err := ddb.db.GetDataset(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return ds.HasHead(), nil
+}
+
+// stagedTransaction is a transaction with working sets staged in a database
+type stagedTransaction struct {
+	txID, coordinator string
+}
+
+// RecoverStagedWorkingSets finishes the transactions left with working sets staged in |dbs|, which are keyed by
+// database name, by a crash. The working sets of a transaction are published if its coordinator has its commit
+// record, and are discarded otherwise. The working sets of a transaction whose coordinator isn't in |dbs| are left
+// staged. It must be called before any transaction is committed to the databases.
+func RecoverStagedWorkingSets(ctx context.Context, dbs map[string]*DoltDB) error {
+	for name, ddb := range dbs {
+		datasets, err := ddb.db.Datasets(ctx)
+		if err != nil {
+			return err
+		}
+		txs := make(map[stagedTransaction]bool)
+		err = datasets.IterAll(ctx, func(id string, _ hash.Hash) error {
+			if txID, coordinator, rest, ok := parseStagedTransactionID(id); ok && rest != commitRecordName {
+				txs[stagedTransaction{txID: txID, coordinator: coordinator}] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for tx := range txs {
+			cdb, ok := dbs[tx.coordinator]
+			if !ok {
+				continue
+			}
+			committed, err := cdb.hasDataset(ctx, stagedTransactionID(tx.txID, tx.coordinator)+commitRecordName)
+			if err != nil {
+				return err
+			}
+			if committed && tx.coordinator != name {
+				err = ddb.PublishStagedWorkingSets(ctx, tx.txID, tx.coordinator, false, nil, nil)
+			} else if !committed {
+				err = ddb.DeleteStagedWorkingSets(ctx, tx.txID, tx.coordinator)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to recover transaction %s in database %s: %w", tx.txID, name, err)
+			}
+		}
+	}
+	return nil
+}
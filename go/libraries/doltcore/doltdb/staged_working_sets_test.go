@@ -0,0 +1,150 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRecoverStagedWorkingSets(t *testing.T) {
+	ctx := context.Background()
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef("master"))
+	require.NoError(t, err)
+	meta := &datas.WorkingSetMeta{Name: "Bill Billerson", Email: "bigbillieb@fake.horse"}
+
+	newDB := func(t *testing.T) *DoltDB {
+		ddb, err := LoadDoltDB(ctx, types.Format_Default, InMemDoltDB, filesys.LocalFS)
+		require.NoError(t, err)
+		t.Cleanup(func() { ddb.Close() })
+		require.NoError(t, ddb.WriteEmptyRepo(ctx, "master", "Bill Billerson", "bigbillieb@fake.horse"))
+		return ddb
+	}
+	// withTable returns the working set of master with a table added to it
+	withTable := func(t *testing.T, ddb *DoltDB) *WorkingSet {
+		cm, err := ddb.ResolveCommitRef(ctx, ref.NewBranchRef("master"))
+		require.NoError(t, err)
+		root, err := cm.GetRootValue(ctx)
+		require.NoError(t, err)
+		tbl, err := NewEmptyTable(ctx, ddb.ValueReadWriter(), ddb.NodeStore(), createTestSchema(t))
+		require.NoError(t, err)
+		root, err = root.PutTable(ctx, "t", tbl)
+		require.NoError(t, err)
+		return EmptyWorkingSet(wsRef).WithWorkingRoot(root).WithStagedRoot(root)
+	}
+	hasTable := func(t *testing.T, ddb *DoltDB) bool {
+		ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+		if errors.Is(err, ErrWorkingSetNotFound) {
+			return false
+		}
+		require.NoError(t, err)
+		ok, err := ws.WorkingRoot().HasTable(ctx, "t")
+		require.NoError(t, err)
+		return ok
+	}
+	transactionDatasets := func(t *testing.T, ddb *DoltDB) []string {
+		datasets, err := ddb.db.Datasets(ctx)
+		require.NoError(t, err)
+		var ids []string
+		require.NoError(t, datasets.IterAll(ctx, func(id string, _ hash.Hash) error {
+			if strings.HasPrefix(id, stagedWorkingSetsPrefix) {
+				ids = append(ids, id)
+			}
+			return nil
+		}))
+		return ids
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		coordinator, participant := newDB(t), newDB(t)
+		require.NoError(t, coordinator.StageWorkingSet(ctx, "tx", "a", withTable(t, coordinator), meta))
+		require.NoError(t, participant.StageWorkingSet(ctx, "tx", "a", withTable(t, participant), meta))
+		assert.False(t, hasTable(t, coordinator))
+		assert.False(t, hasTable(t, participant))
+
+		require.NoError(t, coordinator.PublishStagedWorkingSets(ctx, "tx", "a", true, nil, nil))
+		require.NoError(t, participant.PublishStagedWorkingSets(ctx, "tx", "a", false, nil, nil))
+		require.NoError(t, coordinator.DeleteStagedWorkingSets(ctx, "tx", "a"))
+		assert.True(t, hasTable(t, coordinator))
+		assert.True(t, hasTable(t, participant))
+		assert.Empty(t, transactionDatasets(t, coordinator))
+		assert.Empty(t, transactionDatasets(t, participant))
+	})
+
+	t.Run("working set changed", func(t *testing.T) {
+		coordinator := newDB(t)
+		require.NoError(t, coordinator.StageWorkingSet(ctx, "tx", "a", withTable(t, coordinator), meta))
+		prev := map[ref.WorkingSetRef]hash.Hash{wsRef: hash.Of([]byte("not the working set"))}
+		err := coordinator.PublishStagedWorkingSets(ctx, "tx", "a", true, prev, nil)
+		assert.True(t, errors.Is(err, datas.ErrOptimisticLockFailed))
+		assert.False(t, hasTable(t, coordinator))
+		require.NoError(t, coordinator.DeleteStagedWorkingSets(ctx, "tx", "a"))
+		assert.Empty(t, transactionDatasets(t, coordinator))
+	})
+
+	tests := []struct {
+		name      string
+		committed bool
+	}{
+		{name: "crash before commit", committed: false},
+		{name: "crash after commit", committed: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			coordinator, participant := newDB(t), newDB(t)
+			require.NoError(t, coordinator.StageWorkingSet(ctx, "tx", "a", withTable(t, coordinator), meta))
+			require.NoError(t, participant.StageWorkingSet(ctx, "tx", "a", withTable(t, participant), meta))
+			if test.committed {
+				require.NoError(t, coordinator.PublishStagedWorkingSets(ctx, "tx", "a", true, nil, nil))
+			}
+
+			dbs := map[string]*DoltDB{"a": coordinator, "b": participant}
+			require.NoError(t, RecoverStagedWorkingSets(ctx, dbs))
+			assert.Equal(t, test.committed, hasTable(t, coordinator))
+			assert.Equal(t, test.committed, hasTable(t, participant))
+			assert.Empty(t, transactionDatasets(t, participant))
+			if test.committed {
+				// the commit record is kept for databases that weren't recovered
+				assert.Equal(t, []string{stagedTransactionID("tx", "a") + commitRecordName}, transactionDatasets(t, coordinator))
+			} else {
+				assert.Empty(t, transactionDatasets(t, coordinator))
+			}
+
+			// recovering again changes nothing
+			require.NoError(t, RecoverStagedWorkingSets(ctx, dbs))
+			assert.Equal(t, test.committed, hasTable(t, coordinator))
+			assert.Equal(t, test.committed, hasTable(t, participant))
+		})
+	}
+
+	t.Run("coordinator not loaded", func(t *testing.T) {
+		participant := newDB(t)
+		require.NoError(t, participant.StageWorkingSet(ctx, "tx", "a", withTable(t, participant), meta))
+		require.NoError(t, RecoverStagedWorkingSets(ctx, map[string]*DoltDB{"b": participant}))
+		assert.False(t, hasTable(t, participant))
+		assert.Len(t, transactionDatasets(t, participant), 1)
+	})
+}
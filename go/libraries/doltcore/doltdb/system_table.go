@@ -151,6 +151,8 @@ var writeableSystemTables = []string{
 	SchemasTableName,
 	ProceduresTableName,
 	IgnoreTableName,
+	MergeDriversTableName,
+	ExternalTablesTableName,
 }
 
 var persistedSystemTables = []string{
@@ -159,6 +161,8 @@ var persistedSystemTables = []string{
 	SchemasTableName,
 	ProceduresTableName,
 	IgnoreTableName,
+	MergeDriversTableName,
+	ExternalTablesTableName,
 }
 
 var generatedSystemTables = []string{
@@ -171,6 +175,9 @@ var generatedSystemTables = []string{
 	CommitAncestorsTableName,
 	StatusTableName,
 	RemotesTableName,
+	StatementStatsTableName,
+	StorageUsageTableName,
+	OperationStatusTableName,
 }
 
 var generatedSystemViewPrefixes = []string{
@@ -306,7 +313,22 @@ const (
 	// TagsTableName is the tags table name
 	TagsTableName = "dolt_tags"
 
+	// StatementStatsTableName is the statement stats system table name
+	StatementStatsTableName = "dolt_statement_stats"
+
+	// StorageUsageTableName is the storage usage system table name
+	StorageUsageTableName = "dolt_storage_usage"
+
+	// OperationStatusTableName is the operation status system table name
+	OperationStatusTableName = "dolt_operation_status"
+
 	IgnoreTableName = "dolt_ignore"
+
+	// MergeDriversTableName is the name of the system table that configures column merge drivers
+	MergeDriversTableName = "dolt_merge_drivers"
+
+	// ExternalTablesTableName is the name of the system table that defines read-only tables over external files
+	ExternalTablesTableName = "dolt_external_tables"
 )
 
 const (
@@ -0,0 +1,146 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const verifyBatchSize = 4096
+
+// VerifyReport is the result of checking a database with Verify.
+type VerifyReport struct {
+	// Root is the root hash of the database
+	Root hash.Hash
+	// TableFiles is the number of table files in the manifest of the database, or zero if it doesn't have one
+	TableFiles int
+	// Refs is the number of refs that were checked, including working sets and remote tracking refs
+	Refs int
+	// Chunks is the number of chunks reachable from the refs
+	Chunks int
+	// Problems describes each problem that was found
+	Problems []string
+}
+
+// Verify checks the integrity of the database. It checks that the table files in its manifest can be read, that
+// every chunk reachable from its refs is present, and that the hash of every one of those chunks matches its address.
+// Problems with the data are described in the report rather than returned as errors, so that a single check reports
+// all of them.
+func (ddb *DoltDB) Verify(ctx context.Context) (VerifyReport, error) {
+	var report VerifyReport
+	cs := datas.ChunkStoreFromDatabase(ddb.db)
+	root, err := cs.Root(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Root = root
+
+	if tfs, ok := cs.(chunks.TableFileStore); ok {
+		manifestRoot, tableFiles, _, err := tfs.Sources(ctx)
+		if err != nil {
+			return report, err
+		}
+		if manifestRoot != root {
+			report.Problems = append(report.Problems, fmt.Sprintf("the root of the manifest, %s, is not the root of the database, %s", manifestRoot, root))
+		}
+		report.TableFiles = len(tableFiles)
+		for _, tf := range tableFiles {
+			rd, _, err := tf.Open(ctx)
+			if err != nil {
+				report.Problems = append(report.Problems, fmt.Sprintf("table file %s in the manifest can't be read: %s", tf.FileID(), err))
+				continue
+			}
+			_ = rd.Close()
+		}
+	}
+
+	walk, err := types.WalkAddrsForChunkStore(cs)
+	if err != nil {
+		return report, err
+	}
+	dss, err := ddb.db.Datasets(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	visited := make(hash.HashSet)
+	err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+		report.Refs++
+		return verifyReachable(ctx, cs, walk, id, addr, visited, &report)
+	})
+	if err != nil {
+		return report, err
+	}
+	report.Chunks = len(visited)
+	return report, nil
+}
+
+// verifyReachable checks the chunks reachable from |addr|, the head of the ref |id|, that aren't in |visited|, and adds
+// them to it.
+func verifyReachable(ctx context.Context, cs chunks.ChunkStore, walk func(chunks.Chunk, func(hash.Hash, bool) error) error, id string, addr hash.Hash, visited hash.HashSet, report *VerifyReport) error {
+	next := make(hash.HashSet)
+	if !visited.Has(addr) {
+		next.Insert(addr)
+	}
+	for len(next) > 0 {
+		batch := make(hash.HashSet)
+		for h := range next {
+			visited.Insert(h)
+			batch.Insert(h)
+			delete(next, h)
+			if len(batch) == verifyBatchSize {
+				break
+			}
+		}
+
+		var mu sync.Mutex
+		found := make(hash.HashSet)
+		err := cs.GetMany(ctx, batch, func(_ context.Context, c *chunks.Chunk) {
+			mu.Lock()
+			defer mu.Unlock()
+			found.Insert(c.Hash())
+			if actual := hash.Of(c.Data()); actual != c.Hash() {
+				report.Problems = append(report.Problems, fmt.Sprintf("ref %s: chunk %s has the hash %s", id, c.Hash(), actual))
+				return
+			}
+			err := walk(*c, func(h hash.Hash, _ bool) error {
+				if !visited.Has(h) {
+					next.Insert(h)
+				}
+				return nil
+			})
+			if err != nil {
+				report.Problems = append(report.Problems, fmt.Sprintf("ref %s: chunk %s can't be read: %s", id, c.Hash(), err))
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		for h := range batch {
+			if !found.Has(h) {
+				report.Problems = append(report.Problems, fmt.Sprintf("ref %s: chunk %s is missing", id, h))
+			}
+		}
+	}
+	return nil
+}
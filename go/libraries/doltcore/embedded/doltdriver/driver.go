@@ -0,0 +1,436 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package doltdriver is a database/sql driver for dolt databases opened in process with the embedded package.
+// Importing it registers the driver as "dolt":
+//
+//	db, err := sql.Open("dolt", "file:///path/to/dbs?database=mydb&branch=main")
+//
+// The path of the data source name is the directory of a dolt database, or of a directory holding dolt databases in
+// its subdirectories. Its parameters are:
+//
+//	database     the database connections use, which defaults to the first database found
+//	branch       the branch connections check out, which defaults to the database's default branch
+//	asof         a branch, tag or commit that connections read from, which can't be combined with |branch|. The
+//	             connections are read only unless it's a branch.
+//	commitname   the name of the author of commits, which defaults to user.name in the dolt config
+//	commitemail  the email of the author of commits, which defaults to user.email in the dolt config
+//	readonly     if true, connections can't write to the databases
+package doltdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/embedded"
+)
+
+// DriverName is the name the driver is registered with.
+const DriverName = "dolt"
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver is a database/sql driver for dolt databases.
+type Driver struct{}
+
+var _ driver.Driver = (*Driver)(nil)
+var _ driver.DriverContext = (*Driver)(nil)
+
+// Open implements driver.Driver. The connection opens the databases named by |dsn| for itself, so sql.DB, which opens
+// its connections with a shared Connector, should be preferred.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	c, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.Connect(context.Background())
+	if err != nil {
+		_ = c.(*Connector).Close()
+		return nil, err
+	}
+	conn.(*Conn).closeConnector = true
+	return conn, nil
+}
+
+// OpenConnector implements driver.DriverContext.
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	db, err := embedded.Open(context.Background(), cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &Connector{driver: d, db: db, cfg: cfg}, nil
+}
+
+// Config is a parsed data source name.
+type Config struct {
+	embedded.Config
+	// Branch is the branch connections check out.
+	Branch string
+	// AsOf is the revision connections read from.
+	AsOf string
+}
+
+// ParseDSN parses a data source name of the form file:///path/to/dbs?param=value.
+func ParseDSN(dsn string) (Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return Config{}, err
+	}
+	if u.Scheme != "file" {
+		return Config{}, fmt.Errorf("unsupported data source name '%s'; expected file:///path/to/dbs", dsn)
+	}
+
+	var cfg Config
+	cfg.Directory = u.Host + u.Path
+	if cfg.Directory == "" {
+		return Config{}, fmt.Errorf("data source name '%s' has no path", dsn)
+	}
+
+	q := u.Query()
+	cfg.Database = q.Get("database")
+	cfg.Branch = q.Get("branch")
+	cfg.AsOf = q.Get("asof")
+	cfg.CommitName = q.Get("commitname")
+	cfg.CommitEmail = q.Get("commitemail")
+	if ro := q.Get("readonly"); ro != "" {
+		if cfg.ReadOnly, err = strconv.ParseBool(ro); err != nil {
+			return Config{}, fmt.Errorf("invalid value '%s' for readonly", ro)
+		}
+	}
+	if cfg.Branch != "" && cfg.AsOf != "" {
+		return Config{}, errors.New("branch and asof can't both be set")
+	}
+	return cfg, nil
+}
+
+// Connector creates connections to an embedded.DB.
+type Connector struct {
+	driver *Driver
+	db     *embedded.DB
+	cfg    Config
+}
+
+var _ driver.Connector = (*Connector)(nil)
+var _ io.Closer = (*Connector)(nil)
+
+// Connect implements driver.Connector.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	sess, err := c.db.NewSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cfg.AsOf != "" {
+		var database string
+		if res, err := sess.Query(ctx, "select database()"); err == nil && len(res.Rows) > 0 && res.Rows[0][0] != nil {
+			database = res.Rows[0][0].(string)
+		}
+		err = sess.Use(ctx, database+"/"+c.cfg.AsOf)
+	} else if c.cfg.Branch != "" {
+		err = sess.Checkout(ctx, c.cfg.Branch)
+	}
+	if err != nil {
+		_ = sess.Close()
+		return nil, err
+	}
+	return &Conn{connector: c, sess: sess}, nil
+}
+
+// Driver implements driver.Connector.
+func (c *Connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Close closes the databases of the connector. It's called by sql.DB.Close.
+func (c *Connector) Close() error {
+	return c.db.Close()
+}
+
+// Conn is a connection to a dolt database, backed by an embedded.Session.
+type Conn struct {
+	connector *Connector
+	sess      *embedded.Session
+	// closeConnector is true if the connection was opened by Driver.Open, and its connector is closed with it
+	closeConnector bool
+}
+
+var _ driver.Conn = (*Conn)(nil)
+var _ driver.ConnBeginTx = (*Conn)(nil)
+var _ driver.QueryerContext = (*Conn)(nil)
+var _ driver.ExecerContext = (*Conn)(nil)
+var _ driver.ConnPrepareContext = (*Conn)(nil)
+
+// Prepare implements driver.Conn.
+func (c *Conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *Conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &Stmt{conn: c, query: query}, nil
+}
+
+// Close implements driver.Conn.
+func (c *Conn) Close() error {
+	err := c.sess.Close()
+	if c.closeConnector {
+		if cErr := c.connector.Close(); err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// Begin implements driver.Conn.
+func (c *Conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) && opts.Isolation != driver.IsolationLevel(sql.LevelRepeatableRead) {
+		return nil, fmt.Errorf("unsupported isolation level %s", sql.IsolationLevel(opts.Isolation).String())
+	}
+	query := "start transaction"
+	if opts.ReadOnly {
+		query = "start transaction read only"
+	}
+	if err := c.sess.Exec(ctx, query); err != nil {
+		return nil, err
+	}
+	return &Tx{conn: c}, nil
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	vals, err := argValues(args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.sess.Rows(ctx, query, vals...)
+	if err != nil {
+		return nil, err
+	}
+	return &Rows{rows: rows}, nil
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	vals, err := argValues(args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.sess.Query(ctx, query, vals...)
+	if err != nil {
+		return nil, err
+	}
+	if types.IsOkResultSchema(res.Schema) && len(res.Rows) == 1 {
+		ok := res.Rows[0][0].(types.OkResult)
+		return &Result{rowsAffected: int64(ok.RowsAffected), lastInsertId: int64(ok.InsertID)}, nil
+	}
+	return &Result{}, nil
+}
+
+// argValues returns the values of |args|, which must be positional.
+func argValues(args []driver.NamedValue) ([]interface{}, error) {
+	vals := make([]interface{}, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, fmt.Errorf("named parameters are not supported: %s", a.Name)
+		}
+		vals[i] = a.Value
+	}
+	return vals, nil
+}
+
+// Stmt is a statement prepared on a Conn. Statements are analyzed each time they're run.
+type Stmt struct {
+	conn  *Conn
+	query string
+}
+
+var _ driver.Stmt = (*Stmt)(nil)
+var _ driver.StmtQueryContext = (*Stmt)(nil)
+var _ driver.StmtExecContext = (*Stmt)(nil)
+
+// Close implements driver.Stmt.
+func (s *Stmt) Close() error {
+	return nil
+}
+
+// NumInput implements driver.Stmt. The number of placeholders isn't checked before the statement is run.
+func (s *Stmt) NumInput() int {
+	return -1
+}
+
+// Exec implements driver.Stmt.
+func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// Query implements driver.Stmt.
+func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (s *Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+// QueryContext implements driver.StmtQueryContext.
+func (s *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
+	}
+	return named
+}
+
+// Tx is a transaction on a Conn.
+type Tx struct {
+	conn *Conn
+}
+
+var _ driver.Tx = (*Tx)(nil)
+
+// Commit implements driver.Tx.
+func (t *Tx) Commit() error {
+	return t.conn.sess.Exec(context.Background(), "commit")
+}
+
+// Rollback implements driver.Tx.
+func (t *Tx) Rollback() error {
+	return t.conn.sess.Exec(context.Background(), "rollback")
+}
+
+// Result is the result of a statement run with Exec.
+type Result struct {
+	rowsAffected int64
+	lastInsertId int64
+}
+
+var _ driver.Result = (*Result)(nil)
+
+// LastInsertId implements driver.Result.
+func (r *Result) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+// RowsAffected implements driver.Result.
+func (r *Result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// Rows are the rows of a query.
+type Rows struct {
+	rows *embedded.Rows
+}
+
+var _ driver.Rows = (*Rows)(nil)
+
+// Columns implements driver.Rows.
+func (r *Rows) Columns() []string {
+	cols := make([]string, len(r.rows.Schema))
+	for i, c := range r.rows.Schema {
+		cols[i] = c.Name
+	}
+	return cols
+}
+
+// Close implements driver.Rows.
+func (r *Rows) Close() error {
+	return r.rows.Close()
+}
+
+// Next implements driver.Rows.
+func (r *Rows) Next(dest []driver.Value) error {
+	row, err := r.rows.Next()
+	if err != nil {
+		return err
+	}
+	for i := range dest {
+		if dest[i], err = driverValue(r.rows.Context(), r.rows.Schema[i].Type, row[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// driverValue converts |v|, a value of |typ|, to one of the types of driver.Value.
+func driverValue(ctx *gmssql.Context, typ gmssql.Type, v interface{}) (driver.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time, []byte, string, bool, float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	}
+
+	if types.IsInteger(typ) {
+		switch v := v.(type) {
+		case int8:
+			return int64(v), nil
+		case int16:
+			return int64(v), nil
+		case int32:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case uint8:
+			return int64(v), nil
+		case uint16:
+			return int64(v), nil
+		case uint32:
+			return int64(v), nil
+		case uint64:
+			if v <= math.MaxInt64 {
+				return int64(v), nil
+			}
+			return strconv.FormatUint(v, 10), nil
+		}
+	}
+
+	// everything else, like decimals, enums and JSON, is returned as its MySQL wire format
+	val, err := typ.SQL(ctx, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	if types.IsBinaryType(typ) || types.IsGeometry(typ) {
+		return val.ToBytes(), nil
+	}
+	return val.ToString(), nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdriver
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestParseDSN(t *testing.T) {
+	cfg, err := ParseDSN("file:///path/to/dbs?database=mydb&branch=feature&commitname=me&commitemail=me@dolthub.com&readonly=true")
+	require.NoError(t, err)
+	assert.Equal(t, "/path/to/dbs", cfg.Directory)
+	assert.Equal(t, "mydb", cfg.Database)
+	assert.Equal(t, "feature", cfg.Branch)
+	assert.Equal(t, "me", cfg.CommitName)
+	assert.Equal(t, "me@dolthub.com", cfg.CommitEmail)
+	assert.True(t, cfg.ReadOnly)
+
+	_, err = ParseDSN("mysql://localhost:3306/mydb")
+	assert.Error(t, err)
+	_, err = ParseDSN("file:///path/to/dbs?branch=main&asof=v1")
+	assert.Error(t, err)
+	_, err = ParseDSN("file:///path/to/dbs?readonly=maybe")
+	assert.Error(t, err)
+}
+
+func TestDriver(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "mydb")
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB, "test")
+	require.NoError(t, dEnv.InitRepo(ctx, types.Format_Default, "test", "test@dolthub.com", env.DefaultInitBranch))
+	require.NoError(t, dEnv.DoltDB.Close())
+	require.NoError(t, dbfactory.DeleteFromSingletonCache(dir+"/.dolt/noms"))
+
+	db, err := sql.Open(DriverName, "file://"+filepath.ToSlash(dir)+"?commitname=driver&commitemail=driver@dolthub.com")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("create table t (pk int primary key, c varchar(20), d decimal(5,2))")
+	require.NoError(t, err)
+	res, err := db.Exec("insert into t values (?, ?, ?), (?, ?, ?)", 1, "one", "1.50", 2, "two", nil)
+	require.NoError(t, err)
+	affected, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	var c string
+	var d sql.NullString
+	require.NoError(t, db.QueryRow("select c, d from t where pk = ?", 1).Scan(&c, &d))
+	assert.Equal(t, "one", c)
+	assert.Equal(t, sql.NullString{String: "1.50", Valid: true}, d)
+
+	_, err = db.Exec("call dolt_commit('-Am', 'first commit')")
+	require.NoError(t, err)
+	_, err = db.Exec("call dolt_tag('v1')")
+	require.NoError(t, err)
+	_, err = db.Exec("call dolt_branch('feature')")
+	require.NoError(t, err)
+
+	// a rolled back transaction leaves nothing behind
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("insert into t values (3, 'three', null)")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+
+	var count int
+	require.NoError(t, db.QueryRow("select count(*) from t").Scan(&count))
+	assert.Equal(t, 2, count)
+	_, err = db.Exec("insert into t values (3, 'three', null)")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// connections can check out a branch, or read from a revision
+	branchDB, err := sql.Open(DriverName, "file://"+filepath.ToSlash(dir)+"?branch=feature")
+	require.NoError(t, err)
+	var branch string
+	require.NoError(t, branchDB.QueryRow("select active_branch()").Scan(&branch))
+	assert.Equal(t, "feature", branch)
+	require.NoError(t, branchDB.QueryRow("select count(*) from t").Scan(&count))
+	assert.Equal(t, 2, count)
+	require.NoError(t, branchDB.Close())
+
+	asOfDB, err := sql.Open(DriverName, "file://"+filepath.ToSlash(dir)+"?asof=v1")
+	require.NoError(t, err)
+	require.NoError(t, asOfDB.QueryRow("select count(*) from t").Scan(&count))
+	assert.Equal(t, 2, count)
+	_, err = asOfDB.Exec("insert into t values (4, 'four', null)")
+	assert.Error(t, err)
+	require.NoError(t, asOfDB.Close())
+}
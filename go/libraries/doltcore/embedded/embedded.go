@@ -0,0 +1,422 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embedded opens dolt databases in the calling process, so that Go programs can query and version them without
+// running a sql-server or the dolt CLI.
+//
+// A DB is opened on a directory holding a dolt database, or holding dolt databases in its subdirectories. Work is done
+// in Sessions, which each have their own current database, checked out branch and transaction, like the connections
+// to a sql-server. A DB can be used by many goroutines at once, but each Session can be used by only one at a time.
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	gms "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const defaultVersion = "embedded"
+
+// ErrNoDatabases is returned by Open when the directory it's given holds no dolt databases.
+var ErrNoDatabases = errors.New("no dolt databases found")
+
+// Config configures a DB.
+type Config struct {
+	// Directory is the directory of a dolt database, or of a directory holding dolt databases in its subdirectories.
+	Directory string
+	// Database is the database that sessions use when they're created. It defaults to the first database found.
+	Database string
+	// CommitName and CommitEmail are the author of the commits made by sessions. They default to the user.name and
+	// user.email of the dolt config.
+	CommitName  string
+	CommitEmail string
+	// ReadOnly prevents sessions from writing to the databases.
+	ReadOnly bool
+	// Version is the version reported to remotes. It defaults to "embedded".
+	Version string
+}
+
+// DB is a set of dolt databases opened in process.
+type DB struct {
+	mrEnv    *env.MultiRepoEnv
+	engine   *gms.Engine
+	provider dsqle.DoltDatabaseProvider
+	config   config.ReadWriteConfig
+	bc       *branch_control.Controller
+	database string
+}
+
+// Open opens the dolt databases in the directory configured by |cfg|.
+func Open(ctx context.Context, cfg Config) (*DB, error) {
+	if cfg.Version == "" {
+		cfg.Version = defaultVersion
+	}
+
+	fs, err := filesys.LocalFilesysWithWorkingDir(cfg.Directory)
+	if err != nil {
+		return nil, err
+	}
+	dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB, cfg.Version)
+	dEnv.Config.SetFailsafes(env.DefaultFailsafeConfig)
+
+	mrEnv, err := env.MultiEnvForDirectory(ctx, dEnv.Config.WriteableConfig(), fs, cfg.Version, dEnv.IgnoreLockFile, dEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbs []dsess.SqlDatabase
+	var locations []filesys.Filesys
+	err = mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		tmpDir, err := dEnv.TempTableFilesDir()
+		if err != nil {
+			return true, err
+		}
+		db, err := dsqle.NewDatabase(ctx, name, dEnv.DbData(), editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir})
+		if err != nil {
+			return true, err
+		}
+		dbs = append(dbs, db)
+		locations = append(locations, dEnv.FS)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(dbs) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoDatabases, cfg.Directory)
+	}
+
+	pro, err := dsqle.NewDoltDatabaseProviderWithDatabases(env.GetDefaultInitBranch(mrEnv.Config()), fs, dbs, locations)
+	if err != nil {
+		return nil, err
+	}
+	pro = pro.WithRemoteDialer(mrEnv.RemoteDialProvider())
+
+	engine := gms.New(analyzer.NewBuilder(pro).Build(), &gms.Config{IsReadOnly: cfg.ReadOnly})
+	dsqle.AddAnalyzerRules(engine.Analyzer)
+
+	// the configured author takes precedence over the dolt config
+	author := make(map[string]string)
+	if cfg.CommitName != "" {
+		author[env.UserNameKey] = cfg.CommitName
+	}
+	if cfg.CommitEmail != "" {
+		author[env.UserEmailKey] = cfg.CommitEmail
+	}
+	sessConfig := config.NewConfigHierarchy()
+	sessConfig.AddConfig("embedded", config.NewMapConfig(author))
+	sessConfig.AddConfig("dolt", mrEnv.Config())
+
+	database := cfg.Database
+	if database == "" {
+		database = mrEnv.GetFirstDatabase()
+	}
+
+	return &DB{
+		mrEnv:    mrEnv,
+		engine:   engine,
+		provider: pro,
+		config:   sessConfig,
+		bc:       branch_control.CreateDefaultController(),
+		database: database,
+	}, nil
+}
+
+// Databases returns the names of the databases of |db|.
+func (db *DB) Databases(ctx context.Context) []string {
+	sqlCtx := sql.NewContext(ctx)
+	var names []string
+	for _, d := range db.provider.AllDatabases(sqlCtx) {
+		if _, ok := d.(dsess.SqlDatabase); ok {
+			names = append(names, d.Name())
+		}
+	}
+	return names
+}
+
+// Close closes |db|. Sessions can't be used once their DB is closed.
+func (db *DB) Close() error {
+	// shutting down the engine's background threads cancels their context, which isn't an error
+	err := db.engine.Close()
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	closeErr := db.mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		if err = dEnv.DoltDB.Close(); err != nil {
+			return false, err
+		}
+		// the closed database mustn't be returned when the directory is opened again
+		loc, err := dEnv.FS.Abs("")
+		if err != nil {
+			return false, err
+		}
+		return false, dbfactory.DeleteFromSingletonCache(loc + "/.dolt/noms")
+	})
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// NewSession returns a new session on |db|, using the configured database and its default branch.
+func (db *DB) NewSession(ctx context.Context) (*Session, error) {
+	sess, err := dsess.NewDoltSession(sql.NewBaseSession(), db.provider, db.config, db.bc)
+	if err != nil {
+		return nil, err
+	}
+	sess.SetClient(sql.Client{User: "root", Address: "%"})
+	if db.database != "" {
+		sess.SetCurrentDatabase(db.database)
+	}
+	return &Session{db: db, sess: sess}, nil
+}
+
+// Session runs statements against a DB. Like a connection to a sql-server, it has its own current database, checked
+// out branch and transaction. A Session can be used by only one goroutine at a time.
+type Session struct {
+	db   *DB
+	sess *dsess.DoltSession
+}
+
+// Result is the result of a query.
+type Result struct {
+	Schema sql.Schema
+	Rows   []sql.Row
+}
+
+// Query runs |query| and returns its result. The placeholders of |query| are bound to |args|, in order.
+func (s *Session) Query(ctx context.Context, query string, args ...interface{}) (*Result, error) {
+	rows, err := s.Rows(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Schema: rows.Schema}
+	for {
+		r, err := rows.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		res.Rows = append(res.Rows, r)
+	}
+	return res, rows.Close()
+}
+
+// Rows runs |query| and returns an iterator over its rows, which must be closed before the session runs another
+// statement. The placeholders of |query| are bound to |args|, in order.
+func (s *Session) Rows(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	var bindings map[string]sql.Expression
+	if len(args) > 0 {
+		bindings = make(map[string]sql.Expression, len(args))
+		for i, a := range args {
+			typ := types.Null
+			if a != nil {
+				typ = types.ApproximateTypeFromValue(a)
+			}
+			bindings[fmt.Sprintf("v%d", i+1)] = expression.NewLiteral(a, typ)
+		}
+	}
+
+	sqlCtx := sql.NewContext(ctx, sql.WithSession(s.sess), sql.WithQuery(query))
+	sch, iter, err := s.db.engine.QueryWithBindings(sqlCtx, query, bindings)
+	if err != nil {
+		return nil, err
+	}
+	return &Rows{Schema: sch, ctx: sqlCtx, iter: iter}, nil
+}
+
+// Rows iterates over the rows of a query.
+type Rows struct {
+	Schema sql.Schema
+	ctx    *sql.Context
+	iter   sql.RowIter
+}
+
+// Next returns the next row, or io.EOF once every row has been returned.
+func (r *Rows) Next() (sql.Row, error) {
+	return r.iter.Next(r.ctx)
+}
+
+// Context returns the context the query is run with, which converting its values to other types requires.
+func (r *Rows) Context() *sql.Context {
+	return r.ctx
+}
+
+// Close closes the iterator, completing the statement.
+func (r *Rows) Close() error {
+	return r.iter.Close(r.ctx)
+}
+
+// Exec runs |query|, discarding its result. The placeholders of |query| are bound to |args|, in order.
+func (s *Session) Exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := s.Query(ctx, query, args...)
+	return err
+}
+
+// Use makes |database| the session's current database. Like a USE statement, |database| can name a revision of a
+// database, such as mydb/feature or mydb/v1.0, which is read only unless the revision is a branch.
+func (s *Session) Use(ctx context.Context, database string) error {
+	return s.Exec(ctx, "use "+sql.QuoteIdentifier(database))
+}
+
+// Close ends the session, rolling back any transaction it has open. A bulk load in progress is committed.
+func (s *Session) Close() error {
+	defer s.db.engine.CloseSession(s.sess.ID())
+	return s.sess.Close(sql.NewContext(context.Background(), sql.WithSession(s.sess)))
+}
+
+// Commit stages every table of the checked out branch and commits them with |message|, returning the hash of the new
+// commit.
+func (s *Session) Commit(ctx context.Context, message string) (string, error) {
+	res, err := s.Query(ctx, call("dolt_commit", append(s.authorArgs(), "-Am", message)...))
+	if err != nil {
+		return "", err
+	}
+	return firstString(res), nil
+}
+
+// ActiveBranch returns the branch checked out by the session.
+func (s *Session) ActiveBranch(ctx context.Context) (string, error) {
+	res, err := s.Query(ctx, "select active_branch()")
+	if err != nil {
+		return "", err
+	}
+	return firstString(res), nil
+}
+
+// Branches returns the names of the branches of the session's current database.
+func (s *Session) Branches(ctx context.Context) ([]string, error) {
+	res, err := s.Query(ctx, "select name from dolt_branches order by name")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(res.Rows))
+	for i, r := range res.Rows {
+		names[i] = r[0].(string)
+	}
+	return names, nil
+}
+
+// CreateBranch creates the branch |name| at |startPoint|, or at the checked out branch if |startPoint| is empty.
+func (s *Session) CreateBranch(ctx context.Context, name, startPoint string) error {
+	if startPoint == "" {
+		return s.Exec(ctx, call("dolt_branch", name))
+	}
+	return s.Exec(ctx, call("dolt_branch", name, startPoint))
+}
+
+// DeleteBranch deletes the branch |name|, which must have been merged unless |force| is true.
+func (s *Session) DeleteBranch(ctx context.Context, name string, force bool) error {
+	if force {
+		return s.Exec(ctx, call("dolt_branch", "-D", name))
+	}
+	return s.Exec(ctx, call("dolt_branch", "-d", name))
+}
+
+// Checkout checks out |branch| in the session.
+func (s *Session) Checkout(ctx context.Context, branch string) error {
+	return s.Exec(ctx, call("dolt_checkout", branch))
+}
+
+// MergeResult is the result of a merge.
+type MergeResult struct {
+	// Hash is the hash of the commit the merge made or fast-forwarded to, if the merge was committed.
+	Hash string
+	// FastForward is true if the checked out branch was fast-forwarded to the merged branch.
+	FastForward bool
+	// Conflicts is true if the merge has conflicts, which must be resolved before it can be committed.
+	Conflicts bool
+}
+
+// Merge merges |branch| into the checked out branch, committing the merge with |message| if it has no conflicts. If
+// |message| is empty, the merge is committed with a default message.
+func (s *Session) Merge(ctx context.Context, branch, message string) (MergeResult, error) {
+	args := append(s.authorArgs(), branch)
+	if message != "" {
+		args = append(args, "-m", message)
+	}
+	res, err := s.Query(ctx, call("dolt_merge", args...))
+	if err != nil {
+		return MergeResult{}, err
+	}
+	r := res.Rows[0]
+	var mr MergeResult
+	if r[0] != nil {
+		mr.Hash = r[0].(string)
+	}
+	mr.FastForward = r[1].(int64) != 0
+	mr.Conflicts = r[2].(int64) != 0
+	return mr, nil
+}
+
+// Diff returns the rows of |table| that differ between the revisions |from| and |to|, as returned by the dolt_diff()
+// table function. Revisions can be branches, tags, commit hashes, or WORKING and STAGED.
+func (s *Session) Diff(ctx context.Context, from, to, table string) (*Result, error) {
+	return s.Query(ctx, fmt.Sprintf("select * from dolt_diff(%s, %s, %s)", quote(from), quote(to), quote(table)))
+}
+
+// authorArgs returns the --author argument of the procedures that make commits. Procedures called from SQL make
+// commits as the SQL user unless they're given an author, so the configured author has to be passed explicitly.
+func (s *Session) authorArgs() []string {
+	name, nameErr := s.db.config.GetString(env.UserNameKey)
+	email, emailErr := s.db.config.GetString(env.UserEmailKey)
+	if nameErr != nil || emailErr != nil {
+		return nil
+	}
+	return []string{"--author", fmt.Sprintf("%s <%s>", name, email)}
+}
+
+// call returns a statement calling the procedure |name| with |args|.
+func call(name string, args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quote(a)
+	}
+	return fmt.Sprintf("call %s(%s)", name, strings.Join(quoted, ", "))
+}
+
+// quote returns |s| as a SQL string literal.
+func quote(s string) string {
+	var sb strings.Builder
+	sqltypes.NewVarChar(s).EncodeSQL(&sb)
+	return sb.String()
+}
+
+func firstString(res *Result) string {
+	if len(res.Rows) == 0 || len(res.Rows[0]) == 0 || res.Rows[0][0] == nil {
+		return ""
+	}
+	return fmt.Sprint(res.Rows[0][0])
+}
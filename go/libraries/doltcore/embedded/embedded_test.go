@@ -0,0 +1,164 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func initDatabase(t *testing.T, ctx context.Context, dir string) {
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB, "test")
+	require.NoError(t, dEnv.InitRepo(ctx, types.Format_Default, "test", "test@dolthub.com", env.DefaultInitBranch))
+	require.NoError(t, dEnv.DoltDB.Close())
+	require.NoError(t, dbfactory.DeleteFromSingletonCache(dir+"/.dolt/noms"))
+}
+
+func TestEmbedded(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "mydb")
+	initDatabase(t, ctx, dir)
+
+	db, err := Open(ctx, Config{Directory: dir, CommitName: "embedder", CommitEmail: "embedder@dolthub.com"})
+	require.NoError(t, err)
+	defer db.Close()
+	assert.Equal(t, []string{"mydb"}, db.Databases(ctx))
+
+	sess, err := db.NewSession(ctx)
+	require.NoError(t, err)
+	defer sess.Close()
+
+	require.NoError(t, sess.Exec(ctx, "create table t (pk int primary key, c varchar(20))"))
+	require.NoError(t, sess.Exec(ctx, "insert into t values (1, 'one')"))
+	_, err = sess.Commit(ctx, "it's the first commit")
+	require.NoError(t, err)
+
+	res, err := sess.Query(ctx, "select committer, message from dolt_log limit 1")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"embedder", "it's the first commit"}}, res.Rows)
+
+	require.NoError(t, sess.CreateBranch(ctx, "feature", ""))
+	branches, err := sess.Branches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature", env.DefaultInitBranch}, branches)
+
+	require.NoError(t, sess.Checkout(ctx, "feature"))
+	active, err := sess.ActiveBranch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", active)
+	require.NoError(t, sess.Exec(ctx, "insert into t values (2, 'two')"))
+	_, err = sess.Commit(ctx, "second commit")
+	require.NoError(t, err)
+
+	res, err = sess.Diff(ctx, env.DefaultInitBranch, "feature", "t")
+	require.NoError(t, err)
+	require.Len(t, res.Rows, 1)
+
+	// another session has its own checked out branch
+	other, err := db.NewSession(ctx)
+	require.NoError(t, err)
+	defer other.Close()
+	res, err = other.Query(ctx, "select count(*) from t")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1)}}, res.Rows)
+
+	mr, err := other.Merge(ctx, "feature", "")
+	require.NoError(t, err)
+	assert.True(t, mr.FastForward)
+	assert.False(t, mr.Conflicts)
+	res, err = other.Query(ctx, "select count(*) from t")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(2)}}, res.Rows)
+
+	require.NoError(t, other.DeleteBranch(ctx, "feature", false))
+	branches, err = other.Branches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{env.DefaultInitBranch}, branches)
+}
+
+func TestDefaultAsOf(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "mydb")
+	initDatabase(t, ctx, dir)
+
+	db, err := Open(ctx, Config{Directory: dir})
+	require.NoError(t, err)
+	defer db.Close()
+
+	sess, err := db.NewSession(ctx)
+	require.NoError(t, err)
+	defer sess.Close()
+	require.NoError(t, sess.Exec(ctx, "create table t (pk int primary key)"))
+	require.NoError(t, sess.Exec(ctx, "insert into t values (1)"))
+	_, err = sess.Commit(ctx, "first commit")
+	require.NoError(t, err)
+	require.NoError(t, sess.Exec(ctx, "insert into t values (2)"))
+	_, err = sess.Commit(ctx, "second commit")
+	require.NoError(t, err)
+
+	// the Dolt analyzer rules read tables as of @@dolt_default_as_of
+	require.NoError(t, sess.Exec(ctx, "set @@dolt_default_as_of = 'HEAD~1'"))
+	res, err := sess.Query(ctx, "select count(*) from t")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1)}}, res.Rows)
+}
+
+func TestSessionCloseFlushesBulkLoad(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "mydb")
+	initDatabase(t, ctx, dir)
+
+	db, err := Open(ctx, Config{Directory: dir})
+	require.NoError(t, err)
+	defer db.Close()
+
+	sess, err := db.NewSession(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Exec(ctx, "create table t (pk int primary key)"))
+	require.NoError(t, sess.Exec(ctx, "set @@dolt_bulk_load = 1"))
+	require.NoError(t, sess.Exec(ctx, "insert into t values (1), (2)"))
+
+	other, err := db.NewSession(ctx)
+	require.NoError(t, err)
+	defer other.Close()
+	res, err := other.Query(ctx, "select count(*) from t")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(0)}}, res.Rows)
+
+	require.NoError(t, sess.Close())
+	res, err = other.Query(ctx, "select count(*) from t")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(2)}}, res.Rows)
+}
+
+func TestOpenNoDatabases(t *testing.T) {
+	_, err := Open(context.Background(), Config{Directory: t.TempDir()})
+	assert.ErrorIs(t, err, ErrNoDatabases)
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// backupGenerationsDir is the directory of the dolt dir that has the generations of each backup
+const backupGenerationsDir = "backups"
+
+// BackupGeneration is a record of a sync of a backup. The first sync of a backup copies every chunk of the database,
+// and each sync after it is incremental, copying only the chunks that aren't in the backup yet.
+type BackupGeneration struct {
+	Generation  int       `json:"generation"`
+	Time        time.Time `json:"time"`
+	Root        string    `json:"root"`
+	Incremental bool      `json:"incremental"`
+	Chunks      uint64    `json:"chunks"`
+	Bytes       uint64    `json:"bytes"`
+}
+
+func backupGenerationsFile(doltDir, backupName string) string {
+	return filepath.Join(doltDir, backupGenerationsDir, backupName+".jsonl")
+}
+
+// LoadBackupGenerations returns the generations of the backup named |backupName|, oldest first, from the dolt dir
+// |doltDir|
+func LoadBackupGenerations(fs filesys.ReadableFS, doltDir, backupName string) ([]BackupGeneration, error) {
+	path := backupGenerationsFile(doltDir, backupName)
+	if exists, _ := fs.Exists(path); !exists {
+		return nil, nil
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var gens []BackupGeneration
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var gen BackupGeneration
+		if err := json.Unmarshal(line, &gen); err != nil {
+			return nil, fmt.Errorf("invalid generation of backup '%s': %w", backupName, err)
+		}
+		gens = append(gens, gen)
+	}
+	return gens, scanner.Err()
+}
+
+// RecordBackupGeneration numbers |gen| after the last generation of the backup named |backupName| and adds it to the
+// generations of the backup in the dolt dir |doltDir|.
+func RecordBackupGeneration(fs filesys.ReadWriteFS, doltDir, backupName string, gen BackupGeneration) (BackupGeneration, error) {
+	gens, err := LoadBackupGenerations(fs, doltDir, backupName)
+	if err != nil {
+		return BackupGeneration{}, err
+	}
+	gen.Generation = 1
+	if len(gens) > 0 {
+		gen.Generation = gens[len(gens)-1].Generation + 1
+	}
+
+	gens = append(gens, gen)
+	var buf bytes.Buffer
+	for _, g := range gens {
+		data, err := json.Marshal(g)
+		if err != nil {
+			return BackupGeneration{}, err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	err = fs.MkDirs(filepath.Join(doltDir, backupGenerationsDir))
+	if err != nil {
+		return BackupGeneration{}, err
+	}
+	err = fs.WriteFile(backupGenerationsFile(doltDir, backupName), buf.Bytes())
+	if err != nil {
+		return BackupGeneration{}, err
+	}
+	return gen, nil
+}
+
+// RemoveBackupGenerations removes the generations of the backup named |backupName|
+func RemoveBackupGenerations(fs filesys.ReadWriteFS, doltDir, backupName string) error {
+	path := backupGenerationsFile(doltDir, backupName)
+	if exists, _ := fs.Exists(path); !exists {
+		return nil
+	}
+	return fs.DeleteFile(path)
+}
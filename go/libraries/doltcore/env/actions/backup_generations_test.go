@@ -0,0 +1,57 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestBackupGenerations(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/repo")
+	doltDir := "/repo/.dolt"
+
+	gens, err := LoadBackupGenerations(fs, doltDir, "nightly")
+	require.NoError(t, err)
+	assert.Empty(t, gens)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	full, err := RecordBackupGeneration(fs, doltDir, "nightly", BackupGeneration{Time: now, Root: "abc", Chunks: 100, Bytes: 4096})
+	require.NoError(t, err)
+	assert.Equal(t, 1, full.Generation)
+
+	incr, err := RecordBackupGeneration(fs, doltDir, "nightly", BackupGeneration{Time: now.Add(time.Hour), Root: "def", Incremental: true, Chunks: 3, Bytes: 120})
+	require.NoError(t, err)
+	assert.Equal(t, 2, incr.Generation)
+
+	other, err := RecordBackupGeneration(fs, doltDir, "weekly", BackupGeneration{Time: now, Root: "def"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, other.Generation)
+
+	gens, err = LoadBackupGenerations(fs, doltDir, "nightly")
+	require.NoError(t, err)
+	assert.Equal(t, []BackupGeneration{full, incr}, gens)
+
+	require.NoError(t, RemoveBackupGenerations(fs, doltDir, "nightly"))
+	gens, err = LoadBackupGenerations(fs, doltDir, "nightly")
+	require.NoError(t, err)
+	assert.Empty(t, gens)
+	require.NoError(t, RemoveBackupGenerations(fs, doltDir, "nightly"))
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -89,6 +90,10 @@ func EnvForClone(ctx context.Context, nbf *types.NomsBinFormat, r env.Remote, di
 	return dEnv, nil
 }
 
+// CloneProgFunc consumes the events of downloading the table files of a remote database while it's cloned, until
+// |eventCh| is closed.
+type CloneProgFunc func(eventCh <-chan pull.TableFileEvent)
+
 func cloneProg(eventCh <-chan pull.TableFileEvent) {
 	var (
 		chunksC           int64
@@ -156,20 +161,50 @@ func sortedKeys(m map[string]iohelp.ReadStats) []string {
 	return keys
 }
 
-func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, dEnv *env.DoltEnv) error {
-	eventCh := make(chan pull.TableFileEvent, 128)
+// CloneRemote clones |srcDB| into |dEnv|, creating remote-tracking branches under |remoteName| and checking out
+// |branch|, or the default branch if |branch| is empty. If |singleBranch| is true, only the history of that branch is
+// downloaded, and the remote is configured to fetch only that branch. The progress of downloading table files is
+// reported to |progFn|, or printed if it's nil.
+func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, singleBranch bool, dEnv *env.DoltEnv, progFn CloneProgFunc) error {
+	// Chunks are copied as they are, whether their table files are downloaded or they're pulled one at a time, so the
+	// clone must be in the format of the remote
+	if srcDB.Format() != dEnv.DoltDB.Format() {
+		return fmt.Errorf("%w; %s: remote format %s, clone format %s", ErrCloneFailed, pull.ErrFormatMismatch.Error(),
+			srcDB.Format().VersionString(), dEnv.DoltDB.Format().VersionString())
+	}
 
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		cloneProg(eventCh)
-	}()
+	if singleBranch {
+		return cloneSingleBranch(ctx, srcDB, remoteName, branch, dEnv)
+	}
+
+	// Downloading the remote's table files and manifest as they are is much faster than pulling its chunks one at a
+	// time, but can only be done when the puller doesn't need to verify each chunk and both databases expose their
+	// table files.
+	var err error
+	if srcDB.VerifiesPulls() {
+		err = cloneChunks(ctx, srcDB, dEnv)
+	} else {
+		eventCh := make(chan pull.TableFileEvent, 128)
+		if progFn == nil {
+			progFn = cloneProg
+		}
 
-	err := Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
-	close(eventCh)
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			progFn(eventCh)
+		}()
 
-	wg.Wait()
+		err = Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
+		close(eventCh)
+
+		wg.Wait()
+
+		if errors.Is(err, pull.ErrCloneUnsupported) {
+			err = cloneChunks(ctx, srcDB, dEnv)
+		}
+	}
 
 	if err != nil {
 		if err == pull.ErrNoData {
@@ -237,8 +272,108 @@ func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 		}
 	}
 
+	return checkoutClonedBranch(ctx, dEnv, branch, rootVal)
+}
+
+// cloneChunks pulls every chunk of |srcDB| into |dEnv| through the puller, rather than copying the table files of
+// |srcDB| as they are. If |srcDB| verifies pulls, the puller checks each chunk against its address. Chunks are written
+// as they are, so |dEnv| must be in the format of |srcDB|.
+func cloneChunks(ctx context.Context, srcDB *doltdb.DoltDB, dEnv *env.DoltEnv) error {
+	srcRoot, err := srcDB.NomsRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if srcRoot.IsEmpty() {
+		return pull.ErrNoData
+	}
+
+	destRoot, err := dEnv.DoltDB.NomsRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	err = dEnv.DoltDB.PullChunks(ctx, tmpDir, srcDB, []hash.Hash{srcRoot}, nil)
+	if err != nil {
+		return err
+	}
+
+	ok, err := dEnv.DoltDB.CommitRoot(ctx, srcRoot, destRoot)
+	if err != nil {
+		return err
+	} else if !ok {
+		return errors.New("the cloned database was modified during the clone")
+	}
+	return nil
+}
+
+// cloneSingleBranch pulls only the commit graph of |branch| from |srcDB| into |dEnv|, creating a single
+// remote-tracking branch for it and restricting the fetch spec of |remoteName| to that branch.
+func cloneSingleBranch(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, dEnv *env.DoltEnv) error {
+	srcBranches, err := srcDB.GetBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("%w; %s", ErrFailedToListBranches, err.Error())
+	}
+	if len(srcBranches) == 0 {
+		return fmt.Errorf("%w; %s", ErrCloneFailed, ErrNoDataAtRemote.Error())
+	}
+
+	if branch == "" {
+		branch = env.GetDefaultBranch(dEnv, srcBranches)
+	}
+
+	cs, _ := doltdb.NewCommitSpec(branch)
+	cm, err := srcDB.Resolve(ctx, cs, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s; %s", ErrFailedToGetBranch, branch, err.Error())
+	}
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return err
+	}
+
+	err = FetchCommit(ctx, tmpDir, srcDB, dEnv.DoltDB, cm, nil)
+	if err != nil {
+		return fmt.Errorf("%w; %s", ErrCloneFailed, err.Error())
+	}
+
+	remoteRef := ref.NewRemoteRef(remoteName, branch)
+	err = dEnv.DoltDB.SetHeadToCommit(ctx, remoteRef, cm)
+	if err != nil {
+		return fmt.Errorf("%w: %s; %s", ErrFailedToCreateRemoteRef, remoteRef.String(), err.Error())
+	}
+
+	err = dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef(branch), cm, nil)
+	if err != nil {
+		return err
+	}
+
+	if r, ok := dEnv.RepoState.Remotes[remoteName]; ok {
+		r.FetchSpecs = []string{fmt.Sprintf("refs/heads/%s:refs/remotes/%s/%s", branch, remoteName, branch)}
+		dEnv.RepoState.AddRemote(r)
+		if err = dEnv.RepoState.Save(dEnv.FS); err != nil {
+			return fmt.Errorf("%w; %s", ErrFailedToCreateRepoStateWithRemote, err.Error())
+		}
+	}
+
+	rootVal, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s; %s", ErrFailedToGetRootValue, branch, err.Error())
+	}
+
+	return checkoutClonedBranch(ctx, dEnv, branch, rootVal)
+}
+
+// checkoutClonedBranch makes |branch| the current branch of a newly cloned |dEnv|, with a clean working set at
+// |rootVal|.
+func checkoutClonedBranch(ctx context.Context, dEnv *env.DoltEnv, branch string, rootVal *doltdb.RootValue) error {
 	// TODO: make this interface take a DoltRef and marshal it automatically
-	err = dEnv.RepoStateWriter().SetCWBHeadRef(ctx, ref.MarshalableRef{Ref: ref.NewBranchRef(branch)})
+	err := dEnv.RepoStateWriter().SetCWBHeadRef(ctx, ref.MarshalableRef{Ref: ref.NewBranchRef(branch)})
 	if err != nil {
 		return err
 	}
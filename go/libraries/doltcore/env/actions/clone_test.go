@@ -0,0 +1,92 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func drainCloneEvents(eventCh <-chan pull.TableFileEvent) {
+	for range eventCh {
+	}
+}
+
+func headHash(t *testing.T, ctx context.Context, dEnv *env.DoltEnv) hash.Hash {
+	cs, err := doltdb.NewCommitSpec(env.DefaultInitBranch)
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h
+}
+
+func TestCloneRemote(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	remote := env.NewRemote("origin", "file://"+filepath.ToSlash(filepath.Join(dir, "src", ".dolt", "noms")), nil)
+
+	srcEnv, err := EnvForClone(ctx, types.Format_DOLT, env.NoRemote, "src", fs, "test", env.GetCurrentUserHomeDir)
+	require.NoError(t, err)
+	require.NoError(t, srcEnv.DoltDB.WriteEmptyRepo(ctx, env.DefaultInitBranch, "test", "test@dolthub.com"))
+	defer srcEnv.DoltDB.Close()
+
+	t.Run("same format", func(t *testing.T) {
+		dEnv, err := EnvForClone(ctx, srcEnv.DoltDB.Format(), remote, "same", fs, "test", env.GetCurrentUserHomeDir)
+		require.NoError(t, err)
+		defer dEnv.DoltDB.Close()
+
+		require.NoError(t, CloneRemote(ctx, srcEnv.DoltDB, "origin", "", false, dEnv, drainCloneEvents))
+		assert.Equal(t, headHash(t, ctx, srcEnv), headHash(t, ctx, dEnv))
+	})
+
+	t.Run("different format", func(t *testing.T) {
+		for _, verified := range []bool{false, true} {
+			srcDB := srcEnv.DoltDB
+			if verified {
+				srcDB = srcDB.WithVerifiedPulls()
+			}
+			cloneDir := filepath.Join("different", "unverified")
+			if verified {
+				cloneDir = filepath.Join("different", "verified")
+			}
+
+			dEnv, err := EnvForClone(ctx, types.Format_LD_1, remote, cloneDir, fs, "test", env.GetCurrentUserHomeDir)
+			require.NoError(t, err)
+
+			err = CloneRemote(ctx, srcDB, "origin", "", false, dEnv, drainCloneEvents)
+			assert.ErrorIs(t, err, ErrCloneFailed)
+			assert.Contains(t, err.Error(), pull.ErrFormatMismatch.Error())
+			cloneRoot, err := dEnv.DoltDB.NomsRoot(ctx)
+			require.NoError(t, err)
+			assert.True(t, cloneRoot.IsEmpty())
+			require.NoError(t, dEnv.DoltDB.Close())
+		}
+	})
+}
@@ -32,6 +32,10 @@ type CommitStagedProps struct {
 	Force      bool
 	Name       string
 	Email      string
+	// Sign, if set, signs the commit. See datas.CommitOptions.
+	Sign func(payload []byte) (string, error)
+	// Metadata is a JSON object of key-value metadata to attach to the commit. See datas.NormalizeCommitMetadata.
+	Metadata string
 }
 
 // GetCommitStaged returns a new pending commit with the roots and commit properties given.
@@ -106,6 +110,17 @@ func GetCommitStaged(
 	if err != nil {
 		return nil, err
 	}
+	if props.Metadata != "" {
+		meta.Metadata, err = datas.NormalizeCommitMetadata(props.Metadata)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return db.NewPendingCommit(ctx, roots, mergeParents, meta)
+	pendingCommit, err := db.NewPendingCommit(ctx, roots, mergeParents, meta)
+	if err != nil {
+		return nil, err
+	}
+	pendingCommit.CommitOptions.Sign = props.Sign
+	return pendingCommit, nil
 }
@@ -299,6 +299,9 @@ type dotDotCommiterator struct {
 	excludingCommitHashes []hash.Hash
 	matchFn               func(*doltdb.Commit) (bool, error)
 	q                     *q
+	// excluded holds the commits reachable from excludingCommitHashes when they can be found with a commit-graph,
+	// instead of walking them as invisible commits.
+	excluded hash.HashSet
 }
 
 var _ doltdb.CommitItr = (*dotDotCommiterator)(nil)
@@ -330,6 +333,9 @@ func (i *dotDotCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commi
 		}
 
 		for _, parentID := range parents {
+			if i.excluded.Has(parentID) {
+				continue
+			}
 			if nextC.invisible {
 				if err := i.q.SetInvisible(ctx, nextC.ddb, parentID); err != nil {
 					return hash.Hash{}, nil, err
@@ -361,6 +367,24 @@ func (i *dotDotCommiterator) Next(ctx context.Context) (hash.Hash, *doltdb.Commi
 // Reset implements doltdb.CommitItr
 func (i *dotDotCommiterator) Reset(ctx context.Context) error {
 	i.q = newQueue()
+	i.excluded = nil
+	if g := i.includedDdb.CommitGraph(); g != nil && i.includedDdb == i.excludedDdb {
+		excluded, err := g.ReachableFrom(ctx, i.includedDdb.ValueReadWriter(), i.excludingCommitHashes)
+		if err != nil {
+			return err
+		}
+		i.excluded = excluded
+		for _, startCommitHash := range i.startCommitHashes {
+			if excluded.Has(startCommitHash) {
+				continue
+			}
+			if err := i.q.AddPendingIfUnseen(ctx, i.includedDdb, startCommitHash); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	for _, excludingCommitHash := range i.excludingCommitHashes {
 		if err := i.q.SetInvisible(ctx, i.excludedDdb, excludingCommitHash); err != nil {
 			return err
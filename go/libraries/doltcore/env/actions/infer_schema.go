@@ -68,13 +68,19 @@ OUTER:
 
 		next := int(math.Pow(exp, float64(j)))
 		for n := 0; n < next; n++ {
-			curr, err = rd.ReadRow(ctx)
+			r, err := rd.ReadRow(ctx)
 			if err == io.EOF {
 				break OUTER
+			} else if table.IsBadRow(err) {
+				// malformed rows are rejected when the rows are imported, they don't have a type
+				continue
 			} else if err != nil {
 				return nil, err
 			}
-			prev = curr
+			curr, prev = r, r
+		}
+		if curr == nil {
+			continue
 		}
 		if err = i.processRow(curr); err != nil {
 			return nil, err
@@ -0,0 +1,163 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// BranchAsOf is the commit a branch was at as of a point in time. |Commit| is nil if the branch has no commit that
+// was created at or before that time.
+type BranchAsOf struct {
+	Branch ref.DoltRef
+	Commit *doltdb.Commit
+}
+
+// commitTime returns the time |meta| was created, falling back to its user supplied date for commits that don't
+// record a creation time.
+func commitTime(meta *datas.CommitMeta) time.Time {
+	if meta.Timestamp == 0 {
+		return meta.Time()
+	}
+	return time.UnixMilli(int64(meta.Timestamp))
+}
+
+// CommitAsOf returns the newest commit on the first-parent history of |cm| that was created at or before |t|, or nil
+// if there isn't one. Without a record of where a branch has pointed over time, this is the best approximation of the
+// commit the branch was at as of |t|. It can differ when the branch was reset or fast-forwarded to older commits.
+func CommitAsOf(ctx context.Context, cm *doltdb.Commit, t time.Time) (*doltdb.Commit, error) {
+	for {
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !commitTime(meta).After(t) {
+			return cm, nil
+		}
+		if cm.NumParents() == 0 {
+			return nil, nil
+		}
+		cm, err = cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// BranchesAsOf returns the commit every branch of |ddb| was at as of |t|.
+func BranchesAsOf(ctx context.Context, ddb *doltdb.DoltDB, t time.Time) ([]BranchAsOf, error) {
+	branches, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]BranchAsOf, 0, len(branches))
+	for _, b := range branches {
+		head, err := ddb.ResolveCommitRef(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		cm, err := CommitAsOf(ctx, head, t)
+		if err != nil {
+			return nil, fmt.Errorf("could not find the commit of branch '%s' as of %s: %w", b.GetPath(), t.Format(time.RFC3339), err)
+		}
+		res = append(res, BranchAsOf{Branch: b, Commit: cm})
+	}
+	return res, nil
+}
+
+// RestoreBranchesAsOf moves every branch of |ddb| back to the commit it was at as of |t|, and resets its working set
+// to that commit. Branches that have no commits from before |t| are deleted, as are tags created after |t|. This is
+// meant to be run on a fresh restore of a backup, since it discards everything that happened after |t|.
+func RestoreBranchesAsOf(ctx context.Context, ddb *doltdb.DoltDB, t time.Time) ([]BranchAsOf, error) {
+	branches, err := BranchesAsOf(ctx, ddb, t)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range branches {
+		if b.Commit == nil {
+			err = ddb.DeleteBranch(ctx, b.Branch, nil)
+		} else {
+			err = ddb.NewBranchAtCommit(ctx, b.Branch, b.Commit, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := ddb.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tr := range tags {
+		tag, err := ddb.ResolveTag(ctx, ref.NewTagRef(tr.GetPath()))
+		if err != nil {
+			return nil, err
+		}
+		if tag.Meta.Timestamp != 0 && time.UnixMilli(int64(tag.Meta.Timestamp)).After(t) {
+			err = ddb.DeleteTag(ctx, tr)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return branches, nil
+}
+
+// CreateBranchesAsOf creates a branch named |prefix|/<branch> for every branch of |ddb|, at the commit the branch was
+// at as of |t|, and leaves the existing branches as they are. Branches that have no commits from before |t| are
+// skipped. It is an error if any of the new branches already exists.
+func CreateBranchesAsOf(ctx context.Context, ddb *doltdb.DoltDB, t time.Time, prefix string) ([]BranchAsOf, error) {
+	branches, err := BranchesAsOf(ctx, ddb, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []BranchAsOf
+	for _, b := range branches {
+		if b.Commit == nil {
+			continue
+		}
+		name := prefix + "/" + b.Branch.GetPath()
+		if !doltdb.IsValidUserBranchName(name) {
+			return nil, fmt.Errorf("%s is an invalid branch name", name)
+		}
+		newRef := ref.NewBranchRef(name)
+		exists, err := ddb.HasRef(ctx, newRef)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("fatal: A branch named '%s' already exists.", name)
+		}
+		created = append(created, BranchAsOf{Branch: newRef, Commit: b.Commit})
+	}
+
+	for _, b := range created {
+		err = ddb.NewBranchAtCommit(ctx, b.Branch, b.Commit, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
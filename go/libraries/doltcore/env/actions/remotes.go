@@ -127,6 +127,115 @@ func DoPush(ctx context.Context, rsr env.RepoStateReader, rsw env.RepoStateWrite
 	return err
 }
 
+// PushAll pushes each of the refs described by |opts| to |destDB|. The chunks of every ref are sent to |destDB| in a
+// single pull, rather than negotiating with the remote once per ref. It returns the result of pushing each ref, which
+// is nil if the ref was pushed, or an error such as ErrCantFF or doltdb.ErrUpToDate if it wasn't. A non-nil error is
+// returned if the push failed entirely.
+func PushAll(ctx context.Context, rsw env.RepoStateWriter, srcDB, destDB *doltdb.DoltDB, tempTableDir string, opts []*env.PushOpts, progStarter ProgStarter, progStopper ProgStopper) ([]error, error) {
+	results := make([]error, len(opts))
+	addrs := make([]hash.Hash, len(opts))
+
+	var toPull []hash.Hash
+	for i, o := range opts {
+		addrs[i], results[i] = pushAllAddr(ctx, o, srcDB, destDB)
+		if results[i] == nil {
+			toPull = append(toPull, addrs[i])
+		}
+	}
+
+	if len(toPull) > 0 {
+		newCtx, cancelFunc := context.WithCancel(ctx)
+		wg, statsCh := progStarter(newCtx)
+		err := destDB.PullChunks(ctx, tempTableDir, srcDB, toPull, statsCh)
+		progStopper(cancelFunc, wg, statsCh)
+		if err != nil && !errors.Is(err, pull.ErrDBUpToDate) {
+			return nil, fmt.Errorf("%w; %s", ErrUnknownPushErr, err.Error())
+		}
+		cli.Println()
+	}
+
+	for i, o := range opts {
+		if results[i] == nil {
+			results[i] = updatePushedRef(ctx, o, srcDB, destDB, addrs[i])
+		}
+
+		if o.SetUpstream && (results[i] == nil || errors.Is(results[i], doltdb.ErrUpToDate)) {
+			err := rsw.UpdateBranch(o.SrcRef.GetPath(), env.BranchConfig{
+				Merge: ref.MarshalableRef{
+					Ref: o.DestRef,
+				},
+				Remote: o.Remote.Name,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// pushAllAddr returns the address of the commit or tag that |opts| pushes, or an error if it can't or shouldn't be
+// pushed to |destDB|.
+func pushAllAddr(ctx context.Context, opts *env.PushOpts, srcDB, destDB *doltdb.DoltDB) (hash.Hash, error) {
+	switch opts.SrcRef.GetType() {
+	case ref.BranchRefType:
+		cm, err := srcDB.ResolveCommitRef(ctx, opts.SrcRef)
+		if err != nil {
+			return hash.Hash{}, err
+		}
+		if opts.Mode == ref.FastForwardOnly && opts.RemoteRef != nil {
+			canFF, err := srcDB.CanFastForward(ctx, opts.RemoteRef, cm)
+			if err != nil {
+				return hash.Hash{}, err
+			} else if !canFF {
+				return hash.Hash{}, ErrCantFF
+			}
+		}
+		return cm.HashOf()
+	case ref.TagRefType:
+		tg, err := srcDB.ResolveTag(ctx, opts.SrcRef.(ref.TagRef))
+		if err != nil {
+			return hash.Hash{}, err
+		}
+		addr, err := tg.GetAddr()
+		if err != nil {
+			return hash.Hash{}, err
+		}
+		if remoteTag, err := destDB.ResolveTag(ctx, opts.DestRef.(ref.TagRef)); err == nil {
+			if remoteAddr, err := remoteTag.GetAddr(); err == nil && remoteAddr == addr {
+				return hash.Hash{}, doltdb.ErrUpToDate
+			}
+		}
+		return addr, nil
+	default:
+		return hash.Hash{}, fmt.Errorf("%w: %s of type %s", ErrCannotPushRef, opts.SrcRef.String(), opts.SrcRef.GetType())
+	}
+}
+
+// updatePushedRef points the destination ref of |opts| at |addr| once its chunks have been pushed to |destDB|, and
+// updates the remote tracking ref for branches.
+func updatePushedRef(ctx context.Context, opts *env.PushOpts, srcDB, destDB *doltdb.DoltDB, addr hash.Hash) error {
+	if opts.SrcRef.GetType() == ref.TagRefType {
+		return destDB.SetHead(ctx, opts.DestRef, addr)
+	}
+
+	var err error
+	switch opts.Mode {
+	case ref.ForceUpdate:
+		err = destDB.SetHead(ctx, opts.DestRef, addr)
+		if err == nil && opts.RemoteRef != nil {
+			err = srcDB.SetHead(ctx, opts.RemoteRef, addr)
+		}
+	case ref.FastForwardOnly:
+		err = destDB.FastForwardToHash(ctx, opts.DestRef, addr)
+		if err == nil && opts.RemoteRef != nil {
+			err = srcDB.FastForwardToHash(ctx, opts.RemoteRef, addr)
+		}
+	}
+	return err
+}
+
 // PushTag pushes a commit tag and all underlying data from a local source database to a remote destination database.
 func PushTag(ctx context.Context, tempTableDir string, destRef ref.TagRef, srcDB, destDB *doltdb.DoltDB, tag *doltdb.Tag, statsCh chan pull.Stats) error {
 	var err error
@@ -496,6 +605,57 @@ func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB,
 	return nil
 }
 
+// PruneBranches deletes the remote-tracking branches of |remote| that no longer correspond to a branch in |srcDB|,
+// returning the refs that were pruned. A remote-tracking branch is kept if any of |refSpecs| or the remote's fetch
+// specs map a branch in |srcDB| to it. When |dryRun| is true, the refs to prune are returned without deleting them.
+func PruneBranches(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, refSpecs []ref.RemoteRefSpec, remote env.Remote, dryRun bool) ([]ref.DoltRef, error) {
+	fetchSpecs, err := env.GetRefSpecs(dbData.Rsr, remote.Name)
+	if err != nil {
+		return nil, err
+	}
+	refSpecs = append(append([]ref.RemoteRefSpec{}, refSpecs...), fetchSpecs...)
+
+	branches, err := srcDB.GetBranches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", env.ErrFailedToReadDb, err.Error())
+	}
+
+	tracked := make(map[string]struct{})
+	for _, rs := range refSpecs {
+		for _, b := range branches {
+			if remoteTrackRef := rs.DestRef(b); remoteTrackRef != nil {
+				tracked[remoteTrackRef.String()] = struct{}{}
+			}
+		}
+	}
+
+	remoteRefs, err := dbData.Ddb.GetRemoteRefs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", env.ErrFailedToReadFromDb, err.Error())
+	}
+
+	var pruned []ref.DoltRef
+	for _, r := range remoteRefs {
+		rr, ok := r.(ref.RemoteRef)
+		if !ok || rr.GetRemote() != remote.Name {
+			continue
+		}
+		if _, ok := tracked[rr.String()]; ok {
+			continue
+		}
+
+		if !dryRun {
+			err = dbData.Ddb.DeleteBranch(ctx, rr, nil)
+			if err != nil {
+				return pruned, fmt.Errorf("%w; failed to delete remote tracking ref '%s'; %s", env.ErrFailedToDeleteRemote, rr.String(), err.Error())
+			}
+		}
+		pruned = append(pruned, rr)
+	}
+
+	return pruned, nil
+}
+
 // SyncRoots is going to copy the root hash of the database from srcDb to destDb.
 // We can do this |Clone| if (1) destDb is empty, (2) destDb and srcDb are both
 // |TableFileStore|s, and (3) srcDb does *not* have a journal file. The most
@@ -531,18 +691,32 @@ func canSyncRootsWithClone(ctx context.Context, srcDb, destDb *doltdb.DoltDB, de
 // TODO     to prevent "restoring a remote", "cloning a backup", "syncing a remote" and "pushing
 // TODO     a backup." SyncRoots has more destructive potential than push right now.
 func SyncRoots(ctx context.Context, srcDb, destDb *doltdb.DoltDB, tempTableDir string, progStarter ProgStarter, progStopper ProgStopper) error {
+	_, err := SyncRootsWithGeneration(ctx, srcDb, destDb, tempTableDir, progStarter, progStopper)
+	return err
+}
+
+// SyncRootsWithGeneration is SyncRoots, returning a record of the sync. If destDb already has chunks, only the chunks
+// of srcDb that it doesn't have are copied, and the sync is incremental. The returned generation isn't numbered; see
+// RecordBackupGeneration.
+func SyncRootsWithGeneration(ctx context.Context, srcDb, destDb *doltdb.DoltDB, tempTableDir string, progStarter ProgStarter, progStopper ProgStopper) (BackupGeneration, error) {
 	srcRoot, err := srcDb.NomsRoot(ctx)
 	if err != nil {
-		return nil
+		return BackupGeneration{}, err
 	}
 
 	destRoot, err := destDb.NomsRoot(ctx)
 	if err != nil {
-		return err
+		return BackupGeneration{}, err
+	}
+
+	gen := BackupGeneration{
+		Time:        time.Now().UTC(),
+		Root:        srcRoot.String(),
+		Incremental: !destRoot.IsEmpty(),
 	}
 
 	if srcRoot == destRoot {
-		return pull.ErrDBUpToDate
+		return gen, pull.ErrDBUpToDate
 	}
 
 	newCtx, cancelFunc := context.WithCancel(ctx)
@@ -556,12 +730,15 @@ func SyncRoots(ctx context.Context, srcDb, destDb *doltdb.DoltDB, tempTableDir s
 
 	canClone, err := canSyncRootsWithClone(ctx, srcDb, destDb, destRoot)
 	if err != nil {
-		return err
+		return BackupGeneration{}, err
 	}
 
 	if canClone {
 		tfCh := make(chan pull.TableFileEvent)
+		statsDone := make(chan struct{})
+		var lastStats pull.Stats
 		go func() {
+			defer close(statsDone)
 			start := time.Now()
 			stats := make(map[string]iohelp.ReadStats)
 			for {
@@ -596,6 +773,7 @@ func SyncRoots(ctx context.Context, srcDb, destDb *doltdb.DoltDB, tempTableDir s
 							FetchedSourceBytes:       totalSentBytes,
 							FetchedSourceBytesPerSec: float64(totalSentBytes) / (time.Since(start).Seconds()),
 						}
+						lastStats = toEmit
 
 						// TODO: This looks wrong without a ctx.Done() select, but Puller does not conditionally send here...
 						select {
@@ -608,24 +786,41 @@ func SyncRoots(ctx context.Context, srcDb, destDb *doltdb.DoltDB, tempTableDir s
 
 		err := srcDb.Clone(ctx, destDb, tfCh)
 		close(tfCh)
+		<-statsDone
 		if err == nil {
-			return nil
+			gen.Chunks, gen.Bytes = lastStats.FetchedSourceChunks, lastStats.FinishedSendBytes
+			return gen, nil
 		}
 		if !errors.Is(err, pull.ErrCloneUnsupported) {
-			return err
+			return BackupGeneration{}, err
 		}
 
 		// If clone is unsupported, we can fall back to pull.
 	}
 
-	err = destDb.PullChunks(ctx, tempTableDir, srcDb, []hash.Hash{srcRoot}, statsCh)
+	// the puller only copies the chunks that destDb doesn't have, and its last stats count them
+	pullCh := make(chan pull.Stats)
+	statsDone := make(chan struct{})
+	var lastStats pull.Stats
+	go func() {
+		defer close(statsDone)
+		for s := range pullCh {
+			lastStats = s
+			statsCh <- s
+		}
+	}()
+
+	err = destDb.PullChunks(ctx, tempTableDir, srcDb, []hash.Hash{srcRoot}, pullCh)
+	close(pullCh)
+	<-statsDone
 	if err != nil {
-		return err
+		return BackupGeneration{}, err
 	}
 
 	destDb.CommitRoot(ctx, srcRoot, destRoot)
 
-	return nil
+	gen.Chunks, gen.Bytes = lastStats.FetchedSourceChunks, lastStats.FinishedSendBytes
+	return gen, nil
 }
 
 func HandleInitRemoteStorageClientErr(name, url string, err error) error {
@@ -29,6 +29,9 @@ type TagProps struct {
 	TaggerName  string
 	TaggerEmail string
 	Description string
+	// Sign, if set, signs the tag. It's called with the tag's signing payload, and returns the signature to store
+	// in the tag's metadata. See datas.TagSigningPayload.
+	Sign func(payload []byte) (string, error)
 }
 
 func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint string, props TagProps) error {
@@ -69,6 +72,16 @@ func CreateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagName, startPoint
 	}
 
 	meta := datas.NewTagMeta(props.TaggerName, props.TaggerEmail, props.Description)
+	if props.Sign != nil {
+		h, err := cm.HashOf()
+		if err != nil {
+			return err
+		}
+		meta.Signature, err = props.Sign(datas.TagSigningPayload(h, meta))
+		if err != nil {
+			return err
+		}
+	}
 
 	return ddb.NewTagAtCommit(ctx, tagRef, cm, meta)
 }
@@ -0,0 +1,104 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+)
+
+var ErrBranchNameNotAllowed = goerrors.NewKind("branch name '%s' is not allowed: %s")
+
+// BranchNamePolicy restricts the names of the branches that can be created, copied to or renamed to. It's configured
+// with these keys, any of which may be unset:
+//
+//	branch.name.allow: a regular expression that every branch name must match
+//	branch.name.deny: a regular expression that no branch name may match
+//	branch.name.prefixes: a comma separated list of prefixes, one of which every branch name must begin with
+//
+// Regular expressions must match the whole branch name.
+type BranchNamePolicy struct {
+	allow    *regexp.Regexp
+	deny     *regexp.Regexp
+	prefixes []string
+}
+
+// GetBranchNamePolicy returns the BranchNamePolicy in the supplied config, or an error if one of its regular
+// expressions is invalid.
+func GetBranchNamePolicy(cfg config.ReadableConfig) (*BranchNamePolicy, error) {
+	var p BranchNamePolicy
+	var err error
+	if p.allow, err = compileBranchNamePattern(cfg, BranchNameAllow); err != nil {
+		return nil, err
+	}
+	if p.deny, err = compileBranchNamePattern(cfg, BranchNameDeny); err != nil {
+		return nil, err
+	}
+	for _, prefix := range strings.Split(cfg.GetStringOrDefault(BranchNamePrefixes, ""), ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			p.prefixes = append(p.prefixes, prefix)
+		}
+	}
+	return &p, nil
+}
+
+func compileBranchNamePattern(cfg config.ReadableConfig, key string) (*regexp.Regexp, error) {
+	pattern := cfg.GetStringOrDefault(key, "")
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return re, nil
+}
+
+// Check returns ErrBranchNameNotAllowed if |branchName| doesn't satisfy the policy.
+func (p *BranchNamePolicy) Check(branchName string) error {
+	if len(p.prefixes) > 0 {
+		hasPrefix := false
+		for _, prefix := range p.prefixes {
+			if strings.HasPrefix(branchName, prefix) {
+				hasPrefix = true
+				break
+			}
+		}
+		if !hasPrefix {
+			return ErrBranchNameNotAllowed.New(branchName, fmt.Sprintf("it must begin with one of %s", strings.Join(p.prefixes, ", ")))
+		}
+	}
+	if p.allow != nil && !p.allow.MatchString(branchName) {
+		return ErrBranchNameNotAllowed.New(branchName, fmt.Sprintf("it must match %s", BranchNameAllow))
+	}
+	if p.deny != nil && p.deny.MatchString(branchName) {
+		return ErrBranchNameNotAllowed.New(branchName, fmt.Sprintf("it matches %s", BranchNameDeny))
+	}
+	return nil
+}
+
+// CheckBranchName returns an error if |branchName| doesn't satisfy the BranchNamePolicy in the supplied config.
+func CheckBranchName(cfg config.ReadableConfig, branchName string) error {
+	p, err := GetBranchNamePolicy(cfg)
+	if err != nil {
+		return err
+	}
+	return p.Check(branchName)
+}
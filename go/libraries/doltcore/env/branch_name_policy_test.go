@@ -0,0 +1,84 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/config"
+)
+
+func TestBranchNamePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     map[string]string
+		allowed []string
+		denied  []string
+	}{
+		{
+			name:    "no policy",
+			cfg:     map[string]string{},
+			allowed: []string{"main", "feature/x", "anything-goes"},
+		},
+		{
+			name:    "prefixes",
+			cfg:     map[string]string{BranchNamePrefixes: "feature/, fix/"},
+			allowed: []string{"feature/x", "fix/y"},
+			denied:  []string{"main", "features/x", "x/feature/"},
+		},
+		{
+			name:    "allow",
+			cfg:     map[string]string{BranchNameAllow: "[a-z0-9/-]+"},
+			allowed: []string{"feature/x-1"},
+			denied:  []string{"Feature/x", "feature_x"},
+		},
+		{
+			name:    "deny",
+			cfg:     map[string]string{BranchNameDeny: "tmp.*|.*wip.*"},
+			allowed: []string{"feature/tmp", "main"},
+			denied:  []string{"tmp", "tmp-1", "my-wip-branch"},
+		},
+		{
+			name: "all",
+			cfg: map[string]string{
+				BranchNamePrefixes: "feature/",
+				BranchNameAllow:    "feature/[A-Z]+-[0-9]+.*",
+				BranchNameDeny:     ".*-old",
+			},
+			allowed: []string{"feature/DOLT-123", "feature/DOLT-123-fix-merge"},
+			denied:  []string{"DOLT-123", "feature/fix-merge", "feature/DOLT-123-old"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := GetBranchNamePolicy(config.NewMapConfig(test.cfg))
+			require.NoError(t, err)
+			for _, name := range test.allowed {
+				assert.NoError(t, p.Check(name), name)
+			}
+			for _, name := range test.denied {
+				err := p.Check(name)
+				assert.True(t, ErrBranchNameNotAllowed.Is(err), name)
+			}
+		})
+	}
+
+	_, err := GetBranchNamePolicy(config.NewMapConfig(map[string]string{BranchNameAllow: "feature/("}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// CommitGraphFile is the name of the commit-graph file in the .dolt directory.
+const CommitGraphFile = "commit-graph"
+
+// CommitGraphPath returns the path of the commit-graph file, or "" if there's no .dolt directory.
+func (dEnv *DoltEnv) CommitGraphPath() string {
+	doltDir := dEnv.GetDoltDir()
+	if doltDir == "" {
+		return ""
+	}
+	return filepath.Join(doltDir, CommitGraphFile)
+}
+
+// HasCommitGraph returns whether this environment has a commit-graph file.
+func (dEnv *DoltEnv) HasCommitGraph() bool {
+	path := dEnv.CommitGraphPath()
+	if path == "" {
+		return false
+	}
+	exists, isDir := dEnv.FS.Exists(path)
+	return exists && !isDir
+}
+
+// ReadCommitGraph reads the commit-graph file.
+func (dEnv *DoltEnv) ReadCommitGraph() (*datas.CommitGraph, error) {
+	rd, err := dEnv.FS.OpenForRead(dEnv.CommitGraphPath())
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return datas.ReadCommitGraph(rd)
+}
+
+// loadCommitGraph sets the database's commit-graph from the commit-graph file, if there is one. The commit-graph only
+// speeds up queries, so a commit-graph file that can't be read is ignored.
+func (dEnv *DoltEnv) loadCommitGraph() {
+	if dEnv.DoltDB == nil || !dEnv.HasCommitGraph() {
+		return
+	}
+	g, err := dEnv.ReadCommitGraph()
+	if err != nil {
+		return
+	}
+	dEnv.DoltDB.SetCommitGraph(g)
+}
+
+// WriteCommitGraph adds the commits reachable from the database's refs to its commit-graph, starting from the
+// commit-graph file if there is one and |rewrite| is false, and writes it to the commit-graph file. It returns the
+// commit-graph and the number of commits added to it.
+func (dEnv *DoltEnv) WriteCommitGraph(ctx context.Context, rewrite bool) (*datas.CommitGraph, int, error) {
+	g := datas.NewCommitGraph()
+	if !rewrite && dEnv.HasCommitGraph() {
+		if existing, err := dEnv.ReadCommitGraph(); err == nil {
+			g = existing
+		}
+	}
+
+	added, err := dEnv.DoltDB.UpdateCommitGraph(ctx, g)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Write to a temporary file and move it into place, so that a reader never sees a partially written file.
+	path := dEnv.CommitGraphPath()
+	tmpPath := path + ".tmp"
+	wr, err := dEnv.FS.OpenForWrite(tmpPath, 0644)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, err = g.WriteTo(wr)
+	if cerr := wr.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = dEnv.FS.DeleteFile(tmpPath)
+		return nil, 0, err
+	}
+	if err = dEnv.FS.MoveFile(tmpPath, path); err != nil {
+		return nil, 0, err
+	}
+
+	dEnv.DoltDB.SetCommitGraph(g)
+	return g, added, nil
+}
+
+// RemoveCommitGraph deletes the commit-graph file, and stops the database from using a commit-graph.
+func (dEnv *DoltEnv) RemoveCommitGraph() error {
+	if dEnv.DoltDB != nil {
+		dEnv.DoltDB.SetCommitGraph(nil)
+	}
+	if !dEnv.HasCommitGraph() {
+		return nil
+	}
+	return dEnv.FS.DeleteFile(dEnv.CommitGraphPath())
+}
@@ -15,10 +15,14 @@
 package env
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -44,6 +48,7 @@ const (
 	RemotesApiHostPortKey = "remotes.default_port"
 
 	AddCredsUrlKey     = "creds.add_url"
+	CredsHelperKey     = "creds.helper"
 	DoltLabInsecureKey = "doltlab.insecure"
 
 	MetricsDisabled = "metrics.disabled"
@@ -52,6 +57,31 @@ const (
 	MetricsInsecure = "metrics.insecure"
 
 	PushAutoSetupRemote = "push.autosetupremote"
+
+	FetchVerify         = "fetch.verify"
+	FetchSharedCacheDir = "fetch.shared_cache_dir"
+
+	SigningKey           = "user.signingkey"
+	CommitGpgSign        = "commit.gpgsign"
+	TagGpgSign           = "tag.gpgsign"
+	GpgFormat            = "gpg.format"
+	GpgProgram           = "gpg.program"
+	GpgSSHAllowedSigners = "gpg.ssh.allowedsignersfile"
+
+	BranchNameAllow    = "branch.name.allow"
+	BranchNameDeny     = "branch.name.deny"
+	BranchNamePrefixes = "branch.name.prefixes"
+
+	// ExternalTableSchemes are the schemes of the locations external tables may read, separated by commas, such as
+	// file,s3. No schemes are enabled if it isn't set.
+	ExternalTableSchemes = "external_table.schemes"
+
+	// SqlServerCreds is the id of the credentials registered with DOLT_LOGIN that sql-server uses for remote calls.
+	SqlServerCreds = "sqlserver.creds"
+	// SqlServerUserCredsPrefix begins the keys of the credentials registered with DOLT_LOGIN for a single SQL user.
+	SqlServerUserCredsPrefix = "sqlserver.user_creds."
+	// SqlServerCredsOwnerPrefix begins the keys naming the SQL user that created credentials with DOLT_LOGIN.
+	SqlServerCredsOwnerPrefix = "sqlserver.creds_owner."
 )
 
 var LocalConfigWhitelist = set.NewStrSet([]string{UserNameKey, UserEmailKey})
@@ -225,6 +255,56 @@ func GetNameAndEmail(cfg config.ReadableConfig) (string, string, error) {
 	return name, email, nil
 }
 
+// SqlServerUserCredsKey returns the config key of the credentials registered with DOLT_LOGIN for the SQL user |user|.
+func SqlServerUserCredsKey(user string) string {
+	return SqlServerUserCredsPrefix + user
+}
+
+// SqlServerCredsOwnerKey returns the config key naming the SQL user that created the credentials with id |keyID|.
+func SqlServerCredsOwnerKey(keyID string) string {
+	return SqlServerCredsOwnerPrefix + keyID
+}
+
+// GetSqlServerCredsKeyID returns the id of the credentials that sql-server uses for remote calls made by the SQL user
+// |user|: the credentials registered for |user| if there are any, otherwise the credentials registered for the server.
+// It returns an empty string if no credentials are registered.
+func GetSqlServerCredsKeyID(cfg config.ReadableConfig, user string) string {
+	if user != "" {
+		if kid := GetStringOrDefault(cfg, SqlServerUserCredsKey(user), ""); kid != "" {
+			return kid
+		}
+	}
+	return GetStringOrDefault(cfg, SqlServerCreds, "")
+}
+
+// VerifyFetches returns whether the chunks downloaded by a fetch, pull or clone should be verified against their
+// addresses, which they are if |verifyFlag| is set or if fetch.verify is true in the supplied config
+func VerifyFetches(cfg config.ReadableConfig, verifyFlag bool) (bool, error) {
+	if verifyFlag {
+		return true, nil
+	}
+	verify, err := strconv.ParseBool(GetStringOrDefault(cfg, FetchVerify, "false"))
+	if err != nil {
+		return false, fmt.Errorf("invalid value for %s: %w", FetchVerify, err)
+	}
+	return verify, nil
+}
+
+// SharedCacheDir returns the directory of the shared cache that fetch, pull and clone download chunks through, which
+// is set by fetch.shared_cache_dir in the supplied config. It's empty if no shared cache is configured. A relative
+// directory is resolved against the working directory.
+func SharedCacheDir(cfg config.ReadableConfig) (string, error) {
+	dir := GetStringOrDefault(cfg, FetchSharedCacheDir, "")
+	if dir == "" {
+		return "", nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for %s: %w", FetchSharedCacheDir, err)
+	}
+	return dir, nil
+}
+
 // writeableLocalDoltCliConfig is an extension to DoltCliConfig that reads values from the hierarchy but writes to
 // local config.
 type writeableLocalDoltCliConfig struct {
@@ -285,3 +365,30 @@ func (w writeableLocalDoltCliConfig) Unset(params []string) error {
 
 	return cfg.Unset(params)
 }
+
+// GetSigner returns the signer for the signing key in the supplied config
+func GetSigner(cfg config.ReadableConfig) (*creds.Signer, error) {
+	return creds.NewSigner(
+		cfg.GetStringOrDefault(GpgFormat, creds.OpenPGPSigningFormat),
+		cfg.GetStringOrDefault(SigningKey, ""),
+		cfg.GetStringOrDefault(GpgProgram, ""))
+}
+
+// GetSignFunc returns the function that signs a commit or tag with the signing key in the supplied config, or nil if
+// it shouldn't be signed. It's signed if |sign| is true, or if |defaultKey|, commit.gpgsign or tag.gpgsign, is true in
+// the config and |noSign| is false.
+func GetSignFunc(ctx context.Context, cfg config.ReadableConfig, defaultKey string, sign, noSign bool) (func(payload []byte) (string, error), error) {
+	if !sign {
+		byDefault, err := strconv.ParseBool(cfg.GetStringOrDefault(defaultKey, "false"))
+		if noSign || err != nil || !byDefault {
+			return nil, nil
+		}
+	}
+	signer, err := GetSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return func(payload []byte) (string, error) {
+		return signer.Sign(ctx, payload)
+	}, nil
+}
@@ -122,3 +122,16 @@ func TestWritableDoltConfigNoLocal(t *testing.T) {
 	_, err = gCfg.GetString("test")
 	assert.Equal(t, config.ErrConfigParamNotFound, err)
 }
+
+func TestGetSqlServerCredsKeyID(t *testing.T) {
+	cfg := config.NewMapConfig(map[string]string{})
+	assert.Equal(t, "", GetSqlServerCredsKeyID(cfg, "root"))
+
+	require.NoError(t, cfg.SetStrings(map[string]string{SqlServerCreds: "serverkey"}))
+	assert.Equal(t, "serverkey", GetSqlServerCredsKeyID(cfg, "root"))
+	assert.Equal(t, "serverkey", GetSqlServerCredsKeyID(cfg, ""))
+
+	require.NoError(t, cfg.SetStrings(map[string]string{SqlServerUserCredsKey("root"): "rootkey"}))
+	assert.Equal(t, "rootkey", GetSqlServerCredsKeyID(cfg, "root"))
+	assert.Equal(t, "serverkey", GetSqlServerCredsKeyID(cfg, "other"))
+}
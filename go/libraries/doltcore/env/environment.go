@@ -180,6 +180,10 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr s
 		}
 	}
 
+	if dbLoadErr == nil {
+		dEnv.loadCommitGraph()
+	}
+
 	if dEnv.RSLoadErr == nil && dbLoadErr == nil {
 		// If the working set isn't present in the DB, create it from the repo state. This step can be removed post 1.0.
 		_, err := dEnv.WorkingSet(ctx)
@@ -817,6 +821,25 @@ func (dEnv *DoltEnv) UserDoltCreds() (creds.DoltCreds, bool, error) {
 	return creds.DoltCreds{}, false, nil
 }
 
+// DoltCredsForKeyID returns the credentials with the key id |kid| from the user's credentials directory
+func (dEnv *DoltEnv) DoltCredsForKeyID(kid string) (creds.DoltCreds, error) {
+	dir, err := dEnv.CredsDir()
+	if err != nil {
+		return creds.DoltCreds{}, err
+	}
+
+	path, err := dEnv.FindCreds(dir, kid)
+	if err != nil {
+		return creds.DoltCreds{}, fmt.Errorf("could not find credentials '%s': %w", kid, err)
+	}
+
+	c, err := creds.JWKCredsReadFromFile(dEnv.FS, path)
+	if err != nil || !c.IsPrivKeyValid() || !c.IsPubKeyValid() {
+		return creds.DoltCreds{}, fmt.Errorf("%w: %s", ErrInvalidCredsFile, path)
+	}
+	return c, nil
+}
+
 // GetGRPCDialParams implements dbfactory.GRPCDialProvider
 func (dEnv *DoltEnv) GetGRPCDialParams(config grpcendpoint.Config) (dbfactory.GRPCRemoteConfig, error) {
 	return NewGRPCDialProviderFromDoltEnv(dEnv).GetGRPCDialParams(config)
@@ -870,6 +893,16 @@ func (dEnv *DoltEnv) AddRemote(r Remote) error {
 	return dEnv.RepoState.Save(dEnv.FS)
 }
 
+// UpdateRemote replaces the configuration of the existing remote with the same name as |r|
+func (dEnv *DoltEnv) UpdateRemote(r Remote) error {
+	if _, ok := dEnv.RepoState.Remotes[r.Name]; !ok {
+		return ErrRemoteNotFound
+	}
+
+	dEnv.RepoState.AddRemote(r)
+	return dEnv.RepoState.Save(dEnv.FS)
+}
+
 func (dEnv *DoltEnv) GetBackups() (map[string]Remote, error) {
 	if dEnv.RSLoadErr != nil {
 		return nil, dEnv.RSLoadErr
@@ -16,8 +16,10 @@ package env
 
 import (
 	"crypto/tls"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"runtime"
 	"strings"
 	"unicode"
@@ -25,10 +27,14 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/grpcendpoint"
 )
 
+// RemotePasswordEnvVar is the environment variable that holds the password used to authenticate with remotes as a user
+const RemotePasswordEnvVar = "DOLT_REMOTE_PASSWORD"
+
 // GRPCDialProvider implements dbfactory.GRPCDialProvider. By default, it is not able to use custom user credentials, but
 // if it is initialized with a DoltEnv, it will load custom user credentials from it.
 type GRPCDialProvider struct {
@@ -88,7 +94,7 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 	if config.Creds != nil {
 		opts = append(opts, grpc.WithPerRPCCredentials(config.Creds))
 	} else if config.WithEnvCreds {
-		rpcCreds, err := p.getRPCCreds(endpoint)
+		rpcCreds, err := p.getRPCCreds(config, endpoint)
 		if err != nil {
 			return dbfactory.GRPCRemoteConfig{}, err
 		}
@@ -104,8 +110,11 @@ func (p GRPCDialProvider) GetGRPCDialParams(config grpcendpoint.Config) (dbfacto
 }
 
 // getRPCCreds returns any RPC credentials available to this dial provider. If a DoltEnv has been configured
-// in this dial provider, it will be used to load custom user credentials, otherwise nil will be returned.
-func (p GRPCDialProvider) getRPCCreds(endpoint string) (credentials.PerRPCCredentials, error) {
+// in this dial provider, it will be used to load custom user credentials, otherwise nil will be returned. Credentials
+// given for the command take precedence over credentials configured for the remote in |config|, which take
+// precedence over the credential helper set by creds.helper, which takes precedence over the user's default
+// credentials.
+func (p GRPCDialProvider) getRPCCreds(config grpcendpoint.Config, endpoint string) (credentials.PerRPCCredentials, error) {
 	if p.dEnv == nil {
 		return nil, nil
 	}
@@ -114,6 +123,34 @@ func (p GRPCDialProvider) getRPCCreds(endpoint string) (credentials.PerRPCCreden
 		return p.dEnv.UserPassConfig.RPCCreds(), nil
 	}
 
+	if config.User != "" {
+		pass, ok := os.LookupEnv(RemotePasswordEnvVar)
+		if !ok {
+			return nil, fmt.Errorf("must set %s environment variable to authenticate with the remote as user '%s'", RemotePasswordEnvVar, config.User)
+		}
+		return creds.DoltCredsForPass{Username: config.User, Password: pass}.RPCCreds(), nil
+	}
+
+	if config.CredsKeyID != "" {
+		dCreds, err := p.dEnv.DoltCredsForKeyID(config.CredsKeyID)
+		if err != nil {
+			return nil, err
+		}
+		return dCreds.RPCCreds(getHostFromEndpoint(endpoint)), nil
+	}
+
+	credsHelper := config.CredsHelper
+	if credsHelper == "" && p.dEnv.Config != nil {
+		credsHelper = p.dEnv.Config.GetStringOrDefault(CredsHelperKey, "")
+	}
+	if credsHelper != "" {
+		protocol := "https"
+		if config.Insecure {
+			protocol = "http"
+		}
+		return creds.NewHelperRPCCreds(credsHelper, protocol, endpoint), nil
+	}
+
 	dCreds, valid, err := p.dEnv.UserDoltCreds()
 	if err != nil {
 		return nil, ErrInvalidCredsFile
@@ -152,6 +152,50 @@ func MultiEnvForDirectory(
 	return mrEnv, nil
 }
 
+// LoadSiblingEnv loads the database named |dbName| from a directory beside the one |dEnv| was loaded from, matching
+// directories to database names the way MultiEnvForDirectory does when run from their parent directory. It returns
+// |dEnv| itself if that's the database named, and nil if there's no such database.
+func LoadSiblingEnv(ctx context.Context, dEnv *DoltEnv, dbName string) (*DoltEnv, error) {
+	parentFS, err := dEnv.FS.WithWorkingDir("..")
+	if err != nil {
+		return nil, err
+	}
+	path, err := dEnv.FS.Abs("")
+	if err != nil {
+		return nil, err
+	}
+	ownDir := filepath.Base(path)
+
+	var found *DoltEnv
+	err = parentFS.Iter(".", false, func(path string, size int64, isDir bool) (stop bool) {
+		dir := filepath.Base(path)
+		if !isDir || dirToDBName(dir) != dbName {
+			return false
+		}
+		if dir == ownDir {
+			found = dEnv
+			return true
+		}
+
+		newFs, err := parentFS.WithWorkingDir(dir)
+		if err != nil {
+			return false
+		}
+
+		newEnv := Load(ctx, GetCurrentUserHomeDir, newFs, doltdb.LocalDirDoltDB, dEnv.Version)
+		if newEnv.Valid() {
+			found = newEnv
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
 // MultiEnvForPaths takes a variable list of EnvNameAndPath objects loads each of the environments, and returns a new
 // MultiRepoEnv
 func MultiEnvForPaths(
@@ -245,6 +289,12 @@ func (mrEnv *MultiRepoEnv) addEnv(name string, dEnv *DoltEnv) {
 	})
 }
 
+// AddEnv adds an environment to the MultiRepoEnv by name, e.g. to include a database loaded from outside the
+// MultiRepoEnv's directory
+func (mrEnv *MultiRepoEnv) AddEnv(name string, dEnv *DoltEnv) {
+	mrEnv.addEnv(name, dEnv)
+}
+
 // GetEnv returns the env with the name given, or nil if no such env exists
 func (mrEnv *MultiRepoEnv) GetEnv(name string) *DoltEnv {
 	var found *DoltEnv
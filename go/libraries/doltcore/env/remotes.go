@@ -45,6 +45,7 @@ var ErrCannotSetUpstreamForTag = errors.New("cannot set upstream for tag")
 var ErrCannotPushRef = errors.New("cannot push ref")
 var ErrNoRefSpecForRemote = errors.New("no refspec for remote")
 var ErrInvalidSetUpstreamArgs = errors.New("invalid set-upstream arguments")
+var ErrInvalidPushAllArgs = errors.New("--all and --tags cannot be used with a refspec")
 var ErrInvalidFetchSpec = errors.New("invalid fetch spec")
 var ErrPullWithRemoteNoUpstream = errors.New("You asked to pull from the remote '%s', but did not specify a branch. Because this is not the default configured remote for your current branch, you must specify a branch.")
 var ErrPullWithNoRemoteAndNoUpstream = errors.New("There is no tracking information for the current branch.\nPlease specify which branch you want to merge with.\n\n\tdolt pull <remote> <branch>\n\nIf you wish to set tracking information for this branch you can do so with:\n\n\t dolt push --set-upstream <remote> <branch>\n")
@@ -242,6 +243,75 @@ func NewPushOpts(ctx context.Context, apr *argparser.ArgParseResults, rsr RepoSt
 	return opts, nil
 }
 
+// NewPushAllOpts returns the PushOpts for pushing every local branch, if |branches| is true, and every tag, if |tags|
+// is true, to the remote named by |args|, or the default remote if |args| is empty. Each ref is pushed to the ref with
+// the same name on the remote.
+func NewPushAllOpts(ctx context.Context, args []string, rsr RepoStateReader, ddb *doltdb.DoltDB, branches, tags, force, setUpstream bool) ([]*PushOpts, error) {
+	if len(args) > 1 {
+		return nil, ErrInvalidPushAllArgs
+	}
+	if setUpstream && !branches {
+		return nil, ErrCannotSetUpstreamForTag
+	}
+
+	var remote Remote
+	if len(args) == 1 {
+		remotes, err := rsr.GetRemotes()
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if remote, ok = remotes[args[0]]; !ok {
+			return nil, fmt.Errorf("%w: '%s'", ErrUnknownRemote, args[0])
+		}
+	} else {
+		var err error
+		if remote, err = GetDefaultRemote(rsr); err != nil {
+			return nil, err
+		}
+	}
+
+	var refs []ref.DoltRef
+	if branches {
+		branchRefs, err := ddb.GetBranches(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFailedToReadDb, err.Error())
+		}
+		refs = append(refs, branchRefs...)
+	}
+	if tags {
+		tagRefs, err := ddb.GetTags(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFailedToReadDb, err.Error())
+		}
+		refs = append(refs, tagRefs...)
+	}
+
+	opts := make([]*PushOpts, len(refs))
+	for i, r := range refs {
+		var remoteRef ref.DoltRef
+		if r.GetType() == ref.BranchRefType {
+			var err error
+			if remoteRef, err = GetTrackingRef(r, remote); err != nil {
+				return nil, err
+			}
+		}
+
+		opts[i] = &PushOpts{
+			SrcRef:    r,
+			DestRef:   r,
+			RemoteRef: remoteRef,
+			Remote:    remote,
+			Mode: ref.UpdateMode{
+				Force: force,
+			},
+			SetUpstream: setUpstream && r.GetType() == ref.BranchRefType,
+		}
+	}
+
+	return opts, nil
+}
+
 // NewFetchOpts returns remote and refSpec for given remote name. If remote name is not defined,
 // default remote is used. Default remote is "origin" if there are multiple remotes for now.
 func NewFetchOpts(args []string, rsr RepoStateReader) (Remote, []ref.RemoteRefSpec, error) {
@@ -462,6 +532,10 @@ func NewPullSpec(_ context.Context, rsr RepoStateReader, remoteName, remoteRefNa
 }
 
 func GetAbsRemoteUrl(fs filesys2.Filesys, cfg config.ReadableConfig, urlArg string) (string, string, error) {
+	if sshUrl, ok := dbfactory.SSHURLFromSCPLike(urlArg); ok {
+		return dbfactory.SSHScheme, sshUrl, nil
+	}
+
 	u, err := earl.Parse(urlArg)
 	if err != nil {
 		return "", "", err
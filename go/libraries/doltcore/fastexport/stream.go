@@ -0,0 +1,330 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fastexport reads and writes the streams of commits produced by dolt fast-export and consumed by dolt
+// fast-import. A stream is text, modeled on the git fast-import format:
+//
+//	dolt-fast-export 1
+//	commit refs/heads/main
+//	mark :2
+//	author Tim Sehn <tim@dolthub.com> 1686000000000
+//	timestamp 1686000000000
+//	data 14
+//	add t1 table
+//	from :1
+//	merge :3
+//	stmt 46
+//	CREATE TABLE `t1` (`pk` int, PRIMARY KEY (`pk`));
+//
+//	done
+//
+// Each commit records its metadata, the marks of its parents, and the SQL statements that produce its root value from
+// the root value of its first parent. Timestamps are milliseconds since the epoch. Messages and statements are
+// written as a byte count followed by that many bytes, so they may contain any characters.
+package fastexport
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	header        = "dolt-fast-export"
+	formatVersion = 1
+)
+
+// ErrInvalidStream is returned when a stream can't be parsed
+var ErrInvalidStream = errors.New("invalid fast-export stream")
+
+// Commit is a single commit in a stream
+type Commit struct {
+	// Ref is the name of the ref the commit was exported from, e.g. refs/heads/main
+	Ref string
+	// Mark identifies the commit within the stream
+	Mark int
+
+	Name          string
+	Email         string
+	UserTimestamp int64
+	Timestamp     uint64
+	Message       string
+
+	// Parents are the marks of the commit's parents, first parent first
+	Parents []int
+	// Statements are the SQL statements that produce the commit's root value from that of its first parent
+	Statements []string
+}
+
+// Writer writes commits to a stream
+type Writer struct {
+	wr      *bufio.Writer
+	started bool
+}
+
+// NewWriter returns a Writer that writes a stream to |wr|
+func NewWriter(wr io.Writer) *Writer {
+	return &Writer{wr: bufio.NewWriter(wr)}
+}
+
+// WriteCommit writes |c| to the stream. A commit's parents must be written before it.
+func (w *Writer) WriteCommit(c Commit) error {
+	if !w.started {
+		w.started = true
+		fmt.Fprintf(w.wr, "%s %d\n", header, formatVersion)
+	}
+
+	fmt.Fprintf(w.wr, "commit %s\n", c.Ref)
+	fmt.Fprintf(w.wr, "mark :%d\n", c.Mark)
+	fmt.Fprintf(w.wr, "author %s <%s> %d\n", c.Name, c.Email, c.UserTimestamp)
+	fmt.Fprintf(w.wr, "timestamp %d\n", c.Timestamp)
+	w.writeData("data", c.Message)
+	for i, p := range c.Parents {
+		if i == 0 {
+			fmt.Fprintf(w.wr, "from :%d\n", p)
+		} else {
+			fmt.Fprintf(w.wr, "merge :%d\n", p)
+		}
+	}
+	for _, stmt := range c.Statements {
+		w.writeData("stmt", stmt)
+	}
+	_, err := w.wr.WriteString("\n")
+	return err
+}
+
+func (w *Writer) writeData(cmd, data string) {
+	fmt.Fprintf(w.wr, "%s %d\n%s\n", cmd, len(data), data)
+}
+
+// Close ends the stream and flushes it to the underlying writer
+func (w *Writer) Close() error {
+	if !w.started {
+		fmt.Fprintf(w.wr, "%s %d\n", header, formatVersion)
+	}
+	if _, err := w.wr.WriteString("done\n"); err != nil {
+		return err
+	}
+	return w.wr.Flush()
+}
+
+// Reader reads commits from a stream
+type Reader struct {
+	rd      *bufio.Reader
+	lineNum int
+	started bool
+	done    bool
+}
+
+// NewReader returns a Reader for the stream in |rd|
+func NewReader(rd io.Reader) *Reader {
+	return &Reader{rd: bufio.NewReader(rd)}
+}
+
+// ReadCommit reads the next commit from the stream, returning io.EOF once the end of the stream is reached
+func (r *Reader) ReadCommit() (Commit, error) {
+	if r.done {
+		return Commit{}, io.EOF
+	}
+
+	if !r.started {
+		line, err := r.readLine()
+		if err != nil {
+			return Commit{}, err
+		}
+		var version int
+		if _, err := fmt.Sscanf(line, header+" %d", &version); err != nil {
+			return Commit{}, r.errorf("missing %s header", header)
+		}
+		if version != formatVersion {
+			return Commit{}, r.errorf("unsupported format version %d", version)
+		}
+		r.started = true
+	}
+
+	line, err := r.readLine()
+	for err == nil && line == "" {
+		line, err = r.readLine()
+	}
+	if err != nil {
+		return Commit{}, err
+	}
+
+	if line == "done" {
+		r.done = true
+		return Commit{}, io.EOF
+	}
+
+	var c Commit
+	var ok bool
+	if c.Ref, ok = cutPrefix(line, "commit "); !ok {
+		return Commit{}, r.errorf("expected commit, found '%s'", line)
+	}
+
+	if line, err = r.readLine(); err != nil {
+		return Commit{}, err
+	}
+	if c.Mark, err = parseMark(line, "mark "); err != nil {
+		return Commit{}, r.errorf("%s", err.Error())
+	}
+
+	if line, err = r.readLine(); err != nil {
+		return Commit{}, err
+	}
+	if err = c.parseAuthor(line); err != nil {
+		return Commit{}, r.errorf("%s", err.Error())
+	}
+
+	if line, err = r.readLine(); err != nil {
+		return Commit{}, err
+	}
+	ts, ok := cutPrefix(line, "timestamp ")
+	if !ok {
+		return Commit{}, r.errorf("expected timestamp, found '%s'", line)
+	}
+	if c.Timestamp, err = strconv.ParseUint(ts, 10, 64); err != nil {
+		return Commit{}, r.errorf("invalid timestamp '%s'", ts)
+	}
+
+	if c.Message, err = r.readData("data"); err != nil {
+		return Commit{}, err
+	}
+
+	for {
+		line, err = r.readLine()
+		if err != nil {
+			return Commit{}, err
+		}
+
+		switch {
+		case line == "":
+			return c, nil
+		case strings.HasPrefix(line, "from ") && len(c.Parents) == 0:
+			p, err := parseMark(line, "from ")
+			if err != nil {
+				return Commit{}, r.errorf("%s", err.Error())
+			}
+			c.Parents = append(c.Parents, p)
+		case strings.HasPrefix(line, "merge ") && len(c.Parents) > 0:
+			p, err := parseMark(line, "merge ")
+			if err != nil {
+				return Commit{}, r.errorf("%s", err.Error())
+			}
+			c.Parents = append(c.Parents, p)
+		case strings.HasPrefix(line, "stmt "):
+			stmt, err := r.readDataWithHeader(line, "stmt")
+			if err != nil {
+				return Commit{}, err
+			}
+			c.Statements = append(c.Statements, stmt)
+		default:
+			return Commit{}, r.errorf("unexpected '%s' in commit :%d", line, c.Mark)
+		}
+	}
+}
+
+func (r *Reader) readLine() (string, error) {
+	line, err := r.rd.ReadString('\n')
+	if err == io.EOF {
+		if line == "" {
+			return "", r.errorf("unexpected end of stream")
+		}
+	} else if err != nil {
+		return "", err
+	}
+	r.lineNum++
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+func (r *Reader) readData(cmd string) (string, error) {
+	line, err := r.readLine()
+	if err != nil {
+		return "", err
+	}
+	return r.readDataWithHeader(line, cmd)
+}
+
+// readDataWithHeader reads the data announced by |line|, which should be |cmd| followed by the length of the data
+func (r *Reader) readDataWithHeader(line, cmd string) (string, error) {
+	lenStr, ok := cutPrefix(line, cmd+" ")
+	if !ok {
+		return "", r.errorf("expected %s, found '%s'", cmd, line)
+	}
+	n, err := strconv.Atoi(lenStr)
+	if err != nil || n < 0 {
+		return "", r.errorf("invalid %s length '%s'", cmd, lenStr)
+	}
+
+	buf := make([]byte, n+1)
+	if _, err = io.ReadFull(r.rd, buf); err != nil {
+		return "", r.errorf("unexpected end of stream")
+	}
+	if buf[n] != '\n' {
+		return "", r.errorf("%s is not followed by a newline", cmd)
+	}
+
+	data := string(buf[:n])
+	r.lineNum += strings.Count(data, "\n") + 1
+	return data, nil
+}
+
+func (r *Reader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidStream, r.lineNum, fmt.Sprintf(format, args...))
+}
+
+func parseMark(line, prefix string) (int, error) {
+	markStr, ok := cutPrefix(line, prefix+":")
+	if !ok {
+		return 0, fmt.Errorf("expected %s, found '%s'", strings.TrimSpace(prefix), line)
+	}
+	mark, err := strconv.Atoi(markStr)
+	if err != nil || mark <= 0 {
+		return 0, fmt.Errorf("invalid mark '%s'", markStr)
+	}
+	return mark, nil
+}
+
+// parseAuthor parses a line of the form: author Name <email> timestamp
+func (c *Commit) parseAuthor(line string) error {
+	author, ok := cutPrefix(line, "author ")
+	if !ok {
+		return fmt.Errorf("expected author, found '%s'", line)
+	}
+
+	open := strings.LastIndex(author, " <")
+	end := strings.LastIndex(author, "> ")
+	if open == -1 || end < open {
+		return fmt.Errorf("invalid author '%s'", author)
+	}
+
+	ts, err := strconv.ParseInt(author[end+2:], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid author timestamp '%s'", author[end+2:])
+	}
+
+	c.Name = author[:open]
+	c.Email = author[open+2 : end]
+	c.UserTimestamp = ts
+	return nil
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fastexport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	commits := []Commit{
+		{
+			Ref:           "refs/heads/main",
+			Mark:          1,
+			Name:          "Tim Sehn",
+			Email:         "tim@dolthub.com",
+			UserTimestamp: 1686000000000,
+			Timestamp:     1686000000001,
+			Message:       "Initialize data repository",
+		},
+		{
+			Ref:           "refs/heads/main",
+			Mark:          2,
+			Name:          "Brian <Hendriks>",
+			Email:         "brian@dolthub.com",
+			UserTimestamp: 1686000001000,
+			Timestamp:     1686000001000,
+			Message:       "multi\nline\n\nmessage\n",
+			Parents:       []int{1},
+			Statements: []string{
+				"CREATE TABLE `t` (`pk` int, `v` text, PRIMARY KEY (`pk`));",
+				"INSERT INTO `t` (`pk`,`v`) VALUES (1,'a\nstmt 5\n\n');",
+			},
+		},
+		{
+			Ref:           "refs/heads/main",
+			Mark:          3,
+			Name:          "Aaron",
+			Email:         "aaron@dolthub.com",
+			UserTimestamp: 1686000002000,
+			Timestamp:     1686000002000,
+			Message:       "merge",
+			Parents:       []int{2, 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, c := range commits {
+		require.NoError(t, w.WriteCommit(c))
+	}
+	require.NoError(t, w.Close())
+
+	r := NewReader(&buf)
+	for _, expected := range commits {
+		c, err := r.ReadCommit()
+		require.NoError(t, err)
+		assert.Equal(t, expected, c)
+	}
+	_, err := r.ReadCommit()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestEmptyStream(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf).Close())
+	assert.Equal(t, "dolt-fast-export 1\ndone\n", buf.String())
+
+	_, err := NewReader(&buf).ReadCommit()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestInvalidStreams(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    string
+		expectErr string
+	}{
+		{name: "no header", stream: "commit refs/heads/main\n", expectErr: "missing dolt-fast-export header"},
+		{name: "unknown version", stream: "dolt-fast-export 2\ndone\n", expectErr: "unsupported format version 2"},
+		{name: "truncated", stream: "dolt-fast-export 1\ncommit refs/heads/main\nmark :1\n", expectErr: "unexpected end of stream"},
+		{name: "bad mark", stream: "dolt-fast-export 1\ncommit refs/heads/main\nmark 1\n", expectErr: "expected mark"},
+		{
+			name:      "bad author",
+			stream:    "dolt-fast-export 1\ncommit refs/heads/main\nmark :1\nauthor Tim 1\n",
+			expectErr: "invalid author",
+		},
+		{
+			name:      "short data",
+			stream:    "dolt-fast-export 1\ncommit refs/heads/main\nmark :1\nauthor Tim <t@d.com> 1\ntimestamp 1\ndata 10\nabc\n",
+			expectErr: "unexpected end of stream",
+		},
+		{
+			name:      "merge without from",
+			stream:    "dolt-fast-export 1\ncommit refs/heads/main\nmark :2\nauthor Tim <t@d.com> 1\ntimestamp 1\ndata 3\nabc\nmerge :1\n\n",
+			expectErr: "unexpected 'merge :1' in commit :2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(test.stream)).ReadCommit()
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidStream))
+			assert.Contains(t, err.Error(), test.expectErr)
+		})
+	}
+}
@@ -27,6 +27,15 @@ type Config struct {
 	Creds        credentials.PerRPCCredentials
 	WithEnvCreds bool
 
+	// If WithEnvCreds is set, CredsKeyID is the id of the user's dolt credentials to authenticate with, instead of the
+	// credentials selected by user.creds.
+	CredsKeyID string
+	// If WithEnvCreds is set, User is the user name to authenticate with. The password is read from the environment.
+	User string
+	// If WithEnvCreds is set, CredsHelper is the credential helper run to get the credentials to authenticate with,
+	// instead of the credentials selected by user.creds.
+	CredsHelper string
+
 	// If non-nil, this is used for transport level security in the dial
 	// options, instead of a default option based on `Insecure`.
 	TLSConfig *tls.Config
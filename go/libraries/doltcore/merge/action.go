@@ -51,6 +51,9 @@ type MergeSpec struct {
 	Email           string
 	Name            string
 	Date            time.Time
+
+	// VerifyForeignKeys is set to verify the foreign keys of the whole merged result, see MergeOpts.
+	VerifyForeignKeys bool
 }
 
 // NewMergeSpec returns MergeSpec object using arguments passed into this function, which are doltdb.Roots, username,
@@ -189,7 +192,7 @@ func ExecuteMerge(ctx context.Context, dEnv *env.DoltEnv, spec *MergeSpec) (map[
 		return nil, err
 	}
 	opts := editor.Options{Deaf: dEnv.BulkDbEaFactory(), Tempdir: tmpDir}
-	result, err := MergeCommits(ctx, spec.HeadC, spec.MergeC, opts)
+	result, err := MergeCommits(ctx, spec.HeadC, spec.MergeC, opts, MergeOpts{VerifyForeignKeys: spec.VerifyForeignKeys})
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -204,6 +207,21 @@ func ExecuteMerge(ctx context.Context, dEnv *env.DoltEnv, spec *MergeSpec) (map[
 	return result.Stats, nil
 }
 
+// PreviewMerge computes the merge described by |spec| and returns its stats without writing the result to the working
+// set. Fast-forward merges have no stats to report, callers should check for them before calling this.
+func PreviewMerge(ctx context.Context, dEnv *env.DoltEnv, spec *MergeSpec) (map[string]*MergeStats, error) {
+	tmpDir, err := dEnv.TempTableFilesDir()
+	if err != nil {
+		return nil, err
+	}
+	opts := editor.Options{Deaf: dEnv.BulkDbEaFactory(), Tempdir: tmpDir}
+	result, err := MergeCommits(ctx, spec.HeadC, spec.MergeC, opts, MergeOpts{VerifyForeignKeys: spec.VerifyForeignKeys})
+	if err != nil {
+		return nil, err
+	}
+	return result.Stats, nil
+}
+
 func mergedRootToWorking(
 	ctx context.Context,
 	squash bool,
@@ -0,0 +1,312 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// ColumnMergeDriver merges a cell that was changed differently on both sides of a three-way merge, before the cell is
+// declared a conflict. |left|, |right| and |base| are the cell's values as returned by the storage layer for a column
+// of type |typ|, and |base| is nil if the row didn't exist in the merge base. A driver returns false if it can't
+// reconcile the changes, in which case the row conflicts as usual. Errors are also treated as conflicts.
+type ColumnMergeDriver interface {
+	Merge(ctx context.Context, typ sql.Type, left, right, base interface{}) (interface{}, bool, error)
+}
+
+// ColumnMergeDriverFunc adapts an ordinary function to the ColumnMergeDriver interface.
+type ColumnMergeDriverFunc func(ctx context.Context, typ sql.Type, left, right, base interface{}) (interface{}, bool, error)
+
+// Merge implements ColumnMergeDriver.
+func (f ColumnMergeDriverFunc) Merge(ctx context.Context, typ sql.Type, left, right, base interface{}) (interface{}, bool, error) {
+	return f(ctx, typ, left, right, base)
+}
+
+const (
+	// JSONDeepMergeDriverName names the driver that merges JSON objects key by key, recursively.
+	JSONDeepMergeDriverName = "json_deep_merge"
+	// MaxMergeDriverName names the driver that takes the greater of the two values, e.g. for last-modified timestamps.
+	MaxMergeDriverName = "max"
+	// MinMergeDriverName names the driver that takes the lesser of the two values.
+	MinMergeDriverName = "min"
+	// SumMergeDriverName names the driver that applies both sides' changes to a numeric counter.
+	SumMergeDriverName = "sum"
+)
+
+var columnMergeDrivers = struct {
+	mu      sync.RWMutex
+	drivers map[string]ColumnMergeDriver
+}{
+	drivers: map[string]ColumnMergeDriver{
+		JSONDeepMergeDriverName: ColumnMergeDriverFunc(jsonDeepMerge),
+		MaxMergeDriverName:      ColumnMergeDriverFunc(maxMerge),
+		MinMergeDriverName:      ColumnMergeDriverFunc(minMerge),
+		SumMergeDriverName:      ColumnMergeDriverFunc(sumMerge),
+	},
+}
+
+// RegisterColumnMergeDriver makes |driver| available under |name|, so that it can be assigned to columns in the
+// dolt_merge_drivers system table. Registering an existing name replaces the previous driver.
+func RegisterColumnMergeDriver(name string, driver ColumnMergeDriver) {
+	columnMergeDrivers.mu.Lock()
+	defer columnMergeDrivers.mu.Unlock()
+	columnMergeDrivers.drivers[strings.ToLower(name)] = driver
+}
+
+func getColumnMergeDriver(name string) (ColumnMergeDriver, bool) {
+	columnMergeDrivers.mu.RLock()
+	defer columnMergeDrivers.mu.RUnlock()
+	d, ok := columnMergeDrivers.drivers[strings.ToLower(name)]
+	return d, ok
+}
+
+// boundMergeDriver is a ColumnMergeDriver resolved for a single column of a merged schema.
+type boundMergeDriver struct {
+	driver ColumnMergeDriver
+	typ    sql.Type
+}
+
+// resolveColumnMergeDrivers returns the merge driver for each non-primary-key column of |sch|, the merged schema of
+// table |tblName|, as assigned in |assignments|. Columns without a driver have a nil entry. An assignment naming the
+// column, as "table.column", takes precedence over one naming the column's type.
+func resolveColumnMergeDrivers(tblName string, sch schema.Schema, assignments []doltdb.MergeDriverAssignment) ([]*boundMergeDriver, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	byTarget := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		byTarget[strings.ToLower(a.Target)] = a.Driver
+	}
+
+	cols := sch.GetNonPKCols().GetColumns()
+	drivers := make([]*boundMergeDriver, len(cols))
+	for i, col := range cols {
+		typ := col.TypeInfo.ToSqlType()
+		name, ok := byTarget[strings.ToLower(tblName+"."+col.Name)]
+		if !ok {
+			name, ok = byTarget[mergeDriverTypeName(typ)]
+		}
+		if !ok {
+			continue
+		}
+
+		d, ok := getColumnMergeDriver(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown merge driver '%s' assigned to column '%s' of table '%s'", name, col.Name, tblName)
+		}
+		drivers[i] = &boundMergeDriver{driver: d, typ: typ}
+	}
+	return drivers, nil
+}
+
+// mergeDriverTypeName returns the name that matches columns of type |typ| in dolt_merge_drivers: the type's name
+// without its length, precision or other modifiers, e.g. "decimal" for decimal(10,2).
+func mergeDriverTypeName(typ sql.Type) string {
+	name := strings.ToLower(typ.String())
+	if i := strings.IndexAny(name, "( "); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func maxMerge(_ context.Context, typ sql.Type, left, right, _ interface{}) (interface{}, bool, error) {
+	return pickMerge(typ, left, right, 1)
+}
+
+func minMerge(_ context.Context, typ sql.Type, left, right, _ interface{}) (interface{}, bool, error) {
+	return pickMerge(typ, left, right, -1)
+}
+
+// pickMerge returns whichever of |left| and |right| compares in the direction of |sign|. NULLs lose to any value.
+func pickMerge(typ sql.Type, left, right interface{}, sign int) (interface{}, bool, error) {
+	if left == nil {
+		return right, true, nil
+	} else if right == nil {
+		return left, true, nil
+	}
+	cmp, err := typ.Compare(left, right)
+	if err != nil {
+		return nil, false, err
+	}
+	if cmp*sign >= 0 {
+		return left, true, nil
+	}
+	return right, true, nil
+}
+
+// sumMerge treats the column as a counter, applying the change made on each side to the base value. A NULL is
+// treated as zero.
+func sumMerge(_ context.Context, typ sql.Type, left, right, base interface{}) (interface{}, bool, error) {
+	if !types.IsNumber(typ) {
+		return nil, false, fmt.Errorf("the %s merge driver can't be used with columns of type %s", SumMergeDriverName, typ.String())
+	}
+
+	var sum decimal.Decimal
+	for i, v := range []interface{}{left, right, base} {
+		d, err := toDecimal(v)
+		if err != nil {
+			return nil, false, err
+		}
+		if i < 2 {
+			sum = sum.Add(d)
+		} else {
+			sum = sum.Sub(d)
+		}
+	}
+
+	merged, inRange, err := typ.Convert(sum)
+	if err != nil {
+		return nil, false, err
+	} else if inRange != sql.InRange {
+		return nil, false, nil
+	}
+	return merged, true, nil
+}
+
+func toDecimal(v interface{}) (decimal.Decimal, error) {
+	switch v := v.(type) {
+	case nil:
+		return decimal.Zero, nil
+	case int8:
+		return decimal.NewFromInt(int64(v)), nil
+	case int16:
+		return decimal.NewFromInt(int64(v)), nil
+	case int32:
+		return decimal.NewFromInt(int64(v)), nil
+	case int64:
+		return decimal.NewFromInt(v), nil
+	case uint8:
+		return decimal.NewFromInt(int64(v)), nil
+	case uint16:
+		return decimal.NewFromInt(int64(v)), nil
+	case uint32:
+		return decimal.NewFromInt(int64(v)), nil
+	case uint64:
+		return decimal.NewFromString(fmt.Sprintf("%d", v))
+	case float32:
+		return decimal.NewFromFloat32(v), nil
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case decimal.Decimal:
+		return v, nil
+	default:
+		return decimal.Zero, fmt.Errorf("unexpected numeric value of type %T", v)
+	}
+}
+
+// jsonDeepMerge three-way merges JSON documents: keys of objects are merged independently and recursively, and any
+// other value conflicts if both sides changed it differently.
+func jsonDeepMerge(_ context.Context, typ sql.Type, left, right, base interface{}) (interface{}, bool, error) {
+	if !types.IsJSON(typ) {
+		return nil, false, fmt.Errorf("the %s merge driver can't be used with columns of type %s", JSONDeepMergeDriverName, typ.String())
+	}
+
+	l, err := jsonValue(left)
+	if err != nil {
+		return nil, false, err
+	}
+	r, err := jsonValue(right)
+	if err != nil {
+		return nil, false, err
+	}
+	b, err := jsonValue(base)
+	if err != nil {
+		return nil, false, err
+	}
+
+	merged, ok := mergeJSONValues(l, r, b)
+	if !ok {
+		return nil, false, nil
+	}
+	if merged == jsonMissing {
+		return nil, true, nil
+	}
+	return types.JSONDocument{Val: merged}, true, nil
+}
+
+// jsonMissing stands in for a NULL cell, or an object key that doesn't exist on one side of the merge.
+var jsonMissing = &struct{}{}
+
+func jsonValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return jsonMissing, nil
+	}
+	jv, ok := v.(types.JSONValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected JSON value of type %T", v)
+	}
+	doc, err := jv.Unmarshall(nil)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Val, nil
+}
+
+func mergeJSONValues(left, right, base interface{}) (interface{}, bool) {
+	switch {
+	case reflect.DeepEqual(left, right):
+		return left, true
+	case reflect.DeepEqual(left, base):
+		return right, true
+	case reflect.DeepEqual(right, base):
+		return left, true
+	}
+
+	lObj, lOk := left.(map[string]interface{})
+	rObj, rOk := right.(map[string]interface{})
+	if !lOk || !rOk {
+		return nil, false
+	}
+	bObj, _ := base.(map[string]interface{})
+
+	merged := make(map[string]interface{}, len(lObj))
+	for _, obj := range []map[string]interface{}{lObj, rObj} {
+		for k := range obj {
+			if _, ok := merged[k]; ok {
+				continue
+			}
+			v, ok := mergeJSONValues(jsonField(lObj, k), jsonField(rObj, k), jsonField(bObj, k))
+			if !ok {
+				return nil, false
+			}
+			merged[k] = v
+		}
+	}
+	for k, v := range merged {
+		if v == jsonMissing {
+			delete(merged, k)
+		}
+	}
+	return merged, true
+}
+
+func jsonField(obj map[string]interface{}, key string) interface{} {
+	if v, ok := obj[key]; ok {
+		return v
+	}
+	return jsonMissing
+}
@@ -0,0 +1,190 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+func TestColumnMergeDrivers(t *testing.T) {
+	ctx := context.Background()
+	t1 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		driver            string
+		typ               sql.Type
+		left, right, base interface{}
+		expected          interface{}
+		conflict          bool
+	}{
+		{
+			name:     "sum of two increments",
+			driver:   SumMergeDriverName,
+			typ:      types.Int32,
+			left:     int32(12),
+			right:    int32(15),
+			base:     int32(10),
+			expected: int32(17),
+		},
+		{
+			name:     "sum with a NULL base",
+			driver:   SumMergeDriverName,
+			typ:      types.Int64,
+			left:     int64(1),
+			right:    int64(2),
+			expected: int64(3),
+		},
+		{
+			name:     "sum out of range conflicts",
+			driver:   SumMergeDriverName,
+			typ:      types.Int8,
+			left:     int8(100),
+			right:    int8(100),
+			base:     int8(0),
+			conflict: true,
+		},
+		{
+			name:     "sum of a string conflicts",
+			driver:   SumMergeDriverName,
+			typ:      types.Text,
+			left:     "a",
+			right:    "b",
+			conflict: true,
+		},
+		{
+			name:     "max of timestamps",
+			driver:   MaxMergeDriverName,
+			typ:      types.Timestamp,
+			left:     t2,
+			right:    t1,
+			expected: t2,
+		},
+		{
+			name:     "min of timestamps",
+			driver:   MinMergeDriverName,
+			typ:      types.Timestamp,
+			left:     t2,
+			right:    t1,
+			expected: t1,
+		},
+		{
+			name:     "max with a NULL",
+			driver:   MaxMergeDriverName,
+			typ:      types.Int32,
+			left:     nil,
+			right:    int32(-5),
+			expected: int32(-5),
+		},
+		{
+			name:     "json deep merge of different keys",
+			driver:   JSONDeepMergeDriverName,
+			typ:      types.JSON,
+			left:     types.MustJSON(`{"a": 2, "b": {"c": 1, "d": 1}, "e": 1}`),
+			right:    types.MustJSON(`{"a": 1, "b": {"c": 1, "d": 2}, "f": 1}`),
+			base:     types.MustJSON(`{"a": 1, "b": {"c": 1, "d": 1}, "e": 1}`),
+			expected: types.MustJSON(`{"a": 2, "b": {"c": 1, "d": 2}, "f": 1}`),
+		},
+		{
+			name:     "json deep merge of the same key conflicts",
+			driver:   JSONDeepMergeDriverName,
+			typ:      types.JSON,
+			left:     types.MustJSON(`{"a": 2}`),
+			right:    types.MustJSON(`{"a": 3}`),
+			base:     types.MustJSON(`{"a": 1}`),
+			conflict: true,
+		},
+		{
+			name:     "json deep merge of arrays conflicts",
+			driver:   JSONDeepMergeDriverName,
+			typ:      types.JSON,
+			left:     types.MustJSON(`[1, 2]`),
+			right:    types.MustJSON(`[1, 3]`),
+			base:     types.MustJSON(`[1]`),
+			conflict: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, ok := getColumnMergeDriver(test.driver)
+			require.True(t, ok)
+			merged, ok, err := d.Merge(ctx, test.typ, test.left, test.right, test.base)
+			if test.conflict {
+				assert.False(t, ok && err == nil)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, test.expected, merged)
+		})
+	}
+}
+
+func TestResolveColumnMergeDrivers(t *testing.T) {
+	newColumn := func(name string, tag uint64, ti typeinfo.TypeInfo, partOfPK bool) schema.Column {
+		col, err := schema.NewColumnWithTypeInfo(name, tag, ti, partOfPK, "", false, "")
+		require.NoError(t, err)
+		return col
+	}
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		newColumn("pk", 0, typeinfo.Int64Type, true),
+		newColumn("hits", 1, typeinfo.Int64Type, false),
+		newColumn("views", 2, typeinfo.Int64Type, false),
+		newColumn("doc", 3, typeinfo.JSONType, false),
+	))
+
+	drivers, err := resolveColumnMergeDrivers("t", sch, nil)
+	require.NoError(t, err)
+	assert.Nil(t, drivers)
+
+	drivers, err = resolveColumnMergeDrivers("t", sch, []doltdb.MergeDriverAssignment{
+		{Target: "bigint", Driver: "max"},
+		{Target: "T.Hits", Driver: "sum"},
+		{Target: "other.doc", Driver: "json_deep_merge"},
+	})
+	require.NoError(t, err)
+	require.Len(t, drivers, 3)
+	require.NotNil(t, drivers[0])
+	require.NotNil(t, drivers[1])
+	assert.Nil(t, drivers[2])
+
+	_, err = resolveColumnMergeDrivers("t", sch, []doltdb.MergeDriverAssignment{{Target: "t.doc", Driver: "nope"}})
+	assert.Error(t, err)
+}
+
+func TestRegisterColumnMergeDriver(t *testing.T) {
+	RegisterColumnMergeDriver("Left_Wins", ColumnMergeDriverFunc(func(_ context.Context, _ sql.Type, left, _, _ interface{}) (interface{}, bool, error) {
+		return left, true, nil
+	}))
+	d, ok := getColumnMergeDriver("left_wins")
+	require.True(t, ok)
+	merged, ok, err := d.Merge(context.Background(), types.Int32, int32(1), int32(2), int32(3))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int32(1), merged)
+}
@@ -306,6 +306,51 @@ func TestMergeConcurrency(t *testing.T) {
 	assert.NoError(t, eg.Wait())
 }
 
+// TestMergeManyTables merges several tables with schema changes from SQL, which merges the tables concurrently, each
+// needing a *sql.Context to fill in column defaults. Run it with -race to check that the merges don't share a session.
+func TestMergeManyTables(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtu.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+	dbName, eng := engineFromEnvironment(ctx, dEnv)
+	sqlCtx, err := eng.NewLocalContext(ctx)
+	require.NoError(t, err)
+	sqlCtx.SetCurrentDatabase(dbName)
+
+	const tables = 8
+	var queries []string
+	for i := 0; i < tables; i++ {
+		queries = append(queries,
+			fmt.Sprintf("CREATE TABLE t%d (pk int PRIMARY KEY, c0 int)", i),
+			fmt.Sprintf("INSERT INTO t%d VALUES (1,1),(2,2)", i))
+	}
+	queries = append(queries, "CALL DOLT_COMMIT('-Am', 'created tables')", "CALL DOLT_BRANCH('other')")
+	for i := 0; i < tables; i++ {
+		queries = append(queries, fmt.Sprintf("ALTER TABLE t%d ADD COLUMN c1 int DEFAULT (c0 * 10)", i))
+	}
+	queries = append(queries, "CALL DOLT_COMMIT('-am', 'added columns on main')", "CALL DOLT_CHECKOUT('other')")
+	for i := 0; i < tables; i++ {
+		queries = append(queries, fmt.Sprintf("INSERT INTO t%d VALUES (3,3)", i))
+	}
+	queries = append(queries, "CALL DOLT_COMMIT('-am', 'added rows on other')", "CALL DOLT_CHECKOUT('main')",
+		"CALL DOLT_MERGE('other')")
+	for _, q := range queries {
+		require.NoError(t, executeQuery(sqlCtx, eng, q), q)
+	}
+
+	for i := 0; i < tables; i++ {
+		_, iter, err := eng.Query(sqlCtx, fmt.Sprintf("SELECT * FROM t%d ORDER BY pk", i))
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(sqlCtx, nil, iter)
+		require.NoError(t, err)
+		assert.Equal(t, []sql.Row{
+			{int32(1), int32(1), int32(10)},
+			{int32(2), int32(2), int32(20)},
+			{int32(3), int32(3), int32(30)},
+		}, rows)
+	}
+}
+
 func runConcurrentTxs(ctx context.Context, eng *engine.SqlEngine, seed int) error {
 	sess, err := eng.NewDoltSession(ctx, sql.NewBaseSession())
 	if err != nil {
@@ -45,7 +45,9 @@ var ErrMultipleViolationsForRow = errors.New("multiple violations for row not su
 
 var ErrSameTblAddedTwice = goerrors.NewKind("table with same name '%s' added in 2 commits can't be merged")
 
-func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options) (*Result, error) {
+// MergeCommits three-way merges |mergeCommit| into |commit|. Schema conflicts are kept, rather than ending the merge,
+// and the other options of |mo| are used as given.
+func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, opts editor.Options, mo MergeOpts) (*Result, error) {
 	ancCommit, err := doltdb.GetCommitAncestor(ctx, commit, mergeCommit)
 	if err != nil {
 		return nil, err
@@ -66,10 +68,8 @@ func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, opts
 		return nil, err
 	}
 
-	mo := MergeOpts{
-		IsCherryPick:        false,
-		KeepSchemaConflicts: true,
-	}
+	mo.IsCherryPick = false
+	mo.KeepSchemaConflicts = true
 	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, mergeCommit, ancCommit, opts, mo)
 }
 
@@ -202,9 +202,14 @@ func MergeRoots(
 		return nil, err
 	}
 
+	merged, err := merger.mergeTables(ctx, tblNames, opts, mergeOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	var schConflicts []SchemaConflict
-	for _, tblName := range tblNames {
-		mergedTable, stats, err := merger.MergeTable(ctx, tblName, opts, mergeOpts)
+	for i, tblName := range tblNames {
+		mergedTable, stats, err := merged[i].table, merged[i].stats, merged[i].err
 		if err != nil {
 			return nil, err
 		}
@@ -267,7 +272,15 @@ func MergeRoots(
 		return nil, err
 	}
 
-	mergedRoot, _, err = AddForeignKeyViolations(ctx, mergedRoot, ancRoot, nil, h)
+	fkBaseRoot := ancRoot
+	if mergeOpts.VerifyForeignKeys {
+		// diffing against an empty root checks every row of the merged result
+		fkBaseRoot, err = doltdb.EmptyRootValue(ctx, ourRoot.VRW(), ourRoot.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+	}
+	mergedRoot, fkViolatedTables, err := AddForeignKeyViolations(ctx, mergedRoot, fkBaseRoot, nil, h)
 	if err != nil {
 		return nil, err
 	}
@@ -278,6 +291,18 @@ func MergeRoots(
 			return nil, err
 		}
 
+		if mergeOpts.VerifyForeignKeys {
+			fkViolations, err := describeForeignKeyViolations(ctx, mergedRoot, fkViolatedTables, h)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range fkViolations {
+				if stats, ok := tblToStats[v.Table]; ok {
+					stats.ForeignKeyViolations = append(stats.ForeignKeyViolations, v)
+				}
+			}
+		}
+
 		return &Result{
 			Root:            mergedRoot,
 			SchemaConflicts: schConflicts,
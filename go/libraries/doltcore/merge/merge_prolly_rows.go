@@ -82,6 +82,12 @@ func mergeProllyTable(ctx context.Context, tm *TableMerger, mergedSch schema.Sch
 		sqlCtx = sql.NewContext(ctx)
 	}
 
+	valueMerger.drivers, err = resolveColumnMergeDrivers(tm.name, mergedSch, tm.mergeDrivers)
+	if err != nil {
+		return nil, nil, err
+	}
+	valueMerger.ctx, valueMerger.ns = sqlCtx, tm.ns
+
 	// Migrate primary index data to rewrite the values on the left side of the merge if necessary
 	schemasDifferentSize := len(tm.leftSch.GetAllCols().GetColumns()) != len(mergedSch.GetAllCols().GetColumns())
 	if schemasDifferentSize || leftMapping.IsIdentityMapping() == false {
@@ -137,7 +143,7 @@ func mergeProllyTableData(ctx *sql.Context, tm *TableMerger, finalSch schema.Sch
 	if err != nil {
 		return nil, nil, err
 	}
-	leftEditor := durable.ProllyMapFromIndex(lr).Mutate()
+	leftEditor := durable.ProllyMapFromIndex(lr).MutateInOrder()
 
 	ai, err := mergeTbl.GetArtifacts(ctx)
 	if err != nil {
@@ -706,7 +712,7 @@ type nullValidator struct {
 	// edits is the artifacts maps editor
 	artEditor *prolly.ArtifactsEditor
 	// leftEdits if the left-side row editor
-	leftEditor *prolly.MutableMap
+	leftEditor *prolly.OrderedMutator
 	// secEditors are the secondary index editors
 	secEditors []MutableSecondaryIdx
 	// theirRootish is the hash.Hash of the right-side revision
@@ -721,7 +727,7 @@ func newNullValidator(
 	tm *TableMerger,
 	vm *valueMerger,
 	artEditor *prolly.ArtifactsEditor,
-	leftEditor *prolly.MutableMap,
+	leftEditor *prolly.OrderedMutator,
 	secEditors []MutableSecondaryIdx,
 ) (nullValidator, error) {
 	theirRootish, err := tm.rightSrc.HashOf()
@@ -898,13 +904,13 @@ func (m *conflictMerger) finalize(ctx context.Context) (durable.ArtifactIndex, e
 // primaryMerger translates three-way diffs
 // on the primary index into merge-left updates.
 type primaryMerger struct {
-	mut         *prolly.MutableMap
+	mut         *prolly.OrderedMutator
 	valueMerger *valueMerger
 	tableMerger *TableMerger
 	finalSch    schema.Schema
 }
 
-func newPrimaryMerger(leftEditor *prolly.MutableMap, tableMerger *TableMerger, valueMerger *valueMerger, finalSch schema.Schema) (*primaryMerger, error) {
+func newPrimaryMerger(leftEditor *prolly.OrderedMutator, tableMerger *TableMerger, valueMerger *valueMerger, finalSch schema.Schema) (*primaryMerger, error) {
 	return &primaryMerger{
 		mut:         leftEditor,
 		valueMerger: valueMerger,
@@ -1193,21 +1199,36 @@ type valueMerger struct {
 	numCols                                int
 	vD                                     val.TupleDesc
 	leftMapping, rightMapping, baseMapping val.OrdinalMapping
-	syncPool                               pool.BuffPool
-	keyless                                bool
+	// addedOnOneSide records the columns of the merged schema that were added on only one side of the merge. The
+	// other side never had these columns, so their values can't conflict, even though rows migrated to the merged
+	// schema have them filled in with a default value.
+	addedOnOneSide []bool
+	// drivers holds the merge driver assigned to each column of the merged schema, if any, which resolves cells
+	// changed on both sides before they're declared a conflict. It's nil if no drivers apply to the table.
+	drivers  []*boundMergeDriver
+	ctx      context.Context
+	ns       tree.NodeStore
+	syncPool pool.BuffPool
+	keyless  bool
 }
 
 func newValueMerger(merged, leftSch, rightSch, baseSch schema.Schema, syncPool pool.BuffPool) *valueMerger {
 	leftMapping, rightMapping, baseMapping := generateSchemaMappings(merged, leftSch, rightSch, baseSch)
 
+	addedOnOneSide := make([]bool, len(baseMapping))
+	for i := range baseMapping {
+		addedOnOneSide[i] = baseMapping[i] == -1 && (leftMapping[i] == -1 || rightMapping[i] == -1)
+	}
+
 	return &valueMerger{
-		numCols:      merged.GetNonPKCols().Size(),
-		vD:           merged.GetValueDescriptor(),
-		leftMapping:  leftMapping,
-		rightMapping: rightMapping,
-		baseMapping:  baseMapping,
-		syncPool:     syncPool,
-		keyless:      schema.IsKeyless(merged),
+		numCols:        merged.GetNonPKCols().Size(),
+		vD:             merged.GetValueDescriptor(),
+		leftMapping:    leftMapping,
+		rightMapping:   rightMapping,
+		baseMapping:    baseMapping,
+		addedOnOneSide: addedOnOneSide,
+		syncPool:       syncPool,
+		keyless:        schema.IsKeyless(merged),
 	}
 }
 
@@ -1261,12 +1282,20 @@ func migrateDataToMergedSchema(ctx *sql.Context, tm *TableMerger, vm *valueMerge
 		return err
 	}
 	leftRows := durable.ProllyMapFromIndex(lr)
-	mut := leftRows.Mutate()
-	mapIter, err := mut.IterAll(ctx)
+	mapIter, err := leftRows.IterAll(ctx)
 	if err != nil {
 		return err
 	}
 
+	// Every row is rewritten, so the migrated rows are written to a new, empty map rather than as edits to the
+	// existing one. They're written in key order, so the map appends them directly to its tree as they arrive,
+	// rather than buffering them, and memory use doesn't grow with the size of the table.
+	empty, err := durable.NewEmptyIndex(ctx, tm.vrw, tm.ns, mergedSch)
+	if err != nil {
+		return err
+	}
+	mut := durable.ProllyMapFromIndex(empty).Mutate()
+
 	leftSch, err := tm.leftTbl.GetSchema(ctx)
 	if err != nil {
 		return err
@@ -1367,9 +1396,18 @@ func (m *valueMerger) processColumn(i int, left, right, base val.Tuple) ([]byte,
 		return leftCol, false
 	}
 
+	if m.addedOnOneSide[i] {
+		// The left mapping is replaced with an identity mapping once the left side has been migrated to the merged
+		// schema, so only the right mapping tells us which side the column was added on
+		if m.rightMapping[i] == -1 {
+			return leftCol, false
+		}
+		return rightCol, false
+	}
+
 	if base == nil {
 		// Conflicting insert
-		return nil, true
+		return m.mergeWithDriver(i, leftCol, rightCol, nil)
 	}
 
 	var baseVal []byte
@@ -1382,10 +1420,57 @@ func (m *valueMerger) processColumn(i int, left, right, base val.Tuple) ([]byte,
 
 	switch {
 	case leftModified && rightModified:
-		return nil, true
+		return m.mergeWithDriver(i, leftCol, rightCol, baseVal)
 	case leftModified:
 		return leftCol, false
 	default:
 		return rightCol, false
 	}
 }
+
+// mergeWithDriver merges column |i|, which was changed differently on both sides of the merge, using the merge driver
+// assigned to the column. Returns true if there is no driver, or the driver can't reconcile the changes.
+func (m *valueMerger) mergeWithDriver(i int, leftCol, rightCol, baseCol []byte) ([]byte, bool) {
+	if m.drivers == nil || m.drivers[i] == nil {
+		return nil, true
+	}
+	d := m.drivers[i]
+
+	desc := val.NewTupleDescriptor(m.vD.Types[i])
+	decode := func(cell []byte) (interface{}, error) {
+		if cell == nil {
+			return nil, nil
+		}
+		return index.GetField(m.ctx, desc, 0, val.NewTuple(m.syncPool, cell), m.ns)
+	}
+
+	left, err := decode(leftCol)
+	if err != nil {
+		return nil, true
+	}
+	right, err := decode(rightCol)
+	if err != nil {
+		return nil, true
+	}
+	base, err := decode(baseCol)
+	if err != nil {
+		return nil, true
+	}
+
+	merged, ok, err := d.driver.Merge(m.ctx, d.typ, left, right, base)
+	if err != nil || !ok {
+		return nil, true
+	}
+	if merged == nil {
+		if !m.vD.Types[i].Nullable {
+			return nil, true
+		}
+		return nil, false
+	}
+
+	tb := val.NewTupleBuilder(desc)
+	if err = index.PutField(m.ctx, m.ns, tb, 0, merged); err != nil {
+		return nil, true
+	}
+	return tb.Build(m.syncPool).GetField(0), false
+}
@@ -16,8 +16,11 @@ package merge
 
 import (
 	"context"
+	"runtime"
+	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/conflict"
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
@@ -37,6 +40,12 @@ type MergeOpts struct {
 	// KeepSchemaConflicts if schema conflicts should be
 	// stored, otherwise we end the merge with an error.
 	KeepSchemaConflicts bool
+	// VerifyForeignKeys checks the foreign keys of every row in the merged result, rather than only the rows that
+	// changed since the merge base, and describes the violations found in MergeStats.ForeignKeyViolations.
+	VerifyForeignKeys bool
+	// TablesMerged, if set, is called with the number of tables merged so far and the number of tables to merge,
+	// before any are merged and after each one is.
+	TablesMerged func(merged, total int)
 }
 
 type TableMerger struct {
@@ -53,6 +62,9 @@ type TableMerger struct {
 	rightSrc    doltdb.Rootish
 	ancestorSrc doltdb.Rootish
 
+	// mergeDrivers are the column merge drivers assigned in the left side's dolt_merge_drivers table
+	mergeDrivers []doltdb.MergeDriverAssignment
+
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
 }
@@ -84,6 +96,9 @@ type RootMerger struct {
 	rightSrc doltdb.Rootish
 	ancSrc   doltdb.Rootish
 
+	mergeDrivers       []doltdb.MergeDriverAssignment
+	mergeDriversLoaded bool
+
 	vrw types.ValueReadWriter
 	ns  tree.NodeStore
 }
@@ -158,13 +173,97 @@ func (rm *RootMerger) MergeTable(ctx context.Context, tblName string, opts edito
 	return &MergedTable{table: tbl}, stats, nil
 }
 
+// tableMergeResult is the result of merging a single table with MergeTable.
+type tableMergeResult struct {
+	table *MergedTable
+	stats *MergeStats
+	err   error
+}
+
+// mergeTables merges each of the tables named |tblNames|, returning the result of each merge in the same order. In the
+// __DOLT__ format the tables are merged concurrently, since merging a table only reads the roots being merged and
+// writes new chunks of its own. The results are returned rather than the first error encountered, so that callers see
+// the same error regardless of the order the merges finish in. In the old format tables are merged one at a time, and
+// no tables are merged after the first one that fails.
+func (rm *RootMerger) mergeTables(ctx context.Context, tblNames []string, opts editor.Options, mergeOpts MergeOpts) ([]tableMergeResult, error) {
+	results := make([]tableMergeResult, len(tblNames))
+	var mu sync.Mutex
+	merged := 0
+	tableMerged := func() {
+		if mergeOpts.TablesMerged == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		merged++
+		mergeOpts.TablesMerged(merged, len(tblNames))
+	}
+	if mergeOpts.TablesMerged != nil {
+		mergeOpts.TablesMerged(0, len(tblNames))
+	}
+
+	if !types.IsFormat_DOLT(rm.vrw.Format()) {
+		for i, tblName := range tblNames {
+			r := &results[i]
+			r.table, r.stats, r.err = rm.MergeTable(ctx, tblName, opts, mergeOpts)
+			if r.err != nil {
+				break
+			}
+			tableMerged()
+		}
+		return results, nil
+	}
+
+	// load the merge drivers before merging concurrently, rather than lazily in each merge
+	if err := rm.loadMergeDrivers(ctx); err != nil {
+		return nil, err
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(runtime.GOMAXPROCS(0))
+	// merges that need a *sql.Context use the session of |ctx|, with the context of the group
+	mergeCtx := context.Context(egCtx)
+	if sqlCtx, ok := ctx.(*sql.Context); ok {
+		mergeCtx = sqlCtx.WithContext(egCtx)
+	}
+	for i := range tblNames {
+		i := i
+		eg.Go(func() error {
+			r := &results[i]
+			r.table, r.stats, r.err = rm.MergeTable(mergeCtx, tblNames[i], opts, mergeOpts)
+			tableMerged()
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return results, nil
+}
+
+func (rm *RootMerger) loadMergeDrivers(ctx context.Context) error {
+	if rm.mergeDriversLoaded {
+		return nil
+	}
+	drivers, err := doltdb.GetMergeDriverAssignments(ctx, rm.left)
+	if err != nil {
+		return err
+	}
+	rm.mergeDrivers, rm.mergeDriversLoaded = drivers, true
+	return nil
+}
+
 func (rm *RootMerger) makeTableMerger(ctx context.Context, tblName string) (*TableMerger, error) {
+	// The merge drivers configured on the branch being merged into apply to every table in the merge
+	if err := rm.loadMergeDrivers(ctx); err != nil {
+		return nil, err
+	}
+
 	tm := TableMerger{
-		name:        tblName,
-		rightSrc:    rm.rightSrc,
-		ancestorSrc: rm.ancSrc,
-		vrw:         rm.vrw,
-		ns:          rm.ns,
+		name:         tblName,
+		rightSrc:     rm.rightSrc,
+		ancestorSrc:  rm.ancSrc,
+		mergeDrivers: rm.mergeDrivers,
+		vrw:          rm.vrw,
+		ns:           rm.ns,
 	}
 
 	var ok bool
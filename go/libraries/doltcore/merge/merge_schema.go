@@ -355,7 +355,11 @@ func mergeColumns(format *storetypes.NomsBinFormat, ourCC, theirCC, ancCC *schem
 				oursChanged := !anc.Equals(*ours)
 				theirsChanged := !anc.Equals(*theirs)
 				if oursChanged && theirsChanged {
-					// This is a schema change conflict and has already been handled by checkSchemaConflicts
+					// If both sides made the same change, use it. Otherwise, this is a schema change conflict and has
+					// already been handled by checkSchemaConflicts
+					if ours.Equals(*theirs) {
+						mergedColumns = append(mergedColumns, *ours)
+					}
 				} else if theirsChanged {
 					// In this case, only theirsChanged, so we need to check if moving from ours->theirs
 					// is valid, otherwise it's a conflict
@@ -675,6 +679,11 @@ func indexesInCommon(mergedCC *schema.ColCollection, ours, theirs, anc schema.In
 		ancIdx, ok := anc.GetIndexByTags(idxTags...)
 
 		if !ok {
+			if !strings.EqualFold(ourIdx.Name(), theirIdx.Name()) {
+				// differently named indexes on the same columns added on each branch, which can coexist. Both are
+				// added to the merged schema as new indexes.
+				return false, nil
+			}
 			// index added on our branch and their branch with different defs, conflict
 			conflicts = append(conflicts, IdxConflict{
 				Kind:   TagCollision,
@@ -31,6 +31,8 @@ type MergeStats struct {
 	DataConflicts        int
 	SchemaConflicts      int
 	ConstraintViolations int
+	// ForeignKeyViolations describes the table's foreign key violations when the merge verified foreign keys.
+	ForeignKeyViolations []ForeignKeyViolation
 }
 
 func (ms *MergeStats) HasArtifacts() bool {
@@ -183,17 +183,36 @@ var testCases = []testCase{
 		true,
 		false,
 	},
-	// TODO (dhruv): need to fix this test case for new storage format
-	//{
-	//	"add rows but one holds a new column",
-	//	build(1, 1),
-	//	build(1, 1, 1),
-	//	nil,
-	//	2, 3, 2,
-	//	build(1, 1, 1),
-	//	true,
-	//	false,
-	//},
+	{
+		"add rows but one holds a new column",
+		build(1, 1),
+		build(1, 1, 1),
+		nil,
+		2, 3, 2,
+		build(1, 1, 1),
+		true,
+		false,
+	},
+	{
+		"modify different columns of a wide row",
+		build(2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 3),
+		build(1, 1, 1, 1, 1, 1, 1, 1, 1, 4, 1, 1, 1, 1, 1, 1, 1, 1, 5, 1),
+		build(1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1),
+		20, 20, 20,
+		build(2, 1, 1, 1, 1, 1, 1, 1, 1, 4, 1, 1, 1, 1, 1, 1, 1, 1, 5, 3),
+		true,
+		false,
+	},
+	{
+		"modify the same cell of a wide row differently",
+		build(1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 3),
+		build(1, 1, 1, 1, 1, 1, 1, 1, 1, 4, 1, 1, 1, 1, 1, 1, 1, 1, 5, 1),
+		build(1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1),
+		20, 20, 20,
+		nil,
+		false,
+		true,
+	},
 	{
 		"Delete a row in one, set all null in the other",
 		build(0, 0, 0), // build translates zeros into NULL values
@@ -263,6 +263,29 @@ var mergeSchemaTests = []mergeSchemaTest{
 			schema.NewIndex("c3_idx", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
 		),
 	},
+	{
+		name: "add differently named indexes on the same column on both branches, merge",
+		setup: []testCommand{
+			{commands.SqlCmd{}, []string{"-q", "create index c3_idx on test(c3);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch main"}},
+			{commands.CheckoutCmd{}, []string{"other"}},
+			{commands.SqlCmd{}, []string{"-q", "create index c3_index on test(c3);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch other"}},
+			{commands.CheckoutCmd{}, []string{env.DefaultInitBranch}},
+		},
+		sch: schemaFromColsAndIdxs(
+			colCollection(
+				newColTypeInfo("pk", uint64(3228), typeinfo.Int32Type, true, schema.NotNullConstraint{}),
+				newColTypeInfo("c1", uint64(8201), typeinfo.Int32Type, false, schema.NotNullConstraint{}),
+				newColTypeInfo("c2", uint64(8539), typeinfo.Int32Type, false),
+				newColTypeInfo("c3", uint64(4696), typeinfo.Int32Type, false)),
+			schema.NewIndex("c1_idx", []uint64{8201}, []uint64{8201, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
+			schema.NewIndex("c3_idx", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
+			schema.NewIndex("c3_index", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
+		),
+	},
 }
 
 var mergeSchemaConflictTests = []mergeSchemaConflictTest{
@@ -356,29 +379,6 @@ var mergeSchemaConflictTests = []mergeSchemaConflictTest{
 			},
 		},
 	},
-	{
-		name: "index definition collision",
-		setup: []testCommand{
-			{commands.SqlCmd{}, []string{"-q", "create index c3_idx on test(c3);"}},
-			{commands.AddCmd{}, []string{"."}},
-			{commands.CommitCmd{}, []string{"-m", "modified branch main"}},
-			{commands.CheckoutCmd{}, []string{"other"}},
-			{commands.SqlCmd{}, []string{"-q", "create index c3_index on test(c3);"}},
-			{commands.AddCmd{}, []string{"."}},
-			{commands.CommitCmd{}, []string{"-m", "modified branch other"}},
-			{commands.CheckoutCmd{}, []string{env.DefaultInitBranch}},
-		},
-		expConflict: merge.SchemaConflict{
-			TableName: "test",
-			IdxConflicts: []merge.IdxConflict{
-				{
-					Kind:   merge.TagCollision,
-					Ours:   schema.NewIndex("c3_idx", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
-					Theirs: schema.NewIndex("c3_index", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
-				},
-			},
-		},
-	},
 	{
 		name: "check definition collision",
 		setup: []testCommand{
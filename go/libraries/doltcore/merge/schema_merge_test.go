@@ -168,6 +168,13 @@ var columnAddDropTests = []schemaMergeTest{
 		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY)       "), row(1), row(11)),
 		merged:   tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)"), row(1, 2), row(11, nil)),
 	},
+	{
+		name:     "right side column add with a default, left side modifies another column of the same row",
+		ancestor: tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)                 "), row(1, 1)),
+		left:     tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)                 "), row(1, 2)),
+		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int, b int DEFAULT 7)"), row(1, 1, 3)),
+		merged:   tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int, b int DEFAULT 7)"), row(1, 2, 3)),
+	},
 	{
 		name:                "left side column drop, right side insert row",
 		ancestor:            tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)"), row(1, 2)),
@@ -259,6 +266,13 @@ var columnDefaultTests = []schemaMergeTest{
 		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)           ")),
 		merged:   tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)           ")),
 	},
+	{
+		name:     "convergent add, independent column add",
+		ancestor: tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int)                  "), row(1, 2)),
+		left:     tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int DEFAULT 42, b int)"), row(1, 2, 3)),
+		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int DEFAULT 42)       "), row(1, 2)),
+		merged:   tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a int DEFAULT 42, b int)"), row(1, 2, 3)),
+	},
 	// one side changes columns, the other inserts rows
 	{
 		// TODO: this test silently does the wrong thing without erroring
@@ -460,21 +474,6 @@ var simpleConflictTests = []schemaMergeTest{
 		right:      tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, UNIQUE INDEX idx (a))")),
 		conflict:   true,
 	},
-	{
-		// TODO: This test case does NOT generate a conflict; the merge gets short circuited, because the table's
-		//       right/left/anc hashes are all the same. This is an issue with the test framework, not with Dolt.
-		//       The code we use in these tests to create a schema (sqlutil.ParseCreateTableStatement) silently
-		//       drops index and check constraint definitions.
-		skipNewFmt: true,
-		skipOldFmt: true,
-		// TODO: multiple indexes can exist for the same column set, so this shouldn't actually be a conflict;
-		//       Dolt does report this as a schema conflict today, but we could merge the two indexes together.
-		name:     "conflicting index adds: same column different names",
-		ancestor: tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float)                 ")),
-		left:     tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX a_idx (a))")),
-		right:    tbl(sch("CREATE TABLE t (id int PRIMARY KEY, a char(20), b float, INDEX key_a (a))")),
-		conflict: true,
-	},
 	{
 		// TODO: This test case does NOT generate a conflict; the merge gets short circuited, because the table's
 		//       right/left/anc hashes are all the same. This is an issue with the test framework, not with Dolt.
@@ -0,0 +1,159 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+)
+
+// ForeignKeyViolation describes a row of a merged table whose foreign key references a parent row that doesn't exist.
+type ForeignKeyViolation struct {
+	// Table is the child table, and Key the primary key of the violating row. Key is nil for keyless tables.
+	Table string
+	Key   sql.Row
+	// ForeignKey is the name of the violated foreign key.
+	ForeignKey string
+	// MissingKey holds the values of the row's foreign key columns, |Columns|, that no row of |ReferencedTable| has
+	// in |ReferencedColumns|.
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	MissingKey        sql.Row
+}
+
+// String returns a one line description of the violation, e.g.
+// "foreign key fk1: child row (1) references parent(pk) = (10), which does not exist".
+func (v ForeignKeyViolation) String() string {
+	row := v.Table + " row"
+	if v.Key != nil {
+		row = fmt.Sprintf("%s (%s)", row, formatKeyValues(v.Key))
+	}
+	return fmt.Sprintf("foreign key %s: %s references %s(%s) = (%s), which does not exist",
+		v.ForeignKey, row, v.ReferencedTable, strings.Join(v.ReferencedColumns, ", "), formatKeyValues(v.MissingKey))
+}
+
+func formatKeyValues(row sql.Row) string {
+	vals := make([]string, len(row))
+	for i, v := range row {
+		if v == nil {
+			vals[i] = "NULL"
+		} else {
+			vals[i] = fmt.Sprintf("%v", v)
+		}
+	}
+	return strings.Join(vals, ", ")
+}
+
+// describeForeignKeyViolations returns the foreign key violations recorded by the merge of |theirRootIsh| into
+// |root|, the merged root, in the tables named by |tables|.
+func describeForeignKeyViolations(ctx context.Context, root *doltdb.RootValue, tables *set.StrSet, theirRootIsh hash.Hash) ([]ForeignKeyViolation, error) {
+	if tables == nil {
+		return nil, nil
+	}
+
+	var violations []ForeignKeyViolation
+	for _, tblName := range tables.AsSortedSlice() {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		kd, vd := sch.GetMapDescriptors()
+		keyless := schema.IsKeyless(sch)
+		ns := tbl.NodeStore()
+
+		arts, err := tbl.GetArtifacts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		iter, err := durable.ProllyMapFromArtifactIndex(arts).IterAllCVs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			art, err := iter.Next(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			if art.ArtType != prolly.ArtifactTypeForeignKeyViol || art.SourceRootish != theirRootIsh {
+				continue
+			}
+
+			var meta prolly.ConstraintViolationMeta
+			if err = json.Unmarshal(art.Metadata, &meta); err != nil {
+				return nil, err
+			}
+			var fkMeta FkCVMeta
+			if err = json.Unmarshal(meta.VInfo, &fkMeta); err != nil {
+				return nil, err
+			}
+
+			v := ForeignKeyViolation{
+				Table:             tblName,
+				ForeignKey:        fkMeta.ForeignKey,
+				Columns:           fkMeta.Columns,
+				ReferencedTable:   fkMeta.ReferencedTable,
+				ReferencedColumns: fkMeta.ReferencedColumns,
+				MissingKey:        make(sql.Row, len(fkMeta.Columns)),
+			}
+			if !keyless {
+				v.Key = make(sql.Row, kd.Count())
+				for i := range v.Key {
+					if v.Key[i], err = index.GetField(ctx, kd, i, art.SourceKey, ns); err != nil {
+						return nil, err
+					}
+				}
+			}
+			for i, name := range fkMeta.Columns {
+				if j := sch.GetPKCols().IndexOf(name); j >= 0 && !keyless {
+					v.MissingKey[i], err = index.GetField(ctx, kd, j, art.SourceKey, ns)
+				} else if j = sch.GetNonPKCols().IndexOf(name); j >= 0 {
+					if keyless {
+						// the first field of a keyless row is its cardinality
+						j++
+					}
+					v.MissingKey[i], err = index.GetField(ctx, vd, j, meta.Value, ns)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
@@ -52,11 +52,21 @@ const (
 	// JsonFile is the format of a data location that is a json file
 	JsonFile DataFormat = ".json"
 
+	// JsonlFile is the format of a data location that is a JSON Lines file, with a JSON object on each line
+	JsonlFile DataFormat = ".jsonl"
+
+	// GeoJsonFile is the format of a data location that is a GeoJSON FeatureCollection, with a feature for each row
+	GeoJsonFile DataFormat = ".geojson"
+
 	// SqlFile is the format of a data location that is a .sql file
 	SqlFile DataFormat = ".sql"
 
 	// ParquetFile is the format of a data location that is a .paquet file
 	ParquetFile DataFormat = ".parquet"
+
+	// FixedWidthFile is the format of a data location that is a file of fixed-width records, whose fields are at the
+	// positions given by a layout file. It is never inferred from a file's extension.
+	FixedWidthFile DataFormat = "fixed-width"
 )
 
 // ReadableStr returns a human readable string for a DataFormat
@@ -72,10 +82,16 @@ func (df DataFormat) ReadableStr() string {
 		return "xlsx file"
 	case JsonFile:
 		return "json file"
+	case JsonlFile:
+		return "jsonl file"
+	case GeoJsonFile:
+		return "geojson file"
 	case SqlFile:
 		return "sql file"
 	case ParquetFile:
 		return "parquet file"
+	case FixedWidthFile:
+		return "fixed-width file"
 	default:
 		return "invalid"
 	}
@@ -116,6 +132,10 @@ func NewDataLocation(path, fileFmtStr string) DataLocation {
 			dataFmt = XlsxFile
 		case string(JsonFile):
 			dataFmt = JsonFile
+		case string(JsonlFile), ".ndjson":
+			dataFmt = JsonlFile
+		case string(GeoJsonFile):
+			dataFmt = GeoJsonFile
 		case string(SqlFile):
 			dataFmt = SqlFile
 		case string(ParquetFile):
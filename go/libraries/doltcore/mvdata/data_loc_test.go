@@ -84,6 +84,7 @@ func TestBasics(t *testing.T) {
 		{NewDataLocation("file.csv", ""), CsvFile.ReadableStr() + ":file.csv", true},
 		{NewDataLocation("file.psv", ""), PsvFile.ReadableStr() + ":file.psv", true},
 		{NewDataLocation("file.json", ""), JsonFile.ReadableStr() + ":file.json", true},
+		{NewDataLocation("file.geojson", ""), GeoJsonFile.ReadableStr() + ":file.geojson", true},
 		//{NewDataLocation("file.nbf", ""), NbfFile, "file.nbf", true},
 	}
 
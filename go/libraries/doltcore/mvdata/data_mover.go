@@ -25,12 +25,33 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fixedwidth"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 )
 
 type CsvOptions struct {
 	Delim string
+	// Quote, Escape and Encoding are described by csv.CSVFileInfo
+	Quote    string
+	Escape   string
+	Encoding string
+}
+
+// csvInfo returns the csv.CSVFileInfo for a file with the CsvOptions |opts|, using |delim| if |opts| doesn't have a
+// delimiter.
+func csvInfo(opts interface{}, delim string) *csv.CSVFileInfo {
+	info := csv.NewCSVInfo().SetDelim(delim)
+	csvOpts, _ := opts.(CsvOptions)
+	if len(csvOpts.Delim) != 0 {
+		info.SetDelim(csvOpts.Delim)
+	}
+	if len(csvOpts.Quote) != 0 {
+		info.SetQuote(csvOpts.Quote)
+	}
+	return info.SetEscape(csvOpts.Escape).SetEncoding(csvOpts.Encoding)
 }
 
 type XlsxOptions struct {
@@ -40,6 +61,14 @@ type XlsxOptions struct {
 type JSONOptions struct {
 	TableName string
 	SchFile   string
+	// Flatten maps the nested fields of JSON Lines files to columns
+	Flatten *json.FlattenSpec
+}
+
+type FixedWidthOptions struct {
+	Layout *fixedwidth.Layout
+	// Encoding is the character encoding of the file, which is UTF-8 if it's empty
+	Encoding string
 }
 
 type ParquetOptions struct {
@@ -63,6 +92,32 @@ type DataMoverOptions interface {
 	DestName() string
 }
 
+// FlattenedWriteOptions is implemented by the DataMoverOptions of moves to JSON Lines files that write columns to
+// nested fields
+type FlattenedWriteOptions interface {
+	FlattenSpec() *json.FlattenSpec
+}
+
+func flattenSpec(mvOpts DataMoverOptions) *json.FlattenSpec {
+	if fo, ok := mvOpts.(FlattenedWriteOptions); ok {
+		return fo.FlattenSpec()
+	}
+	return nil
+}
+
+// SpatialWriteOptions is implemented by the DataMoverOptions of moves to csv and psv files that choose the format
+// spatial values are written in
+type SpatialWriteOptions interface {
+	SpatialFormat() sqlutil.SpatialFormat
+}
+
+func spatialFormat(mvOpts DataMoverOptions) sqlutil.SpatialFormat {
+	if so, ok := mvOpts.(SpatialWriteOptions); ok {
+		return so.SpatialFormat()
+	}
+	return sqlutil.SpatialHex
+}
+
 type DataMoverCreationErrType string
 
 const (
@@ -29,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fixedwidth"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/sqlexport"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/xlsx"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -45,10 +46,16 @@ func DFFromString(dfStr string) DataFormat {
 		return XlsxFile
 	case "json", ".json":
 		return JsonFile
+	case "jsonl", ".jsonl", "ndjson", ".ndjson":
+		return JsonlFile
+	case "geojson", ".geojson":
+		return GeoJsonFile
 	case "sql", ".sql":
 		return SqlFile
 	case "parquet", ".parquet":
 		return ParquetFile
+	case "fixed-width", "fixedwidth":
+		return FixedWidthFile
 	default:
 		return InvalidDataFormat
 	}
@@ -86,22 +93,12 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 
 	switch dl.Format {
 	case CsvFile:
-		delim := ","
-
-		if opts != nil {
-			csvOpts, _ := opts.(CsvOptions)
-
-			if len(csvOpts.Delim) != 0 {
-				delim = csvOpts.Delim
-			}
-		}
-
-		rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csv.NewCSVInfo().SetDelim(delim))
+		rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csvInfo(opts, ","))
 
 		return rd, false, err
 
 	case PsvFile:
-		rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csv.NewCSVInfo().SetDelim("|"))
+		rd, err := csv.OpenCSVReader(root.VRW().Format(), dl.Path, fs, csvInfo(opts, "|"))
 		return rd, false, err
 
 	case XlsxFile:
@@ -110,35 +107,33 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 		return rd, false, err
 
 	case JsonFile:
-		var sch schema.Schema
+		sch, err := jsonImportSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		rd, err := json.OpenJSONReader(root.VRW(), dl.Path, fs, sch)
+		return rd, false, err
+
+	case JsonlFile:
+		sch, err := jsonImportSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
+		}
 		jsonOpts, _ := opts.(JSONOptions)
-		if jsonOpts.SchFile != "" {
-			tn, s, err := SchAndTableNameFromFile(ctx, jsonOpts.SchFile, fs, root)
-			if err != nil {
-				return nil, false, err
-			}
-			if tn != jsonOpts.TableName {
-				return nil, false, fmt.Errorf("table name '%s' from schema file %s does not match table arg '%s'", tn, jsonOpts.SchFile, jsonOpts.TableName)
-			}
-			sch = s
-		} else {
-			if opts == nil {
-				return nil, false, errors.New("Unable to determine table name on JSON import")
-			}
-			tbl, exists, err := root.GetTable(context.TODO(), jsonOpts.TableName)
-			if !exists {
-				return nil, false, errors.New(fmt.Sprintf("The following table could not be found:\n%v", jsonOpts.TableName))
-			}
-			if err != nil {
-				return nil, false, errors.New(fmt.Sprintf("An error occurred attempting to read the table:\n%v", err.Error()))
-			}
-			sch, err = tbl.GetSchema(context.TODO())
-			if err != nil {
-				return nil, false, errors.New(fmt.Sprintf("An error occurred attempting to read the table schema:\n%v", err.Error()))
-			}
+		rd, err := json.OpenJSONLReader(dl.Path, fs, sch, jsonOpts.Flatten)
+		return rd, false, err
+
+	case GeoJsonFile:
+		sch, err := jsonImportSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
 		}
+		rd, err := json.OpenGeoJSONReader(dl.Path, fs, sch)
+		return rd, false, err
 
-		rd, err := json.OpenJSONReader(root.VRW(), dl.Path, fs, sch)
+	case FixedWidthFile:
+		fwOpts, _ := opts.(FixedWidthOptions)
+		rd, err := fixedwidth.OpenReader(root.VRW().Format(), dl.Path, fs, fwOpts.Layout, fwOpts.Encoding)
 		return rd, false, err
 
 	case ParquetFile:
@@ -181,13 +176,19 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 func (dl FileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMoverOptions, root *doltdb.RootValue, outSch schema.Schema, opts editor.Options, wr io.WriteCloser) (table.SqlRowWriter, error) {
 	switch dl.Format {
 	case CsvFile:
-		return csv.NewCSVWriter(wr, outSch, csv.NewCSVInfo())
+		return csv.NewCSVWriter(wr, outSch, csv.NewCSVInfo().SetSpatialFormat(spatialFormat(mvOpts)))
 	case PsvFile:
-		return csv.NewCSVWriter(wr, outSch, csv.NewCSVInfo().SetDelim("|"))
+		return csv.NewCSVWriter(wr, outSch, csv.NewCSVInfo().SetDelim("|").SetSpatialFormat(spatialFormat(mvOpts)))
 	case XlsxFile:
 		panic("writing to xlsx files is not supported yet")
+	case FixedWidthFile:
+		return nil, errors.New("writing to fixed-width files is not supported")
 	case JsonFile:
 		return json.NewJSONWriter(wr, outSch)
+	case JsonlFile:
+		return json.NewJSONLWriter(wr, outSch, flattenSpec(mvOpts))
+	case GeoJsonFile:
+		return json.NewGeoJSONWriter(wr, outSch)
 	case SqlFile:
 		if mvOpts.IsBatched() {
 			return sqlexport.OpenBatchedSQLExportWriter(ctx, wr, root, mvOpts.SrcName(), mvOpts.IsAutocommitOff(), outSch, opts)
@@ -200,3 +201,35 @@ func (dl FileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMov
 
 	panic("Invalid Data Format." + string(dl.Format))
 }
+
+// jsonImportSchema returns the schema of the rows of a JSON or JSON Lines file, which is read from the schema file
+// given in |opts| or else is the schema of the table being imported to.
+func jsonImportSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS, opts interface{}) (schema.Schema, error) {
+	jsonOpts, _ := opts.(JSONOptions)
+	if jsonOpts.SchFile != "" {
+		tn, sch, err := SchAndTableNameFromFile(ctx, jsonOpts.SchFile, fs, root)
+		if err != nil {
+			return nil, err
+		}
+		if tn != jsonOpts.TableName {
+			return nil, fmt.Errorf("table name '%s' from schema file %s does not match table arg '%s'", tn, jsonOpts.SchFile, jsonOpts.TableName)
+		}
+		return sch, nil
+	}
+
+	if opts == nil {
+		return nil, errors.New("Unable to determine table name on JSON import")
+	}
+	tbl, exists, err := root.GetTable(context.TODO(), jsonOpts.TableName)
+	if !exists {
+		return nil, errors.New(fmt.Sprintf("The following table could not be found:\n%v", jsonOpts.TableName))
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("An error occurred attempting to read the table:\n%v", err.Error()))
+	}
+	sch, err := tbl.GetSchema(context.TODO())
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("An error occurred attempting to read the table schema:\n%v", err.Error()))
+	}
+	return sch, nil
+}
@@ -0,0 +1,296 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// mappedCol describes how a single output column of a MappedReader is produced
+type mappedCol struct {
+	// srcIdx and srcTag identify the input column, when the output column comes from one
+	srcIdx  int
+	srcTag  uint64
+	fromSrc bool
+
+	transform func(string) string
+	castType  sql.Type
+
+	constant string
+	template string
+	isTmpl   bool
+}
+
+// MappedReader is a table.SqlRowReader that applies a rowconv.MappingSpec to the rows of another reader. Its schema
+// has the output column names of the spec, so the rows it returns can be written without any further name mapping.
+type MappedReader struct {
+	rd      table.SqlRowReader
+	sch     schema.Schema
+	cols    []mappedCol
+	srcIdxs map[string]int
+	srcTags map[string]uint64
+}
+
+var _ table.SqlRowReader = (*MappedReader)(nil)
+
+// NewMappedReader validates |spec| against the schema of |rd| and returns a reader that applies it
+func NewMappedReader(ctx context.Context, rd table.SqlRowReader, spec *rowconv.MappingSpec) (*MappedReader, error) {
+	srcSch := rd.GetSchema()
+	if err := spec.Validate(ctx, srcSch); err != nil {
+		return nil, err
+	}
+
+	var outCols []schema.Column
+	var mapped []mappedCol
+	srcIdxs := make(map[string]int)
+	srcTags := make(map[string]uint64)
+	var maxTag uint64
+
+	srcCols := srcSch.GetAllCols()
+	for i := 0; i < srcCols.Size(); i++ {
+		col := srcCols.GetByIndex(i)
+		srcIdxs[col.Name] = i
+		srcTags[col.Name] = col.Tag
+		if col.Tag > maxTag {
+			maxTag = col.Tag
+		}
+
+		name, ok := spec.OutputName(col.Name)
+		if !ok {
+			continue
+		}
+
+		mc := mappedCol{srcIdx: i, srcTag: col.Tag, fromSrc: true}
+		if t := spec.Columns[col.Name].Transform; t != "" {
+			mc.transform = rowconv.Transforms[t]
+		}
+		castType, _, err := spec.ColumnType(ctx, col.Name)
+		if err != nil {
+			return nil, err
+		}
+		mc.castType = castType
+
+		col.Name = name
+		outCols = append(outCols, col)
+		mapped = append(mapped, mc)
+	}
+
+	extra := make([]string, 0, len(spec.Defaults)+len(spec.Expressions))
+	for name := range spec.Defaults {
+		extra = append(extra, name)
+	}
+	for name := range spec.Expressions {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+
+	for _, name := range extra {
+		maxTag++
+		col, err := schema.NewColumnWithTypeInfo(name, maxTag, typeinfo.StringDefaultType, false, "", false, "")
+		if err != nil {
+			return nil, err
+		}
+		outCols = append(outCols, col)
+
+		if tmpl, ok := spec.Expressions[name]; ok {
+			mapped = append(mapped, mappedCol{template: tmpl, isTmpl: true})
+		} else {
+			mapped = append(mapped, mappedCol{constant: spec.Defaults[name]})
+		}
+	}
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(outCols...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MappedReader{
+		rd:      rd,
+		sch:     sch,
+		cols:    mapped,
+		srcIdxs: srcIdxs,
+		srcTags: srcTags,
+	}, nil
+}
+
+// GetSchema returns the schema of the mapped rows
+func (mr *MappedReader) GetSchema() schema.Schema {
+	return mr.sch
+}
+
+// ReadRow reads a row from the underlying reader and maps it. Casts are not applied to rows read this way, as they
+// are only used for inferring schemas, where the cast types are applied to the inferred schema instead.
+func (mr *MappedReader) ReadRow(ctx context.Context) (row.Row, error) {
+	r, err := mr.rd.ReadRow(ctx)
+	if err != nil {
+		return r, err
+	}
+
+	valStr := func(v types.Value) string {
+		if v == nil || types.IsNull(v) {
+			return ""
+		}
+		if s, ok := v.(types.String); ok {
+			return string(s)
+		}
+		return v.HumanReadableString()
+	}
+
+	outCols := mr.sch.GetAllCols()
+	taggedVals := make(row.TaggedValues)
+	for i, mc := range mr.cols {
+		tag := outCols.GetByIndex(i).Tag
+		switch {
+		case mc.fromSrc:
+			v, _ := r.GetColVal(mc.srcTag)
+			if s, ok := v.(types.String); ok && mc.transform != nil {
+				v = types.String(mc.transform(string(s)))
+			}
+			if v != nil {
+				taggedVals[tag] = v
+			}
+		case mc.isTmpl:
+			taggedVals[tag] = types.String(rowconv.ExpandTemplate(mc.template, func(col string) string {
+				v, _ := r.GetColVal(mr.srcTags[col])
+				return valStr(v)
+			}))
+		default:
+			taggedVals[tag] = types.String(mc.constant)
+		}
+	}
+
+	return row.New(r.Format(), mr.sch, taggedVals)
+}
+
+// ReadSqlRow reads a row from the underlying reader and maps it. Values that can't be cast to the type given in the
+// mapping spec are returned as bad rows.
+func (mr *MappedReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	r, err := mr.rd.ReadSqlRow(ctx)
+	if err != nil {
+		return r, err
+	}
+
+	valStr := func(v interface{}) string {
+		switch v := v.(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+
+	out := make(sql.Row, len(mr.cols))
+	for i, mc := range mr.cols {
+		switch {
+		case mc.fromSrc:
+			v := r[mc.srcIdx]
+			if s, ok := v.(string); ok && mc.transform != nil {
+				v = mc.transform(s)
+			}
+			if mc.castType != nil && v != nil {
+				// empty strings are imported as NULL for non-string types
+				if s, ok := v.(string); ok && s == "" {
+					v = nil
+				} else if v, _, err = mc.castType.Convert(v); err != nil {
+					col := mr.sch.GetAllCols().GetByIndex(i)
+					return r, table.NewBadRow(nil, fmt.Sprintf("could not cast value '%v' of column '%s' to %s: %s", r[mc.srcIdx], col.Name, mc.castType.String(), err.Error()))
+				}
+			}
+			out[i] = v
+		case mc.isTmpl:
+			out[i] = rowconv.ExpandTemplate(mc.template, func(col string) string {
+				return valStr(r[mr.srcIdxs[col]])
+			})
+		default:
+			out[i] = mc.constant
+		}
+	}
+
+	return out, nil
+}
+
+// Close closes the underlying reader
+func (mr *MappedReader) Close(ctx context.Context) error {
+	return mr.rd.Close(ctx)
+}
+
+// ApplyMappingTypes returns |sch| with the columns that |spec| gives a type for changed to that type
+func ApplyMappingTypes(ctx context.Context, sch schema.Schema, spec *rowconv.MappingSpec) (schema.Schema, error) {
+	colTypes := make(map[string]sql.Type)
+	for src := range spec.Columns {
+		t, ok, err := spec.ColumnType(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		if name, mapped := spec.OutputName(src); ok && mapped {
+			colTypes[name] = t
+		}
+	}
+	return ApplyColumnTypes(sch, colTypes)
+}
+
+// ApplyColumnTypes returns |sch| with the columns named in |colTypes| changed to the type given for them. It is an
+// error if |colTypes| names a column that is not in |sch|.
+func ApplyColumnTypes(sch schema.Schema, colTypes map[string]sql.Type) (schema.Schema, error) {
+	if len(colTypes) == 0 {
+		return sch, nil
+	}
+	for name := range colTypes {
+		if _, ok := sch.GetAllCols().GetByName(name); !ok {
+			return nil, fmt.Errorf("column '%s' is not in the imported data", name)
+		}
+	}
+
+	var err error
+	cols := schema.MapColCollection(sch.GetAllCols(), func(col schema.Column) schema.Column {
+		t, ok := colTypes[col.Name]
+		if !ok || err != nil {
+			return col
+		}
+		var ti typeinfo.TypeInfo
+		ti, err = typeinfo.FromSqlType(t)
+		if err != nil {
+			return col
+		}
+		col.TypeInfo = ti
+		col.Kind = ti.NomsKind()
+		return col
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newSch, err := schema.SchemaFromCols(cols)
+	if err != nil {
+		return nil, err
+	}
+	if err = newSch.SetPkOrdinals(sch.GetPkOrdinals()); err != nil {
+		return nil, err
+	}
+	return newSch, nil
+}
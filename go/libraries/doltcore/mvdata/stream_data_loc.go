@@ -23,7 +23,9 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/fixedwidth"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 )
@@ -49,22 +51,34 @@ func (dl StreamDataLocation) Exists(ctx context.Context, root *doltdb.RootValue,
 func (dl StreamDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS, opts interface{}) (rdCl table.SqlRowReader, sorted bool, err error) {
 	switch dl.Format {
 	case CsvFile:
-		delim := ","
+		rd, err := csv.NewCSVReader(root.VRW().Format(), io.NopCloser(dl.Reader), csvInfo(opts, ","))
 
-		if opts != nil {
-			csvOpts, _ := opts.(CsvOptions)
+		return rd, false, err
 
-			if len(csvOpts.Delim) != 0 {
-				delim = csvOpts.Delim
-			}
-		}
+	case PsvFile:
+		rd, err := csv.NewCSVReader(root.VRW().Format(), io.NopCloser(dl.Reader), csvInfo(opts, "|"))
+		return rd, false, err
 
-		rd, err := csv.NewCSVReader(root.VRW().Format(), io.NopCloser(dl.Reader), csv.NewCSVInfo().SetDelim(delim))
+	case JsonlFile:
+		sch, err := jsonImportSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		jsonOpts, _ := opts.(JSONOptions)
+		rd, err := json.NewJSONLReader(io.NopCloser(dl.Reader), sch, jsonOpts.Flatten)
+		return rd, false, err
 
+	case GeoJsonFile:
+		sch, err := jsonImportSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
+		}
+		rd, err := json.NewGeoJSONReader(io.NopCloser(dl.Reader), sch)
 		return rd, false, err
 
-	case PsvFile:
-		rd, err := csv.NewCSVReader(root.VRW().Format(), io.NopCloser(dl.Reader), csv.NewCSVInfo().SetDelim("|"))
+	case FixedWidthFile:
+		fwOpts, _ := opts.(FixedWidthOptions)
+		rd, err := fixedwidth.NewReader(root.VRW().Format(), io.NopCloser(dl.Reader), fwOpts.Layout, fwOpts.Encoding)
 		return rd, false, err
 	}
 
@@ -76,10 +90,16 @@ func (dl StreamDataLocation) NewReader(ctx context.Context, root *doltdb.RootVal
 func (dl StreamDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMoverOptions, root *doltdb.RootValue, outSch schema.Schema, opts editor.Options, wr io.WriteCloser) (table.SqlRowWriter, error) {
 	switch dl.Format {
 	case CsvFile:
-		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo())
+		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo().SetSpatialFormat(spatialFormat(mvOpts)))
 
 	case PsvFile:
-		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo().SetDelim("|"))
+		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo().SetDelim("|").SetSpatialFormat(spatialFormat(mvOpts)))
+
+	case JsonlFile:
+		return json.NewJSONLWriter(iohelp.NopWrCloser(dl.Writer), outSch, flattenSpec(mvOpts))
+
+	case GeoJsonFile:
+		return json.NewGeoJSONWriter(iohelp.NopWrCloser(dl.Writer), outSch)
 	}
 
 	return nil, errors.New(string(dl.Format) + "is an unsupported format to write to stdout")
@@ -0,0 +1,258 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlshdump
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Dialect is the format of the rows in a table's data files, as given to LOAD DATA
+type Dialect struct {
+	FieldsTerminatedBy string
+	// FieldsEnclosedBy is the character that fields may be quoted with, or empty if they aren't quoted
+	FieldsEnclosedBy string
+	// FieldsEscapedBy is the character that escapes special characters, or empty if nothing is escaped
+	FieldsEscapedBy   string
+	LinesTerminatedBy string
+}
+
+// decoder decodes a value of a column that was encoded to dump it, like binary data written as base64
+type decoder func(string) (string, error)
+
+func newDecoder(fn string) (decoder, error) {
+	// the function may be given as a name, or as an expression applied to a user variable, e.g. FROM_BASE64(@c)
+	name := strings.ToUpper(fn)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	switch strings.TrimSpace(name) {
+	case "UNHEX":
+		return func(s string) (string, error) {
+			b, err := hex.DecodeString(s)
+			return string(b), err
+		}, nil
+	case "FROM_BASE64":
+		return func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			return string(b), err
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported decode function '%s'", fn)
+	}
+}
+
+// ChunkReader reads the rows of a data file of a table
+type ChunkReader struct {
+	path    string
+	f       *os.File
+	rc      io.ReadCloser
+	rd      *bufio.Reader
+	tbl     *Table
+	line    int
+	escape  byte
+	enclose byte
+}
+
+// OpenChunk opens the data file at |path|, which must be one of |tbl|'s chunks
+func (tbl *Table) OpenChunk(path string) (*ChunkReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &ChunkReader{path: path, f: f, tbl: tbl, line: 1}
+	switch tbl.compression {
+	case "zstd":
+		dec, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		cr.rc = dec.IOReadCloser()
+		cr.rd = bufio.NewReaderSize(cr.rc, 256*1024)
+	case "gzip":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		cr.rc = gz
+		cr.rd = bufio.NewReaderSize(gz, 256*1024)
+	case "", "none":
+		cr.rd = bufio.NewReaderSize(f, 256*1024)
+	default:
+		f.Close()
+		return nil, fmt.Errorf("unsupported compression '%s'", tbl.compression)
+	}
+
+	if len(tbl.dialect.FieldsEscapedBy) > 0 {
+		cr.escape = tbl.dialect.FieldsEscapedBy[0]
+	}
+	if len(tbl.dialect.FieldsEnclosedBy) > 0 {
+		cr.enclose = tbl.dialect.FieldsEnclosedBy[0]
+	}
+	return cr, nil
+}
+
+// Close closes the data file
+func (cr *ChunkReader) Close() error {
+	if cr.rc != nil {
+		_ = cr.rc.Close()
+	}
+	return cr.f.Close()
+}
+
+// ReadRow returns the next row, with a value for each of the table's columns, in order. NULL values are nil, and
+// others are strings. It returns io.EOF after the last row.
+func (cr *ChunkReader) ReadRow() ([]interface{}, error) {
+	fieldTerm := []byte(cr.tbl.dialect.FieldsTerminatedBy)
+	lineTerm := []byte(cr.tbl.dialect.LinesTerminatedBy)
+
+	var row []interface{}
+	var buf []byte
+	// protected is the length of the start of |buf| that can't be part of a terminator, because it was escaped or
+	// enclosed
+	protected := 0
+	isNull, enclosed, inQuotes, started := false, false, false, false
+
+	endField := func() {
+		switch {
+		case isNull:
+			row = append(row, nil)
+		case !enclosed && cr.escape == 0 && string(buf) == "NULL":
+			row = append(row, nil)
+		default:
+			row = append(row, string(buf))
+		}
+		buf, protected = nil, 0
+		isNull, enclosed = false, false
+	}
+
+	for {
+		b, err := cr.rd.ReadByte()
+		if err == io.EOF {
+			if inQuotes {
+				return nil, cr.errorf("unterminated quoted field")
+			}
+			if !started {
+				return nil, io.EOF
+			}
+			// the last line doesn't have a terminator
+			endField()
+			return cr.finishRow(row)
+		} else if err != nil {
+			return nil, err
+		}
+		started = true
+
+		if cr.escape != 0 && b == cr.escape {
+			n, err := cr.rd.ReadByte()
+			if err != nil {
+				return nil, cr.errorf("escape character at end of file")
+			}
+			if n == 'N' && len(buf) == 0 && !enclosed && !inQuotes {
+				isNull = true
+			} else {
+				buf = append(buf, unescape(n))
+			}
+			protected = len(buf)
+			continue
+		}
+
+		if inQuotes {
+			if b == cr.enclose {
+				if next, err := cr.rd.Peek(1); err == nil && next[0] == cr.enclose {
+					_, _ = cr.rd.ReadByte()
+					buf = append(buf, b)
+				} else {
+					inQuotes = false
+				}
+			} else {
+				buf = append(buf, b)
+			}
+			protected = len(buf)
+			continue
+		}
+
+		if cr.enclose != 0 && b == cr.enclose && len(buf) == 0 && !enclosed {
+			inQuotes, enclosed = true, true
+			continue
+		}
+
+		buf = append(buf, b)
+		if len(buf)-protected >= len(lineTerm) && bytes.HasSuffix(buf, lineTerm) {
+			buf = buf[:len(buf)-len(lineTerm)]
+			endField()
+			return cr.finishRow(row)
+		}
+		if len(buf)-protected >= len(fieldTerm) && bytes.HasSuffix(buf, fieldTerm) {
+			buf = buf[:len(buf)-len(fieldTerm)]
+			endField()
+		}
+	}
+}
+
+func (cr *ChunkReader) finishRow(row []interface{}) ([]interface{}, error) {
+	defer func() { cr.line++ }()
+	if len(row) != len(cr.tbl.Columns) {
+		return nil, cr.errorf("expected %d fields, found %d", len(cr.tbl.Columns), len(row))
+	}
+	for i, dec := range cr.tbl.decoders {
+		if dec == nil || row[i] == nil {
+			continue
+		}
+		v, err := dec(row[i].(string))
+		if err != nil {
+			return nil, cr.errorf("column '%s': %v", cr.tbl.Columns[i], err)
+		}
+		row[i] = v
+	}
+	return row, nil
+}
+
+func (cr *ChunkReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s: line %d: %s", filepath.Base(cr.path), cr.line, fmt.Sprintf(format, args...))
+}
+
+// unescape returns the character that the escape sequence ending in |b| stands for, as LOAD DATA interprets it
+func unescape(b byte) byte {
+	switch b {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	default:
+		return b
+	}
+}
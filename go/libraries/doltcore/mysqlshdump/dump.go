@@ -0,0 +1,281 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mysqlshdump reads the dumps written by MySQL Shell's util.dumpInstance, util.dumpSchemas and
+// util.dumpTables. A dump is a directory of files:
+//
+//	@.json                          metadata of the dump, including the schemas it contains
+//	@.done.json                     written when the dump is complete
+//	<schema>.json                   metadata of a schema, including its tables and views
+//	<schema>@<table>.json           metadata of a table, including its columns and the format of its data
+//	<schema>@<table>.sql            the CREATE TABLE statement of a table
+//	<schema>@<table>.triggers.sql   the triggers of a table
+//	<schema>@<view>.sql             the CREATE VIEW statement of a view
+//	<schema>@<table>@<n>.tsv.zst    a chunk of the rows of a table. The last chunk is named <schema>@<table>@@<n>.
+//
+// Names in file names are replaced by the base names given in the metadata, which escape characters that can't
+// appear in file names. Tables that aren't chunked have a single data file, <schema>@<table>.tsv.zst.
+package mysqlshdump
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	dumpMetadataFile = "@.json"
+	dumpDoneFile     = "@.done.json"
+)
+
+// ErrIncompleteDump is returned when opening a dump that MySQL Shell didn't finish writing
+var ErrIncompleteDump = errors.New("the dump is incomplete")
+
+// Dump is a dump written by MySQL Shell
+type Dump struct {
+	dir  string
+	meta dumpMetadata
+}
+
+type dumpMetadata struct {
+	Dumper    string            `json:"dumper"`
+	Version   string            `json:"version"`
+	Schemas   []string          `json:"schemas"`
+	Basenames map[string]string `json:"basenames"`
+}
+
+type schemaMetadata struct {
+	Tables    []string          `json:"tables"`
+	Views     []string          `json:"views"`
+	Basenames map[string]string `json:"basenames"`
+}
+
+type tableMetadata struct {
+	Options struct {
+		Columns                  []string          `json:"columns"`
+		FieldsTerminatedBy       string            `json:"fieldsTerminatedBy"`
+		FieldsEnclosedBy         string            `json:"fieldsEnclosedBy"`
+		FieldsOptionallyEnclosed bool              `json:"fieldsOptionallyEnclosed"`
+		FieldsEscapedBy          string            `json:"fieldsEscapedBy"`
+		LinesTerminatedBy        string            `json:"linesTerminatedBy"`
+		DecodeColumns            map[string]string `json:"decodeColumns"`
+	} `json:"options"`
+	Triggers     []string `json:"triggers"`
+	IncludesData *bool    `json:"includesData"`
+	Extension    string   `json:"extension"`
+	Chunking     bool     `json:"chunking"`
+	Compression  string   `json:"compression"`
+}
+
+// Open opens the dump in |dir|
+func Open(dir string) (*Dump, error) {
+	d := &Dump{dir: dir}
+	if err := readJSON(filepath.Join(dir, dumpMetadataFile), &d.meta); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, dumpDoneFile)); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrIncompleteDump
+	} else if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Schemas returns the names of the schemas in the dump
+func (d *Dump) Schemas() []string {
+	return d.meta.Schemas
+}
+
+// Schema is a schema in a dump
+type Schema struct {
+	Name   string
+	Tables []*Table
+	// Views are the paths of the files of CREATE VIEW statements of the schema's views
+	Views []string
+}
+
+// Table is a table in a dump
+type Table struct {
+	Name string
+	// DDL is the path of the file of the table's CREATE TABLE statement
+	DDL string
+	// Triggers is the path of the file of the table's triggers, or empty if it has none
+	Triggers string
+	// Columns are the names of the columns in each row of the table's data, in order
+	Columns []string
+	// Chunks are the paths of the files of the table's data, in order
+	Chunks []string
+
+	dialect     Dialect
+	decoders    []decoder
+	compression string
+}
+
+// Schema returns the schema named |name| in the dump
+func (d *Dump) Schema(name string) (*Schema, error) {
+	found := false
+	for _, s := range d.meta.Schemas {
+		found = found || s == name
+	}
+	if !found {
+		return nil, fmt.Errorf("schema '%s' is not in the dump", name)
+	}
+
+	schemaBase := basename(d.meta.Basenames, name)
+	var meta schemaMetadata
+	if err := readJSON(filepath.Join(d.dir, schemaBase+".json"), &meta); err != nil {
+		return nil, err
+	}
+
+	sch := &Schema{Name: name}
+	for _, view := range meta.Views {
+		sch.Views = append(sch.Views, filepath.Join(d.dir, basename(meta.Basenames, view)+".sql"))
+	}
+	for _, name := range meta.Tables {
+		tbl, err := d.readTable(name, basename(meta.Basenames, name))
+		if err != nil {
+			return nil, err
+		}
+		sch.Tables = append(sch.Tables, tbl)
+	}
+	return sch, nil
+}
+
+func (d *Dump) readTable(name, base string) (*Table, error) {
+	var meta tableMetadata
+	if err := readJSON(filepath.Join(d.dir, base+".json"), &meta); err != nil {
+		return nil, err
+	}
+
+	tbl := &Table{
+		Name:        name,
+		DDL:         filepath.Join(d.dir, base+".sql"),
+		Columns:     meta.Options.Columns,
+		compression: meta.Compression,
+		dialect: Dialect{
+			FieldsTerminatedBy: meta.Options.FieldsTerminatedBy,
+			FieldsEnclosedBy:   meta.Options.FieldsEnclosedBy,
+			FieldsEscapedBy:    meta.Options.FieldsEscapedBy,
+			LinesTerminatedBy:  meta.Options.LinesTerminatedBy,
+		},
+	}
+	if tbl.dialect.FieldsTerminatedBy == "" {
+		tbl.dialect.FieldsTerminatedBy = "\t"
+	}
+	if tbl.dialect.LinesTerminatedBy == "" {
+		tbl.dialect.LinesTerminatedBy = "\n"
+	}
+	if len(meta.Triggers) > 0 {
+		tbl.Triggers = filepath.Join(d.dir, base+".triggers.sql")
+	}
+
+	tbl.decoders = make([]decoder, len(tbl.Columns))
+	for col, fn := range meta.Options.DecodeColumns {
+		i := indexOf(tbl.Columns, col)
+		if i < 0 {
+			return nil, fmt.Errorf("table '%s': decodeColumns: unknown column '%s'", name, col)
+		}
+		dec, err := newDecoder(fn)
+		if err != nil {
+			return nil, fmt.Errorf("table '%s': column '%s': %w", name, col, err)
+		}
+		tbl.decoders[i] = dec
+	}
+
+	if meta.IncludesData != nil && !*meta.IncludesData {
+		return tbl, nil
+	}
+	chunks, err := d.findChunks(base, meta.Extension)
+	if err != nil {
+		return nil, err
+	}
+	tbl.Chunks = chunks
+	return tbl, nil
+}
+
+// findChunks returns the paths of the data files of the table with the base name |base|, in order
+func (d *Dump) findChunks(base, ext string) ([]string, error) {
+	if ext == "" {
+		ext = "tsv.zst"
+	}
+	whole := filepath.Join(d.dir, base+"."+ext)
+	if _, err := os.Stat(whole); err == nil {
+		return []string{whole}, nil
+	}
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+	type chunk struct {
+		n    int
+		path string
+	}
+	var chunks []chunk
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base+"@") || !strings.HasSuffix(name, "."+ext) {
+			continue
+		}
+		num := strings.TrimSuffix(strings.TrimPrefix(name, base+"@"), "."+ext)
+		num = strings.TrimPrefix(num, "@")
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			// the name of another table that starts with this one's
+			continue
+		}
+		chunks = append(chunks, chunk{n: n, path: filepath.Join(d.dir, name)})
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].n < chunks[j].n
+	})
+
+	paths := make([]string, len(chunks))
+	for i, c := range chunks {
+		paths[i] = c.path
+	}
+	return paths, nil
+}
+
+func basename(basenames map[string]string, name string) string {
+	if b, ok := basenames[name]; ok {
+		return b
+	}
+	return name
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlshdump
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, contents string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+}
+
+func writeZstdFile(t *testing.T, dir, name, contents string) {
+	f, err := os.Create(filepath.Join(dir, name))
+	require.NoError(t, err)
+	defer f.Close()
+	w, err := zstd.NewWriter(f)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func readRows(t *testing.T, tbl *Table, path string) [][]interface{} {
+	cr, err := tbl.OpenChunk(path)
+	require.NoError(t, err)
+	defer cr.Close()
+
+	var rows [][]interface{}
+	for {
+		row, err := cr.ReadRow()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestDump(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "@.json", `{"dumper": "mysqlsh Ver 8.0.33", "version": "2.0.1", "schemas": ["shop"], "basenames": {"shop": "shop"}}`)
+	writeFile(t, dir, "shop.json", `{"tables": ["items", "notes"], "views": ["cheap"], "basenames": {"items": "shop@items", "notes": "shop@notes", "cheap": "shop@cheap"}}`)
+	writeFile(t, dir, "shop@items.json", `{"options": {"columns": ["id", "name", "data"], "fieldsTerminatedBy": "\t", "fieldsEnclosedBy": "", "fieldsEscapedBy": "\\", "linesTerminatedBy": "\n", "decodeColumns": {"data": "FROM_BASE64"}}, "triggers": ["items_ins"], "extension": "tsv.zst", "chunking": true, "compression": "zstd"}`)
+	writeFile(t, dir, "shop@items.sql", "CREATE TABLE IF NOT EXISTS `items` (`id` int primary key, `name` text, `data` blob);\n")
+	writeZstdFile(t, dir, "shop@items@0.tsv.zst", "1\tone\taGk=\n2\t\\N\t\\N\n")
+	writeZstdFile(t, dir, "shop@items@@1.tsv.zst", "3\ttab\\there\\\\\tAA==\n")
+	writeFile(t, dir, "shop@notes.json", `{"options": {"columns": ["id", "note"], "fieldsTerminatedBy": ",", "fieldsEnclosedBy": "\"", "fieldsEscapedBy": "", "linesTerminatedBy": "\r\n"}, "extension": "csv", "chunking": false, "compression": "none"}`)
+	writeFile(t, dir, "shop@notes.csv", "1,\"a, \"\"quoted\"\" note\"\r\n2,NULL\r\n3,\"line\r\nbreak\"")
+
+	_, err := Open(dir)
+	assert.ErrorIs(t, err, ErrIncompleteDump)
+	writeFile(t, dir, "@.done.json", `{}`)
+
+	d, err := Open(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop"}, d.Schemas())
+	_, err = d.Schema("other")
+	assert.Error(t, err)
+
+	sch, err := d.Schema("shop")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "shop@cheap.sql")}, sch.Views)
+	require.Len(t, sch.Tables, 2)
+
+	items := sch.Tables[0]
+	assert.Equal(t, "items", items.Name)
+	assert.Equal(t, filepath.Join(dir, "shop@items.sql"), items.DDL)
+	assert.Equal(t, filepath.Join(dir, "shop@items.triggers.sql"), items.Triggers)
+	assert.Equal(t, []string{"id", "name", "data"}, items.Columns)
+	require.Equal(t, []string{filepath.Join(dir, "shop@items@0.tsv.zst"), filepath.Join(dir, "shop@items@@1.tsv.zst")}, items.Chunks)
+	assert.Equal(t, [][]interface{}{{"1", "one", "hi"}, {"2", nil, nil}}, readRows(t, items, items.Chunks[0]))
+	assert.Equal(t, [][]interface{}{{"3", "tab\there\\", "\x00"}}, readRows(t, items, items.Chunks[1]))
+
+	notes := sch.Tables[1]
+	assert.Empty(t, notes.Triggers)
+	require.Equal(t, []string{filepath.Join(dir, "shop@notes.csv")}, notes.Chunks)
+	assert.Equal(t, [][]interface{}{{"1", `a, "quoted" note`}, {"2", nil}, {"3", "line\r\nbreak"}}, readRows(t, notes, notes.Chunks[0]))
+}
+
+func TestReadRowErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chunk.tsv")
+	tbl := &Table{
+		Columns:  []string{"a", "b"},
+		decoders: make([]decoder, 2),
+		dialect:  Dialect{FieldsTerminatedBy: "\t", FieldsEscapedBy: "\\", LinesTerminatedBy: "\n"},
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("1\t2\n1\t2\t3\n"), 0644))
+	cr, err := tbl.OpenChunk(path)
+	require.NoError(t, err)
+	defer cr.Close()
+	_, err = cr.ReadRow()
+	require.NoError(t, err)
+	_, err = cr.ReadRow()
+	assert.EqualError(t, err, "chunk.tsv: line 2: expected 2 fields, found 3")
+}
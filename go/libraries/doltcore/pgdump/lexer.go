@@ -0,0 +1,411 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgdump
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	// tkWord is an unquoted keyword or identifier
+	tkWord tokenKind = iota
+	// tkIdent is a quoted identifier. Its text is unquoted.
+	tkIdent
+	// tkString is a string constant. Its text is unquoted and unescaped.
+	tkString
+	tkNumber
+	// tkOp is an operator or punctuation
+	tkOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// is returns whether the token is an unquoted word equal to one of |words|, ignoring case
+func (t token) is(words ...string) bool {
+	if t.kind != tkWord {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) isOp(op string) bool {
+	return t.kind == tkOp && t.text == op
+}
+
+// isName returns whether the token can be the name of a table, column or other object
+func (t token) isName() bool {
+	return t.kind == tkWord || t.kind == tkIdent
+}
+
+// name returns the name of the object the token refers to. Postgres folds unquoted names to lower case.
+func (t token) name() string {
+	if t.kind == tkWord {
+		return strings.ToLower(t.text)
+	}
+	return t.text
+}
+
+// sql returns the token in the MySQL dialect
+func (t token) sql() string {
+	switch t.kind {
+	case tkIdent:
+		return quoteIdent(t.text)
+	case tkString:
+		return quoteString(t.text)
+	default:
+		return t.text
+	}
+}
+
+func quoteIdent(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+func quoteString(s string) string {
+	return "'" + stringEscaper.Replace(s) + "'"
+}
+
+// keywordsBeforeParens are the keywords that are separated from a following parenthesis when rendering, so they
+// aren't mistaken for function calls
+var keywordsBeforeParens = map[string]bool{
+	"all": true, "and": true, "any": true, "as": true, "by": true, "check": true, "default": true, "else": true,
+	"exists": true, "from": true, "in": true, "into": true, "join": true, "key": true, "not": true, "on": true,
+	"or": true, "select": true, "then": true, "using": true, "values": true, "when": true, "where": true,
+}
+
+// render returns the statement made of |toks| in the MySQL dialect
+func render(toks []token) string {
+	var sb strings.Builder
+	for i, t := range toks {
+		if i > 0 && needsSpace(toks[i-1], t) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(t.sql())
+	}
+	return sb.String()
+}
+
+func needsSpace(prev, t token) bool {
+	switch {
+	case prev.isOp("(") || prev.isOp("."):
+		return false
+	case t.isOp(",") || t.isOp(")") || t.isOp("."):
+		return false
+	case t.isOp("("):
+		return !prev.isName() || keywordsBeforeParens[strings.ToLower(prev.text)] && prev.kind == tkWord
+	default:
+		return true
+	}
+}
+
+const opChars = "+-*/<>=~!@#%^&|?:"
+
+// lexer splits a dump into the tokens of its statements
+type lexer struct {
+	rd *bufio.Reader
+}
+
+// statement returns the tokens of the next statement, without its terminating semicolon. It returns io.EOF if there
+// are no more statements. psql meta-commands, like \connect, are skipped.
+func (l *lexer) statement() ([]token, error) {
+	var toks []token
+	for {
+		if len(toks) == 0 {
+			if err := l.skipSpace(); err == io.EOF {
+				return nil, io.EOF
+			} else if err != nil {
+				return nil, err
+			}
+			if b, err := l.rd.Peek(1); err == nil && b[0] == '\\' {
+				if _, err = l.rd.ReadString('\n'); err != nil && err != io.EOF {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		tok, err := l.next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("unterminated statement: %s", truncate(render(toks)))
+		} else if err != nil {
+			return nil, err
+		}
+		if tok.isOp(";") {
+			return toks, nil
+		}
+		toks = append(toks, tok)
+	}
+}
+
+// restOfLine discards the rest of the current line
+func (l *lexer) restOfLine() error {
+	_, err := l.rd.ReadString('\n')
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpace(); err != nil {
+		return token{}, err
+	}
+	c, err := l.rd.ReadByte()
+	if err != nil {
+		return token{}, err
+	}
+
+	switch {
+	case c == '\'':
+		s, err := l.quoted('\'', false)
+		return token{kind: tkString, text: s}, err
+	case c == '"':
+		s, err := l.quoted('"', false)
+		return token{kind: tkIdent, text: s}, err
+	case (c == 'e' || c == 'E') && l.peekIs('\''):
+		_, _ = l.rd.ReadByte()
+		s, err := l.quoted('\'', true)
+		return token{kind: tkString, text: s}, err
+	case c == '$':
+		return l.dollarQuoted()
+	case isWordStart(c):
+		return token{kind: tkWord, text: l.readWhile(c, isWordChar)}, nil
+	case isDigit(c):
+		return token{kind: tkNumber, text: l.number(c)}, nil
+	case strings.IndexByte(opChars, c) >= 0:
+		op := []byte{c}
+		for {
+			b, err := l.rd.Peek(2)
+			if len(b) == 0 || strings.IndexByte(opChars, b[0]) < 0 {
+				break
+			}
+			if err == nil && (string(b) == "--" || string(b) == "/*") {
+				break
+			}
+			op = append(op, b[0])
+			_, _ = l.rd.ReadByte()
+		}
+		return token{kind: tkOp, text: string(op)}, nil
+	default:
+		return token{kind: tkOp, text: string(c)}, nil
+	}
+}
+
+// skipSpace skips whitespace and comments
+func (l *lexer) skipSpace() error {
+	for {
+		b, err := l.rd.Peek(2)
+		if len(b) == 0 {
+			return err
+		}
+		switch {
+		case b[0] == ' ' || b[0] == '\t' || b[0] == '\n' || b[0] == '\r' || b[0] == '\f':
+			_, _ = l.rd.ReadByte()
+		case string(b) == "--":
+			if _, err := l.rd.ReadString('\n'); err != nil {
+				return err
+			}
+		case string(b) == "/*":
+			if err := l.blockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// blockComment skips a comment, which may contain nested comments
+func (l *lexer) blockComment() error {
+	depth := 0
+	var prev byte
+	for {
+		c, err := l.rd.ReadByte()
+		if err == io.EOF {
+			return fmt.Errorf("unterminated comment")
+		} else if err != nil {
+			return err
+		}
+		switch {
+		case prev == '/' && c == '*':
+			depth++
+			c = 0
+		case prev == '*' && c == '/':
+			depth--
+			if depth == 0 {
+				return nil
+			}
+			c = 0
+		}
+		prev = c
+	}
+}
+
+func (l *lexer) peekIs(c byte) bool {
+	b, err := l.rd.Peek(1)
+	return err == nil && b[0] == c
+}
+
+// quoted reads the rest of a string or identifier that started with |q|. A doubled |q| stands for itself. If
+// |backslashes| is true, backslash escape sequences are interpreted.
+func (l *lexer) quoted(q byte, backslashes bool) (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := l.rd.ReadByte()
+		if err == io.EOF {
+			return "", fmt.Errorf("unterminated quoted string")
+		} else if err != nil {
+			return "", err
+		}
+		switch {
+		case c == q:
+			if !l.peekIs(q) {
+				return sb.String(), nil
+			}
+			_, _ = l.rd.ReadByte()
+			sb.WriteByte(q)
+		case c == '\\' && backslashes:
+			e, err := l.rd.ReadByte()
+			if err != nil {
+				return "", fmt.Errorf("unterminated quoted string")
+			}
+			sb.WriteByte(unescapeChar(e))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// dollarQuoted reads the rest of a string quoted by $tag$ ... $tag$. A $ that doesn't start a tag, like one in a
+// positional parameter, is returned as an operator.
+func (l *lexer) dollarQuoted() (token, error) {
+	var tag []byte
+	for {
+		b, err := l.rd.Peek(1)
+		if err == nil && b[0] == '$' {
+			break
+		}
+		if err != nil || !isWordChar(b[0]) {
+			return token{kind: tkOp, text: "$" + string(tag)}, nil
+		}
+		tag = append(tag, b[0])
+		_, _ = l.rd.ReadByte()
+	}
+	_, _ = l.rd.ReadByte()
+
+	end := "$" + string(tag) + "$"
+	var sb strings.Builder
+	for {
+		c, err := l.rd.ReadByte()
+		if err == io.EOF {
+			return token{}, fmt.Errorf("unterminated dollar-quoted string")
+		} else if err != nil {
+			return token{}, err
+		}
+		sb.WriteByte(c)
+		if c == '$' && strings.HasSuffix(sb.String(), end) {
+			s := sb.String()
+			return token{kind: tkString, text: s[:len(s)-len(end)]}, nil
+		}
+	}
+}
+
+func (l *lexer) number(first byte) string {
+	num := []byte{first}
+	for {
+		b, err := l.rd.Peek(2)
+		if len(b) == 0 {
+			return string(num)
+		}
+		c := b[0]
+		exp := (c == 'e' || c == 'E') && err == nil && (isDigit(b[1]) || b[1] == '+' || b[1] == '-')
+		if !isDigit(c) && c != '.' && !exp {
+			return string(num)
+		}
+		_, _ = l.rd.ReadByte()
+		num = append(num, c)
+		if exp {
+			sign, _ := l.rd.ReadByte()
+			num = append(num, sign)
+		}
+	}
+}
+
+func (l *lexer) readWhile(first byte, pred func(byte) bool) string {
+	s := []byte{first}
+	for {
+		b, err := l.rd.Peek(1)
+		if err != nil || !pred(b[0]) {
+			return string(s)
+		}
+		s = append(s, b[0])
+		_, _ = l.rd.ReadByte()
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '$'
+}
+
+// unescapeChar returns the character that a backslash followed by |c| stands for
+func unescapeChar(c byte) byte {
+	switch c {
+	case 'b':
+		return '\b'
+	case 'f':
+		return '\f'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'v':
+		return '\v'
+	default:
+		return c
+	}
+}
+
+func truncate(s string) string {
+	const max = 60
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
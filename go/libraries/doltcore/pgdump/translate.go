@@ -0,0 +1,1022 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pgdump translates the plain format dumps written by pg_dump to MySQL statements, so that Postgres databases
+// can be imported by running the translated statements.
+//
+// Tables, constraints, indexes, views and enum types are translated, and the data in COPY blocks is translated to
+// batched INSERT statements. serial and identity columns become AUTO_INCREMENT columns, which are declared at the end
+// of the dump, after their tables' primary keys have been added. Statements that only apply to Postgres, like
+// SET, COMMENT ON, GRANT and those about sequences and owners, are dropped. Other statements that can't be
+// translated, like CREATE FUNCTION, are skipped with a warning.
+package pgdump
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxInsertRows  = 1000
+	maxInsertBytes = 1 << 20
+)
+
+// valueKind is how the values of a column in a COPY block are translated
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindBool
+	kindBytes
+	kindBit
+	kindTimestampTZ
+	kindTimeTZ
+)
+
+type column struct {
+	name    string
+	sqlType string
+	kind    valueKind
+}
+
+type table struct {
+	columns []*column
+}
+
+func (t *table) column(name string) *column {
+	if t == nil {
+		return nil
+	}
+	for _, c := range t.columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+type autoInc struct {
+	table, column string
+}
+
+// copyState is the state of a COPY block whose rows are being translated
+type copyState struct {
+	table  string
+	prefix string
+	kinds  []valueKind
+}
+
+// Translator reads a dump written by pg_dump --format=plain and translates it to MySQL statements, each terminated
+// by a semicolon and a newline.
+type Translator struct {
+	lex  *lexer
+	warn func(msg string)
+	out  bytes.Buffer
+	err  error
+
+	tables   map[string]*table
+	enums    map[string][]string
+	autoIncs []autoInc
+	copy     *copyState
+}
+
+var _ io.Reader = (*Translator)(nil)
+
+// NewTranslator returns a Translator reading the dump from |rd|. |warn| is called with a message for each statement
+// that is skipped because it can't be translated.
+func NewTranslator(rd io.Reader, warn func(msg string)) *Translator {
+	if warn == nil {
+		warn = func(string) {}
+	}
+	return &Translator{
+		lex:    &lexer{rd: bufio.NewReaderSize(rd, 256*1024)},
+		warn:   warn,
+		tables: make(map[string]*table),
+		enums:  make(map[string][]string),
+	}
+}
+
+// Read implements io.Reader, reading the translated statements
+func (t *Translator) Read(p []byte) (int, error) {
+	for t.out.Len() == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+		t.err = t.translateNext()
+	}
+	return t.out.Read(p)
+}
+
+// translateNext translates the next statement of the dump, or the next batch of rows of a COPY block
+func (t *Translator) translateNext() error {
+	if t.copy != nil {
+		return t.copyRows()
+	}
+
+	toks, err := t.lex.statement()
+	if err == io.EOF {
+		t.finish()
+		return io.EOF
+	} else if err != nil {
+		return err
+	}
+	if len(toks) == 0 {
+		return nil
+	}
+	toks = stripQualifiers(toks)
+
+	switch {
+	case toks[0].is("SET", "SELECT", "GRANT", "REVOKE", "COMMENT"):
+		// session settings, sequence values, privileges and comments
+		return nil
+	case toks[0].is("COPY"):
+		return t.startCopy(toks)
+	case toks[0].is("CREATE"):
+		return t.create(toks)
+	case toks[0].is("ALTER"):
+		return t.alter(toks)
+	default:
+		t.skip(toks)
+		return nil
+	}
+}
+
+func (t *Translator) emit(toks []token) {
+	t.out.WriteString(render(toks))
+	t.out.WriteString(";\n")
+}
+
+func (t *Translator) skip(toks []token) {
+	t.warn(fmt.Sprintf("skipped unsupported statement: %s", truncate(render(toks))))
+}
+
+func (t *Translator) create(toks []token) error {
+	i := 1
+	if keywords(toks, i, "OR", "REPLACE") {
+		i += 2
+	}
+	switch {
+	case keywords(toks, i, "TABLE"):
+		return t.createTable(toks, i+1)
+	case keywords(toks, i, "UNLOGGED", "TABLE"):
+		return t.createTable(toks, i+2)
+	case keywords(toks, i, "INDEX"):
+		t.createIndex(toks, false, i+1)
+	case keywords(toks, i, "UNIQUE", "INDEX"):
+		t.createIndex(toks, true, i+2)
+	case keywords(toks, i, "VIEW"):
+		t.emit(expressions(toks))
+	case keywords(toks, i, "TYPE"):
+		t.createType(toks, i+1)
+	case keywords(toks, i, "SCHEMA"), keywords(toks, i, "EXTENSION"), keywords(toks, i, "SEQUENCE"):
+		// everything is imported into the current database, and sequences are replaced by AUTO_INCREMENT columns
+	default:
+		t.skip(toks)
+	}
+	return nil
+}
+
+func (t *Translator) createTable(toks []token, i int) error {
+	if keywords(toks, i, "IF", "NOT", "EXISTS") {
+		i += 3
+	}
+	if i+1 >= len(toks) || !toks[i].isName() || !toks[i+1].isOp("(") {
+		// e.g. a partition, or CREATE TABLE ... AS
+		t.skip(toks)
+		return nil
+	}
+	name := toks[i].name()
+	end := matchBracket(toks, i+1)
+	if end < 0 {
+		return fmt.Errorf("table %s: unbalanced parentheses", name)
+	}
+	if end+1 < len(toks) {
+		t.warn(fmt.Sprintf("table %s: ignored %s", name, truncate(render(toks[end+1:]))))
+	}
+
+	tbl := &table{}
+	out := []token{word("CREATE"), word("TABLE"), ident(name), op("(")}
+	var defs [][]token
+	for _, elem := range splitTopLevel(toks[i+2 : end]) {
+		switch {
+		case len(elem) == 0:
+			continue
+		case elem[0].is("CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK"):
+			defs = append(defs, constraint(elem))
+		case elem[0].is("LIKE", "EXCLUDE") || !elem[0].isName():
+			t.warn(fmt.Sprintf("table %s: ignored %s", name, truncate(render(elem))))
+		default:
+			def, col := t.columnDef(name, elem)
+			defs = append(defs, def)
+			tbl.columns = append(tbl.columns, col)
+		}
+	}
+	for k, def := range defs {
+		if k > 0 {
+			out = append(out, op(","))
+		}
+		out = append(out, def...)
+	}
+	out = append(out, op(")"))
+
+	t.tables[name] = tbl
+	t.emit(out)
+	return nil
+}
+
+// columnConstraintWords start the parts of a column definition that follow its type
+var columnConstraintWords = []string{"CONSTRAINT", "NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "COLLATE", "GENERATED"}
+
+func (t *Translator) columnDef(tableName string, elem []token) ([]token, *column) {
+	colName := elem[0].name()
+	end := typeEnd(elem, 1)
+	sqlType, kind, serial := t.columnType(tableName, colName, elem[1:end])
+	col := &column{name: colName, sqlType: sqlType, kind: kind}
+
+	out := []token{ident(colName), word(sqlType)}
+	notNull := false
+	rest := elem[end:]
+	for k := 0; k < len(rest); {
+		tok := rest[k]
+		switch {
+		case tok.is("DEFAULT"):
+			exprEnd := k + 1
+			for depth := 0; exprEnd < len(rest); exprEnd++ {
+				if rest[exprEnd].isOp("(") {
+					depth++
+				} else if rest[exprEnd].isOp(")") {
+					depth--
+				} else if depth == 0 && exprEnd > k+1 && rest[exprEnd].is(columnConstraintWords...) {
+					break
+				}
+			}
+			expr := expressions(rest[k+1 : exprEnd])
+			if isNextval(expr) {
+				serial = true
+			} else {
+				out = append(out, tok)
+				out = append(out, defaultExpr(expr)...)
+			}
+			k = exprEnd
+		case tok.is("COLLATE"):
+			// Postgres collations don't have MySQL equivalents
+			k += 2
+		case tok.is("GENERATED") && isIdentity(rest[k:]):
+			serial = true
+			k = identityEnd(rest, k)
+		case tok.is("NOT") && k+1 < len(rest) && rest[k+1].is("NULL"):
+			notNull = true
+			out = append(out, rest[k], rest[k+1])
+			k += 2
+		case tok.is("REFERENCES", "CHECK", "CONSTRAINT"):
+			c := constraint(rest[k:])
+			out = append(out, c...)
+			k = len(rest)
+		default:
+			out = append(out, tok)
+			k++
+		}
+	}
+
+	if serial {
+		if !notNull {
+			out = append(out, word("NOT"), word("NULL"))
+		}
+		t.addAutoInc(tableName, colName)
+	}
+	return out, col
+}
+
+// columnType returns the MySQL type of a column with the Postgres type |typ|, how its values are translated, and
+// whether it is a serial column
+func (t *Translator) columnType(tableName, colName string, typ []token) (string, valueKind, bool) {
+	var words, args []string
+	array := false
+	for _, tok := range typ {
+		switch {
+		case tok.isOp("."):
+			// a type in another schema
+			words = words[:0]
+		case tok.isOp("["):
+			array = true
+		case tok.isName():
+			words = append(words, tok.name())
+		case tok.kind == tkNumber:
+			args = append(args, tok.text)
+		}
+	}
+	base := strings.Join(words, " ")
+	arg := func(def string) string {
+		if len(args) > 0 {
+			return args[0]
+		}
+		return def
+	}
+	withArgs := func(name string) string {
+		if len(args) > 0 {
+			return name + "(" + strings.Join(args, ",") + ")"
+		}
+		return name
+	}
+
+	if array {
+		// arrays are imported in their text representation, e.g. {1,2,3}
+		return "longtext", kindString, false
+	}
+
+	switch base {
+	case "smallint", "int2":
+		return "smallint", kindString, false
+	case "integer", "int", "int4":
+		return "int", kindString, false
+	case "bigint", "int8":
+		return "bigint", kindString, false
+	case "smallserial", "serial2":
+		return "smallint", kindString, true
+	case "serial", "serial4":
+		return "int", kindString, true
+	case "bigserial", "serial8":
+		return "bigint", kindString, true
+	case "real", "float4":
+		return "float", kindString, false
+	case "double precision", "float8":
+		return "double", kindString, false
+	case "float":
+		if n, err := strconv.Atoi(arg("53")); err == nil && n <= 24 {
+			return "float", kindString, false
+		}
+		return "double", kindString, false
+	case "numeric", "decimal":
+		if len(args) == 0 {
+			return "decimal(65,30)", kindString, false
+		}
+		return withArgs("decimal"), kindString, false
+	case "money":
+		return "decimal(19,2)", kindString, false
+	case "boolean", "bool":
+		return "tinyint(1)", kindBool, false
+	case "character varying", "varchar":
+		if len(args) == 0 {
+			return "longtext", kindString, false
+		}
+		return withArgs("varchar"), kindString, false
+	case "character", "char", "bpchar":
+		return "char(" + arg("1") + ")", kindString, false
+	case "text", "citext", "name", "xml", "interval", "tsvector":
+		return "longtext", kindString, false
+	case "bytea":
+		return "longblob", kindBytes, false
+	case "date":
+		return "date", kindString, false
+	case "timestamp", "timestamp without time zone":
+		return "datetime(" + arg("6") + ")", kindString, false
+	case "timestamp with time zone", "timestamptz":
+		return "datetime(" + arg("6") + ")", kindTimestampTZ, false
+	case "time", "time without time zone":
+		return "time(" + arg("6") + ")", kindString, false
+	case "time with time zone", "timetz":
+		return "time(" + arg("6") + ")", kindTimeTZ, false
+	case "uuid":
+		return "char(36)", kindString, false
+	case "json", "jsonb":
+		return "json", kindString, false
+	case "inet", "cidr":
+		return "varchar(43)", kindString, false
+	case "macaddr", "macaddr8":
+		return "varchar(23)", kindString, false
+	case "bit":
+		return "bit(" + arg("1") + ")", kindBit, false
+	case "bit varying", "varbit":
+		return "bit(" + arg("64") + ")", kindBit, false
+	}
+
+	if vals, ok := t.enums[base]; ok {
+		quoted := make([]string, len(vals))
+		for i, v := range vals {
+			quoted[i] = quoteString(v)
+		}
+		return "enum(" + strings.Join(quoted, ",") + ")", kindString, false
+	}
+
+	t.warn(fmt.Sprintf("column %s.%s: unsupported type %s, imported as longtext", tableName, colName, render(typ)))
+	return "longtext", kindString, false
+}
+
+func (t *Translator) createType(toks []token, i int) {
+	if i >= len(toks) || !toks[i].isName() || !keywords(toks, i+1, "AS", "ENUM") || i+3 >= len(toks) {
+		t.skip(toks)
+		return
+	}
+	var vals []string
+	for _, tok := range toks[i+3:] {
+		if tok.kind == tkString {
+			vals = append(vals, tok.text)
+		}
+	}
+	t.enums[toks[i].name()] = vals
+}
+
+func (t *Translator) createIndex(toks []token, unique bool, i int) {
+	if keywords(toks, i, "CONCURRENTLY") {
+		i++
+	}
+	if keywords(toks, i, "IF", "NOT", "EXISTS") {
+		i += 3
+	}
+	if i+2 >= len(toks) || !toks[i].isName() || !toks[i+1].is("ON") {
+		t.skip(toks)
+		return
+	}
+	name := toks[i].name()
+	i += 2
+	if keywords(toks, i, "ONLY") {
+		i++
+	}
+	if i >= len(toks) || !toks[i].isName() {
+		t.skip(toks)
+		return
+	}
+	tableName := toks[i].name()
+	i++
+	if keywords(toks, i, "USING") {
+		if i+1 >= len(toks) || !toks[i+1].is("btree") {
+			t.skip(toks)
+			return
+		}
+		i += 2
+	}
+	if i >= len(toks) || !toks[i].isOp("(") {
+		t.skip(toks)
+		return
+	}
+	end := matchBracket(toks, i)
+	if end < 0 {
+		t.skip(toks)
+		return
+	}
+	for _, tok := range toks[end+1:] {
+		if tok.is("WHERE") {
+			// partial indexes aren't supported
+			t.skip(toks)
+			return
+		}
+	}
+
+	out := []token{word("CREATE")}
+	if unique {
+		out = append(out, word("UNIQUE"))
+	}
+	out = append(out, word("INDEX"), ident(name), word("ON"), ident(tableName), op("("))
+	for k, elem := range splitTopLevel(toks[i+1 : end]) {
+		// only indexes of columns are supported, not of expressions
+		if len(elem) == 0 || !elem[0].isName() {
+			t.skip(toks)
+			return
+		}
+		if k > 0 {
+			out = append(out, op(","))
+		}
+		out = append(out, ident(elem[0].name()))
+		for _, tok := range elem[1:] {
+			switch {
+			case tok.is("ASC", "DESC"):
+				out = append(out, tok)
+			case tok.is("NULLS", "FIRST", "LAST"):
+			default:
+				t.skip(toks)
+				return
+			}
+		}
+	}
+	out = append(out, op(")"))
+	t.emit(out)
+}
+
+func (t *Translator) alter(toks []token) error {
+	if len(toks) >= 3 && toks[len(toks)-3].is("OWNER") && toks[len(toks)-2].is("TO") {
+		return nil
+	}
+	if !keywords(toks, 1, "TABLE") {
+		if !keywords(toks, 1, "SEQUENCE") && !keywords(toks, 1, "SCHEMA") && !keywords(toks, 1, "DEFAULT", "PRIVILEGES") {
+			t.skip(toks)
+		}
+		return nil
+	}
+
+	i := 2
+	if keywords(toks, i, "IF", "EXISTS") {
+		i += 2
+	}
+	if keywords(toks, i, "ONLY") {
+		i++
+	}
+	if i >= len(toks) || !toks[i].isName() {
+		t.skip(toks)
+		return nil
+	}
+	tableName := toks[i].name()
+	action := toks[i+1:]
+
+	switch {
+	case keywords(action, 0, "ADD", "CONSTRAINT") && len(action) > 3:
+		if action[3].is("EXCLUDE") {
+			t.skip(toks)
+			return nil
+		}
+		out := []token{word("ALTER"), word("TABLE"), ident(tableName), word("ADD")}
+		t.emit(append(out, constraint(action[1:])...))
+	case keywords(action, 0, "ALTER"):
+		c := 1
+		if keywords(action, c, "COLUMN") {
+			c++
+		}
+		if c+1 >= len(action) || !action[c].isName() {
+			t.skip(toks)
+			return nil
+		}
+		colName := action[c].name()
+		sub := action[c+1:]
+		switch {
+		case keywords(sub, 0, "SET", "DEFAULT"):
+			expr := expressions(sub[2:])
+			if isNextval(expr) {
+				t.addAutoInc(tableName, colName)
+				return nil
+			}
+			out := []token{word("ALTER"), word("TABLE"), ident(tableName), word("ALTER"), word("COLUMN"), ident(colName), word("SET"), word("DEFAULT")}
+			t.emit(append(out, defaultExpr(expr)...))
+		case keywords(sub, 0, "ADD", "GENERATED") && isIdentity(sub[1:]):
+			t.addAutoInc(tableName, colName)
+		default:
+			t.skip(toks)
+		}
+	default:
+		t.skip(toks)
+	}
+	return nil
+}
+
+func (t *Translator) addAutoInc(tableName, colName string) {
+	for _, ai := range t.autoIncs {
+		if ai.table == tableName && ai.column == colName {
+			return
+		}
+	}
+	t.autoIncs = append(t.autoIncs, autoInc{table: tableName, column: colName})
+}
+
+// finish declares the AUTO_INCREMENT columns, which must be keys, so it is done after all constraints are added
+func (t *Translator) finish() {
+	for _, ai := range t.autoIncs {
+		col := t.tables[ai.table].column(ai.column)
+		if col == nil {
+			t.warn(fmt.Sprintf("column %s.%s: can't make an unknown column AUTO_INCREMENT", ai.table, ai.column))
+			continue
+		}
+		t.emit([]token{word("ALTER"), word("TABLE"), ident(ai.table), word("MODIFY"), word("COLUMN"), ident(ai.column),
+			word(col.sqlType), word("NOT"), word("NULL"), word("AUTO_INCREMENT")})
+	}
+	t.autoIncs = nil
+}
+
+// startCopy starts translating the rows of the COPY block started by the statement |toks|
+func (t *Translator) startCopy(toks []token) error {
+	i := 1
+	if i >= len(toks) || !toks[i].isName() {
+		return fmt.Errorf("unsupported COPY statement: %s", truncate(render(toks)))
+	}
+	tableName := toks[i].name()
+	i++
+
+	tbl := t.tables[tableName]
+	var cols []string
+	if i < len(toks) && toks[i].isOp("(") {
+		end := matchBracket(toks, i)
+		if end < 0 {
+			return fmt.Errorf("unsupported COPY statement: %s", truncate(render(toks)))
+		}
+		for _, elem := range splitTopLevel(toks[i+1 : end]) {
+			if len(elem) != 1 || !elem[0].isName() {
+				return fmt.Errorf("unsupported COPY statement: %s", truncate(render(toks)))
+			}
+			cols = append(cols, elem[0].name())
+		}
+		i = end + 1
+	} else if tbl != nil {
+		for _, c := range tbl.columns {
+			cols = append(cols, c.name)
+		}
+	}
+	if !keywords(toks, i, "FROM", "stdin") || i+2 != len(toks) {
+		return fmt.Errorf("unsupported COPY statement: %s", truncate(render(toks)))
+	}
+	// the rows start on the next line
+	if err := t.lex.restOfLine(); err != nil {
+		return err
+	}
+
+	prefix := []token{word("INSERT"), word("INTO"), ident(tableName)}
+	kinds := make([]valueKind, len(cols))
+	if len(cols) > 0 {
+		prefix = append(prefix, op("("))
+		for k, c := range cols {
+			if k > 0 {
+				prefix = append(prefix, op(","))
+			}
+			prefix = append(prefix, ident(c))
+			if col := tbl.column(c); col != nil {
+				kinds[k] = col.kind
+			}
+		}
+		prefix = append(prefix, op(")"))
+	}
+	prefix = append(prefix, word("VALUES"))
+
+	t.copy = &copyState{table: tableName, prefix: render(prefix) + " ", kinds: kinds}
+	return nil
+}
+
+// copyRows translates the next rows of the current COPY block to an INSERT statement
+func (t *Translator) copyRows() error {
+	cs := t.copy
+	var values strings.Builder
+	n := 0
+	for n < maxInsertRows && values.Len() < maxInsertBytes {
+		line, err := t.lex.rd.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return fmt.Errorf("the data of table %s is not terminated by \\.", cs.table)
+		} else if err != nil && err != io.EOF {
+			return err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == `\.` {
+			t.copy = nil
+			break
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(cs.kinds) > 0 && len(fields) != len(cs.kinds) {
+			return fmt.Errorf("table %s: expected %d values, found %d: %s", cs.table, len(cs.kinds), len(fields), truncate(line))
+		}
+		if n > 0 {
+			values.WriteString(",\n")
+		}
+		values.WriteByte('(')
+		for k, f := range fields {
+			if k > 0 {
+				values.WriteByte(',')
+			}
+			kind := kindString
+			if k < len(cs.kinds) {
+				kind = cs.kinds[k]
+			}
+			values.WriteString(copyValue(f, kind))
+		}
+		values.WriteByte(')')
+		n++
+	}
+
+	if n > 0 {
+		t.out.WriteString(cs.prefix)
+		t.out.WriteString(values.String())
+		t.out.WriteString(";\n")
+	}
+	return nil
+}
+
+// copyValue returns a value in the text format of COPY as a MySQL literal
+func copyValue(f string, kind valueKind) string {
+	if f == `\N` {
+		return "NULL"
+	}
+	v := unescapeCopy(f)
+	switch kind {
+	case kindBool:
+		if v == "t" || v == "true" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case kindBytes:
+		if h := strings.TrimPrefix(v, `\x`); h != v {
+			if _, err := hex.DecodeString(h); err == nil {
+				if h == "" {
+					return "''"
+				}
+				return "X'" + h + "'"
+			}
+		}
+	case kindBit:
+		if strings.Trim(v, "01") == "" && v != "" {
+			return "b'" + v + "'"
+		}
+	case kindTimestampTZ:
+		v = utcTimestamp(v)
+	case kindTimeTZ:
+		if k := strings.LastIndexAny(v, "+-"); k > 0 {
+			v = v[:k]
+		}
+	}
+	return quoteString(v)
+}
+
+// unescapeCopy interprets the backslash escape sequences of a value in the text format of COPY
+func unescapeCopy(f string) string {
+	if strings.IndexByte(f, '\\') < 0 {
+		return f
+	}
+	var sb strings.Builder
+	for i := 0; i < len(f); i++ {
+		c := f[i]
+		if c != '\\' || i+1 == len(f) {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		c = f[i]
+		switch {
+		case c >= '0' && c <= '7':
+			// up to 3 octal digits
+			j := i
+			for j < len(f) && j < i+3 && f[j] >= '0' && f[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(f[i:j], 8, 8)
+			sb.WriteByte(byte(n))
+			i = j - 1
+		case c == 'x' && i+1 < len(f) && isHex(f[i+1]):
+			// up to 2 hex digits
+			j := i + 1
+			for j < len(f) && j < i+3 && isHex(f[j]) {
+				j++
+			}
+			n, _ := strconv.ParseUint(f[i+1:j], 16, 8)
+			sb.WriteByte(byte(n))
+			i = j - 1
+		default:
+			sb.WriteByte(unescapeChar(c))
+		}
+	}
+	return sb.String()
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+var timestampTZLayouts = []string{"2006-01-02 15:04:05-07", "2006-01-02 15:04:05-07:00", "2006-01-02 15:04:05-07:00:00"}
+
+// utcTimestamp converts a timestamp with a time zone offset to UTC, since MySQL's DATETIME doesn't have a time zone.
+// Values that can't be parsed, like infinity, are returned unchanged.
+func utcTimestamp(v string) string {
+	for _, layout := range timestampTZLayouts {
+		if ts, err := time.Parse(layout, v); err == nil {
+			return ts.UTC().Format("2006-01-02 15:04:05.999999")
+		}
+	}
+	return v
+}
+
+func word(s string) token {
+	return token{kind: tkWord, text: s}
+}
+
+func ident(s string) token {
+	return token{kind: tkIdent, text: s}
+}
+
+func op(s string) token {
+	return token{kind: tkOp, text: s}
+}
+
+// keywords returns whether the tokens starting at |i| are the unquoted words |words|
+func keywords(toks []token, i int, words ...string) bool {
+	if i < 0 || i+len(words) > len(toks) {
+		return false
+	}
+	for k, w := range words {
+		if !toks[i+k].is(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchBracket returns the index of the bracket that closes the one at |i|, or -1 if it isn't closed
+func matchBracket(toks []token, i int) int {
+	open := toks[i].text
+	close := map[string]string{"(": ")", "[": "]"}[open]
+	depth := 0
+	for k := i; k < len(toks); k++ {
+		if toks[k].isOp(open) {
+			depth++
+		} else if toks[k].isOp(close) {
+			depth--
+			if depth == 0 {
+				return k
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits |toks| at the commas that aren't in brackets
+func splitTopLevel(toks []token) [][]token {
+	var elems [][]token
+	depth, start := 0, 0
+	for k, tok := range toks {
+		switch {
+		case tok.isOp("(") || tok.isOp("["):
+			depth++
+		case tok.isOp(")") || tok.isOp("]"):
+			depth--
+		case tok.isOp(",") && depth == 0:
+			elems = append(elems, toks[start:k])
+			start = k + 1
+		}
+	}
+	return append(elems, toks[start:])
+}
+
+// typeContinuations are the words that continue the names of types, like double precision
+var typeContinuations = []string{"varying", "precision", "without", "with", "time", "zone"}
+
+// typeEnd returns the index after the end of the type name that starts at |i|
+func typeEnd(toks []token, i int) int {
+	if i >= len(toks) {
+		return i
+	}
+	i++
+	if i+1 < len(toks) && toks[i].isOp(".") && toks[i+1].isName() {
+		i += 2
+	}
+	for i < len(toks) {
+		switch {
+		case toks[i].is(typeContinuations...):
+			i++
+		case toks[i].isOp("(") || toks[i].isOp("["):
+			end := matchBracket(toks, i)
+			if end < 0 {
+				return len(toks)
+			}
+			i = end + 1
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// stripQualifiers removes the public and pg_catalog schemas from qualified names
+func stripQualifiers(toks []token) []token {
+	out := make([]token, 0, len(toks))
+	for k := 0; k < len(toks); k++ {
+		if k+1 < len(toks) && toks[k+1].isOp(".") && toks[k].isName() && (toks[k].name() == "public" || toks[k].name() == "pg_catalog") {
+			k++
+			continue
+		}
+		out = append(out, toks[k])
+	}
+	return out
+}
+
+// expressions translates the expressions in |toks|, removing casts and replacing comparisons to arrays with IN
+func expressions(toks []token) []token {
+	var out []token
+	for k := 0; k < len(toks); k++ {
+		if toks[k].isOp("::") {
+			k = typeEnd(toks, k+1) - 1
+			continue
+		}
+		out = append(out, toks[k])
+	}
+	return rewriteArrayComparisons(out)
+}
+
+// rewriteArrayComparisons replaces x = ANY (ARRAY[...]) with x IN (...), and x <> ALL (ARRAY[...]) with x NOT IN (...)
+func rewriteArrayComparisons(toks []token) []token {
+	var out []token
+	for k := 0; k < len(toks); k++ {
+		eq := toks[k].isOp("=") && keywords(toks, k+1, "ANY")
+		ne := (toks[k].isOp("<>") || toks[k].isOp("!=")) && keywords(toks, k+1, "ALL")
+		if (eq || ne) && k+4 < len(toks) && toks[k+2].isOp("(") && toks[k+3].is("ARRAY") && toks[k+4].isOp("[") {
+			end := matchBracket(toks, k+4)
+			if end > 0 && end+1 < len(toks) && toks[end+1].isOp(")") {
+				if ne {
+					out = append(out, word("NOT"))
+				}
+				out = append(out, word("IN"), op("("))
+				out = append(out, rewriteArrayComparisons(toks[k+5:end])...)
+				out = append(out, op(")"))
+				k = end + 1
+				continue
+			}
+		}
+		out = append(out, toks[k])
+	}
+	return out
+}
+
+// constraint translates a table constraint, or the constraints of a column that follow its type
+func constraint(toks []token) []token {
+	toks = expressions(toks)
+	out := make([]token, 0, len(toks))
+	quoteList := false
+	for k := 0; k < len(toks); k++ {
+		tok := toks[k]
+		switch {
+		case tok.is("DEFERRABLE"):
+		case tok.is("NOT") && k+1 < len(toks) && toks[k+1].is("DEFERRABLE", "VALID"):
+			k++
+		case tok.is("INITIALLY") && k+1 < len(toks):
+			k++
+		case tok.is("CONSTRAINT", "REFERENCES") && k+1 < len(toks) && toks[k+1].isName():
+			out = append(out, tok, ident(toks[k+1].name()))
+			quoteList = tok.is("REFERENCES")
+			k++
+		case tok.is("KEY", "UNIQUE"):
+			out = append(out, tok)
+			quoteList = true
+		case tok.isOp("(") && quoteList:
+			end := matchBracket(toks, k)
+			if end < 0 {
+				end = len(toks) - 1
+			}
+			out = append(out, tok)
+			for _, t := range toks[k+1 : end+1] {
+				if t.kind == tkWord {
+					t = ident(t.name())
+				}
+				out = append(out, t)
+			}
+			k = end
+			quoteList = false
+		default:
+			out = append(out, tok)
+			quoteList = false
+		}
+	}
+	return out
+}
+
+// defaultExpr returns the default value |expr| of a column. Expressions that aren't literals must be parenthesized.
+func defaultExpr(expr []token) []token {
+	switch {
+	case len(expr) == 1 && (expr[0].kind == tkString || expr[0].kind == tkNumber):
+		return expr
+	case len(expr) == 1 && expr[0].is("TRUE", "FALSE", "NULL", "CURRENT_TIMESTAMP", "CURRENT_DATE", "LOCALTIMESTAMP"):
+		return expr
+	case len(expr) == 2 && expr[0].isOp("-") && expr[1].kind == tkNumber:
+		return expr
+	case len(expr) == 3 && expr[0].isOp("(") && expr[2].isOp(")"):
+		return defaultExpr(expr[1:2])
+	}
+	return append(append([]token{op("(")}, expr...), op(")"))
+}
+
+func isNextval(expr []token) bool {
+	for _, tok := range expr {
+		if tok.is("nextval") {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentity returns whether |toks| start with GENERATED {ALWAYS | BY DEFAULT} AS IDENTITY
+func isIdentity(toks []token) bool {
+	return keywords(toks, 0, "GENERATED", "ALWAYS", "AS", "IDENTITY") || keywords(toks, 0, "GENERATED", "BY", "DEFAULT", "AS", "IDENTITY")
+}
+
+// identityEnd returns the index after the identity clause starting at |k|, including its sequence options
+func identityEnd(toks []token, k int) int {
+	for ; k < len(toks); k++ {
+		if toks[k].is("IDENTITY") {
+			break
+		}
+	}
+	k++
+	if k < len(toks) && toks[k].isOp("(") {
+		if end := matchBracket(toks, k); end > 0 {
+			return end + 1
+		}
+		return len(toks)
+	}
+	return k
+}
@@ -0,0 +1,181 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgdump
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func translate(t *testing.T, dump string) (string, []string) {
+	var warnings []string
+	tr := NewTranslator(strings.NewReader(dump), func(msg string) {
+		warnings = append(warnings, msg)
+	})
+	out, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	return string(out), warnings
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		dump     string
+		expected string
+		warnings []string
+	}{
+		{
+			name: "settings, comments and meta-commands are dropped",
+			dump: `--
+-- PostgreSQL database dump
+--
+\restrict abc
+SET statement_timeout = 0;
+SELECT pg_catalog.set_config('search_path', '', false);
+COMMENT ON EXTENSION plpgsql IS 'PL/pgSQL procedural language';
+/* a /* nested */ comment */
+\unrestrict abc
+`,
+		},
+		{
+			name: "tables",
+			dump: `CREATE TYPE public.mood AS ENUM (
+    'sad',
+    'happy'
+);
+CREATE TABLE public.t (
+    id integer NOT NULL,
+    name character varying(50) COLLATE pg_catalog."C",
+    "Key" text DEFAULT 'x'::text,
+    active boolean DEFAULT true NOT NULL,
+    data bytea,
+    feeling public.mood,
+    created timestamp with time zone DEFAULT now(),
+    amount numeric(10,2) DEFAULT '-1.5'::numeric,
+    tags text[],
+    CONSTRAINT t_name_check CHECK (((name)::text = ANY (ARRAY['a'::text, 'b'::text])))
+);
+ALTER TABLE public.t OWNER TO postgres;
+`,
+			expected: "CREATE TABLE `t`(`id` int NOT NULL, `name` varchar(50), `Key` longtext DEFAULT 'x', `active` tinyint(1) DEFAULT true NOT NULL, " +
+				"`data` longblob, `feeling` enum('sad','happy'), `created` datetime(6) DEFAULT (now()), `amount` decimal(10,2) DEFAULT '-1.5', `tags` longtext, " +
+				"CONSTRAINT `t_name_check` CHECK (((name) IN ('a', 'b'))));\n",
+		},
+		{
+			name: "serial and identity columns",
+			dump: `CREATE TABLE public.a (id integer NOT NULL);
+CREATE SEQUENCE public.a_id_seq AS integer START WITH 1;
+ALTER SEQUENCE public.a_id_seq OWNED BY public.a.id;
+CREATE TABLE public.b (id bigint NOT NULL, v text);
+ALTER TABLE public.b ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY (
+    SEQUENCE NAME public.b_id_seq
+    START WITH 1
+);
+CREATE TABLE public.c (id serial, v text);
+ALTER TABLE ONLY public.a ALTER COLUMN id SET DEFAULT nextval('public.a_id_seq'::regclass);
+ALTER TABLE ONLY public.b ALTER COLUMN v SET DEFAULT 'none'::text;
+SELECT pg_catalog.setval('public.a_id_seq', 2, true);
+ALTER TABLE ONLY public.a ADD CONSTRAINT a_pkey PRIMARY KEY (id);
+`,
+			expected: "CREATE TABLE `a`(`id` int NOT NULL);\n" +
+				"CREATE TABLE `b`(`id` bigint NOT NULL, `v` longtext);\n" +
+				"CREATE TABLE `c`(`id` int NOT NULL, `v` longtext);\n" +
+				"ALTER TABLE `b` ALTER COLUMN `v` SET DEFAULT 'none';\n" +
+				"ALTER TABLE `a` ADD CONSTRAINT `a_pkey` PRIMARY KEY (`id`);\n" +
+				"ALTER TABLE `b` MODIFY COLUMN `id` bigint NOT NULL AUTO_INCREMENT;\n" +
+				"ALTER TABLE `c` MODIFY COLUMN `id` int NOT NULL AUTO_INCREMENT;\n" +
+				"ALTER TABLE `a` MODIFY COLUMN `id` int NOT NULL AUTO_INCREMENT;\n",
+		},
+		{
+			name: "copy",
+			dump: `CREATE TABLE public.t (id integer, name text, active boolean, data bytea, created timestamp with time zone, flags bit(3));
+COPY public.t (id, name, active, data, created, flags) FROM stdin;
+1	it's a\ttab	t	\\x6869	2023-01-02 03:04:05.5+02	101
+2	\N	f	\N	\N	\N
+\.
+
+COPY public.t (id) FROM stdin;
+\.
+`,
+			expected: "CREATE TABLE `t`(`id` int, `name` longtext, `active` tinyint(1), `data` longblob, `created` datetime(6), `flags` bit(3));\n" +
+				"INSERT INTO `t`(`id`, `name`, `active`, `data`, `created`, `flags`) VALUES ('1','it\\'s a\ttab',TRUE,X'6869','2023-01-02 01:04:05.5',b'101'),\n" +
+				"('2',NULL,FALSE,NULL,NULL,NULL);\n",
+		},
+		{
+			name: "indexes, foreign keys and views",
+			dump: `ALTER TABLE ONLY public.c ADD CONSTRAINT c_p_fkey FOREIGN KEY (p) REFERENCES public.p(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+CREATE UNIQUE INDEX c_name_idx ON public.c USING btree (name DESC NULLS LAST);
+CREATE INDEX c_lower_idx ON public.c USING btree (lower(name));
+CREATE INDEX c_doc_idx ON public.c USING gin (doc);
+CREATE VIEW public.v AS
+ SELECT c.id
+   FROM public.c
+  WHERE (c.total > (100)::numeric);
+`,
+			expected: "ALTER TABLE `c` ADD CONSTRAINT `c_p_fkey` FOREIGN KEY (`p`) REFERENCES `p`(`id`) ON DELETE CASCADE;\n" +
+				"CREATE UNIQUE INDEX `c_name_idx` ON `c`(`name` DESC);\n" +
+				"CREATE VIEW v AS SELECT c.id FROM c WHERE (c.total > (100));\n",
+			warnings: []string{
+				"skipped unsupported statement: CREATE INDEX c_lower_idx ON c USING btree(lower(name))",
+				"skipped unsupported statement: CREATE INDEX c_doc_idx ON c USING gin(doc)",
+			},
+		},
+		{
+			name: "unsupported statements",
+			dump: `CREATE FUNCTION public.f() RETURNS integer
+    LANGUAGE sql
+    AS $_$ select 1; $_$;
+CREATE TABLE public.t (p point);
+`,
+			expected: "CREATE TABLE `t`(`p` longtext);\n",
+			warnings: []string{
+				"skipped unsupported statement: CREATE FUNCTION f() RETURNS integer LANGUAGE sql AS ' select...",
+				"column t.p: unsupported type point, imported as longtext",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, warnings := translate(t, test.dump)
+			assert.Equal(t, test.expected, out)
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tr := NewTranslator(strings.NewReader("CREATE TABLE t (id int);\nCOPY t (id) FROM stdin;\n1\t2\n\\.\n"), nil)
+	_, err := io.ReadAll(tr)
+	assert.EqualError(t, err, "table t: expected 1 values, found 2: 1\t2")
+
+	tr = NewTranslator(strings.NewReader("COPY t (id) FROM stdin;\n1\n"), nil)
+	_, err = io.ReadAll(tr)
+	assert.EqualError(t, err, `the data of table t is not terminated by \.`)
+
+	tr = NewTranslator(strings.NewReader("CREATE TABLE t (id int)"), nil)
+	_, err = io.ReadAll(tr)
+	assert.EqualError(t, err, "unterminated statement: CREATE TABLE t(id int)")
+}
+
+func TestUnescapeCopy(t *testing.T) {
+	assert.Equal(t, "plain", unescapeCopy("plain"))
+	assert.Equal(t, "a\tb\nc\\d", unescapeCopy(`a\tb\nc\\d`))
+	assert.Equal(t, "\x01A\x7fz", unescapeCopy(`\1\x41\177\z`))
+}
@@ -183,6 +183,12 @@ func rebaseRefs(ctx context.Context, dbData env.DbData, replay ReplayCommitFn, n
 	return nil
 }
 
+// Commits rewrites the history of each of |origins| using the |replay| function, and returns the rewritten commits in
+// the same order. Commits in the history of more than one origin are only replayed once.
+func Commits(ctx context.Context, ddb *doltdb.DoltDB, replay ReplayCommitFn, nerf NeedsRebaseFn, origins ...*doltdb.Commit) ([]*doltdb.Commit, error) {
+	return rebase(ctx, ddb, replay, nerf, origins...)
+}
+
 func rebase(ctx context.Context, ddb *doltdb.DoltDB, replay ReplayCommitFn, nerf NeedsRebaseFn, origins ...*doltdb.Commit) ([]*doltdb.Commit, error) {
 	var rebasedCommits []*doltdb.Commit
 	vs := make(visitedSet)
@@ -0,0 +1,187 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// HistorySquasher rewrites history so that the commits made before a cutoff are replaced by snapshots, keeping the
+// latest commit of each period, such as a week, along the first parents of the history. Commits made after the
+// cutoff are replayed on top of the snapshots with their original roots and metadata. Squashing history that was
+// already squashed with the same cutoff and period leaves it unchanged.
+//
+// A HistorySquasher remembers the commits it rewrote, so that the history shared by several branches or tags is
+// rewritten once.
+type HistorySquasher struct {
+	ddb    *doltdb.DoltDB
+	cutoff time.Time
+	period time.Duration
+
+	rewritten map[hash.Hash]*doltdb.Commit
+}
+
+// NewHistorySquasher returns a HistorySquasher that squashes the commits of |ddb| made before |cutoff| into a
+// snapshot every |period|.
+func NewHistorySquasher(ddb *doltdb.DoltDB, cutoff time.Time, period time.Duration) (*HistorySquasher, error) {
+	if period <= 0 {
+		return nil, errors.New("snapshot period must be positive")
+	}
+	return &HistorySquasher{
+		ddb:       ddb,
+		cutoff:    cutoff,
+		period:    period,
+		rewritten: make(map[hash.Hash]*doltdb.Commit),
+	}, nil
+}
+
+// Squash returns |cm| with its history squashed.
+func (s *HistorySquasher) Squash(ctx context.Context, cm *doltdb.Commit) (*doltdb.Commit, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if rw, ok := s.rewritten[h]; ok {
+		return rw, nil
+	}
+
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Time().Before(s.cutoff) {
+		return s.snapshot(ctx, cm)
+	}
+
+	parents, err := s.ddb.ResolveAllParents(ctx, cm)
+	if err != nil {
+		return nil, err
+	}
+	changed := false
+	rewrittenParents := make([]*doltdb.Commit, len(parents))
+	for i, p := range parents {
+		if rewrittenParents[i], err = s.Squash(ctx, p); err != nil {
+			return nil, err
+		}
+		if changed, err = commitChanged(changed, p, rewrittenParents[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	rw := cm
+	if changed {
+		rw, err = s.recommit(ctx, cm, rewrittenParents)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.rewritten[h] = rw
+	return rw, nil
+}
+
+// snapshot returns a snapshot of |cm|, a commit made before the cutoff, whose parent is the snapshot of the latest
+// first-parent ancestor of |cm| made in an earlier period.
+func (s *HistorySquasher) snapshot(ctx context.Context, cm *doltdb.Commit) (*doltdb.Commit, error) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+	if rw, ok := s.rewritten[h]; ok {
+		return rw, nil
+	}
+
+	period, err := s.periodOf(ctx, cm)
+	if err != nil {
+		return nil, err
+	}
+	ancestor := cm
+	for {
+		if ancestor.NumParents() == 0 {
+			ancestor = nil
+			break
+		}
+		if ancestor, err = s.ddb.ResolveParent(ctx, ancestor, 0); err != nil {
+			return nil, err
+		}
+		p, err := s.periodOf(ctx, ancestor)
+		if err != nil {
+			return nil, err
+		}
+		if p != period {
+			break
+		}
+	}
+
+	var parents []*doltdb.Commit
+	if ancestor != nil {
+		parent, err := s.snapshot(ctx, ancestor)
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent)
+	}
+
+	rw, err := s.recommit(ctx, cm, parents)
+	if err != nil {
+		return nil, err
+	}
+	s.rewritten[h] = rw
+	return rw, nil
+}
+
+func (s *HistorySquasher) periodOf(ctx context.Context, cm *doltdb.Commit) (int64, error) {
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return meta.Time().UnixMilli() / s.period.Milliseconds(), nil
+}
+
+// recommit returns a commit with the root value and metadata of |cm| and the parents |parents|.
+func (s *HistorySquasher) recommit(ctx context.Context, cm *doltdb.Commit, parents []*doltdb.Commit) (*doltdb.Commit, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, valueHash, err := s.ddb.WriteRootValue(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ddb.CommitDanglingWithParentCommits(ctx, valueHash, parents, meta)
+}
+
+func commitChanged(changed bool, orig, rewritten *doltdb.Commit) (bool, error) {
+	if changed {
+		return true, nil
+	}
+	origHash, err := orig.HashOf()
+	if err != nil {
+		return false, err
+	}
+	rewrittenHash, err := rewritten.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return origHash != rewrittenHash, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rebase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+func TestHistorySquasher(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+	ddb := dEnv.DoltDB
+
+	root, err := dEnv.HeadRoot(ctx)
+	require.NoError(t, err)
+	_, valHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+
+	// a commit at midnight UTC on each of the 30 days before Friday, June 30th
+	now := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	var head *doltdb.Commit
+	for i := 30; i > 0; i-- {
+		meta, err := datas.NewCommitMetaWithUserTS("Bill Billerson", "bill@billerson.com", fmt.Sprintf("day %d", i), now.AddDate(0, 0, -i))
+		require.NoError(t, err)
+		var parents []*doltdb.Commit
+		if head != nil {
+			parents = []*doltdb.Commit{head}
+		}
+		head, err = ddb.CommitDanglingWithParentCommits(ctx, valHash, parents, meta)
+		require.NoError(t, err)
+	}
+
+	squasher, err := rebase.NewHistorySquasher(ddb, now.AddDate(0, 0, -10), 7*24*time.Hour)
+	require.NoError(t, err)
+	squashed, err := squasher.Squash(ctx, head)
+	require.NoError(t, err)
+
+	// the last 10 days are kept, and the latest commit of each week before then, weeks starting on Thursdays
+	expected := []string{"day 1", "day 2", "day 3", "day 4", "day 5", "day 6", "day 7", "day 8", "day 9", "day 10",
+		"day 11", "day 16", "day 23", "day 30"}
+	assert.Equal(t, expected, firstParentMessages(t, ctx, ddb, squashed))
+
+	// squashing squashed history leaves it unchanged
+	squasher, err = rebase.NewHistorySquasher(ddb, now.AddDate(0, 0, -10), 7*24*time.Hour)
+	require.NoError(t, err)
+	again, err := squasher.Squash(ctx, squashed)
+	require.NoError(t, err)
+	squashedHash, err := squashed.HashOf()
+	require.NoError(t, err)
+	againHash, err := again.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, squashedHash, againHash)
+
+	// history after the cutoff is unchanged
+	squasher, err = rebase.NewHistorySquasher(ddb, now.AddDate(0, 0, -31), 7*24*time.Hour)
+	require.NoError(t, err)
+	unchanged, err := squasher.Squash(ctx, head)
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	unchangedHash, err := unchanged.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, headHash, unchangedHash)
+}
+
+func firstParentMessages(t *testing.T, ctx context.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit) []string {
+	var msgs []string
+	for {
+		meta, err := cm.GetCommitMeta(ctx)
+		require.NoError(t, err)
+		msgs = append(msgs, meta.Description)
+		if cm.NumParents() == 0 {
+			return msgs
+		}
+		cm, err = ddb.ResolveParent(ctx, cm, 0)
+		require.NoError(t, err)
+	}
+}
@@ -15,6 +15,9 @@
 package remotesrv
 
 import (
+	"context"
+	"io"
+
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/nbs"
@@ -27,9 +30,14 @@ type DBCache interface {
 type RemoteSrvStore interface {
 	chunks.ChunkStore
 	chunks.TableFileStore
+	chunks.UploadedTableFileStore
 
 	Path() (string, bool)
 	GetChunkLocationsWithPaths(hashes hash.HashSet) (map[string]map[hash.Hash]nbs.Range, error)
+
+	// OpenTransferFile opens a table file which is served by the store but isn't in its directory,
+	// returning false if there is no such table file named |fileID|.
+	OpenTransferFile(ctx context.Context, fileID string) (*io.SectionReader, bool, error)
 }
 
 var _ RemoteSrvStore = &nbs.NomsBlockStore{}
@@ -0,0 +1,201 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/libraries/utils/delta"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDeltas(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cs, err := nbs.NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 1<<20, nbs.NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer cs.Close()
+
+	// the store has the base chunks, and the edited chunks are similar to them
+	rng := rand.New(rand.NewSource(0))
+	var bases, edited []chunks.Chunk
+	for i := 0; i < 16; i++ {
+		data := make([]byte, 4096)
+		rng.Read(data)
+		bases = append(bases, chunks.NewChunk(data))
+		data = append([]byte{}, data...)
+		data[rng.Intn(len(data))]++
+		edited = append(edited, chunks.NewChunk(data))
+	}
+	baseFileId, baseData, err := nbs.WriteChunks(bases)
+	require.NoError(t, err)
+	err = cs.WriteTableFile(ctx, baseFileId, len(bases), nil, func() (io.ReadCloser, uint64, error) {
+		return io.NopCloser(bytes.NewReader(baseData)), uint64(len(baseData)), nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, cs.AddTableFilesToManifest(ctx, map[string]int{baseFileId: len(bases)}))
+
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	sealer, err := NewSingleSymmetricKeySealer()
+	require.NoError(t, err)
+	lgr := logrus.NewEntry(logrus.New())
+	dbCache := singletonDBCache{cs}
+
+	srv := httptest.NewServer(newFileHandler(lgr, dbCache, fs, false, sealer))
+	defer srv.Close()
+	rcs := NewHttpFSBackedChunkStore(lgr, srv.Listener.Addr().String(), dbCache, fs, "http", sealer)
+
+	t.Run("GetDeltaChunks", func(t *testing.T) {
+		// the store sends base chunks as deltas against each other, as it doesn't have the edited chunks
+		req := &remotesapi.GetDeltaChunksRequest{RepoPath: GoodRepoPath}
+		for i := 0; i < 4; i++ {
+			h, base := bases[i].Hash(), bases[(i+1)%4].Hash()
+			req.ChunkHashes = append(req.ChunkHashes, h[:])
+			req.BaseHashes = append(req.BaseHashes, base[:])
+		}
+		absent, base := edited[0].Hash(), bases[0].Hash()
+		req.ChunkHashes = append(req.ChunkHashes, absent[:])
+		req.BaseHashes = append(req.BaseHashes, base[:])
+
+		// random chunks don't delta well against each other, so none are sent
+		resp, err := rcs.GetDeltaChunks(ctx, req)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Chunks)
+
+		// a chunk similar to its base is sent as a delta
+		err = cs.Put(ctx, edited[0], func(ctx context.Context, c chunks.Chunk) (hash.HashSet, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		_, err = cs.Commit(ctx, hash.Hash{}, hash.Hash{})
+		require.NoError(t, err)
+		resp, err = rcs.GetDeltaChunks(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, resp.Chunks, 1)
+		dc := resp.Chunks[0]
+		assert.Equal(t, absent[:], dc.Hash)
+		assert.Equal(t, base[:], dc.BaseHash)
+		assert.Less(t, len(dc.Delta), len(edited[0].Data())/10)
+		data, err := delta.Decode(bases[0].Data(), dc.Delta)
+		require.NoError(t, err)
+		assert.Equal(t, edited[0].Data(), data)
+	})
+
+	// the table file of the edited chunks, and its delta pack
+	wr, err := nbs.NewCmpChunkTableWriter("")
+	require.NoError(t, err)
+	defer wr.Remove()
+	for _, c := range edited {
+		require.NoError(t, wr.AddCmpChunk(nbs.ChunkToCompressedChunk(c)))
+	}
+	fileId, err := wr.Finish()
+	require.NoError(t, err)
+	contentHash := wr.GetMD5()
+
+	// writePack writes a delta pack of the edited chunks, with each delta labelled as a delta against |baseOf(i)|
+	writePack := func(t *testing.T, baseOf func(i int) hash.Hash) []byte {
+		pack, err := nbs.NewDeltaPackWriter("")
+		require.NoError(t, err)
+		defer pack.Remove()
+		for i, c := range edited {
+			require.NoError(t, pack.AddDelta(c.Hash(), baseOf(i), delta.Encode(bases[i].Data(), c.Data())))
+		}
+		require.NoError(t, pack.Finish())
+		rd, err := pack.Reader()
+		require.NoError(t, err)
+		defer rd.Close()
+		data, err := io.ReadAll(rd)
+		require.NoError(t, err)
+		return data
+	}
+
+	getLoc := func(t *testing.T, deltaPacks bool) *remotesapi.UploadLoc {
+		id := hash.Parse(fileId)
+		resp, err := rcs.GetUploadLocations(ctx, &remotesapi.GetUploadLocsRequest{
+			RepoPath: GoodRepoPath,
+			TableFileDetails: []*remotesapi.TableFileDetails{{
+				Id:            id[:],
+				ContentLength: wr.ContentLength(),
+				ContentHash:   contentHash,
+				NumChunks:     uint64(len(edited)),
+			}},
+			SupportsDeltaPacks: deltaPacks,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Locs, 1)
+		return resp.Locs[0]
+	}
+
+	t.Run("ClientWithoutSupport", func(t *testing.T) {
+		assert.Nil(t, getLoc(t, false).DeltaPack)
+	})
+
+	t.Run("RejectsWrongBases", func(t *testing.T) {
+		pack := writePack(t, func(i int) hash.Hash {
+			return bases[(i+1)%len(bases)].Hash()
+		})
+		loc := getLoc(t, true)
+		require.NotNil(t, loc.DeltaPack)
+		err := remotestorage.HttpPostUpload(ctx, nil, loc.DeltaPack, contentHash, int64(len(pack)), io.NopCloser(bytes.NewReader(pack)))
+		assert.Error(t, err)
+	})
+
+	t.Run("UploadDeltaPack", func(t *testing.T) {
+		pack := writePack(t, func(i int) hash.Hash {
+			return bases[i].Hash()
+		})
+		assert.Less(t, uint64(len(pack)), wr.ContentLength()/10)
+
+		loc := getLoc(t, true)
+		require.NotNil(t, loc.DeltaPack)
+		err := remotestorage.HttpPostUpload(ctx, nil, loc.DeltaPack, contentHash, int64(len(pack)), io.NopCloser(bytes.NewReader(pack)))
+		require.NoError(t, err)
+
+		require.NoError(t, cs.AddTableFilesToManifest(ctx, map[string]int{fileId: len(edited)}))
+		for _, c := range edited {
+			got, err := cs.Get(ctx, c.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, c.Data(), got.Data())
+		}
+
+		// the table file rebuilt from the delta pack is the table file it was made from
+		rd, err := wr.Reader()
+		require.NoError(t, err)
+		defer rd.Close()
+		expected, err := io.ReadAll(rd)
+		require.NoError(t, err)
+		written, err := os.ReadFile(filepath.Join(dir, fileId))
+		require.NoError(t, err)
+		assert.Equal(t, expected, written)
+	})
+}
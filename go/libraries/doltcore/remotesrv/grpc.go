@@ -24,8 +24,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 
+	"github.com/golang/snappy"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -33,6 +35,7 @@ import (
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/libraries/utils/delta"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -52,6 +55,14 @@ type RemoteChunkStore struct {
 	fs      filesys.Filesys
 	lgr     *logrus.Entry
 	sealer  Sealer
+
+	// uploadPartSize is the size of the parts of resumable uploads. Table files no larger than this are uploaded in
+	// a single request.
+	uploadPartSize uint64
+
+	// pushHooks, if set, are run when a client pushes.
+	pushHooks *PushHooks
+
 	remotesapi.UnimplementedChunkStoreServiceServer
 }
 
@@ -65,7 +76,8 @@ func NewHttpFSBackedChunkStore(lgr *logrus.Entry, httpHost string, csCache DBCac
 		lgr: lgr.WithFields(logrus.Fields{
 			"service": "dolt.services.remotesapi.v1alpha1.ChunkStoreServiceServer",
 		}),
-		sealer: sealer,
+		sealer:         sealer,
+		uploadPartSize: DefaultUploadPartSize,
 	}
 }
 
@@ -189,7 +201,7 @@ func (rs *RemoteChunkStore) GetDownloadLocations(ctx context.Context, req *remot
 			ranges = append(ranges, &remotesapi.RangeChunk{Hash: hCpy[:], Offset: r.Offset, Length: r.Length})
 		}
 
-		url := rs.getDownloadUrl(md, prefix+"/"+loc)
+		url := rs.getDownloadUrl(md, repoPath, prefix+"/"+loc)
 		preurl := url.String()
 		url, err = rs.sealer.Seal(url)
 		if err != nil {
@@ -292,7 +304,7 @@ func (rs *RemoteChunkStore) StreamDownloadLocations(stream remotesapi.ChunkStore
 				ranges = append(ranges, &remotesapi.RangeChunk{Hash: hCpy[:], Offset: r.Offset, Length: r.Length})
 			}
 
-			url := rs.getDownloadUrl(md, prefix+"/"+loc)
+			url := rs.getDownloadUrl(md, repoPath, prefix+"/"+loc)
 			preurl := url.String()
 			url, err = rs.sealer.Seal(url)
 			if err != nil {
@@ -331,12 +343,17 @@ func (rs *RemoteChunkStore) getHost(md metadata.MD) string {
 	return host
 }
 
-func (rs *RemoteChunkStore) getDownloadUrl(md metadata.MD, path string) *url.URL {
+// getDownloadUrl returns the URL of the table file at |path|. The URL names the repo the table file belongs
+// to, so that table files which aren't in the repo's directory can be served from the repo's store.
+func (rs *RemoteChunkStore) getDownloadUrl(md metadata.MD, repoPath, path string) *url.URL {
 	host := rs.getHost(md)
+	params := url.Values{}
+	params.Add("repo_path", repoPath)
 	return &url.URL{
-		Scheme: rs.httpScheme,
-		Host:   host,
-		Path:   path,
+		Scheme:   rs.httpScheme,
+		Host:     host,
+		Path:     path,
+		RawQuery: params.Encode(),
 	}
 }
 
@@ -368,11 +385,15 @@ func (rs *RemoteChunkStore) GetUploadLocations(ctx context.Context, req *remotes
 	logger = logger.WithField(RepoPathField, repoPath)
 	defer func() { logger.Info("finished") }()
 
-	_, err := rs.getStore(logger, repoPath)
+	cs, err := rs.getStore(logger, repoPath)
 	if err != nil {
 		return nil, err
 	}
 
+	// the parts of resumable uploads are kept in the directory of the store until the upload is complete
+	_, hasPath := cs.Path()
+	resumable := req.SupportsResumableUploads && hasPath
+
 	tfds := parseTableFileDetails(req)
 
 	md, _ := metadata.FromIncomingContext(ctx)
@@ -381,14 +402,38 @@ func (rs *RemoteChunkStore) GetUploadLocations(ctx context.Context, req *remotes
 	for _, tfd := range tfds {
 		h := hash.New(tfd.Id)
 		url := rs.getUploadUrl(md, repoPath, tfd)
+		partSize := uint64(0)
+		if resumable && tfd.ContentLength > rs.uploadPartSize {
+			partSize = rs.uploadPartSize
+			q := url.Query()
+			q.Add("part_size", strconv.FormatUint(partSize, 10))
+			url.RawQuery = q.Encode()
+		}
 		url, err = rs.sealer.Seal(url)
 		if err != nil {
 			logger.WithError(err).Error("error sealing upload url")
 			return nil, status.Error(codes.Internal, "Failed to seal upload Url.")
 		}
 
-		loc := &remotesapi.UploadLoc_HttpPost{HttpPost: &remotesapi.HttpPostTableFile{Url: url.String()}}
-		locs = append(locs, &remotesapi.UploadLoc{TableFileHash: h[:], Location: loc})
+		loc := &remotesapi.UploadLoc{TableFileHash: h[:]}
+		if partSize > 0 {
+			loc.Location = &remotesapi.UploadLoc_HttpResumable{HttpResumable: &remotesapi.HttpResumableTableFile{Url: url.String(), PartSize: partSize}}
+		} else {
+			loc.Location = &remotesapi.UploadLoc_HttpPost{HttpPost: &remotesapi.HttpPostTableFile{Url: url.String()}}
+		}
+		if req.SupportsDeltaPacks {
+			packUrl := rs.getUploadUrl(md, repoPath, tfd)
+			q := packUrl.Query()
+			q.Add("delta_pack", "true")
+			packUrl.RawQuery = q.Encode()
+			packUrl, err = rs.sealer.Seal(packUrl)
+			if err != nil {
+				logger.WithError(err).Error("error sealing upload url")
+				return nil, status.Error(codes.Internal, "Failed to seal upload Url.")
+			}
+			loc.DeltaPack = &remotesapi.HttpPostTableFile{Url: packUrl.String()}
+		}
+		locs = append(locs, loc)
 
 		logger.WithFields(logrus.Fields{
 			"table_file_hash": h.String(),
@@ -471,6 +516,10 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 		return nil, err
 	}
 
+	if err = checkClientRepoFormat(cs, repoPath, req.ClientRepoFormat); err != nil {
+		return nil, err
+	}
+
 	updates := make(map[string]int)
 	for _, cti := range req.ChunkTableInfo {
 		updates[hash.New(cti.Hash).String()] = int(cti.ChunkCount)
@@ -485,6 +534,24 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 	currHash := hash.New(req.Current)
 	lastHash := hash.New(req.Last)
 
+	var event *PushEvent
+	if rs.pushHooks.enabled() {
+		event, err = pushEvent(ctx, cs, repoPath, lastHash, currHash)
+		if err != nil {
+			logger.WithError(err).Error("error reading the refs updated by the push")
+			return nil, status.Errorf(codes.Internal, "failed to read pushed refs: %v", err)
+		}
+		err = rs.pushHooks.runPreReceive(ctx, event)
+		var rejected *PushRejectedError
+		if errors.As(err, &rejected) {
+			logger.WithError(err).Info("push rejected")
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		} else if err != nil {
+			logger.WithError(err).Error("error running pre-receive hook")
+			return nil, status.Errorf(codes.Internal, "failed to run pre-receive hook: %v", err)
+		}
+	}
+
 	var ok bool
 	ok, err = cs.Commit(ctx, currHash, lastHash)
 	if err != nil {
@@ -495,6 +562,10 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 		return nil, status.Errorf(codes.Internal, "failed to commit: %v", err)
 	}
 
+	if ok && event != nil {
+		rs.pushHooks.fireWebhooks(logger, event)
+	}
+
 	logger.Tracef("Commit success; moved from %s -> %s", lastHash.String(), currHash.String())
 	return &remotesapi.CommitResponse{Success: ok}, nil
 }
@@ -513,6 +584,11 @@ func (rs *RemoteChunkStore) GetRepoMetadata(ctx context.Context, req *remotesapi
 		return nil, err
 	}
 
+	if ok, supported := remotestorage.ClientSupportsFormat(ctx, cs.Version()); !ok {
+		logger.WithField("nbf_version", cs.Version()).Info("client does not support the repository's storage format")
+		return nil, remotestorage.UnsupportedFormatStatus(repoPath, cs.Version(), supported)
+	}
+
 	size, err := cs.Size(ctx)
 	if err != nil {
 		logger.WithError(err).Error("error calling Size")
@@ -588,7 +664,7 @@ func getTableFileInfo(
 	}
 	appendixTableFileInfo := make([]*remotesapi.TableFileInfo, 0)
 	for _, t := range tableList {
-		url := rs.getDownloadUrl(md, prefix+"/"+t.FileID())
+		url := rs.getDownloadUrl(md, getRepoPath(req), prefix+"/"+t.FileID())
 		url, err = rs.sealer.Seal(url)
 		if err != nil {
 			return nil, status.Error(codes.Internal, "failed to get seal download url for "+t.FileID())
@@ -618,6 +694,10 @@ func (rs *RemoteChunkStore) AddTableFiles(ctx context.Context, req *remotesapi.A
 		return nil, err
 	}
 
+	if err = checkClientRepoFormat(cs, repoPath, req.ClientRepoFormat); err != nil {
+		return nil, err
+	}
+
 	updates := make(map[string]int)
 	for _, cti := range req.ChunkTableInfo {
 		updates[hash.New(cti.Hash).String()] = int(cti.ChunkCount)
@@ -636,6 +716,114 @@ func (rs *RemoteChunkStore) AddTableFiles(ctx context.Context, req *remotesapi.A
 	return &remotesapi.AddTableFilesResponse{Success: true}, nil
 }
 
+// GetUploadedTableFiles returns which of the requested table files were uploaded to the repository and can still be
+// added to its manifest.
+func (rs *RemoteChunkStore) GetUploadedTableFiles(ctx context.Context, req *remotesapi.GetUploadedTableFilesRequest) (*remotesapi.GetUploadedTableFilesResponse, error) {
+	logger := getReqLogger(rs.lgr, "GetUploadedTableFiles")
+	if err := ValidateGetUploadedTableFilesRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	repoPath := getRepoPath(req)
+	logger = logger.WithField(RepoPathField, repoPath)
+	defer func() { logger.Info("finished") }()
+
+	cs, err := rs.getStore(logger, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[string]int)
+	for _, cti := range req.ChunkTableInfo {
+		requested[hash.New(cti.Hash).String()] = int(cti.ChunkCount)
+	}
+
+	uploaded, err := cs.HasUploadedTableFiles(ctx, requested)
+	if err != nil {
+		logger.WithError(err).Error("error occurred checking for uploaded table files")
+		return nil, status.Error(codes.Internal, "error checking for uploaded table files")
+	}
+
+	logger = logger.WithFields(logrus.Fields{
+		"num_requested": len(requested),
+		"num_uploaded":  len(uploaded),
+	})
+
+	ctis := make([]*remotesapi.ChunkTableInfo, 0, len(uploaded))
+	for fileId, numChunks := range uploaded {
+		h := hash.Parse(fileId)
+		ctis = append(ctis, &remotesapi.ChunkTableInfo{Hash: h[:], ChunkCount: uint32(numChunks)})
+	}
+
+	return &remotesapi.GetUploadedTableFilesResponse{ChunkTableInfo: ctis}, nil
+}
+
+func (rs *RemoteChunkStore) GetDeltaChunks(ctx context.Context, req *remotesapi.GetDeltaChunksRequest) (*remotesapi.GetDeltaChunksResponse, error) {
+	logger := getReqLogger(rs.lgr, "GetDeltaChunks")
+	if err := ValidateGetDeltaChunksRequest(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	repoPath := getRepoPath(req)
+	logger = logger.WithField(RepoPathField, repoPath)
+	defer func() { logger.Info("finished") }()
+
+	cs, err := rs.getStore(logger, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(hash.HashSet)
+	for i := range req.ChunkHashes {
+		hashes.Insert(hash.New(req.ChunkHashes[i]))
+		hashes.Insert(hash.New(req.BaseHashes[i]))
+	}
+	var mu sync.Mutex
+	data := make(map[hash.Hash][]byte, len(hashes))
+	err = cs.GetMany(ctx, hashes, func(_ context.Context, c *chunks.Chunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		data[c.Hash()] = c.Data()
+	})
+	if err != nil {
+		logger.WithError(err).Error("error calling GetMany")
+		return nil, status.Error(codes.Internal, "GetMany failure:"+err.Error())
+	}
+
+	// a delta is only sent if it's smaller than the chunk would be downloaded as
+	var deltas []*remotesapi.DeltaChunk
+	for i := range req.ChunkHashes {
+		target, ok := data[hash.New(req.ChunkHashes[i])]
+		if !ok {
+			continue
+		}
+		base, ok := data[hash.New(req.BaseHashes[i])]
+		if !ok {
+			continue
+		}
+		d := delta.Encode(base, target)
+		if len(d) >= len(snappy.Encode(nil, target)) {
+			continue
+		}
+		deltas = append(deltas, &remotesapi.DeltaChunk{Hash: req.ChunkHashes[i], BaseHash: req.BaseHashes[i], Delta: d})
+	}
+
+	logger = logger.WithFields(logrus.Fields{
+		"num_requested": len(req.ChunkHashes),
+		"num_deltas":    len(deltas),
+	})
+
+	return &remotesapi.GetDeltaChunksResponse{Chunks: deltas}, nil
+}
+
+// checkClientRepoFormat returns an error if a client writing to the repository given does so in a different storage
+// format than the repository's. Clients that don't report their format are allowed to write.
+func checkClientRepoFormat(cs RemoteSrvStore, repoPath string, format *remotesapi.ClientRepoFormat) error {
+	if format == nil || format.NbfVersion == "" || format.NbfVersion == cs.Version() {
+		return nil
+	}
+	return status.Errorf(codes.FailedPrecondition, "repository %s uses storage format %s, but the client writes storage format %s",
+		repoPath, cs.Version(), format.NbfVersion)
+}
+
 func (rs *RemoteChunkStore) getStore(logger *logrus.Entry, repoPath string) (RemoteSrvStore, error) {
 	return rs.getOrCreateStore(logger, repoPath, types.Format_Default.VersionString())
 }
@@ -0,0 +1,87 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// versionedStore reports a storage format other than the one of the store it wraps
+type versionedStore struct {
+	RemoteSrvStore
+	version string
+}
+
+func (s versionedStore) Version() string {
+	return s.version
+}
+
+func TestGetRepoMetadataFormats(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cs, err := nbs.NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 1<<20, nbs.NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer cs.Close()
+
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	lgr := logrus.NewEntry(logrus.New())
+
+	// |version| is the format of the repository, clients always report they use the current format
+	getRepoMetadata := func(ctx context.Context, version string) (*remotesapi.GetRepoMetadataResponse, error) {
+		rcs := NewHttpFSBackedChunkStore(lgr, "localhost", singletonDBCache{versionedStore{cs, version}}, fs, "http", nil)
+		return rcs.GetRepoMetadata(ctx, &remotesapi.GetRepoMetadataRequest{
+			RepoPath: GoodRepoPath,
+			ClientRepoFormat: &remotesapi.ClientRepoFormat{
+				NbfVersion: constants.FormatDoltString,
+				NbsVersion: nbs.StorageVersion,
+			},
+		})
+	}
+
+	// clients that advertise the formats they support
+	newClient := metadata.NewIncomingContext(ctx, metadata.Pairs(remotestorage.SupportedFormatsHeader, constants.FormatDoltString))
+	resp, err := getRepoMetadata(newClient, constants.FormatDoltString)
+	require.NoError(t, err)
+	assert.Equal(t, constants.FormatDoltString, resp.NbfVersion)
+	_, err = getRepoMetadata(newClient, constants.FormatLD1String)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// clients that predate format negotiation and don't advertise their formats can read the formats that existed
+	// before it, but not newer ones
+	for _, version := range []string{constants.FormatLD1String, constants.FormatDoltString} {
+		resp, err = getRepoMetadata(ctx, version)
+		require.NoError(t, err)
+		assert.Equal(t, version, resp.NbfVersion)
+	}
+	_, err = getRepoMetadata(ctx, "__DOLT_FUTURE__")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "uses storage format __DOLT_FUTURE__")
+}
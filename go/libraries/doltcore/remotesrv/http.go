@@ -33,6 +33,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -92,7 +93,8 @@ func (fh filehandler) ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 			respWr.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		_, ok := hash.MaybeParse(path[i+1:])
+		fileId := path[i+1:]
+		_, ok := hash.MaybeParse(fileId)
 		if !ok {
 			logger.WithField("last_path_component", path[i+1:]).Warn("bad request with unparseable last path component")
 			respWr.WriteHeader(http.StatusBadRequest)
@@ -104,8 +106,35 @@ func (fh filehandler) ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 			respWr.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		open := func() (tableFileReader, int64, error) {
+			return openFile(abs)
+		}
+		if repoPath := req.URL.Query().Get("repo_path"); repoPath != "" {
+			open = func() (tableFileReader, int64, error) {
+				f, sz, err := openFile(abs)
+				if errors.Is(err, os.ErrNotExist) {
+					return openTransferFile(req.Context(), fh.dbCache, repoPath, fileId)
+				}
+				return f, sz, err
+			}
+		}
 		respWr.Header().Add("Accept-Ranges", "bytes")
-		logger, statusCode = readTableFile(logger, abs, respWr, req.Header.Get("Range"))
+		logger, statusCode = readTableFile(logger, open, respWr, req.Header.Get("Range"))
+
+	case http.MethodHead:
+		if fh.readOnly {
+			respWr.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		i := strings.LastIndex(path, "/")
+		if i < 0 || len(path[i:]) != 33 || req.URL.Query().Get("part_size") == "" {
+			logger = logger.WithField("status", http.StatusNotFound)
+			respWr.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		logger, statusCode = getUploadOffset(logger, fh.dbCache, path[:i], path[i+1:], respWr)
 
 	case http.MethodPost, http.MethodPut:
 		if fh.readOnly {
@@ -168,7 +197,20 @@ func (fh filehandler) ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 			return
 		}
 
-		logger, statusCode = writeTableFile(req.Context(), logger, fh.dbCache, filepath, file, num_chunks, content_hash, uint64(content_length), req.Body)
+		if q.Get("delta_pack") != "" {
+			logger, statusCode = writeDeltaPack(req.Context(), logger, fh.dbCache, filepath, file, num_chunks, content_hash, uint64(content_length), req.Body)
+		} else if pss := q.Get("part_size"); pss != "" {
+			partSize, err := strconv.ParseUint(pss, 10, 64)
+			if err != nil {
+				logger = logger.WithField("status", http.StatusBadRequest)
+				logger.WithError(err).Warn("bad request: part_size parameter did not parse")
+				respWr.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			logger, statusCode = writeTableFilePart(req.Context(), logger, fh.dbCache, filepath, file, num_chunks, content_hash, uint64(content_length), partSize, req, respWr)
+		} else {
+			logger, statusCode = writeTableFile(req.Context(), logger, fh.dbCache, filepath, file, num_chunks, content_hash, uint64(content_length), req.Body)
+		}
 	}
 
 	if statusCode != -1 {
@@ -176,14 +218,14 @@ func (fh filehandler) ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func readTableFile(logger *logrus.Entry, path string, respWr http.ResponseWriter, rangeStr string) (*logrus.Entry, int) {
+func readTableFile(logger *logrus.Entry, open func() (tableFileReader, int64, error), respWr http.ResponseWriter, rangeStr string) (*logrus.Entry, int) {
 	var r io.ReadCloser
 	var readSize int64
 	var fileErr error
 	{
 		if rangeStr == "" {
 			logger = logger.WithField("whole_file", true)
-			r, readSize, fileErr = getFileReader(path)
+			r, readSize, fileErr = getFileReader(open)
 		} else {
 			offset, length, headerStr, err := offsetAndLenFromRange(rangeStr)
 			if err != nil {
@@ -196,7 +238,7 @@ func readTableFile(logger *logrus.Entry, path string, respWr http.ResponseWriter
 			})
 			readSize = length
 			var fSize int64
-			r, fSize, fileErr = getFileReaderAt(path, offset, length)
+			r, fSize, fileErr = getFileReaderAt(open, offset, length)
 			if fileErr == nil {
 				respWr.Header().Add("Content-Range", headerStr+strconv.Itoa(int(fSize)))
 			}
@@ -222,10 +264,11 @@ func readTableFile(logger *logrus.Entry, path string, respWr http.ResponseWriter
 		}
 	}()
 
+	respWr.Header().Set("Content-Length", strconv.FormatInt(readSize, 10))
 	if rangeStr == "" {
-		respWr.WriteHeader(http.StatusPartialContent)
-	} else {
 		respWr.WriteHeader(http.StatusOK)
+	} else {
+		respWr.WriteHeader(http.StatusPartialContent)
 	}
 
 	n, err := io.Copy(respWr, r)
@@ -324,6 +367,75 @@ func writeTableFile(ctx context.Context, logger *logrus.Entry, dbCache DBCache,
 	return logger, http.StatusOK
 }
 
+// writeDeltaPack rebuilds the table file |fileId| from the delta pack in |body| and writes it as writeTableFile does,
+// checking it against its length and hash.
+func writeDeltaPack(ctx context.Context, logger *logrus.Entry, dbCache DBCache, path, fileId string, numChunks int, contentHash []byte, contentLength uint64, body io.ReadCloser) (*logrus.Entry, int) {
+	defer body.Close()
+	if _, ok := hash.MaybeParse(fileId); !ok {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.Warnf("%s is not a valid hash", fileId)
+		return logger, http.StatusBadRequest
+	}
+
+	cs, err := dbCache.Get(path, types.Format_Default.VersionString())
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to get repository")
+		return logger, http.StatusInternalServerError
+	}
+
+	var enc *nbs.Encryption
+	if es, ok := cs.(nbs.EncryptedStore); ok {
+		enc = es.Encryption()
+	}
+	wr, err := nbs.NewEncryptedCmpChunkTableWriter("", enc)
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to create table file writer")
+		return logger, http.StatusInternalServerError
+	}
+	defer wr.Remove()
+
+	err = nbs.ReadDeltaPack(body, func(h hash.Hash) ([]byte, error) {
+		c, err := cs.Get(ctx, h)
+		if err != nil {
+			return nil, err
+		} else if c.IsEmpty() {
+			return nil, fmt.Errorf("%w: base chunk %s not found", nbs.ErrInvalidDeltaPack, h.String())
+		}
+		return c.Data(), nil
+	}, wr)
+	if errors.Is(err, nbs.ErrInvalidDeltaPack) {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.WithError(err).Warn("bad request: invalid delta pack")
+		return logger, http.StatusBadRequest
+	} else if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to read delta pack")
+		return logger, http.StatusInternalServerError
+	}
+
+	id, err := wr.Finish()
+	if err != nil {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.WithError(err).Warn("bad request: failed to rebuild table file from delta pack")
+		return logger, http.StatusBadRequest
+	}
+	if id != fileId {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.Warnf("bad request: delta pack rebuilt table file %s, not %s", id, fileId)
+		return logger, http.StatusBadRequest
+	}
+
+	rd, err := wr.Reader()
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to read rebuilt table file")
+		return logger, http.StatusInternalServerError
+	}
+	return writeTableFile(ctx, logger, dbCache, path, fileId, numChunks, contentHash, contentLength, rd)
+}
+
 func offsetAndLenFromRange(rngStr string) (int64, int64, string, error) {
 	if rngStr == "" {
 		return -1, -1, "", nil
@@ -354,13 +466,23 @@ func offsetAndLenFromRange(rngStr string) (int64, int64, string, error) {
 	return int64(start), int64(end-start) + 1, "bytes " + tokens[0] + "-" + tokens[1] + "/", nil
 }
 
-// getFileReader opens a file at the given path and returns an io.ReadCloser,
-// the corresponding file's filesize, and a http status.
-func getFileReader(path string) (io.ReadCloser, int64, error) {
-	return openFile(path)
+// tableFileReader is a table file being served, which is usually a file in a repo's directory.
+type tableFileReader interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// getFileReader opens a table file with |open| and returns an io.ReadCloser
+// and the corresponding file's filesize.
+func getFileReader(open func() (tableFileReader, int64, error)) (io.ReadCloser, int64, error) {
+	f, fSize, err := open()
+	if err != nil {
+		return nil, 0, err
+	}
+	return closerReaderWrapper{io.NewSectionReader(f, 0, fSize), f}, fSize, nil
 }
 
-func openFile(path string) (*os.File, int64, error) {
+func openFile(path string) (tableFileReader, int64, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get stats for file at path %s: %w", path, err)
@@ -374,26 +496,46 @@ func openFile(path string) (*os.File, int64, error) {
 	return f, info.Size(), nil
 }
 
+// openTransferFile opens the table file |fileId| served by the store of |repoPath|
+// which isn't in its directory, see RemoteSrvStore.OpenTransferFile.
+func openTransferFile(ctx context.Context, dbCache DBCache, repoPath, fileId string) (tableFileReader, int64, error) {
+	cs, err := dbCache.Get(repoPath, types.Format_Default.VersionString())
+	if err != nil {
+		return nil, 0, err
+	}
+	r, ok, err := cs.OpenTransferFile(ctx, fileId)
+	if err != nil {
+		return nil, 0, err
+	} else if !ok {
+		return nil, 0, fmt.Errorf("failed to find table file %s of repo %s: %w", fileId, repoPath, os.ErrNotExist)
+	}
+	return nopCloserReaderAt{r}, r.Size(), nil
+}
+
+type nopCloserReaderAt struct {
+	io.ReaderAt
+}
+
+func (nopCloserReaderAt) Close() error {
+	return nil
+}
+
 type closerReaderWrapper struct {
 	io.Reader
 	io.Closer
 }
 
-func getFileReaderAt(path string, offset int64, length int64) (io.ReadCloser, int64, error) {
-	f, fSize, err := openFile(path)
+func getFileReaderAt(open func() (tableFileReader, int64, error), offset int64, length int64) (io.ReadCloser, int64, error) {
+	f, fSize, err := open()
 	if err != nil {
 		return nil, 0, err
 	}
 
 	if fSize < int64(offset+length) {
-		return nil, 0, fmt.Errorf("failed to read file %s at offset %d, length %d: %w", path, offset, length, ErrReadOutOfBounds)
-	}
-
-	_, err = f.Seek(int64(offset), 0)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to seek file at path %s to offset %d: %w", path, offset, err)
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to read table file at offset %d, length %d: %w", offset, length, ErrReadOutOfBounds)
 	}
 
-	r := closerReaderWrapper{io.LimitReader(f, length), f}
+	r := closerReaderWrapper{io.NewSectionReader(f, offset, length), f}
 	return r, fSize, nil
 }
@@ -0,0 +1,216 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// webhookTimeout is how long a webhook, or a pre-receive hook endpoint, has to respond.
+const webhookTimeout = 30 * time.Second
+
+// PushHooks are run when a client pushes to the server. A push moves the root of the remote database, and the hooks
+// are given the refs whose heads that move changes.
+type PushHooks struct {
+	// PreReceive is run before a push is applied, and rejects the push if it fails. If it's an http or https URL, the
+	// PushEvent is POSTed to it as JSON, and it fails if it doesn't respond with a 2xx status. Otherwise, it's a
+	// script that is run with a line of "<old hash> <new hash> <ref>" on its standard input for each updated ref,
+	// like git's pre-receive hook, and fails if it exits with a non-zero status. The repository's path is in the
+	// DOLT_PUSH_REPO_PATH environment variable. What the hook writes, or responds with, is returned to the
+	// client when it rejects the push.
+	PreReceive string
+	// Webhooks are the URLs the PushEvent is POSTed to, as JSON, after a push is applied. They are called in the
+	// background, and failures are only logged.
+	Webhooks []string
+}
+
+// PushEvent describes a push.
+type PushEvent struct {
+	// RepoPath is the path of the repository pushed to.
+	RepoPath string `json:"repo_path"`
+	// Refs are the refs the push updates.
+	Refs []RefUpdate `json:"refs"`
+}
+
+// RefUpdate is a ref updated by a push. The commits pushed to a branch are the ones reachable from New but not Old.
+type RefUpdate struct {
+	// Ref is the name of the ref, like refs/heads/main.
+	Ref string `json:"ref"`
+	// Old is the address of the ref's previous head, or the zero hash if the push created it.
+	Old string `json:"old"`
+	// New is the address of the ref's new head, or the zero hash if the push deleted it.
+	New string `json:"new"`
+}
+
+// PushRejectedError is returned when the pre-receive hook rejects a push.
+type PushRejectedError struct {
+	Message string
+}
+
+func (e *PushRejectedError) Error() string {
+	if e.Message == "" {
+		return "push rejected by pre-receive hook"
+	}
+	return "push rejected by pre-receive hook: " + e.Message
+}
+
+func (h *PushHooks) enabled() bool {
+	return h != nil && (h.PreReceive != "" || len(h.Webhooks) > 0)
+}
+
+// pushEvent returns the PushEvent for a push to |repoPath| that moves its root from |last| to |curr|. The refs are
+// found by comparing the datasets in the two roots; working sets and other datasets that aren't refs are left out.
+func pushEvent(ctx context.Context, cs RemoteSrvStore, repoPath string, last, curr hash.Hash) (*PushEvent, error) {
+	db := datas.NewTypesDatabase(types.NewValueStore(cs), tree.NewNodeStore(cs))
+
+	heads := func(root hash.Hash) (map[string]hash.Hash, error) {
+		m := make(map[string]hash.Hash)
+		if root.IsEmpty() {
+			return m, nil
+		}
+		dss, err := db.DatasetsByRootHash(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		err = dss.IterAll(ctx, func(id string, addr hash.Hash) error {
+			if strings.HasPrefix(id, "refs/") {
+				m[id] = addr
+			}
+			return nil
+		})
+		return m, err
+	}
+
+	oldHeads, err := heads(last)
+	if err != nil {
+		return nil, err
+	}
+	newHeads, err := heads(curr)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &PushEvent{RepoPath: repoPath, Refs: []RefUpdate{}}
+	for ref, addr := range newHeads {
+		if old := oldHeads[ref]; old != addr {
+			event.Refs = append(event.Refs, RefUpdate{Ref: ref, Old: old.String(), New: addr.String()})
+		}
+	}
+	for ref, old := range oldHeads {
+		if _, ok := newHeads[ref]; !ok {
+			event.Refs = append(event.Refs, RefUpdate{Ref: ref, Old: old.String(), New: hash.Hash{}.String()})
+		}
+	}
+	sort.Slice(event.Refs, func(i, j int) bool {
+		return event.Refs[i].Ref < event.Refs[j].Ref
+	})
+	return event, nil
+}
+
+// runPreReceive runs the pre-receive hook for |event|, returning a *PushRejectedError if it rejects the push.
+func (h *PushHooks) runPreReceive(ctx context.Context, event *PushEvent) error {
+	if h.PreReceive == "" {
+		return nil
+	}
+	if isURL(h.PreReceive) {
+		status, body, err := postEvent(ctx, h.PreReceive, event)
+		if err != nil {
+			return err
+		}
+		if status < 200 || status > 299 {
+			return &PushRejectedError{Message: strings.TrimSpace(body)}
+		}
+		return nil
+	}
+
+	var stdin bytes.Buffer
+	for _, r := range event.Refs {
+		fmt.Fprintf(&stdin, "%s %s %s\n", r.Old, r.New, r.Ref)
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.PreReceive)
+	cmd.Env = append(os.Environ(), "DOLT_PUSH_REPO_PATH="+event.RepoPath)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return &PushRejectedError{Message: strings.TrimSpace(out.String())}
+		}
+		return fmt.Errorf("failed to run pre-receive hook %s: %w", h.PreReceive, err)
+	}
+	return nil
+}
+
+// fireWebhooks POSTs |event| to each of the webhooks in the background.
+func (h *PushHooks) fireWebhooks(logger *logrus.Entry, event *PushEvent) {
+	for _, url := range h.Webhooks {
+		url := url
+		go func() {
+			status, body, err := postEvent(context.Background(), url, event)
+			if err != nil {
+				logger.WithError(err).WithField("webhook", url).Warn("error calling push webhook")
+			} else if status < 200 || status > 299 {
+				logger.WithField("webhook", url).Warnf("push webhook responded with status %d: %s", status, strings.TrimSpace(body))
+			}
+		}()
+	}
+}
+
+func postEvent(ctx context.Context, url string, event *PushEvent) (int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return 0, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPushEvent = &PushEvent{
+	RepoPath: GoodRepoPath,
+	Refs: []RefUpdate{
+		{Ref: "refs/heads/main", Old: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", New: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+	},
+}
+
+func TestPreReceiveScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("pre-receive scripts are shell scripts")
+	}
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	accept := filepath.Join(dir, "accept.sh")
+	require.NoError(t, os.WriteFile(accept, []byte("#!/bin/sh\ngrep -q 'refs/heads/main' && [ \"$DOLT_PUSH_REPO_PATH\" = \"dolthub/database\" ]\n"), 0755))
+	hooks := &PushHooks{PreReceive: accept}
+	require.NoError(t, hooks.runPreReceive(ctx, testPushEvent))
+
+	reject := filepath.Join(dir, "reject.sh")
+	require.NoError(t, os.WriteFile(reject, []byte("#!/bin/sh\necho 'main is frozen'\nexit 1\n"), 0755))
+	hooks = &PushHooks{PreReceive: reject}
+	err := hooks.runPreReceive(ctx, testPushEvent)
+	var rejected *PushRejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, "main is frozen", rejected.Message)
+
+	hooks = &PushHooks{PreReceive: filepath.Join(dir, "missing.sh")}
+	err = hooks.runPreReceive(ctx, testPushEvent)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &rejected))
+}
+
+func TestPreReceiveEndpoint(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event PushEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if event.Refs[0].Ref == "refs/heads/main" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("main is frozen"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	hooks := &PushHooks{PreReceive: srv.URL}
+	err := hooks.runPreReceive(ctx, testPushEvent)
+	var rejected *PushRejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, "main is frozen", rejected.Message)
+
+	other := &PushEvent{RepoPath: GoodRepoPath, Refs: []RefUpdate{{Ref: "refs/heads/other"}}}
+	require.NoError(t, hooks.runPreReceive(ctx, other))
+}
+
+func TestWebhooks(t *testing.T) {
+	received := make(chan PushEvent, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event PushEvent
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+	}))
+	defer srv.Close()
+
+	hooks := &PushHooks{Webhooks: []string{srv.URL, srv.URL}}
+	require.True(t, hooks.enabled())
+	hooks.fireWebhooks(logrus.NewEntry(logrus.StandardLogger()), testPushEvent)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, *testPushEvent, <-received)
+	}
+
+	var none *PushHooks
+	assert.False(t, none.enabled())
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DefaultUploadPartSize is the size of the parts that table files larger than it are uploaded in, when the client
+// supports resumable uploads
+const DefaultUploadPartSize = 32 * 1024 * 1024
+
+// resumableUploadsDir is the directory, within the directory of a store, where the parts of resumable uploads are
+// written until the last part is received
+const resumableUploadsDir = "uploads"
+
+var errResumableUploadsUnsupported = errors.New("store does not support resumable uploads")
+
+// uploadLocks serializes the requests for each resumable upload
+var uploadLocks = &keyedMutex{locks: make(map[string]*refCountedMutex)}
+
+type refCountedMutex struct {
+	sync.Mutex
+	refs int
+}
+
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refCountedMutex
+}
+
+// lock locks the mutex for |key| and returns the func that unlocks it
+func (km *keyedMutex) lock(key string) func() {
+	km.mu.Lock()
+	l, ok := km.locks[key]
+	if !ok {
+		l = &refCountedMutex{}
+		km.locks[key] = l
+	}
+	l.refs++
+	km.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		km.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(km.locks, key)
+		}
+		km.mu.Unlock()
+	}
+}
+
+// partialUploadPath returns the path of the file that the parts of a resumable upload of |fileId| received so far are
+// written to
+func partialUploadPath(cs RemoteSrvStore, fileId string) (string, error) {
+	dir, ok := cs.Path()
+	if !ok {
+		return "", errResumableUploadsUnsupported
+	}
+	return filepath.Join(dir, resumableUploadsDir, fileId), nil
+}
+
+// partialUploadSize returns the number of bytes of the upload at |path| received so far
+func partialUploadSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func getUploadOffset(logger *logrus.Entry, dbCache DBCache, path, fileId string, respWr http.ResponseWriter) (*logrus.Entry, int) {
+	cs, err := dbCache.Get(path, types.Format_Default.VersionString())
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to get repository")
+		return logger, http.StatusInternalServerError
+	}
+
+	partialPath, err := partialUploadPath(cs, fileId)
+	if err != nil {
+		logger = logger.WithField("status", http.StatusNotFound)
+		logger.WithError(err).Warn("bad request: resumable upload to a store without a path")
+		return logger, http.StatusNotFound
+	}
+
+	unlock := uploadLocks.lock(partialPath)
+	defer unlock()
+
+	offset, err := partialUploadSize(partialPath)
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to stat partial upload")
+		return logger, http.StatusInternalServerError
+	}
+
+	respWr.Header().Set(remotestorage.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+	return logger, http.StatusOK
+}
+
+// writeTableFilePart writes a part of a resumable upload of a table file. Parts must be written in order, and each is
+// checked against the hash in its Content-MD5 header before it's kept. Once the last part is written, the table file
+// is checked against |contentHash| and written to the store.
+func writeTableFilePart(ctx context.Context, logger *logrus.Entry, dbCache DBCache, path, fileId string, numChunks int, contentHash []byte, contentLength, partSize uint64, req *http.Request, respWr http.ResponseWriter) (*logrus.Entry, int) {
+	var start, end, total uint64
+	if _, err := fmt.Sscanf(req.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.WithError(err).Warn("bad request: Content-Range header did not parse")
+		return logger, http.StatusBadRequest
+	}
+	if total != contentLength || end < start || end >= total || end-start+1 > partSize {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.Warnf("bad request: invalid Content-Range %d-%d/%d for part size %d", start, end, total, partSize)
+		return logger, http.StatusBadRequest
+	}
+	partHash, err := base64.StdEncoding.DecodeString(req.Header.Get("Content-MD5"))
+	if err != nil || len(partHash) != md5.Size {
+		logger = logger.WithField("status", http.StatusBadRequest)
+		logger.Warn("bad request: Content-MD5 header not provided or did not parse")
+		return logger, http.StatusBadRequest
+	}
+
+	cs, err := dbCache.Get(path, types.Format_Default.VersionString())
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to get repository")
+		return logger, http.StatusInternalServerError
+	}
+	partialPath, err := partialUploadPath(cs, fileId)
+	if err != nil {
+		logger = logger.WithField("status", http.StatusNotFound)
+		logger.WithError(err).Warn("bad request: resumable upload to a store without a path")
+		return logger, http.StatusNotFound
+	}
+
+	unlock := uploadLocks.lock(partialPath)
+	defer unlock()
+
+	offset, err := partialUploadSize(partialPath)
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to stat partial upload")
+		return logger, http.StatusInternalServerError
+	}
+	respWr.Header().Set(remotestorage.UploadOffsetHeader, strconv.FormatInt(offset, 10))
+	if uint64(offset) != start {
+		logger = logger.WithField("status", http.StatusConflict)
+		logger.Warnf("part starting at %d does not follow the %d bytes received", start, offset)
+		return logger, http.StatusConflict
+	}
+
+	status, err := appendPart(partialPath, offset, int64(end-start+1), partHash, req.Body)
+	if err != nil {
+		logger = logger.WithField("status", status)
+		logger.WithError(err).Warn("failed to write part of upload")
+		return logger, status
+	}
+	respWr.Header().Set(remotestorage.UploadOffsetHeader, strconv.FormatUint(end+1, 10))
+
+	if end+1 < total {
+		return logger, http.StatusOK
+	}
+
+	f, err := os.Open(partialPath)
+	if err != nil {
+		logger = logger.WithField("status", http.StatusInternalServerError)
+		logger.WithError(err).Error("failed to open completed upload")
+		return logger, http.StatusInternalServerError
+	}
+	logger, status = writeTableFile(ctx, logger, dbCache, path, fileId, numChunks, contentHash, contentLength, f)
+	if status == http.StatusOK || status == http.StatusBadRequest {
+		// a table file which doesn't match its details won't once it's uploaded again either
+		if err := os.Remove(partialPath); err != nil {
+			logger.WithError(err).Warn("failed to remove completed upload")
+		}
+	}
+	return logger, status
+}
+
+// appendPart appends a part of |length| bytes read from |body| to the partial upload at |path|, which is |offset|
+// bytes long. The part is discarded if it doesn't match |partHash|.
+func appendPart(path string, offset, length int64, partHash []byte, body io.Reader) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer f.Close()
+
+	discard := func(status int, err error) (int, error) {
+		if terr := f.Truncate(offset); terr != nil {
+			return http.StatusInternalServerError, terr
+		}
+		return status, err
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	h := md5.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(body, length+1))
+	if err != nil {
+		return discard(http.StatusInternalServerError, err)
+	}
+	if n != length {
+		return discard(http.StatusBadRequest, errBodyLengthTFDMismatch)
+	}
+	if !bytes.Equal(h.Sum(nil), partHash) {
+		return discard(http.StatusBadRequest, errBodyHashTFDMismatch)
+	}
+	if err := f.Sync(); err != nil {
+		return discard(http.StatusInternalServerError, err)
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesrv
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+type singletonDBCache struct {
+	cs RemoteSrvStore
+}
+
+func (c singletonDBCache) Get(path, nbfVerStr string) (RemoteSrvStore, error) {
+	return c.cs, nil
+}
+
+// failingFetcher fails every request after the first |remaining|
+type failingFetcher struct {
+	remaining int
+}
+
+func (f *failingFetcher) Do(req *http.Request) (*http.Response, error) {
+	if f.remaining == 0 {
+		return nil, errors.New("connection reset")
+	}
+	f.remaining--
+	return http.DefaultClient.Do(req)
+}
+
+func TestResumableUpload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cs, err := nbs.NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 1<<20, nbs.NewUnlimitedMemQuotaProvider())
+	require.NoError(t, err)
+	defer cs.Close()
+
+	var chnks []chunks.Chunk
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 16; i++ {
+		data := make([]byte, 512)
+		rng.Read(data)
+		chnks = append(chnks, chunks.NewChunk(data))
+	}
+	fileId, data, err := nbs.WriteChunks(chnks)
+	require.NoError(t, err)
+	contentHash := md5.Sum(data)
+
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	sealer, err := NewSingleSymmetricKeySealer()
+	require.NoError(t, err)
+	lgr := logrus.NewEntry(logrus.New())
+	dbCache := singletonDBCache{cs}
+
+	srv := httptest.NewServer(newFileHandler(lgr, dbCache, fs, false, sealer))
+	defer srv.Close()
+
+	rcs := NewHttpFSBackedChunkStore(lgr, srv.Listener.Addr().String(), dbCache, fs, "http", sealer)
+	rcs.uploadPartSize = 1024
+
+	getLoc := func(t *testing.T, resumable bool) *remotesapi.UploadLoc {
+		id := hash.Parse(fileId)
+		resp, err := rcs.GetUploadLocations(ctx, &remotesapi.GetUploadLocsRequest{
+			RepoPath: GoodRepoPath,
+			TableFileDetails: []*remotesapi.TableFileDetails{{
+				Id:            id[:],
+				ContentLength: uint64(len(data)),
+				ContentHash:   contentHash[:],
+				NumChunks:     uint64(len(chnks)),
+			}},
+			SupportsResumableUploads: resumable,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Locs, 1)
+		return resp.Locs[0]
+	}
+
+	getOffset := func(t *testing.T, url string) string {
+		req, err := http.NewRequest(http.MethodHead, url, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp.Header.Get(remotestorage.UploadOffsetHeader)
+	}
+
+	putPart := func(t *testing.T, url string, start int, part []byte, partHash []byte) int {
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(part))
+		require.NoError(t, err)
+		req.Header.Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(start+len(part)-1)+"/"+strconv.Itoa(len(data)))
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(partHash))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("ClientWithoutSupport", func(t *testing.T) {
+		loc := getLoc(t, false)
+		assert.NotNil(t, loc.GetHttpPost())
+	})
+
+	t.Run("ResumeInterruptedUpload", func(t *testing.T) {
+		loc := getLoc(t, true)
+		resumable := loc.GetHttpResumable()
+		require.NotNil(t, resumable)
+		assert.Equal(t, uint64(1024), resumable.PartSize)
+
+		// the HEAD request and three parts succeed before the connection fails
+		err := remotestorage.HttpResumableUpload(ctx, &failingFetcher{remaining: 4}, resumable, int64(len(data)), io.NopCloser(bytes.NewReader(data)))
+		require.Error(t, err)
+		assert.Equal(t, "3072", getOffset(t, resumable.Url))
+		_, err = os.Stat(filepath.Join(dir, fileId))
+		assert.True(t, os.IsNotExist(err))
+
+		// a retry gets a new location for the same upload
+		resumable = getLoc(t, true).GetHttpResumable()
+		fetcher := &failingFetcher{remaining: 1000}
+		err = remotestorage.HttpResumableUpload(ctx, fetcher, resumable, int64(len(data)), io.NopCloser(bytes.NewReader(data)))
+		require.NoError(t, err)
+		partsSent := 1000 - fetcher.remaining - 1
+		assert.Equal(t, (len(data)+1023)/1024-3, partsSent)
+
+		written, err := os.ReadFile(filepath.Join(dir, fileId))
+		require.NoError(t, err)
+		assert.Equal(t, data, written)
+		_, err = os.Stat(filepath.Join(dir, resumableUploadsDir, fileId))
+		assert.True(t, os.IsNotExist(err))
+		assert.Equal(t, "0", getOffset(t, resumable.Url))
+	})
+
+	t.Run("RejectsBadParts", func(t *testing.T) {
+		url := getLoc(t, true).GetHttpResumable().Url
+
+		part := data[:1024]
+		badHash := md5.Sum([]byte("not the part"))
+		assert.Equal(t, http.StatusBadRequest, putPart(t, url, 0, part, badHash[:]))
+		assert.Equal(t, "0", getOffset(t, url))
+
+		goodHash := md5.Sum(part)
+		assert.Equal(t, http.StatusOK, putPart(t, url, 0, part, goodHash[:]))
+		assert.Equal(t, "1024", getOffset(t, url))
+
+		// parts must be uploaded in order
+		assert.Equal(t, http.StatusConflict, putPart(t, url, 0, part, goodHash[:]))
+		skipped := data[2048:3072]
+		skippedHash := md5.Sum(skipped)
+		assert.Equal(t, http.StatusConflict, putPart(t, url, 2048, skipped, skippedHash[:]))
+
+		// parts can't be larger than the part size
+		large := data[1024:3072]
+		largeHash := md5.Sum(large)
+		assert.Equal(t, http.StatusBadRequest, putPart(t, url, 1024, large, largeHash[:]))
+		assert.Equal(t, "1024", getOffset(t, url))
+	})
+}
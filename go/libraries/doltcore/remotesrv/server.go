@@ -63,6 +63,13 @@ type ServerArgs struct {
 
 	HttpInterceptor func(http.Handler) http.Handler
 
+	// UploadPartSize is the size of the parts that clients upload large table files in, so that interrupted uploads
+	// can be resumed. Defaults to DefaultUploadPartSize.
+	UploadPartSize uint64
+
+	// PushHooks, if set, are run when a client pushes to the server. Pushes are only accepted if ReadOnly is false.
+	PushHooks *PushHooks
+
 	// If supplied, the listener(s) returned from Listeners() will be TLS
 	// listeners. The scheme used in the URLs returned from the gRPC server
 	// will be https.
@@ -91,7 +98,12 @@ func NewServer(args ServerArgs) (*Server, error) {
 	s.wg.Add(2)
 	s.grpcListenAddr = args.GrpcListenAddr
 	s.grpcSrv = grpc.NewServer(append([]grpc.ServerOption{grpc.MaxRecvMsgSize(128 * 1024 * 1024)}, args.Options...)...)
-	var chnkSt remotesapi.ChunkStoreServiceServer = NewHttpFSBackedChunkStore(args.Logger, args.HttpHost, args.DBCache, args.FS, scheme, sealer)
+	rcs := NewHttpFSBackedChunkStore(args.Logger, args.HttpHost, args.DBCache, args.FS, scheme, sealer)
+	if args.UploadPartSize > 0 {
+		rcs.uploadPartSize = args.UploadPartSize
+	}
+	rcs.pushHooks = args.PushHooks
+	var chnkSt remotesapi.ChunkStoreServiceServer = rcs
 	if args.ReadOnly {
 		chnkSt = ReadOnlyChunkStore{chnkSt}
 	}
@@ -160,6 +172,23 @@ func (s *Server) Listeners() (Listeners, error) {
 	return Listeners{http: httpListener, grpc: grpcListener}, nil
 }
 
+// ServeConns serves HTTP/2 with prior knowledge over each of |conns|, instead of accepting connections from
+// listeners. Both the gRPC service and the table file HTTP endpoints are served on every connection, so the server
+// must have been created with the same listen address for each. ServeConns returns once all of |conns| are closed.
+func (s *Server) ServeConns(conns ...net.Conn) {
+	h2s := &http2.Server{}
+	var wg sync.WaitGroup
+	for _, conn := range conns {
+		conn := conn
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h2s.ServeConn(conn, &http2.ServeConnOpts{Handler: s.httpSrv.Handler})
+		}()
+	}
+	wg.Wait()
+}
+
 func (s *Server) Serve(listeners Listeners) {
 	if listeners.grpc != nil {
 		go func() {
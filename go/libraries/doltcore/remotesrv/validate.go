@@ -155,3 +155,26 @@ func ValidateAddTableFilesRequest(req *remotesapi.AddTableFilesRequest) error {
 	}
 	return nil
 }
+
+func ValidateGetUploadedTableFilesRequest(req *remotesapi.GetUploadedTableFilesRequest) error {
+	if err := validateRepoRequest(req); err != nil {
+		return err
+	}
+	if err := validateChunkTableInfo("chunk_table_info", req.ChunkTableInfo); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateGetDeltaChunksRequest(req *remotesapi.GetDeltaChunksRequest) error {
+	if err := validateRepoRequest(req); err != nil {
+		return err
+	}
+	if len(req.ChunkHashes) != len(req.BaseHashes) {
+		return fmt.Errorf("expected as many base_hashes as chunk_hashes, got %d and %d", len(req.BaseHashes), len(req.ChunkHashes))
+	}
+	if err := validateHashes("chunk_hashes", req.ChunkHashes); err != nil {
+		return err
+	}
+	return validateHashes("base_hashes", req.BaseHashes)
+}
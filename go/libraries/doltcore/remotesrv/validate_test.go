@@ -534,3 +534,109 @@ func TestValidateAddTableFilesRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGetUploadedTableFilesRequest(t *testing.T) {
+	for i, errMsg := range []*remotesapi.GetUploadedTableFilesRequest{
+		{},
+		{
+			RepoId: &remotesapi.RepoId{
+				Org: "dolthub",
+			},
+		},
+		{
+			RepoId: &remotesapi.RepoId{
+				RepoName: "database",
+			},
+		},
+		{
+			RepoId: GoodRepoId,
+			ChunkTableInfo: []*remotesapi.ChunkTableInfo{
+				{
+					Hash: GoodHash,
+				},
+			},
+		},
+		{
+			RepoId: GoodRepoId,
+			ChunkTableInfo: []*remotesapi.ChunkTableInfo{
+				{
+					Hash:       LongHash,
+					ChunkCount: 32,
+				},
+			},
+		},
+	} {
+		t.Run(fmt.Sprintf("Error #%02d", i), func(t *testing.T) {
+			assert.Error(t, ValidateGetUploadedTableFilesRequest(errMsg), "%v should not validate", errMsg)
+		})
+	}
+	for i, msg := range []*remotesapi.GetUploadedTableFilesRequest{
+		{
+			RepoPath: GoodRepoPath,
+		},
+		{
+			RepoId: GoodRepoId,
+		},
+		{
+			RepoId: GoodRepoId,
+			ChunkTableInfo: []*remotesapi.ChunkTableInfo{
+				{
+					Hash:       GoodHash,
+					ChunkCount: 32,
+				},
+			},
+		},
+	} {
+		t.Run(fmt.Sprintf("NoError #%02d", i), func(t *testing.T) {
+			assert.NoError(t, ValidateGetUploadedTableFilesRequest(msg), "%v should validate", msg)
+		})
+	}
+}
+
+func TestValidateGetDeltaChunksRequest(t *testing.T) {
+	for i, errMsg := range []*remotesapi.GetDeltaChunksRequest{
+		{},
+		{
+			RepoId: &remotesapi.RepoId{
+				Org: "dolthub",
+			},
+		},
+		{
+			RepoPath:    GoodRepoPath,
+			ChunkHashes: [][]byte{GoodHash},
+		},
+		{
+			RepoPath:    GoodRepoPath,
+			ChunkHashes: [][]byte{GoodHash},
+			BaseHashes:  [][]byte{GoodHash, GoodHash},
+		},
+		{
+			RepoPath:    GoodRepoPath,
+			ChunkHashes: [][]byte{LongHash},
+			BaseHashes:  [][]byte{GoodHash},
+		},
+		{
+			RepoPath:    GoodRepoPath,
+			ChunkHashes: [][]byte{GoodHash},
+			BaseHashes:  [][]byte{ShortHash},
+		},
+	} {
+		t.Run(fmt.Sprintf("Error #%02d", i), func(t *testing.T) {
+			assert.Error(t, ValidateGetDeltaChunksRequest(errMsg), "%v should not validate", errMsg)
+		})
+	}
+	for i, msg := range []*remotesapi.GetDeltaChunksRequest{
+		{
+			RepoPath: GoodRepoPath,
+		},
+		{
+			RepoId:      GoodRepoId,
+			ChunkHashes: [][]byte{GoodHash},
+			BaseHashes:  [][]byte{GoodHash},
+		},
+	} {
+		t.Run(fmt.Sprintf("NoError #%02d", i), func(t *testing.T) {
+			assert.NoError(t, ValidateGetDeltaChunksRequest(msg), "%v should validate", msg)
+		})
+	}
+}
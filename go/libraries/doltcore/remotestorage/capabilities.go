@@ -0,0 +1,127 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// SupportedFormatsHeader is the gRPC metadata key with which clients advertise the storage formats they can read, as a
+// comma separated list of format version strings. Servers use it to refuse access to repositories a client can't read
+// with a descriptive error, instead of letting the client fail to decode the chunks it fetches.
+const SupportedFormatsHeader = "x-dolt-supported-formats"
+
+const upgradeUrl = "https://github.com/dolthub/dolt/releases/latest/"
+
+// SupportedFormats are the storage formats this version of Dolt can read
+var SupportedFormats = []string{constants.FormatLD1String, constants.FormatDoltString}
+
+// legacyClientFormats are the storage formats that clients which predate format negotiation, and so don't advertise
+// the formats they support, can read
+var legacyClientFormats = []string{constants.FormatLD1String, constants.FormatDoltString}
+
+// unsupportedFormatReason and errorInfoDomain identify the ErrorInfo detail of the status servers return for a
+// repository whose storage format the client can't read, which distinguishes it from other FailedPrecondition errors
+const (
+	unsupportedFormatReason = "UNSUPPORTED_STORAGE_FORMAT"
+	errorInfoDomain         = "remotesapi.dolthub.com"
+)
+
+// ErrUnsupportedRemoteFormat is returned when a remote's storage format can't be read by this version of Dolt
+var ErrUnsupportedRemoteFormat = errors.New("remote uses a storage format this version of dolt can't read")
+
+// withSupportedFormats returns a context that advertises SupportedFormats on outgoing requests
+func withSupportedFormats(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, SupportedFormatsHeader, strings.Join(SupportedFormats, ","))
+}
+
+// checkRemoteFormat returns an error if the storage format of a remote, as reported in its repo metadata, is one this
+// version of Dolt can't read. Servers that predate format negotiation won't refuse the request themselves.
+func checkRemoteFormat(host, path, nbfVersion string) error {
+	if nbfVersion == "" {
+		return nil
+	}
+	if _, err := types.GetFormatForVersionString(nbfVersion); err != nil {
+		return fmt.Errorf("%w: %s/%s uses storage format %s. Upgrade dolt to access it: %s", ErrUnsupportedRemoteFormat, host, path, nbfVersion, upgradeUrl)
+	}
+	return nil
+}
+
+// remoteFormatError converts the error returned by servers that refuse a client's advertised formats into
+// ErrUnsupportedRemoteFormat, returning any other error unchanged.
+func remoteFormatError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		return err
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == errorInfoDomain && info.Reason == unsupportedFormatReason {
+			return fmt.Errorf("%w: %s", ErrUnsupportedRemoteFormat, st.Message())
+		}
+	}
+	return err
+}
+
+// ClientSupportsFormat returns whether the client making the request in |ctx| can read the storage format given, along
+// with the formats it supports. Clients that don't advertise their formats predate format negotiation, and are
+// assumed to support only the formats that existed before it.
+func ClientSupportsFormat(ctx context.Context, nbfVersion string) (bool, []string) {
+	var vals []string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		vals = md.Get(SupportedFormatsHeader)
+	}
+
+	supported := legacyClientFormats
+	if len(vals) > 0 {
+		supported = nil
+		for _, v := range vals {
+			supported = append(supported, strings.Split(v, ",")...)
+		}
+	}
+
+	for _, f := range supported {
+		if f == nbfVersion {
+			return true, supported
+		}
+	}
+
+	return false, supported
+}
+
+// UnsupportedFormatStatus returns the error servers return for a repository whose storage format the client can't read
+func UnsupportedFormatStatus(repoPath, nbfVersion string, supported []string) error {
+	st := status.Newf(codes.FailedPrecondition, "repository %s uses storage format %s, but this client only supports %s. Upgrade dolt to access it: %s",
+		repoPath, nbfVersion, strings.Join(supported, ", "), upgradeUrl)
+	withInfo, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   unsupportedFormatReason,
+		Domain:   errorInfoDomain,
+		Metadata: map[string]string{"nbf_version": nbfVersion},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
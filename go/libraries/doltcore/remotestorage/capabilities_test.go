@@ -0,0 +1,75 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/store/constants"
+)
+
+func TestClientSupportsFormat(t *testing.T) {
+	ctx := context.Background()
+
+	// clients that don't advertise their formats are assumed to support the formats that predate negotiation
+	ok, supported := ClientSupportsFormat(ctx, constants.FormatDoltString)
+	assert.True(t, ok)
+	assert.Equal(t, legacyClientFormats, supported)
+	ok, _ = ClientSupportsFormat(ctx, "__DOLT_FUTURE__")
+	assert.False(t, ok)
+
+	// the outgoing metadata a client sends is the incoming metadata the server reads
+	md, _ := metadata.FromOutgoingContext(withSupportedFormats(ctx))
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	ok, supported = ClientSupportsFormat(ctx, constants.FormatDoltString)
+	assert.True(t, ok)
+	assert.Equal(t, SupportedFormats, supported)
+
+	ok, supported = ClientSupportsFormat(ctx, "__DOLT_FUTURE__")
+	assert.False(t, ok)
+	assert.Equal(t, SupportedFormats, supported)
+}
+
+func TestCheckRemoteFormat(t *testing.T) {
+	assert.NoError(t, checkRemoteFormat("host", "org/repo", constants.FormatDoltString))
+	assert.NoError(t, checkRemoteFormat("host", "org/repo", ""))
+
+	err := checkRemoteFormat("host", "org/repo", "__DOLT_FUTURE__")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrUnsupportedRemoteFormat))
+	assert.Contains(t, err.Error(), "__DOLT_FUTURE__")
+}
+
+func TestRemoteFormatError(t *testing.T) {
+	err := remoteFormatError(UnsupportedFormatStatus("org/repo", "__DOLT_FUTURE__", SupportedFormats))
+	assert.True(t, errors.Is(err, ErrUnsupportedRemoteFormat))
+	assert.Contains(t, err.Error(), "org/repo uses storage format __DOLT_FUTURE__")
+
+	other := errors.New("other")
+	assert.Equal(t, other, remoteFormatError(other))
+
+	// other FailedPrecondition errors, like writing in a different format than the repository's, are left as they are
+	precondition := status.Error(codes.FailedPrecondition, "repository org/repo uses storage format __DOLT__, but the client writes storage format __LD_1__")
+	assert.Equal(t, precondition, remoteFormatError(precondition))
+}
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -35,6 +36,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
@@ -62,6 +65,9 @@ var ErrUploadFailed = errors.New("upload failed")
 var globalHttpFetcher HTTPFetcher = &http.Client{}
 
 var _ chunks.TableFileStore = (*DoltChunkStore)(nil)
+var _ chunks.UploadedTableFileStore = (*DoltChunkStore)(nil)
+var _ chunks.DeltaChunkSource = (*DoltChunkStore)(nil)
+var _ chunks.DeltaTableFileStore = (*DoltChunkStore)(nil)
 var _ nbs.NBSCompressedChunkStore = (*DoltChunkStore)(nil)
 var _ chunks.ChunkStore = (*DoltChunkStore)(nil)
 var _ chunks.LoggingChunkStore = (*DoltChunkStore)(nil)
@@ -103,6 +109,11 @@ type ConcurrencyParams struct {
 	ConcurrentSmallFetches int
 	ConcurrentLargeFetches int
 	LargeFetchSize         int
+
+	// TableFileStreams is the number of concurrent range requests used to download each table file that is larger
+	// than TableFilePartSize.
+	TableFileStreams  int
+	TableFilePartSize int
 }
 
 type DoltChunkStore struct {
@@ -119,6 +130,8 @@ type DoltChunkStore struct {
 	concurrency ConcurrencyParams
 	stats       cacheStats
 	logger      chunks.DebugLogger
+	// noDeltas is set once the remote is found not to serve GetDeltaChunks
+	noDeltas atomic.Bool
 }
 
 func NewDoltChunkStoreFromPath(ctx context.Context, nbf *types.NomsBinFormat, path, host string, csClient remotesapi.ChunkStoreServiceClient) (*DoltChunkStore, error) {
@@ -135,7 +148,7 @@ func NewDoltChunkStoreFromPath(ctx context.Context, nbf *types.NomsBinFormat, pa
 		}
 	}
 
-	metadata, err := csClient.GetRepoMetadata(ctx, &remotesapi.GetRepoMetadataRequest{
+	metadata, err := csClient.GetRepoMetadata(withSupportedFormats(ctx), &remotesapi.GetRepoMetadataRequest{
 		RepoId:   repoId,
 		RepoPath: path,
 		ClientRepoFormat: &remotesapi.ClientRepoFormat{
@@ -144,6 +157,9 @@ func NewDoltChunkStoreFromPath(ctx context.Context, nbf *types.NomsBinFormat, pa
 		},
 	})
 	if err != nil {
+		return nil, remoteFormatError(err)
+	}
+	if err = checkRemoteFormat(host, path, metadata.NbfVersion); err != nil {
 		return nil, err
 	}
 
@@ -695,6 +711,50 @@ func (dcs *DoltChunkStore) readChunksAndCache(ctx context.Context, hashes hash.H
 	return eg.Wait()
 }
 
+const maxGetDeltasBatchSize = 1024
+
+// GetManyDeltas gets the chunks in |bases| as deltas against their bases. Chunks the remote doesn't send as deltas,
+// including all of them for remotes which don't serve deltas, are left to be fetched with GetManyCompressed.
+func (dcs *DoltChunkStore) GetManyDeltas(ctx context.Context, bases map[hash.Hash]hash.Hash, found func(context.Context, chunks.DeltaChunk)) error {
+	if dcs.noDeltas.Load() || len(bases) == 0 {
+		return nil
+	}
+
+	chunkSl := make([][]byte, 0, len(bases))
+	baseSl := make([][]byte, 0, len(bases))
+	for h, base := range bases {
+		h, base := h, base
+		chunkSl = append(chunkSl, h[:])
+		baseSl = append(baseSl, base[:])
+	}
+
+	var err error
+	batchItr(len(chunkSl), maxGetDeltasBatchSize, func(st, end int) (stop bool) {
+		id, token := dcs.getRepoId()
+		req := &remotesapi.GetDeltaChunksRequest{RepoId: id, RepoToken: token, RepoPath: dcs.repoPath, ChunkHashes: chunkSl[st:end], BaseHashes: baseSl[st:end]}
+		var resp *remotesapi.GetDeltaChunksResponse
+		resp, err = dcs.csClient.GetDeltaChunks(ctx, req)
+		if status.Code(err) == codes.Unimplemented {
+			dcs.noDeltas.Store(true)
+			err = nil
+			return true
+		} else if err != nil {
+			err = NewRpcError(err, "GetDeltaChunks", dcs.host, req)
+			return true
+		}
+
+		if resp.RepoToken != "" {
+			dcs.repoToken.Store(resp.RepoToken)
+		}
+
+		for _, dc := range resp.Chunks {
+			found(ctx, chunks.DeltaChunk{H: hash.New(dc.Hash), Base: hash.New(dc.BaseHash), Delta: dc.Delta})
+		}
+		return false
+	})
+	return err
+}
+
 // Returns true iff the value at the address |h| is contained in the
 // store
 func (dcs *DoltChunkStore) Has(ctx context.Context, h hash.Hash) (bool, error) {
@@ -847,10 +907,16 @@ func (dcs *DoltChunkStore) refreshRepoMetadata(ctx context.Context) error {
 			NbsVersion: nbs.StorageVersion,
 		},
 	}
-	metadata, err := dcs.csClient.GetRepoMetadata(ctx, mdReq)
+	metadata, err := dcs.csClient.GetRepoMetadata(withSupportedFormats(ctx), mdReq)
 	if err != nil {
+		if ferr := remoteFormatError(err); ferr != err {
+			return ferr
+		}
 		return NewRpcError(err, "GetRepoMetadata", dcs.host, mdReq)
 	}
+	if err = checkRemoteFormat(dcs.host, dcs.repoPath, metadata.NbfVersion); err != nil {
+		return err
+	}
 	if metadata.RepoToken != "" {
 		dcs.repoToken.Store(metadata.RepoToken)
 	}
@@ -983,7 +1049,7 @@ func (dcs *DoltChunkStore) uploadChunks(ctx context.Context) (map[hash.Hash]int,
 		err := dcs.uploadTableFileWithRetries(ctx, h, uint64(hashToCount[h]), contentHash, func() (io.ReadCloser, uint64, error) {
 			data := hashToData[h]
 			return io.NopCloser(bytes.NewReader(data)), uint64(len(data)), nil
-		})
+		}, nil)
 		if err != nil {
 			return map[hash.Hash]int{}, err
 		}
@@ -992,7 +1058,9 @@ func (dcs *DoltChunkStore) uploadChunks(ctx context.Context) (map[hash.Hash]int,
 	return hashToCount, nil
 }
 
-func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, tableFileId hash.Hash, numChunks uint64, tableFileContentHash []byte, getContent func() (io.ReadCloser, uint64, error)) error {
+// uploadTableFileWithRetries uploads a table file, read with |getContent|. If |getPackContent| is non-nil, it reads a
+// delta pack of the table file, which is uploaded instead when the remote accepts delta packs.
+func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, tableFileId hash.Hash, numChunks uint64, tableFileContentHash []byte, getContent, getPackContent func() (io.ReadCloser, uint64, error)) error {
 	op := func() error {
 		body, contentLength, err := getContent()
 		if err != nil {
@@ -1008,7 +1076,7 @@ func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, table
 
 		dcs.logf("getting upload location for file %s", tableFileId.String())
 		id, token := dcs.getRepoId()
-		req := &remotesapi.GetUploadLocsRequest{RepoId: id, RepoToken: token, RepoPath: dcs.repoPath, TableFileDetails: []*remotesapi.TableFileDetails{tbfd}}
+		req := &remotesapi.GetUploadLocsRequest{RepoId: id, RepoToken: token, RepoPath: dcs.repoPath, TableFileDetails: []*remotesapi.TableFileDetails{tbfd}, SupportsResumableUploads: true, SupportsDeltaPacks: getPackContent != nil}
 		resp, err := dcs.csClient.GetUploadLocations(ctx, req)
 		if err != nil {
 			if err != nil {
@@ -1025,6 +1093,16 @@ func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, table
 		}
 		loc := resp.Locs[0]
 
+		if loc.DeltaPack != nil && getPackContent != nil {
+			err = dcs.uploadDeltaPack(ctx, tableFileId, loc.DeltaPack, tableFileContentHash, getPackContent)
+			if err == nil {
+				_ = body.Close()
+				return nil
+			}
+			// the table file is uploaded in full if its delta pack isn't accepted
+			dcs.logf("failed to upload delta pack of file %s, err: %v", tableFileId.String(), err)
+		}
+
 		switch typedLoc := loc.Location.(type) {
 		case *remotesapi.UploadLoc_HttpPost:
 
@@ -1044,6 +1122,20 @@ func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, table
 				return err
 			}
 			dcs.logf("successfully uploaded file %s to %s", tableFileId.String(), urlStr)
+		case *remotesapi.UploadLoc_HttpResumable:
+			urlStr := typedLoc.HttpResumable.Url
+			qmIdx := strings.IndexRune(urlStr, '?')
+			if qmIdx != -1 {
+				urlStr = urlStr[:qmIdx]
+			}
+
+			dcs.logf("uploading file %s to %s in parts of %d bytes", tableFileId.String(), urlStr, typedLoc.HttpResumable.PartSize)
+			err = HttpResumableUpload(ctx, dcs.httpFetcher, typedLoc.HttpResumable, int64(contentLength), body)
+			if err != nil {
+				dcs.logf("failed to upload file %s to %s, err: %v", tableFileId.String(), urlStr, err)
+				return err
+			}
+			dcs.logf("successfully uploaded file %s to %s", tableFileId.String(), urlStr)
 		default:
 			break
 		}
@@ -1054,6 +1146,20 @@ func (dcs *DoltChunkStore) uploadTableFileWithRetries(ctx context.Context, table
 	return backoff.Retry(op, uploadBackOff(ctx))
 }
 
+func (dcs *DoltChunkStore) uploadDeltaPack(ctx context.Context, tableFileId hash.Hash, post *remotesapi.HttpPostTableFile, tableFileContentHash []byte, getPackContent func() (io.ReadCloser, uint64, error)) error {
+	pack, packLength, err := getPackContent()
+	if err != nil {
+		return err
+	}
+	dcs.logf("uploading delta pack of file %s, %d bytes", tableFileId.String(), packLength)
+	err = dcs.httpPostUpload(ctx, post, tableFileContentHash, int64(packLength), pack)
+	if err != nil {
+		return err
+	}
+	dcs.logf("successfully uploaded delta pack of file %s", tableFileId.String())
+	return nil
+}
+
 type Sizer interface {
 	Size() int64
 }
@@ -1091,6 +1197,89 @@ func HttpPostUpload(ctx context.Context, httpFetcher HTTPFetcher, post *remotesa
 	return processHttpResp(resp, err)
 }
 
+// UploadOffsetHeader is the header in which servers report the number of bytes of a resumable upload they've received
+const UploadOffsetHeader = "Upload-Offset"
+
+// HttpResumableUpload uploads a table file to a resumable upload location. The upload continues from the number of
+// bytes the server reports it has received, so an upload which is retried after a failure doesn't send the parts
+// that were received again. Each part is sent with its hash, which the server checks before keeping it.
+func HttpResumableUpload(ctx context.Context, httpFetcher HTTPFetcher, loc *remotesapi.HttpResumableTableFile, contentLength int64, body io.ReadCloser) error {
+	defer body.Close()
+
+	fetcher := globalHttpFetcher
+	if httpFetcher != nil {
+		fetcher = httpFetcher
+	}
+
+	partSize := int64(loc.PartSize)
+	if partSize <= 0 {
+		return backoff.Permanent(fmt.Errorf("invalid upload part size %d", loc.PartSize))
+	}
+
+	offset, err := resumableUploadOffset(ctx, fetcher, loc.Url)
+	if err != nil {
+		return err
+	}
+	if offset > contentLength {
+		return fmt.Errorf("server reported receiving %d bytes of a %d byte upload", offset, contentLength)
+	}
+	if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+		return err
+	}
+
+	buf := make([]byte, partSize)
+	for offset < contentLength {
+		part := buf
+		if rem := contentLength - offset; rem < partSize {
+			part = buf[:rem]
+		}
+		if _, err := io.ReadFull(body, part); err != nil {
+			return err
+		}
+		sum := md5.Sum(part)
+
+		req, err := http.NewRequest(http.MethodPut, loc.Url, bytes.NewReader(part))
+		if err != nil {
+			return err
+		}
+		req.ContentLength = int64(len(part))
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(part))-1, contentLength))
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+
+		resp, err := fetcher.Do(req.WithContext(ctx))
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		if err := processHttpResp(resp, err); err != nil {
+			return err
+		}
+		offset += int64(len(part))
+	}
+
+	return nil
+}
+
+// resumableUploadOffset returns the number of bytes of the resumable upload at |url| that the server has received
+func resumableUploadOffset(ctx context.Context, fetcher HTTPFetcher, url string) (int64, error) {
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := fetcher.Do(req.WithContext(ctx))
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+	if err := processHttpResp(resp, err); err != nil {
+		return 0, err
+	}
+
+	offset, err := strconv.ParseInt(resp.Header.Get(UploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid %s header in response to resumable upload: '%s'", UploadOffsetHeader, resp.Header.Get(UploadOffsetHeader))
+	}
+	return offset, nil
+}
+
 // aggregateDownloads looks for byte ranges that need to be downloaded, and tries to aggregate them into a smaller number
 // of larger downloads.  It does this by sorting the ranges of bytes that are needed, and then comparing how close together
 // neighboring ranges are.  If they are within the threshold the two ranges will be aggregated into a single request for
@@ -1113,6 +1302,8 @@ var defaultConcurrency ConcurrencyParams = ConcurrencyParams{
 	ConcurrentSmallFetches: 64,
 	ConcurrentLargeFetches: 2,
 	LargeFetchSize:         2 * 1024 * 1024,
+	TableFileStreams:       4,
+	TableFilePartSize:      32 * 1024 * 1024,
 }
 
 func logDownloadStats(span trace.Span, originalGets map[string]*GetRange, computedGets []*GetRange) {
@@ -1287,13 +1478,20 @@ func (dcs *DoltChunkStore) SupportedOperations() chunks.TableFileStoreOps {
 // WriteTableFile reads a table file from the provided reader and writes it to the chunk store.
 func (dcs *DoltChunkStore) WriteTableFile(ctx context.Context, fileId string, numChunks int, contentHash []byte, getRd func() (io.ReadCloser, uint64, error)) error {
 	fileIdBytes := hash.Parse(fileId)
-	err := dcs.uploadTableFileWithRetries(ctx, fileIdBytes, uint64(numChunks), contentHash, getRd)
+	err := dcs.uploadTableFileWithRetries(ctx, fileIdBytes, uint64(numChunks), contentHash, getRd, nil)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// WriteTableFileWithDeltaPack writes a table file to the chunk store, uploading the delta pack read with |getPackRd|
+// instead of the table file if the remote accepts delta packs.
+func (dcs *DoltChunkStore) WriteTableFileWithDeltaPack(ctx context.Context, fileId string, numChunks int, contentHash []byte, getRd, getPackRd func() (io.ReadCloser, uint64, error)) error {
+	fileIdBytes := hash.Parse(fileId)
+	return dcs.uploadTableFileWithRetries(ctx, fileIdBytes, uint64(numChunks), contentHash, getRd, getPackRd)
+}
+
 // AddTableFilesToManifest adds table files to the manifest
 func (dcs *DoltChunkStore) AddTableFilesToManifest(ctx context.Context, fileIdToNumChunks map[string]int) error {
 	chnkTblInfo := make([]*remotesapi.ChunkTableInfo, 0, len(fileIdToNumChunks))
@@ -1334,6 +1532,41 @@ func (dcs *DoltChunkStore) AddTableFilesToManifest(ctx context.Context, fileIdTo
 	return nil
 }
 
+// HasUploadedTableFiles returns the table files in |fileIdToNumChunks| which were uploaded to the remote and can still
+// be added to its manifest. Remotes which can't report on uploaded table files are treated as having none of them.
+func (dcs *DoltChunkStore) HasUploadedTableFiles(ctx context.Context, fileIdToNumChunks map[string]int) (map[string]int, error) {
+	chnkTblInfo := make([]*remotesapi.ChunkTableInfo, 0, len(fileIdToNumChunks))
+	for fileId, numChunks := range fileIdToNumChunks {
+		fileIdBytes := hash.Parse(fileId)
+		chnkTblInfo = append(chnkTblInfo, &remotesapi.ChunkTableInfo{Hash: fileIdBytes[:], ChunkCount: uint32(numChunks)})
+	}
+
+	id, token := dcs.getRepoId()
+	req := &remotesapi.GetUploadedTableFilesRequest{
+		RepoId:         id,
+		RepoToken:      token,
+		RepoPath:       dcs.repoPath,
+		ChunkTableInfo: chnkTblInfo,
+	}
+
+	resp, err := dcs.csClient.GetUploadedTableFiles(ctx, req)
+	if status.Code(err) == codes.Unimplemented {
+		return map[string]int{}, nil
+	} else if err != nil {
+		return nil, NewRpcError(err, "GetUploadedTableFiles", dcs.host, req)
+	}
+
+	if resp.RepoToken != "" {
+		dcs.repoToken.Store(resp.RepoToken)
+	}
+
+	uploaded := make(map[string]int, len(resp.ChunkTableInfo))
+	for _, cti := range resp.ChunkTableInfo {
+		uploaded[hash.New(cti.Hash).String()] = int(cti.ChunkCount)
+	}
+	return uploaded, nil
+}
+
 // PruneTableFiles deletes old table files that are no longer referenced in the manifest.
 func (dcs *DoltChunkStore) PruneTableFiles(ctx context.Context) error {
 	return chunks.ErrUnsupportedOperation
@@ -1404,7 +1637,8 @@ func sanitizeSignedUrl(url string) string {
 	}
 }
 
-// Open returns an io.ReadCloser which can be used to read the bytes of a table file.
+// Open returns an io.ReadCloser which can be used to read the bytes of a table file. Large table files are downloaded
+// over multiple concurrent range requests when the server supports them.
 func (drtf DoltRemoteTableFile) Open(ctx context.Context) (io.ReadCloser, uint64, error) {
 	if drtf.info.RefreshAfter != nil && drtf.info.RefreshAfter.AsTime().After(time.Now()) {
 		resp, err := drtf.dcs.csClient.RefreshTableFileUrl(ctx, drtf.info.RefreshRequest)
@@ -1414,7 +1648,8 @@ func (drtf DoltRemoteTableFile) Open(ctx context.Context) (io.ReadCloser, uint64
 		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, drtf.info.Url, nil)
+	urlStr := drtf.info.Url
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1428,8 +1663,32 @@ func (drtf DoltRemoteTableFile) Open(ctx context.Context) (io.ReadCloser, uint64
 		defer resp.Body.Close()
 		body := make([]byte, 4096)
 		n, _ := io.ReadFull(resp.Body, body)
-		return nil, 0, fmt.Errorf("%w: status code: %d;\nurl: %s\n\nbody:\n\n%s\n", ErrRemoteTableFileGet, resp.StatusCode, sanitizeSignedUrl(drtf.info.Url), string(body[0:n]))
+		return nil, 0, fmt.Errorf("%w: status code: %d;\nurl: %s\n\nbody:\n\n%s\n", ErrRemoteTableFileGet, resp.StatusCode, sanitizeSignedUrl(urlStr), string(body[0:n]))
 	}
 
-	return resp.Body, uint64(resp.ContentLength), nil
+	size := uint64(resp.ContentLength)
+	params := drtf.dcs.concurrency
+	partSize := uint64(params.TableFilePartSize)
+	if resp.ContentLength > 0 && resp.Header.Get("Accept-Ranges") == "bytes" && params.TableFileStreams > 0 && partSize > 0 && size > partSize {
+		return newTableFileStream(ctx, drtf.dcs.httpFetcher, resp.Body, size, partSize, params.TableFileStreams, drtf.urlFactory(ctx, urlStr)), size, nil
+	}
+
+	return resp.Body, size, nil
+}
+
+// urlFactory returns a urlFactoryFunc for the range requests of a table file download, which refreshes the url of
+// the table file after a request fails, in case the url has expired.
+func (drtf DoltRemoteTableFile) urlFactory(ctx context.Context, urlStr string) urlFactoryFunc {
+	var mu sync.Mutex
+	return func(lastError error) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if lastError != nil && drtf.info.RefreshRequest != nil {
+			resp, err := drtf.dcs.csClient.RefreshTableFileUrl(ctx, drtf.info.RefreshRequest)
+			if err == nil {
+				urlStr = resp.Url
+			}
+		}
+		return urlStr, nil
+	}
 }
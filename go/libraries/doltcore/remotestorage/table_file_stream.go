@@ -0,0 +1,181 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"golang.org/x/sync/semaphore"
+)
+
+type tableFilePart struct {
+	data []byte
+	err  error
+}
+
+// tableFileStream is an io.ReadCloser over a table file that is downloaded as a sequence of parts over multiple
+// concurrent HTTP streams. The first part is read from the body of the GET for the whole file, so that downloading a
+// file doesn't cost an extra round trip. The remaining parts are fetched with range requests, which are retried and
+// resumed from where they left off when they fail, and are read in order as they complete. At most |streams| parts
+// are downloaded at once, and only parts that are being downloaded or have yet to be read are held in memory.
+type tableFileStream struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stats   StatsRecorder
+	fetcher HTTPFetcher
+	urlF    urlFactoryFunc
+
+	size     uint64
+	partSize uint64
+	first    io.ReadCloser
+	results  []chan tableFilePart
+	sem      *semaphore.Weighted
+
+	// pos is the offset in the file of the next byte returned by Read
+	pos  uint64
+	buf  []byte
+	next int
+	err  error
+}
+
+var _ io.ReadCloser = (*tableFileStream)(nil)
+
+// newTableFileStream returns a tableFileStream for a file of |size| bytes. |first| is the body of a response to a GET
+// for the whole file.
+func newTableFileStream(ctx context.Context, fetcher HTTPFetcher, first io.ReadCloser, size, partSize uint64, streams int, urlF urlFactoryFunc) *tableFileStream {
+	ctx, cancel := context.WithCancel(ctx)
+	numParts := int((size + partSize - 1) / partSize)
+	s := &tableFileStream{
+		ctx:      ctx,
+		cancel:   cancel,
+		stats:    StatsFactory(),
+		fetcher:  fetcher,
+		urlF:     urlF,
+		size:     size,
+		partSize: partSize,
+		first:    first,
+		results:  make([]chan tableFilePart, numParts),
+		sem:      semaphore.NewWeighted(int64(streams)),
+		next:     1,
+	}
+	for i := range s.results {
+		s.results[i] = make(chan tableFilePart, 1)
+	}
+
+	go s.fetchParts()
+	return s
+}
+
+// fetchParts starts the download of each part after the first, in order, as download streams become available
+func (s *tableFileStream) fetchParts() {
+	for i := 1; i < len(s.results); i++ {
+		if err := s.sem.Acquire(s.ctx, 1); err != nil {
+			s.results[i] <- tableFilePart{err: err}
+			return
+		}
+
+		offset := uint64(i) * s.partSize
+		length := s.partSize
+		if offset+length > s.size {
+			length = s.size - offset
+		}
+
+		res := s.results[i]
+		go func() {
+			data, err := rangeDownloadWithRetries(s.ctx, s.stats, s.fetcher, offset, length, 0, s.urlF)
+			res <- tableFilePart{data: data, err: err}
+		}()
+	}
+}
+
+// Read reads the next bytes of the table file
+func (s *tableFileStream) Read(p []byte) (int, error) {
+	if s.first != nil {
+		return s.readFirst(p)
+	}
+
+	for len(s.buf) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if s.pos == s.size {
+			return 0, io.EOF
+		}
+
+		var part tableFilePart
+		select {
+		case part = <-s.results[s.next]:
+			s.sem.Release(1)
+			s.next++
+		case <-s.ctx.Done():
+			part.err = s.ctx.Err()
+		}
+
+		if part.err != nil {
+			s.err = part.err
+		} else {
+			s.buf = part.data
+		}
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	s.pos += uint64(n)
+	return n, nil
+}
+
+// readFirst reads from the body of the whole file GET until the end of the first part. If reading the body fails, the
+// rest of the first part is fetched with a range request instead.
+func (s *tableFileStream) readFirst(p []byte) (int, error) {
+	if rem := s.partSize - s.pos; uint64(len(p)) > rem {
+		p = p[:rem]
+	}
+
+	n, err := s.first.Read(p)
+	s.pos += uint64(n)
+
+	if s.pos == s.partSize {
+		s.closeFirst()
+	} else if err != nil {
+		s.closeFirst()
+		if errors.Is(err, context.Canceled) {
+			s.err = err
+		} else {
+			s.buf, s.err = rangeDownloadWithRetries(s.ctx, s.stats, s.fetcher, s.pos, s.partSize-s.pos, 0, s.urlF)
+		}
+		if n == 0 {
+			return s.Read(p)
+		}
+	}
+
+	return n, nil
+}
+
+func (s *tableFileStream) closeFirst() {
+	_ = s.first.Close()
+	s.first = nil
+}
+
+// Close stops any downloads in progress
+func (s *tableFileStream) Close() error {
+	s.cancel()
+	if s.first != nil {
+		s.closeFirst()
+	}
+	StatsFlusher(s.stats)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeFetcher is an HTTPFetcher that serves range requests for |data|, failing the first |failures| requests
+type rangeFetcher struct {
+	data []byte
+
+	mu       sync.Mutex
+	failures int
+	requests int
+}
+
+func (f *rangeFetcher) Do(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.requests++
+	fail := f.failures > 0
+	if fail {
+		f.failures--
+	}
+	f.mu.Unlock()
+
+	if fail {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(&bytes.Buffer{})}, nil
+	}
+
+	var start, end int
+	if _, err := fmt.Sscanf(req.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusPartialContent,
+		Body:       io.NopCloser(bytes.NewReader(f.data[start : end+1])),
+	}, nil
+}
+
+// failingReader returns the first |n| bytes of |data| and then fails
+type failingReader struct {
+	data []byte
+	n    int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, errors.New("connection reset")
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	r.n -= n
+	return n, nil
+}
+
+func TestTableFileStream(t *testing.T) {
+	data := make([]byte, 10*1024+17)
+	rand.Read(data)
+	urlF := func(error) (string, error) { return "http://localhost/table-file", nil }
+
+	tests := []struct {
+		name     string
+		first    io.Reader
+		failures int
+	}{
+		{name: "whole file", first: bytes.NewReader(data)},
+		{name: "range requests fail", first: bytes.NewReader(data), failures: 3},
+		{name: "first part fails", first: &failingReader{data: data, n: 100}},
+		{name: "first part fails immediately", first: &failingReader{data: data}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fetcher := &rangeFetcher{data: data, failures: test.failures}
+			s := newTableFileStream(context.Background(), fetcher, io.NopCloser(test.first), uint64(len(data)), 1024, 3, urlF)
+
+			read, err := io.ReadAll(s)
+			require.NoError(t, err)
+			require.NoError(t, s.Close())
+			assert.True(t, bytes.Equal(data, read))
+			assert.GreaterOrEqual(t, fetcher.requests, 10)
+		})
+	}
+}
+
+func TestTableFileStreamClose(t *testing.T) {
+	data := make([]byte, 64*1024)
+	fetcher := &rangeFetcher{data: data}
+	urlF := func(error) (string, error) { return "http://localhost/table-file", nil }
+
+	s := newTableFileStream(context.Background(), fetcher, io.NopCloser(bytes.NewReader(data)), uint64(len(data)), 1024, 2, urlF)
+	buf := make([]byte, 10)
+	_, err := s.Read(buf)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	_, err = io.ReadAll(s)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
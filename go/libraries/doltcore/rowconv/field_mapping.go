@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
@@ -156,26 +155,15 @@ func NameMapping(srcSch, destSch schema.Schema, nameMapper NameMapper) (*FieldMa
 	return NewFieldMapping(srcSch, destSch, srcToDest)
 }
 
-// NameMapperFromFile reads a JSON file containing a name mapping and returns a NameMapper.
+// NameMapperFromFile reads a JSON mapping file and returns a NameMapper for the columns it renames. See MappingSpec
+// for the supported formats.
 func NameMapperFromFile(mappingFile string, FS filesys.ReadableFS) (NameMapper, error) {
-	var nm NameMapper
-
-	if mappingFile == "" {
-		// identity mapper
-		return make(NameMapper), nil
-	}
-
-	if fileExists, _ := FS.Exists(mappingFile); !fileExists {
-		return nil, errhand.BuildDError("error: '%s' does not exist.", mappingFile).Build()
-	}
-
-	err := filesys.UnmarshalJSONFile(FS, mappingFile, &nm)
-
+	spec, err := MappingSpecFromFile(mappingFile, FS)
 	if err != nil {
-		return nil, errhand.BuildDError(ErrMappingFileRead.Error()).AddCause(err).Build()
+		return nil, err
 	}
 
-	return nm, nil
+	return spec.NameMapper(), nil
 }
 
 // TagMappingByTagAndName takes a source schema and a destination schema and maps
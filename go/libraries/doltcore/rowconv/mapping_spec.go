@@ -0,0 +1,299 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowconv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// ErrInvalidMappingSpec is returned when a mapping file is well-formed JSON but describes an invalid mapping
+var ErrInvalidMappingSpec = errors.New("invalid mapping file")
+
+const (
+	TransformUpper = "upper"
+	TransformLower = "lower"
+	TransformTrim  = "trim"
+)
+
+// Transforms are the functions that can be applied to string values by a ColumnSpec
+var Transforms = map[string]func(string) string{
+	TransformUpper: strings.ToUpper,
+	TransformLower: strings.ToLower,
+	TransformTrim:  strings.TrimSpace,
+}
+
+var templateRefRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// ColumnSpec describes how a single column of the input data is imported
+type ColumnSpec struct {
+	// Name is the name of the column the input column is written to. Defaults to the input column's name.
+	Name string `json:"name,omitempty"`
+	// Type is a SQL column type the input values are cast to. When a table is created, it is the type of the column.
+	Type string `json:"type,omitempty"`
+	// Transform is the name of a function in Transforms that is applied to string values.
+	Transform string `json:"transform,omitempty"`
+	// Skip causes the input column to not be imported.
+	Skip bool `json:"skip,omitempty"`
+}
+
+// UnmarshalJSON allows a ColumnSpec to be written as a string, which is shorthand for a rename.
+func (cs *ColumnSpec) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*cs = ColumnSpec{Name: name}
+		return nil
+	}
+
+	type columnSpec ColumnSpec
+	var spec columnSpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return err
+	}
+	*cs = ColumnSpec(spec)
+	return nil
+}
+
+// MappingSpec describes how the columns of a file being imported map to the columns of a table. Mapping files are
+// either a JSON object mapping input column names to output column names, or a JSON object of the form:
+//
+//	{
+//	  "columns": {
+//	    "first_name": "first",
+//	    "id": {"name": "pk", "type": "bigint"},
+//	    "state": {"transform": "upper"},
+//	    "notes": {"skip": true}
+//	  },
+//	  "defaults": {"source": "crm-export"},
+//	  "expressions": {"full_name": "${first_name} ${last_name}"}
+//	}
+//
+// |defaults| sets output columns to a constant, and |expressions| sets output columns to a template in which ${col}
+// is replaced with the value of the input column |col|.
+type MappingSpec struct {
+	Columns     map[string]ColumnSpec `json:"columns"`
+	Defaults    map[string]string     `json:"defaults,omitempty"`
+	Expressions map[string]string     `json:"expressions,omitempty"`
+
+	// structured is true for specs read from the "columns" format, which are always validated
+	structured bool
+}
+
+// NameMapper returns a NameMapper for the columns renamed by this spec
+func (spec *MappingSpec) NameMapper() NameMapper {
+	nm := make(NameMapper)
+	for src, cs := range spec.Columns {
+		if !cs.Skip && cs.Name != "" {
+			nm[src] = cs.Name
+		}
+	}
+	return nm
+}
+
+// IsNameMappingOnly returns whether this spec was read from a file that maps input column names to output column
+// names, in which case it can be applied with a NameMapper.
+func (spec *MappingSpec) IsNameMappingOnly() bool {
+	if spec.structured || len(spec.Defaults) > 0 || len(spec.Expressions) > 0 {
+		return false
+	}
+	for _, cs := range spec.Columns {
+		if cs.Skip || cs.Type != "" || cs.Transform != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// OutputName returns the name of the output column that the input column |srcName| is written to, and false if the
+// input column is skipped.
+func (spec *MappingSpec) OutputName(srcName string) (string, bool) {
+	cs := spec.Columns[srcName]
+	if cs.Skip {
+		return "", false
+	}
+	if cs.Name != "" {
+		return cs.Name, true
+	}
+	return srcName, true
+}
+
+// ColumnType returns the SQL type of the input column |srcName|, if one is given
+func (spec *MappingSpec) ColumnType(ctx context.Context, srcName string) (sql.Type, bool, error) {
+	cs := spec.Columns[srcName]
+	if cs.Type == "" {
+		return nil, false, nil
+	}
+	t, err := parse.ParseColumnTypeString(sql.NewContext(ctx), cs.Type)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: column '%s' has invalid type '%s': %s", ErrInvalidMappingSpec, srcName, cs.Type, err.Error())
+	}
+	return t, true, nil
+}
+
+// TemplateRefs returns the input columns referenced by |template|
+func TemplateRefs(template string) []string {
+	var refs []string
+	for _, m := range templateRefRegex.FindAllStringSubmatch(template, -1) {
+		refs = append(refs, m[1])
+	}
+	return refs
+}
+
+// ExpandTemplate replaces each ${col} in |template| with the result of |lookup| for that column
+func ExpandTemplate(template string, lookup func(col string) string) string {
+	return templateRefRegex.ReplaceAllStringFunc(template, func(ref string) string {
+		return lookup(ref[2 : len(ref)-1])
+	})
+}
+
+// Validate checks that this spec can be applied to input data with the schema |srcSch|
+func (spec *MappingSpec) Validate(ctx context.Context, srcSch schema.Schema) error {
+	srcCols := srcSch.GetAllCols()
+	outputs := make(map[string]string)
+	addOutput := func(name, source string) error {
+		if name == "" {
+			return fmt.Errorf("%w: %s has an empty output column name", ErrInvalidMappingSpec, source)
+		}
+		key := strings.ToLower(name)
+		if other, ok := outputs[key]; ok {
+			return fmt.Errorf("%w: both %s and %s are written to column '%s'", ErrInvalidMappingSpec, other, source, name)
+		}
+		outputs[key] = source
+		return nil
+	}
+
+	for _, src := range sortedKeys(spec.Columns) {
+		cs := spec.Columns[src]
+		if _, ok := srcCols.GetByName(src); !ok {
+			return fmt.Errorf("%w: column '%s' is not in the input data", ErrInvalidMappingSpec, src)
+		}
+		if cs.Skip && (cs.Name != "" || cs.Type != "" || cs.Transform != "") {
+			return fmt.Errorf("%w: skipped column '%s' cannot have a name, type or transform", ErrInvalidMappingSpec, src)
+		}
+		if _, ok := Transforms[cs.Transform]; cs.Transform != "" && !ok {
+			return fmt.Errorf("%w: unknown transform '%s' for column '%s'", ErrInvalidMappingSpec, cs.Transform, src)
+		}
+		if _, _, err := spec.ColumnType(ctx, src); err != nil {
+			return err
+		}
+	}
+
+	err := srcCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if name, ok := spec.OutputName(col.Name); ok {
+			return false, addOutput(name, fmt.Sprintf("input column '%s'", col.Name))
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(spec.Defaults) {
+		if err := addOutput(name, fmt.Sprintf("default '%s'", name)); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(spec.Expressions) {
+		if err := addOutput(name, fmt.Sprintf("expression '%s'", name)); err != nil {
+			return err
+		}
+		for _, ref := range TemplateRefs(spec.Expressions[name]) {
+			if _, ok := srcCols.GetByName(ref); !ok {
+				return fmt.Errorf("%w: expression '%s' references column '%s', which is not in the input data", ErrInvalidMappingSpec, name, ref)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MappingSpecFromFile reads a mapping file in either of the formats described by MappingSpec. An empty |mappingFile|
+// returns an empty spec.
+func MappingSpecFromFile(mappingFile string, FS filesys.ReadableFS) (*MappingSpec, error) {
+	if mappingFile == "" {
+		return &MappingSpec{Columns: make(map[string]ColumnSpec)}, nil
+	}
+
+	if fileExists, _ := FS.Exists(mappingFile); !fileExists {
+		return nil, errhand.BuildDError("error: '%s' does not exist.", mappingFile).Build()
+	}
+
+	data, err := FS.ReadFile(mappingFile)
+	if err != nil {
+		return nil, errhand.BuildDError(ErrMappingFileRead.Error()).AddCause(err).Build()
+	}
+
+	spec, err := parseMappingSpec(data)
+	if err != nil {
+		return nil, errhand.BuildDError(ErrMappingFileRead.Error()).AddCause(err).Build()
+	}
+	return spec, nil
+}
+
+func parseMappingSpec(data []byte) (*MappingSpec, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	if cols, ok := fields["columns"]; !ok || !bytes.HasPrefix(bytes.TrimSpace(cols), []byte("{")) {
+		var nm NameMapper
+		if err := json.Unmarshal(data, &nm); err != nil {
+			return nil, err
+		}
+		spec := &MappingSpec{Columns: make(map[string]ColumnSpec, len(nm))}
+		for src, dest := range nm {
+			spec.Columns[src] = ColumnSpec{Name: dest}
+		}
+		return spec, nil
+	}
+
+	var spec MappingSpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return nil, err
+	}
+	if spec.Columns == nil {
+		spec.Columns = make(map[string]ColumnSpec)
+	}
+	spec.structured = true
+	return &spec, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowconv
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestMappingSpecFromFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		mappingJSON  string
+		expectErr    bool
+		nameOnly     bool
+		expectedCols map[string]ColumnSpec
+	}{
+		{
+			name:         "name mapping",
+			mappingJSON:  `{"a": "key", "b": "value"}`,
+			nameOnly:     true,
+			expectedCols: map[string]ColumnSpec{"a": {Name: "key"}, "b": {Name: "value"}},
+		},
+		{
+			name:         "name mapping with a column named columns",
+			mappingJSON:  `{"columns": "cols"}`,
+			nameOnly:     true,
+			expectedCols: map[string]ColumnSpec{"columns": {Name: "cols"}},
+		},
+		{
+			name:        "spec",
+			mappingJSON: `{"columns": {"a": "key", "b": {"name": "value", "type": "int"}, "c": {"skip": true}}}`,
+			expectedCols: map[string]ColumnSpec{
+				"a": {Name: "key"},
+				"b": {Name: "value", Type: "int"},
+				"c": {Skip: true},
+			},
+		},
+		{
+			name:        "unknown column field",
+			mappingJSON: `{"columns": {"a": {"rename": "key"}}}`,
+			expectErr:   true,
+		},
+		{
+			name:        "unknown top level field",
+			mappingJSON: `{"columns": {}, "constants": {}}`,
+			expectErr:   true,
+		},
+		{
+			name:        "invalid json",
+			mappingJSON: `{"columns": }`,
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := filesys.NewInMemFS([]string{"/"}, nil, "/")
+			require.NoError(t, fs.WriteFile("mapping.json", []byte(test.mappingJSON)))
+
+			spec, err := MappingSpecFromFile("mapping.json", fs)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedCols, spec.Columns)
+			assert.Equal(t, test.nameOnly, spec.IsNameMappingOnly())
+		})
+	}
+}
+
+func TestMappingSpecValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      MappingSpec
+		expectErr string
+	}{
+		{
+			name: "valid",
+			spec: MappingSpec{
+				Columns:     map[string]ColumnSpec{"a": {Name: "key", Type: "varchar(10)"}, "b": {Transform: TransformUpper}, "c": {Skip: true}},
+				Defaults:    map[string]string{"d": "constant"},
+				Expressions: map[string]string{"e": "${a}-${c}"},
+			},
+		},
+		{
+			name:      "unknown input column",
+			spec:      MappingSpec{Columns: map[string]ColumnSpec{"z": {Name: "key"}}},
+			expectErr: "column 'z' is not in the input data",
+		},
+		{
+			name:      "unknown transform",
+			spec:      MappingSpec{Columns: map[string]ColumnSpec{"a": {Transform: "reverse"}}},
+			expectErr: "unknown transform 'reverse'",
+		},
+		{
+			name:      "invalid type",
+			spec:      MappingSpec{Columns: map[string]ColumnSpec{"a": {Type: "notatype"}}},
+			expectErr: "invalid type 'notatype'",
+		},
+		{
+			name:      "skipped column with a name",
+			spec:      MappingSpec{Columns: map[string]ColumnSpec{"a": {Skip: true, Name: "key"}}},
+			expectErr: "skipped column 'a' cannot have a name, type or transform",
+		},
+		{
+			name:      "duplicate output column",
+			spec:      MappingSpec{Columns: map[string]ColumnSpec{"a": {Name: "b"}}},
+			expectErr: "both input column 'a' and input column 'b' are written to column 'b'",
+		},
+		{
+			name:      "default collides with input column",
+			spec:      MappingSpec{Defaults: map[string]string{"c": "constant"}},
+			expectErr: "both input column 'c' and default 'c' are written to column 'c'",
+		},
+		{
+			name:      "expression references unknown column",
+			spec:      MappingSpec{Expressions: map[string]string{"e": "${z}"}},
+			expectErr: "expression 'e' references column 'z'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.spec.Validate(context.Background(), schemaA)
+			if test.expectErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidMappingSpec))
+			assert.Contains(t, err.Error(), test.expectErr)
+		})
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	vals := map[string]string{"first": "Ada", "last": "Lovelace"}
+	lookup := func(col string) string { return vals[col] }
+
+	assert.Equal(t, "Ada Lovelace", ExpandTemplate("${first} ${last}", lookup))
+	assert.Equal(t, "no refs", ExpandTemplate("no refs", lookup))
+	assert.Equal(t, []string{"first", "last"}, TemplateRefs("${first} ${last}"))
+}
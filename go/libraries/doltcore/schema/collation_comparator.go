@@ -132,13 +132,15 @@ func compareCollatedStrings(collation sql.CollationID, left, right []byte) int {
 		}
 	}
 
+	// Back up to the start of the rune containing the first differing byte. Malformed strings may not have a rune
+	// start before it, in which case we compare from the beginning.
 	li := i
-	for ; li >= 0 && !utf8.RuneStart(left[li]); li-- {
+	for ; li > 0 && !utf8.RuneStart(left[li]); li-- {
 	}
 	left = left[li:]
 
 	ri := i
-	for ; ri >= 0 && !utf8.RuneStart(right[ri]); ri-- {
+	for ; ri > 0 && !utf8.RuneStart(right[ri]); ri-- {
 	}
 	right = right[ri:]
 
@@ -147,11 +149,13 @@ func compareCollatedStrings(collation sql.CollationID, left, right []byte) int {
 		// Binary strings aren't handled through this function, so it is safe to use the utf8 functions
 		leftRune, leftRead := utf8.DecodeRune(left)
 		rightRune, rightRead := utf8.DecodeRune(right)
-		if leftRead == utf8.RuneError || rightRead == utf8.RuneError {
+		leftMalformed := leftRune == utf8.RuneError && leftRead <= 1
+		rightMalformed := rightRune == utf8.RuneError && rightRead <= 1
+		if leftMalformed || rightMalformed {
 			// Malformed strings sort after well-formed strings, and we consider two malformed strings to be equal
-			if leftRead == utf8.RuneError && rightRead != utf8.RuneError {
+			if leftMalformed && !rightMalformed {
 				return 1
-			} else if leftRead != utf8.RuneError && rightRead == utf8.RuneError {
+			} else if !leftMalformed && rightMalformed {
 				return -1
 			} else {
 				return 0
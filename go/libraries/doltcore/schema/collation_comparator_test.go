@@ -63,3 +63,42 @@ func TestCompareCollatedStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareCollatedStringsByCollation(t *testing.T) {
+	tests := []struct {
+		collation sql.CollationID
+		left      string
+		right     string
+		exp       int
+	}{
+		{sql.Collation_utf8mb4_0900_bin, "a", "A", 1},
+		{sql.Collation_utf8mb4_0900_ai_ci, "a", "A", 0},
+		{sql.Collation_utf8mb4_0900_ai_ci, "schön", "schon", 0},
+		{sql.Collation_utf8mb4_0900_as_cs, "schön", "schon", 1},
+		{sql.Collation_utf8mb4_0900_ai_ci, "apple", "Banana", -1},
+		{sql.Collation_utf8mb4_0900_bin, "apple", "Banana", 1},
+		{sql.Collation_latin1_swedish_ci, "Bar", "bar", 0},
+		{sql.Collation_latin1_german1_ci, "Bär", "Bar", 0},
+		{sql.Collation_latin1_german2_ci, "Bär", "Bar", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s: %s vs %s", tt.collation.Name(), tt.left, tt.right), func(t *testing.T) {
+			cmp := compareCollatedStrings(tt.collation, []byte(tt.left), []byte(tt.right))
+			require.Equal(t, tt.exp, cmp)
+			cmp = compareCollatedStrings(tt.collation, []byte(tt.right), []byte(tt.left))
+			require.Equal(t, -tt.exp, cmp)
+		})
+	}
+}
+
+func TestCompareMalformedCollatedStrings(t *testing.T) {
+	// strings starting with a continuation byte have no rune start before the first difference
+	left := []byte{0x80, 'a'}
+	right := []byte{0x81, 'a'}
+	require.NotPanics(t, func() {
+		require.Equal(t, 0, compareCollatedStrings(sql.Collation_utf8mb4_0900_ai_ci, left, right))
+	})
+	require.Equal(t, 1, compareCollatedStrings(sql.Collation_utf8mb4_0900_ai_ci, left, []byte("a")))
+	require.Equal(t, -1, compareCollatedStrings(sql.Collation_utf8mb4_0900_ai_ci, []byte("a"), right))
+}
@@ -113,3 +113,17 @@ const (
 	DoltIgnorePatternTag = iota + SystemTableReservedMin + uint64(8000)
 	DoltIgnoreIgnoredTag
 )
+
+// Tags for the dolt_merge_drivers table
+const (
+	DoltMergeDriversTargetTag = iota + SystemTableReservedMin + uint64(9000)
+	DoltMergeDriversDriverTag
+)
+
+// Tags for the dolt_external_tables table
+const (
+	DoltExternalTablesNameTag = iota + SystemTableReservedMin + uint64(10000)
+	DoltExternalTablesLocationTag
+	DoltExternalTablesFormatTag
+	DoltExternalTablesColumnsTag
+)
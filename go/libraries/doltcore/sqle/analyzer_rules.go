@@ -0,0 +1,199 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// aliasAsOfBindVarTablesId is the id of the aliasAsOfBindVarTables rule. It's well past the ids of the engine's own
+// rules, so that the analyzer's rule selectors don't filter it out.
+const aliasAsOfBindVarTablesId analyzer.RuleId = 1000
+
+// applyDefaultAsOfId is the id of the applyDefaultAsOf rule
+const applyDefaultAsOfId analyzer.RuleId = 1001
+
+// AddAnalyzerRules adds the analyzer rules Dolt needs to |a|. They run before the engine's own rules.
+func AddAnalyzerRules(a *analyzer.Analyzer) {
+	for _, b := range a.Batches {
+		if b.Desc == "pre-analyzer" {
+			b.Rules = append(b.Rules,
+				analyzer.Rule{Id: applyDefaultAsOfId, Apply: applyDefaultAsOf},
+				analyzer.Rule{Id: aliasAsOfBindVarTablesId, Apply: aliasAsOfBindVarTables})
+			return
+		}
+	}
+}
+
+// aliasAsOfBindVarTables aliases each table whose AS OF is a bind variable of a prepared statement to its own name.
+// The engine defers resolving those tables until the statement is executed, and until then it only knows the names
+// of the ones that are aliased, so columns qualified with the names of the others couldn't be resolved, e.g. in
+// `select t.pk from t as of ? join u on t.pk = u.pk`.
+func aliasAsOfBindVarTables(_ *sql.Context, _ *analyzer.Analyzer, n sql.Node, _ *plan.Scope, _ analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.NodeWithCtx(n, nil, func(c transform.Context) (sql.Node, transform.TreeIdentity, error) {
+		t, ok := c.Node.(*plan.UnresolvedTable)
+		if !ok || t.AsOf() == nil {
+			return c.Node, transform.SameTree, nil
+		}
+		if _, ok := c.Parent.(*plan.TableAlias); ok {
+			return c.Node, transform.SameTree, nil
+		}
+		bindVar := transform.InspectExpr(t.AsOf(), func(e sql.Expression) bool {
+			_, ok := e.(*expression.BindVar)
+			return ok
+		})
+		if !bindVar {
+			return c.Node, transform.SameTree, nil
+		}
+		return plan.NewTableAlias(t.Name(), t), transform.NewTree, nil
+	})
+}
+
+// applyDefaultAsOf reads the tables a statement reads as of @@dolt_default_as_of, when it's set, by giving it as the
+// AS OF of each table without one, and of SHOW TABLES. This is the only place the default is applied. Only tables that are read are affected: the tables written by INSERT, UPDATE and
+// DELETE statements, the tables changed by DDL, and Dolt system tables are always resolved against the session's
+// current root, as are temporary tables. The rows an INSERT ... SELECT inserts are read as of the default, as are those read by subqueries.
+func applyDefaultAsOf(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, _ *plan.Scope, _ analyzer.RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	asOf, err := defaultAsOf(ctx)
+	if err != nil || asOf == nil {
+		return n, transform.SameTree, err
+	}
+
+	var asOfExpr sql.Expression
+	if t, ok := asOf.(time.Time); ok {
+		asOfExpr = expression.NewLiteral(t, types.Datetime)
+	} else {
+		asOfExpr = expression.NewLiteral(asOf, types.LongText)
+	}
+
+	// common table expressions are referenced like tables, but have no history
+	ctes := make(map[string]struct{})
+	transform.Inspect(n, func(n sql.Node) bool {
+		if w, ok := n.(*plan.With); ok {
+			for _, cte := range w.CTEs {
+				ctes[strings.ToLower(cte.Subquery.Name())] = struct{}{}
+			}
+		}
+		return true
+	})
+
+	var apply func(n sql.Node) (sql.Node, transform.TreeIdentity, error)
+	apply = func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		switch n := n.(type) {
+		case *plan.InsertInto:
+			src, same, err := apply(n.Source)
+			if err != nil || same {
+				return n, transform.SameTree, err
+			}
+			return n.WithSource(src), transform.NewTree, nil
+		case *plan.UnresolvedTable:
+			if n.AsOf() != nil || doltdb.HasDoltPrefix(n.Name()) {
+				return n, transform.SameTree, nil
+			}
+			if _, ok := ctes[strings.ToLower(n.Name())]; ok {
+				return n, transform.SameTree, nil
+			}
+			dbName := n.Database().Name()
+			if dbName == "" {
+				dbName = ctx.GetCurrentDatabase()
+			}
+			if !isDoltDatabase(ctx, a, dbName) {
+				return n, transform.SameTree, nil
+			}
+			// temporary tables have no history
+			if _, ok := dsess.DSessFromSess(ctx.Session).GetTemporaryTable(ctx, dbName, n.Name()); ok {
+				return n, transform.SameTree, nil
+			}
+			withAsOf, err := n.WithAsOf(asOfExpr)
+			if err != nil {
+				return nil, transform.SameTree, err
+			}
+			return withAsOf, transform.NewTree, nil
+		case *plan.ShowTables:
+			dbName := n.Database().Name()
+			if dbName == "" {
+				dbName = ctx.GetCurrentDatabase()
+			}
+			if n.AsOf() != nil || !isDoltDatabase(ctx, a, dbName) {
+				return n, transform.SameTree, nil
+			}
+			withAsOf, err := n.WithAsOf(asOfExpr)
+			if err != nil {
+				return nil, transform.SameTree, err
+			}
+			return withAsOf, transform.NewTree, nil
+		}
+
+		if writesTables(n) {
+			return n, transform.SameTree, nil
+		}
+
+		children := n.Children()
+		newChildren := make([]sql.Node, len(children))
+		same := transform.SameTree
+		for i, child := range children {
+			var childSame transform.TreeIdentity
+			newChildren[i], childSame, err = apply(child)
+			if err != nil {
+				return nil, transform.SameTree, err
+			}
+			same = same && childSame
+		}
+		if same {
+			return n, transform.SameTree, nil
+		}
+		newNode, err := n.WithChildren(newChildren...)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		return newNode, transform.NewTree, nil
+	}
+
+	return apply(n)
+}
+
+// isDoltDatabase returns whether the database named |dbName| is a Dolt database. Databases that don't exist are left
+// for table resolution to report.
+func isDoltDatabase(ctx *sql.Context, a *analyzer.Analyzer, dbName string) bool {
+	db, err := a.Catalog.Provider.Database(ctx, dbName)
+	if err != nil {
+		return false
+	}
+	_, ok := db.(dsess.SqlDatabase)
+	return ok
+}
+
+// writesTables returns whether |n| writes to or changes the schema of the tables under it
+func writesTables(n sql.Node) bool {
+	switch n.(type) {
+	case *plan.Update, *plan.DeleteFrom, *plan.Truncate, *plan.LoadData,
+		*plan.LockTables, *plan.AnalyzeTable,
+		*plan.BeginEndBlock, *plan.TriggerBeginEndBlock:
+		return true
+	default:
+		return plan.IsDDLNode(n)
+	}
+}
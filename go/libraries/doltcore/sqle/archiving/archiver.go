@@ -0,0 +1,444 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archiving
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/datas/pull"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	archiverThreadName = "Continuous Archiver"
+	archiveQueueSize   = 64
+
+	dbLabel = "database"
+)
+
+// Archiver continuously archives the branches of its databases to a backup of each database. A database is archived
+// once a number of commits have been made to its archived branches, and on an interval while it has commits that
+// haven't been archived. Archiving a database pushes the new head of each of its archived branches to the backup.
+//
+// Databases are archived in the background, one at a time. When archiving a database fails, its branches are pushed
+// again the next time it's archived. Archives and failures are counted in metrics served on the metrics endpoint,
+// along with the number of commits waiting to be archived, so that alerts can be raised when archiving falls behind.
+type Archiver struct {
+	cfg     Config
+	lgr     *logrus.Entry
+	metrics *archiveMetrics
+	ch      chan string
+	// openBackup opens the backup named |name| of |dEnv|
+	openBackup func(ctx context.Context, dEnv *env.DoltEnv, name string) (*doltdb.DoltDB, error)
+	// tempDir returns the directory table files are written to while they're pushed from |dEnv|
+	tempDir func(dEnv *env.DoltEnv) (string, error)
+
+	mu  sync.Mutex
+	dbs map[string]*archivedDB
+}
+
+// archivedDB is the archiving state of a database.
+type archivedDB struct {
+	name string
+	dEnv *env.DoltEnv
+	// backup is the database's backup, opened the first time the database is archived
+	backup *doltdb.DoltDB
+	// pending are the heads of the branches that changed since they were archived. A branch that was deleted has an
+	// empty hash.
+	pending map[string]hash.Hash
+	// commits is the number of commits made since the database was archived
+	commits int
+	// queued is true if the database is waiting to be archived
+	queued bool
+}
+
+// NewArchiver returns an Archiver configured by |cfg| whose metrics have the constant labels |labels|, or nil if
+// |cfg| is nil.
+func NewArchiver(cfg Config, labels prometheus.Labels, lgr *logrus.Logger) (*Archiver, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.Backup() == "" {
+		return nil, fmt.Errorf("archiving: backup: cannot be empty")
+	}
+	if cfg.EveryCommits() < 0 {
+		return nil, fmt.Errorf("archiving: every_commits: cannot be negative")
+	}
+	if cfg.IntervalSecs() < 0 {
+		return nil, fmt.Errorf("archiving: interval_secs: cannot be negative")
+	}
+	if cfg.EveryCommits() == 0 && cfg.IntervalSecs() == 0 {
+		return nil, fmt.Errorf("archiving: one of every_commits and interval_secs must be set")
+	}
+	metrics := newArchiveMetrics(labels)
+	metrics.register()
+	return newArchiver(cfg, metrics, logrus.NewEntry(lgr)), nil
+}
+
+func newArchiver(cfg Config, metrics *archiveMetrics, lgr *logrus.Entry) *Archiver {
+	return &Archiver{
+		cfg:        cfg,
+		lgr:        lgr.WithField("component", "archiving"),
+		metrics:    metrics,
+		ch:         make(chan string, archiveQueueSize),
+		openBackup: openBackup,
+		tempDir:    (*env.DoltEnv).TempTableFilesDir,
+		dbs:        make(map[string]*archivedDB),
+	}
+}
+
+func openBackup(ctx context.Context, dEnv *env.DoltEnv, name string) (*doltdb.DoltDB, error) {
+	backups, err := dEnv.GetBackups()
+	if err != nil {
+		return nil, err
+	}
+	b, ok := backups[name]
+	if !ok {
+		return nil, fmt.Errorf("no backup named '%s'", name)
+	}
+	return b.GetRemoteDB(ctx, dEnv.DoltDB.Format(), dEnv)
+}
+
+// Run starts archiving databases on a background thread.
+func (a *Archiver) Run(bt *sql.BackgroundThreads) error {
+	if a == nil {
+		return nil
+	}
+	return bt.Add(archiverThreadName, func(ctx context.Context) {
+		var tick <-chan time.Time
+		if a.cfg.IntervalSecs() > 0 {
+			ticker := time.NewTicker(time.Duration(a.cfg.IntervalSecs()) * time.Second)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case name := <-a.ch:
+				a.archive(ctx, name)
+			case <-tick:
+				for _, name := range a.pendingDatabases() {
+					a.archive(ctx, name)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// ApplyCommitHooks adds a commit hook that archives each database in |mrEnv|.
+func (a *Archiver) ApplyCommitHooks(ctx context.Context, mrEnv *env.MultiRepoEnv) error {
+	if a == nil {
+		return nil
+	}
+	return mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		a.addDatabase(ctx, name, dEnv)
+		return false, nil
+	})
+}
+
+// NewInitDatabaseHook returns an InitDatabaseHook that also archives newly created databases.
+func NewInitDatabaseHook(a *Archiver, orig sqle.InitDatabaseHook) sqle.InitDatabaseHook {
+	if a == nil {
+		return orig
+	}
+	return func(ctx *sql.Context, pro sqle.DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
+		if err := orig(ctx, pro, name, denv); err != nil {
+			return err
+		}
+		a.addDatabase(ctx, name, denv)
+		return nil
+	}
+}
+
+func (a *Archiver) addDatabase(ctx context.Context, name string, dEnv *env.DoltEnv) {
+	if backups, err := dEnv.GetBackups(); err == nil {
+		if _, ok := backups[a.cfg.Backup()]; !ok {
+			a.lgr.Warnf("database %s has no backup named '%s', add it with dolt_backup('add', '%s', <url>) to archive the database", name, a.cfg.Backup(), a.cfg.Backup())
+		}
+	}
+
+	a.mu.Lock()
+	a.dbs[name] = &archivedDB{name: name, dEnv: dEnv, pending: make(map[string]hash.Hash)}
+	a.mu.Unlock()
+	dEnv.DoltDB.PrependCommitHook(ctx, &commitHook{a: a, dbName: name})
+}
+
+func (a *Archiver) archivesBranch(branch string) bool {
+	if len(a.cfg.Branches()) == 0 {
+		return true
+	}
+	for _, b := range a.cfg.Branches() {
+		if b == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// headUpdated records the new head |addr| of |branch| in the database |dbName|, and queues the database to be
+// archived if enough commits have been made to it.
+func (a *Archiver) headUpdated(dbName, branch string, addr hash.Hash) {
+	a.mu.Lock()
+	db, ok := a.dbs[dbName]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	db.pending[branch] = addr
+	db.commits++
+	a.metrics.unarchived.WithLabelValues(dbName).Set(float64(db.commits))
+	queue := a.cfg.EveryCommits() > 0 && db.commits >= a.cfg.EveryCommits() && !db.queued
+	if queue {
+		db.queued = true
+	}
+	a.mu.Unlock()
+
+	if queue {
+		select {
+		case a.ch <- dbName:
+		default:
+			// the database is archived on the next commit or interval instead
+			a.mu.Lock()
+			db.queued = false
+			a.mu.Unlock()
+		}
+	}
+}
+
+// pendingDatabases returns the names of the databases with branches to archive.
+func (a *Archiver) pendingDatabases() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var names []string
+	for name, db := range a.dbs {
+		if len(db.pending) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// archive pushes the branches of the database |name| that changed since it was last archived to its backup.
+func (a *Archiver) archive(ctx context.Context, name string) {
+	a.mu.Lock()
+	db, ok := a.dbs[name]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+	db.queued = false
+	pending, commits := db.pending, db.commits
+	db.pending, db.commits = make(map[string]hash.Hash), 0
+	a.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	err := a.push(ctx, db, pending)
+	if ctx.Err() != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err != nil {
+		a.lgr.WithError(err).Errorf("failed to archive database %s to backup '%s'", name, a.cfg.Backup())
+		a.metrics.failures.WithLabelValues(name).Inc()
+		// push the branches again next time, unless they've moved since
+		for branch, addr := range pending {
+			if _, ok := db.pending[branch]; !ok {
+				db.pending[branch] = addr
+			}
+		}
+		db.commits += commits
+	} else {
+		a.metrics.archives.WithLabelValues(name).Inc()
+		a.metrics.lastSuccess.WithLabelValues(name).SetToCurrentTime()
+	}
+	a.metrics.unarchived.WithLabelValues(name).Set(float64(db.commits))
+}
+
+// push pushes the heads in |pending| to the backup of |db|. The working set of each branch pushed is reset to its
+// head, so that the backup can be restored.
+func (a *Archiver) push(ctx context.Context, db *archivedDB, pending map[string]hash.Hash) error {
+	if db.backup == nil {
+		backup, err := a.openBackup(ctx, db.dEnv, a.cfg.Backup())
+		if err != nil {
+			return err
+		}
+		db.backup = backup
+	}
+	tmpDir, err := a.tempDir(db.dEnv)
+	if err != nil {
+		return err
+	}
+
+	branches := make([]string, 0, len(pending))
+	for branch := range pending {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+
+	for _, branch := range branches {
+		addr, branchRef := pending[branch], ref.NewBranchRef(branch)
+		if addr.IsEmpty() {
+			err = db.backup.DeleteBranch(ctx, branchRef, nil)
+			if err != nil && !errors.Is(err, doltdb.ErrBranchNotFound) {
+				return err
+			}
+			continue
+		}
+
+		err = db.backup.PullChunks(ctx, tmpDir, db.dEnv.DoltDB, []hash.Hash{addr}, nil)
+		if err != nil && !errors.Is(err, pull.ErrDBUpToDate) {
+			return err
+		}
+		cm, err := db.backup.ReadCommit(ctx, addr)
+		if err != nil {
+			return err
+		}
+		if err = db.backup.NewBranchAtCommit(ctx, branchRef, cm, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//------------------------------------
+// metrics
+//------------------------------------
+
+type archiveMetrics struct {
+	archives    *prometheus.CounterVec
+	failures    *prometheus.CounterVec
+	lastSuccess *prometheus.GaugeVec
+	unarchived  *prometheus.GaugeVec
+}
+
+func newArchiveMetrics(labels prometheus.Labels) *archiveMetrics {
+	return &archiveMetrics{
+		archives: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_archives",
+			Help:        "Count of the times a database was archived to its backup",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "dss_archive_failures",
+			Help:        "Count of the times archiving a database to its backup failed",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_archive_last_success_seconds",
+			Help:        "The unix time a database was last archived to its backup",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+		unarchived: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "dss_archive_unarchived_commits",
+			Help:        "The number of commits made to a database that haven't been archived to its backup",
+			ConstLabels: labels,
+		}, []string{dbLabel}),
+	}
+}
+
+// register registers the metrics with the default prometheus registry, reusing the metrics of a previous archiver
+// if they were already registered.
+func (m *archiveMetrics) register() {
+	m.archives = registerCounterVec(m.archives)
+	m.failures = registerCounterVec(m.failures)
+	m.lastSuccess = registerGaugeVec(m.lastSuccess)
+	m.unarchived = registerGaugeVec(m.unarchived)
+}
+
+func registerCounterVec(c *prometheus.CounterVec) *prometheus.CounterVec {
+	var are prometheus.AlreadyRegisteredError
+	if err := prometheus.Register(c); errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+			return existing
+		}
+	}
+	return c
+}
+
+func registerGaugeVec(g *prometheus.GaugeVec) *prometheus.GaugeVec {
+	var are prometheus.AlreadyRegisteredError
+	if err := prometheus.Register(g); errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*prometheus.GaugeVec); ok {
+			return existing
+		}
+	}
+	return g
+}
+
+//------------------------------------
+// commitHook
+//------------------------------------
+
+// commitHook records the new heads of the archived branches of a database for its Archiver.
+type commitHook struct {
+	a      *Archiver
+	dbName string
+	out    io.Writer
+}
+
+var _ doltdb.CommitHook = (*commitHook)(nil)
+
+// Execute implements doltdb.CommitHook
+func (h *commitHook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
+	rf, err := ref.Parse(ds.ID())
+	if err != nil || rf.GetType() != ref.BranchRefType || !h.a.archivesBranch(rf.GetPath()) {
+		return nil, nil
+	}
+	// a deleted branch has no head, and is deleted from the backup
+	addr, _ := ds.MaybeHeadAddr()
+	h.a.headUpdated(h.dbName, rf.GetPath(), addr)
+	return nil, nil
+}
+
+// HandleError implements doltdb.CommitHook
+func (h *commitHook) HandleError(ctx context.Context, err error) error {
+	if h.out != nil {
+		_, err = h.out.Write([]byte(fmt.Sprintf("error recording commit to archive: %+v", err)))
+		return err
+	}
+	return nil
+}
+
+// SetLogger implements doltdb.CommitHook
+func (h *commitHook) SetLogger(ctx context.Context, wr io.Writer) error {
+	h.out = wr
+	return nil
+}
+
+// ExecuteForWorkingSets implements doltdb.CommitHook
+func (h *commitHook) ExecuteForWorkingSets() bool {
+	return false
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archiving
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+type testConfig struct {
+	everyCommits int
+}
+
+func (c testConfig) Backup() string     { return "archive" }
+func (c testConfig) Branches() []string { return []string{"main"} }
+func (c testConfig) EveryCommits() int  { return c.everyCommits }
+func (c testConfig) IntervalSecs() int  { return 0 }
+
+func commitSql(t *testing.T, ctx context.Context, dEnv *env.DoltEnv, statements string) hash.Hash {
+	root, err := dEnv.HeadRoot(ctx)
+	require.NoError(t, err)
+	root, err = sqle.ExecuteSql(dEnv, root, statements)
+	require.NoError(t, err)
+	_, h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bill@billerson.com", statements)
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Commit(ctx, h, ref.NewBranchRef("main"), meta)
+	require.NoError(t, err)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+	return cmHash
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+	backup, err := doltdb.LoadDoltDB(ctx, types.Format_Default, doltdb.InMemDoltDB, filesys.LocalFS)
+	require.NoError(t, err)
+	defer backup.Close()
+
+	a := newArchiver(testConfig{everyCommits: 2}, newArchiveMetrics(prometheus.Labels{}), logrus.NewEntry(logrus.New()))
+	fail := false
+	a.openBackup = func(context.Context, *env.DoltEnv, string) (*doltdb.DoltDB, error) {
+		if fail {
+			return nil, errors.New("backup unavailable")
+		}
+		return backup, nil
+	}
+	tmpDir := t.TempDir()
+	a.tempDir = func(*env.DoltEnv) (string, error) {
+		return tmpDir, nil
+	}
+	a.addDatabase(ctx, "mydb", dEnv)
+
+	// the database is queued once two commits have been made to it
+	commitSql(t, ctx, dEnv, "create table t (pk int primary key);")
+	assert.Empty(t, a.ch)
+	h2 := commitSql(t, ctx, dEnv, "insert into t values (1);")
+	require.Len(t, a.ch, 1)
+	assert.Equal(t, "mydb", <-a.ch)
+	assert.Equal(t, float64(2), testutil.ToFloat64(a.metrics.unarchived.WithLabelValues("mydb")))
+
+	a.archive(ctx, "mydb")
+	head, err := backup.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, h2, headHash)
+	assert.Equal(t, float64(1), testutil.ToFloat64(a.metrics.archives.WithLabelValues("mydb")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(a.metrics.unarchived.WithLabelValues("mydb")))
+	assert.Empty(t, a.pendingDatabases())
+
+	// a failed archive is counted, and its branches are archived again next time
+	h3 := commitSql(t, ctx, dEnv, "insert into t values (2);")
+	a.dbs["mydb"].backup = nil
+	fail = true
+	a.archive(ctx, "mydb")
+	assert.Equal(t, float64(1), testutil.ToFloat64(a.metrics.failures.WithLabelValues("mydb")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(a.metrics.unarchived.WithLabelValues("mydb")))
+	assert.Equal(t, []string{"mydb"}, a.pendingDatabases())
+
+	fail = false
+	a.archive(ctx, "mydb")
+	head, err = backup.ResolveCommitRef(ctx, ref.NewBranchRef("main"))
+	require.NoError(t, err)
+	headHash, err = head.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, h3, headHash)
+	assert.Equal(t, float64(2), testutil.ToFloat64(a.metrics.archives.WithLabelValues("mydb")))
+	assert.Empty(t, a.pendingDatabases())
+}
+
+func TestArchivesBranch(t *testing.T) {
+	a := newArchiver(testConfig{everyCommits: 1}, newArchiveMetrics(prometheus.Labels{}), logrus.NewEntry(logrus.New()))
+	assert.True(t, a.archivesBranch("main"))
+	assert.False(t, a.archivesBranch("other"))
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archiving
+
+// Config is the configuration for continuously archiving branches to a backup.
+type Config interface {
+	// Backup is the name of the backup each database is archived to, as added with `dolt backup add`. Databases
+	// without a backup of this name aren't archived.
+	Backup() string
+	// Branches are the branches that are archived. Every branch is archived if it's empty.
+	Branches() []string
+	// EveryCommits archives a database once this many commits have been made to its archived branches since it was
+	// last archived. 0 doesn't archive after a number of commits.
+	EveryCommits() int
+	// IntervalSecs archives every database with commits that haven't been archived this often, in seconds. 0 doesn't
+	// archive on an interval.
+	IntervalSecs() int
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+// Config is the configuration for publishing row changes to Kafka.
+type Config interface {
+	// Brokers are the addresses of the Kafka brokers to publish to.
+	Brokers() []string
+	// TopicPrefix is the first part of the topic each change is published to, which is <prefix>.<database>.<table>.
+	TopicPrefix() string
+	// Branches are the branches whose commits are published. Commits to every branch are published if it's empty.
+	Branches() []string
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaProducer is a Producer that writes to Kafka brokers. Messages are partitioned by the hash of their keys, so
+// the changes to a row are delivered in order.
+type kafkaProducer struct {
+	w *kafka.Writer
+}
+
+var _ Producer = (*kafkaProducer)(nil)
+
+func newKafkaProducer(brokers []string) *kafkaProducer {
+	return &kafkaProducer{w: &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+	}}
+}
+
+// Produce implements Producer
+func (p *kafkaProducer) Produce(ctx context.Context, msgs []Message) error {
+	kmsgs := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		kmsgs[i] = kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value}
+	}
+	return p.w.WriteMessages(ctx, kmsgs...)
+}
+
+// Close implements Producer
+func (p *kafkaProducer) Close() error {
+	return p.w.Close()
+}
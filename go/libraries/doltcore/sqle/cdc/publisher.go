@@ -0,0 +1,464 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	publisherBufferSize = 1024
+	publisherThreadName = "CDC Publisher"
+
+	// connectorName is the name of the connector in the source of each change event.
+	connectorName = "dolt"
+)
+
+// Message is a message to publish to a topic.
+type Message struct {
+	Topic string
+	Key   []byte
+	// Value is nil for a tombstone, which follows the deletion of a row so that compacted topics can drop its key.
+	Value []byte
+}
+
+// Producer publishes messages to Kafka. Messages with the same topic and key are delivered in the order they're
+// produced.
+type Producer interface {
+	Produce(ctx context.Context, msgs []Message) error
+	Close() error
+}
+
+// Publisher publishes the row changes made by each commit to the configured branches of its databases, as change
+// events in the envelope Debezium uses, so that consumers written for Debezium's MySQL connector can read them.
+//
+// Changes are published in the background, in commit order, one commit at a time. The changes of a commit are
+// those it made to its first parent. If a branch's head moves to a commit that doesn't descend from the last commit
+// published for it, like after a reset, the changes published are those between the two commits.
+//
+// Which commit was last published for each branch is kept in memory, so when the server starts, the first commit
+// published for a branch is the first one made to it after that.
+type Publisher struct {
+	cfg      Config
+	producer Producer
+	lgr      *logrus.Entry
+	ch       chan headUpdate
+
+	mu        sync.Mutex
+	published map[string]hash.Hash
+}
+
+// headUpdate is a new head of a branch to publish the changes of.
+type headUpdate struct {
+	dbName string
+	branch string
+	ddb    *doltdb.DoltDB
+	head   hash.Hash
+}
+
+func (u headUpdate) key() string {
+	return u.dbName + "/" + u.branch
+}
+
+// NewPublisher returns a Publisher that publishes to the Kafka brokers in |cfg|, or nil if |cfg| is nil.
+func NewPublisher(cfg Config, lgr *logrus.Logger) (*Publisher, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.Brokers()) == 0 {
+		return nil, fmt.Errorf("cdc: kafka: brokers: must supply at least one broker")
+	}
+	if cfg.TopicPrefix() == "" {
+		return nil, fmt.Errorf("cdc: topic_prefix: cannot be empty")
+	}
+	return newPublisher(cfg, newKafkaProducer(cfg.Brokers()), logrus.NewEntry(lgr)), nil
+}
+
+func newPublisher(cfg Config, producer Producer, lgr *logrus.Entry) *Publisher {
+	return &Publisher{
+		cfg:       cfg,
+		producer:  producer,
+		lgr:       lgr.WithField("component", "cdc"),
+		ch:        make(chan headUpdate, publisherBufferSize),
+		published: make(map[string]hash.Hash),
+	}
+}
+
+// Run starts publishing changes on a background thread.
+func (p *Publisher) Run(bt *sql.BackgroundThreads) error {
+	if p == nil {
+		return nil
+	}
+	return bt.Add(publisherThreadName, func(ctx context.Context) {
+		for {
+			select {
+			case u := <-p.ch:
+				if err := p.publish(ctx, u); err != nil {
+					p.lgr.WithError(err).Errorf("failed to publish changes to %s", u.key())
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// Close closes the connection to Kafka.
+func (p *Publisher) Close() error {
+	if p == nil {
+		return nil
+	}
+	return p.producer.Close()
+}
+
+// ApplyCommitHooks adds a commit hook that publishes changes to each database in |mrEnv|.
+func (p *Publisher) ApplyCommitHooks(ctx context.Context, mrEnv *env.MultiRepoEnv) error {
+	if p == nil {
+		return nil
+	}
+	return mrEnv.Iter(func(name string, dEnv *env.DoltEnv) (stop bool, err error) {
+		dEnv.DoltDB.PrependCommitHook(ctx, p.commitHook(name, dEnv.DoltDB))
+		return false, nil
+	})
+}
+
+// NewInitDatabaseHook returns an InitDatabaseHook that also adds a commit hook that publishes changes to newly
+// created databases.
+func NewInitDatabaseHook(p *Publisher, orig sqle.InitDatabaseHook) sqle.InitDatabaseHook {
+	if p == nil {
+		return orig
+	}
+	return func(ctx *sql.Context, pro sqle.DoltDatabaseProvider, name string, denv *env.DoltEnv) error {
+		if err := orig(ctx, pro, name, denv); err != nil {
+			return err
+		}
+		denv.DoltDB.PrependCommitHook(ctx, p.commitHook(name, denv.DoltDB))
+		return nil
+	}
+}
+
+func (p *Publisher) publishesBranch(branch string) bool {
+	if len(p.cfg.Branches()) == 0 {
+		return true
+	}
+	for _, b := range p.cfg.Branches() {
+		if b == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// publish publishes the changes made by the commits between the last commit published for the branch in |u| and
+// its new head.
+func (p *Publisher) publish(ctx context.Context, u headUpdate) error {
+	p.mu.Lock()
+	prevHash, ok := p.published[u.key()]
+	p.mu.Unlock()
+	if ok && prevHash == u.head {
+		return nil
+	}
+
+	head, err := u.ddb.ReadCommit(ctx, u.head)
+	if err != nil {
+		return err
+	}
+
+	var prev *doltdb.Commit
+	if ok {
+		if prev, err = u.ddb.ReadCommit(ctx, prevHash); err != nil {
+			return err
+		}
+	}
+
+	var commits []*doltdb.Commit
+	if prev == nil {
+		commits = []*doltdb.Commit{head}
+	} else {
+		commits, err = sqle.FirstParentCommitsAfter(ctx, head, prev)
+		if sqle.ErrChangesNotAncestor.Is(err) {
+			// the branch was reset, so the changes are those between the commits
+			if err = p.publishCommit(ctx, u, head, prev); err != nil {
+				return err
+			}
+			p.setPublished(u, head)
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	for _, cm := range commits {
+		var parent *doltdb.Commit
+		if cm.NumParents() > 0 {
+			if parent, err = cm.GetParent(ctx, 0); err != nil {
+				return err
+			}
+		}
+		if err = p.publishCommit(ctx, u, cm, parent); err != nil {
+			return err
+		}
+		p.setPublished(u, cm)
+	}
+	return nil
+}
+
+func (p *Publisher) setPublished(u headUpdate, cm *doltdb.Commit) {
+	h, err := cm.HashOf()
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published[u.key()] = h
+}
+
+// publishCommit publishes the changes made to each table with a primary key between |from| and |cm|. |from| may be
+// nil, in which case every row of |cm| is published as inserted.
+func (p *Publisher) publishCommit(ctx context.Context, u headUpdate, cm, from *doltdb.Commit) error {
+	toRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	var fromRoot *doltdb.RootValue
+	if from != nil {
+		if fromRoot, err = from.GetRootValue(ctx); err != nil {
+			return err
+		}
+	}
+	meta, err := cm.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	tables, err := changedTables(ctx, fromRoot, toRoot)
+	if err != nil {
+		return err
+	}
+
+	var msgs []Message
+	now := time.Now()
+	for _, table := range tables {
+		src := source{
+			Connector: connectorName,
+			Name:      p.cfg.TopicPrefix(),
+			TsMs:      meta.Time().UnixMilli(),
+			Db:        u.dbName,
+			Table:     table,
+			Branch:    u.branch,
+			Commit:    h.String(),
+		}
+		topic := p.cfg.TopicPrefix() + "." + u.dbName + "." + table
+		err = sqle.DiffTableRows(ctx, table, fromRoot, toRoot, func(change sqle.RowChange) error {
+			tableMsgs, err := changeMessages(topic, src, change, now)
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, tableMsgs...)
+			return nil
+		})
+		if err != nil {
+			// tables without primary keys, or whose primary key changed, can't be published
+			p.lgr.WithError(err).Warnf("not publishing changes to table %s in %s at commit %s", table, u.key(), h.String())
+			continue
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	return p.producer.Produce(ctx, msgs)
+}
+
+// changedTables returns the names of the user tables that differ between |fromRoot| and |toRoot|, in order.
+func changedTables(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) ([]string, error) {
+	hashes := make(map[string]hash.Hash)
+	if fromRoot != nil {
+		names, err := fromRoot.GetTableNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			h, _, err := fromRoot.GetTableHash(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			hashes[name] = h
+		}
+	}
+
+	var changed []string
+	names, err := toRoot.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		h, _, err := toRoot.GetTableHash(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if fromHash, ok := hashes[name]; !ok || fromHash != h {
+			changed = append(changed, name)
+		}
+		delete(hashes, name)
+	}
+	// the tables left were dropped
+	for name := range hashes {
+		changed = append(changed, name)
+	}
+
+	var tables []string
+	for _, name := range changed {
+		if !doltdb.HasDoltPrefix(name) {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// source is where a change event came from.
+type source struct {
+	Connector string `json:"connector"`
+	Name      string `json:"name"`
+	TsMs      int64  `json:"ts_ms"`
+	Db        string `json:"db"`
+	Table     string `json:"table"`
+	Branch    string `json:"branch"`
+	Commit    string `json:"commit"`
+}
+
+// changeEvent is the value of the message for a change, in Debezium's envelope.
+type changeEvent struct {
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+	Source source                 `json:"source"`
+	Op     string                 `json:"op"`
+	TsMs   int64                  `json:"ts_ms"`
+}
+
+// changeMessages returns the messages for |change|: its change event, keyed by the row's primary key, and a
+// tombstone if the row was deleted.
+func changeMessages(topic string, src source, change sqle.RowChange, now time.Time) ([]Message, error) {
+	key, err := json.Marshal(change.Key)
+	if err != nil {
+		return nil, err
+	}
+	event := changeEvent{
+		Before: change.Before,
+		After:  change.After,
+		Source: src,
+		TsMs:   now.UnixMilli(),
+	}
+	switch {
+	case change.Before == nil:
+		event.Op = "c"
+	case change.After == nil:
+		event.Op = "d"
+	default:
+		event.Op = "u"
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := []Message{{Topic: topic, Key: key, Value: value}}
+	if event.Op == "d" {
+		msgs = append(msgs, Message{Topic: topic, Key: key})
+	}
+	return msgs, nil
+}
+
+//------------------------------------
+// commitHook
+//------------------------------------
+
+// commitHook queues the new heads of the published branches of a database for its Publisher.
+type commitHook struct {
+	p      *Publisher
+	dbName string
+	ddb    *doltdb.DoltDB
+	out    io.Writer
+}
+
+var _ doltdb.CommitHook = (*commitHook)(nil)
+
+func (p *Publisher) commitHook(dbName string, ddb *doltdb.DoltDB) *commitHook {
+	return &commitHook{p: p, dbName: dbName, ddb: ddb}
+}
+
+// Execute implements doltdb.CommitHook
+func (h *commitHook) Execute(ctx context.Context, ds datas.Dataset, db datas.Database) (func(context.Context) error, error) {
+	rf, err := ref.Parse(ds.ID())
+	if err != nil || rf.GetType() != ref.BranchRefType || !h.p.publishesBranch(rf.GetPath()) {
+		return nil, nil
+	}
+	addr, ok := ds.MaybeHeadAddr()
+	if !ok {
+		// the branch was deleted
+		h.p.mu.Lock()
+		delete(h.p.published, h.dbName+"/"+rf.GetPath())
+		h.p.mu.Unlock()
+		return nil, nil
+	}
+
+	select {
+	case h.p.ch <- headUpdate{dbName: h.dbName, branch: rf.GetPath(), ddb: h.ddb, head: addr}:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// HandleError implements doltdb.CommitHook
+func (h *commitHook) HandleError(ctx context.Context, err error) error {
+	if h.out != nil {
+		_, err = h.out.Write([]byte(fmt.Sprintf("error queueing changes to publish: %+v", err)))
+		return err
+	}
+	return nil
+}
+
+// SetLogger implements doltdb.CommitHook
+func (h *commitHook) SetLogger(ctx context.Context, wr io.Writer) error {
+	h.out = wr
+	return nil
+}
+
+// ExecuteForWorkingSets implements doltdb.CommitHook
+func (h *commitHook) ExecuteForWorkingSets() bool {
+	return false
+}
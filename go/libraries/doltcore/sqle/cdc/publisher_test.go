@@ -0,0 +1,134 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+type testConfig struct{}
+
+func (testConfig) Brokers() []string   { return []string{"localhost:9092"} }
+func (testConfig) TopicPrefix() string { return "dolt" }
+func (testConfig) Branches() []string  { return []string{"main"} }
+
+type testProducer struct {
+	msgs []Message
+}
+
+func (p *testProducer) Produce(_ context.Context, msgs []Message) error {
+	p.msgs = append(p.msgs, msgs...)
+	return nil
+}
+
+func (p *testProducer) Close() error {
+	return nil
+}
+
+// events returns the ops, keys and after values of the change events produced since the last call, skipping
+// tombstones.
+func (p *testProducer) events(t *testing.T) (ops, keys []string, afters []map[string]interface{}) {
+	for _, m := range p.msgs {
+		require.Equal(t, "dolt.mydb.t", m.Topic)
+		if m.Value == nil {
+			continue
+		}
+		var event changeEvent
+		require.NoError(t, json.Unmarshal(m.Value, &event))
+		assert.Equal(t, "mydb", event.Source.Db)
+		assert.Equal(t, "main", event.Source.Branch)
+		ops = append(ops, event.Op)
+		keys = append(keys, string(m.Key))
+		afters = append(afters, event.After)
+	}
+	p.msgs = nil
+	return ops, keys, afters
+}
+
+func commitSql(t *testing.T, ctx context.Context, dEnv *env.DoltEnv, statements string) hash.Hash {
+	root, err := dEnv.HeadRoot(ctx)
+	require.NoError(t, err)
+	root, err = sqle.ExecuteSql(dEnv, root, statements)
+	require.NoError(t, err)
+	_, h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := datas.NewCommitMeta("Bill Billerson", "bill@billerson.com", statements)
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Commit(ctx, h, ref.NewBranchRef("main"), meta)
+	require.NoError(t, err)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+	return cmHash
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	producer := &testProducer{}
+	p := newPublisher(testConfig{}, producer, logrus.NewEntry(logrus.New()))
+	update := func(h hash.Hash) headUpdate {
+		return headUpdate{dbName: "mydb", branch: "main", ddb: dEnv.DoltDB, head: h}
+	}
+
+	h1 := commitSql(t, ctx, dEnv, "create table t (pk int primary key, c varchar(10));\ninsert into t values (1, 'a'), (2, 'b');")
+	require.NoError(t, p.publish(ctx, update(h1)))
+	ops, keys, afters := producer.events(t)
+	assert.Equal(t, []string{"c", "c"}, ops)
+	assert.Equal(t, []string{`{"pk":1}`, `{"pk":2}`}, keys)
+	assert.Equal(t, map[string]interface{}{"pk": float64(1), "c": "a"}, afters[0])
+
+	// both commits since the last one published are published, in order
+	commitSql(t, ctx, dEnv, "update t set c = 'z' where pk = 1;")
+	h3 := commitSql(t, ctx, dEnv, "delete from t where pk = 2;")
+	require.NoError(t, p.publish(ctx, update(h3)))
+	require.Len(t, producer.msgs, 3)
+	assert.Nil(t, producer.msgs[2].Value, "a deleted row is followed by a tombstone")
+	ops, keys, afters = producer.events(t)
+	assert.Equal(t, []string{"u", "d"}, ops)
+	assert.Equal(t, []string{`{"pk":1}`, `{"pk":2}`}, keys)
+	assert.Equal(t, map[string]interface{}{"pk": float64(1), "c": "z"}, afters[0])
+	assert.Nil(t, afters[1])
+
+	// resetting the branch publishes the changes between the heads
+	require.NoError(t, p.publish(ctx, update(h1)))
+	ops, keys, _ = producer.events(t)
+	assert.Equal(t, []string{"u", "c"}, ops)
+	assert.Equal(t, []string{`{"pk":1}`, `{"pk":2}`}, keys)
+
+	// publishing the same head again publishes nothing
+	require.NoError(t, p.publish(ctx, update(h1)))
+	assert.Empty(t, producer.msgs)
+}
+
+func TestPublishesBranch(t *testing.T) {
+	p := newPublisher(testConfig{}, &testProducer{}, logrus.NewEntry(logrus.New()))
+	assert.True(t, p.publishesBranch("main"))
+	assert.False(t, p.publishesBranch("other"))
+}
@@ -29,11 +29,34 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 )
 
 var _ doltdb.CommitHook = (*commithook)(nil)
 var _ doltdb.NotifyWaitFailedCommitHook = (*commithook)(nil)
 
+// commithook replicates a database on a primary to one standby. Replication
+// is driven by root updates rather than by a periodic push: every commit and
+// working set write on the primary calls Execute, which hands the new root
+// hash to the replicate thread. The replicate thread pushes any chunks the
+// standby is missing for that root and then moves the standby's root to it,
+// so standby lag is bounded by the time it takes to push a single write.
+//
+// When the primary's chunk store has a chunk journal, chunks are pushed by
+// shipping the journal: every chunk written to it since the last successful
+// push is uploaded to the standby as a table file, without walking the
+// chunk graph. |journalPos| records how far the journal has been shipped.
+// The journal is only shipped if everything the shipped chunks reference is
+// already on the standby; otherwise, or if |journalPos| is lost to a GC or
+// a table file change on the primary, the push falls back to PullChunks for
+// the new root and shipping resumes from the journal's end.
+//
+// After a disconnect, catch-up needs no separate protocol. Intermediate roots
+// are collapsed into |nextHead|, and the next successful push sends every
+// chunk the standby is missing, either by shipping the journal from
+// |journalPos| or by falling back to PullChunks, regardless of how many
+// writes were missed. Failed attempts are retried every second, or
+// immediately when a new root comes in.
 type commithook struct {
 	rootLgr              *logrus.Entry
 	lgr                  atomic.Value // *logrus.Entry
@@ -44,6 +67,7 @@ type commithook struct {
 	cond                 *sync.Cond
 	nextHead             hash.Hash
 	lastPushedHead       hash.Hash
+	journalPos           nbs.JournalPosition
 	nextPushAttempt      time.Time
 	nextHeadIncomingTime time.Time
 	lastSuccess          time.Time
@@ -244,6 +268,7 @@ func (h *commithook) attemptHeartbeat(ctx context.Context) {
 func (h *commithook) attemptReplicate(ctx context.Context) {
 	lgr := h.logger()
 	toPush := h.nextHead
+	journalPos := h.journalPos
 	incomingTime := h.nextHeadIncomingTime
 	destDB := h.destDB
 	ctx, h.cancelReplicate = context.WithCancel(ctx)
@@ -285,7 +310,7 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 	}
 
 	lgr.Tracef("cluster/commithook: pushing chunks for root hash %v to destDB", toPush.String())
-	err := destDB.PullChunks(ctx, h.tempDir, h.srcDB, []hash.Hash{toPush}, nil)
+	journalPos, err := h.pushChunks(ctx, destDB, toPush, journalPos)
 	if err == nil {
 		lgr.Tracef("cluster/commithook: successfully pushed chunks, setting root")
 		datasDB := doltdb.HackDatasDatabaseFromDoltDB(destDB)
@@ -308,6 +333,7 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 			h.currentError = nil
 			lgr.Tracef("cluster/commithook: successfully Committed chunks on destDB")
 			h.lastPushedHead = toPush
+			h.journalPos = journalPos
 			h.lastSuccess = incomingTime
 			h.nextPushAttempt = time.Time{}
 			if len(successChs) != 0 {
@@ -329,6 +355,44 @@ func (h *commithook) attemptReplicate(ctx context.Context) {
 	}
 }
 
+// Called by attemptReplicate to get every chunk reachable from |toPush| onto
+// |destDB|, shipping the chunk journal from |from| where possible. Returns the
+// journal position to ship from on the next push, which is the zero position
+// if the journal cannot be shipped.
+//
+// Called without h.mu held.
+func (h *commithook) pushChunks(ctx context.Context, destDB *doltdb.DoltDB, toPush hash.Hash, from nbs.JournalPosition) (nbs.JournalPosition, error) {
+	lgr := h.logger()
+	srcCS := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(h.srcDB))
+	src, ok := srcCS.(journalSource)
+	if ok && !from.IsZero() {
+		destCS := datas.ChunkStoreFromDatabase(doltdb.HackDatasDatabaseFromDoltDB(destDB))
+		end, err := shipJournalChunks(ctx, src, h.srcDB.Format(), destCS, h.tempDir, from, toPush)
+		if err == nil {
+			lgr.Tracef("cluster/commithook: shipped chunk journal to destDB")
+			return end, nil
+		}
+		if !errors.Is(err, nbs.ErrJournalPositionLost) && !errors.Is(err, errJournalRefsMissing) && !errors.Is(err, errJournalShippingUnsupported) {
+			return from, err
+		}
+		lgr.Tracef("cluster/commithook: cannot ship chunk journal, pulling chunks instead: %v", err)
+	}
+
+	// Take the journal position before pulling, so that chunks written
+	// while we pull are shipped next time.
+	var end nbs.JournalPosition
+	if ok {
+		var err error
+		if end, _, err = src.JournalEnd(); err != nil {
+			return from, err
+		}
+	}
+	if err := destDB.PullChunks(ctx, h.tempDir, h.srcDB, []hash.Hash{toPush}, nil); err != nil {
+		return from, err
+	}
+	return end, nil
+}
+
 func (h *commithook) status() (replicationLag *time.Duration, lastUpdate *time.Time, currentErr *string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -397,6 +461,7 @@ func (h *commithook) setRole(role Role) {
 	h.currentError = nil
 	h.nextHead = hash.Hash{}
 	h.lastPushedHead = hash.Hash{}
+	h.journalPos = nbs.JournalPosition{}
 	h.lastSuccess = time.Time{}
 	h.nextPushAttempt = time.Time{}
 	h.role = role
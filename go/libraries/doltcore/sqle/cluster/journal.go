@@ -0,0 +1,133 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// journalSource is implemented by chunk stores which keep a chunk journal
+// that can be shipped to a standby.
+type journalSource interface {
+	JournalEnd() (nbs.JournalPosition, bool, error)
+	ReadJournalChunks(ctx context.Context, from nbs.JournalPosition, cb func(nbs.CompressedChunk) error) (nbs.JournalPosition, error)
+}
+
+var _ journalSource = (*nbs.NomsBlockStore)(nil)
+var _ journalSource = (*nbs.GenerationalNBS)(nil)
+
+// errJournalRefsMissing is returned by shipJournalChunks when the chunks
+// written to the journal reference chunks which are neither in the journal
+// nor on the standby. This happens when the standby is behind the journal
+// position, for example because the chunks were written by a transaction
+// which was still in flight when the position was taken.
+var errJournalRefsMissing = errors.New("cluster/commithook: chunk journal references chunks missing on the standby")
+
+// errJournalShippingUnsupported is returned by shipJournalChunks when the
+// standby's chunk store cannot accept table files.
+var errJournalShippingUnsupported = errors.New("cluster/commithook: standby does not support table file uploads")
+
+// shipJournalChunks ships every chunk written to |src|'s chunk journal after
+// |from| to |dest| as a single table file, and returns the journal position
+// to ship from next time.
+//
+// Nothing is uploaded unless every chunk referenced by the shipped chunks,
+// along with |toPush| itself, is either shipped or already present on
+// |dest|. This keeps every chunk on |dest| complete, so that |toPush| can
+// be committed on it without walking the chunk graph. If the check fails,
+// errJournalRefsMissing is returned and the caller should fall back to
+// pulling the chunks reachable from |toPush|.
+func shipJournalChunks(ctx context.Context, src journalSource, nbf *types.NomsBinFormat, dest chunks.ChunkStore, tempDir string, from nbs.JournalPosition, toPush hash.Hash) (nbs.JournalPosition, error) {
+	tfs, ok := dest.(chunks.TableFileStore)
+	if !ok {
+		return nbs.JournalPosition{}, errJournalShippingUnsupported
+	}
+
+	// the shipped chunks are encrypted in the temp file if |src| is encrypted
+	var enc *nbs.Encryption
+	if es, ok := src.(nbs.EncryptedStore); ok {
+		enc = es.Encryption()
+	}
+	tw, err := nbs.NewEncryptedCmpChunkTableWriter(tempDir, enc)
+	if err != nil {
+		return nbs.JournalPosition{}, err
+	}
+	defer tw.Remove()
+
+	shipped := hash.NewHashSet()
+	refs := hash.NewHashSet(toPush)
+	end, err := src.ReadJournalChunks(ctx, from, func(cc nbs.CompressedChunk) error {
+		if shipped.Has(cc.H) {
+			return nil
+		}
+		c, err := cc.ToChunk()
+		if err != nil {
+			return err
+		}
+		addrs, err := types.AddrsFromNomsValue(ctx, c, nbf)
+		if err != nil {
+			return err
+		}
+		refs.InsertAll(addrs)
+		shipped.Insert(cc.H)
+		return tw.AddCmpChunk(cc)
+	})
+	if err != nil {
+		return nbs.JournalPosition{}, err
+	}
+
+	for h := range shipped {
+		refs.Remove(h)
+	}
+	if refs.Size() > 0 {
+		absent, err := dest.HasMany(ctx, refs)
+		if err != nil {
+			return nbs.JournalPosition{}, err
+		}
+		if absent.Size() > 0 {
+			return nbs.JournalPosition{}, errJournalRefsMissing
+		}
+	}
+
+	if tw.ChunkCount() == 0 {
+		return end, nil
+	}
+	id, err := tw.Finish()
+	if err != nil {
+		return nbs.JournalPosition{}, err
+	}
+	err = tfs.WriteTableFile(ctx, id, tw.ChunkCount(), tw.GetMD5(), func() (io.ReadCloser, uint64, error) {
+		rc, err := tw.Reader()
+		if err != nil {
+			return nil, 0, err
+		}
+		return rc, tw.ContentLength(), nil
+	})
+	if err != nil {
+		return nbs.JournalPosition{}, err
+	}
+	err = tfs.AddTableFilesToManifest(ctx, map[string]int{id: tw.ChunkCount()})
+	if err != nil {
+		return nbs.JournalPosition{}, err
+	}
+	return end, nil
+}
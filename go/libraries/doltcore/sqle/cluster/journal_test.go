@@ -0,0 +1,120 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func newJournalTestStores(t *testing.T) (src, dest *nbs.NomsBlockStore) {
+	ctx := context.Background()
+	nbf := types.Format_LD_1.VersionString()
+	q := nbs.NewUnlimitedMemQuotaProvider()
+	src, err := nbs.NewLocalJournalingStore(ctx, nbf, t.TempDir(), q)
+	require.NoError(t, err)
+	t.Cleanup(func() { src.Close() })
+	dest, err = nbs.NewLocalStore(ctx, nbf, t.TempDir(), 1<<20, q)
+	require.NoError(t, err)
+	t.Cleanup(func() { dest.Close() })
+	return src, dest
+}
+
+// writeAndCommit writes |v| to |vs| and makes it the root of its store.
+func writeAndCommit(t *testing.T, vs *types.ValueStore, v types.Value) hash.Hash {
+	ctx := context.Background()
+	ref, err := vs.WriteValue(ctx, v)
+	require.NoError(t, err)
+	last, err := vs.Root(ctx)
+	require.NoError(t, err)
+	ok, err := vs.Commit(ctx, ref.TargetHash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return ref.TargetHash()
+}
+
+func TestShipJournalChunks(t *testing.T) {
+	ctx := context.Background()
+	src, dest := newJournalTestStores(t)
+	vs := types.NewValueStore(src)
+
+	writeAndCommit(t, vs, types.String("first"))
+	pos, ok, err := src.JournalEnd()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	leaf, err := vs.WriteValue(ctx, types.String("leaf"))
+	require.NoError(t, err)
+	lst, err := types.NewList(ctx, vs, leaf)
+	require.NoError(t, err)
+	toPush := writeAndCommit(t, vs, lst)
+
+	end, err := shipJournalChunks(ctx, src, types.Format_LD_1, dest, t.TempDir(), pos, toPush)
+	require.NoError(t, err)
+	latest, _, err := src.JournalEnd()
+	require.NoError(t, err)
+	assert.Equal(t, latest, end)
+
+	absent, err := dest.HasMany(ctx, hash.NewHashSet(toPush, leaf.TargetHash()))
+	require.NoError(t, err)
+	assert.Empty(t, absent)
+
+	// shipping again from the end sends nothing and leaves the position alone
+	again, err := shipJournalChunks(ctx, src, types.Format_LD_1, dest, t.TempDir(), end, toPush)
+	require.NoError(t, err)
+	assert.Equal(t, end, again)
+}
+
+func TestShipJournalChunksRefsMissing(t *testing.T) {
+	ctx := context.Background()
+	src, dest := newJournalTestStores(t)
+	vs := types.NewValueStore(src)
+
+	// |leaf| is in the journal before |pos|, and never made it to |dest|.
+	leaf := writeAndCommit(t, vs, types.String("leaf"))
+	pos, ok, err := src.JournalEnd()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ref, err := types.NewRef(types.String("leaf"), types.Format_LD_1)
+	require.NoError(t, err)
+	lst, err := types.NewList(ctx, vs, ref)
+	require.NoError(t, err)
+	toPush := writeAndCommit(t, vs, lst)
+
+	_, err = shipJournalChunks(ctx, src, types.Format_LD_1, dest, t.TempDir(), pos, toPush)
+	assert.ErrorIs(t, err, errJournalRefsMissing)
+
+	absent, err := dest.HasMany(ctx, hash.NewHashSet(toPush, leaf))
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(toPush, leaf), absent)
+}
+
+func TestShipJournalChunksPositionLost(t *testing.T) {
+	ctx := context.Background()
+	src, dest := newJournalTestStores(t)
+	vs := types.NewValueStore(src)
+
+	toPush := writeAndCommit(t, vs, types.String("first"))
+	_, err := shipJournalChunks(ctx, src, types.Format_LD_1, dest, t.TempDir(), nbs.JournalPosition{}, toPush)
+	assert.ErrorIs(t, err, nbs.ErrJournalPositionLost)
+}
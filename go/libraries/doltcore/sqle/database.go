@@ -430,6 +430,12 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewMergeStatusTable(db.RevisionQualifiedName()), true
 	case doltdb.TagsTableName:
 		dt, found = dtables.NewTagsTable(ctx, db.ddb), true
+	case doltdb.StatementStatsTableName:
+		dt, found = dtables.NewStatementStatsTable(db.AliasedName()), true
+	case doltdb.StorageUsageTableName:
+		dt, found = dtables.NewStorageUsageTable(db.AliasedName(), db.ddb), true
+	case doltdb.OperationStatusTableName:
+		dt, found = dtables.NewOperationStatusTable(), true
 	case dtables.AccessTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -450,6 +456,18 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 			return nil, false, err
 		}
 		dt, found = dtables.NewIgnoreTable(ctx, db.ddb, backingTable), true
+	case doltdb.MergeDriversTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.MergeDriversTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		dt, found = dtables.NewMergeDriversTable(ctx, db.ddb, backingTable), true
+	case doltdb.ExternalTablesTableName:
+		backingTable, _, err := db.getTable(ctx, root, doltdb.ExternalTablesTableName)
+		if err != nil {
+			return nil, false, err
+		}
+		dt, found = dtables.NewExternalTablesTable(ctx, db.ddb, backingTable), true
 	}
 
 	if found {
@@ -457,7 +475,36 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 	}
 
 	// TODO: this should reuse the root, not lookup the db state again
-	return db.getTable(ctx, root, tblName)
+	tbl, ok, err := db.getTable(ctx, root, tblName)
+	if err != nil || ok {
+		return tbl, ok, err
+	}
+
+	// tables of the database take precedence over external tables with the same name
+	return db.getExternalTable(ctx, root, tblName)
+}
+
+// defaultAsOf returns the revision tables are read as of when a query doesn't specify one, as set by
+// @@dolt_default_as_of, or nil if it isn't set. Values that parse as a datetime are returned as a time.Time, and all
+// others as a commit spec string.
+func defaultAsOf(ctx *sql.Context) (interface{}, error) {
+	val, err := ctx.GetSessionVariable(ctx, dsess.DefaultAsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	asOf, ok := val.(string)
+	if !ok || len(asOf) == 0 {
+		return nil, nil
+	}
+
+	if t, _, err := types.Datetime.Convert(asOf); err == nil {
+		if t, ok := t.(time.Time); ok {
+			return t, nil
+		}
+	}
+
+	return asOf, nil
 }
 
 // resolveAsOf resolves given expression to a commit, if one exists.
@@ -471,6 +518,9 @@ func resolveAsOf(ctx *sql.Context, db Database, asOf interface{}) (*doltdb.Commi
 		return resolveAsOfTime(ctx, db.ddb, head, x)
 	case string:
 		return resolveAsOfCommitRef(ctx, db, head, x)
+	case []byte:
+		// prepared statements bind the string arguments of clients as bytes
+		return resolveAsOfCommitRef(ctx, db, head, string(x))
 	default:
 		return nil, nil, fmt.Errorf("unsupported AS OF type %T", asOf)
 	}
@@ -526,7 +576,8 @@ func resolveAsOfCommitRef(ctx *sql.Context, db Database, head ref.DoltRef, commi
 
 	if commitRef == doltdb.Working || commitRef == doltdb.Staged {
 		sess := dsess.DSessFromSess(ctx.Session)
-		root, _, _, err := sess.ResolveRootForRef(ctx, ctx.GetCurrentDatabase(), commitRef)
+		// the working and staged roots are those of |db|, which in a query across databases may not be the current one
+		root, _, _, err := sess.ResolveRootForRef(ctx, db.RevisionQualifiedName(), commitRef)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -740,6 +791,9 @@ func (db Database) DropTable(ctx *sql.Context, tableName string) error {
 	if doltdb.IsNonAlterableSystemTable(tableName) {
 		return ErrSystemTableAlter.New(tableName)
 	}
+	if err := lockTableMetadata(ctx, db, tableName, true); err != nil {
+		return err
+	}
 
 	return db.dropTable(ctx, tableName)
 }
@@ -857,6 +911,10 @@ func (db Database) CreateTable(ctx *sql.Context, tableName string, sch sql.Prima
 		return ErrInvalidTableName.New(tableName)
 	}
 
+	if err := lockTableMetadata(ctx, db, tableName, true); err != nil {
+		return err
+	}
+
 	return db.createSqlTable(ctx, tableName, sch, collation)
 }
 
@@ -878,6 +936,10 @@ func (db Database) CreateIndexedTable(ctx *sql.Context, tableName string, sch sq
 		return ErrInvalidTableName.New(tableName)
 	}
 
+	if err := lockTableMetadata(ctx, db, tableName, true); err != nil {
+		return err
+	}
+
 	return db.createIndexedSqlTable(ctx, tableName, sch, idxDef, collation)
 }
 
@@ -1051,6 +1113,13 @@ func (db Database) RenameTable(ctx *sql.Context, oldName, newName string) error
 		return sql.ErrTableAlreadyExists.New(newName)
 	}
 
+	if err := lockTableMetadata(ctx, db, oldName, true); err != nil {
+		return err
+	}
+	if err := lockTableMetadata(ctx, db, newName, true); err != nil {
+		return err
+	}
+
 	newRoot, err := renameTable(ctx, root, oldName, newName)
 
 	if err != nil {
@@ -36,6 +36,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqlserver"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas/pull"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -93,7 +94,7 @@ func NewDoltDatabaseProviderWithDatabases(defaultBranch string, fs filesys.Files
 
 	dbLocations := make(map[string]filesys.Filesys, len(locations))
 	for i, dbLocation := range locations {
-		dbLocations[databases[i].Name()] = dbLocation
+		dbLocations[strings.ToLower(databases[i].Name())] = dbLocation
 	}
 
 	funcs := make(map[string]sql.Function, len(dfunctions.DoltFunctions))
@@ -343,12 +344,48 @@ func (p DoltDatabaseProvider) allRevisionDbs(ctx *sql.Context, db dsess.SqlDatab
 }
 
 func (p DoltDatabaseProvider) GetRemoteDB(ctx context.Context, format *types.NomsBinFormat, r env.Remote, withCaching bool) (*doltdb.DoltDB, error) {
+	r = withSqlServerCreds(ctx, r)
 	if withCaching {
 		return r.GetRemoteDB(ctx, format, p.remoteDialer)
 	}
 	return r.GetRemoteDBWithoutCaching(ctx, format, p.remoteDialer)
 }
 
+// withSqlServerCreds returns |r| authenticating with the credentials registered with DOLT_LOGIN for the session's
+// user, or for the server, if |r| is a remotesapi remote that doesn't configure its own credentials. The global config
+// is read again each time, so that newly registered credentials are used without restarting the server.
+func withSqlServerCreds(ctx context.Context, r env.Remote) env.Remote {
+	if !strings.HasPrefix(r.Url, dbfactory.HTTPSScheme+"://") && !strings.HasPrefix(r.Url, dbfactory.HTTPScheme+"://") {
+		return r
+	}
+	for _, param := range dbfactory.GRPCCredsParams {
+		if _, ok := r.Params[param]; ok {
+			return r
+		}
+	}
+
+	var user string
+	if sqlCtx, ok := ctx.(*sql.Context); ok && sqlCtx.Session != nil {
+		user = sqlCtx.Client().User
+	}
+	cfg, err := env.LoadDoltCliConfig(env.GetCurrentUserHomeDir, filesys.LocalFS)
+	if err != nil {
+		return r
+	}
+	kid := env.GetSqlServerCredsKeyID(cfg, user)
+	if kid == "" {
+		return r
+	}
+
+	params := make(map[string]string, len(r.Params)+1)
+	for k, v := range r.Params {
+		params[k] = v
+	}
+	params[dbfactory.GRPCCredsKeyParam] = kid
+	r.Params = params
+	return r
+}
+
 func (p DoltDatabaseProvider) CreateDatabase(ctx *sql.Context, name string) error {
 	return p.CreateCollatedDatabase(ctx, name, sql.Collation_Default)
 }
@@ -531,6 +568,30 @@ func (p DoltDatabaseProvider) CloneDatabaseFromRemote(
 	return ConfigureReplicationDatabaseHook(ctx, p, dbName, dEnv)
 }
 
+// cloneOperationProg returns an actions.CloneProgFunc that reports the chunks of the table files downloaded while
+// cloning as the progress of |op|.
+func cloneOperationProg(op *dsess.Operation) actions.CloneProgFunc {
+	return func(eventCh <-chan pull.TableFileEvent) {
+		op.SetPhase("downloading", "chunks", 0)
+		var total, downloaded uint64
+		for evt := range eventCh {
+			switch evt.EventType {
+			case pull.Listed:
+				for _, tf := range evt.TableFiles {
+					total += uint64(tf.NumChunks())
+				}
+			case pull.DownloadSuccess:
+				for _, tf := range evt.TableFiles {
+					downloaded += uint64(tf.NumChunks())
+				}
+			default:
+				continue
+			}
+			op.SetProgress(downloaded, total)
+		}
+	}
+}
+
 // cloneDatabaseFromRemote encapsulates the inner logic for cloning a database so that if any error
 // is returned by this function, the caller can capture the error and safely clean up the failed
 // clone directory before returning the error to the user. This function should not be used directly;
@@ -546,7 +607,8 @@ func (p DoltDatabaseProvider) cloneDatabaseFromRemote(
 
 	// TODO: params for AWS, others that need them
 	r := env.NewRemote(remoteName, remoteUrl, nil)
-	srcDB, err := r.GetRemoteDB(ctx, types.Format_Default, p.remoteDialer)
+	srcRemote := withSqlServerCreds(ctx, r)
+	srcDB, err := srcRemote.GetRemoteDB(ctx, types.Format_Default, p.remoteDialer)
 	if err != nil {
 		return nil, err
 	}
@@ -556,7 +618,7 @@ func (p DoltDatabaseProvider) cloneDatabaseFromRemote(
 		return nil, err
 	}
 
-	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, dEnv)
+	err = actions.CloneRemote(ctx, srcDB, remoteName, branch, false, dEnv, cloneOperationProg(dsess.GlobalOperations.Current(ctx)))
 	if err != nil {
 		return nil, err
 	}
@@ -861,7 +923,12 @@ func initialDbState(ctx context.Context, db dsess.SqlDatabase, branch string) (d
 
 	var retainedErr error
 
-	headCommit, err := ddb.ResolveCommitRef(ctx, r)
+	// the working set is resolved at the same root as the head commit when it's first accessed
+	nomsRoot, err := ddb.NomsRoot(ctx)
+	if err != nil {
+		return dsess.InitialDbState{}, err
+	}
+	headCommit, err := ddb.ResolveCommitRefAtRoot(ctx, r, nomsRoot)
 	if err == doltdb.ErrBranchNotFound {
 		retainedErr = err
 		err = nil
@@ -870,17 +937,13 @@ func initialDbState(ctx context.Context, db dsess.SqlDatabase, branch string) (d
 		return dsess.InitialDbState{}, err
 	}
 
-	var ws *doltdb.WorkingSet
+	var loadWorkingSet dsess.WorkingSetLoader
 	if retainedErr == nil {
 		workingSetRef, err := ref.WorkingSetRefForHead(r)
 		if err != nil {
 			return dsess.InitialDbState{}, err
 		}
-
-		ws, err = db.DbData().Ddb.ResolveWorkingSet(ctx, workingSetRef)
-		if err != nil {
-			return dsess.InitialDbState{}, err
-		}
+		loadWorkingSet = dsess.NewWorkingSetLoader(ddb, workingSetRef, nomsRoot)
 	}
 
 	remotes, err := rsr.GetRemotes()
@@ -899,14 +962,14 @@ func initialDbState(ctx context.Context, db dsess.SqlDatabase, branch string) (d
 	}
 
 	return dsess.InitialDbState{
-		Db:         db,
-		HeadCommit: headCommit,
-		WorkingSet: ws,
-		DbData:     db.DbData(),
-		Remotes:    remotes,
-		Branches:   branches,
-		Backups:    backups,
-		Err:        retainedErr,
+		Db:             db,
+		HeadCommit:     headCommit,
+		LoadWorkingSet: loadWorkingSet,
+		DbData:         db.DbData(),
+		Remotes:        remotes,
+		Branches:       branches,
+		Backups:        backups,
+		Err:            retainedErr,
 	}, nil
 }
 
@@ -1157,7 +1220,9 @@ func (p DoltDatabaseProvider) SessionDatabase(ctx *sql.Context, name string) (ds
 func (p DoltDatabaseProvider) Function(_ *sql.Context, name string) (sql.Function, error) {
 	fn, ok := p.functions[strings.ToLower(name)]
 	if !ok {
-		return nil, sql.ErrFunctionNotFound.New(name)
+		if fn, ok = dfunctions.UserFunction(name); !ok {
+			return nil, sql.ErrFunctionNotFound.New(name)
+		}
 	}
 	return fn, nil
 }
@@ -1195,8 +1260,20 @@ func (p DoltDatabaseProvider) TableFunction(_ *sql.Context, name string) (sql.Ta
 	case "dolt_patch":
 		dtf := &PatchTableFunction{}
 		return dtf, nil
+	case "dolt_grep":
+		dtf := &GrepTableFunction{}
+		return dtf, nil
+	case "dolt_column_history":
+		dtf := &ColumnHistoryTableFunction{}
+		return dtf, nil
+	case "dolt_changes":
+		dtf := &ChangesTableFunction{}
+		return dtf, nil
 	}
 
+	if tf, ok := dfunctions.UserTableFunction(name); ok {
+		return tf, nil
+	}
 	return nil, sql.ErrTableFunctionNotFound.New(name)
 }
 
@@ -1312,11 +1389,6 @@ func initialStateForBranchDb(ctx *sql.Context, srcDb dsess.SqlDatabase) (dsess.I
 		return dsess.InitialDbState{}, err
 	}
 
-	ws, err := srcDb.DbData().Ddb.ResolveWorkingSetAtRoot(ctx, wsRef, rootHash)
-	if err != nil {
-		return dsess.InitialDbState{}, err
-	}
-
 	static := staticRepoState{
 		branch:          branch,
 		RepoStateWriter: srcDb.DbData().Rsw,
@@ -1339,9 +1411,9 @@ func initialStateForBranchDb(ctx *sql.Context, srcDb dsess.SqlDatabase) (dsess.I
 	}
 
 	init := dsess.InitialDbState{
-		Db:         srcDb,
-		HeadCommit: cm,
-		WorkingSet: ws,
+		Db:             srcDb,
+		HeadCommit:     cm,
+		LoadWorkingSet: dsess.NewWorkingSetLoader(srcDb.DbData().Ddb, wsRef, rootHash),
 		DbData: env.DbData{
 			Ddb: srcDb.DbData().Ddb,
 			Rsw: static,
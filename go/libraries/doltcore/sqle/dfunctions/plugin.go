@@ -0,0 +1,45 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo && (linux || darwin || freebsd)
+
+package dfunctions
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// LoadPlugin opens the Go plugin at |path| and registers the functions and table functions of the Plugin it exports
+// as PluginSymbol. The plugin must be built with the same version of Go and of Dolt's dependencies as the binary
+// loading it. Go plugins need cgo, so only binaries built with cgo enabled on Linux, macOS or FreeBSD can load them.
+// The release binaries are built without cgo, and LoadPlugin returns ErrPluginsNotSupported in them.
+func LoadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("error loading plugin %s: %w", path, err)
+	}
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return fmt.Errorf("error loading plugin %s: %w", path, err)
+	}
+	dp, ok := sym.(Plugin)
+	if !ok {
+		return fmt.Errorf("error loading plugin %s: %s does not implement dfunctions.Plugin", path, PluginSymbol)
+	}
+	if err = RegisterPlugin(dp); err != nil {
+		return fmt.Errorf("error loading plugin %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cgo || !(linux || darwin || freebsd)
+
+package dfunctions
+
+import "fmt"
+
+// LoadPlugin returns ErrPluginsNotSupported. Go plugins need cgo, and this binary was built without it or for a
+// platform without plugin support. The release binaries are built without cgo; Dolt must be built from source with
+// CGO_ENABLED=1 on Linux, macOS or FreeBSD to load plugins.
+func LoadPlugin(path string) error {
+	return fmt.Errorf("error loading plugin %s: %w", path, ErrPluginsNotSupported)
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
+)
+
+// PluginSymbol is the symbol a Go plugin loaded with LoadPlugin exports, which must implement Plugin. For example:
+//
+//	type myPlugin struct{}
+//	func (myPlugin) Functions() []sql.Function { ... }
+//	func (myPlugin) TableFunctions() []sql.TableFunction { ... }
+//	var DoltPlugin myPlugin
+const PluginSymbol = "DoltPlugin"
+
+// ErrPluginsNotSupported is returned by LoadPlugin in binaries which can't load Go plugins.
+var ErrPluginsNotSupported = errors.New("plugins are only supported by dolt binaries built with cgo on Linux, macOS or FreeBSD")
+
+// Plugin is a set of user-defined functions and table functions.
+type Plugin interface {
+	Functions() []sql.Function
+	TableFunctions() []sql.TableFunction
+}
+
+// reservedPrefix begins the names of the functions and table functions added by Dolt, which user-defined functions
+// can't use.
+const reservedPrefix = "dolt_"
+
+var userFunctions = struct {
+	mu         sync.RWMutex
+	funcs      map[string]sql.Function
+	tableFuncs map[string]sql.TableFunction
+}{
+	funcs:      make(map[string]sql.Function),
+	tableFuncs: make(map[string]sql.TableFunction),
+}
+
+// builtInTableFunctions are the names of the table functions built into go-mysql-server, which user-defined
+// functions can't use. JSON_TABLE is part of its grammar, and its in-memory database provider has SEQUENCE_TABLE.
+var builtInTableFunctions = []string{"json_table", memory.IntSequenceTable{}.Name()}
+
+// RegisterFunction adds a user-defined function, which is available to every database provider. It returns an error
+// if a built-in function, a function added by Dolt or another user-defined function has the same name.
+func RegisterFunction(fn sql.Function) error {
+	return registerFunctions([]sql.Function{fn}, nil)
+}
+
+// RegisterTableFunction adds a user-defined table function, which is available to every database provider. It
+// returns an error if a built-in table function or another user-defined table function has the same name.
+func RegisterTableFunction(fn sql.TableFunction) error {
+	return registerFunctions(nil, []sql.TableFunction{fn})
+}
+
+// RegisterPlugin registers the functions and table functions of |p|. If any of them can't be registered, none of them
+// are.
+func RegisterPlugin(p Plugin) error {
+	return registerFunctions(p.Functions(), p.TableFunctions())
+}
+
+// registerFunctions registers |funcs| and |tableFuncs| once every name has been checked, so that either all of them
+// are registered or none are.
+func registerFunctions(funcs []sql.Function, tableFuncs []sql.TableFunction) error {
+	userFunctions.mu.Lock()
+	defer userFunctions.mu.Unlock()
+
+	funcNames := make(map[string]sql.Function, len(funcs))
+	for _, fn := range funcs {
+		name := strings.ToLower(fn.FunctionName())
+		if err := checkUserFunctionName(name); err != nil {
+			return err
+		}
+		for _, builtIn := range function.BuiltIns {
+			if strings.EqualFold(builtIn.FunctionName(), name) {
+				return fmt.Errorf("function %s is a built-in function", name)
+			}
+		}
+		for _, doltFn := range DoltFunctions {
+			if strings.EqualFold(doltFn.FunctionName(), name) {
+				return fmt.Errorf("function %s is a dolt function", name)
+			}
+		}
+		if _, ok := userFunctions.funcs[name]; ok {
+			return fmt.Errorf("function %s is already registered", name)
+		} else if _, ok = funcNames[name]; ok {
+			return fmt.Errorf("function %s is registered more than once", name)
+		}
+		funcNames[name] = fn
+	}
+
+	tableFuncNames := make(map[string]sql.TableFunction, len(tableFuncs))
+	for _, fn := range tableFuncs {
+		name := strings.ToLower(fn.Name())
+		if err := checkUserFunctionName(name); err != nil {
+			return err
+		}
+		if _, ok := userFunctions.tableFuncs[name]; ok {
+			return fmt.Errorf("table function %s is already registered", name)
+		} else if _, ok = tableFuncNames[name]; ok {
+			return fmt.Errorf("table function %s is registered more than once", name)
+		}
+		tableFuncNames[name] = fn
+	}
+
+	for name, fn := range funcNames {
+		userFunctions.funcs[name] = fn
+	}
+	for name, fn := range tableFuncNames {
+		userFunctions.tableFuncs[name] = fn
+	}
+	return nil
+}
+
+func checkUserFunctionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("functions must have a name")
+	}
+	if strings.HasPrefix(name, reservedPrefix) {
+		return fmt.Errorf("function %s can't be registered; names beginning with %s are reserved", name, reservedPrefix)
+	}
+	for _, builtIn := range builtInTableFunctions {
+		if name == builtIn {
+			return fmt.Errorf("function %s is a built-in table function", name)
+		}
+	}
+	return nil
+}
+
+// UserFunction returns the user-defined function named |name|, if there is one.
+func UserFunction(name string) (sql.Function, bool) {
+	userFunctions.mu.RLock()
+	defer userFunctions.mu.RUnlock()
+	fn, ok := userFunctions.funcs[strings.ToLower(name)]
+	return fn, ok
+}
+
+// UserTableFunction returns the user-defined table function named |name|, if there is one.
+func UserTableFunction(name string) (sql.TableFunction, bool) {
+	userFunctions.mu.RLock()
+	defer userFunctions.mu.RUnlock()
+	fn, ok := userFunctions.tableFuncs[strings.ToLower(name)]
+	return fn, ok
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const SnapshotCommitFuncName = "dolt_snapshot_commit"
+
+// SnapshotCommitFunc returns the hash of the HEAD commit that a snapshot session (@@dolt_snapshot_session) has pinned
+// for the current database, or NULL when the session is not in snapshot mode.
+type SnapshotCommitFunc struct {
+}
+
+// NewSnapshotCommitFunc creates a new SnapshotCommitFunc expression.
+func NewSnapshotCommitFunc() sql.Expression {
+	return &SnapshotCommitFunc{}
+}
+
+// Eval implements the Expression interface.
+func (sc *SnapshotCommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	if !dSess.SnapshotActive() {
+		return nil, nil
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if dbName == "" {
+		return nil, sql.ErrNoDatabaseSelected.New()
+	}
+
+	cm, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.String(), nil
+}
+
+// String implements the Stringer interface.
+func (sc *SnapshotCommitFunc) String() string {
+	return fmt.Sprint("DOLT_SNAPSHOT_COMMIT()")
+}
+
+// IsNullable implements the Expression interface.
+func (sc *SnapshotCommitFunc) IsNullable() bool {
+	return true
+}
+
+// Resolved implements the Expression interface.
+func (*SnapshotCommitFunc) Resolved() bool {
+	return true
+}
+
+func (sc *SnapshotCommitFunc) Type() sql.Type {
+	return types.Text
+}
+
+// Children implements the Expression interface.
+func (*SnapshotCommitFunc) Children() []sql.Expression {
+	return nil
+}
+
+// WithChildren implements the Expression interface.
+func (sc *SnapshotCommitFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(sc, len(children), 0)
+	}
+	return NewSnapshotCommitFunc(), nil
+}
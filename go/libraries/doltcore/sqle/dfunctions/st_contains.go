@@ -0,0 +1,28 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/spatial"
+)
+
+const STContainsFuncName = "st_contains"
+
+// NewSTContains returns an ST_CONTAINS(g1, g2) sql function. |g1| contains |g2| exactly when |g2| is within |g1|, so
+// it's built as ST_WITHIN(g2, g1), which the analyzer knows how to answer with a spatial index.
+func NewSTContains(g1, g2 sql.Expression) sql.Expression {
+	return spatial.NewWithin(g2, g1)
+}
@@ -0,0 +1,624 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var ErrChangesNotAncestor = errors.NewKind("dolt_changes: the starting commit %s is not an ancestor of the last commit")
+
+var _ sql.TableFunction = (*ChangesTableFunction)(nil)
+var _ sql.ExecSourceRel = (*ChangesTableFunction)(nil)
+
+// ChangesTableFunction returns the row level changes made to a table by each commit after a starting commit, up to
+// HEAD or a given commit, oldest first. It's a changelog of the table: a consumer that applies the changes in order to
+// the table as of the starting commit ends up with the table as of the last commit, and can resume from the last
+// commit it consumed.
+//
+// The commits are those on the first parent history of the last commit, so a merge commit's changes are those the
+// merge made to the branch it was merged into, and the commits of the merged branch aren't visited.
+type ChangesTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr  sql.Expression
+	fromCommitExpr sql.Expression
+	toCommitExpr   sql.Expression
+	database       sql.Database
+}
+
+var changesTableSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: sqltypes.Text},
+	&sql.Column{Name: "committer", Type: sqltypes.Text},
+	&sql.Column{Name: "date", Type: sqltypes.Datetime},
+	&sql.Column{Name: "op", Type: sqltypes.Text},
+	&sql.Column{Name: "primary_key", Type: sqltypes.JSON},
+	&sql.Column{Name: "before", Type: sqltypes.JSON, Nullable: true},
+	&sql.Column{Name: "after", Type: sqltypes.JSON, Nullable: true},
+}
+
+const (
+	changeOpInsert = "insert"
+	changeOpUpdate = "update"
+	changeOpDelete = "delete"
+)
+
+// NewInstance creates a new instance of TableFunction interface
+func (ctf *ChangesTableFunction) NewInstance(ctx *sql.Context, db sql.Database, exprs []sql.Expression) (sql.Node, error) {
+	newInstance := &ChangesTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (ctf *ChangesTableFunction) Database() sql.Database {
+	return ctf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (ctf *ChangesTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nctf := *ctf
+	nctf.database = database
+	return &nctf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (ctf *ChangesTableFunction) Name() string {
+	return "dolt_changes"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (ctf *ChangesTableFunction) Resolved() bool {
+	for _, expr := range ctf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (ctf *ChangesTableFunction) String() string {
+	args := make([]string, 0, 3)
+	for _, expr := range ctf.Expressions() {
+		args = append(args, expr.String())
+	}
+	return fmt.Sprintf("DOLT_CHANGES(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (ctf *ChangesTableFunction) Schema() sql.Schema {
+	return changesTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (ctf *ChangesTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (ctf *ChangesTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return ctf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (ctf *ChangesTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, err := expressionToString(ctf.ctx, ctf.tableNameExpr)
+	if err != nil {
+		return false
+	}
+
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(ctf.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (ctf *ChangesTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{ctf.tableNameExpr, ctf.fromCommitExpr}
+	if ctf.toCommitExpr != nil {
+		exprs = append(exprs, ctf.toCommitExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (ctf *ChangesTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 2 || len(expression) > 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(ctf.Name(), "2 or 3", len(expression))
+	}
+
+	for i, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(ctf.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(ctf.Name(), expr.String())
+		}
+		// the starting commit may be NULL, to return every change since the table was created
+		if i == 1 && sqltypes.IsNull(expr) {
+			continue
+		}
+		if !sqltypes.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(ctf.Name(), expr.String())
+		}
+	}
+
+	newCtf := *ctf
+	newCtf.tableNameExpr = expression[0]
+	newCtf.fromCommitExpr = expression[1]
+	newCtf.toCommitExpr = nil
+	if len(expression) == 3 {
+		newCtf.toCommitExpr = expression[2]
+	}
+
+	return &newCtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (ctf *ChangesTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	tableName, err := expressionToString(ctx, ctf.tableNameExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := ctf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", ctf.database)
+	}
+	ddb := sqledb.DbData().Ddb
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	headRef, err := sess.CWBHeadRef(ctx, sqledb.RevisionQualifiedName())
+	if err != nil && err != doltdb.ErrOperationNotSupportedInDetachedHead {
+		return nil, err
+	}
+
+	var toCommit *doltdb.Commit
+	if ctf.toCommitExpr != nil {
+		to, err := expressionToString(ctx, ctf.toCommitExpr)
+		if err != nil {
+			return nil, err
+		}
+		if toCommit, err = resolveCommit(ctx, ddb, headRef, to); err != nil {
+			return nil, err
+		}
+	} else {
+		if toCommit, err = sess.GetHeadCommit(ctx, sqledb.RevisionQualifiedName()); err != nil {
+			return nil, err
+		}
+	}
+
+	var fromCommit *doltdb.Commit
+	fromVal, err := ctf.fromCommitExpr.Eval(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if fromVal != nil {
+		from, ok := fromVal.(string)
+		if !ok {
+			return nil, fmt.Errorf("received '%v' when expecting string", fromVal)
+		}
+		if fromCommit, err = resolveCommit(ctx, ddb, headRef, from); err != nil {
+			return nil, err
+		}
+	}
+
+	// the table may have been dropped since the starting commit, whose changes include its rows being deleted
+	found, err := commitHasTable(ctx, ddb, toCommit, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !found && fromCommit != nil {
+		if found, err = commitHasTable(ctx, ddb, fromCommit, tableName); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	commits, err := FirstParentCommitsAfter(ctx, toCommit, fromCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &changesRowIter{
+		ddb:       ddb,
+		tableName: tableName,
+		commits:   commits,
+	}, nil
+}
+
+// FirstParentCommitsAfter returns the commits on the first parent history of |to| that come after |from|, oldest
+// first, or all of them if |from| is nil. |from| must be an ancestor of |to|. If it isn't on the first parent history
+// of |to|, because it was merged in from another branch, the commits start at the merge that brought it in.
+func FirstParentCommitsAfter(ctx context.Context, to, from *doltdb.Commit) ([]*doltdb.Commit, error) {
+	var fromHash hash.Hash
+	var fromHeight uint64
+	if from != nil {
+		ancestor, err := doltdb.GetCommitAncestor(ctx, to, from)
+		if err != nil {
+			return nil, err
+		}
+		ancHash, err := ancestor.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if fromHash, err = from.HashOf(); err != nil {
+			return nil, err
+		}
+		if ancHash != fromHash {
+			return nil, ErrChangesNotAncestor.New(fromHash.String())
+		}
+		if fromHeight, err = from.Height(); err != nil {
+			return nil, err
+		}
+	}
+
+	var commits []*doltdb.Commit
+	for cm := to; ; {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if from != nil {
+			height, err := cm.Height()
+			if err != nil {
+				return nil, err
+			}
+			if h == fromHash || height <= fromHeight {
+				break
+			}
+		}
+		commits = append(commits, cm)
+		if cm.NumParents() == 0 {
+			break
+		}
+		if cm, err = cm.GetParent(ctx, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+func commitHasTable(ctx *sql.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit, tableName string) (bool, error) {
+	entry, err := ddb.CommitRootEntry(ctx, cm)
+	if err != nil {
+		return false, err
+	}
+	_, _, ok := entry.TableHash(tableName)
+	return ok, nil
+}
+
+func commitHashString(cm *doltdb.Commit) string {
+	h, _ := cm.HashOf()
+	return h.String()
+}
+
+//------------------------------------
+// changesRowIter
+//------------------------------------
+
+var _ sql.RowIter = (*changesRowIter)(nil)
+
+// changesRowIter diffs the table at each commit against its first parent, returning a row for each row that changed.
+type changesRowIter struct {
+	ddb       *doltdb.DoltDB
+	tableName string
+	commits   []*doltdb.Commit
+	pending   []sql.Row
+}
+
+// changesTableVersion is the schema and row data of a version of the table whose changes are being returned.
+type changesTableVersion struct {
+	sch  schema.Schema
+	rows prolly.Map
+	ns   tree.NodeStore
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+func (itr *changesRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for len(itr.pending) == 0 {
+		if len(itr.commits) == 0 {
+			return nil, io.EOF
+		}
+		cm := itr.commits[0]
+		itr.commits = itr.commits[1:]
+
+		var err error
+		itr.pending, err = itr.changesAtCommit(ctx, cm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	row := itr.pending[0]
+	itr.pending = itr.pending[1:]
+	return row, nil
+}
+
+// changesAtCommit returns the rows describing the changes |cm| made to the table against its first parent, in
+// primary key order.
+func (itr *changesRowIter) changesAtCommit(ctx *sql.Context, cm *doltdb.Commit) ([]sql.Row, error) {
+	entry, err := itr.ddb.CommitRootEntry(ctx, cm)
+	if err != nil {
+		return nil, err
+	}
+	_, tblHash, ok := entry.TableHash(itr.tableName)
+
+	var parent *doltdb.Commit
+	if cm.NumParents() > 0 {
+		if parent, err = cm.GetParent(ctx, 0); err != nil {
+			return nil, err
+		}
+		pEntry, err := itr.ddb.CommitRootEntry(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		// the table is the same as in the parent, or in neither of them, so the commit didn't change it
+		if _, pHash, pOk := pEntry.TableHash(itr.tableName); pOk == ok && pHash == tblHash {
+			return nil, nil
+		}
+	} else if !ok {
+		return nil, nil
+	}
+
+	toRoot, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var fromRoot *doltdb.RootValue
+	if parent != nil {
+		if fromRoot, err = parent.GetRootValue(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	meta := entry.Meta
+	h := commitHashString(cm)
+	var rows []sql.Row
+	err = DiffTableRows(ctx, itr.tableName, fromRoot, toRoot, func(change RowChange) error {
+		key, err := changesJSON(change.Key)
+		if err != nil {
+			return err
+		}
+		var before, after interface{}
+		if change.Before != nil {
+			if before, err = changesJSON(change.Before); err != nil {
+				return err
+			}
+		}
+		if change.After != nil {
+			if after, err = changesJSON(change.After); err != nil {
+				return err
+			}
+		}
+
+		rows = append(rows, sql.NewRow(h, meta.Name, meta.Time(), change.Op, key, before, after))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// RowChange is a change to a row of a table. Its primary key, and its values before and after the change, are maps
+// of column names to values as they appear in JSON. Before is nil for an inserted row, and After for a deleted row.
+type RowChange struct {
+	Op     string
+	Key    map[string]interface{}
+	Before map[string]interface{}
+	After  map[string]interface{}
+}
+
+// DiffTableRows calls |cb| with each change to the rows of table |tableName| between |fromRoot| and |toRoot|, in
+// primary key order. A root that is nil or doesn't have the table is diffed as an empty table. Keyless tables, and
+// tables whose primary key changed, are not supported.
+func DiffTableRows(ctx context.Context, tableName string, fromRoot, toRoot *doltdb.RootValue, cb func(RowChange) error) error {
+	from, err := loadChangesTableVersion(ctx, fromRoot, tableName)
+	if err != nil {
+		return err
+	}
+	to, err := loadChangesTableVersion(ctx, toRoot, tableName)
+	if err != nil {
+		return err
+	}
+
+	// a table that was created or dropped is diffed against an empty table
+	if from == nil && to == nil {
+		return nil
+	} else if from == nil {
+		if from, err = emptyChangesTableVersion(ctx, to); err != nil {
+			return err
+		}
+	} else if to == nil {
+		if to, err = emptyChangesTableVersion(ctx, from); err != nil {
+			return err
+		}
+	}
+	if !schema.ColCollsAreEqual(from.sch.GetPKCols(), to.sch.GetPKCols()) {
+		return fmt.Errorf("the primary key of table '%s' changed, so its changes can't be matched to rows", tableName)
+	}
+
+	err = prolly.DiffMaps(ctx, from.rows, to.rows, func(ctx context.Context, diff tree.Diff) error {
+		key, err := to.keyValues(ctx, val.Tuple(diff.Key))
+		if err != nil {
+			return err
+		}
+		before, err := from.rowValues(ctx, val.Tuple(diff.Key), val.Tuple(diff.From))
+		if err != nil {
+			return err
+		}
+		after, err := to.rowValues(ctx, val.Tuple(diff.Key), val.Tuple(diff.To))
+		if err != nil {
+			return err
+		}
+		return cb(RowChange{Op: changeOp(diff.Type), Key: key, Before: before, After: after})
+	})
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// loadChangesTableVersion returns the version of the table in the root given, or nil if the root has no such table
+func loadChangesTableVersion(ctx context.Context, root *doltdb.RootValue, tableName string) (*changesTableVersion, error) {
+	if root == nil {
+		return nil, nil
+	}
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if tbl.Format() != types.Format_DOLT {
+		return nil, fmt.Errorf("row changes are not supported for the legacy storage format")
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, fmt.Errorf("row changes are not supported for keyless table '%s'", tableName)
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &changesTableVersion{
+		sch:  sch,
+		rows: durable.ProllyMapFromIndex(idx),
+		ns:   tbl.NodeStore(),
+	}, nil
+}
+
+func emptyChangesTableVersion(ctx context.Context, v *changesTableVersion) (*changesTableVersion, error) {
+	rows, err := prolly.NewMapFromTuples(ctx, v.ns, v.rows.KeyDesc(), v.rows.ValDesc())
+	if err != nil {
+		return nil, err
+	}
+	return &changesTableVersion{sch: v.sch, rows: rows, ns: v.ns}, nil
+}
+
+// keyValues returns the primary key in the key tuple given as a map of column names to values.
+func (v *changesTableVersion) keyValues(ctx context.Context, key val.Tuple) (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+	kd := v.rows.KeyDesc()
+	for i, col := range v.sch.GetPKCols().GetColumns() {
+		f, err := index.GetField(ctx, kd, i, key, v.ns)
+		if err != nil {
+			return nil, err
+		}
+		if obj[col.Name], err = changeValue(f); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// rowValues returns the row with the key and value tuples given as a map of column names to values, or nil if the
+// row doesn't exist.
+func (v *changesTableVersion) rowValues(ctx context.Context, key, value val.Tuple) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	obj, err := v.keyValues(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	vd := v.rows.ValDesc()
+	for i, col := range v.sch.GetNonPKCols().GetColumns() {
+		f, err := index.GetField(ctx, vd, i, value, v.ns)
+		if err != nil {
+			return nil, err
+		}
+		if obj[col.Name], err = changeValue(f); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// changeValue returns a column value as it appears in a JSON object: numbers, strings and JSON documents as
+// themselves, and other values, like dates and decimals, as strings.
+func changeValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil, bool, string, int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64:
+		return v, nil
+	case sqltypes.JSONDocument:
+		return v.Val, nil
+	default:
+		str, _, err := sqltypes.LongText.Convert(v)
+		return str, err
+	}
+}
+
+// changesJSON returns |obj| as a JSON document.
+func changesJSON(obj map[string]interface{}) (interface{}, error) {
+	doc, _, err := sqltypes.JSON.Convert(obj)
+	return doc, err
+}
+
+func changeOp(t tree.DiffType) string {
+	switch t {
+	case tree.AddedDiff:
+		return changeOpInsert
+	case tree.RemovedDiff:
+		return changeOpDelete
+	default:
+		return changeOpUpdate
+	}
+}
+
+func (itr *changesRowIter) Close(_ *sql.Context) error {
+	return nil
+}
@@ -0,0 +1,565 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var _ sql.TableFunction = (*ColumnHistoryTableFunction)(nil)
+var _ sql.ExecSourceRel = (*ColumnHistoryTableFunction)(nil)
+
+// ColumnHistoryTableFunction returns the commits reachable from HEAD that changed the value of a column of a table,
+// along with the value before and after each change. Changes can be limited to a single row by giving the values of
+// its primary key. Commits that didn't change the table are skipped using the commit root index, and the commits that
+// did are diffed against their parents, so only the rows that changed are read.
+type ColumnHistoryTableFunction struct {
+	ctx *sql.Context
+
+	tableNameExpr  sql.Expression
+	columnNameExpr sql.Expression
+	keyExprs       []sql.Expression
+	database       sql.Database
+}
+
+var columnHistoryTableSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: sqltypes.Text},
+	&sql.Column{Name: "committer", Type: sqltypes.Text},
+	&sql.Column{Name: "email", Type: sqltypes.Text},
+	&sql.Column{Name: "date", Type: sqltypes.Datetime},
+	&sql.Column{Name: "message", Type: sqltypes.Text},
+	&sql.Column{Name: "primary_key", Type: sqltypes.Text},
+	&sql.Column{Name: "diff_type", Type: sqltypes.Text},
+	&sql.Column{Name: "from_value", Type: sqltypes.LongText, Nullable: true},
+	&sql.Column{Name: "to_value", Type: sqltypes.LongText, Nullable: true},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (chtf *ColumnHistoryTableFunction) NewInstance(ctx *sql.Context, db sql.Database, exprs []sql.Expression) (sql.Node, error) {
+	newInstance := &ColumnHistoryTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (chtf *ColumnHistoryTableFunction) Database() sql.Database {
+	return chtf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (chtf *ColumnHistoryTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	nchtf := *chtf
+	nchtf.database = database
+	return &nchtf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (chtf *ColumnHistoryTableFunction) Name() string {
+	return "dolt_column_history"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (chtf *ColumnHistoryTableFunction) Resolved() bool {
+	for _, expr := range chtf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (chtf *ColumnHistoryTableFunction) String() string {
+	args := make([]string, 0, len(chtf.keyExprs)+2)
+	for _, expr := range chtf.Expressions() {
+		args = append(args, expr.String())
+	}
+	return fmt.Sprintf("DOLT_COLUMN_HISTORY(%s)", strings.Join(args, ", "))
+}
+
+// Schema implements the sql.Node interface.
+func (chtf *ColumnHistoryTableFunction) Schema() sql.Schema {
+	return columnHistoryTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (chtf *ColumnHistoryTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (chtf *ColumnHistoryTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return chtf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (chtf *ColumnHistoryTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, err := expressionToString(chtf.ctx, chtf.tableNameExpr)
+	if err != nil {
+		return false
+	}
+
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(chtf.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (chtf *ColumnHistoryTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{chtf.tableNameExpr, chtf.columnNameExpr}
+	return append(exprs, chtf.keyExprs...)
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (chtf *ColumnHistoryTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New(chtf.Name(), "2 or more", len(expression))
+	}
+
+	for i, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(chtf.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(chtf.Name(), expr.String())
+		}
+		// the table and column names must be strings, the primary key values may be of any type
+		if i < 2 && !sqltypes.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(chtf.Name(), expr.String())
+		}
+	}
+
+	newChtf := *chtf
+	newChtf.tableNameExpr = expression[0]
+	newChtf.columnNameExpr = expression[1]
+	newChtf.keyExprs = expression[2:]
+
+	return &newChtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (chtf *ColumnHistoryTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	tableName, err := expressionToString(ctx, chtf.tableNameExpr)
+	if err != nil {
+		return nil, err
+	}
+	columnName, err := expressionToString(ctx, chtf.columnNameExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := chtf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", chtf.database)
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	commit, err := sess.GetHeadCommit(ctx, sqledb.RevisionQualifiedName())
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, resolvedName, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+	tableName = resolvedName
+	if tbl.Format() != types.Format_DOLT {
+		return nil, fmt.Errorf("%s is not supported for the legacy storage format", chtf.Name())
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schema.IsKeyless(sch) {
+		return nil, fmt.Errorf("%s is not supported for keyless table '%s'", chtf.Name(), tableName)
+	}
+	if _, ok := sch.GetPKCols().GetByNameCaseInsensitive(columnName); ok {
+		return nil, fmt.Errorf("column '%s' is part of the primary key of table '%s'", columnName, tableName)
+	}
+	col, ok := sch.GetNonPKCols().GetByNameCaseInsensitive(columnName)
+	if !ok {
+		return nil, sql.ErrTableColumnNotFound.New(tableName, columnName)
+	}
+
+	var key sql.Row
+	if len(chtf.keyExprs) > 0 {
+		key, err = chtf.evaluateKey(ctx, sch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	child, err := commitwalk.GetTopologicalOrderIterator(ctx, sqledb.DbData().Ddb, []hash.Hash{h}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &columnHistoryRowIter{
+		ddb:        sqledb.DbData().Ddb,
+		child:      child,
+		tableName:  tableName,
+		columnName: col.Name,
+		keyCols:    sch.GetPKCols().GetColumns(),
+		key:        key,
+	}, nil
+}
+
+// evaluateKey returns the primary key values given as arguments, converted to the types of the primary key columns of
+// |sch|
+func (chtf *ColumnHistoryTableFunction) evaluateKey(ctx *sql.Context, sch schema.Schema) (sql.Row, error) {
+	pkCols := sch.GetPKCols().GetColumns()
+	if len(chtf.keyExprs) != len(pkCols) {
+		return nil, fmt.Errorf("%s expects %d primary key value(s), got %d", chtf.Name(), len(pkCols), len(chtf.keyExprs))
+	}
+
+	key := make(sql.Row, len(pkCols))
+	for i, expr := range chtf.keyExprs {
+		v, err := expr.Eval(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		key[i], _, err = pkCols[i].TypeInfo.ToSqlType().Convert(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+//------------------------------------
+// columnHistoryRowIter
+//------------------------------------
+
+var _ sql.RowIter = (*columnHistoryRowIter)(nil)
+
+// columnHistoryRowIter walks the commit history, returning a row for each row whose value of the column changed in
+// each commit.
+type columnHistoryRowIter struct {
+	ddb        *doltdb.DoltDB
+	child      doltdb.CommitItr
+	tableName  string
+	columnName string
+
+	// key is the primary key of the row to return changes to, or nil to return changes to every row. keyCols are the
+	// primary key columns of the table at HEAD, whose types |key| was converted to.
+	key     sql.Row
+	keyCols []schema.Column
+
+	pending []sql.Row
+}
+
+// columnVersion is the table, schema and row data of a version of the table whose history is being returned.
+type columnVersion struct {
+	sch  schema.Schema
+	rows prolly.Map
+	ns   tree.NodeStore
+	// idx is the index of the column in the value tuples of |rows|, or -1 if the version of the table has no such
+	// column.
+	idx int
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+func (itr *columnHistoryRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for len(itr.pending) == 0 {
+		h, cm, err := itr.child.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		itr.pending, err = itr.changesAtCommit(ctx, h, cm)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	row := itr.pending[0]
+	itr.pending = itr.pending[1:]
+	return row, nil
+}
+
+// changesAtCommit returns the rows describing the changes to the column made by the commit given. The changes of a
+// merge commit are those it made against its first parent that don't take the value of any of its other parents, so
+// that changes merged from another branch are only reported at the commits that made them.
+func (itr *columnHistoryRowIter) changesAtCommit(ctx *sql.Context, h hash.Hash, cm *doltdb.Commit) ([]sql.Row, error) {
+	entry, err := itr.ddb.CommitRootEntry(ctx, cm)
+	if err != nil {
+		return nil, err
+	}
+	_, tblHash, ok := entry.TableHash(itr.tableName)
+	if !ok {
+		return nil, nil
+	}
+
+	parents := make([]*doltdb.Commit, cm.NumParents())
+	for i := range parents {
+		if parents[i], err = cm.GetParent(ctx, i); err != nil {
+			return nil, err
+		}
+		pEntry, err := itr.ddb.CommitRootEntry(ctx, parents[i])
+		if err != nil {
+			return nil, err
+		}
+		// the table is the same as in one of the parents, so the commit didn't change it
+		if _, pHash, ok := pEntry.TableHash(itr.tableName); ok && pHash == tblHash {
+			return nil, nil
+		}
+	}
+
+	to, err := itr.loadVersion(ctx, cm)
+	if err != nil || to == nil || to.idx < 0 {
+		return nil, err
+	}
+
+	var from *columnVersion
+	if len(parents) > 0 {
+		from, err = itr.loadVersion(ctx, parents[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if from == nil {
+		// the table was created by this commit, so every row of it was added
+		from = &columnVersion{sch: to.sch, ns: to.ns, idx: -1}
+		from.rows, err = prolly.NewMapFromTuples(ctx, to.ns, to.rows.KeyDesc(), to.rows.ValDesc())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !schema.ColCollsAreEqual(from.sch.GetPKCols(), to.sch.GetPKCols()) {
+		// rows can't be matched across a change to the primary key, so dolt diff doesn't diff their data either
+		return nil, nil
+	}
+
+	others := make([]*columnVersion, 0, len(parents))
+	for _, p := range parents[1:] {
+		other, err := itr.loadVersion(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if other != nil && schema.ColCollsAreEqual(other.sch.GetPKCols(), to.sch.GetPKCols()) {
+			others = append(others, other)
+		}
+	}
+
+	meta := entry.Meta
+	var rows []sql.Row
+	err = prolly.DiffMaps(ctx, from.rows, to.rows, func(ctx context.Context, diff tree.Diff) error {
+		key, err := itr.decodeKey(ctx, to, val.Tuple(diff.Key))
+		if err != nil || key == nil {
+			return err
+		}
+
+		fromVal, err := from.value(ctx, val.Tuple(diff.From))
+		if err != nil {
+			return err
+		}
+		toVal, err := to.value(ctx, val.Tuple(diff.To))
+		if err != nil {
+			return err
+		}
+		if columnValuesEqual(fromVal, toVal) {
+			return nil
+		}
+
+		for _, other := range others {
+			var otherVal *string
+			var found bool
+			err = other.rows.Get(ctx, val.Tuple(diff.Key), func(_, v val.Tuple) (err error) {
+				if v == nil {
+					return nil
+				}
+				found = true
+				otherVal, err = other.value(ctx, v)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if found && columnValuesEqual(otherVal, toVal) {
+				return nil
+			}
+		}
+
+		rows = append(rows, sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description,
+			formatPrimaryKey(key), diffTypeString(diff.Type), columnValueToSql(fromVal), columnValueToSql(toVal)))
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// loadVersion returns the version of the table at the commit given, or nil if the commit has no such table
+func (itr *columnHistoryRowIter) loadVersion(ctx context.Context, cm *doltdb.Commit) (*columnVersion, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, itr.tableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &columnVersion{
+		sch:  sch,
+		rows: durable.ProllyMapFromIndex(idx),
+		ns:   tbl.NodeStore(),
+		idx:  -1,
+	}
+	if col, ok := sch.GetNonPKCols().GetByNameCaseInsensitive(itr.columnName); ok {
+		v.idx = sch.GetNonPKCols().IndexOf(col.Name)
+	}
+	return v, nil
+}
+
+// decodeKey returns the primary key values of the key tuple given, or nil if the iterator only returns changes to
+// another row
+func (itr *columnHistoryRowIter) decodeKey(ctx context.Context, v *columnVersion, tup val.Tuple) (sql.Row, error) {
+	kd := v.rows.KeyDesc()
+	key := make(sql.Row, kd.Count())
+	for i := range key {
+		var err error
+		if key[i], err = index.GetField(ctx, kd, i, tup, v.ns); err != nil {
+			return nil, err
+		}
+	}
+
+	if itr.key == nil {
+		return key, nil
+	}
+	for i := range key {
+		typ := itr.keyCols[i].TypeInfo.ToSqlType()
+		cmp, err := typ.Compare(key[i], itr.key[i])
+		if err != nil {
+			return nil, err
+		}
+		if cmp != 0 {
+			return nil, nil
+		}
+	}
+	return key, nil
+}
+
+// value returns the value of the column in the value tuple given, formatted as a string so that values can be compared
+// across changes to the column's type. It returns nil for NULL values, rows that don't exist and versions of the table
+// without the column.
+func (v *columnVersion) value(ctx context.Context, tup val.Tuple) (*string, error) {
+	if tup == nil || v.idx < 0 {
+		return nil, nil
+	}
+	f, err := index.GetField(ctx, v.rows.ValDesc(), v.idx, tup, v.ns)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	str, _, err := sqltypes.LongText.Convert(f)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := str.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type converting %v to a string: %T", f, str)
+	}
+	return &s, nil
+}
+
+func columnValuesEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func columnValueToSql(v *string) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func formatPrimaryKey(key sql.Row) string {
+	vals := make([]string, len(key))
+	for i, v := range key {
+		vals[i] = fmt.Sprintf("%v", v)
+	}
+	return strings.Join(vals, ", ")
+}
+
+func diffTypeString(t tree.DiffType) string {
+	switch t {
+	case tree.AddedDiff:
+		return "added"
+	case tree.RemovedDiff:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+func (itr *columnHistoryRowIter) Close(_ *sql.Context) error {
+	return nil
+}
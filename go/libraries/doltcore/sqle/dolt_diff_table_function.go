@@ -49,6 +49,10 @@ type DiffTableFunction struct {
 	sqlSch         sql.Schema
 	joiner         *rowconv.Joiner
 
+	// minKeyExpr and maxKeyExpr optionally bound the values of the first primary key column of the rows diffed
+	minKeyExpr sql.Expression
+	maxKeyExpr sql.Expression
+
 	tableDelta diff.TableDelta
 	fromDate   *types.Timestamp
 	toDate     *types.Timestamp
@@ -83,20 +87,26 @@ func (dtf *DiffTableFunction) WithDatabase(database sql.Database) (sql.Node, err
 
 // Expressions implements the sql.Expressioner interface
 func (dtf *DiffTableFunction) Expressions() []sql.Expression {
+	var exprs []sql.Expression
 	if dtf.dotCommitExpr != nil {
-		return []sql.Expression{
+		exprs = []sql.Expression{
 			dtf.dotCommitExpr, dtf.tableNameExpr,
 		}
+	} else {
+		exprs = []sql.Expression{
+			dtf.fromCommitExpr, dtf.toCommitExpr, dtf.tableNameExpr,
+		}
 	}
-	return []sql.Expression{
-		dtf.fromCommitExpr, dtf.toCommitExpr, dtf.tableNameExpr,
+	if dtf.minKeyExpr != nil {
+		exprs = append(exprs, dtf.minKeyExpr, dtf.maxKeyExpr)
 	}
+	return exprs
 }
 
 // WithExpressions implements the sql.Expressioner interface
 func (dtf *DiffTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
 	if len(expression) < 2 {
-		return nil, sql.ErrInvalidArgumentNumber.New(dtf.Name(), "2 to 3", len(expression))
+		return nil, sql.ErrInvalidArgumentNumber.New(dtf.Name(), "2 to 5", len(expression))
 	}
 
 	// TODO: For now, we will only support literal / fully-resolved arguments to the
@@ -114,18 +124,24 @@ func (dtf *DiffTableFunction) WithExpressions(expression ...sql.Expression) (sql
 
 	newDtf := *dtf
 	if strings.Contains(expression[0].String(), "..") {
-		if len(expression) != 2 {
-			return nil, sql.ErrInvalidArgumentNumber.New(fmt.Sprintf("%v with .. or ...", newDtf.Name()), 2, len(expression))
+		if len(expression) != 2 && len(expression) != 4 {
+			return nil, sql.ErrInvalidArgumentNumber.New(fmt.Sprintf("%v with .. or ...", newDtf.Name()), "2 or 4", len(expression))
 		}
 		newDtf.dotCommitExpr = expression[0]
 		newDtf.tableNameExpr = expression[1]
+		expression = expression[2:]
 	} else {
-		if len(expression) != 3 {
-			return nil, sql.ErrInvalidArgumentNumber.New(newDtf.Name(), 3, len(expression))
+		if len(expression) != 3 && len(expression) != 5 {
+			return nil, sql.ErrInvalidArgumentNumber.New(newDtf.Name(), "3 or 5", len(expression))
 		}
 		newDtf.fromCommitExpr = expression[0]
 		newDtf.toCommitExpr = expression[1]
 		newDtf.tableNameExpr = expression[2]
+		expression = expression[3:]
+	}
+	if len(expression) == 2 {
+		newDtf.minKeyExpr = expression[0]
+		newDtf.maxKeyExpr = expression[1]
 	}
 
 	fromCommitVal, toCommitVal, dotCommitVal, tableName, err := newDtf.evaluateArguments()
@@ -170,9 +186,57 @@ func (dtf *DiffTableFunction) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter,
 	ddb := sqledb.DbData().Ddb
 	dp := dtables.NewDiffPartition(dtf.tableDelta.ToTable, dtf.tableDelta.FromTable, toCommitStr, fromCommitStr, dtf.toDate, dtf.fromDate, dtf.tableDelta.ToSch, dtf.tableDelta.FromSch)
 
+	if dtf.minKeyExpr != nil {
+		keyRange, err := dtf.evaluateKeyRange(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if keyRange != nil {
+			dp = dp.WithKeyRange(keyRange)
+		}
+	}
+
 	return dtables.NewDiffPartitionRowIter(*dp, ddb, dtf.joiner), nil
 }
 
+// evaluateKeyRange returns the range of the first primary key column of the table to diff, given by the key bound
+// arguments, or nil if both bounds are NULL
+func (dtf *DiffTableFunction) evaluateKeyRange(ctx *sql.Context) (*dtables.DiffKeyRange, error) {
+	sch := dtf.tableDelta.ToSch
+	if sch == nil {
+		sch = dtf.tableDelta.FromSch
+	}
+	if sch == nil {
+		return nil, nil
+	}
+	if !types.IsFormat_DOLT(dtf.tableDelta.Format()) {
+		return nil, fmt.Errorf("key ranges in %s are not supported for the legacy storage format", dtf.Name())
+	}
+	if schema.IsKeyless(sch) {
+		return nil, fmt.Errorf("key ranges in %s are not supported for keyless tables", dtf.Name())
+	}
+
+	typ := sch.GetPKCols().GetByIndex(0).TypeInfo.ToSqlType()
+	var bounds [2]interface{}
+	for i, expr := range []sql.Expression{dtf.minKeyExpr, dtf.maxKeyExpr} {
+		v, err := expr.Eval(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			continue
+		}
+		if bounds[i], _, err = typ.Convert(v); err != nil {
+			return nil, sql.ErrInvalidArgumentDetails.New(dtf.Name(), expr.String())
+		}
+	}
+
+	if bounds[0] == nil && bounds[1] == nil {
+		return nil, nil
+	}
+	return &dtables.DiffKeyRange{Min: bounds[0], Max: bounds[1]}, nil
+}
+
 // findMatchingDelta returns the best matching table delta for the table name
 // given, taking renames into account
 func findMatchingDelta(deltas []diff.TableDelta, tableName string) diff.TableDelta {
@@ -210,13 +274,13 @@ func loadDetailsForRefs(ctx *sql.Context, fromRef, toRef, dotRef interface{}, db
 	sess := dsess.DSessFromSess(ctx.Session)
 	dbName := db.Name()
 
-	fromRoot, fromCommitTime, fromHashStr, err := sess.ResolveRootForRef(ctx, dbName, fromCommitStr)
+	fromRoot, fromCommitTime, fromHashStr, err := resolveRootForRef(ctx, sess, dbName, fromCommitStr)
 	if err != nil {
 		return nil, nil, err
 	}
 	fromDetails := &refDetails{fromRoot, fromHashStr, fromCommitTime}
 
-	toRoot, toCommitTime, toHashStr, err := sess.ResolveRootForRef(ctx, dbName, toCommitStr)
+	toRoot, toCommitTime, toHashStr, err := resolveRootForRef(ctx, sess, dbName, toCommitStr)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -225,6 +289,27 @@ func loadDetailsForRefs(ctx *sql.Context, fromRef, toRef, dotRef interface{}, db
 	return fromDetails, toDetails, nil
 }
 
+// resolveRootForRef resolves |refStr| to a root value in the database named |dbName|. A ref that doesn't resolve there
+// may name a commit in another database by prefixing it with that database's name, e.g. `otherdb/main`, so that a
+// table can be diffed across two databases or clones. Tables in both roots are compared chunk-wise, so only the parts
+// of the tables that differ are read.
+func resolveRootForRef(ctx *sql.Context, sess *dsess.DoltSession, dbName, refStr string) (*doltdb.RootValue, *types.Timestamp, string, error) {
+	root, commitTime, hashStr, err := sess.ResolveRootForRef(ctx, dbName, refStr)
+	if err == nil {
+		return root, commitTime, hashStr, nil
+	}
+
+	otherDbName, otherRefStr, ok := strings.Cut(refStr, "/")
+	if !ok || len(otherRefStr) == 0 || otherRefStr == doltdb.Working || otherRefStr == doltdb.Staged {
+		return nil, nil, "", err
+	}
+	if _, ok := sess.GetDbData(ctx, otherDbName); !ok {
+		return nil, nil, "", err
+	}
+
+	return sess.ResolveRootForRef(ctx, otherDbName, otherRefStr)
+}
+
 func resolveCommitStrings(ctx *sql.Context, fromRef, toRef, dotRef interface{}, db dsess.SqlDatabase) (string, string, error) {
 	if dotRef != nil {
 		dotStr, err := interfaceToString(dotRef)
@@ -532,6 +617,9 @@ func (dtf *DiffTableFunction) Schema() sql.Schema {
 
 // Resolved implements the sql.Resolvable interface
 func (dtf *DiffTableFunction) Resolved() bool {
+	if dtf.minKeyExpr != nil && !(dtf.minKeyExpr.Resolved() && dtf.maxKeyExpr.Resolved()) {
+		return false
+	}
 	if dtf.dotCommitExpr != nil {
 		return dtf.tableNameExpr.Resolved() && dtf.dotCommitExpr.Resolved()
 	}
@@ -540,15 +628,11 @@ func (dtf *DiffTableFunction) Resolved() bool {
 
 // String implements the Stringer interface
 func (dtf *DiffTableFunction) String() string {
-	if dtf.dotCommitExpr != nil {
-		return fmt.Sprintf("DOLT_DIFF(%s, %s)",
-			dtf.dotCommitExpr.String(),
-			dtf.tableNameExpr.String())
-	}
-	return fmt.Sprintf("DOLT_DIFF(%s, %s, %s)",
-		dtf.fromCommitExpr.String(),
-		dtf.toCommitExpr.String(),
-		dtf.tableNameExpr.String())
+	args := make([]string, 0, 5)
+	for _, expr := range dtf.Expressions() {
+		args = append(args, expr.String())
+	}
+	return fmt.Sprintf("DOLT_DIFF(%s)", strings.Join(args, ", "))
 }
 
 // Name implements the sql.TableFunction interface
@@ -0,0 +1,548 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+var _ sql.TableFunction = (*GrepTableFunction)(nil)
+var _ sql.ExecSourceRel = (*GrepTableFunction)(nil)
+
+// GrepTableFunction searches every version of a table's rows reachable from a revision for values matching a regular
+// expression. Commits that share a table hash with a recently searched version reuse its matches, and a version with
+// the same schema as the version searched before it is searched by diffing the two, so only the rows changed between
+// them are read. Long histories with few changes to the table stay cheap to search.
+type GrepTableFunction struct {
+	ctx *sql.Context
+
+	patternExpr   sql.Expression
+	tableNameExpr sql.Expression
+	revisionExpr  sql.Expression
+	database      sql.Database
+}
+
+var grepTableSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: sqltypes.Text},
+	&sql.Column{Name: "committer", Type: sqltypes.Text},
+	&sql.Column{Name: "email", Type: sqltypes.Text},
+	&sql.Column{Name: "date", Type: sqltypes.Datetime},
+	&sql.Column{Name: "column_name", Type: sqltypes.Text},
+	&sql.Column{Name: "value", Type: sqltypes.LongText},
+}
+
+// NewInstance creates a new instance of TableFunction interface
+func (gtf *GrepTableFunction) NewInstance(ctx *sql.Context, db sql.Database, exprs []sql.Expression) (sql.Node, error) {
+	newInstance := &GrepTableFunction{
+		ctx:      ctx,
+		database: db,
+	}
+
+	node, err := newInstance.WithExpressions(exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// Database implements the sql.Databaser interface
+func (gtf *GrepTableFunction) Database() sql.Database {
+	return gtf.database
+}
+
+// WithDatabase implements the sql.Databaser interface
+func (gtf *GrepTableFunction) WithDatabase(database sql.Database) (sql.Node, error) {
+	ngtf := *gtf
+	ngtf.database = database
+	return &ngtf, nil
+}
+
+// Name implements the sql.TableFunction interface
+func (gtf *GrepTableFunction) Name() string {
+	return "dolt_grep"
+}
+
+// Resolved implements the sql.Resolvable interface
+func (gtf *GrepTableFunction) Resolved() bool {
+	for _, expr := range gtf.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface
+func (gtf *GrepTableFunction) String() string {
+	if gtf.revisionExpr != nil {
+		return fmt.Sprintf("DOLT_GREP(%s, %s, %s)", gtf.patternExpr.String(), gtf.tableNameExpr.String(), gtf.revisionExpr.String())
+	}
+	return fmt.Sprintf("DOLT_GREP(%s, %s)", gtf.patternExpr.String(), gtf.tableNameExpr.String())
+}
+
+// Schema implements the sql.Node interface.
+func (gtf *GrepTableFunction) Schema() sql.Schema {
+	return grepTableSchema
+}
+
+// Children implements the sql.Node interface.
+func (gtf *GrepTableFunction) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (gtf *GrepTableFunction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, fmt.Errorf("unexpected children")
+	}
+	return gtf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (gtf *GrepTableFunction) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	tableName, err := expressionToString(gtf.ctx, gtf.tableNameExpr)
+	if err != nil {
+		return false
+	}
+
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(gtf.database.Name(), tableName, "", sql.PrivilegeType_Select))
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (gtf *GrepTableFunction) Expressions() []sql.Expression {
+	exprs := []sql.Expression{gtf.patternExpr, gtf.tableNameExpr}
+	if gtf.revisionExpr != nil {
+		exprs = append(exprs, gtf.revisionExpr)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (gtf *GrepTableFunction) WithExpressions(expression ...sql.Expression) (sql.Node, error) {
+	if len(expression) < 2 || len(expression) > 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(gtf.Name(), "2 or 3", len(expression))
+	}
+
+	for _, expr := range expression {
+		if !expr.Resolved() {
+			return nil, ErrInvalidNonLiteralArgument.New(gtf.Name(), expr.String())
+		}
+		// prepared statements resolve functions beforehand, so above check fails
+		if _, ok := expr.(sql.FunctionExpression); ok {
+			return nil, ErrInvalidNonLiteralArgument.New(gtf.Name(), expr.String())
+		}
+		if !sqltypes.IsText(expr.Type()) {
+			return nil, sql.ErrInvalidArgumentDetails.New(gtf.Name(), expr.String())
+		}
+	}
+
+	newGtf := *gtf
+	newGtf.patternExpr = expression[0]
+	newGtf.tableNameExpr = expression[1]
+	if len(expression) == 3 {
+		newGtf.revisionExpr = expression[2]
+	}
+
+	return &newGtf, nil
+}
+
+// RowIter implements the sql.Node interface
+func (gtf *GrepTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	patternStr, err := expressionToString(ctx, gtf.patternExpr)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := regexp.Compile(patternStr)
+	if err != nil {
+		return nil, sql.ErrInvalidArgumentDetails.New(gtf.Name(), err.Error())
+	}
+
+	tableName, err := expressionToString(ctx, gtf.tableNameExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	sqledb, ok := gtf.database.(dsess.SqlDatabase)
+	if !ok {
+		return nil, fmt.Errorf("unexpected database type: %T", gtf.database)
+	}
+
+	sess := dsess.DSessFromSess(ctx.Session)
+	var commit *doltdb.Commit
+	if gtf.revisionExpr != nil {
+		revision, err := expressionToString(ctx, gtf.revisionExpr)
+		if err != nil {
+			return nil, err
+		}
+		cs, err := doltdb.NewCommitSpec(revision)
+		if err != nil {
+			return nil, err
+		}
+		headRef, err := sess.CWBHeadRef(ctx, sqledb.RevisionQualifiedName())
+		if err != nil && err != doltdb.ErrOperationNotSupportedInDetachedHead {
+			return nil, err
+		}
+		commit, err = sqledb.DbData().Ddb.Resolve(ctx, cs, headRef)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		commit, err = sess.GetHeadCommit(ctx, sqledb.RevisionQualifiedName())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tableName, ok, err = root.ResolveTableName(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	h, err := commit.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	child, err := commitwalk.GetTopologicalOrderIterator(ctx, sqledb.DbData().Ddb, []hash.Hash{h}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := lru.New[hash.Hash, []grepMatch](grepMatchesCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grepTableFunctionRowIter{
+		child:     child,
+		db:        sqledb,
+		tableName: tableName,
+		pattern:   pattern,
+		matches:   matches,
+	}, nil
+}
+
+//------------------------------------
+// grepTableFunctionRowIter
+//------------------------------------
+
+var _ sql.RowIter = (*grepTableFunctionRowIter)(nil)
+
+// grepMatchesCacheSize is the number of versions of the table whose matches a dolt_grep query keeps
+const grepMatchesCacheSize = 256
+
+type grepMatch struct {
+	column string
+	value  string
+}
+
+// grepVersion is the version of the table last searched, which the next version is diffed against
+type grepVersion struct {
+	schHash hash.Hash
+	sch     schema.Schema
+	rows    prolly.Map
+	// counts are the number of rows each matching value is in
+	counts map[grepMatch]int
+}
+
+// grepTableFunctionRowIter walks the commit history, returning a row for each distinct matching value of each column
+// in the version of the table at each commit.
+type grepTableFunctionRowIter struct {
+	child     doltdb.CommitItr
+	db        dsess.SqlDatabase
+	tableName string
+	pattern   *regexp.Regexp
+
+	// matches caches the matches found in recently searched versions of the table, keyed by table hash
+	matches *lru.Cache[hash.Hash, []grepMatch]
+	last    *grepVersion
+	pending []sql.Row
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+func (itr *grepTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for len(itr.pending) == 0 {
+		h, cm, err := itr.child.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		matches, err := itr.matchesAtCommit(ctx, cm)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range matches {
+			itr.pending = append(itr.pending, sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), m.column, m.value))
+		}
+	}
+
+	row := itr.pending[0]
+	itr.pending = itr.pending[1:]
+	return row, nil
+}
+
+// matchesAtCommit returns the matches in the version of the table at the commit given, searching the table only if
+// this version of it hasn't been searched recently
+func (itr *grepTableFunctionRowIter) matchesAtCommit(ctx *sql.Context, cm *doltdb.Commit) ([]grepMatch, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tblHash, ok, err := root.GetTableHash(ctx, itr.tableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if matches, ok := itr.matches.Get(tblHash); ok {
+		return matches, nil
+	}
+
+	tbl, ok, err := root.GetTable(ctx, itr.tableName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schHash, err := tbl.GetSchemaHash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var version *grepVersion
+	// keyless tables store duplicate rows as a count, so they're always scanned
+	if types.IsFormat_DOLT(tbl.Format()) && !schema.IsKeyless(sch) {
+		idx, err := tbl.GetRowData(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rows := durable.ProllyMapFromIndex(idx)
+		if itr.last != nil && itr.last.schHash == schHash {
+			version = itr.last
+			if err = itr.diffRows(ctx, version, rows, tbl.NodeStore()); err != nil {
+				return nil, err
+			}
+		} else {
+			version = &grepVersion{schHash: schHash, sch: sch, rows: rows}
+			if version.counts, err = itr.scanTable(ctx, root, tbl, sch); err != nil {
+				return nil, err
+			}
+		}
+		itr.last = version
+	} else {
+		counts, err := itr.scanTable(ctx, root, tbl, sch)
+		if err != nil {
+			return nil, err
+		}
+		version = &grepVersion{sch: sch, counts: counts}
+	}
+
+	matches := sortedGrepMatches(version.sch, version.counts)
+	itr.matches.Add(tblHash, matches)
+	return matches, nil
+}
+
+// diffRows updates |version| to the version of the table with the rows given, which has the same schema, counting
+// the matching values of only the rows that changed between them
+func (itr *grepTableFunctionRowIter) diffRows(ctx *sql.Context, version *grepVersion, rows prolly.Map, ns tree.NodeStore) error {
+	conv, err := dtables.NewProllyRowConverter(version.sch, version.sch, nil, ns)
+	if err != nil {
+		return err
+	}
+	cols := version.sch.GetAllCols().GetColumnNames()
+	r := make(sql.Row, len(cols))
+	count := func(key, value val.Tuple, delta int) error {
+		if err := conv.PutConverted(ctx, key, value, r); err != nil {
+			return err
+		}
+		return itr.matchRow(r, cols, func(m grepMatch) {
+			version.counts[m] += delta
+			if version.counts[m] == 0 {
+				delete(version.counts, m)
+			}
+		})
+	}
+
+	err = prolly.DiffMaps(ctx, version.rows, rows, func(ctx context.Context, diff tree.Diff) error {
+		if diff.Type != tree.AddedDiff {
+			if err := count(val.Tuple(diff.Key), val.Tuple(diff.From), -1); err != nil {
+				return err
+			}
+		}
+		if diff.Type != tree.RemovedDiff {
+			if err := count(val.Tuple(diff.Key), val.Tuple(diff.To), 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && err != io.EOF {
+		return err
+	}
+	version.rows = rows
+	return nil
+}
+
+// scanTable returns the number of rows of the table given that each matching value is in
+func (itr *grepTableFunctionRowIter) scanTable(ctx *sql.Context, root *doltdb.RootValue, tbl *doltdb.Table, sch schema.Schema) (map[grepMatch]int, error) {
+	dt, err := NewDoltTable(itr.tableName, sch, tbl, itr.db, editor.Options{})
+	if err != nil {
+		return nil, err
+	}
+	dt, err = dt.LockedToRoot(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]string, len(dt.Schema()))
+	for i, col := range dt.Schema() {
+		cols[i] = col.Name
+	}
+	counts := make(map[grepMatch]int)
+
+	partitions, err := dt.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer partitions.Close(ctx)
+
+	for {
+		p, err := partitions.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		err = itr.scanPartition(ctx, dt, p, cols, counts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
+// scanPartition counts the rows of the partition given that each matching value is in
+func (itr *grepTableFunctionRowIter) scanPartition(ctx *sql.Context, dt *DoltTable, p sql.Partition, cols []string, counts map[grepMatch]int) error {
+	rows, err := dt.PartitionRows(ctx, p)
+	if err != nil {
+		return err
+	}
+	defer rows.Close(ctx)
+
+	for {
+		r, err := rows.Next(ctx)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		err = itr.matchRow(r, cols, func(m grepMatch) {
+			counts[m]++
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// matchRow calls |cb| with each value of the row given that matches the pattern. |cols| are the names of the columns
+// of the row.
+func (itr *grepTableFunctionRowIter) matchRow(r sql.Row, cols []string, cb func(m grepMatch)) error {
+	for i, v := range r {
+		if v == nil {
+			continue
+		}
+
+		str, _, err := sqltypes.LongText.Convert(v)
+		if err != nil {
+			return err
+		}
+
+		s, ok := str.(string)
+		if !ok || !itr.pattern.MatchString(s) {
+			continue
+		}
+		cb(grepMatch{column: cols[i], value: s})
+	}
+	return nil
+}
+
+// sortedGrepMatches returns the values in |counts|, ordered by column and then by value
+func sortedGrepMatches(sch schema.Schema, counts map[grepMatch]int) []grepMatch {
+	colIdx := make(map[string]int)
+	for i, col := range sch.GetAllCols().GetColumns() {
+		colIdx[col.Name] = i
+	}
+
+	matches := make([]grepMatch, 0, len(counts))
+	for m := range counts {
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		ci, cj := colIdx[matches[i].column], colIdx[matches[j].column]
+		if ci != cj {
+			return ci < cj
+		}
+		return matches[i].value < matches[j].value
+	})
+	return matches
+}
+
+func (itr *grepTableFunctionRowIter) Close(_ *sql.Context) error {
+	return nil
+}
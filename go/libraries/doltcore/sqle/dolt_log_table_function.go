@@ -16,7 +16,10 @@ package sqle
 
 import (
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -27,6 +30,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
@@ -43,6 +47,14 @@ type LogTableFunction struct {
 	minParents  int
 	showParents bool
 	decoration  string
+	tableName   string
+
+	// author, since and until filter the commits during the commit walk, and limit and skip page through them
+	author *regexp.Regexp
+	since  time.Time
+	until  time.Time
+	limit  int
+	skip   int
 
 	database sql.Database
 }
@@ -53,6 +65,8 @@ var logTableSchema = sql.Schema{
 	&sql.Column{Name: "email", Type: types.Text},
 	&sql.Column{Name: "date", Type: types.Datetime},
 	&sql.Column{Name: "message", Type: types.Text},
+	&sql.Column{Name: "signed", Type: types.Boolean},
+	&sql.Column{Name: "metadata", Type: types.JSON, Nullable: true},
 }
 
 // NewInstance creates a new instance of TableFunction interface
@@ -130,6 +144,30 @@ func (ltf *LogTableFunction) getOptionsString() string {
 		options = append(options, fmt.Sprintf("--%s %s", cli.DecorateFlag, ltf.decoration))
 	}
 
+	if ltf.author != nil {
+		options = append(options, fmt.Sprintf("--%s %s", cli.AuthorParam, ltf.author.String()))
+	}
+
+	if !ltf.since.IsZero() {
+		options = append(options, fmt.Sprintf("--%s %s", cli.SinceParam, ltf.since.Format(time.RFC3339)))
+	}
+
+	if !ltf.until.IsZero() {
+		options = append(options, fmt.Sprintf("--%s %s", cli.UntilParam, ltf.until.Format(time.RFC3339)))
+	}
+
+	if ltf.limit >= 0 {
+		options = append(options, fmt.Sprintf("--%s %d", cli.NumberFlag, ltf.limit))
+	}
+
+	if ltf.skip > 0 {
+		options = append(options, fmt.Sprintf("--%s %d", cli.SkipParam, ltf.skip))
+	}
+
+	if len(ltf.tableName) > 0 {
+		options = append(options, "--", ltf.tableName)
+	}
+
 	return strings.Join(options, ", ")
 }
 
@@ -221,7 +259,7 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 		return err
 	}
 
-	apr, err := cli.CreateLogArgParser().Parse(args)
+	apr, err := cli.CreateLogTableFunctionArgParser().Parse(args)
 	if err != nil {
 		return sql.ErrInvalidArgumentDetails.New(ltf.Name(), err.Error())
 	}
@@ -246,6 +284,48 @@ func (ltf *LogTableFunction) addOptions(expression []sql.Expression) error {
 	}
 	ltf.decoration = decorateOption
 
+	ltf.author = nil
+	if pattern, ok := apr.GetValue(cli.AuthorParam); ok {
+		ltf.author, err = regexp.Compile(pattern)
+		if err != nil {
+			return sql.ErrInvalidArgumentDetails.New(ltf.Name(), fmt.Sprintf("invalid --%s pattern: %s", cli.AuthorParam, err.Error()))
+		}
+	}
+
+	ltf.since, ltf.until = time.Time{}, time.Time{}
+	if since, ok := apr.GetValue(cli.SinceParam); ok {
+		ltf.since, err = cli.ParseDate(since)
+		if err != nil {
+			return sql.ErrInvalidArgumentDetails.New(ltf.Name(), err.Error())
+		}
+	}
+	if until, ok := apr.GetValue(cli.UntilParam); ok {
+		ltf.until, err = cli.ParseDate(until)
+		if err != nil {
+			return sql.ErrInvalidArgumentDetails.New(ltf.Name(), err.Error())
+		}
+	}
+
+	ltf.limit = apr.GetIntOrDefault(cli.NumberFlag, -1)
+	if apr.Contains(cli.NumberFlag) && ltf.limit < 0 {
+		return sql.ErrInvalidArgumentDetails.New(ltf.Name(), fmt.Sprintf("--%s must not be negative", cli.NumberFlag))
+	}
+	ltf.skip = apr.GetIntOrDefault(cli.SkipParam, 0)
+	if ltf.skip < 0 {
+		return sql.ErrInvalidArgumentDetails.New(ltf.Name(), fmt.Sprintf("--%s must not be negative", cli.SkipParam))
+	}
+
+	// `dolt_log([<revisions>...], '--', <table>)`
+	for i, arg := range apr.Args {
+		if arg == "--" {
+			if len(apr.Args[i+1:]) != 1 {
+				return sql.ErrInvalidArgumentDetails.New(ltf.Name(), "exactly one table must be provided after --")
+			}
+			ltf.tableName = apr.Args[i+1]
+			break
+		}
+	}
+
 	return nil
 }
 
@@ -269,6 +349,10 @@ func (ltf *LogTableFunction) WithExpressions(expression ...sql.Expression) (sql.
 	// Gets revisions, excluding any flag-related expression
 	var filteredExpressions []sql.Expression
 	for i, ex := range expression {
+		// Everything after -- is a table name
+		if mustExpressionToString(ltf.ctx, ex) == "--" {
+			break
+		}
 		if !strings.Contains(ex.String(), "--") && !(i > 0 && strings.Contains(expression[i-1].String(), "--")) {
 			filteredExpressions = append(filteredExpressions, ex)
 		}
@@ -392,8 +476,21 @@ func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter
 		}
 	}
 
+	if err = ltf.checkTableExists(ctx, commit); err != nil {
+		return nil, err
+	}
+
 	matchFunc := func(commit *doltdb.Commit) (bool, error) {
-		return commit.NumParents() >= ltf.minParents, nil
+		if commit.NumParents() < ltf.minParents {
+			return false, nil
+		}
+		if ok, err := ltf.matchesMeta(ctx, commit); err != nil || !ok {
+			return false, err
+		}
+		if len(ltf.tableName) > 0 {
+			return commit.TableChanged(ctx, ltf.tableName)
+		}
+		return true, nil
 	}
 
 	cHashToRefs, err := getCommitHashToRefs(ctx, sqledb.DbData().Ddb, ltf.decoration)
@@ -414,6 +511,10 @@ func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter
 		}
 
 		if threeDot {
+			if err = ltf.checkTableExists(ctx, secondCommit); err != nil {
+				return nil, err
+			}
+
 			mergeBase, err := merge.MergeBase(ctx, commit, secondCommit)
 			if err != nil {
 				return nil, err
@@ -440,6 +541,48 @@ func (ltf *LogTableFunction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter
 	return ltf.NewLogTableFunctionRowIter(ctx, sqledb.DbData().Ddb, commit, matchFunc, cHashToRefs)
 }
 
+// checkTableExists returns an error if a table to filter by was given and it doesn't exist in |commit|, one of the
+// commits the log starts from. This matches dolt log.
+func (ltf *LogTableFunction) checkTableExists(ctx *sql.Context, commit *doltdb.Commit) error {
+	if len(ltf.tableName) == 0 {
+		return nil
+	}
+
+	root, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, ok, err := root.GetTableHash(ctx, ltf.tableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(ltf.tableName)
+	}
+	return nil
+}
+
+// matchesMeta returns whether the author and date of |commit| match the --author, --since and --until arguments.
+func (ltf *LogTableFunction) matchesMeta(ctx *sql.Context, commit *doltdb.Commit) (bool, error) {
+	if ltf.author == nil && ltf.since.IsZero() && ltf.until.IsZero() {
+		return true, nil
+	}
+	meta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return false, err
+	}
+	if ltf.author != nil && !ltf.author.MatchString(fmt.Sprintf("%s <%s>", meta.Name, meta.Email)) {
+		return false, nil
+	}
+	if !ltf.since.IsZero() && meta.Time().Before(ltf.since) {
+		return false, nil
+	}
+	if !ltf.until.IsZero() && meta.Time().After(ltf.until) {
+		return false, nil
+	}
+	return true, nil
+}
+
 func getCommitHashToRefs(ctx *sql.Context, ddb *doltdb.DoltDB, decoration string) (map[hash.Hash][]string, error) {
 	cHashToRefs := map[hash.Hash][]string{}
 
@@ -581,6 +724,11 @@ type logTableFunctionRowIter struct {
 	decoration  string
 	cHashToRefs map[hash.Hash][]string
 	headHash    hash.Hash
+
+	// remaining is the number of commits left to return, or -1 for every commit, and skip is the number of commits
+	// left to skip before returning any
+	remaining int
+	skip      int
 }
 
 func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit, matchFn func(*doltdb.Commit) (bool, error), cHashToRefs map[hash.Hash][]string) (*logTableFunctionRowIter, error) {
@@ -600,6 +748,8 @@ func (ltf *LogTableFunction) NewLogTableFunctionRowIter(ctx *sql.Context, ddb *d
 		decoration:  ltf.decoration,
 		cHashToRefs: cHashToRefs,
 		headHash:    h,
+		remaining:   ltf.limit,
+		skip:        ltf.skip,
 	}, nil
 }
 
@@ -636,23 +786,42 @@ func (ltf *LogTableFunction) NewDotDotLogTableFunctionRowIter(ctx *sql.Context,
 		decoration:  ltf.decoration,
 		cHashToRefs: cHashToRefs,
 		headHash:    headHash,
+		remaining:   ltf.limit,
+		skip:        ltf.skip,
 	}, nil
 }
 
 // Next retrieves the next row. It will return io.EOF if it's the last row.
 // After retrieving the last row, Close will be automatically closed.
 func (itr *logTableFunctionRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if itr.remaining == 0 {
+		return nil, io.EOF
+	}
+
 	h, cm, err := itr.child.Next(ctx)
 	if err != nil {
 		return nil, err
 	}
+	for ; itr.skip > 0; itr.skip-- {
+		if h, cm, err = itr.child.Next(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if itr.remaining > 0 {
+		itr.remaining--
+	}
 
 	meta, err := cm.GetCommitMeta(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	row := sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description)
+	md, err := dtables.CommitMetadataValue(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	row := sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, meta.Signature != "", md)
 
 	if itr.showParents {
 		prStr, err := getParentsString(ctx, cm)
@@ -16,6 +16,7 @@ package dprocedures
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -101,6 +102,11 @@ func doDoltBackup(ctx *sql.Context, args []string) (int, error) {
 		if err != nil {
 			return statusErr, fmt.Errorf("error syncing backup: %w", err)
 		}
+	case cli.VerifyBackupId:
+		err = verifyBackup(ctx, dbData, sess, apr)
+		if err != nil {
+			return statusErr, fmt.Errorf("error verifying backup: %w", err)
+		}
 	default:
 		return statusErr, fmt.Errorf("unrecognized dolt_backup parameter: %s", apr.Arg(0))
 	}
@@ -156,6 +162,9 @@ func removeBackup(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 	err := dbData.Rsw.RemoveBackup(ctx, backupName)
 	switch err {
 	case nil:
+		if tmpDir, err := dbData.Rsw.TempTableFilesDir(); err == nil && tmpDir != "" {
+			return actions.RemoveBackupGenerations(filesys.LocalFS, filepath.Dir(tmpDir), backupName)
+		}
 		return nil
 	case env.ErrFailedToWriteRepoState:
 		return fmt.Errorf("error: failed to save change to repo state, cause: %s", err.Error())
@@ -232,6 +241,36 @@ func syncBackupViaName(ctx *sql.Context, dbData env.DbData, sess *dsess.DoltSess
 	return syncRoots(ctx, dbData, sess, b)
 }
 
+func verifyBackup(ctx *sql.Context, dbData env.DbData, sess *dsess.DoltSession, apr *argparser.ArgParseResults) error {
+	if apr.NArg() != 2 {
+		return fmt.Errorf("usage: dolt_backup('verify', BACKUP_NAME)")
+	}
+
+	backupName := strings.TrimSpace(apr.Arg(1))
+	backups, err := dbData.Rsr.GetBackups()
+	if err != nil {
+		return err
+	}
+
+	b, ok := backups[backupName]
+	if !ok {
+		return fmt.Errorf("error: unknown backup: '%s'", backupName)
+	}
+
+	backupDb, err := sess.Provider().GetRemoteDB(ctx, dbData.Ddb.ValueReadWriter().Format(), b, true)
+	if err != nil {
+		return fmt.Errorf("error loading backup: %w", err)
+	}
+	report, err := backupDb.Verify(ctx)
+	if err != nil {
+		return err
+	}
+	if len(report.Problems) > 0 {
+		return fmt.Errorf("backup '%s' failed verification with %d problems: %s", backupName, len(report.Problems), strings.Join(report.Problems, "; "))
+	}
+	return nil
+}
+
 func syncRoots(ctx *sql.Context, dbData env.DbData, sess *dsess.DoltSession, backup env.Remote) error {
 	destDb, err := sess.Provider().GetRemoteDB(ctx, dbData.Ddb.ValueReadWriter().Format(), backup, true)
 	if err != nil {
@@ -243,10 +282,22 @@ func syncRoots(ctx *sql.Context, dbData env.DbData, sess *dsess.DoltSession, bac
 		return err
 	}
 
-	err = actions.SyncRoots(ctx, dbData.Ddb, destDb, tmpDir, runProgFuncs, stopProgFuncs)
+	op := dsess.GlobalOperations.Start(ctx, ctx.GetCurrentDatabase(), "backup")
+	defer op.Finish()
+
+	gen, err := actions.SyncRootsWithGeneration(ctx, dbData.Ddb, destDb, tmpDir, progStarter(op, "syncing"), stopProgFuncs)
 	if err != nil && err != pull.ErrDBUpToDate {
 		return fmt.Errorf("error syncing backup: %w", err)
 	}
 
+	// backups synced by url don't have a name to record their generations under. The temp table files dir is in the
+	// dolt dir of the database, next to the generations of its backups.
+	if backup.Name != "__temp__" && tmpDir != "" {
+		_, err = actions.RecordBackupGeneration(filesys.LocalFS, filepath.Dir(tmpDir), backup.Name, gen)
+		if err != nil {
+			return fmt.Errorf("error recording backup generation: %w", err)
+		}
+	}
+
 	return nil
 }
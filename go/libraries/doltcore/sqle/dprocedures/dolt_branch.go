@@ -117,6 +117,9 @@ func renameBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseRe
 	if err := branch_control.CanCreateBranch(ctx, newBranchName); err != nil {
 		return err
 	}
+	if err := checkBranchName(ctx, newBranchName); err != nil {
+		return err
+	}
 	force := apr.Contains(cli.ForceFlag)
 
 	if !force {
@@ -295,6 +298,11 @@ func loadConfig(ctx *sql.Context) *env.DoltCliConfig {
 	return dEnv.Config
 }
 
+// checkBranchName returns an error if the branch name policy in the config doesn't allow |branchName|.
+func checkBranchName(ctx *sql.Context, branchName string) error {
+	return env.CheckBranchName(loadConfig(ctx), branchName)
+}
+
 func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgParseResults, rsc *doltdb.ReplicationStatusController) error {
 	if apr.NArg() == 0 || apr.NArg() > 2 {
 		return InvalidArgErr
@@ -352,6 +360,10 @@ func createNewBranch(ctx *sql.Context, dbData env.DbData, apr *argparser.ArgPars
 	if err != nil {
 		return err
 	}
+	err = checkBranchName(ctx, branchName)
+	if err != nil {
+		return err
+	}
 
 	err = actions.CreateBranchWithStartPt(ctx, dbData, branchName, startPt, apr.Contains(cli.ForceFlag), rsc)
 	if err != nil {
@@ -392,6 +404,9 @@ func copyABranch(ctx *sql.Context, dbData env.DbData, srcBr string, destBr strin
 	if err := branch_control.CanCreateBranch(ctx, destBr); err != nil {
 		return err
 	}
+	if err := checkBranchName(ctx, destBr); err != nil {
+		return err
+	}
 	// If force is enabled, we can overwrite the destination branch, so we require a permission check here, even if the
 	// destination branch doesn't exist. An unauthorized user could simply rerun the command without the force flag.
 	if force {
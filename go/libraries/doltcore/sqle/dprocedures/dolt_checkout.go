@@ -256,6 +256,11 @@ func checkoutNewBranch(ctx *sql.Context, dbName string, dbData env.DbData, apr *
 		newBranchName = newBranch
 	}
 
+	err = checkBranchName(ctx, newBranchName)
+	if err != nil {
+		return err
+	}
+
 	err = actions.CreateBranchWithStartPt(ctx, dbData, newBranchName, startPt, false, rsc)
 	if err != nil {
 		return err
@@ -21,6 +21,7 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -43,6 +44,9 @@ func doltClone(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 		return nil, err
 	}
 
+	op := dsess.GlobalOperations.Start(ctx, dir, "clone")
+	defer op.Finish()
+
 	sess := dsess.DSessFromSess(ctx.Session)
 	scheme, remoteUrl, err := env.GetAbsRemoteUrl(sess.Provider().FileSystem(), emptyConfig(), urlStr)
 	if err != nil {
@@ -72,7 +76,11 @@ func getDirectoryAndUrlString(apr *argparser.ArgParseResults) (string, string, e
 	}
 
 	urlStr := apr.Arg(0)
-	_, err := earl.Parse(urlStr)
+	parsedUrlStr := urlStr
+	if sshUrl, ok := dbfactory.SSHURLFromSCPLike(urlStr); ok {
+		parsedUrlStr = sshUrl
+	}
+	_, err := earl.Parse(parsedUrlStr)
 	if err != nil {
 		return "", "", errhand.BuildDError("error: invalid remote url: " + urlStr).Build()
 	}
@@ -81,9 +89,9 @@ func getDirectoryAndUrlString(apr *argparser.ArgParseResults) (string, string, e
 	if apr.NArg() == 2 {
 		dir = apr.Arg(1)
 	} else {
-		dir = path.Base(urlStr)
+		dir = path.Base(parsedUrlStr)
 		if dir == "." {
-			dir = path.Dir(urlStr)
+			dir = path.Dir(parsedUrlStr)
 		} else if dir == "/" {
 			return "", "", errhand.BuildDError("Could not infer repo name. Please explicitly define a directory for this url").Build()
 		}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
@@ -110,20 +111,24 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 	amend := apr.Contains(cli.AmendFlag)
 
 	msg, msgOk := apr.GetValue(cli.MessageArg)
-	if !msgOk {
-		if amend {
-			commit, err := dSess.GetHeadCommit(ctx, dbName)
-			if err != nil {
-				return "", false, err
-			}
-			commitMeta, err := commit.GetCommitMeta(ctx)
-			if err != nil {
-				return "", false, err
-			}
+	metadata, metadataOk := apr.GetValue(cli.MetadataParam)
+	if amend && (!msgOk || !metadataOk) {
+		commit, err := dSess.GetHeadCommit(ctx, dbName)
+		if err != nil {
+			return "", false, err
+		}
+		commitMeta, err := commit.GetCommitMeta(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		if !msgOk {
 			msg = commitMeta.Description
-		} else {
-			return "", false, fmt.Errorf("Must provide commit message.")
 		}
+		if !metadataOk {
+			metadata = commitMeta.Metadata
+		}
+	} else if !msgOk {
+		return "", false, fmt.Errorf("Must provide commit message.")
 	}
 
 	t := ctx.QueryTime()
@@ -143,6 +148,11 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		}
 	}
 
+	sign, err := env.GetSignFunc(ctx, loadConfig(ctx), env.CommitGpgSign, apr.Contains(cli.SignFlag), apr.Contains(cli.NoSignFlag))
+	if err != nil {
+		return "", false, err
+	}
+
 	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
 		Message:    msg,
 		Date:       t,
@@ -152,6 +162,8 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 		Force:      apr.Contains(cli.ForceFlag),
 		Name:       name,
 		Email:      email,
+		Sign:       sign,
+		Metadata:   metadata,
 	})
 	if err != nil {
 		return "", false, err
@@ -125,6 +125,10 @@ func countCommits(ctx *sql.Context, args ...string) (ahead uint64, behind uint64
 // countCommitsInRange returns the number of commits between the given starting point to trace back to the given target point.
 // The starting commit must be a descendant of the target commit. Target commit must be a common ancestor commit.
 func countCommitsInRange(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, targetCommitHash hash.Hash) (uint64, error) {
+	if g := ddb.CommitGraph(); g != nil {
+		return g.CountCommitsNotReachable(ctx, ddb.ValueReadWriter(), startCommitHash, targetCommitHash)
+	}
+
 	itr, iErr := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{startCommitHash}, nil)
 	if iErr != nil {
 		return 0, iErr
@@ -46,6 +46,9 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, err
 	}
 
+	op := dsess.GlobalOperations.Start(ctx, dbName, "fetch")
+	defer op.Finish()
+
 	sess := dsess.DSessFromSess(ctx.Session)
 	dbData, ok := sess.GetDbData(ctx, dbName)
 	if !ok {
@@ -67,9 +70,33 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 		return 1, err
 	}
 
-	err = actions.FetchRefSpecs(ctx, dbData, srcDB, refSpecs, remote, ref.UpdateMode{Force: true}, runProgFuncs, stopProgFuncs)
+	verify, err := env.VerifyFetches(loadConfig(ctx), apr.Contains(cli.VerifyFlag))
+	if err != nil {
+		return cmdFailure, err
+	}
+	if verify {
+		srcDB = srcDB.WithVerifiedPulls()
+	}
+
+	cacheDir, err := env.SharedCacheDir(loadConfig(ctx))
+	if err != nil {
+		return cmdFailure, err
+	}
+	if cacheDir != "" {
+		srcDB = srcDB.WithSharedCache(cacheDir)
+	}
+
+	err = actions.FetchRefSpecs(ctx, dbData, srcDB, refSpecs, remote, ref.UpdateMode{Force: true}, progStarter(op, "fetching"), stopProgFuncs)
 	if err != nil {
 		return cmdFailure, fmt.Errorf("fetch failed: %w", err)
 	}
+
+	if apr.Contains(cli.PruneFlag) {
+		_, err = actions.PruneBranches(ctx, dbData, srcDB, refSpecs, remote, false)
+		if err != nil {
+			return cmdFailure, fmt.Errorf("prune failed: %w", err)
+		}
+	}
+
 	return cmdSuccess, nil
 }
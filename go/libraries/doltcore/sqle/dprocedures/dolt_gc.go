@@ -80,6 +80,10 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, fmt.Errorf("Could not load database %s", dbName)
 	}
 
+	op := dsess.GlobalOperations.Start(ctx, dbName, "gc")
+	defer op.Finish()
+	op.SetPhase("collecting", "", 0)
+
 	if apr.Contains(cli.ShallowFlag) {
 		err = ddb.ShallowGC(ctx)
 		if err != nil {
@@ -109,6 +113,7 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 		// (allowed-to-block) callback at the end, we could more
 		// gracefully tear things down.
 		err = ddb.GC(ctx, func() error {
+			op.SetPhase("establishing safepoint", "", 0)
 			if origepoch != -1 {
 				// Here we need to sanity check role and epoch.
 				if _, role, ok := sql.SystemVariables.GetGlobal(dsess.DoltClusterRoleVariable); ok {
@@ -158,6 +163,7 @@ func doDoltGC(ctx *sql.Context, args []string) (int, error) {
 			}
 			ctx.Session.SetTransaction(nil)
 			dsess.DSessFromSess(ctx.Session).SetValidateErr(ErrServerPerformedGC)
+			op.SetPhase("finalizing", "", 0)
 			return nil
 		})
 		if err != nil {
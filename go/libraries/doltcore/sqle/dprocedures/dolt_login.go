@@ -0,0 +1,204 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/grpcendpoint"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const (
+	loginStatusPending    = "pending"
+	loginStatusAssociated = "associated"
+)
+
+var doltLoginSchema = stringSchema("status", "key_id", "public_key", "login_url", "username", "email")
+
+// doltLogin is the stored procedure version for the CLI command `dolt login`. With no arguments, it creates new
+// credentials and returns the url where they can be associated with an account on the remote host. Given the key id
+// or public key of existing credentials, it checks whether they're associated with an account and, if they are,
+// registers them as the credentials sql-server uses for remote calls made by the current SQL user, or by every SQL
+// user with --server. Registering credentials replaces those previously registered for the same user or the server,
+// and takes effect without restarting the server. Registering credentials with --server, registering credentials
+// that another user created, or checking them against an auth endpoint other than the configured one, requires the
+// SUPER privilege.
+func doltLogin(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltLogin(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(res...), nil
+}
+
+func doDoltLogin(ctx *sql.Context, args []string) ([]interface{}, error) {
+	ap := cli.CreateLoginArgParser()
+	ap.SupportsFlag(cli.ServerFlag, "", "Register the credentials for every SQL user instead of the current one.")
+	apr, err := ap.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return nil, err
+	}
+	superErr := checkSuperPrivilege(ctx)
+	if superErr != nil && (apr.Contains(cli.ServerFlag) || apr.Contains(cli.AuthEndpointParam) || apr.Contains(cli.InsecureFlag)) {
+		return nil, superErr
+	}
+
+	// Like loadConfig, load the environment of the user running the server, which holds its credentials and the
+	// global config the credentials are registered in.
+	dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, filesys.LocalFS, doltdb.LocalDirDoltDB, "")
+
+	// use config values over defaults, flag values over config values
+	loginUrl := dEnv.Config.GetStringOrDefault(env.AddCredsUrlKey, env.DefaultLoginUrl)
+	loginUrl = apr.GetValueOrDefault(cli.LoginURLParam, loginUrl)
+	if loginUrl == "" {
+		loginUrl = env.DefaultLoginUrl
+	}
+
+	var authHost, authPort string
+	if authEndpoint, ok := apr.GetValue(cli.AuthEndpointParam); ok {
+		authHost, authPort, err = net.SplitHostPort(authEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse auth-endpoint: '%s': %w", authEndpoint, err)
+		}
+	} else {
+		authHost = dEnv.Config.GetStringOrDefault(env.RemotesApiHostKey, env.DefaultRemotesApiHost)
+		authPort = dEnv.Config.GetStringOrDefault(env.RemotesApiHostPortKey, env.DefaultRemotesApiPort)
+	}
+
+	insecure := apr.Contains(cli.InsecureFlag)
+	if !insecure {
+		insecureStr := dEnv.Config.GetStringOrDefault(env.DoltLabInsecureKey, "false")
+		insecure, err = strconv.ParseBool(insecureStr)
+		if err != nil {
+			return nil, fmt.Errorf("the config value of '%s' is '%s' which is not a valid true/false value", env.DoltLabInsecureKey, insecureStr)
+		}
+	}
+
+	gcfg, ok := dEnv.Config.GetConfig(env.GlobalConfig)
+	if !ok {
+		return nil, fmt.Errorf("global config not found")
+	}
+
+	if apr.NArg() == 0 {
+		_, newCreds, verr := actions.NewCredsFile(dEnv)
+		if verr != nil {
+			return nil, fmt.Errorf("unable to create credentials: %w", verr)
+		}
+		// remember who created the credentials, so that other users can't register them as their own
+		if err = gcfg.SetStrings(map[string]string{env.SqlServerCredsOwnerKey(newCreds.KeyIDBase32Str()): ctx.Client().User}); err != nil {
+			return nil, err
+		}
+		// new credentials can't be associated with an account yet
+		return loginRow(loginStatusPending, newCreds, loginUrl, nil), nil
+	}
+
+	dc, err := dEnv.DoltCredsForKeyID(apr.Arg(0))
+	if err != nil {
+		return nil, err
+	}
+	// Credentials that weren't created by the current user with DOLT_LOGIN, such as those created with dolt creds new
+	// by whoever runs the server, may only be used by a super user.
+	if superErr != nil {
+		owner, err := gcfg.GetString(env.SqlServerCredsOwnerKey(dc.KeyIDBase32Str()))
+		if err != nil || owner != ctx.Client().User {
+			return nil, superErr
+		}
+	}
+
+	whoAmI, err := credsWhoAmI(ctx, dEnv, dc, authHost, net.JoinHostPort(authHost, authPort), insecure)
+	if err != nil {
+		return nil, err
+	} else if whoAmI == nil {
+		return loginRow(loginStatusPending, dc, loginUrl, nil), nil
+	}
+
+	key := env.SqlServerCreds
+	if !apr.Contains(cli.ServerFlag) {
+		key = env.SqlServerUserCredsKey(ctx.Client().User)
+	}
+	if err = gcfg.SetStrings(map[string]string{key: dc.KeyIDBase32Str()}); err != nil {
+		return nil, err
+	}
+
+	return loginRow(loginStatusAssociated, dc, loginUrl, whoAmI), nil
+}
+
+// checkSuperPrivilege returns an error unless the current user has the SUPER privilege.
+func checkSuperPrivilege(ctx *sql.Context) error {
+	branchAwareSession := branch_control.GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow all operations
+	if branchAwareSession == nil {
+		return nil
+	}
+	// the privilege set is cached in the session when the privileges of the CALL are checked
+	privSet, counter := branchAwareSession.GetPrivilegeSet()
+	if counter == 0 || !privSet.Has(sql.PrivilegeType_Super) {
+		return sql.ErrPrivilegeCheckFailed.New(fmt.Sprintf("'%s'@'%s'", branchAwareSession.GetUser(), branchAwareSession.GetHost()))
+	}
+	return nil
+}
+
+// credsWhoAmI returns the account |dc| is associated with on the remote host at |authEndpoint|, or nil if it isn't
+// associated with one.
+func credsWhoAmI(ctx *sql.Context, dEnv *env.DoltEnv, dc creds.DoltCreds, authHost, authEndpoint string, insecure bool) (*remotesapi.WhoAmIResponse, error) {
+	cfg, err := dEnv.GetGRPCDialParams(grpcendpoint.Config{
+		Endpoint: authEndpoint,
+		Creds:    dc.RPCCreds(authHost),
+		Insecure: insecure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to build dial options for connecting to server with credentials: %w", err)
+	}
+	conn, err := grpc.Dial(cfg.Endpoint, cfg.DialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to server with credentials: %w", err)
+	}
+	defer conn.Close()
+
+	whoAmI, err := remotesapi.NewCredentialsServiceClient(conn).WhoAmI(ctx, &remotesapi.WhoAmIRequest{})
+	if status.Code(err) == codes.Unavailable {
+		return nil, fmt.Errorf("unable to connect to server with credentials: %w", err)
+	} else if err != nil {
+		return nil, nil
+	}
+	return whoAmI, nil
+}
+
+func loginRow(loginStatus string, dc creds.DoltCreds, loginUrl string, whoAmI *remotesapi.WhoAmIResponse) []interface{} {
+	var username, email string
+	if whoAmI != nil {
+		username, email = whoAmI.Username, whoAmI.EmailAddress
+	}
+	return []interface{}{loginStatus, dc.KeyIDBase32Str(), dc.PubKeyBase32Str(), fmt.Sprintf("%s#%s", loginUrl, dc.PubKeyBase32Str()), username, email}
+}
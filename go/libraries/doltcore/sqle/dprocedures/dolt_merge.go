@@ -28,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -85,6 +86,9 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 		return "", noConflictsOrViolations, threeWayMerge, err
 	}
 
+	op := dsess.GlobalOperations.Start(ctx, dbName, "merge")
+	defer op.Finish()
+
 	sess := dsess.DSessFromSess(ctx.Session)
 
 	apr, err := cli.CreateMergeArgParser().Parse(args)
@@ -135,6 +139,11 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 		return "", noConflictsOrViolations, threeWayMerge, err
 	}
 
+	if apr.Contains(cli.DryRunFlag) {
+		conflicts, fastForward, err := dryRunMerge(ctx, sess, dbName, mergeSpec)
+		return "", conflicts, fastForward, err
+	}
+
 	dbData, ok := sess.GetDbData(ctx, dbName)
 	if !ok {
 		return "", noConflictsOrViolations, threeWayMerge, fmt.Errorf("Could not load database %s", dbName)
@@ -150,13 +159,54 @@ func doDoltMerge(ctx *sql.Context, args []string) (string, int, int, error) {
 	}
 
 	ws, commit, conflicts, fastForward, err := performMerge(ctx, sess, roots, ws, dbName, mergeSpec, apr.Contains(cli.NoCommitFlag), msg)
-	if err != nil || conflicts != 0 || fastForward != 0 {
+	if err != nil || conflicts != 0 {
 		return "", conflicts, fastForward, err
 	}
 
+	if !apr.Contains(cli.NoCommitFlag) && !apr.Contains(cli.SquashParam) {
+		deleteSource, err := dsess.GetBooleanSystemVar(ctx, dsess.MergeDeleteSource)
+		if err != nil {
+			return "", conflicts, fastForward, err
+		}
+		if deleteSource || apr.Contains(cli.DeleteSourceFlag) {
+			err = deleteMergedBranch(ctx, sess, dbData, headRef, branchName)
+			if err != nil {
+				return "", conflicts, fastForward, err
+			}
+		}
+	}
+
+	if fastForward != 0 {
+		return "", conflicts, fastForward, nil
+	}
 	return commit, conflicts, fastForward, nil
 }
 
+// deleteMergedBranch deletes the branch |branchName| and its working set once it has been merged into |headRef|.
+// Merges of commits, tags and remote branches have nothing to delete. Since the merge has already landed, a branch
+// that can't be deleted, because the user lacks permission or another session has it checked out, is kept with a
+// warning rather than failing the merge.
+func deleteMergedBranch(ctx *sql.Context, sess *dsess.DoltSession, dbData env.DbData, headRef ref.DoltRef, branchName string) error {
+	name, ok, err := dbData.Ddb.HasBranch(ctx, branchName)
+	if err != nil {
+		return err
+	} else if !ok || name == headRef.GetPath() {
+		return nil
+	}
+
+	if err = branch_control.CanDeleteBranch(ctx, name); err != nil {
+		ctx.Warn(DoltMergeWarningCode, "branch '%s' was not deleted: %s", name, err.Error())
+		return nil
+	}
+	if err = validateBranchNotActiveInAnySession(ctx, name); err != nil {
+		ctx.Warn(DoltMergeWarningCode, "branch '%s' was not deleted: %s", name, err.Error())
+		return nil
+	}
+
+	// the merge commit is already on |headRef|, so there's no need to check that the branch is merged
+	return actions.DeleteBranch(ctx, dbData, name, actions.DeleteOptions{Force: true}, sess.Provider(), nil)
+}
+
 // performMerge encapsulates server merge logic, switching between
 // fast-forward, no fast-forward, merge commit, and merging into working set.
 // Returns a new WorkingSet, whether there were merge conflicts, and whether a
@@ -224,7 +274,7 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 		return ws, "", noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
 	}
 
-	ws, err = executeMerge(ctx, spec.Squash, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts())
+	ws, err = executeMerge(ctx, spec.Squash, spec.VerifyForeignKeys, spec.HeadC, spec.MergeC, spec.MergeCSpecStr, ws, dbState.EditOpts())
 	if err == doltdb.ErrUnresolvedConflictsOrViolations {
 		// if there are unresolved conflicts, write the resulting working set back to the session and return an
 		// error message
@@ -256,6 +306,59 @@ func performMerge(ctx *sql.Context, sess *dsess.DoltSession, roots doltdb.Roots,
 	return ws, "", noConflictsOrViolations, threeWayMerge, nil
 }
 
+// dryRunMerge computes the merge described by |spec| and reports whether it would be a fast-forward and whether it
+// would produce conflicts or constraint violations. Neither the session's working set nor the branch head is modified.
+func dryRunMerge(ctx *sql.Context, sess *dsess.DoltSession, dbName string, spec *merge.MergeSpec) (int, int, error) {
+	canFF, err := spec.HeadC.CanFastForwardTo(ctx, spec.MergeC)
+	if err != nil {
+		switch err {
+		case doltdb.ErrIsAhead, doltdb.ErrUpToDate:
+			ctx.Warn(DoltMergeWarningCode, err.Error())
+			return noConflictsOrViolations, threeWayMerge, nil
+		default:
+			return noConflictsOrViolations, threeWayMerge, err
+		}
+	}
+	if canFF && !spec.Noff {
+		return noConflictsOrViolations, fastForwardMerge, nil
+	} else if canFF {
+		return noConflictsOrViolations, threeWayMerge, nil
+	}
+
+	dbState, ok, err := sess.LookupDbState(ctx, dbName)
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, err
+	} else if !ok {
+		return noConflictsOrViolations, threeWayMerge, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	mo := merge.MergeOpts{VerifyForeignKeys: spec.VerifyForeignKeys, TablesMerged: mergeProgress(ctx)}
+	result, err := merge.MergeCommits(ctx, spec.HeadC, spec.MergeC, dbState.EditOpts(), mo)
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, err
+	}
+	if result.HasMergeArtifacts() {
+		return hasConflictsOrViolations, threeWayMerge, nil
+	}
+	return noConflictsOrViolations, threeWayMerge, nil
+}
+
+// mergeProgress returns a MergeOpts.TablesMerged callback that reports the tables merged as the progress of the
+// operation running in the connection of |ctx|, such as a merge or a pull, or nil if there isn't one.
+func mergeProgress(ctx *sql.Context) func(merged, total int) {
+	op := dsess.GlobalOperations.Current(ctx)
+	if op == nil {
+		return nil
+	}
+	return func(merged, total int) {
+		if merged == 0 {
+			op.SetPhase("merging", "tables", uint64(total))
+			return
+		}
+		op.SetProgress(uint64(merged), uint64(total))
+	}
+}
+
 func abortMerge(ctx *sql.Context, workingSet *doltdb.WorkingSet, roots doltdb.Roots) (*doltdb.WorkingSet, error) {
 	tbls, err := doltdb.UnionTableNames(ctx, roots.Working, roots.Staged, roots.Head)
 	if err != nil {
@@ -272,8 +375,9 @@ func abortMerge(ctx *sql.Context, workingSet *doltdb.WorkingSet, roots doltdb.Ro
 	return workingSet, nil
 }
 
-func executeMerge(ctx *sql.Context, squash bool, head, cm *doltdb.Commit, cmSpec string, ws *doltdb.WorkingSet, opts editor.Options) (*doltdb.WorkingSet, error) {
-	result, err := merge.MergeCommits(ctx, head, cm, opts)
+func executeMerge(ctx *sql.Context, squash, verifyFKs bool, head, cm *doltdb.Commit, cmSpec string, ws *doltdb.WorkingSet, opts editor.Options) (*doltdb.WorkingSet, error) {
+	mo := merge.MergeOpts{VerifyForeignKeys: verifyFKs, TablesMerged: mergeProgress(ctx)}
+	result, err := merge.MergeCommits(ctx, head, cm, opts, mo)
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -421,7 +525,12 @@ func createMergeSpec(ctx *sql.Context, sess *dsess.DoltSession, dbName string, a
 	if apr.Contains(cli.NoCommitFlag) && apr.Contains(cli.CommitFlag) {
 		return nil, errors.New("cannot define both 'commit' and 'no-commit' flags at the same time")
 	}
-	return merge.NewMergeSpec(ctx, dbData.Rsr, ddb, roots, name, email, msg, commitSpecStr, apr.Contains(cli.SquashParam), apr.Contains(cli.NoFFParam), apr.Contains(cli.ForceFlag), apr.Contains(cli.NoCommitFlag), apr.Contains(cli.NoEditFlag), t)
+	spec, err := merge.NewMergeSpec(ctx, dbData.Rsr, ddb, roots, name, email, msg, commitSpecStr, apr.Contains(cli.SquashParam), apr.Contains(cli.NoFFParam), apr.Contains(cli.ForceFlag), apr.Contains(cli.NoCommitFlag), apr.Contains(cli.NoEditFlag), t)
+	if err != nil {
+		return nil, err
+	}
+	spec.VerifyForeignKeys = apr.Contains(cli.VerifyFKsFlag)
+	return spec, nil
 }
 
 // TODO: this copied from commands/merge.go because the latter isn't reusable. Fix that.
@@ -52,6 +52,9 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 		return noConflictsOrViolations, threeWayMerge, err
 	}
 
+	op := dsess.GlobalOperations.Start(ctx, dbName, "pull")
+	defer op.Finish()
+
 	sess := dsess.DSessFromSess(ctx.Session)
 	dbData, ok := sess.GetDbData(ctx, dbName)
 	if !ok {
@@ -85,6 +88,22 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 		return noConflictsOrViolations, threeWayMerge, fmt.Errorf("failed to get remote db; %w", err)
 	}
 
+	verify, err := env.VerifyFetches(loadConfig(ctx), apr.Contains(cli.VerifyFlag))
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, err
+	}
+	if verify {
+		srcDB = srcDB.WithVerifiedPulls()
+	}
+
+	cacheDir, err := env.SharedCacheDir(loadConfig(ctx))
+	if err != nil {
+		return noConflictsOrViolations, threeWayMerge, err
+	}
+	if cacheDir != "" {
+		srcDB = srcDB.WithSharedCache(cacheDir)
+	}
+
 	ws, err := sess.WorkingSet(ctx, dbName)
 	if err != nil {
 		return noConflictsOrViolations, threeWayMerge, err
@@ -121,8 +140,7 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 			if err != nil {
 				return noConflictsOrViolations, threeWayMerge, err
 			}
-			// todo: can we pass nil for either of the channels?
-			srcDBCommit, err := actions.FetchRemoteBranch(ctx, tmpDir, pullSpec.Remote, srcDB, dbData.Ddb, branchRef, runProgFuncs, stopProgFuncs)
+			srcDBCommit, err := actions.FetchRemoteBranch(ctx, tmpDir, pullSpec.Remote, srcDB, dbData.Ddb, branchRef, progStarter(op, "fetching"), stopProgFuncs)
 			if err != nil {
 				return noConflictsOrViolations, threeWayMerge, err
 			}
@@ -172,7 +190,7 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 	if err != nil {
 		return noConflictsOrViolations, threeWayMerge, err
 	}
-	err = actions.FetchFollowTags(ctx, tmpDir, srcDB, dbData.Ddb, runProgFuncs, stopProgFuncs)
+	err = actions.FetchFollowTags(ctx, tmpDir, srcDB, dbData.Ddb, progStarter(op, "fetching tags"), stopProgFuncs)
 	if err != nil {
 		return conflicts, fastForward, err
 	}
@@ -180,36 +198,45 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 	return conflicts, fastForward, nil
 }
 
-// TODO: remove this as it does not do anything useful
-func pullerProgFunc(ctx context.Context, statsCh <-chan pull.Stats) {
-	for {
-		if ctx.Err() != nil {
-			return
-		}
-		select {
-		case <-ctx.Done():
-			return
-		case <-statsCh:
-		default:
-		}
-	}
-}
-
-// TODO: remove this as it does not do anything useful
-func runProgFuncs(ctx context.Context) (*sync.WaitGroup, chan pull.Stats) {
-	statsCh := make(chan pull.Stats)
-	wg := &sync.WaitGroup{}
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		pullerProgFunc(ctx, statsCh)
-	}()
+// progStarter returns an actions.ProgStarter that reports the stats of pulling chunks as the progress of |op|: the
+// chunks read from the source database in the |phase| phase, and then the bytes of the table files written from them
+// while they're uploaded to the destination, if they are.
+func progStarter(op *dsess.Operation, phase string) actions.ProgStarter {
+	return func(ctx context.Context) (*sync.WaitGroup, chan pull.Stats) {
+		statsCh := make(chan pull.Stats)
+		wg := &sync.WaitGroup{}
+		op.SetPhase(phase, "chunks", 0)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uploading := false
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case stats, ok := <-statsCh:
+					if !ok {
+						return
+					}
+					allFetched := stats.TotalSourceChunks > 0 && stats.FetchedSourceChunks >= stats.TotalSourceChunks
+					if !uploading && allFetched && stats.BufferedSendBytes > stats.FinishedSendBytes {
+						uploading = true
+						op.SetPhase("uploading", "bytes", stats.BufferedSendBytes)
+					}
+					if uploading {
+						op.SetProgress(stats.FinishedSendBytes, stats.BufferedSendBytes)
+					} else {
+						op.SetProgress(stats.FetchedSourceChunks, stats.TotalSourceChunks)
+					}
+				}
+			}
+		}()
 
-	return wg, statsCh
+		return wg, statsCh
+	}
 }
 
-// TODO: remove this as it does not do anything useful
 func stopProgFuncs(cancel context.CancelFunc, wg *sync.WaitGroup, statsCh chan pull.Stats) {
 	cancel()
 	close(statsCh)
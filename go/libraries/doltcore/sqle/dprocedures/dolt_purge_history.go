@@ -0,0 +1,218 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const DoltPurgeHistoryWarningCode int = 1111 // Custom warning code
+
+var ErrPurgeHistoryUncommittedChanges = errors.New("cannot purge history with uncommitted changes")
+
+// doltPurgeHistory is the stored procedure that removes a table from the commits of the current database created
+// before the --before date, keeping the table in recent commits and in the commits that branches and tags point to.
+// The history of every branch and tag is rewritten, so it requires the SUPER privilege. The old versions of the table
+// are removed by the next dolt_gc().
+func doltPurgeHistory(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltPurgeHistory(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltPurgeHistory(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 0, fmt.Errorf("Empty database name.")
+	}
+	if err := checkSuperPrivilege(ctx); err != nil {
+		return 0, err
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 0, err
+	}
+
+	apr, err := cli.CreatePurgeHistoryArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+	if apr.NArg() != 1 {
+		return 0, fmt.Errorf("dolt_purge_history requires a table name")
+	}
+	tableName := apr.Arg(0)
+	beforeStr, ok := apr.GetValue(cli.PurgeBeforeParam)
+	if !ok {
+		return 0, fmt.Errorf("--%s is required", cli.PurgeBeforeParam)
+	}
+	cutoff, err := cli.ParseDate(beforeStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid date: %w", err)
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+	if err = checkCleanRoots(roots); err != nil {
+		return 0, err
+	}
+
+	branches, err := ddb.GetBranchesWithHashes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tags, err := ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	heads := make(map[hash.Hash]struct{}, len(branches)+len(tags))
+	origins := make([]*doltdb.Commit, 0, len(branches)+len(tags))
+	for _, b := range branches {
+		heads[b.Hash] = struct{}{}
+		cm, err := ddb.ResolveCommitRef(ctx, b.Ref)
+		if err != nil {
+			return 0, err
+		}
+		origins = append(origins, cm)
+	}
+	for _, t := range tags {
+		heads[t.Hash] = struct{}{}
+		origins = append(origins, t.Tag.Commit)
+	}
+
+	purged := 0
+	replay := func(ctx context.Context, commit, _, _ *doltdb.Commit) (*doltdb.RootValue, error) {
+		root, err := commit.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		purge, err := shouldPurgeFromCommit(ctx, commit, cutoff, heads)
+		if err != nil || !purge {
+			return root, err
+		}
+		if ok, err := root.HasTable(ctx, tableName); err != nil || !ok {
+			return root, err
+		}
+		purged++
+		return root.RemoveTables(ctx, false, false, tableName)
+	}
+
+	rewritten, err := rebase.Commits(ctx, ddb, replay, rebase.EntireHistory(), origins...)
+	if err != nil || purged == 0 {
+		return 0, err
+	}
+
+	// the new tags are written before any ref is moved, and each ref is moved in a single update
+	tagAddrs := make([]hash.Hash, len(tags))
+	for i, t := range tags {
+		rewrittenHash, err := rewritten[len(branches)+i].HashOf()
+		if err != nil {
+			return 0, err
+		}
+		if rewrittenHash == t.Hash {
+			continue
+		}
+		tagAddrs[i], err = ddb.BuildTagAtCommit(ctx, rewritten[len(branches)+i], t.Tag.Meta)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i, b := range branches {
+		rewrittenHash, err := rewritten[i].HashOf()
+		if err != nil {
+			return 0, err
+		}
+		if rewrittenHash == b.Hash {
+			continue
+		}
+		// the working set of the branch is left alone, its head has the same root value
+		err = ddb.SetHeadToCommitIfUnchanged(ctx, b.Ref, rewritten[i], b.Hash)
+		if err == datas.ErrMergeNeeded {
+			ctx.Warn(DoltPurgeHistoryWarningCode, "branch %s changed while its history was rewritten, it will be purged next time", b.Ref.GetPath())
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	for i, t := range tags {
+		if tagAddrs[i].IsEmpty() {
+			continue
+		}
+		if err = ddb.SetHead(ctx, ref.NewTagRef(t.Tag.Name), tagAddrs[i]); err != nil {
+			return 0, err
+		}
+	}
+	return purged, nil
+}
+
+// checkCleanRoots returns an error if |roots| has staged or working changes.
+func checkCleanRoots(roots doltdb.Roots) error {
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return err
+	}
+	stagedHash, err := roots.Staged.HashOf()
+	if err != nil {
+		return err
+	}
+	workingHash, err := roots.Working.HashOf()
+	if err != nil {
+		return err
+	}
+	if headHash != stagedHash || headHash != workingHash {
+		return ErrPurgeHistoryUncommittedChanges
+	}
+	return nil
+}
+
+// shouldPurgeFromCommit returns whether old versions of the table should be removed from |commit|, which is true for
+// commits created before |cutoff| that no branch or tag points to.
+func shouldPurgeFromCommit(ctx context.Context, commit *doltdb.Commit, cutoff time.Time, heads map[hash.Hash]struct{}) (bool, error) {
+	h, err := commit.HashOf()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := heads[h]; ok {
+		return false, nil
+	}
+
+	meta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return false, err
+	}
+	return meta.Time().Before(cutoff), nil
+}
@@ -15,8 +15,10 @@
 package dprocedures
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/store/datas"
 )
 
@@ -48,6 +51,9 @@ func doDoltPush(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, err
 	}
 
+	op := dsess.GlobalOperations.Start(ctx, dbName, "push")
+	defer op.Finish()
+
 	sess := dsess.DSessFromSess(ctx.Session)
 	dbData, ok := sess.GetDbData(ctx, dbName)
 
@@ -66,6 +72,10 @@ func doDoltPush(ctx *sql.Context, args []string) (int, error) {
 		return cmdFailure, err
 	}
 
+	if apr.ContainsAny(cli.AllFlag, cli.TagsFlag) {
+		return doDoltPushAll(ctx, apr, dbData, sess, op)
+	}
+
 	opts, err := env.NewPushOpts(ctx, apr, dbData.Rsr, dbData.Ddb, apr.Contains(cli.ForceFlag), apr.Contains(cli.SetUpstreamFlag), pushAutoSetUpRemote)
 	if err != nil {
 		return cmdFailure, err
@@ -79,7 +89,7 @@ func doDoltPush(ctx *sql.Context, args []string) (int, error) {
 	if err != nil {
 		return cmdFailure, err
 	}
-	err = actions.DoPush(ctx, dbData.Rsr, dbData.Rsw, dbData.Ddb, remoteDB, tmpDir, opts, runProgFuncs, stopProgFuncs)
+	err = actions.DoPush(ctx, dbData.Rsr, dbData.Rsw, dbData.Ddb, remoteDB, tmpDir, opts, progStarter(op, "pushing"), stopProgFuncs)
 	if err != nil {
 		switch err {
 		case doltdb.ErrUpToDate:
@@ -93,3 +103,41 @@ func doDoltPush(ctx *sql.Context, args []string) (int, error) {
 	// TODO : set upstream should be persisted outside of session
 	return cmdSuccess, nil
 }
+
+// doDoltPushAll pushes all branches and/or tags to the remote, as requested by --all and --tags
+func doDoltPushAll(ctx *sql.Context, apr *argparser.ArgParseResults, dbData env.DbData, sess *dsess.DoltSession, op *dsess.Operation) (int, error) {
+	opts, err := env.NewPushAllOpts(ctx, apr.Args, dbData.Rsr, dbData.Ddb, apr.Contains(cli.AllFlag), apr.Contains(cli.TagsFlag), apr.Contains(cli.ForceFlag), apr.Contains(cli.SetUpstreamFlag))
+	if err != nil {
+		return cmdFailure, err
+	}
+	if len(opts) == 0 {
+		return cmdSuccess, nil
+	}
+
+	remote := opts[0].Remote
+	remoteDB, err := sess.Provider().GetRemoteDB(ctx, dbData.Ddb.ValueReadWriter().Format(), remote, true)
+	if err != nil {
+		return cmdFailure, actions.HandleInitRemoteStorageClientErr(remote.Name, remote.Url, err)
+	}
+
+	tmpDir, err := dbData.Rsw.TempTableFilesDir()
+	if err != nil {
+		return cmdFailure, err
+	}
+
+	results, err := actions.PushAll(ctx, dbData.Rsw, dbData.Ddb, remoteDB, tmpDir, opts, progStarter(op, "pushing"), stopProgFuncs)
+	if err != nil {
+		return cmdFailure, err
+	}
+
+	var failed []string
+	for i, res := range results {
+		if res != nil && !errors.Is(res, doltdb.ErrUpToDate) {
+			failed = append(failed, fmt.Sprintf("%s (%s)", opts[i].SrcRef.GetPath(), res.Error()))
+		}
+	}
+	if len(failed) > 0 {
+		return cmdFailure, fmt.Errorf("failed to push some refs to '%s': %s", remote.Url, strings.Join(failed, ", "))
+	}
+	return cmdSuccess, nil
+}
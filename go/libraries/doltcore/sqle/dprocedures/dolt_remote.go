@@ -162,6 +162,15 @@ func remoteParams(apr *argparser.ArgParseResults, scheme, remoteUrl string) (map
 	default:
 		err = cli.VerifyNoAwsParams(apr)
 	}
+	if err == nil {
+		err = cli.AddGCSParams(remoteUrl, apr, params)
+	}
+	if err == nil {
+		err = cli.AddAzureParams(remoteUrl, apr, params)
+	}
+	if err == nil {
+		err = cli.AddOCIParams(remoteUrl, apr, params)
+	}
 
 	if err != nil {
 		return nil, errhand.VerboseErrorFromError(err)
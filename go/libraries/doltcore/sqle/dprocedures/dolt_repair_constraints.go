@@ -0,0 +1,388 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/pool"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// constraintRepairStrategy is the set of repairs requested of dolt_repair_constraints.
+type constraintRepairStrategy struct {
+	deleteOrphans bool
+	nullifyFks    bool
+	dedupeUnique  bool
+}
+
+// doltRepairConstraints is the stored procedure that repairs the rows recorded in dolt_constraint_violations, most
+// commonly after a merge, and stages the repaired tables. It returns the number of rows that were repaired.
+func doltRepairConstraints(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltRepairConstraints(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltRepairConstraints(ctx *sql.Context, args []string) (int, error) {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 0, err
+	}
+	dbName := ctx.GetCurrentDatabase()
+
+	apr, err := cli.CreateRepairConstraintsArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+
+	strategy := constraintRepairStrategy{
+		deleteOrphans: apr.Contains(cli.DeleteOrphansFlag),
+		nullifyFks:    apr.Contains(cli.NullifyFksFlag),
+		dedupeUnique:  apr.Contains(cli.DedupeUniqueFlag),
+	}
+	if strategy.deleteOrphans && strategy.nullifyFks {
+		return 0, fmt.Errorf("specify only either --%s or --%s", cli.DeleteOrphansFlag, cli.NullifyFksFlag)
+	} else if !strategy.deleteOrphans && !strategy.nullifyFks && !strategy.dedupeUnique {
+		return 0, fmt.Errorf("specify at least one of --%s, --%s or --%s", cli.DeleteOrphansFlag, cli.NullifyFksFlag, cli.DedupeUniqueFlag)
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+	working := roots.Working
+
+	var tblNames []string
+	if apr.NArg() == 0 {
+		tblNames, err = working.TablesWithConstraintViolations(ctx)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		for _, arg := range apr.Args {
+			_, tblName, ok, err := working.GetTableInsensitive(ctx, arg)
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				return 0, doltdb.ErrTableNotFound
+			}
+			tblNames = append(tblNames, tblName)
+		}
+	}
+
+	repaired := 0
+	var repairedTbls []string
+	for _, tblName := range tblNames {
+		tbl, _, err := working.GetTable(ctx, tblName)
+		if err != nil {
+			return 0, err
+		}
+		if tbl.Format() != types.Format_DOLT {
+			return 0, fmt.Errorf("dolt_repair_constraints is not supported for the legacy storage format")
+		}
+		sch, err := tbl.GetSchema(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if schema.IsKeyless(sch) {
+			return 0, fmt.Errorf("can not repair constraint violations in keyless table '%s'", tblName)
+		}
+
+		tbl, n, err := repairProllyConstraintViolations(ctx, tbl, sch, strategy)
+		if err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			continue
+		}
+		working, err = working.PutTable(ctx, tblName, tbl)
+		if err != nil {
+			return 0, err
+		}
+		repaired += n
+		repairedTbls = append(repairedTbls, tblName)
+	}
+	if repaired == 0 {
+		return 0, nil
+	}
+
+	roots.Working = working
+	roots, err = actions.StageTables(ctx, roots, repairedTbls, false)
+	if err != nil {
+		return 0, err
+	}
+	if err = dSess.SetRoots(ctx, dbName, roots); err != nil {
+		return 0, err
+	}
+	return repaired, nil
+}
+
+// repairProllyConstraintViolations applies |strategy| to the rows of |tbl| that are recorded as constraint violations,
+// removing the violations it repairs. Violations that |strategy| doesn't cover are left in place. Deleting or
+// changing rows never records new violations, so a row that is referenced by a foreign key of another table may leave
+// that table's rows orphaned; run dolt_verify_constraints afterwards to find them.
+func repairProllyConstraintViolations(ctx *sql.Context, tbl *doltdb.Table, sch schema.Schema, strategy constraintRepairStrategy) (*doltdb.Table, int, error) {
+	artIdx, err := tbl.GetArtifacts(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	artMap := durable.ProllyMapFromArtifactIndex(artIdx)
+
+	rowIdx, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows := durable.ProllyMapFromIndex(rowIdx)
+	mut := rows.Mutate()
+
+	idxSet, err := tbl.GetIndexSet(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	mutIdxs, err := merge.GetMutableSecondaryIdxs(ctx, sch, idxSet)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	iter, err := artMap.IterAllCVs(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_, vd := sch.GetMapDescriptors()
+	var resolved []val.Tuple
+	deleted := make(map[string]struct{})
+	// unique key values that have kept a row, by unique index. Artifacts are ordered by primary key, so the row that
+	// is kept is the one with the lowest primary key.
+	kept := make(map[string]struct{})
+	repaired := 0
+
+	deleteRow := func(k, v val.Tuple) error {
+		if err := mut.Delete(ctx, k); err != nil {
+			return err
+		}
+		for _, mutIdx := range mutIdxs {
+			if err := mutIdx.DeleteEntry(ctx, k, v); err != nil {
+				return err
+			}
+		}
+		deleted[string(k)] = struct{}{}
+		repaired++
+		return nil
+	}
+
+	for {
+		art, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, 0, err
+		}
+		if _, ok := deleted[string(art.SourceKey)]; ok {
+			continue
+		}
+
+		var value val.Tuple
+		err = mut.Get(ctx, art.SourceKey, func(_, v val.Tuple) error {
+			value = v
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		if value == nil {
+			// the row is gone, so is the violation
+			resolved = append(resolved, art.ArtKey)
+			continue
+		}
+
+		var meta prolly.ConstraintViolationMeta
+		if err = json.Unmarshal(art.Metadata, &meta); err != nil {
+			return nil, 0, err
+		}
+
+		switch art.ArtType {
+		case prolly.ArtifactTypeForeignKeyViol:
+			if strategy.deleteOrphans {
+				if err = deleteRow(art.SourceKey, value); err != nil {
+					return nil, 0, err
+				}
+			} else if strategy.nullifyFks {
+				var fkMeta merge.FkCVMeta
+				if err = json.Unmarshal(meta.VInfo, &fkMeta); err != nil {
+					return nil, 0, err
+				}
+				newValue, err := nullifyColumns(sch, vd, value, fkMeta.Columns, rows.Pool())
+				if err != nil {
+					return nil, 0, err
+				}
+				if err = mut.Put(ctx, art.SourceKey, newValue); err != nil {
+					return nil, 0, err
+				}
+				for _, mutIdx := range mutIdxs {
+					if err = mutIdx.UpdateEntry(ctx, art.SourceKey, value, newValue); err != nil {
+						return nil, 0, err
+					}
+				}
+				resolved = append(resolved, art.ArtKey)
+				repaired++
+			}
+		case prolly.ArtifactTypeUniqueKeyViol:
+			if strategy.dedupeUnique {
+				var uniqMeta merge.UniqCVMeta
+				if err = json.Unmarshal(meta.VInfo, &uniqMeta); err != nil {
+					return nil, 0, err
+				}
+				group, err := uniqueKeyGroup(sch, uniqMeta, art.SourceKey, value)
+				if err != nil {
+					return nil, 0, err
+				}
+				if _, ok := kept[group]; !ok {
+					kept[group] = struct{}{}
+					resolved = append(resolved, art.ArtKey)
+				} else if err = deleteRow(art.SourceKey, value); err != nil {
+					return nil, 0, err
+				}
+			}
+		}
+	}
+
+	// every violation of a deleted row is resolved, including those seen before the row was deleted
+	if len(deleted) > 0 {
+		iter, err = artMap.IterAllCVs(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		for {
+			art, err := iter.Next(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, 0, err
+			}
+			if _, ok := deleted[string(art.SourceKey)]; ok {
+				resolved = append(resolved, art.ArtKey)
+			}
+		}
+	}
+
+	if len(resolved) == 0 {
+		return tbl, 0, nil
+	}
+
+	editor := artMap.Editor()
+	for _, k := range resolved {
+		if err = editor.Delete(ctx, k); err != nil {
+			return nil, 0, err
+		}
+	}
+	artMap, err = editor.Flush(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	newRows, err := mut.Map(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	tbl, err = tbl.UpdateRows(ctx, durable.IndexFromProllyMap(newRows))
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, mutIdx := range mutIdxs {
+		m, err := mutIdx.Map(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		idxSet, err = idxSet.PutIndex(ctx, mutIdx.Name, durable.IndexFromProllyMap(m))
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	tbl, err = tbl.SetIndexSet(ctx, idxSet)
+	if err != nil {
+		return nil, 0, err
+	}
+	tbl, err = tbl.SetArtifacts(ctx, durable.ArtifactIndexFromProllyMap(artMap))
+	if err != nil {
+		return nil, 0, err
+	}
+	return tbl, repaired, nil
+}
+
+// nullifyColumns returns a copy of the row value |v| with the columns named |cols| set to NULL.
+func nullifyColumns(sch schema.Schema, vd val.TupleDesc, v val.Tuple, cols []string, pool pool.BuffPool) (val.Tuple, error) {
+	nulled := make(map[int]struct{}, len(cols))
+	for _, name := range cols {
+		if sch.GetPKCols().Contains(name) {
+			return nil, fmt.Errorf("can not nullify foreign key column '%s', it is part of the primary key", name)
+		}
+		i := sch.GetNonPKCols().IndexOf(name)
+		if i < 0 {
+			return nil, fmt.Errorf("foreign key column '%s' not found", name)
+		}
+		if !vd.Types[i].Nullable {
+			return nil, fmt.Errorf("can not nullify foreign key column '%s', it is not nullable", name)
+		}
+		nulled[i] = struct{}{}
+	}
+
+	tb := val.NewTupleBuilder(vd)
+	for i := 0; i < vd.Count(); i++ {
+		if _, ok := nulled[i]; ok {
+			continue
+		}
+		tb.PutRaw(i, v.GetField(i))
+	}
+	return tb.Build(pool), nil
+}
+
+// uniqueKeyGroup returns a string identifying the unique index and the unique key values of a row in violation.
+func uniqueKeyGroup(sch schema.Schema, meta merge.UniqCVMeta, k, v val.Tuple) (string, error) {
+	buf := []byte(meta.Name)
+	buf = append(buf, 0)
+	for _, name := range meta.Columns {
+		var field []byte
+		if i := sch.GetPKCols().IndexOf(name); i >= 0 {
+			field = k.GetField(i)
+		} else if i = sch.GetNonPKCols().IndexOf(name); i >= 0 {
+			field = v.GetField(i)
+		} else {
+			return "", fmt.Errorf("unique key column '%s' not found", name)
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(field)))
+		buf = append(buf, field...)
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rebase"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+const (
+	DoltRetentionWarningCode int = 1110 // Custom warning code
+
+	defaultRetentionSnapshotDays = 7
+)
+
+// doltRetention is the stored procedure that applies a history retention policy to the current database: the
+// commits made in the last --keep-days days are kept, and the commits made before then are squashed into one
+// snapshot per --snapshot-days days. Tags keep a snapshot of the commit they point to. The history that's no longer
+// referenced is removed by the next dolt_gc(). Since it rewrites the history of every user's branches, it requires
+// the SUPER privilege.
+func doltRetention(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	res, err := doDoltRetention(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(int64(res)), nil
+}
+
+func doDoltRetention(ctx *sql.Context, args []string) (int, error) {
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return 0, fmt.Errorf("Empty database name.")
+	}
+	if err := checkSuperPrivilege(ctx); err != nil {
+		return 0, err
+	}
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return 0, err
+	}
+
+	apr, err := cli.CreateRetentionArgParser().Parse(args)
+	if err != nil {
+		return 0, err
+	}
+	keepDays, ok := apr.GetInt(cli.KeepDaysParam)
+	if !ok || keepDays <= 0 {
+		return 0, fmt.Errorf("--%s must be a positive number of days", cli.KeepDaysParam)
+	}
+	snapshotDays := apr.GetIntOrDefault(cli.SnapshotDaysParam, defaultRetentionSnapshotDays)
+	if snapshotDays <= 0 {
+		return 0, fmt.Errorf("--%s must be a positive number of days", cli.SnapshotDaysParam)
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return 0, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	var branches []ref.DoltRef
+	if apr.NArg() == 0 {
+		if branches, err = ddb.GetBranches(ctx); err != nil {
+			return 0, err
+		}
+	} else {
+		for _, name := range apr.Args {
+			branches = append(branches, ref.NewBranchRef(name))
+		}
+	}
+
+	const day = 24 * time.Hour
+	squasher, err := rebase.NewHistorySquasher(ddb, time.Now().Add(-time.Duration(keepDays)*day), time.Duration(snapshotDays)*day)
+	if err != nil {
+		return 0, err
+	}
+
+	rewritten := 0
+	for _, branch := range branches {
+		ok, err := squashBranch(ctx, ddb, squasher, branch)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			rewritten++
+		}
+	}
+	if err = squashTags(ctx, ddb, squasher); err != nil {
+		return 0, err
+	}
+	return rewritten, nil
+}
+
+// squashBranch squashes the history of |branch|, returning whether it changed. The branch isn't changed if a commit
+// was made to it while its history was squashed.
+func squashBranch(ctx *sql.Context, ddb *doltdb.DoltDB, squasher *rebase.HistorySquasher, branch ref.DoltRef) (bool, error) {
+	head, err := ddb.ResolveCommitRef(ctx, branch)
+	if err != nil {
+		return false, err
+	}
+	headHash, err := head.HashOf()
+	if err != nil {
+		return false, err
+	}
+	squashed, err := squasher.Squash(ctx, head)
+	if err != nil {
+		return false, err
+	}
+	squashedHash, err := squashed.HashOf()
+	if err != nil {
+		return false, err
+	}
+	if squashedHash == headHash {
+		return false, nil
+	}
+
+	// the working set of the branch is left alone, its head has the same root value
+	err = ddb.SetHeadToCommitIfUnchanged(ctx, branch, squashed, headHash)
+	if err == datas.ErrMergeNeeded {
+		ctx.Warn(DoltRetentionWarningCode, "branch %s changed while its history was squashed, it will be squashed next time", branch.GetPath())
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// squashTags squashes the history of every tag, so that tags keep a snapshot of the commits they point to but not
+// the rest of their history.
+func squashTags(ctx *sql.Context, ddb *doltdb.DoltDB, squasher *rebase.HistorySquasher) error {
+	tags, err := ddb.GetTagsWithHashes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range tags {
+		squashed, err := squasher.Squash(ctx, t.Tag.Commit)
+		if err != nil {
+			return err
+		}
+		squashedHash, err := squashed.HashOf()
+		if err != nil {
+			return err
+		}
+		if squashedHash == t.Hash {
+			continue
+		}
+		tagRef := ref.NewTagRef(t.Tag.Name)
+		if err = ddb.DeleteTag(ctx, tagRef); err != nil {
+			return err
+		}
+		if err = ddb.NewTagAtCommit(ctx, tagRef, squashed, t.Tag.Meta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
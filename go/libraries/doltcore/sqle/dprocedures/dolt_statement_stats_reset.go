@@ -0,0 +1,47 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltStatementStatsReset is the stored procedure that clears the stats shown in dolt_statement_stats for the current
+// database, or for all databases with --all.
+func doltStatementStatsReset(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	apr, err := cli.CreateStatementStatsResetArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if apr.Contains(cli.AllFlag) {
+		dsess.GlobalStatementStats.Reset("")
+		return rowToIter(int64(0)), nil
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if len(dbName) == 0 {
+		return nil, fmt.Errorf("Empty database name.")
+	}
+
+	dbName, _ = dsess.SplitRevisionDbName(dbName)
+	dsess.GlobalStatementStats.Reset(dbName)
+	return rowToIter(int64(0)), nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 )
@@ -86,10 +87,16 @@ func doDoltTag(ctx *sql.Context, args []string) (int, error) {
 
 	msg, _ := apr.GetValue(cli.MessageArg)
 
+	sign, err := env.GetSignFunc(ctx, loadConfig(ctx), env.TagGpgSign, apr.Contains(cli.SignFlag), apr.Contains(cli.NoSignFlag))
+	if err != nil {
+		return 1, err
+	}
+
 	props := actions.TagProps{
 		TaggerName:  name,
 		TaggerEmail: email,
 		Description: msg,
+		Sign:        sign,
 	}
 
 	tagName := apr.Arg(0)
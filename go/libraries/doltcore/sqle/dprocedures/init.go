@@ -36,11 +36,17 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	// dolt_gc is enabled behind a feature flag for now, see dolt_gc.go
 	{Name: "dolt_gc", Schema: int64Schema("success"), Function: doltGC},
 
+	{Name: "dolt_login", Schema: doltLoginSchema, Function: doltLogin},
+
 	{Name: "dolt_merge", Schema: doltMergeSchema, Function: doltMerge},
 	{Name: "dolt_pull", Schema: int64Schema("fast_forward", "conflicts"), Function: doltPull},
+	{Name: "dolt_purge_history", Schema: int64Schema("commits_purged"), Function: doltPurgeHistory},
 	{Name: "dolt_push", Schema: int64Schema("success"), Function: doltPush},
 	{Name: "dolt_remote", Schema: int64Schema("status"), Function: doltRemote},
+	{Name: "dolt_repair_constraints", Schema: int64Schema("repaired"), Function: doltRepairConstraints},
 	{Name: "dolt_reset", Schema: int64Schema("status"), Function: doltReset},
+	{Name: "dolt_retention", Schema: int64Schema("branches_squashed"), Function: doltRetention},
+	{Name: "dolt_statement_stats_reset", Schema: int64Schema("status"), Function: doltStatementStatsReset},
 	{Name: "dolt_revert", Schema: int64Schema("status"), Function: doltRevert},
 	{Name: "dolt_tag", Schema: int64Schema("status"), Function: doltTag},
 	{Name: "dolt_verify_constraints", Schema: int64Schema("violations"), Function: doltVerifyConstraints},
@@ -15,13 +15,19 @@
 package dsess
 
 import (
+	"context"
+	"sync"
+
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 // InitialDbState is the initial state of a database, as returned by SessionDatabase.InitialDBState. It is used to
@@ -31,6 +37,9 @@ type InitialDbState struct {
 	// WorkingSet is the working set for this database. May be nil for databases tied to a detached root value, in which
 	// case HeadCommit must be set
 	WorkingSet *doltdb.WorkingSet
+	// LoadWorkingSet loads the working set for this database the first time it's accessed. If it's set, WorkingSet is
+	// nil.
+	LoadWorkingSet WorkingSetLoader
 	// The head commit for this database. May be nil for databases tied to a detached root value, in which case
 	// RootValue must be set.
 	HeadCommit *doltdb.Commit
@@ -49,6 +58,24 @@ type InitialDbState struct {
 	Err error
 }
 
+// WorkingSetLoader loads the working set of a database for InitialDbState.
+type WorkingSetLoader func(ctx context.Context) (*doltdb.WorkingSet, error)
+
+// NewWorkingSetLoader returns a WorkingSetLoader that resolves the working set |wsRef| of |ddb| at |nomsRoot|. The
+// working set is resolved only once, the first time the loader is called, and the same result is returned after, so
+// that sessions sharing a cached InitialDbState share the working set too.
+func NewWorkingSetLoader(ddb *doltdb.DoltDB, wsRef ref.WorkingSetRef, nomsRoot hash.Hash) WorkingSetLoader {
+	var once sync.Once
+	var ws *doltdb.WorkingSet
+	var err error
+	return func(ctx context.Context) (*doltdb.WorkingSet, error) {
+		once.Do(func() {
+			ws, err = ddb.ResolveWorkingSetAtRoot(ctx, wsRef, nomsRoot)
+		})
+		return ws, err
+	}
+}
+
 // SessionDatabase is a database that can be managed by a dsess.Session. It has methods to return its initial state in
 // order for the session to manage it.
 type SessionDatabase interface {
@@ -113,15 +140,26 @@ type branchState struct {
 	// headCommit is the head commit for this database. May be nil for databases tied to a detached root value, in which
 	// case headRoot must be set.
 	headCommit *doltdb.Commit
-	// HeadRoot is the root value for databases without a headCommit. Nil for databases with a headCommit.
+	// headRoot is the root value of headCommit, or the root value for databases without a headCommit. For databases
+	// with a headCommit, it's loaded by load.
 	headRoot *doltdb.RootValue
 	// workingSet is the working set for this database. May be nil for databases tied to a detached root value, in which
-	// case headCommit must be set
+	// case headCommit must be set. If loadWorkingSet is set, it's loaded by load.
 	workingSet *doltdb.WorkingSet
+	// loadWorkingSet loads workingSet. Nil for heads whose working set was given, and for detached heads.
+	loadWorkingSet WorkingSetLoader
+	// loadOnce guards the loading of workingSet and headRoot, and loadErr is any error loading them
+	loadOnce sync.Once
+	loadErr  error
 	// dbData is an accessor for the underlying doltDb
 	dbData env.DbData
-	// writeSession is this head's write session
+	// writeSession is this head's write session. It's created the first time the head is written to, so that sessions
+	// that only read a head don't set one up. Nil until then.
 	writeSession writer.WriteSession
+	// nbf, aiTracker and editOpts are used to create the write session
+	nbf       *types.NomsBinFormat
+	aiTracker globalstate.AutoIncrementTracker
+	editOpts  editor.Options
 	// readOnly is true if this database is read only
 	readOnly bool
 	// dirty is true if this branch state has uncommitted changes
@@ -145,6 +183,23 @@ func (dbState *DatabaseSessionState) NewEmptyBranchState(head string) *branchSta
 	return b
 }
 
+// load loads the working set and the head root of this head the first time it's called. Sessions that only look up
+// a head, e.g. to switch to it or to read its head commit, don't load them.
+func (bs *branchState) load(ctx context.Context) error {
+	bs.loadOnce.Do(func() {
+		if bs.loadWorkingSet != nil {
+			bs.workingSet, bs.loadErr = bs.loadWorkingSet(ctx)
+			if bs.loadErr != nil {
+				return
+			}
+		}
+		if bs.headRoot == nil && bs.headCommit != nil {
+			bs.headRoot, bs.loadErr = bs.headCommit.GetRootValue(ctx)
+		}
+	})
+	return bs.loadErr
+}
+
 func (bs *branchState) WorkingRoot() *doltdb.RootValue {
 	return bs.roots().Working
 }
@@ -155,7 +210,12 @@ func (bs *branchState) WorkingSet() *doltdb.WorkingSet {
 	return bs.workingSet
 }
 
+// WriteSession returns this head's write session, creating it if it hasn't been written to yet. Returns nil for heads
+// without a working set.
 func (bs *branchState) WriteSession() writer.WriteSession {
+	if bs.writeSession == nil && bs.workingSet != nil {
+		bs.writeSession = writer.NewWriteSession(bs.nbf, bs.workingSet, bs.aiTracker, bs.editOpts)
+	}
 	return bs.writeSession
 }
 
@@ -163,8 +223,21 @@ func (bs *branchState) SessionCache() *SessionCache {
 	return bs.dbState.headCache[bs.head]
 }
 
-func (bs branchState) EditOpts() editor.Options {
-	return bs.WriteSession().GetOptions()
+func (bs *branchState) EditOpts() editor.Options {
+	if bs.writeSession == nil {
+		return bs.editOpts
+	}
+	return bs.writeSession.GetOptions()
+}
+
+// setForeignKeyChecksDisabled sets whether this head's writes check foreign keys, without creating its write session.
+func (bs *branchState) setForeignKeyChecksDisabled(disabled bool) {
+	bs.editOpts.ForeignKeyChecksDisabled = disabled
+	if bs.writeSession != nil {
+		opts := bs.writeSession.GetOptions()
+		opts.ForeignKeyChecksDisabled = disabled
+		bs.writeSession.SetOptions(opts)
+	}
 }
 
 func (bs *branchState) roots() doltdb.Roots {
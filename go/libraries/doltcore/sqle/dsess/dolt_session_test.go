@@ -16,15 +16,20 @@ package dsess
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	_ "github.com/dolthub/go-mysql-server/sql/variables"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
@@ -302,3 +307,66 @@ func (e emptyRevisionDatabaseProvider) CreateDatabase(ctx *sql.Context, dbName s
 func (e emptyRevisionDatabaseProvider) RevisionDbState(_ *sql.Context, revDB string) (InitialDbState, error) {
 	return InitialDbState{}, sql.ErrDatabaseNotFound.New(revDB)
 }
+
+func TestBranchStateEditOptsWithoutWriteSession(t *testing.T) {
+	bs := &branchState{}
+	bs.setForeignKeyChecksDisabled(true)
+	assert.Nil(t, bs.writeSession)
+	assert.True(t, bs.EditOpts().ForeignKeyChecksDisabled)
+
+	// heads without a working set never have a write session
+	assert.Nil(t, bs.WriteSession())
+}
+
+func TestBranchStateLoad(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+	ddb := dEnv.DoltDB
+
+	branch := ref.NewBranchRef(env.DefaultInitBranch)
+	wsRef, err := ref.WorkingSetRefForHead(branch)
+	require.NoError(t, err)
+	nomsRoot, err := ddb.NomsRoot(ctx)
+	require.NoError(t, err)
+	headCommit, err := ddb.ResolveCommitRefAtRoot(ctx, branch, nomsRoot)
+	require.NoError(t, err)
+
+	var loads int32
+	loader := NewWorkingSetLoader(ddb, wsRef, nomsRoot)
+	countingLoader := func(ctx context.Context) (*doltdb.WorkingSet, error) {
+		atomic.AddInt32(&loads, 1)
+		return loader(ctx)
+	}
+
+	bs := newEmptyDatabaseSessionState().NewEmptyBranchState(env.DefaultInitBranch)
+	bs.headCommit = headCommit
+	bs.loadWorkingSet = countingLoader
+
+	// nothing is loaded until the state is first accessed
+	assert.Nil(t, bs.workingSet)
+	assert.Nil(t, bs.headRoot)
+	assert.Zero(t, atomic.LoadInt32(&loads))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, bs.load(ctx))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+	require.NotNil(t, bs.WorkingSet())
+	assert.Equal(t, wsRef, bs.WorkingSet().Ref())
+	expectedHeadRoot, err := headCommit.GetRootValue(ctx)
+	require.NoError(t, err)
+	assert.True(t, rootsEqual(expectedHeadRoot, bs.roots().Head))
+
+	// the loader resolves the working set once, even for other states sharing it
+	other := newEmptyDatabaseSessionState().NewEmptyBranchState(env.DefaultInitBranch)
+	other.loadWorkingSet = loader
+	require.NoError(t, other.load(ctx))
+	assert.Same(t, bs.WorkingSet(), other.WorkingSet())
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// GlobalOperations tracks the long-running operations, like pushes and garbage collection, running in this process
+var GlobalOperations = NewOperations()
+
+// OperationStatus is the progress of a long-running operation run by a procedure, such as DOLT_PUSH(). An operation
+// goes through one or more phases, each of which processes some number of units, like bytes or tables.
+type OperationStatus struct {
+	ID           uint64
+	ConnectionID uint32
+	User         string
+	Database     string
+	Operation    string
+	Phase        string
+	// Unit is what Processed and Total count, such as "bytes" or "tables"
+	Unit      string
+	Processed uint64
+	// Total is the number of units the phase will process, or 0 if it isn't known
+	Total        uint64
+	Started      time.Time
+	PhaseStarted time.Time
+	Updated      time.Time
+}
+
+// ETA estimates how long the current phase will take to finish, from the rate at which it has processed units so far.
+// It returns false if there's no estimate, because the total isn't known or nothing has been processed yet.
+func (s OperationStatus) ETA(now time.Time) (time.Duration, bool) {
+	if s.Total == 0 || s.Processed == 0 {
+		return 0, false
+	}
+	if s.Processed >= s.Total {
+		return 0, true
+	}
+	elapsed := now.Sub(s.PhaseStarted)
+	return time.Duration(float64(elapsed) * float64(s.Total-s.Processed) / float64(s.Processed)), true
+}
+
+// Operations tracks the operations running in each connection. A connection runs at most one operation at a time.
+type Operations struct {
+	mu     *sync.Mutex
+	nextID uint64
+	ops    map[uint32]*OperationStatus
+}
+
+func NewOperations() *Operations {
+	return &Operations{
+		mu:  &sync.Mutex{},
+		ops: make(map[uint32]*OperationStatus),
+	}
+}
+
+// Operation reports the progress of one operation. Its methods are safe to call concurrently and on a nil Operation,
+// which reports nothing.
+type Operation struct {
+	ops    *Operations
+	connID uint32
+	id     uint64
+}
+
+// Start starts tracking the operation named |name|, like "push", run by the connection of |ctx| against the database
+// |dbName|. Finish must be called when it's done. An operation started while another is running in the same
+// connection, like a merge run by a pull, reports its progress as phases of the running operation, and finishing it
+// does nothing.
+func (o *Operations) Start(ctx *sql.Context, dbName, name string) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	connID := ctx.Session.ID()
+	if _, ok := o.ops[connID]; ok {
+		return &Operation{ops: o, connID: connID}
+	}
+
+	o.nextID++
+	now := time.Now()
+	o.ops[connID] = &OperationStatus{
+		ID:           o.nextID,
+		ConnectionID: connID,
+		User:         ctx.Session.Client().User,
+		Database:     dbName,
+		Operation:    name,
+		Started:      now,
+		PhaseStarted: now,
+		Updated:      now,
+	}
+	return &Operation{ops: o, connID: connID, id: o.nextID}
+}
+
+// Current returns the operation running in the connection of |ctx|, or nil if there isn't one.
+func (o *Operations) Current(ctx *sql.Context) *Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.ops[ctx.Session.ID()]; !ok {
+		return nil
+	}
+	return &Operation{ops: o, connID: ctx.Session.ID()}
+}
+
+// Snapshot returns a copy of the status of every running operation, ordered by when they started.
+func (o *Operations) Snapshot() []OperationStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	statuses := make([]OperationStatus, 0, len(o.ops))
+	for _, s := range o.ops {
+		statuses = append(statuses, *s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ID < statuses[j].ID
+	})
+	return statuses
+}
+
+func (op *Operation) update(f func(s *OperationStatus)) {
+	if op == nil {
+		return
+	}
+	op.ops.mu.Lock()
+	defer op.ops.mu.Unlock()
+	if s, ok := op.ops.ops[op.connID]; ok {
+		f(s)
+		s.Updated = time.Now()
+	}
+}
+
+// SetPhase starts a new phase of the operation, which will process |total| of |unit|, or an unknown number if |total|
+// is 0.
+func (op *Operation) SetPhase(phase, unit string, total uint64) {
+	op.update(func(s *OperationStatus) {
+		s.Phase, s.Unit, s.Processed, s.Total = phase, unit, 0, total
+		s.PhaseStarted = time.Now()
+	})
+}
+
+// SetProgress sets the number of units processed by the current phase and its total, or leaves the total as it is if
+// |total| is 0.
+func (op *Operation) SetProgress(processed, total uint64) {
+	op.update(func(s *OperationStatus) {
+		s.Processed = processed
+		if total > 0 {
+			s.Total = total
+		}
+	})
+}
+
+// AddProgress adds |n| to the number of units processed by the current phase.
+func (op *Operation) AddProgress(n uint64) {
+	op.update(func(s *OperationStatus) {
+		s.Processed += n
+	})
+}
+
+// Finish stops tracking the operation.
+func (op *Operation) Finish() {
+	if op == nil || op.id == 0 {
+		return
+	}
+	op.ops.mu.Lock()
+	defer op.ops.mu.Unlock()
+	if s, ok := op.ops.ops[op.connID]; ok && s.ID == op.id {
+		delete(op.ops.ops, op.connID)
+	}
+}
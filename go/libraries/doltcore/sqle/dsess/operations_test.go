@@ -0,0 +1,94 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperations(t *testing.T) {
+	ops := NewOperations()
+	newCtx := func(id uint32) *sql.Context {
+		sess := sql.NewBaseSessionWithClientServer("", sql.Client{User: "root", Address: "localhost"}, id)
+		return sql.NewContext(context.Background(), sql.WithSession(sess))
+	}
+	ctx1, ctx2 := newCtx(1), newCtx(2)
+
+	assert.Nil(t, ops.Current(ctx1))
+	var nilOp *Operation
+	nilOp.SetPhase("ignored", "bytes", 10)
+	nilOp.Finish()
+
+	push := ops.Start(ctx1, "mydb", "push")
+	push.SetPhase("uploading", "bytes", 100)
+	push.AddProgress(25)
+	gc := ops.Start(ctx2, "otherdb", "gc")
+	gc.SetPhase("collecting", "", 0)
+
+	statuses := ops.Snapshot()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "push", statuses[0].Operation)
+	assert.Equal(t, "mydb", statuses[0].Database)
+	assert.Equal(t, "root", statuses[0].User)
+	assert.Equal(t, uint32(1), statuses[0].ConnectionID)
+	assert.Equal(t, "uploading", statuses[0].Phase)
+	assert.Equal(t, uint64(25), statuses[0].Processed)
+	assert.Equal(t, uint64(100), statuses[0].Total)
+	assert.Equal(t, "gc", statuses[1].Operation)
+
+	// a nested operation reports phases of the running operation
+	merge := ops.Start(ctx1, "mydb", "merge")
+	merge.SetPhase("merging", "tables", 4)
+	merge.SetProgress(2, 0)
+	merge.Finish()
+	assert.NotNil(t, ops.Current(ctx1))
+	statuses = ops.Snapshot()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "push", statuses[0].Operation)
+	assert.Equal(t, "merging", statuses[0].Phase)
+	assert.Equal(t, uint64(2), statuses[0].Processed)
+	assert.Equal(t, uint64(4), statuses[0].Total)
+
+	push.Finish()
+	gc.Finish()
+	assert.Empty(t, ops.Snapshot())
+	assert.Nil(t, ops.Current(ctx1))
+}
+
+func TestOperationETA(t *testing.T) {
+	start := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+	s := OperationStatus{PhaseStarted: start, Total: 100}
+	_, ok := s.ETA(start.Add(time.Minute))
+	assert.False(t, ok)
+
+	s.Processed = 25
+	eta, ok := s.ETA(start.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Minute, eta)
+
+	s.Processed = 100
+	eta, ok = s.ETA(start.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), eta)
+
+	_, ok = OperationStatus{PhaseStarted: start, Processed: 10}.ETA(start.Add(time.Minute))
+	assert.False(t, ok)
+}
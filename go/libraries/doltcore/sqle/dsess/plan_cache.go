@@ -0,0 +1,328 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// planCacheMinExecutions is the number of times a connection runs a statement before its plan is cached.
+const planCacheMinExecutions = 2
+
+// PreparedStatements are the statements an engine has analyzed ahead of time, keyed by connection and query. The
+// engine analyzes the statements it finds here with the few rules that depend on their bindings, rather than analyzing
+// them from scratch. It's implemented by *gms.PreparedDataCache.
+type PreparedStatements interface {
+	GetCachedStmt(sessId uint32, query string) (sql.Node, bool)
+	UncacheStmt(sessId uint32, query string)
+}
+
+// PrepareFunc analyzes |query| ahead of time and adds it to the engine's PreparedStatements.
+type PrepareFunc func(ctx *sql.Context, query string) (sql.Node, error)
+
+// PlanCache caches the plans of the SELECT statements each connection runs repeatedly, so that they aren't analyzed
+// from scratch on every execution. Plans are kept with the statements an engine prepares for its clients, and each is
+// checked against the content hashes of the schemas of the tables it reads before it's used. A plan is dropped when
+// the connection changes its current database or any of the session variables that change how a statement is
+// analyzed, or when any of its tables has a different schema on the branch the statement runs against, whether
+// because the schema changed or because the connection switched branches.
+//
+// A statement is planned when the session is validated, before the engine analyzes it, so that the execution which
+// plans a statement runs from its plan rather than being analyzed a second time.
+type PlanCache struct {
+	size     int
+	prepared PreparedStatements
+	prepare  PrepareFunc
+}
+
+// NewPlanCache returns a PlanCache keeping up to |size| plans for each connection, or nil if |size| isn't positive.
+func NewPlanCache(size int, prepared PreparedStatements, prepare PrepareFunc) *PlanCache {
+	if size <= 0 {
+		return nil
+	}
+	return &PlanCache{size: size, prepared: prepared, prepare: prepare}
+}
+
+// planVariables are the session variables whose values change how a statement is parsed or analyzed. A plan is only
+// used while they have the values it was analyzed with.
+var planVariables = []string{
+	"sql_mode",
+	"character_set_client",
+	"character_set_connection",
+	"collation_connection",
+	"time_zone",
+	"foreign_key_checks",
+	DefaultAsOf,
+}
+
+// sessionPlans are the statements a session has planned with a PlanCache.
+type sessionPlans struct {
+	planned *lru.Cache[string, *plannedStatement]
+	// executions counts the executions of statements that haven't been planned yet
+	executions map[string]int
+}
+
+// plannedStatement is what a cached plan was analyzed against.
+type plannedStatement struct {
+	currentDb string
+	variables []interface{}
+	tables    []plannedTable
+}
+
+type plannedTable struct {
+	db         string
+	name       string
+	schemaHash hash.Hash
+}
+
+// isReadStatement returns whether |query| is a SELECT statement, which may be a parenthesized query or begin with
+// common table expressions. Only their plans are cached.
+func isReadStatement(query string) bool {
+	query = strings.ToLower(strings.TrimLeft(query, " \t\r\n("))
+	return strings.HasPrefix(query, "select") || strings.HasPrefix(query, "with")
+}
+
+// planVariableValues returns the values of the planVariables in the session
+func planVariableValues(ctx *sql.Context) ([]interface{}, error) {
+	vals := make([]interface{}, len(planVariables))
+	for i, name := range planVariables {
+		val, err := ctx.GetSessionVariable(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+// planStatement counts an execution of the current statement, caching its plan once it's been run
+// planCacheMinExecutions times.
+func (d *DoltSession) planStatement(ctx *sql.Context) error {
+	c := d.planCache
+	query := ctx.Query()
+	if c == nil || !isReadStatement(query) {
+		return nil
+	}
+
+	if d.plans == nil {
+		sessId := d.ID()
+		planned, err := lru.NewWithEvict[string, *plannedStatement](c.size, func(query string, _ *plannedStatement) {
+			c.prepared.UncacheStmt(sessId, query)
+		})
+		if err != nil {
+			return err
+		}
+		d.plans = &sessionPlans{planned: planned, executions: make(map[string]int)}
+	}
+
+	if d.plans.planned.Contains(query) {
+		return nil
+	}
+	if _, ok := c.prepared.GetCachedStmt(d.ID(), query); ok {
+		// prepared by the client
+		return nil
+	}
+
+	if len(d.plans.executions) >= 4*c.size {
+		d.plans.executions = make(map[string]int)
+	}
+	d.plans.executions[query]++
+	if d.plans.executions[query] < planCacheMinExecutions {
+		return nil
+	}
+	delete(d.plans.executions, query)
+
+	vals, err := planVariableValues(ctx)
+	if err != nil {
+		return err
+	}
+	node, err := c.prepare(ctx, query)
+	if err != nil {
+		c.prepared.UncacheStmt(d.ID(), query)
+		return err
+	}
+	tables, ok, err := d.plannedTables(ctx, node)
+	if err != nil || !ok {
+		c.prepared.UncacheStmt(d.ID(), query)
+		return err
+	}
+	d.plans.planned.Add(query, &plannedStatement{currentDb: ctx.GetCurrentDatabase(), variables: vals, tables: tables})
+	return nil
+}
+
+// plannedTables returns the tables read by |node| and the hashes of their schemas, and false if the plan of |node|
+// can't be cached. Only plans that read Dolt tables at the session's current root, and don't write anything, can be.
+func (d *DoltSession) plannedTables(ctx *sql.Context, node sql.Node) ([]plannedTable, bool, error) {
+	var tables []plannedTable
+	var err error
+	cacheable := true
+	var inspect func(n sql.Node) bool
+	inspect = func(n sql.Node) bool {
+		if n == nil || !cacheable || err != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *plan.ResolvedTable:
+			if n.AsOf != nil || n.Database == nil {
+				cacheable = false
+				return false
+			}
+			// the table is read from the root planIsCurrent checks the plan against, so plans that read tables
+			// that aren't in it, like system tables, aren't cached
+			var root *doltdb.RootValue
+			if root, err = d.statementRoot(ctx, n.Database.Name()); err != nil {
+				return false
+			}
+			var tbl *doltdb.Table
+			var ok bool
+			if root != nil {
+				if tbl, ok, err = root.GetTable(ctx, n.Name()); err != nil {
+					return false
+				}
+			}
+			if !ok {
+				cacheable = false
+				return false
+			}
+			var h hash.Hash
+			if h, err = tbl.GetSchemaHash(ctx); err != nil {
+				return false
+			}
+			tables = append(tables, plannedTable{db: n.Database.Name(), name: n.Name(), schemaHash: h})
+		case *plan.IndexedTableAccess:
+			return inspect(n.ResolvedTable)
+		case *plan.Into, sql.TableFunction, *plan.InsertInto, *plan.Update, *plan.DeleteFrom:
+			cacheable = false
+		}
+		if ex, ok := n.(sql.Expressioner); ok {
+			for _, e := range ex.Expressions() {
+				sql.Inspect(e, func(e sql.Expression) bool {
+					switch e := e.(type) {
+					case *plan.Subquery:
+						transform.Inspect(e.Query, inspect)
+					case *expression.BindVar:
+						cacheable = false
+					}
+					return cacheable && err == nil
+				})
+			}
+		}
+		return cacheable && err == nil
+	}
+	transform.Inspect(node, inspect)
+	if err != nil {
+		return nil, false, err
+	}
+	return tables, cacheable && len(tables) > 0, nil
+}
+
+// validatePlan drops the cached plan of the current statement if it was analyzed against a different database or
+// branch, or different table schemas, than the statement will run against.
+func (d *DoltSession) validatePlan(ctx *sql.Context) {
+	if d.plans == nil {
+		return
+	}
+	query := ctx.Query()
+	ps, ok := d.plans.planned.Peek(query)
+	if !ok {
+		return
+	}
+
+	current, err := d.planIsCurrent(ctx, ps)
+	if err != nil || !current {
+		d.plans.planned.Remove(query)
+	}
+}
+
+func (d *DoltSession) planIsCurrent(ctx *sql.Context, ps *plannedStatement) (bool, error) {
+	if !strings.EqualFold(ctx.GetCurrentDatabase(), ps.currentDb) {
+		return false, nil
+	}
+	vals, err := planVariableValues(ctx)
+	if err != nil {
+		return false, err
+	}
+	for i := range vals {
+		if vals[i] != ps.variables[i] {
+			return false, nil
+		}
+	}
+	for _, t := range ps.tables {
+		root, err := d.statementRoot(ctx, t.db)
+		if err != nil || root == nil {
+			return false, err
+		}
+		tbl, ok, err := root.GetTable(ctx, t.name)
+		if err != nil || !ok {
+			return false, err
+		}
+		h, err := tbl.GetSchemaHash(ctx)
+		if err != nil {
+			return false, err
+		}
+		if h != t.schemaHash {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// statementRoot returns the working root the next statement will read for the database named |dbName|, or nil if the
+// session hasn't loaded the database. It's called before the statement starts its transaction, so the working root is
+// read from the database unless the statement continues a transaction or the session's roots are pinned.
+func (d *DoltSession) statementRoot(ctx *sql.Context, dbName string) (*doltdb.RootValue, error) {
+	baseName, rev := SplitRevisionDbName(strings.ToLower(dbName))
+
+	d.mu.Lock()
+	dbState, ok := d.dbStates[baseName]
+	d.mu.Unlock()
+	if !ok || dbState.Err != nil {
+		return nil, nil
+	}
+	if rev == "" {
+		rev = dbState.currRevSpec
+	}
+	bs, ok := dbState.heads[strings.ToLower(rev)]
+	if !ok {
+		return nil, nil
+	}
+	if err := bs.load(ctx); err != nil {
+		return nil, err
+	}
+	if bs.workingSet == nil {
+		return nil, nil
+	}
+
+	if ctx.GetTransaction() != nil || d.snapshotRoots != nil || d.bulkLoadTx != nil {
+		return bs.workingSet.WorkingRoot(), nil
+	}
+	ws, err := bs.dbData.Ddb.ResolveWorkingSet(ctx, bs.workingSet.Ref())
+	if errors.Is(err, doltdb.ErrWorkingSetNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return ws.WorkingRoot(), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+)
+
+// sessionClosingProcessList wraps a sql.ProcessList to close the DoltSession of each connection when it's removed
+// from the process list, which the server does when the connection is closed.
+type sessionClosingProcessList struct {
+	sql.ProcessList
+	mu       *sync.Mutex
+	sessions map[uint32]*DoltSession
+}
+
+var _ sql.ProcessList = (*sessionClosingProcessList)(nil)
+
+// NewSessionClosingProcessList returns a sql.ProcessList that closes the DoltSession of each connection removed from
+// |pl|
+func NewSessionClosingProcessList(pl sql.ProcessList) sql.ProcessList {
+	return &sessionClosingProcessList{
+		ProcessList: pl,
+		mu:          &sync.Mutex{},
+		sessions:    make(map[uint32]*DoltSession),
+	}
+}
+
+// ConnectionReady implements sql.ProcessList
+func (pl *sessionClosingProcessList) ConnectionReady(sess sql.Session) {
+	pl.ProcessList.ConnectionReady(sess)
+	if dSess, ok := sess.(*DoltSession); ok {
+		pl.mu.Lock()
+		defer pl.mu.Unlock()
+		pl.sessions[sess.ID()] = dSess
+	}
+}
+
+// RemoveConnection implements sql.ProcessList
+func (pl *sessionClosingProcessList) RemoveConnection(connID uint32) {
+	pl.ProcessList.RemoveConnection(connID)
+
+	pl.mu.Lock()
+	sess, ok := pl.sessions[connID]
+	delete(pl.sessions, connID)
+	pl.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sess))
+	if err := sess.Close(ctx); err != nil {
+		logrus.WithField(sql.ConnectionIdLogField, connID).Errorf("error closing session: %s", err)
+	}
+}
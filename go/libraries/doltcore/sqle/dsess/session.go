@@ -33,7 +33,6 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
-	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -64,6 +63,31 @@ type DoltSession struct {
 	// If non-nil, this will be returned from ValidateSession.
 	// Used by sqle/cluster to put a session into a terminal err state.
 	validateErr error
+
+	// snapshotRoots holds the noms root of each database pinned by @@dolt_snapshot_session, keyed by lower-cased base
+	// database name. Nil when the session is not in snapshot mode.
+	snapshotRoots map[string]hash.Hash
+
+	// bulkLoad is true while @@dolt_bulk_load is set. bulkLoadTx is the transaction whose commit it deferred, which the
+	// statements after it continue until the bulk load is flushed, or nil if there isn't one.
+	bulkLoad   bool
+	bulkLoadTx sql.Transaction
+
+	// planCache caches the plans of the statements this session runs repeatedly, or is nil if they aren't cached.
+	// plans are the statements whose plans this session has cached with it, or nil if it hasn't cached any.
+	planCache *PlanCache
+	plans     *sessionPlans
+
+	// heldMetadataLocks are the metadata lock managers this session holds locks in during the current transaction
+	heldMetadataLocks map[*globalstate.MetadataLocks]struct{}
+
+	// stmtRowsRead and stmtRowsWritten count the rows read and written by the statement currently running in this
+	// session, for @@dolt_statement_stats. They are updated atomically, since row iterators may run concurrently.
+	stmtRowsRead    int64
+	stmtRowsWritten int64
+
+	// storageQuotas are the storage quotas enforced when this session commits a transaction, or nil if there are none.
+	storageQuotas *StorageQuotas
 }
 
 var _ sql.Session = (*DoltSession)(nil)
@@ -121,6 +145,21 @@ func (d *DoltSession) Provider() DoltDatabaseProvider {
 	return d.provider
 }
 
+// StorageQuotas returns the storage quotas enforced when this session commits a transaction, or nil if there are none.
+func (d *DoltSession) StorageQuotas() *StorageQuotas {
+	return d.storageQuotas
+}
+
+// SetStorageQuotas sets the storage quotas enforced when this session commits a transaction.
+func (d *DoltSession) SetStorageQuotas(quotas *StorageQuotas) {
+	d.storageQuotas = quotas
+}
+
+// SetPlanCache sets the cache of the plans of the statements this session runs repeatedly.
+func (d *DoltSession) SetPlanCache(c *PlanCache) {
+	d.planCache = c
+}
+
 // DSessFromSess retrieves a dolt session from a standard sql.Session
 func DSessFromSess(sess sql.Session) *DoltSession {
 	return sess.(*DoltSession)
@@ -129,6 +168,19 @@ func DSessFromSess(sess sql.Session) *DoltSession {
 // lookupDbState is the private version of LookupDbState, returning a struct that has more information available than
 // the interface returned by the public method.
 func (d *DoltSession) lookupDbState(ctx *sql.Context, dbName string) (*branchState, bool, error) {
+	branchState, ok, err := d.lookupUnloadedDbState(ctx, dbName)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	if err = branchState.load(ctx); err != nil {
+		return nil, false, err
+	}
+	return branchState, true, nil
+}
+
+// lookupUnloadedDbState is like lookupDbState, but doesn't load the working set and head root of the state returned,
+// for callers which don't read them.
+func (d *DoltSession) lookupUnloadedDbState(ctx *sql.Context, dbName string) (*branchState, bool, error) {
 	dbName = strings.ToLower(dbName)
 
 	var baseName, rev string
@@ -239,7 +291,16 @@ func (d *DoltSession) SetValidateErr(err error) {
 // If there is no sessionState or its current working set not defined, then no need for validation,
 // so no error is returned.
 func (d *DoltSession) ValidateSession(ctx *sql.Context) error {
-	return d.validateErr
+	if d.validateErr != nil {
+		return d.validateErr
+	}
+	// The engine looks up the cached plan of a statement after this, so drop it here if it's stale, and plan the
+	// statement here if it's run often enough
+	d.validatePlan(ctx)
+	if err := d.planStatement(ctx); err != nil {
+		ctx.GetLogger().Warnf("unable to cache plan of query: %s", err.Error())
+	}
+	return nil
 }
 
 // StartTransaction refreshes the state of this session and starts a new transaction.
@@ -250,8 +311,16 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 		return DisabledTransaction{}, nil
 	}
 
+	// Statements run during a bulk load continue the transaction whose commit was deferred, rather than refreshing the
+	// session state from the database
+	if tx := d.deferredBulkLoadTx(); tx != nil {
+		ctx.SetTransaction(tx)
+		return tx, nil
+	}
+
 	// New transaction, clear all session state
 	d.clear()
+	d.releaseMetadataLocks(ctx)
 
 	// Take a snapshot of the current noms root for every database under management
 	doltDatabases := d.provider.DoltDatabases()
@@ -286,11 +355,21 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 		}
 	}
 
+	// Snapshot sessions only ever read the roots they pinned, so their transactions can't be allowed to write
+	snapshot := d.snapshotActive()
+	if snapshot {
+		tCharacteristic = sql.ReadOnly
+	}
+
 	tx, err := NewDoltTransaction(ctx, txDbs, tCharacteristic)
 	if err != nil {
 		return nil, err
 	}
 
+	if snapshot {
+		d.pinTransactionRoots(tx)
+	}
+
 	// The engine sets the transaction after this call as well, but since we begin accessing data below, we need to set
 	// this now to avoid seeding the session state with stale data in some cases. The duplication is harmless since the
 	// code below cannot error. Additionally we clear any state that was cached by replication updates in the block above.
@@ -302,7 +381,7 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 		// faulty settings can make it impossible to load particular DB branch states, so we ignore any errors in this
 		// loop and just decline to set the session vars. Throwing an error on transaction start in these cases makes it
 		// impossible for the user to correct any problems.
-		bs, ok, err := d.lookupDbState(ctx, db.Name())
+		bs, ok, err := d.lookupUnloadedDbState(ctx, db.Name())
 		if err != nil || !ok {
 			continue
 		}
@@ -313,6 +392,75 @@ func (d *DoltSession) StartTransaction(ctx *sql.Context, tCharacteristic sql.Tra
 	return tx, nil
 }
 
+// snapshotActive returns whether this session is in snapshot mode, i.e. @@dolt_snapshot_session is set
+func (d *DoltSession) snapshotActive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snapshotRoots != nil
+}
+
+// pinTransactionRoots replaces the start points of the transaction given with the roots pinned by this snapshot
+// session. Databases that were not yet pinned (e.g. because they were created after the snapshot began) are pinned at
+// the root the transaction started with.
+func (d *DoltSession) pinTransactionRoots(tx *DoltTransaction) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for name, start := range tx.dbStartPoints {
+		if pinned, ok := d.snapshotRoots[name]; ok {
+			start.rootHash = pinned
+			tx.dbStartPoints[name] = start
+		} else {
+			d.snapshotRoots[name] = start.rootHash
+		}
+	}
+}
+
+// beginSnapshot pins every database under management to the root it has in the current transaction, or to its
+// current root if there is no transaction. All transactions started until the snapshot ends read from these roots.
+func (d *DoltSession) beginSnapshot(ctx *sql.Context) error {
+	roots := make(map[string]hash.Hash)
+	tx, usingDoltTransaction := ctx.GetTransaction().(*DoltTransaction)
+	for _, db := range d.provider.DoltDatabases() {
+		ddb := db.DbData().Ddb
+		if ddb == nil {
+			continue
+		}
+
+		baseName, _ := SplitRevisionDbName(db.Name())
+		baseName = strings.ToLower(baseName)
+		if usingDoltTransaction {
+			if nomsRoot, ok := tx.GetInitialRoot(baseName); ok {
+				roots[baseName] = nomsRoot
+				continue
+			}
+		}
+
+		nomsRoot, err := ddb.NomsRoot(ctx)
+		if err != nil {
+			return err
+		}
+		roots[baseName] = nomsRoot
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snapshotRoots = roots
+	return nil
+}
+
+// endSnapshot returns this session to normal operation, with each new transaction reading the latest roots
+func (d *DoltSession) endSnapshot() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.snapshotRoots = nil
+}
+
+// SnapshotActive returns whether this session is pinned to a snapshot by @@dolt_snapshot_session
+func (d *DoltSession) SnapshotActive() bool {
+	return d.snapshotActive()
+}
+
 // clear clears all DB state for this session
 func (d *DoltSession) clear() {
 	d.mu.Lock()
@@ -348,9 +496,13 @@ func (d *DoltSession) newWorkingSetForHead(ctx *sql.Context, wsRef ref.WorkingSe
 }
 
 // CommitTransaction commits the in-progress transaction. Depending on session settings, this may write only a new
-// working set, or may additionally create a new dolt commit for the current HEAD. If more than one branch head has
-// changes, the transaction is rejected.
+// working set, or may additionally create a new dolt commit for the current HEAD. If more than one branch head, in one
+// or more databases, has changes, their working sets are all committed or none are, and a dolt commit isn't allowed.
 func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) (err error) {
+	if d.deferBulkLoadCommit(ctx, tx) {
+		return nil
+	}
+
 	// Any non-error path must set the ctx's transaction to nil even if no work was done, because the engine only clears
 	// out transaction state in some cases. Changes to only branch heads (creating a new branch, reset, etc.) have no
 	// changes to commit visible to the transaction logic, but they still need a new transaction on the next statement.
@@ -358,7 +510,9 @@ func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) (e
 	defer func() {
 		if err == nil {
 			ctx.SetTransaction(nil)
+			d.clearBulkLoadTx()
 		}
+		d.releaseMetadataLocks(ctx)
 	}()
 
 	if TransactionsDisabled(ctx) {
@@ -370,10 +524,6 @@ func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) (e
 		return nil
 	}
 
-	if len(dirties) > 1 {
-		return ErrDirtyWorkingSets
-	}
-
 	performDoltCommitVar, err := d.Session.GetSessionVariable(ctx, DoltCommitOnTransactionCommit)
 	if err != nil {
 		return err
@@ -384,6 +534,15 @@ func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) (e
 		return fmt.Errorf(fmt.Sprintf("Unexpected type for var %s: %T", DoltCommitOnTransactionCommit, performDoltCommitVar))
 	}
 
+	if len(dirties) > 1 {
+		// a Dolt commit is made on a single branch, but working sets on any number of branches and databases can be
+		// committed together
+		if peformDoltCommitInt == 1 {
+			return ErrDirtyWorkingSets
+		}
+		return d.commitWorkingSets(ctx, dirties, tx)
+	}
+
 	dirtyBranchState := dirties[0]
 	if peformDoltCommitInt == 1 {
 		// if the dirty working set doesn't belong to the currently checked out branch, that's an error
@@ -487,6 +646,29 @@ func (d *DoltSession) commitWorkingSet(ctx *sql.Context, branchState *branchStat
 	return err
 }
 
+// commitWorkingSets commits the working sets of the branch states given atomically, without creating any dolt commits.
+func (d *DoltSession) commitWorkingSets(ctx *sql.Context, branchStates []*branchState, tx sql.Transaction) error {
+	dtx, ok := tx.(*DoltTransaction)
+	if !ok {
+		return fmt.Errorf("expected a DoltTransaction")
+	}
+
+	dbNames := make([]string, len(branchStates))
+	workingSets := make([]*doltdb.WorkingSet, len(branchStates))
+	for i, branchState := range branchStates {
+		dbNames[i] = branchState.dbState.dbName
+		workingSets[i] = branchState.WorkingSet()
+	}
+	if err := dtx.CommitAll(ctx, dbNames, workingSets); err != nil {
+		return err
+	}
+
+	// See comment in |commitBranchState|
+	ctx.SetTransaction(nil)
+	d.clearBulkLoadTx()
+	return nil
+}
+
 // DoltCommit commits the working set and a new dolt commit with the properties given.
 // Clients should typically use CommitTransaction, which performs additional checks, instead of this method.
 func (d *DoltSession) DoltCommit(
@@ -537,6 +719,7 @@ func (d *DoltSession) commitBranchState(
 	// COMMIT statements. Any other statements that commit a transaction, including stored procedures, needs to do this
 	// themselves.
 	ctx.SetTransaction(nil)
+	d.clearBulkLoadTx()
 	return newCommit, nil
 }
 
@@ -638,9 +821,34 @@ func (d *DoltSession) newPendingCommit(ctx *sql.Context, branchState *branchStat
 func (d *DoltSession) Rollback(ctx *sql.Context, tx sql.Transaction) error {
 	// Nothing to do here, we just throw away all our work and let a new transaction begin next statement
 	d.clear()
+	d.clearBulkLoadTx()
+	d.releaseMetadataLocks(ctx)
 	return nil
 }
 
+// TrackMetadataLocks records that this session holds locks in the metadata lock manager given, to be released when
+// the current transaction ends
+func (d *DoltSession) TrackMetadataLocks(locks *globalstate.MetadataLocks) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.heldMetadataLocks == nil {
+		d.heldMetadataLocks = make(map[*globalstate.MetadataLocks]struct{})
+	}
+	d.heldMetadataLocks[locks] = struct{}{}
+}
+
+// releaseMetadataLocks releases all metadata locks held by this session
+func (d *DoltSession) releaseMetadataLocks(ctx *sql.Context) {
+	d.mu.Lock()
+	held := d.heldMetadataLocks
+	d.heldMetadataLocks = nil
+	d.mu.Unlock()
+
+	for locks := range held {
+		locks.ReleaseAll(d)
+	}
+}
+
 // CreateSavepoint creates a new savepoint for this transaction with the name given. A previously created savepoint
 // with the same name will be overwritten.
 func (d *DoltSession) CreateSavepoint(ctx *sql.Context, tx sql.Transaction, savepointName string) error {
@@ -719,7 +927,7 @@ func (d *DoltSession) ReleaseSavepoint(ctx *sql.Context, tx sql.Transaction, sav
 
 // GetDoltDB returns the *DoltDB for a given database by name
 func (d *DoltSession) GetDoltDB(ctx *sql.Context, dbName string) (*doltdb.DoltDB, bool) {
-	branchState, ok, err := d.lookupDbState(ctx, dbName)
+	branchState, ok, err := d.lookupUnloadedDbState(ctx, dbName)
 	if err != nil {
 		return nil, false
 	}
@@ -731,7 +939,7 @@ func (d *DoltSession) GetDoltDB(ctx *sql.Context, dbName string) (*doltdb.DoltDB
 }
 
 func (d *DoltSession) GetDbData(ctx *sql.Context, dbName string) (env.DbData, bool) {
-	branchState, ok, err := d.lookupDbState(ctx, dbName)
+	branchState, ok, err := d.lookupUnloadedDbState(ctx, dbName)
 	if err != nil {
 		return env.DbData{}, false
 	}
@@ -763,8 +971,10 @@ func (d *DoltSession) ResolveRootForRef(ctx *sql.Context, dbName, refStr string)
 	if refStr == doltdb.Working || refStr == doltdb.Staged {
 		// TODO: get from working set / staged update time
 		now := types.Timestamp(time.Now())
-		// TODO: no current database
-		roots, _ := d.GetRoots(ctx, ctx.GetCurrentDatabase())
+		roots, ok := d.GetRoots(ctx, dbName)
+		if !ok {
+			return nil, nil, "", sql.ErrDatabaseNotFound.New(dbName)
+		}
 		if refStr == doltdb.Working {
 			return roots.Working, &now, refStr, nil
 		} else if refStr == doltdb.Staged {
@@ -885,14 +1095,21 @@ func (d *DoltSession) SetWorkingSet(ctx *sql.Context, dbName string, ws *doltdb.
 	}
 	branchState.workingSet = ws
 
-	err = d.setDbSessionVars(ctx, branchState, true)
-	if err != nil {
-		return err
+	// During a bulk load the session vars for the working set are updated when the next transaction starts, rather
+	// than every time a statement writes to it
+	if !d.BulkLoadActive() {
+		err = d.setDbSessionVars(ctx, branchState, true)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = branchState.WriteSession().SetWorkingSet(ctx, ws)
-	if err != nil {
-		return err
+	// A head that hasn't been written to yet creates its write session from the working set when it's first written to
+	if branchState.writeSession != nil {
+		err = branchState.writeSession.SetWorkingSet(ctx, ws)
+		if err != nil {
+			return err
+		}
 	}
 
 	branchState.dirty = true
@@ -951,7 +1168,7 @@ func (d *DoltSession) UseDatabase(ctx *sql.Context, db sql.Database) error {
 		return nil
 	}
 
-	branchState, ok, err := d.lookupDbState(ctx, sdb.RevisionQualifiedName())
+	branchState, ok, err := d.lookupUnloadedDbState(ctx, sdb.RevisionQualifiedName())
 	if err != nil {
 		return err
 	}
@@ -992,7 +1209,7 @@ func (d *DoltSession) WorkingSet(ctx *sql.Context, dbName string) (*doltdb.Worki
 
 // GetHeadCommit returns the parent commit of the current session.
 func (d *DoltSession) GetHeadCommit(ctx *sql.Context, dbName string) (*doltdb.Commit, error) {
-	branchState, ok, err := d.lookupDbState(ctx, dbName)
+	branchState, ok, err := d.lookupUnloadedDbState(ctx, dbName)
 	if err != nil {
 		return nil, err
 	}
@@ -1021,6 +1238,14 @@ func (d *DoltSession) SetSessionVariable(ctx *sql.Context, key string, value int
 		return d.setForeignKeyChecksSessionVar(ctx, key, value)
 	}
 
+	if strings.ToLower(key) == SnapshotSession {
+		return d.setSnapshotSessionVar(ctx, key, value)
+	}
+
+	if strings.ToLower(key) == BulkLoad {
+		return d.setBulkLoadSessionVar(ctx, key, value)
+	}
+
 	return d.Session.SetSessionVariable(ctx, key, value)
 }
 
@@ -1057,17 +1282,13 @@ func (d *DoltSession) setForeignKeyChecksSessionVar(ctx *sql.Context, key string
 	if intVal == 0 {
 		for _, dbState := range d.dbStates {
 			for _, branchState := range dbState.heads {
-				opts := branchState.WriteSession().GetOptions()
-				opts.ForeignKeyChecksDisabled = true
-				branchState.WriteSession().SetOptions(opts)
+				branchState.setForeignKeyChecksDisabled(true)
 			}
 		}
 	} else if intVal == 1 {
 		for _, dbState := range d.dbStates {
 			for _, branchState := range dbState.heads {
-				opts := branchState.WriteSession().GetOptions()
-				opts.ForeignKeyChecksDisabled = false
-				branchState.WriteSession().SetOptions(opts)
+				branchState.setForeignKeyChecksDisabled(false)
 			}
 		}
 	} else {
@@ -1077,6 +1298,129 @@ func (d *DoltSession) setForeignKeyChecksSessionVar(ctx *sql.Context, key string
 	return d.Session.SetSessionVariable(ctx, key, value)
 }
 
+func (d *DoltSession) setSnapshotSessionVar(ctx *sql.Context, key string, value interface{}) error {
+	convertedVal, _, err := sqltypes.Int64.Convert(value)
+	if err != nil {
+		return err
+	}
+	intVal := int64(0)
+	if convertedVal != nil {
+		intVal = convertedVal.(int64)
+	}
+
+	if intVal == 0 {
+		d.endSnapshot()
+	} else if intVal == 1 {
+		// Setting the variable again while a snapshot is active keeps the original snapshot
+		if !d.snapshotActive() {
+			if err := d.beginSnapshot(ctx); err != nil {
+				return err
+			}
+		}
+	} else {
+		return fmt.Errorf("variable '%s' can't be set to the value of '%d'", SnapshotSession, intVal)
+	}
+
+	return d.Session.SetSessionVariable(ctx, key, value)
+}
+
+func (d *DoltSession) setBulkLoadSessionVar(ctx *sql.Context, key string, value interface{}) error {
+	convertedVal, _, err := sqltypes.Int64.Convert(value)
+	if err != nil {
+		return err
+	}
+	intVal := int64(0)
+	if convertedVal != nil {
+		intVal = convertedVal.(int64)
+	}
+
+	if intVal == 0 {
+		// The bulk load ends even if its flush fails, so the variable is unset either way
+		flushErr := d.flushBulkLoad(ctx)
+		if err := d.Session.SetSessionVariable(ctx, key, value); err != nil {
+			return err
+		}
+		return flushErr
+	} else if intVal == 1 {
+		d.mu.Lock()
+		d.bulkLoad = true
+		d.mu.Unlock()
+	} else {
+		return fmt.Errorf("variable '%s' can't be set to the value of '%d'", BulkLoad, intVal)
+	}
+
+	return d.Session.SetSessionVariable(ctx, key, value)
+}
+
+// BulkLoadActive returns whether this session is bulk loading, i.e. @@dolt_bulk_load is set
+func (d *DoltSession) BulkLoadActive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.bulkLoad
+}
+
+// deferBulkLoadCommit returns whether the commit of |tx| is deferred because this session is bulk loading, in which
+// case the statements after it continue |tx| until the bulk load is flushed. Only the implicit commit at the end of
+// each statement with @@autocommit is deferred, a COMMIT that ends a transaction begun with START TRANSACTION commits
+// as usual.
+func (d *DoltSession) deferBulkLoadCommit(ctx *sql.Context, tx sql.Transaction) bool {
+	if ctx.GetIgnoreAutoCommit() {
+		return false
+	}
+	if _, ok := tx.(*DoltTransaction); !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.bulkLoad {
+		return false
+	}
+	d.bulkLoadTx = tx
+	return true
+}
+
+// deferredBulkLoadTx returns the transaction whose commit was deferred by a bulk load, or nil if there isn't one
+func (d *DoltSession) deferredBulkLoadTx() sql.Transaction {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.bulkLoadTx
+}
+
+func (d *DoltSession) clearBulkLoadTx() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.bulkLoadTx = nil
+}
+
+// flushBulkLoad ends the bulk load of this session, committing the transaction whose commit it deferred, if any. If
+// that commit fails, the transaction is rolled back, so that the statements after it start a new one.
+func (d *DoltSession) flushBulkLoad(ctx *sql.Context) error {
+	d.mu.Lock()
+	d.bulkLoad = false
+	tx := d.bulkLoadTx
+	d.bulkLoadTx = nil
+	d.mu.Unlock()
+
+	if tx == nil {
+		return nil
+	}
+	if err := d.CommitTransaction(ctx, tx); err != nil {
+		ctx.SetTransaction(nil)
+		if rbErr := d.Rollback(ctx, tx); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+	return nil
+}
+
+// Close ends this session. A bulk load in progress is flushed, rather than losing the statements whose commit it
+// deferred with the session.
+func (d *DoltSession) Close(ctx *sql.Context) error {
+	return d.flushBulkLoad(ctx)
+}
+
 // addDB adds the database given to this session. This establishes a starting root value for this session, as well as
 // other state tracking metadata.
 func (d *DoltSession) addDB(ctx *sql.Context, db SqlDatabase) error {
@@ -1169,8 +1513,9 @@ func (d *DoltSession) addDB(ctx *sql.Context, db SqlDatabase) error {
 
 	if dbState.Err != nil {
 		sessionState.Err = dbState.Err
-	} else if dbState.WorkingSet != nil {
+	} else if dbState.WorkingSet != nil || dbState.LoadWorkingSet != nil {
 		branchState.workingSet = dbState.WorkingSet
+		branchState.loadWorkingSet = dbState.LoadWorkingSet
 
 		// TODO: this is pretty clunky, there is a silly dependency between InitialDbState and globalstate.StateProvider
 		//  that's hard to express with the current types
@@ -1184,17 +1529,11 @@ func (d *DoltSession) addDB(ctx *sql.Context, db SqlDatabase) error {
 		if err != nil {
 			return err
 		}
-		branchState.writeSession = writer.NewWriteSession(nbf, branchState.WorkingSet(), tracker, editOpts)
+		branchState.nbf, branchState.aiTracker, branchState.editOpts = nbf, tracker, editOpts
 	}
 
-	// WorkingSet is nil in the case of a read only, detached head DB
-	if dbState.HeadCommit != nil {
-		headRoot, err := dbState.HeadCommit.GetRootValue(ctx)
-		if err != nil {
-			return err
-		}
-		branchState.headRoot = headRoot
-	} else if dbState.HeadRoot != nil {
+	// WorkingSet is nil in the case of a read only, detached head DB. The root of a head commit is loaded on first access.
+	if dbState.HeadCommit == nil {
 		branchState.headRoot = dbState.HeadRoot
 	}
 
@@ -1237,7 +1576,7 @@ func (d *DoltSession) GetAllTemporaryTables(ctx *sql.Context, db string) ([]sql.
 
 // CWBHeadRef returns the branch ref for this session HEAD for the database named
 func (d *DoltSession) CWBHeadRef(ctx *sql.Context, dbName string) (ref.DoltRef, error) {
-	branchState, ok, err := d.lookupDbState(ctx, dbName)
+	branchState, ok, err := d.lookupUnloadedDbState(ctx, dbName)
 	if err != nil {
 		return nil, err
 	}
@@ -1291,6 +1630,9 @@ func (d *DoltSession) setDbSessionVars(ctx *sql.Context, state *branchState, for
 		return nil
 	}
 
+	if err := state.load(ctx); err != nil {
+		return err
+	}
 	baseName := state.dbState.dbName
 
 	// Different DBs have different requirements for what state is set, so we are maximally permissive on what's expected
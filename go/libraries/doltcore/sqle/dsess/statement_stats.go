@@ -0,0 +1,264 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// GlobalStatementStats holds the statement stats of every database served by this process
+var GlobalStatementStats = NewStatementStats()
+
+// StatementStat is the resource usage of all executions of statements with the same digest against a database
+type StatementStat struct {
+	Database    string
+	Statement   string
+	Count       uint64
+	TotalTime   time.Duration
+	MaxTime     time.Duration
+	RowsRead    uint64
+	RowsWritten uint64
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+type statementStatKey struct {
+	db     string
+	digest string
+}
+
+// StatementStats aggregates the resource usage of statements by database and statement digest. A statement's digest
+// is its text with literals replaced by placeholders, so executions that differ only in their values are counted
+// together.
+type StatementStats struct {
+	mu    *sync.Mutex
+	stats map[statementStatKey]*StatementStat
+}
+
+func NewStatementStats() *StatementStats {
+	return &StatementStats{
+		mu:    &sync.Mutex{},
+		stats: make(map[statementStatKey]*StatementStat),
+	}
+}
+
+// Record adds one execution of the statement with the digest given to the stats of the database given. If the
+// statement hasn't been seen before and |maxStatements| are already tracked, the least recently seen statement is
+// evicted to make room for it.
+func (s *StatementStats) Record(db, digest string, start time.Time, elapsed time.Duration, rowsRead, rowsWritten uint64, maxStatements int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := statementStatKey{db: strings.ToLower(db), digest: digest}
+	stat, ok := s.stats[key]
+	if !ok {
+		if maxStatements <= 0 {
+			return
+		}
+		for len(s.stats) >= maxStatements {
+			s.evictLeastRecentlySeen()
+		}
+		stat = &StatementStat{Database: key.db, Statement: digest, FirstSeen: start}
+		s.stats[key] = stat
+	}
+
+	stat.Count++
+	stat.TotalTime += elapsed
+	if elapsed > stat.MaxTime {
+		stat.MaxTime = elapsed
+	}
+	stat.RowsRead += rowsRead
+	stat.RowsWritten += rowsWritten
+	stat.LastSeen = start
+}
+
+func (s *StatementStats) evictLeastRecentlySeen() {
+	var oldest statementStatKey
+	var oldestSeen time.Time
+	first := true
+	for key, stat := range s.stats {
+		if first || stat.LastSeen.Before(oldestSeen) {
+			oldest, oldestSeen, first = key, stat.LastSeen, false
+		}
+	}
+	delete(s.stats, oldest)
+}
+
+// Snapshot returns a copy of the stats of the database given, ordered by total time spent, descending
+func (s *StatementStats) Snapshot(db string) []StatementStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db = strings.ToLower(db)
+	var stats []StatementStat
+	for key, stat := range s.stats {
+		if key.db == db {
+			stats = append(stats, *stat)
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalTime != stats[j].TotalTime {
+			return stats[i].TotalTime > stats[j].TotalTime
+		}
+		return stats[i].Statement < stats[j].Statement
+	})
+	return stats
+}
+
+// Reset clears the stats of the database given, or of all databases if |db| is empty
+func (s *StatementStats) Reset(db string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db = strings.ToLower(db)
+	for key := range s.stats {
+		if db == "" || key.db == db {
+			delete(s.stats, key)
+		}
+	}
+}
+
+var (
+	placeholderRegex = regexp.MustCompile(`::?stmtstat\d+`)
+	whitespaceRegex  = regexp.MustCompile(`\s+`)
+)
+
+// StatementDigest returns the digest of the query given: its text with all literals replaced by ?, and multi-row
+// VALUES lists collapsed to a single row. Queries that can't be parsed are used as is, with whitespace collapsed.
+func StatementDigest(query string) string {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return strings.TrimSpace(whitespaceRegex.ReplaceAllString(query, " "))
+	}
+
+	if ins, ok := stmt.(*sqlparser.Insert); ok {
+		if rows, ok := ins.Rows.(sqlparser.Values); ok && len(rows) > 1 {
+			ins.Rows = rows[:1]
+		}
+	}
+
+	sqlparser.Normalize(stmt, map[string]*querypb.BindVariable{}, "stmtstat")
+	return placeholderRegex.ReplaceAllString(sqlparser.String(stmt), "?")
+}
+
+// IsStatementStatsEnabled returns whether @@dolt_statement_stats is enabled
+func IsStatementStatsEnabled() bool {
+	_, val, ok := sql.SystemVariables.GetGlobal(StatementStatsEnabled)
+	return ok && val == SysVarTrue
+}
+
+func maxTrackedStatements() int {
+	_, val, ok := sql.SystemVariables.GetGlobal(StatementStatsMaxStatements)
+	if !ok {
+		return 0
+	}
+	n, _ := val.(int64)
+	return int(n)
+}
+
+// RecordRowsRead adds to the count of rows read by the statement currently running in this session
+func (d *DoltSession) RecordRowsRead(n int64) {
+	atomic.AddInt64(&d.stmtRowsRead, n)
+}
+
+// RecordRowsWritten adds to the count of rows written by the statement currently running in this session
+func (d *DoltSession) RecordRowsWritten(n int64) {
+	atomic.AddInt64(&d.stmtRowsWritten, n)
+}
+
+// resetStatementCounters zeroes the row counts of this session's current statement, returning their old values
+func (d *DoltSession) resetStatementCounters() (rowsRead, rowsWritten uint64) {
+	return uint64(atomic.SwapInt64(&d.stmtRowsRead, 0)), uint64(atomic.SwapInt64(&d.stmtRowsWritten, 0))
+}
+
+type runningStatement struct {
+	db    string
+	query string
+	start time.Time
+}
+
+// statementStatsProcessList wraps a sql.ProcessList to time the queries that begin and end in it and record their
+// resource usage in GlobalStatementStats when @@dolt_statement_stats is enabled.
+type statementStatsProcessList struct {
+	sql.ProcessList
+	mu      *sync.Mutex
+	running map[uint64]runningStatement
+}
+
+var _ sql.ProcessList = (*statementStatsProcessList)(nil)
+
+// NewStatementStatsProcessList returns a sql.ProcessList that records statement stats for the queries run through
+// |pl|
+func NewStatementStatsProcessList(pl sql.ProcessList) sql.ProcessList {
+	return &statementStatsProcessList{
+		ProcessList: pl,
+		mu:          &sync.Mutex{},
+		running:     make(map[uint64]runningStatement),
+	}
+}
+
+// BeginQuery implements sql.ProcessList
+func (pl *statementStatsProcessList) BeginQuery(ctx *sql.Context, query string) (*sql.Context, error) {
+	newCtx, err := pl.ProcessList.BeginQuery(ctx, query)
+	if err != nil || !IsStatementStatsEnabled() {
+		return newCtx, err
+	}
+
+	if sess, ok := ctx.Session.(*DoltSession); ok {
+		sess.resetStatementCounters()
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	pl.running[ctx.Pid()] = runningStatement{
+		db:    ctx.GetCurrentDatabase(),
+		query: query,
+		start: time.Now(),
+	}
+	return newCtx, nil
+}
+
+// EndQuery implements sql.ProcessList
+func (pl *statementStatsProcessList) EndQuery(ctx *sql.Context) {
+	pl.ProcessList.EndQuery(ctx)
+
+	// EndQuery can be called more than once for the same query, only the first call is recorded
+	pl.mu.Lock()
+	stmt, ok := pl.running[ctx.Pid()]
+	delete(pl.running, ctx.Pid())
+	pl.mu.Unlock()
+	if !ok || stmt.db == "" {
+		return
+	}
+
+	elapsed := time.Since(stmt.start)
+	var rowsRead, rowsWritten uint64
+	if sess, ok := ctx.Session.(*DoltSession); ok {
+		rowsRead, rowsWritten = sess.resetStatementCounters()
+	}
+
+	db, _ := SplitRevisionDbName(stmt.db)
+	GlobalStatementStats.Record(db, StatementDigest(stmt.query), stmt.start, elapsed, rowsRead, rowsWritten, maxTrackedStatements())
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatementDigest(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"SELECT * FROM t WHERE pk = 1", "select * from t where pk = ?"},
+		{"select *  from t\n where pk = 'abc'", "select * from t where pk = ?"},
+		{"SELECT * FROM t WHERE pk IN (1, 2, 3)", "select * from t where pk in ?"},
+		{"INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'c')", "insert into t values (?, ?)"},
+		{"UPDATE t SET v = v + 1 WHERE pk > 3", "update t set v = v + ? where pk > ?"},
+		{"not   valid\tsql", "not valid sql"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.expected, StatementDigest(test.query))
+		})
+	}
+}
+
+func TestStatementStats(t *testing.T) {
+	stats := NewStatementStats()
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	stats.Record("DB1", "select ?", start, 2*time.Millisecond, 1, 0, 10)
+	stats.Record("db1", "select ?", start.Add(time.Second), 4*time.Millisecond, 1, 0, 10)
+	stats.Record("db1", "insert ?", start.Add(2*time.Second), time.Millisecond, 0, 5, 10)
+	stats.Record("db2", "select ?", start, time.Millisecond, 1, 0, 10)
+
+	db1 := stats.Snapshot("db1")
+	require.Len(t, db1, 2)
+	assert.Equal(t, "select ?", db1[0].Statement)
+	assert.Equal(t, uint64(2), db1[0].Count)
+	assert.Equal(t, 6*time.Millisecond, db1[0].TotalTime)
+	assert.Equal(t, 4*time.Millisecond, db1[0].MaxTime)
+	assert.Equal(t, uint64(2), db1[0].RowsRead)
+	assert.Equal(t, start, db1[0].FirstSeen)
+	assert.Equal(t, start.Add(time.Second), db1[0].LastSeen)
+	assert.Equal(t, "insert ?", db1[1].Statement)
+	assert.Equal(t, uint64(5), db1[1].RowsWritten)
+
+	stats.Reset("db1")
+	assert.Empty(t, stats.Snapshot("db1"))
+	assert.Len(t, stats.Snapshot("db2"), 1)
+
+	stats.Reset("")
+	assert.Empty(t, stats.Snapshot("db2"))
+}
+
+func TestStatementStatsEviction(t *testing.T) {
+	stats := NewStatementStats()
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	stats.Record("db", "a", start, time.Millisecond, 0, 0, 2)
+	stats.Record("db", "b", start.Add(time.Second), time.Millisecond, 0, 0, 2)
+	stats.Record("db", "a", start.Add(2*time.Second), time.Millisecond, 0, 0, 2)
+	stats.Record("db", "c", start.Add(3*time.Second), time.Millisecond, 0, 0, 2)
+
+	var statements []string
+	for _, stat := range stats.Snapshot("db") {
+		statements = append(statements, stat.Statement)
+	}
+	assert.ElementsMatch(t, []string{"a", "c"}, statements)
+
+	stats.Record("db", "d", start, time.Millisecond, 0, 0, 0)
+	assert.Len(t, stats.Snapshot("db"), 2)
+}
@@ -0,0 +1,201 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// storageQuotaCacheEntries is the number of subtree sizes, and separately of table sizes, cached by StorageQuotas.
+const storageQuotaCacheEntries = 1 << 16
+
+var ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+// StorageQuota limits the storage used by a database, or by one of its branches.
+type StorageQuota struct {
+	// Database is the name of the database the quota applies to.
+	Database string
+	// Branch is the branch the quota applies to, or empty if it applies to the whole database.
+	Branch string
+	// MaxBytes is the most storage, in bytes, the database or branch may use.
+	MaxBytes uint64
+}
+
+// StorageQuotas enforces StorageQuota limits when transactions are committed. The usage of a branch is the size of the
+// row data and indexes of the tables in its working set, which is shared with other branches where their data is the
+// same. The usage of a database is the size of its table files on disk, including its history and the data that's no
+// longer referenced until it's garbage collected.
+//
+// A transaction is rejected if it grows a branch that is over its quota, or that belongs to a database over its quota.
+// Transactions that shrink a branch are always allowed, so that a branch over its quota can be cleaned up.
+type StorageQuotas struct {
+	quotas []StorageQuota
+	nodes  *tree.SizeCache
+	tables *lru.Cache[hash.Hash, uint64]
+}
+
+// NewStorageQuotas returns StorageQuotas enforcing |quotas|.
+func NewStorageQuotas(quotas []StorageQuota) (*StorageQuotas, error) {
+	nodes, err := tree.NewSizeCache(storageQuotaCacheEntries)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := lru.New[hash.Hash, uint64](storageQuotaCacheEntries)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageQuotas{quotas: quotas, nodes: nodes, tables: tables}, nil
+}
+
+// DatabaseQuota returns the quota of the database |dbName|, if it has one.
+func (q *StorageQuotas) DatabaseQuota(dbName string) (StorageQuota, bool) {
+	return q.find(dbName, "")
+}
+
+// BranchQuota returns the quota of the branch |branch| of the database |dbName|, if it has one.
+func (q *StorageQuotas) BranchQuota(dbName, branch string) (StorageQuota, bool) {
+	return q.find(dbName, branch)
+}
+
+func (q *StorageQuotas) find(dbName, branch string) (StorageQuota, bool) {
+	if q == nil {
+		return StorageQuota{}, false
+	}
+	for _, quota := range q.quotas {
+		if strings.EqualFold(quota.Database, dbName) && quota.Branch == branch {
+			return quota, true
+		}
+	}
+	return StorageQuota{}, false
+}
+
+// RootSize returns the size in bytes of the row data and indexes of the tables in |root|. Values stored out of band,
+// like large TEXT and BLOB values, aren't included. Roots in the old storage format have no size.
+func (q *StorageQuotas) RootSize(ctx context.Context, root *doltdb.RootValue) (uint64, error) {
+	if root == nil || !types.IsFormat_DOLT(root.VRW().Format()) {
+		return 0, nil
+	}
+	tableHashes, err := root.MapTableHashes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var size uint64
+	for name, h := range tableHashes {
+		tableSize, ok := q.tables.Get(h)
+		if !ok {
+			tbl, ok, err := root.GetTable(ctx, name)
+			if err != nil {
+				return 0, err
+			} else if !ok {
+				return 0, fmt.Errorf("root found a table with name '%s' but could not load it", name)
+			}
+			if tableSize, err = q.tableSize(ctx, tbl); err != nil {
+				return 0, err
+			}
+			q.tables.Add(h, tableSize)
+		}
+		size += tableSize
+	}
+	return size, nil
+}
+
+func (q *StorageQuotas) tableSize(ctx context.Context, tbl *doltdb.Table) (uint64, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return 0, err
+	}
+	size, err := q.indexSize(ctx, rows)
+	if err != nil {
+		return 0, err
+	}
+
+	indexes, err := tbl.GetIndexSet(ctx)
+	if err != nil {
+		return 0, err
+	}
+	err = durable.IterAllIndexes(ctx, sch, indexes, func(_ string, idx durable.Index) error {
+		indexSize, err := q.indexSize(ctx, idx)
+		size += indexSize
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func (q *StorageQuotas) indexSize(ctx context.Context, idx durable.Index) (uint64, error) {
+	m := durable.ProllyMapFromIndex(idx)
+	return q.nodes.Size(ctx, m.NodeStore(), m.Node())
+}
+
+// Check returns an error wrapping ErrStorageQuotaExceeded if writing |workingSet| to the database |dbName| in place
+// of |existing| grows a branch or database over its quota.
+func (q *StorageQuotas) Check(ctx context.Context, dbName string, ddb *doltdb.DoltDB, existing, workingSet *doltdb.WorkingSet) error {
+	headRef, err := workingSet.Ref().ToHeadRef()
+	if err != nil {
+		return err
+	}
+	branch := headRef.GetPath()
+	branchQuota, hasBranchQuota := q.BranchQuota(dbName, branch)
+	dbQuota, hasDbQuota := q.DatabaseQuota(dbName)
+	if !hasBranchQuota && !hasDbQuota {
+		return nil
+	}
+
+	size, err := q.RootSize(ctx, workingSet.WorkingRoot())
+	if err != nil {
+		return err
+	}
+	existingSize, err := q.RootSize(ctx, existing.WorkingRoot())
+	if err != nil {
+		return err
+	}
+	if size <= existingSize {
+		return nil
+	}
+
+	if hasBranchQuota && size > branchQuota.MaxBytes {
+		return fmt.Errorf("%w: branch %s of database %s would use %d bytes of the %d bytes allowed",
+			ErrStorageQuotaExceeded, branch, dbName, size, branchQuota.MaxBytes)
+	}
+	if hasDbQuota {
+		dbSize, err := ddb.StorageSize(ctx)
+		if err != nil {
+			return err
+		}
+		if dbSize > dbQuota.MaxBytes {
+			return fmt.Errorf("%w: database %s is using %d bytes of the %d bytes allowed",
+				ErrStorageQuotaExceeded, dbName, dbSize, dbQuota.MaxBytes)
+		}
+	}
+	return nil
+}
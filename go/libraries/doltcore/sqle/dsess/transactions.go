@@ -25,11 +25,13 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/vitess/go/mysql"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -391,51 +393,12 @@ func (tx *DoltTransaction) doCommit(
 			txLock.Lock()
 			defer txLock.Unlock()
 
-			newWorkingSet := false
-
-			existingWs, err := startPoint.db.ResolveWorkingSet(ctx, workingSet.Ref())
-			if err == doltdb.ErrWorkingSetNotFound {
-				// This is to handle the case where an existing DB pre working sets is committing to this HEAD for the
-				// first time. Can be removed and called an error post 1.0
-				existingWs = doltdb.EmptyWorkingSet(workingSet.Ref())
-				newWorkingSet = true
-			} else if err != nil {
-				return nil, nil, err
-			}
-
-			existingWSHash, err := existingWs.HashOf()
+			mergedWorkingSet, existingWs, _, err := tx.mergeWorkingSet(ctx, branchState.dbState.dbName, startPoint.db, startState, workingSet, mergeOpts)
 			if err != nil {
 				return nil, nil, err
 			}
 
-			if newWorkingSet || workingAndStagedEqual(existingWs, startState) {
-				// ff merge
-				err = tx.validateWorkingSetForCommit(ctx, workingSet, isFfMerge)
-				if err != nil {
-					return nil, nil, err
-				}
-
-				var newCommit *doltdb.Commit
-				workingSet, newCommit, err = writeFn(ctx, tx, startPoint.db, startState, commit, workingSet, existingWSHash, mergeOpts)
-				if err == datas.ErrOptimisticLockFailed {
-					// this is effectively a `continue` in the loop
-					return nil, nil, nil
-				} else if err != nil {
-					return nil, nil, err
-				}
-
-				return workingSet, newCommit, nil
-			}
-
-			// otherwise (not a ff), merge the working sets together
-			start := time.Now()
-			mergedWorkingSet, err := tx.mergeRoots(ctx, startState, existingWs, workingSet, mergeOpts)
-			if err != nil {
-				return nil, nil, err
-			}
-			logrus.Tracef("working set merge took %s", time.Since(start))
-
-			err = tx.validateWorkingSetForCommit(ctx, mergedWorkingSet, notFfMerge)
+			existingWSHash, err := existingWs.HashOf()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -463,6 +426,233 @@ func (tx *DoltTransaction) doCommit(
 	return nil, nil, datas.ErrOptimisticLockFailed
 }
 
+// mergeWorkingSet returns the working set to write in place of the current one for |workingSet|'s ref, along with that
+// current working set. If the current working set hasn't changed since the transaction started, that's |workingSet|
+// itself; otherwise it's |workingSet| merged with the changes committed since. The result is validated for commit and
+// checked against the session's storage quotas. If there is no current working set, |newWorkingSet| is true. |dbName|
+// is the base name of the database of |db|. Callers must hold txLock.
+func (tx *DoltTransaction) mergeWorkingSet(
+	ctx *sql.Context,
+	dbName string,
+	db *doltdb.DoltDB,
+	startState *doltdb.WorkingSet,
+	workingSet *doltdb.WorkingSet,
+	mergeOpts editor.Options,
+) (merged *doltdb.WorkingSet, existingWs *doltdb.WorkingSet, newWorkingSet bool, err error) {
+	existingWs, err = db.ResolveWorkingSet(ctx, workingSet.Ref())
+	if err == doltdb.ErrWorkingSetNotFound {
+		// This is to handle the case where an existing DB pre working sets is committing to this HEAD for the
+		// first time. Can be removed and called an error post 1.0
+		existingWs = doltdb.EmptyWorkingSet(workingSet.Ref())
+		newWorkingSet = true
+	} else if err != nil {
+		return nil, nil, false, err
+	}
+
+	if newWorkingSet || workingAndStagedEqual(existingWs, startState) {
+		// ff merge
+		err = tx.validateWorkingSetForCommit(ctx, workingSet, isFfMerge)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		err = tx.checkStorageQuota(ctx, dbName, db, existingWs, workingSet)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return workingSet, existingWs, newWorkingSet, nil
+	}
+
+	// otherwise (not a ff), merge the working sets together
+	start := time.Now()
+	merged, err = tx.mergeRoots(ctx, startState, existingWs, workingSet, mergeOpts)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	logrus.Tracef("working set merge took %s", time.Since(start))
+
+	err = tx.validateWorkingSetForCommit(ctx, merged, notFfMerge)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	err = tx.checkStorageQuota(ctx, dbName, db, existingWs, merged)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return merged, existingWs, newWorkingSet, nil
+}
+
+// checkStorageQuota rolls back the transaction and returns an error if writing |workingSet| in place of |existingWs|
+// exceeds the storage quotas of the session. |dbName| is the base name of the database of |db|.
+func (tx *DoltTransaction) checkStorageQuota(ctx *sql.Context, dbName string, db *doltdb.DoltDB, existingWs, workingSet *doltdb.WorkingSet) error {
+	quotas := DSessFromSess(ctx.Session).StorageQuotas()
+	if quotas == nil {
+		return nil
+	}
+	if err := quotas.Check(ctx, dbName, db, existingWs, workingSet); err != nil {
+		if rollbackErr := tx.rollback(ctx); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return nil
+}
+
+// transactionWorkingSet is a working set written by CommitAll
+type transactionWorkingSet struct {
+	dbName     string
+	db         *doltdb.DoltDB
+	startState *doltdb.WorkingSet
+	workingSet *doltdb.WorkingSet
+	mergeOpts  editor.Options
+	// existing and written describe the working set replaced by the most recent attempt to write this one
+	existing *doltdb.WorkingSet
+	written  *doltdb.WorkingSet
+}
+
+// CommitAll commits the working sets given, which may belong to different databases and branches: either all of them
+// are written or none are. Every working set is merged and validated as in Commit before any is written, and all of
+// them are written under the lock that serializes transaction commits, so no other transaction commits in between.
+// The working sets are written in two phases, so that a crash can't leave only some of them written, see
+// doltdb.StageWorkingSet. The database of the first working set coordinates the transaction.
+//
+// The writes aren't isolated from readers: a transaction that starts while they're made can see the working sets of
+// some databases without the others. |dbNames| are the names of the databases of |workingSets|.
+func (tx *DoltTransaction) CommitAll(ctx *sql.Context, dbNames []string, workingSets []*doltdb.WorkingSet) error {
+	sess := DSessFromSess(ctx.Session)
+	writes := make([]*transactionWorkingSet, len(workingSets))
+	for i, workingSet := range workingSets {
+		branchState, ok, err := sess.lookupDbState(ctx, dbNames[i])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("database %s unknown to transaction, this is a bug", dbNames[i])
+		}
+
+		startPoint, ok := tx.dbStartPoints[strings.ToLower(branchState.dbState.dbName)]
+		if !ok {
+			return fmt.Errorf("database %s unknown to transaction, this is a bug", dbNames[i])
+		}
+
+		startState, err := startPoint.db.ResolveWorkingSetAtRoot(ctx, workingSet.Ref(), startPoint.rootHash)
+		if err != nil {
+			return err
+		}
+
+		writes[i] = &transactionWorkingSet{
+			dbName:     branchState.dbState.dbName,
+			db:         startPoint.db,
+			startState: startState,
+			workingSet: workingSet,
+			mergeOpts:  branchState.EditOpts(),
+		}
+	}
+
+	for i := 0; i < maxTxCommitRetries; i++ {
+		done, err := func() (bool, error) {
+			txLock.Lock()
+			defer txLock.Unlock()
+
+			// merge and validate every working set before writing any of them
+			for _, w := range writes {
+				var err error
+				w.written, w.existing, _, err = tx.mergeWorkingSet(ctx, w.dbName, w.db, w.startState, w.workingSet, w.mergeOpts)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			err := tx.writeWorkingSets(ctx, writes)
+			if err == datas.ErrOptimisticLockFailed {
+				// this is effectively a `continue` in the loop
+				return false, nil
+			}
+			return err == nil, err
+		}()
+
+		if err != nil {
+			return err
+		} else if done {
+			return nil
+		}
+	}
+
+	// TODO: different error type for retries exhausted
+	return datas.ErrOptimisticLockFailed
+}
+
+// transactionDatabase is a database written to by CommitAll
+type transactionDatabase struct {
+	name string
+	db   *doltdb.DoltDB
+	// prevHashes are the hashes of the working sets of the database replaced by the transaction
+	prevHashes map[ref.WorkingSetRef]hash.Hash
+}
+
+// writeWorkingSets writes the merged working sets of |writes| in two phases. First each working set is staged in its
+// database. Then the working sets staged in the database of the first write, the coordinator, are published, which
+// commits the transaction, as long as its working sets haven't changed since they were merged. Last, the working sets
+// staged in the other databases are published. If the transaction fails before it commits, the working sets staged
+// for it are removed. Callers must hold txLock.
+func (tx *DoltTransaction) writeWorkingSets(ctx *sql.Context, writes []*transactionWorkingSet) error {
+	var dbs []*transactionDatabase
+	byDb := make(map[*doltdb.DoltDB]*transactionDatabase)
+	for _, w := range writes {
+		d, ok := byDb[w.db]
+		if !ok {
+			d = &transactionDatabase{name: strings.ToLower(w.dbName), db: w.db, prevHashes: make(map[ref.WorkingSetRef]hash.Hash)}
+			byDb[w.db] = d
+			dbs = append(dbs, d)
+		}
+		existingHash, err := w.existing.HashOf()
+		if err != nil {
+			return err
+		}
+		d.prevHashes[w.written.Ref()] = existingHash
+	}
+
+	txID := uuid.NewString()
+	coordinator := dbs[0]
+	meta := tx.getWorkingSetMeta(ctx)
+	for _, w := range writes {
+		if err := w.db.StageWorkingSet(ctx, txID, coordinator.name, w.written, meta); err != nil {
+			return abortWorkingSets(ctx, txID, dbs, err)
+		}
+	}
+
+	var rsc doltdb.ReplicationStatusController
+	err := coordinator.db.PublishStagedWorkingSets(ctx, txID, coordinator.name, true, coordinator.prevHashes, &rsc)
+	WaitForReplicationController(ctx, rsc)
+	if err != nil {
+		return abortWorkingSets(ctx, txID, dbs, err)
+	}
+
+	// the transaction is committed, so if a working set fails to be published here, it's published when the databases
+	// are next loaded
+	for _, d := range dbs[1:] {
+		var rsc doltdb.ReplicationStatusController
+		err = d.db.PublishStagedWorkingSets(ctx, txID, coordinator.name, false, nil, &rsc)
+		WaitForReplicationController(ctx, rsc)
+		if err != nil {
+			return fmt.Errorf("transaction committed, but its changes to database %s will only be visible once it's reloaded: %w", d.name, err)
+		}
+	}
+	// remove the commit record
+	return coordinator.db.DeleteStagedWorkingSets(ctx, txID, coordinator.name)
+}
+
+// abortWorkingSets removes the working sets staged in |dbs| for the transaction |txID| which failed with |err|, and
+// returns |err|. The coordinator's are removed last, so that a crash can't leave working sets staged in another
+// database without them.
+func abortWorkingSets(ctx *sql.Context, txID string, dbs []*transactionDatabase, err error) error {
+	for i := len(dbs) - 1; i >= 0; i-- {
+		if rerr := dbs[i].db.DeleteStagedWorkingSets(ctx, txID, dbs[0].name); rerr != nil {
+			return fmt.Errorf("%w; removing the working sets staged for the transaction also failed: %s", err, rerr.Error())
+		}
+	}
+	return err
+}
+
 // mergeRoots merges the roots in the existing working set with the one being committed and returns the resulting
 // working set. Conflicts are automatically resolved with "accept ours" if the session settings dictate it.
 // Currently merges working and staged roots as necessary. HEAD root is only handled by the DoltCommit function.
@@ -0,0 +1,102 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dsess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+func TestWriteWorkingSets(t *testing.T) {
+	tests := []struct {
+		name string
+		// changed is the index of the database whose working set is changed before the writes, or -1 if none are
+		changed int
+	}{
+		{name: "every write succeeds", changed: -1},
+		{name: "coordinator's working set changed", changed: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := sql.NewContext(context.Background(), sql.WithSession(DefaultSession(emptyDatabaseProvider())))
+			writes := make([]*transactionWorkingSet, 3)
+			for i := range writes {
+				dEnv := dtestutils.CreateTestEnvWithName(t.Name() + string(rune('a'+i)))
+				defer dEnv.DoltDB.Close()
+				writes[i] = tableCreatingWrite(t, ctx, dEnv.DoltDB, string(rune('a'+i)))
+			}
+
+			if test.changed >= 0 {
+				w := writes[test.changed]
+				prevHash, err := w.existing.HashOf()
+				require.NoError(t, err)
+				changed := w.existing.WithStagedRoot(w.written.WorkingRoot())
+				require.NoError(t, w.db.UpdateWorkingSet(ctx, w.existing.Ref(), changed, prevHash, nil, nil))
+			}
+
+			tx := &DoltTransaction{}
+			err := tx.writeWorkingSets(ctx, writes)
+			if test.changed >= 0 {
+				require.ErrorIs(t, err, datas.ErrOptimisticLockFailed)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// either every database has the new table or none do
+			for _, w := range writes {
+				ws, err := w.db.ResolveWorkingSet(ctx, w.existing.Ref())
+				require.NoError(t, err)
+				has, err := ws.WorkingRoot().HasTable(ctx, "t")
+				require.NoError(t, err)
+				assert.Equal(t, test.changed < 0, has)
+			}
+		})
+	}
+}
+
+// tableCreatingWrite returns a write of the working set of the default branch of |db|, named |name|, that adds a table to it.
+func tableCreatingWrite(t *testing.T, ctx *sql.Context, db *doltdb.DoltDB, name string) *transactionWorkingSet {
+	wsRef, err := ref.WorkingSetRefForHead(ref.NewBranchRef(env.DefaultInitBranch))
+	require.NoError(t, err)
+	existing, err := db.ResolveWorkingSet(ctx, wsRef)
+	require.NoError(t, err)
+
+	sch, err := dtestutils.Schema()
+	require.NoError(t, err)
+	tbl, err := doltdb.NewEmptyTable(ctx, db.ValueReadWriter(), db.NodeStore(), sch)
+	require.NoError(t, err)
+	root, err := existing.WorkingRoot().PutTable(ctx, "t", tbl)
+	require.NoError(t, err)
+
+	return &transactionWorkingSet{
+		dbName:     name,
+		db:         db,
+		startState: existing,
+		workingSet: existing.WithWorkingRoot(root),
+		existing:   existing,
+		written:    existing.WithWorkingRoot(root),
+	}
+}
@@ -51,6 +51,13 @@ const (
 	AwsCredsRegion                = "aws_credentials_region"
 	ShowBranchDatabases           = "dolt_show_branch_databases"
 	DoltLogLevel                  = "dolt_log_level"
+	SnapshotSession               = "dolt_snapshot_session"
+	BulkLoad                      = "dolt_bulk_load"
+	MetadataLocksEnabled          = "dolt_metadata_locks"
+	DefaultAsOf                   = "dolt_default_as_of"
+	StatementStatsEnabled         = "dolt_statement_stats"
+	StatementStatsMaxStatements   = "dolt_statement_stats_max_statements"
+	MergeDeleteSource             = "dolt_merge_delete_source"
 
 	DoltClusterRoleVariable         = "dolt_cluster_role"
 	DoltClusterRoleEpochVariable    = "dolt_cluster_role_epoch"
@@ -205,6 +205,7 @@ type prollyDiffIter struct {
 	fromConverter, toConverter ProllyRowConverter
 	fromVD, toVD               val.TupleDesc
 	keyless                    bool
+	keyRange                   *prolly.Range
 
 	fromCm commitInfo2
 	toCm   commitInfo2
@@ -268,19 +269,44 @@ func newProllyDiffIter(ctx *sql.Context, dp DiffPartition, targetFromSchema, tar
 		}
 	}
 
-	var nodeStore tree.NodeStore
+	// the two sides of the diff can come from different databases, so each side's values are read from its own store
+	var fromNodeStore, toNodeStore tree.NodeStore
 	if dp.to != nil {
-		nodeStore = dp.to.NodeStore()
-	} else {
-		nodeStore = dp.from.NodeStore()
+		fromNodeStore, toNodeStore = dp.to.NodeStore(), dp.to.NodeStore()
+	}
+	if dp.from != nil {
+		fromNodeStore = dp.from.NodeStore()
+		if dp.to == nil {
+			toNodeStore = fromNodeStore
+		}
+	}
+
+	var keyRange *prolly.Range
+	if dp.keyRange != nil {
+		var err error
+		// range diffs search both trees for the bounds of the range, so a missing table is diffed as an empty one
+		if dp.from == nil {
+			if from, err = prolly.NewMapFromTuples(ctx, toNodeStore, to.KeyDesc(), to.ValDesc()); err != nil {
+				return prollyDiffIter{}, err
+			}
+		} else if dp.to == nil {
+			if to, err = prolly.NewMapFromTuples(ctx, fromNodeStore, from.KeyDesc(), from.ValDesc()); err != nil {
+				return prollyDiffIter{}, err
+			}
+		}
+		rng, err := dp.keyRange.prollyRange(ctx, to.KeyDesc(), toNodeStore)
+		if err != nil {
+			return prollyDiffIter{}, err
+		}
+		keyRange = &rng
 	}
 
-	fromConverter, err := NewProllyRowConverter(fsch, targetFromSchema, ctx.Warn, nodeStore)
+	fromConverter, err := NewProllyRowConverter(fsch, targetFromSchema, ctx.Warn, fromNodeStore)
 	if err != nil {
 		return prollyDiffIter{}, err
 	}
 
-	toConverter, err := NewProllyRowConverter(tsch, targetToSchema, ctx.Warn, nodeStore)
+	toConverter, err := NewProllyRowConverter(tsch, targetToSchema, ctx.Warn, toNodeStore)
 	if err != nil {
 		return prollyDiffIter{}, err
 	}
@@ -301,6 +327,7 @@ func newProllyDiffIter(ctx *sql.Context, dp DiffPartition, targetFromSchema, tar
 		fromVD:        fromVD,
 		toVD:          toVD,
 		keyless:       keyless,
+		keyRange:      keyRange,
 		fromCm:        fromCm,
 		toCm:          toCm,
 		rows:          make(chan sql.Row, 64),
@@ -335,7 +362,7 @@ func (itr prollyDiffIter) Close(ctx *sql.Context) error {
 }
 
 func (itr prollyDiffIter) queueRows(ctx context.Context) {
-	err := prolly.DiffMaps(ctx, itr.from, itr.to, func(ctx context.Context, d tree.Diff) error {
+	err := itr.diffMaps(ctx, func(ctx context.Context, d tree.Diff) error {
 		dItr, err := itr.makeDiffRowItr(ctx, d)
 		if err != nil {
 			return err
@@ -367,6 +394,38 @@ func (itr prollyDiffIter) queueRows(ctx context.Context) {
 	close(itr.rows)
 }
 
+// diffMaps calls |cb| with each difference between the from and to rows, limited to the iterator's key range if it
+// has one
+func (itr prollyDiffIter) diffMaps(ctx context.Context, cb tree.DiffFn) error {
+	if itr.keyRange != nil {
+		return prolly.RangeDiffMaps(ctx, itr.from, itr.to, *itr.keyRange, cb)
+	}
+	return prolly.DiffMaps(ctx, itr.from, itr.to, cb)
+}
+
+// prollyRange returns the prolly.Range of the tuples described by |kd| whose first field is within the key range
+func (r *DiffKeyRange) prollyRange(ctx context.Context, kd val.TupleDesc, ns tree.NodeStore) (prolly.Range, error) {
+	var field prolly.RangeField
+	tb := val.NewTupleBuilder(kd.PrefixDesc(1))
+	if r.Min != nil {
+		if err := index.PutField(ctx, ns, tb, 0, r.Min); err != nil {
+			return prolly.Range{}, err
+		}
+		field.Lo = prolly.Bound{Binding: true, Inclusive: true, Value: tb.BuildPermissive(ns.Pool()).GetField(0)}
+	}
+	if r.Max != nil {
+		if err := index.PutField(ctx, ns, tb, 0, r.Max); err != nil {
+			return prolly.Range{}, err
+		}
+		field.Hi = prolly.Bound{Binding: true, Inclusive: true, Value: tb.BuildPermissive(ns.Pool()).GetField(0)}
+	}
+	if field.Lo.Binding && field.Hi.Binding {
+		field.Exact = kd.Comparator().CompareValues(0, field.Lo.Value, field.Hi.Value, kd.Types[0]) == 0
+	}
+
+	return prolly.Range{Fields: []prolly.RangeField{field}, Desc: kd}, nil
+}
+
 // todo(andy): copy string fields
 func (itr prollyDiffIter) makeDiffRowItr(ctx context.Context, d tree.Diff) (*repeatingRowIter, error) {
 	if !itr.keyless {
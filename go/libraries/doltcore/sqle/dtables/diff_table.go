@@ -166,7 +166,7 @@ func (dt *DiffTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
 	}
 
 	cmHashToTblInfo := make(map[hash.Hash]TblInfoAtCommit)
-	cmHashToTblInfo[cmHash] = TblInfoAtCommit{"WORKING", nil, t, wrTblHash}
+	cmHashToTblInfo[cmHash] = NewTblInfoAtCommit("WORKING", nil, t, wrTblHash)
 
 	err = cmItr.Reset(ctx)
 	if err != nil {
@@ -174,6 +174,7 @@ func (dt *DiffTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
 	}
 
 	return &DiffPartitions{
+		ddb:             dt.ddb,
 		tblName:         exactName,
 		cmItr:           cmItr,
 		cmHashToTblInfo: cmHashToTblInfo,
@@ -296,7 +297,7 @@ func (dt *DiffTable) fromCommitLookupPartitions(ctx *sql.Context, hashes []hash.
 		}
 
 		if childCm != nil {
-			ti, err := tableInfoForCommit(ctx, dt.name, childCm, childHs)
+			ti, err := tableInfoForCommit(ctx, dt.ddb, dt.name, childCm, childHs)
 			if err != nil {
 				return nil, err
 			}
@@ -321,6 +322,7 @@ func (dt *DiffTable) fromCommitLookupPartitions(ctx *sql.Context, hashes []hash.
 	}
 
 	return &DiffPartitions{
+		ddb:             dt.ddb,
 		tblName:         exactName,
 		cmItr:           cmItr,
 		cmHashToTblInfo: cmHashToTblInfo,
@@ -398,32 +400,20 @@ func (dt *DiffTable) reverseIterForChild(ctx *sql.Context, parent hash.Hash) (*d
 	}
 }
 
-func tableInfoForCommit(ctx context.Context, table string, cm *doltdb.Commit, hs hash.Hash) (TblInfoAtCommit, error) {
-	r, err := cm.GetRootValue(ctx)
-	if err != nil {
-		return TblInfoAtCommit{}, err
-	}
-
-	tbl, exactName, ok, err := r.GetTableInsensitive(ctx, table)
+// tableInfoForCommit returns the info of |table| at |cm|, without loading the table. It's loaded from the commit's root
+// value only if it's diffed.
+func tableInfoForCommit(ctx context.Context, ddb *doltdb.DoltDB, table string, cm *doltdb.Commit, hs hash.Hash) (TblInfoAtCommit, error) {
+	entry, err := ddb.CommitRootEntry(ctx, cm)
 	if err != nil {
 		return TblInfoAtCommit{}, err
 	}
+	_, tblHash, ok := entry.TableHash(table)
 	if !ok {
 		return TblInfoAtCommit{}, nil
 	}
 
-	tblHash, _, err := r.GetTableHash(ctx, exactName)
-	if err != nil {
-		return TblInfoAtCommit{}, err
-	}
-
-	meta, err := cm.GetCommitMeta(ctx)
-	if err != nil {
-		return TblInfoAtCommit{}, err
-	}
-
-	ts := types.Timestamp(meta.Time())
-	return NewTblInfoAtCommit(hs.String(), &ts, tbl, tblHash), nil
+	ts := types.Timestamp(entry.Meta.Time())
+	return TblInfoAtCommit{name: hs.String(), date: &ts, tblHash: tblHash, cm: cm}, nil
 }
 
 // toCommitLookupPartitions creates a diff partition iterator for a set of
@@ -455,7 +445,7 @@ func (dt *DiffTable) toCommitLookupPartitions(ctx *sql.Context, hashes []hash.Ha
 				return nil, err
 			}
 
-			toCmInfo = TblInfoAtCommit{"WORKING", nil, t, wrTblHash}
+			toCmInfo = NewTblInfoAtCommit("WORKING", nil, t, wrTblHash)
 			cmHashToTblInfo[hs] = toCmInfo
 			parentHashes = append(parentHashes, hs)
 			pCommits = append(pCommits, dt.head)
@@ -475,7 +465,7 @@ func (dt *DiffTable) toCommitLookupPartitions(ctx *sql.Context, hashes []hash.Ha
 			continue
 		}
 
-		ti, err := tableInfoForCommit(ctx, dt.name, cm, hs)
+		ti, err := tableInfoForCommit(ctx, dt.ddb, dt.name, cm, hs)
 		if err != nil {
 			return nil, err
 		}
@@ -515,6 +505,7 @@ func (dt *DiffTable) toCommitLookupPartitions(ctx *sql.Context, hashes []hash.Ha
 	}
 
 	return &DiffPartitions{
+		ddb:             dt.ddb,
 		tblName:         exactName,
 		cmItr:           cmItr,
 		cmHashToTblInfo: cmHashToTblInfo,
@@ -572,11 +563,13 @@ type TblInfoAtCommit struct {
 	date    *types.Timestamp
 	tbl     *doltdb.Table
 	tblHash hash.Hash
+	// cm is the commit the table is loaded from if |tbl| is nil
+	cm *doltdb.Commit
 }
 
 func NewTblInfoAtCommit(name string, date *types.Timestamp, tbl *doltdb.Table, tblHash hash.Hash) TblInfoAtCommit {
 	return TblInfoAtCommit{
-		name, date, tbl, tblHash,
+		name: name, date: date, tbl: tbl, tblHash: tblHash,
 	}
 }
 
@@ -584,6 +577,20 @@ func (ti TblInfoAtCommit) IsEmpty() bool {
 	return ti.name == ""
 }
 
+// table returns the table named |tblName|, loading it from the root value of its commit if it hasn't been loaded. It
+// returns nil if the table doesn't exist at the commit.
+func (ti TblInfoAtCommit) table(ctx context.Context, tblName string) (*doltdb.Table, error) {
+	if ti.tbl != nil || ti.cm == nil || ti.tblHash.IsEmpty() {
+		return ti.tbl, nil
+	}
+	root, err := ti.cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tbl, _, _, err := root.GetTableInsensitive(ctx, tblName)
+	return tbl, err
+}
+
 var _ sql.Partition = (*DiffPartition)(nil)
 
 // DiffPartition data partitioned into pairs of table states which get compared
@@ -597,6 +604,15 @@ type DiffPartition struct {
 	// fromSch and toSch are usually identical. It is the schema of the table at head.
 	toSch   schema.Schema
 	fromSch schema.Schema
+	// keyRange, if set, limits the diff to the rows within it
+	keyRange *DiffKeyRange
+}
+
+// DiffKeyRange bounds the values of the first primary key column of the rows in a diff. Both bounds are inclusive, and
+// a nil bound leaves that end of the range open. The bounds are searched for in the prolly trees being diffed, so only
+// the parts of the trees within the range are read.
+type DiffKeyRange struct {
+	Min, Max interface{}
 }
 
 func NewDiffPartition(to, from *doltdb.Table, toName, fromName string, toDate, fromDate *types.Timestamp, toSch, fromSch schema.Schema) *DiffPartition {
@@ -612,6 +628,13 @@ func NewDiffPartition(to, from *doltdb.Table, toName, fromName string, toDate, f
 	}
 }
 
+// WithKeyRange returns a copy of the partition that only diffs the rows within |rng|. Key ranges are only supported
+// for tables with primary keys in the __DOLT__ format.
+func (dp DiffPartition) WithKeyRange(rng *DiffKeyRange) *DiffPartition {
+	dp.keyRange = rng
+	return &dp
+}
+
 func (dp DiffPartition) Key() []byte {
 	return []byte(dp.toName + dp.fromName)
 }
@@ -698,6 +721,7 @@ var _ sql.PartitionIter = &DiffPartitions{}
 
 // DiffPartitions a collection of partitions. Implements PartitionItr
 type DiffPartitions struct {
+	ddb             *doltdb.DoltDB
 	tblName         string
 	cmItr           doltdb.CommitItr
 	cmHashToTblInfo map[hash.Hash]TblInfoAtCommit
@@ -706,8 +730,9 @@ type DiffPartitions struct {
 	fromSch         schema.Schema
 }
 
-func NewDiffPartitions(tblName string, cmItr doltdb.CommitItr, cmHashToTblInfo map[hash.Hash]TblInfoAtCommit, selectFunc partitionSelectFunc, toSch, fromSch schema.Schema) *DiffPartitions {
+func NewDiffPartitions(ddb *doltdb.DoltDB, tblName string, cmItr doltdb.CommitItr, cmHashToTblInfo map[hash.Hash]TblInfoAtCommit, selectFunc partitionSelectFunc, toSch, fromSch schema.Schema) *DiffPartitions {
 	return &DiffPartitions{
+		ddb:             ddb,
 		tblName:         tblName,
 		cmItr:           cmItr,
 		cmHashToTblInfo: cmHashToTblInfo,
@@ -718,29 +743,27 @@ func NewDiffPartitions(tblName string, cmItr doltdb.CommitItr, cmHashToTblInfo m
 }
 
 // processCommit is called in a commit iteration loop. Adds partitions when it finds a commit and its parent that have
-// different values for the hash of the table being looked at.
-func (dps *DiffPartitions) processCommit(ctx *sql.Context, cmHash hash.Hash, cm *doltdb.Commit, root *doltdb.RootValue, tbl *doltdb.Table) (*DiffPartition, error) {
-	tblHash, _, err := root.GetTableHash(ctx, dps.tblName)
-
+// different values for the hash of the table being looked at. The commit root index answers whether the table changed,
+// and the commit and date filters are checked before the tables are loaded, so a commit's root value is only loaded
+// when it has a partition that's returned.
+func (dps *DiffPartitions) processCommit(ctx *sql.Context, cmHash hash.Hash, cm *doltdb.Commit) (*DiffPartition, error) {
+	entry, err := dps.ddb.CommitRootEntry(ctx, cm)
 	if err != nil {
 		return nil, err
 	}
+	_, tblHash, _ := entry.TableHash(dps.tblName)
 
 	toInfoForCommit := dps.cmHashToTblInfo[cmHash]
 	cmHashStr := cmHash.String()
-	meta, err := cm.GetCommitMeta(ctx)
-
-	if err != nil {
-		return nil, err
-	}
-
-	ts := types.Timestamp(meta.Time())
+	ts := types.Timestamp(entry.Meta.Time())
 
+	// the table is the same as the one in the child commit unless its hash changed
+	newInfo := TblInfoAtCommit{cmHashStr, &ts, toInfoForCommit.tbl, tblHash, toInfoForCommit.cm}
 	var nextPartition *DiffPartition
 	if tblHash != toInfoForCommit.tblHash {
+		newInfo = TblInfoAtCommit{name: cmHashStr, date: &ts, tblHash: tblHash, cm: cm}
+
 		partition := DiffPartition{
-			to:       toInfoForCommit.tbl,
-			from:     tbl,
 			toName:   toInfoForCommit.name,
 			fromName: cmHashStr,
 			toDate:   toInfoForCommit.date,
@@ -755,11 +778,17 @@ func (dps *DiffPartitions) processCommit(ctx *sql.Context, cmHash hash.Hash, cm
 		}
 
 		if selected {
+			if partition.to, err = toInfoForCommit.table(ctx, dps.tblName); err != nil {
+				return nil, err
+			}
+			if partition.from, err = newInfo.table(ctx, dps.tblName); err != nil {
+				return nil, err
+			}
+			newInfo.tbl = partition.from
 			nextPartition = &partition
 		}
 	}
 
-	newInfo := TblInfoAtCommit{cmHashStr, &ts, tbl, tblHash}
 	parentHashes, err := cm.ParentHashes(ctx)
 
 	if err != nil {
@@ -780,19 +809,7 @@ func (dps *DiffPartitions) Next(ctx *sql.Context) (sql.Partition, error) {
 			return nil, err
 		}
 
-		root, err := cm.GetRootValue(ctx)
-
-		if err != nil {
-			return nil, err
-		}
-
-		tbl, _, _, err := root.GetTableInsensitive(ctx, dps.tblName)
-
-		if err != nil {
-			return nil, err
-		}
-
-		next, err := dps.processCommit(ctx, cmHash, cm, root, tbl)
+		next, err := dps.processCommit(ctx, cmHash, cm)
 
 		if err != nil {
 			return nil, err
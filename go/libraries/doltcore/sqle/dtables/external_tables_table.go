@@ -0,0 +1,306 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqlTypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var _ sql.Table = (*ExternalTablesTable)(nil)
+var _ sql.UpdatableTable = (*ExternalTablesTable)(nil)
+var _ sql.DeletableTable = (*ExternalTablesTable)(nil)
+var _ sql.InsertableTable = (*ExternalTablesTable)(nil)
+var _ sql.ReplaceableTable = (*ExternalTablesTable)(nil)
+
+// ExternalTablesTable is the system table that defines external tables, read-only tables whose rows are read from
+// csv or parquet files on disk or in object storage each time they are scanned.
+type ExternalTablesTable struct {
+	ddb          *doltdb.DoltDB
+	backingTable sql.Table
+}
+
+func (et *ExternalTablesTable) Name() string {
+	return doltdb.ExternalTablesTableName
+}
+
+func (et *ExternalTablesTable) String() string {
+	return doltdb.ExternalTablesTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_external_tables system table.
+func (et *ExternalTablesTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table_name", Type: sqlTypes.Text, Source: doltdb.ExternalTablesTableName, PrimaryKey: true},
+		{Name: "location", Type: sqlTypes.Text, Source: doltdb.ExternalTablesTableName, PrimaryKey: false, Nullable: false},
+		{Name: "format", Type: sqlTypes.Text, Source: doltdb.ExternalTablesTableName, PrimaryKey: false, Nullable: true},
+		{Name: "columns", Type: sqlTypes.Text, Source: doltdb.ExternalTablesTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+func (et *ExternalTablesTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (et *ExternalTablesTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if et.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return et.backingTable.Partitions(context)
+}
+
+func (et *ExternalTablesTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if et.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+
+	return et.backingTable.PartitionRows(context, partition)
+}
+
+// NewExternalTablesTable creates an ExternalTablesTable
+func NewExternalTablesTable(_ *sql.Context, ddb *doltdb.DoltDB, backingTable sql.Table) sql.Table {
+	return &ExternalTablesTable{ddb: ddb, backingTable: backingTable}
+}
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (et *ExternalTablesTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return newExternalTablesWriter(et)
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (et *ExternalTablesTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return newExternalTablesWriter(et)
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (et *ExternalTablesTable) Inserter(*sql.Context) sql.RowInserter {
+	return newExternalTablesWriter(et)
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (et *ExternalTablesTable) Deleter(*sql.Context) sql.RowDeleter {
+	return newExternalTablesWriter(et)
+}
+
+var _ sql.RowReplacer = (*externalTablesWriter)(nil)
+var _ sql.RowUpdater = (*externalTablesWriter)(nil)
+var _ sql.RowInserter = (*externalTablesWriter)(nil)
+var _ sql.RowDeleter = (*externalTablesWriter)(nil)
+
+type externalTablesWriter struct {
+	et                      *ExternalTablesTable
+	errDuringStatementBegin error
+	prevHash                *hash.Hash
+	tableWriter             writer.TableWriter
+}
+
+func newExternalTablesWriter(et *ExternalTablesTable) *externalTablesWriter {
+	return &externalTablesWriter{et, nil, nil, nil}
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (ew *externalTablesWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := ew.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return ew.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (ew *externalTablesWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := ew.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return ew.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (ew *externalTablesWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := ew.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return ew.tableWriter.Delete(ctx, r)
+}
+
+// StatementBegin is called before the first operation of a statement. Integrators should mark the state of the data
+// in some way that it may be returned to in the case of an error.
+func (ew *externalTablesWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	// TODO: this needs to use a revision qualified name
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		ew.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		ew.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	prevHash, err := roots.Working.HashOf()
+	if err != nil {
+		ew.errDuringStatementBegin = err
+		return
+	}
+
+	ew.prevHash = &prevHash
+
+	found, err := roots.Working.HasTable(ctx, doltdb.ExternalTablesTableName)
+
+	if err != nil {
+		ew.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		// TODO: This is effectively a duplicate of the schema declaration above in a different format.
+		// We should find a way to not repeat ourselves.
+		colCollection := schema.NewColCollection(
+			schema.Column{
+				Name:          "table_name",
+				Tag:           schema.DoltExternalTablesNameTag,
+				Kind:          types.StringKind,
+				IsPartOfPK:    true,
+				TypeInfo:      typeinfo.FromKind(types.StringKind),
+				Default:       "",
+				AutoIncrement: false,
+				Comment:       "",
+				Constraints:   nil,
+			},
+			schema.Column{
+				Name:          "location",
+				Tag:           schema.DoltExternalTablesLocationTag,
+				Kind:          types.StringKind,
+				IsPartOfPK:    false,
+				TypeInfo:      typeinfo.FromKind(types.StringKind),
+				Default:       "",
+				AutoIncrement: false,
+				Comment:       "",
+				Constraints:   nil,
+			},
+			schema.Column{
+				Name:          "format",
+				Tag:           schema.DoltExternalTablesFormatTag,
+				Kind:          types.StringKind,
+				IsPartOfPK:    false,
+				TypeInfo:      typeinfo.FromKind(types.StringKind),
+				Default:       "",
+				AutoIncrement: false,
+				Comment:       "",
+				Constraints:   nil,
+			},
+			schema.Column{
+				Name:          "columns",
+				Tag:           schema.DoltExternalTablesColumnsTag,
+				Kind:          types.StringKind,
+				IsPartOfPK:    false,
+				TypeInfo:      typeinfo.FromKind(types.StringKind),
+				Default:       "",
+				AutoIncrement: false,
+				Comment:       "",
+				Constraints:   nil,
+			},
+		)
+
+		newSchema, err := schema.NewSchema(colCollection, nil, schema.Collation_Default, nil, nil)
+		if err != nil {
+			ew.errDuringStatementBegin = err
+			return
+		}
+
+		// underlying table doesn't exist. Record this, then create the table.
+		newRootValue, err := roots.Working.CreateEmptyTable(ctx, doltdb.ExternalTablesTableName, newSchema)
+
+		if err != nil {
+			ew.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			ew.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// We use WriteSession.SetWorkingSet instead of DoltSession.SetRoot because we want to avoid modifying the root
+		// until the end of the transaction, but we still want the WriteSession to be able to find the newly
+		// created table.
+		err = dbState.WriteSession().SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+		if err != nil {
+			ew.errDuringStatementBegin = err
+			return
+		}
+
+		dSess.SetRoot(ctx, dbName, newRootValue)
+	}
+
+	tableWriter, err := dbState.WriteSession().GetTableWriter(ctx, doltdb.ExternalTablesTableName, dbName, dSess.SetRoot)
+	if err != nil {
+		ew.errDuringStatementBegin = err
+		return
+	}
+
+	ew.tableWriter = tableWriter
+
+	tableWriter.StatementBegin(ctx)
+
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (ew *externalTablesWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if ew.tableWriter != nil {
+		return ew.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+// The mark set in StatementBegin may be removed, and a new one should be created on the next StatementBegin.
+func (ew *externalTablesWriter) StatementComplete(ctx *sql.Context) error {
+	return ew.tableWriter.StatementComplete(ctx)
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (ew externalTablesWriter) Close(ctx *sql.Context) error {
+	if ew.tableWriter != nil {
+		return ew.tableWriter.Close(ctx)
+	}
+	return nil
+}
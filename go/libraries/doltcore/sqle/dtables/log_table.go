@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/prolly"
 )
@@ -84,6 +85,8 @@ func (dt *LogTable) Schema() sql.Schema {
 		{Name: "email", Type: types.Text, Source: doltdb.LogTableName, PrimaryKey: false},
 		{Name: "date", Type: types.Datetime, Source: doltdb.LogTableName, PrimaryKey: false},
 		{Name: "message", Type: types.Text, Source: doltdb.LogTableName, PrimaryKey: false},
+		{Name: "signed", Type: types.Boolean, Source: doltdb.LogTableName, PrimaryKey: false, Nullable: false},
+		{Name: "metadata", Type: types.JSON, Source: doltdb.LogTableName, PrimaryKey: false, Nullable: true},
 	}
 }
 
@@ -227,7 +230,21 @@ func (itr *LogItr) Next(ctx *sql.Context) (sql.Row, error) {
 		return nil, err
 	}
 
-	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description), nil
+	md, err := CommitMetadataValue(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, meta.Signature != "", md), nil
+}
+
+// CommitMetadataValue returns the key-value metadata of the commit with |meta| as a JSON value, or nil if it has none.
+func CommitMetadataValue(meta *datas.CommitMeta) (interface{}, error) {
+	if meta.Metadata == "" {
+		return nil, nil
+	}
+	md, _, err := types.JSON.Convert(meta.Metadata)
+	return md, err
 }
 
 // Close closes the iterator.
@@ -0,0 +1,282 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqlTypes "github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/writer"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var _ sql.Table = (*MergeDriversTable)(nil)
+var _ sql.UpdatableTable = (*MergeDriversTable)(nil)
+var _ sql.DeletableTable = (*MergeDriversTable)(nil)
+var _ sql.InsertableTable = (*MergeDriversTable)(nil)
+var _ sql.ReplaceableTable = (*MergeDriversTable)(nil)
+
+// MergeDriversTable is the system table that assigns merge drivers to columns, or to all columns of a type. Merge
+// drivers resolve cells that were changed on both sides of a three-way merge before a conflict is declared.
+type MergeDriversTable struct {
+	ddb          *doltdb.DoltDB
+	backingTable sql.Table
+}
+
+func (mt *MergeDriversTable) Name() string {
+	return doltdb.MergeDriversTableName
+}
+
+func (mt *MergeDriversTable) String() string {
+	return doltdb.MergeDriversTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the dolt_merge_drivers system table.
+func (mt *MergeDriversTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "target", Type: sqlTypes.Text, Source: doltdb.MergeDriversTableName, PrimaryKey: true},
+		{Name: "driver", Type: sqlTypes.Text, Source: doltdb.MergeDriversTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+func (mt *MergeDriversTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.
+func (mt *MergeDriversTable) Partitions(context *sql.Context) (sql.PartitionIter, error) {
+	if mt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return index.SinglePartitionIterFromNomsMap(nil), nil
+	}
+	return mt.backingTable.Partitions(context)
+}
+
+func (mt *MergeDriversTable) PartitionRows(context *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if mt.backingTable == nil {
+		// no backing table; return an empty iter.
+		return sql.RowsToRowIter(), nil
+	}
+
+	return mt.backingTable.PartitionRows(context, partition)
+}
+
+// NewMergeDriversTable creates a MergeDriversTable
+func NewMergeDriversTable(_ *sql.Context, ddb *doltdb.DoltDB, backingTable sql.Table) sql.Table {
+	return &MergeDriversTable{ddb: ddb, backingTable: backingTable}
+}
+
+// Replacer returns a RowReplacer for this table. The RowReplacer will have Insert and optionally Delete called once
+// for each row, followed by a call to Close() when all rows have been processed.
+func (mt *MergeDriversTable) Replacer(ctx *sql.Context) sql.RowReplacer {
+	return newMergeDriversWriter(mt)
+}
+
+// Updater returns a RowUpdater for this table. The RowUpdater will have Update called once for each row to be
+// updated, followed by a call to Close() when all rows have been processed.
+func (mt *MergeDriversTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return newMergeDriversWriter(mt)
+}
+
+// Inserter returns an Inserter for this table. The Inserter will get one call to Insert() for each row to be
+// inserted, and will end with a call to Close() to finalize the insert operation.
+func (mt *MergeDriversTable) Inserter(*sql.Context) sql.RowInserter {
+	return newMergeDriversWriter(mt)
+}
+
+// Deleter returns a RowDeleter for this table. The RowDeleter will get one call to Delete for each row to be deleted,
+// and will end with a call to Close() to finalize the delete operation.
+func (mt *MergeDriversTable) Deleter(*sql.Context) sql.RowDeleter {
+	return newMergeDriversWriter(mt)
+}
+
+var _ sql.RowReplacer = (*mergeDriversWriter)(nil)
+var _ sql.RowUpdater = (*mergeDriversWriter)(nil)
+var _ sql.RowInserter = (*mergeDriversWriter)(nil)
+var _ sql.RowDeleter = (*mergeDriversWriter)(nil)
+
+type mergeDriversWriter struct {
+	mt                      *MergeDriversTable
+	errDuringStatementBegin error
+	prevHash                *hash.Hash
+	tableWriter             writer.TableWriter
+}
+
+func newMergeDriversWriter(mt *MergeDriversTable) *mergeDriversWriter {
+	return &mergeDriversWriter{mt, nil, nil, nil}
+}
+
+// Insert inserts the row given, returning an error if it cannot. Insert will be called once for each row to process
+// for the insert operation, which may involve many rows. After all rows in an operation have been processed, Close
+// is called.
+func (mw *mergeDriversWriter) Insert(ctx *sql.Context, r sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return mw.tableWriter.Insert(ctx, r)
+}
+
+// Update the given row. Provides both the old and new rows.
+func (mw *mergeDriversWriter) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return mw.tableWriter.Update(ctx, old, new)
+}
+
+// Delete deletes the given row. Returns ErrDeleteRowNotFound if the row was not found. Delete will be called once for
+// each row to process for the delete operation, which may involve many rows. After all rows have been processed,
+// Close is called.
+func (mw *mergeDriversWriter) Delete(ctx *sql.Context, r sql.Row) error {
+	if err := mw.errDuringStatementBegin; err != nil {
+		return err
+	}
+	return mw.tableWriter.Delete(ctx, r)
+}
+
+// StatementBegin is called before the first operation of a statement. Integrators should mark the state of the data
+// in some way that it may be returned to in the case of an error.
+func (mw *mergeDriversWriter) StatementBegin(ctx *sql.Context) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	// TODO: this needs to use a revision qualified name
+	roots, _ := dSess.GetRoots(ctx, dbName)
+	dbState, ok, err := dSess.LookupDbState(ctx, dbName)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+	if !ok {
+		mw.errDuringStatementBegin = fmt.Errorf("no root value found in session")
+		return
+	}
+
+	prevHash, err := roots.Working.HashOf()
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+
+	mw.prevHash = &prevHash
+
+	found, err := roots.Working.HasTable(ctx, doltdb.MergeDriversTableName)
+
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+
+	if !found {
+		// TODO: This is effectively a duplicate of the schema declaration above in a different format.
+		// We should find a way to not repeat ourselves.
+		colCollection := schema.NewColCollection(
+			schema.Column{
+				Name:          "target",
+				Tag:           schema.DoltMergeDriversTargetTag,
+				Kind:          types.StringKind,
+				IsPartOfPK:    true,
+				TypeInfo:      typeinfo.FromKind(types.StringKind),
+				Default:       "",
+				AutoIncrement: false,
+				Comment:       "",
+				Constraints:   nil,
+			},
+			schema.Column{
+				Name:          "driver",
+				Tag:           schema.DoltMergeDriversDriverTag,
+				Kind:          types.StringKind,
+				IsPartOfPK:    false,
+				TypeInfo:      typeinfo.FromKind(types.StringKind),
+				Default:       "",
+				AutoIncrement: false,
+				Comment:       "",
+				Constraints:   nil,
+			},
+		)
+
+		newSchema, err := schema.NewSchema(colCollection, nil, schema.Collation_Default, nil, nil)
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		// underlying table doesn't exist. Record this, then create the table.
+		newRootValue, err := roots.Working.CreateEmptyTable(ctx, doltdb.MergeDriversTableName, newSchema)
+
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		if dbState.WorkingSet() == nil {
+			mw.errDuringStatementBegin = doltdb.ErrOperationNotSupportedInDetachedHead
+			return
+		}
+
+		// We use WriteSession.SetWorkingSet instead of DoltSession.SetRoot because we want to avoid modifying the root
+		// until the end of the transaction, but we still want the WriteSession to be able to find the newly
+		// created table.
+		err = dbState.WriteSession().SetWorkingSet(ctx, dbState.WorkingSet().WithWorkingRoot(newRootValue))
+		if err != nil {
+			mw.errDuringStatementBegin = err
+			return
+		}
+
+		dSess.SetRoot(ctx, dbName, newRootValue)
+	}
+
+	tableWriter, err := dbState.WriteSession().GetTableWriter(ctx, doltdb.MergeDriversTableName, dbName, dSess.SetRoot)
+	if err != nil {
+		mw.errDuringStatementBegin = err
+		return
+	}
+
+	mw.tableWriter = tableWriter
+
+	tableWriter.StatementBegin(ctx)
+
+}
+
+// DiscardChanges is called if a statement encounters an error, and all current changes since the statement beginning
+// should be discarded.
+func (mw *mergeDriversWriter) DiscardChanges(ctx *sql.Context, errorEncountered error) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.DiscardChanges(ctx, errorEncountered)
+	}
+	return nil
+}
+
+// StatementComplete is called after the last operation of the statement, indicating that it has successfully completed.
+// The mark set in StatementBegin may be removed, and a new one should be created on the next StatementBegin.
+func (mw *mergeDriversWriter) StatementComplete(ctx *sql.Context) error {
+	return mw.tableWriter.StatementComplete(ctx)
+}
+
+// Close finalizes the delete operation, persisting the result.
+func (mw mergeDriversWriter) Close(ctx *sql.Context) error {
+	if mw.tableWriter != nil {
+		return mw.tableWriter.Close(ctx)
+	}
+	return nil
+}
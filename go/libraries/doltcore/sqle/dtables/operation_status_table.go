@@ -0,0 +1,129 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"io"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*OperationStatusTable)(nil)
+
+// OperationStatusTable is a sql.Table implementation that implements a system table which shows the progress of the
+// long-running procedures, like DOLT_PUSH() and DOLT_GC(), running in every connection to the server, so that clients
+// can poll it from another connection while a procedure runs.
+type OperationStatusTable struct{}
+
+// NewOperationStatusTable creates an OperationStatusTable
+func NewOperationStatusTable() sql.Table {
+	return &OperationStatusTable{}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// OperationStatusTableName
+func (ot *OperationStatusTable) Name() string {
+	return doltdb.OperationStatusTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// OperationStatusTableName
+func (ot *OperationStatusTable) String() string {
+	return doltdb.OperationStatusTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the operation status system table
+func (ot *OperationStatusTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "id", Type: types.Uint64, Source: doltdb.OperationStatusTableName, PrimaryKey: true, Nullable: false},
+		{Name: "connection_id", Type: types.Uint32, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "user", Type: types.Text, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "database", Type: types.Text, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "operation", Type: types.Text, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "phase", Type: types.Text, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "unit", Type: types.Text, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "processed", Type: types.Uint64, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "total", Type: types.Uint64, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "started", Type: types.Datetime, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "elapsed_secs", Type: types.Float64, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "eta_secs", Type: types.Float64, Source: doltdb.OperationStatusTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (ot *OperationStatusTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (ot *OperationStatusTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (ot *OperationStatusTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	return &operationStatusItr{statuses: dsess.GlobalOperations.Snapshot(), now: time.Now()}, nil
+}
+
+// operationStatusItr is a sql.RowItr implementation which iterates over the status of each running operation
+type operationStatusItr struct {
+	statuses []dsess.OperationStatus
+	now      time.Time
+	idx      int
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+func (itr *operationStatusItr) Next(*sql.Context) (sql.Row, error) {
+	if itr.idx >= len(itr.statuses) {
+		return nil, io.EOF
+	}
+
+	s := itr.statuses[itr.idx]
+	itr.idx++
+
+	var total, eta interface{}
+	if s.Total > 0 {
+		total = s.Total
+	}
+	if d, ok := s.ETA(itr.now); ok {
+		eta = d.Seconds()
+	}
+
+	return sql.NewRow(
+		s.ID,
+		s.ConnectionID,
+		s.User,
+		s.Database,
+		s.Operation,
+		s.Phase,
+		s.Unit,
+		s.Processed,
+		total,
+		s.Started,
+		itr.now.Sub(s.Started).Seconds(),
+		eta,
+	), nil
+}
+
+// Close closes the iterator.
+func (itr *operationStatusItr) Close(*sql.Context) error {
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"io"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*StatementStatsTable)(nil)
+
+// StatementStatsTable is a sql.Table implementation that implements a system table which shows the resources used by
+// the statements run against a database, aggregated by statement digest. Stats are only collected while
+// @@dolt_statement_stats is enabled.
+type StatementStatsTable struct {
+	dbName string
+}
+
+// NewStatementStatsTable creates a StatementStatsTable
+func NewStatementStatsTable(dbName string) sql.Table {
+	return &StatementStatsTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StatementStatsTableName
+func (st *StatementStatsTable) Name() string {
+	return doltdb.StatementStatsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StatementStatsTableName
+func (st *StatementStatsTable) String() string {
+	return doltdb.StatementStatsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the statement stats system table
+func (st *StatementStatsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "statement", Type: types.LongText, Source: doltdb.StatementStatsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "count", Type: types.Uint64, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "total_time_ms", Type: types.Float64, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "avg_time_ms", Type: types.Float64, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "max_time_ms", Type: types.Float64, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "rows_read", Type: types.Uint64, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "rows_written", Type: types.Uint64, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "first_seen", Type: types.Datetime, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "last_seen", Type: types.Datetime, Source: doltdb.StatementStatsTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (st *StatementStatsTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (st *StatementStatsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (st *StatementStatsTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	return &statementStatsItr{stats: dsess.GlobalStatementStats.Snapshot(st.dbName)}, nil
+}
+
+// statementStatsItr is a sql.RowItr implementation which iterates over the stats of each statement digest
+type statementStatsItr struct {
+	stats []dsess.StatementStat
+	idx   int
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+func (itr *statementStatsItr) Next(*sql.Context) (sql.Row, error) {
+	if itr.idx >= len(itr.stats) {
+		return nil, io.EOF
+	}
+
+	stat := itr.stats[itr.idx]
+	itr.idx++
+
+	return sql.NewRow(
+		stat.Statement,
+		stat.Count,
+		durationMillis(stat.TotalTime),
+		durationMillis(stat.TotalTime/time.Duration(stat.Count)),
+		durationMillis(stat.MaxTime),
+		stat.RowsRead,
+		stat.RowsWritten,
+		stat.FirstSeen,
+		stat.LastSeen,
+	), nil
+}
+
+// Close closes the iterator.
+func (itr *statementStatsItr) Close(*sql.Context) error {
+	return nil
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*StorageUsageTable)(nil)
+
+// StorageUsageTable is a sql.Table implementation that implements a system table which shows the storage used by a
+// database and each of its branches, along with their storage quotas. The first row is the database's, with a NULL
+// branch, and reports the size of its table files on disk. The other rows report the size of the row data and indexes
+// of the working set of each branch, as measured for dsess.StorageQuotas.
+type StorageUsageTable struct {
+	dbName string
+	ddb    *doltdb.DoltDB
+}
+
+// NewStorageUsageTable creates a StorageUsageTable
+func NewStorageUsageTable(dbName string, ddb *doltdb.DoltDB) sql.Table {
+	return &StorageUsageTable{dbName: dbName, ddb: ddb}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StorageUsageTableName
+func (st *StorageUsageTable) Name() string {
+	return doltdb.StorageUsageTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StorageUsageTableName
+func (st *StorageUsageTable) String() string {
+	return doltdb.StorageUsageTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the storage usage system table
+func (st *StorageUsageTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "branch", Type: types.Text, Source: doltdb.StorageUsageTableName, PrimaryKey: false, Nullable: true},
+		{Name: "usage_bytes", Type: types.Uint64, Source: doltdb.StorageUsageTableName, PrimaryKey: false, Nullable: false},
+		{Name: "max_bytes", Type: types.Uint64, Source: doltdb.StorageUsageTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (st *StorageUsageTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (st *StorageUsageTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (st *StorageUsageTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	quotas := dsess.DSessFromSess(ctx.Session).StorageQuotas()
+	if quotas == nil {
+		var err error
+		if quotas, err = dsess.NewStorageQuotas(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	dbSize, err := st.ddb.StorageSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := []sql.Row{sql.NewRow(nil, dbSize, maxBytes(quotas.DatabaseQuota(st.dbName)))}
+
+	branches, err := st.ddb.GetBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, branch := range branches {
+		root, err := st.branchRoot(ctx, branch)
+		if err != nil {
+			return nil, err
+		}
+		size, err := quotas.RootSize(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, sql.NewRow(branch.GetPath(), size, maxBytes(quotas.BranchQuota(st.dbName, branch.GetPath()))))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// branchRoot returns the working root of |branch|, or the root of its head if it has no working set.
+func (st *StorageUsageTable) branchRoot(ctx *sql.Context, branch ref.DoltRef) (*doltdb.RootValue, error) {
+	wsRef, err := ref.WorkingSetRefForHead(branch)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := st.ddb.ResolveWorkingSet(ctx, wsRef)
+	if err == nil {
+		return ws.WorkingRoot(), nil
+	} else if err != doltdb.ErrWorkingSetNotFound {
+		return nil, err
+	}
+
+	head, err := st.ddb.ResolveCommitRef(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+	return head.GetRootValue(ctx)
+}
+
+func maxBytes(quota dsess.StorageQuota, ok bool) interface{} {
+	if !ok {
+		return nil
+	}
+	return quota.MaxBytes
+}
@@ -1287,6 +1287,14 @@ func TestMultiDbTransactions(t *testing.T) {
 			enginetest.TestTransactionScript(t, h, script)
 		}()
 	}
+
+	for _, script := range MultiDbAtomicCommitTests {
+		func() {
+			h := newDoltHarness(t)
+			defer h.Close()
+			enginetest.TestTransactionScript(t, h, script)
+		}()
+	}
 }
 
 func TestMultiDbTransactionsPrepared(t *testing.T) {
@@ -2028,6 +2036,78 @@ func TestLogTableFunctionPrepared(t *testing.T) {
 	}
 }
 
+func TestGrepTableFunction(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	for _, test := range GrepTableFunctionScriptTests {
+		harness.engine = nil
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScript(t, harness, test)
+		})
+	}
+}
+
+func TestGrepTableFunctionPrepared(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	for _, test := range GrepTableFunctionScriptTests {
+		harness.engine = nil
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScriptPrepared(t, harness, test)
+		})
+	}
+}
+
+func TestColumnHistoryTableFunction(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	for _, test := range ColumnHistoryTableFunctionScriptTests {
+		harness.engine = nil
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScript(t, harness, test)
+		})
+	}
+}
+
+func TestColumnHistoryTableFunctionPrepared(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	for _, test := range ColumnHistoryTableFunctionScriptTests {
+		harness.engine = nil
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScriptPrepared(t, harness, test)
+		})
+	}
+}
+
+func TestChangesTableFunction(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	for _, test := range ChangesTableFunctionScriptTests {
+		harness.engine = nil
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScript(t, harness, test)
+		})
+	}
+}
+
+func TestChangesTableFunctionPrepared(t *testing.T) {
+	harness := newDoltHarness(t)
+	defer harness.Close()
+	harness.Setup(setup.MydbData)
+	for _, test := range ChangesTableFunctionScriptTests {
+		harness.engine = nil
+		t.Run(test.Name, func(t *testing.T) {
+			enginetest.TestScriptPrepared(t, harness, test)
+		})
+	}
+}
+
 func TestCommitDiffSystemTable(t *testing.T) {
 	harness := newDoltHarness(t)
 	defer harness.Close()
@@ -2656,6 +2736,26 @@ func TestDoltVerifyConstraints(t *testing.T) {
 	}
 }
 
+func TestDoltRepairConstraints(t *testing.T) {
+	for _, script := range DoltRepairConstraintsTestScripts {
+		func() {
+			harness := newDoltHarness(t)
+			defer harness.Close()
+			enginetest.TestScript(t, harness, script)
+		}()
+	}
+}
+
+func TestDoltRepairConstraintsPrepared(t *testing.T) {
+	for _, script := range DoltRepairConstraintsTestScripts {
+		func() {
+			harness := newDoltHarness(t)
+			defer harness.Close()
+			enginetest.TestScriptPrepared(t, harness, script)
+		}()
+	}
+}
+
 func TestDoltStorageFormat(t *testing.T) {
 	var expectedFormatString string
 	if types.IsFormat_DOLT(types.Format_Default) {
@@ -181,6 +181,7 @@ func (d *DoltHarness) NewEngine(t *testing.T) (*gms.Engine, error) {
 			return nil, err
 		}
 		e.Analyzer.ExecBuilder = rowexec.DefaultBuilder
+		sqle.AddAnalyzerRules(e.Analyzer)
 		d.engine = e
 
 		ctx := enginetest.NewContext(d)
@@ -606,6 +606,55 @@ var DoltRevisionDbScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "database revision specs: joins across databases pinned to different revisions",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c varchar(20));",
+			"insert into t values (1, 'one'), (2, 'two');",
+			"call dolt_commit('-Am', 'creating table t');",
+			"call dolt_tag('v1');",
+			"insert into t values (3, 'three');",
+			"call dolt_commit('-am', 'adding a row to t');",
+			"create database otherdb;",
+			"use otherdb;",
+			"create table u (pk int primary key, d varchar(20));",
+			"insert into u values (1, 'uno'), (2, 'dos'), (3, 'tres');",
+			"call dolt_commit('-Am', 'creating table u');",
+			"call dolt_checkout('-b', 'feature');",
+			"update u set d = 'DOS' where pk = 2;",
+			"call dolt_commit('-am', 'updating a row in u');",
+			"call dolt_checkout('main');",
+			"insert into u values (4, 'cuatro');",
+			"use mydb;",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select t.pk, t.c, u.d from t as of 'v1' join `otherdb/feature`.u on t.pk = u.pk order by 1;",
+				Expected: []sql.Row{{1, "one", "uno"}, {2, "two", "DOS"}},
+			},
+			{
+				Query:    "select t.pk, u2.d from `mydb/v1`.t join otherdb.u as of 'feature' u2 on t.pk = u2.pk order by 1;",
+				Expected: []sql.Row{{1, "uno"}, {2, "DOS"}},
+			},
+			{
+				Query:    "select t.pk, u1.d, u2.d from t join otherdb.u as of 'main' u1 on t.pk = u1.pk join `otherdb/feature`.u u2 on t.pk = u2.pk where u1.d <> u2.d;",
+				Expected: []sql.Row{{2, "dos", "DOS"}},
+			},
+			{
+				// the working and staged roots are those of the table's database, not the current one
+				Query:    "select count(*) from otherdb.u as of 'WORKING';",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select count(*) from otherdb.u as of 'STAGED';",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "select count(*) from t as of 'WORKING' join otherdb.u as of 'WORKING' on t.pk = u.pk;",
+				Expected: []sql.Row{{3}},
+			},
+		},
+	},
 }
 
 // DoltScripts are script tests specific to Dolt (not the engine in general), e.g. by involving Dolt functions. Break
@@ -987,6 +1036,197 @@ var DoltScripts = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_default_as_of session variable",
+		SetUpScript: []string{
+			"create table default_as_of_t (pk int primary key, c int);",
+			"call dolt_add('.');",
+			"insert into default_as_of_t values (1, 1);",
+			"call dolt_commit('-am', 'first');",
+			"insert into default_as_of_t values (2, 2);",
+			"call dolt_commit('-am', 'second');",
+			"create table default_as_of_u (pk int primary key);",
+			"call dolt_add('.');",
+			"call dolt_commit('-am', 'third');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "set @@dolt_default_as_of = 'HEAD~1';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select * from default_as_of_t;",
+				Expected: []sql.Row{{1, 1}, {2, 2}},
+			},
+			{
+				Query:       "select * from default_as_of_u;",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:    "show tables like 'default_as_of%';",
+				Expected: []sql.Row{{"default_as_of_t"}},
+			},
+			{
+				Query:    "show tables as of 'HEAD' like 'default_as_of%';",
+				Expected: []sql.Row{{"default_as_of_t"}, {"default_as_of_u"}},
+			},
+			{
+				Query:    "select * from default_as_of_t as of 'HEAD~2';",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "set @@dolt_default_as_of = 'HEAD~2';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select * from default_as_of_t;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "set @@dolt_default_as_of = '1970-01-01 00:00:00';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:       "select * from default_as_of_t;",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:    "set @@dolt_default_as_of = '';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select count(*) from default_as_of_t;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select count(*) from default_as_of_u;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "show tables like 'default_as_of%';",
+				Expected: []sql.Row{{"default_as_of_t"}, {"default_as_of_u"}},
+			},
+		},
+	},
+	{
+		Name: "dolt_default_as_of doesn't apply to writes, DDL or system tables",
+		SetUpScript: []string{
+			"create table default_as_of_w (pk int primary key, c int);",
+			"call dolt_add('.');",
+			"insert into default_as_of_w values (1, 1);",
+			"call dolt_commit('-am', 'first');",
+			"insert into default_as_of_w values (2, 2);",
+			"call dolt_commit('-am', 'second');",
+			"set @@dolt_default_as_of = 'HEAD~1';",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "insert into default_as_of_w values (3, 3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "update default_as_of_w set c = 20 where pk = 2;",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "delete from default_as_of_w where pk = 1;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				// the rows inserted are read as of the default
+				Query:    "insert into default_as_of_w select pk + 10, c from default_as_of_w;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "alter table default_as_of_w add column d int;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "create table default_as_of_x (pk int primary key);",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into default_as_of_x values (1);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from default_as_of_w;",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "select * from dolt_status order by table_name;",
+				Expected: []sql.Row{{"default_as_of_w", false, "modified"}, {"default_as_of_x", false, "new table"}},
+			},
+			{
+				Query:    "select message from dolt_log limit 1;",
+				Expected: []sql.Row{{"second"}},
+			},
+			{
+				Query:    "select count(*) from dolt_diff_default_as_of_w where to_commit = 'WORKING';",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "set @@dolt_default_as_of = '';",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "select * from default_as_of_w order by pk;",
+				Expected: []sql.Row{{2, 20, nil}, {3, 3, nil}, {11, 1, nil}},
+			},
+			{
+				Query:    "select * from default_as_of_x;",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "CALL DOLT_COMMIT('--metadata') attaches key-value metadata to the commit",
+		SetUpScript: []string{
+			"CREATE table tickets (pk int primary key);",
+			"CALL DOLT_ADD('tickets');",
+			`CALL DOLT_COMMIT('-m', 'add table tickets', '--metadata', '{"ticket": "ABC-123", "score": 0.95}');`,
+			"INSERT INTO tickets VALUES (1);",
+			"CALL DOLT_COMMIT('-am', 'insert into tickets');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select message, metadata from dolt_log where message = 'add table tickets'",
+				Expected: []sql.Row{{"add table tickets", types.MustJSON(`{"score": 0.95, "ticket": "ABC-123"}`)}},
+			},
+			{
+				Query:    "select message, metadata is null from dolt_log limit 1",
+				Expected: []sql.Row{{"insert into tickets", true}},
+			},
+			{
+				Query:    "select message from dolt_log where json_unquote(json_extract(metadata, '$.ticket')) = 'ABC-123'",
+				Expected: []sql.Row{{"add table tickets"}},
+			},
+			{
+				Query:    "select json_extract(metadata, '$.ticket') from dolt_log() where message = 'add table tickets'",
+				Expected: []sql.Row{{types.MustJSON(`"ABC-123"`)}},
+			},
+			{
+				Query:            `CALL DOLT_COMMIT('--amend', '--metadata', '{"ticket": "ABC-456"}');`,
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select message, json_unquote(json_extract(metadata, '$.ticket')) from dolt_log limit 1",
+				Expected: []sql.Row{{"insert into tickets", "ABC-456"}},
+			},
+			{
+				Query:            "CALL DOLT_COMMIT('--amend', '-m', 'insert a row into tickets');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "select message, json_unquote(json_extract(metadata, '$.ticket')) from dolt_log limit 1",
+				Expected: []sql.Row{{"insert a row into tickets", "ABC-456"}},
+			},
+			{
+				Query:          `CALL DOLT_COMMIT('--allow-empty', '-m', 'bad metadata', '--metadata', '["ABC-123"]');`,
+				ExpectedErrStr: `commit metadata must be a JSON object: ["ABC-123"]`,
+			},
+		},
+	},
 }
 
 func makeLargeInsert(sz int) string {
@@ -1379,6 +1619,104 @@ var DoltUserPrivTests = []queries.UserPrivilegeTest{
 			},
 		},
 	},
+	{
+		Name: "dolt_login privilege checking",
+		SetUpScript: []string{
+			"CREATE USER tester@localhost;",
+			"GRANT ALL ON mydb.* TO tester@localhost;",
+		},
+		Assertions: []queries.UserPrivilegeTestAssertion{
+			{
+				// Without SUPER, credentials can't be registered for every user
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL dolt_login('--server', 'abcdefghijklmnopqrstuvwxyz');",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				// Without SUPER, credentials can't be checked against another auth endpoint
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL dolt_login('--auth-endpoint', 'localhost:1', 'abcdefghijklmnopqrstuvwxyz');",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				// Without SUPER, credentials can't be checked without TLS
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL dolt_login('--insecure', 'abcdefghijklmnopqrstuvwxyz');",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+		},
+	},
+	{
+		Name: "dolt_retention privilege checking",
+		SetUpScript: []string{
+			"CREATE USER tester@localhost;",
+			"GRANT ALL ON mydb.* TO tester@localhost;",
+		},
+		Assertions: []queries.UserPrivilegeTestAssertion{
+			{
+				// Without SUPER, the history of every branch can't be squashed
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL dolt_retention('--keep-days', '30');",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_retention('--keep-days', '30');",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "dolt_purge_history privilege checking",
+		SetUpScript: []string{
+			"CREATE USER tester@localhost;",
+			"GRANT ALL ON mydb.* TO tester@localhost;",
+		},
+		Assertions: []queries.UserPrivilegeTestAssertion{
+			{
+				// Without SUPER, the history of every branch can't be rewritten
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL dolt_purge_history('--before', '2000-01-01', 't');",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "CALL dolt_purge_history('--before', '2000-01-01', 't');",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "external table privilege checking",
+		SetUpScript: []string{
+			"INSERT INTO dolt_external_tables VALUES ('people', 'nope://bucket/people.csv', 'csv', 'id int, name text');",
+			"CREATE USER tester@localhost;",
+			"GRANT ALL ON mydb.* TO tester@localhost;",
+		},
+		Assertions: []queries.UserPrivilegeTestAssertion{
+			{
+				// Without FILE or SUPER, the files the server has access to can't be read
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT * FROM people;",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				// root passes the privilege check, and the location is checked next
+				User:           "root",
+				Host:           "localhost",
+				Query:          "SELECT * FROM people;",
+				ExpectedErrStr: "external table location 'nope://bucket/people.csv' is not allowed: the nope scheme is not enabled in external_table.schemes",
+			},
+		},
+	},
 }
 
 // HistorySystemTableScriptTests contains working tests for both prepared and non-prepared
@@ -3056,6 +3394,451 @@ var LogTableFunctionScriptTests = []queries.ScriptTest{
 			},
 		},
 	},*/
+	{
+		Name: "filtering by table",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"create table u (pk int primary key);",
+			"call dolt_add('.')",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'creating tables');",
+
+			"insert into u values (1);",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'inserting into u');",
+
+			"call dolt_checkout('-b', 'branch1');",
+			"insert into t values (1);",
+			"set @Commit3 = '';",
+			"call dolt_commit_hash_out(@Commit3, '-am', 'inserting into t on branch1');",
+
+			"call dolt_checkout('main');",
+			"insert into u values (2);",
+			"call dolt_commit('-am', 'inserting into u on main');",
+			"call dolt_merge('branch1', '--no-ff', '-m', 'merging branch1');",
+
+			"drop table u;",
+			"call dolt_commit('-am', 'dropping u');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT commit_hash = @Commit3, commit_hash = @Commit1 from dolt_log('--', 't');",
+				Expected: []sql.Row{{true, false}, {false, true}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('main~', '--', 'u');",
+				Expected: []sql.Row{{"inserting into u on main"}, {"inserting into u"}, {"creating tables"}},
+			},
+			{
+				// u was dropped on main, so it doesn't exist where the log starts
+				Query:       "SELECT message from dolt_log('main', '--', 'u');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:    "SELECT message from dolt_log('branch1', '--', 'u');",
+				Expected: []sql.Row{{"inserting into u"}, {"creating tables"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('branch1..main', '--', 't');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT message from dolt_log(@Commit2, '--', 't');",
+				Expected: []sql.Row{{"creating tables"}},
+			},
+			{
+				Query:       "SELECT count(*) from dolt_log('--', 'nonexistent');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:       "SELECT * from dolt_log('--', 't', 'u');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_log('main', '--');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+		},
+	},
+	{
+		Name: "filtering and paging",
+		SetUpScript: []string{
+			"create table t (pk int primary key);",
+			"call dolt_add('.');",
+			"call dolt_commit('-am', 'creating table t', '--date', '2023-01-01T12:00:00');",
+			"insert into t values (1);",
+			"call dolt_commit('-am', 'inserting 1', '--date', '2023-02-01T12:00:00', '--author', 'John Doe <johndoe@example.com>');",
+			"insert into t values (2);",
+			"call dolt_commit('-am', 'inserting 2', '--date', '2023-03-01T12:00:00');",
+			"insert into t values (3);",
+			"call dolt_commit('-am', 'inserting 3', '--date', '2023-04-01T12:00:00', '--author', 'John Doe <johndoe@example.com>');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT message from dolt_log('--author', 'John Doe');",
+				Expected: []sql.Row{{"inserting 3"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('main', '--author', '<johndoe@example.com>$');",
+				Expected: []sql.Row{{"inserting 3"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--since', '2023-02-01', '--until', '2023-03-15');",
+				Expected: []sql.Row{{"inserting 2"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--since', '2023-02-01', '--author', 'John');",
+				Expected: []sql.Row{{"inserting 3"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--number', '2');",
+				Expected: []sql.Row{{"inserting 3"}, {"inserting 2"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--number', '2', '--skip', '1');",
+				Expected: []sql.Row{{"inserting 2"}, {"inserting 1"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('main', '--since', '2023-01-01', '--until', '2023-12-31', '--skip', '3');",
+				Expected: []sql.Row{{"creating table t"}},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--since', '2023-01-01', '--skip', '10');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--number', '0');",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT message from dolt_log('--author', 'John', '--number', '1', '--', 't');",
+				Expected: []sql.Row{{"inserting 3"}},
+			},
+			{
+				Query:       "SELECT * from dolt_log('--since', 'yesterday');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_log('--author', '(');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "SELECT * from dolt_log('--number', 'ten');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+		},
+	},
+}
+
+var GrepTableFunctionScriptTests = []queries.ScriptTest{
+	{
+		Name: "search values across history",
+		SetUpScript: []string{
+			"create table people (id int primary key, email varchar(100), name varchar(100));",
+			"call dolt_add('.');",
+			"insert into people values (1, 'alice@example.com', 'alice'), (2, 'bob@example.com', 'bob');",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'adding people');",
+			"update people set email = 'bob@example.org' where id = 2;",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'updating bob');",
+			"create table other (id int primary key);",
+			"call dolt_add('.');",
+			"set @Commit3 = '';",
+			"call dolt_commit_hash_out(@Commit3, '-am', 'adding other');",
+			"delete from people where id = 1;",
+			"set @Commit4 = '';",
+			"call dolt_commit_hash_out(@Commit4, '-am', 'deleting alice');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select commit_hash = @Commit3, commit_hash = @Commit2, commit_hash = @Commit1, column_name, value from dolt_grep('alice@', 'people');",
+				Expected: []sql.Row{{true, false, false, "email", "alice@example.com"}, {false, true, false, "email", "alice@example.com"}, {false, false, true, "email", "alice@example.com"}},
+			},
+			{
+				Query:    "select commit_hash = @Commit1, column_name, value from dolt_grep('^bob@example\\.com$', 'people');",
+				Expected: []sql.Row{{true, "email", "bob@example.com"}},
+			},
+			{
+				Query:    "select column_name, value from dolt_grep('bob', 'people', @Commit1);",
+				Expected: []sql.Row{{"email", "bob@example.com"}, {"name", "bob"}},
+			},
+			{
+				Query:    "select column_name, value from dolt_grep('^2$', 'people', 'HEAD~3');",
+				Expected: []sql.Row{{"id", "2"}},
+			},
+			{
+				Query:    "select count(*) from dolt_grep('carol', 'people');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "select * from dolt_grep('bob', 'nonexistent');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:       "select * from dolt_grep('(', 'people');",
+				ExpectedErr: sql.ErrInvalidArgumentDetails,
+			},
+			{
+				Query:       "select * from dolt_grep('bob');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+		},
+	},
+	{
+		Name: "search values shared by rows and across schema changes",
+		SetUpScript: []string{
+			"create table pets (id int primary key, name varchar(100));",
+			"call dolt_add('.');",
+			"insert into pets values (1, 'rex'), (2, 'rex'), (3, 'fido');",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'adding pets');",
+			"delete from pets where id = 1;",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'deleting a rex');",
+			"update pets set name = 'max' where id = 2;",
+			"set @Commit3 = '';",
+			"call dolt_commit_hash_out(@Commit3, '-am', 'renaming the other rex');",
+			"alter table pets add column owner varchar(100);",
+			"update pets set owner = 'rexford' where id = 3;",
+			"set @Commit4 = '';",
+			"call dolt_commit_hash_out(@Commit4, '-am', 'adding owners');",
+			"insert into pets values (4, 'rex', null), (5, 'rex', null);",
+			"set @Commit5 = '';",
+			"call dolt_commit_hash_out(@Commit5, '-am', 'adding more rexes');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select commit_hash = @Commit5, commit_hash = @Commit4, commit_hash = @Commit2, commit_hash = @Commit1, column_name, value from dolt_grep('rex', 'pets');",
+				Expected: []sql.Row{
+					{true, false, false, false, "name", "rex"},
+					{true, false, false, false, "owner", "rexford"},
+					{false, true, false, false, "owner", "rexford"},
+					{false, false, true, false, "name", "rex"},
+					{false, false, false, true, "name", "rex"},
+				},
+			},
+		},
+	},
+}
+
+var ColumnHistoryTableFunctionScriptTests = []queries.ScriptTest{
+	{
+		Name: "changes to a column across history",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20), c2 int);",
+			"call dolt_add('.');",
+			"insert into t values (1, 'a', 1), (2, 'b', 2);",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'inserting rows');",
+			"update t set c2 = 10 where pk = 1;",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'updating c2');",
+			"update t set c1 = 'z' where pk = 2;",
+			"set @Commit3 = '';",
+			"call dolt_commit_hash_out(@Commit3, '-am', 'updating c1');",
+			"delete from t where pk = 1;",
+			"set @Commit4 = '';",
+			"call dolt_commit_hash_out(@Commit4, '-am', 'deleting a row');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select commit_hash = @Commit4, commit_hash = @Commit3, commit_hash = @Commit1, primary_key, diff_type, from_value, to_value from dolt_column_history('t', 'c1');",
+				Expected: []sql.Row{
+					{true, false, false, "1", "removed", "a", nil},
+					{false, true, false, "2", "modified", "b", "z"},
+					{false, false, true, "1", "added", nil, "a"},
+					{false, false, true, "2", "added", nil, "b"},
+				},
+			},
+			{
+				Query:    "select commit_hash = @Commit3, message, diff_type, from_value, to_value from dolt_column_history('t', 'c1', 2);",
+				Expected: []sql.Row{{true, "updating c1", "modified", "b", "z"}, {false, "inserting rows", "added", nil, "b"}},
+			},
+			{
+				Query:    "select commit_hash = @Commit2, from_value, to_value from dolt_column_history('T', 'C2', '1') where diff_type = 'modified';",
+				Expected: []sql.Row{{true, "1", "10"}},
+			},
+			{
+				Query:    "select count(*) from dolt_column_history('t', 'c2');",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select count(*) from dolt_column_history('t', 'c1', 3);",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "select * from dolt_column_history('nonexistent', 'c1');",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:       "select * from dolt_column_history('t', 'nonexistent');",
+				ExpectedErr: sql.ErrTableColumnNotFound,
+			},
+			{
+				Query:          "select * from dolt_column_history('t', 'pk');",
+				ExpectedErrStr: "column 'pk' is part of the primary key of table 't'",
+			},
+			{
+				Query:          "select * from dolt_column_history('t', 'c1', 1, 2);",
+				ExpectedErrStr: "dolt_column_history expects 1 primary key value(s), got 2",
+			},
+			{
+				Query:       "select * from dolt_column_history('t');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+		},
+	},
+	{
+		Name: "changes merged from another branch are reported once",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"call dolt_add('.');",
+			"insert into t values (1, 1);",
+			"call dolt_commit('-am', 'inserting a row');",
+			"call dolt_checkout('-b', 'other');",
+			"update t set c = 2 where pk = 1;",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'updating on other');",
+			"call dolt_checkout('main');",
+			"insert into t values (2, 5);",
+			"call dolt_commit('-am', 'inserting on main');",
+			"call dolt_merge('other');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select commit_hash = @Commit2, diff_type, from_value, to_value from dolt_column_history('t', 'c', 1);",
+				Expected: []sql.Row{{true, "modified", "1", "2"}, {false, "added", nil, "1"}},
+			},
+			{
+				Query:    "select count(*) from dolt_column_history('t', 'c');",
+				Expected: []sql.Row{{3}},
+			},
+		},
+	},
+}
+
+var ChangesTableFunctionScriptTests = []queries.ScriptTest{
+	{
+		Name: "row changes across history",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c1 varchar(20), c2 int);",
+			"call dolt_add('.');",
+			"set @Commit0 = '';",
+			"call dolt_commit_hash_out(@Commit0, '-am', 'creating table');",
+			"insert into t values (1, 'a', 1), (2, 'b', 2);",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'inserting rows');",
+			"update t set c2 = 10 where pk = 1;",
+			"set @Commit2 = '';",
+			"call dolt_commit_hash_out(@Commit2, '-am', 'updating a row');",
+			"delete from t where pk = 2;",
+			"set @Commit3 = '';",
+			"call dolt_commit_hash_out(@Commit3, '-am', 'deleting a row');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select commit_hash = @Commit1, commit_hash = @Commit2, commit_hash = @Commit3, op, primary_key, `before`, `after` from dolt_changes('t', @Commit0);",
+				Expected: []sql.Row{
+					{true, false, false, "insert", types.MustJSON(`{"pk": 1}`), nil, types.MustJSON(`{"pk": 1, "c1": "a", "c2": 1}`)},
+					{true, false, false, "insert", types.MustJSON(`{"pk": 2}`), nil, types.MustJSON(`{"pk": 2, "c1": "b", "c2": 2}`)},
+					{false, true, false, "update", types.MustJSON(`{"pk": 1}`), types.MustJSON(`{"pk": 1, "c1": "a", "c2": 1}`), types.MustJSON(`{"pk": 1, "c1": "a", "c2": 10}`)},
+					{false, false, true, "delete", types.MustJSON(`{"pk": 2}`), types.MustJSON(`{"pk": 2, "c1": "b", "c2": 2}`), nil},
+				},
+			},
+			{
+				Query:    "select commit_hash = @Commit3, op from dolt_changes('t', @Commit2);",
+				Expected: []sql.Row{{true, "delete"}},
+			},
+			{
+				Query:    "select commit_hash = @Commit2, op from dolt_changes('T', @Commit1, @Commit2);",
+				Expected: []sql.Row{{true, "update"}},
+			},
+			{
+				Query:    "select count(*) from dolt_changes('t', NULL);",
+				Expected: []sql.Row{{4}},
+			},
+			{
+				Query:    "select count(*) from dolt_changes('t', 'HEAD');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "select * from dolt_changes('t', @Commit2, @Commit1);",
+				ExpectedErr: sqle.ErrChangesNotAncestor,
+			},
+			{
+				Query:       "select * from dolt_changes('nonexistent', @Commit0);",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:       "select * from dolt_changes('t');",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+		},
+	},
+	{
+		Name: "changes merged from another branch are reported at the merge",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"call dolt_add('.');",
+			"insert into t values (1, 1);",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'inserting a row');",
+			"call dolt_checkout('-b', 'other');",
+			"update t set c = 2 where pk = 1;",
+			"call dolt_commit('-am', 'updating on other');",
+			"call dolt_checkout('main');",
+			"insert into t values (2, 5);",
+			"call dolt_commit('-am', 'inserting on main');",
+			"call dolt_merge('other', '--no-ff', '-m', 'merging other');",
+			"set @Merge = hashof('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "select commit_hash = @Merge, op, primary_key from dolt_changes('t', @Commit1);",
+				Expected: []sql.Row{
+					{false, "insert", types.MustJSON(`{"pk": 2}`)},
+					{true, "update", types.MustJSON(`{"pk": 1}`)},
+				},
+			},
+		},
+	},
+	{
+		Name: "dropped tables",
+		SetUpScript: []string{
+			"create table t (pk int primary key, c int);",
+			"insert into t values (1, 1);",
+			"call dolt_add('.');",
+			"set @Commit1 = '';",
+			"call dolt_commit_hash_out(@Commit1, '-am', 'creating table');",
+			"drop table t;",
+			"call dolt_commit('-am', 'dropping table');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select op, `before`, `after` from dolt_changes('t', @Commit1);",
+				Expected: []sql.Row{{"delete", types.MustJSON(`{"pk": 1, "c": 1}`), nil}},
+			},
+			{
+				Query:    "select op, `before`, `after` from dolt_changes('t', NULL, @Commit1);",
+				Expected: []sql.Row{{"insert", nil, types.MustJSON(`{"pk": 1, "c": 1}`)}},
+			},
+		},
+	},
+	{
+		Name: "keyless tables",
+		SetUpScript: []string{
+			"create table t (c int);",
+			"call dolt_add('.');",
+			"call dolt_commit('-am', 'creating table');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "select * from dolt_changes('t', NULL);",
+				ExpectedErrStr: "row changes are not supported for keyless table 't'",
+			},
+		},
+	},
 }
 
 var LargeJsonObjectScriptTests = []queries.ScriptTest{
@@ -1386,6 +1386,81 @@ inner join t on to_pk = t.pk;`,
 			},
 		},
 	},
+	{
+		Name: "diff between two databases",
+		SetUpScript: []string{
+			"create database otherdb;",
+			"use otherdb;",
+			"create table t (pk int primary key, c1 text);",
+			"insert into t values (1, 'a'), (2, 'b');",
+			"call dolt_add('.');",
+			"call dolt_commit('-m', 'creating t in otherdb');",
+			"use mydb;",
+			"create table t (pk int primary key, c1 text);",
+			"insert into t values (1, 'a'), (2, 'z'), (3, 'c');",
+			"call dolt_add('.');",
+			"call dolt_commit('-m', 'creating t in mydb');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select from_pk, from_c1, to_pk, to_c1, diff_type from dolt_diff('otherdb/main', 'main', 't') order by coalesce(to_pk, from_pk);",
+				Expected: []sql.Row{{2, "b", 2, "z", "modified"}, {nil, nil, 3, "c", "added"}},
+			},
+			{
+				Query:    "select from_pk, from_c1, to_pk, to_c1, diff_type from dolt_diff('mydb/main', 'otherdb/main', 't') order by coalesce(to_pk, from_pk);",
+				Expected: []sql.Row{{2, "z", 2, "b", "modified"}, {3, "c", nil, nil, "removed"}},
+			},
+			{
+				Query:    "select from_pk, to_pk, diff_type from dolt_diff('otherdb/main..main', 't');",
+				Expected: []sql.Row{{2, 2, "modified"}, {nil, 3, "added"}},
+			},
+		},
+	},
+	{
+		Name: "diff limited to a range of primary keys",
+		SetUpScript: []string{
+			"create table t (a int, b int, c int, primary key (a, b));",
+			"create table k (x int);",
+			"insert into t values (1, 1, 0), (1, 2, 0), (2, 1, 0), (3, 1, 0);",
+			"call dolt_add('.');",
+			"call dolt_commit('-m', 'inserting rows');",
+			"update t set c = 1;",
+			"delete from t where a = 2;",
+			"insert into t values (4, 1, 0);",
+			"insert into k values (1);",
+			"call dolt_commit('-am', 'updating rows');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "select to_a, to_b, from_a, from_b, diff_type from dolt_diff('HEAD~', 'HEAD', 't', 1, 1);",
+				Expected: []sql.Row{{1, 1, 1, 1, "modified"}, {1, 2, 1, 2, "modified"}},
+			},
+			{
+				Query:    "select to_a, to_b, from_a, from_b, diff_type from dolt_diff('HEAD~..HEAD', 't', 2, NULL);",
+				Expected: []sql.Row{{nil, nil, 2, 1, "removed"}, {3, 1, 3, 1, "modified"}, {4, 1, nil, nil, "added"}},
+			},
+			{
+				Query:    "select to_a, to_b, diff_type from dolt_diff('HEAD~', 'HEAD', 't', '3', 10);",
+				Expected: []sql.Row{{3, 1, "modified"}, {4, 1, "added"}},
+			},
+			{
+				Query:    "select count(*) from dolt_diff('HEAD~', 'HEAD', 't', NULL, NULL);",
+				Expected: []sql.Row{{5}},
+			},
+			{
+				Query:    "select count(*) from dolt_diff('HEAD~', 'HEAD', 't', 5, NULL);",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:       "select * from dolt_diff('HEAD~', 'HEAD', 't', 1);",
+				ExpectedErr: sql.ErrInvalidArgumentNumber,
+			},
+			{
+				Query:          "select * from dolt_diff('HEAD~', 'HEAD', 'k', 1, 1);",
+				ExpectedErrStr: "key ranges in dolt_diff are not supported for keyless tables",
+			},
+		},
+	},
 }
 
 var DiffStatTableFunctionScriptTests = []queries.ScriptTest{
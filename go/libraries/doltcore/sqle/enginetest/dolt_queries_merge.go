@@ -50,6 +50,68 @@ type MergeScriptTest struct {
 }
 
 var MergeScripts = []queries.ScriptTest{
+	{
+		Name: "CALL DOLT_MERGE with --dry-run reports conflicts without modifying the working set",
+		SetUpScript: []string{
+			"CREATE TABLE test (pk int primary key, c int)",
+			"INSERT INTO test VALUES (0, 0), (1, 1);",
+			"CALL DOLT_COMMIT('-Am', 'Step 1');",
+			"CALL DOLT_BRANCH('ff-branch')",
+			"CALL DOLT_BRANCH('clean-branch')",
+			"CALL DOLT_BRANCH('conflict-branch')",
+			"CALL DOLT_CHECKOUT('clean-branch');",
+			"INSERT INTO test VALUES (2, 2);",
+			"CALL DOLT_COMMIT('-am', 'clean change');",
+			"CALL DOLT_CHECKOUT('conflict-branch');",
+			"UPDATE test SET c = 10 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'conflicting change');",
+			"CALL DOLT_CHECKOUT('main');",
+			"UPDATE test SET c = 100 WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'main change');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('--dry-run', 'conflict-branch')",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "CALL DOLT_MERGE('--dry-run', 'clean-branch')",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "SELECT is_merging FROM DOLT_MERGE_STATUS;",
+				Expected: []sql.Row{{false}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_conflicts",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT * FROM dolt_status",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1",
+				Expected: []sql.Row{{"main change"}},
+			},
+			{
+				Query:    "SELECT * FROM test ORDER BY pk",
+				Expected: []sql.Row{{0, 0}, {1, 100}},
+			},
+			{
+				Query:    "CALL DOLT_CHECKOUT('ff-branch')",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "CALL DOLT_MERGE('--dry-run', 'main')",
+				Expected: []sql.Row{{"", 1, 0}},
+			},
+			{
+				Query:    "SELECT message FROM dolt_log LIMIT 1",
+				Expected: []sql.Row{{"Step 1"}},
+			},
+		},
+	},
 	{
 		Name: "CALL DOLT_MERGE ff correctly works with autocommit off",
 		SetUpScript: []string{
@@ -1414,6 +1476,48 @@ var MergeScripts = []queries.ScriptTest{
 }
 
 var Dolt1MergeScripts = []queries.ScriptTest{
+	{
+		Name: "merge --verify-fks finds violations in rows the merge didn't change",
+		SetUpScript: []string{
+			"SET dolt_force_transaction_commit = on;",
+			"CREATE TABLE parent (pk int PRIMARY KEY);",
+			"CREATE TABLE child (pk int PRIMARY KEY, parent_fk int, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1);",
+			"SET foreign_key_checks = 0;",
+			"INSERT INTO child VALUES (1, 1), (2, 2);",
+			"SET foreign_key_checks = 1;",
+			"CALL DOLT_COMMIT('-Am', 'orphaned child');",
+			"CALL DOLT_BRANCH('other');",
+			"CALL DOLT_BRANCH('other2');",
+			"INSERT INTO parent VALUES (3);",
+			"CALL DOLT_COMMIT('-am', 'main');",
+			"CALL DOLT_CHECKOUT('other');",
+			"INSERT INTO parent VALUES (4);",
+			"CALL DOLT_COMMIT('-am', 'other');",
+			"CALL DOLT_CHECKOUT('other2');",
+			"INSERT INTO parent VALUES (5);",
+			"CALL DOLT_COMMIT('-am', 'other2');",
+			"CALL DOLT_CHECKOUT('main');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "CALL DOLT_MERGE('other');",
+				Expected: []sql.Row{{"", 0, 0}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_constraint_violations;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "CALL DOLT_MERGE('other2', '--verify-fks');",
+				Expected: []sql.Row{{"", 0, 1}},
+			},
+			{
+				Query:    "SELECT violation_type, pk, parent_fk FROM dolt_constraint_violations_child;",
+				Expected: []sql.Row{{uint64(merge.CvType_ForeignKey), 2, 2}},
+			},
+		},
+	},
 	{
 		Name: "dropping constraint from one branch drops from both",
 		SetUpScript: []string{
@@ -4033,6 +4137,174 @@ var DoltVerifyConstraintsTestScripts = []queries.ScriptTest{
 	},
 }
 
+var DoltRepairConstraintsTestScripts = []queries.ScriptTest{
+	{
+		Name: "repair-constraints: delete orphaned child rows",
+		SetUpScript: []string{
+			"SET dolt_force_transaction_commit = on;",
+			"CREATE TABLE parent (pk int PRIMARY KEY);",
+			"CREATE TABLE child (pk int PRIMARY KEY, parent_fk int, INDEX (parent_fk), FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1), (2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"DELETE FROM parent WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'delete parent 1');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO child VALUES (1, 1), (2, 1), (3, 2);",
+			"CALL DOLT_COMMIT('-am', 'insert children');",
+			"CALL DOLT_CHECKOUT('main');",
+			"CALL DOLT_MERGE('right');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT count(*) FROM dolt_constraint_violations_child;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "CALL DOLT_REPAIR_CONSTRAINTS('--delete-orphans');",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "SELECT * FROM child;",
+				Expected: []sql.Row{{3, 2}},
+			},
+			{
+				Query:    "SELECT * FROM child WHERE parent_fk = 1;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT * FROM dolt_constraint_violations;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT * FROM dolt_status WHERE staged = false;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:            "CALL DOLT_COMMIT('-m', 'merge right');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "SELECT * FROM child;",
+				Expected: []sql.Row{{3, 2}},
+			},
+		},
+	},
+	{
+		Name: "repair-constraints: nullify broken foreign keys",
+		SetUpScript: []string{
+			"SET dolt_force_transaction_commit = on;",
+			"CREATE TABLE parent (pk int PRIMARY KEY);",
+			"CREATE TABLE child (pk int PRIMARY KEY, parent_fk int, v int, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1), (2);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"DELETE FROM parent WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'delete parent 1');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO child VALUES (1, 1, 10), (2, 2, 20);",
+			"CALL DOLT_COMMIT('-am', 'insert children');",
+			"CALL DOLT_CHECKOUT('main');",
+			"CALL DOLT_MERGE('right');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_REPAIR_CONSTRAINTS('--delete-orphans', '--nullify-fks');",
+				ExpectedErrStr: "specify only either --delete-orphans or --nullify-fks",
+			},
+			{
+				Query:          "CALL DOLT_REPAIR_CONSTRAINTS();",
+				ExpectedErrStr: "specify at least one of --delete-orphans, --nullify-fks or --dedupe-unique",
+			},
+			{
+				Query:    "CALL DOLT_REPAIR_CONSTRAINTS('--nullify-fks', 'child');",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT * FROM child;",
+				Expected: []sql.Row{{1, nil, 10}, {2, 2, 20}},
+			},
+			{
+				Query:    "SELECT pk FROM child WHERE parent_fk IS NULL;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_constraint_violations;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
+	{
+		Name: "repair-constraints: nullify a NOT NULL foreign key column",
+		SetUpScript: []string{
+			"SET dolt_force_transaction_commit = on;",
+			"CREATE TABLE parent (pk int PRIMARY KEY);",
+			"CREATE TABLE child (pk int PRIMARY KEY, parent_fk int NOT NULL, FOREIGN KEY (parent_fk) REFERENCES parent(pk));",
+			"INSERT INTO parent VALUES (1);",
+			"CALL DOLT_COMMIT('-Am', 'setup');",
+			"CALL DOLT_BRANCH('right');",
+			"DELETE FROM parent WHERE pk = 1;",
+			"CALL DOLT_COMMIT('-am', 'delete parent 1');",
+			"CALL DOLT_CHECKOUT('right');",
+			"INSERT INTO child VALUES (1, 1);",
+			"CALL DOLT_COMMIT('-am', 'insert child');",
+			"CALL DOLT_CHECKOUT('main');",
+			"CALL DOLT_MERGE('right');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:          "CALL DOLT_REPAIR_CONSTRAINTS('--nullify-fks');",
+				ExpectedErrStr: "can not nullify foreign key column 'parent_fk', it is not nullable",
+			},
+			{
+				Query:    "SELECT count(*) FROM dolt_constraint_violations_child;",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
+	{
+		Name: "repair-constraints: dedupe unique key violations",
+		SetUpScript: []string{
+			"SET dolt_force_transaction_commit = on;",
+			"CREATE TABLE t (pk int PRIMARY KEY, col1 int UNIQUE, col2 int);",
+			"CALL DOLT_COMMIT('-Am', 'create table');",
+			"CALL DOLT_CHECKOUT('-b', 'right');",
+			"INSERT INTO t VALUES (2, 1, 2), (3, 3, 3), (5, 5, 5);",
+			"CALL DOLT_COMMIT('-am', 'right insert');",
+			"CALL DOLT_CHECKOUT('main');",
+			"INSERT INTO t VALUES (1, 1, 1), (4, 4, 4), (6, 5, 6);",
+			"CALL DOLT_COMMIT('-am', 'left insert');",
+			"CALL DOLT_MERGE('right');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT pk, col1 FROM dolt_constraint_violations_t;",
+				Expected: []sql.Row{{1, 1}, {2, 1}, {5, 5}, {6, 5}},
+			},
+			{
+				Query:    "CALL DOLT_REPAIR_CONSTRAINTS('--delete-orphans');",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "CALL DOLT_REPAIR_CONSTRAINTS('--dedupe-unique');",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "SELECT * FROM t;",
+				Expected: []sql.Row{{1, 1, 1}, {3, 3, 3}, {4, 4, 4}, {5, 5, 5}},
+			},
+			{
+				Query:    "SELECT * FROM dolt_constraint_violations;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT pk FROM t WHERE col1 = 5;",
+				Expected: []sql.Row{{5}},
+			},
+		},
+	},
+}
+
 var errTmplNoAutomaticMerge = "table %s can't be automatically merged.\nTo merge this table, make the schema on the source and target branch equal."
 
 var ThreeWayMergeWithSchemaChangeTestScripts = []MergeScriptTest{
@@ -21,6 +21,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
 )
 
 var DoltTransactionTests = []queries.TransactionTest{
@@ -755,6 +756,266 @@ var DoltTransactionTests = []queries.TransactionTest{
 			},
 		},
 	},
+	{
+		Name: "snapshot session reads are pinned across other clients' commits",
+		SetUpScript: []string{
+			"create table t (x int primary key)",
+			"insert into t values (1)",
+			"call dolt_commit('-Am', 'first row')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ select dolt_snapshot_commit()",
+				Expected: []sql.Row{{nil}},
+			},
+			{
+				Query:    "/* client a */ set @@dolt_snapshot_session = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client b */ insert into t values (2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:            "/* client b */ call dolt_commit('-am', 'second row')",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "/* client a */ select dolt_snapshot_commit() = (select commit_hash from dolt_log limit 1)",
+				Expected: []sql.Row{{true}},
+			},
+			{
+				Query:    "/* client a */ select message from dolt_log limit 1",
+				Expected: []sql.Row{{"first row"}},
+			},
+			{
+				Query:       "/* client a */ insert into t values (3)",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:    "/* client a */ set @@dolt_snapshot_session = 0",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "/* client a */ select dolt_snapshot_commit()",
+				Expected: []sql.Row{{nil}},
+			},
+		},
+	},
+	{
+		Name: "bulk load defers commits until it's flushed",
+		SetUpScript: []string{
+			"create table t (x int primary key)",
+			"call dolt_commit('-Am', 'create t')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ set @@dolt_bulk_load = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (1)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ select * from t order by x",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ set @@dolt_bulk_load = 0",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:    "/* client a */ set @@dolt_bulk_load = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:            "/* client a */ call dolt_commit('-am', 'bulk load')",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+			{
+				Query:    "/* client b */ select message from dolt_log limit 1",
+				Expected: []sql.Row{{"bulk load"}},
+			},
+		},
+	},
+	{
+		Name: "bulk load that fails to flush is rolled back",
+		SetUpScript: []string{
+			"create table t (pk int primary key, col1 int)",
+			"insert into t values (1, 1)",
+			"call dolt_commit('-Am', 'create t')",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ set @@dolt_bulk_load = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ update t set col1 = -100 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ update t set col1 = 100 where pk = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:       "/* client a */ set @@dolt_bulk_load = 0",
+				ExpectedErr: sql.ErrLockDeadlock,
+			},
+			{
+				Query:    "/* client a */ select @@dolt_bulk_load",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "/* client a */ select * from t order by pk",
+				Expected: []sql.Row{{1, 100}},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2, 2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ select * from t order by pk",
+				Expected: []sql.Row{{1, 100}, {2, 2}},
+			},
+		},
+	},
+	{
+		Name: "metadata locks serialize DDL with reads and writes of the same table",
+		SetUpScript: []string{
+			"create table t (x int primary key)",
+			"create table other (x int primary key)",
+			"insert into t values (1)",
+			"set global dolt_metadata_locks = 1",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client b */ set lock_wait_timeout = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ set lock_wait_timeout = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into t values (2)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:       "/* client b */ alter table t add column y int",
+				ExpectedErr: globalstate.ErrMetadataLockWaitTimeout,
+			},
+			{
+				Query:    "/* client b */ alter table other add column y int",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ alter table t add column y int",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ update t set y = 1 where x = 1",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:       "/* client b */ drop table t",
+				ExpectedErr: globalstate.ErrMetadataLockWaitTimeout,
+			},
+			{
+				Query:       "/* client b */ rename table t to t2",
+				ExpectedErr: globalstate.ErrMetadataLockWaitTimeout,
+			},
+			{
+				Query:       "/* client b */ truncate table t",
+				ExpectedErr: globalstate.ErrMetadataLockWaitTimeout,
+			},
+			{
+				Query:    "/* client b */ insert into t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, nil}, {3, 3}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ select x from t where x = 1",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:       "/* client b */ alter table t drop column y",
+				ExpectedErr: globalstate.ErrMetadataLockWaitTimeout,
+			},
+			{
+				Query:    "/* client b */ select * from t order by x",
+				Expected: []sql.Row{{1, 1}, {2, nil}, {3, 3}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ alter table t drop column y",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "/* client a */ set global dolt_metadata_locks = 0",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
 }
 
 var DoltConflictHandlingTests = []queries.TransactionTest{
@@ -2864,6 +3125,43 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 			"call dolt_branch('b1')",
 			"set autocommit = 0",
 		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "insert into t1 values (1)",
+				Expected: []sql.Row{
+					{types.OkResult{RowsAffected: 1}},
+				},
+			},
+			{
+				Query: "insert into `mydb/b1`.t1 values (2)",
+				Expected: []sql.Row{
+					{types.OkResult{RowsAffected: 1}},
+				},
+			},
+			{
+				Query:    "commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t1 order by a",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from `mydb/b1`.t1 order by a",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+	{
+		Name: "committing to more than one branch at a time with dolt_transaction_commit",
+		SetUpScript: []string{
+			"create table t1 (a int)",
+			"call dolt_add('.')",
+			"call dolt_commit('-am', 'new table')",
+			"call dolt_branch('b1')",
+			"set autocommit = 0",
+			"set dolt_transaction_commit = 1",
+		},
 		Assertions: []queries.ScriptTestAssertion{
 			{
 				Query: "insert into t1 values (1)",
@@ -2910,8 +3208,16 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 				},
 			},
 			{
-				Query:          "commit",
-				ExpectedErrStr: "Cannot commit changes on more than one branch / database",
+				Query:    "commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t1 order by a",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select * from `mydb/main`.t1 order by a",
+				Expected: []sql.Row{{1}},
 			},
 		},
 	},
@@ -2939,8 +3245,113 @@ var MultiDbTransactionTests = []queries.ScriptTest{
 				},
 			},
 			{
-				Query:          "commit",
-				ExpectedErrStr: "Cannot commit changes on more than one branch / database",
+				Query:    "commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select * from t1 order by a",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select * from db2.t1 order by a",
+				Expected: []sql.Row{{2}},
+			},
+		},
+	},
+}
+
+var MultiDbAtomicCommitTests = []queries.TransactionTest{
+	{
+		Name: "committing to more than one database is all or nothing",
+		SetUpScript: []string{
+			"create database db1",
+			"create database db2",
+			"create table db1.t (x int primary key, y int)",
+			"insert into db1.t values (1, 1)",
+			"create table db2.t (x int primary key, y int)",
+			"insert into db2.t values (2, 2)",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "/* client a */ set autocommit = off",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client b */ set autocommit = off",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into db1.t values (3, 3)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ insert into db2.t values (4, 4)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client b */ select * from db1.t order by x",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:    "/* client a */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ select * from db1.t order by x",
+				Expected: []sql.Row{{1, 1}, {3, 3}},
+			},
+			{
+				Query:    "/* client b */ select * from db2.t order by x",
+				Expected: []sql.Row{{2, 2}, {4, 4}},
+			},
+			{
+				Query:    "/* client a */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client b */ start transaction",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "/* client a */ insert into db1.t values (5, 5)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "/* client a */ update db2.t set y = 40 where x = 4",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ update db2.t set y = 400 where x = 4",
+				Expected: []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "/* client b */ commit",
+				Expected: []sql.Row{},
+			},
+			{
+				// the conflict in db2 keeps the change to db1 from being committed too
+				Query:          "/* client a */ commit",
+				ExpectedErrStr: sql.ErrLockDeadlock.New(dsess.ErrRetryTransaction.Error()).Error(),
+			},
+			{
+				Query:    "/* client a */ select * from db1.t order by x",
+				Expected: []sql.Row{{1, 1}, {3, 3}},
+			},
+			{
+				Query:    "/* client a */ select * from db2.t order by x",
+				Expected: []sql.Row{{2, 2}, {4, 400}},
 			},
 		},
 	},
@@ -0,0 +1,543 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/xitongsys/parquet-go-source/local"
+	pqparquet "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	externalCsvFormat     = "csv"
+	externalParquetFormat = "parquet"
+)
+
+// ExternalTable is a read-only table whose rows are read from a csv or parquet file each time it's scanned. External
+// tables are defined by the rows of dolt_external_tables, so their definitions are versioned with the rest of the
+// database, but their data is not. To snapshot the data of an external table, insert it into a dolt table.
+type ExternalTable struct {
+	def    doltdb.ExternalTableDef
+	format string
+	sch    sql.Schema
+}
+
+var _ sql.Table = (*ExternalTable)(nil)
+
+// getExternalTable returns the external table named |tableName| defined in |root|, if there is one
+func (db Database) getExternalTable(ctx *sql.Context, root *doltdb.RootValue, tableName string) (sql.Table, bool, error) {
+	def, ok, err := doltdb.GetExternalTableDef(ctx, root, tableName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	tbl, err := NewExternalTable(ctx, def)
+	if err != nil {
+		return nil, false, err
+	}
+	return tbl, true, nil
+}
+
+// NewExternalTable returns the external table defined by |def|. If |def| doesn't declare its columns, they are read
+// from the file: the header of a csv file, whose columns are all LONGTEXT, or the schema of a parquet file.
+func NewExternalTable(ctx *sql.Context, def doltdb.ExternalTableDef) (*ExternalTable, error) {
+	format, err := externalFormat(def)
+	if err != nil {
+		return nil, err
+	}
+
+	var sch sql.Schema
+	if strings.TrimSpace(def.Columns) != "" {
+		sch, err = parseExternalColumns(ctx, def)
+	} else if format == externalCsvFormat {
+		sch, err = csvExternalSchema(ctx, def)
+	} else {
+		sch, err = parquetExternalSchema(ctx, def)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, col := range sch {
+		col.Source = def.Name
+		col.PrimaryKey = false
+	}
+	return &ExternalTable{def: def, format: format, sch: sch}, nil
+}
+
+// externalFormat returns the format of the external table |def|, which is its extension if it isn't given
+func externalFormat(def doltdb.ExternalTableDef) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(def.Format))
+	if format == "" {
+		locPath := def.Location
+		if u, err := url.Parse(def.Location); err == nil && u.Scheme != "" {
+			locPath = u.Path
+		}
+		format = strings.TrimPrefix(strings.ToLower(path.Ext(locPath)), ".")
+	}
+
+	switch format {
+	case externalCsvFormat, externalParquetFormat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("external table %s has unsupported format '%s', expected csv or parquet", def.Name, format)
+	}
+}
+
+// parseExternalColumns parses the column definitions of the external table |def|
+func parseExternalColumns(ctx *sql.Context, def doltdb.ExternalTableDef) (sql.Schema, error) {
+	stmt, err := sqlparser.Parse(fmt.Sprintf("CREATE TABLE t (%s)", def.Columns))
+	if err != nil {
+		return nil, fmt.Errorf("invalid columns for external table %s: %w", def.Name, err)
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil {
+		return nil, fmt.Errorf("invalid columns for external table %s", def.Name)
+	}
+
+	pkSch, _, err := parse.TableSpecToSchema(ctx, ddl.TableSpec, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid columns for external table %s: %w", def.Name, err)
+	}
+	return pkSch.Schema, nil
+}
+
+// csvExternalSchema returns a LONGTEXT column for each column in the header of the csv file of |def|
+func csvExternalSchema(ctx *sql.Context, def doltdb.ExternalTableDef) (sql.Schema, error) {
+	rd, err := openExternalCsv(ctx, def)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close(ctx)
+
+	var sch sql.Schema
+	for _, col := range rd.GetSchema().GetAllCols().GetColumns() {
+		sch = append(sch, &sql.Column{Name: col.Name, Type: gmstypes.LongText, Nullable: true})
+	}
+	return sch, nil
+}
+
+// parquetExternalSchema returns the columns of the parquet file of |def|
+func parquetExternalSchema(ctx *sql.Context, def doltdb.ExternalTableDef) (sch sql.Schema, err error) {
+	filePath, cleanup, err := localExternalFile(ctx, def.Location)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	fr, err := local.NewLocalFileReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetColumnReader(fr, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.ReadStop()
+
+	// the first element is the root of the schema
+	for _, el := range pr.SchemaHandler.SchemaElements[1:] {
+		if el.GetNumChildren() > 0 {
+			return nil, fmt.Errorf("external table %s: nested parquet column %s is not supported", def.Name, el.GetName())
+		}
+		typ, err := parquetColumnType(el)
+		if err != nil {
+			return nil, fmt.Errorf("external table %s: %w", def.Name, err)
+		}
+		sch = append(sch, &sql.Column{Name: el.GetName(), Type: typ, Nullable: true})
+	}
+	return sch, nil
+}
+
+// parquetColumnType returns the sql type of the parquet column |el|
+func parquetColumnType(el *pqparquet.SchemaElement) (sql.Type, error) {
+	switch el.GetType() {
+	case pqparquet.Type_BOOLEAN:
+		return gmstypes.Boolean, nil
+	case pqparquet.Type_INT32:
+		return gmstypes.Int32, nil
+	case pqparquet.Type_INT64:
+		if el.IsSetConvertedType() && el.GetConvertedType() == pqparquet.ConvertedType_TIMESTAMP_MICROS {
+			return gmstypes.Datetime, nil
+		}
+		return gmstypes.Int64, nil
+	case pqparquet.Type_FLOAT:
+		return gmstypes.Float32, nil
+	case pqparquet.Type_DOUBLE:
+		return gmstypes.Float64, nil
+	case pqparquet.Type_BYTE_ARRAY, pqparquet.Type_FIXED_LEN_BYTE_ARRAY:
+		if el.IsSetConvertedType() && el.GetConvertedType() == pqparquet.ConvertedType_UTF8 {
+			return gmstypes.LongText, nil
+		}
+		return gmstypes.LongBlob, nil
+	default:
+		return nil, fmt.Errorf("parquet column %s has unsupported type %s", el.GetName(), el.GetType().String())
+	}
+}
+
+func (t *ExternalTable) Name() string {
+	return t.def.Name
+}
+
+func (t *ExternalTable) String() string {
+	return t.def.Name
+}
+
+// Schema returns the columns declared by the definition of this table, or read from its file
+func (t *ExternalTable) Schema() sql.Schema {
+	return t.sch
+}
+
+func (t *ExternalTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions returns a single partition, the whole file
+func (t *ExternalTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows reads the rows of the file, converting each value to the type of its column. Columns of the table
+// that aren't in the file are null.
+func (t *ExternalTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	var rd table.SqlRowReader
+	var cleanup func()
+	var err error
+	if t.format == externalCsvFormat {
+		rd, err = openExternalCsv(ctx, t.def)
+		cleanup = func() {}
+	} else {
+		rd, cleanup, err = t.openParquet(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fileCols := rd.GetSchema().GetAllCols().GetColumns()
+	fileIdx := make([]int, len(t.sch))
+	for i, col := range t.sch {
+		fileIdx[i] = -1
+		for j, fileCol := range fileCols {
+			if strings.EqualFold(col.Name, fileCol.Name) {
+				fileIdx[i] = j
+				break
+			}
+		}
+	}
+
+	return &externalRowIter{tbl: t, rd: rd, fileIdx: fileIdx, cleanup: cleanup}, nil
+}
+
+// openParquet opens a reader of the columns of this table that are in its parquet file
+func (t *ExternalTable) openParquet(ctx *sql.Context) (table.SqlRowReader, func(), error) {
+	filePath, cleanup, err := localExternalFile(ctx, t.def.Location)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fileSch, err := parquetExternalSchema(ctx, doltdb.ExternalTableDef{Name: t.def.Name, Location: filePath})
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	var readSch sql.Schema
+	for _, col := range fileSch {
+		if t.sch.IndexOfColName(col.Name) >= 0 {
+			readSch = append(readSch, col)
+		}
+	}
+	doltSch, err := sqlutil.ToDoltResultSchema(readSch)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	rd, err := parquet.OpenParquetReader(nil, filePath, doltSch)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return rd, cleanup, nil
+}
+
+type externalRowIter struct {
+	tbl     *ExternalTable
+	rd      table.SqlRowReader
+	fileIdx []int
+	cleanup func()
+	rowNum  int
+}
+
+var _ sql.RowIter = (*externalRowIter)(nil)
+
+func (itr *externalRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	r, err := itr.rd.ReadSqlRow(ctx)
+	if err == io.EOF {
+		return nil, io.EOF
+	} else if table.IsBadRow(err) {
+		return nil, fmt.Errorf("external table %s: row %d: %s", itr.tbl.def.Name, itr.rowNum+1, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	itr.rowNum++
+
+	ret := make(sql.Row, len(itr.tbl.sch))
+	for i, col := range itr.tbl.sch {
+		if itr.fileIdx[i] < 0 || r[itr.fileIdx[i]] == nil {
+			continue
+		}
+		ret[i], _, err = col.Type.Convert(r[itr.fileIdx[i]])
+		if err != nil {
+			return nil, fmt.Errorf("external table %s: row %d: column %s: %w", itr.tbl.def.Name, itr.rowNum, col.Name, err)
+		}
+	}
+	return ret, nil
+}
+
+func (itr *externalRowIter) Close(ctx *sql.Context) error {
+	defer itr.cleanup()
+	return itr.rd.Close(ctx)
+}
+
+// openExternalCsv opens a reader of the csv file of |def|
+func openExternalCsv(ctx *sql.Context, def doltdb.ExternalTableDef) (*csv.CSVReader, error) {
+	rc, err := openExternalLocation(ctx, def.Location)
+	if err != nil {
+		return nil, err
+	}
+	return csv.NewCSVReader(types.Format_Default, rc, csv.NewCSVInfo())
+}
+
+// localExternalFile returns the path of a local copy of the file at |location|, downloading it to a temporary file if
+// it isn't local. The returned func removes the copy.
+func localExternalFile(ctx *sql.Context, location string) (string, func(), error) {
+	if filePath, ok := localExternalPath(location); ok {
+		return filePath, func() {}, nil
+	}
+
+	rc, err := openExternalLocation(ctx, location)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rc.Close()
+
+	f, err := os.CreateTemp("", "dolt-external-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		os.Remove(f.Name())
+	}
+	_, err = io.Copy(f, rc)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return f.Name(), cleanup, nil
+}
+
+// localExternalPath returns the path of |location| if it's a local file, either a path or a file:// url
+func localExternalPath(location string) (string, bool) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// paths without a scheme, including windows paths with a drive letter
+		return location, true
+	} else if u.Scheme == "file" {
+		return u.Path, true
+	}
+	return "", false
+}
+
+// externalLocationScheme returns the scheme of |location|, which is file for local paths
+func externalLocationScheme(location string) string {
+	if _, ok := localExternalPath(location); ok {
+		return "file"
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Scheme)
+}
+
+// checkExternalLocation returns an error unless the current user may read the file at |location|. Like LOAD DATA,
+// reading the files the server has access to requires the FILE or SUPER privilege, and local files must be in the
+// @@secure_file_priv directory if it's set. The scheme of |location| must also be one of those an admin enabled in
+// the external_table.schemes config.
+func checkExternalLocation(ctx *sql.Context, location string) error {
+	if branchAwareSession := branch_control.GetBranchAwareSession(ctx); branchAwareSession != nil {
+		// the privilege set is cached in the session when the privileges on the database of the table are checked
+		privSet, counter := branchAwareSession.GetPrivilegeSet()
+		if counter == 0 || (!privSet.Has(sql.PrivilegeType_File) && !privSet.Has(sql.PrivilegeType_Super)) {
+			return sql.ErrPrivilegeCheckFailed.New(fmt.Sprintf("'%s'@'%s'", branchAwareSession.GetUser(), branchAwareSession.GetHost()))
+		}
+	}
+
+	// the config is loaded from the home directory of the server, which SQL users can't change
+	cfg, err := env.LoadDoltCliConfig(env.GetCurrentUserHomeDir, filesys.LocalFS)
+	if err != nil {
+		return err
+	}
+	schemes := cfg.GetStringOrDefault(env.ExternalTableSchemes, "")
+	_, secureFilePriv, _ := sql.SystemVariables.GetGlobal("secure_file_priv")
+	secureFilePrivStr, _ := secureFilePriv.(string)
+	return externalLocationAllowed(location, schemes, secureFilePrivStr)
+}
+
+// externalLocationAllowed returns an error unless the scheme of |location| is one of the comma separated |schemes|,
+// and, if it's a local file and |secureFilePriv| isn't empty, it's in the |secureFilePriv| directory.
+func externalLocationAllowed(location, schemes, secureFilePriv string) error {
+	scheme := externalLocationScheme(location)
+	enabled := false
+	for _, s := range strings.Split(schemes, ",") {
+		if strings.EqualFold(strings.TrimSpace(s), scheme) {
+			enabled = true
+			break
+		}
+	}
+	if !enabled {
+		return fmt.Errorf("external table location '%s' is not allowed: the %s scheme is not enabled in %s", location, scheme, env.ExternalTableSchemes)
+	}
+
+	filePath, ok := localExternalPath(location)
+	if !ok || secureFilePriv == "" {
+		return nil
+	}
+	// symlinks are resolved so that they can't point outside of the directory
+	dir, err := filepath.EvalSymlinks(secureFilePriv)
+	if err != nil {
+		return err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	file, err := filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return err
+	}
+	file, err = filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(dir, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("external table location '%s' is not allowed: it's outside of the secure_file_priv directory %s", location, secureFilePriv)
+	}
+	return nil
+}
+
+// openExternalLocation opens the file at |location|, which is a local path, or a file://, s3://, gs://, http:// or
+// https:// url. Credentials for s3 and gs are found the same way as for remotes in those object stores.
+func openExternalLocation(ctx *sql.Context, location string) (io.ReadCloser, error) {
+	if err := checkExternalLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	if filePath, ok := localExternalPath(location); ok {
+		return os.Open(filePath)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+
+	switch strings.ToLower(u.Scheme) {
+	case "s3":
+		sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+		if err != nil {
+			return nil, err
+		}
+		out, err := s3.New(sess).GetObjectWithContext(ctx, &s3.GetObjectInput{Bucket: aws.String(u.Host), Key: aws.String(key)})
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", location, err)
+		}
+		return out.Body, nil
+	case "gs":
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rd, err := client.Bucket(u.Host).Object(key).NewReader(ctx)
+		if err != nil {
+			client.Close()
+			return nil, fmt.Errorf("could not read %s: %w", location, err)
+		}
+		return gcsReadCloser{rd, client}, nil
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("could not read %s: %s", location, resp.Status)
+		}
+		return resp.Body, nil
+	default:
+		return nil, fmt.Errorf("unsupported external table location '%s', expected a path or a file, s3, gs, http or https url", location)
+	}
+}
+
+// gcsReadCloser closes the client of an object reader along with the reader
+type gcsReadCloser struct {
+	*storage.Reader
+	client *storage.Client
+}
+
+func (rc gcsReadCloser) Close() error {
+	err := rc.Reader.Close()
+	if cerr := rc.client.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func readExternalTable(t *testing.T, ctx *sql.Context, tbl *ExternalTable) []sql.Row {
+	parts, err := tbl.Partitions(ctx)
+	require.NoError(t, err)
+	part, err := parts.Next(ctx)
+	require.NoError(t, err)
+	iter, err := tbl.PartitionRows(ctx, part)
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(ctx, nil, iter)
+	require.NoError(t, err)
+	return rows
+}
+
+func TestExternalCsvTable(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	home := t.TempDir()
+	t.Setenv("DOLT_ROOT_PATH", home)
+	cfg, err := env.LoadDoltCliConfig(env.GetCurrentUserHomeDir, filesys.LocalFS)
+	require.NoError(t, err)
+	require.NoError(t, cfg.WriteableConfig().SetStrings(map[string]string{env.ExternalTableSchemes: "file"}))
+	path := filepath.Join(t.TempDir(), "people.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,name,age\n1,ada,36\n2,alan,\n"), 0644))
+
+	tbl, err := NewExternalTable(ctx, doltdb.ExternalTableDef{Name: "people", Location: path})
+	require.NoError(t, err)
+	assert.Equal(t, sql.Schema{
+		{Name: "id", Type: gmstypes.LongText, Nullable: true, Source: "people"},
+		{Name: "name", Type: gmstypes.LongText, Nullable: true, Source: "people"},
+		{Name: "age", Type: gmstypes.LongText, Nullable: true, Source: "people"},
+	}, tbl.Schema())
+	assert.Equal(t, []sql.Row{{"1", "ada", "36"}, {"2", "alan", nil}}, readExternalTable(t, ctx, tbl))
+
+	// declared columns are converted to their types, and columns that aren't in the file are null
+	tbl, err = NewExternalTable(ctx, doltdb.ExternalTableDef{
+		Name:     "people",
+		Location: "file://" + filepath.ToSlash(path),
+		Format:   "CSV",
+		Columns:  "id int primary key, age int, email varchar(100)",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int32(1), int32(36), nil}, {int32(2), nil, nil}}, readExternalTable(t, ctx, tbl))
+
+	_, err = NewExternalTable(ctx, doltdb.ExternalTableDef{Name: "people", Location: path, Columns: "id int,"})
+	assert.Error(t, err)
+}
+
+func TestExternalFormat(t *testing.T) {
+	tests := []struct {
+		def      doltdb.ExternalTableDef
+		expected string
+	}{
+		{doltdb.ExternalTableDef{Location: "/data/a.csv"}, "csv"},
+		{doltdb.ExternalTableDef{Location: "s3://bucket/dir/a.PARQUET"}, "parquet"},
+		{doltdb.ExternalTableDef{Location: "https://example.com/a.csv?token=abc"}, "csv"},
+		{doltdb.ExternalTableDef{Location: "gs://bucket/a", Format: "parquet"}, "parquet"},
+	}
+	for _, test := range tests {
+		format, err := externalFormat(test.def)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, format, test.def.Location)
+	}
+
+	_, err := externalFormat(doltdb.ExternalTableDef{Name: "t", Location: "s3://bucket/a.json"})
+	assert.EqualError(t, err, "external table t has unsupported format 'json', expected csv or parquet")
+}
+
+func TestLocalExternalPath(t *testing.T) {
+	for location, expected := range map[string]string{
+		"data/a.csv":         "data/a.csv",
+		"/data/a.csv":        "/data/a.csv",
+		`C:\data\a.csv`:      `C:\data\a.csv`,
+		"file:///data/a.csv": "/data/a.csv",
+	} {
+		p, ok := localExternalPath(location)
+		assert.True(t, ok, location)
+		assert.Equal(t, expected, p)
+	}
+
+	for _, location := range []string{"s3://bucket/a.csv", "gs://bucket/a.csv", "https://example.com/a.csv"} {
+		_, ok := localExternalPath(location)
+		assert.False(t, ok, location)
+	}
+}
+
+func TestExternalLocationAllowed(t *testing.T) {
+	dir := t.TempDir()
+	secureDir := filepath.Join(dir, "secure")
+	require.NoError(t, os.Mkdir(secureDir, 0755))
+	inside := filepath.Join(secureDir, "a.csv")
+	outside := filepath.Join(dir, "b.csv")
+	for _, p := range []string{inside, outside} {
+		require.NoError(t, os.WriteFile(p, []byte("a\n1\n"), 0644))
+	}
+	link := filepath.Join(secureDir, "link.csv")
+	require.NoError(t, os.Symlink(outside, link))
+
+	// only the schemes that are enabled are allowed
+	assert.NoError(t, externalLocationAllowed(outside, "file", ""))
+	assert.NoError(t, externalLocationAllowed("file://"+filepath.ToSlash(outside), "s3, FILE", ""))
+	assert.NoError(t, externalLocationAllowed("s3://bucket/a.csv", "file,s3", ""))
+	assert.Error(t, externalLocationAllowed(outside, "", ""))
+	assert.Error(t, externalLocationAllowed(outside, "s3,gs", ""))
+	assert.Error(t, externalLocationAllowed("https://example.com/a.csv", "file,http", ""))
+
+	// local files must be in the secure_file_priv directory, if it's set
+	assert.NoError(t, externalLocationAllowed(inside, "file", secureDir))
+	assert.Error(t, externalLocationAllowed(outside, "file", secureDir))
+	assert.Error(t, externalLocationAllowed(filepath.Join(secureDir, "..", "b.csv"), "file", secureDir))
+	assert.Error(t, externalLocationAllowed(link, "file", secureDir))
+	assert.NoError(t, externalLocationAllowed("s3://bucket/a.csv", "s3", secureDir))
+}
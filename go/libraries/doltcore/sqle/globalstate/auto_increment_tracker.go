@@ -20,6 +20,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -85,6 +86,14 @@ func (a AutoIncrementTracker) Current(tableName string) uint64 {
 // Next returns the next auto increment value for the table named using the provided value from an insert (which may
 // be null or 0, in which case it will be generated from the sequence).
 func (a AutoIncrementTracker) Next(tbl string, insertVal interface{}) (uint64, error) {
+	return a.NextInterleaved(tbl, insertVal, 1, 1)
+}
+
+// NextInterleaved is like Next, but generates values from the interleaved sequence |offset|, |offset| + |increment|,
+// |offset| + 2*|increment|, ..., like MySQL does for the auto_increment_increment and auto_increment_offset system
+// variables. Giving each clone of a database, or each server writing to it, a different offset keeps the rows they
+// insert independently from colliding on their primary keys when their branches are merged.
+func (a AutoIncrementTracker) NextInterleaved(tbl string, insertVal interface{}, increment, offset uint64) (uint64, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -99,8 +108,9 @@ func (a AutoIncrementTracker) Next(tbl string, insertVal interface{}) (uint64, e
 
 	if given == 0 {
 		// |given| is 0 or NULL
-		a.sequences[tbl]++
-		return curr, nil
+		next := interleavedAutoIncrementValue(curr, increment, offset)
+		a.sequences[tbl] = next + 1
+		return next, nil
 	}
 
 	if given >= curr {
@@ -113,6 +123,41 @@ func (a AutoIncrementTracker) Next(tbl string, insertVal interface{}) (uint64, e
 	return given, nil
 }
 
+// interleavedAutoIncrementValue returns the smallest value of the sequence |offset| + N*|increment| that isn't less
+// than |curr|. Like MySQL, |offset| is ignored if it's greater than |increment|.
+func interleavedAutoIncrementValue(curr, increment, offset uint64) uint64 {
+	if increment <= 1 {
+		return curr
+	}
+	if offset == 0 || offset > increment {
+		offset = 1
+	}
+	if curr <= offset {
+		return offset
+	}
+	return offset + (curr-offset+increment-1)/increment*increment
+}
+
+// AutoIncrementInterleaving returns the auto_increment_increment and auto_increment_offset system variables of the
+// session given.
+func AutoIncrementInterleaving(ctx *sql.Context) (increment, offset uint64, err error) {
+	incrementVal, err := ctx.GetSessionVariable(ctx, "auto_increment_increment")
+	if err != nil {
+		return 0, 0, err
+	}
+	offsetVal, err := ctx.GetSessionVariable(ctx, "auto_increment_offset")
+	if err != nil {
+		return 0, 0, err
+	}
+	if increment, err = CoerceAutoIncrementValue(incrementVal); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = CoerceAutoIncrementValue(offsetVal); err != nil {
+		return 0, 0, err
+	}
+	return increment, offset, nil
+}
+
 // CoerceAutoIncrementValue converts |val| into an AUTO_INCREMENT sequence value
 func CoerceAutoIncrementValue(val interface{}) (uint64, error) {
 	switch typ := val.(type) {
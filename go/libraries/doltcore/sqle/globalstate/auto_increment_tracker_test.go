@@ -16,6 +16,7 @@ package globalstate
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -70,3 +71,63 @@ func TestCoerceAutoIncrementValue(t *testing.T) {
 		})
 	}
 }
+
+func TestNextInterleaved(t *testing.T) {
+	tests := []struct {
+		name      string
+		increment uint64
+		offset    uint64
+		inserts   []interface{}
+		exp       []uint64
+	}{
+		{
+			name:      "no interleaving",
+			increment: 1,
+			offset:    1,
+			inserts:   []interface{}{nil, nil, nil},
+			exp:       []uint64{1, 2, 3},
+		},
+		{
+			name:      "first of two",
+			increment: 2,
+			offset:    1,
+			inserts:   []interface{}{nil, nil, nil},
+			exp:       []uint64{1, 3, 5},
+		},
+		{
+			name:      "second of two",
+			increment: 2,
+			offset:    2,
+			inserts:   []interface{}{nil, nil, nil},
+			exp:       []uint64{2, 4, 6},
+		},
+		{
+			name:      "explicit values",
+			increment: 10,
+			offset:    3,
+			inserts:   []interface{}{nil, 25, nil, 4, nil},
+			exp:       []uint64{3, 25, 33, 4, 43},
+		},
+		{
+			name:      "offset greater than increment is ignored",
+			increment: 5,
+			offset:    7,
+			inserts:   []interface{}{nil, nil},
+			exp:       []uint64{1, 6},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ait := AutoIncrementTracker{
+				sequences: map[string]uint64{"t": 1},
+				mu:        &sync.Mutex{},
+			}
+			for i, insertVal := range test.inserts {
+				act, err := ait.NextInterleaved("t", insertVal, test.increment, test.offset)
+				assert.NoError(t, err)
+				assert.Equal(t, test.exp[i], act)
+			}
+		})
+	}
+}
@@ -81,15 +81,22 @@ func NewGlobalStateStoreForDb(ctx context.Context, db *doltdb.DoltDB) (GlobalSta
 
 	return GlobalState{
 		aiTracker: tracker,
+		mdLocks:   NewMetadataLocks(),
 		mu:        &sync.Mutex{},
 	}, nil
 }
 
 type GlobalState struct {
 	aiTracker AutoIncrementTracker
+	mdLocks   *MetadataLocks
 	mu        *sync.Mutex
 }
 
 func (g GlobalState) GetAutoIncrementTracker(ctx *sql.Context) (AutoIncrementTracker, error) {
 	return g.aiTracker, nil
 }
+
+// GetMetadataLocks returns the metadata locks shared by all sessions using this database
+func (g GlobalState) GetMetadataLocks() *MetadataLocks {
+	return g.mdLocks
+}
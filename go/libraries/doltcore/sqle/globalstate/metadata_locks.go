@@ -0,0 +1,261 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package globalstate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// ErrMetadataLockWaitTimeout is returned when a metadata lock can't be acquired before the session's lock_wait_timeout
+var ErrMetadataLockWaitTimeout = errors.NewKind("Lock wait timeout exceeded; waiting for metadata lock on table '%s' held by connection %d; try restarting transaction")
+
+// MetadataLocks implements MySQL-style metadata locks on tables. Statements that read or write table data take a
+// shared lock on the table, while statements that change its schema take an exclusive lock. Locks are owned by a
+// session and held until its transaction ends, so a schema change waits for in-flight transactions using the table
+// to finish (and vice versa) instead of racing them and producing conflicts in the working set.
+//
+// Exclusive requests are preferred over shared ones: once a session is waiting for an exclusive lock, sessions that
+// don't already hold the lock wait behind it, so a steady stream of readers can't starve a schema change. A request
+// that would wait on a session which is itself (transitively) waiting on the requester fails immediately with
+// sql.ErrLockDeadlock rather than waiting out its timeout.
+//
+// Locks are keyed by an opaque string, which callers construct to include the working set the table belongs to, since
+// tables on different branches never conflict.
+type MetadataLocks struct {
+	mu    *sync.Mutex
+	locks map[string]*metadataLock
+	// waiting is the request each blocked session is waiting on
+	waiting map[sql.Session]lockRequest
+	// released is closed and replaced every time a lock is released or a request stops waiting, to wake up any
+	// waiters
+	released chan struct{}
+}
+
+type metadataLock struct {
+	// exclusive is the session holding the exclusive lock, or nil if there isn't one
+	exclusive sql.Session
+	// shared is the set of sessions holding a shared lock
+	shared map[sql.Session]struct{}
+}
+
+type lockRequest struct {
+	key       string
+	exclusive bool
+}
+
+func NewMetadataLocks() *MetadataLocks {
+	return &MetadataLocks{
+		mu:       &sync.Mutex{},
+		locks:    make(map[string]*metadataLock),
+		waiting:  make(map[sql.Session]lockRequest),
+		released: make(chan struct{}),
+	}
+}
+
+// Acquire takes a shared or exclusive lock on the key given for the session of the context given, waiting up to
+// |timeout| for conflicting locks held by other sessions to be released. Locks are reentrant, and a session that
+// is the only holder of a shared lock can upgrade it to an exclusive one. Returns sql.ErrLockDeadlock if waiting
+// would deadlock.
+func (m *MetadataLocks) Acquire(ctx *sql.Context, key, tableName string, exclusive bool, timeout time.Duration) error {
+	owner := ctx.Session
+	req := lockRequest{key: key, exclusive: exclusive}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		m.mu.Lock()
+		blockers := m.blockers(owner, req)
+		if len(blockers) > 0 {
+			m.releaseDisconnected(ctx)
+			blockers = m.blockers(owner, req)
+		}
+		if len(blockers) == 0 {
+			m.grant(owner, req)
+			m.mu.Unlock()
+			return nil
+		}
+		if m.waitsOn(blockers, owner) {
+			m.stopWaiting(owner)
+			m.mu.Unlock()
+			return sql.ErrLockDeadlock.New("deadlock waiting for metadata lock on table '" + tableName + "'")
+		}
+		m.waiting[owner] = req
+		released := m.released
+		m.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-deadline.C:
+			m.mu.Lock()
+			m.stopWaiting(owner)
+			m.mu.Unlock()
+			return ErrMetadataLockWaitTimeout.New(tableName, blockers[0].ID())
+		case <-ctx.Done():
+			m.mu.Lock()
+			m.stopWaiting(owner)
+			m.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+}
+
+// blockers returns the sessions the request given must wait for before it can be granted to |owner|. An exclusive
+// request waits for every other holder of the lock. A shared request waits for another session's exclusive lock, and
+// if |owner| doesn't already hold the lock, for other sessions waiting for an exclusive one. Must be called with
+// |m.mu| held.
+func (m *MetadataLocks) blockers(owner sql.Session, req lockRequest) []sql.Session {
+	var blockers []sql.Session
+	l, ok := m.locks[req.key]
+	if ok && l.exclusive != nil && l.exclusive != owner {
+		blockers = append(blockers, l.exclusive)
+	}
+
+	if req.exclusive {
+		if ok {
+			for holder := range l.shared {
+				if holder != owner {
+					blockers = append(blockers, holder)
+				}
+			}
+		}
+		return blockers
+	}
+
+	if ok {
+		if _, held := l.shared[owner]; held || l.exclusive == owner {
+			return blockers
+		}
+	}
+	for waiter, w := range m.waiting {
+		if waiter != owner && w.exclusive && w.key == req.key {
+			blockers = append(blockers, waiter)
+		}
+	}
+	return blockers
+}
+
+// waitsOn returns whether any of |blockers| is, directly or through other waiting sessions, waiting on |owner|. Must
+// be called with |m.mu| held.
+func (m *MetadataLocks) waitsOn(blockers []sql.Session, owner sql.Session) bool {
+	visited := make(map[sql.Session]bool)
+	stack := append([]sql.Session(nil), blockers...)
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s == owner {
+			return true
+		}
+		if visited[s] {
+			continue
+		}
+		visited[s] = true
+		if req, ok := m.waiting[s]; ok {
+			stack = append(stack, m.blockers(s, req)...)
+		}
+	}
+	return false
+}
+
+// grant gives the lock requested to |owner|, which must not have any blockers. Must be called with |m.mu| held.
+func (m *MetadataLocks) grant(owner sql.Session, req lockRequest) {
+	l, ok := m.locks[req.key]
+	if !ok {
+		l = &metadataLock{shared: make(map[sql.Session]struct{})}
+		m.locks[req.key] = l
+	}
+	if req.exclusive {
+		l.exclusive = owner
+	} else {
+		l.shared[owner] = struct{}{}
+	}
+	m.stopWaiting(owner)
+}
+
+// stopWaiting removes any request |owner| is waiting on, waking up the sessions queued behind it. Must be called with
+// |m.mu| held.
+func (m *MetadataLocks) stopWaiting(owner sql.Session) {
+	if _, ok := m.waiting[owner]; ok {
+		delete(m.waiting, owner)
+		m.wake()
+	}
+}
+
+// wake wakes up every waiting session to retry its request. Must be called with |m.mu| held.
+func (m *MetadataLocks) wake() {
+	close(m.released)
+	m.released = make(chan struct{})
+}
+
+// releaseDisconnected releases the locks held by connections that are no longer in the server's process list, which
+// can happen when a client disconnects in the middle of a transaction. The process list is only consulted when it
+// includes the current connection, since some contexts (e.g. embedded use) don't track connections at all. Must be
+// called with |m.mu| held.
+func (m *MetadataLocks) releaseDisconnected(ctx *sql.Context) {
+	if ctx.ProcessList == nil {
+		return
+	}
+
+	live := make(map[uint32]bool)
+	for _, p := range ctx.ProcessList.Processes() {
+		live[p.Connection] = true
+	}
+	if !live[ctx.Session.ID()] {
+		return
+	}
+
+	for _, l := range m.locks {
+		if l.exclusive != nil && !live[l.exclusive.ID()] {
+			m.releaseLocked(l.exclusive)
+		}
+		for holder := range l.shared {
+			if !live[holder.ID()] {
+				m.releaseLocked(holder)
+			}
+		}
+	}
+}
+
+// ReleaseAll releases every lock held by the session given
+func (m *MetadataLocks) ReleaseAll(owner sql.Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releaseLocked(owner)
+}
+
+// releaseLocked releases every lock held by the session given. Must be called with |m.mu| held.
+func (m *MetadataLocks) releaseLocked(owner sql.Session) {
+	found := false
+	for key, l := range m.locks {
+		if l.exclusive == owner {
+			l.exclusive = nil
+			found = true
+		}
+		if _, ok := l.shared[owner]; ok {
+			delete(l.shared, owner)
+			found = true
+		}
+		if l.exclusive == nil && len(l.shared) == 0 {
+			delete(m.locks, key)
+		}
+	}
+
+	if found {
+		m.wake()
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package globalstate
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	lockTestKey     = "workingSets/heads/main/t"
+	longLockTimeout = 10 * time.Second
+)
+
+func newLockTestContext() *sql.Context {
+	return sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+}
+
+// acquireAsync acquires the lock given in a new goroutine, returning a channel that receives the result
+func acquireAsync(m *MetadataLocks, ctx *sql.Context, exclusive bool, timeout time.Duration) <-chan error {
+	res := make(chan error, 1)
+	go func() {
+		res <- m.Acquire(ctx, lockTestKey, "t", exclusive, timeout)
+	}()
+	return res
+}
+
+// waitForWaiters waits until |n| sessions are waiting on a lock
+func waitForWaiters(t *testing.T, m *MetadataLocks, n int) {
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.waiting) == n
+	}, longLockTimeout, time.Millisecond)
+}
+
+func assertBlocked(t *testing.T, res <-chan error) {
+	select {
+	case err := <-res:
+		t.Fatalf("expected lock request to wait, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func assertAcquired(t *testing.T, res <-chan error) {
+	select {
+	case err := <-res:
+		require.NoError(t, err)
+	case <-time.After(longLockTimeout):
+		t.Fatal("timed out waiting for lock")
+	}
+}
+
+func TestMetadataLocksSharedReaders(t *testing.T) {
+	m := NewMetadataLocks()
+	readers := make([]*sql.Context, 8)
+	for i := range readers {
+		readers[i] = newLockTestContext()
+	}
+
+	var wg sync.WaitGroup
+	for _, ctx := range readers {
+		wg.Add(1)
+		go func(ctx *sql.Context) {
+			defer wg.Done()
+			assert.NoError(t, m.Acquire(ctx, lockTestKey, "t", false, longLockTimeout))
+		}(ctx)
+	}
+	wg.Wait()
+
+	writer := newLockTestContext()
+	res := acquireAsync(m, writer, true, longLockTimeout)
+	assertBlocked(t, res)
+
+	for _, ctx := range readers {
+		m.ReleaseAll(ctx.Session)
+	}
+	assertAcquired(t, res)
+}
+
+func TestMetadataLocksWriterPreferred(t *testing.T) {
+	m := NewMetadataLocks()
+	reader := newLockTestContext()
+	require.NoError(t, m.Acquire(reader, lockTestKey, "t", false, longLockTimeout))
+
+	writer := newLockTestContext()
+	writerRes := acquireAsync(m, writer, true, longLockTimeout)
+	waitForWaiters(t, m, 1)
+
+	// a new reader queues behind the waiting writer, but an existing one can take the lock again
+	lateReader := newLockTestContext()
+	lateRes := acquireAsync(m, lateReader, false, longLockTimeout)
+	waitForWaiters(t, m, 2)
+	require.NoError(t, m.Acquire(reader, lockTestKey, "t", false, longLockTimeout))
+	assertBlocked(t, lateRes)
+
+	m.ReleaseAll(reader.Session)
+	assertAcquired(t, writerRes)
+	assertBlocked(t, lateRes)
+
+	m.ReleaseAll(writer.Session)
+	assertAcquired(t, lateRes)
+}
+
+func TestMetadataLocksWriterGivesUp(t *testing.T) {
+	m := NewMetadataLocks()
+	reader := newLockTestContext()
+	require.NoError(t, m.Acquire(reader, lockTestKey, "t", false, longLockTimeout))
+
+	writer := newLockTestContext()
+	writerRes := acquireAsync(m, writer, true, 100*time.Millisecond)
+	waitForWaiters(t, m, 1)
+
+	lateReader := newLockTestContext()
+	lateRes := acquireAsync(m, lateReader, false, longLockTimeout)
+
+	select {
+	case err := <-writerRes:
+		assert.True(t, ErrMetadataLockWaitTimeout.Is(err), "unexpected error %v", err)
+	case <-time.After(longLockTimeout):
+		t.Fatal("timed out waiting for lock timeout")
+	}
+	assertAcquired(t, lateRes)
+}
+
+func TestMetadataLocksUpgradeDeadlock(t *testing.T) {
+	m := NewMetadataLocks()
+	a, b := newLockTestContext(), newLockTestContext()
+	require.NoError(t, m.Acquire(a, lockTestKey, "t", false, longLockTimeout))
+	require.NoError(t, m.Acquire(b, lockTestKey, "t", false, longLockTimeout))
+
+	aRes := acquireAsync(m, a, true, longLockTimeout)
+	waitForWaiters(t, m, 1)
+
+	start := time.Now()
+	err := m.Acquire(b, lockTestKey, "t", true, longLockTimeout)
+	require.Error(t, err)
+	assert.True(t, sql.ErrLockDeadlock.Is(err), "unexpected error %v", err)
+	assert.Less(t, time.Since(start), longLockTimeout/2)
+
+	m.ReleaseAll(b.Session)
+	assertAcquired(t, aRes)
+}
+
+func TestMetadataLocksDeadlockAcrossTables(t *testing.T) {
+	m := NewMetadataLocks()
+	a, b := newLockTestContext(), newLockTestContext()
+	require.NoError(t, m.Acquire(a, "t1", "t1", true, longLockTimeout))
+	require.NoError(t, m.Acquire(b, "t2", "t2", true, longLockTimeout))
+
+	aRes := make(chan error, 1)
+	go func() {
+		aRes <- m.Acquire(a, "t2", "t2", false, longLockTimeout)
+	}()
+	waitForWaiters(t, m, 1)
+
+	err := m.Acquire(b, "t1", "t1", false, longLockTimeout)
+	assert.True(t, sql.ErrLockDeadlock.Is(err), "unexpected error %v", err)
+
+	m.ReleaseAll(b.Session)
+	assertAcquired(t, aRes)
+}
+
+func TestMetadataLocksTimeout(t *testing.T) {
+	m := NewMetadataLocks()
+	writer := newLockTestContext()
+	require.NoError(t, m.Acquire(writer, lockTestKey, "t", true, longLockTimeout))
+	require.NoError(t, m.Acquire(writer, lockTestKey, "t", false, longLockTimeout))
+
+	reader := newLockTestContext()
+	err := m.Acquire(reader, lockTestKey, "t", false, 10*time.Millisecond)
+	assert.True(t, ErrMetadataLockWaitTimeout.Is(err), "unexpected error %v", err)
+
+	// other keys don't conflict
+	require.NoError(t, m.Acquire(reader, "other", "other", true, longLockTimeout))
+
+	m.mu.Lock()
+	assert.Empty(t, m.waiting)
+	m.mu.Unlock()
+}
+
+func TestMetadataLocksContention(t *testing.T) {
+	m := NewMetadataLocks()
+	var mu sync.Mutex
+	readers, writers := 0, 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(exclusive bool) {
+			defer wg.Done()
+			ctx := newLockTestContext()
+			for j := 0; j < 20; j++ {
+				if !assert.NoError(t, m.Acquire(ctx, lockTestKey, "t", exclusive, longLockTimeout)) {
+					return
+				}
+				mu.Lock()
+				if exclusive {
+					writers++
+				} else {
+					readers++
+				}
+				assert.True(t, writers == 0 || (writers == 1 && readers == 0))
+				mu.Unlock()
+
+				time.Sleep(100 * time.Microsecond)
+				mu.Lock()
+				if exclusive {
+					writers--
+				} else {
+					readers--
+				}
+				mu.Unlock()
+				m.ReleaseAll(ctx.Session)
+			}
+		}(i%4 == 0)
+	}
+	wg.Wait()
+}
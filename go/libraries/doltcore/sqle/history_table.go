@@ -436,23 +436,20 @@ type historyIter struct {
 func newRowItrForTableAtCommit(ctx *sql.Context, table *DoltTable, h hash.Hash, cm *doltdb.Commit, lookup sql.IndexLookup, projections []uint64) (*historyIter, error) {
 	targetSchema := table.Schema()
 
-	root, err := cm.GetRootValue(ctx)
+	// the commit root index tells us whether the table exists at this commit without loading its root value
+	entry, err := table.db.DbData().Ddb.CommitRootEntry(ctx, cm)
 	if err != nil {
 		return nil, err
 	}
-
-	meta, err := cm.GetCommitMeta(ctx)
-	if err != nil {
-		return nil, err
+	if _, _, ok := entry.TableHash(table.Name()); !ok {
+		return &historyIter{nonExistentTable: true}, nil
 	}
+	meta := entry.Meta
 
-	_, _, ok, err := root.GetTableInsensitive(ctx, table.Name())
+	root, err := cm.GetRootValue(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if !ok {
-		return &historyIter{nonExistentTable: true}, nil
-	}
 
 	table, err = table.LockedToRoot(ctx, root)
 	if err != nil {
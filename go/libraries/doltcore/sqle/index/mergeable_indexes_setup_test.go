@@ -105,6 +105,7 @@ func setupIndexes(t *testing.T, tableName, insertQuery string) (*sqle.Engine, *s
 
 	pro = pro.WithDbFactoryUrl(doltdb.InMemDoltDB)
 	engine = sqle.NewDefault(pro)
+	dsqle.AddAnalyzerRules(engine.Analyzer)
 
 	it := []*indexTuple{
 		idxv1ToTuple,
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -129,6 +130,49 @@ func GetField(ctx context.Context, td val.TupleDesc, i int, tup val.Tuple, ns tr
 	return v, err
 }
 
+// StreamedValueThreshold is the size in bytes above which GetFieldReader streams a BLOB or TEXT value stored out of
+// band from its blob tree, rather than reading the value into memory first.
+var StreamedValueThreshold = 1 << 20
+
+// GetFieldReader returns a reader of the ith field of the Tuple, which must be a BLOB or TEXT field, and whether the
+// field is not NULL. Values stored out of band that may be larger than StreamedValueThreshold are loaded from their
+// blob tree as they're read, so that they're never held in memory. GetField reads the same fields in full.
+func GetFieldReader(ctx context.Context, td val.TupleDesc, i int, tup val.Tuple, ns tree.NodeStore) (io.Reader, bool, error) {
+	var h hash.Hash
+	var ok bool
+	switch td.Types[i].Enc {
+	case val.ByteStringEnc:
+		b, ok := td.GetBytes(i, tup)
+		return bytes.NewReader(b), ok, nil
+	case val.StringEnc:
+		s, ok := td.GetString(i, tup)
+		return strings.NewReader(s), ok, nil
+	case val.BytesAddrEnc:
+		h, ok = td.GetBytesAddr(i, tup)
+	case val.StringAddrEnc:
+		h, ok = td.GetStringAddr(i, tup)
+	default:
+		return nil, false, fmt.Errorf("cannot read field of encoding %d as a stream", td.Types[i].Enc)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	blob := tree.NewByteArray(h, ns)
+	sz, err := blob.SizeUpperBound(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if sz > StreamedValueThreshold {
+		return blob.Reader(ctx), true, nil
+	}
+	b, err := blob.ToBytes(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return bytes.NewReader(b), true, nil
+}
+
 // PutField writes an interface{} to the ith field of the Tuple being built.
 func PutField(ctx context.Context, ns tree.NodeStore, tb *val.TupleBuilder, i int, v interface{}) error {
 	if v == nil {
@@ -17,6 +17,7 @@ package index
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"math"
 	"testing"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/pool"
 	"github.com/dolthub/dolt/go/store/prolly/tree"
 	"github.com/dolthub/dolt/go/store/val"
@@ -244,3 +246,67 @@ func dateFromTime(t time.Time) time.Time {
 	y, m, d := t.Year(), t.Month(), t.Day()
 	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
+
+func TestGetFieldReader(t *testing.T) {
+	ctx := context.Background()
+	desc := val.NewTupleDescriptor(
+		val.Type{Enc: val.BytesAddrEnc, Nullable: true},
+		val.Type{Enc: val.StringAddrEnc, Nullable: true},
+		val.Type{Enc: val.BytesAddrEnc, Nullable: true},
+	)
+	large := make([]byte, 4*StreamedValueThreshold)
+	for i := range large {
+		large[i] = byte(i % 251)
+	}
+	small := "a small value"
+
+	ns := &countingNodeStore{NodeStore: tree.NewTestNodeStore()}
+	builder := val.NewTupleBuilder(desc)
+	require.NoError(t, PutField(ctx, ns, builder, 0, large))
+	require.NoError(t, PutField(ctx, ns, builder, 1, small))
+	tup := builder.Build(testPool)
+
+	t.Run("large value", func(t *testing.T) {
+		ns.reads = 0
+		r, ok, err := GetFieldReader(ctx, desc, 0, tup, ns)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// reading the start of the value loads only the path to its first leaf
+		buf := make([]byte, tree.DefaultFixedChunkLength)
+		_, err = io.ReadFull(r, buf)
+		require.NoError(t, err)
+		assert.Equal(t, large[:len(buf)], buf)
+		assert.Less(t, ns.reads, 10)
+
+		rest, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, large[len(buf):], rest)
+	})
+
+	t.Run("small value", func(t *testing.T) {
+		r, ok, err := GetFieldReader(ctx, desc, 1, tup, ns)
+		require.NoError(t, err)
+		require.True(t, ok)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, small, string(b))
+	})
+
+	t.Run("null", func(t *testing.T) {
+		_, ok, err := GetFieldReader(ctx, desc, 2, tup, ns)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+// countingNodeStore counts the nodes read from it.
+type countingNodeStore struct {
+	tree.NodeStore
+	reads int
+}
+
+func (ns *countingNodeStore) Read(ctx context.Context, ref hash.Hash) (tree.Node, error) {
+	ns.reads++
+	return ns.NodeStore.Read(ctx, ref)
+}
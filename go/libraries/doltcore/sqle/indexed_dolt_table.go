@@ -66,6 +66,9 @@ func (idt *IndexedDoltTable) Collation() sql.CollationID {
 }
 
 func (idt *IndexedDoltTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if err := idt.table.lockMetadataForRead(ctx); err != nil {
+		return nil, err
+	}
 	return index.NewRangePartitionIter(ctx, idt.table, lookup, idt.isDoltFormat)
 }
 
@@ -88,7 +91,12 @@ func (idt *IndexedDoltTable) PartitionRows(ctx *sql.Context, part sql.Partition)
 		}
 	}
 
-	return idt.lb.NewRowIter(ctx, part)
+	iter, err := idt.lb.NewRowIter(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRowsReadStats(ctx, iter), nil
 }
 
 func (idt *IndexedDoltTable) PartitionRows2(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
@@ -105,7 +113,12 @@ func (idt *IndexedDoltTable) PartitionRows2(ctx *sql.Context, part sql.Partition
 		}
 	}
 
-	return idt.lb.NewRowIter(ctx, part)
+	iter, err := idt.lb.NewRowIter(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRowsReadStats(ctx, iter), nil
 }
 
 func (idt *IndexedDoltTable) IsTemporary() bool {
@@ -137,6 +150,9 @@ type WritableIndexedDoltTable struct {
 }
 
 func (t *WritableIndexedDoltTable) LookupPartitions(ctx *sql.Context, lookup sql.IndexLookup) (sql.PartitionIter, error) {
+	if err := t.lockMetadataForRead(ctx); err != nil {
+		return nil, err
+	}
 	return index.NewRangePartitionIter(ctx, t.DoltTable, lookup, t.isDoltFormat)
 }
 
@@ -158,7 +174,12 @@ func (t *WritableIndexedDoltTable) PartitionRows(ctx *sql.Context, part sql.Part
 		}
 	}
 
-	return t.lb.NewRowIter(ctx, part)
+	iter, err := t.lb.NewRowIter(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRowsReadStats(ctx, iter), nil
 }
 
 // WithProjections implements sql.ProjectedTable
@@ -299,6 +299,7 @@ func sqlNewEngine(dEnv *env.DoltEnv) (*sqle.Engine, dsess.DoltDatabaseProvider,
 
 	pro = pro.WithDbFactoryUrl(doltdb.InMemDoltDB)
 	engine := sqle.NewDefault(pro)
+	dsql.AddAnalyzerRules(engine.Analyzer)
 
 	return engine, pro, nil
 }
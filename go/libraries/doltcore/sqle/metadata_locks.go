@@ -0,0 +1,86 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/globalstate"
+)
+
+const lockWaitTimeoutSysVar = "lock_wait_timeout"
+
+// lockTableMetadata takes a metadata lock on the table named for the current transaction when @@dolt_metadata_locks is
+// enabled. DDL should take an exclusive lock, and DML and reads a shared one. The lock is scoped to the working set the
+// table belongs to and released when the transaction commits or rolls back.
+func lockTableMetadata(ctx *sql.Context, db dsess.SqlDatabase, tableName string, exclusive bool) error {
+	if _, val, ok := sql.SystemVariables.GetGlobal(dsess.MetadataLocksEnabled); !ok || val != dsess.SysVarTrue {
+		return nil
+	}
+
+	// Locks are released when the transaction ends, which never happens without a real transaction
+	if _, ok := ctx.GetTransaction().(*dsess.DoltTransaction); !ok {
+		return nil
+	}
+
+	sp, ok := db.(globalstate.StateProvider)
+	if !ok {
+		return nil
+	}
+	locks := sp.GetGlobalState().GetMetadataLocks()
+	if locks == nil {
+		return nil
+	}
+
+	dbState, ok, err := dsess.DSessFromSess(ctx.Session).LookupDbState(ctx, db.RevisionQualifiedName())
+	if err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("no root value found in session")
+	}
+	ws := dbState.WorkingSet()
+	if ws == nil {
+		// read-only revisions can't be changed, so there is nothing to protect
+		return nil
+	}
+
+	timeout, err := lockWaitTimeout(ctx)
+	if err != nil {
+		return err
+	}
+
+	dsess.DSessFromSess(ctx.Session).TrackMetadataLocks(locks)
+	key := ws.Ref().String() + "/" + strings.ToLower(tableName)
+	return locks.Acquire(ctx, key, tableName, exclusive, timeout)
+}
+
+// lockWaitTimeout returns the session's @@lock_wait_timeout as a duration
+func lockWaitTimeout(ctx *sql.Context) (time.Duration, error) {
+	val, err := ctx.GetSessionVariable(ctx, lockWaitTimeoutSysVar)
+	if err != nil {
+		return 0, err
+	}
+
+	secs, ok := val.(int64)
+	if !ok {
+		return 0, sql.ErrInvalidSystemVariableValue.New(lockWaitTimeoutSysVar, val)
+	}
+	return time.Duration(secs) * time.Second, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestPlanCache(t *testing.T) {
+	ctx := context.Background()
+	dEnv := CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir})
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(dEnv, ctx, db)
+	require.NoError(t, err)
+
+	dsess.DSessFromSess(sqlCtx.Session).SetPlanCache(dsess.NewPlanCache(8, engine.PreparedDataCache, engine.PrepareQuery))
+
+	query := func(q string) []sql.Row {
+		qCtx := sql.NewContext(ctx, sql.WithSession(sqlCtx.Session), sql.WithQuery(q))
+		sch, iter, err := engine.Query(qCtx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(qCtx, sch, iter)
+		require.NoError(t, err)
+		return rows
+	}
+	planned := func(q string) bool {
+		_, ok := engine.PreparedDataCache.GetCachedStmt(sqlCtx.Session.ID(), q)
+		return ok
+	}
+
+	query("create table t (pk int primary key, c int)")
+	query("insert into t values (1, 10)")
+
+	// a statement is planned once it's been run twice, and then run from its plan
+	const q = "select * from t where pk = 1"
+	assert.Equal(t, []sql.Row{{int32(1), int32(10)}}, query(q))
+	assert.False(t, planned(q))
+	assert.Equal(t, []sql.Row{{int32(1), int32(10)}}, query(q))
+	assert.True(t, planned(q))
+	assert.Equal(t, []sql.Row{{int32(1), int32(10)}}, query(q))
+	assert.True(t, planned(q))
+
+	// changing the data doesn't change the plan
+	query("update t set c = 20 where pk = 1")
+	assert.Equal(t, []sql.Row{{int32(1), int32(20)}}, query(q))
+	assert.True(t, planned(q))
+
+	// changing the schema drops the plan before it's used
+	query("alter table t add column d int")
+	assert.Equal(t, []sql.Row{{int32(1), int32(20), nil}}, query(q))
+	assert.False(t, planned(q))
+
+	// changing a variable that affects analysis drops the plan before it's used
+	query(q)
+	assert.True(t, planned(q))
+	query("set @@sql_mode = 'ANSI_QUOTES'")
+	assert.Equal(t, []sql.Row{{int32(1), int32(20), nil}}, query(q))
+	assert.False(t, planned(q))
+	query("set @@sql_mode = ''")
+
+	// queries with common table expressions and unions of parenthesized queries are planned
+	for _, rq := range []string{"with cte as (select c from t) select * from cte", "(select c from t) union (select c from t)"} {
+		assert.Equal(t, []sql.Row{{int32(20)}}, query(rq))
+		assert.Equal(t, []sql.Row{{int32(20)}}, query(rq))
+		assert.True(t, planned(rq), rq)
+	}
+
+	// statements that write aren't planned
+	const ins = "insert into t values (2, 20, 20)"
+	query(ins)
+	query("delete from t where pk = 2")
+	query(ins)
+	assert.False(t, planned(ins))
+}
@@ -0,0 +1,380 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const defaultResultCacheMaxRows = 10_000
+
+const gmsPackagePrefix = "github.com/dolthub/go-mysql-server/"
+
+// resultCacheSessionVars are the session variables that can change the result of a query without changing its plan.
+var resultCacheSessionVars = []string{"sql_mode", "time_zone", "collation_connection", "div_precision_increment"}
+
+// ResultCache caches the results of read only queries. Results are keyed by the analyzed plan of the query, which
+// normalizes its text, and the hashes of the tables it reads. Any change to one of those tables, committed or not,
+// changes the table's hash, so stale results are never returned and are evicted as the cache fills.
+type ResultCache struct {
+	results *lru.Cache[string, []sql.Row]
+	maxRows int
+}
+
+// NewResultCache returns a ResultCache holding up to |entries| results of at most |maxRows| rows each, or nil if
+// |entries| isn't positive.
+func NewResultCache(entries, maxRows int) *ResultCache {
+	if entries <= 0 {
+		return nil
+	}
+	if maxRows <= 0 {
+		maxRows = defaultResultCacheMaxRows
+	}
+	results, err := lru.New[string, []sql.Row](entries)
+	if err != nil {
+		panic(err)
+	}
+	return &ResultCache{results: results, maxRows: maxRows}
+}
+
+// Len returns the number of results in the cache.
+func (c *ResultCache) Len() int {
+	return c.results.Len()
+}
+
+// NewResultCachingBuilder returns a sql.NodeExecBuilder that answers cacheable queries from |cache|, building
+// everything else with |builder|. It returns |builder| if |cache| is nil.
+func NewResultCachingBuilder(builder sql.NodeExecBuilder, cache *ResultCache) sql.NodeExecBuilder {
+	if cache == nil {
+		return builder
+	}
+	return &resultCachingBuilder{builder: builder, cache: cache}
+}
+
+type resultCachingBuilder struct {
+	builder sql.NodeExecBuilder
+	cache   *ResultCache
+}
+
+var _ sql.NodeExecBuilder = (*resultCachingBuilder)(nil)
+
+// Build implements sql.NodeExecBuilder
+func (b *resultCachingBuilder) Build(ctx *sql.Context, n sql.Node, r sql.Row) (sql.RowIter, error) {
+	if len(r) > 0 {
+		return b.builder.Build(ctx, n, r)
+	}
+
+	query := unwrapQuery(n)
+	key, ok, err := resultCacheKey(ctx, query)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return b.builder.Build(ctx, n, r)
+	}
+
+	var replacement sql.Node
+	if rows, ok := b.cache.results.Get(key); ok {
+		replacement = &cachedResultNode{sch: query.Schema(), rows: rows}
+	} else {
+		replacement = &resultCachingNode{query: query, builder: b.builder, cache: b.cache, key: key}
+	}
+	n, err = replaceQuery(n, replacement)
+	if err != nil {
+		return nil, err
+	}
+	return b.builder.Build(ctx, n, r)
+}
+
+// unwrapQuery returns the query beneath the process tracking and transaction committing nodes added to every
+// statement.
+func unwrapQuery(n sql.Node) sql.Node {
+	switch n := n.(type) {
+	case *plan.QueryProcess:
+		return unwrapQuery(n.Child())
+	case *plan.TransactionCommittingNode:
+		return unwrapQuery(n.Child())
+	default:
+		return n
+	}
+}
+
+// replaceQuery returns |n| with the query found by unwrapQuery replaced by |replacement|.
+func replaceQuery(n sql.Node, replacement sql.Node) (sql.Node, error) {
+	switch n := n.(type) {
+	case *plan.QueryProcess, *plan.TransactionCommittingNode:
+		child, err := replaceQuery(n.Children()[0], replacement)
+		if err != nil {
+			return nil, err
+		}
+		return n.WithChildren(child)
+	default:
+		return replacement, nil
+	}
+}
+
+// resultCacheKey returns the key of the result of |query| in a ResultCache, and false if the result can't be cached.
+// Only queries that read Dolt tables, don't write anything and always return the same rows for the same table data
+// are cached.
+func resultCacheKey(ctx *sql.Context, query sql.Node) (string, bool, error) {
+	var tables []sql.Node
+	if !cacheableQuery(query, &tables) || len(tables) == 0 {
+		return "", false, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(sql.DebugString(query))
+	for _, name := range resultCacheSessionVars {
+		v, err := ctx.GetSessionVariable(ctx, name)
+		if err != nil {
+			return "", false, err
+		}
+		fmt.Fprintf(&sb, "\n%s=%v", name, v)
+	}
+	for _, t := range tables {
+		rt, ok := t.(*plan.ResolvedTable)
+		if !ok {
+			rt = t.(*plan.IndexedTableAccess).ResolvedTable
+		}
+		dt, ok := doltTableOf(rt.Table)
+		if !ok {
+			return "", false, nil
+		}
+		tbl, err := dt.DoltTable(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		h, err := tbl.HashOf()
+		if err != nil {
+			return "", false, err
+		}
+		fmt.Fprintf(&sb, "\n%s=%s", rt.Name(), h.String())
+	}
+	return sb.String(), true, nil
+}
+
+// doltTableOf returns the DoltTable of |table|, unwrapping the tables that track the progress of a query.
+func doltTableOf(table sql.Table) (*DoltTable, bool) {
+	switch t := table.(type) {
+	case *DoltTable:
+		return t, true
+	case *WritableDoltTable:
+		return t.DoltTable, true
+	case *AlterableDoltTable:
+		return t.DoltTable, true
+	case sql.TableWrapper:
+		return doltTableOf(t.Underlying())
+	default:
+		return nil, false
+	}
+}
+
+// cacheableQuery returns whether the result of |query| can be cached, appending the tables it reads to |tables|.
+func cacheableQuery(query sql.Node, tables *[]sql.Node) bool {
+	cacheable := true
+	transform.Inspect(query, func(n sql.Node) bool {
+		if n == nil || !cacheable {
+			return false
+		}
+		switch n := n.(type) {
+		case *plan.ResolvedTable, *plan.IndexedTableAccess:
+			*tables = append(*tables, n)
+		case *plan.Limit:
+			// the rows found are counted as the rows are read
+			cacheable = !n.CalcFoundRows
+		case *plan.Project, *plan.Filter, *plan.Sort, *plan.TopN, *plan.Offset, *plan.GroupBy, *plan.Window,
+			*plan.Distinct, *plan.OrderedDistinct, *plan.Having, *plan.JoinNode, *plan.TableAlias,
+			*plan.SubqueryAlias, *plan.Union, *plan.Exchange, *plan.StripRowNode:
+		default:
+			cacheable = false
+		}
+		if ex, ok := n.(sql.Expressioner); ok && cacheable {
+			for _, e := range ex.Expressions() {
+				if !cacheableExpression(e, tables) {
+					cacheable = false
+				}
+			}
+		}
+		return cacheable
+	})
+	return cacheable
+}
+
+// cacheableExpression returns whether the value of |e| depends only on the rows it's evaluated against, appending the
+// tables read by its subqueries to |tables|.
+func cacheableExpression(e sql.Expression, tables *[]sql.Node) bool {
+	cacheable := true
+	sql.Inspect(e, func(e sql.Expression) bool {
+		if e == nil || !cacheable {
+			return false
+		}
+		switch e := e.(type) {
+		case *expression.UserVar, *expression.SystemVar, *expression.ProcedureParam:
+			cacheable = false
+		case *plan.Subquery:
+			cacheable = cacheableQuery(e.Query, tables)
+		case sql.NonDeterministicExpression:
+			cacheable = !e.IsNonDeterministic()
+		}
+		if cacheable {
+			cacheable = isGmsExpression(e)
+		}
+		return cacheable
+	})
+	return cacheable
+}
+
+// isGmsExpression returns whether |e| is one of go-mysql-server's expressions. The functions Dolt adds read the state
+// of the session and its databases rather than just their arguments, and user-defined functions could do anything.
+func isGmsExpression(e sql.Expression) bool {
+	t := reflect.TypeOf(e)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.HasPrefix(t.PkgPath(), gmsPackagePrefix)
+}
+
+// cachedResultNode returns the rows of a cached result.
+type cachedResultNode struct {
+	sch  sql.Schema
+	rows []sql.Row
+}
+
+var _ sql.ExecSourceRel = (*cachedResultNode)(nil)
+
+func (n *cachedResultNode) Resolved() bool {
+	return true
+}
+
+func (n *cachedResultNode) String() string {
+	return "CachedResult"
+}
+
+func (n *cachedResultNode) Schema() sql.Schema {
+	return n.sch
+}
+
+func (n *cachedResultNode) Children() []sql.Node {
+	return nil
+}
+
+func (n *cachedResultNode) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+func (n *cachedResultNode) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return true
+}
+
+func (n *cachedResultNode) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error) {
+	return sql.RowsToRowIter(n.rows...), nil
+}
+
+// resultCachingNode builds the iterator of a query, adding its rows to a ResultCache once they have all been read.
+type resultCachingNode struct {
+	query   sql.Node
+	builder sql.NodeExecBuilder
+	cache   *ResultCache
+	key     string
+}
+
+var _ sql.ExecSourceRel = (*resultCachingNode)(nil)
+
+func (n *resultCachingNode) Resolved() bool {
+	return true
+}
+
+func (n *resultCachingNode) String() string {
+	return n.query.String()
+}
+
+func (n *resultCachingNode) Schema() sql.Schema {
+	return n.query.Schema()
+}
+
+func (n *resultCachingNode) Children() []sql.Node {
+	return nil
+}
+
+func (n *resultCachingNode) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+func (n *resultCachingNode) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return n.query.CheckPrivileges(ctx, opChecker)
+}
+
+func (n *resultCachingNode) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error) {
+	iter, err := n.builder.Build(ctx, n.query, r)
+	if err != nil {
+		return nil, err
+	}
+	return &resultCachingIter{RowIter: iter, cache: n.cache, key: n.key}, nil
+}
+
+// resultCachingIter records the rows of a query's iterator, adding them to a ResultCache if the iterator is read to
+// the end without returning more than the cache's maximum number of rows.
+type resultCachingIter struct {
+	sql.RowIter
+	cache *ResultCache
+	key   string
+	rows  []sql.Row
+	// done is true once every row has been read
+	done bool
+	// tooLarge is true if the result has more rows than the cache holds
+	tooLarge bool
+}
+
+var _ sql.RowIter = (*resultCachingIter)(nil)
+
+func (itr *resultCachingIter) Next(ctx *sql.Context) (sql.Row, error) {
+	r, err := itr.RowIter.Next(ctx)
+	if err == io.EOF {
+		itr.done = true
+	} else if err == nil && !itr.tooLarge {
+		if len(itr.rows) < itr.cache.maxRows {
+			itr.rows = append(itr.rows, r.Copy())
+		} else {
+			itr.tooLarge, itr.rows = true, nil
+		}
+	}
+	return r, err
+}
+
+func (itr *resultCachingIter) Close(ctx *sql.Context) error {
+	err := itr.RowIter.Close(ctx)
+	if err == nil && itr.done && !itr.tooLarge {
+		if itr.rows == nil {
+			itr.rows = []sql.Row{}
+		}
+		itr.cache.results.Add(itr.key, itr.rows)
+	}
+	itr.rows = nil
+	return err
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/rowexec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestResultCache(t *testing.T) {
+	ctx := context.Background()
+	dEnv := CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir})
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(dEnv, ctx, db)
+	require.NoError(t, err)
+
+	cache := NewResultCache(16, 2)
+	engine.Analyzer.ExecBuilder = NewResultCachingBuilder(rowexec.DefaultBuilder, cache)
+
+	query := func(q string) []sql.Row {
+		sch, iter, err := engine.Query(sqlCtx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(sqlCtx, sch, iter)
+		require.NoError(t, err)
+		return rows
+	}
+
+	query("create table t (pk int primary key, c int)")
+	query("insert into t values (1, 10), (2, 20)")
+	assert.Equal(t, 0, cache.Len())
+
+	// the result is cached, and returned from the cache while the table is unchanged
+	assert.Equal(t, []sql.Row{{float64(30)}}, query("select sum(c) from t"))
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, []sql.Row{{float64(30)}}, query("select sum(c) from t"))
+	assert.Equal(t, 1, cache.Len())
+
+	// changing the table changes the key of the result
+	query("insert into t values (3, 30)")
+	assert.Equal(t, []sql.Row{{float64(60)}}, query("select sum(c) from t"))
+	assert.Equal(t, 2, cache.Len())
+
+	// results with more rows than the limit aren't cached
+	assert.Len(t, query("select * from t"), 3)
+	assert.Equal(t, 2, cache.Len())
+
+	// neither are the results of non-deterministic queries
+	query("select c, rand() from t where pk = 1")
+	query("select c, @@autocommit from t where pk = 1")
+	query("select c, active_branch() from t where pk = 1")
+	assert.Equal(t, 2, cache.Len())
+}
@@ -33,6 +33,7 @@ func PrepareCreateTableStmt(ctx context.Context, sqlDb dsess.SqlDatabase) (*sql.
 		return nil, nil, nil
 	}
 	engine := sqle.NewDefault(pro)
+	AddAnalyzerRules(engine.Analyzer)
 
 	sess := dsess.DefaultSession(pro)
 	sqlCtx := sql.NewContext(ctx, sql.WithSession(sess))
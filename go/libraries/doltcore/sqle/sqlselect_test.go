@@ -754,6 +754,8 @@ func BasicSelectTests() []SelectTest {
 					"bigbillieb@fake.horse",
 					time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).In(LoadedLocalLocation()),
 					"Initialize data repository",
+					false,
+					nil,
 				},
 			},
 			ExpectedSqlSchema: sql.Schema{
@@ -762,6 +764,8 @@ func BasicSelectTests() []SelectTest {
 				&sql.Column{Name: "email", Type: gmstypes.Text},
 				&sql.Column{Name: "date", Type: gmstypes.Datetime},
 				&sql.Column{Name: "message", Type: gmstypes.Text},
+				&sql.Column{Name: "signed", Type: gmstypes.Boolean},
+				&sql.Column{Name: "metadata", Type: gmstypes.JSON},
 			},
 		},
 		{
@@ -0,0 +1,354 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlutil
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/spatial"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// SpatialFormat is the text format that spatial values are written in when they are exported to text files
+type SpatialFormat string
+
+const (
+	// SpatialHex writes spatial values as 0x followed by the hex of the value in MySQL's internal format, which is
+	// the SRID followed by the WKB of the value
+	SpatialHex SpatialFormat = "hex"
+	// SpatialWKT writes spatial values as well-known text, prefixed with SRID=<srid>; if their SRID isn't 0
+	SpatialWKT SpatialFormat = "wkt"
+	// SpatialWKB writes spatial values as the hex of their well-known binary, which is extended with the SRID of
+	// values whose SRID isn't 0 in the same way as PostGIS's EWKB
+	SpatialWKB SpatialFormat = "wkb"
+)
+
+// ewkbSRIDFlag is set in the geometry type of EWKB values that are followed by an SRID
+const ewkbSRIDFlag = 0x20000000
+
+// SpatialFormatFromString returns the SpatialFormat named |s|
+func SpatialFormatFromString(s string) (SpatialFormat, error) {
+	switch f := SpatialFormat(strings.ToLower(s)); f {
+	case SpatialHex, SpatialWKT, SpatialWKB:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid spatial format '%s', expected one of hex, wkt or wkb", s)
+	}
+}
+
+// SpatialToStr returns the spatial value |g| in the format |f|
+func SpatialToStr(g gmstypes.GeometryValue, f SpatialFormat) string {
+	switch f {
+	case SpatialWKT:
+		return SpatialToWKT(g)
+	case SpatialWKB:
+		return SpatialToWKB(g)
+	default:
+		return fmt.Sprintf("0x%X", g.Serialize())
+	}
+}
+
+// SpatialToWKT returns the well-known text of |g|. Coordinates are written in x y order, which is longitude latitude
+// order for geographic SRIDs, as in GeoJSON and PostGIS. Values whose SRID isn't 0 are prefixed with SRID=<srid>;.
+func SpatialToWKT(g gmstypes.GeometryValue) string {
+	wkt := geomToWKT(g)
+	if g.GetSRID() != gmstypes.CartesianSRID {
+		return fmt.Sprintf("SRID=%d;%s", g.GetSRID(), wkt)
+	}
+	return wkt
+}
+
+func geomToWKT(g gmstypes.GeometryValue) string {
+	switch v := g.(type) {
+	case gmstypes.Point:
+		return "POINT(" + spatial.PointToWKT(v, false) + ")"
+	case gmstypes.LineString:
+		return "LINESTRING(" + spatial.LineToWKT(v, false) + ")"
+	case gmstypes.Polygon:
+		return "POLYGON(" + spatial.PolygonToWKT(v, false) + ")"
+	case gmstypes.MultiPoint:
+		return "MULTIPOINT(" + spatial.MultiPointToWKT(v, false) + ")"
+	case gmstypes.MultiLineString:
+		return "MULTILINESTRING(" + spatial.MultiLineStringToWKT(v, false) + ")"
+	case gmstypes.MultiPolygon:
+		return "MULTIPOLYGON(" + spatial.MultiPolygonToWKT(v, false) + ")"
+	case gmstypes.GeomColl:
+		return "GEOMETRYCOLLECTION(" + spatial.GeomCollToWKT(v, false) + ")"
+	default:
+		return ""
+	}
+}
+
+// SpatialToWKB returns the hex of the well-known binary of |g|. The SRID of values whose SRID isn't 0 is written
+// after the geometry type, which has the EWKB SRID flag set.
+func SpatialToWKB(g gmstypes.GeometryValue) string {
+	wkb := g.Serialize()[gmstypes.SRIDSize:]
+	if g.GetSRID() == gmstypes.CartesianSRID {
+		return fmt.Sprintf("%X", wkb)
+	}
+
+	hdr := gmstypes.WKBHeaderSize
+	ewkb := make([]byte, 0, len(wkb)+gmstypes.SRIDSize)
+	ewkb = append(ewkb, wkb[:hdr]...)
+	ewkb = binary.LittleEndian.AppendUint32(ewkb, g.GetSRID())
+	ewkb = append(ewkb, wkb[hdr:]...)
+	binary.LittleEndian.PutUint32(ewkb[gmstypes.EndianSize:], binary.LittleEndian.Uint32(wkb[gmstypes.EndianSize:])|ewkbSRIDFlag)
+	return fmt.Sprintf("%X", ewkb)
+}
+
+// SpatialToGeoJSON returns the GeoJSON geometry object of |g|. Objects of values whose SRID isn't 0 have a crs member
+// that names their SRID, e.g. EPSG:4326.
+func SpatialToGeoJSON(g gmstypes.GeometryValue) map[string]interface{} {
+	obj := make(map[string]interface{})
+	switch v := g.(type) {
+	case gmstypes.Point:
+		obj["type"] = "Point"
+		obj["coordinates"] = spatial.PointToSlice(v)
+	case gmstypes.LineString:
+		obj["type"] = "LineString"
+		obj["coordinates"] = spatial.LineToSlice(v)
+	case gmstypes.Polygon:
+		obj["type"] = "Polygon"
+		obj["coordinates"] = spatial.PolyToSlice(v)
+	case gmstypes.MultiPoint:
+		obj["type"] = "MultiPoint"
+		obj["coordinates"] = spatial.MPointToSlice(v)
+	case gmstypes.MultiLineString:
+		obj["type"] = "MultiLineString"
+		obj["coordinates"] = spatial.MLineToSlice(v)
+	case gmstypes.MultiPolygon:
+		obj["type"] = "MultiPolygon"
+		obj["coordinates"] = spatial.MPolyToSlice(v)
+	case gmstypes.GeomColl:
+		obj["type"] = "GeometryCollection"
+		obj["geometries"] = spatial.GeomCollToSlice(v)
+	}
+	if g.GetSRID() != gmstypes.CartesianSRID {
+		obj["crs"] = GeoJSONCRS(g.GetSRID())
+	}
+	return obj
+}
+
+// GeoJSONCRS returns the crs member for GeoJSON objects whose coordinates are in the SRID |srid|
+func GeoJSONCRS(srid uint32) map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "name",
+		"properties": map[string]interface{}{"name": fmt.Sprintf("EPSG:%d", srid)},
+	}
+}
+
+// SRIDFromGeoJSONCRS returns the SRID named by the crs member of the GeoJSON object |obj|. Names of the forms
+// EPSG:<srid>, urn:ogc:def:crs:EPSG::<srid> and urn:ogc:def:crs:OGC:1.3:CRS84 are understood. |ok| is false if |obj|
+// has no crs member.
+func SRIDFromGeoJSONCRS(obj map[string]interface{}) (srid uint32, ok bool, err error) {
+	crs, ok := obj["crs"]
+	if !ok || crs == nil {
+		return 0, false, nil
+	}
+
+	crsObj, _ := crs.(map[string]interface{})
+	props, _ := crsObj["properties"].(map[string]interface{})
+	name, _ := props["name"].(string)
+	if name == "" {
+		return 0, false, fmt.Errorf("unsupported crs, only named crs like EPSG:4326 are supported")
+	}
+
+	upper := strings.ToUpper(name)
+	if strings.HasSuffix(upper, "CRS84") {
+		return gmstypes.GeoSpatialSRID, true, nil
+	}
+	i := strings.LastIndex(upper, "EPSG:")
+	if i < 0 {
+		return 0, false, fmt.Errorf("unsupported crs '%s'", name)
+	}
+	n, err := strconv.ParseUint(strings.TrimLeft(upper[i+len("EPSG:"):], ":"), 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("unsupported crs '%s'", name)
+	}
+	return uint32(n), true, nil
+}
+
+// ParseSpatial returns the spatial value of |v|, which is being written to a column of the spatial type |typ|. |v|
+// may be a GeoJSON object, or a string that is one of:
+//   - well-known text, which may be prefixed with SRID=<srid>;
+//   - 0x followed by the hex of the value in MySQL's internal format, as exported by dolt
+//   - the hex of the value's well-known binary, which may be PostGIS's EWKB
+//   - the text of a GeoJSON object
+//
+// Values that don't give their SRID have the SRID of |typ|, or 0 if |typ| doesn't define one.
+func ParseSpatial(v interface{}, typ sql.Type) (gmstypes.GeometryValue, error) {
+	srid := gmstypes.CartesianSRID
+	if st, ok := typ.(sql.SpatialColumnType); ok {
+		if s, defined := st.GetSpatialTypeSRID(); defined {
+			srid = s
+		}
+	}
+
+	switch val := v.(type) {
+	case gmstypes.GeometryValue:
+		return val, nil
+	case map[string]interface{}:
+		return geoJSONToSpatial(val, srid)
+	case string:
+		s := strings.TrimSpace(val)
+		switch {
+		case strings.HasPrefix(s, "{"):
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(s), &obj); err != nil {
+				return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+			}
+			return geoJSONToSpatial(obj, srid)
+		case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+			buf, err := hex.DecodeString(s[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid spatial value '%s'", val)
+			}
+			return internalToSpatial(buf)
+		case isHex(s):
+			return wkbToSpatial(s, srid)
+		default:
+			return wktToSpatial(s, srid)
+		}
+	default:
+		return nil, fmt.Errorf("invalid spatial value '%v'", v)
+	}
+}
+
+func isHex(s string) bool {
+	if len(s) == 0 || len(s)%2 != 0 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// wktToSpatial parses the well-known text |s|, whose SRID is |srid| unless it starts with SRID=<srid>;
+func wktToSpatial(s string, srid uint32) (gmstypes.GeometryValue, error) {
+	wkt := s
+	if strings.HasPrefix(strings.ToUpper(wkt), "SRID=") {
+		i := strings.IndexByte(wkt, ';')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid spatial value '%s'", s)
+		}
+		n, err := strconv.ParseUint(wkt[len("SRID="):i], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spatial value '%s'", s)
+		}
+		srid, wkt = uint32(n), strings.TrimSpace(wkt[i+1:])
+	}
+
+	geomType, data, end, err := spatial.ParseWKTHeader(wkt)
+	if err != nil || end != len(wkt) {
+		return nil, fmt.Errorf("invalid spatial value '%s'", s)
+	}
+
+	var g gmstypes.GeometryValue
+	switch geomType {
+	case "point":
+		g, err = spatial.WKTToPoint(data, srid, false)
+	case "linestring":
+		g, err = spatial.WKTToLine(data, srid, false)
+	case "polygon":
+		g, err = spatial.WKTToPoly(data, srid, false)
+	case "multipoint":
+		g, err = spatial.WKTToMPoint(data, srid, false)
+	case "multilinestring":
+		g, err = spatial.WKTToMLine(data, srid, false)
+	case "multipolygon":
+		g, err = spatial.WKTToMPoly(data, srid, false)
+	case "geometrycollection":
+		g, err = spatial.WKTToGeomColl(data, srid, false)
+	default:
+		return nil, fmt.Errorf("invalid spatial value '%s'", s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid spatial value '%s'", s)
+	}
+	return g, nil
+}
+
+// wkbToSpatial parses the hex of the well-known binary |s|, whose SRID is |srid| unless it's EWKB with an SRID
+func wkbToSpatial(s string, srid uint32) (gmstypes.GeometryValue, error) {
+	wkb, err := hex.DecodeString(s)
+	hdr := gmstypes.WKBHeaderSize
+	if err != nil || len(wkb) < hdr {
+		return nil, fmt.Errorf("invalid spatial value '%s'", s)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if wkb[0] == 0 {
+		order = binary.BigEndian
+	}
+	typ := order.Uint32(wkb[gmstypes.EndianSize:])
+	if typ&ewkbSRIDFlag != 0 {
+		if len(wkb) < hdr+gmstypes.SRIDSize {
+			return nil, fmt.Errorf("invalid spatial value '%s'", s)
+		}
+		srid = order.Uint32(wkb[hdr:])
+		stripped := make([]byte, 0, len(wkb)-gmstypes.SRIDSize)
+		stripped = append(stripped, wkb[:hdr]...)
+		stripped = append(stripped, wkb[hdr+gmstypes.SRIDSize:]...)
+		order.PutUint32(stripped[gmstypes.EndianSize:], typ&^ewkbSRIDFlag)
+		wkb = stripped
+	}
+
+	buf := make([]byte, gmstypes.SRIDSize, gmstypes.SRIDSize+len(wkb))
+	binary.LittleEndian.PutUint32(buf, srid)
+	g, err := internalToSpatial(append(buf, wkb...))
+	if err != nil {
+		return nil, fmt.Errorf("invalid spatial value '%s'", s)
+	}
+	return g, nil
+}
+
+// internalToSpatial deserializes |buf|, which is a spatial value in MySQL's internal format
+func internalToSpatial(buf []byte) (gmstypes.GeometryValue, error) {
+	g, _, err := gmstypes.GeometryType{}.Convert(buf)
+	if err != nil {
+		return nil, err
+	}
+	return g.(gmstypes.GeometryValue), nil
+}
+
+// geoJSONToSpatial returns the value of the GeoJSON geometry or feature |obj|, whose SRID is |srid| unless it has a
+// crs member
+func geoJSONToSpatial(obj map[string]interface{}, srid uint32) (gmstypes.GeometryValue, error) {
+	s, ok, err := SRIDFromGeoJSONCRS(obj)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		srid = s
+	}
+
+	res, _, err := spatial.ParseGeoJsonData(obj)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	g, ok := res.(gmstypes.GeometryValue)
+	if !ok {
+		return nil, fmt.Errorf("invalid GeoJSON")
+	}
+	return g.SetSRID(srid), nil
+}
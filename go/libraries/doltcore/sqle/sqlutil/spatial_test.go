@@ -0,0 +1,91 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlutil
+
+import (
+	"testing"
+
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpatial(t *testing.T) {
+	point := gmstypes.Point{X: 1, Y: 2}
+	point4326 := gmstypes.Point{SRID: 4326, X: 1, Y: 2}
+	line := gmstypes.LineString{SRID: 3857, Points: []gmstypes.Point{{SRID: 3857}, {SRID: 3857, X: 1, Y: 1}}}
+
+	tests := []struct {
+		name     string
+		val      interface{}
+		typ      gmstypes.GeometryType
+		expected gmstypes.GeometryValue
+	}{
+		{"wkt", "POINT(1 2)", gmstypes.GeometryType{}, point},
+		{"lower case wkt", " point (1 2) ", gmstypes.GeometryType{}, point},
+		{"wkt gets the srid of the column", "POINT(1 2)", gmstypes.GeometryType{SRID: 4326, DefinedSRID: true}, point4326},
+		{"ewkt", "SRID=3857;LINESTRING(0 0, 1 1)", gmstypes.GeometryType{}, line},
+		{"internal hex", "0x000000000101000000000000000000F03F0000000000000040", gmstypes.GeometryType{}, point},
+		{"wkb", "0101000000000000000000F03F0000000000000040", gmstypes.GeometryType{}, point},
+		{"big endian wkb", "00000000013FF00000000000004000000000000000", gmstypes.GeometryType{}, point},
+		{"ewkb", "0101000020E6100000000000000000F03F0000000000000040", gmstypes.GeometryType{}, point4326},
+		{"geojson", `{"type": "Point", "coordinates": [1, 2]}`, gmstypes.GeometryType{}, point},
+		{"geojson with a crs", map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []interface{}{1.0, 2.0},
+			"crs":         map[string]interface{}{"type": "name", "properties": map[string]interface{}{"name": "urn:ogc:def:crs:OGC:1.3:CRS84"}},
+		}, gmstypes.GeometryType{}, point4326},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, err := ParseSpatial(test.val, test.typ)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, g)
+		})
+	}
+
+	for _, s := range []string{"POINT(1)", "POINT(1 2) x", "SRID=x;POINT(1 2)", "0x01", "01", "{", "nonsense"} {
+		_, err := ParseSpatial(s, gmstypes.GeometryType{})
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSpatialToStr(t *testing.T) {
+	point := gmstypes.Point{X: 1, Y: 2}
+	point4326 := gmstypes.Point{SRID: 4326, X: 1, Y: 2}
+	poly := gmstypes.Polygon{Lines: []gmstypes.LineString{{Points: []gmstypes.Point{{}, {X: 1}, {X: 1, Y: 1}, {}}}}}
+
+	assert.Equal(t, "0x000000000101000000000000000000F03F0000000000000040", SpatialToStr(point, SpatialHex))
+	assert.Equal(t, "POINT(1 2)", SpatialToStr(point, SpatialWKT))
+	assert.Equal(t, "SRID=4326;POINT(1 2)", SpatialToStr(point4326, SpatialWKT))
+	assert.Equal(t, "POLYGON((0 0,1 0,1 1,0 0))", SpatialToStr(poly, SpatialWKT))
+	assert.Equal(t, "0101000000000000000000F03F0000000000000040", SpatialToStr(point, SpatialWKB))
+	assert.Equal(t, "0101000020E6100000000000000000F03F0000000000000040", SpatialToStr(point4326, SpatialWKB))
+
+	for _, g := range []gmstypes.GeometryValue{point, point4326, poly} {
+		for _, f := range []SpatialFormat{SpatialHex, SpatialWKT, SpatialWKB} {
+			parsed, err := ParseSpatial(SpatialToStr(g, f), gmstypes.GeometryType{})
+			require.NoError(t, err)
+			assert.Equal(t, g, parsed)
+		}
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"type":        "Point",
+		"coordinates": [2]float64{1, 2},
+		"crs":         map[string]interface{}{"type": "name", "properties": map[string]interface{}{"name": "EPSG:4326"}},
+	}, SpatialToGeoJSON(point4326))
+}
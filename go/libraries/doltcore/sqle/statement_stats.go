@@ -0,0 +1,58 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// withRowsReadStats wraps the row iterator given to count the rows it returns towards the rows read by the current
+// statement, when @@dolt_statement_stats is enabled.
+func withRowsReadStats(ctx *sql.Context, iter sql.RowIter) sql.RowIter {
+	if !dsess.IsStatementStatsEnabled() {
+		return iter
+	}
+	sess, ok := ctx.Session.(*dsess.DoltSession)
+	if !ok {
+		return iter
+	}
+	return &rowsReadIter{RowIter: iter, sess: sess}
+}
+
+// rowsReadIter counts the rows returned by a row iterator, adding them to the session's statement counters when it's
+// closed.
+type rowsReadIter struct {
+	sql.RowIter
+	sess *dsess.DoltSession
+	rows int64
+}
+
+var _ sql.RowIter = (*rowsReadIter)(nil)
+
+func (itr *rowsReadIter) Next(ctx *sql.Context) (sql.Row, error) {
+	r, err := itr.RowIter.Next(ctx)
+	if err == nil {
+		itr.rows++
+	}
+	return r, err
+}
+
+func (itr *rowsReadIter) Close(ctx *sql.Context) error {
+	itr.sess.RecordRowsRead(itr.rows)
+	itr.rows = 0
+	return itr.RowIter.Close(ctx)
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statspro
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/cespare/xxhash"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+const (
+	// maxBuckets is the number of buckets in the histogram of a numeric column.
+	maxBuckets = 32
+	// sampleSize is the number of values of a numeric column sampled to build its histogram.
+	sampleSize = 1 << 16
+)
+
+// columnCollector accumulates the statistics of a single column while the rows of a table are scanned.
+type columnCollector struct {
+	numeric  bool
+	hist     sql.Histogram
+	distinct map[uint64]struct{}
+	sample   []float64
+	seen     int
+	rng      *rand.Rand
+}
+
+func newColumnCollector(col *sql.Column) *columnCollector {
+	return &columnCollector{
+		numeric:  types.IsNumber(col.Type),
+		hist:     sql.Histogram{Min: math.MaxFloat64, Max: -math.MaxFloat64},
+		distinct: make(map[uint64]struct{}),
+		rng:      rand.New(rand.NewSource(1)),
+	}
+}
+
+func (c *columnCollector) add(v interface{}) {
+	if v == nil {
+		c.hist.NullCount++
+		return
+	}
+	c.hist.Count++
+	c.distinct[hashValue(v)] = struct{}{}
+	if !c.numeric {
+		return
+	}
+
+	f, _, err := types.Float64.Convert(v)
+	if err != nil {
+		return
+	}
+	x := f.(float64)
+	c.hist.Mean += x
+	c.hist.Min = math.Min(c.hist.Min, x)
+	c.hist.Max = math.Max(c.hist.Max, x)
+
+	// reservoir sampling keeps a uniform sample of the column's values in bounded memory
+	c.seen++
+	if len(c.sample) < sampleSize {
+		c.sample = append(c.sample, x)
+	} else if i := c.rng.Intn(c.seen); i < sampleSize {
+		c.sample[i] = x
+	}
+}
+
+func (c *columnCollector) histogram() *sql.Histogram {
+	h := c.hist
+	h.DistinctCount = uint64(len(c.distinct))
+	if c.seen == 0 {
+		h.Min, h.Max, h.Mean = 0, 0, 0
+		return &h
+	}
+	h.Mean /= float64(c.seen)
+	h.Buckets = buildBuckets(c.sample)
+	return &h
+}
+
+// buildBuckets builds an equi-height histogram of |sample|. Each bucket holds roughly the same number of values, and
+// no value is split across two buckets, so columns with few distinct values get one bucket per value.
+func buildBuckets(sample []float64) []*sql.HistogramBucket {
+	if len(sample) == 0 {
+		return nil
+	}
+	sort.Float64s(sample)
+	total := float64(len(sample))
+	height := (len(sample) + maxBuckets - 1) / maxBuckets
+
+	var buckets []*sql.HistogramBucket
+	for start := 0; start < len(sample); {
+		end := start + height
+		if end > len(sample) {
+			end = len(sample)
+		}
+		for end < len(sample) && sample[end] == sample[end-1] {
+			end++
+		}
+		buckets = append(buckets, &sql.HistogramBucket{
+			LowerBound: sample[start],
+			UpperBound: sample[end-1],
+			Frequency:  float64(end-start) / total,
+		})
+		start = end
+	}
+	return buckets
+}
+
+func hashValue(v interface{}) uint64 {
+	switch v := v.(type) {
+	case string:
+		return xxhash.Sum64String(v)
+	case []byte:
+		return xxhash.Sum64(v)
+	default:
+		return xxhash.Sum64String(fmt.Sprint(v))
+	}
+}
+
+// collectTableStats scans every row of |t| to compute its row count, and the null count, number of distinct values
+// and, for numeric columns, the histogram of each of its columns.
+func collectTableStats(ctx *sql.Context, t sql.Table) (*sql.TableStatistics, error) {
+	sch := t.Schema()
+	cols := make([]*columnCollector, len(sch))
+	for i, col := range sch {
+		cols[i] = newColumnCollector(col)
+	}
+
+	var rowCount uint64
+	parts, err := t.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer parts.Close(ctx)
+	for {
+		part, err := parts.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		err = func() error {
+			iter, err := t.PartitionRows(ctx, part)
+			if err != nil {
+				return err
+			}
+			defer iter.Close(ctx)
+			for {
+				row, err := iter.Next(ctx)
+				if err == io.EOF {
+					return nil
+				} else if err != nil {
+					return err
+				}
+				rowCount++
+				for i, c := range cols {
+					c.add(row[i])
+				}
+			}
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	histograms := make(sql.HistogramMap, len(sch))
+	for i, col := range sch {
+		histograms[col.Name] = cols[i].histogram()
+	}
+	return &sql.TableStatistics{
+		RowCount:   rowCount,
+		CreatedAt:  time.Now().UTC(),
+		Histograms: histograms,
+	}, nil
+}
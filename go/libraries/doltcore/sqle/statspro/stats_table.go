@@ -0,0 +1,288 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statspro
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/information_schema"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// StatsFile is the file, relative to the root directory of a database, that holds the statistics of its tables.
+var StatsFile = filepath.Join(dbfactory.DoltDir, "statistics.json")
+
+// FileSystemProvider returns the file system rooted at the directory of a database.
+type FileSystemProvider interface {
+	FileSystemForDatabase(dbName string) (filesys.Filesys, error)
+}
+
+// dbStats are the statistics of the tables of a database, by branch and then by lower case table name.
+type dbStats map[string]map[string]*sql.TableStatistics
+
+// informationSchemaDatabase replaces the statistics table of the information_schema database with a StatsTable.
+type informationSchemaDatabase struct {
+	sql.Database
+	fs FileSystemProvider
+	mu *sync.Mutex
+}
+
+var _ sql.Database = informationSchemaDatabase{}
+
+// NewInformationSchemaDatabase wraps the information_schema database |isDb| so that the statistics the analyzer reads
+// are the ones computed by ANALYZE TABLE and stored alongside each database in |fs|.
+func NewInformationSchemaDatabase(isDb sql.Database, fs FileSystemProvider) sql.Database {
+	return informationSchemaDatabase{Database: isDb, fs: fs, mu: &sync.Mutex{}}
+}
+
+func (db informationSchemaDatabase) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	t, ok, err := db.Database.GetTableInsensitive(ctx, tblName)
+	if err != nil || !ok || !strings.EqualFold(tblName, information_schema.StatisticsTableName) {
+		return t, ok, err
+	}
+	inner, ok := t.(sql.StatsReadWriter)
+	if !ok {
+		return nil, false, fmt.Errorf("information_schema.statistics does not implement sql.StatsReadWriter")
+	}
+	return &StatsTable{StatsReadWriter: inner, fs: db.fs, mu: db.mu}, true, nil
+}
+
+// StatsTable is the information_schema.statistics table. It persists the table statistics computed by ANALYZE TABLE
+// in the directory of each database, keyed by branch, so that they survive restarts and are shared by every session.
+// Rows of the table itself are the index statistics of the wrapped table.
+type StatsTable struct {
+	sql.StatsReadWriter
+	fs      FileSystemProvider
+	mu      *sync.Mutex
+	catalog sql.Catalog
+}
+
+var _ sql.StatsReadWriter = (*StatsTable)(nil)
+
+// AssignCatalog implements sql.CatalogTable
+func (s *StatsTable) AssignCatalog(cat sql.Catalog) sql.Table {
+	ns := *s
+	ns.StatsReadWriter = s.StatsReadWriter.AssignCatalog(cat).(sql.StatsReadWriter)
+	ns.catalog = cat
+	return &ns
+}
+
+// PartitionRows implements sql.Table. It fills in the cardinality of the index statistics of analyzed tables, which
+// is estimated from the number of distinct values of the columns of each index prefix.
+func (s *StatsTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	iter, err := s.StatsReadWriter.PartitionRows(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := sql.RowIterToRows(ctx, nil, iter)
+	if err != nil {
+		return nil, err
+	}
+
+	hists := make(map[[2]string]*sql.TableStatistics)
+	var card float64
+	for _, row := range rows {
+		db, _ := row[1].(string)
+		table, _ := row[2].(string)
+		seq, _ := row[6].(int)
+		col, _ := row[7].(string)
+		key := [2]string{db, table}
+		ts, ok := hists[key]
+		if !ok {
+			ts = s.analyzedStats(ctx, db, table)
+			hists[key] = ts
+		}
+		if ts == nil {
+			continue
+		}
+		if seq == 1 {
+			card = 1
+		}
+		hist, ok := ts.Histograms[col]
+		if !ok || card == 0 {
+			// a column of the prefix was added after the table was analyzed
+			card = 0
+			continue
+		}
+		// columns are assumed to be independent, so the distinct values of a prefix are the product of the distinct
+		// values of its columns, up to the number of rows
+		card = math.Min(card*math.Max(float64(hist.DistinctCount), 1), float64(ts.RowCount))
+		row[9] = int64(card)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// analyzedStats returns the statistics of |table| in |db| if it has been analyzed, or nil.
+func (s *StatsTable) analyzedStats(ctx *sql.Context, db, table string) *sql.TableStatistics {
+	t, sqlDb, err := s.catalog.Table(ctx, db, table)
+	if err != nil {
+		return nil
+	}
+	ts, err := s.tableStats(ctx, sqlDb, t.Name())
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+// Hist implements sql.StatsReader
+func (s *StatsTable) Hist(ctx *sql.Context, db, table string) (sql.HistogramMap, error) {
+	t, sqlDb, err := s.catalog.Table(ctx, db, table)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := s.tableStats(ctx, sqlDb, t.Name())
+	if err != nil {
+		return nil, err
+	}
+	if ts == nil {
+		return nil, fmt.Errorf("histogram not found for table '%s.%s'", db, table)
+	}
+	for _, col := range t.Schema() {
+		if _, ok := ts.Histograms[col.Name]; !ok {
+			return nil, fmt.Errorf("histogram for table '%s.%s' is out of date, run ANALYZE TABLE to refresh it", db, table)
+		}
+	}
+	return ts.Histograms, nil
+}
+
+// RowCount implements sql.StatsReader. The live row count of tables that know it is always exact, so the analyzed
+// row count is only used for tables that don't.
+func (s *StatsTable) RowCount(ctx *sql.Context, db, table string) (uint64, bool, error) {
+	t, sqlDb, err := s.catalog.Table(ctx, db, table)
+	if err != nil {
+		return 0, false, err
+	}
+	if st, ok := t.(sql.StatisticsTable); ok {
+		cnt, err := st.RowCount(ctx)
+		if err != nil {
+			return 0, false, err
+		}
+		return cnt, true, nil
+	}
+	ts, err := s.tableStats(ctx, sqlDb, t.Name())
+	if err != nil || ts == nil {
+		return 0, false, err
+	}
+	return ts.RowCount, true, nil
+}
+
+// Analyze implements sql.StatsWriter
+func (s *StatsTable) Analyze(ctx *sql.Context, db, table string) error {
+	sqlDb, err := s.catalog.Database(ctx, db)
+	if err != nil {
+		return err
+	}
+	t, _, err := s.catalog.DatabaseTable(ctx, sqlDb, table)
+	if err != nil {
+		return err
+	}
+	ts, err := collectTableStats(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, base, branch, err := s.statsLocation(ctx, sqlDb)
+	if err != nil {
+		return err
+	}
+	stats, err := readStats(fs)
+	if err != nil {
+		return err
+	}
+	if stats[branch] == nil {
+		stats[branch] = make(map[string]*sql.TableStatistics)
+	}
+	stats[branch][strings.ToLower(t.Name())] = ts
+	if err = writeStats(fs, stats); err != nil {
+		return fmt.Errorf("error writing statistics of database %s: %w", base, err)
+	}
+	return nil
+}
+
+// tableStats returns the statistics of |table| in |db|, or nil if it hasn't been analyzed.
+func (s *StatsTable) tableStats(ctx *sql.Context, db sql.Database, table string) (*sql.TableStatistics, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, _, branch, err := s.statsLocation(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := readStats(fs)
+	if err != nil {
+		return nil, err
+	}
+	return stats[branch][strings.ToLower(table)], nil
+}
+
+// statsLocation returns the file system the statistics of |db| are stored in, along with the name of the database
+// and the branch they are stored under.
+func (s *StatsTable) statsLocation(ctx *sql.Context, db sql.Database) (fs filesys.Filesys, base, branch string, err error) {
+	base = db.Name()
+	if rdb, ok := db.(dsess.RevisionDatabase); ok {
+		base, branch = dsess.SplitRevisionDbName(rdb.RevisionQualifiedName())
+	}
+	if sess, ok := ctx.Session.(*dsess.DoltSession); ok && branch == "" {
+		if branch, _, err = sess.CurrentHead(ctx, base); err != nil {
+			return nil, "", "", err
+		}
+	}
+	fs, err = s.fs.FileSystemForDatabase(base)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if fs == nil {
+		return nil, "", "", fmt.Errorf("database %s does not support table statistics", base)
+	}
+	return fs, base, branch, nil
+}
+
+func readStats(fs filesys.Filesys) (dbStats, error) {
+	stats := make(dbStats)
+	if ok, _ := fs.Exists(StatsFile); !ok {
+		return stats, nil
+	}
+	data, err := fs.ReadFile(StatsFile)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", StatsFile, err)
+	}
+	return stats, nil
+}
+
+func writeStats(fs filesys.Filesys, stats dbStats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	tmp := StatsFile + ".tmp"
+	if err = fs.WriteFile(tmp, data); err != nil {
+		return err
+	}
+	return fs.MoveFile(tmp, StatsFile)
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statspro
+
+import (
+	"testing"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+type testFileSystems map[string]filesys.Filesys
+
+func (fs testFileSystems) FileSystemForDatabase(dbName string) (filesys.Filesys, error) {
+	if f, ok := fs[dbName]; ok {
+		return f, nil
+	}
+	return nil, sql.ErrDatabaseNotFound.New(dbName)
+}
+
+func TestBuildBuckets(t *testing.T) {
+	assert.Nil(t, buildBuckets(nil))
+
+	buckets := buildBuckets([]float64{3, 1, 2, 1})
+	require.Len(t, buckets, 3)
+	assert.Equal(t, sql.HistogramBucket{LowerBound: 1, UpperBound: 1, Frequency: 0.5}, *buckets[0])
+	assert.Equal(t, sql.HistogramBucket{LowerBound: 3, UpperBound: 3, Frequency: 0.25}, *buckets[2])
+
+	sample := make([]float64, 1000)
+	for i := range sample {
+		sample[i] = float64(i % 500)
+	}
+	buckets = buildBuckets(sample)
+	assert.LessOrEqual(t, len(buckets), maxBuckets)
+	var freq float64
+	for i, b := range buckets {
+		freq += b.Frequency
+		if i > 0 {
+			assert.Less(t, buckets[i-1].UpperBound, b.LowerBound)
+		}
+	}
+	assert.InDelta(t, 1.0, freq, 1e-9)
+}
+
+func TestAnalyzeTable(t *testing.T) {
+	fs := testFileSystems{"mydb": filesys.EmptyInMemFS("/")}
+	newEngine := func() *sqle.Engine {
+		db := memory.NewDatabase("mydb")
+		e := sqle.NewDefault(memory.NewDBProvider(db))
+		e.Analyzer.Catalog.InfoSchema = NewInformationSchemaDatabase(e.Analyzer.Catalog.InfoSchema, fs)
+		return e
+	}
+	query := func(e *sqle.Engine, q string) []sql.Row {
+		ctx := sql.NewEmptyContext()
+		ctx.SetCurrentDatabase("mydb")
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(t, err)
+		return rows
+	}
+
+	e := newEngine()
+	query(e, "create table t (pk int primary key, a int, b varchar(10), key ab (a, b))")
+	query(e, "insert into t values (1, 1, 'x'), (2, 1, 'y'), (3, 2, null), (4, 5, 'x')")
+	assert.Equal(t, []sql.Row{
+		{"ab", 1, int64(0)},
+		{"ab", 2, int64(0)},
+	}, query(e, "select index_name, seq_in_index, cardinality from information_schema.statistics order by 1, 2"))
+
+	query(e, "analyze table t")
+	ok, _ := fs["mydb"].Exists(StatsFile)
+	assert.True(t, ok)
+
+	stats, err := e.Analyzer.Catalog.Statistics(sql.NewEmptyContext())
+	require.NoError(t, err)
+	hist, err := stats.Hist(sql.NewEmptyContext(), "mydb", "t")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), hist["a"].DistinctCount)
+	assert.Equal(t, uint64(2), hist["b"].DistinctCount)
+	assert.Equal(t, uint64(1), hist["b"].NullCount)
+	assert.Equal(t, 2.25, hist["a"].Mean)
+	assert.Equal(t, 5.0, hist["a"].Max)
+	assert.Len(t, hist["pk"].Buckets, 4)
+
+	assert.Equal(t, []sql.Row{
+		{"ab", 1, int64(3)},
+		{"ab", 2, int64(4)},
+	}, query(e, "select index_name, seq_in_index, cardinality from information_schema.statistics order by 1, 2"))
+
+	// the memory database doesn't persist its tables, but the statistics of the new table are loaded from the
+	// previous analysis
+	e = newEngine()
+	query(e, "create table t (pk int primary key, a int, b varchar(10), c int, key ab (a, b))")
+	assert.Equal(t, []sql.Row{
+		{"ab", 1, int64(3)},
+		{"ab", 2, int64(4)},
+	}, query(e, "select index_name, seq_in_index, cardinality from information_schema.statistics order by 1, 2"))
+
+	// c wasn't analyzed, so the histograms are out of date
+	stats, err = e.Analyzer.Catalog.Statistics(sql.NewEmptyContext())
+	require.NoError(t, err)
+	_, err = stats.Hist(sql.NewEmptyContext(), "mydb", "t")
+	assert.Error(t, err)
+}
@@ -166,6 +166,62 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemBoolType(dsess.ShowBranchDatabases),
 			Default:           int8(0),
 		},
+		{ // If true, pins every database read by this session to the root it had when the variable was set.
+			Name:              dsess.SnapshotSession,
+			Scope:             sql.SystemVariableScope_Session,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.SnapshotSession),
+			Default:           int8(0),
+		},
+		{ // If true, the commit of each statement's transaction is deferred until the variable is unset or DOLT_COMMIT is called.
+			Name:              dsess.BulkLoad,
+			Scope:             sql.SystemVariableScope_Session,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.BulkLoad),
+			Default:           int8(0),
+		},
+		{ // A commit spec or timestamp that tables are read as of in queries without an AS OF clause.
+			Name:              dsess.DefaultAsOf,
+			Scope:             sql.SystemVariableScope_Session,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.DefaultAsOf),
+			Default:           "",
+		},
+		{ // If true, DDL takes exclusive metadata locks on tables and DML takes shared ones, held until transaction end.
+			Name:              dsess.MetadataLocksEnabled,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.MetadataLocksEnabled),
+			Default:           int8(0),
+		},
+		{ // If true, the resources used by statements are aggregated by statement digest in dolt_statement_stats.
+			Name:              dsess.StatementStatsEnabled,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.StatementStatsEnabled),
+			Default:           int8(0),
+		},
+		{ // The number of distinct statements tracked in dolt_statement_stats before the least recently seen are evicted.
+			Name:              dsess.StatementStatsMaxStatements,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.StatementStatsMaxStatements, 0, 1000000, false),
+			Default:           int64(1000),
+		},
+		{ // If true, DOLT_MERGE deletes the merged branch once the merge has been committed, as if --delete-source were given.
+			Name:              dsess.MergeDeleteSource,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.MergeDeleteSource),
+			Default:           int8(0),
+		},
 		{
 			Name:    dsess.DoltClusterAckWritesTimeoutSecs,
 			Dynamic: true,
@@ -370,6 +370,10 @@ func (t *DoltTable) sqlSchema() sql.PrimaryKeySchema {
 
 // Partitions returns the partitions for this table.
 func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	if err := t.lockMetadataForRead(ctx); err != nil {
+		return nil, err
+	}
+
 	table, err := t.DoltTable(ctx)
 	if err != nil {
 		return nil, err
@@ -387,6 +391,15 @@ func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
 	return newDoltTablePartitionIter(rows, partitions...), nil
 }
 
+// lockMetadataForRead takes a shared metadata lock on this table for the current transaction, unless the table is
+// locked to a root value, which schema changes can't affect.
+func (t *DoltTable) lockMetadataForRead(ctx *sql.Context) error {
+	if t.lockedToRoot != nil {
+		return nil
+	}
+	return lockTableMetadata(ctx, t.db, t.tableName, false)
+}
+
 func (t *DoltTable) IsTemporary() bool {
 	return false
 }
@@ -444,7 +457,12 @@ func (t *DoltTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sq
 		return nil, err
 	}
 
-	return partitionRows(ctx, table, t.sqlSch.Schema, t.projectedCols, partition)
+	iter, err := partitionRows(ctx, table, t.sqlSch.Schema, t.projectedCols, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRowsReadStats(ctx, iter), nil
 }
 
 func partitionRows(ctx *sql.Context, t *doltdb.Table, sqlSch sql.Schema, projCols []uint64, partition sql.Partition) (sql.RowIter, error) {
@@ -508,6 +526,10 @@ func (t *WritableDoltTable) Inserter(ctx *sql.Context) sql.RowInserter {
 }
 
 func (t *WritableDoltTable) getTableEditor(ctx *sql.Context) (ed writer.TableWriter, err error) {
+	if err := lockTableMetadata(ctx, t.db, t.tableName, false); err != nil {
+		return nil, err
+	}
+
 	ds := dsess.DSessFromSess(ctx.Session)
 
 	state, _, err := ds.LookupDbState(ctx, t.db.RevisionQualifiedName())
@@ -554,6 +576,9 @@ func (t *WritableDoltTable) Truncate(ctx *sql.Context) (int, error) {
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return 0, err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return 0, err
+	}
 	table, err := t.DoltTable.DoltTable(ctx)
 	if err != nil {
 		return 0, err
@@ -1090,6 +1115,9 @@ func (t *AlterableDoltTable) AddColumn(ctx *sql.Context, column *sql.Column, ord
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
@@ -1227,6 +1255,9 @@ func (t *AlterableDoltTable) RewriteInserter(
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return nil, err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return nil, err
+	}
 	err := validateSchemaChange(t.Name(), oldSchema, newSchema, oldColumn, newColumn, idxCols)
 	if err != nil {
 		return nil, err
@@ -1353,7 +1384,7 @@ func (t *AlterableDoltTable) RewriteInserter(
 
 	// We can't just call getTableEditor here because it uses the session state, which we can't update until after the
 	// rewrite operation
-	opts := dbState.WriteSession().GetOptions()
+	opts := dbState.EditOpts()
 	opts.ForeignKeyChecksDisabled = true
 
 	newRoot, err := ws.WorkingRoot().PutTable(ctx, t.Name(), dt)
@@ -1604,6 +1635,9 @@ func (t *AlterableDoltTable) ModifyColumn(ctx *sql.Context, columnName string, c
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	ws, err := t.db.GetWorkingSet(ctx)
 	if err != nil {
 		return err
@@ -1771,6 +1805,9 @@ func (t *AlterableDoltTable) CreateIndex(ctx *sql.Context, idx sql.IndexDef) err
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	if idx.Constraint != sql.IndexConstraint_None && idx.Constraint != sql.IndexConstraint_Unique && idx.Constraint != sql.IndexConstraint_Spatial {
 		return fmt.Errorf("only the following types of index constraints are supported: none, unique, spatial")
 	}
@@ -1846,6 +1883,9 @@ func (t *AlterableDoltTable) DropIndex(ctx *sql.Context, indexName string) error
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	// We disallow removing internal dolt_ tables from SQL directly
 	if strings.HasPrefix(indexName, "dolt_") {
 		return fmt.Errorf("dolt internal indexes may not be dropped")
@@ -1874,6 +1914,9 @@ func (t *AlterableDoltTable) RenameIndex(ctx *sql.Context, fromIndexName string,
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	// RenameIndex will error if there is a name collision or an index does not exist
 	_, err := t.sch.Indexes().RenameIndex(fromIndexName, toIndexName)
 	if err != nil {
@@ -2012,6 +2055,9 @@ func (t *AlterableDoltTable) AddForeignKey(ctx *sql.Context, sqlFk sql.ForeignKe
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	// empty string foreign key names are replaced with a generated name elsewhere
 	if sqlFk.Name != "" && !doltdb.IsValidIdentifier(sqlFk.Name) {
 		return fmt.Errorf("invalid foreign key name `%s`", sqlFk.Name)
@@ -2071,6 +2117,9 @@ func (t *AlterableDoltTable) DropForeignKey(ctx *sql.Context, fkName string) err
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
@@ -2099,6 +2148,9 @@ func (t *AlterableDoltTable) UpdateForeignKey(ctx *sql.Context, fkName string, s
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
@@ -2386,6 +2438,9 @@ func (t *AlterableDoltTable) CreateCheck(ctx *sql.Context, check *sql.CheckDefin
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
@@ -2442,6 +2497,9 @@ func (t *AlterableDoltTable) DropCheck(ctx *sql.Context, chName string) error {
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
@@ -2493,6 +2551,9 @@ func (t *AlterableDoltTable) ModifyDefaultCollation(ctx *sql.Context, collation
 	if err := dsess.CheckAccessForDb(ctx, t.db, branch_control.Permissions_Write); err != nil {
 		return err
 	}
+	if err := lockTableMetadata(ctx, t.db, t.tableName, true); err != nil {
+		return err
+	}
 	root, err := t.getRoot(ctx)
 	if err != nil {
 		return err
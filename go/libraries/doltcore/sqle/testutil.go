@@ -81,7 +81,7 @@ func ExecuteSql(dEnv *env.DoltEnv, root *doltdb.RootValue, statements string) (*
 			return nil, errors.New("Show statements aren't handled")
 		case *sqlparser.Select, *sqlparser.OtherRead:
 			return nil, errors.New("Select statements aren't handled")
-		case *sqlparser.Insert:
+		case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
 			var rowIter sql.RowIter
 			_, rowIter, execErr = engine.Query(ctx, query)
 			if execErr == nil {
@@ -132,6 +132,7 @@ func NewTestEngine(dEnv *env.DoltEnv, ctx context.Context, db dsess.SqlDatabase)
 	}
 
 	engine := sqle.NewDefault(pro)
+	AddAnalyzerRules(engine.Analyzer)
 	sqlCtx := NewTestSQLCtxWithProvider(ctx, pro)
 	sqlCtx.SetCurrentDatabase(db.Name())
 	return engine, sqlCtx, nil
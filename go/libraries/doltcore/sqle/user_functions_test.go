@@ -0,0 +1,108 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+)
+
+func TestUserFunctions(t *testing.T) {
+	ctx := context.Background()
+	dEnv := CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	db, err := NewDatabase(ctx, "dolt", dEnv.DbData(), editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir})
+	require.NoError(t, err)
+	engine, sqlCtx, err := NewTestEngine(dEnv, ctx, db)
+	require.NoError(t, err)
+
+	require.NoError(t, dfunctions.RegisterFunction(sql.Function1{Name: "test_shout", Fn: function.NewUpper}))
+	assert.Error(t, dfunctions.RegisterFunction(sql.Function1{Name: "test_shout", Fn: function.NewUpper}))
+	assert.Error(t, dfunctions.RegisterFunction(sql.Function1{Name: "upper", Fn: function.NewUpper}))
+	assert.Error(t, dfunctions.RegisterFunction(sql.Function1{Name: "dolt_shout", Fn: function.NewUpper}))
+	assert.Error(t, dfunctions.RegisterTableFunction(&LogTableFunction{}))
+	assert.Error(t, dfunctions.RegisterTableFunction(namedTableFunction{name: "JSON_TABLE"}))
+
+	// a plugin's functions are only registered if all of them can be
+	err = dfunctions.RegisterPlugin(testPlugin{
+		funcs:      []sql.Function{sql.Function1{Name: "test_whisper", Fn: function.NewLower}},
+		tableFuncs: []sql.TableFunction{namedTableFunction{name: "test_log"}, namedTableFunction{name: "sequence_table"}},
+	})
+	assert.Error(t, err)
+	_, ok := dfunctions.UserFunction("test_whisper")
+	assert.False(t, ok)
+	_, ok = dfunctions.UserTableFunction("test_log")
+	assert.False(t, ok)
+	assert.Error(t, dfunctions.RegisterPlugin(testPlugin{
+		funcs: []sql.Function{
+			sql.Function1{Name: "test_whisper", Fn: function.NewLower},
+			sql.Function1{Name: "TEST_WHISPER", Fn: function.NewLower},
+		},
+	}))
+	_, ok = dfunctions.UserFunction("test_whisper")
+	assert.False(t, ok)
+	require.NoError(t, dfunctions.RegisterPlugin(testPlugin{
+		funcs:      []sql.Function{sql.Function1{Name: "test_whisper", Fn: function.NewLower}},
+		tableFuncs: []sql.TableFunction{namedTableFunction{name: "test_log"}},
+	}))
+	_, ok = dfunctions.UserTableFunction("test_log")
+	assert.True(t, ok)
+
+	sch, iter, err := engine.Query(sqlCtx, "select test_shout('hello')")
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(sqlCtx, sch, iter)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"HELLO"}}, rows)
+
+	sch, iter, err = engine.Query(sqlCtx, "select test_whisper('HELLO')")
+	require.NoError(t, err)
+	rows, err = sql.RowIterToRows(sqlCtx, sch, iter)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"hello"}}, rows)
+}
+
+type testPlugin struct {
+	funcs      []sql.Function
+	tableFuncs []sql.TableFunction
+}
+
+func (p testPlugin) Functions() []sql.Function {
+	return p.funcs
+}
+
+func (p testPlugin) TableFunctions() []sql.TableFunction {
+	return p.tableFuncs
+}
+
+// namedTableFunction is a LogTableFunction with another name
+type namedTableFunction struct {
+	*LogTableFunction
+	name string
+}
+
+func (tf namedTableFunction) Name() string {
+	return tf.name
+}
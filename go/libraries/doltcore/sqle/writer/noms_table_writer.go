@@ -71,11 +71,16 @@ func (te *nomsTableWriter) duplicateKeyErrFunc(keyString, indexName string, k, v
 	return sql.NewUniqueKeyErr(keyString, isPk, oldRow)
 }
 
-func (te *nomsTableWriter) Insert(ctx *sql.Context, sqlRow sql.Row) error {
+func (te *nomsTableWriter) Insert(ctx *sql.Context, sqlRow sql.Row) (err error) {
 	if schema.IsKeyless(te.sch) {
-		return te.keylessInsert(ctx, sqlRow)
+		err = te.keylessInsert(ctx, sqlRow)
+	} else {
+		err = te.keyedInsert(ctx, sqlRow)
+	}
+	if err == nil {
+		recordRowWritten(ctx)
 	}
-	return te.keyedInsert(ctx, sqlRow)
+	return err
 }
 
 func (te *nomsTableWriter) keylessInsert(ctx *sql.Context, sqlRow sql.Row) error {
@@ -100,14 +105,20 @@ func (te *nomsTableWriter) Delete(ctx *sql.Context, sqlRow sql.Row) error {
 		if err != nil {
 			return err
 		}
-		return te.tableEditor.DeleteByKey(ctx, k, tagToVal)
+		if err = te.tableEditor.DeleteByKey(ctx, k, tagToVal); err != nil {
+			return err
+		}
 	} else {
 		dRow, err := sqlutil.SqlRowToDoltRow(ctx, te.vrw, sqlRow, te.sch)
 		if err != nil {
 			return err
 		}
-		return te.tableEditor.DeleteRow(ctx, dRow)
+		if err = te.tableEditor.DeleteRow(ctx, dRow); err != nil {
+			return err
+		}
 	}
+	recordRowWritten(ctx)
+	return nil
 }
 
 func (te *nomsTableWriter) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) error {
@@ -120,11 +131,19 @@ func (te *nomsTableWriter) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.R
 		return err
 	}
 
-	return te.tableEditor.UpdateRow(ctx, dOldRow, dNewRow, te.duplicateKeyErrFunc)
+	if err = te.tableEditor.UpdateRow(ctx, dOldRow, dNewRow, te.duplicateKeyErrFunc); err != nil {
+		return err
+	}
+	recordRowWritten(ctx)
+	return nil
 }
 
 func (te *nomsTableWriter) GetNextAutoIncrementValue(ctx *sql.Context, insertVal interface{}) (uint64, error) {
-	return te.autoInc.Next(te.tableName, insertVal)
+	increment, offset, err := globalstate.AutoIncrementInterleaving(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return te.autoInc.NextInterleaved(te.tableName, insertVal, increment, offset)
 }
 
 func (te *nomsTableWriter) SetAutoIncrementValue(ctx *sql.Context, val uint64) error {
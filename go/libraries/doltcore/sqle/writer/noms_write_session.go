@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -54,6 +55,18 @@ type WriteSessionFlusher interface {
 	Flush(ctx context.Context) (*doltdb.WorkingSet, error)
 }
 
+// RowsWrittenRecorder is implemented by sessions that count the rows written by their current statement
+type RowsWrittenRecorder interface {
+	RecordRowsWritten(n int64)
+}
+
+// recordRowWritten counts a row written by the current statement of the session of the context given, if it counts them
+func recordRowWritten(ctx *sql.Context) {
+	if r, ok := ctx.Session.(RowsWrittenRecorder); ok {
+		r.RecordRowsWritten(1)
+	}
+}
+
 // nomsWriteSession handles all edit operations on a table that may also update other tables.
 // Serves as coordination for SessionedTableEditors.
 type nomsWriteSession struct {
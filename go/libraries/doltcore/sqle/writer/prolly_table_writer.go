@@ -153,6 +153,7 @@ func (w *prollyTableWriter) Insert(ctx *sql.Context, sqlRow sql.Row) (err error)
 	if err = w.primary.Insert(ctx, sqlRow); err != nil {
 		return err
 	}
+	recordRowWritten(ctx)
 	return nil
 }
 
@@ -166,6 +167,7 @@ func (w *prollyTableWriter) Delete(ctx *sql.Context, sqlRow sql.Row) (err error)
 	if err := w.primary.Delete(ctx, sqlRow); err != nil {
 		return err
 	}
+	recordRowWritten(ctx)
 	return nil
 }
 
@@ -182,12 +184,17 @@ func (w *prollyTableWriter) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.
 	if err := w.primary.Update(ctx, oldRow, newRow); err != nil {
 		return err
 	}
+	recordRowWritten(ctx)
 	return nil
 }
 
 // GetNextAutoIncrementValue implements TableWriter.
 func (w *prollyTableWriter) GetNextAutoIncrementValue(ctx *sql.Context, insertVal interface{}) (uint64, error) {
-	return w.aiTracker.Next(w.tableName, insertVal)
+	increment, offset, err := globalstate.AutoIncrementInterleaving(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return w.aiTracker.NextInterleaved(w.tableName, insertVal, increment, offset)
 }
 
 // SetAutoIncrementValue implements TableWriter.
@@ -161,13 +161,9 @@ func (s *prollyWriteSession) flush(ctx context.Context) (*doltdb.WorkingSet, err
 		return nil, err
 	}
 
-	var err error
-	flushed := s.workingSet.WorkingRoot()
-	for name, tbl := range tables {
-		flushed, err = flushed.PutTable(ctx, name, tbl)
-		if err != nil {
-			return nil, err
-		}
+	flushed, err := s.workingSet.WorkingRoot().PutTables(ctx, tables)
+	if err != nil {
+		return nil, err
 	}
 
 	s.workingSet = s.workingSet.WithWorkingRoot(flushed)
@@ -167,7 +167,8 @@ func BuildSecondaryIndex(ctx context.Context, tbl *doltdb.Table, idx schema.Inde
 }
 
 // BuildSecondaryProllyIndex builds secondary index data for the given primary
-// index row data |primary|. |sch| is the current schema of the table.
+// index row data |primary|. |sch| is the current schema of the table. Large
+// tables are indexed by several workers concurrently.
 func BuildSecondaryProllyIndex(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, sch schema.Schema, idx schema.Index, primary prolly.Map) (durable.Index, error) {
 	if idx.IsUnique() {
 		kd := idx.Schema().GetKeyDescriptor()
@@ -177,6 +178,14 @@ func BuildSecondaryProllyIndex(ctx context.Context, vrw types.ValueReadWriter, n
 		})
 	}
 
+	workers, err := indexBuildWorkers(primary)
+	if err != nil {
+		return nil, err
+	}
+	if workers > 1 {
+		return buildProllyIndexParallel(ctx, vrw, ns, sch, idx, primary, workers, nil)
+	}
+
 	empty, err := durable.NewEmptyIndex(ctx, vrw, ns, idx.Schema())
 	if err != nil {
 		return nil, err
@@ -223,8 +232,18 @@ type DupEntryCb func(ctx context.Context, existingKey, newKey val.Tuple) error
 
 // BuildUniqueProllyIndex builds a unique index based on the given |primary| row
 // data. If any duplicate entries are found, they are passed to |cb|. If |cb|
-// returns a non-nil error then the process is stopped.
+// returns a non-nil error then the process is stopped. Large tables are indexed
+// by several workers concurrently, in which case duplicates are found in index
+// order rather than in primary key order.
 func BuildUniqueProllyIndex(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, sch schema.Schema, idx schema.Index, primary prolly.Map, cb DupEntryCb) (durable.Index, error) {
+	workers, err := indexBuildWorkers(primary)
+	if err != nil {
+		return nil, err
+	}
+	if workers > 1 {
+		return buildProllyIndexParallel(ctx, vrw, ns, sch, idx, primary, workers, cb)
+	}
+
 	empty, err := durable.NewEmptyIndex(ctx, vrw, ns, idx.Schema())
 	if err != nil {
 		return nil, err
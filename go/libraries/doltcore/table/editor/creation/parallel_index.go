@@ -0,0 +1,234 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creation
+
+import (
+	"container/heap"
+	"context"
+	"io"
+	"runtime"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// indexBuildRowsPerWorker is the fewest rows each worker building a secondary index is given. Tables with fewer than
+// twice this many rows are indexed by a single worker.
+var indexBuildRowsPerWorker = 1 << 17
+
+// indexBuildWorkers returns the number of workers to build a secondary index of the rows in |primary| with.
+func indexBuildWorkers(primary prolly.Map) (int, error) {
+	cnt, err := primary.Count()
+	if err != nil {
+		return 0, err
+	}
+	workers := cnt / indexBuildRowsPerWorker
+	if max := runtime.GOMAXPROCS(0); workers > max {
+		workers = max
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers, nil
+}
+
+// buildProllyIndexParallel builds secondary index data for the rows of |primary| with |workers| workers. The rows are
+// partitioned into contiguous ranges of the primary index, each worker builds the index entries of one range, and the
+// entries of every range are then merged in order into the index. If |cb| isn't nil the index is unique: entries with
+// nulls in their indexed columns are skipped, and |cb| is called for each entry with the same indexed columns as the
+// one before it.
+func buildProllyIndexParallel(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, sch schema.Schema, idx schema.Index, primary prolly.Map, workers int, cb DupEntryCb) (durable.Index, error) {
+	cnt, err := primary.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]prolly.Map, workers)
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i := 0; i < workers; i++ {
+		i := i
+		start, stop := uint64(cnt*i/workers), uint64(cnt*(i+1)/workers)
+		eg.Go(func() (err error) {
+			parts[i], err = buildIndexPartition(egCtx, vrw, ns, sch, idx, primary, start, stop, cb != nil)
+			return err
+		})
+	}
+	if err = eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	kd, vd := parts[0].Descriptors()
+	iter, err := newPartitionMergeIter(ctx, parts, kd.PrefixDesc(idx.Count()), cb)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := prolly.NewMapFromTupleIter(ctx, ns, kd, vd, iter)
+	if err != nil {
+		return nil, err
+	}
+	if iter.err != nil {
+		return nil, iter.err
+	}
+	return durable.IndexFromProllyMap(secondary), nil
+}
+
+// buildIndexPartition returns a map of the secondary index entries of the rows of |primary| from the ordinal |start|
+// up to |stop|.
+func buildIndexPartition(ctx context.Context, vrw types.ValueReadWriter, ns tree.NodeStore, sch schema.Schema, idx schema.Index, primary prolly.Map, start, stop uint64, skipNulls bool) (prolly.Map, error) {
+	empty, err := durable.NewEmptyIndex(ctx, vrw, ns, idx.Schema())
+	if err != nil {
+		return prolly.Map{}, err
+	}
+	secondary := durable.ProllyMapFromIndex(empty)
+	if schema.IsKeyless(sch) {
+		secondary = prolly.ConvertToSecondaryKeylessIndex(secondary)
+	}
+
+	prefixDesc := secondary.KeyDesc().PrefixDesc(idx.Count())
+	secondaryBld := index.NewSecondaryKeyBuilder(sch, idx, secondary.KeyDesc(), primary.Pool())
+
+	iter, err := primary.IterOrdinalRange(ctx, start, stop)
+	if err != nil {
+		return prolly.Map{}, err
+	}
+
+	mut := secondary.Mutate()
+	for {
+		k, v, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return prolly.Map{}, err
+		}
+
+		idxKey := secondaryBld.SecondaryKeyFromRow(k, v)
+		if skipNulls && prefixDesc.HasNulls(idxKey) {
+			continue
+		}
+		if err = mut.Put(ctx, idxKey, val.EmptyTuple); err != nil {
+			return prolly.Map{}, err
+		}
+	}
+	return mut.Map(ctx)
+}
+
+// partitionMergeIter is a prolly.TupleIter of the entries of several secondary index maps, in order.
+type partitionMergeIter struct {
+	heap       partitionHeap
+	prefixDesc val.TupleDesc
+	cb         DupEntryCb
+	prev       val.Tuple
+	// err is the error that ended the iteration early, if any
+	err error
+}
+
+var _ prolly.TupleIter = (*partitionMergeIter)(nil)
+
+func newPartitionMergeIter(ctx context.Context, parts []prolly.Map, prefixDesc val.TupleDesc, cb DupEntryCb) (*partitionMergeIter, error) {
+	kd := parts[0].KeyDesc()
+	it := &partitionMergeIter{heap: partitionHeap{kd: kd}, prefixDesc: prefixDesc, cb: cb}
+	for _, m := range parts {
+		iter, err := m.IterAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c := &partitionCursor{iter: iter}
+		if ok, err := c.advance(ctx); err != nil {
+			return nil, err
+		} else if ok {
+			it.heap.cursors = append(it.heap.cursors, c)
+		}
+	}
+	heap.Init(&it.heap)
+	return it, nil
+}
+
+// Next implements prolly.TupleIter
+func (it *partitionMergeIter) Next(ctx context.Context) (k, v val.Tuple) {
+	if it.err != nil || it.heap.Len() == 0 {
+		return nil, nil
+	}
+
+	c := it.heap.cursors[0]
+	k, v = c.k, c.v
+	if it.cb != nil && it.prev != nil && it.prefixDesc.Compare(it.prev, k) == 0 {
+		if it.err = it.cb(ctx, it.prev, k); it.err != nil {
+			return nil, nil
+		}
+	} else {
+		it.prev = k
+	}
+
+	if ok, err := c.advance(ctx); err != nil {
+		it.err = err
+		return nil, nil
+	} else if ok {
+		heap.Fix(&it.heap, 0)
+	} else {
+		heap.Pop(&it.heap)
+	}
+	return k, v
+}
+
+type partitionCursor struct {
+	iter prolly.MapIter
+	k, v val.Tuple
+}
+
+func (c *partitionCursor) advance(ctx context.Context) (bool, error) {
+	k, v, err := c.iter.Next(ctx)
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	c.k, c.v = k, v
+	return true, nil
+}
+
+// partitionHeap is a heap.Interface of partition cursors ordered by their current key.
+type partitionHeap struct {
+	cursors []*partitionCursor
+	kd      val.TupleDesc
+}
+
+func (h partitionHeap) Len() int {
+	return len(h.cursors)
+}
+
+func (h partitionHeap) Less(i, j int) bool {
+	return h.kd.Compare(h.cursors[i].k, h.cursors[j].k) < 0
+}
+
+func (h partitionHeap) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+func (h *partitionHeap) Push(x interface{}) {
+	h.cursors = append(h.cursors, x.(*partitionCursor))
+}
+
+func (h *partitionHeap) Pop() interface{} {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return last
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package creation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb/durable"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/prolly"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+const testRows = 1000
+
+func testPrimary(t *testing.T, ctx context.Context, ns tree.NodeStore, sch schema.Schema, c func(i int) int64) prolly.Map {
+	kd, vd := sch.GetMapDescriptors()
+	kb, vb := val.NewTupleBuilder(kd), val.NewTupleBuilder(vd)
+	tups := make([]val.Tuple, 0, testRows*2)
+	for i := 0; i < testRows; i++ {
+		kb.PutInt64(0, int64(i))
+		vb.PutInt64(0, c(i))
+		tups = append(tups, kb.Build(ns.Pool()), vb.Build(ns.Pool()))
+	}
+	m, err := prolly.NewMapFromTuples(ctx, ns, kd, vd, tups...)
+	require.NoError(t, err)
+	return m
+}
+
+func TestBuildIndexParallel(t *testing.T) {
+	if !types.IsFormat_DOLT(types.Format_Default) {
+		t.Skip()
+	}
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	ns := tree.NewTestNodeStore()
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("pk", 1, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("c", 2, types.IntKind, false),
+	))
+	require.NoError(t, err)
+	idx, err := sch.Indexes().AddIndexByColTags("idx_c", []uint64{2}, nil, schema.IndexProperties{IsUserDefined: true})
+	require.NoError(t, err)
+	uniq, err := sch.Indexes().AddIndexByColTags("uniq_c", []uint64{2}, nil, schema.IndexProperties{IsUnique: true, IsUserDefined: true})
+	require.NoError(t, err)
+
+	reversed := testPrimary(t, ctx, ns, sch, func(i int) int64 { return int64(testRows - i) })
+	dups := testPrimary(t, ctx, ns, sch, func(i int) int64 { return int64(i % (testRows / 2)) })
+
+	build := func(rowsPerWorker int, idx schema.Index, primary prolly.Map) (hash.Hash, int) {
+		defer func(orig int) { indexBuildRowsPerWorker = orig }(indexBuildRowsPerWorker)
+		indexBuildRowsPerWorker = rowsPerWorker
+
+		var built durable.Index
+		var err error
+		var dupCount int
+		if idx.IsUnique() {
+			built, err = BuildUniqueProllyIndex(ctx, vrw, ns, sch, idx, primary, func(ctx context.Context, existingKey, newKey val.Tuple) error {
+				dupCount++
+				return nil
+			})
+		} else {
+			built, err = BuildSecondaryProllyIndex(ctx, vrw, ns, sch, idx, primary)
+		}
+		require.NoError(t, err)
+		return durable.ProllyMapFromIndex(built).HashOf(), dupCount
+	}
+
+	for _, primary := range []prolly.Map{reversed, dups} {
+		for _, i := range []schema.Index{idx, uniq} {
+			sequentialHash, sequentialDups := build(testRows, i, primary)
+			parallelHash, parallelDups := build(testRows/8, i, primary)
+			assert.Equal(t, sequentialHash, parallelHash)
+			assert.Equal(t, sequentialDups, parallelDups)
+		}
+	}
+
+	// a duplicate entry stops a unique index build with the error returned by the callback
+	defer func(orig int) { indexBuildRowsPerWorker = orig }(indexBuildRowsPerWorker)
+	indexBuildRowsPerWorker = testRows / 8
+	_, err = BuildSecondaryProllyIndex(ctx, vrw, ns, sch, uniq, dups)
+	assert.Error(t, err)
+}
@@ -0,0 +1,315 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const defaultFlattenSeparator = "_"
+
+// FlattenSpec describes how the nested fields of JSON objects are mapped to the columns of a table. It is read from a
+// file of the form:
+//
+//	{
+//	  "columns": {"user_id": "$.user.id", "first_tag": "$.tags[0]"},
+//	  "flatten": true,
+//	  "separator": "_",
+//	  "remainder": "extra"
+//	}
+//
+// |columns| sets a column to the value at a JSONPath. If |flatten| is true, nested objects are flattened into columns
+// named by joining the keys on the path to each field with |separator|, so {"user": {"name": "x"}} sets the column
+// user_name. Fields that aren't written to any column are collected into a JSON object that is written to the
+// |remainder| column. Without a remainder column, such fields are an error.
+type FlattenSpec struct {
+	Columns   map[string]string `json:"columns,omitempty"`
+	Flatten   bool              `json:"flatten,omitempty"`
+	Separator string            `json:"separator,omitempty"`
+	Remainder string            `json:"remainder,omitempty"`
+
+	paths map[string]jsonPath
+}
+
+// FlattenSpecFromFile reads a FlattenSpec from the json file at |path|
+func FlattenSpecFromFile(path string, fs filesys.ReadableFS) (*FlattenSpec, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec FlattenSpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid flatten spec '%s': %w", path, err)
+	}
+	return &spec, nil
+}
+
+// validate checks that this spec can be used to import into a table with the schema |sch|, and parses its paths
+func (spec *FlattenSpec) validate(sch schema.Schema) error {
+	if spec.Separator == "" {
+		spec.Separator = defaultFlattenSeparator
+	}
+
+	cols := sch.GetAllCols()
+	spec.paths = make(map[string]jsonPath, len(spec.Columns))
+	for col, pathStr := range spec.Columns {
+		if _, ok := cols.GetByName(col); !ok {
+			return fmt.Errorf("flatten spec column '%s' is not in the table", col)
+		}
+		path, err := parseJSONPath(pathStr)
+		if err != nil {
+			return fmt.Errorf("flatten spec column '%s': %w", col, err)
+		}
+		spec.paths[col] = path
+	}
+
+	if spec.Remainder != "" {
+		if _, ok := cols.GetByName(spec.Remainder); !ok {
+			return fmt.Errorf("flatten spec remainder column '%s' is not in the table", spec.Remainder)
+		}
+		if _, ok := spec.Columns[spec.Remainder]; ok {
+			return fmt.Errorf("flatten spec remainder column '%s' cannot also be extracted from a path", spec.Remainder)
+		}
+	}
+
+	return nil
+}
+
+// apply maps the fields of |obj| to columns of |cols|, returning the value of each column that is set. Fields that are
+// written to a column are removed from |obj|, so that it holds the remainder afterwards.
+func (spec *FlattenSpec) apply(obj map[string]interface{}, cols *schema.ColCollection) map[string]interface{} {
+	vals := make(map[string]interface{})
+
+	for _, col := range sortedKeys(spec.paths) {
+		if v, ok := spec.paths[col].extract(obj); ok {
+			vals[col] = v
+		}
+	}
+
+	spec.applyFields(obj, "", cols, vals)
+	return vals
+}
+
+// applyFields writes each field of |obj| whose name, prefixed by |prefix|, is a column to |vals|, recursing into
+// nested objects when flattening.
+func (spec *FlattenSpec) applyFields(obj map[string]interface{}, prefix string, cols *schema.ColCollection, vals map[string]interface{}) {
+	for _, k := range sortedKeys(obj) {
+		name := prefix + k
+		if name == spec.Remainder {
+			continue
+		}
+
+		if col, ok := cols.GetByName(name); ok {
+			if _, set := vals[col.Name]; !set {
+				vals[col.Name] = obj[k]
+				delete(obj, k)
+			}
+			continue
+		}
+
+		if nested, ok := obj[k].(map[string]interface{}); ok && spec.Flatten {
+			spec.applyFields(nested, name+spec.Separator, cols, vals)
+			if len(nested) == 0 {
+				delete(obj, k)
+			}
+		}
+	}
+}
+
+// nest is the inverse of apply, used when exporting rows. It merges the fields of the remainder column into |vals|, and
+// then moves the value of each column extracted from a path to that path. Columns that were flattened on import are
+// left as they are, since their names don't say whether they were flattened.
+func (spec *FlattenSpec) nest(vals map[string]interface{}) error {
+	if spec.Remainder != "" {
+		rem, ok := vals[spec.Remainder]
+		delete(vals, spec.Remainder)
+		if obj, isObj := rem.(map[string]interface{}); isObj {
+			for k, v := range obj {
+				if _, set := vals[k]; !set {
+					vals[k] = v
+				}
+			}
+		} else if ok && rem != nil {
+			return fmt.Errorf("remainder column '%s' is not a JSON object", spec.Remainder)
+		}
+	}
+
+	for _, col := range sortedKeys(spec.paths) {
+		v, ok := vals[col]
+		if !ok {
+			continue
+		}
+		delete(vals, col)
+		if _, err := spec.paths[col].insert(vals, v); err != nil {
+			return fmt.Errorf("column '%s': %w", col, err)
+		}
+	}
+	return nil
+}
+
+// jsonPath is a parsed JSONPath of the form $.key['other key'][0]. Only member and index selectors are supported.
+type jsonPath []pathElem
+
+type pathElem struct {
+	key   string
+	idx   int
+	isIdx bool
+}
+
+func parseJSONPath(s string) (jsonPath, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("invalid JSONPath '%s': must start with '$'", s)
+	}
+
+	var path jsonPath
+	rest := s[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("invalid JSONPath '%s': empty member name", s)
+			}
+			path = append(path, pathElem{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath '%s': unclosed '['", s)
+			}
+			sel := rest[1:end]
+			if len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0] {
+				path = append(path, pathElem{key: sel[1 : len(sel)-1]})
+			} else if idx, err := strconv.Atoi(sel); err == nil && idx >= 0 {
+				path = append(path, pathElem{idx: idx, isIdx: true})
+			} else {
+				return nil, fmt.Errorf("invalid JSONPath '%s': unsupported selector '[%s]'", s, sel)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath '%s': unexpected '%c'", s, rest[0])
+		}
+	}
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid JSONPath '%s': the root object can't be extracted", s)
+	}
+	return path, nil
+}
+
+// extract returns the value at this path in |obj|. A value that is a member of an object is removed from that object,
+// along with any objects containing it that are left empty.
+func (p jsonPath) extract(obj map[string]interface{}) (interface{}, bool) {
+	type member struct {
+		obj map[string]interface{}
+		key string
+	}
+	var members []member
+	inArray := false
+
+	var cur interface{} = obj
+	for _, elem := range p {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[elem.key]
+			if elem.isIdx || !ok {
+				return nil, false
+			}
+			if !inArray {
+				members = append(members, member{c, elem.key})
+			}
+			cur = v
+		case []interface{}:
+			if !elem.isIdx || elem.idx >= len(c) {
+				return nil, false
+			}
+			// values inside arrays are left in place, so the remainder keeps its structure
+			members, inArray = nil, true
+			cur = c[elem.idx]
+		default:
+			return nil, false
+		}
+	}
+
+	for i := len(members) - 1; i >= 0; i-- {
+		delete(members[i].obj, members[i].key)
+		if len(members[i].obj) > 0 {
+			break
+		}
+	}
+	return cur, true
+}
+
+// insert sets the value at this path in |container| to |v|, creating the objects and arrays on the path that don't
+// exist, and returns the updated container. Array elements before an index that is set are null.
+func (p jsonPath) insert(container interface{}, v interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return v, nil
+	}
+
+	elem := p[0]
+	if elem.isIdx {
+		arr, ok := container.([]interface{})
+		if container != nil && !ok {
+			return nil, fmt.Errorf("the value at [%d] conflicts with a field that isn't an array", elem.idx)
+		}
+		for len(arr) <= elem.idx {
+			arr = append(arr, nil)
+		}
+		child, err := p[1:].insert(arr[elem.idx], v)
+		if err != nil {
+			return nil, err
+		}
+		arr[elem.idx] = child
+		return arr, nil
+	}
+
+	obj, ok := container.(map[string]interface{})
+	if container == nil {
+		obj = make(map[string]interface{})
+	} else if !ok {
+		return nil, fmt.Errorf("the value at .%s conflicts with a field that isn't an object", elem.key)
+	}
+	child, err := p[1:].insert(obj[elem.key], v)
+	if err != nil {
+		return nil, err
+	}
+	obj[elem.key] = child
+	return obj, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
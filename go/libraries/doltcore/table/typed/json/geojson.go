@@ -0,0 +1,274 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+const geoJSONHeader = `{"type": "FeatureCollection", "features": [`
+const geoJSONFooter = `]}`
+
+// geometryColumn returns the first spatial column of |sch|, which is the geometry of the features of GeoJSON files
+func geometryColumn(sch schema.Schema) (schema.Column, error) {
+	var geomCol schema.Column
+	found := false
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if _, ok := col.TypeInfo.ToSqlType().(sql.SpatialColumnType); ok {
+			geomCol, found = col, true
+			return true, nil
+		}
+		return false, nil
+	})
+	if !found {
+		return schema.Column{}, errors.New("GeoJSON files can only be used with tables that have a spatial column for the geometry of their features")
+	}
+	return geomCol, nil
+}
+
+// NewGeoJSONWriter returns a new writer that encodes rows as a GeoJSON FeatureCollection. The first spatial column of
+// |outSch| is the geometry of each feature, and the other columns are its properties.
+func NewGeoJSONWriter(wr io.WriteCloser, outSch schema.Schema) (*RowWriter, error) {
+	geomCol, err := geometryColumn(outSch)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := NewJSONWriterWithHeader(wr, outSch, geoJSONHeader, geoJSONFooter, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	w.geometryCol = geomCol.Name
+	return w, nil
+}
+
+// toFeature returns the GeoJSON feature of the row with the column values |colValMap|
+func toFeature(colValMap map[string]interface{}, geometryCol string) map[string]interface{} {
+	geometry := colValMap[geometryCol]
+	delete(colValMap, geometryCol)
+	return map[string]interface{}{
+		"type":       "Feature",
+		"geometry":   geometry,
+		"properties": colValMap,
+	}
+}
+
+// GeoJSONReader reads the features of a GeoJSON FeatureCollection as rows. The geometry of each feature is read into
+// the first spatial column of the schema, and its properties are read into the columns with the same names.
+type GeoJSONReader struct {
+	closer     io.Closer
+	dec        *json.Decoder
+	sch        schema.Schema
+	geomCol    schema.Column
+	crs        interface{}
+	inFeatures bool
+	done       bool
+	featureNum int
+}
+
+var _ table.SqlTableReader = (*GeoJSONReader)(nil)
+
+// OpenGeoJSONReader opens the GeoJSON file at |path|
+func OpenGeoJSONReader(path string, fs filesys.ReadableFS, sch schema.Schema) (*GeoJSONReader, error) {
+	r, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGeoJSONReader(r, sch)
+}
+
+// NewGeoJSONReader returns a reader for the GeoJSON FeatureCollection in |r|
+func NewGeoJSONReader(r io.ReadCloser, sch schema.Schema) (*GeoJSONReader, error) {
+	if sch == nil {
+		return nil, errors.New("schema must be provided to GeoJSONReader")
+	}
+	geomCol, err := geometryColumn(sch)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		r.Close()
+		return nil, errors.New("expected a GeoJSON FeatureCollection object")
+	}
+
+	return &GeoJSONReader{closer: r, dec: dec, sch: sch, geomCol: geomCol}, nil
+}
+
+// Close should release resources being held
+func (r *GeoJSONReader) Close(ctx context.Context) error {
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
+
+		return err
+	}
+	return errors.New("already closed")
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (r *GeoJSONReader) GetSchema() schema.Schema {
+	return r.sch
+}
+
+func (r *GeoJSONReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("deprecated")
+}
+
+// ReadSqlRow reads the next feature. Features with properties that aren't columns of the schema, or with values that
+// can't be converted to their column's type, are returned as bad rows.
+func (r *GeoJSONReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	for !r.done {
+		if r.inFeatures {
+			if !r.dec.More() {
+				if _, err := r.dec.Token(); err != nil {
+					return nil, err
+				}
+				r.inFeatures = false
+				continue
+			}
+
+			var raw json.RawMessage
+			if err := r.dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			r.featureNum++
+
+			ret, reason := r.convToSqlRow(raw)
+			if reason != "" {
+				return sql.Row{string(raw)}, table.NewBadRow(nil, fmt.Sprintf("feature %d: %s", r.featureNum, reason))
+			}
+			return ret, nil
+		}
+
+		if !r.dec.More() {
+			r.done = true
+			break
+		}
+		if err := r.readMember(); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, io.EOF
+}
+
+// readMember reads the next member of the FeatureCollection, stopping at the start of its features
+func (r *GeoJSONReader) readMember() error {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return err
+	}
+	key, _ := tok.(string)
+
+	switch key {
+	case "type":
+		var typ string
+		if err := r.dec.Decode(&typ); err != nil || typ != "FeatureCollection" {
+			return errors.New("expected a GeoJSON FeatureCollection object")
+		}
+	case "crs":
+		if r.featureNum > 0 {
+			return errors.New("the crs of a FeatureCollection must come before its features")
+		}
+		return r.dec.Decode(&r.crs)
+	case "features":
+		if tok, err := r.dec.Token(); err != nil || tok != json.Delim('[') {
+			return errors.New("the features of a FeatureCollection must be an array")
+		}
+		r.inFeatures = true
+	default:
+		var ignored json.RawMessage
+		return r.dec.Decode(&ignored)
+	}
+	return nil
+}
+
+func (r *GeoJSONReader) convToSqlRow(raw json.RawMessage) (sql.Row, string) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var feature map[string]interface{}
+	if err := dec.Decode(&feature); err != nil || feature == nil || feature["type"] != "Feature" {
+		return nil, "not a GeoJSON Feature"
+	}
+
+	allCols := r.sch.GetAllCols()
+	ret := make(sql.Row, allCols.Size())
+
+	props, ok := feature["properties"].(map[string]interface{})
+	if !ok && feature["properties"] != nil {
+		return nil, "properties is not an object"
+	}
+	var unknown []string
+	for name, v := range props {
+		col, ok := allCols.GetByName(name)
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if col.Tag == r.geomCol.Tag {
+			return nil, fmt.Sprintf("property %s has the same name as the geometry column", name)
+		}
+		v, err := toColumnValue(col, v)
+		if err != nil {
+			return nil, fmt.Sprintf("property %s: %s", name, err.Error())
+		}
+		ret[allCols.TagToIdx[col.Tag]] = v
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Sprintf("properties %s are not columns of the table", strings.Join(unknown, ", "))
+	}
+
+	if geometry, ok := feature["geometry"].(map[string]interface{}); ok {
+		// a crs of the feature or of its collection applies to geometries that don't have their own
+		if _, ok := geometry["crs"]; !ok {
+			if crs, ok := feature["crs"]; ok {
+				geometry["crs"] = crs
+			} else if r.crs != nil {
+				geometry["crs"] = r.crs
+			}
+		}
+		v, err := toColumnValue(r.geomCol, geometry)
+		if err != nil {
+			return nil, fmt.Sprintf("geometry: %s", err.Error())
+		}
+		ret[allCols.TagToIdx[r.geomCol.Tag]] = v
+	} else if feature["geometry"] != nil {
+		return nil, "geometry is not an object"
+	}
+
+	return ret, ""
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func geoJSONTestSchema(t *testing.T) schema.Schema {
+	colColl := schema.NewColCollection(
+		schema.Column{Name: "id", Tag: 0, Kind: types.IntKind, IsPartOfPK: true, TypeInfo: typeinfo.Int64Type},
+		schema.Column{Name: "name", Tag: 1, Kind: types.StringKind, TypeInfo: typeinfo.StringDefaultType},
+		schema.Column{Name: "geom", Tag: 2, Kind: types.GeometryKind, TypeInfo: typeinfo.GeometryType},
+	)
+	sch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	return sch
+}
+
+func readGeoJSON(t *testing.T, data string) ([]sql.Row, []string) {
+	reader, err := NewGeoJSONReader(io.NopCloser(strings.NewReader(data)), geoJSONTestSchema(t))
+	require.NoError(t, err)
+	defer reader.Close(context.Background())
+
+	var rows []sql.Row
+	var bad []string
+	for {
+		r, err := reader.ReadSqlRow(context.Background())
+		if err == io.EOF {
+			break
+		} else if table.IsBadRow(err) {
+			bad = append(bad, err.Error())
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+	return rows, bad
+}
+
+func writeGeoJSON(t *testing.T, rows []sql.Row) string {
+	var buf bytes.Buffer
+	wr, err := NewGeoJSONWriter(iohelp.NopWrCloser(&buf), geoJSONTestSchema(t))
+	require.NoError(t, err)
+	for _, r := range rows {
+		require.NoError(t, wr.WriteSqlRow(context.Background(), r))
+	}
+	require.NoError(t, wr.Close(context.Background()))
+	return buf.String()
+}
+
+func TestGeoJSONReader(t *testing.T) {
+	data := `{
+  "type": "FeatureCollection",
+  "name": "places",
+  "crs": {"type": "name", "properties": {"name": "urn:ogc:def:crs:EPSG::3857"}},
+  "features": [
+    {"type": "Feature", "properties": {"id": 1, "name": "a"}, "geometry": {"type": "Point", "coordinates": [1, 2]}},
+    {"type": "Feature", "properties": {"id": 2}, "geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1]],
+      "crs": {"type": "name", "properties": {"name": "EPSG:4326"}}}},
+    {"type": "Feature", "properties": {"id": 3, "name": "c"}, "geometry": null},
+    {"type": "Feature", "properties": {"id": 4, "color": "red"}, "geometry": null},
+    {"type": "Feature", "properties": {"id": 5}, "geometry": {"type": "Point"}}
+  ]
+}`
+	rows, bad := readGeoJSON(t, data)
+	assert.Equal(t, []sql.Row{
+		{int64(1), "a", gmstypes.Point{SRID: 3857, X: 1, Y: 2}},
+		{int64(2), nil, gmstypes.LineString{SRID: 4326, Points: []gmstypes.Point{{SRID: 4326}, {SRID: 4326, X: 1, Y: 1}}}},
+		{int64(3), "c", nil},
+	}, rows)
+	assert.Equal(t, []string{
+		"feature 4: properties color are not columns of the table",
+		"feature 5: geometry: invalid GeoJSON: missing required member 'coordinates'",
+	}, bad)
+
+	_, err := NewGeoJSONReader(io.NopCloser(strings.NewReader(`[]`)), geoJSONTestSchema(t))
+	assert.EqualError(t, err, "expected a GeoJSON FeatureCollection object")
+}
+
+func TestGeoJSONRoundTrip(t *testing.T) {
+	assert.Equal(t, `{"type": "FeatureCollection", "features": []}`, writeGeoJSON(t, nil))
+
+	rows := []sql.Row{
+		{int64(1), "a", gmstypes.Point{X: 1.5, Y: 2}},
+		{int64(2), "b", gmstypes.Point{SRID: 4326, X: -71.06, Y: 42.36}},
+		{int64(3), nil, nil},
+	}
+	out := writeGeoJSON(t, rows)
+	assert.Equal(t, `{"type": "FeatureCollection", "features": [`+
+		`{"geometry":{"coordinates":[1.5,2],"type":"Point"},"properties":{"id":1,"name":"a"},"type":"Feature"},`+
+		`{"geometry":{"coordinates":[-71.06,42.36],"crs":{"properties":{"name":"EPSG:4326"},"type":"name"},"type":"Point"},"properties":{"id":2,"name":"b"},"type":"Feature"},`+
+		`{"geometry":null,"properties":{"id":3},"type":"Feature"}]}`, out)
+
+	read, bad := readGeoJSON(t, out)
+	require.Empty(t, bad)
+	assert.Equal(t, rows, read)
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// JSONLReader reads rows from JSON Lines (newline delimited JSON) data, where each line is a JSON object. The fields
+// of each object are mapped to columns by a FlattenSpec.
+type JSONLReader struct {
+	closer  io.Closer
+	rd      *bufio.Reader
+	sch     schema.Schema
+	spec    *FlattenSpec
+	lineNum int
+}
+
+var _ table.SqlTableReader = (*JSONLReader)(nil)
+
+// OpenJSONLReader opens the JSON Lines file at |path|. |spec| may be nil, in which case the fields of each object
+// must match the columns of |sch|.
+func OpenJSONLReader(path string, fs filesys.ReadableFS, sch schema.Schema, spec *FlattenSpec) (*JSONLReader, error) {
+	r, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONLReader(r, sch, spec)
+}
+
+// NewJSONLReader returns a reader for the JSON Lines data in |r|
+func NewJSONLReader(r io.ReadCloser, sch schema.Schema, spec *FlattenSpec) (*JSONLReader, error) {
+	if sch == nil {
+		return nil, errors.New("schema must be provided to JSONLReader")
+	}
+	if spec == nil {
+		spec = &FlattenSpec{}
+	}
+	if err := spec.validate(sch); err != nil {
+		return nil, err
+	}
+
+	return &JSONLReader{closer: r, rd: bufio.NewReaderSize(r, ReadBufSize), sch: sch, spec: spec}, nil
+}
+
+// Close should release resources being held
+func (r *JSONLReader) Close(ctx context.Context) error {
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
+
+		return err
+	}
+	return errors.New("already closed")
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (r *JSONLReader) GetSchema() schema.Schema {
+	return r.sch
+}
+
+func (r *JSONLReader) ReadRow(ctx context.Context) (row.Row, error) {
+	panic("deprecated")
+}
+
+// ReadSqlRow reads the next line. Lines that aren't JSON objects, that have fields not written to any column, or
+// that have values which can't be converted to their column's type are returned as bad rows.
+func (r *JSONLReader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	for {
+		line, err := r.rd.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF && len(line) == 0 {
+			return nil, io.EOF
+		}
+		r.lineNum++
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		ret, reason := r.convToSqlRow(line)
+		if reason != "" {
+			return sql.Row{line}, table.NewBadRow(nil, fmt.Sprintf("line %d: %s", r.lineNum, reason))
+		}
+		return ret, nil
+	}
+}
+
+func (r *JSONLReader) convToSqlRow(line string) (sql.Row, string) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil || obj == nil {
+		return nil, "not a JSON object"
+	}
+	if dec.More() {
+		return nil, "more than one JSON value on a line"
+	}
+
+	allCols := r.sch.GetAllCols()
+	vals := r.spec.apply(obj, allCols)
+
+	if len(obj) > 0 {
+		if r.spec.Remainder == "" {
+			return nil, fmt.Sprintf("fields %s are not written to any column", strings.Join(sortedKeys(obj), ", "))
+		}
+		vals[r.spec.Remainder] = obj
+	}
+
+	ret := make(sql.Row, allCols.Size())
+	for name, v := range vals {
+		col, _ := allCols.GetByName(name)
+		v, err := toColumnValue(col, v)
+		if err != nil {
+			return nil, fmt.Sprintf("column %s: %s", col.Name, err.Error())
+		}
+		ret[allCols.TagToIdx[col.Tag]] = v
+	}
+
+	return ret, ""
+}
+
+// toColumnValue converts the decoded JSON value |v| to the SQL type of |col|. Objects and arrays are converted from
+// their JSON text, so they can be written to JSON or string columns, and spatial columns are parsed from GeoJSON
+// objects or from strings as described by sqlutil.ParseSpatial.
+func toColumnValue(col schema.Column, v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		v = val.String()
+	case map[string]interface{}, []interface{}:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(val); err != nil {
+			return nil, err
+		}
+		v = strings.TrimSuffix(buf.String(), "\n")
+	case bool:
+		if col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+			v = fmt.Sprint(val)
+		}
+	case string:
+		if col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+			b, err := json.Marshal(val)
+			if err != nil {
+				return nil, err
+			}
+			v = string(b)
+		}
+	}
+
+	typ := col.TypeInfo.ToSqlType()
+	if _, ok := typ.(sql.SpatialColumnType); ok {
+		return sqlutil.ParseSpatial(v, typ)
+	}
+	v, _, err := typ.Convert(v)
+	return v, err
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	sqltypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func jsonlTestSchema(t *testing.T) schema.Schema {
+	colColl := schema.NewColCollection(
+		schema.Column{Name: "id", Tag: 0, Kind: types.IntKind, IsPartOfPK: true, TypeInfo: typeinfo.Int64Type},
+		schema.Column{Name: "user_name", Tag: 1, Kind: types.StringKind, TypeInfo: typeinfo.StringDefaultType},
+		schema.Column{Name: "first_tag", Tag: 2, Kind: types.StringKind, TypeInfo: typeinfo.StringDefaultType},
+		schema.Column{Name: "extra", Tag: 3, Kind: types.JSONKind, TypeInfo: typeinfo.JSONType},
+	)
+	sch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	return sch
+}
+
+func readJSONL(t *testing.T, data string, spec *FlattenSpec) ([]sql.Row, []string) {
+	fs := filesys.EmptyInMemFS("/")
+	require.NoError(t, fs.WriteFile("file.jsonl", []byte(data)))
+
+	reader, err := OpenJSONLReader("file.jsonl", fs, jsonlTestSchema(t), spec)
+	require.NoError(t, err)
+	defer reader.Close(context.Background())
+
+	var rows []sql.Row
+	var bad []string
+	for {
+		r, err := reader.ReadSqlRow(context.Background())
+		if err == io.EOF {
+			break
+		} else if table.IsBadRow(err) {
+			bad = append(bad, r[0].(string))
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+	return rows, bad
+}
+
+func TestJSONLReader(t *testing.T) {
+	data := `{"id": 1, "user": {"name": "tim", "age": 40}, "tags": ["a", "b"]}
+
+{"id": 2, "user": {"name": "brian"}, "tags": ["c"], "other": null}
+[1, 2]
+{"id": "not a number", "user": {"name": "aaron"}}
+`
+	spec := &FlattenSpec{
+		Columns:   map[string]string{"first_tag": "$.tags[0]"},
+		Flatten:   true,
+		Remainder: "extra",
+	}
+
+	rows, bad := readJSONL(t, data, spec)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{`[1, 2]`, `{"id": "not a number", "user": {"name": "aaron"}}`}, bad)
+
+	assert.Equal(t, int64(1), rows[0][0])
+	assert.Equal(t, "tim", rows[0][1])
+	assert.Equal(t, "a", rows[0][2])
+	assert.Equal(t, `{"tags": ["a", "b"], "user": {"age": 40}}`, jsonString(t, rows[0][3]))
+
+	assert.Equal(t, int64(2), rows[1][0])
+	assert.Equal(t, "brian", rows[1][1])
+	assert.Equal(t, "c", rows[1][2])
+	assert.Equal(t, `{"tags": ["c"], "other": null}`, jsonString(t, rows[1][3]))
+}
+
+func TestJSONLReaderWithoutRemainder(t *testing.T) {
+	data := `{"id": 1, "user_name": "tim"}
+{"id": 2, "user_name": "brian", "unknown": true}
+`
+	rows, bad := readJSONL(t, data, nil)
+	require.Len(t, rows, 1)
+	assert.Equal(t, sql.Row{int64(1), "tim", nil, nil}, rows[0])
+	assert.Equal(t, []string{`{"id": 2, "user_name": "brian", "unknown": true}`}, bad)
+}
+
+func TestFlattenSpecValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      FlattenSpec
+		expectErr string
+	}{
+		{name: "valid", spec: FlattenSpec{Columns: map[string]string{"user_name": "$.user['name']"}, Remainder: "extra"}},
+		{name: "unknown column", spec: FlattenSpec{Columns: map[string]string{"nope": "$.a"}}, expectErr: "column 'nope' is not in the table"},
+		{name: "invalid path", spec: FlattenSpec{Columns: map[string]string{"user_name": "user.name"}}, expectErr: "must start with '$'"},
+		{name: "unsupported selector", spec: FlattenSpec{Columns: map[string]string{"user_name": "$.tags[*]"}}, expectErr: "unsupported selector"},
+		{name: "unknown remainder", spec: FlattenSpec{Remainder: "rest"}, expectErr: "remainder column 'rest' is not in the table"},
+		{name: "extracted remainder", spec: FlattenSpec{Columns: map[string]string{"extra": "$.a"}, Remainder: "extra"}, expectErr: "cannot also be extracted"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.spec.validate(jsonlTestSchema(t))
+			if test.expectErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectErr)
+		})
+	}
+}
+
+func jsonString(t *testing.T, v interface{}) string {
+	doc, ok := v.(sqltypes.JSONValue)
+	require.True(t, ok, "expected a JSON value, got %T", v)
+	s, err := doc.ToString(sql.NewEmptyContext())
+	require.NoError(t, err)
+	return s
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
@@ -129,7 +130,7 @@ func (r *JSONReader) convToSqlRow(rowMap map[string]interface{}) (sql.Row, error
 			return nil, fmt.Errorf("column %s not found in schema", k)
 		}
 
-		v, _, err := col.TypeInfo.ToSqlType().Convert(v)
+		v, err := convertValue(col.TypeInfo.ToSqlType(), v)
 		if err != nil {
 			return nil, err
 		}
@@ -140,3 +141,13 @@ func (r *JSONReader) convToSqlRow(rowMap map[string]interface{}) (sql.Row, error
 
 	return ret, nil
 }
+
+// convertValue converts |v| to the SQL type |typ|. Values of spatial columns may be GeoJSON objects or strings, as
+// described by sqlutil.ParseSpatial.
+func convertValue(typ sql.Type, v interface{}) (interface{}, error) {
+	if _, ok := typ.(sql.SpatialColumnType); ok && v != nil {
+		return sqlutil.ParseSpatial(v, typ)
+	}
+	v, _, err := typ.Convert(v)
+	return v, err
+}
@@ -30,6 +30,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 )
@@ -48,6 +49,8 @@ type RowWriter struct {
 	bWr         *bufio.Writer
 	sch         schema.Schema
 	sqlSch      sql.Schema
+	spec        *FlattenSpec
+	geometryCol string
 	rowsWritten int
 }
 
@@ -71,6 +74,37 @@ func NewJSONSqlWriter(wr io.WriteCloser, sch sql.Schema) (*RowWriter, error) {
 	return w, nil
 }
 
+// NewJSONLWriter returns a new writer that encodes rows as JSON Lines, with the JSON object of each row on its own line.
+// If |spec| isn't nil, the columns it extracts from paths on import are written to those paths, and the fields of its
+// remainder column are written to the object of each row.
+func NewJSONLWriter(wr io.WriteCloser, outSch schema.Schema, spec *FlattenSpec) (*RowWriter, error) {
+	if spec != nil {
+		if err := spec.validate(outSch); err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := NewJSONWriterWithHeader(wr, outSch, "", "\n", "\n")
+	if err != nil {
+		return nil, err
+	}
+
+	w.spec = spec
+	return w, nil
+}
+
+// NewJSONLSqlWriter returns a new writer that encodes rows as JSON Lines, with the JSON object of each row on its own
+// line.
+func NewJSONLSqlWriter(wr io.WriteCloser, sch sql.Schema) (*RowWriter, error) {
+	w, err := NewJSONWriterWithHeader(wr, nil, "", "\n", "\n")
+	if err != nil {
+		return nil, err
+	}
+
+	w.sqlSch = sch
+	return w, nil
+}
+
 func NewJSONWriterWithHeader(wr io.WriteCloser, outSch schema.Schema, header, footer, separator string) (*RowWriter, error) {
 	bwr := bufio.NewWriterSize(wr, WriteBufSize)
 	return &RowWriter{
@@ -138,6 +172,10 @@ func (j *RowWriter) jsonDataForSchema(row sql.Row) ([]byte, error) {
 		if val == nil {
 			return false, nil
 		}
+		if g, ok := val.(types.GeometryValue); ok {
+			colValMap[col.Name] = sqlutil.SpatialToGeoJSON(g)
+			return false, nil
+		}
 
 		switch col.TypeInfo.GetTypeIdentifier() {
 		case typeinfo.DatetimeTypeIdentifier,
@@ -176,6 +214,15 @@ func (j *RowWriter) jsonDataForSchema(row sql.Row) ([]byte, error) {
 		return nil, err
 	}
 
+	if j.spec != nil {
+		if err := j.spec.nest(colValMap); err != nil {
+			return nil, err
+		}
+	}
+	if j.geometryCol != "" {
+		return marshalToJson(toFeature(colValMap, j.geometryCol))
+	}
+
 	jsonRowData, err := marshalToJson(colValMap)
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling row to json: %w", err)
@@ -191,6 +238,10 @@ func (j *RowWriter) jsonDataForSqlSchema(row sql.Row) ([]byte, error) {
 		if val == nil {
 			continue
 		}
+		if g, ok := val.(types.GeometryValue); ok {
+			colValMap[col.Name] = sqlutil.SpatialToGeoJSON(g)
+			continue
+		}
 
 		switch col.Type.(type) {
 		case sql.DatetimeType,
@@ -232,7 +283,14 @@ func (j *RowWriter) Flush() error {
 // Close should flush all writes, release resources being held
 func (j *RowWriter) Close(ctx context.Context) error {
 	if j.closer != nil {
-		if j.rowsWritten > 0 {
+		// a feature collection is written even if it has no features, as an empty file isn't valid GeoJSON
+		if j.rowsWritten == 0 && j.geometryCol != "" {
+			err := iohelp.WriteAll(j.bWr, []byte(j.header))
+			if err != nil {
+				return err
+			}
+		}
+		if j.rowsWritten > 0 || j.geometryCol != "" {
 			err := iohelp.WriteAll(j.bWr, []byte(j.footer))
 			if err != nil {
 				return err
@@ -0,0 +1,80 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+func writeJSONL(t *testing.T, rows []sql.Row, spec *FlattenSpec) string {
+	var buf bytes.Buffer
+	wr, err := NewJSONLWriter(iohelp.NopWrCloser(&buf), jsonlTestSchema(t), spec)
+	require.NoError(t, err)
+	for _, r := range rows {
+		require.NoError(t, wr.WriteSqlRow(context.Background(), r))
+	}
+	require.NoError(t, wr.Close(context.Background()))
+	return buf.String()
+}
+
+func TestJSONLWriter(t *testing.T) {
+	assert.Equal(t, "", writeJSONL(t, nil, nil))
+
+	rows := []sql.Row{{int64(1), "tim", nil, nil}, {int64(2), nil, "x", nil}}
+	assert.Equal(t, "{\"id\":1,\"user_name\":\"tim\"}\n{\"first_tag\":\"x\",\"id\":2}\n", writeJSONL(t, rows, nil))
+
+	spec := &FlattenSpec{Columns: map[string]string{"first_tag": "$.tags[1]", "user_name": "$.user.name"}}
+	assert.Equal(t, "{\"id\":1,\"user\":{\"name\":\"tim\"}}\n{\"id\":2,\"tags\":[null,\"x\"]}\n", writeJSONL(t, rows, spec))
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	data := `{"id": 1, "user": {"name": "tim", "age": 40}, "tags": ["a", "b"]}
+{"id": 2, "user": {"name": "brian"}, "tags": ["c"], "other": null}
+`
+	spec := &FlattenSpec{
+		Columns:   map[string]string{"first_tag": "$.tags[0]"},
+		Flatten:   true,
+		Remainder: "extra",
+	}
+	rows, bad := readJSONL(t, data, spec)
+	require.Empty(t, bad)
+
+	// flattened columns keep their names, the rest of each object is restored
+	expected := `{"id":1,"tags":["a","b"],"user":{"age":40},"user_name":"tim"}
+{"id":2,"other":null,"tags":["c"],"user_name":"brian"}
+`
+	assert.Equal(t, expected, writeJSONL(t, rows, spec))
+}
+
+func TestFlattenSpecNestErrors(t *testing.T) {
+	spec := &FlattenSpec{Columns: map[string]string{"first_tag": "$.extra.tag"}}
+	require.NoError(t, spec.validate(jsonlTestSchema(t)))
+
+	err := spec.nest(map[string]interface{}{"first_tag": "a", "extra": "not an object"})
+	assert.EqualError(t, err, "column 'first_tag': the value at .tag conflicts with a field that isn't an object")
+
+	spec = &FlattenSpec{Remainder: "extra"}
+	require.NoError(t, spec.validate(jsonlTestSchema(t)))
+	err = spec.nest(map[string]interface{}{"extra": []interface{}{1}})
+	assert.EqualError(t, err, "remainder column 'extra' is not a JSON object")
+}
@@ -14,6 +14,8 @@
 
 package csv
 
+import "github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+
 // CSVFileInfo describes a csv file
 type CSVFileInfo struct {
 	// Delim says which character is used as a field delimiter
@@ -24,11 +26,20 @@ type CSVFileInfo struct {
 	Columns []string
 	// EscapeQuotes says whether quotes should be escaped when parsing the csv
 	EscapeQuotes bool
+	// Quote is the character used to quote fields
+	Quote string
+	// Escape is the character used to escape a quote, or any other character, inside a quoted field. If it is empty,
+	// a quote is escaped by doubling it.
+	Escape string
+	// Encoding is the name of the character encoding of the file. If it is empty, the file is read as UTF-8.
+	Encoding string
+	// SpatialFormat is the format that spatial values are written in. If it is empty, they are written as hex.
+	SpatialFormat sqlutil.SpatialFormat
 }
 
 // NewCSVInfo creates a new CSVInfo struct with default values
 func NewCSVInfo() *CSVFileInfo {
-	return &CSVFileInfo{",", true, nil, true}
+	return &CSVFileInfo{Delim: ",", HasHeaderLine: true, Columns: nil, EscapeQuotes: true, Quote: `"`}
 }
 
 // SetDelim sets the Delim member and returns the CSVFileInfo
@@ -54,3 +65,27 @@ func (info *CSVFileInfo) SetEscapeQuotes(escapeQuotes bool) *CSVFileInfo {
 	info.EscapeQuotes = escapeQuotes
 	return info
 }
+
+// SetQuote sets the Quote member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetQuote(quote string) *CSVFileInfo {
+	info.Quote = quote
+	return info
+}
+
+// SetEscape sets the Escape member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetEscape(escape string) *CSVFileInfo {
+	info.Escape = escape
+	return info
+}
+
+// SetEncoding sets the Encoding member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetEncoding(encoding string) *CSVFileInfo {
+	info.Encoding = encoding
+	return info
+}
+
+// SetSpatialFormat sets the SpatialFormat member and returns the CSVFileInfo
+func (info *CSVFileInfo) SetSpatialFormat(format sqlutil.SpatialFormat) *CSVFileInfo {
+	info.SpatialFormat = format
+	return info
+}
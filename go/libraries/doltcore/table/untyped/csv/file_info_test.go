@@ -22,7 +22,7 @@ import (
 func TestCSVFileInfo(t *testing.T) {
 	nfo := NewCSVInfo()
 
-	if nfo.Delim != "," || nfo.HasHeaderLine != true || nfo.Columns != nil || !nfo.EscapeQuotes {
+	if nfo.Delim != "," || nfo.HasHeaderLine != true || nfo.Columns != nil || !nfo.EscapeQuotes || nfo.Quote != `"` || nfo.Escape != "" || nfo.Encoding != "" {
 		t.Error("Unexpected values")
 	}
 
@@ -31,9 +31,13 @@ func TestCSVFileInfo(t *testing.T) {
 		SetColumns(testCols).
 		SetDelim("|").
 		SetEscapeQuotes(false).
-		SetHasHeaderLine(false)
+		SetHasHeaderLine(false).
+		SetQuote("'").
+		SetEscape(`\`).
+		SetEncoding("latin1")
 
-	if nfo.Delim != "|" || nfo.HasHeaderLine != false || !reflect.DeepEqual(nfo.Columns, testCols) || nfo.EscapeQuotes {
+	if nfo.Delim != "|" || nfo.HasHeaderLine != false || !reflect.DeepEqual(nfo.Columns, testCols) || nfo.EscapeQuotes ||
+		nfo.Quote != "'" || nfo.Escape != `\` || nfo.Encoding != "latin1" {
 		t.Error("Unexpected values")
 	}
 }
@@ -20,8 +20,8 @@ import (
 	"strings"
 )
 
-func csvSplitLine(str string, delim string, escapedQuotes bool) ([]*string, error) {
-	if strings.IndexRune(delim, '"') != -1 {
+func csvSplitLine(str string, delim string, quote byte, escapedQuotes bool) ([]*string, error) {
+	if strings.IndexByte(delim, quote) != -1 {
 		panic("delims cannot contain quotes")
 	}
 
@@ -34,7 +34,7 @@ func csvSplitLine(str string, delim string, escapedQuotes bool) ([]*string, erro
 	cellStart := 0
 	for !done {
 		remainingStr := str[currPos:]
-		nextQuote := strings.Index(remainingStr, "\"")
+		nextQuote := strings.IndexByte(remainingStr, quote)
 		nextDelim := strings.Index(remainingStr, delim)
 
 		if nextQuote == -1 || !escapedQuotes {
@@ -47,7 +47,7 @@ func csvSplitLine(str string, delim string, escapedQuotes bool) ([]*string, erro
 				done = true
 			}
 
-			tokens = appendToken(tokens, str, cellStart, currPos+nextDelim, escapedQuotes)
+			tokens = appendToken(tokens, str, cellStart, currPos+nextDelim, quote, escapedQuotes)
 			cellStart = currPos + nextDelim + delimLen
 			currPos = cellStart
 		} else if escapedQuotes && nextQuote != -1 && nextQuote != math.MaxInt32 {
@@ -64,7 +64,7 @@ func csvSplitLine(str string, delim string, escapedQuotes bool) ([]*string, erro
 	return tokens, nil
 }
 
-func appendToken(tokens []*string, line string, start, pos int, escapedQuotes bool) []*string {
+func appendToken(tokens []*string, line string, start, pos int, quote byte, escapedQuotes bool) []*string {
 	if pos == start {
 		return append(tokens, nil)
 	}
@@ -85,7 +85,7 @@ func appendToken(tokens []*string, line string, start, pos int, escapedQuotes bo
 	}
 
 	if escapedQuotes {
-		if line[start] == '"' && line[pos-1] == '"' {
+		if line[start] == quote && line[pos-1] == quote {
 			start++
 			pos--
 		} else {
@@ -107,8 +107,8 @@ func appendToken(tokens []*string, line string, start, pos int, escapedQuotes bo
 	for i := start; i < pos; i++ {
 		c := line[i]
 
-		if c == '"' {
-			if i+1 < len(line) && line[i+1] == '"' {
+		if c == quote {
+			if i+1 < len(line) && line[i+1] == quote {
 				token[end] = c
 				end++
 				i++
@@ -56,6 +56,7 @@ func csvReaderRead(s string, delim string) ([]*string, error) {
 		isDone:          false,
 		nbf:             nil,
 		delim:           []byte(delim),
+		quote:           '"',
 		fieldsPerRecord: 0,
 	}
 	strs, err := csvr.csvReadRecords(nil)
@@ -54,6 +54,8 @@ type CSVReader struct {
 	// empty strings, and to use multi-rune delimiters. This adaptation removes the
 	// comment feature and the lazyQuotes option
 	delim           []byte
+	quote           byte
+	escape          byte
 	numLine         int
 	fieldsPerRecord int
 }
@@ -77,12 +79,28 @@ func NewCSVReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *CSVFileInfo)
 	if len(info.Delim) < 1 {
 		return nil, errors.New(fmt.Sprintf("delimiter '%s' has invalid length", info.Delim))
 	}
-	if !validDelim(info.Delim) {
+	quote, err := dialectChar("quote", info.Quote, '"')
+	if err != nil {
+		return nil, err
+	}
+	escape, err := dialectChar("escape", info.Escape, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !validDelim(info.Delim, quote) {
 		return nil, errors.New(fmt.Sprintf("invalid delimiter: %s", string(info.Delim)))
 	}
 
-	br := bufio.NewReaderSize(r, ReadBufSize)
-	colStrs, err := getColHeaders(br, info)
+	var textRd io.Reader = r
+	if info.Encoding != "" {
+		textRd, err = untyped.TranscodingReader(r, info.Encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	br := bufio.NewReaderSize(textRd, ReadBufSize)
+	colStrs, err := getColHeaders(br, info, quote)
 
 	if err != nil {
 		r.Close()
@@ -98,10 +116,24 @@ func NewCSVReader(nbf *types.NomsBinFormat, r io.ReadCloser, info *CSVFileInfo)
 		isDone:          false,
 		nbf:             nbf,
 		delim:           []byte(info.Delim),
+		quote:           quote,
+		escape:          escape,
 		fieldsPerRecord: sch.GetAllCols().Size(),
 	}, nil
 }
 
+// dialectChar returns the single byte character |val| used as the |name| character of a csv dialect, or |def| if
+// |val| is empty.
+func dialectChar(name, val string, def byte) (byte, error) {
+	if val == "" {
+		return def, nil
+	}
+	if len(val) != 1 || val[0] == '\r' || val[0] == '\n' || val[0] >= utf8.RuneSelf {
+		return 0, fmt.Errorf("invalid %s character '%s': must be a single ASCII character", name, val)
+	}
+	return val[0], nil
+}
+
 // trimBOM checks if the given string has the Byte Order Mark, and removes it if it is
 // the BOM is there if the first 3 bytes are xEF\xBB\xBF and indicates that a file is in UTF-8 encoding
 func trimBOM(s string) string {
@@ -114,7 +146,7 @@ func trimBOM(s string) string {
 	return s
 }
 
-func getColHeaders(br *bufio.Reader, info *CSVFileInfo) ([]string, error) {
+func getColHeaders(br *bufio.Reader, info *CSVFileInfo, quote byte) ([]string, error) {
 	colStrs := info.Columns
 	if info.HasHeaderLine {
 		line, _, err := iohelp.ReadLine(br)
@@ -125,7 +157,7 @@ func getColHeaders(br *bufio.Reader, info *CSVFileInfo) ([]string, error) {
 			return nil, errors.New("Header line is empty")
 		}
 		line = trimBOM(line)
-		colStrsFromFile, err := csvSplitLine(line, info.Delim, info.EscapeQuotes)
+		colStrsFromFile, err := csvSplitLine(line, info.Delim, quote, info.EscapeQuotes)
 
 		if err != nil {
 			return nil, err
@@ -276,8 +308,8 @@ func (csvr *CSVReader) Close(ctx context.Context) error {
 
 // Functions below this line are borrowed or adapted from encoding/csv/reader.go
 
-func validDelim(s string) bool {
-	return !(strings.Contains(s, "\"") ||
+func validDelim(s string, quote byte) bool {
+	return !(strings.IndexByte(s, quote) != -1 ||
 		strings.Contains(s, "\r") ||
 		strings.Contains(s, "\n") ||
 		strings.Contains(s, string([]byte{0xFF, 0xFD}))) // Unicode replacement char
@@ -359,7 +391,7 @@ func (csvr *CSVReader) csvReadRecords(dst []*string) ([]*string, error) {
 		// Parse each field in the record.
 		rs.line = bytes.TrimLeftFunc(rs.line, unicode.IsSpace)
 		keep := true
-		if len(rs.line) == 0 || rs.line[0] != '"' {
+		if len(rs.line) == 0 || rs.line[0] != csvr.quote {
 			kontinue, keep, err = csvr.parseField(&rs)
 			if !keep {
 				nullString[fieldIdx] = true
@@ -422,7 +454,7 @@ func (csvr *CSVReader) parseField(rs *recordState) (kontinue bool, keep bool, er
 }
 
 func (csvr *CSVReader) parseQuotedField(rs *recordState) (kontinue bool, err error) {
-	const quoteLen = len(`"`)
+	const quoteLen = 1
 	dl := len(csvr.delim)
 	recordStartLine := csvr.numLine
 	fullLine := rs.line
@@ -430,14 +462,22 @@ func (csvr *CSVReader) parseQuotedField(rs *recordState) (kontinue bool, err err
 	// Quoted string field
 	rs.line = rs.line[quoteLen:]
 	for {
-		i := bytes.IndexByte(rs.line, '"')
-		if i >= 0 {
+		i := csvr.indexQuoteOrEscape(rs.line)
+		if i >= 0 && rs.line[i] != csvr.quote {
+			// Hit an escape character, which escapes the character following it.
+			rs.recordBuffer = append(rs.recordBuffer, rs.line[:i]...)
+			rs.line = rs.line[i+1:]
+			// An escaped line ending is left in place to be handled as the end of the line.
+			if len(rs.line) > 0 && rs.line[0] != '\n' {
+				rs.recordBuffer = append(rs.recordBuffer, rs.line[0])
+				rs.line = rs.line[1:]
+			}
+		} else if i >= 0 {
 			// Hit next quote.
 			rs.recordBuffer = append(rs.recordBuffer, rs.line[:i]...)
 			rs.line = rs.line[i+quoteLen:]
 
 			atDelimiter := len(rs.line) >= dl && bytes.Compare(rs.line[:dl], csvr.delim) == 0
-			nextRune, _ := utf8.DecodeRune(rs.line)
 
 			switch {
 			case atDelimiter:
@@ -445,9 +485,9 @@ func (csvr *CSVReader) parseQuotedField(rs *recordState) (kontinue bool, err err
 				rs.line = rs.line[dl:]
 				rs.fieldIndexes = append(rs.fieldIndexes, len(rs.recordBuffer))
 				return true, err
-			case nextRune == '"':
+			case len(rs.line) > 0 && rs.line[0] == csvr.quote:
 				// `""` sequence (append quote).
-				rs.recordBuffer = append(rs.recordBuffer, '"')
+				rs.recordBuffer = append(rs.recordBuffer, csvr.quote)
 				rs.line = rs.line[quoteLen:]
 			case lengthNL(rs.line) == len(rs.line):
 				// `"\n` sequence (end of line).
@@ -483,6 +523,14 @@ func (csvr *CSVReader) parseQuotedField(rs *recordState) (kontinue bool, err err
 	}
 }
 
+// indexQuoteOrEscape returns the index of the first quote or escape character in |line|, or -1 if there is none.
+func (csvr *CSVReader) indexQuoteOrEscape(line []byte) int {
+	if csvr.escape == 0 || csvr.escape == csvr.quote {
+		return bytes.IndexByte(line, csvr.quote)
+	}
+	return bytes.IndexAny(line, string([]byte{csvr.quote, csvr.escape}))
+}
+
 // interpretRowSizeError returns a format map (written as a string) of a set of columns to their row values. It also
 // returns a slice of an unused strings.
 func interpretRowSizeError(schema schema.Schema, rowVals []*string) (string, []string) {
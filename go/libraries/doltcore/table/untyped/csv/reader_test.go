@@ -136,6 +136,106 @@ func TestReader(t *testing.T) {
 	}
 }
 
+func TestReaderDialects(t *testing.T) {
+	colNames := []string{"name", "title"}
+	_, sch := untyped.NewUntypedSchema(colNames...)
+
+	tests := []struct {
+		name         string
+		inputStr     string
+		info         *CSVFileInfo
+		expectedRows [][]string
+		expectErr    bool
+	}{
+		{
+			name:         "single quotes",
+			inputStr:     "name;title\n'Rob Robertson';'Assistant; ''Dufus'''\n",
+			info:         NewCSVInfo().SetDelim(";").SetQuote("'"),
+			expectedRows: [][]string{{"Rob Robertson", "Assistant; 'Dufus'"}},
+		},
+		{
+			name:         "backslash escapes",
+			inputStr:     `name,title` + "\n" + `"Rob \"The Rob\" Robertson","C:\\dufus"` + "\n",
+			info:         NewCSVInfo().SetEscape(`\`),
+			expectedRows: [][]string{{`Rob "The Rob" Robertson`, `C:\dufus`}},
+		},
+		{
+			name:         "escaped line ending",
+			inputStr:     "name,title\n\"John Johnson\",\"Intern\\\nDufus\"\n",
+			info:         NewCSVInfo().SetEscape(`\`),
+			expectedRows: [][]string{{"John Johnson", "Intern\nDufus"}},
+		},
+		{
+			name:         "latin1",
+			inputStr:     "name,title\nJos\xe9,Se\xf1or Dufus\n",
+			info:         NewCSVInfo().SetEncoding("latin1"),
+			expectedRows: [][]string{{"José", "Señor Dufus"}},
+		},
+		{
+			name:         "utf-16le",
+			inputStr:     "n\x00a\x00m\x00e\x00,\x00t\x00i\x00t\x00l\x00e\x00\n\x00J\x00o\x00s\x00\xe9\x00,\x00D\x00\n\x00",
+			info:         NewCSVInfo().SetEncoding("utf-16le"),
+			expectedRows: [][]string{{"José", "D"}},
+		},
+		{
+			name:         "utf-16 with a big endian byte order mark",
+			inputStr:     "\xfe\xff\x00n\x00a\x00m\x00e\x00,\x00t\x00i\x00t\x00l\x00e\x00\n\x00J\x00o\x00s\x00\xe9\x00,\x00D\x00\n",
+			info:         NewCSVInfo().SetEncoding("utf16"),
+			expectedRows: [][]string{{"José", "D"}},
+		},
+		{
+			name:      "unknown encoding",
+			inputStr:  "name,title\n",
+			info:      NewCSVInfo().SetEncoding("not-an-encoding"),
+			expectErr: true,
+		},
+		{
+			name:      "multi character quote",
+			inputStr:  "name,title\n",
+			info:      NewCSVInfo().SetQuote("''"),
+			expectErr: true,
+		},
+		{
+			name:      "delimiter contains quote",
+			inputStr:  "name,title\n",
+			info:      NewCSVInfo().SetDelim("'").SetQuote("'"),
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := filesys.NewInMemFS(nil, map[string][]byte{"/file.csv": []byte(test.inputStr)}, "/")
+			csvR, err := OpenCSVReader(types.Format_Default, "/file.csv", fs, test.info)
+			if test.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			defer csvR.Close(context.Background())
+
+			for _, expected := range test.expectedRows {
+				r, err := csvR.ReadRow(context.Background())
+				if err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+				expectedRow := mustRow(untyped.NewRowFromStrings(types.Format_Default, sch, expected))
+				if !row.AreEqual(r, expectedRow, sch) {
+					t.Error(row.Fmt(context.Background(), r, sch), "!=", row.Fmt(context.Background(), expectedRow, sch))
+				}
+			}
+
+			if _, err := csvR.ReadRow(context.Background()); err != io.EOF {
+				t.Error("expected EOF, got:", err)
+			}
+		})
+	}
+}
+
 func readTestRows(t *testing.T, inputStr string, info *CSVFileInfo) ([]row.Row, int, error) {
 	const root = "/"
 	const path = "/file.csv"
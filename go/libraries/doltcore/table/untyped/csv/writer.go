@@ -125,11 +125,13 @@ func (csvw *CSVWriter) WriteSqlRow(ctx context.Context, r sql.Row) error {
 	return csvw.write(colValStrs)
 }
 
-func toCsvString(colType sql.Type, val interface{}) (string, error) {
+func (csvw *CSVWriter) toCsvString(colType sql.Type, val interface{}) (string, error) {
 	var v string
 	// Due to BIT's unique output, we special-case writing the integer specifically for CSV
 	if _, ok := colType.(types.BitType); ok {
 		v = strconv.FormatUint(val.(uint64), 10)
+	} else if g, ok := val.(types.GeometryValue); ok {
+		v = sqlutil.SpatialToStr(g, csvw.info.SpatialFormat)
 	} else {
 		var err error
 		v, err = sqlutil.SqlColToStr(colType, val)
@@ -148,7 +150,7 @@ func (csvw *CSVWriter) processRowWithSchema(r sql.Row) ([]*string, error) {
 			colValStrs[i] = nil
 		} else {
 			colType := csvw.sch.GetAllCols().GetByIndex(i).TypeInfo.ToSqlType()
-			v, err := toCsvString(colType, val)
+			v, err := csvw.toCsvString(colType, val)
 			if err != nil {
 				return nil, err
 			}
@@ -165,7 +167,7 @@ func (csvw *CSVWriter) processRowWithSqlSchema(r sql.Row) ([]*string, error) {
 			colValStrs[i] = nil
 		} else {
 			colType := csvw.sqlSch[i].Type
-			v, err := toCsvString(colType, val)
+			v, err := csvw.toCsvString(colType, val)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,66 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package untyped
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	textunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// encodingAliases are names of encodings that are commonly used, but aren't in the WHATWG Encoding Standard
+var encodingAliases = map[string]string{
+	"utf16":   "utf-16",
+	"utf16le": "utf-16le",
+	"utf16be": "utf-16be",
+	"latin-1": "latin1",
+}
+
+// ebcdicEncodings are the EBCDIC code pages of mainframe extracts, which aren't in the WHATWG Encoding Standard
+var ebcdicEncodings = map[string]encoding.Encoding{
+	"ebcdic":  charmap.CodePage037,
+	"cp037":   charmap.CodePage037,
+	"ibm037":  charmap.CodePage037,
+	"cp1047":  charmap.CodePage1047,
+	"ibm1047": charmap.CodePage1047,
+}
+
+// TranscodingReader returns a reader that decodes |r| from the character encoding named |encName| to UTF-8.
+// Encodings are named as in the WHATWG Encoding Standard, e.g. latin1, windows-1252, shift_jis, or utf-16le, or are
+// one of the EBCDIC code pages cp037 and cp1047. A byte order mark at the start of |r| overrides the encoding, so that
+// utf-16 is read in the byte order it was written in.
+func TranscodingReader(r io.Reader, encName string) (io.Reader, error) {
+	if enc, ok := ebcdicEncodings[strings.ToLower(encName)]; ok {
+		return transform.NewReader(r, enc.NewDecoder()), nil
+	}
+
+	if alias, ok := encodingAliases[strings.ToLower(encName)]; ok {
+		encName = alias
+	}
+	enc, err := htmlindex.Get(encName)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding '%s'", encName)
+	}
+	if name, _ := htmlindex.Name(enc); name == "utf-8" {
+		return r, nil
+	}
+	return transform.NewReader(r, textunicode.BOMOverride(enc.NewDecoder())), nil
+}
@@ -0,0 +1,406 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedwidth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// item is a data description entry of a copybook
+type item struct {
+	level     int
+	name      string
+	pic       string
+	occurs    int
+	redefines bool
+	signSep   bool
+	children  []*item
+}
+
+// ParseCopybook returns the layout of the record described by a COBOL copybook. Each elementary item of the record is
+// a column, named after the item in lower case with its hyphens replaced by underscores. Items that occur more than
+// once are numbered, e.g. phone_1 and phone_2. FILLER items, and items that redefine others, aren't columns. Since
+// fixed-width files are read as text, only items with DISPLAY usage are supported.
+func ParseCopybook(src string) (*Layout, error) {
+	entries, err := copybookEntries(src)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &item{level: 0}
+	stack := []*item{root}
+	records := 0
+	for _, words := range entries {
+		it, err := parseEntry(words)
+		if err != nil {
+			return nil, err
+		}
+		if it == nil {
+			continue
+		}
+		if it.level == 1 {
+			records++
+			if records > 1 {
+				return nil, fmt.Errorf("the copybook describes more than one record")
+			}
+		}
+
+		for stack[len(stack)-1].level >= it.level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		if parent.pic != "" {
+			return nil, fmt.Errorf("item %s has a PICTURE, so it can't contain %s", parent.name, it.name)
+		}
+		parent.children = append(parent.children, it)
+		stack = append(stack, it)
+	}
+
+	layout := &Layout{}
+	if _, err = layout.addItems(root.children, "", 0); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// addItems adds the columns of |items|, which start at |offset| in the record, and returns the number of characters
+// they take up. |suffix| is appended to the names of the columns of items in groups that occur more than once.
+func (l *Layout) addItems(items []*item, suffix string, offset int) (int, error) {
+	size := 0
+	for _, it := range items {
+		if it.redefines {
+			continue
+		}
+		for i := 1; i <= it.occurs; i++ {
+			sfx := suffix
+			if it.occurs > 1 {
+				sfx = fmt.Sprintf("%s_%d", suffix, i)
+			}
+			n, err := l.addItem(it, sfx, offset+size)
+			if err != nil {
+				return 0, err
+			}
+			size += n
+		}
+	}
+	return size, nil
+}
+
+func (l *Layout) addItem(it *item, suffix string, offset int) (int, error) {
+	if it.pic == "" {
+		if len(it.children) == 0 {
+			return 0, fmt.Errorf("item %s has no PICTURE and no subordinate items", it.name)
+		}
+		return l.addItems(it.children, suffix, offset)
+	}
+
+	pic, err := parsePicture(it.pic)
+	if err != nil {
+		return 0, fmt.Errorf("item %s: %w", it.name, err)
+	}
+	width := pic.width
+	if pic.signed && it.signSep {
+		width++
+	}
+
+	if it.name != "" {
+		l.Columns = append(l.Columns, Column{
+			Name:   strings.ToLower(strings.ReplaceAll(it.name, "-", "_")) + suffix,
+			Start:  offset + 1,
+			Width:  width,
+			Type:   pic.sqlType(),
+			Scale:  pic.scale,
+			Signed: pic.signed,
+		})
+	}
+	return width, nil
+}
+
+// copybookEntries returns the words of each data description entry in |src|. Copybooks in the fixed reference format,
+// where columns 1-6 are a sequence number, column 7 is an indicator and the text ends at column 72, are detected.
+func copybookEntries(src string) ([][]string, error) {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	fixed := isFixedFormat(lines)
+
+	var text strings.Builder
+	for _, line := range lines {
+		if fixed {
+			if len(line) < 7 || line[6] == '*' || line[6] == '/' {
+				continue
+			}
+			line = line[7:]
+			if len(line) > 65 {
+				line = line[:65]
+			}
+		} else if strings.HasPrefix(strings.TrimSpace(line), "*") {
+			continue
+		}
+		if i := strings.Index(line, "*>"); i >= 0 {
+			line = line[:i]
+		}
+		text.WriteString(line)
+		text.WriteByte(' ')
+	}
+
+	var entries [][]string
+	var words []string
+	var word strings.Builder
+	var quote byte
+	s := text.String()
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			word.WriteByte(c)
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			word.WriteByte(c)
+		case c == ' ' || c == '\t':
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		case c == '.' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t'):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			if len(words) > 0 {
+				entries = append(entries, words)
+			}
+			words = nil
+		default:
+			word.WriteByte(c)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated literal")
+	}
+	if word.Len() > 0 || len(words) > 0 {
+		return nil, fmt.Errorf("entry '%s' is not terminated by a period", strings.Join(append(words, word.String()), " "))
+	}
+	return entries, nil
+}
+
+func isFixedFormat(lines []string) bool {
+	found := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) < 7 || strings.IndexByte(" */-Dd", line[6]) < 0 {
+			return false
+		}
+		for _, c := range line[:6] {
+			if c != ' ' && (c < '0' || c > '9') {
+				return false
+			}
+		}
+		found = true
+	}
+	return found
+}
+
+// parseEntry parses the words of a data description entry. It returns nil for entries that don't describe data in the
+// record, like condition names.
+func parseEntry(words []string) (*item, error) {
+	level, err := strconv.Atoi(words[0])
+	if err != nil || level < 1 {
+		return nil, fmt.Errorf("expected a level number, found '%s'", words[0])
+	}
+	switch {
+	case level == 66 || level == 77 || level == 88:
+		return nil, nil
+	case level > 49:
+		return nil, fmt.Errorf("invalid level number %d", level)
+	}
+
+	it := &item{level: level, occurs: 1}
+	rest := words[1:]
+	if len(rest) > 0 && !isClause(rest[0]) {
+		if !strings.EqualFold(rest[0], "FILLER") {
+			it.name = strings.ToUpper(rest[0])
+		}
+		rest = rest[1:]
+	}
+	display := func() string {
+		if it.name == "" {
+			return "FILLER"
+		}
+		return it.name
+	}
+
+	// next returns the next word, skipping |optional| if it's next
+	next := func(optional ...string) string {
+		for len(rest) > 0 {
+			w := strings.ToUpper(rest[0])
+			rest = rest[1:]
+			skip := false
+			for _, o := range optional {
+				skip = skip || w == o
+			}
+			if !skip {
+				return w
+			}
+		}
+		return ""
+	}
+
+	for len(rest) > 0 {
+		switch w := next(); w {
+		case "PIC", "PICTURE":
+			it.pic = next("IS")
+			if it.pic == "" {
+				return nil, fmt.Errorf("item %s: missing PICTURE string", display())
+			}
+		case "REDEFINES":
+			it.redefines = true
+			next()
+		case "OCCURS":
+			n, err := strconv.Atoi(next())
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("item %s: invalid OCCURS clause", display())
+			}
+			if len(rest) > 0 && strings.EqualFold(rest[0], "TO") {
+				return nil, fmt.Errorf("item %s: OCCURS DEPENDING ON is not supported", display())
+			}
+			it.occurs = n
+			if len(rest) > 0 && strings.EqualFold(rest[0], "TIMES") {
+				rest = rest[1:]
+			}
+		case "USAGE":
+			if u := next("IS"); u != "DISPLAY" {
+				return nil, fmt.Errorf("item %s: USAGE %s is not supported, only DISPLAY items can be read from text", display(), u)
+			}
+		case "DISPLAY":
+		case "SIGN", "LEADING", "TRAILING":
+			if w == "SIGN" {
+				next("IS")
+			}
+			if len(rest) > 0 && strings.EqualFold(rest[0], "SEPARATE") {
+				it.signSep = true
+				next()
+				if len(rest) > 0 && strings.EqualFold(rest[0], "CHARACTER") {
+					next()
+				}
+			}
+		case "VALUE", "VALUES":
+			// the initial value is the last clause we need to read
+			rest = nil
+		case "JUSTIFIED", "JUST", "RIGHT", "LEFT", "SYNC", "SYNCHRONIZED", "BLANK", "WHEN", "ZERO", "ZEROS", "ZEROES",
+			"GLOBAL", "EXTERNAL":
+		default:
+			if isUsage(w) {
+				return nil, fmt.Errorf("item %s: USAGE %s is not supported, only DISPLAY items can be read from text", display(), w)
+			}
+			return nil, fmt.Errorf("item %s: unsupported clause '%s'", display(), w)
+		}
+	}
+	return it, nil
+}
+
+func isClause(w string) bool {
+	switch strings.ToUpper(w) {
+	case "PIC", "PICTURE", "REDEFINES", "OCCURS", "USAGE", "DISPLAY", "SIGN", "LEADING", "TRAILING", "VALUE", "VALUES",
+		"JUSTIFIED", "JUST", "SYNC", "SYNCHRONIZED", "BLANK", "GLOBAL", "EXTERNAL":
+		return true
+	}
+	return isUsage(w)
+}
+
+func isUsage(w string) bool {
+	w = strings.ToUpper(w)
+	return strings.HasPrefix(w, "COMP") || w == "BINARY" || w == "PACKED-DECIMAL" || w == "INDEX" || w == "POINTER"
+}
+
+// picture is a parsed PICTURE string
+type picture struct {
+	width   int
+	digits  int
+	scale   int
+	signed  bool
+	numeric bool
+}
+
+// parsePicture parses a PICTURE string like X(20), 9(5) or S9(7)V99. Edited pictures, like ZZ9.99, are read as
+// strings.
+func parsePicture(s string) (picture, error) {
+	pic := picture{numeric: true}
+	afterV := false
+	s = strings.ToUpper(s)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		n := 1
+		if i+1 < len(s) && s[i+1] == '(' {
+			end := strings.IndexByte(s[i:], ')')
+			if end == -1 {
+				return pic, fmt.Errorf("invalid PICTURE '%s'", s)
+			}
+			var err error
+			n, err = strconv.Atoi(s[i+2 : i+end])
+			if err != nil || n < 1 {
+				return pic, fmt.Errorf("invalid PICTURE '%s'", s)
+			}
+			i += end
+		}
+
+		switch {
+		case c == '9':
+			pic.width += n
+			pic.digits += n
+			if afterV {
+				pic.scale += n
+			}
+		case c == 'S' && pic.width == 0 && !pic.signed:
+			pic.signed = true
+		case c == 'V' && !afterV:
+			afterV = true
+		case c == 'P':
+			return pic, fmt.Errorf("PICTURE '%s': scaling positions are not supported", s)
+		case strings.IndexByte("XAZB0/,.+-*$CRD", c) >= 0:
+			pic.width += n
+			pic.numeric = false
+		default:
+			return pic, fmt.Errorf("invalid PICTURE '%s'", s)
+		}
+	}
+
+	if pic.width == 0 {
+		return pic, fmt.Errorf("invalid PICTURE '%s'", s)
+	}
+	if !pic.numeric {
+		pic.digits, pic.scale, pic.signed = 0, 0, false
+	}
+	return pic, nil
+}
+
+// sqlType returns the SQL type of the values of the picture
+func (p picture) sqlType() string {
+	switch {
+	case !p.numeric:
+		return fmt.Sprintf("varchar(%d)", p.width)
+	case p.scale > 0 || p.digits > 18:
+		return fmt.Sprintf("decimal(%d,%d)", p.digits, p.scale)
+	case p.digits > 9:
+		return "bigint"
+	default:
+		return "int"
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedwidth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const customerCopybook = `000100* CUSTOMER MASTER RECORD
+000200 01  CUSTOMER-RECORD.
+000300     05  CUST-ID               PIC 9(6).
+000400     05  CUST-NAME             PIC X(20).
+000500     05  FILLER                PIC X(2).
+000600     05  CUST-BALANCE          PIC S9(7)V99.
+000700     05  CUST-BALANCE-X REDEFINES CUST-BALANCE PIC X(9).
+000800     05  CUST-PHONES OCCURS 2 TIMES.
+000900         10  PHONE-NUMBER      PIC X(10).
+001000     05  CUST-STATUS           PIC X VALUE 'A'.
+001100         88  ACTIVE            VALUE 'A'.
+001200     05  CUST-RATE PIC S9V9(3) SIGN IS LEADING SEPARATE.
+001300     05  CUST-AMOUNT           PIC ZZ,ZZ9.99.
+`
+
+func TestParseCopybook(t *testing.T) {
+	layout, err := ParseCopybook(customerCopybook)
+	require.NoError(t, err)
+	require.NoError(t, layout.resolve())
+
+	expected := []Column{
+		{Name: "cust_id", Start: 1, Width: 6, Type: "int"},
+		{Name: "cust_name", Start: 7, Width: 20, Type: "varchar(20)"},
+		{Name: "cust_balance", Start: 29, Width: 9, Type: "decimal(9,2)", Scale: 2, Signed: true},
+		{Name: "phone_number_1", Start: 38, Width: 10, Type: "varchar(10)"},
+		{Name: "phone_number_2", Start: 48, Width: 10, Type: "varchar(10)"},
+		{Name: "cust_status", Start: 58, Width: 1, Type: "varchar(1)"},
+		{Name: "cust_rate", Start: 59, Width: 5, Type: "decimal(4,3)", Scale: 3, Signed: true},
+		{Name: "cust_amount", Start: 64, Width: 9, Type: "varchar(9)"},
+	}
+	assert.Equal(t, expected, layout.Columns)
+}
+
+func TestParseCopybookFreeFormat(t *testing.T) {
+	layout, err := ParseCopybook(`*> an order
+01 ORDER-REC.
+   05 ORDER-ID PIC 9(12).   *> the order number
+   05 QTY PICTURE IS S9(4) USAGE IS DISPLAY.
+   05 SIGNED-QTY PIC S9(4) SIGN TRAILING SEPARATE CHARACTER.
+`)
+	require.NoError(t, err)
+
+	expected := []Column{
+		{Name: "order_id", Start: 1, Width: 12, Type: "bigint"},
+		{Name: "qty", Start: 13, Width: 4, Type: "int", Signed: true},
+		{Name: "signed_qty", Start: 17, Width: 5, Type: "int", Signed: true},
+	}
+	assert.Equal(t, expected, layout.Columns)
+}
+
+func TestParseCopybookErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		copybook  string
+		expectErr string
+	}{
+		{name: "packed decimal", copybook: "01 R.\n 05 A PIC S9(5) COMP-3.\n", expectErr: "item A: USAGE COMP-3 is not supported"},
+		{name: "binary usage", copybook: "01 R.\n 05 A PIC 9(4) USAGE BINARY.\n", expectErr: "item A: USAGE BINARY is not supported"},
+		{name: "variable occurs", copybook: "01 R.\n 05 A OCCURS 1 TO 5 TIMES DEPENDING ON N.\n 10 B PIC X.\n", expectErr: "OCCURS DEPENDING ON is not supported"},
+		{name: "two records", copybook: "01 R.\n 05 A PIC X.\n01 S.\n 05 B PIC X.\n", expectErr: "more than one record"},
+		{name: "missing period", copybook: "01 R.\n 05 A PIC X\n", expectErr: "is not terminated by a period"},
+		{name: "bad picture", copybook: "01 R.\n 05 A PIC 9(x).\n", expectErr: "invalid PICTURE"},
+		{name: "scaling", copybook: "01 R.\n 05 A PIC 99PP.\n", expectErr: "scaling positions are not supported"},
+		{name: "empty group", copybook: "01 R.\n 05 A.\n", expectErr: "item A has no PICTURE and no subordinate items"},
+		{name: "no level", copybook: "R PIC X.\n", expectErr: "expected a level number"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCopybook(test.copybook)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectErr)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedwidth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// Column is a field of a fixed-width record
+type Column struct {
+	Name string `json:"name"`
+	// Start is the 1-based position of the first character of the field. If it's 0, the field starts after the
+	// previous one.
+	Start int `json:"start,omitempty"`
+	// Width is the number of characters in the field
+	Width int `json:"width"`
+	// Type is the SQL type of the column when a table is created, e.g. varchar(20) or decimal(9,2). If it's empty,
+	// the type is inferred from the data.
+	Type string `json:"type,omitempty"`
+	// Scale is the number of implied decimal places of a numeric field, whose decimal point isn't in the data
+	Scale int `json:"scale,omitempty"`
+	// Signed is whether the sign of a numeric field is overpunched on its last digit, as in COBOL's zoned decimals
+	Signed bool `json:"signed,omitempty"`
+}
+
+// numeric returns whether the field is a number that needs to be decoded
+func (c Column) numeric() bool {
+	return c.Scale > 0 || c.Signed
+}
+
+// Layout describes the fields of the records of a fixed-width file. It is read from a json file of the form:
+//
+//	{
+//	  "columns": [
+//	    {"name": "id", "start": 1, "width": 6, "type": "int"},
+//	    {"name": "name", "width": 20},
+//	    {"name": "balance", "width": 9, "type": "decimal(9,2)", "scale": 2, "signed": true}
+//	  ],
+//	  "record_length": 0
+//	}
+//
+// or from a COBOL copybook. Records are lines, unless |RecordLength| is set, in which case the file is a sequence of
+// records of that many characters without line endings.
+type Layout struct {
+	Columns      []Column `json:"columns"`
+	RecordLength int      `json:"record_length,omitempty"`
+}
+
+// LayoutFromFile reads the layout in the file at |path|. Files with a .json extension are read as json layouts, and
+// other files as COBOL copybooks.
+func LayoutFromFile(path string, fs filesys.ReadableFS) (*Layout, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var layout *Layout
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		layout = &Layout{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(layout)
+	} else {
+		layout, err = ParseCopybook(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout '%s': %w", path, err)
+	}
+
+	if err = layout.resolve(); err != nil {
+		return nil, fmt.Errorf("invalid layout '%s': %w", path, err)
+	}
+	return layout, nil
+}
+
+// resolve sets the start of the columns that follow the previous one, and checks that the columns are valid
+func (l *Layout) resolve() error {
+	if len(l.Columns) == 0 {
+		return fmt.Errorf("no columns")
+	}
+
+	names := make(map[string]bool)
+	next := 1
+	for i := range l.Columns {
+		col := &l.Columns[i]
+		if col.Name == "" {
+			return fmt.Errorf("column %d has no name", i+1)
+		}
+		if names[strings.ToLower(col.Name)] {
+			return fmt.Errorf("duplicate column '%s'", col.Name)
+		}
+		names[strings.ToLower(col.Name)] = true
+
+		if col.Start == 0 {
+			col.Start = next
+		}
+		if col.Start < 1 || col.Width < 1 {
+			return fmt.Errorf("column '%s' must have a positive start and width", col.Name)
+		}
+		if col.Scale < 0 || col.Scale > col.Width {
+			return fmt.Errorf("column '%s' has an invalid scale %d", col.Name, col.Scale)
+		}
+		next = col.Start + col.Width
+		if l.RecordLength > 0 && next-1 > l.RecordLength {
+			return fmt.Errorf("column '%s' ends after the end of the record", col.Name)
+		}
+	}
+	return nil
+}
+
+// ColumnNames returns the names of the columns of the layout
+func (l *Layout) ColumnNames() []string {
+	names := make([]string, len(l.Columns))
+	for i, col := range l.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// ColumnTypes returns the SQL types of the columns of the layout that have one
+func (l *Layout) ColumnTypes() map[string]string {
+	types := make(map[string]string)
+	for _, col := range l.Columns {
+		if col.Type != "" {
+			types[col.Name] = col.Type
+		}
+	}
+	return types
+}
@@ -0,0 +1,284 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedwidth
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ReadBufSize = 256 * 1024
+
+// Reader reads the records of a fixed-width file, whose fields are at the positions given by a Layout. Fields are
+// read as strings, with leading and trailing spaces removed. Fields that are all spaces are null.
+type Reader struct {
+	closer    io.Closer
+	rd        *bufio.Reader
+	sch       schema.Schema
+	nbf       *types.NomsBinFormat
+	layout    *Layout
+	recordNum int
+}
+
+var _ table.SqlTableReader = (*Reader)(nil)
+
+// OpenReader opens the fixed-width file at |path|, which is in the character encoding named |encoding|, or in UTF-8
+// if |encoding| is empty.
+func OpenReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS, layout *Layout, encoding string) (*Reader, error) {
+	r, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReader(nbf, r, layout, encoding)
+}
+
+// NewReader returns a reader for the fixed-width records in |r|
+func NewReader(nbf *types.NomsBinFormat, r io.ReadCloser, layout *Layout, encoding string) (*Reader, error) {
+	if layout == nil {
+		return nil, errors.New("a layout must be provided to read fixed-width files")
+	}
+
+	var textRd io.Reader = r
+	if encoding != "" {
+		var err error
+		textRd, err = untyped.TranscodingReader(r, encoding)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+
+	_, sch := untyped.NewUntypedSchema(layout.ColumnNames()...)
+	return &Reader{
+		closer: r,
+		rd:     bufio.NewReaderSize(textRd, ReadBufSize),
+		sch:    sch,
+		nbf:    nbf,
+		layout: layout,
+	}, nil
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (r *Reader) GetSchema() schema.Schema {
+	return r.sch
+}
+
+// VerifySchema checks that the in schema matches the original schema
+func (r *Reader) VerifySchema(outSch schema.Schema) (bool, error) {
+	return schema.VerifyInSchema(r.sch, outSch)
+}
+
+// Close should release resources being held
+func (r *Reader) Close(ctx context.Context) error {
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
+
+		return err
+	}
+	return errors.New("already closed")
+}
+
+func (r *Reader) ReadRow(ctx context.Context) (row.Row, error) {
+	_, vals, reason, err := r.readRecord()
+	if err != nil {
+		return nil, err
+	} else if reason != "" {
+		return nil, table.NewBadRow(nil, reason)
+	}
+
+	allCols := r.sch.GetAllCols()
+	taggedVals := make(row.TaggedValues)
+	for i := 0; i < allCols.Size(); i++ {
+		if vals[i] != nil {
+			taggedVals[allCols.GetByIndex(i).Tag] = types.String(*vals[i])
+		}
+	}
+	return row.New(r.nbf, r.sch, taggedVals)
+}
+
+// ReadSqlRow reads the next record. Records with numeric fields that can't be decoded are returned as bad rows.
+func (r *Reader) ReadSqlRow(ctx context.Context) (sql.Row, error) {
+	rec, vals, reason, err := r.readRecord()
+	if err != nil {
+		return nil, err
+	} else if reason != "" {
+		return sql.Row{rec}, table.NewBadRow(nil, reason)
+	}
+
+	ret := make(sql.Row, len(vals))
+	for i, v := range vals {
+		if v != nil {
+			ret[i] = *v
+		}
+	}
+	return ret, nil
+}
+
+// readRecord reads the next record and returns it along with the values of its fields. If a field can't be decoded,
+// the reason the record is bad is returned instead of the values.
+func (r *Reader) readRecord() (rec string, vals []*string, reason string, err error) {
+	for {
+		runes, err := r.nextRecord()
+		if err != nil {
+			return "", nil, "", err
+		}
+		r.recordNum++
+		rec = string(runes)
+		if strings.TrimSpace(rec) == "" {
+			continue
+		}
+
+		vals = make([]*string, len(r.layout.Columns))
+		for i, col := range r.layout.Columns {
+			v, err := fieldValue(runes, col)
+			if err != nil {
+				return rec, nil, fmt.Sprintf("record %d: column %s: %s", r.recordNum, col.Name, err.Error()), nil
+			}
+			vals[i] = v
+		}
+		return rec, vals, "", nil
+	}
+}
+
+// nextRecord returns the characters of the next record, which is either the next line or the next |RecordLength|
+// characters
+func (r *Reader) nextRecord() ([]rune, error) {
+	if r.layout.RecordLength == 0 {
+		line, err := r.rd.ReadString('\n')
+		if err == io.EOF && len(line) == 0 {
+			return nil, io.EOF
+		} else if err != nil && err != io.EOF {
+			return nil, err
+		}
+		return []rune(strings.TrimRight(line, "\r\n")), nil
+	}
+
+	rec := make([]rune, 0, r.layout.RecordLength)
+	for len(rec) < r.layout.RecordLength {
+		c, _, err := r.rd.ReadRune()
+		if err == io.EOF {
+			if len(rec) == 0 {
+				return nil, io.EOF
+			}
+			// a truncated last record is padded with spaces
+			return rec, nil
+		} else if err != nil {
+			return nil, err
+		}
+		rec = append(rec, c)
+	}
+	return rec, nil
+}
+
+// fieldValue returns the value of the field |col| of |rec|, or nil if it's all spaces. Records that are shorter than
+// the layout are padded with spaces.
+func fieldValue(rec []rune, col Column) (*string, error) {
+	start, end := col.Start-1, col.Start-1+col.Width
+	if start >= len(rec) {
+		return nil, nil
+	}
+	if end > len(rec) {
+		end = len(rec)
+	}
+
+	s := strings.TrimSpace(string(rec[start:end]))
+	if s == "" {
+		return nil, nil
+	}
+	if col.numeric() {
+		var err error
+		if s, err = decodeNumber(s, col); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// decodeNumber returns the number in the numeric field |s|, decoding its sign and inserting its implied decimal point.
+// The sign may be a separate leading or trailing + or -, or be overpunched on the first or last digit: {, A-I for
+// positive digits and }, J-R for negative ones.
+func decodeNumber(s string, col Column) (string, error) {
+	neg := false
+	digits := []byte(s)
+	if col.Signed {
+		switch {
+		case digits[0] == '+' || digits[0] == '-':
+			neg = digits[0] == '-'
+			digits = digits[1:]
+		case digits[len(digits)-1] == '+' || digits[len(digits)-1] == '-':
+			neg = digits[len(digits)-1] == '-'
+			digits = digits[:len(digits)-1]
+		default:
+			var ok bool
+			if neg, ok = unpunch(&digits[len(digits)-1]); !ok {
+				neg, _ = unpunch(&digits[0])
+			}
+		}
+	}
+
+	if len(digits) == 0 {
+		return "", fmt.Errorf("invalid number '%s'", s)
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("invalid number '%s'", s)
+		}
+	}
+
+	num := strings.TrimLeft(string(digits), "0")
+	if len(num) <= col.Scale {
+		num = strings.Repeat("0", col.Scale-len(num)+1) + num
+	}
+	if col.Scale > 0 {
+		num = num[:len(num)-col.Scale] + "." + num[len(num)-col.Scale:]
+	}
+	if neg && strings.Trim(num, "0.") != "" {
+		num = "-" + num
+	}
+	return num, nil
+}
+
+// unpunch replaces the overpunched digit |c| with the digit it stands for, and returns whether it was negative. It
+// returns false for |ok| if |c| isn't overpunched.
+func unpunch(c *byte) (neg bool, ok bool) {
+	switch {
+	case *c == '{':
+		*c = '0'
+	case *c >= 'A' && *c <= 'I':
+		*c = '1' + (*c - 'A')
+	case *c == '}':
+		*c, neg = '0', true
+	case *c >= 'J' && *c <= 'R':
+		*c, neg = '1'+(*c-'J'), true
+	default:
+		return false, false
+	}
+	return neg, true
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixedwidth
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func readAll(t *testing.T, data string, layout *Layout, encoding string) ([]sql.Row, []string) {
+	fs := filesys.EmptyInMemFS("/")
+	require.NoError(t, fs.WriteFile("file.dat", []byte(data)))
+
+	rd, err := OpenReader(types.Format_Default, "file.dat", fs, layout, encoding)
+	require.NoError(t, err)
+	defer rd.Close(context.Background())
+
+	var rows []sql.Row
+	var bad []string
+	for {
+		r, err := rd.ReadSqlRow(context.Background())
+		if err == io.EOF {
+			return rows, bad
+		} else if table.IsBadRow(err) {
+			bad = append(bad, err.Error())
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+}
+
+func TestReader(t *testing.T) {
+	layout := &Layout{Columns: []Column{
+		{Name: "id", Width: 4},
+		{Name: "name", Width: 8},
+		{Name: "balance", Start: 14, Width: 6, Scale: 2, Signed: true},
+	}}
+	require.NoError(t, layout.resolve())
+
+	data := "0001José    X00123{\r\n" +
+		"\n" +
+		"0002  spaced -00005\n" +
+		"0003          1234J\n" +
+		"0004short\n" +
+		"0005bad       12x45\n"
+	rows, bad := readAll(t, data, layout, "")
+	assert.Equal(t, []sql.Row{
+		{"0001", "José", "12.30"},
+		{"0002", "spaced", "-0.05"},
+		{"0003", nil, "-123.41"},
+		{"0004", "short", nil},
+	}, rows)
+	assert.Equal(t, []string{"record 6: column balance: invalid number '12x45'"}, bad)
+}
+
+func TestReaderRecordLength(t *testing.T) {
+	layout := &Layout{Columns: []Column{{Name: "a", Width: 2}, {Name: "b", Width: 3}}, RecordLength: 6}
+	require.NoError(t, layout.resolve())
+
+	rows, bad := readAll(t, "01abc.02def.03", layout, "")
+	assert.Empty(t, bad)
+	assert.Equal(t, []sql.Row{{"01", "abc"}, {"02", "def"}, {"03", nil}}, rows)
+
+	// "AB123" in EBCDIC
+	rows, _ = readAll(t, "\xc1\xc2\xf1\xf2\xf3\x40", layout, "cp037")
+	assert.Equal(t, []sql.Row{{"AB", "123"}}, rows)
+}
+
+func TestLayoutFromFile(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/")
+	require.NoError(t, fs.WriteFile("layout.json", []byte(`{"columns": [{"name": "id", "width": 4, "type": "int"}, {"name": "name", "start": 10, "width": 5}]}`)))
+	require.NoError(t, fs.WriteFile("dup.json", []byte(`{"columns": [{"name": "id", "width": 4}, {"name": "ID", "width": 5}]}`)))
+	require.NoError(t, fs.WriteFile("long.json", []byte(`{"columns": [{"name": "id", "width": 4}], "record_length": 3}`)))
+	require.NoError(t, fs.WriteFile("record.cpy", []byte("01 R.\n  05 ID PIC 9(4).\n")))
+
+	layout, err := LayoutFromFile("layout.json", fs)
+	require.NoError(t, err)
+	assert.Equal(t, []Column{{Name: "id", Start: 1, Width: 4, Type: "int"}, {Name: "name", Start: 10, Width: 5}}, layout.Columns)
+	assert.Equal(t, map[string]string{"id": "int"}, layout.ColumnTypes())
+
+	layout, err = LayoutFromFile("record.cpy", fs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, layout.ColumnNames())
+
+	_, err = LayoutFromFile("dup.json", fs)
+	assert.ErrorContains(t, err, "duplicate column 'ID'")
+	_, err = LayoutFromFile("long.json", fs)
+	assert.ErrorContains(t, err, "column 'id' ends after the end of the record")
+}
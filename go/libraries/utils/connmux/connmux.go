@@ -0,0 +1,254 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package connmux multiplexes a fixed number of connections over a single byte stream, such as the stdin and stdout
+// of a process. Both ends of the stream create the same number of connections, and the data written to connection i
+// on one end is read from connection i on the other.
+package connmux
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// a frame is a one byte connection index, a four byte payload length and the payload. A frame with an empty
+	// payload closes the connection for writing.
+	frameHeaderLen = 5
+	maxPayloadLen  = 32 * 1024
+)
+
+// Addr is the net.Addr of the connections of a multiplexed stream
+type Addr struct{}
+
+func (Addr) Network() string { return "connmux" }
+func (Addr) String() string  { return "connmux" }
+
+type mux struct {
+	rw io.ReadWriteCloser
+
+	wrMu sync.Mutex
+
+	mu     sync.Mutex
+	conns  []*Conn
+	open   int
+	closed bool
+}
+
+// New multiplexes |n| connections over |rw|, which is closed once all of them are closed. Data received for a
+// connection is buffered until it is read, so that one connection not being read doesn't block the others.
+func New(rw io.ReadWriteCloser, n int) []*Conn {
+	if n <= 0 || n > 256 {
+		panic(fmt.Sprintf("invalid number of multiplexed connections: %d", n))
+	}
+
+	m := &mux{rw: rw, conns: make([]*Conn, n), open: n}
+	for i := range m.conns {
+		c := &Conn{m: m, idx: byte(i)}
+		c.cond = sync.NewCond(&c.mu)
+		m.conns[i] = c
+	}
+	go m.demux()
+	return m.conns
+}
+
+func (m *mux) demux() {
+	err := func() error {
+		var hdr [frameHeaderLen]byte
+		for {
+			if _, err := io.ReadFull(m.rw, hdr[:]); err != nil {
+				return err
+			}
+			idx := int(hdr[0])
+			n := binary.BigEndian.Uint32(hdr[1:])
+			if idx >= len(m.conns) || n > maxPayloadLen {
+				return errors.New("connmux: invalid frame")
+			}
+
+			c := m.conns[idx]
+			if n == 0 {
+				c.receive(nil, io.EOF)
+				continue
+			}
+			payload := make([]byte, n)
+			if _, err := io.ReadFull(m.rw, payload); err != nil {
+				return err
+			}
+			c.receive(payload, nil)
+		}
+	}()
+
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	for _, c := range m.conns {
+		c.receive(nil, err)
+	}
+}
+
+func (m *mux) write(idx byte, p []byte) error {
+	var hdr [frameHeaderLen]byte
+	hdr[0] = idx
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(p)))
+
+	m.wrMu.Lock()
+	defer m.wrMu.Unlock()
+	if _, err := m.rw.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := m.rw.Write(p)
+	return err
+}
+
+func (m *mux) connClosed() error {
+	m.mu.Lock()
+	m.open--
+	closeStream := m.open == 0 && !m.closed
+	if closeStream {
+		m.closed = true
+	}
+	m.mu.Unlock()
+
+	if closeStream {
+		return m.rw.Close()
+	}
+	return nil
+}
+
+// Conn is one of the connections multiplexed over a stream. Deadlines are not supported.
+type Conn struct {
+	m   *mux
+	idx byte
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    bytes.Buffer
+	rdErr  error
+	closed bool
+}
+
+var _ net.Conn = (*Conn)(nil)
+
+func (c *Conn) receive(p []byte, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(p) > 0 {
+		c.buf.Write(p)
+	}
+	if err != nil && c.rdErr == nil {
+		c.rdErr = err
+	}
+	c.cond.Broadcast()
+}
+
+// Read reads data written to the connection at the other end of the stream. It returns io.EOF once that connection
+// is closed and all of its data has been read.
+func (c *Conn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.buf.Len() == 0 && c.rdErr == nil && !c.closed {
+		c.cond.Wait()
+	}
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+	if c.buf.Len() > 0 {
+		return c.buf.Read(p)
+	}
+	return 0, c.rdErr
+}
+
+// Write writes |p| to the connection at the other end of the stream.
+func (c *Conn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return 0, net.ErrClosed
+	}
+
+	var n int
+	for len(p) > 0 {
+		l := len(p)
+		if l > maxPayloadLen {
+			l = maxPayloadLen
+		}
+		if err := c.m.write(c.idx, p[:l]); err != nil {
+			return n, err
+		}
+		n += l
+		p = p[l:]
+	}
+	return n, nil
+}
+
+// Close closes the connection, after which the connection at the other end of the stream reads io.EOF.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	err := c.m.write(c.idx, nil)
+	if cerr := c.m.connClosed(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return Addr{}
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	return Addr{}
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+type pipes struct {
+	io.Reader
+	io.Writer
+	close func() error
+}
+
+func (p pipes) Close() error {
+	return p.close()
+}
+
+// Pipes returns an io.ReadWriteCloser which reads from |r| and writes to |w|, such as the stdout and stdin of a
+// process, and which calls |close| when it's closed.
+func Pipes(r io.Reader, w io.Writer, close func() error) io.ReadWriteCloser {
+	return pipes{r, w, close}
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connmux
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeRecorder struct {
+	net.Conn
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
+
+func (c *closeRecorder) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func TestConnMux(t *testing.T) {
+	a, b := net.Pipe()
+	aStream, bStream := &closeRecorder{Conn: a}, &closeRecorder{Conn: b}
+	aConns, bConns := New(aStream, 2), New(bStream, 2)
+
+	data := make([][]byte, 2)
+	for i := range data {
+		data[i] = make([]byte, 3*maxPayloadLen+17)
+		_, err := rand.Read(data[i])
+		require.NoError(t, err)
+	}
+
+	// each connection sends its data to the other end and echoes back what it receives
+	var wg sync.WaitGroup
+	for i := range data {
+		i := i
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := aConns[i].Write(data[i])
+			assert.NoError(t, err)
+			echoed := make([]byte, len(data[i]))
+			_, err = io.ReadFull(aConns[i], echoed)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(data[i], echoed))
+			assert.NoError(t, aConns[i].Close())
+		}()
+		go func() {
+			defer wg.Done()
+			received, err := io.ReadAll(io.LimitReader(bConns[i], int64(len(data[i]))))
+			assert.NoError(t, err)
+			_, err = bConns[i].Write(received)
+			assert.NoError(t, err)
+
+			// the other end closes its connection after reading the echo
+			n, err := bConns[i].Read(make([]byte, 1))
+			assert.Equal(t, 0, n)
+			assert.Equal(t, io.EOF, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, aStream.isClosed())
+	assert.False(t, bStream.isClosed())
+
+	// reads and writes fail once a connection is closed
+	_, err := aConns[0].Write([]byte("data"))
+	assert.ErrorIs(t, err, net.ErrClosed)
+	_, err = aConns[0].Read(make([]byte, 1))
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestConnMuxStreamFailure(t *testing.T) {
+	a, b := net.Pipe()
+	aConns := New(a, 1)
+	require.NoError(t, b.Close())
+
+	_, err := aConns[0].Read(make([]byte, 1))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cron parses cron schedules and computes the times they fire.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search for the next time a schedule fires, for schedules like "0 0 30 2 *" that never do.
+const maxSearchYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a cron schedule with the five standard fields: minute, hour, day of month, month and day of week. Each
+// field is a comma separated list of values, ranges like 1-5 and * for every value, and values other than a single
+// number may be followed by a step like /15. Days of the week are numbered from 0 for Sunday, and 7 is also Sunday. As
+// in other cron implementations, when both the day of month and the day of week are restricted, a day matches if
+// either does. The macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported too.
+type Schedule struct {
+	minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday are true if the day of month or the day of week field is *
+	anyDay, anyWeekday bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField  = field{"minute", 0, 59}
+	hourField    = field{"hour", 0, 23}
+	dayField     = field{"day of month", 1, 31}
+	monthField   = field{"month", 1, 12}
+	weekdayField = field{"day of week", 0, 7}
+)
+
+// Parse parses a cron schedule.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := macros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron schedule '%s': expected 5 fields but found %d", spec, len(fields))
+	}
+
+	var s Schedule
+	var err error
+	if s.minutes, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.days, err = parseField(fields[2], dayField); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.weekdays, err = parseField(fields[4], weekdayField); err != nil {
+		return nil, err
+	}
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+	s.anyDay = fields[2] == "*"
+	s.anyWeekday = fields[4] == "*"
+	return &s, nil
+}
+
+// parseField returns the values of |expr| as a bitset.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s '%s': invalid step '%s'", f.name, expr, stepStr)
+			}
+		}
+
+		var lo, hi int
+		if rng == "*" {
+			lo, hi = f.min, f.max
+		} else if loStr, hiStr, isRange := strings.Cut(rng, "-"); isRange {
+			var err error
+			if lo, err = parseValue(loStr, expr, f); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(hiStr, expr, f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s '%s': range %s is backwards", f.name, expr, rng)
+			}
+		} else {
+			var err error
+			if lo, err = parseValue(rng, expr, f); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if hasStep {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s, expr string, f field) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s '%s': '%s' is not a number between %d and %d", f.name, expr, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after |t|, in the location of |t|, that the schedule fires, or the zero time if it never
+// does.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	end := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(end) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"* * * * *", "0 0 * * *", "*/15 9-17 * * 1-5", "0,30 * 1,15 * *", "5/10 * * * 7", "@daily", "@HOURLY"} {
+		_, err := Parse(spec)
+		assert.NoError(t, err, spec)
+	}
+	for _, spec := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@never"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2024, time.May, 1, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.May, 1, 10, 31, 0, 0, time.UTC)},
+		{"0 0 * * *", time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.May, 1, 11, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.May, 1, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, time.May, 2, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2024, time.May, 1, 11, 0, 0, 0, time.UTC)},
+		// May 1st 2024 is a Wednesday
+		{"0 0 * * 0", time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// the day of month or the day of week can match when both are restricted
+		{"0 0 15 * 5", time.Date(2024, time.May, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			s, err := Parse(test.spec)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, s.Next(from))
+		})
+	}
+}
+
+func TestNextLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	s, err := Parse("0 0 * * *")
+	require.NoError(t, err)
+	next := s.Next(time.Date(2024, time.May, 1, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC), next)
+	next = s.Next(time.Date(2024, time.May, 1, 23, 0, 0, 0, time.UTC).In(loc))
+	assert.Equal(t, time.Date(2024, time.May, 2, 0, 0, 0, 0, loc), next)
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package delta encodes a byte slice as the differences between it and a similar base byte slice, so that it can be
+// sent to a receiver that has the base in far fewer bytes than it takes itself.
+//
+// A delta is the length of the base and the length of the target, each a uvarint, followed by a sequence of
+// instructions. A copy instruction, the byte 0x80 followed by a uvarint offset and a uvarint length, appends that
+// range of the base to the target. An insert instruction, a byte n between 1 and 127 followed by n bytes, appends those
+// bytes to the target.
+package delta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidDelta is returned by Decode for a delta which is corrupt, or which was not encoded against the base given
+var ErrInvalidDelta = errors.New("invalid delta")
+
+const (
+	copyOp = 0x80
+
+	// maxInsertLen is the greatest number of bytes in a single insert instruction
+	maxInsertLen = 0x7f
+
+	// blockLen is the length of the blocks of the base that are indexed. Ranges of the target matching the base are
+	// found if they contain an aligned block of the base, so a range of 2*blockLen-1 bytes is always found.
+	blockLen = 16
+)
+
+// Encode returns the delta of |target| against |base|.
+func Encode(base, target []byte) []byte {
+	out := make([]byte, 0, 2*binary.MaxVarintLen64+len(target)/4)
+	out = binary.AppendUvarint(out, uint64(len(base)))
+	out = binary.AppendUvarint(out, uint64(len(target)))
+
+	index := make(map[uint64]int, len(base)/blockLen)
+	for off := 0; off+blockLen <= len(base); off += blockLen {
+		k := fingerprint(base[off : off+blockLen])
+		if _, ok := index[k]; !ok {
+			index[k] = off
+		}
+	}
+
+	// |target[pending:i]| is yet to be encoded
+	pending := 0
+	for i := 0; i+blockLen <= len(target); {
+		off, ok := index[fingerprint(target[i:i+blockLen])]
+		if !ok || !bytes.Equal(base[off:off+blockLen], target[i:i+blockLen]) {
+			i++
+			continue
+		}
+
+		start, baseStart := i, off
+		for start > pending && baseStart > 0 && target[start-1] == base[baseStart-1] {
+			start--
+			baseStart--
+		}
+		end, baseEnd := i+blockLen, off+blockLen
+		for end < len(target) && baseEnd < len(base) && target[end] == base[baseEnd] {
+			end++
+			baseEnd++
+		}
+
+		out = appendInserts(out, target[pending:start])
+		out = append(out, copyOp)
+		out = binary.AppendUvarint(out, uint64(baseStart))
+		out = binary.AppendUvarint(out, uint64(end-start))
+		i, pending = end, end
+	}
+
+	return appendInserts(out, target[pending:])
+}
+
+// Decode returns the target encoded by |delta| against |base|.
+func Decode(base, delta []byte) ([]byte, error) {
+	baseLen, n := binary.Uvarint(delta)
+	if n <= 0 || baseLen != uint64(len(base)) {
+		return nil, ErrInvalidDelta
+	}
+	delta = delta[n:]
+	targetLen, n := binary.Uvarint(delta)
+	if n <= 0 {
+		return nil, ErrInvalidDelta
+	}
+	delta = delta[n:]
+
+	// the target is allocated as it's decoded when its length is out of proportion to the base and the delta
+	capacity := targetLen
+	if limit := uint64(len(base) + len(delta)); capacity > limit {
+		capacity = limit
+	}
+	target := make([]byte, 0, capacity)
+
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+		switch {
+		case op == copyOp:
+			off, n := binary.Uvarint(delta)
+			if n <= 0 {
+				return nil, ErrInvalidDelta
+			}
+			delta = delta[n:]
+			length, n := binary.Uvarint(delta)
+			if n <= 0 {
+				return nil, ErrInvalidDelta
+			}
+			delta = delta[n:]
+			if off > uint64(len(base)) || length > uint64(len(base))-off || length > targetLen-uint64(len(target)) {
+				return nil, ErrInvalidDelta
+			}
+			target = append(target, base[off:off+length]...)
+		case op > 0 && op <= maxInsertLen:
+			if int(op) > len(delta) || uint64(op) > targetLen-uint64(len(target)) {
+				return nil, ErrInvalidDelta
+			}
+			target = append(target, delta[:op]...)
+			delta = delta[op:]
+		default:
+			return nil, ErrInvalidDelta
+		}
+	}
+
+	if uint64(len(target)) != targetLen {
+		return nil, ErrInvalidDelta
+	}
+	return target, nil
+}
+
+// appendInserts appends the instructions inserting |data| to |out|
+func appendInserts(out, data []byte) []byte {
+	for len(data) > 0 {
+		n := len(data)
+		if n > maxInsertLen {
+			n = maxInsertLen
+		}
+		out = append(out, byte(n))
+		out = append(out, data[:n]...)
+		data = data[n:]
+	}
+	return out
+}
+
+// fingerprint hashes a block of |blockLen| bytes
+func fingerprint(b []byte) uint64 {
+	const prime1, prime2 = 0x9e3779b185ebca87, 0xc2b2ae3d27d4eb4f
+	return binary.LittleEndian.Uint64(b[:8])*prime1 ^ binary.LittleEndian.Uint64(b[8:16])*prime2
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBytes(rng *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rng.Read(b)
+	return b
+}
+
+func TestEncodeDecode(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	base := randomBytes(rng, 4096)
+
+	edited := append([]byte{}, base...)
+	copy(edited[1000:], randomBytes(rng, 20))
+	copy(edited[3000:], randomBytes(rng, 8))
+
+	inserted := append(append(append([]byte{}, base[:2048]...), randomBytes(rng, 300)...), base[2048:]...)
+	removed := append(append([]byte{}, base[:512]...), base[1024:]...)
+
+	tests := []struct {
+		name    string
+		base    []byte
+		target  []byte
+		maxSize int
+	}{
+		{name: "identical", base: base, target: base, maxSize: 16},
+		{name: "edited", base: base, target: edited, maxSize: 100},
+		{name: "inserted", base: base, target: inserted, maxSize: 350},
+		{name: "removed", base: base, target: removed, maxSize: 16},
+		{name: "unrelated", base: base, target: randomBytes(rng, 4096), maxSize: 4096 + 4096/maxInsertLen + 8},
+		{name: "empty base", base: nil, target: base[:100], maxSize: 110},
+		{name: "empty target", base: base, target: nil, maxSize: 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := Encode(test.base, test.target)
+			assert.LessOrEqual(t, len(d), test.maxSize)
+			decoded, err := Decode(test.base, d)
+			require.NoError(t, err)
+			assert.Equal(t, len(test.target), len(decoded))
+			assert.Equal(t, string(test.target), string(decoded))
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	base := randomBytes(rng, 1024)
+	target := append(append([]byte{}, base[:512]...), randomBytes(rng, 100)...)
+	d := Encode(base, target)
+
+	_, err := Decode(base[:1000], d)
+	assert.ErrorIs(t, err, ErrInvalidDelta)
+
+	for i := 1; i < len(d); i++ {
+		_, err = Decode(base, d[:i])
+		assert.ErrorIs(t, err, ErrInvalidDelta)
+	}
+
+	_, err = Decode(base, append(d, 0))
+	assert.ErrorIs(t, err, ErrInvalidDelta)
+
+	_, err = Decode(base, []byte{0x80, 0x08, 0x00, 0x80, 0x00, 0xff, 0x01})
+	assert.ErrorIs(t, err, ErrInvalidDelta)
+}
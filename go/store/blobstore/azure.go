@@ -0,0 +1,173 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+const (
+	// azureUploadBlockSize is the size of the blocks that blobs are staged in before they are committed
+	azureUploadBlockSize = 8 * 1024 * 1024
+
+	// azureUploadConcurrency is the number of blocks of a single blob that are staged at once
+	azureUploadConcurrency = 4
+)
+
+// AzureBlobstore provides an Azure Blob Storage implementation of the Blobstore interface. Versions are the ETags
+// of blobs.
+type AzureBlobstore struct {
+	container     *container.Client
+	containerName string
+	prefix        string
+}
+
+var _ Blobstore = &AzureBlobstore{}
+
+// NewAzureBlobstore creates a new instance of an AzureBlobstore that stores blobs under |prefix| in the container
+// that |client| points at
+func NewAzureBlobstore(client *container.Client, containerName, prefix string) *AzureBlobstore {
+	return &AzureBlobstore{
+		container:     client,
+		containerName: containerName,
+		prefix:        normalizePrefix(prefix),
+	}
+}
+
+func (bs *AzureBlobstore) Path() string {
+	return path.Join(bs.containerName, bs.prefix)
+}
+
+// Exists returns true if a blob exists for the given key, and false if it does not.
+func (bs *AzureBlobstore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := bs.container.NewBlobClient(bs.absKey(key)).GetProperties(ctx, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Get retrieves an io.reader for the portion of a blob specified by br along with its version
+func (bs *AzureBlobstore) Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error) {
+	absKey := bs.absKey(key)
+	blobClient := bs.container.NewBlobClient(absKey)
+
+	opts := &blob.DownloadStreamOptions{}
+	if br.offset < 0 {
+		// Azure doesn't support suffix ranges, so the blob's size is needed to resolve them. The download is
+		// conditioned on the blob not changing after its size was read.
+		props, err := blobClient.GetProperties(ctx, nil)
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return nil, "", NotFound{"az://" + path.Join(bs.containerName, absKey)}
+		} else if err != nil {
+			return nil, "", err
+		}
+		br = br.positiveRange(*props.ContentLength)
+		opts.AccessConditions = &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfMatch: props.ETag},
+		}
+	}
+	if !br.isAllRange() {
+		opts.Range = blob.HTTPRange{Offset: br.offset, Count: br.length}
+	}
+
+	resp, err := blobClient.DownloadStream(ctx, opts)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil, "", NotFound{"az://" + path.Join(bs.containerName, absKey)}
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return resp.Body, fmtETag(resp.ETag), nil
+}
+
+// Put sets the blob and the version for a key. Blobs are uploaded in blocks, so a failed block is retried without
+// resending the blocks before it.
+func (bs *AzureBlobstore) Put(ctx context.Context, key string, reader io.Reader) (string, error) {
+	return bs.upload(ctx, key, reader, nil)
+}
+
+// CheckAndPut will check the current version of a blob against an expectedVersion, and if the
+// versions match it will update the data and version associated with the key
+func (bs *AzureBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, reader io.Reader) (string, error) {
+	conds := &blob.ModifiedAccessConditions{}
+	if expectedVersion != "" {
+		etag := azcore.ETag(expectedVersion)
+		conds.IfMatch = &etag
+	} else {
+		etag := azcore.ETagAny
+		conds.IfNoneMatch = &etag
+	}
+
+	ver, err := bs.upload(ctx, key, reader, &blob.AccessConditions{ModifiedAccessConditions: conds})
+	if bloberror.HasCode(err, bloberror.ConditionNotMet, bloberror.BlobAlreadyExists, bloberror.BlobNotFound) {
+		return "", CheckAndPutError{key, expectedVersion, "unknown (Not supported in Azure implementation)"}
+	}
+	return ver, err
+}
+
+func (bs *AzureBlobstore) upload(ctx context.Context, key string, reader io.Reader, conds *blob.AccessConditions) (string, error) {
+	resp, err := bs.container.NewBlockBlobClient(bs.absKey(key)).UploadStream(ctx, reader, &blockblob.UploadStreamOptions{
+		BlockSize:        azureUploadBlockSize,
+		Concurrency:      azureUploadConcurrency,
+		AccessConditions: conds,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmtETag(resp.ETag), nil
+}
+
+// Concatenate creates a new blob named |key| by concatenating |sources|. Blocks can't be shared between blobs, so
+// the sources are downloaded and uploaded again as a single blob.
+func (bs *AzureBlobstore) Concatenate(ctx context.Context, key string, sources []string) (string, error) {
+	readers := make([]io.Reader, len(sources))
+	closers := make([]io.Closer, 0, len(sources))
+	defer func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}()
+
+	for i := range sources {
+		rc, _, err := bs.Get(ctx, sources[i], BlobRange{})
+		if err != nil {
+			return "", err
+		}
+		closers = append(closers, rc)
+		readers[i] = rc
+	}
+
+	return bs.Put(ctx, key, io.MultiReader(readers...))
+}
+
+func (bs *AzureBlobstore) absKey(key string) string {
+	return path.Join(bs.prefix, key)
+}
+
+func fmtETag(etag *azcore.ETag) string {
+	if etag == nil {
+		return ""
+	}
+	return string(*etag)
+}
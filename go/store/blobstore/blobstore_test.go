@@ -69,7 +69,7 @@ type BlobstoreTest struct {
 
 func appendGCSTest(tests []BlobstoreTest) []BlobstoreTest {
 	if testGCSBucket != "" {
-		gcsTest := BlobstoreTest{"gcs", &GCSBlobstore{bucket, testGCSBucket, uuid.New().String() + "/"}, 4, 4}
+		gcsTest := BlobstoreTest{"gcs", &GCSBlobstore{bucket, testGCSBucket, uuid.New().String() + "/", DefaultGCSUploadChunkSize}, 4, 4}
 		tests = append(tests, gcsTest)
 	}
 
@@ -90,6 +90,7 @@ func newBlobStoreTests() []BlobstoreTest {
 	var tests []BlobstoreTest
 	tests = append(tests, BlobstoreTest{"inmem", NewInMemoryBlobstore(""), 10, 20})
 	tests = appendLocalTest(tests)
+	tests = appendOCITest(tests)
 	tests = appendGCSTest(tests)
 
 	return tests
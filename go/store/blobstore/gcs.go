@@ -31,6 +31,9 @@ const (
 	precondFailCode = 412
 
 	composeBatch = 32
+
+	// DefaultGCSUploadChunkSize is the default size of the chunks that blobs are uploaded in
+	DefaultGCSUploadChunkSize = googleapi.DefaultUploadChunkSize
 )
 
 // GCSBlobstore provides a GCS implementation of the Blobstore interface
@@ -38,6 +41,11 @@ type GCSBlobstore struct {
 	bucket     *storage.BucketHandle
 	bucketName string
 	prefix     string
+
+	// uploadChunkSize is the size of the chunks that blobs are uploaded in. Blobs larger than a chunk are written with a
+	// resumable upload, so a failed chunk is retried without resending the chunks before it. If it is 0, every blob
+	// is written in a single request, which can't be retried.
+	uploadChunkSize int
 }
 
 var _ Blobstore = &GCSBlobstore{}
@@ -49,7 +57,15 @@ func NewGCSBlobstore(gcs *storage.Client, bucketName, prefix string) *GCSBlobsto
 	}
 
 	bucket := gcs.Bucket(bucketName)
-	return &GCSBlobstore{bucket, bucketName, prefix}
+	return &GCSBlobstore{bucket, bucketName, prefix, DefaultGCSUploadChunkSize}
+}
+
+// WithUploadChunkSize returns a copy of this blobstore that uploads blobs in chunks of |size| bytes. GCS rounds |size|
+// up to a multiple of 256KiB. A |size| of 0 disables resumable uploads.
+func (bs *GCSBlobstore) WithUploadChunkSize(size int) *GCSBlobstore {
+	ret := *bs
+	ret.uploadChunkSize = size
+	return &ret
 }
 
 func (bs *GCSBlobstore) Path() string {
@@ -126,6 +142,7 @@ func (bs *GCSBlobstore) Put(ctx context.Context, key string, reader io.Reader) (
 	absKey := path.Join(bs.prefix, key)
 	oh := bs.bucket.Object(absKey)
 	writer := oh.NewWriter(ctx)
+	writer.ChunkSize = bs.uploadChunkSize
 
 	return writeObj(writer, reader)
 }
@@ -150,6 +167,7 @@ func (bs *GCSBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key st
 	}
 
 	writer := conditionalHandle.NewWriter(ctx)
+	writer.ChunkSize = bs.uploadChunkSize
 
 	ver, err := writeObj(writer, reader)
 
@@ -0,0 +1,576 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// OCIArtifactType is the artifact type of the manifests that dolt databases are stored under in OCI registries
+	OCIArtifactType = "application/vnd.dolthub.dolt.database.v1"
+
+	// OCIBlobMediaType is the media type of the layers that hold the blobs of a dolt database in an OCI registry
+	OCIBlobMediaType = "application/vnd.dolthub.dolt.blob.v1"
+
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociEmptyMediaType    = "application/vnd.oci.empty.v1+json"
+	ociTitleAnnotation   = "org.opencontainers.image.title"
+)
+
+// ociEmptyConfig is the config blob of the manifests that dolt databases are stored under, which have no config
+var ociEmptyConfig = []byte("{}")
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	ArtifactType  string          `json:"artifactType,omitempty"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// layer returns the descriptor of the layer holding the blob keyed by |key|
+func (m *ociManifest) layer(key string) (ociDescriptor, bool) {
+	for _, l := range m.Layers {
+		if l.Annotations[ociTitleAnnotation] == key {
+			return l, true
+		}
+	}
+	return ociDescriptor{}, false
+}
+
+// setLayer makes |desc| the layer holding the blob keyed by |key|
+func (m *ociManifest) setLayer(key string, desc ociDescriptor) {
+	desc.Annotations = map[string]string{ociTitleAnnotation: key}
+	for i, l := range m.Layers {
+		if l.Annotations[ociTitleAnnotation] == key {
+			m.Layers[i] = desc
+			return
+		}
+	}
+	m.Layers = append(m.Layers, desc)
+	sort.Slice(m.Layers, func(i, j int) bool {
+		return m.Layers[i].Annotations[ociTitleAnnotation] < m.Layers[j].Annotations[ociTitleAnnotation]
+	})
+}
+
+// OCICredentials are the credentials used to authenticate with an OCI registry. The registry is accessed anonymously
+// if they're empty.
+type OCICredentials struct {
+	Username string
+	Password string
+}
+
+// OCIBlobstore provides an implementation of the Blobstore interface that stores blobs as the layers of an artifact
+// in an OCI registry, such as ghcr.io or Docker Hub. Every blob is a layer of the manifest that a single tag points
+// at, annotated with its key. Versions are the digests of blobs.
+//
+// Registries can't update a tag conditionally, so CheckAndPut is only atomic with respect to the other writers using
+// this OCIBlobstore.
+type OCIBlobstore struct {
+	client      *http.Client
+	registryURL *url.URL
+	repository  string
+	tag         string
+	creds       OCICredentials
+
+	// mu serializes updates of the manifest that |tag| points at
+	mu           *sync.Mutex
+	configPushed bool
+
+	tokensMu *sync.Mutex
+	// tokens are the Authorization headers to send to the registry, by scope
+	tokens map[string]string
+}
+
+var _ Blobstore = &OCIBlobstore{}
+
+// NewOCIBlobstore creates a new instance of an OCIBlobstore that stores blobs under |tag| in |repository| of the
+// registry at |registryURL|, such as https://ghcr.io
+func NewOCIBlobstore(client *http.Client, registryURL *url.URL, repository, tag string, creds OCICredentials) *OCIBlobstore {
+	return &OCIBlobstore{
+		client:      client,
+		registryURL: registryURL,
+		repository:  repository,
+		tag:         tag,
+		creds:       creds,
+		mu:          &sync.Mutex{},
+		tokensMu:    &sync.Mutex{},
+		tokens:      make(map[string]string),
+	}
+}
+
+func (bs *OCIBlobstore) Path() string {
+	return bs.registryURL.Host + "/" + bs.repository + ":" + bs.tag
+}
+
+// Exists returns true if a blob exists for the given key, and false if it does not.
+func (bs *OCIBlobstore) Exists(ctx context.Context, key string) (bool, error) {
+	m, err := bs.getManifest(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := m.layer(key)
+	return ok, nil
+}
+
+// Get retrieves an io.reader for the portion of a blob specified by br along with its version
+func (bs *OCIBlobstore) Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error) {
+	m, err := bs.getManifest(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	desc, ok := m.layer(key)
+	if !ok {
+		return nil, "", NotFound{"oci://" + bs.Path() + "/" + key}
+	}
+
+	hdr := http.Header{}
+	if br.offset < 0 {
+		br = br.positiveRange(desc.Size)
+	}
+	if !br.isAllRange() {
+		if br.length == 0 {
+			hdr.Set("Range", fmt.Sprintf("bytes=%d-", br.offset))
+		} else {
+			hdr.Set("Range", fmt.Sprintf("bytes=%d-%d", br.offset, br.offset+br.length-1))
+		}
+	}
+
+	resp, err := bs.do(ctx, bs.pullScope(), http.MethodGet, bs.blobURL(desc.Digest), hdr, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return blobBody{resp.Body, resp.Body}, desc.Digest, nil
+	case http.StatusOK:
+		if br.isAllRange() {
+			return blobBody{resp.Body, resp.Body}, desc.Digest, nil
+		}
+		// the registry doesn't support range requests
+		if _, err = io.CopyN(io.Discard, resp.Body, br.offset); err != nil {
+			resp.Body.Close()
+			return nil, "", err
+		}
+		rd := io.Reader(resp.Body)
+		if br.length != 0 {
+			rd = io.LimitReader(resp.Body, br.length)
+		}
+		return blobBody{rd, resp.Body}, desc.Digest, nil
+	default:
+		defer resp.Body.Close()
+		return nil, "", ociResponseError(resp)
+	}
+}
+
+// blobBody is the body of a blob read from a registry. It returns io.EOF on its own, rather than with the last of the
+// blob's data.
+type blobBody struct {
+	io.Reader
+	io.Closer
+}
+
+func (b blobBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	return n, err
+}
+
+// Put sets the blob and the version for a key
+func (bs *OCIBlobstore) Put(ctx context.Context, key string, reader io.Reader) (string, error) {
+	desc, err := bs.pushBlob(ctx, reader)
+	if err != nil {
+		return "", err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	m, err := bs.getManifest(ctx)
+	if err != nil {
+		return "", err
+	}
+	m.setLayer(key, desc)
+	if err = bs.putManifest(ctx, m); err != nil {
+		return "", err
+	}
+	return desc.Digest, nil
+}
+
+// CheckAndPut will check the current version of a blob against an expectedVersion, and if the
+// versions match it will update the data and version associated with the key
+func (bs *OCIBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, reader io.Reader) (string, error) {
+	desc, err := bs.pushBlob(ctx, reader)
+	if err != nil {
+		return "", err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	m, err := bs.getManifest(ctx)
+	if err != nil {
+		return "", err
+	}
+	cur, _ := m.layer(key)
+	if cur.Digest != expectedVersion {
+		return "", CheckAndPutError{key, expectedVersion, cur.Digest}
+	}
+	m.setLayer(key, desc)
+	if err = bs.putManifest(ctx, m); err != nil {
+		return "", err
+	}
+	return desc.Digest, nil
+}
+
+// Concatenate creates a new blob named |key| by concatenating |sources|. Layers can't be composed, so the sources are
+// downloaded and uploaded again as a single blob.
+func (bs *OCIBlobstore) Concatenate(ctx context.Context, key string, sources []string) (string, error) {
+	readers := make([]io.Reader, len(sources))
+	closers := make([]io.Closer, 0, len(sources))
+	defer func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}()
+
+	for i := range sources {
+		rc, _, err := bs.Get(ctx, sources[i], BlobRange{})
+		if err != nil {
+			return "", err
+		}
+		closers = append(closers, rc)
+		readers[i] = rc
+	}
+
+	return bs.Put(ctx, key, io.MultiReader(readers...))
+}
+
+// getManifest returns the manifest that |tag| points at, or an empty manifest if the tag doesn't exist yet
+func (bs *OCIBlobstore) getManifest(ctx context.Context) (*ociManifest, error) {
+	hdr := http.Header{}
+	hdr.Set("Accept", ociManifestMediaType)
+	resp, err := bs.do(ctx, bs.pullScope(), http.MethodGet, bs.manifestURL(), hdr, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &ociManifest{
+			SchemaVersion: 2,
+			MediaType:     ociManifestMediaType,
+			ArtifactType:  OCIArtifactType,
+			Config:        ociDescriptor{MediaType: ociEmptyMediaType, Digest: sha256Digest(ociEmptyConfig), Size: int64(len(ociEmptyConfig))},
+		}, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, ociResponseError(resp)
+	}
+
+	var m ociManifest
+	if err = json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest of %s: %w", bs.Path(), err)
+	}
+	if m.MediaType != ociManifestMediaType || m.ArtifactType != OCIArtifactType {
+		return nil, fmt.Errorf("%s is not a dolt database; its manifest has media type '%s' and artifact type '%s'", bs.Path(), m.MediaType, m.ArtifactType)
+	}
+	return &m, nil
+}
+
+// putManifest points |tag| at |m|. The caller must hold |mu|.
+func (bs *OCIBlobstore) putManifest(ctx context.Context, m *ociManifest) error {
+	if !bs.configPushed {
+		if _, err := bs.pushBlob(ctx, bytes.NewReader(ociEmptyConfig)); err != nil {
+			return err
+		}
+		bs.configPushed = true
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	hdr := http.Header{}
+	hdr.Set("Content-Type", ociManifestMediaType)
+	resp, err := bs.do(ctx, bs.pushScope(), http.MethodPut, bs.manifestURL(), hdr, bytesBody(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return ociResponseError(resp)
+	}
+	return nil
+}
+
+// pushBlob uploads the contents of |reader| to the repository, unless a blob with the same digest is already there,
+// and returns its descriptor
+func (bs *OCIBlobstore) pushBlob(ctx context.Context, reader io.Reader) (ociDescriptor, error) {
+	f, err := os.CreateTemp("", "dolt-oci-blob-")
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), reader)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	desc := ociDescriptor{MediaType: OCIBlobMediaType, Digest: "sha256:" + hex.EncodeToString(h.Sum(nil)), Size: size}
+
+	resp, err := bs.do(ctx, bs.pushScope(), http.MethodHead, bs.blobURL(desc.Digest), nil, nil)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return desc, nil
+	}
+
+	resp, err = bs.do(ctx, bs.pushScope(), http.MethodPost, bs.registryURL.JoinPath("v2", bs.repository, "blobs", "uploads/").String(), nil, nil)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return ociDescriptor{}, ociResponseError(resp)
+	}
+
+	loc, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	q := loc.Query()
+	q.Set("digest", desc.Digest)
+	loc.RawQuery = q.Encode()
+
+	hdr := http.Header{}
+	hdr.Set("Content-Type", "application/octet-stream")
+	resp, err = bs.do(ctx, bs.pushScope(), http.MethodPut, loc.String(), hdr, func() (io.Reader, int64, error) {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+		return f, size, nil
+	})
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return ociDescriptor{}, ociResponseError(resp)
+	}
+	return desc, nil
+}
+
+func (bs *OCIBlobstore) manifestURL() string {
+	return bs.registryURL.JoinPath("v2", bs.repository, "manifests", bs.tag).String()
+}
+
+func (bs *OCIBlobstore) blobURL(digest string) string {
+	return bs.registryURL.JoinPath("v2", bs.repository, "blobs", digest).String()
+}
+
+func (bs *OCIBlobstore) pullScope() string {
+	return "repository:" + bs.repository + ":pull"
+}
+
+func (bs *OCIBlobstore) pushScope() string {
+	return "repository:" + bs.repository + ":pull,push"
+}
+
+// requestBody returns the body of a request, and its length. It's called again when a request is retried after
+// authenticating.
+type requestBody func() (io.Reader, int64, error)
+
+func bytesBody(data []byte) requestBody {
+	return func() (io.Reader, int64, error) {
+		return bytes.NewReader(data), int64(len(data)), nil
+	}
+}
+
+// do sends a request to the registry, authenticating for |scope| and sending it again if the registry asks for
+// credentials
+func (bs *OCIBlobstore) do(ctx context.Context, scope, method, url string, hdr http.Header, body requestBody) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		var rd io.Reader
+		var length int64
+		if body != nil {
+			var err error
+			if rd, length, err = body(); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, rd)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range hdr {
+			req.Header[k] = v
+		}
+		if body != nil {
+			req.ContentLength = length
+		}
+
+		bs.tokensMu.Lock()
+		auth := bs.tokens[scope]
+		bs.tokensMu.Unlock()
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		return bs.client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if err = bs.authenticate(ctx, scope, resp.Header.Get("WWW-Authenticate")); err != nil {
+		return nil, err
+	}
+	return send()
+}
+
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate gets the Authorization header to send with requests for |scope| in response to |challenge|
+func (bs *OCIBlobstore) authenticate(ctx context.Context, scope, challenge string) error {
+	authType, params, _ := strings.Cut(challenge, " ")
+
+	var auth string
+	switch strings.ToLower(authType) {
+	case "basic":
+		if bs.creds.Username == "" {
+			return fmt.Errorf("%s requires credentials", bs.registryURL.Host)
+		}
+		req, err := http.NewRequest(http.MethodGet, bs.registryURL.String(), nil)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(bs.creds.Username, bs.creds.Password)
+		auth = req.Header.Get("Authorization")
+
+	case "bearer":
+		vals := make(map[string]string)
+		for _, m := range challengeParamRegex.FindAllStringSubmatch(params, -1) {
+			vals[strings.ToLower(m[1])] = m[2]
+		}
+		token, err := bs.fetchToken(ctx, vals["realm"], vals["service"], scope)
+		if err != nil {
+			return err
+		}
+		auth = "Bearer " + token
+
+	default:
+		return fmt.Errorf("%s asked for unsupported authentication '%s'", bs.registryURL.Host, challenge)
+	}
+
+	bs.tokensMu.Lock()
+	defer bs.tokensMu.Unlock()
+	bs.tokens[scope] = auth
+	return nil
+}
+
+// fetchToken gets a bearer token for |scope| from the token server at |realm|
+func (bs *OCIBlobstore) fetchToken(ctx context.Context, realm, service, scope string) (string, error) {
+	if realm == "" {
+		return "", fmt.Errorf("%s asked for a bearer token without giving a realm", bs.registryURL.Host)
+	}
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	if service != "" {
+		q.Set("service", service)
+	}
+	q.Set("scope", scope)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if bs.creds.Username != "" {
+		req.SetBasicAuth(bs.creds.Username, bs.creds.Password)
+	}
+
+	resp, err := bs.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", ociResponseError(resp)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	} else if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, nil
+	}
+	return "", fmt.Errorf("no token in the response from %s", realm)
+}
+
+func ociResponseError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("%s %s failed with status %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status, strings.TrimSpace(string(msg)))
+}
+
+func sha256Digest(data []byte) string {
+	h := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
@@ -0,0 +1,191 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOCIUser     = "user"
+	testOCIPassword = "password"
+	testOCIToken    = "token"
+)
+
+// testOCIRegistry is an in memory implementation of the parts of the OCI distribution API used by OCIBlobstore. It
+// requires bearer tokens, which it gives out to testOCIUser.
+type testOCIRegistry struct {
+	mu        *sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   map[string]bool
+}
+
+func newTestOCIRegistry() *httptest.Server {
+	reg := &testOCIRegistry{
+		mu:        &sync.Mutex{},
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string][]byte),
+		uploads:   make(map[string]bool),
+	}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != testOCIUser || pass != testOCIPassword {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": testOCIToken})
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+testOCIToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.serve(w, r)
+	}))
+	return srv
+}
+
+func (reg *testOCIRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	p := strings.TrimPrefix(r.URL.Path, "/v2/")
+	switch {
+	case strings.Contains(p, "/blobs/uploads/"):
+		repo, id, _ := strings.Cut(p, "/blobs/uploads/")
+		if r.Method == http.MethodPost {
+			id = uuid.New().String()
+			reg.uploads[id] = true
+			w.Header().Set("Location", "/v2/"+repo+"/blobs/uploads/"+id)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if !reg.uploads[id] || digest != sha256Digest(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		delete(reg.uploads, id)
+		reg.blobs[digest] = data
+		w.WriteHeader(http.StatusCreated)
+
+	case strings.Contains(p, "/blobs/"):
+		_, digest, _ := strings.Cut(p, "/blobs/")
+		data, ok := reg.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+
+	case strings.Contains(p, "/manifests/"):
+		if r.Method == http.MethodPut {
+			data, _ := io.ReadAll(r.Body)
+			var m ociManifest
+			if err := json.Unmarshal(data, &m); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for _, desc := range append(m.Layers, m.Config) {
+				if _, ok := reg.blobs[desc.Digest]; !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}
+			reg.manifests[p] = data
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		data, ok := reg.manifests[p]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ociManifestMediaType)
+		_, _ = w.Write(data)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func appendOCITest(tests []BlobstoreTest) []BlobstoreTest {
+	srv := newTestOCIRegistry()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		panic(err)
+	}
+	bs := NewOCIBlobstore(srv.Client(), u, "org/"+uuid.New().String(), "latest", OCICredentials{testOCIUser, testOCIPassword})
+	return append(tests, BlobstoreTest{"oci", bs, 4, 4})
+}
+
+func TestOCIBlobstoreManifest(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestOCIRegistry()
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	bs := NewOCIBlobstore(srv.Client(), u, "org/db", "v1", OCICredentials{testOCIUser, testOCIPassword})
+	assert.Equal(t, u.Host+"/org/db:v1", bs.Path())
+
+	_, err = PutBytes(ctx, bs, "b", []byte("bbb"))
+	require.NoError(t, err)
+	ver, err := PutBytes(ctx, bs, "a", []byte("aaa"))
+	require.NoError(t, err)
+	assert.Equal(t, sha256Digest([]byte("aaa")), ver)
+
+	m, err := bs.getManifest(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, OCIArtifactType, m.ArtifactType)
+	assert.Equal(t, ociEmptyMediaType, m.Config.MediaType)
+	require.Len(t, m.Layers, 2)
+	assert.Equal(t, "a", m.Layers[0].Annotations[ociTitleAnnotation])
+	assert.Equal(t, "b", m.Layers[1].Annotations[ociTitleAnnotation])
+	assert.Equal(t, OCIBlobMediaType, m.Layers[0].MediaType)
+	assert.Equal(t, int64(3), m.Layers[0].Size)
+
+	// other tags of the repository are separate databases
+	other := NewOCIBlobstore(srv.Client(), u, "org/db", "v2", OCICredentials{testOCIUser, testOCIPassword})
+	ok, err := other.Exists(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// registries require credentials
+	anon := NewOCIBlobstore(srv.Client(), u, "org/db", "v1", OCICredentials{})
+	_, err = anon.Exists(ctx, "a")
+	assert.Error(t, err)
+}
@@ -160,3 +160,19 @@ type GenerationalCS interface {
 var ErrUnsupportedOperation = errors.New("operation not supported")
 
 var ErrGCGenerationExpired = errors.New("garbage collection generation expired")
+
+// DeltaChunk is a chunk encoded as a delta against a base chunk. See the delta package.
+type DeltaChunk struct {
+	H     hash.Hash
+	Base  hash.Hash
+	Delta []byte
+}
+
+// DeltaChunkSource is implemented by ChunkStores which can send chunks as deltas against base chunks that the
+// receiver already has, which is far fewer bytes than the chunks themselves when they're similar.
+type DeltaChunkSource interface {
+	// GetManyDeltas calls |found| with the delta of each chunk in |bases|, a map from the address of a chunk to the
+	// address of its base, against its base. Chunks whose delta isn't smaller than the chunk, or whose base the store
+	// doesn't have, are skipped.
+	GetManyDeltas(ctx context.Context, bases map[hash.Hash]hash.Hash, found func(context.Context, DeltaChunk)) error
+}
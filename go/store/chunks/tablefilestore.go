@@ -72,3 +72,19 @@ type TableFileStore interface {
 	// SupportedOperations returns a description of the support TableFile operations. Some stores only support reading table files, not writing.
 	SupportedOperations() TableFileStoreOps
 }
+
+// UploadedTableFileStore is implemented by TableFileStores which can report on table files that were written to them
+// but may not have been added to their manifest yet, such as the table files of an interrupted push.
+type UploadedTableFileStore interface {
+	// HasUploadedTableFiles returns the subset of |fileIdToNumChunks| that was written to the store and can still be
+	// added to its manifest with AddTableFilesToManifest.
+	HasUploadedTableFiles(ctx context.Context, fileIdToNumChunks map[string]int) (map[string]int, error)
+}
+
+// DeltaTableFileStore is implemented by TableFileStores which can receive a table file as a delta pack, which encodes
+// some of the chunks of the table file as deltas against chunks the store already has. See nbs.DeltaPackWriter.
+type DeltaTableFileStore interface {
+	// WriteTableFileWithDeltaPack writes a table file like WriteTableFile. The store reads the delta pack of the table
+	// file with |getPackRd| if it can rebuild the table file from it, and otherwise reads the table file with |getRd|.
+	WriteTableFileWithDeltaPack(ctx context.Context, fileId string, numChunks int, contentHash []byte, getRd, getPackRd func() (io.ReadCloser, uint64, error)) error
+}
@@ -141,6 +141,15 @@ func NewCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 	return newCommitForValue(ctx, cs, vrw, ns, v, opts)
 }
 
+// NewRootCommitForValue returns a commit of |v| with no parents, which starts a new history.
+func NewRootCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.ValueReadWriter, ns tree.NodeStore, v types.Value, opts CommitOptions) (*Commit, error) {
+	if len(opts.Parents) > 0 {
+		return nil, errors.New("cannot create root commit with parents")
+	}
+
+	return newCommitForValue(ctx, cs, vrw, ns, v, opts)
+}
+
 func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, parentsClosureAddr hash.Hash) (serial.Message, uint64) {
 	builder := flatbuffers.NewBuilder(1024)
 	vaddroff := builder.CreateByteVector(vaddr[:])
@@ -169,6 +178,14 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	nameoff := builder.CreateString(opts.Meta.Name)
 	emailoff := builder.CreateString(opts.Meta.Email)
 	descoff := builder.CreateString(opts.Meta.Description)
+	var sigoff flatbuffers.UOffsetT
+	if opts.Meta.Signature != "" {
+		sigoff = builder.CreateString(opts.Meta.Signature)
+	}
+	var mdoff flatbuffers.UOffsetT
+	if opts.Meta.Metadata != "" {
+		mdoff = builder.CreateString(opts.Meta.Metadata)
+	}
 	serial.CommitStart(builder)
 	serial.CommitAddRoot(builder, vaddroff)
 	serial.CommitAddHeight(builder, maxheight+1)
@@ -179,6 +196,12 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	serial.CommitAddDescription(builder, descoff)
 	serial.CommitAddTimestampMillis(builder, opts.Meta.Timestamp)
 	serial.CommitAddUserTimestampMillis(builder, opts.Meta.UserTimestamp)
+	if opts.Meta.Signature != "" {
+		serial.CommitAddSignature(builder, sigoff)
+	}
+	if opts.Meta.Metadata != "" {
+		serial.CommitAddMetadata(builder, mdoff)
+	}
 
 	bytes := serial.FinishMessage(builder, serial.CommitEnd(builder), []byte(serial.CommitFileID))
 	return bytes, maxheight + 1
@@ -217,6 +240,14 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		if err != nil {
 			return nil, err
 		}
+		if opts.Sign != nil {
+			meta := *opts.Meta
+			meta.Signature, err = opts.Sign(CommitSigningPayload(r.TargetHash(), opts.Parents, &meta))
+			if err != nil {
+				return nil, err
+			}
+			opts.Meta = &meta
+		}
 		bs, height := commit_flatbuffer(r.TargetHash(), opts, heights, parentClosureAddr)
 		v := types.SerialMessage(bs)
 		addr, err := v.Hash(vrw.Format())
@@ -226,6 +257,10 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		return &Commit{v, addr, height}, nil
 	}
 
+	if opts.Sign != nil {
+		return nil, ErrSigningNotSupported
+	}
+
 	metaSt, err := opts.Meta.toNomsStruct(vrw.Format())
 	if err != nil {
 		return nil, err
@@ -557,6 +592,8 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 		ret.Description = string(cmsg.Description())
 		ret.Timestamp = cmsg.TimestampMillis()
 		ret.UserTimestamp = cmsg.UserTimestampMillis()
+		ret.Signature = string(cmsg.Signature())
+		ret.Metadata = string(cmsg.Metadata())
 		return ret, nil
 	}
 	c, ok := cv.(types.Struct)
@@ -0,0 +1,420 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// A CommitGraph holds the heights and parents of commits, so that ancestry queries, like finding a merge base or
+// counting the commits between two branches, can walk the commit graph in memory instead of reading and
+// deserializing commits one at a time. It's serialized to a commit-graph file, which is read when a database is
+// opened.
+//
+// Commits are immutable and addressed by their contents, so a CommitGraph is never wrong about the commits it holds,
+// but it can be missing commits made after it was last written. Queries read those commits from the database, so a
+// CommitGraph only needs to be written from time to time to keep queries fast.
+//
+// Commits are kept in topological order: every commit's parents come before it, and a commit's parents are stored as
+// their positions in the graph.
+type CommitGraph struct {
+	mu      sync.RWMutex
+	pos     map[hash.Hash]uint32
+	entries []commitGraphEntry
+}
+
+type commitGraphEntry struct {
+	addr    hash.Hash
+	height  uint64
+	parents []uint32
+}
+
+var commitGraphMagic = [4]byte{'D', 'C', 'G', 'R'}
+
+const commitGraphVersion = 1
+
+var ErrCorruptCommitGraph = errors.New("commit-graph file is corrupt")
+
+// NewCommitGraph returns an empty CommitGraph.
+func NewCommitGraph() *CommitGraph {
+	return &CommitGraph{pos: make(map[hash.Hash]uint32)}
+}
+
+// ReadCommitGraph reads a CommitGraph written by CommitGraph.WriteTo.
+//
+// The format is the magic bytes "DCGR", a version byte and the number of commits as a uint32, followed by each
+// commit's address, its height as a uint64, the number of its parents as a byte and the position of each parent as a
+// uint32, and finally a CRC-32 of everything before it. Integers are big endian.
+func ReadCommitGraph(r io.Reader) (*CommitGraph, error) {
+	// everything but the trailer is read through |cr|, so that the checksum doesn't include the trailer
+	br := bufio.NewReader(r)
+	crc := crc32.NewIEEE()
+	cr := io.TeeReader(br, crc)
+
+	var header [9]byte
+	if _, err := io.ReadFull(cr, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptCommitGraph, err)
+	}
+	if !bytes.Equal(header[:4], commitGraphMagic[:]) {
+		return nil, fmt.Errorf("%w: bad magic", ErrCorruptCommitGraph)
+	}
+	if header[4] != commitGraphVersion {
+		return nil, fmt.Errorf("unsupported commit-graph version %d", header[4])
+	}
+	n := binary.BigEndian.Uint32(header[5:])
+
+	g := &CommitGraph{pos: make(map[hash.Hash]uint32, n), entries: make([]commitGraphEntry, 0, n)}
+	var buf [hash.ByteLen + 8 + 1]byte
+	for i := uint32(0); i < n; i++ {
+		if _, err := io.ReadFull(cr, buf[:]); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorruptCommitGraph, err)
+		}
+		e := commitGraphEntry{
+			addr:    hash.New(buf[:hash.ByteLen]),
+			height:  binary.BigEndian.Uint64(buf[hash.ByteLen:]),
+			parents: make([]uint32, buf[hash.ByteLen+8]),
+		}
+		for j := range e.parents {
+			var p [4]byte
+			if _, err := io.ReadFull(cr, p[:]); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrCorruptCommitGraph, err)
+			}
+			e.parents[j] = binary.BigEndian.Uint32(p[:])
+			if e.parents[j] >= i {
+				return nil, fmt.Errorf("%w: parent out of order", ErrCorruptCommitGraph)
+			}
+		}
+		g.pos[e.addr] = i
+		g.entries = append(g.entries, e)
+	}
+
+	sum := crc.Sum32()
+	var trailer [4]byte
+	if _, err := io.ReadFull(br, trailer[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptCommitGraph, err)
+	}
+	if binary.BigEndian.Uint32(trailer[:]) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorruptCommitGraph)
+	}
+	return g, nil
+}
+
+// WriteTo writes the CommitGraph to |w| in the format read by ReadCommitGraph.
+func (g *CommitGraph) WriteTo(w io.Writer) (int64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	crc := crc32.NewIEEE()
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+	var written int64
+	write := func(bs []byte) error {
+		n, err := bw.Write(bs)
+		written += int64(n)
+		return err
+	}
+
+	header := make([]byte, 9)
+	copy(header, commitGraphMagic[:])
+	header[4] = commitGraphVersion
+	binary.BigEndian.PutUint32(header[5:], uint32(len(g.entries)))
+	if err := write(header); err != nil {
+		return written, err
+	}
+	for _, e := range g.entries {
+		buf := make([]byte, hash.ByteLen+8+1+4*len(e.parents))
+		copy(buf, e.addr[:])
+		binary.BigEndian.PutUint64(buf[hash.ByteLen:], e.height)
+		buf[hash.ByteLen+8] = byte(len(e.parents))
+		for j, p := range e.parents {
+			binary.BigEndian.PutUint32(buf[hash.ByteLen+9+4*j:], p)
+		}
+		if err := write(buf); err != nil {
+			return written, err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return written, err
+	}
+
+	var trailer [4]byte
+	binary.BigEndian.PutUint32(trailer[:], crc.Sum32())
+	n, err := w.Write(trailer[:])
+	return written + int64(n), err
+}
+
+// Len returns the number of commits in the CommitGraph.
+func (g *CommitGraph) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.entries)
+}
+
+// Contains returns whether the commit at |addr| is in the CommitGraph.
+func (g *CommitGraph) Contains(addr hash.Hash) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.pos[addr]
+	return ok
+}
+
+// Add adds the commits reachable from |heads| that aren't in the CommitGraph yet, reading them from |vr|. It returns
+// the number of commits added.
+func (g *CommitGraph) Add(ctx context.Context, vr types.ValueReader, heads []hash.Hash) (int, error) {
+	type pending struct {
+		addr    hash.Hash
+		height  uint64
+		parents []hash.Hash
+	}
+	var added []pending
+	seen := make(map[hash.Hash]bool)
+	stack := append([]hash.Hash(nil), heads...)
+	for len(stack) > 0 {
+		addr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[addr] || g.Contains(addr) {
+			continue
+		}
+		seen[addr] = true
+
+		height, parents, err := readCommitGraphEntry(ctx, vr, addr)
+		if err != nil {
+			return 0, err
+		}
+		added = append(added, pending{addr, height, parents})
+		stack = append(stack, parents...)
+	}
+
+	// A commit is higher than each of its parents, so ordering the new commits by height puts them after their parents.
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].height < added[j].height
+	})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, p := range added {
+		if _, ok := g.pos[p.addr]; ok {
+			continue
+		}
+		e := commitGraphEntry{addr: p.addr, height: p.height, parents: make([]uint32, len(p.parents))}
+		for i, parent := range p.parents {
+			e.parents[i] = g.pos[parent]
+		}
+		g.pos[p.addr] = uint32(len(g.entries))
+		g.entries = append(g.entries, e)
+	}
+	return len(added), nil
+}
+
+// readCommitGraphEntry reads the height and the parents of the commit at |addr| from |vr|.
+func readCommitGraphEntry(ctx context.Context, vr types.ValueReader, addr hash.Hash) (uint64, []hash.Hash, error) {
+	c, err := LoadCommitAddr(ctx, vr, addr)
+	if err != nil {
+		return 0, nil, err
+	}
+	if sm, ok := c.NomsValue().(types.SerialMessage); ok {
+		parents, err := types.SerialCommitParentAddrs(vr.Format(), sm)
+		return c.Height(), parents, err
+	}
+	pcs, err := GetCommitParents(ctx, vr, c.NomsValue())
+	if err != nil {
+		return 0, nil, err
+	}
+	parents := make([]hash.Hash, len(pcs))
+	for i, pc := range pcs {
+		parents[i] = pc.Addr()
+	}
+	return c.Height(), parents, nil
+}
+
+// commitGraphWalk looks up commits in a CommitGraph for a single query, and reads the commits that aren't in it from
+// a ValueReader.
+type commitGraphWalk struct {
+	g     *CommitGraph
+	vr    types.ValueReader
+	extra map[hash.Hash]walkCommit
+}
+
+type walkCommit struct {
+	height  uint64
+	parents []hash.Hash
+}
+
+func (g *CommitGraph) newWalk(vr types.ValueReader) *commitGraphWalk {
+	return &commitGraphWalk{g: g, vr: vr, extra: make(map[hash.Hash]walkCommit)}
+}
+
+func (w *commitGraphWalk) get(ctx context.Context, addr hash.Hash) (walkCommit, error) {
+	if w.g != nil {
+		w.g.mu.RLock()
+		i, ok := w.g.pos[addr]
+		if ok {
+			e := w.g.entries[i]
+			wc := walkCommit{height: e.height, parents: make([]hash.Hash, len(e.parents))}
+			for j, p := range e.parents {
+				wc.parents[j] = w.g.entries[p].addr
+			}
+			w.g.mu.RUnlock()
+			return wc, nil
+		}
+		w.g.mu.RUnlock()
+	}
+	if wc, ok := w.extra[addr]; ok {
+		return wc, nil
+	}
+	height, parents, err := readCommitGraphEntry(ctx, w.vr, addr)
+	if err != nil {
+		return walkCommit{}, err
+	}
+	wc := walkCommit{height: height, parents: parents}
+	w.extra[addr] = wc
+	return wc, nil
+}
+
+const (
+	paintLeft  = 1
+	paintRight = 2
+	paintBoth  = paintLeft | paintRight
+)
+
+type paintItem struct {
+	addr   hash.Hash
+	height uint64
+}
+
+type paintQueue []paintItem
+
+func (q paintQueue) Len() int            { return len(q) }
+func (q paintQueue) Less(i, j int) bool  { return q[i].height > q[j].height }
+func (q paintQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *paintQueue) Push(x interface{}) { *q = append(*q, x.(paintItem)) }
+func (q *paintQueue) Pop() interface{} {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
+
+// paint walks the ancestors of |left| and |right| from the highest commits down, marking each commit with whether
+// it's reachable from |left|, |right| or both. |visit| is called with each commit and its final marks, and the walk
+// stops when it returns false.
+func (w *commitGraphWalk) paint(ctx context.Context, left, right []hash.Hash, visit func(addr hash.Hash, marks int, remaining map[hash.Hash]int) bool) error {
+	marks := make(map[hash.Hash]int)
+	done := make(map[hash.Hash]bool)
+	q := &paintQueue{}
+	push := func(addr hash.Hash, m int) error {
+		if done[addr] {
+			return nil
+		}
+		if old, ok := marks[addr]; ok {
+			marks[addr] = old | m
+			return nil
+		}
+		wc, err := w.get(ctx, addr)
+		if err != nil {
+			return err
+		}
+		marks[addr] = m
+		heap.Push(q, paintItem{addr, wc.height})
+		return nil
+	}
+	for _, h := range left {
+		if err := push(h, paintLeft); err != nil {
+			return err
+		}
+	}
+	for _, h := range right {
+		if err := push(h, paintRight); err != nil {
+			return err
+		}
+	}
+
+	for q.Len() > 0 {
+		it := heap.Pop(q).(paintItem)
+		m := marks[it.addr]
+		delete(marks, it.addr)
+		done[it.addr] = true
+		if !visit(it.addr, m, marks) {
+			return nil
+		}
+		wc, err := w.get(ctx, it.addr)
+		if err != nil {
+			return err
+		}
+		for _, p := range wc.parents {
+			if err := push(p, m); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FindCommonAncestor returns the highest commit that is an ancestor of both |c1| and |c2|, like the package level
+// FindCommonAncestor, looking up the commits between them in the CommitGraph and reading those it doesn't have from
+// |vr|.
+func (g *CommitGraph) FindCommonAncestor(ctx context.Context, vr types.ValueReader, c1, c2 hash.Hash) (hash.Hash, bool, error) {
+	var ancestor hash.Hash
+	var found bool
+	err := g.newWalk(vr).paint(ctx, []hash.Hash{c1}, []hash.Hash{c2}, func(addr hash.Hash, marks int, _ map[hash.Hash]int) bool {
+		if marks == paintBoth {
+			ancestor, found = addr, true
+			return false
+		}
+		return true
+	})
+	return ancestor, found, err
+}
+
+// CountCommitsNotReachable returns the number of commits that are reachable from |from| but not from |exclude|, like
+// `git rev-list --count exclude..from`.
+func (g *CommitGraph) CountCommitsNotReachable(ctx context.Context, vr types.ValueReader, from, exclude hash.Hash) (uint64, error) {
+	var count uint64
+	err := g.newWalk(vr).paint(ctx, []hash.Hash{from}, []hash.Hash{exclude}, func(addr hash.Hash, marks int, remaining map[hash.Hash]int) bool {
+		if marks == paintLeft {
+			count++
+		}
+		// Once every commit left to walk is reachable from |exclude|, so are all of their ancestors.
+		for _, m := range remaining {
+			if m == paintLeft {
+				return true
+			}
+		}
+		return marks == paintLeft
+	})
+	return count, err
+}
+
+// ReachableFrom returns the commits reachable from |heads|, including |heads| themselves.
+func (g *CommitGraph) ReachableFrom(ctx context.Context, vr types.ValueReader, heads []hash.Hash) (hash.HashSet, error) {
+	reachable := hash.NewHashSet()
+	err := g.newWalk(vr).paint(ctx, heads, nil, func(addr hash.Hash, _ int, _ map[hash.Hash]int) bool {
+		reachable.Insert(addr)
+		return true
+	})
+	return reachable, err
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestCommitGraph(t *testing.T) {
+	ctx := context.Background()
+	storage := &chunks.TestStorage{}
+	db := NewDatabase(storage.NewViewWithDefaultFormat()).(*database)
+	defer db.Close()
+
+	// ds-a: a1<-a2<-a3<-a4<-a5
+	//            ^   ^      |
+	//            |    \--\  |
+	//            |        \ V
+	// ds-b:      b3<-b4<-b5
+	//
+	// ds-d: d1
+	a, b, d := "ds-a", "ds-b", "ds-d"
+	a1v, a1 := addCommit(t, db, a, "a1")
+	_, d1 := addCommit(t, db, d, "d1")
+	a2v, a2 := addCommit(t, db, a, "a2", a1v)
+	a3v, a3 := addCommit(t, db, a, "a3", a2v)
+	b3v, b3 := addCommit(t, db, b, "b3", a2v)
+	a4v, a4 := addCommit(t, db, a, "a4", a3v)
+	b4v, b4 := addCommit(t, db, b, "b4", b3v)
+	b5v, b5 := addCommit(t, db, b, "b5", b4v, a3v)
+	_, a5 := addCommit(t, db, a, "a5", a4v, b5v)
+
+	// Only a4 and its ancestors are in the graph, so queries that involve later commits read them from |db|.
+	g := NewCommitGraph()
+	added, err := g.Add(ctx, db, []hash.Hash{a4})
+	require.NoError(t, err)
+	assert.Equal(t, 4, added)
+	assert.True(t, g.Contains(a1))
+	assert.False(t, g.Contains(b3))
+
+	added, err = g.Add(ctx, db, []hash.Hash{a4, b4})
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 6, g.Len())
+
+	var buf bytes.Buffer
+	_, err = g.WriteTo(&buf)
+	require.NoError(t, err)
+	read, err := ReadCommitGraph(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, g.entries, read.entries)
+
+	corrupt := append([]byte(nil), buf.Bytes()...)
+	corrupt[len(corrupt)-5] ^= 0xff
+	_, err = ReadCommitGraph(bytes.NewReader(corrupt))
+	assert.True(t, errors.Is(err, ErrCorruptCommitGraph))
+
+	ancestorTests := []struct {
+		c1, c2   hash.Hash
+		ancestor hash.Hash
+	}{
+		{a1, a1, a1},
+		{a1, a2, a1},
+		{a3, b3, a2},
+		{a4, b4, a2},
+		{a4, b5, a3},
+		{a5, b5, b5},
+	}
+	for _, test := range ancestorTests {
+		for _, graph := range []*CommitGraph{read, NewCommitGraph()} {
+			ancestor, ok, err := graph.FindCommonAncestor(ctx, db, test.c1, test.c2)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, test.ancestor, ancestor)
+		}
+	}
+	_, ok, err := read.FindCommonAncestor(ctx, db, a5, d1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	count, err := read.CountCommitsNotReachable(ctx, db, a5, b4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), count) // a5, b5, a4 and a3
+	count, err = read.CountCommitsNotReachable(ctx, db, b4, a5)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+	count, err = read.CountCommitsNotReachable(ctx, db, b5, a4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	reachable, err := read.ReachableFrom(ctx, db, []hash.Hash{b5})
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(b5, b4, b3, a3, a2, a1), reachable)
+}
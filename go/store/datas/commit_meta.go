@@ -15,6 +15,7 @@
 package datas
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -30,6 +31,7 @@ const (
 	commitMetaTimestampKey = "timestamp"
 	commitMetaUserTSKey    = "user_timestamp"
 	commitMetaVersionKey   = "metaversion"
+	commitMetaMetadataKey  = "custom_metadata"
 
 	commitMetaStName  = "metadata"
 	commitMetaVersion = "1.0"
@@ -40,6 +42,7 @@ const defaultInitialCommitMessage = "Initialize data repository"
 var ErrNameNotConfigured = errors.New("Aborting commit due to empty committer name. Is your config set?")
 var ErrEmailNotConfigured = errors.New("Aborting commit due to empty committer email. Is your config set?")
 var ErrEmptyCommitMessage = errors.New("Aborting commit due to empty commit message.")
+var ErrInvalidCommitMetadata = errors.New("commit metadata must be a JSON object")
 
 var CommitNowFunc = time.Now
 var CommitLoc = time.Local
@@ -51,6 +54,12 @@ type CommitMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	// Signature is the GPG or SSH signature of the commit, or empty if the commit isn't signed. See
+	// CommitSigningPayload.
+	Signature string
+	// Metadata is a JSON object of key-value metadata attached to the commit by its author, or empty if there isn't
+	// any. See NormalizeCommitMetadata.
+	Metadata string
 }
 
 // NewCommitMeta creates a CommitMeta instance from a name, email, and description and uses the current time for the
@@ -80,7 +89,29 @@ func NewCommitMetaWithUserTS(name, email, desc string, userTS time.Time) (*Commi
 	ms := uint64(CommitNowFunc().UnixMilli())
 	userMS := userTS.UnixMilli()
 
-	return &CommitMeta{n, e, ms, d, userMS}, nil
+	return &CommitMeta{n, e, ms, d, userMS, "", ""}, nil
+}
+
+// NormalizeCommitMetadata checks that |md| is a JSON object, and returns it in canonical form, with its keys sorted
+// and without insignificant whitespace. An empty object is normalized to the empty string.
+func NormalizeCommitMetadata(md string) (string, error) {
+	var obj map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(md))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil || obj == nil || dec.More() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCommitMetadata, md)
+	}
+	if len(obj) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
 }
 
 func getRequiredFromSt(st types.Struct, k string) (types.Value, error) {
@@ -126,12 +157,21 @@ func CommitMetaFromNomsSt(st types.Struct) (*CommitMeta, error) {
 		userTS = types.Int(int64(uint64(ts.(types.Uint))))
 	}
 
+	var md string
+	if v, ok, err := st.MaybeGet(commitMetaMetadataKey); err != nil {
+		return nil, err
+	} else if ok {
+		md = string(v.(types.String))
+	}
+
 	return &CommitMeta{
 		string(n.(types.String)),
 		string(e.(types.String)),
 		uint64(ts.(types.Uint)),
 		string(d.(types.String)),
 		int64(userTS.(types.Int)),
+		"",
+		md,
 	}, nil
 }
 
@@ -144,6 +184,9 @@ func (cm *CommitMeta) toNomsStruct(nbf *types.NomsBinFormat) (types.Struct, erro
 		commitMetaVersionKey:   types.String(commitMetaVersion),
 		commitMetaUserTSKey:    types.Int(cm.UserTimestamp),
 	}
+	if cm.Metadata != "" {
+		metadata[commitMetaMetadataKey] = types.String(cm.Metadata)
+	}
 
 	return types.NewStruct(nbf, commitMetaStName, metadata)
 }
@@ -37,3 +37,28 @@ func TestCommitMetaToAndFromNomsStruct(t *testing.T) {
 
 	t.Log(cm.String())
 }
+
+func TestCommitMetadataToAndFromNomsStruct(t *testing.T) {
+	cm, _ := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "This is a test commit")
+	cm.Metadata = `{"ticket":"ABC-123"}`
+	cmSt, err := cm.toNomsStruct(types.Format_Default)
+	assert.NoError(t, err)
+	result, err := CommitMetaFromNomsSt(cmSt)
+	assert.NoError(t, err)
+	assert.Equal(t, cm, result)
+}
+
+func TestNormalizeCommitMetadata(t *testing.T) {
+	md, err := NormalizeCommitMetadata(` { "ticket": "ABC-123", "score": 0.95, "run": {"url": "https://ci/run?a=1&b=2"} } `)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"run":{"url":"https://ci/run?a=1&b=2"},"score":0.95,"ticket":"ABC-123"}`, md)
+
+	md, err = NormalizeCommitMetadata(`{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "", md)
+
+	for _, bad := range []string{``, `null`, `[1, 2]`, `"ticket"`, `{"ticket": }`, `{} {}`} {
+		_, err = NormalizeCommitMetadata(bad)
+		assert.ErrorIs(t, err, ErrInvalidCommitMetadata, bad)
+	}
+}
@@ -33,4 +33,8 @@ type CommitOptions struct {
 	Parents []hash.Hash
 
 	Meta *CommitMeta
+
+	// Sign, if set, signs the commit. It's called with the commit's signing payload once its parents are known, and
+	// returns the signature to store in the commit's metadata. See CommitSigningPayload.
+	Sign func(payload []byte) (string, error)
 }
@@ -97,6 +97,11 @@ type Database interface {
 	// `opts.Meta`.
 	Tag(ctx context.Context, ds Dataset, commitAddr hash.Hash, opts TagOptions) (Dataset, error)
 
+	// BuildNewTag writes a new Tag struct pointing at |commitAddr| with the
+	// metadata in |opts.Meta|, but does not point any dataset at it. It
+	// returns the address of the new Tag, which can be passed to SetHead.
+	BuildNewTag(ctx context.Context, commitAddr hash.Hash, opts TagOptions) (hash.Hash, error)
+
 	// UpdateStashList updates the stash list dataset only with given address hash to the updated stash list.
 	// The new/updated stash list address should be obtained before calling this function depending on
 	// whether add or remove a stash actions have been performed. This function does not perform any actions
@@ -127,6 +132,14 @@ type Database interface {
 	// Delete returns an 'ErrMergeNeeded' error.
 	Delete(ctx context.Context, ds Dataset) (Dataset, error)
 
+	// SetDatasets points each dataset named in |heads| at the head of the
+	// dataset it maps to, and removes the datasets named in |deletes|, in a
+	// single update of the root of the Database. If |prevHashes| has a hash
+	// for a dataset named in |heads|, the dataset's current head must match
+	// it or this method returns ErrOptimisticLockFailed, as
+	// UpdateWorkingSet does.
+	SetDatasets(ctx context.Context, heads map[string]string, deletes []string, prevHashes map[string]hash.Hash) error
+
 	// SetHead ignores any lineage constraints (e.g. the current head being
 	// an ancestor of the new Commit) and force-sets a mapping from
 	// datasetID: addr in this database. addr can point to a Commit or a
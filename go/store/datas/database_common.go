@@ -47,6 +47,7 @@ var (
 	ErrOptimisticLockFailed = errors.New("optimistic lock failed on database Root update")
 	ErrMergeNeeded          = errors.New("dataset head is not ancestor of commit")
 	ErrAlreadyCommitted     = errors.New("dataset head already pointing at given commit")
+	ErrDatasetNotFound      = errors.New("dataset not found")
 )
 
 // rootTracker is a narrowing of the ChunkStore interface, to keep Database disciplined about working directly with Chunks
@@ -550,6 +551,11 @@ func (db *database) Tag(ctx context.Context, ds Dataset, commitAddr hash.Hash, o
 	)
 }
 
+func (db *database) BuildNewTag(ctx context.Context, commitAddr hash.Hash, opts TagOptions) (hash.Hash, error) {
+	addr, _, err := newTag(ctx, db, commitAddr, opts.Meta)
+	return addr, err
+}
+
 // doTag manages concurrent access the single logical piece of mutable state: the current Root. It uses
 // the same optimistic writing algorithm as doCommit (see above).
 func (db *database) doTag(ctx context.Context, datasetID string, tagAddr hash.Hash, tagRef types.Ref) error {
@@ -789,6 +795,65 @@ func (db *database) Delete(ctx context.Context, ds Dataset) (Dataset, error) {
 	return db.doHeadUpdate(ctx, ds, func(ds Dataset) error { return db.doDelete(ctx, ds.ID()) })
 }
 
+func (db *database) SetDatasets(ctx context.Context, heads map[string]string, deletes []string, prevHashes map[string]hash.Hash) error {
+	for id := range heads {
+		if err := ValidateDatasetId(id); err != nil {
+			return err
+		}
+	}
+	return db.update(ctx, func(ctx context.Context, datasets types.Map) (types.Map, error) {
+		ed := datasets.Edit()
+		for _, id := range deletes {
+			ed.Remove(types.String(id))
+		}
+		for id, from := range heads {
+			head, ok, err := datasets.MaybeGet(ctx, types.String(from))
+			if err != nil {
+				return types.Map{}, err
+			} else if !ok {
+				return types.Map{}, fmt.Errorf("cannot set dataset %s to %s: %w", id, from, ErrDatasetNotFound)
+			}
+			if prev, ok := prevHashes[id]; ok {
+				success, err := assertDatasetHash(ctx, datasets, id, prev)
+				if err != nil {
+					return types.Map{}, err
+				} else if !success {
+					return types.Map{}, ErrOptimisticLockFailed
+				}
+			}
+			ed.Set(types.String(id), head)
+		}
+		return ed.Map(ctx)
+	}, func(ctx context.Context, am prolly.AddressMap) (prolly.AddressMap, error) {
+		ae := am.Editor()
+		for _, id := range deletes {
+			if err := ae.Delete(ctx, id); err != nil {
+				return prolly.AddressMap{}, err
+			}
+		}
+		for id, from := range heads {
+			head, err := am.Get(ctx, from)
+			if err != nil {
+				return prolly.AddressMap{}, err
+			} else if head.IsEmpty() {
+				return prolly.AddressMap{}, fmt.Errorf("cannot set dataset %s to %s: %w", id, from, ErrDatasetNotFound)
+			}
+			if prev, ok := prevHashes[id]; ok {
+				curr, err := am.Get(ctx, id)
+				if err != nil {
+					return prolly.AddressMap{}, err
+				} else if curr != prev {
+					return prolly.AddressMap{}, ErrOptimisticLockFailed
+				}
+			}
+			if err = ae.Update(ctx, id, head); err != nil {
+				return prolly.AddressMap{}, err
+			}
+		}
+		return ae.Flush(ctx)
+	})
+}
+
 func (db *database) update(ctx context.Context,
 	edit func(context.Context, types.Map) (types.Map, error),
 	editFB func(context.Context, prolly.AddressMap) (prolly.AddressMap, error)) error {
@@ -322,6 +322,7 @@ func (h serialTagHead) HeadTag() (*TagMeta, hash.Hash, error) {
 		Timestamp:     h.msg.TimestampMillis(),
 		Description:   string(h.msg.Desc()),
 		UserTimestamp: h.msg.UserTimestampMillis(),
+		Signature:     string(h.msg.Signature()),
 	}
 	return meta, addr, nil
 }
@@ -27,12 +27,19 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 )
 
 var ErrNoData = errors.New("no data")
 var ErrCloneUnsupported = errors.New("clone unsupported")
-
-func Clone(ctx context.Context, srcCS, sinkCS chunks.ChunkStore, eventCh chan<- TableFileEvent) error {
+var ErrFormatMismatch = errors.New("src and sink db formats do not match")
+
+// Clone copies the table files of |srcCS| to |sinkCS| and sets the root of |sinkCS| to the root of |srcCS|. If |cache|
+// is not nil, the table files are written through it, and those it already has aren't downloaded. It returns
+// ErrCloneUnsupported if either store doesn't expose its table files, in which case the chunks must be pulled
+// individually instead. Neither converts chunks from one format to another, so it returns ErrFormatMismatch if the
+// stores have different formats.
+func Clone(ctx context.Context, srcCS, sinkCS chunks.ChunkStore, cache *nbs.SharedCache, eventCh chan<- TableFileEvent) error {
 	srcTS, srcOK := srcCS.(chunks.TableFileStore)
 
 	if !srcOK {
@@ -55,7 +62,11 @@ func Clone(ctx context.Context, srcCS, sinkCS chunks.ChunkStore, eventCh chan<-
 		return fmt.Errorf("%w: sink db is not a Table File Store", ErrCloneUnsupported)
 	}
 
-	return clone(ctx, srcTS, sinkTS, sinkCS, eventCh)
+	if srcCS.Version() != sinkCS.Version() {
+		return fmt.Errorf("%w: src db format %s, sink db format %s", ErrFormatMismatch, srcCS.Version(), sinkCS.Version())
+	}
+
+	return clone(ctx, srcTS, sinkTS, sinkCS, cache, eventCh)
 }
 
 type CloneTableFileEvent int
@@ -91,7 +102,7 @@ func mapTableFiles(tblFiles []chunks.TableFile) ([]string, map[string]chunks.Tab
 
 const concurrentTableFileDownloads = 3
 
-func clone(ctx context.Context, srcTS, sinkTS chunks.TableFileStore, sinkCS chunks.ChunkStore, eventCh chan<- TableFileEvent) error {
+func clone(ctx context.Context, srcTS, sinkTS chunks.TableFileStore, sinkCS chunks.ChunkStore, cache *nbs.SharedCache, eventCh chan<- TableFileEvent) error {
 	root, sourceFiles, appendixFiles, err := srcTS.Sources(ctx)
 	if err != nil {
 		return err
@@ -136,7 +147,7 @@ func clone(ctx context.Context, srcTS, sinkTS chunks.TableFileStore, sinkCS chun
 				}
 
 				report(TableFileEvent{EventType: DownloadStart, TableFiles: []chunks.TableFile{tblFile}})
-				err = sinkTS.WriteTableFile(ctx, tblFile.FileID(), tblFile.NumChunks(), nil, func() (io.ReadCloser, uint64, error) {
+				getRd := func() (io.ReadCloser, uint64, error) {
 					rd, contentLength, err := tblFile.Open(ctx)
 					if err != nil {
 						return nil, 0, err
@@ -152,7 +163,12 @@ func clone(ctx context.Context, srcTS, sinkTS chunks.TableFileStore, sinkCS chun
 					})
 
 					return rdStats, contentLength, nil
-				})
+				}
+				if cache != nil {
+					err = cache.WriteTableFile(ctx, sinkTS, tblFile.FileID(), tblFile.NumChunks(), nil, getRd)
+				} else {
+					err = sinkTS.WriteTableFile(ctx, tblFile.FileID(), tblFile.NumChunks(), nil, getRd)
+				}
 				if err != nil {
 					report(TableFileEvent{EventType: DownloadFailed, TableFiles: []chunks.TableFile{tblFile}})
 					return err
@@ -210,7 +226,9 @@ func clone(ctx context.Context, srcTS, sinkTS chunks.TableFileStore, sinkCS chun
 		}
 	}
 
-	sinkTS.AddTableFilesToManifest(ctx, fileIDToNumChunks)
+	if err = sinkTS.AddTableFilesToManifest(ctx, fileIDToNumChunks); err != nil {
+		return err
+	}
 
 	// AddTableFilesToManifest can set the root chunk if there is a chunk
 	// journal which we downloaded in the clone. If that happened, the
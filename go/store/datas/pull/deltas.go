@@ -0,0 +1,221 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/utils/delta"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+// deltaBases tracks the base of each chunk being pulled that has one. A base is a chunk the sink has which is likely to
+// be similar to the chunk: the chunk in the same position of the tree of an older version of the data.
+//
+// Bases start from the pairs given to SetDeltaBases, such as a commit being pulled and an ancestor of it the sink has.
+// As each chunk with a base is pulled, its refs are paired with the refs of its base by aligning the two lists of
+// refs. The refs the two have in common are unchanged subtrees, and the refs between two common refs are paired by
+// their relative positions, so a chunk rewritten by a change is paired with the chunk it replaced.
+type deltaBases struct {
+	mu    sync.Mutex
+	bases map[hash.Hash]hash.Hash
+}
+
+// getMany returns the bases of the chunks in |hashes| that have one
+func (db *deltaBases) getMany(hashes hash.HashSet) map[hash.Hash]hash.Hash {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	found := make(map[hash.Hash]hash.Hash)
+	for h := range hashes {
+		if base, ok := db.bases[h]; ok {
+			found[h] = base
+		}
+	}
+	return found
+}
+
+// add adds the bases in |pairs| of chunks which don't already have one
+func (db *deltaBases) add(pairs map[hash.Hash]hash.Hash) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for h, base := range pairs {
+		if _, ok := db.bases[h]; !ok {
+			db.bases[h] = base
+		}
+	}
+}
+
+// take returns the base of |h| and removes it, once |h| has been pulled
+func (db *deltaBases) take(h hash.Hash) (hash.Hash, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	base, ok := db.bases[h]
+	delete(db.bases, h)
+	return base, ok
+}
+
+// alignRefs pairs each ref in |refs| with the ref in the same position of |baseRefs|. Refs found in both lists anchor
+// the alignment, and the refs between two anchors are paired by their relative positions between them. Only refs in
+// |novel| are paired.
+func alignRefs(refs, baseRefs []hash.Hash, novel hash.HashSet) map[hash.Hash]hash.Hash {
+	basePos := make(map[hash.Hash]int, len(baseRefs))
+	for i := len(baseRefs) - 1; i >= 0; i-- {
+		basePos[baseRefs[i]] = i
+	}
+
+	pairs := make(map[hash.Hash]hash.Hash)
+	pairGap := func(gap, baseGap []hash.Hash) {
+		if len(baseGap) == 0 {
+			return
+		}
+		for i := range gap {
+			b := baseGap[i*len(baseGap)/len(gap)]
+			if novel.Has(gap[i]) && gap[i] != b {
+				pairs[gap[i]] = b
+			}
+		}
+	}
+
+	gapStart, baseGapStart := 0, 0
+	for i, r := range refs {
+		if pos, ok := basePos[r]; ok && pos >= baseGapStart {
+			pairGap(refs[gapStart:i], baseRefs[baseGapStart:pos])
+			gapStart, baseGapStart = i+1, pos+1
+		}
+	}
+	pairGap(refs[gapStart:], baseRefs[baseGapStart:])
+	return pairs
+}
+
+// SetDeltaBases sets the chunks of the sink which the chunks being pulled are similar to, as a map from the address of
+// a chunk being pulled, such as a commit, to the address of a chunk the sink has, such as an older commit of the same
+// branch. When the source can send chunks as deltas, or the sink can receive table files as delta packs, each chunk
+// pulled is sent as a delta against the chunk in the same position of the tree of its base, where that's smaller. A
+// change to a small part of a large tree then sends little more than the change.
+func (p *Puller) SetDeltaBases(bases map[hash.Hash]hash.Hash) {
+	p.deltaSrc, p.deltaSink = nil, nil
+	if dcs, ok := p.srcChunkStore.(chunks.DeltaChunkSource); ok {
+		p.deltaSrc = dcs
+	} else if dts, ok := p.sinkDBCS.(chunks.DeltaTableFileStore); ok {
+		p.deltaSink = dts
+	}
+	if (p.deltaSrc == nil && p.deltaSink == nil) || len(bases) == 0 {
+		p.bases = nil
+		return
+	}
+
+	p.bases = &deltaBases{bases: make(map[hash.Hash]hash.Hash, len(bases))}
+	p.bases.add(bases)
+}
+
+// getBase returns the data of |base|, which is read from whichever of the source and sink is local. It's nil if the
+// chunk isn't found.
+func (p *Puller) getBase(ctx context.Context, base hash.Hash) ([]byte, error) {
+	var cs chunks.ChunkStore = p.srcChunkStore
+	if p.deltaSrc != nil {
+		cs = p.sinkDBCS
+	}
+	c, err := cs.Get(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	if c.IsEmpty() {
+		return nil, nil
+	}
+	return c.Data(), nil
+}
+
+// useBase pairs the novel refs of the pulled chunk |chnk|, in the order |refs|, with the refs of its base, if it has
+// one. If the sink receives delta packs, it returns the base and the delta of |chnk| against it, when the delta is
+// smaller than |cmpChnk|.
+func (p *Puller) useBase(ctx context.Context, chnk chunks.Chunk, cmpChnk nbs.CompressedChunk, refs []hash.Hash, novel hash.HashSet) (hash.Hash, []byte, error) {
+	base, ok := p.bases.take(chnk.Hash())
+	if !ok {
+		return hash.Hash{}, nil, nil
+	}
+	data, err := p.getBase(ctx, base)
+	if err != nil || data == nil {
+		return hash.Hash{}, nil, err
+	}
+
+	if novel.Size() > 0 {
+		var baseRefs []hash.Hash
+		err = p.waf(chunks.NewChunkWithHash(base, data), func(r hash.Hash, _ bool) error {
+			baseRefs = append(baseRefs, r)
+			return nil
+		})
+		if err != nil {
+			return hash.Hash{}, nil, err
+		}
+		p.bases.add(alignRefs(refs, baseRefs, novel))
+	}
+
+	if p.deltaSink == nil {
+		return hash.Hash{}, nil, nil
+	}
+	d := delta.Encode(data, chnk.Data())
+	if len(d) >= len(cmpChnk.FullCompressedChunk) {
+		return hash.Hash{}, nil, nil
+	}
+	// the receiver rebuilds the compressed chunk from its data, which must give the same table file
+	if !bytes.Equal(nbs.ChunkToCompressedChunk(chnk).FullCompressedChunk, cmpChnk.FullCompressedChunk) {
+		return hash.Hash{}, nil, nil
+	}
+	return base, d, nil
+}
+
+// getDeltas gets the chunks of |remaining| that have bases as deltas from the source, and removes the chunks received
+// from |remaining|. Deltas which don't rebuild their chunks are ignored, so those chunks are fetched in full.
+func (p *Puller) getDeltas(ctx context.Context, remaining hash.HashSet, mu *sync.Mutex, found func(context.Context, nbs.CompressedChunk)) error {
+	mu.Lock()
+	bases := p.bases.getMany(remaining)
+	mu.Unlock()
+	if len(bases) == 0 {
+		return nil
+	}
+
+	var baseErr error
+	err := p.deltaSrc.GetManyDeltas(ctx, bases, func(ctx context.Context, dc chunks.DeltaChunk) {
+		if bases[dc.H] != dc.Base {
+			return
+		}
+		data, err := p.getBase(ctx, dc.Base)
+		if err != nil {
+			mu.Lock()
+			baseErr = err
+			mu.Unlock()
+			return
+		} else if data == nil {
+			return
+		}
+		data, err = delta.Decode(data, dc.Delta)
+		if err != nil || hash.Of(data) != dc.H {
+			return
+		}
+
+		mu.Lock()
+		remaining.Remove(dc.H)
+		mu.Unlock()
+		found(ctx, nbs.ChunkToCompressedChunk(chunks.NewChunkWithHash(dc.H, data)))
+	})
+	if err != nil {
+		return err
+	}
+	return baseErr
+}
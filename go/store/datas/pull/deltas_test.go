@@ -0,0 +1,96 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestAlignRefs(t *testing.T) {
+	h := func(s string) hash.Hash {
+		return hash.Of([]byte(s))
+	}
+	a, b, c, d, e := h("a"), h("b"), h("c"), h("d"), h("e")
+	x, y, z := h("x"), h("y"), h("z")
+
+	tests := []struct {
+		name     string
+		refs     []hash.Hash
+		baseRefs []hash.Hash
+		novel    []hash.Hash
+		expected map[hash.Hash]hash.Hash
+	}{
+		{
+			name:     "changed ref",
+			refs:     []hash.Hash{a, x, c},
+			baseRefs: []hash.Hash{a, b, c},
+			novel:    []hash.Hash{x},
+			expected: map[hash.Hash]hash.Hash{x: b},
+		},
+		{
+			name:     "inserted ref",
+			refs:     []hash.Hash{a, x, b, c},
+			baseRefs: []hash.Hash{a, b, c},
+			novel:    []hash.Hash{x},
+			expected: map[hash.Hash]hash.Hash{},
+		},
+		{
+			name:     "split ref",
+			refs:     []hash.Hash{a, x, y, d},
+			baseRefs: []hash.Hash{a, b, d},
+			novel:    []hash.Hash{x, y},
+			expected: map[hash.Hash]hash.Hash{x: b, y: b},
+		},
+		{
+			name:     "merged refs",
+			refs:     []hash.Hash{a, x, e},
+			baseRefs: []hash.Hash{a, b, c, d, e},
+			novel:    []hash.Hash{x},
+			expected: map[hash.Hash]hash.Hash{x: b},
+		},
+		{
+			name:     "changed first and last refs",
+			refs:     []hash.Hash{x, b, c, y},
+			baseRefs: []hash.Hash{a, b, c, d},
+			novel:    []hash.Hash{x, y},
+			expected: map[hash.Hash]hash.Hash{x: a, y: d},
+		},
+		{
+			name:     "only novel refs are paired",
+			refs:     []hash.Hash{x, y, z},
+			baseRefs: []hash.Hash{a, b, e},
+			novel:    []hash.Hash{x, z},
+			expected: map[hash.Hash]hash.Hash{x: a, z: e},
+		},
+		{
+			name:     "no base refs",
+			refs:     []hash.Hash{x, y},
+			baseRefs: nil,
+			novel:    []hash.Hash{x, y},
+			expected: map[hash.Hash]hash.Hash{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := alignRefs(test.refs, test.baseRefs, hash.NewHashSet(test.novel...))
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -43,6 +43,19 @@ var ErrDBUpToDate = errors.New("the database does not need to be pulled as it's
 // the event that the source ChunkStore does not implement `NBSCompressedChunkStore`.
 var ErrIncompatibleSourceChunkStore = errors.New("the chunk store of the source database does not implement NBSCompressedChunkStore.")
 
+// ChunkVerificationError is returned by Puller.Pull when it verifies chunks and the data of a chunk received from the
+// source doesn't hash to the chunk's address.
+type ChunkVerificationError struct {
+	// Addr is the address the chunk was requested by
+	Addr hash.Hash
+	// Actual is the hash of the data received
+	Actual hash.Hash
+}
+
+func (e *ChunkVerificationError) Error() string {
+	return fmt.Sprintf("chunk %s failed verification; the data received for it hashes to %s", e.Addr.String(), e.Actual.String())
+}
+
 const (
 	maxChunkWorkers       = 2
 	outstandingTableFiles = 2
@@ -51,12 +64,18 @@ const (
 // FilledWriters store CmpChunkTableWriter that have been filled and are ready to be flushed.  In the future will likely
 // add the md5 of the data to this structure to be used to verify table upload calls.
 type FilledWriters struct {
-	wr *nbs.CmpChunkTableWriter
+	wr    *nbs.CmpChunkTableWriter
+	addrs []hash.Hash
+	// pack is the delta pack of the table file, when the sink receives them
+	pack *nbs.DeltaPackWriter
 }
 
 // CmpChnkAndRefs holds a CompressedChunk and all of it's references
 type CmpChnkAndRefs struct {
 	cmpChnk nbs.CompressedChunk
+	// delta is the delta of the chunk against |base|, when it's sent as one in a delta pack
+	base  hash.Hash
+	delta []byte
 }
 
 type WalkAddrs func(chunks.Chunk, func(hash.Hash, bool) error) error
@@ -70,16 +89,41 @@ type Puller struct {
 	hashes        hash.HashSet
 
 	wr            *nbs.CmpChunkTableWriter
+	wrAddrs       []hash.Hash
+	pack          *nbs.DeltaPackWriter
 	tablefileSema *semaphore.Weighted
 	tempDir       string
-	chunksPerTF   int
+	// enc encrypts the temp files of the table files and delta packs, if it isn't nil
+	enc         *nbs.Encryption
+	chunksPerTF int
 
 	pushLog *log.Logger
 
+	// journal records the table files uploaded to the sink, if the sink can report on them when a pull is retried
+	journal *uploadJournal
+	// resumed are the chunks in the table files uploaded by a previous attempt at this pull, which aren't uploaded again
+	resumed hash.HashSet
+	// verify is true if the data of each chunk received from the source is checked against its address
+	verify bool
+	// cache is the shared cache consulted for chunks before the source, and which table files are written through
+	cache *nbs.SharedCache
+	// bases are the chunks of the sink that chunks are sent as deltas against, when |deltaSrc| or |deltaSink| is set
+	bases     *deltaBases
+	deltaSrc  chunks.DeltaChunkSource
+	deltaSink chunks.DeltaTableFileStore
+
 	statsCh chan Stats
 	stats   *stats
 }
 
+// storeEncryption returns the Encryption of |cs|, or nil if |cs| doesn't encrypt its data at rest.
+func storeEncryption(cs chunks.ChunkStore) *nbs.Encryption {
+	if es, ok := cs.(nbs.EncryptedStore); ok {
+		return es.Encryption()
+	}
+	return nil
+}
+
 // NewPuller creates a new Puller instance to do the syncing.  If a nil puller is returned without error that means
 // that there is nothing to pull and the sinkDB is already up to date.
 func NewPuller(
@@ -119,7 +163,12 @@ func NewPuller(
 		return nil, ErrIncompatibleSourceChunkStore
 	}
 
-	wr, err := nbs.NewCmpChunkTableWriter(tempDir)
+	// the table files are encrypted while they're in temp files if either store is encrypted
+	enc := storeEncryption(sinkCS)
+	if enc == nil {
+		enc = storeEncryption(srcCS)
+	}
+	wr, err := nbs.NewEncryptedCmpChunkTableWriter(tempDir, enc)
 
 	if err != nil {
 		return nil, err
@@ -142,6 +191,7 @@ func NewPuller(
 		hashes:        hash.NewHashSet(hashes...),
 		tablefileSema: semaphore.NewWeighted(outstandingTableFiles),
 		tempDir:       tempDir,
+		enc:           enc,
 		wr:            wr,
 		chunksPerTF:   chunksPerTF,
 		pushLog:       pushLogger,
@@ -153,9 +203,29 @@ func NewPuller(
 		lcs.SetLogger(p)
 	}
 
+	if uts, ok := sinkCS.(chunks.UploadedTableFileStore); ok {
+		p.journal, err = openUploadJournal(ctx, tempDir, hashes, uts)
+		if err != nil {
+			return nil, err
+		}
+		p.resumed = p.journal.chunks.Copy()
+	}
+
 	return p, nil
 }
 
+// SetVerify sets whether the data of each chunk received from the source is hashed and checked against the chunk's
+// address. The pull fails with a *ChunkVerificationError on the first chunk which doesn't match.
+func (p *Puller) SetVerify(verify bool) {
+	p.verify = verify
+}
+
+// SetSharedCache sets a shared cache for the pull. Chunks in |cache| are read from it rather than the source, and the
+// table files written to the sink are added to it and linked from it.
+func (p *Puller) SetSharedCache(cache *nbs.SharedCache) {
+	p.cache = cache
+}
+
 func (p *Puller) Logf(fmt string, args ...interface{}) {
 	if p.pushLog != nil {
 		p.pushLog.Printf(fmt, args...)
@@ -174,6 +244,8 @@ type tempTblFile struct {
 	chunksLen   uint64
 	contentLen  uint64
 	contentHash []byte
+	// pack is the delta pack of the table file, if it's smaller than the table file
+	pack *nbs.DeltaPackWriter
 }
 
 type countingReader struct {
@@ -274,6 +346,8 @@ type stats struct {
 	fetchedSourceBytes       uint64
 	fetchedSourceBytesPerSec uint64
 
+	resumedChunks uint64
+
 	sendBytesPerSecF          float64
 	fetchedSourceBytesPerSecF float64
 }
@@ -287,6 +361,9 @@ type Stats struct {
 	FetchedSourceChunks      uint64
 	FetchedSourceBytes       uint64
 	FetchedSourceBytesPerSec float64
+
+	// ResumedChunks is the number of chunks that weren't sent because a previous attempt at the pull had sent them
+	ResumedChunks uint64
 }
 
 func (s *stats) read() Stats {
@@ -298,6 +375,7 @@ func (s *stats) read() Stats {
 	ret.FetchedSourceChunks = atomic.LoadUint64(&s.fetchedSourceChunks)
 	ret.FetchedSourceBytes = atomic.LoadUint64(&s.fetchedSourceBytes)
 	ret.FetchedSourceBytesPerSec = math.Float64frombits(atomic.LoadUint64(&s.fetchedSourceBytesPerSec))
+	ret.ResumedChunks = atomic.LoadUint64(&s.resumedChunks)
 	return ret
 }
 
@@ -305,13 +383,17 @@ func (p *Puller) uploadTempTableFile(ctx context.Context, tmpTblFile tempTblFile
 	fileSize := tmpTblFile.contentLen
 	defer func() {
 		_ = tmpTblFile.read.Remove()
+		if tmpTblFile.pack != nil {
+			_ = tmpTblFile.pack.Remove()
+		}
 	}()
 
 	// By tracking the number of bytes uploaded here,
 	// we can add bytes on to our bufferedSendBytes when
 	// we have to retry a table file write.
 	var localUploaded uint64
-	return p.sinkDBCS.(chunks.TableFileStore).WriteTableFile(ctx, tmpTblFile.id, tmpTblFile.numChunks, tmpTblFile.contentHash, func() (io.ReadCloser, uint64, error) {
+	sinkTS := p.sinkDBCS.(chunks.TableFileStore)
+	getRd := func() (io.ReadCloser, uint64, error) {
 		rc, err := tmpTblFile.read.Reader()
 		if err != nil {
 			return nil, 0, err
@@ -330,11 +412,37 @@ func (p *Puller) uploadTempTableFile(ctx context.Context, tmpTblFile tempTblFile
 		fWithStats := countingReader{countingReader{rc, &localUploaded}, &p.stats.finishedSendBytes}
 
 		return fWithStats, uint64(fileSize), nil
-	})
+	}
+	if p.cache != nil {
+		return p.cache.WriteTableFile(ctx, sinkTS, tmpTblFile.id, tmpTblFile.numChunks, tmpTblFile.contentHash, getRd)
+	}
+	if tmpTblFile.pack != nil {
+		getPackRd := func() (io.ReadCloser, uint64, error) {
+			rc, err := tmpTblFile.pack.Reader()
+			if err != nil {
+				return nil, 0, err
+			}
+			return countingReader{rc, &localUploaded}, tmpTblFile.pack.ContentLength(), nil
+		}
+		return p.deltaSink.WriteTableFileWithDeltaPack(ctx, tmpTblFile.id, tmpTblFile.numChunks, tmpTblFile.contentHash, getRd, getPackRd)
+	}
+	return sinkTS.WriteTableFile(ctx, tmpTblFile.id, tmpTblFile.numChunks, tmpTblFile.contentHash, getRd)
 }
 
+// processCompletedTables uploads the table files written by the puller as they're completed, several at a time, and
+// adds them to the sink's manifest once they have all been uploaded. The number of table files uploaded at once
+// adapts to the throughput of the uploads.
 func (p *Puller) processCompletedTables(ctx context.Context, completedTables <-chan FilledWriters) error {
+	var mu sync.Mutex
 	fileIdToNumChunks := make(map[string]int)
+	if p.journal != nil {
+		for fileId, numChunks := range p.journal.files {
+			fileIdToNumChunks[fileId] = numChunks
+		}
+	}
+
+	limiter := newUploadLimiter(minUploadStreams, maxUploadStreams)
+	eg, egCtx := errgroup.WithContext(ctx)
 
 LOOP:
 	for {
@@ -362,18 +470,62 @@ LOOP:
 				contentLen:  tblFile.wr.ContentLength(),
 				contentHash: tblFile.wr.GetMD5(),
 			}
-			err = p.uploadTempTableFile(ctx, ttf)
-			if err != nil {
-				return err
+			if tblFile.pack != nil {
+				if err = tblFile.pack.Finish(); err != nil {
+					return err
+				}
+				if tblFile.pack.DeltaCount() > 0 && tblFile.pack.ContentLength() < ttf.contentLen {
+					ttf.pack = tblFile.pack
+				} else {
+					_ = tblFile.pack.Remove()
+				}
 			}
 
-			fileIdToNumChunks[id] = ttf.numChunks
-		case <-ctx.Done():
-			return ctx.Err()
+			if err = limiter.acquire(egCtx); err != nil {
+				_ = ttf.read.Remove()
+				break LOOP
+			}
+			addrs := tblFile.addrs
+			eg.Go(func() error {
+				err := p.uploadTempTableFile(egCtx, ttf)
+				if err != nil {
+					limiter.abort()
+					return err
+				}
+				limiter.release(ttf.contentLen, time.Now())
+
+				mu.Lock()
+				defer mu.Unlock()
+				if p.journal != nil {
+					if err = p.journal.record(ttf.id, addrs, ttf.contentLen); err != nil {
+						return err
+					}
+				}
+				fileIdToNumChunks[ttf.id] = ttf.numChunks
+				return nil
+			})
+		case <-egCtx.Done():
+			break LOOP
 		}
 	}
 
-	return p.sinkDBCS.(chunks.TableFileStore).AddTableFilesToManifest(ctx, fileIdToNumChunks)
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := p.sinkDBCS.(chunks.TableFileStore).AddTableFilesToManifest(ctx, fileIdToNumChunks)
+	if err != nil {
+		return err
+	}
+
+	if p.journal != nil {
+		err = p.journal.Remove()
+		p.journal = nil
+	}
+	return err
 }
 
 // Pull executes the sync operation
@@ -433,7 +585,7 @@ func (p *Puller) Pull(ctx context.Context) error {
 
 		if p.wr != nil && p.wr.ChunkCount() > 0 {
 			select {
-			case completedTables <- FilledWriters{p.wr}:
+			case completedTables <- FilledWriters{wr: p.wr, addrs: p.wrAddrs, pack: p.pack}:
 			case <-ctx.Done():
 				return ctx.Err()
 			}
@@ -442,7 +594,13 @@ func (p *Puller) Pull(ctx context.Context) error {
 		return nil
 	})
 
-	return eg.Wait()
+	err := eg.Wait()
+	if err != nil && p.journal != nil {
+		err = p.journal.interrupted(err)
+		_ = p.journal.Close()
+		p.journal = nil
+	}
+	return err
 }
 
 // batchNovel returns a slice of |batch| size HashSets and partial |remainder| HashSet.
@@ -459,6 +617,54 @@ func batchNovel(absent hash.HashSet, batch int) (remainder hash.HashSet, batches
 	return
 }
 
+// getManyCompressed gets the chunks in |batch| from the shared cache, if there is one, then as deltas from the source
+// for those that have bases, when the source sends deltas, and then those that remain from the source
+func (p *Puller) getManyCompressed(ctx context.Context, batch hash.HashSet, found func(context.Context, nbs.CompressedChunk)) error {
+	if p.cache == nil && p.deltaSrc == nil {
+		return p.srcChunkStore.GetManyCompressed(ctx, batch, found)
+	}
+
+	var mu sync.Mutex
+	remaining := batch.Copy()
+	if p.cache != nil {
+		err := p.cache.GetManyCompressed(ctx, batch, func(ctx context.Context, c nbs.CompressedChunk) {
+			mu.Lock()
+			remaining.Remove(c.H)
+			mu.Unlock()
+			found(ctx, c)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if p.deltaSrc != nil && p.bases != nil {
+		if err := p.getDeltas(ctx, remaining, &mu, found); err != nil {
+			return err
+		}
+	}
+	if remaining.Size() == 0 {
+		return nil
+	}
+	return p.srcChunkStore.GetManyCompressed(ctx, remaining, found)
+}
+
+// addToPack adds a chunk written to the current table file to its delta pack, when the sink receives delta packs
+func (p *Puller) addToPack(cmpAndRef CmpChnkAndRefs) (err error) {
+	if p.deltaSink == nil || p.bases == nil {
+		return nil
+	}
+	if p.pack == nil {
+		p.pack, err = nbs.NewEncryptedDeltaPackWriter(p.tempDir, p.enc)
+		if err != nil {
+			return err
+		}
+	}
+	if cmpAndRef.delta != nil {
+		return p.pack.AddDelta(cmpAndRef.cmpChnk.H, cmpAndRef.base, cmpAndRef.delta)
+	}
+	return p.pack.AddCmpChunk(cmpAndRef.cmpChnk)
+}
+
 func (p *Puller) getCmp(ctx context.Context, batch, absent, visited hash.HashSet, completedTables chan FilledWriters) error {
 	found := make(chan nbs.CompressedChunk, 4096)
 	processed := make(chan CmpChnkAndRefs, 4096)
@@ -466,7 +672,7 @@ func (p *Puller) getCmp(ctx context.Context, batch, absent, visited hash.HashSet
 	atomic.AddUint64(&p.stats.totalSourceChunks, uint64(len(batch)))
 	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		err := p.srcChunkStore.GetManyCompressed(ctx, batch, func(ctx context.Context, c nbs.CompressedChunk) {
+		err := p.getManyCompressed(ctx, batch, func(ctx context.Context, c nbs.CompressedChunk) {
 			atomic.AddUint64(&p.stats.fetchedSourceBytes, uint64(len(c.FullCompressedChunk)))
 			atomic.AddUint64(&p.stats.fetchedSourceChunks, uint64(1))
 			select {
@@ -494,19 +700,40 @@ func (p *Puller) getCmp(ctx context.Context, batch, absent, visited hash.HashSet
 				if err != nil {
 					return err
 				}
+				if p.verify {
+					if actual := hash.Of(chnk.Data()); actual != cmpChnk.H {
+						return &ChunkVerificationError{Addr: cmpChnk.H, Actual: actual}
+					}
+				}
+				var refs []hash.Hash
+				novel := make(hash.HashSet)
 				err = p.waf(chnk, func(h hash.Hash, _ bool) error {
 					if !visited.Has(h) {
 						// first sight of |h|
 						visited.Insert(h)
 						absent.Insert(h)
+						if p.bases != nil {
+							novel.Insert(h)
+						}
+					}
+					if p.bases != nil {
+						refs = append(refs, h)
 					}
 					return nil
 				})
 				if err != nil {
 					return err
 				}
+				var base hash.Hash
+				var d []byte
+				if p.bases != nil {
+					base, d, err = p.useBase(ctx, chnk, cmpChnk, refs, novel)
+					if err != nil {
+						return err
+					}
+				}
 				select {
-				case processed <- CmpChnkAndRefs{cmpChnk: cmpChnk}:
+				case processed <- CmpChnkAndRefs{cmpChnk: cmpChnk, base: base, delta: d}:
 				case <-ctx.Done():
 					return ctx.Err()
 				}
@@ -530,25 +757,39 @@ func (p *Puller) getCmp(ctx context.Context, batch, absent, visited hash.HashSet
 				}
 				seen++
 
+				if p.resumed.Has(cmpAndRef.cmpChnk.H) {
+					// uploaded by a previous attempt at this pull
+					atomic.AddUint64(&p.stats.resumedChunks, 1)
+					continue
+				}
+
 				err := p.wr.AddCmpChunk(cmpAndRef.cmpChnk)
 				if err != nil {
 					return err
 				}
+				if err = p.addToPack(cmpAndRef); err != nil {
+					return err
+				}
+				if p.journal != nil {
+					p.wrAddrs = append(p.wrAddrs, cmpAndRef.cmpChnk.H)
+				}
 
 				atomic.AddUint64(&p.stats.bufferedSendBytes, uint64(len(cmpAndRef.cmpChnk.FullCompressedChunk)))
 
 				if p.wr.ChunkCount() >= p.chunksPerTF {
 					select {
-					case completedTables <- FilledWriters{p.wr}:
+					case completedTables <- FilledWriters{wr: p.wr, addrs: p.wrAddrs, pack: p.pack}:
 					case <-ctx.Done():
 						return ctx.Err()
 					}
 					p.wr = nil
+					p.wrAddrs = nil
+					p.pack = nil
 
 					if err := p.tablefileSema.Acquire(ctx, 1); err != nil {
 						return err
 					}
-					p.wr, err = nbs.NewCmpChunkTableWriter(p.tempDir)
+					p.wr, err = nbs.NewEncryptedCmpChunkTableWriter(p.tempDir, p.enc)
 					if err != nil {
 						return err
 					}
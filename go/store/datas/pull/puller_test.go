@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -27,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/d"
 	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -71,6 +73,26 @@ func TestChunkJournalPuller(t *testing.T) {
 	})
 }
 
+func TestEncryptedChunkJournalPuller(t *testing.T) {
+	enc, err := nbs.NewEncryption(make([]byte, 32))
+	require.NoError(t, err)
+	testPuller(t, func(ctx context.Context) (types.ValueReadWriter, datas.Database) {
+		dir := filepath.Join(os.TempDir(), uuid.New().String())
+		err := os.MkdirAll(dir, os.ModePerm)
+		require.NoError(t, err)
+
+		nbf := types.Format_Default.VersionString()
+		q := nbs.NewUnlimitedMemQuotaProvider()
+
+		st, err := nbs.NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, q, enc)
+		require.NoError(t, err)
+
+		ns := tree.NewNodeStore(st)
+		vs := types.NewValueStore(st)
+		return vs, datas.NewTypesDatabase(vs, ns)
+	})
+}
+
 func addTableValues(ctx context.Context, vrw types.ValueReadWriter, m types.Map, tableName string, alternatingKeyVals ...types.Value) (types.Map, error) {
 	val, ok, err := m.MaybeGet(ctx, types.String(tableName))
 
@@ -357,6 +379,185 @@ func testPuller(t *testing.T, makeDB datasFactory) {
 	}
 }
 
+// failingSink is a sink which fails writing table files after |failAfter| have been written
+type failingSink struct {
+	*nbs.NomsBlockStore
+	failAfter int
+	written   int
+}
+
+var errTableFileWrite = errors.New("table file write failed")
+
+func (fs *failingSink) WriteTableFile(ctx context.Context, fileId string, numChunks int, contentHash []byte, getRd func() (io.ReadCloser, uint64, error)) error {
+	if fs.failAfter >= 0 && fs.written >= fs.failAfter {
+		return errTableFileWrite
+	}
+	fs.written++
+	return fs.NomsBlockStore.WriteTableFile(ctx, fileId, numChunks, contentHash, getRd)
+}
+
+func TestPullerResumesInterruptedPull(t *testing.T) {
+	ctx := context.Background()
+	nbf := types.Format_Default.VersionString()
+	q := nbs.NewUnlimitedMemQuotaProvider()
+
+	srcDir := t.TempDir()
+	srcCS, err := nbs.NewLocalStore(ctx, nbf, srcDir, clienttest.DefaultMemTableSize, q)
+	require.NoError(t, err)
+	vs := types.NewValueStore(srcCS)
+	db := datas.NewTypesDatabase(vs, tree.NewNodeStore(srcCS))
+	defer db.Close()
+
+	me, err := types.NewMap(ctx, vs)
+	require.NoError(t, err)
+	ed := me.Edit()
+	for i := 0; i < 16*1024; i++ {
+		ed.Set(types.Int(i), types.String(uuid.New().String()))
+	}
+	m, err := ed.Map(ctx)
+	require.NoError(t, err)
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	ds, err = db.Commit(ctx, ds, m, datas.CommitOptions{})
+	require.NoError(t, err)
+	rootAddr, ok := ds.MaybeHeadAddr()
+	require.True(t, ok)
+
+	sinkDir := t.TempDir()
+	sinkCS, err := nbs.NewLocalStore(ctx, nbf, sinkDir, clienttest.DefaultMemTableSize, q)
+	require.NoError(t, err)
+	sink := &failingSink{NomsBlockStore: sinkCS, failAfter: 2}
+
+	tmpDir := t.TempDir()
+	waf, err := types.WalkAddrsForChunkStore(srcCS)
+	require.NoError(t, err)
+
+	plr, err := NewPuller(ctx, tmpDir, 16, srcCS, sink, waf, []hash.Hash{rootAddr}, nil)
+	require.NoError(t, err)
+	err = plr.Pull(ctx)
+	require.ErrorIs(t, err, errTableFileWrite)
+	var interrupted *InterruptedPullError
+	require.ErrorAs(t, err, &interrupted)
+	assert.Equal(t, 2, interrupted.TableFiles)
+	assert.Equal(t, uint64(32), interrupted.Chunks)
+	assert.NotZero(t, interrupted.Bytes)
+
+	has, err := sinkCS.Has(ctx, rootAddr)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	sink.failAfter = -1
+	plr, err = NewPuller(ctx, tmpDir, 16, srcCS, sink, waf, []hash.Hash{rootAddr}, nil)
+	require.NoError(t, err)
+	require.NoError(t, plr.Pull(ctx))
+	assert.Equal(t, uint64(32), plr.stats.read().ResumedChunks)
+
+	_, err = os.Stat(uploadJournalPath(tmpDir, []hash.Hash{rootAddr}))
+	assert.True(t, os.IsNotExist(err))
+
+	sinkvs := types.NewValueStore(sinkCS)
+	eq, err := pullerAddrEquality(ctx, rootAddr, rootAddr, vs, sinkvs)
+	require.NoError(t, err)
+	assert.True(t, eq)
+
+	// every chunk reachable from the root made it to the sink
+	seen := hash.NewHashSet(rootAddr)
+	next := hash.NewHashSet(rootAddr)
+	for next.Size() > 0 {
+		absent, err := sinkCS.HasMany(ctx, next)
+		require.NoError(t, err)
+		require.Equal(t, 0, absent.Size())
+
+		found := make(hash.HashSet)
+		var mu sync.Mutex
+		err = srcCS.GetMany(ctx, next, func(ctx context.Context, c *chunks.Chunk) {
+			mu.Lock()
+			defer mu.Unlock()
+			require.NoError(t, waf(*c, func(h hash.Hash, _ bool) error {
+				if !seen.Has(h) {
+					seen.Insert(h)
+					found.Insert(h)
+				}
+				return nil
+			}))
+		})
+		require.NoError(t, err)
+		next = found
+	}
+}
+
+// corruptingSource is a source which returns |data| as the chunk with address |corrupt|
+type corruptingSource struct {
+	*nbs.NomsBlockStore
+	corrupt hash.Hash
+	data    chunks.Chunk
+}
+
+func (cs *corruptingSource) GetManyCompressed(ctx context.Context, hashes hash.HashSet, found func(context.Context, nbs.CompressedChunk)) error {
+	return cs.NomsBlockStore.GetManyCompressed(ctx, hashes, func(ctx context.Context, c nbs.CompressedChunk) {
+		if c.H == cs.corrupt {
+			c = nbs.ChunkToCompressedChunk(cs.data)
+			c.H = cs.corrupt
+		}
+		found(ctx, c)
+	})
+}
+
+func TestPullerVerifiesChunks(t *testing.T) {
+	ctx := context.Background()
+	nbf := types.Format_Default.VersionString()
+	q := nbs.NewUnlimitedMemQuotaProvider()
+
+	srcCS, err := nbs.NewLocalStore(ctx, nbf, t.TempDir(), clienttest.DefaultMemTableSize, q)
+	require.NoError(t, err)
+	vs := types.NewValueStore(srcCS)
+	db := datas.NewTypesDatabase(vs, tree.NewNodeStore(srcCS))
+	defer db.Close()
+
+	m, err := types.NewMap(ctx, vs, types.Int(1), types.String("one"))
+	require.NoError(t, err)
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	ds, err = db.Commit(ctx, ds, m, datas.CommitOptions{})
+	require.NoError(t, err)
+	rootAddr, ok := ds.MaybeHeadAddr()
+	require.True(t, ok)
+
+	waf, err := types.WalkAddrsForChunkStore(srcCS)
+	require.NoError(t, err)
+	mapAddr, err := m.Hash(vs.Format())
+	require.NoError(t, err)
+	corrupt, err := types.EncodeValue(types.String("corrupt"), vs.Format())
+	require.NoError(t, err)
+	src := &corruptingSource{NomsBlockStore: srcCS, corrupt: mapAddr, data: corrupt}
+
+	newSink := func() chunks.ChunkStore {
+		sinkCS, err := nbs.NewLocalStore(ctx, nbf, t.TempDir(), clienttest.DefaultMemTableSize, q)
+		require.NoError(t, err)
+		return sinkCS
+	}
+
+	// without verification, the corrupt chunk is accepted
+	plr, err := NewPuller(ctx, t.TempDir(), 16, src, newSink(), waf, []hash.Hash{rootAddr}, nil)
+	require.NoError(t, err)
+	require.NoError(t, plr.Pull(ctx))
+
+	plr, err = NewPuller(ctx, t.TempDir(), 16, src, newSink(), waf, []hash.Hash{rootAddr}, nil)
+	require.NoError(t, err)
+	plr.SetVerify(true)
+	err = plr.Pull(ctx)
+	var verr *ChunkVerificationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, mapAddr, verr.Addr)
+	assert.Equal(t, corrupt.Hash(), verr.Actual)
+
+	// verification passes when the source is intact
+	plr, err = NewPuller(ctx, t.TempDir(), 16, srcCS, newSink(), waf, []hash.Hash{rootAddr}, nil)
+	require.NoError(t, err)
+	plr.SetVerify(true)
+	require.NoError(t, plr.Pull(ctx))
+}
+
 func makeABigTable(ctx context.Context, vrw types.ValueReadWriter) (types.Map, error) {
 	m, err := types.NewMap(ctx, vrw)
 
@@ -0,0 +1,235 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	uploadJournalPrefix = "pull_uploads_"
+	// uploadRecordHeaderLen is the length of a table file id, size and chunk count in an upload journal
+	uploadRecordHeaderLen = hash.ByteLen + 8 + 4
+)
+
+// InterruptedPullError is returned by Puller.Pull when it fails after table files were uploaded to the sink. Those
+// table files aren't added to the sink's manifest, but the sink keeps them, and a retried pull of the same hashes adds
+// them instead of uploading their chunks again.
+type InterruptedPullError struct {
+	Err error
+	// TableFiles is the number of table files the sink received
+	TableFiles int
+	// Chunks is the number of chunks in those table files
+	Chunks uint64
+	// Bytes is the size of those table files
+	Bytes uint64
+}
+
+func (e *InterruptedPullError) Error() string {
+	return fmt.Sprintf("%s; %d table files (%d chunks, %d bytes) were received before the failure and will not be sent again when retried",
+		e.Err.Error(), e.TableFiles, e.Chunks, e.Bytes)
+}
+
+func (e *InterruptedPullError) Unwrap() error {
+	return e.Err
+}
+
+// uploadJournal records the table files a Puller has uploaded to its sink along with the addresses of their chunks.
+// Records are appended and synced as each upload completes, so that the journal survives the pull failing or the
+// process exiting. Each record is a table file id, its size, its chunk count, and that many chunk addresses.
+type uploadJournal struct {
+	path string
+	f    *os.File
+
+	// files are the table files recorded in the journal which the sink still has
+	files map[string]int
+	// chunks are the chunks in |files|
+	chunks hash.HashSet
+	// bytes is the total size of |files|
+	bytes uint64
+}
+
+type uploadRecord struct {
+	size  uint64
+	addrs []hash.Hash
+}
+
+// uploadJournalPath returns the path of the upload journal of a pull of |hashes| to a sink
+func uploadJournalPath(tempDir string, hashes []hash.Hash) string {
+	sorted := make(hash.HashSlice, len(hashes))
+	copy(sorted, hashes)
+	sort.Sort(sorted)
+
+	data := make([]byte, 0, len(sorted)*hash.ByteLen)
+	for _, h := range sorted {
+		data = append(data, h[:]...)
+	}
+	return filepath.Join(tempDir, uploadJournalPrefix+hash.Of(data).String())
+}
+
+// openUploadJournal opens the upload journal of a pull of |hashes| to |sink|. Table files recorded by a previous
+// attempt at the same pull which |sink| no longer has are dropped from the journal.
+func openUploadJournal(ctx context.Context, tempDir string, hashes []hash.Hash, sink chunks.UploadedTableFileStore) (*uploadJournal, error) {
+	path := uploadJournalPath(tempDir, hashes)
+	recorded, err := readUploadJournal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &uploadJournal{path: path, files: make(map[string]int), chunks: make(hash.HashSet)}
+	if len(recorded) > 0 {
+		fileIdToNumChunks := make(map[string]int, len(recorded))
+		for fileId, rec := range recorded {
+			fileIdToNumChunks[fileId] = len(rec.addrs)
+		}
+		uploaded, err := sink.HasUploadedTableFiles(ctx, fileIdToNumChunks)
+		if err != nil {
+			return nil, err
+		}
+		for fileId := range uploaded {
+			if rec, ok := recorded[fileId]; ok {
+				j.add(fileId, rec)
+			}
+		}
+	}
+
+	j.f, err = os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	for fileId := range j.files {
+		if err = j.write(fileId, recorded[fileId]); err != nil {
+			_ = j.f.Close()
+			return nil, err
+		}
+	}
+	if err = j.f.Sync(); err != nil {
+		_ = j.f.Close()
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// readUploadJournal reads the table files recorded in the journal at |path|. A partially written final record is
+// ignored.
+func readUploadJournal(path string) (map[string]uploadRecord, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	recorded := make(map[string]uploadRecord)
+	rd := bufio.NewReader(f)
+	for {
+		var hdr [uploadRecordHeaderLen]byte
+		if _, err = io.ReadFull(rd, hdr[:]); err != nil {
+			break
+		}
+		fileId := hash.New(hdr[:hash.ByteLen]).String()
+		size := binary.BigEndian.Uint64(hdr[hash.ByteLen:])
+		numChunks := binary.BigEndian.Uint32(hdr[hash.ByteLen+8:])
+
+		addrs := make([]hash.Hash, numChunks)
+		for i := range addrs {
+			if _, err = io.ReadFull(rd, addrs[i][:]); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+		recorded[fileId] = uploadRecord{size: size, addrs: addrs}
+	}
+
+	if err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return recorded, nil
+}
+
+// record adds an uploaded table file to the journal
+func (j *uploadJournal) record(fileId string, addrs []hash.Hash, size uint64) error {
+	rec := uploadRecord{size: size, addrs: addrs}
+	if err := j.write(fileId, rec); err != nil {
+		return err
+	}
+	if err := j.f.Sync(); err != nil {
+		return err
+	}
+	j.add(fileId, rec)
+	return nil
+}
+
+func (j *uploadJournal) add(fileId string, rec uploadRecord) {
+	j.files[fileId] = len(rec.addrs)
+	j.chunks.InsertAll(hash.NewHashSet(rec.addrs...))
+	j.bytes += rec.size
+}
+
+func (j *uploadJournal) write(fileId string, rec uploadRecord) error {
+	fileIdHash, ok := hash.MaybeParse(fileId)
+	if !ok {
+		return errors.New("invalid base32 encoded hash: " + fileId)
+	}
+
+	buf := make([]byte, 0, uploadRecordHeaderLen+len(rec.addrs)*hash.ByteLen)
+	buf = append(buf, fileIdHash[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, rec.size)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(rec.addrs)))
+	for _, h := range rec.addrs {
+		buf = append(buf, h[:]...)
+	}
+
+	_, err := j.f.Write(buf)
+	return err
+}
+
+// interrupted wraps |err| in an InterruptedPullError if any table files were uploaded to the sink
+func (j *uploadJournal) interrupted(err error) error {
+	if len(j.files) == 0 {
+		return err
+	}
+	return &InterruptedPullError{
+		Err:        err,
+		TableFiles: len(j.files),
+		Chunks:     uint64(j.chunks.Size()),
+		Bytes:      j.bytes,
+	}
+}
+
+func (j *uploadJournal) Close() error {
+	return j.f.Close()
+}
+
+// Remove closes and deletes the journal once its table files have been added to the sink's manifest
+func (j *uploadJournal) Remove() error {
+	_ = j.f.Close()
+	return os.Remove(j.path)
+}
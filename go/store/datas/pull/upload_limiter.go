@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	minUploadStreams = 2
+	maxUploadStreams = 16
+
+	// uploadRateGain is how much the upload rate must improve by for a limiter to add another stream, and
+	// uploadRateLoss is how much it must fall by for a limiter to take one away.
+	uploadRateGain = 1.1
+	uploadRateLoss = 0.9
+)
+
+// uploadLimiter limits the number of table files uploaded to a sink at once, adapting the limit to the throughput of
+// the uploads. Uploads are measured in windows of as many uploads as the current limit. The limit grows by one stream
+// after each window that uploads faster than the last, and shrinks by one after each window that uploads slower, so it
+// settles near the number of streams the connection to the sink can sustain.
+type uploadLimiter struct {
+	sema *semaphore.Weighted
+
+	mu  sync.Mutex
+	min int
+	max int
+	// limit is the number of uploads allowed at once
+	limit int
+	// withheld is the number of streams to take away as uploads finish
+	withheld int
+
+	// windowStart, windowBytes and windowUploads measure the uploads finished in the current window
+	windowStart   time.Time
+	windowBytes   uint64
+	windowUploads int
+	// lastRate is the upload rate of the last window, in bytes per second
+	lastRate float64
+}
+
+func newUploadLimiter(minStreams, maxStreams int) *uploadLimiter {
+	sema := semaphore.NewWeighted(int64(maxStreams))
+	sema.TryAcquire(int64(maxStreams - minStreams))
+	return &uploadLimiter{
+		sema:        sema,
+		min:         minStreams,
+		max:         maxStreams,
+		limit:       minStreams,
+		windowStart: time.Now(),
+	}
+}
+
+// acquire waits until another upload is allowed to start.
+func (l *uploadLimiter) acquire(ctx context.Context) error {
+	return l.sema.Acquire(ctx, 1)
+}
+
+// release records an upload of |size| bytes that finished at |now|, allowing another upload to start.
+func (l *uploadLimiter) release(size uint64, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.windowBytes += size
+	l.windowUploads++
+	if l.windowUploads >= l.limit {
+		l.adapt(now)
+	}
+
+	if l.withheld > 0 {
+		l.withheld--
+		return
+	}
+	l.sema.Release(1)
+}
+
+// abort allows another upload to start after one fails, without measuring it.
+func (l *uploadLimiter) abort() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.withheld > 0 {
+		l.withheld--
+		return
+	}
+	l.sema.Release(1)
+}
+
+// adapt ends the current window, adjusting the limit to its upload rate.
+func (l *uploadLimiter) adapt(now time.Time) {
+	elapsed := now.Sub(l.windowStart).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	rate := float64(l.windowBytes) / elapsed
+
+	if rate >= l.lastRate*uploadRateGain && l.limit < l.max {
+		l.limit++
+		if l.withheld > 0 {
+			l.withheld--
+		} else {
+			l.sema.Release(1)
+		}
+	} else if rate < l.lastRate*uploadRateLoss && l.limit > l.min {
+		l.limit--
+		l.withheld++
+	}
+
+	l.lastRate = rate
+	l.windowStart, l.windowBytes, l.windowUploads = now, 0, 0
+}
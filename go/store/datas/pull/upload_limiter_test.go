@@ -0,0 +1,65 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadLimiter(t *testing.T) {
+	ctx := context.Background()
+	l := newUploadLimiter(2, 4)
+	now := l.windowStart
+
+	// uploads runs a window of |l.limit| uploads of |size| bytes that take a second, returning the number of uploads
+	// that could be started at once
+	uploads := func(size uint64) int {
+		started := 0
+		for l.sema.TryAcquire(1) {
+			started++
+		}
+		now = now.Add(time.Second)
+		for i := 0; i < started; i++ {
+			l.release(size, now)
+		}
+		return started
+	}
+
+	assert.Equal(t, 2, uploads(100))
+	// faster windows add streams, up to the maximum
+	assert.Equal(t, 3, uploads(200))
+	assert.Equal(t, 4, uploads(400))
+	assert.Equal(t, 4, uploads(800))
+	assert.Equal(t, 4, l.limit)
+
+	// slower windows take them away, down to the minimum
+	assert.Equal(t, 4, uploads(100))
+	assert.Equal(t, 3, l.limit)
+	assert.Equal(t, 3, uploads(10))
+	assert.Equal(t, 2, uploads(1))
+	assert.Equal(t, 2, l.limit)
+	assert.Equal(t, 2, uploads(0))
+	assert.Equal(t, 2, l.limit)
+
+	// a failed upload frees its stream without being measured
+	require.NoError(t, l.acquire(ctx))
+	l.abort()
+	assert.Equal(t, 2, uploads(0))
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/gen/fb/serial"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrSigningNotSupported = errors.New("signed commits and tags are not supported by the __LD_1__ storage format")
+
+// CommitSigningPayload returns the bytes that are signed to sign a commit of the root value at |root| with
+// |parents| and |meta|. It covers every field of the commit except the signature itself and the fields derived from
+// the parents, in a text format similar to git's commit objects.
+func CommitSigningPayload(root hash.Hash, parents []hash.Hash, meta *CommitMeta) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "root %s\n", root.String())
+	for _, p := range parents {
+		fmt.Fprintf(&sb, "parent %s\n", p.String())
+	}
+	if meta.Metadata != "" {
+		fmt.Fprintf(&sb, "metadata %s\n", meta.Metadata)
+	}
+	writeSigningMeta(&sb, meta.Name, meta.Email, meta.Timestamp, meta.UserTimestamp, meta.Description)
+	return []byte(sb.String())
+}
+
+// TagSigningPayload returns the bytes that are signed to sign a tag of the commit at |commitAddr| with |meta|.
+func TagSigningPayload(commitAddr hash.Hash, meta *TagMeta) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "commit %s\n", commitAddr.String())
+	writeSigningMeta(&sb, meta.Name, meta.Email, meta.Timestamp, meta.UserTimestamp, meta.Description)
+	return []byte(sb.String())
+}
+
+func writeSigningMeta(sb *strings.Builder, name, email string, ts uint64, userTS int64, desc string) {
+	fmt.Fprintf(sb, "name %s\n", name)
+	fmt.Fprintf(sb, "email %s\n", email)
+	fmt.Fprintf(sb, "timestamp %d\n", ts)
+	fmt.Fprintf(sb, "user_timestamp %d\n", userTS)
+	sb.WriteString("\n")
+	sb.WriteString(desc)
+	sb.WriteString("\n")
+}
+
+// GetCommitSigningPayload returns the signing payload of the commit |cv|, and its signature, which is empty if the
+// commit isn't signed.
+func GetCommitSigningPayload(cv types.Value) ([]byte, string, error) {
+	sm, ok := cv.(types.SerialMessage)
+	if !ok {
+		return nil, "", nil
+	}
+	data := []byte(sm)
+	if serial.GetFileID(data) != serial.CommitFileID {
+		return nil, "", errors.New("GetCommitSigningPayload: provided value is not a commit.")
+	}
+	var cmsg serial.Commit
+	err := serial.InitCommitRoot(&cmsg, data, serial.MessagePrefixSz)
+	if err != nil {
+		return nil, "", err
+	}
+
+	root := hash.New(cmsg.RootBytes())
+	addrs := cmsg.ParentAddrsBytes()
+	parents := make([]hash.Hash, len(addrs)/hash.ByteLen)
+	for i := range parents {
+		parents[i] = hash.New(addrs[i*hash.ByteLen : (i+1)*hash.ByteLen])
+	}
+	meta := &CommitMeta{
+		Name:          string(cmsg.Name()),
+		Email:         string(cmsg.Email()),
+		Timestamp:     cmsg.TimestampMillis(),
+		Description:   string(cmsg.Description()),
+		UserTimestamp: cmsg.UserTimestampMillis(),
+		Metadata:      string(cmsg.Metadata()),
+	}
+	return CommitSigningPayload(root, parents, meta), string(cmsg.Signature()), nil
+}
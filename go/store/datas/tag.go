@@ -53,6 +53,9 @@ type TagOptions struct {
 // the format for |db| is noms.
 func newTag(ctx context.Context, db *database, commitAddr hash.Hash, meta *TagMeta) (hash.Hash, types.Ref, error) {
 	if !db.Format().UsesFlatbuffers() {
+		if meta != nil && meta.Signature != "" {
+			return hash.Hash{}, types.Ref{}, ErrSigningNotSupported
+		}
 		commitSt, err := db.ReadValue(ctx, commitAddr)
 		if err != nil {
 			return hash.Hash{}, types.Ref{}, err
@@ -114,11 +117,14 @@ func newTag(ctx context.Context, db *database, commitAddr hash.Hash, meta *TagMe
 func tag_flatbuffer(commitAddr hash.Hash, meta *TagMeta) serial.Message {
 	builder := flatbuffers.NewBuilder(1024)
 	addroff := builder.CreateByteVector(commitAddr[:])
-	var nameOff, emailOff, descOff flatbuffers.UOffsetT
+	var nameOff, emailOff, descOff, sigOff flatbuffers.UOffsetT
 	if meta != nil {
 		nameOff = builder.CreateString(meta.Name)
 		emailOff = builder.CreateString(meta.Email)
 		descOff = builder.CreateString(meta.Description)
+		if meta.Signature != "" {
+			sigOff = builder.CreateString(meta.Signature)
+		}
 	}
 	serial.TagStart(builder)
 	serial.TagAddCommitAddr(builder, addroff)
@@ -128,6 +134,9 @@ func tag_flatbuffer(commitAddr hash.Hash, meta *TagMeta) serial.Message {
 		serial.TagAddDesc(builder, descOff)
 		serial.TagAddTimestampMillis(builder, meta.Timestamp)
 		serial.TagAddUserTimestampMillis(builder, meta.UserTimestamp)
+		if meta.Signature != "" {
+			serial.TagAddSignature(builder, sigOff)
+		}
 	}
 	return serial.FinishMessage(builder, serial.TagEnd(builder), []byte(serial.TagFileID))
 }
@@ -44,6 +44,8 @@ type TagMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	// Signature is the GPG or SSH signature of the tag, or empty if the tag isn't signed. See TagSigningPayload.
+	Signature string
 }
 
 // NewTagMetaWithUserTS returns TagMeta that can be used to create a tag.
@@ -61,7 +63,7 @@ func NewTagMetaWithUserTS(name, email, desc string, userTS time.Time) *TagMeta {
 	ms := uint64(TagNowFunc().UnixMilli())
 	userMS := userTS.UnixMilli()
 
-	return &TagMeta{n, e, ms, d, userMS}
+	return &TagMeta{n, e, ms, d, userMS, ""}
 }
 
 func tagMetaFromNomsSt(st types.Struct) (*TagMeta, error) {
@@ -103,6 +105,7 @@ func tagMetaFromNomsSt(st types.Struct) (*TagMeta, error) {
 		uint64(ts.(types.Uint)),
 		string(d.(types.String)),
 		int64(userTS.(types.Int)),
+		"",
 	}, nil
 }
 
@@ -0,0 +1,593 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
+)
+
+// Archive files are a second tier of table file for chunks which are rarely read. Rather than
+// compressing each chunk on its own, an archive groups chunks into large blocks and compresses
+// each block with zstd, using a dictionary trained on samples taken from across all of the
+// archive's chunks. Archives are much smaller than table files holding the same chunks, but
+// reading a chunk from an archive means decompressing its whole block.
+//
+// An archive file is laid out as:
+//
+//	+---------+-----+-------------+------------+-------+-------------+--------+
+//	| Block 0 | ... | Block N - 1 | Dictionary | Index | Block Table | Footer |
+//	+---------+-----+-------------+------------+-------+-------------+--------+
+//
+// Each block is a zstd frame holding the uncompressed data of a run of chunks. The index
+// holds a record for each chunk, sorted by address:
+//
+//	+--------------+----------------+-----------------+-----------------+
+//	| (20) Address | (uint32) Block | (uint32) Offset | (uint32) Length |
+//	+--------------+----------------+-----------------+-----------------+
+//
+// where Offset and Length locate the chunk's data within its decompressed block. The block
+// table holds the (uint64) offset and (uint32) length of each block in the file. The footer is:
+//
+//	+-------------------+-----------------+-----------------+----------------------------+-----------+
+//	| (uint32) Dict Len | (uint32) Blocks | (uint32) Chunks | (uint64) Uncompressed Data | (8) Magic |
+//	+-------------------+-----------------+-----------------+----------------------------+-----------+
+//
+// Archives are named and listed in the manifest like table files, and are told apart from
+// table files by their magic number. Archives are never conjoined.
+
+const (
+	archiveMagic = "DOLTARC1"
+
+	archiveIndexRecordSize = addrSize + 3*uint32Size
+	archiveBlockRecordSize = uint64Size + uint32Size
+	archiveFooterSize      = 3*uint32Size + uint64Size + len(archiveMagic)
+
+	// archiveBlockSize is the uncompressed size past which an archive block is compressed
+	archiveBlockSize = 256 * 1024
+
+	// archiveBlockCacheSize is the number of decompressed
+	// blocks an archive keeps in memory
+	archiveBlockCacheSize = 8
+)
+
+var ErrArchivedChunkLocations = errors.New("chunk locations are not available for archived chunks")
+
+// isArchiveFile returns true if the file |r| of size |sz| is an archive.
+func isArchiveFile(r io.ReaderAt, sz int64) (bool, error) {
+	if sz < int64(archiveFooterSize) {
+		return false, nil
+	}
+	var magic [len(archiveMagic)]byte
+	if _, err := r.ReadAt(magic[:], sz-int64(len(magic))); err != nil {
+		return false, err
+	}
+	return string(magic[:]) == archiveMagic, nil
+}
+
+type archiveChunk struct {
+	a   addr
+	blk uint32
+	off uint32
+	len uint32
+}
+
+// archiveWriter writes chunks into an archive file. Chunks are written uncompressed to a spill
+// file as they're added, and only compressed into the archive by finish, so that the archive's
+// dictionary can be trained on samples taken from across all of them.
+type archiveWriter struct {
+	f    *os.File
+	w    io.WriteCloser
+	path string
+	off  uint64
+
+	spill    *os.File
+	spillOff uint64
+
+	// enc encrypts the archive and its spill file, if it isn't nil. The archive is
+	// encrypted like a table file, and each block of the spill file is sealed with
+	// |spillNonce| and its index.
+	enc        *Encryption
+	spillNonce []byte
+
+	// every |stride|th chunk is kept in |samples| to train the dictionary on. When
+	// there are twice |dictMaxSamples| of them, every other one is dropped and
+	// |stride| is doubled, so the samples stay spread evenly across all the chunks.
+	samples [][]byte
+	stride  int
+	added   int
+
+	dict    []byte
+	block   []byte
+	blocks  []Range
+	records []archiveChunk
+	uncmpSz uint64
+}
+
+// newArchiveWriter returns an archiveWriter which writes its temp files to |tempDir|, encrypted
+// with |enc| if it isn't nil.
+func newArchiveWriter(tempDir string, enc *Encryption) (*archiveWriter, error) {
+	f, err := tempfiles.MovableTempFileProvider.NewFile(tempDir, "archive_")
+	if err != nil {
+		return nil, err
+	}
+	w, err := newTableFileWriter(f, enc)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	spill, err := tempfiles.MovableTempFileProvider.NewFile(tempDir, "archive_spill_")
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	aw := &archiveWriter{f: f, w: w, path: f.Name(), spill: spill, enc: enc, stride: 1}
+	if enc != nil {
+		aw.spillNonce = make([]byte, encFileNonceSize)
+		if _, err = rand.Read(aw.spillNonce); err != nil {
+			aw.remove()
+			return nil, err
+		}
+	}
+	return aw, nil
+}
+
+// addChunk adds |c| to the archive.
+func (aw *archiveWriter) addChunk(c chunks.Chunk) error {
+	if aw.added%aw.stride == 0 {
+		aw.samples = append(aw.samples, c.Data())
+		if len(aw.samples) == 2*dictMaxSamples {
+			for i := 0; i < dictMaxSamples; i++ {
+				aw.samples[i] = aw.samples[2*i]
+			}
+			aw.samples = aw.samples[:dictMaxSamples]
+			aw.stride *= 2
+		}
+	}
+	aw.added++
+
+	aw.records = append(aw.records, archiveChunk{
+		a:   addr(c.Hash()),
+		blk: uint32(len(aw.blocks)),
+		off: uint32(len(aw.block)),
+		len: uint32(len(c.Data())),
+	})
+	aw.block = append(aw.block, c.Data()...)
+	aw.uncmpSz += uint64(len(c.Data()))
+	if len(aw.block) >= archiveBlockSize {
+		return aw.spillBlock()
+	}
+	return nil
+}
+
+// spillBlock writes the current block, uncompressed, to the spill file.
+func (aw *archiveWriter) spillBlock() error {
+	buf := aw.block
+	if aw.enc != nil {
+		buf = aw.enc.seal(nil, buf, blockAAD(aw.spillNonce, int64(len(aw.blocks))))
+	}
+	if _, err := aw.spill.Write(buf); err != nil {
+		return err
+	}
+	aw.blocks = append(aw.blocks, Range{Offset: aw.spillOff, Length: uint32(len(buf))})
+	aw.spillOff += uint64(len(buf))
+	aw.block = aw.block[:0]
+	return nil
+}
+
+// compressBlocks trains the dictionary and compresses the blocks of the spill file into the archive.
+func (aw *archiveWriter) compressBlocks() (err error) {
+	aw.dict = trainDictionary(aw.samples)
+	aw.samples = nil
+	enc, err := newDictEncoder(aw.dict)
+	if err != nil {
+		return err
+	}
+	defer enc.Close()
+
+	var buf, cmp []byte
+	for i, rng := range aw.blocks {
+		if cap(buf) < int(rng.Length) {
+			buf = make([]byte, rng.Length)
+		}
+		buf = buf[:rng.Length]
+		if _, err = aw.spill.ReadAt(buf, int64(rng.Offset)); err != nil {
+			return err
+		}
+		plain := buf
+		if aw.enc != nil {
+			if plain, err = aw.enc.open(nil, buf, blockAAD(aw.spillNonce, int64(i))); err != nil {
+				return fmt.Errorf("failed to decrypt archive spill file: %w", err)
+			}
+		}
+		cmp = enc.EncodeAll(plain, cmp[:0])
+		if _, err = aw.w.Write(cmp); err != nil {
+			return err
+		}
+		aw.blocks[i] = Range{Offset: aw.off, Length: uint32(len(cmp))}
+		aw.off += uint64(len(cmp))
+	}
+	return nil
+}
+
+func (aw *archiveWriter) chunkCount() int {
+	return len(aw.records)
+}
+
+// finish writes the dictionary, index and footer of the archive and returns its name.
+func (aw *archiveWriter) finish() (name addr, err error) {
+	if len(aw.block) > 0 {
+		if err = aw.spillBlock(); err != nil {
+			return addr{}, err
+		}
+	}
+	if err = aw.compressBlocks(); err != nil {
+		return addr{}, err
+	}
+
+	sort.Slice(aw.records, func(i, j int) bool {
+		return bytes.Compare(aw.records[i].a[:], aw.records[j].a[:]) < 0
+	})
+	index := make([]byte, len(aw.records)*archiveIndexRecordSize)
+	for i, r := range aw.records {
+		buf := index[i*archiveIndexRecordSize:]
+		copy(buf, r.a[:])
+		binary.BigEndian.PutUint32(buf[addrSize:], r.blk)
+		binary.BigEndian.PutUint32(buf[addrSize+uint32Size:], r.off)
+		binary.BigEndian.PutUint32(buf[addrSize+2*uint32Size:], r.len)
+	}
+
+	tail := make([]byte, 0, len(aw.dict)+len(index)+len(aw.blocks)*archiveBlockRecordSize+archiveFooterSize)
+	tail = append(tail, aw.dict...)
+	tail = append(tail, index...)
+	for _, b := range aw.blocks {
+		tail = binary.BigEndian.AppendUint64(tail, b.Offset)
+		tail = binary.BigEndian.AppendUint32(tail, b.Length)
+	}
+	tail = binary.BigEndian.AppendUint32(tail, uint32(len(aw.dict)))
+	tail = binary.BigEndian.AppendUint32(tail, uint32(len(aw.blocks)))
+	tail = binary.BigEndian.AppendUint32(tail, uint32(len(aw.records)))
+	tail = binary.BigEndian.AppendUint64(tail, aw.uncmpSz)
+	tail = append(tail, archiveMagic...)
+	if _, err = aw.w.Write(tail); err != nil {
+		return addr{}, err
+	}
+	aw.off += uint64(len(tail))
+	if err = aw.w.Close(); err != nil {
+		return addr{}, err
+	}
+	if err = aw.f.Sync(); err != nil {
+		return addr{}, err
+	}
+
+	// archives are named by their index, like table files
+	sum := sha512.Sum512(index)
+	copy(name[:], sum[:addrSize])
+	return name, nil
+}
+
+// reader returns a reader of the finished archive and its size.
+func (aw *archiveWriter) reader() (io.ReadCloser, uint64, error) {
+	r, err := openTableFileReader(aw.path, aw.enc)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r, aw.off, nil
+}
+
+// remove closes and deletes the archive's temp files.
+func (aw *archiveWriter) remove() error {
+	_ = aw.spill.Close()
+	_ = os.Remove(aw.spill.Name())
+	_ = aw.f.Close()
+	return os.Remove(aw.path)
+}
+
+// archiveData is the immutable state of an archive, shared by
+// the clones of an archiveReader.
+type archiveData struct {
+	index  []byte
+	blocks []Range
+	chunks uint32
+	uncmp  uint64
+	sz     uint64
+
+	dec   *zstd.Decoder
+	cache *lru.Cache[uint32, []byte]
+	refs  atomic.Int32
+}
+
+func (d *archiveData) release() {
+	if d.refs.Add(-1) == 0 {
+		d.dec.Close()
+	}
+}
+
+// archiveReader is a chunkSource that reads chunks from an archive file.
+type archiveReader struct {
+	data *archiveData
+	r    tableReaderAt
+	h    addr
+}
+
+var _ chunkSource = archiveReader{}
+
+// newArchiveReader reads the archive named |h| from |r|, which has size |sz|.
+func newArchiveReader(ctx context.Context, h addr, r tableReaderAt, sz int64, stats *Stats) (archiveReader, error) {
+	if sz < int64(archiveFooterSize) {
+		return archiveReader{}, fmt.Errorf("archive %s is too small", h.String())
+	}
+	footer := make([]byte, archiveFooterSize)
+	if _, err := r.ReadAtWithStats(ctx, footer, sz-int64(archiveFooterSize), stats); err != nil {
+		return archiveReader{}, err
+	}
+	dictLen := binary.BigEndian.Uint32(footer)
+	blockCnt := binary.BigEndian.Uint32(footer[uint32Size:])
+	chunkCnt := binary.BigEndian.Uint32(footer[2*uint32Size:])
+	uncmp := binary.BigEndian.Uint64(footer[3*uint32Size:])
+
+	tailSz := int64(dictLen) + int64(chunkCnt)*archiveIndexRecordSize + int64(blockCnt)*archiveBlockRecordSize
+	if tailSz > sz-int64(archiveFooterSize) {
+		return archiveReader{}, fmt.Errorf("archive %s is corrupt", h.String())
+	}
+	tail := make([]byte, tailSz)
+	if _, err := r.ReadAtWithStats(ctx, tail, sz-int64(archiveFooterSize)-tailSz, stats); err != nil {
+		return archiveReader{}, err
+	}
+	dict := tail[:dictLen]
+	index := tail[dictLen : int64(dictLen)+int64(chunkCnt)*archiveIndexRecordSize]
+	blockTable := tail[int64(dictLen)+int64(chunkCnt)*archiveIndexRecordSize:]
+
+	blocks := make([]Range, blockCnt)
+	for i := range blocks {
+		b := blockTable[i*archiveBlockRecordSize:]
+		blocks[i] = Range{
+			Offset: binary.BigEndian.Uint64(b),
+			Length: binary.BigEndian.Uint32(b[uint64Size:]),
+		}
+	}
+
+	dec, err := newDictDecoder(dict)
+	if err != nil {
+		return archiveReader{}, err
+	}
+	cache, err := lru.New[uint32, []byte](archiveBlockCacheSize)
+	if err != nil {
+		dec.Close()
+		return archiveReader{}, err
+	}
+	data := &archiveData{
+		index:  index,
+		blocks: blocks,
+		chunks: chunkCnt,
+		uncmp:  uncmp,
+		sz:     uint64(sz),
+		dec:    dec,
+		cache:  cache,
+	}
+	data.refs.Store(1)
+	return archiveReader{data: data, r: r, h: h}, nil
+}
+
+// lookup returns the index record of the chunk |h|, if the archive holds it.
+func (ar archiveReader) lookup(h addr) (archiveChunk, bool) {
+	idx := ar.data.index
+	n := len(idx) / archiveIndexRecordSize
+	i := sort.Search(n, func(i int) bool {
+		return bytes.Compare(idx[i*archiveIndexRecordSize:i*archiveIndexRecordSize+addrSize], h[:]) >= 0
+	})
+	if i == n {
+		return archiveChunk{}, false
+	}
+	rec := idx[i*archiveIndexRecordSize:]
+	if !bytes.Equal(rec[:addrSize], h[:]) {
+		return archiveChunk{}, false
+	}
+	return archiveChunk{
+		a:   h,
+		blk: binary.BigEndian.Uint32(rec[addrSize:]),
+		off: binary.BigEndian.Uint32(rec[addrSize+uint32Size:]),
+		len: binary.BigEndian.Uint32(rec[addrSize+2*uint32Size:]),
+	}, true
+}
+
+// records returns a getRecord for each chunk in the archive.
+func (ar archiveReader) records() []getRecord {
+	idx := ar.data.index
+	reqs := make([]getRecord, len(idx)/archiveIndexRecordSize)
+	for i := range reqs {
+		a := new(addr)
+		copy(a[:], idx[i*archiveIndexRecordSize:])
+		reqs[i] = getRecord{a: a, prefix: a.Prefix()}
+	}
+	return reqs
+}
+
+// readBlock returns the decompressed block |blk|.
+func (ar archiveReader) readBlock(ctx context.Context, blk uint32, stats *Stats) ([]byte, error) {
+	if b, ok := ar.data.cache.Get(blk); ok {
+		return b, nil
+	}
+	if int(blk) >= len(ar.data.blocks) {
+		return nil, fmt.Errorf("archive %s is corrupt", ar.h.String())
+	}
+	rng := ar.data.blocks[blk]
+	buf := make([]byte, rng.Length)
+	if _, err := ar.r.ReadAtWithStats(ctx, buf, int64(rng.Offset), stats); err != nil {
+		return nil, err
+	}
+	b, err := ar.data.dec.DecodeAll(buf, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archive %s: %w", ar.h.String(), err)
+	}
+	ar.data.cache.Add(blk, b)
+	return b, nil
+}
+
+func (ar archiveReader) readChunk(ctx context.Context, rec archiveChunk, stats *Stats) ([]byte, error) {
+	b, err := ar.readBlock(ctx, rec.blk, stats)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(rec.off)+uint64(rec.len) > uint64(len(b)) {
+		return nil, fmt.Errorf("archive %s is corrupt", ar.h.String())
+	}
+	data := make([]byte, rec.len)
+	copy(data, b[rec.off:])
+	if addr(hash.Of(data)) != rec.a {
+		return nil, fmt.Errorf("archive %s is corrupt: chunk %s does not match its address", ar.h.String(), rec.a.String())
+	}
+	return data, nil
+}
+
+func (ar archiveReader) has(h addr) (bool, error) {
+	_, ok := ar.lookup(h)
+	return ok, nil
+}
+
+func (ar archiveReader) hasMany(addrs []hasRecord) (missing bool, err error) {
+	for i := range addrs {
+		if addrs[i].has {
+			continue
+		}
+		if _, ok := ar.lookup(*addrs[i].a); ok {
+			addrs[i].has = true
+		} else {
+			missing = true
+		}
+	}
+	return
+}
+
+func (ar archiveReader) get(ctx context.Context, h addr, stats *Stats) ([]byte, error) {
+	rec, ok := ar.lookup(h)
+	if !ok {
+		return nil, nil
+	}
+	return ar.readChunk(ctx, rec, stats)
+}
+
+// readMany reads the chunks of |reqs| held by the archive, block by block.
+func (ar archiveReader) readMany(ctx context.Context, reqs []getRecord, found func(h addr, data []byte), stats *Stats) (remaining bool, err error) {
+	recs := make([]archiveChunk, 0, len(reqs))
+	for i := range reqs {
+		if reqs[i].found {
+			continue
+		}
+		if rec, ok := ar.lookup(*reqs[i].a); ok {
+			reqs[i].found = true
+			recs = append(recs, rec)
+		} else {
+			remaining = true
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].blk < recs[j].blk
+	})
+	for _, rec := range recs {
+		data, err := ar.readChunk(ctx, rec, stats)
+		if err != nil {
+			return false, err
+		}
+		found(rec.a, data)
+	}
+	return remaining, nil
+}
+
+func (ar archiveReader) getMany(ctx context.Context, _ *errgroup.Group, reqs []getRecord, found func(context.Context, *chunks.Chunk), stats *Stats) (bool, error) {
+	return ar.readMany(ctx, reqs, func(h addr, data []byte) {
+		c := chunks.NewChunkWithHash(hash.Hash(h), data)
+		found(ctx, &c)
+	}, stats)
+}
+
+func (ar archiveReader) getManyCompressed(ctx context.Context, _ *errgroup.Group, reqs []getRecord, found func(context.Context, CompressedChunk), stats *Stats) (bool, error) {
+	return ar.readMany(ctx, reqs, func(h addr, data []byte) {
+		found(ctx, ChunkToCompressedChunk(chunks.NewChunkWithHash(hash.Hash(h), data)))
+	}, stats)
+}
+
+func (ar archiveReader) count() (uint32, error) {
+	return ar.data.chunks, nil
+}
+
+func (ar archiveReader) uncompressedLen() (uint64, error) {
+	return ar.data.uncmp, nil
+}
+
+func (ar archiveReader) hash() addr {
+	return ar.h
+}
+
+func (ar archiveReader) reader(ctx context.Context) (io.ReadCloser, uint64, error) {
+	r, err := ar.r.Reader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r, ar.data.sz, nil
+}
+
+// getRecordRanges implements chunkSource. Chunks in an archive can't be read
+// directly from the file, so it fails if the archive holds any of |requests|.
+// NomsBlockStore serves them from a table file instead, see archiveTransfer.
+func (ar archiveReader) getRecordRanges(requests []getRecord) (map[hash.Hash]Range, error) {
+	for _, req := range requests {
+		if _, ok := ar.lookup(*req.a); ok && !req.found {
+			return nil, ErrArchivedChunkLocations
+		}
+	}
+	return map[hash.Hash]Range{}, nil
+}
+
+func (ar archiveReader) index() (tableIndex, error) {
+	return nil, fmt.Errorf("archive %s cannot be conjoined", ar.h.String())
+}
+
+func (ar archiveReader) clone() (chunkSource, error) {
+	r, err := ar.r.clone()
+	if err != nil {
+		return nil, err
+	}
+	ar.data.refs.Add(1)
+	return archiveReader{data: ar.data, r: r, h: ar.h}, nil
+}
+
+func (ar archiveReader) close() error {
+	ar.data.release()
+	return ar.r.Close()
+}
+
+func (ar archiveReader) currentSize() uint64 {
+	return ar.data.sz
+}
@@ -0,0 +1,417 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func makeArchiveTestChunks(n, seed int) map[hash.Hash]chunks.Chunk {
+	s := make(map[hash.Hash]chunks.Chunk, n)
+	for i := 0; i < n; i++ {
+		c := chunks.NewChunk([]byte(fmt.Sprintf("%d:%d:%s", seed, i, strings.Repeat("archived row data ", i%16))))
+		s[c.Hash()] = c
+	}
+	return s
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		n   int
+		enc *Encryption
+	}{
+		{n: 1},
+		{n: 100},
+		{n: 5000},
+		{n: 1, enc: makeTestEncryption(t)},
+		{n: 5000, enc: makeTestEncryption(t)},
+	} {
+		n := test.n
+		name := fmt.Sprintf("%d chunks", n)
+		if test.enc != nil {
+			name += " encrypted"
+		}
+		t.Run(name, func(t *testing.T) {
+			data := makeArchiveTestChunks(n, 0)
+			dir := t.TempDir()
+			aw, err := newArchiveWriter(dir, test.enc)
+			require.NoError(t, err)
+			defer aw.remove()
+			for _, c := range data {
+				require.NoError(t, aw.addChunk(c))
+			}
+			name, err := aw.finish()
+			require.NoError(t, err)
+			assert.Equal(t, n, aw.chunkCount())
+
+			if test.enc != nil {
+				// the temp files of the archive don't hold the chunks
+				entries, err := os.ReadDir(dir)
+				require.NoError(t, err)
+				assert.Len(t, entries, 2)
+				for _, e := range entries {
+					b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+					require.NoError(t, err)
+					var i int
+					for _, c := range data {
+						require.False(t, bytes.Contains(b, c.Data()), "%s holds plaintext", e.Name())
+						if i++; i == 64 {
+							break
+						}
+					}
+				}
+			}
+
+			rd, sz, err := aw.reader()
+			require.NoError(t, err)
+			buf, err := io.ReadAll(rd)
+			require.NoError(t, err)
+			require.NoError(t, rd.Close())
+			require.Equal(t, sz, uint64(len(buf)))
+
+			ok, err := isArchiveFile(bytes.NewReader(buf), int64(len(buf)))
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ar, err := newArchiveReader(ctx, name, tableReaderAtFromBytes(buf), int64(len(buf)), &Stats{})
+			require.NoError(t, err)
+			defer ar.close()
+			cnt, err := ar.count()
+			require.NoError(t, err)
+			assert.Equal(t, uint32(n), cnt)
+
+			for h, c := range data {
+				ok, err := ar.has(addr(h))
+				require.NoError(t, err)
+				require.True(t, ok)
+				act, err := ar.get(ctx, addr(h), &Stats{})
+				require.NoError(t, err)
+				require.Equal(t, c.Data(), act)
+			}
+			missing := chunks.NewChunk([]byte("missing"))
+			ok, err = ar.has(addr(missing.Hash()))
+			require.NoError(t, err)
+			assert.False(t, ok)
+			act, err := ar.get(ctx, addr(missing.Hash()), &Stats{})
+			require.NoError(t, err)
+			assert.Nil(t, act)
+
+			reqs := make([]getRecord, 0, len(data))
+			for h := range data {
+				a := addr(h)
+				reqs = append(reqs, getRecord{a: &a, prefix: a.Prefix()})
+			}
+			sort.Sort(getRecordByPrefix(reqs))
+			var mu sync.Mutex
+			found := make(map[hash.Hash]chunks.Chunk)
+			eg, ectx := errgroup.WithContext(ctx)
+			remaining, err := ar.getMany(ectx, eg, reqs, func(_ context.Context, c *chunks.Chunk) {
+				mu.Lock()
+				defer mu.Unlock()
+				found[c.Hash()] = *c
+			}, &Stats{})
+			require.NoError(t, err)
+			require.NoError(t, eg.Wait())
+			assert.False(t, remaining)
+			assert.Equal(t, data, found)
+		})
+	}
+}
+
+func TestArchiveWriterSamples(t *testing.T) {
+	aw, err := newArchiveWriter(t.TempDir(), nil)
+	require.NoError(t, err)
+	defer aw.remove()
+	n := 10 * dictMaxSamples
+	for i := 0; i < n; i++ {
+		require.NoError(t, aw.addChunk(chunks.NewChunk([]byte(fmt.Sprintf("chunk %d", i)))))
+	}
+	// the dictionary is trained on samples from across all the chunks, not just the first ones
+	assert.GreaterOrEqual(t, len(aw.samples), dictMaxSamples)
+	assert.Less(t, len(aw.samples), 2*dictMaxSamples)
+	last := string(aw.samples[len(aw.samples)-1])
+	var i int
+	_, err = fmt.Sscanf(last, "chunk %d", &i)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, i, n-aw.stride)
+	_, err = aw.finish()
+	require.NoError(t, err)
+}
+
+func TestNBSArchiveChunks(t *testing.T) {
+	ctx := context.Background()
+	st, nomsDir, q := makeTestLocalStore(t, 8)
+
+	cold := makeArchiveTestChunks(2048, 0)
+	warm := makeArchiveTestChunks(256, 1)
+	var root hash.Hash
+	for _, s := range []map[hash.Hash]chunks.Chunk{cold, warm} {
+		for h, c := range s {
+			require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+			root = h
+		}
+		last, err := st.Root(ctx)
+		require.NoError(t, err)
+		ok, err := st.Commit(ctx, root, last)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	sizeBefore, err := st.Size(ctx)
+	require.NoError(t, err)
+
+	addrs := make(hash.HashSet, len(cold))
+	for h := range cold {
+		addrs.Insert(h)
+	}
+	n, err := st.ArchiveChunks(ctx, []hash.HashSet{addrs}, false)
+	require.NoError(t, err)
+	assert.Equal(t, len(cold), n)
+
+	// chunks that are already archived are not archived again
+	n, err = st.ArchiveChunks(ctx, []hash.HashSet{addrs}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	assert.Equal(t, 1, countArchives(st))
+	require.NoError(t, st.PruneTableFiles(ctx))
+	sizeAfter, err := st.Size(ctx)
+	require.NoError(t, err)
+	assert.Less(t, sizeAfter, sizeBefore)
+
+	assertChunks := func(st *NomsBlockStore) {
+		for _, s := range []map[hash.Hash]chunks.Chunk{cold, warm} {
+			for h, c := range s {
+				act, err := st.Get(ctx, h)
+				require.NoError(t, err)
+				require.Equal(t, c.Data(), act.Data())
+			}
+		}
+		act, err := st.Root(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, root, act)
+	}
+	assertChunks(st)
+	require.NoError(t, st.Close())
+
+	st, err = newLocalStore(ctx, types.Format_Default.VersionString(), nomsDir, defaultMemTableSize, 8, q, nil)
+	require.NoError(t, err)
+	defer st.Close()
+	assertChunks(st)
+
+	// archives are left alone by conjoin
+	assert.False(t, withoutArchives(st.c, st.tables).conjoinRequired(st.tables))
+}
+
+func TestNBSArchiveChunkGroups(t *testing.T) {
+	ctx := context.Background()
+	st, _, _ := makeTestLocalStore(t, 8)
+	defer st.Close()
+
+	a := makeArchiveTestChunks(512, 0)
+	b := makeArchiveTestChunks(512, 1)
+	warm := makeArchiveTestChunks(128, 2)
+	var root hash.Hash
+	for _, s := range []map[hash.Hash]chunks.Chunk{a, b, warm} {
+		for h, c := range s {
+			require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+			root = h
+		}
+	}
+	ok, err := st.Commit(ctx, root, hash.Hash{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	addrs := func(ss ...map[hash.Hash]chunks.Chunk) hash.HashSet {
+		hs := make(hash.HashSet)
+		for _, s := range ss {
+			for h := range s {
+				hs.Insert(h)
+			}
+		}
+		return hs
+	}
+
+	n, err := st.ArchiveChunks(ctx, []hash.HashSet{addrs(a)}, false)
+	require.NoError(t, err)
+	assert.Equal(t, len(a), n)
+	assert.Equal(t, 1, countArchives(st))
+
+	// each group is archived apart from the others, and archived chunks are left alone
+	n, err = st.ArchiveChunks(ctx, []hash.HashSet{addrs(a), addrs(b)}, false)
+	require.NoError(t, err)
+	assert.Equal(t, len(b), n)
+	assert.Equal(t, 2, countArchives(st))
+
+	// rebuilding replaces the archives holding the chunks of the groups
+	n, err = st.ArchiveChunks(ctx, []hash.HashSet{addrs(a, b)}, true)
+	require.NoError(t, err)
+	assert.Equal(t, len(a)+len(b), n)
+	assert.Equal(t, 1, countArchives(st))
+
+	require.NoError(t, st.PruneTableFiles(ctx))
+	for _, s := range []map[hash.Hash]chunks.Chunk{a, b, warm} {
+		for h, c := range s {
+			act, err := st.Get(ctx, h)
+			require.NoError(t, err)
+			require.Equal(t, c.Data(), act.Data())
+		}
+	}
+}
+
+func countArchives(st *NomsBlockStore) (archives int) {
+	for _, cs := range st.tables.upstream {
+		if _, ok := cs.(archiveReader); ok {
+			archives++
+		}
+	}
+	return
+}
+
+func TestNBSArchiveTransfer(t *testing.T) {
+	ctx := context.Background()
+	st, nomsDir, _ := makeTestLocalStore(t, 8)
+	defer st.Close()
+
+	cold := makeArchiveTestChunks(512, 0)
+	var root hash.Hash
+	addrs := make(hash.HashSet, len(cold))
+	for h, c := range cold {
+		require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+		root = h
+		addrs.Insert(h)
+	}
+	ok, err := st.Commit(ctx, root, hash.Hash{})
+	require.NoError(t, err)
+	require.True(t, ok)
+	n, err := st.ArchiveChunks(ctx, []hash.HashSet{addrs}, false)
+	require.NoError(t, err)
+	require.Equal(t, len(cold), n)
+
+	require.NoError(t, st.PruneTableFiles(ctx))
+	filesBefore, err := os.ReadDir(nomsDir)
+	require.NoError(t, err)
+
+	// archived chunks are located in a table file which is read through the store
+	locs, err := st.GetChunkLocations(addrs)
+	require.NoError(t, err)
+	var located int
+	for name, ranges := range locs {
+		if len(ranges) == 0 {
+			continue
+		}
+		_, err = os.Stat(filepath.Join(nomsDir, name.String()))
+		require.True(t, os.IsNotExist(err))
+		r, ok, err := st.OpenTransferFile(ctx, name.String())
+		require.NoError(t, err)
+		require.True(t, ok)
+		for h, rng := range ranges {
+			buf := make([]byte, rng.Length)
+			_, err = r.ReadAt(buf, int64(rng.Offset))
+			require.NoError(t, err)
+			cc, err := NewCompressedChunk(h, buf)
+			require.NoError(t, err)
+			c, err := cc.ToChunk()
+			require.NoError(t, err)
+			assert.Equal(t, cold[h].Data(), c.Data())
+			located++
+		}
+	}
+	assert.Equal(t, len(cold), located)
+
+	// archives are sent as table files, which are read like any other
+	_, tableFiles, _, err := st.Sources(ctx)
+	require.NoError(t, err)
+	var chunkCount int
+	for _, tf := range tableFiles {
+		rd, sz, err := tf.Open(ctx)
+		require.NoError(t, err)
+		data, err := io.ReadAll(rd)
+		require.NoError(t, err)
+		require.NoError(t, rd.Close())
+		require.Equal(t, sz, uint64(len(data)))
+		isArchive, err := isArchiveFile(bytes.NewReader(data), int64(sz))
+		require.NoError(t, err)
+		assert.False(t, isArchive)
+
+		ti, err := parseTableIndexByCopy(ctx, data, &UnlimitedQuotaProvider{})
+		require.NoError(t, err)
+		tr, err := newTableReader(ti, tableReaderAtFromBytes(data), fileBlockSize)
+		require.NoError(t, err)
+		for h, c := range cold {
+			act, err := tr.get(ctx, addr(h), &Stats{})
+			require.NoError(t, err)
+			assert.Equal(t, c.Data(), act)
+		}
+		require.NoError(t, tr.close())
+		chunkCount += tf.NumChunks()
+	}
+	assert.Equal(t, len(cold), chunkCount)
+
+	// nothing is written to serve them
+	filesAfter, err := os.ReadDir(nomsDir)
+	require.NoError(t, err)
+	assert.Equal(t, len(filesBefore), len(filesAfter))
+	_, ok, err = st.OpenTransferFile(ctx, addr{}.String())
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestArchiveChecksum(t *testing.T) {
+	ctx := context.Background()
+	data := makeArchiveTestChunks(100, 0)
+	aw, err := newArchiveWriter(t.TempDir(), nil)
+	require.NoError(t, err)
+	defer aw.remove()
+	for _, c := range data {
+		require.NoError(t, aw.addChunk(c))
+	}
+	name, err := aw.finish()
+	require.NoError(t, err)
+	rd, _, err := aw.reader()
+	require.NoError(t, err)
+	buf, err := io.ReadAll(rd)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+
+	ar, err := newArchiveReader(ctx, name, tableReaderAtFromBytes(buf), int64(len(buf)), &Stats{})
+	require.NoError(t, err)
+	defer ar.close()
+
+	// point the index record of one chunk at the data of another
+	idx := ar.data.index
+	copy(idx[addrSize:archiveIndexRecordSize], idx[archiveIndexRecordSize+addrSize:2*archiveIndexRecordSize])
+	var a addr
+	copy(a[:], idx)
+	_, err = ar.get(ctx, a, &Stats{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "corrupt")
+}
@@ -0,0 +1,184 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/golang/snappy"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// archiveTransfer is a table file holding the chunks of an archive. Clients read chunks from the
+// table files of a store directly, which they can't do for archives, so fetches and clones are
+// served from an archive's transfer instead. A transfer is never written out: its records are
+// generated from the archive as they're read, and only its index is kept in memory.
+type archiveTransfer struct {
+	ar   archiveReader
+	name addr
+
+	// chunks are the archive's chunks in the order of their records, which is the order
+	// they're stored in the archive, so reading a run of records reads each block once
+	chunks []archiveChunk
+	// offsets are the offsets of each record, followed by the size of all the records
+	offsets []uint64
+	// tail is the index and footer of the table file
+	tail []byte
+}
+
+// newArchiveTransfer builds the transfer of |ar|. Building it reads the whole archive, since
+// the size of each record is only known once its chunk has been compressed.
+func newArchiveTransfer(ctx context.Context, ar archiveReader, stats *Stats) (*archiveTransfer, error) {
+	idx := ar.data.index
+	recs := make([]archiveChunk, len(idx)/archiveIndexRecordSize)
+	for i := range recs {
+		b := idx[i*archiveIndexRecordSize:]
+		copy(recs[i].a[:], b)
+		recs[i].blk = binary.BigEndian.Uint32(b[addrSize:])
+		recs[i].off = binary.BigEndian.Uint32(b[addrSize+uint32Size:])
+		recs[i].len = binary.BigEndian.Uint32(b[addrSize+2*uint32Size:])
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].blk < recs[j].blk || (recs[i].blk == recs[j].blk && recs[i].off < recs[j].off)
+	})
+
+	offsets := make([]uint64, len(recs)+1)
+	prefixes := make(prefixIndexSlice, len(recs))
+	var buf []byte
+	for i, rec := range recs {
+		data, err := ar.readChunk(ctx, rec, stats)
+		if err != nil {
+			return nil, err
+		}
+		buf = snappy.Encode(buf[:cap(buf)], data)
+		sz := uint64(len(buf)) + checksumSize
+		prefixes[i] = prefixIndexRec{addr: rec.a, order: uint32(i), size: uint32(sz)}
+		offsets[i+1] = offsets[i] + sz
+	}
+
+	tw := &tableWriter{
+		buff:                  make([]byte, indexSize(uint32(len(recs)))+footerSize),
+		prefixes:              prefixes,
+		blockHash:             sha512.New(),
+		totalUncompressedData: ar.data.uncmp,
+	}
+	_, name, err := tw.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	cs, err := ar.clone()
+	if err != nil {
+		return nil, err
+	}
+	return &archiveTransfer{
+		ar:      cs.(archiveReader),
+		name:    name,
+		chunks:  recs,
+		offsets: offsets,
+		tail:    tw.buff[:tw.pos],
+	}, nil
+}
+
+func (t *archiveTransfer) count() uint32 {
+	return uint32(len(t.chunks))
+}
+
+func (t *archiveTransfer) size() uint64 {
+	return t.offsets[len(t.chunks)] + uint64(len(t.tail))
+}
+
+// record returns the |i|th record of the table file.
+func (t *archiveTransfer) record(ctx context.Context, i int, stats *Stats) ([]byte, error) {
+	data, err := t.ar.readChunk(ctx, t.chunks[i], stats)
+	if err != nil {
+		return nil, err
+	}
+	rec := snappy.Encode(nil, data)
+	return binary.BigEndian.AppendUint32(rec, crc(rec)), nil
+}
+
+// readAt reads len(|p|) bytes of the table file from |off|.
+func (t *archiveTransfer) readAt(ctx context.Context, p []byte, off int64, stats *Stats) (n int, err error) {
+	sz := t.size()
+	dataSz := t.offsets[len(t.chunks)]
+	for n < len(p) && uint64(off)+uint64(n) < sz {
+		pos := uint64(off) + uint64(n)
+		if pos >= dataSz {
+			n += copy(p[n:], t.tail[pos-dataSz:])
+			continue
+		}
+		i := sort.Search(len(t.chunks), func(i int) bool {
+			return t.offsets[i+1] > pos
+		})
+		rec, err := t.record(ctx, i, stats)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], rec[pos-t.offsets[i]:])
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// reader returns a reader of the whole table file.
+func (t *archiveTransfer) reader(ctx context.Context, stats *Stats) *io.SectionReader {
+	return io.NewSectionReader(archiveTransferReaderAt{t: t, ctx: ctx, stats: stats}, 0, int64(t.size()))
+}
+
+// getRecordRanges returns the ranges of the records of the chunks of |requests| held by the archive.
+func (t *archiveTransfer) getRecordRanges(requests []getRecord) map[hash.Hash]Range {
+	ranges := make(map[hash.Hash]Range)
+	for i := range requests {
+		if requests[i].found {
+			continue
+		}
+		rec, ok := t.ar.lookup(*requests[i].a)
+		if !ok {
+			continue
+		}
+		j := sort.Search(len(t.chunks), func(j int) bool {
+			c := t.chunks[j]
+			return c.blk > rec.blk || (c.blk == rec.blk && c.off >= rec.off)
+		})
+		requests[i].found = true
+		ranges[hash.Hash(rec.a)] = Range{
+			Offset: t.offsets[j],
+			Length: uint32(t.offsets[j+1] - t.offsets[j]),
+		}
+	}
+	return ranges
+}
+
+func (t *archiveTransfer) close() error {
+	return t.ar.close()
+}
+
+type archiveTransferReaderAt struct {
+	t     *archiveTransfer
+	ctx   context.Context
+	stats *Stats
+}
+
+func (r archiveTransferReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.t.readAt(r.ctx, p, off, r.stats)
+}
@@ -111,7 +111,7 @@ func loadTableIndex(ctx context.Context, stats *Stats, cnt uint32, q MemoryQuota
 	stats.IndexReadLatency.SampleTimeSince(t1)
 	stats.IndexBytesPerRead.Sample(uint64(len(buf)))
 
-	idx, err := parseTableIndexWithOffsetBuff(buf[:idxSz], buf[idxSz:], q)
+	idx, err := parseTableIndexWithOffsetBuff(ctx, buf[:idxSz], buf[idxSz:], q)
 	if err != nil {
 		q.ReleaseQuotaBytes(len(buf))
 	}
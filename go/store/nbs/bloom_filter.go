@@ -0,0 +1,74 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"encoding/binary"
+)
+
+const (
+	// 10 bits and 7 probes per address give a false positive rate of about 1%
+	bloomBitsPerAddr = 10
+	bloomProbes      = 7
+)
+
+// bloomFilter is a Bloom filter of chunk addresses. Addresses are
+// uniformly distributed, so the probe locations are derived directly
+// from their bytes by double hashing.
+type bloomFilter struct {
+	bits []byte
+}
+
+// bloomFilterSize returns the size in bytes of the bloomFilter of
+// |count| addresses.
+func bloomFilterSize(count uint32) int {
+	sz := (uint64(count)*bloomBitsPerAddr + 7) / 8
+	if sz == 0 {
+		sz = 1
+	}
+	return int(sz)
+}
+
+// newBloomFilter returns an empty bloomFilter over |bits|, which must
+// be zeroed.
+func newBloomFilter(bits []byte) bloomFilter {
+	return bloomFilter{bits: bits}
+}
+
+func (f bloomFilter) probes(a *addr) (h1, h2, m uint64) {
+	h1 = binary.BigEndian.Uint64(a[:addrPrefixSize])
+	h2 = binary.BigEndian.Uint64(a[addrPrefixSize:]) | 1
+	return h1, h2, uint64(len(f.bits)) * 8
+}
+
+func (f bloomFilter) add(a *addr) {
+	h1, h2, m := f.probes(a)
+	for i := uint64(0); i < bloomProbes; i++ {
+		b := (h1 + i*h2) % m
+		f.bits[b/8] |= 1 << (b % 8)
+	}
+}
+
+// mayContain returns false if |a| was definitely not added to |f|.
+func (f bloomFilter) mayContain(a *addr) bool {
+	h1, h2, m := f.probes(a)
+	for i := uint64(0); i < bloomProbes; i++ {
+		b := (h1 + i*h2) % m
+		if f.bits[b/8]&(1<<(b%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilter(t *testing.T) {
+	const n = 10000
+	f := newBloomFilter(make([]byte, bloomFilterSize(n)))
+	for i := 0; i < n; i++ {
+		a := computeAddr([]byte(fmt.Sprintf("present %d", i)))
+		f.add(&a)
+	}
+	for i := 0; i < n; i++ {
+		a := computeAddr([]byte(fmt.Sprintf("present %d", i)))
+		require.True(t, f.mayContain(&a))
+	}
+
+	var falsePositives int
+	for i := 0; i < n; i++ {
+		a := computeAddr([]byte(fmt.Sprintf("absent %d", i)))
+		if f.mayContain(&a) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, n/50)
+
+	empty := newBloomFilter(make([]byte, bloomFilterSize(0)))
+	a := computeAddr([]byte("absent"))
+	assert.False(t, empty.mayContain(&a))
+}
+
+func TestTableReaderFilter(t *testing.T) {
+	ctx := context.Background()
+	var data [][]byte
+	for i := 0; i < 1000; i++ {
+		data = append(data, []byte(fmt.Sprintf("chunk %d", i)))
+	}
+	tableData, _, err := buildTable(data)
+	require.NoError(t, err)
+	q := &UnlimitedQuotaProvider{}
+	ti, err := parseTableIndexByCopy(ctx, tableData, q)
+	require.NoError(t, err)
+	tr, err := newTableReader(ti, tableReaderAtFromBytes(tableData), fileBlockSize)
+	require.NoError(t, err)
+
+	// the filter is built with the index, and counted in its quota
+	chunks1 := uint64(len(data) - len(data)/2)
+	indexSz := indexSize(uint32(len(data))) + footerSize + chunks1*offsetSize
+	assert.Equal(t, indexSz+uint64(bloomFilterSize(uint32(len(data)))), q.Usage())
+
+	var addrs addrSlice
+	for i := 0; i < 500; i++ {
+		addrs = append(addrs, computeAddr(data[i*2]))
+		addrs = append(addrs, computeAddr([]byte(fmt.Sprintf("absent %d", i))))
+	}
+	hasRecs := make([]hasRecord, len(addrs))
+	getRecs := make([]getRecord, len(addrs))
+	for i := range addrs {
+		hasRecs[i] = hasRecord{a: &addrs[i], prefix: addrs[i].Prefix(), order: i}
+		getRecs[i] = getRecord{a: &addrs[i], prefix: addrs[i].Prefix()}
+	}
+	sort.Sort(hasRecordByPrefix(hasRecs))
+	sort.Sort(getRecordByPrefix(getRecs))
+
+	remaining, err := tr.hasMany(hasRecs)
+	require.NoError(t, err)
+	assert.True(t, remaining)
+	for _, rec := range hasRecs {
+		assert.Equal(t, rec.order%2 == 0, rec.has)
+	}
+
+	ors, remaining, err := tr.findOffsets(getRecs)
+	require.NoError(t, err)
+	assert.True(t, remaining)
+	assert.Len(t, ors, 500)
+
+	require.NoError(t, tr.close())
+	assert.Equal(t, uint64(0), q.Usage())
+}
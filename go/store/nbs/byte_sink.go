@@ -179,26 +179,44 @@ type BufferedFileByteSink struct {
 	ae      *atomicerr.AtomicError
 	wg      *sync.WaitGroup
 
+	f    *os.File
 	wr   io.WriteCloser
 	path string
+	// enc encrypts the file written by the sink, if it isn't nil
+	enc *Encryption
 }
 
 // NewBufferedFileByteSink creates a BufferedFileByteSink
 func NewBufferedFileByteSink(tempDir string, blockSize, chBufferSize int) (*BufferedFileByteSink, error) {
+	return newBufferedFileByteSink(tempDir, blockSize, chBufferSize, nil)
+}
+
+// newBufferedFileByteSink creates a BufferedFileByteSink which encrypts its file with |enc| if it isn't nil. The data
+// read back from the sink is decrypted, but the file it flushes to a path stays encrypted.
+func newBufferedFileByteSink(tempDir string, blockSize, chBufferSize int, enc *Encryption) (*BufferedFileByteSink, error) {
 	f, err := tempfiles.MovableTempFileProvider.NewFile(tempDir, "buffered_file_byte_sink_")
 
 	if err != nil {
 		return nil, err
 	}
 
+	wr, err := newTableFileWriter(f, enc)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+
 	sink := &BufferedFileByteSink{
 		blockSize:    blockSize,
 		currentBlock: make([]byte, blockSize),
 		writeCh:      make(chan []byte, chBufferSize),
 		ae:           atomicerr.New(),
 		wg:           &sync.WaitGroup{},
-		wr:           f,
+		f:            f,
+		wr:           wr,
 		path:         f.Name(),
+		enc:          enc,
 	}
 
 	sink.wg.Add(1)
@@ -248,7 +266,11 @@ func (sink *BufferedFileByteSink) backgroundWrite() {
 		sink.ae.SetIfError(err)
 	}
 
-	err = sink.wr.Close()
+	if err == nil {
+		err = sink.wr.Close()
+		sink.ae.SetIfError(err)
+	}
+	err = sink.f.Close()
 	sink.ae.SetIfError(err)
 }
 
@@ -277,8 +299,8 @@ func (sink *BufferedFileByteSink) Flush(wr io.Writer) (err error) {
 		return err
 	}
 
-	var f *os.File
-	f, err = os.Open(sink.path)
+	var f io.ReadCloser
+	f, err = openTableFileReader(sink.path, sink.enc)
 
 	if err != nil {
 		return err
@@ -312,7 +334,7 @@ func (sink *BufferedFileByteSink) Reader() (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
-	return os.Open(sink.path)
+	return openTableFileReader(sink.path, sink.enc)
 }
 
 // HashingByteSink is a ByteSink that keeps an md5 hash of all the data written to it.
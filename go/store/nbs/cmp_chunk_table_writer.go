@@ -46,16 +46,23 @@ type CmpChunkTableWriter struct {
 	prefixes              prefixIndexSlice
 	blockAddr             *addr
 	path                  string
+	enc                   *Encryption
 }
 
 // NewCmpChunkTableWriter creates a new CmpChunkTableWriter instance with a default ByteSink
 func NewCmpChunkTableWriter(tempDir string) (*CmpChunkTableWriter, error) {
-	s, err := NewBufferedFileByteSink(tempDir, defaultTableSinkBlockSize, defaultChBufferSize)
+	return NewEncryptedCmpChunkTableWriter(tempDir, nil)
+}
+
+// NewEncryptedCmpChunkTableWriter creates a new CmpChunkTableWriter which encrypts the temp file it writes the table
+// file to with |enc|, if |enc| isn't nil. The table file read back from the writer is decrypted.
+func NewEncryptedCmpChunkTableWriter(tempDir string, enc *Encryption) (*CmpChunkTableWriter, error) {
+	s, err := newBufferedFileByteSink(tempDir, defaultTableSinkBlockSize, defaultChBufferSize, enc)
 	if err != nil {
 		return nil, err
 	}
 
-	return &CmpChunkTableWriter{NewHashingByteSink(s), 0, 0, nil, nil, s.path}, nil
+	return &CmpChunkTableWriter{NewHashingByteSink(s), 0, 0, nil, nil, s.path, enc}, nil
 }
 
 func (tw *CmpChunkTableWriter) ChunkCount() int {
@@ -24,10 +24,12 @@ package nbs
 import (
 	"context"
 	"errors"
+	"io"
 	"sort"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 )
 
 type conjoinStrategy interface {
@@ -84,6 +86,53 @@ func (c noopConjoiner) chooseConjoinees(sources []tableSpec) (conjoinees, keeper
 	return
 }
 
+// archiveConjoiner is a conjoinStrategy which never conjoins archives.
+type archiveConjoiner struct {
+	child    conjoinStrategy
+	archives map[addr]struct{}
+}
+
+var _ conjoinStrategy = archiveConjoiner{}
+
+// withoutArchives returns a conjoinStrategy which applies |c| to the
+// tables of |ts| that aren't archives.
+func withoutArchives(c conjoinStrategy, ts tableSet) conjoinStrategy {
+	var archives map[addr]struct{}
+	for name, cs := range ts.upstream {
+		if _, ok := cs.(archiveReader); ok {
+			if archives == nil {
+				archives = make(map[addr]struct{})
+			}
+			archives[name] = struct{}{}
+		}
+	}
+	if archives == nil {
+		return c
+	}
+	return archiveConjoiner{child: c, archives: archives}
+}
+
+func (c archiveConjoiner) conjoinRequired(ts tableSet) bool {
+	return c.child.conjoinRequired(ts) && len(ts.upstream)-len(c.archives) >= 2
+}
+
+func (c archiveConjoiner) chooseConjoinees(upstream []tableSpec) (conjoinees, keepers []tableSpec, err error) {
+	var archives []tableSpec
+	pruned := make([]tableSpec, 0, len(upstream))
+	for _, ts := range upstream {
+		if _, ok := c.archives[ts.name]; ok {
+			archives = append(archives, ts)
+		} else {
+			pruned = append(pruned, ts)
+		}
+	}
+	conjoinees, keepers, err = c.child.chooseConjoinees(pruned)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conjoinees, append(keepers, archives...), nil
+}
+
 // conjoin attempts to use |p| to conjoin some number of tables referenced
 // by |upstream|, allowing it to update |mm| with a new, smaller, set of tables
 // that references precisely the same set of chunks. Conjoin() may not
@@ -241,6 +290,53 @@ func conjoinTables(ctx context.Context, conjoinees []tableSpec, p tablePersister
 	return tableSpec{h, cnt}, cleanup, nil
 }
 
+// throttledPersister is a tablePersister whose ConjoinAll reads its
+// sources no faster than |lim| allows. A nil |lim| does not throttle.
+type throttledPersister struct {
+	tablePersister
+	lim *rate.Limiter
+}
+
+func (p throttledPersister) ConjoinAll(ctx context.Context, sources chunkSources, stats *Stats) (chunkSource, cleanupFunc, error) {
+	if p.lim == nil {
+		return p.tablePersister.ConjoinAll(ctx, sources, stats)
+	}
+	throttled := make(chunkSources, len(sources))
+	for i, src := range sources {
+		throttled[i] = throttledSource{src, p.lim}
+	}
+	return p.tablePersister.ConjoinAll(ctx, throttled, stats)
+}
+
+type throttledSource struct {
+	chunkSource
+	lim *rate.Limiter
+}
+
+func (s throttledSource) reader(ctx context.Context) (io.ReadCloser, uint64, error) {
+	r, sz, err := s.chunkSource.reader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &throttledReader{ReadCloser: r, ctx: ctx, lim: s.lim}, sz, nil
+}
+
+type throttledReader struct {
+	io.ReadCloser
+	ctx context.Context
+	lim *rate.Limiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > r.lim.Burst() {
+		p = p[:r.lim.Burst()]
+	}
+	if err := r.lim.WaitN(r.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return r.ReadCloser.Read(p)
+}
+
 func toSpecs(srcs chunkSources) ([]tableSpec, error) {
 	specs := make([]tableSpec, len(srcs))
 	for i, src := range srcs {
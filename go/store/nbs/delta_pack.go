@@ -0,0 +1,227 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dolthub/dolt/go/libraries/utils/delta"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// A delta pack is a table file in transit, for receivers which have chunks similar to those of the table file. It
+// holds the chunks of the table file in order, each either as the compressed chunk written to the table file, or as a
+// delta of the chunk's data against a base chunk the receiver has. The receiver rebuilds the table file from it with
+// ReadDeltaPack.
+//
+// A delta pack is the magic number followed by its records. A record is a one byte kind and the address of the chunk.
+// A compressed chunk is followed by its length as a uvarint and the compressed chunk. A delta is followed by the address
+// of its base, its length as a uvarint and the delta.
+const (
+	deltaPackMagic = "DLTPACK1"
+
+	deltaPackCompressedChunk = 0
+	deltaPackDelta           = 1
+
+	// maxDeltaPackRecordLen is the greatest length of the compressed chunk or the delta of a record
+	maxDeltaPackRecordLen = 64 * 1024 * 1024
+)
+
+// ErrInvalidDeltaPack is returned by ReadDeltaPack for a delta pack which is corrupt, or which has a delta that
+// doesn't rebuild its chunk from the base given for it.
+var ErrInvalidDeltaPack = errors.New("invalid delta pack")
+
+// DeltaPackWriter writes the delta pack of a table file to a temp file
+type DeltaPackWriter struct {
+	f      *os.File
+	ew     io.WriteCloser
+	wr     *bufio.Writer
+	enc    *Encryption
+	len    uint64
+	deltas int
+}
+
+// NewDeltaPackWriter creates a DeltaPackWriter which writes to a temp file in |tempDir|
+func NewDeltaPackWriter(tempDir string) (*DeltaPackWriter, error) {
+	return NewEncryptedDeltaPackWriter(tempDir, nil)
+}
+
+// NewEncryptedDeltaPackWriter creates a DeltaPackWriter which writes to a temp file in |tempDir|, encrypted with |enc|
+// if |enc| isn't nil. The delta pack read back from the writer is decrypted.
+func NewEncryptedDeltaPackWriter(tempDir string, enc *Encryption) (*DeltaPackWriter, error) {
+	f, err := os.CreateTemp(tempDir, "delta_pack_")
+	if err != nil {
+		return nil, err
+	}
+	ew, err := newTableFileWriter(f, enc)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	w := &DeltaPackWriter{f: f, ew: ew, wr: bufio.NewWriterSize(ew, defaultChBufferSize), enc: enc}
+	if err = w.write([]byte(deltaPackMagic)); err != nil {
+		_ = w.Remove()
+		return nil, err
+	}
+	return w, nil
+}
+
+// AddCmpChunk adds a chunk which is sent as it is written to the table file
+func (w *DeltaPackWriter) AddCmpChunk(c CompressedChunk) error {
+	if err := w.write([]byte{deltaPackCompressedChunk}, c.H[:]); err != nil {
+		return err
+	}
+	return w.writeBytes(c.FullCompressedChunk)
+}
+
+// AddDelta adds the chunk |h| as the delta |d| of its data against the data of |base|
+func (w *DeltaPackWriter) AddDelta(h, base hash.Hash, d []byte) error {
+	if err := w.write([]byte{deltaPackDelta}, h[:], base[:]); err != nil {
+		return err
+	}
+	w.deltas++
+	return w.writeBytes(d)
+}
+
+// DeltaCount returns the number of chunks added as deltas
+func (w *DeltaPackWriter) DeltaCount() int {
+	return w.deltas
+}
+
+// ContentLength returns the size of the delta pack in bytes
+func (w *DeltaPackWriter) ContentLength() uint64 {
+	return w.len
+}
+
+// Finish flushes the delta pack to its temp file
+func (w *DeltaPackWriter) Finish() error {
+	if err := w.wr.Flush(); err != nil {
+		return err
+	}
+	return w.ew.Close()
+}
+
+// Reader returns a reader of the delta pack, once it's finished
+func (w *DeltaPackWriter) Reader() (io.ReadCloser, error) {
+	return openTableFileReader(w.f.Name(), w.enc)
+}
+
+// Remove removes the temp file of the delta pack
+func (w *DeltaPackWriter) Remove() error {
+	_ = w.f.Close()
+	return os.Remove(w.f.Name())
+}
+
+func (w *DeltaPackWriter) writeBytes(b []byte) error {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+	return w.write(lenBuf[:n], b)
+}
+
+func (w *DeltaPackWriter) write(bs ...[]byte) error {
+	for _, b := range bs {
+		n, err := w.wr.Write(b)
+		w.len += uint64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadDeltaPack reads the delta pack from |r| and adds its chunks to |wr| in order, which rebuilds the table file it
+// was made from. The data of the bases of its deltas is read with |getBase|. Each chunk rebuilt from a delta is
+// checked against its address.
+func ReadDeltaPack(r io.Reader, getBase func(hash.Hash) ([]byte, error), wr *CmpChunkTableWriter) error {
+	rd := bufio.NewReaderSize(r, defaultChBufferSize)
+
+	magic := make([]byte, len(deltaPackMagic))
+	if _, err := io.ReadFull(rd, magic); err != nil || string(magic) != deltaPackMagic {
+		return fmt.Errorf("%w: bad magic number", ErrInvalidDeltaPack)
+	}
+
+	for {
+		kind, err := rd.ReadByte()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var h, base hash.Hash
+		if _, err = io.ReadFull(rd, h[:]); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDeltaPack, err)
+		}
+		if kind == deltaPackDelta {
+			if _, err = io.ReadFull(rd, base[:]); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidDeltaPack, err)
+			}
+		}
+		b, err := readDeltaPackBytes(rd)
+		if err != nil {
+			return err
+		}
+
+		var cmp CompressedChunk
+		switch kind {
+		case deltaPackCompressedChunk:
+			cmp, err = NewCompressedChunk(h, b)
+			if err != nil {
+				return fmt.Errorf("%w: chunk %s: %v", ErrInvalidDeltaPack, h.String(), err)
+			}
+		case deltaPackDelta:
+			baseData, err := getBase(base)
+			if err != nil {
+				return err
+			}
+			data, err := delta.Decode(baseData, b)
+			if err != nil {
+				return fmt.Errorf("%w: chunk %s: %v", ErrInvalidDeltaPack, h.String(), err)
+			}
+			if hash.Of(data) != h {
+				return fmt.Errorf("%w: the delta for chunk %s against %s doesn't rebuild it", ErrInvalidDeltaPack, h.String(), base.String())
+			}
+			cmp = ChunkToCompressedChunk(chunks.NewChunkWithHash(h, data))
+		default:
+			return fmt.Errorf("%w: unknown record kind %d", ErrInvalidDeltaPack, kind)
+		}
+
+		if err = wr.AddCmpChunk(cmp); err != nil {
+			return err
+		}
+	}
+}
+
+func readDeltaPackBytes(rd *bufio.Reader) ([]byte, error) {
+	l, err := binary.ReadUvarint(rd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeltaPack, err)
+	}
+	if l > maxDeltaPackRecordLen {
+		return nil, fmt.Errorf("%w: record of %d bytes", ErrInvalidDeltaPack, l)
+	}
+	b := make([]byte, l)
+	if _, err = io.ReadFull(rd, b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeltaPack, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/delta"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestDeltaPack(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	bases := make(map[hash.Hash][]byte)
+	var chnks []chunks.Chunk
+	var baseOf []hash.Hash
+	for i := 0; i < 32; i++ {
+		data := make([]byte, 4096)
+		rng.Read(data)
+		base := chunks.NewChunk(data)
+		bases[base.Hash()] = base.Data()
+
+		edited := append([]byte{}, data...)
+		edited[rng.Intn(len(edited))]++
+		chnks = append(chnks, chunks.NewChunk(edited))
+		baseOf = append(baseOf, base.Hash())
+	}
+
+	// the table file the delta pack is made from
+	wr, err := NewCmpChunkTableWriter("")
+	require.NoError(t, err)
+	defer wr.Remove()
+	pack, err := NewDeltaPackWriter("")
+	require.NoError(t, err)
+	defer pack.Remove()
+	for i, c := range chnks {
+		cmp := ChunkToCompressedChunk(c)
+		require.NoError(t, wr.AddCmpChunk(cmp))
+		if i%2 == 0 {
+			require.NoError(t, pack.AddDelta(c.Hash(), baseOf[i], delta.Encode(bases[baseOf[i]], c.Data())))
+		} else {
+			require.NoError(t, pack.AddCmpChunk(cmp))
+		}
+	}
+	fileId, err := wr.Finish()
+	require.NoError(t, err)
+	require.NoError(t, pack.Finish())
+	assert.Equal(t, len(chnks)/2, pack.DeltaCount())
+	assert.Less(t, pack.ContentLength(), wr.ContentLength())
+
+	getBase := func(h hash.Hash) ([]byte, error) {
+		if data, ok := bases[h]; ok {
+			return data, nil
+		}
+		return nil, errors.New("base not found")
+	}
+	readPack := func(t *testing.T, pack []byte) (*CmpChunkTableWriter, error) {
+		rebuilt, err := NewCmpChunkTableWriter("")
+		require.NoError(t, err)
+		t.Cleanup(func() { rebuilt.Remove() })
+		return rebuilt, ReadDeltaPack(bytes.NewReader(pack), getBase, rebuilt)
+	}
+
+	rd, err := pack.Reader()
+	require.NoError(t, err)
+	packData, err := io.ReadAll(rd)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close())
+	assert.Equal(t, pack.ContentLength(), uint64(len(packData)))
+
+	t.Run("RebuildsTableFile", func(t *testing.T) {
+		rebuilt, err := readPack(t, packData)
+		require.NoError(t, err)
+		rebuiltId, err := rebuilt.Finish()
+		require.NoError(t, err)
+		assert.Equal(t, fileId, rebuiltId)
+		assert.Equal(t, wr.GetMD5(), rebuilt.GetMD5())
+	})
+
+	t.Run("WrongBase", func(t *testing.T) {
+		// a delta against a different base rebuilds a different chunk, which doesn't match its address
+		wrong, err := NewDeltaPackWriter("")
+		require.NoError(t, err)
+		defer wrong.Remove()
+		require.NoError(t, wrong.AddDelta(chnks[0].Hash(), baseOf[1], delta.Encode(bases[baseOf[0]], chnks[0].Data())))
+		require.NoError(t, wrong.Finish())
+		rd, err := wrong.Reader()
+		require.NoError(t, err)
+		defer rd.Close()
+		data, err := io.ReadAll(rd)
+		require.NoError(t, err)
+
+		_, err = readPack(t, data)
+		assert.ErrorIs(t, err, ErrInvalidDeltaPack)
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		_, err := readPack(t, packData[:len(packData)-10])
+		assert.ErrorIs(t, err, ErrInvalidDeltaPack)
+		_, err = readPack(t, []byte("not a delta pack"))
+		assert.ErrorIs(t, err, ErrInvalidDeltaPack)
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression dictionaries let zstd compress small, similar chunks much better than it can
+// compress each of them on its own, since the content they share is found in the dictionary
+// rather than repeated in every chunk. The chunks of one table's rows are alike, so a
+// dictionary is trained for each group of chunks from the chunks themselves.
+//
+// Dictionaries are trained with zstd's dictionary builder. The dictionary's content is a
+// sample taken evenly from across all the chunks it will compress, and its entropy tables
+// are fit to up to |dictMaxSamples| of those chunks.
+
+const (
+	// dictMaxSize is the maximum size of a dictionary's content
+	dictMaxSize = 64 * 1024
+
+	// dictMinSize is the smallest dictionary content zstd can build a dictionary from
+	dictMinSize = 8
+
+	// dictMaxSamples is the maximum number of chunks a dictionary's tables are fit to
+	dictMaxSamples = 1024
+
+	// dictID is the id frames compressed with a dictionary are given. Each group of
+	// chunks has a single dictionary, so the id doesn't need to tell them apart.
+	dictID = 1
+)
+
+// trainDictionary builds a dictionary for |samples|. It returns a nil dictionary if there
+// is too little data to build one from, or if the data has too little in common.
+func trainDictionary(samples [][]byte) []byte {
+	if len(samples) == 0 {
+		return nil
+	}
+	var total int
+	for _, s := range samples {
+		total += len(s)
+	}
+
+	// a dictionary much larger than the data it is built from costs more than it saves
+	sz := dictMaxSize
+	if total/16 < sz {
+		sz = total / 16
+	}
+	if sz < dictMinSize {
+		return nil
+	}
+
+	// take every |stride|th sample, where |stride| is the number of samples
+	// there are for each one that fits in the dictionary's content
+	fits := sz / (total / len(samples))
+	if fits < 1 {
+		fits = 1
+	}
+	stride := len(samples) / fits
+	if stride < 1 {
+		stride = 1
+	}
+	history := make([]byte, 0, sz)
+	for i := 0; i < len(samples) && len(history) < sz; i += stride {
+		s := samples[i]
+		if len(s) > sz-len(history) {
+			s = s[:sz-len(history)]
+		}
+		history = append(history, s...)
+	}
+	if len(history) < dictMinSize {
+		return nil
+	}
+
+	contents := samples
+	if len(contents) > dictMaxSamples {
+		contents = make([][]byte, 0, dictMaxSamples)
+		step := len(samples) / dictMaxSamples
+		for i := 0; i < len(samples) && len(contents) < dictMaxSamples; i += step {
+			contents = append(contents, samples[i])
+		}
+	}
+
+	dict, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       dictID,
+		Contents: contents,
+		History:  history,
+		// zstd's default repeat offsets
+		Offsets: [3]int{1, 4, 8},
+		Level:   zstd.SpeedBestCompression,
+	})
+	if err != nil {
+		// the builder fails for samples which have too little
+		// in common for a dictionary to help compress them
+		return nil
+	}
+	return dict
+}
+
+// newDictEncoder returns an encoder that compresses with |dict|.
+// A nil |dict| compresses without a dictionary.
+func newDictEncoder(dict []byte) (*zstd.Encoder, error) {
+	opts := []zstd.EOption{zstd.WithEncoderLevel(zstd.SpeedBestCompression)}
+	if len(dict) > 0 {
+		opts = append(opts, zstd.WithEncoderDict(dict))
+	}
+	return zstd.NewWriter(nil, opts...)
+}
+
+// newDictDecoder returns a decoder for data compressed with |dict|.
+func newDictDecoder(dict []byte) (*zstd.Decoder, error) {
+	var opts []zstd.DOption
+	if len(dict) > 0 {
+		opts = append(opts, zstd.WithDecoderDicts(dict))
+	}
+	return zstd.NewReader(nil, opts...)
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeDictTestSamples(n int) [][]byte {
+	samples := make([][]byte, n)
+	for i := range samples {
+		samples[i] = []byte(fmt.Sprintf("{\"id\": %d, \"name\": \"customer %d\", \"status\": \"active\", \"region\": \"us-west-%d\"}", i, i, i%4))
+	}
+	return samples
+}
+
+func TestTrainDictionary(t *testing.T) {
+	assert.Nil(t, trainDictionary(nil))
+	assert.Nil(t, trainDictionary([][]byte{[]byte("tiny")}))
+
+	// more samples than a dictionary is fit to
+	dict := trainDictionary(makeDictTestSamples(4 * dictMaxSamples))
+	assert.NotEmpty(t, dict)
+
+	big := make([][]byte, dictMaxSamples)
+	for i := range big {
+		big[i] = make([]byte, 4096)
+		big[i][0] = byte(i)
+	}
+	dict = trainDictionary(big)
+	// the content is capped, the tables add a little more
+	assert.Less(t, len(dict), dictMaxSize+1024)
+}
+
+func TestDictionaryCompression(t *testing.T) {
+	samples := makeDictTestSamples(dictMaxSamples)
+	dict := trainDictionary(samples)
+
+	enc, err := newDictEncoder(dict)
+	require.NoError(t, err)
+	defer enc.Close()
+	dec, err := newDictDecoder(dict)
+	require.NoError(t, err)
+	defer dec.Close()
+	plain, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	require.NoError(t, err)
+	defer plain.Close()
+
+	var withDict, without int
+	for _, s := range samples {
+		cmp := enc.EncodeAll(s, nil)
+		withDict += len(cmp)
+		without += len(plain.EncodeAll(s, nil))
+		act, err := dec.DecodeAll(cmp, nil)
+		require.NoError(t, err)
+		require.Equal(t, s, act)
+	}
+	assert.Less(t, withDict, without/2)
+}
@@ -0,0 +1,424 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// A local store with an Encryption encrypts the table files and chunk journal it writes with AES-GCM.
+//
+// Table files are encrypted whole. An encrypted table file is a header, of the magic number, the id of the key it's
+// encrypted with and a random nonce for the file, followed by the table file in blocks of encBlockSize bytes, each
+// sealed on its own so that the table file can be read at any offset. A block is stored as its nonce, its ciphertext
+// and its tag. The nonce of the file and the index of the block are authenticated with it, so that blocks can't be
+// moved within or between files.
+//
+// The chunk journal is appended to in place, so it's encrypted record by record instead. An encrypted journal begins
+// with an encryption record, holding the id of its key, and the payload of each of its chunk records is the chunk
+// sealed with the chunk's address. Root hash records aren't encrypted. The lookups in the records of its index file
+// are sealed with the root hash of their record.
+//
+// The temp files that table files and delta packs are written to before they're added to an encrypted store, or
+// after they're read from one, are encrypted like table files.
+//
+// Table files and journals which aren't encrypted remain readable by a store with an Encryption, so that encryption
+// can be enabled for an existing database. Its existing data is encrypted as it's rewritten by garbage collection.
+const (
+	encFileMagic      = "DOLTENC1"
+	encFileNonceSize  = 16
+	encFileHeaderSize = len(encFileMagic) + addrSize + encFileNonceSize
+
+	encBlockSize = 1 << 12
+)
+
+var (
+	// ErrNoEncryptionKey is returned when opening an encrypted table file or journal without an Encryption.
+	ErrNoEncryptionKey = errors.New("database is encrypted, but no encryption key was given")
+	// ErrWrongEncryptionKey is returned when opening a table file or journal encrypted with another key.
+	ErrWrongEncryptionKey = errors.New("database is encrypted with a different key than the one given")
+	// ErrEncryptedChunkLocations is returned for the locations of chunks in encrypted files, which can't be read from
+	// the files directly.
+	ErrEncryptedChunkLocations = errors.New("chunk locations are not available for encrypted databases")
+)
+
+// Encryption encrypts the data of a local NomsBlockStore at rest.
+type Encryption struct {
+	aead cipher.AEAD
+	// id identifies the key in the files encrypted with it
+	id addr
+}
+
+// NewEncryption returns an Encryption with the AES-256 key |key|.
+func NewEncryption(key []byte) (*Encryption, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	e := &Encryption{aead: aead}
+	sum := sha256.Sum256(append([]byte("dolt encryption key id:"), key...))
+	copy(e.id[:], sum[:])
+	return e, nil
+}
+
+// EncryptedStore is implemented by chunk stores which can encrypt their data at rest.
+type EncryptedStore interface {
+	// Encryption returns the Encryption of the store, or nil if its data isn't encrypted.
+	Encryption() *Encryption
+}
+
+// sameEncryption returns true if data encrypted with |a| is encrypted with |b|.
+func sameEncryption(a, b *Encryption) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.id == b.id
+}
+
+// overhead is the number of bytes sealing adds to its plaintext.
+func (e *Encryption) overhead() int {
+	return e.aead.NonceSize() + e.aead.Overhead()
+}
+
+// seal appends the nonce and ciphertext of |plain|, authenticated with |aad|, to |dst|.
+func (e *Encryption) seal(dst, plain, aad []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, e.aead.NonceSize())...)
+	nonce := dst[n:]
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+	return e.aead.Seal(dst, nonce, plain, aad)
+}
+
+// open appends the plaintext of |sealed|, authenticated with |aad|, to |dst|.
+func (e *Encryption) open(dst, sealed, aad []byte) ([]byte, error) {
+	ns := e.aead.NonceSize()
+	if len(sealed) < e.overhead() {
+		return nil, errors.New("encrypted data is truncated")
+	}
+	return e.aead.Open(dst, sealed[:ns], sealed[ns:], aad)
+}
+
+// storedBlockSize is the size of a full block of an encrypted table file
+func (e *Encryption) storedBlockSize() int64 {
+	return int64(encBlockSize + e.overhead())
+}
+
+// isEncryptedFile returns true if the file |r| of size |sz| is an encrypted table file.
+func isEncryptedFile(r io.ReaderAt, sz int64) (bool, error) {
+	if sz < int64(encFileHeaderSize) {
+		return false, nil
+	}
+	buf := make([]byte, len(encFileMagic))
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		return false, err
+	}
+	return string(buf) == encFileMagic, nil
+}
+
+// encryptedReaderAt reads an encrypted table file.
+type encryptedReaderAt struct {
+	r     io.ReaderAt
+	enc   *Encryption
+	nonce []byte
+	// size is the size of the decrypted file
+	size int64
+}
+
+// openEncryptedFile returns a reader of the decrypted contents of the encrypted table file |r| of size |sz|, which must
+// be encrypted with |enc|.
+func openEncryptedFile(r io.ReaderAt, sz int64, enc *Encryption) (*encryptedReaderAt, error) {
+	if enc == nil {
+		return nil, ErrNoEncryptionKey
+	}
+	hdr := make([]byte, encFileHeaderSize)
+	if _, err := r.ReadAt(hdr, 0); err != nil {
+		return nil, err
+	}
+	if string(hdr[:len(encFileMagic)]) != encFileMagic {
+		return nil, errors.New("not an encrypted table file")
+	}
+	var id addr
+	copy(id[:], hdr[len(encFileMagic):])
+	if id != enc.id {
+		return nil, ErrWrongEncryptionKey
+	}
+
+	stored := enc.storedBlockSize()
+	n := sz - int64(encFileHeaderSize)
+	size := (n / stored) * encBlockSize
+	if rem := n % stored; rem > 0 {
+		if rem <= int64(enc.overhead()) {
+			return nil, errors.New("encrypted table file is truncated")
+		}
+		size += rem - int64(enc.overhead())
+	}
+	return &encryptedReaderAt{
+		r:     r,
+		enc:   enc,
+		nonce: hdr[len(encFileMagic)+addrSize:],
+		size:  size,
+	}, nil
+}
+
+func (er *encryptedReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	var stored, plain []byte
+	for n < len(p) {
+		if off >= er.size {
+			return n, io.EOF
+		}
+		blk := off / encBlockSize
+		sz := er.size - blk*encBlockSize
+		if sz > encBlockSize {
+			sz = encBlockSize
+		}
+		stored = append(stored[:0], make([]byte, sz+int64(er.enc.overhead()))...)
+		if _, err = er.r.ReadAt(stored, int64(encFileHeaderSize)+blk*er.enc.storedBlockSize()); err != nil {
+			return n, err
+		}
+		if plain, err = er.enc.open(plain[:0], stored, er.blockAAD(blk)); err != nil {
+			return n, fmt.Errorf("failed to decrypt table file: %w", err)
+		}
+		c := copy(p[n:], plain[off-blk*encBlockSize:])
+		n += c
+		off += int64(c)
+	}
+	return n, nil
+}
+
+func (er *encryptedReaderAt) blockAAD(blk int64) []byte {
+	return blockAAD(er.nonce, blk)
+}
+
+func blockAAD(nonce []byte, blk int64) []byte {
+	aad := make([]byte, len(nonce)+uint64Size)
+	copy(aad, nonce)
+	binary.BigEndian.PutUint64(aad[len(nonce):], uint64(blk))
+	return aad
+}
+
+// encryptedFileWriter encrypts a table file as it's written.
+type encryptedFileWriter struct {
+	w     io.Writer
+	enc   *Encryption
+	nonce []byte
+	buf   []byte
+	out   []byte
+	blk   int64
+}
+
+var _ io.WriteCloser = &encryptedFileWriter{}
+
+// newEncryptedFileWriter writes the header of an encrypted table file to |w|, and returns a writer which writes the
+// table file encrypted with |enc| to |w|. Close must be called to write the last block of the file.
+func newEncryptedFileWriter(w io.Writer, enc *Encryption) (*encryptedFileWriter, error) {
+	hdr := make([]byte, encFileHeaderSize)
+	copy(hdr, encFileMagic)
+	copy(hdr[len(encFileMagic):], enc.id[:])
+	nonce := hdr[len(encFileMagic)+addrSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return &encryptedFileWriter{
+		w:     w,
+		enc:   enc,
+		nonce: nonce,
+		buf:   make([]byte, 0, encBlockSize),
+	}, nil
+}
+
+func (ew *encryptedFileWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		c := copy(ew.buf[len(ew.buf):encBlockSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+c]
+		p = p[c:]
+		n += c
+		if len(ew.buf) == encBlockSize {
+			if err = ew.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Close writes the last block of the file. It doesn't close the underlying writer.
+func (ew *encryptedFileWriter) Close() error {
+	if len(ew.buf) == 0 {
+		return nil
+	}
+	return ew.flush()
+}
+
+func (ew *encryptedFileWriter) flush() error {
+	ew.out = ew.enc.seal(ew.out[:0], ew.buf, blockAAD(ew.nonce, ew.blk))
+	if _, err := ew.w.Write(ew.out); err != nil {
+		return err
+	}
+	ew.blk++
+	ew.buf = ew.buf[:0]
+	return nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// newTableFileWriter returns a writer of a table file to |w|, which encrypts it with |enc| if it isn't nil. Closing
+// the writer finishes the table file, but doesn't close |w|.
+func newTableFileWriter(w io.Writer, enc *Encryption) (io.WriteCloser, error) {
+	if enc == nil {
+		return nopWriteCloser{w}, nil
+	}
+	return newEncryptedFileWriter(w, enc)
+}
+
+// encryptedFileReader reads the decrypted contents of an encrypted table file from the start.
+type encryptedFileReader struct {
+	*io.SectionReader
+	io.Closer
+}
+
+// openTableFileReader opens the table file at |path| to be read from the start, decrypting it with |enc| if it isn't
+// nil.
+func openTableFileReader(path string, enc *Encryption) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return f, nil
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	r, err := openEncryptedFile(f, fi.Size(), enc)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return encryptedFileReader{io.NewSectionReader(r, 0, r.size), f}, nil
+}
+
+// readEncryptionRecord returns the key id of the encryption record at the start of |journal|, and whether it has one.
+func readEncryptionRecord(journal io.ReaderAt) (id addr, ok bool, err error) {
+	buf := make([]byte, encryptionRecordSize())
+	if _, err = journal.ReadAt(buf, 0); errors.Is(err, io.EOF) {
+		return id, false, nil
+	} else if err != nil {
+		return id, false, err
+	}
+	if readUint32(buf) != uint32(len(buf)) || !validateJournalRecord(buf) {
+		return id, false, nil
+	}
+	rec, err := readJournalRecord(buf)
+	if err != nil || rec.kind != encryptionJournalRecKind {
+		return id, false, nil
+	}
+	return rec.address, true, nil
+}
+
+// encryptJournal writes the chunk journal read from |r| to |w|, with its chunks encrypted with |enc|. A journal which
+// is already encrypted with |enc| is copied as it is.
+func encryptJournal(r io.Reader, w io.Writer, enc *Encryption) error {
+	rdr := bufio.NewReaderSize(r, journalWriterBuffSize)
+	if buf, err := rdr.Peek(encryptionRecordSize()); err == nil {
+		if id, ok, err := readEncryptionRecord(bytes.NewReader(buf)); err != nil {
+			return err
+		} else if ok && id != enc.id {
+			return ErrWrongEncryptionKey
+		} else if ok {
+			_, err = io.Copy(w, rdr)
+			return err
+		}
+	}
+
+	wr := bufio.NewWriterSize(w, journalWriterBuffSize)
+	out := make([]byte, encryptionRecordSize())
+	writeEncryptionRecord(out, enc.id)
+	if _, err := wr.Write(out); err != nil {
+		return err
+	}
+	for {
+		buf, err := rdr.Peek(uint32Size)
+		if err != nil {
+			break
+		}
+		l := readUint32(buf)
+		if l > journalRecMaxSz {
+			break
+		} else if buf, err = rdr.Peek(int(l)); err != nil {
+			break
+		}
+		if !validateJournalRecord(buf) {
+			break // the end of the journal
+		}
+		rec, err := readJournalRecord(buf)
+		if err != nil {
+			return err
+		}
+		switch rec.kind {
+		case chunkJournalRecKind:
+			cc := CompressedChunk{H: hash.Hash(rec.address)}
+			cc.FullCompressedChunk = enc.seal(nil, rec.payload, rec.address[:])
+			sz, _ := chunkRecordSize(cc)
+			out = append(out[:0], make([]byte, sz)...)
+			writeChunkRecord(out, cc)
+		case rootHashJournalRecKind:
+			out = append(out[:0], buf...)
+		default:
+			return fmt.Errorf("unknown journal record kind (%d)", rec.kind)
+		}
+		if _, err = wr.Write(out); err != nil {
+			return err
+		}
+		if _, err = rdr.Discard(len(buf)); err != nil {
+			return err
+		}
+	}
+	return wr.Flush()
+}
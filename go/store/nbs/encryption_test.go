@@ -0,0 +1,299 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func makeTestEncryption(t *testing.T) *Encryption {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	enc, err := NewEncryption(key)
+	require.NoError(t, err)
+	return enc
+}
+
+func TestEncryptedLocalStoreSuite(t *testing.T) {
+	enc := makeTestEncryption(t)
+	fn := func(ctx context.Context, dir string) (*NomsBlockStore, error) {
+		nbf := constants.FormatDefaultString
+		qp := NewUnlimitedMemQuotaProvider()
+		return NewLocalStoreWithEncryption(ctx, nbf, dir, testMemTableSize, qp, enc)
+	}
+	suite.Run(t, &BlockStoreSuite{factory: fn})
+}
+
+func TestEncryptedChunkJournalBlockStoreSuite(t *testing.T) {
+	enc := makeTestEncryption(t)
+	fn := func(ctx context.Context, dir string) (*NomsBlockStore, error) {
+		nbf := constants.FormatDefaultString
+		qp := NewUnlimitedMemQuotaProvider()
+		return NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, qp, enc)
+	}
+	suite.Run(t, &BlockStoreSuite{
+		factory:        fn,
+		skipInterloper: true,
+	})
+}
+
+func TestEncryptedFile(t *testing.T) {
+	enc := makeTestEncryption(t)
+	for _, sz := range []int{1, encBlockSize - 1, encBlockSize, encBlockSize + 1, 5*encBlockSize + 123} {
+		data := make([]byte, sz)
+		rand.Read(data)
+
+		var buf bytes.Buffer
+		w, err := newEncryptedFileWriter(&buf, enc)
+		require.NoError(t, err)
+		// write in uneven pieces
+		for p := data; len(p) > 0; {
+			n := rand.Intn(2*encBlockSize) + 1
+			if n > len(p) {
+				n = len(p)
+			}
+			_, err = w.Write(p[:n])
+			require.NoError(t, err)
+			p = p[n:]
+		}
+		require.NoError(t, w.Close())
+		if sz >= 16 {
+			assert.False(t, bytes.Contains(buf.Bytes(), data[:16]))
+		}
+
+		stored := bytes.NewReader(buf.Bytes())
+		ok, err := isEncryptedFile(stored, stored.Size())
+		require.NoError(t, err)
+		assert.True(t, ok)
+		r, err := openEncryptedFile(stored, stored.Size(), enc)
+		require.NoError(t, err)
+		assert.Equal(t, int64(sz), r.size)
+
+		all, err := io.ReadAll(io.NewSectionReader(r, 0, r.size))
+		require.NoError(t, err)
+		assert.Equal(t, data, all)
+		for i := 0; i < 16; i++ {
+			off := rand.Intn(sz)
+			p := make([]byte, rand.Intn(sz-off)+1)
+			n, err := r.ReadAt(p, int64(off))
+			require.NoError(t, err)
+			assert.Equal(t, len(p), n)
+			assert.Equal(t, data[off:off+n], p)
+		}
+		_, err = r.ReadAt(make([]byte, 1), int64(sz))
+		assert.Equal(t, io.EOF, err)
+
+		// the file can't be read with another key
+		_, err = openEncryptedFile(stored, stored.Size(), makeTestEncryption(t))
+		assert.ErrorIs(t, err, ErrWrongEncryptionKey)
+		_, err = openEncryptedFile(stored, stored.Size(), nil)
+		assert.ErrorIs(t, err, ErrNoEncryptionKey)
+
+		// tampering with any block is detected
+		b := buf.Bytes()
+		b[len(b)-1] ^= 1
+		_, err = io.ReadAll(io.NewSectionReader(r, 0, r.size))
+		assert.Error(t, err)
+	}
+}
+
+func TestEncryptedStoreAtRest(t *testing.T) {
+	ctx := context.Background()
+	nbf := constants.FormatDefaultString
+	enc := makeTestEncryption(t)
+	secret := []byte("the quick brown fox jumps over the lazy dog, which is not encrypted at rest")
+
+	for _, journal := range []bool{false, true} {
+		dir, err := os.MkdirTemp("", "")
+		require.NoError(t, err)
+		defer file.RemoveAll(dir)
+		open := func(enc *Encryption) (*NomsBlockStore, error) {
+			q := NewUnlimitedMemQuotaProvider()
+			if journal {
+				return NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, q, enc)
+			}
+			return NewLocalStoreWithEncryption(ctx, nbf, dir, testMemTableSize, q, enc)
+		}
+
+		st, err := open(enc)
+		require.NoError(t, err)
+		c := chunks.NewChunk(secret)
+		require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+		root, err := st.Root(ctx)
+		require.NoError(t, err)
+		ok, err := st.Commit(ctx, c.Hash(), root)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, st.Close())
+
+		// no file of the store holds the chunk's data
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+			require.NoError(t, err)
+			assert.False(t, bytes.Contains(b, secret[:16]), "file %s holds plaintext", e.Name())
+		}
+
+		st, err = open(enc)
+		require.NoError(t, err)
+		act, err := st.Get(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, secret, act.Data())
+		_, err = st.GetChunkLocations(hash.NewHashSet(c.Hash()))
+		assert.ErrorIs(t, err, ErrEncryptedChunkLocations)
+		require.NoError(t, st.Close())
+
+		_, err = open(makeTestEncryption(t))
+		assert.ErrorIs(t, err, ErrWrongEncryptionKey)
+		_, err = open(nil)
+		assert.ErrorIs(t, err, ErrNoEncryptionKey)
+	}
+}
+
+func TestEncryptJournal(t *testing.T) {
+	ctx := context.Background()
+	j := makeTestChunkJournal(t)
+	mt, data := randomMemTable(256)
+	_, err := j.Persist(ctx, mt, emptyChunkSource{}, &Stats{})
+	require.NoError(t, err)
+	rdr, _, err := j.wr.snapshot()
+	require.NoError(t, err)
+	defer rdr.Close()
+
+	enc := makeTestEncryption(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, chunkJournalName)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, encryptJournal(rdr, f, enc))
+	require.NoError(t, f.Close())
+
+	wr, ok, err := openJournalWriter(ctx, path)
+	require.NoError(t, err)
+	require.True(t, ok)
+	defer wr.Close()
+	wr.enc = enc
+	_, err = wr.bootstrapJournal(ctx)
+	require.NoError(t, err)
+	assert.True(t, wr.encrypted)
+	for h, ch := range data {
+		cc, err := wr.getCompressedChunk(h)
+		require.NoError(t, err)
+		act, err := cc.ToChunk()
+		require.NoError(t, err)
+		assert.Equal(t, ch.Data(), act.Data())
+	}
+}
+
+func TestEncryptedJournalIndex(t *testing.T) {
+	ctx := context.Background()
+	enc := makeTestEncryption(t)
+	path := newTestFilePath(t)
+	j, err := createJournalWriter(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, j.writeEncryptionRecord(enc))
+	_, err = j.bootstrapJournal(ctx)
+	require.NoError(t, err)
+
+	data := randomCompressedChunks(256)
+	for _, cc := range data {
+		require.NoError(t, j.writeCompressedChunk(cc))
+	}
+	last := hash.Of([]byte("root"))
+	o := j.offset()
+	require.NoError(t, j.commitRootHash(last))
+	require.NoError(t, j.flushIndexRecord(last, o))
+	require.NoError(t, j.Close())
+
+	// the index file doesn't hold the addresses of the journal's chunks
+	idx, err := os.ReadFile(filepath.Join(filepath.Dir(path), journalIndexFileName))
+	require.NoError(t, err)
+	require.NotEmpty(t, idx)
+	for a := range data {
+		assert.False(t, bytes.Contains(idx, a[:]), "index file holds chunk address %s", a.String())
+	}
+
+	// the journal is bootstrapped from its index
+	wr, ok, err := openJournalWriter(ctx, path)
+	require.NoError(t, err)
+	require.True(t, ok)
+	defer wr.Close()
+	wr.enc = enc
+	root, err := wr.bootstrapJournal(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, last, root)
+	assert.Equal(t, o, wr.indexed)
+	for a, cc := range data {
+		act, err := wr.getCompressedChunk(a)
+		require.NoError(t, err)
+		assert.Equal(t, cc, act)
+	}
+}
+
+func TestEncryptedCmpChunkTableWriter(t *testing.T) {
+	enc := makeTestEncryption(t)
+	secret := []byte("the quick brown fox jumps over the lazy dog, which is not encrypted at rest")
+	dir := t.TempDir()
+
+	write := func(enc *Encryption) *CmpChunkTableWriter {
+		tw, err := NewEncryptedCmpChunkTableWriter(dir, enc)
+		require.NoError(t, err)
+		require.NoError(t, tw.AddCmpChunk(ChunkToCompressedChunk(chunks.NewChunk(secret))))
+		_, err = tw.Finish()
+		require.NoError(t, err)
+		return tw
+	}
+	readAll := func(tw *CmpChunkTableWriter) []byte {
+		r, err := tw.Reader()
+		require.NoError(t, err)
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return b
+	}
+
+	plain, encrypted := write(nil), write(enc)
+	defer plain.Remove()
+	defer encrypted.Remove()
+	exp := readAll(plain)
+	assert.Equal(t, uint64(len(exp)), encrypted.ContentLength())
+	assert.Equal(t, plain.GetMD5(), encrypted.GetMD5())
+
+	// the temp file is encrypted, but the table file is read back decrypted
+	stored, err := os.ReadFile(encrypted.path)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(stored, secret[:16]))
+	assert.Equal(t, exp, readAll(encrypted))
+	var buf bytes.Buffer
+	require.NoError(t, encrypted.Flush(&buf))
+	assert.Equal(t, exp, buf.Bytes())
+}
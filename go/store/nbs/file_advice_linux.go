@@ -0,0 +1,37 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// fadvise gives |advice| about the whole of |f| to the kernel, which sets its readahead for pread.
+func fadvise(f *os.File, advice ReadAdvice) error {
+	var a int
+	switch advice {
+	case ReadAdviceRandom:
+		a = unix.FADV_RANDOM
+	case ReadAdviceSequential:
+		a = unix.FADV_SEQUENTIAL
+	case ReadAdviceWillNeed:
+		a = unix.FADV_WILLNEED
+	default:
+		return nil
+	}
+	return unix.Fadvise(int(f.Fd()), 0, 0, a)
+}
@@ -35,18 +35,21 @@ import (
 	"time"
 
 	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/util/tempfiles"
 )
 
 const tempTablePrefix = "nbs_table_"
 
-func newFSTablePersister(dir string, q MemoryQuotaProvider) tablePersister {
-	return &fsTablePersister{dir, q, sync.Mutex{}, nil, make(map[string]struct{})}
+func newFSTablePersister(dir string, q MemoryQuotaProvider, enc *Encryption) tablePersister {
+	return &fsTablePersister{dir, q, enc, sync.Mutex{}, nil, make(map[string]struct{})}
 }
 
 type fsTablePersister struct {
 	dir string
 	q   MemoryQuotaProvider
+	// enc encrypts the table files written by the persister, if it isn't nil
+	enc *Encryption
 
 	// Protects the following two maps.
 	removeMu sync.Mutex
@@ -67,7 +70,7 @@ var _ tablePersister = &fsTablePersister{}
 var _ tableFilePersister = &fsTablePersister{}
 
 func (ftp *fsTablePersister) Open(ctx context.Context, name addr, chunkCount uint32, stats *Stats) (chunkSource, error) {
-	return newFileTableReader(ctx, ftp.dir, name, chunkCount, ftp.q)
+	return newFileTableReader(ctx, ftp.dir, name, chunkCount, ftp.q, ftp.enc)
 }
 
 func (ftp *fsTablePersister) Exists(ctx context.Context, name addr, chunkCount uint32, stats *Stats) (bool, error) {
@@ -127,7 +130,11 @@ func (ftp *fsTablePersister) CopyTableFile(ctx context.Context, r io.ReadCloser,
 			}
 		}()
 
-		_, err = io.Copy(temp, r)
+		if ftp.enc != nil && fileId == chunks.JournalFileID {
+			err = encryptJournal(r, temp, ftp.enc)
+		} else {
+			err = ftp.copyTableFile(temp, r)
+		}
 		if err != nil {
 			return "", cleanup, err
 		}
@@ -148,7 +155,47 @@ func (ftp *fsTablePersister) CopyTableFile(ctx context.Context, r io.ReadCloser,
 	return file.Rename(tn, path)
 }
 
+// copyTableFile copies the table file read from |r| to |w|, encrypting it if |ftp| is encrypted.
+func (ftp *fsTablePersister) copyTableFile(w io.Writer, r io.Reader) error {
+	wr, err := newTableFileWriter(w, ftp.enc)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(wr, r); err != nil {
+		return err
+	}
+	return wr.Close()
+}
+
+// LinkTableFile adds the table file at |path| as |fileId| by hard linking it, so that the two share their data on
+// disk. It's not an error if |fileId| already exists. Table files can't be linked into an encrypted persister, as
+// they're copied to be encrypted.
+func (ftp *fsTablePersister) LinkTableFile(ctx context.Context, path, fileId string) error {
+	if ftp.enc != nil {
+		return errors.New("cannot link a table file into an encrypted database")
+	}
+	dest := filepath.Join(ftp.dir, fileId)
+	ftp.removeMu.Lock()
+	defer ftp.removeMu.Unlock()
+	if ftp.toKeep != nil {
+		ftp.toKeep[filepath.Clean(dest)] = struct{}{}
+	}
+	err := os.Link(path, dest)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	return err
+}
+
 func (ftp *fsTablePersister) TryMoveCmpChunkTableWriter(ctx context.Context, filename string, w *CmpChunkTableWriter) error {
+	if !sameEncryption(ftp.enc, w.enc) {
+		// the table file is encrypted or decrypted as it's copied
+		r, err := w.Reader()
+		if err != nil {
+			return err
+		}
+		return ftp.CopyTableFile(ctx, r, filename, w.ContentLength(), uint32(w.ChunkCount()))
+	}
 	path := filepath.Join(ftp.dir, filename)
 	ftp.removeMu.Lock()
 	if ftp.toKeep != nil {
@@ -187,7 +234,7 @@ func (ftp *fsTablePersister) persistTable(ctx context.Context, name addr, data [
 			}
 		}()
 
-		_, ferr = io.Copy(temp, bytes.NewReader(data))
+		ferr = ftp.copyTableFile(temp, bytes.NewReader(data))
 		if ferr != nil {
 			return "", cleanup, ferr
 		}
@@ -249,6 +296,11 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 			}
 		}()
 
+		wr, ferr := newTableFileWriter(temp, ftp.enc)
+		if ferr != nil {
+			return "", cleanup, ferr
+		}
+
 		for _, sws := range plan.sources.sws {
 			var r io.ReadCloser
 			r, _, ferr = sws.source.reader(ctx)
@@ -256,7 +308,7 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 				return "", cleanup, ferr
 			}
 
-			n, ferr := io.CopyN(temp, r, int64(sws.dataLen))
+			n, ferr := io.CopyN(wr, r, int64(sws.dataLen))
 			if ferr != nil {
 				r.Close()
 				return "", cleanup, ferr
@@ -273,12 +325,16 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 			}
 		}
 
-		_, ferr = temp.Write(plan.mergedIndex)
+		_, ferr = wr.Write(plan.mergedIndex)
 
 		if ferr != nil {
 			return "", cleanup, ferr
 		}
 
+		if ferr = wr.Close(); ferr != nil {
+			return "", cleanup, ferr
+		}
+
 		return temp.Name(), cleanup, nil
 	}()
 	defer f()
@@ -29,11 +29,15 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/dolthub/dolt/go/store/hash"
 )
 
 type fileTableReader struct {
 	tableReader
 	h addr
+	// encrypted is true if the table file is encrypted
+	encrypted bool
 }
 
 const (
@@ -51,10 +55,12 @@ func tableFileExists(ctx context.Context, dir string, h addr) (bool, error) {
 	return err == nil, err
 }
 
-func newFileTableReader(ctx context.Context, dir string, h addr, chunkCount uint32, q MemoryQuotaProvider) (cs chunkSource, err error) {
+func newFileTableReader(ctx context.Context, dir string, h addr, chunkCount uint32, q MemoryQuotaProvider, enc *Encryption) (cs chunkSource, err error) {
 	path := filepath.Join(dir, h.String())
 
 	var f *os.File
+	var ra io.ReaderAt
+	var encrypted, archive bool
 	index, sz, err := func() (ti onHeapTableIndex, sz int64, err error) {
 		// Be careful with how |f| is used below. |RefFile| returns a cached
 		// os.File pointer so the code needs to use f in a concurrency-safe
@@ -79,10 +85,26 @@ func newFileTableReader(ctx context.Context, dir string, h addr, chunkCount uint
 			return
 		}
 
-		idxSz := int64(indexSize(chunkCount) + footerSize)
 		sz = fi.Size()
+		ra = f
+		encrypted, err = isEncryptedFile(f, sz)
+		if err != nil {
+			return
+		} else if encrypted {
+			var er *encryptedReaderAt
+			if er, err = openEncryptedFile(f, sz, enc); err != nil {
+				err = fmt.Errorf("failed to open table file %s: %w", path, err)
+				return
+			}
+			ra, sz = er, er.size
+		}
+		if archive, err = isArchiveFile(ra, sz); err != nil || archive {
+			return
+		}
+
+		idxSz := int64(indexSize(chunkCount) + footerSize)
 		indexOffset := sz - idxSz
-		r := io.NewSectionReader(f, indexOffset, idxSz)
+		r := io.NewSectionReader(ra, indexOffset, idxSz)
 
 		if int64(int(idxSz)) != idxSz {
 			err = fmt.Errorf("table file %s/%s is too large to read on this platform. index size %d > max int.", dir, h.String(), idxSz)
@@ -116,21 +138,42 @@ func newFileTableReader(ctx context.Context, dir string, h addr, chunkCount uint
 		return nil, err
 	}
 
+	if archive {
+		ar, err := newArchiveReader(ctx, h, &fileReaderAt{f: f, r: ra, path: path, sz: sz, enc: enc}, sz, &Stats{})
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if chunkCount != ar.data.chunks {
+			ar.close()
+			return nil, errors.New("unexpected chunk count")
+		}
+		return ar, nil
+	}
+
 	if chunkCount != index.chunkCount() {
 		index.Close()
 		f.Close()
 		return nil, errors.New("unexpected chunk count")
 	}
 
-	tr, err := newTableReader(index, &fileReaderAt{f, path, sz}, fileBlockSize)
+	fra := &fileReaderAt{f: f, r: ra, path: path, sz: sz, enc: enc}
+	if err = fra.applyReadOptions(getFileReadOptions(), !encrypted); err != nil {
+		index.Close()
+		fra.Close()
+		return nil, err
+	}
+
+	tr, err := newTableReader(index, fra, fileBlockSize)
 	if err != nil {
 		index.Close()
-		f.Close()
+		fra.Close()
 		return nil, err
 	}
 	return &fileTableReader{
 		tr,
 		h,
+		encrypted,
 	}, nil
 }
 
@@ -147,33 +190,113 @@ func (ftr *fileTableReader) clone() (chunkSource, error) {
 	if err != nil {
 		return &fileTableReader{}, err
 	}
-	return &fileTableReader{tr, ftr.h}, nil
+	return &fileTableReader{tr, ftr.h, ftr.encrypted}, nil
+}
+
+func (ftr *fileTableReader) getRecordRanges(requests []getRecord) (map[hash.Hash]Range, error) {
+	if ftr.encrypted {
+		return nil, ErrEncryptedChunkLocations
+	}
+	return ftr.tableReader.getRecordRanges(requests)
 }
 
 type fileReaderAt struct {
-	f    *os.File
-	path string
-	sz   int64
+	// f is nil if the file is mapped
+	f *os.File
+	// r reads |f|, decrypting it if it's encrypted, or reads |m|
+	r      io.ReaderAt
+	path   string
+	sz     int64
+	enc    *Encryption
+	m      *mappedFile
+	advice ReadAdvice
+}
+
+// applyReadOptions maps the file of |fra| if |opts| allow it and |mappable| is true, and otherwise advises the
+// kernel how it will be read.
+func (fra *fileReaderAt) applyReadOptions(opts FileReadOptions, mappable bool) error {
+	fra.advice = opts.Advice
+	if mappable {
+		m, err := mapTableFile(fra.f, fra.sz, opts)
+		if err != nil {
+			return err
+		} else if m != nil {
+			// the mapping outlives the file descriptor
+			err = fra.f.Close()
+			fra.f, fra.r, fra.m = nil, m, m
+			return err
+		}
+	}
+	return fadvise(fra.f, fra.advice)
 }
 
 func (fra *fileReaderAt) clone() (tableReaderAt, error) {
+	if fra.m != nil {
+		m := fra.m.ref()
+		return &fileReaderAt{
+			r:      m,
+			path:   fra.path,
+			sz:     fra.sz,
+			enc:    fra.enc,
+			m:      m,
+			advice: fra.advice,
+		}, nil
+	}
 	f, err := os.Open(fra.path)
 	if err != nil {
 		return nil, err
 	}
+	r, err := fra.readerAt(f)
+	if err == nil {
+		err = fadvise(f, fra.advice)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
 	return &fileReaderAt{
-		f,
-		fra.path,
-		fra.sz,
+		f:      f,
+		r:      r,
+		path:   fra.path,
+		sz:     fra.sz,
+		enc:    fra.enc,
+		advice: fra.advice,
 	}, nil
 }
 
+// readerAt returns a reader of |f|, which is decrypted if |fra| is encrypted
+func (fra *fileReaderAt) readerAt(f *os.File) (io.ReaderAt, error) {
+	if _, ok := fra.r.(*encryptedReaderAt); !ok {
+		return f, nil
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return openEncryptedFile(f, fi.Size(), fra.enc)
+}
+
 func (fra *fileReaderAt) Close() error {
+	if fra.m != nil {
+		return fra.m.release()
+	}
 	return fra.f.Close()
 }
 
 func (fra *fileReaderAt) Reader(ctx context.Context) (io.ReadCloser, error) {
-	return os.Open(fra.path)
+	f, err := os.Open(fra.path)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fra.r.(*encryptedReaderAt); !ok {
+		return f, nil
+	}
+	r, err := fra.readerAt(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return encryptedFileReader{io.NewSectionReader(r, 0, fra.sz), f}, nil
 }
 
 func (fra *fileReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int64, stats *Stats) (n int, err error) {
@@ -182,5 +305,5 @@ func (fra *fileReaderAt) ReadAtWithStats(ctx context.Context, p []byte, off int6
 		stats.FileBytesPerRead.Sample(uint64(len(p)))
 		stats.FileReadLatency.SampleTimeSince(t1)
 	}()
-	return fra.f.ReadAt(p, off)
+	return fra.r.ReadAt(p, off)
 }
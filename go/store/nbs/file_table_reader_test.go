@@ -25,6 +25,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync/atomic"
 	"testing"
 
 	"github.com/dolthub/dolt/go/libraries/utils/file"
@@ -51,8 +53,80 @@ func TestMmapTableReader(t *testing.T) {
 	err = os.WriteFile(filepath.Join(dir, h.String()), tableData, 0666)
 	require.NoError(t, err)
 
-	trc, err := newFileTableReader(ctx, dir, h, uint32(len(chunks)), &UnlimitedQuotaProvider{})
+	trc, err := newFileTableReader(ctx, dir, h, uint32(len(chunks)), &UnlimitedQuotaProvider{}, nil)
 	require.NoError(t, err)
 	defer trc.close()
 	assertChunksInReader(chunks, trc, assert)
 }
+
+func TestFileReadOptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("table files are not mapped on windows")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	chunks := [][]byte{
+		[]byte("hello2"),
+		[]byte("goodbye2"),
+		[]byte("badbye2"),
+	}
+	tableData, h, err := buildTable(chunks)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, h.String()), tableData, 0666)
+	require.NoError(t, err)
+
+	open := func(t *testing.T, opts FileReadOptions) *fileTableReader {
+		SetFileReadOptions(opts)
+		t.Cleanup(func() { SetFileReadOptions(FileReadOptions{}) })
+		cs, err := newFileTableReader(ctx, dir, h, uint32(len(chunks)), &UnlimitedQuotaProvider{}, nil)
+		require.NoError(t, err)
+		return cs.(*fileTableReader)
+	}
+
+	t.Run("Mmap", func(t *testing.T) {
+		trc := open(t, FileReadOptions{Mmap: true, Advice: ReadAdviceRandom})
+		fra := trc.r.(*fileReaderAt)
+		require.NotNil(t, fra.m)
+		assert.Equal(t, int64(len(tableData)), atomic.LoadInt64(&mappedBytes))
+		assertChunksInReader(chunks, trc, assert.New(t))
+
+		cl, err := trc.clone()
+		require.NoError(t, err)
+		assert.Same(t, fra.m, cl.(*fileTableReader).r.(*fileReaderAt).m)
+		require.NoError(t, trc.close())
+		assertChunksInReader(chunks, cl, assert.New(t))
+		require.NoError(t, cl.close())
+		assert.Equal(t, int64(0), atomic.LoadInt64(&mappedBytes))
+	})
+
+	t.Run("OverMaxMappedBytes", func(t *testing.T) {
+		trc := open(t, FileReadOptions{Mmap: true, MaxMappedBytes: int64(len(tableData)) - 1})
+		defer trc.close()
+		assert.Nil(t, trc.r.(*fileReaderAt).m)
+		assert.Equal(t, int64(0), atomic.LoadInt64(&mappedBytes))
+		assertChunksInReader(chunks, trc, assert.New(t))
+	})
+
+	t.Run("Pread", func(t *testing.T) {
+		trc := open(t, FileReadOptions{Advice: ReadAdviceSequential})
+		defer trc.close()
+		fra := trc.r.(*fileReaderAt)
+		assert.Nil(t, fra.m)
+		assert.Equal(t, ReadAdviceSequential, fra.advice)
+		assertChunksInReader(chunks, trc, assert.New(t))
+	})
+}
+
+func TestParseReadAdvice(t *testing.T) {
+	for _, a := range []ReadAdvice{ReadAdviceNormal, ReadAdviceRandom, ReadAdviceSequential, ReadAdviceWillNeed} {
+		parsed, err := ParseReadAdvice(a.String())
+		require.NoError(t, err)
+		assert.Equal(t, a, parsed)
+	}
+	a, err := ParseReadAdvice(" Random ")
+	require.NoError(t, err)
+	assert.Equal(t, ReadAdviceRandom, a)
+	_, err = ParseReadAdvice("sometimes")
+	assert.Error(t, err)
+}
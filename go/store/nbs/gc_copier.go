@@ -45,8 +45,9 @@ type gcCopier struct {
 	writer *CmpChunkTableWriter
 }
 
-func newGarbageCollectionCopier() (*gcCopier, error) {
-	writer, err := NewCmpChunkTableWriter("")
+// newGarbageCollectionCopier returns a gcCopier which encrypts the table file it writes with |enc|, if it isn't nil.
+func newGarbageCollectionCopier(enc *Encryption) (*gcCopier, error) {
+	writer, err := NewEncryptedCmpChunkTableWriter("", enc)
 	if err != nil {
 		return nil, err
 	}
@@ -22,6 +22,8 @@ import (
 	"strings"
 	"sync"
 
+	"golang.org/x/time/rate"
+
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
 )
@@ -29,6 +31,9 @@ import (
 var _ chunks.ChunkStore = (*GenerationalNBS)(nil)
 var _ chunks.GenerationalCS = (*GenerationalNBS)(nil)
 var _ chunks.TableFileStore = (*GenerationalNBS)(nil)
+var _ chunks.UploadedTableFileStore = (*GenerationalNBS)(nil)
+var _ ChunkArchiver = (*GenerationalNBS)(nil)
+var _ TableFileConjoiner = (*GenerationalNBS)(nil)
 
 type GenerationalNBS struct {
 	oldGen *NomsBlockStore
@@ -179,6 +184,11 @@ func (gcs *GenerationalNBS) Version() string {
 	return gcs.newGen.Version()
 }
 
+// Encryption returns the Encryption of the store's new generation, or nil if it isn't encrypted.
+func (gcs *GenerationalNBS) Encryption() *Encryption {
+	return gcs.newGen.Encryption()
+}
+
 // Rebase brings this ChunkStore into sync with the persistent storage's
 // current root.
 func (gcs *GenerationalNBS) Rebase(ctx context.Context) error {
@@ -312,14 +322,46 @@ func (gcs *GenerationalNBS) Size(ctx context.Context) (uint64, error) {
 	return oldSize + newSize, nil
 }
 
-// WriteTableFile will read a table file from the provided reader and write it to the new gen TableFileStore
+// WriteTableFile will read a table file from the provided reader and write it to the new gen TableFileStore, or to
+// the old gen TableFileStore if |fileId| has the old gen prefix given to old gen table files by Sources.
 func (gcs *GenerationalNBS) WriteTableFile(ctx context.Context, fileId string, numChunks int, contentHash []byte, getRd func() (io.ReadCloser, uint64, error)) error {
+	if id, ok := gcs.oldGenFileId(fileId); ok {
+		return gcs.oldGen.WriteTableFile(ctx, id, numChunks, contentHash, getRd)
+	}
 	return gcs.newGen.WriteTableFile(ctx, fileId, numChunks, contentHash, getRd)
 }
 
-// AddTableFilesToManifest adds table files to the manifest of the newgen cs
+// AddTableFilesToManifest adds table files to the manifest of the newgen cs, and those with the old gen prefix to the
+// manifest of the oldgen cs
 func (gcs *GenerationalNBS) AddTableFilesToManifest(ctx context.Context, fileIdToNumChunks map[string]int) error {
-	return gcs.newGen.AddTableFilesToManifest(ctx, fileIdToNumChunks)
+	newGen := make(map[string]int, len(fileIdToNumChunks))
+	oldGen := make(map[string]int)
+	for fileId, numChunks := range fileIdToNumChunks {
+		if id, ok := gcs.oldGenFileId(fileId); ok {
+			oldGen[id] = numChunks
+		} else {
+			newGen[fileId] = numChunks
+		}
+	}
+	if err := gcs.oldGen.AddTableFilesToManifest(ctx, oldGen); err != nil {
+		return err
+	}
+	return gcs.newGen.AddTableFilesToManifest(ctx, newGen)
+}
+
+// oldGenFileId returns the file id in the oldgen cs of |fileId|, if it has the old gen prefix given to old gen table
+// files by Sources
+func (gcs *GenerationalNBS) oldGenFileId(fileId string) (string, bool) {
+	prefix := filepath.ToSlash(gcs.RelativeOldGenPath()) + "/"
+	if !strings.HasPrefix(fileId, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(fileId, prefix), true
+}
+
+// HasUploadedTableFiles returns the table files in |fileIdToNumChunks| which were written to the newgen cs
+func (gcs *GenerationalNBS) HasUploadedTableFiles(ctx context.Context, fileIdToNumChunks map[string]int) (map[string]int, error) {
+	return gcs.newGen.HasUploadedTableFiles(ctx, fileIdToNumChunks)
 }
 
 // PruneTableFiles deletes old table files that are no longer referenced in the manifest of the new or old gen chunkstores
@@ -333,6 +375,44 @@ func (gcs *GenerationalNBS) PruneTableFiles(ctx context.Context) error {
 	return gcs.newGen.pruneTableFiles(ctx, gcs.hasMany)
 }
 
+// ConjoinTableFiles conjoins the table files of the new gen and old gen chunkstores
+func (gcs *GenerationalNBS) ConjoinTableFiles(ctx context.Context, maxTables int, lim *rate.Limiter) (bool, error) {
+	newConjoined, err := gcs.newGen.ConjoinTableFiles(ctx, maxTables, lim)
+	if err != nil {
+		return false, err
+	}
+
+	oldConjoined, err := gcs.oldGen.ConjoinTableFiles(ctx, maxTables, lim)
+	if err != nil {
+		return false, err
+	}
+	return newConjoined || oldConjoined, nil
+}
+
+// ArchiveChunks moves the chunks of |groups| held by the old gen and new gen chunkstores into archives
+func (gcs *GenerationalNBS) ArchiveChunks(ctx context.Context, groups []hash.HashSet, rebuild bool) (int, error) {
+	old, err := gcs.oldGen.ArchiveChunks(ctx, groups, rebuild)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := gcs.newGen.ArchiveChunks(ctx, groups, rebuild)
+	if err != nil {
+		return 0, err
+	}
+	return old + n, nil
+}
+
+// OpenTransferFile returns a reader of the table file |fileID| if it is the table file the chunks of one of
+// the old gen or new gen chunkstore's archives are served from
+func (gcs *GenerationalNBS) OpenTransferFile(ctx context.Context, fileID string) (*io.SectionReader, bool, error) {
+	r, ok, err := gcs.newGen.OpenTransferFile(ctx, fileID)
+	if err != nil || ok {
+		return r, ok, err
+	}
+	return gcs.oldGen.OpenTransferFile(ctx, fileID)
+}
+
 // SetRootChunk changes the root chunk hash from the previous value to the new root for the newgen cs
 func (gcs *GenerationalNBS) SetRootChunk(ctx context.Context, root, previous hash.Hash) error {
 	return gcs.newGen.setRootChunk(ctx, root, previous, gcs.hasMany)
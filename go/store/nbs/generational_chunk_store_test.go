@@ -16,13 +16,19 @@ package nbs
 
 import (
 	"context"
+	"io"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
 )
 
 var randGen = rand.New(rand.NewSource(0))
@@ -171,3 +177,58 @@ func TestGenerationalCS(t *testing.T) {
 	putChunks(t, ctx, chnks, cs, inNew, 15, 16, 17, 18, 19)
 	requireChunks(t, ctx, chnks, cs, inOld, inNew)
 }
+
+// makeTestGenerationalCS returns a GenerationalNBS with its oldgen store in the oldgen directory of its newgen store,
+// as in a dolt database.
+func makeTestGenerationalCS(t *testing.T) *GenerationalNBS {
+	ctx := context.Background()
+	dir := filepath.Join(tempfiles.MovableTempFileProvider.GetTempDir(), "noms_"+uuid.New().String()[:8])
+	oldGenDir := filepath.Join(dir, "oldgen")
+	require.NoError(t, os.MkdirAll(oldGenDir, os.ModePerm))
+
+	q := NewUnlimitedMemQuotaProvider()
+	newGen, err := NewLocalStore(ctx, types.Format_Default.VersionString(), dir, defaultMemTableSize, q)
+	require.NoError(t, err)
+	oldGen, err := NewLocalStore(ctx, types.Format_Default.VersionString(), oldGenDir, defaultMemTableSize, q)
+	require.NoError(t, err)
+	return NewGenerationalCS(oldGen, newGen)
+}
+
+func TestGenerationalCSWriteSources(t *testing.T) {
+	ctx := context.Background()
+	src := makeTestGenerationalCS(t)
+	defer src.Close()
+	inOld := make(map[int]bool)
+	inNew := make(map[int]bool)
+	chnks := genChunks(t, 20, 1000)
+
+	putChunks(t, ctx, chnks, src.oldGen, inOld, 0, 1, 2, 3, 4)
+	oldRoot, err := src.oldGen.Root(ctx)
+	require.NoError(t, err)
+	ok, err := src.oldGen.Commit(ctx, oldRoot, oldRoot)
+	require.NoError(t, err)
+	require.True(t, ok)
+	putChunks(t, ctx, chnks, src, inNew, 5, 6, 7, 8, 9)
+	root, err := src.Root(ctx)
+	require.NoError(t, err)
+	ok, err = src.Commit(ctx, chnks[9].Hash(), root)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// the table files of |src| are written to the same generation of |sink|
+	sink := makeTestGenerationalCS(t)
+	defer sink.Close()
+	_, tableFiles, _, err := src.Sources(ctx)
+	require.NoError(t, err)
+	fileIdToNumChunks := make(map[string]int)
+	for _, tf := range tableFiles {
+		tf := tf
+		require.NoError(t, sink.WriteTableFile(ctx, tf.FileID(), tf.NumChunks(), nil, func() (io.ReadCloser, uint64, error) {
+			return tf.Open(ctx)
+		}))
+		fileIdToNumChunks[tf.FileID()] = tf.NumChunks()
+	}
+	require.NoError(t, sink.AddTableFilesToManifest(ctx, fileIdToNumChunks))
+	require.NoError(t, sink.Rebase(ctx))
+	requireChunks(t, ctx, chnks, sink, inOld, inNew)
+}
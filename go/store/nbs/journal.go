@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/dolthub/fslock"
@@ -32,6 +33,10 @@ import (
 
 const (
 	chunkJournalName = chunkJournalAddr // todo
+
+	// journalCompactionDefaultSize is the size past which
+	// the chunk journal is compacted into a table file
+	journalCompactionDefaultSize = 256 * 1024 * 1024
 )
 
 // chunkJournal is a persistence abstraction for a NomsBlockStore.
@@ -44,6 +49,12 @@ type chunkJournal struct {
 	contents  manifestContents
 	backing   *journalManifest
 	persister *fsTablePersister
+
+	// compactSize is the journal size past which
+	// the journal is compacted into a table file
+	compactSize int64
+	// compacting is set while a compaction is in progress
+	compacting atomic.Bool
 }
 
 var _ tablePersister = &chunkJournal{}
@@ -58,7 +69,7 @@ func newChunkJournal(ctx context.Context, nbfVers, dir string, m *journalManifes
 		return nil, err
 	}
 
-	j := &chunkJournal{path: path, backing: m, persister: p}
+	j := &chunkJournal{path: path, backing: m, persister: p, compactSize: journalCompactionDefaultSize}
 	j.contents.nbfVers = nbfVers
 
 	ok, err := fileExists(path)
@@ -98,6 +109,11 @@ func (j *chunkJournal) bootstrapJournalWriter(ctx context.Context) (err error) {
 		if err != nil {
 			return err
 		}
+		if j.persister.enc != nil {
+			if err = j.wr.writeEncryptionRecord(j.persister.enc); err != nil {
+				return err
+			}
+		}
 
 		_, err = j.wr.bootstrapJournal(ctx)
 		if err != nil {
@@ -125,6 +141,7 @@ func (j *chunkJournal) bootstrapJournalWriter(ctx context.Context) (err error) {
 	} else if !ok {
 		return errors.New("missing chunk journal " + j.path)
 	}
+	j.wr.enc = j.persister.enc
 
 	// parse existing journal file
 	root, err := j.wr.bootstrapJournal(ctx)
@@ -253,6 +270,13 @@ func (j *chunkJournal) CopyTableFile(ctx context.Context, r io.ReadCloser, fileI
 	return j.persister.CopyTableFile(ctx, r, fileId, fileSz, chunkCount)
 }
 
+func (j *chunkJournal) LinkTableFile(ctx context.Context, path, fileId string) error {
+	if j.backing.readOnly() {
+		return errReadOnlyManifest
+	}
+	return j.persister.LinkTableFile(ctx, path, fileId)
+}
+
 // Name implements manifest.
 func (j *chunkJournal) Name() string {
 	return j.path
@@ -400,6 +424,42 @@ func (j *chunkJournal) Close() (err error) {
 	return
 }
 
+// compactionRequired returns true if the journal has grown past |j.compactSize|.
+func (j *chunkJournal) compactionRequired() bool {
+	if j.wr == nil || j.backing.readOnly() || j.compactSize <= 0 {
+		return false
+	}
+	return j.wr.currentSize() > j.compactSize
+}
+
+// writeCompactedTable writes the chunks of the journal |wr| into a new table file. It returns
+// the spec of the table file and the journal offset up to which chunks were written to it.
+func (j *chunkJournal) writeCompactedTable(ctx context.Context, wr *journalWriter) (spec tableSpec, end int64, err error) {
+	gcc, err := newGarbageCollectionCopier(j.persister.enc)
+	if err != nil {
+		return tableSpec{}, 0, err
+	}
+	// the journal can hold the same chunk more than once
+	seen := make(map[addr]struct{})
+	end, err = wr.iterChunks(ctx, func(cc CompressedChunk) error {
+		if _, ok := seen[addr(cc.H)]; ok {
+			return nil
+		}
+		seen[addr(cc.H)] = struct{}{}
+		return gcc.addChunk(ctx, cc)
+	})
+	if err != nil {
+		return tableSpec{}, 0, err
+	}
+	specs, err := gcc.copyTablesToDir(ctx, j.persister)
+	if err != nil {
+		return tableSpec{}, 0, err
+	} else if len(specs) == 0 {
+		return tableSpec{}, end, nil
+	}
+	return specs[0], end, nil
+}
+
 type journalConjoiner struct {
 	child conjoinStrategy
 }
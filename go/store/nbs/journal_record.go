@@ -29,9 +29,10 @@ import (
 // journalRec is a record in a chunk journal. Its serialization format uses
 // uint8 tag prefixes to identify fields and allow for format evolution.
 //
-// There are two kinds of journalRecs: chunk records and root hash records.
-// Chunk records store chunks from persisted memTables. Root hash records
-// store root hash updates to the manifest state.
+// There are three kinds of journalRecs: chunk records, root hash records and
+// encryption records. Chunk records store chunks from persisted memTables.
+// Root hash records store root hash updates to the manifest state. An
+// encryption record begins an encrypted journal, see Encryption.
 // Future records kinds may include other updates to manifest state such as
 // updates to GC generation or the table set lock hash.
 //
@@ -71,6 +72,9 @@ const (
 	unknownJournalRecKind  journalRecKind = 0
 	rootHashJournalRecKind journalRecKind = 1
 	chunkJournalRecKind    journalRecKind = 2
+	// encryptionJournalRecKind records store the id
+	// of the key the journal is encrypted with
+	encryptionJournalRecKind journalRecKind = 3
 )
 
 type journalRecTag uint8
@@ -112,6 +116,10 @@ func rootHashRecordSize() (recordSz int) {
 	return
 }
 
+func encryptionRecordSize() int {
+	return rootHashRecordSize()
+}
+
 func writeChunkRecord(buf []byte, c CompressedChunk) (n uint32) {
 	// length
 	l, _ := chunkRecordSize(c)
@@ -140,6 +148,15 @@ func writeChunkRecord(buf []byte, c CompressedChunk) (n uint32) {
 }
 
 func writeRootHashRecord(buf []byte, root addr) (n uint32) {
+	return writeAddrRecord(buf, rootHashJournalRecKind, root)
+}
+
+func writeEncryptionRecord(buf []byte, keyId addr) (n uint32) {
+	return writeAddrRecord(buf, encryptionJournalRecKind, keyId)
+}
+
+// writeAddrRecord writes a record of |kind| with the address |a| and no payload.
+func writeAddrRecord(buf []byte, kind journalRecKind, a addr) (n uint32) {
 	// length
 	l := rootHashRecordSize()
 	writeUint32(buf[:journalRecLenSz], uint32(l))
@@ -147,12 +164,12 @@ func writeRootHashRecord(buf []byte, root addr) (n uint32) {
 	// kind
 	buf[n] = byte(kindJournalRecTag)
 	n += journalRecTagSz
-	buf[n] = byte(rootHashJournalRecKind)
+	buf[n] = byte(kind)
 	n += journalRecKindSz
 	// address
 	buf[n] = byte(addrJournalRecTag)
 	n += journalRecTagSz
-	copy(buf[n:], root[:])
+	copy(buf[n:], a[:])
 	n += journalRecAddrSz
 	// empty payload
 	// checksum
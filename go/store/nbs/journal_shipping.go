@@ -0,0 +1,162 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// ErrJournalPositionLost is returned by ReadJournalChunks when the journal
+// position it was given no longer refers to the store's current journal,
+// for example because the store was garbage collected, compacted its
+// journal or had table files added to it since the position was taken.
+var ErrJournalPositionLost = errors.New("chunk journal position lost")
+
+// JournalPosition is an offset into the chunk journal of a NomsBlockStore.
+// It is only meaningful for the set of table files the store had when the
+// position was taken.
+type JournalPosition struct {
+	tables hash.Hash
+	offset int64
+}
+
+// IsZero returns true if |p| is the zero JournalPosition.
+func (p JournalPosition) IsZero() bool {
+	return p == JournalPosition{}
+}
+
+// JournalEnd returns the position of the end of the store's chunk journal.
+// It returns false if the store does not have a chunk journal.
+func (nbs *NomsBlockStore) JournalEnd() (JournalPosition, bool, error) {
+	nbs.mu.RLock()
+	defer nbs.mu.RUnlock()
+	wr := nbs.journalWriter()
+	if wr == nil {
+		return JournalPosition{}, false, nil
+	}
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if err := wr.flush(); err != nil {
+		return JournalPosition{}, false, err
+	}
+	return JournalPosition{tables: nbs.tablesHash(), offset: wr.off}, true, nil
+}
+
+// ReadJournalChunks calls |cb| with each chunk written to the store's chunk
+// journal after |from|, in the order they were written, and returns the
+// position of the end of the last chunk read. It returns
+// ErrJournalPositionLost if |from| does not refer to the current journal.
+func (nbs *NomsBlockStore) ReadJournalChunks(ctx context.Context, from JournalPosition, cb func(CompressedChunk) error) (JournalPosition, error) {
+	f, end, enc, err := nbs.openJournalAt(from)
+	if err != nil {
+		return JournalPosition{}, err
+	}
+	defer f.Close()
+
+	rdr := io.NewSectionReader(f, 0, end)
+	off, err := processJournalRecords(ctx, rdr, from.offset, func(o int64, r journalRec) error {
+		if r.kind != chunkJournalRecKind {
+			return nil
+		}
+		if enc != nil {
+			p, err := enc.open(nil, r.payload, r.address[:])
+			if err != nil {
+				return fmt.Errorf("failed to decrypt chunk journal record: %w", err)
+			}
+			r.payload = p
+		}
+		cc, err := NewCompressedChunk(hash.Hash(r.address), r.payload)
+		if err != nil {
+			return err
+		}
+		return cb(cc)
+	})
+	if err != nil {
+		return JournalPosition{}, err
+	}
+	return JournalPosition{tables: from.tables, offset: off}, nil
+}
+
+// openJournalAt opens a new file descriptor on the store's chunk journal
+// and returns it along with the current end of the journal and the
+// Encryption of its chunks, if they are encrypted, after checking that
+// |from| refers to it. The file is opened while holding |nbs.mu|, so
+// that it cannot be swapped out from under |from|.
+func (nbs *NomsBlockStore) openJournalAt(from JournalPosition) (*os.File, int64, *Encryption, error) {
+	nbs.mu.RLock()
+	defer nbs.mu.RUnlock()
+	wr := nbs.journalWriter()
+	if wr == nil || from.tables != nbs.tablesHash() {
+		return nil, 0, nil, ErrJournalPositionLost
+	}
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if err := wr.flush(); err != nil {
+		return nil, 0, nil, err
+	}
+	if from.offset > wr.off {
+		return nil, 0, nil, ErrJournalPositionLost
+	}
+	f, err := os.Open(wr.path)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	var enc *Encryption
+	if wr.encrypted {
+		enc = wr.enc
+	}
+	return f, wr.off, enc, nil
+}
+
+// journalWriter returns the store's chunk journal writer, or nil if it
+// does not have one. Callers must hold |nbs.mu|.
+func (nbs *NomsBlockStore) journalWriter() *journalWriter {
+	if j, ok := nbs.p.(*chunkJournal); ok {
+		return j.wr
+	}
+	return nil
+}
+
+// tablesHash returns a hash of the store's table files other than its chunk
+// journal. Callers must hold |nbs.mu|.
+func (nbs *NomsBlockStore) tablesHash() hash.Hash {
+	buf := make([]byte, 0, (len(nbs.upstream.specs)+len(nbs.upstream.appendix)+1)*addrSize)
+	buf = append(buf, nbs.upstream.gcGen[:]...)
+	for _, s := range nbs.upstream.specs {
+		if s.name != journalAddr {
+			buf = append(buf, s.name[:]...)
+		}
+	}
+	for _, s := range nbs.upstream.appendix {
+		buf = append(buf, s.name[:]...)
+	}
+	return hash.Of(buf)
+}
+
+// JournalEnd implements the same method on the new generation store.
+func (gcs *GenerationalNBS) JournalEnd() (JournalPosition, bool, error) {
+	return gcs.newGen.JournalEnd()
+}
+
+// ReadJournalChunks implements the same method on the new generation store.
+func (gcs *GenerationalNBS) ReadJournalChunks(ctx context.Context, from JournalPosition, cb func(CompressedChunk) error) (JournalPosition, error) {
+	return gcs.newGen.ReadJournalChunks(ctx, from, cb)
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/file"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func makeTestJournalingStore(t *testing.T, enc *Encryption) *NomsBlockStore {
+	cacheOnce.Do(makeGlobalCaches)
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { file.RemoveAll(dir) })
+	nbf := types.Format_Default.VersionString()
+	st, err := NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, NewUnlimitedMemQuotaProvider(), enc)
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func commitTestChunks(t *testing.T, st *NomsBlockStore, start, n uint32) hash.HashSet {
+	ctx := context.Background()
+	written := hash.NewHashSet()
+	for i := start; i < start+n; i++ {
+		c := makeChunk(i)
+		require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+		written.Insert(c.Hash())
+	}
+	last, err := st.Root(ctx)
+	require.NoError(t, err)
+	ok, err := st.Commit(ctx, makeChunk(start).Hash(), last)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return written
+}
+
+func readTestJournalChunks(t *testing.T, st *NomsBlockStore, from JournalPosition) (hash.HashSet, JournalPosition, error) {
+	read := hash.NewHashSet()
+	end, err := st.ReadJournalChunks(context.Background(), from, func(cc CompressedChunk) error {
+		c, err := cc.ToChunk()
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, cc.H, c.Hash())
+		read.Insert(cc.H)
+		return nil
+	})
+	return read, end, err
+}
+
+func TestReadJournalChunks(t *testing.T) {
+	t.Run("Unencrypted", func(t *testing.T) {
+		testReadJournalChunks(t, makeTestJournalingStore(t, nil))
+	})
+	t.Run("Encrypted", func(t *testing.T) {
+		testReadJournalChunks(t, makeTestJournalingStore(t, makeTestEncryption(t)))
+	})
+}
+
+func testReadJournalChunks(t *testing.T, st *NomsBlockStore) {
+	commitTestChunks(t, st, 0, 16)
+
+	pos, ok, err := st.JournalEnd()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	written := commitTestChunks(t, st, 16, 32)
+	read, end, err := readTestJournalChunks(t, st, pos)
+	require.NoError(t, err)
+	assert.Equal(t, written, read)
+
+	latest, ok, err := st.JournalEnd()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, latest, end)
+
+	// nothing new to read at the end of the journal
+	read, again, err := readTestJournalChunks(t, st, end)
+	require.NoError(t, err)
+	assert.Empty(t, read)
+	assert.Equal(t, end, again)
+
+	// the position is lost once table files are added to the store
+	populateLocalStore(t, st, 2)
+	_, _, err = readTestJournalChunks(t, st, end)
+	assert.ErrorIs(t, err, ErrJournalPositionLost)
+}
+
+func TestReadJournalChunksAfterCompaction(t *testing.T) {
+	st := makeTestJournalingStore(t, nil)
+	st.p.(*chunkJournal).compactSize = 1024
+	commitTestChunks(t, st, 0, 16)
+	st.compactWg.Wait()
+
+	pos, ok, err := st.JournalEnd()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// compacting the journal adds a table file, which loses the position
+	commitTestChunks(t, st, 16, 32)
+	st.compactWg.Wait()
+	require.Greater(t, len(st.upstream.specs), 1)
+	_, _, err = readTestJournalChunks(t, st, pos)
+	assert.ErrorIs(t, err, ErrJournalPositionLost)
+}
+
+func TestJournalEndWithoutJournal(t *testing.T) {
+	st, _, _ := makeTestLocalStore(t, 8)
+	_, ok, err := st.JournalEnd()
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = st.ReadJournalChunks(context.Background(), JournalPosition{}, func(CompressedChunk) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrJournalPositionLost)
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/utils/file"
 	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -38,7 +39,7 @@ func makeTestChunkJournal(t *testing.T) *chunkJournal {
 	m, err := newJournalManifest(ctx, dir)
 	require.NoError(t, err)
 	q := NewUnlimitedMemQuotaProvider()
-	p := newFSTablePersister(dir, q)
+	p := newFSTablePersister(dir, q, nil)
 	nbf := types.Format_Default.VersionString()
 	j, err := newChunkJournal(ctx, nbf, dir, m, p.(*fsTablePersister))
 	require.NoError(t, err)
@@ -126,6 +127,83 @@ func TestReadRecordRanges(t *testing.T) {
 	}
 }
 
+func TestChunkJournalCompaction(t *testing.T) {
+	ctx := context.Background()
+	nbf := types.Format_Default.VersionString()
+	for name, enc := range map[string]*Encryption{
+		"plaintext": nil,
+		"encrypted": makeTestEncryption(t),
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			st, err := NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, NewUnlimitedMemQuotaProvider(), enc)
+			require.NoError(t, err)
+			j := st.p.(*chunkJournal)
+			j.compactSize = 64 * 1024
+
+			var before tableSet
+			var checked bool
+			data := make(map[addr]chunks.Chunk)
+			root := hash.Hash{}
+			for i := 0; i < 16; i++ {
+				for j := 0; j < 64; j++ {
+					c := chunks.NewChunk(randBuf(256))
+					data[addr(c.Hash())] = c
+					require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+					root = c.Hash()
+				}
+				last, err := st.Root(ctx)
+				require.NoError(t, err)
+				ok, err := st.Commit(ctx, root, last)
+				require.NoError(t, err)
+				require.True(t, ok)
+				st.compactWg.Wait()
+				if !checked && len(st.upstream.specs) > 1 {
+					// a tableSet from before the compaction reads
+					// the compacted chunks through the journal
+					for h, c := range data {
+						act, err := before.get(ctx, h, &Stats{})
+						require.NoError(t, err)
+						require.Equal(t, c.Data(), act)
+					}
+					checked = true
+				}
+				before = st.tables
+			}
+			assert.True(t, checked)
+
+			// the journal holds less than the chunks written to it
+			assert.Less(t, j.wr.currentSize(), int64(len(data)*256))
+			var tables int
+			for _, s := range st.upstream.specs {
+				if !isJournalAddr(s.name) {
+					tables++
+				}
+			}
+			assert.Greater(t, tables, 0)
+
+			for h, c := range data {
+				act, err := st.tables.get(ctx, h, &Stats{})
+				require.NoError(t, err)
+				require.Equal(t, c.Data(), act)
+			}
+			require.NoError(t, st.Close())
+
+			st, err = NewLocalJournalingStoreWithEncryption(ctx, nbf, dir, NewUnlimitedMemQuotaProvider(), enc)
+			require.NoError(t, err)
+			defer st.Close()
+			act, err := st.Root(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, root, act)
+			for h, c := range data {
+				ch, err := st.Get(ctx, hash.Hash(h))
+				require.NoError(t, err)
+				require.Equal(t, c.Data(), ch.Data())
+			}
+		})
+	}
+}
+
 func randBuf(n int) (b []byte) {
 	b = make([]byte, n)
 	rand.Read(b)
@@ -41,6 +41,10 @@ const (
 
 	journalIndexFileName = "journal.idx"
 
+	// journalRotateSuffix is appended to the journal's path
+	// to name the new journal file written by rotate()
+	journalRotateSuffix = ".rotate"
+
 	// journalIndexDefaultMaxNovel determines how often we flush
 	// records qto the out-of-band journal index file.
 	journalIndexDefaultMaxNovel = 16384
@@ -154,6 +158,17 @@ type journalWriter struct {
 	index    *os.File
 	maxNovel int
 
+	// enc is the Encryption of the journal's store, if it has one
+	enc *Encryption
+	// encrypted is true if the chunks in the journal are encrypted
+	// with |enc|, which is so if the journal begins with an encryption
+	// record. A journal created without encryption stays unencrypted.
+	encrypted bool
+
+	// compacted holds the chunks most recently rotated out of the
+	// journal into a table file, see rotate()
+	compacted chunkSource
+
 	lock sync.RWMutex
 }
 
@@ -164,12 +179,25 @@ var _ io.Closer = &journalWriter{}
 func (wr *journalWriter) bootstrapJournal(ctx context.Context) (last hash.Hash, err error) {
 	wr.lock.Lock()
 	defer wr.lock.Unlock()
+	return wr.bootstrap(ctx)
+}
 
+// bootstrap implements bootstrapJournal, callers must hold |wr.lock|.
+func (wr *journalWriter) bootstrap(ctx context.Context) (last hash.Hash, err error) {
 	if wr.maxNovel == 0 {
 		wr.maxNovel = journalIndexDefaultMaxNovel
 	}
 	wr.ranges = newRangeIndex()
 
+	var id addr
+	if id, wr.encrypted, err = readEncryptionRecord(wr.journal); err != nil {
+		return
+	} else if wr.encrypted && wr.enc == nil {
+		return hash.Hash{}, ErrNoEncryptionKey
+	} else if wr.encrypted && id != wr.enc.id {
+		return hash.Hash{}, ErrWrongEncryptionKey
+	}
+
 	p := filepath.Join(filepath.Dir(wr.path), journalIndexFileName)
 	var ok bool
 	ok, err = fileExists(p)
@@ -212,10 +240,16 @@ func (wr *journalWriter) bootstrapJournal(ctx context.Context) (last hash.Hash,
 					} else if h != r.lastRoot {
 						return fmt.Errorf("invalid index record hash (%s != %s)", h.String(), r.lastRoot.String())
 					}
+					payload := r.payload
+					if wr.encrypted {
+						if payload, err = wr.enc.open(nil, payload, r.lastRoot[:]); err != nil {
+							return fmt.Errorf("failed to decrypt index record: %w", err)
+						}
+					}
 					select {
 					case <-ectx.Done():
 						return ectx.Err()
-					case ch <- deserializeLookups(r.payload):
+					case ch <- deserializeLookups(payload):
 						// record a high-water-mark for the indexed portion of the journal
 						wr.indexed = int64(r.end)
 					}
@@ -263,9 +297,18 @@ func (wr *journalWriter) bootstrapJournal(ctx context.Context) (last hash.Hash,
 				Offset: uint64(o) + uint64(r.payloadOffset()),
 				Length: uint32(len(r.payload)),
 			})
+			if wr.encrypted {
+				p, err := wr.enc.open(nil, r.payload, r.address[:])
+				if err != nil {
+					return fmt.Errorf("failed to decrypt chunk journal record: %w", err)
+				}
+				r.payload = p
+			}
 			wr.uncmpSz += r.uncompressedPayloadSize()
 		case rootHashJournalRecKind:
 			last = hash.Hash(r.address)
+		case encryptionJournalRecKind:
+			// checked above
 		default:
 			return fmt.Errorf("unknown journal record kind (%d)", r.kind)
 		}
@@ -297,7 +340,9 @@ func (wr *journalWriter) corruptIndexRecovery(ctx context.Context) (err error) {
 func (wr *journalWriter) hasAddr(h addr) (ok bool) {
 	wr.lock.RLock()
 	defer wr.lock.RUnlock()
-	_, ok = wr.ranges.get(h)
+	if _, ok = wr.ranges.get(h); !ok && wr.compacted != nil {
+		ok, _ = wr.compacted.has(h)
+	}
 	return
 }
 
@@ -306,18 +351,42 @@ func (wr *journalWriter) getCompressedChunk(h addr) (CompressedChunk, error) {
 	wr.lock.RLock()
 	defer wr.lock.RUnlock()
 	r, ok := wr.ranges.get(h)
-	if !ok {
+	if !ok && wr.compacted != nil {
+		return getCompressedFrom(wr.compacted, h)
+	} else if !ok {
 		return CompressedChunk{}, nil
 	}
 	buf := make([]byte, r.Length)
 	if _, err := wr.readAt(buf, int64(r.Offset)); err != nil {
 		return CompressedChunk{}, nil
 	}
+	if wr.encrypted {
+		var err error
+		if buf, err = wr.enc.open(nil, buf, h[:]); err != nil {
+			return CompressedChunk{}, fmt.Errorf("failed to decrypt chunk %s: %w", h.String(), err)
+		}
+	}
 	return NewCompressedChunk(hash.Hash(h), buf)
 }
 
+// getCompressedFrom reads the CompressedChunk with addr |h| from |cr|.
+func getCompressedFrom(cr chunkReader, h addr) (cc CompressedChunk, err error) {
+	eg, ctx := errgroup.WithContext(context.Background())
+	reqs := []getRecord{{a: &h, prefix: h.Prefix()}}
+	_, err = cr.getManyCompressed(ctx, eg, reqs, func(_ context.Context, c CompressedChunk) {
+		cc = c
+	}, &Stats{})
+	if werr := eg.Wait(); err == nil {
+		err = werr
+	}
+	return
+}
+
 // getRange returns a Range for the chunk with addr |h|.
 func (wr *journalWriter) getRange(h addr) (rng Range, ok bool, err error) {
+	if wr.encrypted {
+		return Range{}, false, ErrEncryptedChunkLocations
+	}
 	// callers will use |rng| to read directly from the
 	// journal file, so we must flush here
 	if err = wr.maybeFlush(); err != nil {
@@ -333,6 +402,13 @@ func (wr *journalWriter) getRange(h addr) (rng Range, ok bool, err error) {
 func (wr *journalWriter) writeCompressedChunk(cc CompressedChunk) error {
 	wr.lock.Lock()
 	defer wr.lock.Unlock()
+	if wr.encrypted {
+		// the payload of the record is the sealed chunk
+		cc = CompressedChunk{
+			H:                   cc.H,
+			FullCompressedChunk: wr.enc.seal(nil, cc.FullCompressedChunk, cc.H[:]),
+		}
+	}
 	recordLen, payloadOff := chunkRecordSize(cc)
 	rng := Range{
 		Offset: uint64(wr.offset()) + uint64(payloadOff),
@@ -347,6 +423,27 @@ func (wr *journalWriter) writeCompressedChunk(cc CompressedChunk) error {
 	return nil
 }
 
+// writeEncryptionRecord begins the new journal with an encryption record for |enc|,
+// so that its chunks are encrypted with |enc|. It must be called before the journal
+// is bootstrapped.
+func (wr *journalWriter) writeEncryptionRecord(enc *Encryption) error {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if wr.offset() != 0 {
+		return errors.New("encryption record must be the first record of the chunk journal")
+	}
+	buf, err := wr.getBytes(encryptionRecordSize())
+	if err != nil {
+		return err
+	}
+	writeEncryptionRecord(buf, enc.id)
+	if err = wr.flush(); err != nil {
+		return err
+	}
+	wr.enc = enc
+	return wr.journal.Sync()
+}
+
 // commitRootHash commits |root| to the journal and syncs the file to disk.
 func (wr *journalWriter) commitRootHash(root hash.Hash) error {
 	wr.lock.Lock()
@@ -371,8 +468,12 @@ func (wr *journalWriter) commitRootHash(root hash.Hash) error {
 
 // flushIndexRecord writes a new record to the out-of-band journal index file. Index records
 // accelerate journal bootstrapping by reducing the amount of the journal that must be processed.
+// The lookups of an encrypted journal's index records are sealed with the record's root hash.
 func (wr *journalWriter) flushIndexRecord(root hash.Hash, end int64) (err error) {
 	payload := serializeLookups(wr.ranges.novelLookups())
+	if wr.encrypted {
+		payload = wr.enc.seal(nil, payload, root[:])
+	}
 	buf := make([]byte, journalIndexRecordSize(payload))
 	writeJournalIndexRecord(buf, root, uint64(wr.indexed), uint64(end), payload)
 	if _, err = wr.index.Write(buf); err != nil {
@@ -479,6 +580,139 @@ func (wr *journalWriter) snapshot() (io.ReadCloser, int64, error) {
 	}, wr.off, nil
 }
 
+// iterChunks calls |cb| with each chunk written to the journal file, up to the end of the
+// journal at the time of the call. It returns the offset at which the iteration stopped.
+// Chunks may be written to the journal concurrently, they are not visited.
+func (wr *journalWriter) iterChunks(ctx context.Context, cb func(cc CompressedChunk) error) (int64, error) {
+	wr.lock.Lock()
+	if err := wr.flush(); err != nil {
+		wr.lock.Unlock()
+		return 0, err
+	}
+	end := wr.off
+	wr.lock.Unlock()
+
+	// open a new file descriptor with an
+	// independent lifecycle from |wr.file|
+	f, err := os.Open(wr.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return processJournalRecords(ctx, io.NewSectionReader(f, 0, end), 0, func(o int64, r journalRec) error {
+		if r.kind != chunkJournalRecKind {
+			return nil
+		}
+		if wr.encrypted {
+			p, err := wr.enc.open(nil, r.payload, r.address[:])
+			if err != nil {
+				return fmt.Errorf("failed to decrypt chunk journal record: %w", err)
+			}
+			r.payload = p
+		}
+		cc, err := NewCompressedChunk(hash.Hash(r.address), r.payload)
+		if err != nil {
+			return err
+		}
+		return cb(cc)
+	})
+}
+
+// rotate replaces the journal file with one holding only the records written after offset
+// |end|, once the chunks before |end| have been compacted into the table file |compacted|.
+// The new journal ends with a root hash record for |root|. Lookups of chunks missing from
+// the new journal fall back to |compacted|, so that journalChunkSources in use before the
+// rotation keep serving the compacted chunks.
+func (wr *journalWriter) rotate(ctx context.Context, end int64, root hash.Hash, compacted chunkSource) (err error) {
+	wr.lock.Lock()
+	defer wr.lock.Unlock()
+	if err = wr.flush(); err != nil {
+		return err
+	} else if end > wr.off {
+		return fmt.Errorf("cannot rotate chunk journal at offset %d past its end %d", end, wr.off)
+	}
+
+	tmp := wr.path + journalRotateSuffix
+	if err = wr.writeRotatedJournal(tmp, end, root); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	// the journal index describes the old journal
+	// file, delete it before the file is replaced
+	if wr.index != nil {
+		_ = wr.index.Close()
+		wr.index = nil
+	}
+	idxPath := filepath.Join(filepath.Dir(wr.path), journalIndexFileName)
+	if err = os.Remove(idxPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err = wr.journal.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, wr.path); err != nil {
+		// keep using the old journal file
+		var rerr error
+		if wr.journal, rerr = os.OpenFile(wr.path, os.O_RDWR, 0666); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+	if wr.journal, err = os.OpenFile(wr.path, os.O_RDWR, 0666); err != nil {
+		return err
+	}
+
+	// reset the journal state and bootstrap the new file
+	wr.buf = wr.buf[:0]
+	wr.off, wr.indexed, wr.uncmpSz = 0, 0, 0
+	last, err := wr.bootstrap(ctx)
+	if err != nil {
+		return err
+	} else if last != root {
+		return fmt.Errorf("rotated chunk journal has root %s, expected %s", last.String(), root.String())
+	}
+
+	if wr.compacted != nil {
+		_ = wr.compacted.close()
+	}
+	wr.compacted = compacted
+	return nil
+}
+
+// writeRotatedJournal writes the records of the journal after offset |end| to a new
+// journal file at |path|, followed by a root hash record for |root|.
+func (wr *journalWriter) writeRotatedJournal(path string, end int64, root hash.Hash) (err error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if wr.encrypted {
+		buf := make([]byte, encryptionRecordSize())
+		writeEncryptionRecord(buf, wr.enc.id)
+		if _, err = f.Write(buf); err != nil {
+			return err
+		}
+	}
+	// chunk records are position independent, copy them as-is
+	if _, err = io.Copy(f, io.NewSectionReader(wr.journal, end, wr.off-end)); err != nil {
+		return err
+	}
+	buf := make([]byte, rootHashRecordSize())
+	writeRootHashRecord(buf, addr(root))
+	if _, err = f.Write(buf); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
 func (wr *journalWriter) offset() int64 {
 	return wr.off + int64(len(wr.buf))
 }
@@ -510,6 +744,9 @@ func (wr *journalWriter) Close() (err error) {
 	if wr.index != nil {
 		_ = wr.index.Close()
 	}
+	if wr.compacted != nil {
+		_ = wr.compacted.close()
+	}
 	if cerr := wr.journal.Sync(); cerr != nil {
 		err = cerr
 	}
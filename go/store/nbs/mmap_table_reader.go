@@ -0,0 +1,163 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ReadAdvice is a hint to the operating system about how table files will be read. It controls readahead, and so
+// how much of the page cache reads of table files use.
+type ReadAdvice int
+
+const (
+	// ReadAdviceNormal leaves readahead at the operating system's default.
+	ReadAdviceNormal ReadAdvice = iota
+	// ReadAdviceRandom disables readahead. Point reads only bring the pages they need into the page cache.
+	ReadAdviceRandom
+	// ReadAdviceSequential increases readahead, which helps scans of large tables.
+	ReadAdviceSequential
+	// ReadAdviceWillNeed reads table files into the page cache ahead of use.
+	ReadAdviceWillNeed
+)
+
+// ParseReadAdvice parses the name of a ReadAdvice.
+func ParseReadAdvice(s string) (ReadAdvice, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "normal":
+		return ReadAdviceNormal, nil
+	case "random":
+		return ReadAdviceRandom, nil
+	case "sequential":
+		return ReadAdviceSequential, nil
+	case "willneed":
+		return ReadAdviceWillNeed, nil
+	default:
+		return ReadAdviceNormal, fmt.Errorf("unknown read advice %q, must be one of normal, random, sequential or willneed", s)
+	}
+}
+
+func (a ReadAdvice) String() string {
+	switch a {
+	case ReadAdviceRandom:
+		return "random"
+	case ReadAdviceSequential:
+		return "sequential"
+	case ReadAdviceWillNeed:
+		return "willneed"
+	default:
+		return "normal"
+	}
+}
+
+// FileReadOptions configure how the table files of local stores are read.
+type FileReadOptions struct {
+	// Mmap maps table files into memory instead of reading them with pread. Encrypted table files and archives are
+	// always read with pread.
+	Mmap bool
+	// MaxMappedBytes limits the total size of the table files that are mapped at once. Table files that would
+	// exceed the limit are read with pread. Zero means no limit.
+	MaxMappedBytes int64
+	// Advice is given to the operating system for every table file, mapped or not.
+	Advice ReadAdvice
+}
+
+var fileReadOpts struct {
+	sync.RWMutex
+	opts FileReadOptions
+}
+
+// mappedBytes is the total size of the table files currently mapped.
+var mappedBytes int64
+
+// SetFileReadOptions configures how table files opened from now on are read.
+func SetFileReadOptions(opts FileReadOptions) {
+	fileReadOpts.Lock()
+	defer fileReadOpts.Unlock()
+	fileReadOpts.opts = opts
+}
+
+func getFileReadOptions() FileReadOptions {
+	fileReadOpts.RLock()
+	defer fileReadOpts.RUnlock()
+	return fileReadOpts.opts
+}
+
+// mappedFile is a table file mapped into memory. It is shared by the clones of a fileReaderAt and unmapped when
+// the last of them is closed.
+type mappedFile struct {
+	data []byte
+	refs int32
+}
+
+var _ io.ReaderAt = &mappedFile{}
+
+// mapTableFile maps the |sz| bytes of |f| if |opts| enable mmap and the mapping stays within the budget of mapped
+// bytes. It returns nil if |f| should be read with pread instead.
+func mapTableFile(f *os.File, sz int64, opts FileReadOptions) (*mappedFile, error) {
+	if !opts.Mmap || sz <= 0 || int64(int(sz)) != sz {
+		return nil, nil
+	}
+	if n := atomic.AddInt64(&mappedBytes, sz); opts.MaxMappedBytes > 0 && n > opts.MaxMappedBytes {
+		atomic.AddInt64(&mappedBytes, -sz)
+		return nil, nil
+	}
+	data, err := mmap(f, sz)
+	if err != nil {
+		atomic.AddInt64(&mappedBytes, -sz)
+		if err == errMmapUnsupported {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err = madvise(data, opts.Advice); err != nil {
+		munmap(data)
+		atomic.AddInt64(&mappedBytes, -sz)
+		return nil, err
+	}
+	return &mappedFile{data: data, refs: 1}, nil
+}
+
+func (m *mappedFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 || off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *mappedFile) ref() *mappedFile {
+	atomic.AddInt32(&m.refs, 1)
+	return m
+}
+
+func (m *mappedFile) release() error {
+	if atomic.AddInt32(&m.refs, -1) != 0 {
+		return nil
+	}
+	sz := int64(len(m.data))
+	err := munmap(m.data)
+	m.data = nil
+	atomic.AddInt64(&mappedBytes, -sz)
+	return err
+}
@@ -17,6 +17,34 @@
 
 package nbs
 
-import "os"
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
 
 var mmapAlignment = int64(os.Getpagesize())
+
+var errMmapUnsupported = errors.New("mmap is not supported on this platform")
+
+func mmap(f *os.File, sz int64) ([]byte, error) {
+	return unix.Mmap(int(f.Fd()), 0, int(sz), unix.PROT_READ, unix.MAP_SHARED)
+}
+
+func munmap(b []byte) error {
+	return unix.Munmap(b)
+}
+
+func madvise(b []byte, advice ReadAdvice) error {
+	switch advice {
+	case ReadAdviceRandom:
+		return unix.Madvise(b, unix.MADV_RANDOM)
+	case ReadAdviceSequential:
+		return unix.Madvise(b, unix.MADV_SEQUENTIAL)
+	case ReadAdviceWillNeed:
+		return unix.Madvise(b, unix.MADV_WILLNEED)
+	default:
+		return nil
+	}
+}
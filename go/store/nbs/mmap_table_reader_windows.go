@@ -14,4 +14,24 @@
 
 package nbs
 
+import (
+	"errors"
+	"os"
+)
+
 var mmapAlignment = int64(64 * 1024)
+
+// table files are always read with ReadAt on windows
+var errMmapUnsupported = errors.New("mmap is not supported on this platform")
+
+func mmap(f *os.File, sz int64) ([]byte, error) {
+	return nil, errMmapUnsupported
+}
+
+func munmap(b []byte) error {
+	return nil
+}
+
+func madvise(b []byte, advice ReadAdvice) error {
+	return nil
+}
@@ -40,6 +40,7 @@ func NewNBSMetricWrapper(nbs *NomsBlockStore) *NBSMetricWrapper {
 
 var _ chunks.TableFileStore = &NBSMetricWrapper{}
 var _ chunks.ChunkStoreGarbageCollector = &NBSMetricWrapper{}
+var _ chunks.UploadedTableFileStore = &NBSMetricWrapper{}
 
 // Sources retrieves the current root hash, a list of all the table files,
 // and a list of the appendix table files.
@@ -61,6 +62,11 @@ func (nbsMW *NBSMetricWrapper) AddTableFilesToManifest(ctx context.Context, file
 	return nbsMW.nbs.AddTableFilesToManifest(ctx, fileIdToNumChunks)
 }
 
+// HasUploadedTableFiles returns the table files in |fileIdToNumChunks| which were written to the wrapped block store
+func (nbsMW *NBSMetricWrapper) HasUploadedTableFiles(ctx context.Context, fileIdToNumChunks map[string]int) (map[string]int, error) {
+	return nbsMW.nbs.HasUploadedTableFiles(ctx, fileIdToNumChunks)
+}
+
 // SetRootChunk changes the root chunk hash from the previous value to the new root.
 func (nbsMW *NBSMetricWrapper) SetRootChunk(ctx context.Context, root, previous hash.Hash) error {
 	return nbsMW.nbs.SetRootChunk(ctx, root, previous)
@@ -0,0 +1,141 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// SharedCache is a content-addressed cache of table files in a directory shared by the databases on a machine. The
+// table files downloaded by a clone or a fetch are written to the cache first, and then hard linked into the database
+// that receives them, so that clones of the same remote share their table files on disk rather than each storing a
+// copy. Chunks found in the cache aren't downloaded again.
+//
+// The cache is itself a table file store, with a manifest listing the table files it holds, and may be used by many
+// processes at once. Table files are never removed from it, so it's safe to delete the directory when no dolt process
+// is using it; the databases linked to its table files keep their data.
+type SharedCache struct {
+	dir string
+	cs  *NomsBlockStore
+}
+
+// OpenSharedCache opens the shared cache in |dir| for databases of the format |nbfVerStr|, creating it if it doesn't
+// exist.
+func OpenSharedCache(ctx context.Context, nbfVerStr, dir string, q MemoryQuotaProvider) (*SharedCache, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	cacheOnce.Do(makeGlobalCaches)
+	m, err := getFileManifest(ctx, dir, syncFlush)
+	if err != nil {
+		return nil, err
+	}
+	// the table files of the cache are never conjoined, so that they keep the names they're looked up by
+	cs, err := newNomsBlockStore(ctx, nbfVerStr, makeManifestManager(m), newFSTablePersister(dir, q, nil), q, noopConjoiner{}, defaultMemTableSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the shared chunk cache at %s: %w", dir, err)
+	}
+	return &SharedCache{dir: dir, cs: cs}, nil
+}
+
+// Dir returns the directory of the cache
+func (c *SharedCache) Dir() string {
+	return c.dir
+}
+
+// GetManyCompressed gets the chunks in |hashes| which are in the cache
+func (c *SharedCache) GetManyCompressed(ctx context.Context, hashes hash.HashSet, found func(context.Context, CompressedChunk)) error {
+	return c.cs.GetManyCompressed(ctx, hashes, found)
+}
+
+// WriteTableFile writes the table file |fileId| to |sink|. If the cache doesn't have it yet, it's read with |getRd|
+// and added to the cache first. The table file is hard linked from the cache into |sink| when they are on the same
+// file system, and copied otherwise. A chunk journal is written to |sink| directly, as it isn't immutable.
+func (c *SharedCache) WriteTableFile(ctx context.Context, sink chunks.TableFileStore, fileId string, numChunks int, contentHash []byte, getRd func() (io.ReadCloser, uint64, error)) error {
+	if fileId == chunks.JournalFileID {
+		return sink.WriteTableFile(ctx, fileId, numChunks, contentHash, getRd)
+	}
+
+	path := filepath.Join(c.dir, fileId)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		// table files are written to a temp file and renamed, so a table file in the cache is always complete
+		err = c.cs.WriteTableFile(ctx, fileId, numChunks, contentHash, getRd)
+		if err != nil {
+			return err
+		}
+		err = c.cs.AddTableFilesToManifest(ctx, map[string]int{fileId: numChunks})
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if linked, err := linkTableFile(ctx, sink, path, fileId); err != nil || linked {
+		return err
+	}
+	return sink.WriteTableFile(ctx, fileId, numChunks, nil, func() (io.ReadCloser, uint64, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, uint64(info.Size()), nil
+	})
+}
+
+// Close closes the cache
+func (c *SharedCache) Close() error {
+	return c.cs.Close()
+}
+
+// tableFileLinker is implemented by table persisters which can add a table file by hard linking it
+type tableFileLinker interface {
+	// LinkTableFile adds the table file at |path| as |fileId| by hard linking it
+	LinkTableFile(ctx context.Context, path, fileId string) error
+}
+
+// linkTableFile hard links the table file at |path| into |sink| as |fileId|. linked is false if |sink| doesn't store
+// its table files on the local file system, or the link couldn't be made, such as when |path| is on another device.
+func linkTableFile(ctx context.Context, sink chunks.TableFileStore, path, fileId string) (linked bool, err error) {
+	if gcs, ok := sink.(*GenerationalNBS); ok {
+		sink = gcs.newGen
+	}
+	nbs, ok := sink.(*NomsBlockStore)
+	if !ok {
+		return false, nil
+	}
+	tfl, ok := nbs.p.(tableFileLinker)
+	if !ok {
+		return false, nil
+	}
+	err = tfl.LinkTableFile(ctx, path, fileId)
+	if errors.Is(err, errReadOnlyManifest) {
+		return false, err
+	}
+	return err == nil, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/constants"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestSharedCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	q := NewUnlimitedMemQuotaProvider()
+
+	var chnks []chunks.Chunk
+	hashes := hash.NewHashSet()
+	for i := 0; i < 8; i++ {
+		c := chunks.NewChunk([]byte{byte(i), 1, 2, 3})
+		chnks = append(chnks, c)
+		hashes.Insert(c.Hash())
+	}
+	fileId, data, err := WriteChunks(chnks)
+	require.NoError(t, err)
+
+	downloads := 0
+	getRd := func() (io.ReadCloser, uint64, error) {
+		downloads++
+		return io.NopCloser(bytes.NewReader(data)), uint64(len(data)), nil
+	}
+
+	cacheDir := filepath.Join(dir, "cache")
+	cache, err := OpenSharedCache(ctx, constants.FormatDefaultString, cacheDir, q)
+	require.NoError(t, err)
+
+	var sinks []*NomsBlockStore
+	for _, name := range []string{"a", "b"} {
+		sinkDir := filepath.Join(dir, name)
+		require.NoError(t, os.Mkdir(sinkDir, os.ModePerm))
+		sink, err := NewLocalStore(ctx, constants.FormatDefaultString, sinkDir, defaultMemTableSize, q)
+		require.NoError(t, err)
+		defer sink.Close()
+
+		err = cache.WriteTableFile(ctx, sink, fileId, len(chnks), nil, getRd)
+		require.NoError(t, err)
+		err = sink.AddTableFilesToManifest(ctx, map[string]int{fileId: len(chnks)})
+		require.NoError(t, err)
+		sinks = append(sinks, sink)
+	}
+	assert.Equal(t, 1, downloads)
+	require.NoError(t, cache.Close())
+
+	// the table files of the sinks are links to the table file of the cache
+	cached, err := os.Stat(filepath.Join(cacheDir, fileId))
+	require.NoError(t, err)
+	for _, name := range []string{"a", "b"} {
+		linked, err := os.Stat(filepath.Join(dir, name, fileId))
+		require.NoError(t, err)
+		assert.True(t, os.SameFile(cached, linked))
+	}
+	for _, sink := range sinks {
+		for _, c := range chnks {
+			got, err := sink.Get(ctx, c.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, c.Data(), got.Data())
+		}
+	}
+
+	// the chunks of the table file can be read from the cache once it's reopened
+	cache, err = OpenSharedCache(ctx, constants.FormatDefaultString, cacheDir, q)
+	require.NoError(t, err)
+	defer cache.Close()
+	var mu sync.Mutex
+	found := hash.NewHashSet()
+	absent := hashes.Copy()
+	absent.Insert(hash.Of([]byte("not cached")))
+	err = cache.GetManyCompressed(ctx, absent, func(ctx context.Context, c CompressedChunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		found.Insert(c.H)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hashes, found)
+}
@@ -40,10 +40,12 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 
 	"github.com/dolthub/dolt/go/store/blobstore"
 	"github.com/dolthub/dolt/go/store/chunks"
 	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/util/tempfiles"
 )
 
 var (
@@ -85,6 +87,20 @@ type NBSCompressedChunkStore interface {
 	GetManyCompressed(context.Context, hash.HashSet, func(context.Context, CompressedChunk)) error
 }
 
+// TableFileConjoiner is implemented by chunk stores which can conjoin their table files in the background.
+type TableFileConjoiner interface {
+	// ConjoinTableFiles conjoins the store's table files if it has more than |maxTables| of them, reading them no
+	// faster than |lim| allows, and returns whether it did.
+	ConjoinTableFiles(ctx context.Context, maxTables int, lim *rate.Limiter) (bool, error)
+}
+
+// ChunkArchiver is implemented by chunk stores which can move chunks into archive table files, see archive.go.
+type ChunkArchiver interface {
+	// ArchiveChunks moves the chunks of each of |groups| into an archive of its own and returns the number of chunks
+	// moved. Chunks that are already archived are only moved if |rebuild| is set.
+	ArchiveChunks(ctx context.Context, groups []hash.HashSet, rebuild bool) (int, error)
+}
+
 type NomsBlockStore struct {
 	mm manifestManager
 	p  tablePersister
@@ -104,11 +120,21 @@ type NomsBlockStore struct {
 
 	hasCache *lru.TwoQueueCache[addr, struct{}]
 
+	// compactWg tracks background compactions of the chunk journal
+	compactWg sync.WaitGroup
+
+	// transfers holds the table files the chunks of archives are served from, by archive, see archiveTransfer.
+	transferMu sync.Mutex
+	transfers  map[addr]*archiveTransfer
+
 	stats *Stats
 }
 
 var _ chunks.TableFileStore = &NomsBlockStore{}
 var _ chunks.ChunkStoreGarbageCollector = &NomsBlockStore{}
+var _ chunks.UploadedTableFileStore = &NomsBlockStore{}
+var _ ChunkArchiver = &NomsBlockStore{}
+var _ TableFileConjoiner = &NomsBlockStore{}
 
 // 20-byte keys, ~2MB of key data.
 //
@@ -139,12 +165,21 @@ func (nbs *NomsBlockStore) GetChunkLocations(hashes hash.HashSet) (map[hash.Hash
 
 	fn := func(css chunkSourceSet) error {
 		for _, cs := range css {
-			rng, err := cs.getRecordRanges(gr)
-			if err != nil {
-				return err
+			var rng map[hash.Hash]Range
+			h := hash.Hash(cs.hash())
+			if ar, ok := cs.(archiveReader); ok {
+				t, err := nbs.archiveTransfer(context.Background(), ar)
+				if err != nil {
+					return err
+				}
+				rng, h = t.getRecordRanges(gr), hash.Hash(t.name)
+			} else {
+				var err error
+				if rng, err = cs.getRecordRanges(gr); err != nil {
+					return err
+				}
 			}
 
-			h := hash.Hash(cs.hash())
 			if m, ok := ranges[h]; ok {
 				for k, v := range rng {
 					m[k] = v
@@ -172,8 +207,9 @@ func (nbs *NomsBlockStore) GetChunkLocations(hashes hash.HashSet) (map[hash.Hash
 }
 
 func (nbs *NomsBlockStore) conjoinIfRequired(ctx context.Context) (bool, error) {
-	if nbs.c.conjoinRequired(nbs.tables) {
-		newUpstream, cleanup, err := conjoin(ctx, nbs.c, nbs.upstream, nbs.mm, nbs.p, nbs.stats)
+	c := withoutArchives(nbs.c, nbs.tables)
+	if c.conjoinRequired(nbs.tables) {
+		newUpstream, cleanup, err := conjoin(ctx, c, nbs.upstream, nbs.mm, nbs.p, nbs.stats)
 		if err != nil {
 			return false, err
 		}
@@ -197,6 +233,100 @@ func (nbs *NomsBlockStore) conjoinIfRequired(ctx context.Context) (bool, error)
 	}
 }
 
+// ConjoinTableFiles conjoins the store's table files if it has more than |maxTables| of them. Unlike the conjoins
+// forced by manifest updates, the new table file is written without holding the store's lock, reading the table
+// files being conjoined no faster than |lim| allows. It returns false if there was nothing to conjoin, or if the
+// conjoined table files were replaced while it ran.
+func (nbs *NomsBlockStore) ConjoinTableFiles(ctx context.Context, maxTables int, lim *rate.Limiter) (ok bool, err error) {
+	if _, ok := nbs.c.(noopConjoiner); ok {
+		return false, nil
+	}
+
+	nbs.mu.RLock()
+	c := withoutArchives(journalConjoiner{child: inlineConjoiner{maxTables}}, nbs.tables)
+	required := !nbs.gcInProgress && c.conjoinRequired(nbs.tables)
+	upstream := nbs.upstream
+	nbs.mu.RUnlock()
+	if !required {
+		return false, nil
+	}
+
+	if upstream.NumAppendixSpecs() != 0 {
+		upstream, _ = upstream.removeAppendixSpecs()
+	}
+	conjoinees, _, err := c.chooseConjoinees(upstream.specs)
+	if err != nil {
+		return false, err
+	}
+	conjoined, cleanup, err := conjoinTables(ctx, conjoinees, throttledPersister{nbs.p, lim}, nbs.stats)
+	if err != nil {
+		return false, err
+	}
+	defer cleanup()
+
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+	if nbs.gcInProgress {
+		return false, nil
+	}
+
+	nbs.mm.LockForUpdate()
+	defer func() {
+		unlockErr := nbs.mm.UnlockForUpdate()
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	// replace the conjoinees in the current upstream, if they are all still there
+	conjoineeSet := make(map[addr]struct{}, len(conjoinees))
+	for _, spec := range conjoinees {
+		conjoineeSet[spec.name] = struct{}{}
+	}
+	current := nbs.upstream
+	appendix := current.getAppendixSet()
+	var appendixSpecs, keepers []tableSpec
+	for _, spec := range current.specs {
+		if _, ok := conjoineeSet[spec.name]; ok {
+			delete(conjoineeSet, spec.name)
+		} else if _, ok = appendix[spec.name]; ok {
+			appendixSpecs = append(appendixSpecs, spec)
+		} else {
+			keepers = append(keepers, spec)
+		}
+	}
+	if len(conjoineeSet) > 0 {
+		return false, nil
+	}
+	specs := append(append(appendixSpecs, conjoined), keepers...)
+
+	newContents := manifestContents{
+		nbfVers:  current.nbfVers,
+		root:     current.root,
+		lock:     generateLockHash(current.root, specs, current.appendix),
+		gcGen:    current.gcGen,
+		specs:    specs,
+		appendix: current.appendix,
+	}
+	updated, err := nbs.mm.Update(ctx, current.lock, newContents, nbs.stats, nil)
+	if err != nil {
+		return false, err
+	} else if updated.lock != newContents.lock {
+		return false, nil
+	}
+
+	ts, err := nbs.tables.rebase(ctx, updated.specs, nbs.stats)
+	if err != nil {
+		return false, err
+	}
+	oldTables := nbs.tables
+	nbs.tables, nbs.upstream = ts, updated
+	if err = oldTables.close(); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
 func (nbs *NomsBlockStore) UpdateManifest(ctx context.Context, updates map[hash.Hash]uint32) (mi ManifestInfo, err error) {
 	nbs.mu.Lock()
 	defer nbs.mu.Unlock()
@@ -534,10 +664,15 @@ func NewBSStore(ctx context.Context, nbfVerStr string, bs blobstore.Blobstore, m
 }
 
 func NewLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, q MemoryQuotaProvider) (*NomsBlockStore, error) {
-	return newLocalStore(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables, q)
+	return newLocalStore(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables, q, nil)
+}
+
+// NewLocalStoreWithEncryption returns a local store which encrypts the table files it writes with |enc|.
+func NewLocalStoreWithEncryption(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, q MemoryQuotaProvider, enc *Encryption) (*NomsBlockStore, error) {
+	return newLocalStore(ctx, nbfVerStr, dir, memTableSize, defaultMaxTables, q, enc)
 }
 
-func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, maxTables int, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, maxTables int, q MemoryQuotaProvider, enc *Encryption) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 	if err := checkDir(dir); err != nil {
 		return nil, err
@@ -553,13 +688,23 @@ func newLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSi
 	if err != nil {
 		return nil, err
 	}
-	p := newFSTablePersister(dir, q)
+	p := newFSTablePersister(dir, q, enc)
 	c := conjoinStrategy(inlineConjoiner{maxTables})
 
 	return newNomsBlockStore(ctx, nbfVerStr, makeManifestManager(m), p, q, c, memTableSize)
 }
 
 func NewLocalJournalingStore(ctx context.Context, nbfVers, dir string, q MemoryQuotaProvider) (*NomsBlockStore, error) {
+	return newLocalJournalingStore(ctx, nbfVers, dir, q, nil)
+}
+
+// NewLocalJournalingStoreWithEncryption returns a local journaling store which encrypts the chunk journal and table
+// files it writes with |enc|.
+func NewLocalJournalingStoreWithEncryption(ctx context.Context, nbfVers, dir string, q MemoryQuotaProvider, enc *Encryption) (*NomsBlockStore, error) {
+	return newLocalJournalingStore(ctx, nbfVers, dir, q, enc)
+}
+
+func newLocalJournalingStore(ctx context.Context, nbfVers, dir string, q MemoryQuotaProvider, enc *Encryption) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 	if err := checkDir(dir); err != nil {
 		return nil, err
@@ -569,7 +714,7 @@ func NewLocalJournalingStore(ctx context.Context, nbfVers, dir string, q MemoryQ
 	if err != nil {
 		return nil, err
 	}
-	p := newFSTablePersister(dir, q)
+	p := newFSTablePersister(dir, q, enc)
 
 	journal, err := newChunkJournal(ctx, nbfVers, dir, m, p.(*fsTablePersister))
 	if err != nil {
@@ -1299,17 +1444,126 @@ func (nbs *NomsBlockStore) updateManifest(ctx context.Context, current, last has
 
 	nbs.upstream = newContents
 	nbs.tables = newTables
+	nbs.compactJournalIfRequired()
 
 	return nil
 }
 
+// compactJournalIfRequired starts a background compaction of the chunk journal into a
+// table file if the journal has grown large. Commits keep appending to the journal while
+// the table file is written. callers must acquire lock |nbs.mu|
+func (nbs *NomsBlockStore) compactJournalIfRequired() {
+	j, ok := nbs.p.(*chunkJournal)
+	if !ok || nbs.gcInProgress || !j.compactionRequired() {
+		return
+	}
+	if !j.compacting.CompareAndSwap(false, true) {
+		return
+	}
+	wr := j.wr
+	nbs.compactWg.Add(1)
+	go func() {
+		defer nbs.compactWg.Done()
+		defer j.compacting.Store(false)
+		// if compaction fails the journal is left as it was,
+		// and compaction is retried after the next commit
+		_ = nbs.compactJournal(context.Background(), j, wr)
+	}()
+}
+
+// compactJournal writes the chunks of the journal |wr| into a table file, adds the table
+// file to the manifest and rotates the compacted chunks out of the journal.
+func (nbs *NomsBlockStore) compactJournal(ctx context.Context, j *chunkJournal, wr *journalWriter) (err error) {
+	spec, end, err := j.writeCompactedTable(ctx, wr)
+	if err != nil || spec.chunkCount == 0 {
+		return err
+	}
+
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+	if nbs.gcInProgress || j.wr != wr {
+		// the journal was dropped by GC, the table file
+		// is pruned with the next garbage collection
+		return nil
+	}
+
+	nbs.mm.LockForUpdate()
+	defer func() {
+		unlockErr := nbs.mm.UnlockForUpdate()
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	specs := append(append([]tableSpec{}, nbs.upstream.specs...), spec)
+	newContents := manifestContents{
+		nbfVers:  nbs.upstream.nbfVers,
+		root:     nbs.upstream.root,
+		lock:     generateLockHash(nbs.upstream.root, specs, nbs.upstream.appendix),
+		gcGen:    nbs.upstream.gcGen,
+		specs:    specs,
+		appendix: nbs.upstream.appendix,
+	}
+	upstream, err := nbs.mm.Update(ctx, nbs.upstream.lock, newContents, nbs.stats, nil)
+	if err != nil {
+		return err
+	} else if upstream.lock != newContents.lock {
+		return errOptimisticLockFailedTables
+	}
+
+	ts, err := nbs.tables.rebase(ctx, upstream.specs, nbs.stats)
+	if err != nil {
+		return err
+	}
+	oldTables := nbs.tables
+	nbs.tables, nbs.upstream = ts, upstream
+	if err = oldTables.close(); err != nil {
+		return err
+	}
+
+	// the journal falls back to the compacted table for
+	// tableSets opened before the table file was added
+	compacted, err := ts.upstream[spec.name].clone()
+	if err != nil {
+		return err
+	}
+	if err = wr.rotate(ctx, end, upstream.root, compacted); err != nil {
+		_ = compacted.close()
+		return err
+	}
+	return nil
+}
+
 func (nbs *NomsBlockStore) Version() string {
 	nbs.mu.RLock()
 	defer nbs.mu.RUnlock()
 	return nbs.upstream.nbfVers
 }
 
+var _ EncryptedStore = &NomsBlockStore{}
+
+// Encryption returns the Encryption of the store's table files and chunk journal, or nil if they aren't encrypted.
+func (nbs *NomsBlockStore) Encryption() *Encryption {
+	switch p := nbs.p.(type) {
+	case *fsTablePersister:
+		return p.enc
+	case *chunkJournal:
+		return p.persister.enc
+	default:
+		return nil
+	}
+}
+
 func (nbs *NomsBlockStore) Close() (err error) {
+	nbs.compactWg.Wait()
+	nbs.transferMu.Lock()
+	for _, cs := range nbs.transfers {
+		if cerr := cs.close(); cerr != nil {
+			err = cerr
+		}
+	}
+	nbs.transfers = nil
+	nbs.transferMu.Unlock()
 	if cerr := nbs.p.Close(); cerr != nil {
 		err = cerr
 	}
@@ -1376,14 +1630,24 @@ func (nbs *NomsBlockStore) Sources(ctx context.Context) (hash.Hash, []chunks.Tab
 		return hash.Hash{}, nil, nil, err
 	}
 
-	appendixTableFiles, err := getTableFiles(css, contents, contents.NumAppendixSpecs(), func(mc manifestContents, idx int) tableSpec {
+	// archives are sent as the table files holding their chunks
+	transfers := make(map[addr]*archiveTransfer)
+	for a, cs := range css {
+		if ar, ok := cs.(archiveReader); ok {
+			if transfers[a], err = nbs.archiveTransfer(ctx, ar); err != nil {
+				return hash.Hash{}, nil, nil, err
+			}
+		}
+	}
+
+	appendixTableFiles, err := getTableFiles(css, transfers, contents, contents.NumAppendixSpecs(), func(mc manifestContents, idx int) tableSpec {
 		return mc.getAppendixSpec(idx)
 	})
 	if err != nil {
 		return hash.Hash{}, nil, nil, err
 	}
 
-	allTableFiles, err := getTableFiles(css, contents, contents.NumTableSpecs(), func(mc manifestContents, idx int) tableSpec {
+	allTableFiles, err := getTableFiles(css, transfers, contents, contents.NumTableSpecs(), func(mc manifestContents, idx int) tableSpec {
 		return mc.getSpec(idx)
 	})
 	if err != nil {
@@ -1393,13 +1657,22 @@ func (nbs *NomsBlockStore) Sources(ctx context.Context) (hash.Hash, []chunks.Tab
 	return contents.GetRoot(), allTableFiles, appendixTableFiles, nil
 }
 
-func getTableFiles(css map[addr]chunkSource, contents manifestContents, numSpecs int, specFunc func(mc manifestContents, idx int) tableSpec) ([]chunks.TableFile, error) {
+func getTableFiles(css map[addr]chunkSource, transfers map[addr]*archiveTransfer, contents manifestContents, numSpecs int, specFunc func(mc manifestContents, idx int) tableSpec) ([]chunks.TableFile, error) {
 	tableFiles := make([]chunks.TableFile, 0)
 	if numSpecs == 0 {
 		return tableFiles, nil
 	}
 	for i := 0; i < numSpecs; i++ {
 		info := specFunc(contents, i)
+		if t, ok := transfers[info.name]; ok {
+			tableFiles = append(tableFiles, tableFile{
+				info: tableSpec{name: t.name, chunkCount: t.count()},
+				open: func(ctx context.Context) (io.ReadCloser, uint64, error) {
+					return io.NopCloser(t.reader(ctx, &Stats{})), t.size(), nil
+				},
+			})
+			continue
+		}
 		cs, ok := css[info.name]
 		if !ok {
 			return nil, ErrSpecWithoutChunkSource
@@ -1510,6 +1783,27 @@ func (nbs *NomsBlockStore) AddTableFilesToManifest(ctx context.Context, fileIdTo
 	return err
 }
 
+// HasUploadedTableFiles returns the table files in |fileIdToNumChunks| which were written to this store's persister
+// and so can be added to its manifest
+func (nbs *NomsBlockStore) HasUploadedTableFiles(ctx context.Context, fileIdToNumChunks map[string]int) (map[string]int, error) {
+	uploaded := make(map[string]int)
+	for fileId, numChunks := range fileIdToNumChunks {
+		fileIdHash, ok := hash.MaybeParse(fileId)
+		if !ok {
+			return nil, errors.New("invalid base32 encoded hash: " + fileId)
+		}
+
+		ok, err := nbs.p.Exists(ctx, addr(fileIdHash), uint32(numChunks), nbs.stats)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			uploaded[fileId] = numChunks
+		}
+	}
+	return uploaded, nil
+}
+
 // PruneTableFiles deletes old table files that are no longer referenced in the manifest.
 func (nbs *NomsBlockStore) PruneTableFiles(ctx context.Context) (err error) {
 	return nbs.pruneTableFiles(ctx, nbs.hasMany)
@@ -1528,10 +1822,280 @@ func (nbs *NomsBlockStore) pruneTableFiles(ctx context.Context, checker refCheck
 		for a, _ := range nbs.tables.upstream {
 			keepers = append(keepers, a)
 		}
+
+		nbs.transferMu.Lock()
+		defer nbs.transferMu.Unlock()
+		for a, t := range nbs.transfers {
+			if _, ok := nbs.tables.upstream[a]; !ok {
+				// the archive is gone, and so is its transfer
+				_ = t.close()
+				delete(nbs.transfers, a)
+			}
+		}
 		return keepers
 	}, mtime)
 }
 
+// archiveTransfer returns the table file the chunks of the archive |ar| are served from, see
+// archive_transfer.go. It's built on first use and kept for as long as the store holds the archive.
+func (nbs *NomsBlockStore) archiveTransfer(ctx context.Context, ar archiveReader) (*archiveTransfer, error) {
+	nbs.transferMu.Lock()
+	defer nbs.transferMu.Unlock()
+	if t, ok := nbs.transfers[ar.hash()]; ok {
+		return t, nil
+	}
+	t, err := newArchiveTransfer(ctx, ar, nbs.stats)
+	if err != nil {
+		return nil, err
+	}
+	if nbs.transfers == nil {
+		nbs.transfers = make(map[addr]*archiveTransfer)
+	}
+	nbs.transfers[ar.hash()] = t
+	return t, nil
+}
+
+// OpenTransferFile returns a reader of the table file |fileID| if it is the table file the chunks
+// of one of the store's archives are served from, see archiveTransfer. These table files are never
+// written out, so they can't be read from the store's directory like its other table files.
+func (nbs *NomsBlockStore) OpenTransferFile(ctx context.Context, fileID string) (*io.SectionReader, bool, error) {
+	name, err := parseAddr(fileID)
+	if err != nil {
+		return nil, false, nil
+	}
+	find := func() *archiveTransfer {
+		nbs.transferMu.Lock()
+		defer nbs.transferMu.Unlock()
+		for _, t := range nbs.transfers {
+			if t.name == name {
+				return t
+			}
+		}
+		return nil
+	}
+	t := find()
+	if t == nil {
+		// the transfer may not have been built since the store was opened
+		nbs.mu.RLock()
+		tables := nbs.tables
+		nbs.mu.RUnlock()
+		for _, cs := range tables.upstream {
+			if ar, ok := cs.(archiveReader); ok {
+				if _, err = nbs.archiveTransfer(ctx, ar); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+		if t = find(); t == nil {
+			return nil, false, nil
+		}
+	}
+	return t.reader(ctx, nbs.stats), true, nil
+}
+
+// ArchiveChunks moves the chunks of each of |groups| held in the store's table files into an archive of its own, see
+// archive.go. Each table file holding any of them is replaced by a table file holding the rest of its chunks. The
+// replaced table files are deleted by PruneTableFiles. Chunks in the chunk journal are not moved, and neither are
+// chunks in existing archives unless |rebuild| is set, in which case archives are replaced like table files.
+// It returns the number of chunks archived.
+func (nbs *NomsBlockStore) ArchiveChunks(ctx context.Context, groups []hash.HashSet, rebuild bool) (archived int, err error) {
+	nbs.mu.Lock()
+	defer nbs.mu.Unlock()
+	if nbs.gcInProgress {
+		return 0, errors.New("cannot archive chunks while garbage collection is in progress")
+	}
+	tfp, ok := nbs.p.(tableFilePersister)
+	if !ok {
+		return 0, chunks.ErrUnsupportedOperation
+	}
+
+	nbs.mm.LockForUpdate()
+	defer func() {
+		unlockErr := nbs.mm.UnlockForUpdate()
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	groupOf := make(map[addr]int)
+	for i, g := range groups {
+		for h := range g {
+			groupOf[addr(h)] = i
+		}
+	}
+	writers := make([]*archiveWriter, len(groups))
+	defer func() {
+		for _, aw := range writers {
+			if aw != nil {
+				aw.remove()
+			}
+		}
+	}()
+
+	appendix := nbs.upstream.getAppendixSet()
+	seen := make(map[addr]struct{})
+	replaced := make(map[addr]struct{})
+	var rewritten []tableSpec
+	for _, spec := range nbs.upstream.specs {
+		if _, ok := appendix[spec.name]; ok || isJournalAddr(spec.name) {
+			continue
+		}
+		cs, ok := nbs.tables.upstream[spec.name]
+		if !ok {
+			return 0, ErrSpecWithoutChunkSource
+		}
+
+		var recs []getRecord
+		if ar, ok := cs.(archiveReader); ok {
+			if !rebuild {
+				continue
+			}
+			recs = ar.records()
+		} else {
+			idx, err := cs.index()
+			if err != nil {
+				return 0, err
+			}
+			recs = make([]getRecord, idx.chunkCount())
+			for i := range recs {
+				a := new(addr)
+				if _, err = idx.indexEntry(uint32(i), a); err != nil {
+					return 0, err
+				}
+				recs[i] = getRecord{a: a, prefix: a.Prefix()}
+			}
+		}
+		var cold, warm []getRecord
+		for _, rec := range recs {
+			if _, ok := groupOf[*rec.a]; ok {
+				cold = append(cold, rec)
+			} else {
+				warm = append(warm, rec)
+			}
+		}
+		if len(cold) == 0 {
+			continue
+		}
+
+		err = getManySerially(ctx, cs, cold, nbs.stats, func(cc CompressedChunk) error {
+			if _, ok := seen[addr(cc.H)]; ok {
+				return nil
+			}
+			seen[addr(cc.H)] = struct{}{}
+			c, err := cc.ToChunk()
+			if err != nil {
+				return err
+			}
+			g := groupOf[addr(cc.H)]
+			if writers[g] == nil {
+				if writers[g], err = newArchiveWriter(tempfiles.MovableTempFileProvider.GetTempDir(), nbs.Encryption()); err != nil {
+					return err
+				}
+			}
+			return writers[g].addChunk(c)
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if len(warm) > 0 {
+			gcc, err := newGarbageCollectionCopier(nbs.Encryption())
+			if err != nil {
+				return 0, err
+			}
+			err = getManySerially(ctx, cs, warm, nbs.stats, func(cc CompressedChunk) error {
+				return gcc.addChunk(ctx, cc)
+			})
+			if err != nil {
+				return 0, err
+			}
+			specs, err := gcc.copyTablesToDir(ctx, tfp)
+			if err != nil {
+				return 0, err
+			}
+			rewritten = append(rewritten, specs...)
+		}
+		replaced[spec.name] = struct{}{}
+	}
+	if len(seen) == 0 {
+		return 0, nil
+	}
+
+	for _, aw := range writers {
+		if aw == nil {
+			continue
+		}
+		name, err := aw.finish()
+		if err != nil {
+			return 0, err
+		}
+		r, sz, err := aw.reader()
+		if err != nil {
+			return 0, err
+		}
+		if err = tfp.CopyTableFile(ctx, r, name.String(), sz, uint32(aw.chunkCount())); err != nil {
+			return 0, err
+		}
+		rewritten = append(rewritten, tableSpec{name: name, chunkCount: uint32(aw.chunkCount())})
+	}
+
+	specs := make([]tableSpec, 0, len(nbs.upstream.specs)+len(rewritten))
+	for _, spec := range nbs.upstream.specs {
+		if _, ok := replaced[spec.name]; !ok {
+			specs = append(specs, spec)
+		}
+	}
+	specs = append(specs, rewritten...)
+
+	newContents := manifestContents{
+		nbfVers:  nbs.upstream.nbfVers,
+		root:     nbs.upstream.root,
+		lock:     generateLockHash(nbs.upstream.root, specs, nbs.upstream.appendix),
+		gcGen:    nbs.upstream.gcGen,
+		specs:    specs,
+		appendix: nbs.upstream.appendix,
+	}
+	upstream, err := nbs.mm.Update(ctx, nbs.upstream.lock, newContents, nbs.stats, nil)
+	if err != nil {
+		return 0, err
+	} else if upstream.lock != newContents.lock {
+		return 0, errOptimisticLockFailedTables
+	}
+
+	ts, err := nbs.tables.rebase(ctx, upstream.specs, nbs.stats)
+	if err != nil {
+		return 0, err
+	}
+	oldTables := nbs.tables
+	nbs.tables, nbs.upstream = ts, upstream
+	if err = oldTables.close(); err != nil {
+		return 0, err
+	}
+	return len(seen), nil
+}
+
+// getManySerially reads the chunks of |reqs| from |cs|, calling |found| for each of them in turn.
+func getManySerially(ctx context.Context, cs chunkSource, reqs []getRecord, stats *Stats, found func(CompressedChunk) error) error {
+	sort.Sort(getRecordByPrefix(reqs))
+	var mu sync.Mutex
+	var ferr error
+	eg, ctx := errgroup.WithContext(ctx)
+	_, err := cs.getManyCompressed(ctx, eg, reqs, func(_ context.Context, cc CompressedChunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ferr == nil {
+			ferr = found(cc)
+		}
+	}, stats)
+	if werr := eg.Wait(); err == nil {
+		err = werr
+	}
+	if err != nil {
+		return err
+	}
+	return ferr
+}
+
 func (nbs *NomsBlockStore) BeginGC(keeper func(hash.Hash) bool) error {
 	nbs.cond.L.Lock()
 	defer nbs.cond.L.Unlock()
@@ -1610,7 +2174,7 @@ func (nbs *NomsBlockStore) copyMarkedChunks(ctx context.Context, keepChunks <-ch
 		return nil, fmt.Errorf("NBS does not support copying garbage collection")
 	}
 
-	gcc, err := newGarbageCollectionCopier()
+	gcc, err := newGarbageCollectionCopier(dest.Encryption())
 	if err != nil {
 		return nil, err
 	}
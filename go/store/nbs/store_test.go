@@ -31,6 +31,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
 
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/libraries/utils/test"
@@ -53,7 +54,7 @@ func makeTestLocalStore(t *testing.T, maxTableFiles int) (st *NomsBlockStore, no
 	require.NoError(t, err)
 
 	q = NewUnlimitedMemQuotaProvider()
-	st, err = newLocalStore(ctx, types.Format_Default.VersionString(), nomsDir, defaultMemTableSize, maxTableFiles, q)
+	st, err = newLocalStore(ctx, types.Format_Default.VersionString(), nomsDir, defaultMemTableSize, maxTableFiles, q, nil)
 	require.NoError(t, err)
 	return st, nomsDir, q
 }
@@ -649,3 +650,55 @@ func TestGuessPrefixOrdinal(t *testing.T) {
 		assert.Equal(t, i, guess)
 	}
 }
+
+func TestNBSConjoinTableFiles(t *testing.T) {
+	ctx := context.Background()
+	st, nomsDir, q := makeTestLocalStore(t, defaultMaxTables)
+
+	data := make(map[hash.Hash]chunks.Chunk)
+	for i := 0; i < 16; i++ {
+		var root hash.Hash
+		for h, c := range makeChunkSet(64, 64) {
+			require.NoError(t, st.Put(ctx, c, noopGetAddrs))
+			data[h], root = c, h
+		}
+		last, err := st.Root(ctx)
+		require.NoError(t, err)
+		ok, err := st.Commit(ctx, root, last)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.Equal(t, 16, len(st.upstream.specs))
+
+	ok, err := st.ConjoinTableFiles(ctx, 32, nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	lim := rate.NewLimiter(rate.Limit(1<<20), 4096)
+	ok, err = st.ConjoinTableFiles(ctx, 4, lim)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.LessOrEqual(t, len(st.upstream.specs), 4)
+
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	for h, c := range data {
+		act, err := st.Get(ctx, h)
+		require.NoError(t, err)
+		require.Equal(t, c.Data(), act.Data())
+	}
+	require.NoError(t, st.Close())
+
+	st, err = newLocalStore(ctx, types.Format_Default.VersionString(), nomsDir, defaultMemTableSize, defaultMaxTables, q, nil)
+	require.NoError(t, err)
+	defer st.Close()
+	act, err := st.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, root, act)
+	assert.LessOrEqual(t, len(st.upstream.specs), 4)
+	for h, c := range data {
+		act, err := st.Get(ctx, h)
+		require.NoError(t, err)
+		require.Equal(t, c.Data(), act.Data())
+	}
+}
@@ -65,6 +65,10 @@ type tableIndex interface {
 	// entry corresponds to an indexed chunk address.
 	prefixes() ([]uint64, error)
 
+	// mayHave returns false if the chunk corresponding to the address |h|
+	// is definitely not in the indexed file, without searching the index.
+	mayHave(h *addr) bool
+
 	// chunkCount returns the total number of chunks in the indexed file.
 	chunkCount() uint32
 
@@ -122,7 +126,7 @@ func parseTableIndex(ctx context.Context, buff []byte, q MemoryQuotaProvider) (o
 	if err != nil {
 		return onHeapTableIndex{}, err
 	}
-	idx, err := newOnHeapTableIndex(buff, offsetsBuff1, chunkCount, totalUncompressedData, q)
+	idx, err := newOnHeapTableIndex(ctx, buff, offsetsBuff1, chunkCount, totalUncompressedData, q)
 	if err != nil {
 		q.ReleaseQuotaBytes(len(offsetsBuff1))
 	}
@@ -131,13 +135,13 @@ func parseTableIndex(ctx context.Context, buff []byte, q MemoryQuotaProvider) (o
 
 // similar to parseTableIndex except that it uses the given |offsetsBuff1|
 // instead of allocating the additional space.
-func parseTableIndexWithOffsetBuff(buff []byte, offsetsBuff1 []byte, q MemoryQuotaProvider) (onHeapTableIndex, error) {
+func parseTableIndexWithOffsetBuff(ctx context.Context, buff []byte, offsetsBuff1 []byte, q MemoryQuotaProvider) (onHeapTableIndex, error) {
 	chunkCount, totalUncompressedData, err := ReadTableFooter(bytes.NewReader(buff))
 	if err != nil {
 		return onHeapTableIndex{}, err
 	}
 
-	return newOnHeapTableIndex(buff, offsetsBuff1, chunkCount, totalUncompressedData, q)
+	return newOnHeapTableIndex(ctx, buff, offsetsBuff1, chunkCount, totalUncompressedData, q)
 }
 
 // parseTableIndexByCopy reads the footer, copies indexSize(chunkCount) bytes, and parses an on heap table index.
@@ -181,7 +185,7 @@ func readTableIndexByCopy(ctx context.Context, rd io.ReadSeeker, q MemoryQuotaPr
 		return onHeapTableIndex{}, err
 	}
 
-	idx, err := newOnHeapTableIndex(buff, offsets1Buff, chunkCount, totalUncompressedData, q)
+	idx, err := newOnHeapTableIndex(ctx, buff, offsets1Buff, chunkCount, totalUncompressedData, q)
 	if err != nil {
 		q.ReleaseQuotaBytes(len(buff))
 		q.ReleaseQuotaBytes(len(offsets1Buff))
@@ -218,6 +222,10 @@ type onHeapTableIndex struct {
 	// footer contains in the table file footer
 	footer []byte
 
+	// filter is a bloomFilter of the indexed addresses, built when the
+	// index is loaded so that it's ready for the first hasMany or getMany
+	filter bloomFilter
+
 	q      MemoryQuotaProvider
 	refCnt *int32
 
@@ -234,8 +242,9 @@ var _ tableIndex = &onHeapTableIndex{}
 // offsets. It stores the first n - n/2 offsets in |offsetsBuff1| (the
 // additional space) and the rest into the region of |indexBuff| previously
 // occupied by lengths. |onHeapTableIndex| computes directly on the given
-// |indexBuff| and |offsetsBuff1| buffers.
-func newOnHeapTableIndex(indexBuff []byte, offsetsBuff1 []byte, count uint32, totalUncompressedData uint64, q MemoryQuotaProvider) (onHeapTableIndex, error) {
+// |indexBuff| and |offsetsBuff1| buffers. The index's bloomFilter is
+// allocated from |q| and released with the buffers on Close.
+func newOnHeapTableIndex(ctx context.Context, indexBuff []byte, offsetsBuff1 []byte, count uint32, totalUncompressedData uint64, q MemoryQuotaProvider) (onHeapTableIndex, error) {
 	if len(indexBuff) != int(indexSize(count)+footerSize) {
 		return onHeapTableIndex{}, ErrWrongBufferSize
 	}
@@ -262,6 +271,11 @@ func newOnHeapTableIndex(indexBuff []byte, offsetsBuff1 []byte, count uint32, to
 		}
 	}
 
+	filterBuff, err := q.AcquireQuotaBytes(ctx, bloomFilterSize(count))
+	if err != nil {
+		return onHeapTableIndex{}, err
+	}
+
 	refCnt := new(int32)
 	*refCnt = 1
 
@@ -276,7 +290,7 @@ func newOnHeapTableIndex(indexBuff []byte, offsetsBuff1 []byte, count uint32, to
 		})
 	}
 
-	return onHeapTableIndex{
+	ti := onHeapTableIndex{
 		refCnt:         refCnt,
 		q:              q,
 		prefixTuples:   tuples,
@@ -284,9 +298,19 @@ func newOnHeapTableIndex(indexBuff []byte, offsetsBuff1 []byte, count uint32, to
 		offsets2:       offsetsBuff2,
 		suffixes:       suffixes,
 		footer:         footer,
+		filter:         newBloomFilter(filterBuff),
 		count:          count,
 		uncompressedSz: totalUncompressedData,
-	}, nil
+	}
+	for i := uint32(0); i < count; i++ {
+		a := addr(ti.hashAt(i))
+		ti.filter.add(&a)
+	}
+	return ti, nil
+}
+
+func (ti onHeapTableIndex) mayHave(h *addr) bool {
+	return ti.filter.mayContain(h)
 }
 
 func (ti onHeapTableIndex) entrySuffixMatches(idx uint32, h *addr) (bool, error) {
@@ -531,7 +555,7 @@ func (ti onHeapTableIndex) Close() error {
 	}
 
 	runtime.SetFinalizer(ti.refCnt, nil)
-	ti.q.ReleaseQuotaBytes(len(ti.prefixTuples) + len(ti.offsets1) + len(ti.offsets2) + len(ti.suffixes) + len(ti.footer))
+	ti.q.ReleaseQuotaBytes(len(ti.prefixTuples) + len(ti.offsets1) + len(ti.offsets2) + len(ti.suffixes) + len(ti.footer) + len(ti.filter.bits))
 	return nil
 }
 
@@ -167,12 +167,15 @@ func newTableReader(index tableIndex, r tableReaderAt, blockSize uint64) (tableR
 func (tr tableReader) hasMany(addrs []hasRecord) (bool, error) {
 	filterIdx := uint32(0)
 	filterLen := uint32(tr.idx.chunkCount())
-
 	var remaining bool
 	for i, addr := range addrs {
 		if addr.has {
 			continue
 		}
+		if !tr.idx.mayHave(addr.a) {
+			remaining = true
+			continue
+		}
 
 		// Use binary search to find the location of the addr.prefix in
 		// the prefixes array. filterIdx will be at the first entry
@@ -488,13 +491,16 @@ func (tr tableReader) findOffsets(reqs []getRecord) (ors offsetRecSlice, remaini
 	filterIdx := uint32(0)
 	filterLen := uint32(len(tr.prefixes))
 	ors = make(offsetRecSlice, 0, len(reqs))
-
 	// Iterate over |reqs| and |tr.prefixes| (both sorted by address) and build the set
 	// of table locations which must be read in order to satisfy |reqs|.
 	for i, req := range reqs {
 		if req.found {
 			continue
 		}
+		if !tr.idx.mayHave(req.a) {
+			remaining = true
+			continue
+		}
 
 		// Use binary search to find the location of the addr.prefix in
 		// the prefixes array. filterIdx will be at the first entry
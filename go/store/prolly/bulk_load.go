@@ -0,0 +1,98 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prolly
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/prolly/message"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// bulkLoader builds the tree of a MutableMap bottom-up while every write to it is a Put of a key greater than
+// any before it, which is how imports and CREATE TABLE AS SELECT fill new tables with sorted data. Pairs are
+// appended to the leaves of the tree as they arrive, instead of being buffered in a skip.List and merged into
+// the tree with tree.ApplyMutations.
+//
+// A MutableMap only bulk loads if it starts out empty. The first write out of order, or read of a key the loader
+// may have written, finishes the tree and returns the map to buffering its writes.
+type bulkLoader struct {
+	// chunker appends pairs to the map's tree. It's nil if no pairs have been written since the tree was finished.
+	chunker tree.Chunker
+	// last is the greatest key written to the map, or nil if it's empty
+	last val.Tuple
+	// checkpoint is |last| at the map's last checkpoint
+	checkpoint val.Tuple
+}
+
+// bulkPut appends |key|, |value| to the tree of |mut| if |key| sorts after every key written before it. It returns
+// false if the pair must be written to the edit buffer instead.
+func (mut *MutableMap) bulkPut(ctx context.Context, key, value val.Tuple) (bool, error) {
+	b := mut.bulk
+	if b.last != nil && mut.keyDesc.Compare(key, b.last) <= 0 {
+		return false, mut.stopBulkLoad(ctx)
+	}
+	if b.chunker == nil {
+		s := message.NewProllyMapSerializer(mut.valDesc, mut.NodeStore().Pool())
+		ch, err := tree.NewAppendChunker(ctx, mut.NodeStore(), mut.tuples.Static.Root, s)
+		if err != nil {
+			return false, err
+		}
+		b.chunker = ch
+	}
+	if err := b.chunker.AddPair(ctx, tree.Item(key), tree.Item(value)); err != nil {
+		return false, err
+	}
+	b.last = key
+	return true, nil
+}
+
+// bulkAbsent returns true if |key| is not in |mut| because it sorts after every key written by the bulkLoader.
+func (mut *MutableMap) bulkAbsent(key val.Tuple, order val.TupleDesc) bool {
+	return mut.bulk == nil || mut.bulk.last == nil || order.Compare(key, mut.bulk.last) > 0
+}
+
+// finishBulkLoad writes the pairs appended by the bulkLoader to the tree of |mut|.
+func (mut *MutableMap) finishBulkLoad(ctx context.Context) error {
+	if mut.bulk == nil || mut.bulk.chunker == nil {
+		return nil
+	}
+	if mut.stash == nil {
+		// the tree will no longer match the last checkpoint
+		cp := mut.tuples.Copy()
+		mut.stash = &cp
+	}
+	return mut.writeBulkLoad(ctx)
+}
+
+func (mut *MutableMap) writeBulkLoad(ctx context.Context) error {
+	root, err := mut.bulk.chunker.Done(ctx)
+	if err != nil {
+		return err
+	}
+	mut.bulk.chunker = nil
+	mut.tuples.Static.Root = root
+	return nil
+}
+
+// stopBulkLoad finishes the tree of |mut| and buffers its writes from now on.
+func (mut *MutableMap) stopBulkLoad(ctx context.Context) error {
+	if err := mut.finishBulkLoad(ctx); err != nil {
+		return err
+	}
+	mut.bulk = nil
+	return nil
+}
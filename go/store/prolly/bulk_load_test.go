@@ -0,0 +1,176 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prolly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+func TestBulkLoad(t *testing.T) {
+	ctx := context.Background()
+	const n = 20_000
+	tuples := ascendingTuplesWithStepAndStart(n, 2, 0)
+	expected := mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, tuples)
+
+	emptyMap := func(t *testing.T) *MutableMap {
+		return mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, nil).Mutate()
+	}
+	put := func(t *testing.T, mut *MutableMap, tuples [][2]val.Tuple) {
+		for _, tup := range tuples {
+			require.NoError(t, mut.Put(ctx, tup[0], tup[1]))
+		}
+	}
+	assertMap := func(t *testing.T, expected Map, mut *MutableMap) {
+		m, err := mut.Map(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected.HashOf(), m.HashOf())
+	}
+
+	t.Run("sorted puts", func(t *testing.T) {
+		mut := emptyMap(t)
+		put(t, mut, tuples)
+		require.NotNil(t, mut.bulk)
+		assert.Equal(t, 0, mut.tuples.Edits.Count())
+		assert.True(t, mut.HasEdits())
+		assertMap(t, expected, mut)
+
+		// materializing the map doesn't stop bulk loading
+		more := ascendingTuplesWithStepAndStart(100, 2, 2*n)
+		put(t, mut, more)
+		require.NotNil(t, mut.bulk)
+		assertMap(t, mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, append(tuples, more...)), mut)
+	})
+
+	t.Run("reads", func(t *testing.T) {
+		mut := emptyMap(t)
+		put(t, mut, tuples[:n/2])
+
+		// keys after the last write are absent without finishing the tree
+		ok, err := mut.Has(ctx, tuples[n/2][0])
+		require.NoError(t, err)
+		assert.False(t, ok)
+		require.NotNil(t, mut.bulk)
+
+		// keys the loader may have written are read from the finished tree
+		ok, err = mut.Has(ctx, tuples[n/4][0])
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Nil(t, mut.bulk)
+
+		put(t, mut, tuples[n/2:])
+		assertMap(t, expected, mut)
+	})
+
+	t.Run("out of order puts", func(t *testing.T) {
+		mut := emptyMap(t)
+		put(t, mut, tuples[n/2:])
+		put(t, mut, tuples[:n/2])
+		assert.Nil(t, mut.bulk)
+		assertMap(t, expected, mut)
+	})
+
+	t.Run("deletes", func(t *testing.T) {
+		mut := emptyMap(t)
+		put(t, mut, tuples)
+		require.NoError(t, mut.Delete(ctx, tuples[n-1][0]))
+		assert.Nil(t, mut.bulk)
+		assertMap(t, mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, tuples[:n-1]), mut)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		checkpoint := mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, tuples[:n/2])
+
+		mut := emptyMap(t)
+		put(t, mut, tuples[:n/2])
+		require.NoError(t, mut.Checkpoint(ctx))
+		put(t, mut, tuples[n/2:])
+		mut.Revert(ctx)
+		require.NotNil(t, mut.bulk)
+		assertMap(t, checkpoint, mut)
+
+		// revert to a checkpoint after the tree was materialized
+		put(t, mut, tuples[n/2:])
+		assertMap(t, expected, mut)
+		mut.Revert(ctx)
+		assertMap(t, checkpoint, mut)
+
+		// revert to a checkpoint after bulk loading stopped
+		put(t, mut, tuples[n/2:])
+		require.NoError(t, mut.Delete(ctx, tuples[0][0]))
+		assert.Nil(t, mut.bulk)
+		mut.Revert(ctx)
+		assertMap(t, checkpoint, mut)
+	})
+
+	t.Run("non-empty map", func(t *testing.T) {
+		mut := mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, tuples[:n/2]).Mutate()
+		assert.Nil(t, mut.bulk)
+		put(t, mut, tuples[n/2:])
+		assertMap(t, expected, mut)
+	})
+}
+
+// TestBulkLoadMemory checks that bulk loading a large map holds a bounded amount of memory, no matter how many pairs
+// are written: none of the pairs are buffered in the map's edit buffer, and it never flushes.
+func TestBulkLoadMemory(t *testing.T) {
+	ctx := context.Background()
+	const n = 4 * defaultMaxPending
+	mut := mustProllyMapFromTuples(t, mutKeyDesc, mutValDesc, nil).Mutate()
+	for i := 0; i < n; i++ {
+		k, v := makePut(int64(i), int64(i))
+		require.NoError(t, mut.Put(ctx, k, v))
+		require.NotNil(t, mut.bulk)
+		require.Equal(t, 0, mut.tuples.Edits.Count(), "pair %d was buffered", i)
+	}
+	assert.Nil(t, mut.stash)
+
+	m, err := mut.Map(ctx)
+	require.NoError(t, err)
+	cnt, err := m.Count()
+	require.NoError(t, err)
+	assert.Equal(t, n, cnt)
+}
+
+func BenchmarkBulkLoad(b *testing.B) {
+	ctx := context.Background()
+	tuples := ascendingTuplesWithStepAndStart(100_000, 1, 0)
+	empty := mustProllyMapFromTuples(&testing.T{}, mutKeyDesc, mutValDesc, nil)
+	seed := mustProllyMapFromTuples(&testing.T{}, mutKeyDesc, mutValDesc, tuples[:1])
+
+	b.Run("bulk load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mut := empty.Mutate()
+			for _, tup := range tuples {
+				_ = mut.Put(ctx, tup[0], tup[1])
+			}
+			_, _ = mut.Map(ctx)
+		}
+	})
+	b.Run("edit buffer", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mut := seed.Mutate()
+			for _, tup := range tuples[1:] {
+				_ = mut.Put(ctx, tup[0], tup[1])
+			}
+			_, _ = mut.Map(ctx)
+		}
+	})
+}
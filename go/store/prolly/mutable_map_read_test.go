@@ -49,6 +49,9 @@ func TestMutableMapReads(t *testing.T) {
 			t.Run("iter range", func(t *testing.T) {
 				testIterRange(t, mutableMap, tuples)
 			})
+			t.Run("iter range reverse", func(t *testing.T) {
+				testIterRangeReverse(t, mutableMap, tuples)
+			})
 			t.Run("iter prefix range", func(t *testing.T) {
 				testIterPrefixRange(t, mutableMap, tuples)
 			})
@@ -71,6 +74,9 @@ func TestMutableMapReads(t *testing.T) {
 			t.Run("iter range with pending deletes", func(t *testing.T) {
 				testIterRange(t, mutableMap2, tuples2)
 			})
+			t.Run("iter range reverse with pending deletes", func(t *testing.T) {
+				testIterRangeReverse(t, mutableMap2, tuples2)
+			})
 			t.Run("iter ordinal range", func(t *testing.T) {
 				t.Skip("todo(andy)")
 			})
@@ -91,6 +97,9 @@ func TestMutableMapReads(t *testing.T) {
 			t.Run("iter range after deletes applied", func(t *testing.T) {
 				testIterRange(t, prollyMap, tuples2)
 			})
+			t.Run("iter range reverse after deletes applied", func(t *testing.T) {
+				testIterRangeReverse(t, prollyMap, tuples2)
+			})
 			t.Run("iter ordinal range", func(t *testing.T) {
 				t.Skip("todo(andy)")
 			})
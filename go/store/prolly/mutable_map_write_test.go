@@ -72,6 +72,9 @@ func TestMutableMapWrites(t *testing.T) {
 	t.Run("test internal node splits", func(t *testing.T) {
 		testInternalNodeSplits(t)
 	})
+	t.Run("deletes flush pending writes", func(t *testing.T) {
+		testDeletesFlushPendingWrites(t)
+	})
 }
 
 func testPointUpdates(t *testing.T, mapCount int) {
@@ -501,6 +504,24 @@ func testInternalNodeSplits(t *testing.T) {
 	}
 }
 
+func testDeletesFlushPendingWrites(t *testing.T) {
+	const maxPending = 100
+	ctx := context.Background()
+	orig := ascendingIntMap(t, 10*maxPending)
+	mut := orig.Mutate().WithMaxPending(maxPending)
+
+	for i := 0; i < 10*maxPending; i++ {
+		require.NoError(t, mut.Delete(ctx, makeDelete(int64(i))))
+		assert.LessOrEqual(t, mut.tuples.Edits.Count(), maxPending)
+	}
+
+	m, err := mut.Map(ctx)
+	require.NoError(t, err)
+	c, err := m.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, c)
+}
+
 // utilities
 
 func ascendingIntMap(t *testing.T, count int) Map {
@@ -351,14 +351,106 @@ func (t *ImmutableTree) bytes(ctx context.Context) ([]byte, error) {
 	return t.buf[:], nil
 }
 
-func (t *ImmutableTree) next() (Node, error) {
-	panic("not implemented")
+// SizeUpperBound returns an upper bound on the length of the contents of the tree, reading only its root. The bound
+// is exact for a tree with a single leaf, and otherwise assumes every leaf holds DefaultFixedChunkLength bytes.
+func (t *ImmutableTree) SizeUpperBound(ctx context.Context) (int, error) {
+	if t.buf != nil {
+		return len(t.buf), nil
+	} else if t.Addr.IsEmpty() {
+		return 0, nil
+	}
+	n, err := t.ns.Read(ctx, t.Addr)
+	if err != nil {
+		return 0, err
+	}
+	if n.IsLeaf() {
+		return len(n.GetValue(0)), nil
+	}
+	leaves, err := n.TreeCount()
+	if err != nil {
+		return 0, err
+	}
+	return leaves * DefaultFixedChunkLength, nil
+}
+
+// Reader returns a reader of the contents of the tree that loads its leaves as they are read, so that a large blob
+// can be streamed without holding it in memory.
+func (t *ImmutableTree) Reader(ctx context.Context) io.Reader {
+	if t.buf != nil {
+		return bytes.NewReader(t.buf)
+	}
+	return &blobReader{ctx: ctx, addr: t.Addr, ns: t.ns}
+}
+
+// blobReader reads the leaves of a blob tree in order.
+type blobReader struct {
+	ctx  context.Context
+	addr hash.Hash
+	ns   NodeStore
+
+	// stack holds the children of the internal nodes on the path to
+	// the current leaf that are left to read.
+	stack  [][]hash.Hash
+	leaf   []byte
+	loaded bool
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	for len(r.leaf) == 0 {
+		if err := r.nextLeaf(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.leaf)
+	r.leaf = r.leaf[n:]
+	return n, nil
 }
 
-func (t *ImmutableTree) close() error {
-	panic("not implemented")
+// nextLeaf loads the next leaf of the tree, returning io.EOF after the last one.
+func (r *blobReader) nextLeaf() error {
+	if !r.loaded {
+		r.loaded = true
+		if r.addr.IsEmpty() {
+			return io.EOF
+		}
+		return r.descend(r.addr)
+	}
+	for len(r.stack) > 0 {
+		top := &r.stack[len(r.stack)-1]
+		if len(*top) > 0 {
+			addr := (*top)[0]
+			*top = (*top)[1:]
+			return r.descend(addr)
+		}
+		r.stack = r.stack[:len(r.stack)-1]
+	}
+	return io.EOF
 }
 
-func (t *ImmutableTree) Read(_ bytes.Buffer) (int, error) {
-	panic("not implemented")
+// descend reads the subtree at |addr| down to its first leaf.
+func (r *blobReader) descend(addr hash.Hash) error {
+	for {
+		nd, err := r.ns.Read(r.ctx, addr)
+		if err != nil {
+			return err
+		}
+		if nd.IsLeaf() {
+			r.leaf = nd.GetValue(0)
+			return nil
+		}
+		// the child addresses of internal blob nodes are read with walkAddresses, not GetValue
+		var children []hash.Hash
+		err = walkAddresses(r.ctx, nd, func(ctx context.Context, addr hash.Hash) error {
+			children = append(children, addr)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		r.stack = append(r.stack, children[1:])
+		addr = children[0]
+	}
 }
@@ -19,8 +19,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -327,6 +329,45 @@ func TestImmutableTreeWalk(t *testing.T) {
 	}
 }
 
+func TestImmutableTreeReader(t *testing.T) {
+	tests := []struct {
+		blobLen   int
+		chunkSize int
+	}{
+		{blobLen: 0, chunkSize: 40},
+		{blobLen: 1, chunkSize: 40},
+		{blobLen: 40, chunkSize: 40},
+		{blobLen: 250, chunkSize: 60},
+		{blobLen: 5000, chunkSize: 40},
+		{blobLen: 1_000_000, chunkSize: 4000},
+	}
+
+	ctx := context.Background()
+	ns := NewTestNodeStore()
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("inputSize=%d; chunkSize=%d", tt.blobLen, tt.chunkSize), func(t *testing.T) {
+			addr := mustNewBlob(ctx, ns, tt.blobLen, tt.chunkSize)
+			expected, err := NewByteArray(addr, ns).ToBytes(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.blobLen, len(expected))
+
+			actual, err := io.ReadAll(NewByteArray(addr, ns).Reader(ctx))
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+
+			require.NoError(t, iotest.TestReader(NewTextStorage(addr, ns).Reader(ctx), expected))
+
+			sz, err := NewByteArray(addr, ns).SizeUpperBound(ctx)
+			require.NoError(t, err)
+			if tt.blobLen <= tt.chunkSize {
+				assert.Equal(t, tt.blobLen, sz)
+			} else {
+				assert.GreaterOrEqual(t, sz, tt.blobLen)
+			}
+		})
+	}
+}
+
 func blobAddrCnt(size, chunk int) int {
 	if size == 0 {
 		return 0
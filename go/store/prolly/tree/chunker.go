@@ -53,6 +53,22 @@ func NewEmptyChunker[S message.Serializer](ctx context.Context, ns NodeStore, se
 	return newEmptyChunker(ctx, ns, serializer)
 }
 
+// NewAppendChunker returns a Chunker that appends pairs to the tree rooted at |root|. The keys of the pairs added
+// to it must sort after the last key of |root|.
+func NewAppendChunker[S message.Serializer](ctx context.Context, ns NodeStore, root Node, serializer S) (Chunker, error) {
+	if root.Count() == 0 {
+		return newEmptyChunker(ctx, ns, serializer)
+	}
+	// position |cur| after the last key of |root|, in the last subtree of each level
+	cur, err := newCursorFromSearchFn(ctx, ns, root, func(nd Node) int {
+		return nd.Count()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newChunker(ctx, cur, 0, ns, serializer)
+}
+
 func newEmptyChunker[S message.Serializer](ctx context.Context, ns NodeStore, serializer S) (*chunker[S], error) {
 	return newChunker(ctx, nil, 0, ns, serializer)
 }
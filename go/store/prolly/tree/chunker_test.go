@@ -20,12 +20,17 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/prolly/message"
 )
 
 func TestTreeChunker(t *testing.T) {
 	t.Run("round trip tree items", func(t *testing.T) {
 		roundTripTreeItems(t)
 	})
+	t.Run("append to tree", func(t *testing.T) {
+		appendTreeItems(t)
+	})
 }
 
 func roundTripTreeItems(t *testing.T) {
@@ -66,6 +71,34 @@ func roundTripTreeItems(t *testing.T) {
 	validateTreeItems(t, ns, root, items)
 }
 
+func appendTreeItems(t *testing.T) {
+	ctx := context.Background()
+	expected, items, ns := randomTree(t, 20_000)
+	serializer := message.NewProllyMapSerializer(valDesc, ns.Pool())
+
+	empty, err := newEmptyChunker(ctx, ns, serializer)
+	require.NoError(t, err)
+	emptyRoot, err := empty.Done(ctx)
+	require.NoError(t, err)
+
+	// appending in batches builds the same tree as building it at once
+	for _, stops := range [][]int{{1, 10_000}, {17, 1017, 4017, 4022, 10_000}, {5000, 9999, 10_000}} {
+		root, start := emptyRoot, 0
+		for _, stop := range stops {
+			chkr, err := NewAppendChunker(ctx, ns, root, serializer)
+			require.NoError(t, err)
+			for _, item := range items[start:stop] {
+				require.NoError(t, chkr.AddPair(ctx, item[0], item[1]))
+			}
+			root, err = chkr.Done(ctx)
+			require.NoError(t, err)
+			start = stop
+		}
+		assert.Equal(t, expected.HashOf(), root.HashOf())
+		validateTreeItems(t, ns, root, items)
+	}
+}
+
 func countTree(t *testing.T, ns NodeStore, nd Node) (count int) {
 	ctx := context.Background()
 	err := iterTree(ctx, ns, nd, func(_ Item) (err error) {
@@ -32,14 +32,14 @@ func (m MutableMap[K, V, O]) Put(_ context.Context, key K, value V) error {
 }
 
 func (m MutableMap[K, V, O]) Delete(_ context.Context, key K) error {
-	m.Edits.Put(key, nil)
+	m.Edits.Delete(key)
 	return nil
 }
 
 func (m MutableMap[K, V, O]) Get(ctx context.Context, key K, cb KeyValueFn[K, V]) (err error) {
-	value, ok := m.Edits.Get(key)
+	value, deleted, ok := m.Edits.GetEntry(key)
 	if ok {
-		if value == nil {
+		if deleted {
 			key = nil // there is a pending delete of |key| in |m.Edits|.
 		}
 		return cb(key, value)
@@ -57,7 +57,7 @@ func (m MutableMap[K, V, O]) GetPrefix(ctx context.Context, key K, prefixOrder O
 	})
 	k, v := iter.Current()
 	if k != nil && prefixOrder.Compare(k, key) == 0 {
-		if v == nil {
+		if iter.Deleted() {
 			k = nil // there is a pending delete of |key| in |m.Edits|.
 		}
 		return cb(k, v)
@@ -66,9 +66,9 @@ func (m MutableMap[K, V, O]) GetPrefix(ctx context.Context, key K, prefixOrder O
 }
 
 func (m MutableMap[K, V, O]) Has(ctx context.Context, key K) (present bool, err error) {
-	value, ok := m.Edits.Get(key)
+	_, deleted, ok := m.Edits.GetEntry(key)
 	if ok {
-		present = value != nil
+		present = !deleted
 		return
 	}
 	return m.Static.Has(ctx, key)
@@ -81,9 +81,9 @@ func (m MutableMap[K, V, O]) HasPrefix(ctx context.Context, key K, prefixOrder O
 		}
 		return
 	})
-	k, v := iter.Current()
+	k, _ := iter.Current()
 	if k != nil && prefixOrder.Compare(k, key) == 0 {
-		present = v != nil
+		present = !iter.Deleted()
 		return
 	}
 	return m.Static.HasPrefix(ctx, key, prefixOrder)
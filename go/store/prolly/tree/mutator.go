@@ -64,71 +64,97 @@ func ApplyMutations[K ~[]byte, O Ordering[K], S message.Serializer](
 	serializer S,
 	edits MutationIter,
 ) (Node, error) {
-	newKey, newValue := edits.NextMutation(ctx)
-	if newKey == nil {
-		return root, nil // no mutations
+	m := NewMutator[K](ns, root, order, serializer)
+	for newKey, newValue := edits.NextMutation(ctx); newKey != nil; newKey, newValue = edits.NextMutation(ctx) {
+		if err := m.Mutate(ctx, newKey, newValue); err != nil {
+			return Node{}, err
+		}
 	}
+	return m.Done(ctx)
+}
 
-	cur, err := newCursorAtKey(ctx, ns, root, K(newKey), order)
-	if err != nil {
-		return Node{}, err
-	}
+// Mutator applies a sorted series of edits to a tree as they are made, rather
+// than reading them from a MutationIter. Each edit is written to the new tree
+// when it's made, so memory use doesn't grow with the number of edits.
+// See ApplyMutations for the algorithm.
+type Mutator[K ~[]byte, O Ordering[K], S message.Serializer] struct {
+	ns         NodeStore
+	root       Node
+	order      O
+	serializer S
+
+	cur  *cursor
+	chkr *chunker[S]
+	prev Item
+}
 
-	chkr, err := newChunker(ctx, cur.clone(), 0, ns, serializer)
-	if err != nil {
-		return Node{}, err
+// NewMutator returns a Mutator that edits the tree rooted at |root|.
+func NewMutator[K ~[]byte, O Ordering[K], S message.Serializer](ns NodeStore, root Node, order O, serializer S) *Mutator[K, O, S] {
+	return &Mutator[K, O, S]{
+		ns:         ns,
+		root:       root,
+		order:      order,
+		serializer: serializer,
 	}
+}
 
-	for newKey != nil {
-
-		// move |cur| to the NextMutation mutation point
-		err = Seek(ctx, cur, K(newKey), order)
+// Mutate sets the value of |newKey| to |newValue|, or deletes |newKey| if |newValue|
+// is nil. Each key must be greater than the key of the previous edit.
+func (m *Mutator[K, O, S]) Mutate(ctx context.Context, newKey, newValue Item) (err error) {
+	if m.chkr == nil {
+		m.cur, err = newCursorAtKey(ctx, m.ns, m.root, K(newKey), m.order)
 		if err != nil {
-			return Node{}, err
+			return err
 		}
-
-		var oldValue Item
-		if cur.Valid() {
-			// Compare mutations |newKey| and |newValue|
-			// to the existing pair from the cursor
-			if order.Compare(K(newKey), K(cur.CurrentKey())) == 0 {
-				oldValue = cur.currentValue()
-			}
+		m.chkr, err = newChunker(ctx, m.cur.clone(), 0, m.ns, m.serializer)
+		if err != nil {
+			return err
 		}
+	} else {
+		assertTrue(m.order.Compare(K(newKey), K(m.prev)) > 0, "expected sorted edits")
+	}
+	m.prev = newKey
 
-		// check for no-op mutations
-		if equalValues(newValue, oldValue) {
-			newKey, newValue = edits.NextMutation(ctx)
-			continue // same newValue
-		}
+	// move |cur| to the next mutation point
+	err = Seek(ctx, m.cur, K(newKey), m.order)
+	if err != nil {
+		return err
+	}
 
-		// move |chkr| to the NextMutation mutation point
-		err = chkr.advanceTo(ctx, cur)
-		if err != nil {
-			return Node{}, err
+	var oldValue Item
+	if m.cur.Valid() {
+		// Compare mutations |newKey| and |newValue|
+		// to the existing pair from the cursor
+		if m.order.Compare(K(newKey), K(m.cur.CurrentKey())) == 0 {
+			oldValue = m.cur.currentValue()
 		}
+	}
 
-		if oldValue == nil {
-			err = chkr.AddPair(ctx, newKey, newValue)
-		} else {
-			if newValue != nil {
-				err = chkr.UpdatePair(ctx, newKey, newValue)
-			} else {
-				err = chkr.DeletePair(ctx, newKey, oldValue)
-			}
-		}
-		if err != nil {
-			return Node{}, err
-		}
+	// check for no-op mutations
+	if equalValues(newValue, oldValue) {
+		return nil // same newValue
+	}
 
-		prev := newKey
-		newKey, newValue = edits.NextMutation(ctx)
-		if newKey != nil {
-			assertTrue(order.Compare(K(newKey), K(prev)) > 0, "expected sorted edits")
-		}
+	// move |chkr| to the next mutation point
+	err = m.chkr.advanceTo(ctx, m.cur)
+	if err != nil {
+		return err
 	}
 
-	return chkr.Done(ctx)
+	if oldValue == nil {
+		return m.chkr.AddPair(ctx, newKey, newValue)
+	} else if newValue != nil {
+		return m.chkr.UpdatePair(ctx, newKey, newValue)
+	}
+	return m.chkr.DeletePair(ctx, newKey, oldValue)
+}
+
+// Done finishes the edits and returns the root of the new tree.
+func (m *Mutator[K, O, S]) Done(ctx context.Context) (Node, error) {
+	if m.chkr == nil {
+		return m.root, nil // no mutations
+	}
+	return m.chkr.Done(ctx)
 }
 
 func equalValues(left, right Item) bool {
@@ -0,0 +1,70 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// SizeCache computes the storage size of trees, caching the sizes of the subtrees below internal nodes. Trees that
+// share most of their nodes, like successive versions of a table, share most of their cached sizes, so the size of a
+// tree that was edited is computed by reading only the nodes on the paths to its edits.
+type SizeCache struct {
+	sizes *lru.Cache[hash.Hash, uint64]
+}
+
+// NewSizeCache returns a SizeCache that caches the sizes of at most |entries| subtrees.
+func NewSizeCache(entries int) (*SizeCache, error) {
+	sizes, err := lru.New[hash.Hash, uint64](entries)
+	if err != nil {
+		return nil, err
+	}
+	return &SizeCache{sizes: sizes}, nil
+}
+
+// Size returns the sum of the sizes in bytes of the nodes of the tree rooted at |nd|. Trees stored out of band, like
+// the blobs referenced by the values of a map, aren't included.
+func (c *SizeCache) Size(ctx context.Context, ns NodeStore, nd Node) (uint64, error) {
+	if nd.empty() {
+		return 0, nil
+	}
+	if nd.IsLeaf() {
+		return uint64(nd.Size()), nil
+	}
+
+	h := nd.HashOf()
+	if size, ok := c.sizes.Get(h); ok {
+		return size, nil
+	}
+
+	size := uint64(nd.Size())
+	for i := 0; i < nd.Count(); i++ {
+		child, err := ns.Read(ctx, nd.getAddress(i))
+		if err != nil {
+			return 0, err
+		}
+		childSize, err := c.Size(ctx, ns, child)
+		if err != nil {
+			return 0, err
+		}
+		size += childSize
+	}
+	c.sizes.Add(h, size)
+	return size, nil
+}
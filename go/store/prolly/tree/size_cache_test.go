@@ -0,0 +1,52 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeCache(t *testing.T) {
+	ctx := context.Background()
+	ns := NewTestNodeStore()
+	root := newTree(t, ns, 10_000, 1, 40)
+	require.False(t, root.IsLeaf())
+
+	var expected uint64
+	err := WalkNodes(ctx, root, ns, func(ctx context.Context, nd Node) error {
+		expected += uint64(nd.Size())
+		return nil
+	})
+	require.NoError(t, err)
+
+	c, err := NewSizeCache(1024)
+	require.NoError(t, err)
+	size, err := c.Size(ctx, ns, root)
+	require.NoError(t, err)
+	assert.Equal(t, expected, size)
+	assert.True(t, c.sizes.Contains(root.HashOf()))
+
+	size, err = c.Size(ctx, ns, root)
+	require.NoError(t, err)
+	assert.Equal(t, expected, size)
+
+	size, err = c.Size(ctx, ns, NewEmptyTestNode())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), size)
+}
@@ -43,6 +43,10 @@ type MutableMap struct {
 	// flush the pending writes and continue accumulating
 	stash *tree.MutableMap[val.Tuple, val.Tuple, val.TupleDesc]
 
+	// bulk, if not nil, appends writes directly to the tree while
+	// they arrive in key order. See bulkLoader.
+	bulk *bulkLoader
+
 	// keyDesc and valDesc are tuples descriptors for the map.
 	keyDesc, valDesc val.TupleDesc
 
@@ -52,16 +56,23 @@ type MutableMap struct {
 
 // newMutableMap returns a new MutableMap.
 func newMutableMap(m Map) *MutableMap {
-	return &MutableMap{
+	mut := &MutableMap{
 		tuples:     m.tuples.Mutate(),
 		keyDesc:    m.keyDesc,
 		valDesc:    m.valDesc,
 		maxPending: defaultMaxPending,
 	}
+	if m.tuples.Root.Count() == 0 {
+		mut.bulk = &bulkLoader{}
+	}
+	return mut
 }
 
 // Map materializes all pending and applied mutations in the MutableMap.
 func (mut *MutableMap) Map(ctx context.Context) (Map, error) {
+	if err := mut.finishBulkLoad(ctx); err != nil {
+		return Map{}, err
+	}
 	s := message.NewProllyMapSerializer(mut.valDesc, mut.NodeStore().Pool())
 	return mut.flushWithSerializer(ctx, s)
 }
@@ -100,6 +111,11 @@ func (mut *MutableMap) NodeStore() tree.NodeStore {
 
 // Put adds the Tuple pair |key|, |value| to the MutableMap.
 func (mut *MutableMap) Put(ctx context.Context, key, value val.Tuple) error {
+	if mut.bulk != nil {
+		if ok, err := mut.bulkPut(ctx, key, value); ok || err != nil {
+			return err
+		}
+	}
 	if err := mut.tuples.Put(ctx, key, value); err != nil {
 		return err
 	}
@@ -111,31 +127,76 @@ func (mut *MutableMap) Put(ctx context.Context, key, value val.Tuple) error {
 
 // Delete deletes the pair keyed by |key| from the MutableMap.
 func (mut *MutableMap) Delete(ctx context.Context, key val.Tuple) error {
-	return mut.tuples.Delete(ctx, key)
+	if err := mut.stopBulkLoad(ctx); err != nil {
+		return err
+	}
+	if err := mut.tuples.Delete(ctx, key); err != nil {
+		return err
+	}
+	if mut.tuples.Edits.Count() > mut.maxPending {
+		return mut.flushPending(ctx)
+	}
+	return nil
 }
 
 // Get fetches the Tuple pair keyed by |key|, if it exists, and passes it to |cb|.
 // If the |key| is not present in the MutableMap, a nil Tuple pair is passed to |cb|.
 func (mut *MutableMap) Get(ctx context.Context, key val.Tuple, cb tree.KeyValueFn[val.Tuple, val.Tuple]) (err error) {
+	if mut.bulk != nil {
+		if mut.bulkAbsent(key, mut.keyDesc) {
+			return cb(nil, nil)
+		} else if err = mut.stopBulkLoad(ctx); err != nil {
+			return err
+		}
+	}
 	return mut.tuples.Get(ctx, key, cb)
 }
 
 func (mut *MutableMap) GetPrefix(ctx context.Context, key val.Tuple, prefixDesc val.TupleDesc, cb tree.KeyValueFn[val.Tuple, val.Tuple]) (err error) {
+	if mut.bulk != nil {
+		if mut.bulkAbsent(key, prefixDesc) {
+			return cb(nil, nil)
+		} else if err = mut.stopBulkLoad(ctx); err != nil {
+			return err
+		}
+	}
 	return mut.tuples.GetPrefix(ctx, key, prefixDesc, cb)
 }
 
 // Has returns true if |key| is present in the MutableMap.
 func (mut *MutableMap) Has(ctx context.Context, key val.Tuple) (ok bool, err error) {
+	if mut.bulk != nil {
+		if mut.bulkAbsent(key, mut.keyDesc) {
+			return false, nil
+		} else if err = mut.stopBulkLoad(ctx); err != nil {
+			return false, err
+		}
+	}
 	return mut.tuples.Has(ctx, key)
 }
 
 // HasPrefix returns true if a key with a matching prefix to |key| is present in the MutableMap.
 func (mut *MutableMap) HasPrefix(ctx context.Context, key val.Tuple, prefixDesc val.TupleDesc) (ok bool, err error) {
+	if mut.bulk != nil {
+		if mut.bulkAbsent(key, prefixDesc) {
+			return false, nil
+		} else if err = mut.stopBulkLoad(ctx); err != nil {
+			return false, err
+		}
+	}
 	return mut.tuples.HasPrefix(ctx, key, prefixDesc)
 }
 
 // Checkpoint records a checkpoint that can be reverted to.
-func (mut *MutableMap) Checkpoint(context.Context) error {
+func (mut *MutableMap) Checkpoint(ctx context.Context) error {
+	if mut.bulk != nil {
+		if mut.bulk.chunker != nil {
+			if err := mut.writeBulkLoad(ctx); err != nil {
+				return err
+			}
+		}
+		mut.bulk.checkpoint = mut.bulk.last
+	}
 	// discard previous stash, if one exists
 	mut.stash = nil
 	mut.tuples.Edits.Checkpoint()
@@ -144,11 +205,15 @@ func (mut *MutableMap) Checkpoint(context.Context) error {
 
 // Revert discards writes made since the last checkpoint.
 func (mut *MutableMap) Revert(context.Context) {
+	if mut.bulk != nil {
+		mut.bulk.chunker = nil
+		mut.bulk.last = mut.bulk.checkpoint
+	}
 	// if we've accumulated a large number of writes
 	// since we check-pointed, our last checkpoint
 	// may be stashed in a separate tree.MutableMap
 	if mut.stash != nil {
-		mut.tuples = *mut.stash
+		mut.tuples = mut.stash.Copy()
 		return
 	}
 	mut.tuples.Edits.Revert()
@@ -181,6 +246,9 @@ func (mut *MutableMap) IterAll(ctx context.Context) (MapIter, error) {
 
 // IterRange returns a MapIter that iterates over a Range.
 func (mut *MutableMap) IterRange(ctx context.Context, rng Range) (MapIter, error) {
+	if err := mut.stopBulkLoad(ctx); err != nil {
+		return nil, err
+	}
 	treeIter, err := treeIterFromRange(ctx, mut.tuples.Static.Root, mut.tuples.Static.NodeStore, rng)
 	if err != nil {
 		return nil, err
@@ -196,9 +264,33 @@ func (mut *MutableMap) IterRange(ctx context.Context, rng Range) (MapIter, error
 	return filteredIter{iter: iter, rng: rng}, err
 }
 
+// IterRangeReverse returns a MapIter that iterates over a Range backwards.
+func (mut *MutableMap) IterRangeReverse(ctx context.Context, rng Range) (MapIter, error) {
+	if err := mut.stopBulkLoad(ctx); err != nil {
+		return nil, err
+	}
+	treeIter, err := treeIterFromRangeReverse(ctx, mut.tuples.Static.Root, mut.tuples.Static.NodeStore, rng)
+	if err != nil {
+		return nil, err
+	}
+	memIter := memIterFromRangeReverse(mut.tuples.Edits, rng)
+
+	iter := &mutableMapIter[val.Tuple, val.Tuple, val.TupleDesc]{
+		memory:  memIter,
+		prolly:  treeIter,
+		order:   rng.Desc,
+		reverse: true,
+	}
+
+	return filteredIter{iter: iter, rng: rng}, nil
+}
+
 // HasEdits returns true when the MutableMap has performed at least one Put or Delete operation. This does not indicate
 // whether the materialized map contains different values to the contained unedited map.
 func (mut *MutableMap) HasEdits() bool {
+	if mut.bulk != nil && mut.bulk.chunker != nil {
+		return true
+	}
 	return mut.tuples.Edits.Count() > 0
 }
 
@@ -0,0 +1,77 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prolly
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/store/prolly/message"
+	"github.com/dolthub/dolt/go/store/prolly/tree"
+	"github.com/dolthub/dolt/go/store/val"
+)
+
+// OrderedMutator writes edits made in key order to a Map. Unlike a MutableMap, which buffers its
+// writes and merges them into the tree when the buffer fills, each edit is written to the new tree
+// as it's made, so editing a map holds a bounded amount of memory no matter how many edits are made.
+// An OrderedMutator can't be read from, and every edit must be to a key greater than the last.
+type OrderedMutator struct {
+	mut *tree.Mutator[val.Tuple, val.TupleDesc, message.ProllyMapSerializer]
+	m   Map
+	// last is the key of the last edit, or nil if no edits have been made
+	last val.Tuple
+}
+
+// MutateInOrder returns an OrderedMutator that edits |m|.
+func (m Map) MutateInOrder() *OrderedMutator {
+	return &OrderedMutator{m: m}
+}
+
+// Put adds the Tuple pair |key|, |value| to the map, or deletes |key| if |value| is nil.
+func (mut *OrderedMutator) Put(ctx context.Context, key, value val.Tuple) error {
+	if mut.last != nil && mut.m.keyDesc.Compare(key, mut.last) <= 0 {
+		return fmt.Errorf("ordered mutator: key %s was written out of order",
+			mut.m.keyDesc.Format(key))
+	}
+	if mut.mut == nil {
+		s := message.NewProllyMapSerializer(mut.m.valDesc, mut.m.NodeStore().Pool())
+		mut.mut = tree.NewMutator[val.Tuple](mut.m.NodeStore(), mut.m.tuples.Root, mut.m.keyDesc, s)
+	}
+	if err := mut.mut.Mutate(ctx, tree.Item(key), tree.Item(value)); err != nil {
+		return err
+	}
+	mut.last = key
+	return nil
+}
+
+// Delete deletes the pair keyed by |key| from the map.
+func (mut *OrderedMutator) Delete(ctx context.Context, key val.Tuple) error {
+	return mut.Put(ctx, key, nil)
+}
+
+// Map finishes the edits made to the map and returns the edited Map. Further edits are made to
+// the returned Map, and must still be to keys greater than the last.
+func (mut *OrderedMutator) Map(ctx context.Context) (Map, error) {
+	if mut.mut == nil {
+		return mut.m, nil
+	}
+	root, err := mut.mut.Done(ctx)
+	if err != nil {
+		return Map{}, err
+	}
+	mut.mut = nil
+	mut.m.tuples.Root = root
+	return mut.m, nil
+}
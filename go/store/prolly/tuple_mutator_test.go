@@ -0,0 +1,112 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prolly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMutator(t *testing.T) {
+	ctx := context.Background()
+	const n = 20_000
+	base := ascendingIntMapWithStep(t, n, 2)
+
+	// every third key is deleted, every third is updated or inserted, and the rest are left alone
+	edit := func(t *testing.T, put func(k int64, v int64) error, del func(k int64) error) {
+		for i := int64(0); i < 2*n+10; i++ {
+			switch i % 3 {
+			case 0:
+				require.NoError(t, del(i))
+			case 1:
+				require.NoError(t, put(i, -i))
+			}
+		}
+	}
+	expected := func(t *testing.T) Map {
+		mut := base.Mutate()
+		edit(t, func(k, v int64) error {
+			key, value := makePut(k, v)
+			return mut.Put(ctx, key, value)
+		}, func(k int64) error {
+			return mut.Delete(ctx, makeDelete(k))
+		})
+		m, err := mut.Map(ctx)
+		require.NoError(t, err)
+		return m
+	}(t)
+
+	t.Run("edits", func(t *testing.T) {
+		mut := base.MutateInOrder()
+		edit(t, func(k, v int64) error {
+			key, value := makePut(k, v)
+			return mut.Put(ctx, key, value)
+		}, func(k int64) error {
+			return mut.Delete(ctx, makeDelete(k))
+		})
+		m, err := mut.Map(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected.HashOf(), m.HashOf())
+	})
+
+	t.Run("materialize while editing", func(t *testing.T) {
+		mut := base.MutateInOrder()
+		edit(t, func(k, v int64) error {
+			key, value := makePut(k, v)
+			if k%1000 == 1 {
+				if _, err := mut.Map(ctx); err != nil {
+					return err
+				}
+			}
+			return mut.Put(ctx, key, value)
+		}, func(k int64) error {
+			return mut.Delete(ctx, makeDelete(k))
+		})
+		m, err := mut.Map(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected.HashOf(), m.HashOf())
+	})
+
+	t.Run("no edits", func(t *testing.T) {
+		mut := base.MutateInOrder()
+		m, err := mut.Map(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, base.HashOf(), m.HashOf())
+
+		// writing the pairs already in the map leaves it unchanged
+		for _, tup := range ascendingTuplesWithStepAndStart(n, 2, 0) {
+			require.NoError(t, mut.Put(ctx, tup[0], tup[1]))
+		}
+		m, err = mut.Map(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, base.HashOf(), m.HashOf())
+	})
+
+	t.Run("out of order edits", func(t *testing.T) {
+		mut := base.MutateInOrder()
+		k, v := makePut(10, 10)
+		require.NoError(t, mut.Put(ctx, k, v))
+		assert.Error(t, mut.Put(ctx, k, v))
+		assert.Error(t, mut.Delete(ctx, makeDelete(5)))
+
+		// edits are ordered across calls to Map
+		_, err := mut.Map(ctx)
+		require.NoError(t, err)
+		assert.Error(t, mut.Delete(ctx, makeDelete(5)))
+	})
+}
@@ -42,6 +42,9 @@ type mutableMapIter[K, V ~[]byte, O tree.Ordering[K]] struct {
 	memory rangeIter[K, V]
 	prolly *tree.OrderedTreeIter[K, V]
 	order  O
+	// reverse is true if |memory| and |prolly|
+	// iterate the Range in descending order
+	reverse bool
 }
 
 // Next returns the next pair of Tuples in the Range, or io.EOF if the iter is done.
@@ -105,6 +108,9 @@ func (it mutableMapIter[K, V, O]) compareKeys(memKey, proKey K) int {
 	if proKey == nil {
 		return -1
 	}
+	if it.reverse {
+		return it.order.Compare(proKey, memKey)
+	}
 	return it.order.Compare(memKey, proKey)
 }
 
@@ -133,6 +139,32 @@ func memIterFromRange(list *skip.List, rng Range) *memRangeIter {
 	}
 }
 
+func memIterFromRangeReverse(list *skip.List, rng Range) *memRangeIter {
+	// use the upper bound of |rng| to construct a skip.ListIter
+	iter := list.GetReverseIterFromSeekFn(skipSearchFromRangeReverse(rng))
+
+	// enforce range end
+	var key val.Tuple
+	for {
+		key, _ = iter.Current()
+		if key == nil || rng.belowStop(key) {
+			break // |i| inside |rng|
+		}
+		iter.Retreat()
+	}
+
+	// enforce range start
+	if key == nil || !rng.aboveStart(key) {
+		iter = nil
+	}
+
+	return &memRangeIter{
+		iter:    iter,
+		rng:     rng,
+		reverse: true,
+	}
+}
+
 // skipSearchFromRange is a skip.SeekFn used to initialize
 // a skip.List iterator for a given Range. The skip.SearchFn
 // returns true if the iter being initialized is not yet
@@ -146,6 +178,19 @@ func skipSearchFromRange(rng Range) skip.SeekFn {
 	}
 }
 
+// skipSearchFromRangeReverse is a skip.SeekFn used to initialize
+// a descending skip.List iterator for a given Range. The iter
+// is initialized at the last key for which the skip.SearchFn
+// returns true, the last key within the upper bound of |rng|.
+func skipSearchFromRangeReverse(rng Range) skip.SeekFn {
+	return func(nodeKey []byte) bool {
+		if nodeKey == nil {
+			return false
+		}
+		return rng.belowStop(nodeKey)
+	}
+}
+
 // todo(andy): generalize Range iteration and consolidate this
 // iterator with orderedListIter[K, V] in ordered_map.go.
 // This is not currently possible due to Range checking logic
@@ -153,6 +198,9 @@ func skipSearchFromRange(rng Range) skip.SeekFn {
 type memRangeIter struct {
 	iter *skip.ListIter
 	rng  Range
+	// reverse is true if the iter
+	// iterates |rng| in descending order
+	reverse bool
 }
 
 // Current returns the iter's current Tuple pair, or nil Tuples
@@ -168,11 +216,19 @@ func (it *memRangeIter) Current() (key, value val.Tuple) {
 // Iterate progresses the iter inside its range.
 func (it *memRangeIter) Iterate(context.Context) (err error) {
 	for {
-		it.iter.Advance()
+		if it.reverse {
+			it.iter.Retreat()
+		} else {
+			it.iter.Advance()
+		}
 
 		k, _ := it.Current()
-		if k == nil || !it.rng.belowStop(k) {
+		if k == nil {
 			it.iter = nil // range exhausted
+		} else if it.reverse && !it.rng.aboveStart(k) {
+			it.iter = nil
+		} else if !it.reverse && !it.rng.belowStop(k) {
+			it.iter = nil
 		}
 
 		return
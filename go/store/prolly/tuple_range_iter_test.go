@@ -121,6 +121,53 @@ func testIterRange(t *testing.T, om testMap, tuples [][2]val.Tuple) {
 	}
 }
 
+// testIterRangeReverse checks that IterRangeReverse gives the
+// same Tuples as IterRange, in descending order
+func testIterRangeReverse(t *testing.T, om testMap, tuples [][2]val.Tuple) {
+	ctx := context.Background()
+	desc := keyDescFromMap(om)
+
+	collect := func(iter MapIter) (keys []val.Tuple) {
+		for {
+			k, _, err := iter.Next(ctx)
+			if err == io.EOF {
+				return
+			}
+			require.NoError(t, err)
+			keys = append(keys, k)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		cnt := len(tuples)
+		a, z := testRand.Intn(cnt), testRand.Intn(cnt)
+		if a > z {
+			a, z = z, a
+		}
+		start, stop := tuples[a][0], tuples[z][0]
+
+		ranges := []Range{
+			openRange(start, stop, desc),
+			closedRange(start, stop, desc),
+			greaterRange(start, desc),
+			lesserOrEqualRange(stop, desc),
+		}
+		for _, rng := range ranges {
+			iter, err := om.IterRange(ctx, rng)
+			require.NoError(t, err)
+			exp := collect(iter)
+			for l, r := 0, len(exp)-1; l < r; l, r = l+1, r-1 {
+				exp[l], exp[r] = exp[r], exp[l]
+			}
+
+			iter, err = om.IterRangeReverse(ctx, rng)
+			require.NoError(t, err)
+			act := collect(iter)
+			assert.Equal(t, exp, act)
+		}
+	}
+}
+
 func nonNegative(x int) int {
 	if x < 0 {
 		x = 0
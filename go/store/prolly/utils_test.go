@@ -33,6 +33,7 @@ type testMap interface {
 	Get(ctx context.Context, key val.Tuple, cb tree.KeyValueFn[val.Tuple, val.Tuple]) (err error)
 	IterAll(ctx context.Context) (MapIter, error)
 	IterRange(ctx context.Context, rng Range) (MapIter, error)
+	IterRangeReverse(ctx context.Context, rng Range) (MapIter, error)
 	Descriptors() (val.TupleDesc, val.TupleDesc)
 }
 
@@ -17,13 +17,19 @@ package skip
 import (
 	"hash/maphash"
 	"math"
+	"sync"
+	"sync/atomic"
 )
 
 const (
 	maxHeight  = 9
+	maxScale   = 3
 	maxCount   = math.MaxInt32 - 1
 	sentinelId = nodeId(0)
 	initSize   = 8
+	slabShift  = 10
+	slabSize   = 1 << slabShift
+	slabMask   = slabSize - 1
 )
 
 // A KeyOrder determines the ordering of two keys |l| and |r|.
@@ -34,22 +40,36 @@ type KeyOrder func(l, r []byte) (cmp int)
 type SeekFn func(key []byte) (advance bool)
 
 // List is an in-memory skip-list.
+//
+// A List can be read with Get, Has, GetEntry and its
+// iterators by any number of goroutines concurrently
+// with a single goroutine writing to it with Put and
+// Delete. Readers see each write entirely or not at all.
+// Other methods must not be called concurrently with
+// writes, and Revert and Truncate must not be called
+// concurrently with reads.
 type List struct {
-	// nodes contains all skipNode's in the List.
-	// skipNode's are assigned ascending id's and
-	// are stored in the order they were created,
-	// i.e. skipNode.id stores its index in |nodes|
-	nodes []skipNode
+	// arena stores the skipNode's in the List. It is
+	// replaced when the List is compacted, see compact
+	arena atomic.Pointer[arena]
+
+	// size stores the number of skipNode's in the List
+	size uint32
 
 	// count stores the current number of items in
 	// the list (updates are not made in-place)
-	count uint32
+	count atomic.Uint32
 
 	// checkpoint stores the nodeId of the last
 	// checkpoint made. All nodes created after this
 	// point will be discarded on a Revert()
 	checkpoint nodeId
 
+	// scale is the number of levels of the
+	// skip-list with p = 1/e that each level
+	// of the list spans, see rollHeight
+	scale uint8
+
 	// keyOrder determines the ordering of items
 	keyOrder KeyOrder
 
@@ -57,8 +77,36 @@ type List struct {
 	seed maphash.Seed
 }
 
+// arena contains the skipNode's of a List, and the
+// greatest height of those linked into it. Each read of
+// a List, including the life of an iterator, reads a
+// single arena, since node ids are only meaningful
+// within the arena they were created in.
+type arena struct {
+	// nodes is replaced, rather than modified, as the
+	// arena grows so that it can be read concurrently
+	// with writes
+	nodes atomic.Pointer[nodeStore]
+
+	// level is the greatest height of
+	// the nodes linked into the arena
+	level atomic.Uint32
+}
+
 type nodeId uint32
 
+// loadId atomically loads the nodeId at |p|.
+func loadId(p *nodeId) nodeId {
+	return nodeId(atomic.LoadUint32((*uint32)(p)))
+}
+
+// storeId atomically stores |id| at |p|. Links between
+// skipNode's are written with storeId and read with loadId
+// so that readers see nodes only once they are complete.
+func storeId(p *nodeId, id nodeId) {
+	atomic.StoreUint32((*uint32)(p), uint32(id))
+}
+
 // tower is a multi-level skipNode pointer.
 type tower [maxHeight + 1]nodeId
 
@@ -68,25 +116,61 @@ type skipNode struct {
 	next     tower
 	prev     nodeId
 	height   uint8
+	// deleted is true if the node is a
+	// tombstone recording a deletion
+	deleted bool
+}
+
+// nodeStore contains the skipNode's in a List. |head|
+// contains its first slabSize nodes, and |slabs| contains
+// the rest, in slabs of slabSize nodes. skipNode's are
+// assigned ascending id's and are stored in the order they
+// were created, i.e. skipNode.id stores its index in |head|,
+// or its slab and index in |slabs|.
+type nodeStore struct {
+	head  []skipNode
+	slabs []*nodeSlab
+}
+
+// nodeSlab is a fixed-size block of skipNode's. Lists
+// grow |head| up to slabSize nodes, and then by a slab
+// at a time, so that large Lists grow without copying
+// their nodes.
+type nodeSlab [slabSize]skipNode
+
+// slabPool holds the slabs released by Truncate for reuse
+// by Lists as they grow. Edit buffers are filled, flushed
+// and truncated repeatedly under heavy write workloads, and
+// pooling their slabs saves allocating and collecting them
+// each time.
+var slabPool = sync.Pool{
+	New: func() any {
+		return new(nodeSlab)
+	},
 }
 
 // NewSkipList returns a new skip.List.
 func NewSkipList(order KeyOrder) *List {
-	nodes := make([]skipNode, 0, initSize)
+	head := make([]skipNode, initSize)
 
 	// initialize sentinel node
-	nodes = append(nodes, skipNode{
+	head[sentinelId] = skipNode{
 		id:     sentinelId,
 		height: maxHeight,
 		prev:   sentinelId,
-	})
+	}
 
-	return &List{
-		nodes:      nodes,
+	l := &List{
+		size:       1,
 		checkpoint: nodeId(1),
+		scale:      1,
 		keyOrder:   order,
 		seed:       maphash.MakeSeed(),
 	}
+	a := &arena{}
+	a.nodes.Store(&nodeStore{head: head})
+	l.arena.Store(a)
+	return l
 }
 
 // Checkpoint records a checkpoint that can be reverted to.
@@ -101,44 +185,79 @@ func (l *List) HasCheckpoint() bool {
 // Revert reverts to the last recorded checkpoint.
 func (l *List) Revert() {
 	cp := l.checkpoint
-	keepers := l.nodes[1:cp]
-	l.Truncate()
-	for _, nd := range keepers {
-		l.Put(nd.key, nd.val)
+	l.reset()
+	// keepers are re-inserted in the order they were
+	// created, so each is read before its slot is reused
+	for id := nodeId(1); id < cp; id++ {
+		nd := l.nodePtr(id)
+		l.put(nd.key, nd.val, nd.deleted)
 	}
 	l.checkpoint = cp
 }
 
 // Truncate deletes all entries from the list.
 func (l *List) Truncate() {
-	l.nodes = l.nodes[:1]
+	l.reset()
+	ns := l.arena.Load().nodes.Load()
+	for i, sl := range ns.slabs {
+		if sl != nil {
+			// drop references to keys and values
+			*sl = nodeSlab{}
+			slabPool.Put(sl)
+		}
+		ns.slabs[i] = nil
+	}
+	ns.slabs = ns.slabs[:0]
+}
+
+// reset deletes all entries from the list, keeping its slabs.
+func (l *List) reset() {
+	l.size = 1
 	// point sentinel.prev at itself
 	s := l.nodePtr(sentinelId)
 	s.next = tower{}
 	s.prev = sentinelId
 	l.checkpoint = nodeId(1)
-	l.count = 0
+	l.count.Store(0)
+	l.arena.Load().level.Store(0)
+	l.scale = 1
 }
 
-// Count returns the number of items in the list.
+// Count returns the number of items in the list,
+// including deleted keys.
 func (l *List) Count() int {
-	return int(l.count)
+	return int(l.count.Load())
 }
 
-// Has returns true if |key| is a member of the list.
+// Has returns true if |key| is a member of the list
+// and has not been deleted.
 func (l *List) Has(key []byte) (ok bool) {
 	_, ok = l.Get(key)
 	return
 }
 
 // Get returns the value associated with |key| and true
-// if |key| is a member of the list, otherwise it returns
-// nil and false.
+// if |key| is a member of the list and has not been
+// deleted, otherwise it returns nil and false.
 func (l *List) Get(key []byte) (val []byte, ok bool) {
+	val, deleted, ok := l.GetEntry(key)
+	if deleted {
+		ok = false
+	}
+	return
+}
+
+// GetEntry returns the value associated with |key|,
+// whether |key| has been deleted, and true if |key| is
+// a member of the list, otherwise it returns nil, false
+// and false. A deleted key remains a member of the list
+// as a tombstone, with a nil value.
+func (l *List) GetEntry(key []byte) (val []byte, deleted, ok bool) {
 	var id nodeId
-	next, prev := l.headTower(), sentinelId
-	for lvl := maxHeight; lvl >= 0; {
-		nd := l.nodePtr(next[lvl])
+	a := l.arena.Load()
+	next, prev := a.headTower(), sentinelId
+	for lvl := int(a.level.Load()); lvl >= 0; {
+		nd := a.nodePtr(loadId(&next[lvl]))
 		// descend if we can't advance at |lvl|
 		if l.compareKeys(key, nd.key) < 0 {
 			id = prev
@@ -149,26 +268,41 @@ func (l *List) Get(key []byte) (val []byte, ok bool) {
 		next = &nd.next
 		prev = nd.id
 	}
-	node := l.nodePtr(id)
+	node := a.nodePtr(id)
 	if l.compareKeys(key, node.key) == 0 {
-		val, ok = node.val, true
+		val, deleted, ok = node.val, node.deleted, true
 	}
 	return
 }
 
 // Put adds |key| and |values| to the list.
 func (l *List) Put(key, val []byte) {
+	l.put(key, val, false)
+}
+
+// Delete records the deletion of |key| with a tombstone.
+// Deleted keys remain members of the list, so that edits
+// accumulated in the list can shadow the keys they delete
+// in the data the edits are applied to.
+func (l *List) Delete(key []byte) {
+	l.put(key, nil, true)
+}
+
+func (l *List) put(key, val []byte, deleted bool) {
 	if key == nil {
 		panic("key must be non-nil")
-	} else if len(l.nodes) >= maxCount {
-		panic("list has no capacity")
+	} else if l.size >= maxCount {
+		l.compact()
+		if l.size >= maxCount {
+			panic("list has no capacity")
+		}
 	}
 
 	// find the path to the greatest
 	// existing node key less than |key|
 	var path tower
 	next, prev := l.headTower(), sentinelId
-	for h := maxHeight; h >= 0; {
+	for h := int(l.arena.Load().level.Load()); h >= 0; {
 		curr := l.nodePtr(next[h])
 		// descend if we can't advance at |lvl|
 		if l.compareKeys(key, curr.key) <= 0 {
@@ -186,85 +320,180 @@ func (l *List) Put(key, val []byte) {
 	node = l.nodePtr(node.next[0])
 
 	if l.compareKeys(key, node.key) == 0 {
-		l.overwrite(key, val, &path, node)
+		l.overwrite(key, val, deleted, &path, node)
 	} else {
-		l.insert(key, val, &path)
-		l.count++
+		l.insert(key, val, deleted, &path)
+		count := l.count.Add(1)
+		if l.scale < maxScale && count > scaleLimits[l.scale] {
+			l.rescale(l.scale + 1)
+		}
 	}
 }
 
+// rescale re-rolls the heights of the nodes linked into
+// the list for |scale|, and relinks them at their heights.
+// Each link only ever points forward in the list, and the
+// nodes' links at level 0 are unchanged, so readers find
+// every node while the list is relinked.
+func (l *List) rescale(scale uint8) {
+	l.scale = scale
+	var level uint8
+	// the last node linked at each level
+	var last tower
+	for id := l.headTower()[0]; id != sentinelId; {
+		nd := l.nodePtr(id)
+		next := nd.next[0]
+		nd.height = l.rollHeight(nd.key)
+		if nd.height > level {
+			level = nd.height
+		}
+		for h := uint8(0); h <= nd.height; h++ {
+			storeId(&l.nodePtr(last[h]).next[h], id)
+			last[h] = id
+		}
+		id = next
+	}
+	for h := range last {
+		storeId(&l.nodePtr(last[h]).next[h], sentinelId)
+	}
+	l.arena.Load().level.Store(uint32(level))
+}
+
+// compact frees the ids of the nodes which were overwritten
+// since the last checkpoint, which are needed neither by the
+// list nor by Revert. Nodes are never updated in place, so a
+// list of frequently overwritten keys can run out of ids.
+// The compacted list is built in a new arena, which replaces
+// the list's arena once it's complete. The old arena is left
+// as it was, so reads that began before compaction read the
+// list as it was, and never see nodes reused.
+func (l *List) compact() {
+	a := l.arena.Load()
+	cp := l.checkpoint
+	fresh := NewSkipList(l.keyOrder)
+	fresh.seed = l.seed
+	// replay the nodes created before the checkpoint, as
+	// Revert does, then the edits since the checkpoint
+	// which are still linked into the list
+	for id := nodeId(1); id < cp; id++ {
+		nd := a.nodePtr(id)
+		fresh.put(nd.key, nd.val, nd.deleted)
+	}
+	fresh.Checkpoint()
+	for id := a.headTower()[0]; id != sentinelId; {
+		nd := a.nodePtr(id)
+		if id >= cp {
+			fresh.put(nd.key, nd.val, nd.deleted)
+		}
+		id = nd.next[0]
+	}
+	l.size = fresh.size
+	l.checkpoint = fresh.checkpoint
+	l.scale = fresh.scale
+	l.count.Store(fresh.count.Load())
+	l.arena.Store(fresh.arena.Load())
+}
+
 func (l *List) Copy() *List {
-	copies := make([]skipNode, len(l.nodes))
-	copy(copies, l.nodes)
-	return &List{
-		nodes:      copies,
-		count:      l.count,
+	src := l.arena.Load()
+	ns := src.nodes.Load()
+	head := make([]skipNode, len(ns.head))
+	copy(head, ns.head)
+	slabs := make([]*nodeSlab, len(ns.slabs))
+	for i, sl := range ns.slabs {
+		if sl != nil {
+			slabs[i] = slabPool.Get().(*nodeSlab)
+			*slabs[i] = *sl
+		}
+	}
+	cp := &List{
+		size:       l.size,
 		checkpoint: l.checkpoint,
+		scale:      l.scale,
 		keyOrder:   l.keyOrder,
 		seed:       l.seed,
 	}
-}
-
-func (l *List) insert(key, value []byte, path *tower) {
-	id := l.nextNodeId()
-	l.nodes = append(l.nodes, skipNode{
-		key:    key,
-		val:    value,
-		id:     id,
-		height: l.rollHeight(key),
-	})
-	novel := l.nodePtr(id)
+	a := &arena{}
+	a.nodes.Store(&nodeStore{head: head, slabs: slabs})
+	a.level.Store(src.level.Load())
+	cp.arena.Store(a)
+	cp.count.Store(l.count.Load())
+	return cp
+}
+
+func (l *List) insert(key, value []byte, deleted bool, path *tower) {
+	novel := l.newNode()
+	*novel = skipNode{
+		key:     key,
+		val:     value,
+		id:      novel.id,
+		height:  l.rollHeight(key),
+		deleted: deleted,
+	}
 	for h := uint8(0); h <= novel.height; h++ {
 		// set forward pointers
-		n := l.nodePtr(path[h])
-		novel.next[h] = n.next[h]
-		n.next[h] = novel.id
+		novel.next[h] = l.nodePtr(path[h]).next[h]
+	}
+	novel.prev = l.nodePtr(novel.next[0]).prev
+
+	// link |novel| into the list from the bottom up,
+	// now that it's complete, for concurrent readers
+	for h := uint8(0); h <= novel.height; h++ {
+		storeId(&l.nodePtr(path[h]).next[h], novel.id)
+	}
+	// set back pointer
+	storeId(&l.nodePtr(novel.next[0]).prev, novel.id)
+	if a := l.arena.Load(); uint32(novel.height) > a.level.Load() {
+		a.level.Store(uint32(novel.height))
 	}
-	// set back pointers
-	n := l.nodePtr(novel.next[0])
-	novel.prev = n.prev
-	n.prev = novel.id
 }
 
-func (l *List) overwrite(key, value []byte, path *tower, old *skipNode) {
-	id := l.nextNodeId()
-	l.nodes = append(l.nodes, skipNode{
-		key:    key,
-		val:    value,
-		id:     id,
-		next:   old.next,
-		prev:   old.prev,
-		height: old.height,
-	})
+func (l *List) overwrite(key, value []byte, deleted bool, path *tower, old *skipNode) {
+	nd := l.newNode()
+	id := nd.id
+	*nd = skipNode{
+		key:     key,
+		val:     value,
+		id:      id,
+		next:    old.next,
+		prev:    old.prev,
+		height:  old.height,
+		deleted: deleted,
+	}
 	for h := uint8(0); h <= old.height; h++ {
 		// set forward pointers
-		n := l.nodePtr(path[h])
-		n.next[h] = id
+		storeId(&l.nodePtr(path[h]).next[h], id)
 	}
 	// set back pointer
-	n := l.nodePtr(old.next[0])
-	n.prev = id
+	storeId(&l.nodePtr(old.next[0]).prev, id)
 }
 
 type ListIter struct {
-	curr *skipNode
-	list *List
+	curr  *skipNode
+	arena *arena
 }
 
 // Current returns the current key and value of the iterator.
+// The value of a deleted key is nil.
 func (it *ListIter) Current() (key, val []byte) {
 	return it.curr.key, it.curr.val
 }
 
+// Deleted returns true if the current key of the iterator
+// has been deleted.
+func (it *ListIter) Deleted() bool {
+	return it.curr.deleted
+}
+
 // Advance advances the iterator.
 func (it *ListIter) Advance() {
-	it.curr = it.list.nodePtr(it.curr.next[0])
+	it.curr = it.arena.nodePtr(loadId(&it.curr.next[0]))
 	return
 }
 
 // Retreat retreats the iterator.
 func (it *ListIter) Retreat() {
-	it.curr = it.list.nodePtr(it.curr.prev)
+	it.curr = it.arena.nodePtr(loadId(&it.curr.prev))
 	return
 }
 
@@ -278,9 +507,10 @@ func (l *List) GetIterAt(key []byte) (it *ListIter) {
 
 // GetIterFromSeekFn creates an iterator using a SeekFn.
 func (l *List) GetIterFromSeekFn(fn SeekFn) (it *ListIter) {
+	a := l.arena.Load()
 	it = &ListIter{
-		curr: l.seekWithFn(fn),
-		list: l,
+		curr:  l.seekWithFn(a, fn),
+		arena: a,
 	}
 	if it.curr.id == sentinelId {
 		// try to keep |it| in bounds if |key| is
@@ -290,60 +520,138 @@ func (l *List) GetIterFromSeekFn(fn SeekFn) (it *ListIter) {
 	return
 }
 
+// GetReverseIterFromSeekFn creates an iterator at the last
+// item of the list for which |fn| returns true, to iterate the
+// list in descending order with Retreat. The iterator's key is
+// nil if there is no such item.
+func (l *List) GetReverseIterFromSeekFn(fn SeekFn) (it *ListIter) {
+	a := l.arena.Load()
+	it = &ListIter{
+		curr:  l.seekWithFn(a, fn),
+		arena: a,
+	}
+	it.Retreat()
+	return
+}
+
+// SeekGE creates an iterator at the first item of the list
+// whose key is greater than or equal to |key|. Unlike GetIterAt,
+// the iterator's key is nil if there is no such item.
+func (l *List) SeekGE(key []byte) *ListIter {
+	a := l.arena.Load()
+	return &ListIter{
+		curr:  l.seek(a, key),
+		arena: a,
+	}
+}
+
+// SeekLE creates an iterator at the last item of the list whose
+// key is less than or equal to |key|, to iterate the list in
+// descending order. The iterator's key is nil if there is no
+// such item.
+func (l *List) SeekLE(key []byte) *ListIter {
+	return l.GetReverseIterFromSeekFn(func(nodeKey []byte) bool {
+		return l.compareKeys(key, nodeKey) >= 0
+	})
+}
+
 // IterAtStart creates an iterator at the start of the list.
 func (l *List) IterAtStart() *ListIter {
+	a := l.arena.Load()
 	return &ListIter{
-		curr: l.firstNode(),
-		list: l,
+		curr:  a.firstNode(),
+		arena: a,
 	}
 }
 
 // IterAtEnd creates an iterator at the end of the list.
 func (l *List) IterAtEnd() *ListIter {
+	a := l.arena.Load()
 	return &ListIter{
-		curr: l.lastNode(),
-		list: l,
+		curr:  a.lastNode(),
+		arena: a,
 	}
 }
 
-// seek returns the skipNode with the smallest key >= |key|.
-func (l *List) seek(key []byte) *skipNode {
-	return l.seekWithFn(func(curr []byte) (advance bool) {
+// seek returns the skipNode in |a| with the smallest key >= |key|.
+func (l *List) seek(a *arena, key []byte) *skipNode {
+	return l.seekWithFn(a, func(curr []byte) (advance bool) {
 		return l.compareKeys(key, curr) > 0
 	})
 }
 
-func (l *List) seekWithFn(cb SeekFn) (node *skipNode) {
-	ptr := l.headTower()
-	for h := int64(maxHeight); h >= 0; h-- {
-		node = l.nodePtr(ptr[h])
+func (l *List) seekWithFn(a *arena, cb SeekFn) (node *skipNode) {
+	ptr := a.headTower()
+	for h := int(a.level.Load()); h >= 0; h-- {
+		node = a.nodePtr(loadId(&ptr[h]))
 		for cb(node.key) {
 			ptr = &node.next
-			node = l.nodePtr(ptr[h])
+			node = a.nodePtr(loadId(&ptr[h]))
 		}
 	}
 	return
 }
 
 func (l *List) headTower() *tower {
-	return &l.nodes[0].next
+	return l.arena.Load().headTower()
 }
 
-func (l *List) firstNode() *skipNode {
-	return l.nodePtr(l.nodes[0].next[0])
+func (l *List) nodePtr(id nodeId) *skipNode {
+	return l.arena.Load().nodePtr(id)
 }
 
-func (l *List) lastNode() *skipNode {
-	s := l.nodePtr(sentinelId)
-	return l.nodePtr(s.prev)
+func (a *arena) headTower() *tower {
+	return &a.nodes.Load().head[sentinelId].next
 }
 
-func (l *List) nodePtr(id nodeId) *skipNode {
-	return &l.nodes[id]
+func (a *arena) firstNode() *skipNode {
+	return a.nodePtr(loadId(&a.headTower()[0]))
+}
+
+func (a *arena) lastNode() *skipNode {
+	s := a.nodePtr(sentinelId)
+	return a.nodePtr(loadId(&s.prev))
+}
+
+func (a *arena) nodePtr(id nodeId) *skipNode {
+	ns := a.nodes.Load()
+	if id < slabSize {
+		return &ns.head[id]
+	}
+	return &ns.slabs[id>>slabShift][id&slabMask]
 }
 
 func (l *List) nextNodeId() nodeId {
-	return nodeId(len(l.nodes))
+	return nodeId(l.size)
+}
+
+// newNode returns the slot of the next skipNode
+// with its id set, growing |l| if it is full. The
+// nodeStore of |l| is replaced as it grows, as it
+// may be read concurrently.
+func (l *List) newNode() *skipNode {
+	id := l.nextNodeId()
+	a := l.arena.Load()
+	ns := a.nodes.Load()
+	if id < slabSize {
+		if int(id) == len(ns.head) {
+			head := make([]skipNode, 2*len(ns.head))
+			copy(head, ns.head)
+			a.nodes.Store(&nodeStore{head: head, slabs: ns.slabs})
+		}
+	} else if int(id>>slabShift) >= len(ns.slabs) {
+		slabs := ns.slabs
+		if len(slabs) == 0 {
+			// |head| stands in for the first slab
+			slabs = append(slabs, nil)
+		}
+		slabs = append(slabs, slabPool.Get().(*nodeSlab))
+		a.nodes.Store(&nodeStore{head: ns.head, slabs: slabs})
+	}
+	l.size++
+	nd := l.nodePtr(id)
+	nd.id = id
+	return nd
 }
 
 func (l *List) compareKeys(left, right []byte) int {
@@ -358,21 +666,36 @@ var (
 	// p-value can be used (inverse of Euler's number).
 	//
 	// https://github.com/andy-kimball/arenaskl/blob/master/skl.go
-	probabilities = [maxHeight]uint32{}
+	probabilities = [maxHeight * maxScale]uint64{}
+
+	// scaleLimits are the counts beyond which a List is
+	// rescaled, by the scale it has. A List with a scale
+	// of s has about count / e^(s * maxHeight) nodes at
+	// its highest level, which are searched linearly.
+	scaleLimits = [maxScale]uint32{}
 )
 
 func init() {
 	p := float64(1.0)
-	for i := uint8(0); i < maxHeight; i++ {
+	for i := range probabilities {
 		p /= math.E
-		probabilities[i] = uint32(float64(math.MaxUint32) * p)
+		probabilities[i] = uint64(float64(math.MaxUint64) * p)
+	}
+	for s := 1; s < maxScale; s++ {
+		scaleLimits[s] = uint32(16 * math.Exp(float64(s*maxHeight)))
 	}
 }
 
+// rollHeight returns the height of the node for |key|.
+// Heights are rolled with p = 1/e for each of |l.scale|
+// levels of a node, so that a large list, which has a
+// greater scale, spans its keys in maxHeight levels.
 func (l *List) rollHeight(key []byte) (h uint8) {
 	rnd := maphash.Bytes(l.seed, key)
-	for h < maxHeight && uint32(rnd) <= probabilities[h] {
-		h++
+	lim := maxHeight * int(l.scale)
+	var r int
+	for r < lim && rnd <= probabilities[r] {
+		r++
 	}
-	return
+	return uint8(r / int(l.scale))
 }
@@ -31,6 +31,9 @@ func BenchmarkGet(b *testing.B) {
 		b.Run("n=65536", func(b *testing.B) {
 			benchmarkGet(b, randomInts(65536))
 		})
+		b.Run("n=1048576", func(b *testing.B) {
+			benchmarkGet(b, randomInts(1048576))
+		})
 	})
 	b.Run("ascending keys", func(b *testing.B) {
 		b.Run("n=64", func(b *testing.B) {
@@ -67,6 +70,9 @@ func BenchmarkPut(b *testing.B) {
 		b.Run("n=65536", func(b *testing.B) {
 			benchmarkPut(b, randomInts(65536))
 		})
+		b.Run("n=1048576", func(b *testing.B) {
+			benchmarkPut(b, randomInts(1048576))
+		})
 	})
 	b.Run("asending keys", func(b *testing.B) {
 		b.Run("n=64", func(b *testing.B) {
@@ -92,6 +98,15 @@ func BenchmarkPut(b *testing.B) {
 	})
 }
 
+func BenchmarkFlushEdits(b *testing.B) {
+	b.Run("n=2048", func(b *testing.B) {
+		benchmarkFlushEdits(b, randomInts(2048))
+	})
+	b.Run("n=65536", func(b *testing.B) {
+		benchmarkFlushEdits(b, randomInts(65536))
+	})
+}
+
 func BenchmarkIterAll(b *testing.B) {
 	b.Run("unsorted keys", func(b *testing.B) {
 		b.Run("n=64", func(b *testing.B) {
@@ -155,6 +170,20 @@ func benchmarkPut(b *testing.B, vals [][]byte) {
 	b.ReportAllocs()
 }
 
+// benchmarkFlushEdits fills and truncates a new List in each
+// iteration, as each write session's edit buffers are
+func benchmarkFlushEdits(b *testing.B, vals [][]byte) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := NewSkipList(bytes.Compare)
+		for j := range vals {
+			l.Put(vals[j], vals[j])
+		}
+		l.Truncate()
+	}
+	b.ReportAllocs()
+}
+
 func benchmarkIterAll(b *testing.B, vals [][]byte) {
 	l := NewSkipList(bytes.Compare)
 	for i := range vals {
@@ -17,8 +17,11 @@ package skip
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"unsafe"
 
@@ -77,6 +80,283 @@ func TestSkipListCheckpoints(t *testing.T) {
 	})
 }
 
+func TestSkipListDeletes(t *testing.T) {
+	list := NewSkipList(bytes.Compare)
+	list.Put(b("a"), b("1"))
+	list.Put(b("b"), b("2"))
+
+	// deleting keys in and not in the list
+	list.Delete(b("a"))
+	list.Delete(b("c"))
+	for _, k := range [][]byte{b("a"), b("c")} {
+		assert.False(t, list.Has(k))
+		_, ok := list.Get(k)
+		assert.False(t, ok)
+		val, deleted, ok := list.GetEntry(k)
+		assert.True(t, ok)
+		assert.True(t, deleted)
+		assert.Nil(t, val)
+	}
+	val, deleted, ok := list.GetEntry(b("b"))
+	assert.True(t, ok)
+	assert.False(t, deleted)
+	assert.Equal(t, b("2"), val)
+	assert.Equal(t, 3, list.Count())
+
+	var tombstones []string
+	for it := list.IterAtStart(); ; it.Advance() {
+		k, v := it.Current()
+		if k == nil {
+			break
+		}
+		if it.Deleted() {
+			assert.Nil(t, v)
+			tombstones = append(tombstones, string(k))
+		}
+	}
+	assert.Equal(t, []string{"a", "c"}, tombstones)
+
+	// tombstones are kept by Revert
+	list.Checkpoint()
+	list.Put(b("a"), b("3"))
+	list.Delete(b("b"))
+	assert.True(t, list.Has(b("a")))
+	assert.False(t, list.Has(b("b")))
+	list.Revert()
+	assert.False(t, list.Has(b("a")))
+	assert.True(t, list.Has(b("b")))
+	_, deleted, ok = list.GetEntry(b("a"))
+	assert.True(t, ok)
+	assert.True(t, deleted)
+}
+
+func TestSkipListSeek(t *testing.T) {
+	// even keys, so that odd keys fall between them
+	list := NewSkipList(bytes.Compare)
+	for i := 2; i <= 200; i += 2 {
+		k := []byte(fmt.Sprintf("%03d", i))
+		list.Put(k, k)
+	}
+	key := func(i int) []byte {
+		return []byte(fmt.Sprintf("%03d", i))
+	}
+	current := func(it *ListIter) []byte {
+		k, _ := it.Current()
+		return k
+	}
+
+	for i := 0; i <= 201; i++ {
+		ge, le := i+i%2, i-i%2
+		if ge < 2 {
+			ge = 2
+		}
+		if ge > 200 {
+			assert.Nil(t, current(list.SeekGE(key(i))))
+		} else {
+			assert.Equal(t, key(ge), current(list.SeekGE(key(i))))
+		}
+		if le < 2 {
+			assert.Nil(t, current(list.SeekLE(key(i))))
+		} else {
+			assert.Equal(t, key(le), current(list.SeekLE(key(i))))
+		}
+	}
+
+	// iterate descending from an upper bound
+	it := list.SeekLE(key(101))
+	exp := 100
+	for k := current(it); k != nil; k = current(it) {
+		assert.Equal(t, key(exp), k)
+		exp -= 2
+		it.Retreat()
+	}
+	assert.Equal(t, 0, exp)
+
+	// the last item before an exclusive upper bound
+	it = list.GetReverseIterFromSeekFn(func(nodeKey []byte) bool {
+		return nodeKey != nil && bytes.Compare(nodeKey, key(100)) < 0
+	})
+	assert.Equal(t, key(98), current(it))
+}
+
+func TestSkipListTruncateAndCopy(t *testing.T) {
+	// enough values to fill several slabs
+	vals := randomInts(4 * slabSize)
+	list := NewSkipList(bytes.Compare)
+	for _, v := range vals {
+		list.Put(v, v)
+	}
+	cp := list.Copy()
+
+	// refilling |list| reuses the slabs released by Truncate
+	list.Truncate()
+	assert.Equal(t, 0, list.Count())
+	others := randomInts(4 * slabSize)
+	for _, v := range others {
+		list.Put(v, v)
+	}
+	for id := nodeId(0); id < list.nextNodeId(); id++ {
+		assert.Equal(t, id, list.nodePtr(id).id)
+	}
+	testSkipListGets(t, list, others...)
+
+	// |cp| doesn't share slabs with |list|
+	assert.Equal(t, len(vals), cp.Count())
+	testSkipListGets(t, cp, vals...)
+}
+
+func TestSkipListRescale(t *testing.T) {
+	vals := randomInts(4 * slabSize)
+	list := NewSkipList(bytes.Compare)
+	for _, v := range vals {
+		list.Put(v, v)
+	}
+	for s := uint8(2); s <= maxScale; s++ {
+		list.rescale(s)
+		assert.True(t, list.arena.Load().level.Load() <= maxHeight)
+		testSkipListGets(t, list, vals...)
+		testSkipListIterForward(t, list, vals...)
+		testSkipListIterBackward(t, list, vals...)
+	}
+	// puts into a rescaled list are rolled at its scale
+	others := randomInts(slabSize)
+	for _, v := range others {
+		list.Put(v, v)
+	}
+	testSkipListGets(t, list, others...)
+	list.Truncate()
+	assert.Equal(t, uint8(1), list.scale)
+}
+
+func TestSkipListCompact(t *testing.T) {
+	vals := randomInts(slabSize)
+	list := NewSkipList(bytes.Compare)
+	for _, v := range vals {
+		list.Put(v, v)
+	}
+	list.Checkpoint()
+
+	// overwrite every key several times
+	for i := 0; i < 4; i++ {
+		for _, v := range vals {
+			list.Put(v, b(fmt.Sprintf("%d", i)))
+		}
+	}
+	list.Delete(vals[0])
+	list.compact()
+	// the sentinel, the checkpointed nodes and one node per edited key
+	assert.Equal(t, uint32(1+2*len(vals)), list.size)
+	assert.Equal(t, len(vals), list.Count())
+	for _, v := range vals[1:] {
+		act, ok := list.Get(v)
+		assert.True(t, ok)
+		assert.Equal(t, b("3"), act)
+	}
+	assert.False(t, list.Has(vals[0]))
+
+	// the checkpoint is kept by compact
+	list.Revert()
+	testSkipListGets(t, list, vals...)
+}
+
+func TestSkipListConcurrentReads(t *testing.T) {
+	// enough values to grow the list by several slabs
+	vals := randomInts(4 * slabSize)
+	list := NewSkipList(bytes.Compare)
+
+	// |written| is the number of |vals| in |list|
+	var written atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n := int(written.Load())
+				if n == len(vals) {
+					return
+				}
+				// every value written before the read is found
+				for _, v := range vals[:n] {
+					act, ok := list.Get(v)
+					assert.True(t, ok)
+					assert.Equal(t, v, act)
+				}
+				// iteration sees the keys written before it
+				// in order, and maybe some written during it
+				var cnt int
+				var prev []byte
+				for it := list.IterAtStart(); ; it.Advance() {
+					k, _ := it.Current()
+					if k == nil {
+						break
+					}
+					assert.True(t, prev == nil || bytes.Compare(prev, k) < 0)
+					prev = k
+					cnt++
+				}
+				assert.True(t, cnt >= n)
+			}
+		}()
+	}
+	for i, v := range vals {
+		list.Put(v, v)
+		written.Store(int64(i + 1))
+	}
+	wg.Wait()
+	testSkipListGets(t, list, vals...)
+}
+
+func TestSkipListConcurrentCompact(t *testing.T) {
+	vals := randomInts(2 * slabSize)
+	list := NewSkipList(bytes.Compare)
+	for _, v := range vals {
+		list.Put(v, v)
+	}
+	list.Checkpoint()
+
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() {
+				// every key is found, with its value, while
+				// the list is overwritten and compacted
+				for _, v := range vals {
+					act, ok := list.Get(v)
+					assert.True(t, ok)
+					assert.Equal(t, v, act)
+				}
+				var cnt int
+				var prev []byte
+				for it := list.IterAtStart(); ; it.Advance() {
+					k, v := it.Current()
+					if k == nil {
+						break
+					}
+					assert.True(t, prev == nil || bytes.Compare(prev, k) < 0)
+					assert.Equal(t, k, v)
+					prev = k
+					cnt++
+				}
+				assert.Equal(t, len(vals), cnt)
+			}
+		}()
+	}
+	for i := 0; i < 64; i++ {
+		for _, v := range vals {
+			list.Put(v, v)
+		}
+		list.compact()
+	}
+	done.Store(true)
+	wg.Wait()
+	assert.Equal(t, uint32(1+2*len(vals)), list.size)
+	testSkipListGets(t, list, vals...)
+}
+
 func TestMemoryFootprint(t *testing.T) {
 	var sz int
 	sz = int(unsafe.Sizeof(skipNode{}))
@@ -158,7 +438,7 @@ func testSkipListUpdates(t *testing.T, list *List, vals ...[]byte) {
 	}
 
 	// introspect list to assert copy-on-update behavior
-	assert.Equal(t, 1+len(vals)*2, len(list.nodes))
+	assert.Equal(t, 1+len(vals)*2, int(list.size))
 }
 
 func testSkipListIterForward(t *testing.T, list *List, vals ...[]byte) {
@@ -21,6 +21,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
@@ -36,6 +37,9 @@ func main() {
 	grpcPortParam := flag.Int("grpc-port", -1, "the port the grpc server will listen on; default 50051")
 	httpPortParam := flag.Int("http-port", -1, "the port the http server will listen on; default 80; if http-port is equal to grpc-port, both services will serve over the same port")
 	httpHostParam := flag.String("http-host", "", "hostname to use in the host component of the URLs that the server generates; default ''; if '', server will echo the :authority header")
+	preReceiveParam := flag.String("pre-receive-hook", "", "script or http(s) endpoint run before each push is applied; the push is rejected if it fails")
+	var webhookParams webhookFlags
+	flag.Var(&webhookParams, "webhook", "http(s) endpoint that is sent each push after it is applied; may be given more than once")
 	flag.Parse()
 
 	if dirParam != nil && len(*dirParam) > 0 {
@@ -88,6 +92,10 @@ func main() {
 		FS:             fs,
 		DBCache:        dbCache,
 		ReadOnly:       *readOnlyParam,
+		PushHooks: &remotesrv.PushHooks{
+			PreReceive: *preReceiveParam,
+			Webhooks:   webhookParams,
+		},
 	})
 	if err != nil {
 		log.Fatalf("error creating remotesrv Server: %v\n", err)
@@ -103,6 +111,18 @@ func main() {
 	server.GracefulStop()
 }
 
+// webhookFlags collects the values of a repeated -webhook flag.
+type webhookFlags []string
+
+func (f *webhookFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *webhookFlags) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
 func waitForSignal() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, os.Kill)
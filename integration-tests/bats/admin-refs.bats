@@ -0,0 +1,116 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "create table t (pk int primary key)"
+    dolt commit -Am "create table"
+    dolt branch other
+    dolt sql -q "insert into t values (1)"
+    dolt commit -am "insert a row"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+get_head_commit() {
+    dolt log -n 1 "$@" | grep -m 1 commit | awk '{print $2}'
+}
+
+@test "admin-refs: set-ref refuses to drop commits without --force" {
+    main=`get_head_commit main`
+    other=`get_head_commit other`
+
+    run dolt admin set-ref --branch main --to "$other"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "use --force" ]] || false
+    [ `get_head_commit main` = "$main" ]
+
+    run dolt admin set-ref --branch main --to "$other" --force --dry-run
+    [ $status -eq 0 ]
+    [[ "$output" =~ "would set refs/heads/main from $main to $other" ]] || false
+    [ `get_head_commit main` = "$main" ]
+
+    dolt admin set-ref --ref refs/heads/main --to "$other" --force
+    [ `get_head_commit main` = "$other" ]
+
+    # moving forward doesn't need --force
+    dolt admin set-ref --branch main --to "$main"
+    [ `get_head_commit main` = "$main" ]
+}
+
+@test "admin-refs: set-ref only sets branch, remote and tag refs" {
+    main=`get_head_commit main`
+
+    run dolt admin set-ref --ref workingSets/heads/main --to "$main"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "workingSets/heads/main is not a branch, remote or tag ref" ]] || false
+
+    run dolt admin set-ref --ref refs/internal/create --to "$main" --force
+    [ $status -eq 1 ]
+    [[ "$output" =~ "is not a branch, remote or tag ref" ]] || false
+
+    dolt admin set-ref --remote-name origin --remote-branch main --to "$main"
+    run dolt branch -r
+    [[ "$output" =~ "remotes/origin/main" ]] || false
+}
+
+@test "admin-refs: delete-ref guards the checked out branch" {
+    run dolt admin delete-ref --branch main
+    [ $status -eq 1 ]
+    [[ "$output" =~ "checked out branch" ]] || false
+
+    run dolt admin delete-ref --branch doesnotexist
+    [ $status -eq 1 ]
+    [[ "$output" =~ "does not exist" ]] || false
+
+    run dolt admin delete-ref --branch other --dry-run
+    [ $status -eq 0 ]
+    [[ "$output" =~ "would delete refs/heads/other" ]] || false
+    run dolt branch
+    [[ "$output" =~ "other" ]] || false
+
+    dolt admin delete-ref --branch other
+    run dolt branch
+    [[ ! "$output" =~ "other" ]] || false
+}
+
+@test "admin-refs: check-refs finds and fixes inconsistent refs" {
+    run dolt admin check-refs
+    [ $status -eq 0 ]
+    [[ "$output" =~ "no problems found" ]] || false
+
+    dolt admin delete-ref --ref workingSets/heads/other
+    run dolt admin check-refs
+    [ $status -eq 1 ]
+    [[ "$output" =~ "refs/heads/other has no working set" ]] || false
+
+    run dolt admin check-refs --fix
+    [ $status -eq 0 ]
+    [[ "$output" =~ "created one from its head commit" ]] || false
+    run dolt checkout other
+    [ $status -eq 0 ]
+
+    dolt admin delete-ref --branch main
+    run dolt admin check-refs
+    [ $status -eq 1 ]
+    [[ "$output" =~ "workingSets/heads/main has no corresponding refs/heads/main" ]] || false
+    dolt admin delete-ref --ref workingSets/heads/main
+
+    dolt admin set-head main --force
+    run dolt admin check-refs
+    [ $status -eq 1 ]
+    [[ "$output" =~ "HEAD points to refs/heads/main, which does not exist" ]] || false
+
+    run dolt admin set-head doesnotexist
+    [ $status -eq 1 ]
+    [[ "$output" =~ "use --force" ]] || false
+
+    dolt admin set-head other
+    run dolt admin check-refs
+    [ $status -eq 0 ]
+    [[ "$output" =~ "no problems found" ]] || false
+}
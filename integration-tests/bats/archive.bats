@@ -0,0 +1,188 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+remotesrv_pid=
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE t (pk int primary key, v varchar(100));"
+    dolt add -A && dolt commit -m "create table"
+    for i in 1 2 3 4; do
+        dolt sql -q "INSERT INTO t VALUES ($i, repeat('version $i ', 8));"
+        dolt commit -am "version $i"
+    done
+    dolt gc
+}
+
+teardown() {
+    teardown_common
+    if [ -n "$remotesrv_pid" ]; then
+        kill $remotesrv_pid || :
+    fi
+}
+
+@test "archive: old history is archived and can still be read" {
+    run dolt archive --older-than 2
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Archived" ]] || false
+
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~3'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "4" ]
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 6 ]
+
+    # archived chunks are not archived again
+    run dolt archive --older-than 2
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Nothing to archive" ]] || false
+
+    dolt sql -q "INSERT INTO t VALUES (5, 'version 5');"
+    dolt commit -am "version 5"
+    dolt gc
+
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~4'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+count_archives() {
+    n=0
+    for f in .dolt/noms/* .dolt/noms/oldgen/*; do
+        if [ -f "$f" ] && [ "$(tail -c 8 "$f")" = "DOLTARC1" ]; then
+            n=$((n+1))
+        fi
+    done
+    echo $n
+}
+
+@test "archive: each table is archived separately and --rebuild merges its archives" {
+    dolt sql -q "CREATE TABLE u (pk int primary key, w varchar(100));"
+    dolt add -A && dolt commit -m "create table u"
+    for i in 1 2 3 4; do
+        dolt sql -q "INSERT INTO t VALUES ($i + 4, repeat('version $i ', 8)); INSERT INTO u VALUES ($i, repeat('other $i ', 8));"
+        dolt commit -am "version $i of t and u"
+    done
+    dolt gc
+
+    # one archive for each of t and u, and one for the rest of the old data
+    dolt archive --older-than 4
+    [ "$(count_archives)" -eq 3 ]
+
+    dolt archive --older-than 2
+    [ "$(count_archives)" -eq 6 ]
+
+    run dolt archive --older-than 2 --rebuild
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Archived" ]] || false
+    [ "$(count_archives)" -eq 3 ]
+
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~3'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "5" ]
+    run dolt sql -q "SELECT count(*) FROM u AS OF 'HEAD~3'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "archive: archives survive dolt gc" {
+    dolt archive --older-than 2
+    archives=$(count_archives)
+    [ "$archives" -gt 0 ]
+
+    dolt sql -q "INSERT INTO t VALUES (5, 'version 5');"
+    dolt commit -am "version 5"
+    dolt gc
+    [ "$(count_archives)" -eq "$archives" ]
+
+    dolt gc --shallow
+    [ "$(count_archives)" -eq "$archives" ]
+
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~4'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "5" ]
+}
+
+@test "archive: recent history is not archived" {
+    run dolt archive --older-than 10
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Nothing to archive" ]] || false
+}
+
+@test "archive: --older-than must not be negative" {
+    run dolt archive --older-than -1
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "must not be negative" ]] || false
+}
+
+@test "archive: an archived repo can be cloned and fetched through remotesrv" {
+    skiponwindows "tests are flaky on Windows"
+    dolt archive --older-than 2
+
+    remotesrv --http-port 1234 --repo-mode &
+    remotesrv_pid=$!
+
+    mkdir clones && cd clones
+    dolt clone http://localhost:50051/test-org/test-repo cloned
+    cd cloned
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~3'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    cd ..
+    mkdir fetched && cd fetched
+    dolt init
+    dolt remote add origin http://localhost:50051/test-org/test-repo
+    dolt fetch
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'origin/main~3'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "archive: an archived repo can be pushed to through remotesrv" {
+    skiponwindows "tests are flaky on Windows"
+    dolt archive --older-than 2
+
+    remotesrv --http-port 1234 --repo-mode &
+    remotesrv_pid=$!
+
+    mkdir clones && cd clones
+    dolt clone http://localhost:50051/test-org/test-repo cloned
+    cd cloned
+    dolt sql -q "INSERT INTO t VALUES (5, 'version 5');"
+    dolt commit -am "version 5"
+    dolt push origin main
+
+    cd ..
+    dolt clone http://localhost:50051/test-org/test-repo cloned_again
+    cd cloned_again
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "5" ]
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~4'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "archive: an archived repo can be pushed to and cloned from a file remote" {
+    dolt archive --older-than 2
+    mkdir remote
+    dolt remote add origin file://./remote
+    dolt push origin main
+
+    mkdir clones && cd clones
+    dolt clone file://../remote pushed
+    cd pushed
+    run dolt sql -q "SELECT count(*) FROM t AS OF 'HEAD~3'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
@@ -816,3 +816,56 @@ SQL
     [[ "$output" =~ "8,8" ]] || false
 }
 
+
+@test "auto_increment: interleaved values from auto_increment_increment and auto_increment_offset" {
+    dolt sql <<SQL
+set @@auto_increment_increment = 10;
+set @@auto_increment_offset = 3;
+insert into test (c0) values (1), (2);
+insert into test values (25, 3);
+insert into test (c0) values (4);
+SQL
+
+    run dolt sql -q 'select * from test order by pk' -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "3,1" ]
+    [ "${lines[2]}" = "13,2" ]
+    [ "${lines[3]}" = "25,3" ]
+    [ "${lines[4]}" = "33,4" ]
+}
+
+@test "auto_increment: clones with different offsets merge without key collisions" {
+    dolt sql -q "insert into test (c0) values (0)"
+    dolt commit -Am "initial row"
+    dolt remote add origin file://./remote
+    dolt push origin main
+
+    dolt clone file://./remote clone1
+    dolt clone file://./remote clone2
+
+    cd clone1
+    dolt sql -q "set @@persist.auto_increment_increment = 2"
+    dolt sql -q "set @@persist.auto_increment_offset = 1"
+    dolt sql -q "insert into test (c0) values (1), (1)"
+    dolt commit -am "clone1 rows"
+    dolt push origin main
+
+    cd ../clone2
+    dolt sql -q "set @@persist.auto_increment_increment = 2"
+    dolt sql -q "set @@persist.auto_increment_offset = 2"
+    dolt sql -q "insert into test (c0) values (2), (2)"
+    dolt commit -am "clone2 rows"
+
+    run dolt pull origin main
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q 'select * from test order by pk' -r csv
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 6 ]
+    [ "${lines[1]}" = "1,0" ]
+    [ "${lines[2]}" = "2,2" ]
+    [ "${lines[3]}" = "3,1" ]
+    [ "${lines[4]}" = "4,2" ]
+    [ "${lines[5]}" = "5,1" ]
+}
@@ -251,3 +251,157 @@ teardown() {
     [ "${#lines[@]}" -eq 2 ]
     [[ "$output" =~ "t1" ]] || false
 }
+
+@test "backup: syncs after the first are incremental and recorded as generations" {
+    cd repo1
+    dolt backup add bac1 file://../bac1
+
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "backup 'bac1' generation 1: uploaded" ]] || false
+    [[ "$output" =~ "a full backup" ]] || false
+
+    dolt sql -q "insert into t1 values (1), (2)"
+    dolt commit -am "add rows"
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "backup 'bac1' generation 2: uploaded" ]] || false
+    [[ "$output" =~ "new chunks" ]] || false
+
+    run dolt backup sync bac1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "backup 'bac1' generation 3: no new chunks to upload" ]] || false
+
+    run dolt backup generations bac1
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "${lines[0]}" =~ ^3.*incremental.*"0 chunks" ]] || false
+    [[ "${lines[1]}" =~ ^2.*incremental ]] || false
+    [[ "${lines[2]}" =~ ^1.*full ]] || false
+
+    cd ..
+    dolt backup restore file://./bac1 repo2
+    cd repo2
+    run dolt sql -q "select count(*) from t1" -r csv
+    [ "${lines[1]}" = "2" ]
+
+    cd ../repo1
+    dolt backup remove bac1
+    dolt backup add bac1 file://../bac1
+    run dolt backup generations bac1
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 0 ]
+
+    run dolt backup generations bac2
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown backup: 'bac2'" ]] || false
+}
+
+@test "backup: restore as of a time" {
+    cd repo1
+    dolt sql -q "insert into t1 values (1)"
+    dolt commit -am "before"
+    sleep 1
+    asof=$(date -u +%Y-%m-%dT%H:%M:%S)
+    sleep 1
+    dolt sql -q "insert into t1 values (2)"
+    dolt commit -am "after"
+    dolt checkout -b later
+    dolt commit --allow-empty -m "on later"
+    dolt checkout main
+    dolt tag v2
+    dolt backup add bac1 file://../bac1
+    dolt backup sync bac1
+
+    cd ..
+    run dolt backup restore --as-of "$asof" file://./bac1 repo2
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "restored branch 'main' to" ]] || false
+    [[ "$output" =~ "restored branch 'feature' to" ]] || false
+    [[ "$output" =~ "removed branch 'later'" ]] || false
+
+    cd repo2
+    run dolt sql -q "select * from t1" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "1" ]
+    run dolt log --oneline -n 1
+    [[ "$output" =~ "before" ]] || false
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt branch
+    [ "${#lines[@]}" -eq 2 ]
+    [[ ! "$output" =~ "later" ]] || false
+    run dolt tag
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "v1" ]] || false
+
+    cd ..
+    run dolt backup restore --as-of "yesterday" file://./bac1 repo3
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --as-of" ]] || false
+}
+
+@test "backup: verify a backup" {
+    cd repo1
+    dolt sql -q "insert into t1 values (1), (2)"
+    dolt commit -am "add rows"
+    dolt backup add bac1 file://../bac1
+    dolt backup sync bac1
+
+    run dolt backup verify bac1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "root: " ]] || false
+    [[ "$output" =~ "backup 'bac1' verified" ]] || false
+    [[ ! "$output" =~ "note:" ]] || false
+
+    run dolt backup verify file://../bac1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "backup 'file://../bac1' verified" ]] || false
+
+    run dolt sql -q "call dolt_backup('verify', 'bac1')"
+    [ "$status" -eq 0 ]
+
+    for f in $(ls ../bac1 | grep -v -e manifest -e LOCK -e oldgen); do
+        : > ../bac1/$f
+    done
+    run dolt backup verify bac1
+    [ "$status" -ne 0 ]
+
+    run dolt backup verify bac2
+    [ "$status" -eq 1 ]
+}
+
+@test "backup: encrypt a file backup" {
+    head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n' > backup.key
+    cd repo1
+    dolt sql -q "create table t2 (c varchar(100))"
+    dolt sql -q "insert into t2 values ('plaintext-canary-value-1234567890')"
+    dolt add t2
+    dolt commit -m "add canary"
+
+    run dolt backup add --encryption-key-file ../backup.key bac2 https://doltremoteapi.dolthub.com/org/repo
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "only valid for file urls" ]] || false
+
+    dolt backup add --encryption-key-file ../backup.key bac1 file://../bac1
+    dolt backup sync bac1
+    run grep -r "plaintext-canary-value" ../bac1
+    [ "$status" -ne 0 ]
+
+    run dolt backup verify bac1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "backup 'bac1' verified" ]] || false
+
+    cd ..
+    run dolt backup restore file://./bac1 repo2
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "encrypted" ]] || false
+
+    dolt backup restore --encryption-key-file backup.key file://./bac1 repo3
+    cd repo3
+    run dolt sql -q "select c from t2" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "plaintext-canary-value-1234567890" ]] || false
+}
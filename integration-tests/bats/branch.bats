@@ -218,3 +218,98 @@ teardown() {
     [ "$status" -ne 0 ]
     [[ "$output" =~ "--remote/-r can only be supplied when listing or deleting branches, not when creating branches" ]] || false
 }
+
+@test "branch: --format json" {
+    dolt branch other
+
+    run dolt branch --format json
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ '{"branches":[{"name":"main","hash":"' ]] || false
+    [[ "$output" =~ '"remote":false,"current":true},{"name":"other","hash":"' ]] || false
+    [[ "$output" =~ '"remote":false,"current":false}]}' ]] || false
+
+    run dolt branch --list other --format json
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ '"name":"main"' ]] || false
+    [[ "$output" =~ '"name":"other"' ]] || false
+
+    run dolt branch --format json newbranch
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "only supported when listing branches" ]] || false
+}
+
+@test "branch: --as-of creates a branch for every branch as of a time" {
+    dolt sql -q "create table t (c0 int)"
+    dolt commit -Am "before"
+    dolt branch other
+    sleep 1
+    asof=$(date -u +%Y-%m-%dT%H:%M:%S)
+    sleep 1
+    dolt sql -q "insert into t values (1)"
+    dolt commit -am "after"
+    dolt branch later
+
+    run dolt branch --as-of "$asof" --prefix dr
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "created branch 'dr/main' at" ]] || false
+    [[ "$output" =~ "created branch 'dr/other' at" ]] || false
+    [[ ! "$output" =~ "dr/later" ]] || false
+
+    dolt checkout dr/main
+    run dolt sql -q "select count(*) from t" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+    dolt checkout main
+    run dolt sql -q "select count(*) from t" -r csv
+    [ "${lines[1]}" = "1" ]
+
+    run dolt branch --as-of "$asof" --prefix dr
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "A branch named 'dr/main' already exists" ]] || false
+
+    run dolt branch --as-of "$asof"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "created branch 'as-of-" ]] || false
+
+    run dolt branch --as-of "$asof" -d other
+    [ "$status" -eq 1 ]
+}
+
+@test "branch: branch name policy is enforced when creating, copying and renaming branches" {
+    dolt config --local --add branch.name.prefixes "feature/,fix/"
+    dolt config --local --add branch.name.deny ".*wip.*"
+
+    dolt branch feature/one
+    run dolt branch other
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch name 'other' is not allowed" ]] || false
+    run dolt branch feature/wip
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch name 'feature/wip' is not allowed" ]] || false
+
+    run dolt branch -c feature/one other
+    [ "$status" -ne 0 ]
+    dolt branch -c feature/one fix/two
+
+    run dolt branch -m fix/two two
+    [ "$status" -ne 0 ]
+    dolt branch -m fix/two fix/three
+
+    run dolt checkout -b other
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch name 'other' is not allowed" ]] || false
+    run dolt sql -q "call dolt_checkout('-b', 'other')"
+    [ "$status" -ne 0 ]
+    dolt sql -q "call dolt_branch('fix/four')"
+
+    run dolt branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "feature/one" ]] || false
+    [[ "$output" =~ "fix/three" ]] || false
+    [[ "$output" =~ "fix/four" ]] || false
+    [[ ! "$output" =~ "other" ]] || false
+
+    dolt config --local --unset branch.name.prefixes
+    dolt branch other
+}
@@ -0,0 +1,117 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test (pk int primary key);"
+    dolt add -A && dolt commit -m "commit A"
+
+    dolt sql -q "INSERT INTO test VALUES (0);"
+    dolt commit -am "commit B"
+    dolt branch other
+
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -am "commit C"
+
+    dolt checkout other
+    dolt sql -q "INSERT INTO test VALUES (2);"
+    dolt commit -am "commit D"
+    dolt sql -q "INSERT INTO test VALUES (3);"
+    dolt commit -am "commit E"
+    dolt checkout main
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "commit-graph: write, status and remove" {
+    run dolt commit-graph status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no commit-graph file" ]] || false
+
+    run dolt commit-graph write
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "wrote 6 commits to the commit-graph, 6 new" ]] || false
+    [ -f .dolt/commit-graph ]
+
+    dolt sql -q "INSERT INTO test VALUES (4);"
+    dolt commit -am "commit F"
+    run dolt commit-graph write
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "wrote 7 commits to the commit-graph, 1 new" ]] || false
+
+    run dolt commit-graph status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "7 commits in the commit-graph" ]] || false
+
+    run dolt commit-graph write --rewrite
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "wrote 7 commits to the commit-graph, 7 new" ]] || false
+
+    dolt commit-graph remove
+    [ ! -f .dolt/commit-graph ]
+}
+
+@test "commit-graph: queries give the same results with and without a commit-graph" {
+    base=$(dolt merge-base main other)
+    log=$(dolt log --oneline other..main)
+    counts=$(dolt sql -r csv -q "call dolt_count_commits('--from', 'main', '--to', 'other')")
+
+    dolt commit-graph write
+
+    run dolt merge-base main other
+    [ "$status" -eq 0 ]
+    [ "$output" = "$base" ]
+
+    run dolt log --oneline other..main
+    [ "$status" -eq 0 ]
+    [ "$output" = "$log" ]
+    [[ "$output" =~ "commit C" ]] || false
+    [[ ! "$output" =~ "commit B" ]] || false
+
+    run dolt sql -r csv -q "call dolt_count_commits('--from', 'main', '--to', 'other')"
+    [ "$status" -eq 0 ]
+    [ "$output" = "$counts" ]
+    [[ "$output" =~ "1,2" ]] || false
+
+    # commits made after the commit-graph was written are read from the database
+    dolt checkout other
+    dolt sql -q "INSERT INTO test VALUES (5);"
+    dolt commit -am "commit G"
+    dolt checkout main
+    dolt merge other -m "merge other"
+
+    head=$(dolt sql -r csv -q "select hashof('other')" | tail -n 1)
+    run dolt merge-base main other
+    [ "$status" -eq 0 ]
+    [ "$output" = "$head" ]
+
+    run dolt sql -r csv -q "call dolt_count_commits('--from', 'main', '--to', 'other')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2,0" ]] || false
+}
+
+@test "commit-graph: gc rewrites the commit-graph" {
+    dolt commit-graph write
+    dolt branch -D other
+    dolt gc
+
+    run dolt commit-graph status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "4 commits in the commit-graph" ]] || false
+}
+
+@test "commit-graph: a corrupt commit-graph file is ignored" {
+    base=$(dolt merge-base main other)
+    echo "not a commit-graph" > .dolt/commit-graph
+
+    run dolt merge-base main other
+    [ "$status" -eq 0 ]
+    [ "$output" = "$base" ]
+
+    run dolt commit-graph status
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "commit-graph file is corrupt" ]] || false
+}
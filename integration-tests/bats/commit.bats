@@ -200,3 +200,27 @@ SQL
     [[ "$output" =~ "adding table t2 on branch2" ]] || false
     [[ ! "$output" =~ "adding table t1 on branch1" ]] || false
 }
+
+@test "commit: --metadata attaches key-value metadata that is kept through push and clone" {
+    dolt sql -q "CREATE table t (pk int primary key);"
+    dolt add t
+    dolt commit -m "add table t" --metadata '{"ticket": "ABC-123", "pipeline_run": "https://ci.example.com/runs/42"}'
+
+    run dolt sql -r csv -q "select json_unquote(json_extract(metadata, '$.ticket')) as ticket from dolt_log limit 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "ABC-123" ]] || false
+
+    run dolt commit --allow-empty -m "bad metadata" --metadata 'ABC-123'
+    [ $status -ne 0 ]
+    [[ "$output" =~ "commit metadata must be a JSON object" ]] || false
+
+    mkdir ../metadata-remote
+    dolt remote add origin file://../metadata-remote
+    dolt push origin main
+    cd ..
+    dolt clone file://./metadata-remote metadata-clone
+    cd metadata-clone
+    run dolt sql -r csv -q "select json_unquote(json_extract(metadata, '$.pipeline_run')) as run from dolt_log limit 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "https://ci.example.com/runs/42" ]] || false
+}
@@ -246,6 +246,64 @@ SQL
     [ "$status" -eq 1 ]
 }
 
+@test "create-views: status shows views with missing dependencies" {
+    dolt sql <<SQL
+create table my_users (id int primary key, name varchar(20));
+create view names as select name from my_users;
+create view ids as select id from my_users;
+SQL
+    dolt commit -Am "add views"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "broken view" ]] || false
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    dolt sql -q "alter table my_users rename column name to full_name"
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Views with missing dependencies:" ]] || false
+    [[ "$output" =~ "broken view:      names" ]] || false
+    [[ ! "$output" =~ "broken view:      ids" ]] || false
+
+    dolt sql -q "drop table my_users"
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "broken view:      names" ]] || false
+    [[ "$output" =~ "broken view:      ids" ]] || false
+    [[ "$output" =~ "table not found: my_users" ]] || false
+}
+
+@test "create-views: merge shows views broken by changes on the other branch" {
+    dolt sql <<SQL
+create table my_users (id int primary key, name varchar(20));
+insert into my_users values (1, 'ann');
+SQL
+    dolt commit -Am "add my_users"
+
+    dolt checkout -b other
+    dolt sql -q "alter table my_users drop column name"
+    dolt commit -am "drop name"
+
+    dolt checkout main
+    dolt sql -q "create view names as select name from my_users"
+    dolt commit -Am "add view"
+
+    run dolt merge other -m "merge other"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Views with missing dependencies:" ]] || false
+    [[ "$output" =~ "broken view:      names" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "broken view:      names" ]] || false
+
+    dolt sql -q "drop view names"
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "broken view" ]] || false
+}
+
 @test "create-views: creating view creates creates dolt_schemas table" {
     run dolt sql -q 'create view testing as select 2+2 from dual'
     [ "$status" -eq 0 ]
@@ -127,3 +127,30 @@ teardown() {
     dolt creds import `batshelper known-good.jwk`
     dolt creds ls -v | grep '*' | grep "$pubkey"
 }
+
+@test "creds: dolt_login creates new credentials pending association" {
+    run dolt sql -r csv -q "call dolt_login()"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "status,key_id,public_key,login_url,username,email" ]] || false
+    [[ "$output" =~ "pending," ]] || false
+    pubkey=`echo "${lines[1]}" | awk -F, '{print $3}'`
+    [[ "$output" =~ "#$pubkey" ]] || false
+
+    run dolt creds ls -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "$pubkey" ]] || false
+}
+
+@test "creds: dolt_login fails with unknown credentials" {
+    run dolt sql -q "call dolt_login('abcdefghijklmnopqrstuvwxyz')"
+    [ "$status" -eq 1 ]
+}
+
+@test "creds: dolt_login fails when the auth endpoint is unreachable" {
+    run dolt sql -r csv -q "call dolt_login()"
+    [ "$status" -eq 0 ]
+    kid=`echo "${lines[1]}" | awk -F, '{print $2}'`
+    run dolt sql -q "call dolt_login('--auth-endpoint', 'localhost:1', '--insecure', '$kid')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unable to connect" ]] || false
+}
@@ -0,0 +1,72 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test (pk int primary key);"
+    dolt add -A && dolt commit -m "commit A"
+    dolt tag v1.0.0
+
+    dolt sql -q "INSERT INTO test VALUES (0);"
+    dolt commit -am "commit B"
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -am "commit C"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "describe: tagged commit" {
+    run dolt describe HEAD~2
+    [ "$status" -eq 0 ]
+    [ "$output" = "v1.0.0" ]
+
+    HASH=$(dolt sql -q "select hashof('HEAD~2')" -r csv | tail -n 1)
+    run dolt describe --long HEAD~2
+    [ "$status" -eq 0 ]
+    [ "$output" = "v1.0.0-0-g${HASH:0:7}" ]
+}
+
+@test "describe: commits since nearest tag" {
+    HASH=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    run dolt describe
+    [ "$status" -eq 0 ]
+    [ "$output" = "v1.0.0-2-g${HASH:0:7}" ]
+
+    run dolt describe --abbrev 12
+    [ "$status" -eq 0 ]
+    [ "$output" = "v1.0.0-2-g${HASH:0:12}" ]
+
+    dolt tag v1.1.0 HEAD~1
+    run dolt describe
+    [ "$status" -eq 0 ]
+    [ "$output" = "v1.1.0-1-g${HASH:0:7}" ]
+}
+
+@test "describe: merge commits count both parents" {
+    dolt checkout -b other HEAD~1
+    dolt sql -q "INSERT INTO test VALUES (2);"
+    dolt commit -am "commit D"
+    dolt checkout main
+    dolt merge other -m "merge other"
+
+    HASH=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    run dolt describe
+    [ "$status" -eq 0 ]
+    [ "$output" = "v1.0.0-4-g${HASH:0:7}" ]
+}
+
+@test "describe: no reachable tag" {
+    dolt tag -d v1.0.0
+    run dolt describe
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no tags can describe" ]] || false
+
+    HASH=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    run dolt describe --always
+    [ "$status" -eq 0 ]
+    [ "$output" = "${HASH:0:7}" ]
+}
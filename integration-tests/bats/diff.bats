@@ -1622,6 +1622,50 @@ SQL
 
 }
 
+@test "diff: dolt_procedures table changes are special" {
+    dolt sql <<SQL
+CREATE TABLE people (name varchar(255), age int);
+CREATE PROCEDURE count_people() SELECT count(*) FROM people;
+SQL
+    dolt add .
+    dolt commit -m "commit 1"
+
+    dolt sql <<SQL
+DROP PROCEDURE count_people;
+CREATE PROCEDURE count_people() SELECT count(*) FROM people WHERE age >= 18;
+CREATE PROCEDURE oldest() SELECT max(age) FROM people;
+SQL
+    dolt add .
+    dolt commit -m "commit 2"
+
+    run dolt diff HEAD~1 HEAD
+    [ $status -eq 0 ]
+    [[ "$output" =~ "-CREATE PROCEDURE count_people() SELECT count(*) FROM people;"                ]] || false
+    [[ "$output" =~ "+CREATE PROCEDURE count_people() SELECT count(*) FROM people WHERE age >= 18;" ]] || false
+    [[ "$output" =~ "+CREATE PROCEDURE oldest() SELECT max(age) FROM people;"                     ]] || false
+    [[ ! "$output" =~ "created_at" ]] || false
+
+    run dolt diff -r sql HEAD~1 HEAD
+    [ $status -eq 0 ]
+    [[ "$output" =~ "DROP PROCEDURE \`count_people\`;" ]] || false
+    [[ "$output" =~ "CREATE PROCEDURE count_people() SELECT count(*) FROM people WHERE age >= 18;" ]] || false
+    [[ "$output" =~ "CREATE PROCEDURE oldest() SELECT max(age) FROM people;" ]] || false
+
+    run dolt diff -r json HEAD~1 HEAD
+    [ $status -eq 0 ]
+    [[ "$output" =~ '"procedures":[{"name":"count_people","from_definition":"CREATE PROCEDURE count_people() SELECT count(*) FROM people;"' ]] || false
+    [[ "$output" =~ '{"name":"oldest","from_definition":"","to_definition":"CREATE PROCEDURE oldest() SELECT max(age) FROM people;"}]}' ]] || false
+
+    # recreating a procedure without changing it is not a diff
+    dolt sql <<SQL
+DROP PROCEDURE oldest;
+CREATE PROCEDURE oldest() SELECT max(age) FROM people;
+SQL
+    run dolt diff
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "oldest" ]] || false
+}
+
 @test "diff: get diff on dolt_schemas table with different result output formats" {
     dolt add .
     dolt commit -am "commit 1"
@@ -1663,3 +1707,64 @@ SQL
     [[ "$output" =~ "CREATE TRIGGER trigger1 BEFORE INSERT ON mytable FOR EACH ROW SET new.v1 = -2*new.v1;" ]] || false
     [[ "$output" =~ "CREATE VIEW view1 AS SELECT v1 FROM mytable;" ]] || false
 }
+
+@test "diff: diff a table between two databases" {
+    mkdir -p "$BATS_TMPDIR/dbs-$$/staging" "$BATS_TMPDIR/dbs-$$/prod"
+
+    cd "$BATS_TMPDIR/dbs-$$/staging"
+    dolt init
+    dolt sql -q "create table t (pk int primary key, c1 varchar(20)); insert into t values (1, 'one'), (2, 'two'), (3, 'three');"
+    dolt commit -Am "staging data"
+
+    cd ../prod
+    dolt init
+    dolt sql -q "create table t (pk int primary key, c1 varchar(20)); insert into t values (1, 'one'), (2, 'TWO');"
+    dolt commit -Am "prod data"
+
+    run dolt diff staging/main prod/main t
+    [ $status -eq 0 ]
+    [[ "$output" =~ "| < | 2  | two   |" ]] || false
+    [[ "$output" =~ "| > | 2  | TWO   |" ]] || false
+    [[ "$output" =~ "| - | 3  | three |" ]] || false
+    [[ ! "$output" =~ "one" ]] || false
+
+    run dolt diff staging/main main --stat
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1 Row Modified" ]] || false
+    [[ "$output" =~ "1 Row Deleted" ]] || false
+
+    run dolt diff nonexistent/main main
+    [ $status -ne 0 ]
+
+    cd ../..
+    rm -rf "$BATS_TMPDIR/dbs-$$"
+}
+
+@test "diff: --min-key and --max-key limit data diffs to a range of primary keys" {
+    dolt sql -q "create table orders (customer_id int, order_id int, total int, primary key (customer_id, order_id));"
+    dolt sql -q "insert into orders values (1, 1, 10), (1, 2, 20), (42, 1, 30), (42, 2, 40), (99, 1, 50);"
+    dolt add .
+    dolt commit -m "adding orders"
+    dolt sql -q "update orders set total = total + 1;"
+    dolt sql -q "insert into orders values (42, 3, 60);"
+    dolt commit -am "updating orders"
+
+    run dolt diff HEAD~ HEAD orders --min-key 42 --max-key 42
+    [ $status -eq 0 ]
+    [[ "$output" =~ "| < | 42          | 1        | 30    |" ]] || false
+    [[ "$output" =~ "| > | 42          | 1        | 31    |" ]] || false
+    [[ "$output" =~ "| + | 42          | 3        | 60    |" ]] || false
+    [[ ! "$output" =~ "| 1           |" ]] || false
+    [[ ! "$output" =~ "| 99          |" ]] || false
+
+    run dolt diff HEAD~ HEAD orders --min-key 50
+    [ $status -eq 0 ]
+    [[ "$output" =~ "| 99          | 1        | 51    |" ]] || false
+    [[ ! "$output" =~ "| 42          |" ]] || false
+
+    run dolt diff HEAD~ HEAD orders --max-key 1 -r sql
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "${lines[0]}" =~ "UPDATE" ]] || false
+    [[ "${lines[1]}" =~ "UPDATE" ]] || false
+}
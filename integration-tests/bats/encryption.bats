@@ -0,0 +1,41 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_no_dolt_init
+    head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n' > "$BATS_TMPDIR/key-$$"
+    export DOLT_ENCRYPTION_KEY_FILE="$BATS_TMPDIR/key-$$"
+}
+
+teardown() {
+    unset DOLT_ENCRYPTION_KEY_FILE
+    rm -f "$BATS_TMPDIR/key-$$"
+    teardown_common
+}
+
+@test "encryption: chunk files are encrypted at rest" {
+    dolt init
+    dolt sql -q "create table t (pk int primary key, c varchar(100));"
+    dolt sql -q "insert into t values (1, 'plaintext-canary-value-1234567890');"
+    dolt add -A && dolt commit -m "add canary"
+
+    run grep -r "plaintext-canary-value" .dolt/noms
+    [ "$status" -ne 0 ]
+
+    run dolt sql -q "select c from t where pk = 1;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "plaintext-canary-value-1234567890" ]] || false
+
+    dolt gc
+    run grep -r "plaintext-canary-value" .dolt/noms
+    [ "$status" -ne 0 ]
+
+    run dolt sql -q "select c from t where pk = 1;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "plaintext-canary-value-1234567890" ]] || false
+
+    unset DOLT_ENCRYPTION_KEY_FILE
+    run dolt log
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "encrypted" ]] || false
+}
@@ -513,3 +513,84 @@ SQL
     run dolt sql -q "SELECT * FROM i"
     [ "$output" = "$int_output" ]
 }
+
+@test "export-tables: table export to jsonl with a flatten file" {
+    dolt sql -q "CREATE TABLE clicks (id int primary key, user_name varchar(20), first_tag varchar(10), extra json)"
+    cat <<JSON > clicks.jsonl
+{"id": 1, "user": {"name": "tim", "age": 40}, "tags": ["a", "b"]}
+{"id": 2, "user": {"name": "brian"}, "kind": "click"}
+JSON
+    cat <<JSON > flatten.json
+{"columns": {"first_tag": "\$.tags[0]", "user_name": "\$.user.name"}, "remainder": "extra"}
+JSON
+    dolt table import -u --flatten flatten.json clicks clicks.jsonl
+
+    run dolt table export --flatten flatten.json clicks export.jsonl
+    [ "$status" -eq 0 ]
+    run cat export.jsonl
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[0]}" = '{"id":1,"tags":["a","b"],"user":{"age":40,"name":"tim"}}' ]
+    [ "${lines[1]}" = '{"id":2,"kind":"click","user":{"name":"brian"}}' ]
+
+    run dolt table export --file-type jsonl clicks
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '{"extra":{"kind":"click"},"id":2,"user_name":"brian"}' ]] || false
+
+    dolt sql -q "DELETE FROM clicks"
+    dolt table import -u --flatten flatten.json clicks export.jsonl
+    run dolt sql -q "SELECT id, user_name, first_tag FROM clicks ORDER BY id" -r csv
+    [ "${lines[1]}" = "1,tim,a" ]
+    [ "${lines[2]}" = "2,brian," ]
+
+    run dolt table export --flatten flatten.json clicks clicks.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--flatten is only supported for jsonl files" ]] || false
+}
+
+@test "export-tables: sql query results as jsonl" {
+    dolt sql -q "INSERT INTO test_int VALUES (1, 2, 3, 4, 5, 6), (2, NULL, 3, 4, 5, 6)"
+    run dolt sql -r jsonl -q "SELECT pk, c1 FROM test_int ORDER BY pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = '{"c1":2,"pk":1}' ]
+    [ "${lines[1]}" = '{"pk":2}' ]
+}
+
+@test "export-tables: export spatial columns as wkt, wkb and geojson" {
+    dolt sql -q "CREATE TABLE places (pk int primary key, name varchar(20), geom geometry)"
+    dolt sql -q "INSERT INTO places VALUES (1, 'a', point(1, 2)), (2, 'b', st_srid(point(1, 2), 4326)), (3, NULL, NULL)"
+
+    run dolt table export --spatial-format wkt places places.csv
+    [ "$status" -eq 0 ]
+    run cat places.csv
+    [ "${lines[1]}" = "1,a,POINT(1 2)" ]
+    [ "${lines[2]}" = "2,b,SRID=4326;POINT(1 2)" ]
+    [ "${lines[3]}" = "3,," ]
+
+    run dolt table export -f --spatial-format wkb places places.csv
+    [ "$status" -eq 0 ]
+    run cat places.csv
+    [ "${lines[1]}" = "1,a,0101000000000000000000F03F0000000000000040" ]
+    [ "${lines[2]}" = "2,b,0101000020E6100000000000000000F03F0000000000000040" ]
+
+    run dolt table export places places.geojson
+    [ "$status" -eq 0 ]
+    run cat places.geojson
+    [[ "$output" =~ '{"geometry":{"coordinates":[1,2],"type":"Point"},"properties":{"name":"a","pk":1},"type":"Feature"}' ]] || false
+    [[ "$output" =~ '"crs":{"properties":{"name":"EPSG:4326"},"type":"name"}' ]] || false
+
+    dolt sql -q "CREATE TABLE places2 LIKE places"
+    run dolt table import -u places2 places.geojson
+    [ "$status" -eq 0 ]
+    run dolt sql -q "SELECT pk, name, st_aswkt(geom), st_srid(geom) FROM places2 ORDER BY pk" -r csv
+    [ "${lines[1]}" = "1,a,POINT(1 2),0" ]
+    [ "${lines[2]}" = "2,b,POINT(2 1),4326" ]
+    [ "${lines[3]}" = "3,,," ]
+
+    run dolt table export --spatial-format wkt places places.json
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--spatial-format is only supported for csv and psv files" ]] || false
+
+    run dolt table export test_int test_int.geojson
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "GeoJSON files can only be used with tables that have a spatial column" ]] || false
+}
@@ -0,0 +1,148 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+load $BATS_TEST_DIRNAME/helper/query-server-common.bash
+
+setup() {
+    setup_common
+    dolt config --global --add external_table.schemes file,s3
+
+    cat <<CSV > "$BATS_TMPDIR/prices.csv"
+sku,price
+a1,10
+b2,25
+CSV
+    dolt sql -q "CREATE TABLE products (sku varchar(10) primary key, name varchar(20))"
+    dolt sql -q "INSERT INTO products VALUES ('a1', 'apple'), ('b2', 'banana'), ('c3', 'cherry')"
+}
+
+teardown() {
+    assert_feature_version
+    stop_sql_server
+    rm -f "$BATS_TMPDIR/prices.csv"
+    teardown_common
+}
+
+@test "external-tables: query a csv file as a table" {
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('prices', '$BATS_TMPDIR/prices.csv', NULL, NULL)"
+
+    run dolt sql -q "SELECT * FROM prices ORDER BY sku" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "sku,price" ]
+    [ "${lines[1]}" = "a1,10" ]
+    [ "${lines[2]}" = "b2,25" ]
+
+    run dolt sql -q "SELECT p.name, e.price FROM products p JOIN prices e ON p.sku = e.sku ORDER BY p.sku" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "apple,10" ]
+    [ "${lines[2]}" = "banana,25" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    # external tables aren't listed with the tables of the database
+    run dolt sql -q "SHOW TABLES"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "prices" ]] || false
+}
+
+@test "external-tables: declared columns are converted to their types" {
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('prices', 'file://$BATS_TMPDIR/prices.csv', 'csv', 'sku varchar(10), price decimal(10,2), discount int')"
+
+    run dolt sql -q "SELECT sku, price * 2, discount FROM prices ORDER BY sku" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "a1,20.00," ]
+    [ "${lines[2]}" = "b2,50.00," ]
+
+    run dolt sql -q "INSERT INTO prices VALUES ('c3', 5, NULL)"
+    [ "$status" -eq 1 ]
+}
+
+@test "external-tables: snapshot an external table into history" {
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('prices', '$BATS_TMPDIR/prices.csv', NULL, 'sku varchar(10), price int')"
+    dolt sql -q "CREATE TABLE price_history (sku varchar(10) primary key, price int)"
+    dolt sql -q "INSERT INTO price_history SELECT * FROM prices"
+    dolt add -A
+    dolt commit -m "snapshot prices"
+
+    printf 'sku,price\na1,12\nb2,25\n' > "$BATS_TMPDIR/prices.csv"
+    dolt sql -q "REPLACE INTO price_history SELECT * FROM prices"
+
+    run dolt sql -q "SELECT price FROM price_history AS OF 'HEAD' WHERE sku = 'a1'" -r csv
+    [ "${lines[1]}" = "10" ]
+    run dolt sql -q "SELECT price FROM price_history WHERE sku = 'a1'" -r csv
+    [ "${lines[1]}" = "12" ]
+
+    # the definition is versioned with the database
+    run dolt sql -q "SELECT table_name FROM dolt_external_tables AS OF 'HEAD'" -r csv
+    [ "${lines[1]}" = "prices" ]
+}
+
+@test "external-tables: tables of the database take precedence over external tables" {
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('products', '$BATS_TMPDIR/prices.csv', NULL, NULL)"
+
+    run dolt sql -q "SELECT count(*) FROM products" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+}
+
+@test "external-tables: errors reading external tables" {
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('missing', '$BATS_TMPDIR/missing.csv', NULL, NULL)"
+    run dolt sql -q "SELECT * FROM missing"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "missing.csv" ]] || false
+
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('events', 's3://bucket/events.json', NULL, NULL)"
+    run dolt sql -q "SELECT * FROM events"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "external table events has unsupported format 'json', expected csv or parquet" ]] || false
+
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('bad', '$BATS_TMPDIR/prices.csv', NULL, 'sku varchar(10), price int')"
+    printf 'sku,price\na1,ten\n' > "$BATS_TMPDIR/prices.csv"
+    run dolt sql -q "SELECT * FROM bad"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "external table bad: row 1: column price" ]] || false
+}
+
+@test "external-tables: only the schemes enabled in the config are allowed" {
+    dolt config --global --add external_table.schemes s3
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('prices', '$BATS_TMPDIR/prices.csv', NULL, NULL)"
+    run dolt sql -q "SELECT * FROM prices"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the file scheme is not enabled in external_table.schemes" ]] || false
+
+    dolt config --global --unset external_table.schemes
+    run dolt sql -q "SELECT * FROM prices"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "the file scheme is not enabled in external_table.schemes" ]] || false
+
+    dolt config --global --add external_table.schemes s3,file
+    run dolt sql -q "SELECT * FROM prices ORDER BY sku" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "a1,10" ]
+}
+
+@test "external-tables: reading external tables requires the FILE privilege" {
+    dolt sql -q "INSERT INTO dolt_external_tables VALUES ('prices', '$BATS_TMPDIR/prices.csv', NULL, NULL)"
+    start_sql_server
+    dolt sql-client -P $PORT -u dolt --use-db "dolt_repo_$$" -q "CREATE USER user1@'%'"
+    dolt sql-client -P $PORT -u dolt --use-db "dolt_repo_$$" -q "GRANT SELECT ON *.* TO user1@'%'"
+
+    run dolt sql-client -P $PORT -u user1 --use-db "dolt_repo_$$" -q "SELECT * FROM prices"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "command denied to user 'user1'" ]] || false
+
+    # tables of the database don't need it
+    run dolt sql-client -P $PORT -u user1 --use-db "dolt_repo_$$" -q "SELECT * FROM products"
+    [ "$status" -eq 0 ]
+
+    dolt sql-client -P $PORT -u dolt --use-db "dolt_repo_$$" -q "GRANT FILE ON *.* TO user1@'%'"
+    run dolt sql-client -P $PORT -u user1 --use-db "dolt_repo_$$" --result-format csv -q "SELECT * FROM prices ORDER BY sku"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "a1,10" ]] || false
+}
+
+@test "external-tables: CREATE EXTERNAL TABLE is not parsed yet" {
+    # the parser in go-mysql-server doesn't support CREATE EXTERNAL TABLE, so external tables are defined with
+    # dolt_external_tables instead
+    run dolt sql -q "CREATE EXTERNAL TABLE prices (sku varchar(10), price int) LOCATION '$BATS_TMPDIR/prices.csv'"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "syntax error" ]] || false
+}
@@ -0,0 +1,120 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE parent (
+  pk int NOT NULL PRIMARY KEY,
+  c0 varchar(20)
+);
+CREATE TABLE child (
+  pk int NOT NULL PRIMARY KEY,
+  parent_pk int,
+  FOREIGN KEY (parent_pk) REFERENCES parent (pk)
+);
+INSERT INTO parent VALUES (1,'one');
+INSERT INTO child VALUES (10,1);
+SQL
+    dolt add -A
+    dolt commit -m "added tables
+with a two line message"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "fast-export: round trip a branch with a merge into another database" {
+    dolt branch other
+    dolt sql -q "INSERT INTO parent VALUES (2,'two');"
+    dolt commit -am "added a row on main"
+    dolt checkout other
+    dolt sql -q "ALTER TABLE parent ADD COLUMN c1 int; UPDATE parent SET c1 = 5;"
+    dolt commit -am "added a column on other"
+    dolt checkout main
+    dolt merge other -m "merged other"
+
+    run dolt fast-export --out stream.txt
+    [ "$status" -eq 0 ]
+    run head -n 1 stream.txt
+    [ "$output" = "dolt-fast-export 1" ]
+    run tail -n 1 stream.txt
+    [ "$output" = "done" ]
+
+    mkdir other_db && cd other_db
+    dolt init
+    run dolt fast-import imported ../stream.txt
+    [ "$status" -eq 0 ]
+
+    dolt checkout imported
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Merge:" ]] || false
+    [[ "$output" =~ "merged other" ]] || false
+    [[ "$output" =~ "added a column on other" ]] || false
+    [[ "$output" =~ "added a row on main" ]] || false
+    [[ "$output" =~ "with a two line message" ]] || false
+
+    run dolt sql -q "SELECT * FROM parent ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,one,5" ]] || false
+    [[ "$output" =~ "2,two," ]] || false
+
+    run dolt sql -q "SELECT * FROM child" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "10,1" ]] || false
+
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    # the imported commits have the same dates as the exported commits
+    run dolt sql -q "SELECT date FROM dolt_log LIMIT 1" -r csv
+    imported_date="${lines[1]}"
+    cd ..
+    run dolt sql -q "SELECT date FROM dolt_log LIMIT 1" -r csv
+    [ "${lines[1]}" = "$imported_date" ]
+}
+
+@test "fast-export: export a branch other than the current branch" {
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO parent VALUES (2,'two');"
+    dolt commit -am "added a row on other"
+    dolt checkout main
+
+    dolt fast-export other > stream.txt
+    run grep -c "^commit refs/heads/other$" stream.txt
+    [ "$output" -eq 3 ]
+
+    run dolt fast-export missing
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "branch 'missing' not found" ]] || false
+}
+
+@test "fast-export: fast-import into an existing branch" {
+    dolt fast-export > stream.txt
+
+    dolt branch existing
+    run dolt fast-import existing < stream.txt
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "already exists" ]] || false
+
+    run dolt fast-import --force existing < stream.txt
+    [ "$status" -eq 0 ]
+
+    run dolt fast-import --force main < stream.txt
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "can't replace the current branch" ]] || false
+}
+
+@test "fast-export: fast-import rejects an invalid stream" {
+    echo "not a stream" > stream.txt
+    run dolt fast-import imported stream.txt
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "invalid fast-export stream" ]] || false
+
+    run dolt branch
+    [[ ! "$output" =~ "imported" ]] || false
+}
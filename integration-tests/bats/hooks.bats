@@ -0,0 +1,189 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE test (pk int primary key);"
+    dolt add -A && dolt commit -m "initial"
+    mkdir -p .dolt/hooks
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+write_hook() {
+    printf '#!/bin/sh\n%s\n' "$2" > ".dolt/hooks/$1"
+    chmod +x ".dolt/hooks/$1"
+}
+
+@test "hooks: failing pre-commit hook aborts the commit" {
+    write_hook pre-commit 'echo "validation failed"; exit 1'
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt commit -am "should fail"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "validation failed" ]] || false
+    [[ "$output" =~ "the 'pre-commit' hook exited with status 1" ]] || false
+
+    run dolt log -n 1
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "should fail" ]] || false
+}
+
+@test "hooks: passing pre-commit hook allows the commit" {
+    write_hook pre-commit 'echo "validation passed"'
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt commit -am "should succeed"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "validation passed" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "should succeed" ]] || false
+}
+
+@test "hooks: --no-verify skips the pre-commit hook" {
+    write_hook pre-commit 'exit 1'
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt commit --no-verify -am "skipped hook"
+    [ "$status" -eq 0 ]
+
+    run dolt log -n 1
+    [[ "$output" =~ "skipped hook" ]] || false
+}
+
+@test "hooks: hooks run from the repository root" {
+    write_hook pre-commit 'echo "cwd: $(pwd)"'
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt commit -am "commit"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "cwd: $(pwd)" ]] || false
+}
+
+@test "hooks: non-executable hooks are ignored" {
+    printf '#!/bin/sh\nexit 1\n' > .dolt/hooks/pre-commit
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt commit -am "commit"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "hook was ignored because it's not set as executable" ]] || false
+}
+
+@test "hooks: failing post-commit hook doesn't fail the commit" {
+    write_hook post-commit 'echo "post-commit ran"; exit 1'
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt commit -am "commit"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "post-commit ran" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "commit" ]] || false
+}
+
+@test "hooks: post-checkout hook receives the previous and new HEAD" {
+    write_hook post-checkout 'echo "post-checkout $1 $2 $3"'
+    main_head=$(dolt sql -q "SELECT hashof('main')" -r csv | tail -n 1)
+
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -am "other commit"
+    other_head=$(dolt sql -q "SELECT hashof('other')" -r csv | tail -n 1)
+
+    run dolt checkout main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "post-checkout $other_head $main_head 1" ]] || false
+}
+
+@test "hooks: post-checkout hook runs when tables are checked out" {
+    write_hook post-checkout 'echo "post-checkout $1 $2 $3"'
+    head=$(dolt sql -q "SELECT hashof('main')" -r csv | tail -n 1)
+    dolt sql -q "INSERT INTO test VALUES (1);"
+
+    run dolt checkout test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "post-checkout $head $head 0" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    run dolt checkout .
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "post-checkout $head $head 0" ]] || false
+}
+
+@test "hooks: commit hooks run for merge commits" {
+    write_hook pre-commit 'echo "pre-commit ran"'
+    write_hook post-commit 'echo "post-commit ran"'
+
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit --no-verify -am "other commit"
+    dolt checkout main
+    dolt sql -q "INSERT INTO test VALUES (2);"
+    dolt commit --no-verify -am "main commit"
+
+    run dolt merge other -m "merge other"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "pre-commit ran" ]] || false
+    [[ "$output" =~ "post-commit ran" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "merge other" ]] || false
+}
+
+@test "hooks: failing pre-commit hook aborts the merge commit" {
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -am "other commit"
+    dolt checkout main
+    dolt sql -q "INSERT INTO test VALUES (2);"
+    dolt commit -am "main commit"
+    write_hook pre-commit 'echo "validation failed"; exit 1'
+
+    run dolt merge other -m "merge other"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "validation failed" ]] || false
+
+    run dolt log -n 1
+    [[ ! "$output" =~ "merge other" ]] || false
+}
+
+@test "hooks: post-merge hook runs after a successful merge" {
+    write_hook post-merge 'echo "post-merge squash=$1"'
+
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -am "other commit"
+    dolt checkout main
+
+    run dolt merge other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "post-merge squash=0" ]] || false
+}
+
+@test "hooks: post-merge hook doesn't run when the merge has conflicts" {
+    write_hook post-merge 'echo "post-merge ran"'
+
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (1);"
+    dolt commit -am "other commit"
+    dolt checkout main
+    dolt sql -q "CREATE TABLE test2 (pk int primary key, c int);"
+    dolt sql -q "INSERT INTO test VALUES (2);"
+    dolt add -A && dolt commit -m "main commit"
+    dolt checkout other
+    dolt sql -q "CREATE TABLE test2 (pk int primary key, c int);"
+    dolt sql -q "INSERT INTO test2 VALUES (1, 1);"
+    dolt add -A && dolt commit -m "other commit 2"
+    dolt checkout main
+    dolt sql -q "INSERT INTO test2 VALUES (1, 2);"
+    dolt commit -am "main commit 2"
+
+    run dolt merge other
+    [[ "$output" =~ "CONFLICT" ]] || false
+    [[ ! "$output" =~ "post-merge ran" ]] || false
+}
@@ -813,3 +813,213 @@ DELIM
     [ "${lines[0]}" = "On branch main" ]
     [ "${lines[1]}" = "nothing to commit, working tree clean" ]
 }
+
+@test "import-create-tables: mapping file with renames, types, transforms, defaults and expressions" {
+    cat <<DELIM > people.csv
+id,first_name,last_name,state,notes
+1,Ada, Lovelace ,ca,unused
+2,Alan,Turing,ny,unused
+DELIM
+    cat <<'DELIM' > map.json
+{
+  "columns": {
+    "id": {"name": "pk", "type": "bigint"},
+    "first_name": "first",
+    "last_name": {"name": "last", "transform": "trim"},
+    "state": {"transform": "upper"},
+    "notes": {"skip": true}
+  },
+  "defaults": {"source": "crm"},
+  "expressions": {"full_name": "${first_name} ${last_name}"}
+}
+DELIM
+
+    run dolt table import -c --pk pk -m map.json people people.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt schema show people
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '`pk` bigint NOT NULL' ]] || false
+    [[ ! "$output" =~ "notes" ]] || false
+
+    run dolt sql -q "SELECT pk, first, last, state, source, full_name FROM people ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,Ada,Lovelace,CA,crm,Ada Lovelace " ]
+    [ "${lines[2]}" = "2,Alan,Turing,NY,crm,Alan Turing" ]
+}
+
+@test "import-create-tables: invalid mapping file is rejected before import" {
+    cat <<DELIM > people.csv
+id,name
+1,Ada
+DELIM
+    echo '{"columns": {"id": "pk", "missing": "name"}}' > map.json
+
+    run dolt table import -c --pk pk -m map.json people people.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid mapping file: column 'missing' is not in the input data" ]] || false
+
+    run dolt ls
+    [[ ! "$output" =~ "people" ]] || false
+}
+
+@test "import-create-tables: create a table from a jsonl file with flattening" {
+    cat <<SQL > clicks-sch.sql
+CREATE TABLE clicks (id int primary key, user_name varchar(20), user_id int, first_tag varchar(10), extra json);
+SQL
+    cat <<JSON > clicks.jsonl
+{"id": 1, "user": {"name": "tim", "id": 7, "age": 40}, "tags": ["a", "b"]}
+{"id": 2, "user": {"name": "brian"}, "kind": "click"}
+not json
+JSON
+    cat <<JSON > flatten.json
+{"columns": {"first_tag": "\$.tags[0]", "user_id": "\$.user.id"}, "flatten": true, "remainder": "extra"}
+JSON
+
+    run dolt table import -c clicks clicks.jsonl
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Please specify schema file for .jsonl tables." ]] || false
+
+    run dolt table import -c -s clicks-sch.sql --flatten flatten.json --bad-rows bad.csv clicks clicks.jsonl
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Lines skipped: 1" ]] || false
+
+    run dolt sql -q "SELECT id, user_name, user_id, first_tag, extra FROM clicks ORDER BY id" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = '1,tim,7,a,"{""tags"": [""a"", ""b""], ""user"": {""age"": 40}}"' ]
+    [ "${lines[2]}" = '2,brian,,,"{""kind"": ""click""}"' ]
+
+    run cat bad.csv
+    [[ "$output" =~ "line 3: not a JSON object" ]] || false
+
+    echo '{"id": 3, "user": {"name": "ada"}}' | dolt table import -u --file-type jsonl --flatten flatten.json clicks
+    run dolt sql -q "SELECT user_name FROM clicks WHERE id = 3" -r csv
+    [ "${lines[1]}" = "ada" ]
+
+    echo '{"id": 4, "unknown": 1}' > unknown.jsonl
+    run dolt table import -u clicks unknown.jsonl
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "fields unknown are not written to any column" ]] || false
+
+    run dolt table import -u --flatten flatten.json clicks clicks-sch.sql
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--flatten is only supported for jsonl files" ]] || false
+}
+
+@test "import-create-tables: create a table with column type overrides" {
+    cat <<DELIM > zips.csv
+id,zip,name
+1,02134,Allston
+2,10001,New York
+DELIM
+
+    run dolt table import -c --pk=id --column-types "zip:varchar(10), name:varchar(20)" zips zips.csv
+    [ "$status" -eq 0 ]
+    run dolt schema show zips
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`zip\` varchar(10)" ]] || false
+    [[ "$output" =~ "\`name\` varchar(20)" ]] || false
+    run dolt sql -q "SELECT zip FROM zips WHERE id = 1" -r csv
+    [ "${lines[1]}" = "02134" ]
+
+    run dolt table import -c --pk=id --column-types "missing:int" zips2 zips.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "column 'missing' is not in the imported data" ]] || false
+
+    run dolt table import -c --pk=id --column-types "zip:notatype" zips2 zips.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "column 'zip' has invalid type 'notatype'" ]] || false
+
+    run dolt table import -u --column-types "zip:int" zips zips.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--column-types is only supported when creating a table" ]] || false
+}
+
+@test "import-create-tables: create a table from a csv file with malformed rows" {
+    cat <<DELIM > malformed.csv
+id,name
+1,one
+2,two,extra
+3,three
+DELIM
+
+    run dolt table import -c --pk=id test malformed.csv
+    [ "$status" -eq 1 ]
+
+    run dolt table import -c --pk=id --continue --bad-rows bad.csv test malformed.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [ "${lines[1]}" = "1,one" ]
+    [ "${lines[2]}" = "3,three" ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ -s bad.csv ]
+}
+
+@test "import-create-tables: create a table from a tab delimited utf-16 file" {
+    printf '\xff\xfei\x00d\x00\t\x00n\x00a\x00m\x00e\x00\n\x001\x00\t\x00J\x00o\x00s\x00\xe9\x00\n\x00' > people.tsv
+
+    run dolt table import -c --pk=id --file-type csv --delim '\t' --encoding utf16 people people.tsv
+    [ "$status" -eq 0 ]
+    run dolt sql -q "SELECT name FROM people WHERE id = 1" -r csv
+    [ "${lines[1]}" = "José" ]
+}
+
+@test "import-create-tables: create a table from a fixed-width file with a copybook" {
+    cat <<COPYBOOK > customer.cpy
+      * CUSTOMER MASTER
+       01  CUSTOMER-RECORD.
+           05  CUST-ID               PIC 9(4).
+           05  CUST-NAME             PIC X(10).
+           05  FILLER                PIC X.
+           05  CUST-BALANCE          PIC S9(5)V99.
+COPYBOOK
+    cat <<DATA > customer.dat
+0001Ada        0012345
+0002Grace      000050}
+0003bad        00x1234
+DATA
+
+    run dolt table import -c --pk cust_id customers customer.dat
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Could not infer type file" ]] || false
+
+    run dolt table import -c --pk cust_id --layout customer.cpy --bad-rows bad.csv customers customer.dat
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Lines skipped: 1" ]] || false
+
+    run dolt schema show customers
+    [[ "$output" =~ "\`cust_id\` int NOT NULL" ]] || false
+    [[ "$output" =~ "\`cust_name\` varchar(10)" ]] || false
+    [[ "$output" =~ "\`cust_balance\` decimal(7,2)" ]] || false
+
+    run dolt sql -q "SELECT * FROM customers ORDER BY cust_id" -r csv
+    [ "${lines[1]}" = "1,Ada,123.45" ]
+    [ "${lines[2]}" = "2,Grace,-5.00" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run cat bad.csv
+    [[ "$output" =~ "record 3: column cust_balance: invalid number '00x1234'" ]] || false
+}
+
+@test "import-create-tables: update a table from a fixed-width file with a json layout" {
+    dolt sql -q "CREATE TABLE items (id int primary key, name varchar(20), qty int)"
+    cat <<JSON > layout.json
+{"columns": [{"name": "id", "width": 3}, {"name": "name", "start": 5, "width": 6}, {"name": "qty", "width": 3}], "record_length": 13}
+JSON
+    printf '001 apple  12002 pear    7' > items.dat
+
+    dolt table import -u --layout layout.json items items.dat
+    run dolt sql -q "SELECT * FROM items ORDER BY id" -r csv
+    [ "${lines[1]}" = "1,apple,12" ]
+    [ "${lines[2]}" = "2,pear,7" ]
+
+    run dolt table import -u --file-type fixed-width items items.dat
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--layout is required to import fixed-width files" ]] || false
+
+    run dolt table import -u --file-type csv --layout layout.json items items.dat
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--layout is only supported for fixed-width files" ]] || false
+}
@@ -0,0 +1,165 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    cat > dump.sql <<'SQL'
+--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SELECT pg_catalog.set_config('search_path', '', false);
+
+CREATE TYPE public.status AS ENUM (
+    'open',
+    'shipped'
+);
+
+ALTER TYPE public.status OWNER TO postgres;
+
+CREATE FUNCTION public.one() RETURNS integer
+    LANGUAGE sql
+    AS $$ select 1; $$;
+
+CREATE TABLE public.customers (
+    id integer NOT NULL,
+    name character varying(50) NOT NULL,
+    active boolean DEFAULT true NOT NULL,
+    photo bytea
+);
+
+ALTER TABLE public.customers OWNER TO postgres;
+
+CREATE SEQUENCE public.customers_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE public.customers_id_seq OWNED BY public.customers.id;
+
+CREATE TABLE public.orders (
+    id bigint NOT NULL,
+    customer_id integer,
+    status public.status DEFAULT 'open'::public.status,
+    total numeric(10,2),
+    CONSTRAINT orders_total_check CHECK ((total > (0)::numeric))
+);
+
+ALTER TABLE public.orders ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY (
+    SEQUENCE NAME public.orders_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+CREATE VIEW public.big_orders AS
+ SELECT orders.id,
+    orders.total
+   FROM public.orders
+  WHERE (orders.total > (100)::numeric);
+
+ALTER TABLE ONLY public.customers ALTER COLUMN id SET DEFAULT nextval('public.customers_id_seq'::regclass);
+
+COPY public.customers (id, name, active, photo) FROM stdin;
+1	alice	t	\\x6869
+2	bob\tby	f	\N
+\.
+
+COPY public.orders (id, customer_id, status, total) FROM stdin;
+10	1	shipped	150.50
+11	2	open	5.00
+\.
+
+SELECT pg_catalog.setval('public.customers_id_seq', 2, true);
+
+ALTER TABLE ONLY public.customers
+    ADD CONSTRAINT customers_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX customers_name_idx ON public.customers USING btree (name);
+
+ALTER TABLE ONLY public.orders
+    ADD CONSTRAINT orders_customer_id_fkey FOREIGN KEY (customer_id) REFERENCES public.customers(id);
+
+--
+-- PostgreSQL database dump complete
+--
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "import-pgdump: imports tables, rows, constraints and views" {
+    run dolt sql --pg-dump < dump.sql
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "warning: skipped unsupported statement: CREATE FUNCTION one()" ]] || false
+
+    run dolt sql -q "SELECT id, name, active, photo FROM customers ORDER BY id" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,alice,1,hi" ]] || false
+    [[ "$output" =~ "2,bob	by,0," ]] || false
+
+    run dolt sql -q "SELECT * FROM big_orders" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "10,150.50" ]] || false
+    [ "${#lines[@]}" -eq 2 ]
+
+    # serial and identity columns are AUTO_INCREMENT
+    dolt sql -q "INSERT INTO customers (name) VALUES ('carol')"
+    dolt sql -q "INSERT INTO orders (customer_id, total) VALUES (3, 1.00)"
+    run dolt sql -q "SELECT o.id, c.id, o.status FROM orders o JOIN customers c ON o.customer_id = c.id WHERE c.name = 'carol'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "12,3,open" ]] || false
+
+    run dolt sql -q "INSERT INTO orders (customer_id, total) VALUES (99, 1.00)"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "foreign key" ]] || false
+
+    run dolt sql -q "INSERT INTO orders (customer_id, total) VALUES (1, -1.00)"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "orders_total_check" ]] || false
+
+    run dolt sql -q "INSERT INTO customers (name) VALUES ('alice')"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "duplicate unique key" ]] || false
+}
+
+@test "import-pgdump: reads the dump from a file" {
+    run dolt sql --pg-dump --file dump.sql
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT count(*) FROM orders" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+}
+
+@test "import-pgdump: malformed data fails the import" {
+    cat > bad.sql <<'SQL'
+CREATE TABLE public.t (id integer, v text);
+COPY public.t (id, v) FROM stdin;
+1	a	extra
+\.
+SQL
+    run dolt sql --pg-dump < bad.sql
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "table t: expected 2 values, found 3" ]] || false
+}
+
+@test "import-pgdump: --pg-dump can't be used with --query" {
+    run dolt sql --pg-dump -q "SELECT 1"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "--pg-dump is only used with statements from stdin or --file" ]] || false
+}
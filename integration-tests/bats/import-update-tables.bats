@@ -1364,3 +1364,126 @@ DELIM
     [ $status -eq 0 ]
     [[ "$output" = "$expected" ]] || false
 }
+
+@test "import-update-tables: mapping file casts values and reports bad rows" {
+    dolt sql -q "CREATE TABLE people (pk int primary key, name varchar(20), age int);"
+    cat <<DELIM > people.csv
+id,name,age
+1,ada,36
+2,alan,unknown
+DELIM
+    echo '{"columns": {"id": "pk", "name": {"transform": "upper"}, "age": {"type": "int"}}}' > map.json
+
+    run dolt table import -u -m map.json people people.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "could not cast value 'unknown' of column 'age' to int" ]] || false
+
+    run dolt table import -u --continue -m map.json people people.csv
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT * FROM people" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,ADA,36" ]
+    [ "${#lines[@]}" -eq 2 ]
+}
+
+@test "import-update-tables: import with a custom quote and escape character" {
+    dolt sql -q "CREATE TABLE people (pk int primary key, name varchar(40));"
+    cat <<'DELIM' > people.csv
+pk;name
+1;'O''Brien; Pat'
+2;'it\'s'
+DELIM
+
+    run dolt table import -u --delim ";" --quote "'" --escape '\' people people.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt sql -q "SELECT * FROM people ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,O'Brien; Pat" ]
+    [ "${lines[2]}" = "2,it's" ]
+}
+
+@test "import-update-tables: import a file with a non-utf8 encoding" {
+    dolt sql -q "CREATE TABLE people (pk int primary key, name varchar(40));"
+    printf 'pk,name\n1,Jos\xe9\n2,Se\xf1or\n' > people.csv
+
+    run dolt table import -u --encoding latin1 people people.csv
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT name FROM people ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "José" ]
+    [ "${lines[2]}" = "Señor" ]
+
+    run dolt table import -u --encoding not-an-encoding people people.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unsupported encoding 'not-an-encoding'" ]] || false
+}
+
+@test "import-update-tables: --bad-rows writes rejected rows to a file" {
+    dolt sql -q "CREATE TABLE test (pk int primary key, v int);"
+    cat <<DELIM > test.csv
+pk,v
+1,10
+3
+4,40
+DELIM
+
+    run dolt table import -u --bad-rows rejected.csv test test.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Lines skipped: 1" ]] || false
+    [[ "$output" =~ "Skipped rows were written to rejected.csv" ]] || false
+
+    run cat rejected.csv
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "${lines[0]}" =~ "3,CSV reader expected 2 values, but saw 1." ]] || false
+
+    run dolt sql -q "SELECT pk FROM test ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "4" ]
+    [ "${#lines[@]}" -eq 3 ]
+}
+
+@test "import-update-tables: import spatial values as wkt, wkb and geojson" {
+    dolt sql -q "CREATE TABLE places (pk int primary key, geom geometry);"
+    cat <<DELIM > places.csv
+pk,geom
+1,POINT(1 2)
+2,SRID=4326;LINESTRING(0 0 , 1 1)
+3,0101000000000000000000F03F0000000000000040
+4,0x000000000101000000000000000000F03F0000000000000040
+5,not a geometry
+DELIM
+
+    run dolt table import -u places places.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "column geom: invalid spatial value 'not a geometry'" ]] || false
+
+    run dolt table import -u --continue places places.csv
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT pk, st_aswkt(geom), st_srid(geom) FROM places ORDER BY pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,POINT(1 2),0" ]
+    [ "${lines[2]}" = "2,\"LINESTRING(0 0,1 1)\",4326" ]
+    [ "${lines[3]}" = "3,POINT(1 2),0" ]
+    [ "${lines[4]}" = "4,POINT(1 2),0" ]
+    [ "${#lines[@]}" -eq 5 ]
+
+    cat <<JSON > places.geojson
+{"type": "FeatureCollection", "features": [
+  {"type": "Feature", "properties": {"pk": 6}, "geometry": {"type": "Point", "coordinates": [3, 4]}},
+  {"type": "Feature", "properties": {"pk": 7, "color": "red"}, "geometry": null}
+]}
+JSON
+    run dolt table import -u --continue places places.geojson
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "feature 2: properties color are not columns of the table" ]] || false
+
+    run dolt sql -q "SELECT st_aswkt(geom) FROM places WHERE pk = 6" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "POINT(3 4)" ]
+}
@@ -399,6 +399,35 @@ SQL
 
 }
 
+@test "keyless: diff --keyless-counts and --keyless-hash" {
+    make_dupe_table
+
+    dolt sql -q "DELETE FROM dupe LIMIT 4;"
+    dolt sql -q "INSERT INTO dupe VALUES (2,2),(2,2);"
+
+    run dolt diff --keyless-counts
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 9 ] # 2 diffs + 6 header + 1 footer
+    [[ "$output" =~ "| count |" ]] || false
+    [[ "$output" =~ "| - | 1  | 1  | 4     |" ]] || false
+    [[ "$output" =~ "| + | 2  | 2  | 2     |" ]] || false
+
+    run dolt diff --keyless-counts --keyless-hash -r json
+    [ $status -eq 0 ]
+    [[ "$output" =~ '"count":4' ]] || false
+    [[ "$output" =~ '"count":2' ]] || false
+    [[ "$output" =~ '"row_hash":"' ]] || false
+
+    # each copy of a row has the same hash
+    run dolt diff --keyless-hash -r json
+    [ $status -eq 0 ]
+    [ $(echo "$output" | grep -o '"row_hash":"[a-z0-9]*"' | sort -u | wc -l) -eq 2 ]
+
+    run dolt diff --keyless-counts -r sql
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot be used with sql output" ]] || false
+}
+
 @test "keyless: merge duplicate deletes" {
 
     make_dupe_table
@@ -642,4 +642,97 @@ teardown() {
     [[ !("$output" =~ "HEAD") ]] || false
     run dolt log commit2
     [[ "$output" =~ "HEAD" ]] || false
-}
\ No newline at end of file
+}
+
+@test "log: --graph draws branch and merge topology" {
+    dolt sql -q "create table test (pk int primary key)"
+    dolt commit -Am "Created table"
+    dolt checkout -b branch1
+    dolt sql -q "insert into test values (1)"
+    dolt commit -am "Inserted 1 on branch1"
+    dolt checkout main
+    dolt sql -q "insert into test values (2)"
+    dolt commit -am "Inserted 2 on main"
+    dolt merge branch1 -m "Merged branch1"
+
+    run dolt log --graph --oneline
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "*   " ]] || false
+    [[ "${lines[0]}" =~ "Merged branch1" ]] || false
+    [[ "${lines[1]}" =~ '|\' ]] || false
+    [[ "$output" =~ "| * " ]] || false
+    [[ "$output" =~ "|/" ]] || false
+    [[ "$output" =~ "* " ]] || false
+    [[ "$output" =~ "Initialize data repository" ]] || false
+
+    run dolt log --graph
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Author" ]] || false
+    [[ "$output" =~ "| * commit" ]] || false
+    [[ "$output" =~ "Merged branch1" ]] || false
+
+    run dolt log --graph --oneline --merges
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "${lines[0]}" =~ "* " ]] || false
+    [[ "${lines[0]}" =~ "Merged branch1" ]] || false
+}
+
+@test "log: table after -- filters commits by table" {
+    dolt sql -q "create table main (pk int PRIMARY KEY)"
+    dolt sql -q "create table other (pk int PRIMARY KEY)"
+    dolt add .
+    dolt commit -m "create tables"
+    dolt sql -q "insert into other values (1)"
+    dolt commit -am "insert into other"
+    dolt checkout -b branch1
+    dolt sql -q "insert into main values (1)"
+    dolt commit -am "insert into main on branch1"
+    dolt checkout main
+    dolt sql -q "insert into other values (2)"
+    dolt commit -am "insert into other on main"
+    dolt merge --no-ff branch1 -m "merge branch1"
+
+    # main is both a branch and a table, -- forces it to be read as a table
+    run dolt log -- main
+    [ $status -eq 0 ]
+    [[ "$output" =~ "insert into main on branch1" ]] || false
+    [[ "$output" =~ "create tables" ]] || false
+    [[ ! "$output" =~ "insert into other" ]] || false
+    [[ ! "$output" =~ "merge branch1" ]] || false
+
+    run dolt log main -- other
+    [ $status -eq 0 ]
+    [[ "$output" =~ "insert into other on main" ]] || false
+    [[ "$output" =~ "insert into other" ]] || false
+    [[ ! "$output" =~ "insert into main on branch1" ]] || false
+    [[ ! "$output" =~ "merge branch1" ]] || false
+
+    run dolt log --oneline -n 1 -- other
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "insert into other on main" ]] || false
+
+    run dolt log -- main other
+    [ $status -eq 1 ]
+    [[ "$output" =~ "exactly one table must be provided after --" ]] || false
+}
+
+@test "log: --format json" {
+    dolt commit --allow-empty -m "first
+with a second line"
+    dolt tag v1
+
+    run dolt log --format json -n 2
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ '{"commits":[{"hash":"' ]] || false
+    [[ "$output" =~ '"refs":["main","tag: v1"],"head":true' ]] || false
+    [[ "$output" =~ '"message":"first\nwith a second line"' ]] || false
+    [[ "$output" =~ '"message":"Initialize data repository"' ]] || false
+    [[ ! "$output" =~ $'\033' ]] || false
+
+    run dolt log --format json --oneline
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot be combined with --oneline or --graph" ]] || false
+}
@@ -29,4 +29,88 @@ teardown() {
     log_status_eq 0
     [[ "$output" =~ "1,0" ]] || false
     [[ "$output" =~ "2,0" ]] || false
-}
\ No newline at end of file
+}
+
+@test "merge-3way-schema-changes: independent column and index changes on both branches merge" {
+    dolt sql -q "create table t (pk int primary key, a int, b int);"
+    dolt sql -q "insert into t values (1, 1, 1);"
+    dolt commit -Am "ancestor"
+
+    dolt checkout -b right
+    dolt sql -q "alter table t add column y int;"
+    dolt sql -q "alter table t alter column a set default 10;"
+    dolt sql -q "alter table t add index key_b (b);"
+    dolt sql -q "insert into t values (2, 2, 2, 2);"
+    dolt commit -am "right"
+
+    dolt checkout main
+    dolt sql -q "alter table t add column x int;"
+    dolt sql -q "alter table t alter column a set default 10;"
+    dolt sql -q "alter table t add index b_idx (b);"
+    dolt sql -q "insert into t values (3, 3, 3, 3);"
+    dolt commit -am "left"
+
+    run dolt merge right -m "merge right"
+    log_status_eq 0
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "show create table t"
+    log_status_eq 0
+    [[ "$output" =~ "\`a\` int DEFAULT '10'" ]] || false
+    [[ "$output" =~ "\`x\` int" ]] || false
+    [[ "$output" =~ "\`y\` int" ]] || false
+    [[ "$output" =~ "KEY \`b_idx\` (\`b\`)" ]] || false
+    [[ "$output" =~ "KEY \`key_b\` (\`b\`)" ]] || false
+
+    run dolt sql -q "select pk, a, b, x, y from t order by pk" -r csv
+    log_status_eq 0
+    [ "${lines[1]}" = "1,1,1,," ]
+    [ "${lines[2]}" = "2,2,2,,2" ]
+    [ "${lines[3]}" = "3,3,3,3," ]
+}
+
+@test "merge-3way-schema-changes: changing the same column differently on both branches is a schema conflict" {
+    dolt sql -q "create table t (pk int primary key, a int, b int);"
+    dolt commit -Am "ancestor"
+
+    dolt checkout -b right
+    dolt sql -q "alter table t add column y int;"
+    dolt sql -q "alter table t alter column a set default 20;"
+    dolt commit -am "right"
+
+    dolt checkout main
+    dolt sql -q "alter table t add column x int;"
+    dolt sql -q "alter table t alter column a set default 10;"
+    dolt commit -am "left"
+
+    run dolt merge right -m "merge right"
+    [[ "$output" =~ "CONFLICT (schema)" ]] || false
+
+    run dolt sql -q "select count(*) from dolt_schema_conflicts" -r csv
+    log_status_eq 0
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "merge-3way-schema-changes: setting a column added on a branch doesn't conflict with edits to other columns" {
+    dolt sql -q "create table t (pk int primary key, a int);"
+    dolt sql -q "insert into t values (1, 1), (2, 2);"
+    dolt commit -Am "ancestor"
+
+    dolt checkout -b right
+    dolt sql -q "alter table t add column b int default 7;"
+    dolt sql -q "update t set b = 3 where pk = 1;"
+    dolt commit -am "right"
+
+    dolt checkout main
+    dolt sql -q "update t set a = 10 where pk = 1;"
+    dolt commit -am "left"
+
+    run dolt merge right -m "merge right"
+    log_status_eq 0
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "select * from t order by pk" -r csv
+    log_status_eq 0
+    [ "${lines[1]}" = "1,10,3" ]
+    [ "${lines[2]}" = "2,2,7" ]
+}
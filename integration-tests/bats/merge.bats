@@ -1057,3 +1057,226 @@ SQL
     [ $status -eq 0 ]
     [[ "$output" =~ "2 tables changed, 3 rows added(+), 1 rows modified(*), 1 rows deleted(-)" ]] || false
 }
+
+@test "merge: edits to different columns of the same row in a wide table merge cleanly" {
+    cols=""
+    vals=""
+    for i in $(seq 1 200); do
+        cols="$cols, c$i int"
+        vals="$vals, 0"
+    done
+    dolt sql -q "create table wide (pk int primary key $cols);"
+    dolt sql -q "insert into wide values (1 $vals), (2 $vals);"
+    dolt commit -Am "add wide"
+
+    dolt checkout -b other
+    dolt sql -q "update wide set c1 = 1, c100 = 1 where pk = 1;"
+    dolt sql -q "update wide set c50 = 2 where pk = 2;"
+    dolt commit -am "other"
+
+    dolt checkout main
+    dolt sql -q "update wide set c2 = 1, c200 = 1 where pk = 1;"
+    dolt sql -q "update wide set c50 = 3 where pk = 2;"
+    dolt commit -am "main"
+
+    run dolt merge other -m "merge other"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "CONFLICT (content): Merge conflict in wide" ]] || false
+
+    run dolt sql -q "select base_pk from dolt_conflicts_wide" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "2" ]
+
+    run dolt sql -q "select c1, c2, c100, c200 from wide where pk = 1" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1,1,1,1" ]
+}
+
+@test "merge: --dry-run reports conflicts without modifying the working set" {
+    dolt sql -q "insert into test1 values (1, 1, 1)"
+    dolt commit -am "ancestor"
+    dolt branch clean
+    dolt branch other
+
+    dolt checkout clean
+    dolt sql -q "insert into test2 values (2, 2, 2)"
+    dolt commit -am "clean change"
+
+    dolt checkout other
+    dolt sql -q "update test1 set c1 = 10 where pk = 1"
+    dolt commit -am "conflicting change"
+
+    dolt checkout main
+    dolt sql -q "update test1 set c1 = 100 where pk = 1"
+    dolt commit -am "main change"
+
+    run dolt merge --dry-run other
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "CONFLICT (content): Merge conflict in test1" ]] || false
+    [[ "$output" =~ "Dry run: no changes were made" ]] || false
+
+    run dolt status
+    log_status_eq 0
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+    [[ ! "$output" =~ "merging" ]] || false
+
+    run dolt merge --dry-run clean
+    log_status_eq 0
+    [[ "$output" =~ "test2 | 1" ]] || false
+    [[ "$output" =~ "Dry run: no changes were made" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "main change" ]] || false
+
+    run dolt sql -q "select * from test2" -r csv
+    [ "${#lines[@]}" -eq 1 ]
+}
+
+@test "merge: column merge drivers resolve cells changed on both branches" {
+    dolt sql <<SQL
+create table counters (pk int primary key, hits int, doc json, label varchar(20));
+insert into counters values (1, 10, '{"a": 1, "b": 1}', 'base');
+insert into dolt_merge_drivers values ('counters.hits', 'sum'), ('json', 'json_deep_merge');
+SQL
+    dolt commit -Am "ancestor"
+
+    dolt checkout -b other
+    dolt sql -q "update counters set hits = 15, doc = '{\"a\": 2, \"b\": 1}' where pk = 1"
+    dolt commit -am "other"
+
+    dolt checkout main
+    dolt sql -q "update counters set hits = 12, doc = '{\"a\": 1, \"b\": 2}' where pk = 1"
+    dolt commit -am "main"
+
+    run dolt merge other -m "merge other"
+    log_status_eq 0
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+
+    run dolt sql -q "select hits, json_extract(doc, '$.a'), json_extract(doc, '$.b'), label from counters" -r csv
+    log_status_eq 0
+    [ "${lines[1]}" = "17,2,2,base" ]
+
+    # columns without a driver still conflict
+    dolt checkout -b other2
+    dolt sql -q "update counters set label = 'other' where pk = 1"
+    dolt commit -am "other2"
+
+    dolt checkout main
+    dolt sql -q "update counters set label = 'main' where pk = 1"
+    dolt commit -am "main2"
+
+    run dolt merge other2 -m "merge other2"
+    [[ "$output" =~ "CONFLICT (content): Merge conflict in counters" ]] || false
+}
+
+@test "merge: unknown column merge drivers are an error" {
+    dolt sql -q "insert into dolt_merge_drivers values ('test1.c1', 'no_such_driver')"
+    dolt commit -Am "add merge driver"
+
+    dolt checkout -b other
+    dolt sql -q "insert into test1 values (1, 1, 1)"
+    dolt commit -am "other"
+
+    dolt checkout main
+    dolt sql -q "insert into test1 values (2, 2, 2)"
+    dolt commit -am "main"
+
+    run dolt merge other -m "merge other"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unknown merge driver 'no_such_driver'" ]] || false
+}
+
+@test "merge: --verify-fks reports foreign key violations of the whole merged result" {
+    dolt sql -q "create table parent (pk int primary key)"
+    dolt sql -q "create table child (pk int primary key, parent_fk int, foreign key (parent_fk) references parent(pk))"
+    dolt sql -q "insert into parent values (1)"
+    dolt sql -q "set foreign_key_checks = 0; insert into child values (1, 1), (2, 2);"
+    dolt commit -Am "orphaned child"
+
+    dolt checkout -b other
+    dolt sql -q "insert into parent values (3)"
+    dolt commit -am "other"
+
+    dolt checkout main
+    dolt sql -q "insert into parent values (4)"
+    dolt commit -am "main"
+
+    run dolt merge other --dry-run
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "CONSTRAINT VIOLATION" ]] || false
+
+    run dolt merge other --verify-fks -m "merge other"
+    [[ "$output" =~ "CONSTRAINT VIOLATION (content): Merge created constraint violation in child" ]] || false
+    [[ "$output" =~ "CONSTRAINT VIOLATION (foreign key): foreign key " ]] || false
+    [[ "$output" =~ "child row (2) references parent(pk) = (2), which does not exist" ]] || false
+
+    run dolt sql -q "select pk, parent_fk from dolt_constraint_violations_child" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2,2" ]] || false
+    [[ ! "$output" =~ "1,1" ]] || false
+}
+
+@test "merge: --format json" {
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test1 VALUES (1,1,1),(2,2,2)"
+    dolt commit -am "added rows on other"
+    dolt checkout main
+
+    run dolt merge other --format json
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ '{"status":"fast_forward","from":"' ]] || false
+    [[ "$output" =~ '"committed":false,"dry_run":false,"tables":[]}' ]] || false
+
+    dolt checkout other
+    dolt sql -q "INSERT INTO test1 VALUES (3,3,3)"
+    dolt commit -am "changed row on other"
+    dolt checkout main
+    dolt sql -q "INSERT INTO test2 VALUES (1,1,1)"
+    dolt commit -am "added row on main"
+
+    run dolt merge other --dry-run --format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"status":"merged"' ]] || false
+    [[ "$output" =~ '"dry_run":true' ]] || false
+    [[ "$output" =~ '{"table":"test1","operation":"modified","rows_added":1,"rows_modified":0,"rows_deleted":0' ]] || false
+
+    run dolt merge other --no-edit --format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"status":"merged"' ]] || false
+    [[ "$output" =~ '"committed":true' ]] || false
+    head=$(dolt sql -q "select hashof('HEAD')" -r csv | tail -n 1)
+    [[ "$output" =~ "\"head\":\"$head\"" ]] || false
+
+    run dolt merge other --format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"status":"up_to_date"' ]] || false
+}
+
+@test "merge: --delete-source deletes the merged branch" {
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test1 VALUES (5,5,5)"
+    dolt commit -am "added row on other"
+    dolt checkout main
+
+    run dolt merge other --delete-source --format json
+    [ "$status" -ne 0 ]
+
+    run dolt merge other --delete-source
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Deleted branch other" ]] || false
+    run dolt branch
+    [[ ! "$output" =~ "other" ]] || false
+
+    # merging a commit leaves branches alone
+    dolt branch keep
+    dolt checkout keep
+    dolt sql -q "INSERT INTO test1 VALUES (6,6,6)"
+    dolt commit -am "added row on keep"
+    dolt checkout main
+    run dolt merge $(dolt sql -q "select hashof('keep')" -r csv | tail -n 1) --delete-source
+    [ "$status" -eq 0 ]
+    run dolt branch
+    [[ "$output" =~ "keep" ]] || false
+}
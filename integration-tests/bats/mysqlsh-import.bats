@@ -0,0 +1,115 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    # a dump of the schema shop as written by util.dumpSchemas(["shop"], "dump", {compression: "none"})
+    mkdir dump
+    cat > dump/@.json <<'JSON'
+{"dumper": "mysqlsh Ver 8.0.33", "version": "2.0.1", "schemas": ["shop"], "basenames": {"shop": "shop"}}
+JSON
+    cat > dump/shop.json <<'JSON'
+{"tables": ["customers", "orders"], "views": ["big_orders"], "basenames": {"customers": "shop@customers", "orders": "shop@orders", "big_orders": "shop@big_orders"}}
+JSON
+    cat > dump/shop@customers.json <<'JSON'
+{"options": {"columns": ["id", "name", "photo"], "fieldsTerminatedBy": "\t", "fieldsEnclosedBy": "", "fieldsEscapedBy": "\\", "linesTerminatedBy": "\n", "decodeColumns": {"photo": "FROM_BASE64"}}, "triggers": [], "extension": "tsv", "chunking": true, "compression": "none"}
+JSON
+    cat > dump/shop@customers.sql <<'SQL'
+CREATE TABLE IF NOT EXISTS `customers` (
+  `id` int NOT NULL,
+  `name` varchar(50) DEFAULT NULL,
+  `photo` blob,
+  PRIMARY KEY (`id`)
+);
+SQL
+    printf '1\talice\taGk=\n2\t\\N\t\\N\n' > dump/shop@customers@0.tsv
+    printf '3\tbob\\there\t\\N\n' > dump/shop@customers@@1.tsv
+    cat > dump/shop@orders.json <<'JSON'
+{"options": {"columns": ["id", "customer_id", "total"], "fieldsTerminatedBy": "\t", "fieldsEnclosedBy": "", "fieldsEscapedBy": "\\", "linesTerminatedBy": "\n"}, "triggers": ["orders_ins"], "extension": "tsv", "chunking": false, "compression": "none"}
+JSON
+    cat > dump/shop@orders.sql <<'SQL'
+CREATE TABLE IF NOT EXISTS `orders` (
+  `id` int NOT NULL,
+  `customer_id` int DEFAULT NULL,
+  `total` decimal(10,2) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_customer` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`)
+);
+SQL
+    printf '10\t1\t5.00\n11\t3\t150.50\n' > dump/shop@orders.tsv
+    cat > dump/shop@orders.triggers.sql <<'SQL'
+DELIMITER ;;
+CREATE TRIGGER `orders_ins` BEFORE INSERT ON `orders` FOR EACH ROW BEGIN
+  SET NEW.total = NEW.total + 1;
+END;;
+DELIMITER ;
+SQL
+    cat > dump/shop@big_orders.sql <<'SQL'
+CREATE VIEW `big_orders` AS select `id`, `total` from `orders` where `total` > 100;
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "mysqlsh-import: imports tables, rows, views and triggers" {
+    touch dump/@.done.json
+    run dolt mysqlsh-import --threads 2 dump
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Imported 3 rows into customers" ]] || false
+    [[ "$output" =~ "Imported 2 rows into orders" ]] || false
+
+    run dolt sql -q "SELECT id, name, photo FROM customers ORDER BY id" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,alice,hi" ]] || false
+    [[ "$output" =~ "2,," ]] || false
+    [[ "$output" =~ "3,bob	here," ]] || false
+
+    # the trigger was created after the rows were imported
+    run dolt sql -q "SELECT * FROM big_orders" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "11,150.50" ]] || false
+    [ "${#lines[@]}" -eq 2 ]
+
+    dolt sql -q "INSERT INTO orders VALUES (12, 2, 1.00)"
+    run dolt sql -q "SELECT total FROM orders WHERE id = 12" -r csv
+    [[ "$output" =~ "2.00" ]] || false
+
+    run dolt sql -q "INSERT INTO orders VALUES (13, 99, 1.00)"
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "foreign key" ]] || false
+}
+
+@test "mysqlsh-import: fails on an incomplete dump" {
+    run dolt mysqlsh-import dump
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "the dump is incomplete" ]] || false
+
+    run dolt sql -q "SHOW TABLES"
+    [[ ! "$output" =~ "customers" ]] || false
+}
+
+@test "mysqlsh-import: fails on a bad row" {
+    touch dump/@.done.json
+    printf '4\tcarol\n' > dump/shop@customers@@1.tsv
+    run dolt mysqlsh-import dump
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "shop@customers@@1.tsv: line 1: expected 3 fields, found 2" ]] || false
+}
+
+@test "mysqlsh-import: requires --schema for a dump with several schemas" {
+    touch dump/@.done.json
+    cat > dump/@.json <<'JSON'
+{"schemas": ["shop", "other"], "basenames": {"shop": "shop", "other": "other"}}
+JSON
+    run dolt mysqlsh-import dump
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "use --schema to choose one of: shop, other" ]] || false
+
+    run dolt mysqlsh-import --schema shop dump
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Imported 3 rows into customers" ]] || false
+}
@@ -0,0 +1,130 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE churn (pk int primary key, v int);"
+    dolt sql -q "CREATE TABLE keep (pk int primary key);"
+    dolt add -A && dolt commit -m "create tables" --date 2020-01-01
+    dolt sql -q "INSERT INTO churn VALUES (1, 1); INSERT INTO keep VALUES (1);"
+    dolt commit -am "version 1" --date 2020-06-01
+    dolt sql -q "UPDATE churn SET v = 2;"
+    dolt commit -am "version 2" --date 2021-06-01
+    dolt sql -q "UPDATE churn SET v = 3;"
+    dolt commit -am "version 3" --date 2022-06-01
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "purge-history: removes old versions of a table and keeps recent history" {
+    run dolt purge-history --before 2021-01-01 churn
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Removed 'churn' from 2 commit(s)" ]] || false
+
+    run dolt sql -q "SELECT v FROM dolt_history_churn ORDER BY commit_date" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+    [ "${lines[2]}" = "3" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run dolt sql -q "SELECT v FROM churn" -r csv
+    [ "${lines[1]}" = "3" ]
+
+    # other tables keep their full history
+    run dolt sql -q "SELECT count(*) FROM dolt_history_keep" -r csv
+    [ "${lines[1]}" = "3" ]
+
+    run dolt log
+    [[ "$output" =~ "version 1" ]] || false
+    [[ "$output" =~ "create tables" ]] || false
+}
+
+@test "purge-history: keeps the table in commits pointed to by branches and tags" {
+    dolt tag v1 HEAD~2
+
+    run dolt purge-history --before 2021-01-01 churn
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Removed 'churn' from 1 commit(s)" ]] || false
+
+    run dolt sql -q "SELECT v FROM churn AS OF 'v1'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "purge-history: rewrites all branches" {
+    dolt branch other HEAD~1
+
+    dolt purge-history --before 2021-01-01 churn
+
+    run dolt sql -q "SELECT count(*) FROM dolt_history_churn AS OF 'other'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "purge-history: cutoff older than all commits is a no-op" {
+    head=$(dolt sql -q "SELECT hashof('HEAD')" -r csv | tail -n 1)
+
+    run dolt purge-history --before 2019-01-01 churn
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "No versions of 'churn'" ]] || false
+
+    run dolt sql -q "SELECT hashof('HEAD')" -r csv
+    [ "${lines[1]}" = "$head" ]
+}
+
+@test "purge-history: dolt_purge_history removes old versions from sql" {
+    run dolt sql -q "CALL dolt_purge_history('--before', '2021-01-01', 'churn')" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+
+    run dolt sql -q "SELECT v FROM dolt_history_churn ORDER BY commit_date" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+    [ "${lines[2]}" = "3" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run dolt sql -q "CALL dolt_purge_history('churn')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--before is required" ]] || false
+}
+
+@test "purge-history: requires a clean working set" {
+    dolt sql -q "INSERT INTO churn VALUES (2, 2);"
+
+    run dolt purge-history --before 2021-01-01 churn
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot purge history with uncommitted changes" ]] || false
+
+    run dolt sql -q "CALL dolt_purge_history('--before', '2021-01-01', 'churn')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot purge history with uncommitted changes" ]] || false
+}
+
+@test "purge-history: rewrites tags whose history was purged" {
+    dolt tag -m "release" v3
+
+    run dolt purge-history --before 2021-01-01 churn
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT hashof('v3') = hashof('main')" -r csv
+    [ "${lines[1]}" = "true" ]
+
+    run dolt sql -q "SELECT message FROM dolt_tags WHERE tag_name = 'v3'" -r csv
+    [ "${lines[1]}" = "release" ]
+
+    run dolt sql -q "SELECT count(*) FROM dolt_history_churn AS OF 'v3'" -r csv
+    [ "${lines[1]}" = "2" ]
+}
+
+@test "purge-history: requires a cutoff" {
+    run dolt purge-history churn
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--before is required" ]] || false
+
+    run dolt purge-history --before notadate churn
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid date" ]] || false
+}
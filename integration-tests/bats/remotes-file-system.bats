@@ -176,3 +176,283 @@ SQL
     [ ! -d test-repo ]
     cd ..
 }
+
+@test "remotes-file-system: prune remote-tracking branches deleted on the remote" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt branch b1
+    dolt branch b2
+    dolt push origin main
+    dolt push origin b1
+    dolt push origin b2
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd ../
+    dolt push origin :b1
+
+    cd dolt-repo-clones/test-repo
+    run dolt remote prune --dry-run origin
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[would prune] origin/b1" ]] || false
+    run dolt branch -r
+    [[ "$output" =~ "origin/b1" ]] || false
+
+    run dolt remote prune origin
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[pruned] origin/b1" ]] || false
+    run dolt branch -r
+    [[ ! "$output" =~ "origin/b1" ]] || false
+    [[ "$output" =~ "origin/b2" ]] || false
+    [[ "$output" =~ "origin/main" ]] || false
+
+    cd ../../
+    dolt push origin :b2
+    cd dolt-repo-clones/test-repo
+    run dolt fetch --prune
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[pruned] origin/b2" ]] || false
+    run dolt branch -r
+    [[ ! "$output" =~ "origin/b2" ]] || false
+    [[ "$output" =~ "origin/main" ]] || false
+}
+
+@test "remotes-file-system: dolt_fetch with --prune" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt branch b1
+    dolt push origin main
+    dolt push origin b1
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd ../
+    dolt push origin :b1
+
+    cd dolt-repo-clones/test-repo
+    dolt sql -q "call dolt_fetch('--prune')"
+    run dolt branch -r
+    [[ ! "$output" =~ "origin/b1" ]] || false
+    [[ "$output" =~ "origin/main" ]] || false
+}
+
+@test "remotes-file-system: clone --single-branch" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY)"
+    dolt add . && dolt commit -m "created table"
+    dolt checkout -b b1
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "added row on b1"
+    dolt checkout -b b2
+    dolt sql -q "INSERT INTO test VALUES (2)"
+    dolt commit -am "added row on b2"
+    dolt checkout main
+    dolt push origin main
+    dolt push origin b1
+    dolt push origin b2
+
+    cd dolt-repo-clones
+    run dolt clone --single-branch -b b1 file://../remotedir test-repo
+    [ $status -eq 0 ]
+    cd test-repo
+    run dolt branch -a
+    [ $status -eq 0 ]
+    [[ "$output" =~ "* b1" ]] || false
+    [[ "$output" =~ "remotes/origin/b1" ]] || false
+    [[ ! "$output" =~ "main" ]] || false
+    [[ ! "$output" =~ "b2" ]] || false
+
+    run dolt sql -q "SELECT pk FROM test" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+    [[ ! "$output" =~ "2" ]] || false
+
+    # fetches are limited to the cloned branch
+    run dolt fetch
+    [ $status -eq 0 ]
+    run dolt branch -r
+    [[ "$output" =~ "origin/b1" ]] || false
+    [[ ! "$output" =~ "origin/main" ]] || false
+    [[ ! "$output" =~ "origin/b2" ]] || false
+}
+
+@test "remotes-file-system: clone --single-branch uses the default branch" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt branch b1
+    dolt push origin main
+    dolt push origin b1
+
+    cd dolt-repo-clones
+    dolt clone --single-branch file://../remotedir test-repo
+    cd test-repo
+    run dolt branch -a
+    [ $status -eq 0 ]
+    [[ "$output" =~ "* main" ]] || false
+    [[ "$output" =~ "remotes/origin/main" ]] || false
+    [[ ! "$output" =~ "b1" ]] || false
+}
+
+@test "remotes-file-system: clone --single-branch with a branch that does not exist" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt push origin main
+
+    cd dolt-repo-clones
+    run dolt clone --single-branch -b nope file://../remotedir test-repo
+    [ $status -ne 0 ]
+    [[ "$output" =~ "could not get branch: nope" ]] || false
+    [ ! -d test-repo ]
+}
+
+@test "remotes-file-system: clone, fetch and pull with --verify" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY)"
+    dolt add . && dolt commit -m "created table"
+    dolt branch b1
+    dolt push origin main
+    dolt push origin b1
+
+    cd dolt-repo-clones
+    run dolt clone --verify file://../remotedir test-repo
+    [ $status -eq 0 ]
+    cd test-repo
+    run dolt branch -a
+    [ $status -eq 0 ]
+    [[ "$output" =~ "* main" ]] || false
+    [[ "$output" =~ "remotes/origin/main" ]] || false
+    [[ "$output" =~ "remotes/origin/b1" ]] || false
+
+    cd ../..
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "added row"
+    dolt push origin main
+
+    cd dolt-repo-clones/test-repo
+    run dolt fetch --verify
+    [ $status -eq 0 ]
+    run dolt pull --verify
+    [ $status -eq 0 ]
+    run dolt sql -q "SELECT pk FROM test" -r csv
+    [[ "$output" =~ "1" ]] || false
+
+    dolt config --local --add fetch.verify true
+    run dolt fetch
+    [ $status -eq 0 ]
+    run dolt sql -q "call dolt_fetch('--verify')"
+    [ $status -eq 0 ]
+
+    dolt config --local --add fetch.verify bogus
+    run dolt fetch
+    [ $status -ne 0 ]
+    [[ "$output" =~ "invalid value for fetch.verify" ]] || false
+}
+
+@test "remotes-file-system: clones and fetches share table files through fetch.shared_cache_dir" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY)"
+    dolt add . && dolt commit -m "created table"
+    dolt push origin main
+
+    cache="$(pwd)/shared-cache"
+    dolt config --global --add fetch.shared_cache_dir "$cache"
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo-1
+    dolt clone file://../remotedir test-repo-2
+
+    for tf in $(ls ../remotedir | grep -v -e manifest -e LOCK -e oldgen); do
+        [ "$cache/$tf" -ef "test-repo-1/.dolt/noms/$tf" ]
+        [ "$cache/$tf" -ef "test-repo-2/.dolt/noms/$tf" ]
+    done
+
+    cd ../
+    dolt sql -q "INSERT INTO test VALUES (1)"
+    dolt commit -am "added row"
+    dolt push origin main
+
+    cd dolt-repo-clones/test-repo-1
+    dolt fetch
+    cd ../test-repo-2
+    dolt pull
+    run dolt sql -q "SELECT pk FROM test" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+
+    fetched=0
+    for tf in $(ls .dolt/noms | grep -v -e manifest -e LOCK -e journal -e vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv -e oldgen); do
+        [ "$cache/$tf" -ef "../test-repo-1/.dolt/noms/$tf" ]
+        [ "$cache/$tf" -ef ".dolt/noms/$tf" ]
+        fetched=$((fetched+1))
+    done
+    [ $fetched -gt 0 ]
+}
+
+@test "remotes-file-system: push --all and --tags" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt branch b1
+    dolt branch b2
+    dolt tag v1
+
+    run dolt push --all origin
+    [ $status -eq 0 ]
+    [[ "$output" =~ "main -> main" ]] || false
+    [[ "$output" =~ "b1 -> b1" ]] || false
+    [[ "$output" =~ "b2 -> b2" ]] || false
+    [[ ! "$output" =~ "v1" ]] || false
+
+    run dolt push --all
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Everything up-to-date" ]] || false
+
+    run dolt push --tags
+    [ $status -eq 0 ]
+    [[ "$output" =~ "v1 -> v1" ]] || false
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd test-repo
+    run dolt branch -r
+    [[ "$output" =~ "origin/b1" ]] || false
+    [[ "$output" =~ "origin/b2" ]] || false
+    run dolt tag
+    [[ "$output" =~ "v1" ]] || false
+
+    run dolt push --all origin main
+    [ $status -ne 0 ]
+    [[ "$output" =~ "--all and --tags cannot be used with a refspec" ]] || false
+}
+
+@test "remotes-file-system: push --all rejects branches that can't be fast-forwarded" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt branch b1
+    dolt push --all origin
+
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd test-repo
+    dolt checkout b1
+    dolt commit --allow-empty -m "commit on the clone"
+    dolt push origin b1
+
+    cd ../..
+    dolt checkout b1
+    dolt commit --allow-empty -m "diverging commit"
+    dolt checkout main
+    dolt commit --allow-empty -m "commit on main"
+
+    run dolt push --all origin
+    [ $status -ne 0 ]
+    [[ "$output" =~ "[rejected]" ]] || false
+    [[ "$output" =~ "b1 -> b1 (non-fast-forward)" ]] || false
+    [[ "$output" =~ "[pushed]" ]] || false
+    [[ "$output" =~ "main -> main" ]] || false
+
+    run dolt push --all --force origin
+    [ $status -eq 0 ]
+}
@@ -0,0 +1,114 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    cd $BATS_TMPDIR
+    cd dolt-repo-$$
+    mkdir "dolt-repo-clones"
+
+    # stands in for ssh by running the remote command locally, after dropping the port and destination arguments
+    cat > fake-ssh <<'EOF'
+#!/bin/sh
+if [ "$1" = "-p" ]; then
+    shift 2
+fi
+echo "$@" >> "$(dirname "$0")/fake-ssh.log"
+shift
+exec sh -c "$1"
+EOF
+    chmod +x fake-ssh
+    export DOLT_SSH="$(pwd)/fake-ssh"
+
+    dolt sql -q "create table test (pk int primary key, c1 int)"
+    dolt sql -q "insert into test values (1, 1), (2, 2)"
+    dolt commit -Am "test commit"
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "remotes-ssh: scp-like urls are stored as ssh urls" {
+    dolt remote add abs user@example.com:/srv/repo
+    dolt remote add rel example.com:repos/repo
+    dolt remote add port ssh://user@example.com:2222/srv/repo
+
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "abs ssh://user@example.com/srv/repo" ]] || false
+    [[ "$output" =~ "rel ssh://example.com/~/repos/repo" ]] || false
+    [[ "$output" =~ "port ssh://user@example.com:2222/srv/repo" ]] || false
+}
+
+@test "remotes-ssh: clone, push, and pull a repository over ssh" {
+    repo=$(pwd)
+
+    cd dolt-repo-clones
+    dolt clone "user@example.com:$repo" test-repo
+    grep "user@example.com" ../fake-ssh.log
+    cd test-repo
+
+    run dolt sql -q "select count(*) from test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt remote -v
+    [[ "$output" =~ "origin ssh://user@example.com$repo" ]] || false
+
+    dolt sql -q "insert into test values (3, 3)"
+    dolt commit -am "add a row"
+    dolt push origin main
+
+    cd "$repo"
+    run dolt log -n 1
+    [[ "$output" =~ "add a row" ]] || false
+
+    dolt checkout -b other
+    dolt sql -q "insert into test values (4, 4)"
+    dolt commit -am "add another row"
+
+    cd dolt-repo-clones/test-repo
+    dolt fetch
+    run dolt branch -a
+    [[ "$output" =~ "remotes/origin/other" ]] || false
+}
+
+@test "remotes-ssh: push to a new path stores the database there" {
+    remote="$(pwd)/remotes/bare"
+    dolt remote add origin "ssh://example.com:2222$remote"
+    dolt push origin main
+
+    [ -f "$remote/manifest" ]
+
+    cd dolt-repo-clones
+    dolt clone "example.com:$remote" bare-clone
+    cd bare-clone
+    run dolt sql -q "select count(*) from test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+}
+
+@test "remotes-ssh: DOLT_SSH_EXEC_PATH gives the path of dolt on the host" {
+    repo=$(pwd)
+    export DOLT_SSH_EXEC_PATH="$(which dolt)"
+
+    cd dolt-repo-clones
+    dolt clone "example.com:$repo" test-repo
+    grep "$DOLT_SSH_EXEC_PATH transfer" ../fake-ssh.log
+
+    export DOLT_SSH_EXEC_PATH="/not/dolt"
+    run dolt clone "example.com:$repo" test-repo-2
+    [ "$status" -ne 0 ]
+    [ ! -d test-repo-2 ]
+}
+
+@test "remotes-ssh: clone from a path that is not a directory fails" {
+    touch not-a-dir
+
+    cd dolt-repo-clones
+    run dolt clone "example.com:$(pwd)/../not-a-dir" test-repo
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "is not a directory" ]] || false
+}
@@ -325,6 +325,81 @@ SQL
     [[ "$output" =~ "unknown remote: 'poop'" ]] || false
 }
 
+@test "remotes: add a remote with its own credentials" {
+    dolt creds new
+    dolt creds new
+    run dolt creds ls
+    pub_key=`echo "${lines[1]}" | awk '{print $NF}'`
+    run dolt creds ls -v
+    key_id=`echo "$output" | grep "$pub_key" | awk '{print $NF}'`
+
+    dolt remote add with-key http://localhost:50051/test-org/test-repo --creds-key "$pub_key"
+    dolt remote add with-user http://localhost:50051/test-org/test-repo --creds-user user0
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "with-key http://localhost:50051/test-org/test-repo {\"creds-key\":\"$key_id\"}" ]] || false
+    [[ "$output" =~ "with-user http://localhost:50051/test-org/test-repo {\"creds-user\":\"user0\"}" ]] || false
+
+    run dolt remote add bad-key http://localhost:50051/test-org/test-repo --creds-key notakey
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid value for creds-key" ]] || false
+
+    run dolt remote add file-remote file://../remote --creds-user user0
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "creds-user param is only valid for http and https remotes" ]] || false
+
+    dolt remote set-creds with-key --creds-user user1
+    dolt remote set-creds with-user
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "with-key http://localhost:50051/test-org/test-repo {\"creds-user\":\"user1\"}" ]] || false
+    [[ ! "$output" =~ "user0" ]] || false
+
+    run dolt remote set-creds poop --creds-user user0
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown remote: 'poop'" ]] || false
+}
+
+@test "remotes: add a gcs remote with a credentials file and upload chunk size" {
+    dolt remote add gcs-remote gs://test-bucket/test-db --gcs-creds-file /tmp/key.json --gcs-upload-chunk-size 8388608
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "gcs-remote gs://test-bucket/test-db {\"gcs-creds-file\":\"/tmp/key.json\",\"gcs-upload-chunk-size\":\"8388608\"}" ]] || false
+
+    run dolt remote add http-remote http://localhost:50051/test-org/test-repo --gcs-creds-file /tmp/key.json
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "gcs-creds-file param is only valid for gcs remotes" ]] || false
+}
+
+@test "remotes: add an azure remote with a SAS token file and creds type" {
+    echo "sv=2021-08-06&sig=abc" > token.txt
+    dolt remote add az-remote az://account/container/test-db --az-creds-type sas --az-sas-token-file token.txt
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "az-remote az://account/container/test-db" ]] || false
+    [[ "$output" =~ "\"az-creds-type\":\"sas\"" ]] || false
+    [[ "$output" =~ "\"az-sas-token-file\":\"$(pwd)/token.txt\"" ]] || false
+
+    run dolt remote add bad-type az://account/container/test-db --az-creds-type role
+    [ "$status" -eq 1 ]
+
+    run dolt remote add http-remote http://localhost:50051/test-org/test-repo --az-creds-type sas
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "az-creds-type param is only valid for azure remotes" ]] || false
+}
+
+@test "remotes: add an oci remote with a registry user" {
+    dolt remote add oci-remote oci://ghcr.io/test-org/test-db:v1 --oci-user test-user
+    run dolt remote -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "oci-remote oci://ghcr.io/test-org/test-db:v1" ]] || false
+    [[ "$output" =~ "\"oci-user\":\"test-user\"" ]] || false
+
+    run dolt remote add http-remote http://localhost:50051/test-org/test-repo --oci-user test-user
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "oci-user param is only valid for oci remotes" ]] || false
+}
+
 @test "remotes: push and pull an unknown remote" {
     dolt remote add test-remote http://localhost:50051/test-org/test-repo
     run dolt push poop main
@@ -2324,3 +2399,35 @@ SQL
     [ "$status" -eq 0 ]
     [[ "$output" =~ "test_table" ]] || false
 }
+
+@test "remotes: push and fetch send changed chunks as deltas" {
+    mkdir repo1
+    cd repo1
+    dolt init
+    dolt sql -q "create table t (id int primary key, v varchar(100))"
+    dolt sql -q "insert into t with recursive c(n) as (select 0 union all select n + 1 from c where n < 199) select a.n * 200 + b.n, concat('value-', a.n * 200 + b.n, '-abcdefghijklmnopqrstuvwxyz') from c a, c b"
+    dolt commit -Am "first"
+    dolt remote add origin http://localhost:50051/test-org/test-repo
+    dolt push origin main
+
+    cd ..
+    dolt clone http://localhost:50051/test-org/test-repo repo2
+
+    cd repo1
+    dolt sql -q "update t set v = concat('VALUE', substr(v, 6)) where id % 100 = 7"
+    dolt commit -am "second"
+    dolt push origin main
+    run grep -c "delta_pack=true" $BATS_TMPDIR/remotes-$$/remotesrv.log
+    [ "$output" -gt 0 ]
+
+    cd ../repo2
+    dolt fetch
+    run grep "method=GetDeltaChunks.*num_deltas=[1-9]" $BATS_TMPDIR/remotes-$$/remotesrv.log
+    [ "$status" -eq 0 ]
+    dolt merge origin/main
+    run dolt sql -q "select count(*) from t where v like 'VALUE-%'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "400" ]] || false
+    run dolt sql -q "select v from t where id = 107" -r csv
+    [[ "$output" =~ "VALUE-107-abcdefghijklmnopqrstuvwxyz" ]] || false
+}
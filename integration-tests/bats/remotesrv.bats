@@ -118,3 +118,44 @@ stop_remotesrv() {
     cd ../
     dolt clone http://localhost:1234/test-org/test-repo repo1
 }
+
+@test "remotesrv: pre-receive hook can reject pushes" {
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q 'create table vals (i int);'
+    dolt add vals
+    dolt commit -m 'create vals table.'
+
+    cat > ../pre-receive.sh <<'SH'
+#!/bin/sh
+while read old new ref; do
+    echo "$old $new $ref" >> "$(dirname "$0")/pushed.txt"
+    if [ "$ref" = "refs/heads/frozen" ]; then
+        echo "frozen is frozen"
+        exit 1
+    fi
+done
+SH
+    chmod +x ../pre-receive.sh
+
+    remotesrv --http-port 1234 --repo-mode --pre-receive-hook "$(pwd)/../pre-receive.sh" &
+    remotesrv_pid=$!
+
+    cd ../
+    dolt clone http://localhost:50051/test-org/test-repo repo1
+    cd repo1
+    dolt sql -q 'insert into vals values (1), (2), (3);'
+    dolt commit -am 'insert some values'
+    dolt push origin main:main
+
+    run grep "refs/heads/main" ../pushed.txt
+    [ "$status" -eq 0 ]
+
+    dolt checkout -b frozen
+    run dolt push origin frozen
+    [ "$status" -ne 0 ]
+
+    run dolt branch -r
+    [[ ! "$output" =~ "origin/frozen" ]] || false
+}
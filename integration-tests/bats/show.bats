@@ -232,4 +232,47 @@ assert_has_key_value() {
     run dolt show branch1
     [ $status -eq 1 ]
     [[ "$output" =~ "branch not found: branch1" ]] || false
-}
\ No newline at end of file
+}
+
+@test "show: merge commit is diffed against its first parent" {
+    dolt sql -q "create table t (pk int PRIMARY KEY)"
+    dolt commit -Am "create table"
+    dolt checkout -b other
+    dolt sql -q "insert into t values (1)"
+    dolt commit -am "insert on other"
+    dolt checkout main
+    dolt commit --allow-empty -m "empty commit on main"
+    dolt merge --no-ff other -m "merge other"
+
+    run dolt show
+    [ $status -eq 0 ]
+    [[ "$output" =~ "merge other" ]] || false
+    [[ "$output" =~ "Merge:" ]] || false
+    [[ "$output" =~ "| + | 1  |" ]] || false
+
+    run dolt show --stat
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1 Row Added" ]] || false
+}
+
+@test "show: --format json prints the commit and its diff as one document" {
+    dolt sql -q "create table t (pk int PRIMARY KEY, c1 int)"
+    dolt commit -Am "create table"
+    dolt sql -q "insert into t values (1, 1)"
+    dolt commit -am "insert a row"
+
+    run dolt show --format json
+    [ $status -eq 0 ]
+    echo "$output" | python3 -c "import json, sys; json.load(sys.stdin)"
+    [[ "$output" =~ '"message":"insert a row"' ]] || false
+    [[ "$output" =~ '"data_diff":[{"from_row":{},"to_row":{"c1":1,"pk":1}}]' ]] || false
+
+    run dolt show -r json HEAD~2
+    [ $status -eq 0 ]
+    [[ "$output" =~ '"message":"Initialize data repository"' ]] || false
+    [[ "$output" =~ '"diff":{}' ]] || false
+
+    run dolt show --stat --format json
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot be combined with --format json" ]] || false
+}
@@ -0,0 +1,99 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    if ! command -v ssh-keygen > /dev/null; then
+        skip "ssh-keygen is not installed"
+    fi
+    setup_common
+
+    ssh-keygen -q -t ed25519 -N "" -C "signer@example.com" -f "$BATS_TMPDIR/signing-key-$$"
+    echo "signer@example.com $(cat "$BATS_TMPDIR/signing-key-$$.pub")" > "$BATS_TMPDIR/allowed-signers-$$"
+    dolt config --local --add gpg.format ssh
+    dolt config --local --add user.signingkey "$BATS_TMPDIR/signing-key-$$"
+    dolt config --local --add gpg.ssh.allowedsignersfile "$BATS_TMPDIR/allowed-signers-$$"
+
+    dolt sql -q "create table test (pk int primary key)"
+    dolt add -A
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+    rm -f "$BATS_TMPDIR/signing-key-$$" "$BATS_TMPDIR/signing-key-$$.pub" "$BATS_TMPDIR/allowed-signers-$$"
+}
+
+@test "signing: commit -S signs the commit and verify-commit checks it" {
+    dolt commit -S -m "signed commit"
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "HEAD: good signature from signer@example.com" ]] || false
+
+    run dolt sql -r csv -q "select message, signed from dolt_log limit 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "signed commit,true" ]] || false
+
+    run dolt sql -r csv -q "select message, signed from dolt_log() limit 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "signed commit,true" ]] || false
+}
+
+@test "signing: unsigned commits fail verify-commit" {
+    dolt commit -m "unsigned commit"
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "HEAD: no signature found" ]] || false
+
+    run dolt sql -r csv -q "select message, signed from dolt_log limit 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "unsigned commit,false" ]] || false
+}
+
+@test "signing: commit.gpgsign signs commits by default" {
+    dolt config --local --add commit.gpgsign true
+    dolt commit -m "signed by default"
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+
+    dolt sql -q "insert into test values (1)"
+    dolt commit -am "not signed" --no-gpg-sign
+    run dolt verify-commit HEAD
+    [ "$status" -eq 1 ]
+    run dolt verify-commit HEAD~1
+    [ "$status" -eq 0 ]
+}
+
+@test "signing: dolt_commit procedure signs commits" {
+    dolt sql -q "call dolt_commit('-S', '-m', 'signed in sql')"
+    run dolt verify-commit HEAD
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "good signature" ]] || false
+}
+
+@test "signing: signatures from unknown keys are bad" {
+    dolt commit -S -m "signed commit"
+    echo "" > "$BATS_TMPDIR/allowed-signers-$$"
+
+    run dolt verify-commit HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "HEAD: bad signature" ]] || false
+}
+
+@test "signing: signing without a key is an error" {
+    dolt config --local --unset user.signingkey
+    run dolt commit -S -m "signed commit"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no signing key is configured" ]] || false
+}
+
+@test "signing: tag -s signs the tag" {
+    dolt commit -m "commit"
+    dolt tag -s v1 -m "signed tag"
+    dolt sql -q "call dolt_tag('-s', 'v2', '-m', 'signed tag')"
+    run dolt tag -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "v1" ]] || false
+    [[ "$output" =~ "v2" ]] || false
+}
@@ -21,4 +21,44 @@ teardown() {
     run dolt sql -q "create table t (p point srid 0 not null, spatial index(p))"
     [ "$status" -eq 1 ]
     [[ "$output" =~ "spatial indexes are only supported in storage format" ]] || false
-}
\ No newline at end of file
+}
+
+@test "spatial-index: st_within and st_contains use spatial indexes" {
+    dolt sql <<SQL
+create table t (pk int primary key, g geometry srid 0 not null, spatial index (g));
+insert into t values
+    (1, point(1, 1)),
+    (2, point(5, 5)),
+    (3, point(10, 10)),
+    (4, point(3, 2)),
+    (5, point(-1, 3));
+SQL
+
+    run dolt sql -q "explain select pk from t where st_within(g, st_geomfromtext('polygon((0 0, 0 6, 6 6, 6 0, 0 0))'))"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "IndexedTableAccess(t)" ]] || false
+
+    run dolt sql -q "select pk from t where st_within(g, st_geomfromtext('polygon((0 0, 0 6, 6 6, 6 0, 0 0))')) order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+    [ "${lines[3]}" = "4" ]
+
+    run dolt sql -q "explain select pk from t where st_contains(st_geomfromtext('polygon((0 0, 0 6, 6 6, 6 0, 0 0))'), g)"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "IndexedTableAccess(t)" ]] || false
+
+    run dolt sql -q "select pk from t where st_contains(st_geomfromtext('polygon((0 0, 0 6, 6 6, 6 0, 0 0))'), g) order by pk" -r csv
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "2" ]
+    [ "${lines[3]}" = "4" ]
+}
+
+@test "spatial-index: st_contains is st_within with its arguments swapped" {
+    run dolt sql -q "select st_contains(st_geomfromtext('polygon((0 0, 0 6, 6 6, 6 0, 0 0))'), point(1, 1)), st_contains(st_geomfromtext('polygon((0 0, 0 6, 6 6, 6 0, 0 0))'), point(7, 7)), st_contains(point(1, 1), null)" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "true,false," ]
+}
@@ -122,3 +122,52 @@ teardown() {
     [[ $output =~ "schon" ]] || false
     [[ $output =~ "schön" ]] || false
 }
+
+@test "sql-charsets-collations: primary and unique keys compare with their column's collation" {
+    dolt sql <<SQL
+create table ci (pk varchar(20) collate utf8mb4_0900_ai_ci primary key, u varchar(20) collate latin1_german1_ci, unique key (u));
+create table cs (pk varchar(20) collate utf8mb4_0900_bin primary key);
+insert into ci values ('apple', 'Bar'), ('Banana', 'x'), ('cherry', 'y');
+insert into cs values ('apple'), ('Banana'), ('cherry');
+SQL
+
+    run dolt sql -q "select pk from ci" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "apple" ]
+    [ "${lines[2]}" = "Banana" ]
+    [ "${lines[3]}" = "cherry" ]
+
+    run dolt sql -q "select pk from cs" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "Banana" ]
+    [ "${lines[2]}" = "apple" ]
+    [ "${lines[3]}" = "cherry" ]
+
+    run dolt sql -q "insert into ci values ('APPLE', 'z')"
+    [ $status -eq 1 ]
+    [[ $output =~ "duplicate primary key" ]] || false
+
+    run dolt sql -q "insert into ci values ('date', 'Bär')"
+    [ $status -eq 1 ]
+    [[ $output =~ "duplicate unique key" ]] || false
+
+    dolt sql -q "insert into cs values ('APPLE')"
+    run dolt sql -q "select count(*) from cs" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "4" ]
+}
+
+@test "sql-charsets-collations: pad space collations ignore trailing spaces in keys" {
+    skip "go-mysql-server and the key comparator treat all collations as NO PAD"
+
+    dolt sql -q "create table t (pk varchar(20) collate utf8mb4_general_ci primary key)"
+    dolt sql -q "insert into t values ('a')"
+
+    run dolt sql -q "insert into t values ('a  ')"
+    [ $status -eq 1 ]
+    [[ $output =~ "duplicate primary key" ]] || false
+
+    run dolt sql -q "select count(*) from t where pk = 'a '" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
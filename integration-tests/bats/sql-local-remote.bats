@@ -283,4 +283,24 @@ get_staged_tables() {
     [[ "$output" =~ "starting local mode" ]] || false
     [[ "$output" =~ "main" ]] || false
     [[ "$output" =~ "b2" ]] || false
-}
\ No newline at end of file
+}
+
+@test "sql-local-remote: verify dolt purge-history works against a running server" {
+    cd altDB
+    dolt add -A && dolt commit -m "all tables"
+    dolt sql -q "INSERT INTO table1 VALUES (4);"
+    dolt commit -am "recent commit"
+    cd ..
+
+    start_sql_server altDB
+    cd altDB
+
+    run dolt --verbose-engine-setup --user dolt purge-history --before 2100-01-01 table1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "starting remote mode" ]] || false
+    [[ "$output" =~ "Removed 'table1' from 2 commit(s)" ]] || false
+
+    run dolt --user dolt sql -q "SELECT count(*) FROM dolt_history_table1" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "4" ]
+}
@@ -1130,6 +1130,42 @@ SQL
     [[ "$output" =~ "$regex" ]] || false
 }
 
+@test "sql-merge: DOLT_MERGE with --delete-source deletes the merged branch" {
+    dolt sql <<SQL
+CALL DOLT_COMMIT('-a', '-m', 'Step 1');
+CALL DOLT_BRANCH('ff-branch');
+CALL DOLT_CHECKOUT('-b', 'feature-branch');
+INSERT INTO test VALUES (3);
+CALL DOLT_COMMIT('-a', '-m', 'add 3 from other');
+CALL DOLT_CHECKOUT('main');
+INSERT INTO test VALUES (4);
+CALL DOLT_COMMIT('-a', '-m', 'add 4 from main');
+SQL
+
+    run dolt sql -q "CALL DOLT_MERGE('feature-branch', '--no-commit', '--delete-source');"
+    log_status_eq 0
+    run dolt branch
+    [[ "$output" =~ "feature-branch" ]] || false
+    dolt sql -q "CALL DOLT_MERGE('--abort')"
+
+    run dolt sql -q "CALL DOLT_MERGE('feature-branch', '--delete-source');"
+    log_status_eq 0
+    run dolt branch
+    log_status_eq 0
+    [[ ! "$output" =~ "feature-branch" ]] || false
+    run dolt sql -q "SELECT count(*) FROM test" -r csv
+    [[ "$output" =~ "5" ]] || false
+
+    # fast-forward merges delete the source branch too
+    dolt sql -q "CALL DOLT_BRANCH('-f', 'ff-branch', 'main'); CALL DOLT_CHECKOUT('ff-branch'); INSERT INTO test VALUES (5); CALL DOLT_COMMIT('-a', '-m', 'add 5');"
+    run dolt sql -q "SET @@dolt_merge_delete_source = 1; CALL DOLT_MERGE('ff-branch');"
+    log_status_eq 0
+    run dolt branch
+    [[ ! "$output" =~ "ff-branch" ]] || false
+    run dolt sql -q "SELECT count(*) FROM test" -r csv
+    [[ "$output" =~ "6" ]] || false
+}
+
 get_head_commit() {
     dolt log -n 1 | grep -m 1 commit | cut -c 13-44
 }
@@ -305,3 +305,21 @@ teardown() {
     [ "$status" -eq 1 ]
     [[ "$output" =~ "invalid ref spec: ''" ]] || false
 }
+
+@test "sql-push: CALL dolt_push with --all and --tags" {
+    cd repo1
+    dolt branch b1
+    dolt tag v1
+    dolt sql -q "CALL dolt_push('--all', '--tags', 'origin')"
+
+    cd ../repo2
+    dolt fetch
+    run dolt branch -r
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "origin/b1" ]] || false
+    [[ "$output" =~ "origin/main" ]] || false
+
+    run dolt sql -q "CALL dolt_push('--all', 'origin', 'main')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--all and --tags cannot be used with a refspec" ]] || false
+}
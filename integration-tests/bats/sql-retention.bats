@@ -0,0 +1,76 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY, v int);"
+    dolt add -A && dolt commit -m "create table" --date 2020-01-03T12:00:00
+    dolt sql -q "INSERT INTO t VALUES (1, 1);"
+    dolt commit -am "version 1" --date 2020-01-04T12:00:00
+    dolt sql -q "UPDATE t SET v = 2;"
+    dolt commit -am "version 2" --date 2020-01-05T12:00:00
+    dolt sql -q "UPDATE t SET v = 3;"
+    dolt commit -am "version 3"
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "sql-retention: squashes old history into snapshots" {
+    run dolt sql -q "CALL DOLT_RETENTION('--keep-days', '30')" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    run dolt log --oneline
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "version 3" ]] || false
+    [[ "$output" =~ "version 2" ]] || false
+    [[ ! "$output" =~ "version 1" ]] || false
+    [[ ! "$output" =~ "create table" ]] || false
+
+    run dolt sql -q "SELECT v FROM t AS OF 'HEAD~1'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+
+    # squashing again changes nothing
+    run dolt sql -q "CALL DOLT_RETENTION('--keep-days', '30')" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "sql-retention: keeps uncommitted changes and tagged commits" {
+    dolt tag v1 HEAD~2
+    dolt sql -q "INSERT INTO t VALUES (2, 2);"
+
+    run dolt sql -q "CALL DOLT_RETENTION('--keep-days', '30')" -r csv
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+
+    run dolt sql -q "SELECT v FROM t AS OF 'v1'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "sql-retention: only squashes the given branches" {
+    dolt branch other
+
+    run dolt sql -q "CALL DOLT_RETENTION('--keep-days', '30', 'other')" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    run dolt log --oneline other
+    [[ ! "$output" =~ "version 1" ]] || false
+    run dolt log --oneline main
+    [[ "$output" =~ "version 1" ]] || false
+}
+
+@test "sql-retention: requires --keep-days" {
+    run dolt sql -q "CALL DOLT_RETENTION()"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--keep-days must be a positive number of days" ]] || false
+}
@@ -233,6 +233,106 @@ SQL
     [[ "$output" =~ "11" ]] || false
 }
 
+@test "sql-server-remotesrv: fetch and pull with credentials configured for the remote" {
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q 'create table vals (i int);'
+    dolt sql -q 'insert into vals (i) values (1), (2), (3), (4), (5);'
+    dolt add vals
+    dolt commit -m 'initial vals.'
+    export DOLT_REMOTE_USER="user0"
+    export DOLT_REMOTE_PASSWORD="pass0"
+
+    dolt sql-server --port 3307 -u $DOLT_REMOTE_USER  -p $DOLT_REMOTE_PASSWORD --remotesapi-port 50051 &
+    srv_pid=$!
+    sleep 2 # wait for server to start so we don't lock it out
+
+    cd ../
+    dolt clone http://localhost:50051/remote repo1 --creds-user $DOLT_REMOTE_USER
+    cd repo1
+    run dolt remote -v
+    [[ "$output" =~ "creds-user" ]] || false
+
+    dolt sql-client --port 3307 -u $DOLT_REMOTE_USER  -p $DOLT_REMOTE_PASSWORD <<SQL
+use remote;
+insert into vals (i) values (6);
+call dolt_commit('-am', 'add one val');
+SQL
+
+    # the remote's credentials are used without -u
+    run dolt pull
+    [ "$status" -eq 0 ]
+    run dolt sql -q 'select count(*) from vals;'
+    [[ "$output" =~ "6" ]] || false
+
+    dolt remote set-creds origin
+    run dolt fetch
+    [[ "$status" != 0 ]] || false
+    [[ "$output" =~ "Unauthenticated" ]] || false
+
+    dolt remote set-creds origin --creds-user $DOLT_REMOTE_USER
+    unset DOLT_REMOTE_PASSWORD
+    run dolt fetch
+    [[ "$status" != 0 ]] || false
+    [[ "$output" =~ "must set DOLT_REMOTE_PASSWORD environment variable" ]] || false
+}
+
+@test "sql-server-remotesrv: fetch and pull with credentials from a credential helper" {
+    mkdir remote
+    cd remote
+    dolt init
+    dolt sql -q 'create table vals (i int);'
+    dolt sql -q 'insert into vals (i) values (1), (2), (3), (4), (5);'
+    dolt add vals
+    dolt commit -m 'initial vals.'
+    export DOLT_REMOTE_USER="user0"
+    export DOLT_REMOTE_PASSWORD="pass0"
+
+    dolt sql-server --port 3307 -u $DOLT_REMOTE_USER  -p $DOLT_REMOTE_PASSWORD --remotesapi-port 50051 &
+    srv_pid=$!
+    sleep 2 # wait for server to start so we don't lock it out
+    unset DOLT_REMOTE_PASSWORD
+
+    cd ../
+    helper="$(pwd)/creds-helper.sh"
+    printf '#!/bin/sh\ncat > "%s"\necho username=user0\necho password=pass0\n' "$(pwd)/helper-input" > "$helper"
+    chmod +x "$helper"
+
+    dolt clone http://localhost:50051/remote repo1 --creds-helper "$helper"
+    cd repo1
+    run dolt remote -v
+    [[ "$output" =~ "creds-helper" ]] || false
+    run cat ../helper-input
+    [[ "$output" =~ "protocol=http" ]] || false
+    [[ "$output" =~ "host=localhost:50051" ]] || false
+
+    dolt sql-client --port 3307 -u $DOLT_REMOTE_USER  -p pass0 <<SQL
+use remote;
+insert into vals (i) values (6);
+call dolt_commit('-am', 'add one val');
+SQL
+
+    run dolt pull
+    [ "$status" -eq 0 ]
+    run dolt sql -q 'select count(*) from vals;'
+    [[ "$output" =~ "6" ]] || false
+
+    # creds.helper is used for remotes without credentials of their own
+    dolt remote set-creds origin
+    run dolt fetch
+    [[ "$status" != 0 ]] || false
+    [[ "$output" =~ "Unauthenticated" ]] || false
+    dolt config --local --add creds.helper "$helper"
+    dolt fetch
+
+    # a helper which fails fails the fetch with its error
+    printf '#!/bin/sh\necho "vault is sealed" >&2\nexit 1\n' > "$helper"
+    run dolt fetch
+    [[ "$status" != 0 ]] || false
+    [[ "$output" =~ "vault is sealed" ]] || false
+}
+
 @test "sql-server-remotesrv: dolt clone without authentication errors" {
     mkdir remote
     cd remote
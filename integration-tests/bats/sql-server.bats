@@ -1935,4 +1935,335 @@ behavior:
     [[ "$output" =~ "newOther" ]] || false
     [[ "$output" =~ "main" ]] || false
     [[ ! "$output" =~ "other" ]] || false
-}
\ No newline at end of file
+}
+@test "sql-server: dolt_statement_stats aggregates statements by digest" {
+    cd repo1
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY, v int); INSERT INTO t VALUES (1,1), (2,2), (3,3);"
+    start_sql_server repo1
+
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "SET @@GLOBAL.dolt_statement_stats = 1"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "SELECT * FROM t WHERE pk = 1"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "SELECT * FROM t WHERE pk = 2"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "INSERT INTO t VALUES (4,4), (5,5)"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "UPDATE t SET v = v + 1 WHERE pk > 3"
+
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT statement, count, rows_read, rows_written FROM dolt_statement_stats ORDER BY statement"
+    [ $status -eq 0 ]
+    [[ "$output" =~ '"insert into t values (?, ?)",1,0,2' ]] || false
+    [[ "$output" =~ "select * from t where pk = ?,2,2,0" ]] || false
+    [[ "$output" =~ "update t set v = v + ? where pk > ?,1,2,2" ]] || false
+
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "CALL dolt_statement_stats_reset()"
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT count(*) FROM dolt_statement_stats WHERE statement LIKE 'select%'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "SET @@GLOBAL.dolt_statement_stats = 0"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "SELECT * FROM t WHERE pk = 1"
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT count(*) FROM dolt_statement_stats WHERE statement LIKE 'select * from t%'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "sql-server: event scheduler runs events on the configured branch and commits them" {
+    cd repo1
+    dolt sql -q "CREATE TABLE ticks (pk int PRIMARY KEY AUTO_INCREMENT, at datetime)"
+    dolt commit -Am "add ticks"
+    dolt branch maintenance
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: debug
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+behavior:
+  event_scheduler: true
+  event_scheduler_branch: maintenance
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance -q "CREATE EVENT tick ON SCHEDULE EVERY 1 SECOND DO INSERT INTO ticks (at) VALUES (NOW())"
+    dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance -q "CREATE EVENT once ON SCHEDULE AT CURRENT_TIMESTAMP + INTERVAL 1 SECOND DO INSERT INTO ticks VALUES (1000, NOW())"
+    dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance -q "CALL DOLT_COMMIT('-Am', 'add events')"
+    sleep 4
+
+    run dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance --result-format csv -q "SELECT count(*) > 1 FROM ticks WHERE pk < 1000"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    run dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance --result-format csv -q "SELECT count(*) FROM ticks WHERE pk = 1000"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    # one-time events without ON COMPLETION PRESERVE are dropped after they run
+    run dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance --result-format csv -q "SELECT name FROM dolt_schemas WHERE type = 'event'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "tick" ]] || false
+    [[ ! "$output" =~ "once" ]] || false
+
+    run dolt sql-client -P $PORT -u dolt --use-db repo1/maintenance --result-format csv -q "SELECT count(*) > 1 FROM dolt_log WHERE message = 'Run event tick'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+
+    # events don't run on other branches
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT count(*) FROM ticks"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "sql-server: archiving pushes commits to the backup of the database" {
+    cd repo1
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY)"
+    dolt commit -Am "add t"
+    dolt backup add archive file://../archive
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: debug
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+archiving:
+  backup: archive
+  branches: [main]
+  every_commits: 1
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "INSERT INTO t VALUES (1); CALL DOLT_COMMIT('-am', 'archived commit')"
+    sleep 2
+    stop_sql_server 1
+
+    dolt backup restore file://./archive restored
+    cd restored
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "archived commit" ]] || false
+
+    run dolt sql -q "SELECT count(*) FROM t" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "sql-server: retention squashes and garbage collects old history" {
+    cd repo1
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY, v int)"
+    dolt commit -Am "create table" --date 2020-01-03T12:00:00
+    dolt sql -q "INSERT INTO t VALUES (1, 1)"
+    dolt commit -am "old commit" --date 2020-01-04T12:00:00
+    dolt sql -q "UPDATE t SET v = 2"
+    dolt commit -am "recent commit"
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: debug
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+retention:
+  keep_days: 30
+  interval_secs: 1
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+    sleep 3
+    stop_sql_server 1
+
+    cd repo1
+    run dolt log --oneline
+    [ $status -eq 0 ]
+    [[ "$output" =~ "recent commit" ]] || false
+    [[ "$output" =~ "old commit" ]] || false
+    [[ ! "$output" =~ "create table" ]] || false
+
+    run dolt sql -q "SELECT v FROM t" -r csv
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+}
+
+@test "sql-server: snapshots tag branches on a schedule and prune old snapshot tags" {
+    cd repo1
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY)"
+    dolt commit -Am "create table"
+    dolt tag snapshot/main/2020-01-01-0000 -m "old snapshot"
+    dolt tag snapshot/main/2020-01-02-0000 -m "old snapshot"
+    dolt tag v1 -m "release"
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: debug
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+snapshots:
+  schedule: "* * * * *"
+  branches: [main, missing]
+  time_format: "2006-01-02-1504"
+  keep: 2
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    # the schedule fires at the start of the next minute
+    for i in $(seq 1 70); do
+        run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT COUNT(*) FROM dolt_tags WHERE tag_name LIKE 'snapshot/main/%' AND tag_name NOT LIKE 'snapshot/main/2020%'"
+        [ "${lines[1]}" = "1" ] && break
+        sleep 1
+    done
+    sleep 1
+    stop_sql_server 1
+
+    cd repo1
+    run dolt tag
+    [ $status -eq 0 ]
+    [[ "$output" =~ "snapshot/main/2020-01-02-0000" ]] || false
+    [[ ! "$output" =~ "snapshot/main/2020-01-01-0000" ]] || false
+    [[ "$output" =~ "v1" ]] || false
+    [[ ! "$output" =~ "snapshot/missing" ]] || false
+    [ "$(dolt tag | grep -c 'snapshot/main/')" -eq 2 ]
+}
+
+@test "sql-server: snapshots require a valid schedule" {
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+listener:
+  port: $PORT
+snapshots:
+  schedule: "0 0 * *"
+  branches: [main]
+YAML
+    run dolt sql-server --config server.yaml
+    [ $status -ne 0 ]
+    [[ "$output" =~ "snapshots: schedule" ]] || false
+}
+
+@test "sql-server: dolt_operation_status is empty when no operations are running" {
+    mkdir rem1
+    cd repo1
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY)"
+    dolt commit -Am "create table"
+    dolt remote add origin file://../rem1
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT id, connection_id, user, \`database\`, operation, phase, unit, processed, total, started, elapsed_secs, eta_secs FROM dolt_operation_status"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+
+    # operations stop being reported when they finish
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "CALL dolt_push('origin', 'main')"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "CALL dolt_gc()"
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "SELECT count(*) FROM dolt_operation_status"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+}
+
+@test "sql-server: storage quotas reject transactions that grow a branch over its quota" {
+    cd repo1
+    dolt sql -q "CREATE TABLE t (pk int PRIMARY KEY, v varchar(200))"
+    dolt commit -Am "create table"
+    dolt branch exp
+    cd ..
+
+    PORT=$( definePORT )
+    cat > server.yaml <<YAML
+log_level: debug
+user:
+  name: dolt
+listener:
+  host: 0.0.0.0
+  port: $PORT
+quotas:
+  - database: repo1
+    branch: exp
+    max_bytes: 4096
+YAML
+    dolt sql-server --config server.yaml --socket "dolt.$PORT.sock" &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    INSERT="INSERT INTO t WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 200) SELECT i, repeat('x', 200) FROM n"
+
+    dolt sql-client -P $PORT -u dolt --use-db "repo1/exp" -q "INSERT INTO t VALUES (0, 'small')"
+
+    run dolt sql-client -P $PORT -u dolt --use-db "repo1/exp" -q "$INSERT"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "storage quota exceeded" ]] || false
+
+    run dolt sql-client -P $PORT -u dolt --use-db "repo1/exp" -q "SELECT count(*) FROM t"
+    [ $status -eq 0 ]
+    [[ "$output" =~ " 1 " ]] || false
+
+    # other branches have no quota
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "$INSERT"
+
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 -q "SELECT branch, max_bytes FROM dolt_storage_usage WHERE branch IS NOT NULL ORDER BY branch"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "exp    | 4096" ]] || false
+    [[ "$output" =~ "main   | NULL" ]] || false
+
+    # shrinking a branch is always allowed
+    dolt sql-client -P $PORT -u dolt --use-db "repo1/exp" -q "DELETE FROM t"
+
+    stop_sql_server 1
+}
+
+@test "sql-server: dolt_login only registers credentials the user created" {
+    cd repo1
+    start_sql_server
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "create user user1@'%';"
+    dolt sql-client -P $PORT -u dolt --use-db repo1 -q "grant all privileges on repo1.* to user1@'%';"
+
+    # credentials of the user running the server can't be taken over by a user without SUPER
+    pubkey=`dolt creds new | grep 'pub key:' | awk '{print $3}'`
+    run dolt sql-client -P $PORT -u user1 --use-db repo1 -q "call dolt_login('$pubkey')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "command denied" ]] || false
+
+    # nor can credentials created by another SQL user
+    run dolt sql-client -P $PORT -u dolt --use-db repo1 --result-format csv -q "call dolt_login()"
+    [ $status -eq 0 ]
+    kid=`echo "${lines[1]}" | awk -F, '{print $2}'`
+    run dolt sql-client -P $PORT -u user1 --use-db repo1 -q "call dolt_login('$kid')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "command denied" ]] || false
+
+    # credentials the user created themselves pass the privilege check, and fail to reach the auth endpoint
+    run dolt sql-client -P $PORT -u user1 --use-db repo1 --result-format csv -q "call dolt_login()"
+    [ $status -eq 0 ]
+    kid=`echo "${lines[1]}" | awk -F, '{print $2}'`
+    run dolt sql-client -P $PORT -u user1 --use-db repo1 -q "call dolt_login('$kid')"
+    ! [[ "$output" =~ "command denied" ]] || false
+
+    stop_sql_server 1
+}
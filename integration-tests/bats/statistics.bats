@@ -0,0 +1,70 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql <<SQL
+CREATE TABLE t (pk int PRIMARY KEY, a int, b varchar(10), KEY ab (a, b));
+INSERT INTO t VALUES (1, 1, 'x'), (2, 1, 'y'), (3, 2, NULL), (4, 5, 'x');
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "statistics: analyze table persists statistics across sessions" {
+    run dolt sql -q "select index_name, seq_in_index, cardinality from information_schema.statistics where table_name = 't' order by 1, 2" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ab,1,0" ]] || false
+
+    run dolt sql -q "analyze table t"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "OK" ]] || false
+    [ -f .dolt/statistics.json ]
+
+    run dolt sql -q "select index_name, seq_in_index, cardinality from information_schema.statistics where table_name = 't' order by 1, 2" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "PRIMARY,1,4" ]] || false
+    [[ "$output" =~ "ab,1,3" ]] || false
+    [[ "$output" =~ "ab,2,4" ]] || false
+
+    run dolt sql -q "select column_name, histogram from information_schema.column_statistics where table_name = 't' and column_name = 'a'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "0.50" ]] || false
+    [[ "$output" =~ "5.00" ]] || false
+
+    # statistics are not versioned
+    run dolt status
+    [[ "$output" =~ "new table:" ]] || false
+    [[ ! "$output" =~ "statistics" ]] || false
+}
+
+@test "statistics: statistics are kept per branch" {
+    dolt sql -q "analyze table t"
+    dolt checkout -b other
+
+    run dolt sql -q "select count(*) from information_schema.column_statistics where table_name = 't'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    dolt checkout main
+    run dolt sql -q "select count(*) from information_schema.column_statistics where table_name = 't'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+}
+
+@test "statistics: schema changes make histograms out of date" {
+    dolt sql -q "analyze table t"
+    dolt sql -q "alter table t add column c int"
+
+    run dolt sql -q "select count(*) from information_schema.column_statistics where table_name = 't'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    dolt sql -q "analyze table t"
+    run dolt sql -q "select count(*) from information_schema.column_statistics where table_name = 't'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+}
@@ -488,3 +488,26 @@ SQL
     [[ "$output" =~ "  (use \"dolt add -f <table>\" to include in what will be committed)" ]] || false
     [[ "$output" =~ "	new table:        generated_foo" ]] || false
 }
+
+@test "status: --format json" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt add t1
+
+    run dolt status --format json
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ '"branch":"main","upstream":null,"merge_active":false' ]] || false
+    [[ "$output" =~ '"staged":[{"table":"t1","status":"new table"}]' ]] || false
+    [[ "$output" =~ '"untracked":[{"table":"t2","status":"new table"}]' ]] || false
+    [[ "$output" =~ '"unmerged":[]' ]] || false
+
+    dolt commit -Am "add tables"
+    run dolt status --format json
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '"staged":[],"unstaged":[],"untracked":[],"unmerged":[]' ]] || false
+
+    run dolt status --format yaml
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid output format: yaml" ]] || false
+}
@@ -215,3 +215,79 @@ SQL
     [[ "$output" =~ "6" ]] || false
     [[ "${#lines[@]}" = "2" ]] || false
 }
+
+@test "triggers: Same trigger changed on different branches conflicts" {
+    dolt sql <<SQL
+CREATE TABLE x(a BIGINT PRIMARY KEY);
+CREATE TRIGGER trigger1 BEFORE INSERT ON x FOR EACH ROW SET new.a = new.a + 1;
+SQL
+    dolt add -A
+    dolt commit -m "Initial Commit"
+    dolt checkout -b other
+    dolt sql <<SQL
+DROP TRIGGER trigger1;
+CREATE TRIGGER trigger1 BEFORE INSERT ON x FOR EACH ROW SET new.a = new.a + 100;
+SQL
+    dolt add -A
+    dolt commit -m "On other"
+    dolt checkout main
+    dolt sql <<SQL
+DROP TRIGGER trigger1;
+CREATE TRIGGER trigger1 BEFORE INSERT ON x FOR EACH ROW SET new.a = new.a + 10;
+SQL
+    dolt add -A
+    dolt commit -m "On main"
+
+    run dolt merge other
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "CONFLICT" ]] || false
+    [[ "$output" =~ "dolt_schemas" ]] || false
+
+    run dolt sql -q "SELECT our_name, their_name FROM dolt_conflicts_dolt_schemas" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "trigger1,trigger1" ]] || false
+
+    dolt conflicts resolve --theirs dolt_schemas
+    dolt add dolt_schemas
+    dolt commit -m "Merged other"
+
+    dolt sql -q "INSERT INTO x VALUES (1)"
+    run dolt sql -q "SELECT a FROM x" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "${lines[1]}" = "101" ]] || false
+}
+
+@test "triggers: Audit trigger merged from another branch fires on writes" {
+    dolt sql <<SQL
+CREATE TABLE accounts(id int PRIMARY KEY, balance int);
+CREATE TABLE audit(id int PRIMARY KEY AUTO_INCREMENT, account_id int, old_balance int, new_balance int);
+INSERT INTO accounts VALUES (1, 100);
+SQL
+    dolt add -A
+    dolt commit -m "Initial Commit"
+    dolt checkout -b audit
+    dolt sql <<SQL
+CREATE TRIGGER accounts_audit AFTER UPDATE ON accounts FOR EACH ROW
+  INSERT INTO audit (account_id, old_balance, new_balance) VALUES (old.id, old.balance, new.balance);
+SQL
+    dolt add -A
+    dolt commit -m "Add audit trigger"
+    dolt checkout main
+
+    dolt sql -q "UPDATE accounts SET balance = 50 WHERE id = 1"
+    run dolt sql -q "SELECT count(*) FROM audit" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "${lines[1]}" = "0" ]] || false
+    dolt commit -am "Update before audit"
+
+    dolt merge audit
+    dolt sql -q "UPDATE accounts SET balance = 75 WHERE id = 1"
+    run dolt sql -q "SELECT account_id, old_balance, new_balance FROM audit" -r=csv
+    [ "$status" -eq "0" ]
+    [[ "${lines[1]}" = "1,50,75" ]] || false
+    [[ "${#lines[@]}" = "2" ]] || false
+
+    run dolt diff
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "audit" ]] || false
+}